@@ -8,7 +8,6 @@ import (
 	"errors"
 	"flag"
 	"log"
-	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,6 +18,7 @@ import (
 	_ "github.com/Wei-Shaw/sub2api/ent/runtime"
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/setup"
 	"github.com/Wei-Shaw/sub2api/internal/web"
@@ -45,24 +45,30 @@ func init() {
 	}
 }
 
-// initLogger configures the default slog handler based on gin.Mode().
-// In non-release mode, Debug level logs are enabled.
-func initLogger() {
-	var level slog.Level
+// initLogger configures the default slog handler based on gin.Mode() and the optional log config.
+// In non-release mode, Debug level logs are enabled unless log.level is set.
+func initLogger(cfg *config.LogConfig) {
+	opts := logger.Options{Format: logger.FormatText, Level: "debug"}
 	if gin.Mode() == gin.ReleaseMode {
-		level = slog.LevelInfo
-	} else {
-		level = slog.LevelDebug
+		opts.Level = "info"
+	}
+	if cfg != nil {
+		if strings.TrimSpace(cfg.Format) != "" {
+			opts.Format = cfg.Format
+		}
+		if strings.TrimSpace(cfg.Level) != "" {
+			opts.Level = cfg.Level
+		}
+		opts.Modules = cfg.Modules
+	}
+	if err := logger.Init(opts); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
 	}
-	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: level,
-	})
-	slog.SetDefault(slog.New(handler))
 }
 
 func main() {
 	// Initialize slog logger based on gin mode
-	initLogger()
+	initLogger(nil)
 
 	// Parse command line flags
 	setupMode := flag.Bool("setup", false, "Run setup wizard in CLI mode")
@@ -132,6 +138,7 @@ func runMainServer() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	initLogger(&cfg.Log)
 	if cfg.RunMode == config.RunModeSimple {
 		log.Println("⚠️  WARNING: Running in SIMPLE mode - billing and quota checks are DISABLED")
 	}
@@ -67,6 +67,13 @@ func provideCleanup(
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
 	opsCleanup *service.OpsCleanupService,
 	opsScheduledReport *service.OpsScheduledReportService,
+	accountProbe *service.AccountProbeService,
+	apiKeyAbuse *service.APIKeyAbuseService,
+	spendGuard *service.SpendGuardService,
+	referral *service.ReferralService,
+	userData *service.UserDataService,
+	subscriptionPlan *service.SubscriptionPlanService,
+	payment *service.PaymentService,
 	schedulerSnapshot *service.SchedulerSnapshotService,
 	tokenRefresh *service.TokenRefreshService,
 	accountExpiry *service.AccountExpiryService,
@@ -95,6 +102,48 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"AccountProbeService", func() error {
+				if accountProbe != nil {
+					accountProbe.Stop()
+				}
+				return nil
+			}},
+			{"APIKeyAbuseService", func() error {
+				if apiKeyAbuse != nil {
+					apiKeyAbuse.Stop()
+				}
+				return nil
+			}},
+			{"SpendGuardService", func() error {
+				if spendGuard != nil {
+					spendGuard.Stop()
+				}
+				return nil
+			}},
+			{"ReferralService", func() error {
+				if referral != nil {
+					referral.Stop()
+				}
+				return nil
+			}},
+			{"UserDataService", func() error {
+				if userData != nil {
+					userData.Stop()
+				}
+				return nil
+			}},
+			{"SubscriptionPlanService", func() error {
+				if subscriptionPlan != nil {
+					subscriptionPlan.Stop()
+				}
+				return nil
+			}},
+			{"PaymentService", func() error {
+				if payment != nil {
+					payment.Stop()
+				}
+				return nil
+			}},
 			{"OpsCleanupService", func() error {
 				if opsCleanup != nil {
 					opsCleanup.Stop()
@@ -63,26 +63,52 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	apiKeyCache := repository.NewAPIKeyCache(redisClient)
 	apiKeyService := service.NewAPIKeyService(apiKeyRepository, userRepository, groupRepository, userSubscriptionRepository, userGroupRateRepository, apiKeyCache, configConfig)
 	apiKeyAuthCacheInvalidator := service.ProvideAPIKeyAuthCacheInvalidator(apiKeyService)
-	promoService := service.NewPromoService(promoCodeRepository, userRepository, billingCacheService, client, apiKeyAuthCacheInvalidator)
+	promoService := service.NewPromoService(promoCodeRepository, userRepository, billingCacheService, client, apiKeyAuthCacheInvalidator, userSubscriptionRepository)
 	authService := service.NewAuthService(userRepository, redeemCodeRepository, refreshTokenCache, configConfig, settingService, emailService, turnstileService, emailQueueService, promoService)
 	userService := service.NewUserService(userRepository, apiKeyAuthCacheInvalidator)
 	subscriptionService := service.NewSubscriptionService(groupRepository, userSubscriptionRepository, billingCacheService)
 	redeemCache := repository.NewRedeemCache(redisClient)
-	redeemService := service.NewRedeemService(redeemCodeRepository, userRepository, subscriptionService, redeemCache, billingCacheService, client, apiKeyAuthCacheInvalidator)
+	redeemService := service.NewRedeemService(redeemCodeRepository, userRepository, subscriptionService, redeemCache, billingCacheService, client, apiKeyAuthCacheInvalidator, promoService)
 	secretEncryptor, err := repository.NewAESEncryptor(configConfig)
 	if err != nil {
 		return nil, err
 	}
 	totpCache := repository.NewTotpCache(redisClient)
 	totpService := service.NewTotpService(userRepository, secretEncryptor, totpCache, settingService, emailService, emailQueueService)
-	authHandler := handler.NewAuthHandler(configConfig, authService, userService, settingService, promoService, redeemService, totpService)
+	externalIdentityRepository := repository.NewExternalIdentityRepository(db)
+	oidcService := service.NewOIDCService(settingRepository, externalIdentityRepository, userRepository, authService)
+	webAuthnCredentialRepository := repository.NewWebAuthnCredentialRepository(db)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(db)
+	webAuthnCache := repository.NewWebAuthnCache(redisClient)
+	webAuthnService := service.NewWebAuthnService(configConfig, webAuthnCredentialRepository, recoveryCodeRepository, webAuthnCache, userRepository, settingService, emailService)
+	loginGuardCache := repository.NewLoginGuardCache(redisClient)
+	loginGuardService := service.NewLoginGuardService(configConfig, loginGuardCache, userRepository, emailService, settingService, turnstileService)
+	referralRepository := repository.NewReferralRepository(db)
+	referralService := service.ProvideReferralService(referralRepository, userRepository, settingRepository, billingCacheService, apiKeyAuthCacheInvalidator, redisClient)
+	authHandler := handler.NewAuthHandler(configConfig, authService, userService, settingService, promoService, redeemService, totpService, oidcService, webAuthnService, loginGuardService, referralService)
 	userHandler := handler.NewUserHandler(userService)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	usageLogRepository := repository.NewUsageLogRepository(client, db)
 	usageService := service.NewUsageService(usageLogRepository, userRepository, client, apiKeyAuthCacheInvalidator)
 	usageHandler := handler.NewUsageHandler(usageService, apiKeyService)
 	redeemHandler := handler.NewRedeemHandler(redeemService)
+	promoHandler := handler.NewPromoHandler(promoService, settingService)
+	referralHandler := handler.NewReferralHandler(referralService)
+	impersonationRepository := repository.NewImpersonationRepository(db)
+	impersonationService := service.NewImpersonationService(impersonationRepository, userRepository, authService)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
+	userDataRepository := repository.NewUserDataRepository(db)
+	userAttributeDefinitionRepository := repository.NewUserAttributeDefinitionRepository(client)
+	userAttributeValueRepository := repository.NewUserAttributeValueRepository(client)
+	userDataService := service.ProvideUserDataService(userDataRepository, userRepository, userAttributeDefinitionRepository, userAttributeValueRepository, apiKeyRepository, apiKeyService, userSubscriptionRepository, redeemCodeRepository, authService, emailService, settingService)
+	userDataHandler := handler.NewUserDataHandler(userDataService)
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
+	subscriptionPlanRepository := repository.NewSubscriptionPlanRepository(db)
+	subscriptionPlanService := service.ProvideSubscriptionPlanService(subscriptionPlanRepository, groupRepository, userRepository, userSubscriptionRepository, billingCacheService, apiKeyAuthCacheInvalidator, emailService, settingService, redisClient)
+	subscriptionPlanHandler := handler.NewSubscriptionPlanHandler(subscriptionPlanService)
+	paymentRepository := repository.NewPaymentRepository(db)
+	paymentService := service.ProvidePaymentService(paymentRepository, userRepository, redeemCodeRepository, promoService, settingRepository, settingService, billingCacheService, apiKeyAuthCacheInvalidator, client)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
 	announcementRepository := repository.NewAnnouncementRepository(client)
 	announcementReadRepository := repository.NewAnnouncementReadRepository(client)
 	announcementService := service.NewAnnouncementService(announcementRepository, announcementReadRepository, userRepository, userSubscriptionRepository)
@@ -119,7 +145,8 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	timeoutCounterCache := repository.NewTimeoutCounterCache(redisClient)
 	geminiTokenCache := repository.NewGeminiTokenCache(redisClient)
 	compositeTokenCacheInvalidator := service.NewCompositeTokenCacheInvalidator(geminiTokenCache)
-	rateLimitService := service.ProvideRateLimitService(accountRepository, usageLogRepository, configConfig, geminiQuotaService, tempUnschedCache, timeoutCounterCache, settingService, compositeTokenCacheInvalidator)
+	rateLimitBudgetCache := repository.NewRateLimitBudgetCache(redisClient)
+	rateLimitService := service.ProvideRateLimitService(accountRepository, usageLogRepository, configConfig, geminiQuotaService, tempUnschedCache, timeoutCounterCache, settingService, compositeTokenCacheInvalidator, rateLimitBudgetCache)
 	httpUpstream := repository.NewHTTPUpstream(configConfig)
 	claudeUsageFetcher := repository.NewClaudeUsageFetcher(httpUpstream)
 	antigravityQuotaFetcher := service.NewAntigravityQuotaFetcher(proxyRepository)
@@ -143,7 +170,7 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	antigravityOAuthHandler := admin.NewAntigravityOAuthHandler(antigravityOAuthService)
 	proxyHandler := admin.NewProxyHandler(adminService)
 	adminRedeemHandler := admin.NewRedeemHandler(adminService)
-	promoHandler := admin.NewPromoHandler(promoService)
+	adminPromoHandler := admin.NewPromoHandler(promoService)
 	opsRepository := repository.NewOpsRepository(db)
 	pricingRemoteClient := repository.ProvidePricingRemoteClient(configConfig)
 	pricingService, err := service.ProvidePricingService(configConfig, pricingRemoteClient)
@@ -171,21 +198,38 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	usageCleanupRepository := repository.NewUsageCleanupRepository(client, db)
 	usageCleanupService := service.ProvideUsageCleanupService(usageCleanupRepository, timingWheelService, dashboardAggregationService, configConfig)
 	adminUsageHandler := admin.NewUsageHandler(usageService, apiKeyService, adminService, usageCleanupService)
-	userAttributeDefinitionRepository := repository.NewUserAttributeDefinitionRepository(client)
-	userAttributeValueRepository := repository.NewUserAttributeValueRepository(client)
 	userAttributeService := service.NewUserAttributeService(userAttributeDefinitionRepository, userAttributeValueRepository)
 	userAttributeHandler := admin.NewUserAttributeHandler(userAttributeService)
 	errorPassthroughRepository := repository.NewErrorPassthroughRepository(client)
 	errorPassthroughCache := repository.NewErrorPassthroughCache(redisClient)
 	errorPassthroughService := service.NewErrorPassthroughService(errorPassthroughRepository, errorPassthroughCache)
 	errorPassthroughHandler := admin.NewErrorPassthroughHandler(errorPassthroughService)
-	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, adminAnnouncementHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, errorPassthroughHandler)
+	accountProbeRepository := repository.NewAccountProbeRepository(db)
+	accountProbeService := service.ProvideAccountProbeService(accountRepository, accountProbeRepository, settingRepository, accountTestService, redisClient)
+	accountProbeHandler := admin.NewAccountProbeHandler(accountProbeService)
+	apiKeyAbuseRepository := repository.NewAPIKeyAbuseRepository(db)
+	apiKeyAbuseService := service.ProvideAPIKeyAbuseService(apiKeyAbuseRepository, apiKeyRepository, userRepository, settingRepository, apiKeyService, emailService, redisClient)
+	apiKeyAbuseHandler := admin.NewAPIKeyAbuseHandler(apiKeyAbuseService)
+	spendGuardRepository := repository.NewSpendGuardRepository(db)
+	spendGuardService := service.ProvideSpendGuardService(spendGuardRepository, apiKeyRepository, userRepository, settingRepository, opsRepository, apiKeyService, emailService, redisClient)
+	spendGuardHandler := admin.NewSpendGuardHandler(spendGuardService)
+	oidcProviderHandler := admin.NewOIDCProviderHandler(oidcService)
+	userSessionHandler := admin.NewUserSessionHandler(authService)
+	loginGuardHandler := admin.NewLoginGuardHandler(loginGuardService)
+	adminReferralHandler := admin.NewReferralHandler(referralService)
+	adminImpersonationHandler := admin.NewImpersonationHandler(impersonationService)
+	accountDeletionHandler := admin.NewAccountDeletionHandler(userDataService)
+	adminSubscriptionPlanHandler := admin.NewSubscriptionPlanHandler(subscriptionPlanService)
+	adminPaymentHandler := admin.NewPaymentHandler(paymentService)
+	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, adminAnnouncementHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, adminPromoHandler, settingHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, errorPassthroughHandler, accountProbeHandler, apiKeyAbuseHandler, spendGuardHandler, oidcProviderHandler, userSessionHandler, loginGuardHandler, adminReferralHandler, adminImpersonationHandler, accountDeletionHandler, adminSubscriptionPlanHandler, adminPaymentHandler)
 	gatewayHandler := handler.NewGatewayHandler(gatewayService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, usageService, apiKeyService, errorPassthroughService, configConfig)
 	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, apiKeyService, errorPassthroughService, configConfig)
 	handlerSettingHandler := handler.ProvideSettingHandler(settingService, buildInfo)
 	totpHandler := handler.NewTotpHandler(totpService)
-	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, redeemHandler, subscriptionHandler, announcementHandler, adminHandlers, gatewayHandler, openAIGatewayHandler, handlerSettingHandler, totpHandler)
-	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService, userService)
+	webAuthnHandler := handler.NewWebAuthnHandler(webAuthnService)
+	statusHandler := handler.NewStatusHandler(opsService)
+	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, redeemHandler, promoHandler, referralHandler, impersonationHandler, userDataHandler, subscriptionHandler, subscriptionPlanHandler, paymentHandler, announcementHandler, adminHandlers, gatewayHandler, openAIGatewayHandler, handlerSettingHandler, totpHandler, webAuthnHandler, statusHandler)
+	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService, userService, impersonationService)
 	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(authService, userService, settingService)
 	apiKeyAuthMiddleware := middleware.NewAPIKeyAuthMiddleware(apiKeyService, subscriptionService, configConfig)
 	engine := server.ProvideRouter(configConfig, handlers, jwtAuthMiddleware, adminAuthMiddleware, apiKeyAuthMiddleware, apiKeyService, subscriptionService, opsService, settingService, redisClient)
@@ -198,7 +242,7 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	tokenRefreshService := service.ProvideTokenRefreshService(accountRepository, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, compositeTokenCacheInvalidator, schedulerCache, configConfig)
 	accountExpiryService := service.ProvideAccountExpiryService(accountRepository)
 	subscriptionExpiryService := service.ProvideSubscriptionExpiryService(userSubscriptionRepository)
-	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, opsScheduledReportService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, usageCleanupService, pricingService, emailQueueService, billingCacheService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService)
+	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, opsScheduledReportService, accountProbeService, apiKeyAbuseService, spendGuardService, referralService, userDataService, subscriptionPlanService, paymentService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, usageCleanupService, pricingService, emailQueueService, billingCacheService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService)
 	application := &Application{
 		Server:  httpServer,
 		Cleanup: v,
@@ -228,6 +272,13 @@ func provideCleanup(
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
 	opsCleanup *service.OpsCleanupService,
 	opsScheduledReport *service.OpsScheduledReportService,
+	accountProbe *service.AccountProbeService,
+	apiKeyAbuse *service.APIKeyAbuseService,
+	spendGuard *service.SpendGuardService,
+	referral *service.ReferralService,
+	userData *service.UserDataService,
+	subscriptionPlan *service.SubscriptionPlanService,
+	payment *service.PaymentService,
 	schedulerSnapshot *service.SchedulerSnapshotService,
 	tokenRefresh *service.TokenRefreshService,
 	accountExpiry *service.AccountExpiryService,
@@ -255,6 +306,48 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"AccountProbeService", func() error {
+				if accountProbe != nil {
+					accountProbe.Stop()
+				}
+				return nil
+			}},
+			{"APIKeyAbuseService", func() error {
+				if apiKeyAbuse != nil {
+					apiKeyAbuse.Stop()
+				}
+				return nil
+			}},
+			{"SpendGuardService", func() error {
+				if spendGuard != nil {
+					spendGuard.Stop()
+				}
+				return nil
+			}},
+			{"ReferralService", func() error {
+				if referral != nil {
+					referral.Stop()
+				}
+				return nil
+			}},
+			{"UserDataService", func() error {
+				if userData != nil {
+					userData.Stop()
+				}
+				return nil
+			}},
+			{"SubscriptionPlanService", func() error {
+				if subscriptionPlan != nil {
+					subscriptionPlan.Stop()
+				}
+				return nil
+			}},
+			{"PaymentService", func() error {
+				if payment != nil {
+					payment.Stop()
+				}
+				return nil
+			}},
 			{"OpsCleanupService", func() error {
 				if opsCleanup != nil {
 					opsCleanup.Stop()
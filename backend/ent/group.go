@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"github.com/Wei-Shaw/sub2api/ent/group"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 )
 
 // Group is the model entity for the Group schema.
@@ -72,6 +73,14 @@ type Group struct {
 	SupportedModelScopes []string `json:"supported_model_scopes,omitempty"`
 	// 分组显示排序，数值越小越靠前
 	SortOrder int `json:"sort_order,omitempty"`
+	// 是否启用对冲请求：首账号响应头超时后向第二个账号并发请求
+	HedgeEnabled bool `json:"hedge_enabled,omitempty"`
+	// 触发对冲请求的响应头等待阈值（毫秒）
+	HedgeDelayMs int `json:"hedge_delay_ms,omitempty"`
+	// 对冲额外上游 token 占正常用量的比例上限(0~1)
+	HedgeMaxRatio float64 `json:"hedge_max_ratio,omitempty"`
+	// 分组调度时间窗口，作为未单独配置窗口的账号的默认规则
+	ScheduleRule *domain.ScheduleRule `json:"schedule_rule,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the GroupQuery when eager-loading is set.
 	Edges        GroupEdges `json:"edges"`
@@ -178,13 +187,13 @@ func (*Group) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case group.FieldModelRouting, group.FieldSupportedModelScopes:
+		case group.FieldModelRouting, group.FieldSupportedModelScopes, group.FieldScheduleRule:
 			values[i] = new([]byte)
-		case group.FieldIsExclusive, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldMcpXMLInject:
+		case group.FieldIsExclusive, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldMcpXMLInject, group.FieldHedgeEnabled:
 			values[i] = new(sql.NullBool)
-		case group.FieldRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldCacheReadTransferRatio, group.FieldCacheReadTransferProbability:
+		case group.FieldRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldCacheReadTransferRatio, group.FieldCacheReadTransferProbability, group.FieldHedgeMaxRatio:
 			values[i] = new(sql.NullFloat64)
-		case group.FieldID, group.FieldDefaultValidityDays, group.FieldFallbackGroupID, group.FieldFallbackGroupIDOnInvalidRequest, group.FieldSortOrder:
+		case group.FieldID, group.FieldDefaultValidityDays, group.FieldFallbackGroupID, group.FieldFallbackGroupIDOnInvalidRequest, group.FieldSortOrder, group.FieldHedgeDelayMs:
 			values[i] = new(sql.NullInt64)
 		case group.FieldName, group.FieldDescription, group.FieldStatus, group.FieldPlatform, group.FieldSubscriptionType:
 			values[i] = new(sql.NullString)
@@ -387,6 +396,32 @@ func (_m *Group) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.SortOrder = int(value.Int64)
 			}
+		case group.FieldHedgeEnabled:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field hedge_enabled", values[i])
+			} else if value.Valid {
+				_m.HedgeEnabled = value.Bool
+			}
+		case group.FieldHedgeDelayMs:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field hedge_delay_ms", values[i])
+			} else if value.Valid {
+				_m.HedgeDelayMs = int(value.Int64)
+			}
+		case group.FieldHedgeMaxRatio:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field hedge_max_ratio", values[i])
+			} else if value.Valid {
+				_m.HedgeMaxRatio = value.Float64
+			}
+		case group.FieldScheduleRule:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field schedule_rule", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.ScheduleRule); err != nil {
+					return fmt.Errorf("unmarshal field schedule_rule: %w", err)
+				}
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -563,6 +598,18 @@ func (_m *Group) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("sort_order=")
 	builder.WriteString(fmt.Sprintf("%v", _m.SortOrder))
+	builder.WriteString(", ")
+	builder.WriteString("hedge_enabled=")
+	builder.WriteString(fmt.Sprintf("%v", _m.HedgeEnabled))
+	builder.WriteString(", ")
+	builder.WriteString("hedge_delay_ms=")
+	builder.WriteString(fmt.Sprintf("%v", _m.HedgeDelayMs))
+	builder.WriteString(", ")
+	builder.WriteString("hedge_max_ratio=")
+	builder.WriteString(fmt.Sprintf("%v", _m.HedgeMaxRatio))
+	builder.WriteString(", ")
+	builder.WriteString("schedule_rule=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ScheduleRule))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -69,6 +69,14 @@ const (
 	FieldSupportedModelScopes = "supported_model_scopes"
 	// FieldSortOrder holds the string denoting the sort_order field in the database.
 	FieldSortOrder = "sort_order"
+	// FieldHedgeEnabled holds the string denoting the hedge_enabled field in the database.
+	FieldHedgeEnabled = "hedge_enabled"
+	// FieldHedgeDelayMs holds the string denoting the hedge_delay_ms field in the database.
+	FieldHedgeDelayMs = "hedge_delay_ms"
+	// FieldHedgeMaxRatio holds the string denoting the hedge_max_ratio field in the database.
+	FieldHedgeMaxRatio = "hedge_max_ratio"
+	// FieldScheduleRule holds the string denoting the schedule_rule field in the database.
+	FieldScheduleRule = "schedule_rule"
 	// EdgeAPIKeys holds the string denoting the api_keys edge name in mutations.
 	EdgeAPIKeys = "api_keys"
 	// EdgeRedeemCodes holds the string denoting the redeem_codes edge name in mutations.
@@ -171,6 +179,10 @@ var Columns = []string{
 	FieldMcpXMLInject,
 	FieldSupportedModelScopes,
 	FieldSortOrder,
+	FieldHedgeEnabled,
+	FieldHedgeDelayMs,
+	FieldHedgeMaxRatio,
+	FieldScheduleRule,
 }
 
 var (
@@ -240,6 +252,12 @@ var (
 	DefaultSupportedModelScopes []string
 	// DefaultSortOrder holds the default value on creation for the "sort_order" field.
 	DefaultSortOrder int
+	// DefaultHedgeEnabled holds the default value on creation for the "hedge_enabled" field.
+	DefaultHedgeEnabled bool
+	// DefaultHedgeDelayMs holds the default value on creation for the "hedge_delay_ms" field.
+	DefaultHedgeDelayMs int
+	// DefaultHedgeMaxRatio holds the default value on creation for the "hedge_max_ratio" field.
+	DefaultHedgeMaxRatio float64
 )
 
 // OrderOption defines the ordering options for the Group queries.
@@ -375,6 +393,21 @@ func BySortOrder(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldSortOrder, opts...).ToFunc()
 }
 
+// ByHedgeEnabled orders the results by the hedge_enabled field.
+func ByHedgeEnabled(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHedgeEnabled, opts...).ToFunc()
+}
+
+// ByHedgeDelayMs orders the results by the hedge_delay_ms field.
+func ByHedgeDelayMs(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHedgeDelayMs, opts...).ToFunc()
+}
+
+// ByHedgeMaxRatio orders the results by the hedge_max_ratio field.
+func ByHedgeMaxRatio(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHedgeMaxRatio, opts...).ToFunc()
+}
+
 // ByAPIKeysCount orders the results by api_keys count.
 func ByAPIKeysCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
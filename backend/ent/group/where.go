@@ -180,6 +180,21 @@ func SortOrder(v int) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSortOrder, v))
 }
 
+// HedgeEnabled applies equality check predicate on the "hedge_enabled" field. It's identical to HedgeEnabledEQ.
+func HedgeEnabled(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeEnabled, v))
+}
+
+// HedgeDelayMs applies equality check predicate on the "hedge_delay_ms" field. It's identical to HedgeDelayMsEQ.
+func HedgeDelayMs(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeDelayMs, v))
+}
+
+// HedgeMaxRatio applies equality check predicate on the "hedge_max_ratio" field. It's identical to HedgeMaxRatioEQ.
+func HedgeMaxRatio(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeMaxRatio, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldCreatedAt, v))
@@ -1295,6 +1310,106 @@ func SortOrderLTE(v int) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldSortOrder, v))
 }
 
+// HedgeEnabledEQ applies the EQ predicate on the "hedge_enabled" field.
+func HedgeEnabledEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeEnabled, v))
+}
+
+// HedgeEnabledNEQ applies the NEQ predicate on the "hedge_enabled" field.
+func HedgeEnabledNEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldHedgeEnabled, v))
+}
+
+// HedgeDelayMsEQ applies the EQ predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeDelayMs, v))
+}
+
+// HedgeDelayMsNEQ applies the NEQ predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldHedgeDelayMs, v))
+}
+
+// HedgeDelayMsIn applies the In predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldHedgeDelayMs, vs...))
+}
+
+// HedgeDelayMsNotIn applies the NotIn predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldHedgeDelayMs, vs...))
+}
+
+// HedgeDelayMsGT applies the GT predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldHedgeDelayMs, v))
+}
+
+// HedgeDelayMsGTE applies the GTE predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldHedgeDelayMs, v))
+}
+
+// HedgeDelayMsLT applies the LT predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldHedgeDelayMs, v))
+}
+
+// HedgeDelayMsLTE applies the LTE predicate on the "hedge_delay_ms" field.
+func HedgeDelayMsLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldHedgeDelayMs, v))
+}
+
+// HedgeMaxRatioEQ applies the EQ predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeMaxRatio, v))
+}
+
+// HedgeMaxRatioNEQ applies the NEQ predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioNEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldHedgeMaxRatio, v))
+}
+
+// HedgeMaxRatioIn applies the In predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldHedgeMaxRatio, vs...))
+}
+
+// HedgeMaxRatioNotIn applies the NotIn predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioNotIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldHedgeMaxRatio, vs...))
+}
+
+// HedgeMaxRatioGT applies the GT predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioGT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldHedgeMaxRatio, v))
+}
+
+// HedgeMaxRatioGTE applies the GTE predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioGTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldHedgeMaxRatio, v))
+}
+
+// HedgeMaxRatioLT applies the LT predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioLT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldHedgeMaxRatio, v))
+}
+
+// HedgeMaxRatioLTE applies the LTE predicate on the "hedge_max_ratio" field.
+func HedgeMaxRatioLTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldHedgeMaxRatio, v))
+}
+
+// ScheduleRuleIsNil applies the IsNil predicate on the "schedule_rule" field.
+func ScheduleRuleIsNil() predicate.Group {
+	return predicate.Group(sql.FieldIsNull(FieldScheduleRule))
+}
+
+// ScheduleRuleNotNil applies the NotNil predicate on the "schedule_rule" field.
+func ScheduleRuleNotNil() predicate.Group {
+	return predicate.Group(sql.FieldNotNull(FieldScheduleRule))
+}
+
 // HasAPIKeys applies the HasEdge predicate on the "api_keys" edge.
 func HasAPIKeys() predicate.Group {
 	return predicate.Group(func(s *sql.Selector) {
@@ -18,6 +18,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 )
 
 // GroupCreate is the builder for creating a Group entity.
@@ -382,6 +383,54 @@ func (_c *GroupCreate) SetNillableSortOrder(v *int) *GroupCreate {
 	return _c
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (_c *GroupCreate) SetHedgeEnabled(v bool) *GroupCreate {
+	_c.mutation.SetHedgeEnabled(v)
+	return _c
+}
+
+// SetNillableHedgeEnabled sets the "hedge_enabled" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableHedgeEnabled(v *bool) *GroupCreate {
+	if v != nil {
+		_c.SetHedgeEnabled(*v)
+	}
+	return _c
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (_c *GroupCreate) SetHedgeDelayMs(v int) *GroupCreate {
+	_c.mutation.SetHedgeDelayMs(v)
+	return _c
+}
+
+// SetNillableHedgeDelayMs sets the "hedge_delay_ms" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableHedgeDelayMs(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetHedgeDelayMs(*v)
+	}
+	return _c
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (_c *GroupCreate) SetHedgeMaxRatio(v float64) *GroupCreate {
+	_c.mutation.SetHedgeMaxRatio(v)
+	return _c
+}
+
+// SetNillableHedgeMaxRatio sets the "hedge_max_ratio" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableHedgeMaxRatio(v *float64) *GroupCreate {
+	if v != nil {
+		_c.SetHedgeMaxRatio(*v)
+	}
+	return _c
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (_c *GroupCreate) SetScheduleRule(v *domain.ScheduleRule) *GroupCreate {
+	_c.mutation.SetScheduleRule(v)
+	return _c
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_c *GroupCreate) AddAPIKeyIDs(ids ...int64) *GroupCreate {
 	_c.mutation.AddAPIKeyIDs(ids...)
@@ -575,6 +624,18 @@ func (_c *GroupCreate) defaults() error {
 		v := group.DefaultSortOrder
 		_c.mutation.SetSortOrder(v)
 	}
+	if _, ok := _c.mutation.HedgeEnabled(); !ok {
+		v := group.DefaultHedgeEnabled
+		_c.mutation.SetHedgeEnabled(v)
+	}
+	if _, ok := _c.mutation.HedgeDelayMs(); !ok {
+		v := group.DefaultHedgeDelayMs
+		_c.mutation.SetHedgeDelayMs(v)
+	}
+	if _, ok := _c.mutation.HedgeMaxRatio(); !ok {
+		v := group.DefaultHedgeMaxRatio
+		_c.mutation.SetHedgeMaxRatio(v)
+	}
 	return nil
 }
 
@@ -648,6 +709,15 @@ func (_c *GroupCreate) check() error {
 	if _, ok := _c.mutation.SortOrder(); !ok {
 		return &ValidationError{Name: "sort_order", err: errors.New(`ent: missing required field "Group.sort_order"`)}
 	}
+	if _, ok := _c.mutation.HedgeEnabled(); !ok {
+		return &ValidationError{Name: "hedge_enabled", err: errors.New(`ent: missing required field "Group.hedge_enabled"`)}
+	}
+	if _, ok := _c.mutation.HedgeDelayMs(); !ok {
+		return &ValidationError{Name: "hedge_delay_ms", err: errors.New(`ent: missing required field "Group.hedge_delay_ms"`)}
+	}
+	if _, ok := _c.mutation.HedgeMaxRatio(); !ok {
+		return &ValidationError{Name: "hedge_max_ratio", err: errors.New(`ent: missing required field "Group.hedge_max_ratio"`)}
+	}
 	return nil
 }
 
@@ -783,6 +853,22 @@ func (_c *GroupCreate) createSpec() (*Group, *sqlgraph.CreateSpec) {
 		_spec.SetField(group.FieldSortOrder, field.TypeInt, value)
 		_node.SortOrder = value
 	}
+	if value, ok := _c.mutation.HedgeEnabled(); ok {
+		_spec.SetField(group.FieldHedgeEnabled, field.TypeBool, value)
+		_node.HedgeEnabled = value
+	}
+	if value, ok := _c.mutation.HedgeDelayMs(); ok {
+		_spec.SetField(group.FieldHedgeDelayMs, field.TypeInt, value)
+		_node.HedgeDelayMs = value
+	}
+	if value, ok := _c.mutation.HedgeMaxRatio(); ok {
+		_spec.SetField(group.FieldHedgeMaxRatio, field.TypeFloat64, value)
+		_node.HedgeMaxRatio = value
+	}
+	if value, ok := _c.mutation.ScheduleRule(); ok {
+		_spec.SetField(group.FieldScheduleRule, field.TypeJSON, value)
+		_node.ScheduleRule = value
+	}
 	if nodes := _c.mutation.APIKeysIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -1395,6 +1481,72 @@ func (u *GroupUpsert) AddSortOrder(v int) *GroupUpsert {
 	return u
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (u *GroupUpsert) SetHedgeEnabled(v bool) *GroupUpsert {
+	u.Set(group.FieldHedgeEnabled, v)
+	return u
+}
+
+// UpdateHedgeEnabled sets the "hedge_enabled" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateHedgeEnabled() *GroupUpsert {
+	u.SetExcluded(group.FieldHedgeEnabled)
+	return u
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (u *GroupUpsert) SetHedgeDelayMs(v int) *GroupUpsert {
+	u.Set(group.FieldHedgeDelayMs, v)
+	return u
+}
+
+// UpdateHedgeDelayMs sets the "hedge_delay_ms" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateHedgeDelayMs() *GroupUpsert {
+	u.SetExcluded(group.FieldHedgeDelayMs)
+	return u
+}
+
+// AddHedgeDelayMs adds v to the "hedge_delay_ms" field.
+func (u *GroupUpsert) AddHedgeDelayMs(v int) *GroupUpsert {
+	u.Add(group.FieldHedgeDelayMs, v)
+	return u
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (u *GroupUpsert) SetHedgeMaxRatio(v float64) *GroupUpsert {
+	u.Set(group.FieldHedgeMaxRatio, v)
+	return u
+}
+
+// UpdateHedgeMaxRatio sets the "hedge_max_ratio" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateHedgeMaxRatio() *GroupUpsert {
+	u.SetExcluded(group.FieldHedgeMaxRatio)
+	return u
+}
+
+// AddHedgeMaxRatio adds v to the "hedge_max_ratio" field.
+func (u *GroupUpsert) AddHedgeMaxRatio(v float64) *GroupUpsert {
+	u.Add(group.FieldHedgeMaxRatio, v)
+	return u
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (u *GroupUpsert) SetScheduleRule(v *domain.ScheduleRule) *GroupUpsert {
+	u.Set(group.FieldScheduleRule, v)
+	return u
+}
+
+// UpdateScheduleRule sets the "schedule_rule" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateScheduleRule() *GroupUpsert {
+	u.SetExcluded(group.FieldScheduleRule)
+	return u
+}
+
+// ClearScheduleRule clears the value of the "schedule_rule" field.
+func (u *GroupUpsert) ClearScheduleRule() *GroupUpsert {
+	u.SetNull(group.FieldScheduleRule)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create.
 // Using this option is equivalent to using:
 //
@@ -1972,6 +2124,83 @@ func (u *GroupUpsertOne) UpdateSortOrder() *GroupUpsertOne {
 	})
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (u *GroupUpsertOne) SetHedgeEnabled(v bool) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeEnabled(v)
+	})
+}
+
+// UpdateHedgeEnabled sets the "hedge_enabled" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateHedgeEnabled() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeEnabled()
+	})
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (u *GroupUpsertOne) SetHedgeDelayMs(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeDelayMs(v)
+	})
+}
+
+// AddHedgeDelayMs adds v to the "hedge_delay_ms" field.
+func (u *GroupUpsertOne) AddHedgeDelayMs(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddHedgeDelayMs(v)
+	})
+}
+
+// UpdateHedgeDelayMs sets the "hedge_delay_ms" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateHedgeDelayMs() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeDelayMs()
+	})
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (u *GroupUpsertOne) SetHedgeMaxRatio(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeMaxRatio(v)
+	})
+}
+
+// AddHedgeMaxRatio adds v to the "hedge_max_ratio" field.
+func (u *GroupUpsertOne) AddHedgeMaxRatio(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddHedgeMaxRatio(v)
+	})
+}
+
+// UpdateHedgeMaxRatio sets the "hedge_max_ratio" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateHedgeMaxRatio() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeMaxRatio()
+	})
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (u *GroupUpsertOne) SetScheduleRule(v *domain.ScheduleRule) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetScheduleRule(v)
+	})
+}
+
+// UpdateScheduleRule sets the "schedule_rule" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateScheduleRule() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateScheduleRule()
+	})
+}
+
+// ClearScheduleRule clears the value of the "schedule_rule" field.
+func (u *GroupUpsertOne) ClearScheduleRule() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearScheduleRule()
+	})
+}
+
 // Exec executes the query.
 func (u *GroupUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -2715,6 +2944,83 @@ func (u *GroupUpsertBulk) UpdateSortOrder() *GroupUpsertBulk {
 	})
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (u *GroupUpsertBulk) SetHedgeEnabled(v bool) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeEnabled(v)
+	})
+}
+
+// UpdateHedgeEnabled sets the "hedge_enabled" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateHedgeEnabled() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeEnabled()
+	})
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (u *GroupUpsertBulk) SetHedgeDelayMs(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeDelayMs(v)
+	})
+}
+
+// AddHedgeDelayMs adds v to the "hedge_delay_ms" field.
+func (u *GroupUpsertBulk) AddHedgeDelayMs(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddHedgeDelayMs(v)
+	})
+}
+
+// UpdateHedgeDelayMs sets the "hedge_delay_ms" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateHedgeDelayMs() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeDelayMs()
+	})
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (u *GroupUpsertBulk) SetHedgeMaxRatio(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeMaxRatio(v)
+	})
+}
+
+// AddHedgeMaxRatio adds v to the "hedge_max_ratio" field.
+func (u *GroupUpsertBulk) AddHedgeMaxRatio(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddHedgeMaxRatio(v)
+	})
+}
+
+// UpdateHedgeMaxRatio sets the "hedge_max_ratio" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateHedgeMaxRatio() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeMaxRatio()
+	})
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (u *GroupUpsertBulk) SetScheduleRule(v *domain.ScheduleRule) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetScheduleRule(v)
+	})
+}
+
+// UpdateScheduleRule sets the "schedule_rule" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateScheduleRule() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateScheduleRule()
+	})
+}
+
+// ClearScheduleRule clears the value of the "schedule_rule" field.
+func (u *GroupUpsertBulk) ClearScheduleRule() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearScheduleRule()
+	})
+}
+
 // Exec executes the query.
 func (u *GroupUpsertBulk) Exec(ctx context.Context) error {
 	if u.create.err != nil {
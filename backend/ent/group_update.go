@@ -20,6 +20,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 )
 
 // GroupUpdate is the builder for updating Group entities.
@@ -538,6 +539,74 @@ func (_u *GroupUpdate) AddSortOrder(v int) *GroupUpdate {
 	return _u
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (_u *GroupUpdate) SetHedgeEnabled(v bool) *GroupUpdate {
+	_u.mutation.SetHedgeEnabled(v)
+	return _u
+}
+
+// SetNillableHedgeEnabled sets the "hedge_enabled" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableHedgeEnabled(v *bool) *GroupUpdate {
+	if v != nil {
+		_u.SetHedgeEnabled(*v)
+	}
+	return _u
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (_u *GroupUpdate) SetHedgeDelayMs(v int) *GroupUpdate {
+	_u.mutation.ResetHedgeDelayMs()
+	_u.mutation.SetHedgeDelayMs(v)
+	return _u
+}
+
+// SetNillableHedgeDelayMs sets the "hedge_delay_ms" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableHedgeDelayMs(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetHedgeDelayMs(*v)
+	}
+	return _u
+}
+
+// AddHedgeDelayMs adds value to the "hedge_delay_ms" field.
+func (_u *GroupUpdate) AddHedgeDelayMs(v int) *GroupUpdate {
+	_u.mutation.AddHedgeDelayMs(v)
+	return _u
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (_u *GroupUpdate) SetHedgeMaxRatio(v float64) *GroupUpdate {
+	_u.mutation.ResetHedgeMaxRatio()
+	_u.mutation.SetHedgeMaxRatio(v)
+	return _u
+}
+
+// SetNillableHedgeMaxRatio sets the "hedge_max_ratio" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableHedgeMaxRatio(v *float64) *GroupUpdate {
+	if v != nil {
+		_u.SetHedgeMaxRatio(*v)
+	}
+	return _u
+}
+
+// AddHedgeMaxRatio adds value to the "hedge_max_ratio" field.
+func (_u *GroupUpdate) AddHedgeMaxRatio(v float64) *GroupUpdate {
+	_u.mutation.AddHedgeMaxRatio(v)
+	return _u
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (_u *GroupUpdate) SetScheduleRule(v *domain.ScheduleRule) *GroupUpdate {
+	_u.mutation.SetScheduleRule(v)
+	return _u
+}
+
+// ClearScheduleRule clears the value of the "schedule_rule" field.
+func (_u *GroupUpdate) ClearScheduleRule() *GroupUpdate {
+	_u.mutation.ClearScheduleRule()
+	return _u
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_u *GroupUpdate) AddAPIKeyIDs(ids ...int64) *GroupUpdate {
 	_u.mutation.AddAPIKeyIDs(ids...)
@@ -993,6 +1062,27 @@ func (_u *GroupUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.AddedSortOrder(); ok {
 		_spec.AddField(group.FieldSortOrder, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.HedgeEnabled(); ok {
+		_spec.SetField(group.FieldHedgeEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.HedgeDelayMs(); ok {
+		_spec.SetField(group.FieldHedgeDelayMs, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedHedgeDelayMs(); ok {
+		_spec.AddField(group.FieldHedgeDelayMs, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.HedgeMaxRatio(); ok {
+		_spec.SetField(group.FieldHedgeMaxRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedHedgeMaxRatio(); ok {
+		_spec.AddField(group.FieldHedgeMaxRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.ScheduleRule(); ok {
+		_spec.SetField(group.FieldScheduleRule, field.TypeJSON, value)
+	}
+	if _u.mutation.ScheduleRuleCleared() {
+		_spec.ClearField(group.FieldScheduleRule, field.TypeJSON)
+	}
 	if _u.mutation.APIKeysCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -1810,6 +1900,74 @@ func (_u *GroupUpdateOne) AddSortOrder(v int) *GroupUpdateOne {
 	return _u
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (_u *GroupUpdateOne) SetHedgeEnabled(v bool) *GroupUpdateOne {
+	_u.mutation.SetHedgeEnabled(v)
+	return _u
+}
+
+// SetNillableHedgeEnabled sets the "hedge_enabled" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableHedgeEnabled(v *bool) *GroupUpdateOne {
+	if v != nil {
+		_u.SetHedgeEnabled(*v)
+	}
+	return _u
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (_u *GroupUpdateOne) SetHedgeDelayMs(v int) *GroupUpdateOne {
+	_u.mutation.ResetHedgeDelayMs()
+	_u.mutation.SetHedgeDelayMs(v)
+	return _u
+}
+
+// SetNillableHedgeDelayMs sets the "hedge_delay_ms" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableHedgeDelayMs(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetHedgeDelayMs(*v)
+	}
+	return _u
+}
+
+// AddHedgeDelayMs adds value to the "hedge_delay_ms" field.
+func (_u *GroupUpdateOne) AddHedgeDelayMs(v int) *GroupUpdateOne {
+	_u.mutation.AddHedgeDelayMs(v)
+	return _u
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (_u *GroupUpdateOne) SetHedgeMaxRatio(v float64) *GroupUpdateOne {
+	_u.mutation.ResetHedgeMaxRatio()
+	_u.mutation.SetHedgeMaxRatio(v)
+	return _u
+}
+
+// SetNillableHedgeMaxRatio sets the "hedge_max_ratio" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableHedgeMaxRatio(v *float64) *GroupUpdateOne {
+	if v != nil {
+		_u.SetHedgeMaxRatio(*v)
+	}
+	return _u
+}
+
+// AddHedgeMaxRatio adds value to the "hedge_max_ratio" field.
+func (_u *GroupUpdateOne) AddHedgeMaxRatio(v float64) *GroupUpdateOne {
+	_u.mutation.AddHedgeMaxRatio(v)
+	return _u
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (_u *GroupUpdateOne) SetScheduleRule(v *domain.ScheduleRule) *GroupUpdateOne {
+	_u.mutation.SetScheduleRule(v)
+	return _u
+}
+
+// ClearScheduleRule clears the value of the "schedule_rule" field.
+func (_u *GroupUpdateOne) ClearScheduleRule() *GroupUpdateOne {
+	_u.mutation.ClearScheduleRule()
+	return _u
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_u *GroupUpdateOne) AddAPIKeyIDs(ids ...int64) *GroupUpdateOne {
 	_u.mutation.AddAPIKeyIDs(ids...)
@@ -2295,6 +2453,27 @@ func (_u *GroupUpdateOne) sqlSave(ctx context.Context) (_node *Group, err error)
 	if value, ok := _u.mutation.AddedSortOrder(); ok {
 		_spec.AddField(group.FieldSortOrder, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.HedgeEnabled(); ok {
+		_spec.SetField(group.FieldHedgeEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.HedgeDelayMs(); ok {
+		_spec.SetField(group.FieldHedgeDelayMs, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedHedgeDelayMs(); ok {
+		_spec.AddField(group.FieldHedgeDelayMs, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.HedgeMaxRatio(); ok {
+		_spec.SetField(group.FieldHedgeMaxRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedHedgeMaxRatio(); ok {
+		_spec.AddField(group.FieldHedgeMaxRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.ScheduleRule(); ok {
+		_spec.SetField(group.FieldScheduleRule, field.TypeJSON, value)
+	}
+	if _u.mutation.ScheduleRuleCleared() {
+		_spec.ClearField(group.FieldScheduleRule, field.TypeJSON)
+	}
 	if _u.mutation.APIKeysCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
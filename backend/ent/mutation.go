@@ -7119,6 +7119,12 @@ type GroupMutation struct {
 	appendsupported_model_scopes            []string
 	sort_order                              *int
 	addsort_order                           *int
+	hedge_enabled                           *bool
+	hedge_delay_ms                          *int
+	addhedge_delay_ms                       *int
+	hedge_max_ratio                         *float64
+	addhedge_max_ratio                      *float64
+	schedule_rule                           **domain.ScheduleRule
 	clearedFields                           map[string]struct{}
 	api_keys                                map[int64]struct{}
 	removedapi_keys                         map[int64]struct{}
@@ -8639,6 +8645,203 @@ func (m *GroupMutation) ResetSortOrder() {
 	m.addsort_order = nil
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (m *GroupMutation) SetHedgeEnabled(b bool) {
+	m.hedge_enabled = &b
+}
+
+// HedgeEnabled returns the value of the "hedge_enabled" field in the mutation.
+func (m *GroupMutation) HedgeEnabled() (r bool, exists bool) {
+	v := m.hedge_enabled
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHedgeEnabled returns the old "hedge_enabled" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldHedgeEnabled(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHedgeEnabled is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHedgeEnabled requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHedgeEnabled: %w", err)
+	}
+	return oldValue.HedgeEnabled, nil
+}
+
+// ResetHedgeEnabled resets all changes to the "hedge_enabled" field.
+func (m *GroupMutation) ResetHedgeEnabled() {
+	m.hedge_enabled = nil
+}
+
+// SetHedgeDelayMs sets the "hedge_delay_ms" field.
+func (m *GroupMutation) SetHedgeDelayMs(i int) {
+	m.hedge_delay_ms = &i
+	m.addhedge_delay_ms = nil
+}
+
+// HedgeDelayMs returns the value of the "hedge_delay_ms" field in the mutation.
+func (m *GroupMutation) HedgeDelayMs() (r int, exists bool) {
+	v := m.hedge_delay_ms
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHedgeDelayMs returns the old "hedge_delay_ms" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldHedgeDelayMs(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHedgeDelayMs is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHedgeDelayMs requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHedgeDelayMs: %w", err)
+	}
+	return oldValue.HedgeDelayMs, nil
+}
+
+// AddHedgeDelayMs adds i to the "hedge_delay_ms" field.
+func (m *GroupMutation) AddHedgeDelayMs(i int) {
+	if m.addhedge_delay_ms != nil {
+		*m.addhedge_delay_ms += i
+	} else {
+		m.addhedge_delay_ms = &i
+	}
+}
+
+// AddedHedgeDelayMs returns the value that was added to the "hedge_delay_ms" field in this mutation.
+func (m *GroupMutation) AddedHedgeDelayMs() (r int, exists bool) {
+	v := m.addhedge_delay_ms
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetHedgeDelayMs resets all changes to the "hedge_delay_ms" field.
+func (m *GroupMutation) ResetHedgeDelayMs() {
+	m.hedge_delay_ms = nil
+	m.addhedge_delay_ms = nil
+}
+
+// SetHedgeMaxRatio sets the "hedge_max_ratio" field.
+func (m *GroupMutation) SetHedgeMaxRatio(f float64) {
+	m.hedge_max_ratio = &f
+	m.addhedge_max_ratio = nil
+}
+
+// HedgeMaxRatio returns the value of the "hedge_max_ratio" field in the mutation.
+func (m *GroupMutation) HedgeMaxRatio() (r float64, exists bool) {
+	v := m.hedge_max_ratio
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHedgeMaxRatio returns the old "hedge_max_ratio" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldHedgeMaxRatio(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHedgeMaxRatio is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHedgeMaxRatio requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHedgeMaxRatio: %w", err)
+	}
+	return oldValue.HedgeMaxRatio, nil
+}
+
+// AddHedgeMaxRatio adds f to the "hedge_max_ratio" field.
+func (m *GroupMutation) AddHedgeMaxRatio(f float64) {
+	if m.addhedge_max_ratio != nil {
+		*m.addhedge_max_ratio += f
+	} else {
+		m.addhedge_max_ratio = &f
+	}
+}
+
+// AddedHedgeMaxRatio returns the value that was added to the "hedge_max_ratio" field in this mutation.
+func (m *GroupMutation) AddedHedgeMaxRatio() (r float64, exists bool) {
+	v := m.addhedge_max_ratio
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetHedgeMaxRatio resets all changes to the "hedge_max_ratio" field.
+func (m *GroupMutation) ResetHedgeMaxRatio() {
+	m.hedge_max_ratio = nil
+	m.addhedge_max_ratio = nil
+}
+
+// SetScheduleRule sets the "schedule_rule" field.
+func (m *GroupMutation) SetScheduleRule(dr *domain.ScheduleRule) {
+	m.schedule_rule = &dr
+}
+
+// ScheduleRule returns the value of the "schedule_rule" field in the mutation.
+func (m *GroupMutation) ScheduleRule() (r *domain.ScheduleRule, exists bool) {
+	v := m.schedule_rule
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldScheduleRule returns the old "schedule_rule" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldScheduleRule(ctx context.Context) (v *domain.ScheduleRule, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldScheduleRule is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldScheduleRule requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldScheduleRule: %w", err)
+	}
+	return oldValue.ScheduleRule, nil
+}
+
+// ClearScheduleRule clears the value of the "schedule_rule" field.
+func (m *GroupMutation) ClearScheduleRule() {
+	m.schedule_rule = nil
+	m.clearedFields[group.FieldScheduleRule] = struct{}{}
+}
+
+// ScheduleRuleCleared returns if the "schedule_rule" field was cleared in this mutation.
+func (m *GroupMutation) ScheduleRuleCleared() bool {
+	_, ok := m.clearedFields[group.FieldScheduleRule]
+	return ok
+}
+
+// ResetScheduleRule resets all changes to the "schedule_rule" field.
+func (m *GroupMutation) ResetScheduleRule() {
+	m.schedule_rule = nil
+	delete(m.clearedFields, group.FieldScheduleRule)
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by ids.
 func (m *GroupMutation) AddAPIKeyIDs(ids ...int64) {
 	if m.api_keys == nil {
@@ -8997,7 +9200,7 @@ func (m *GroupMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *GroupMutation) Fields() []string {
-	fields := make([]string, 0, 27)
+	fields := make([]string, 0, 31)
 	if m.created_at != nil {
 		fields = append(fields, group.FieldCreatedAt)
 	}
@@ -9079,6 +9282,18 @@ func (m *GroupMutation) Fields() []string {
 	if m.sort_order != nil {
 		fields = append(fields, group.FieldSortOrder)
 	}
+	if m.hedge_enabled != nil {
+		fields = append(fields, group.FieldHedgeEnabled)
+	}
+	if m.hedge_delay_ms != nil {
+		fields = append(fields, group.FieldHedgeDelayMs)
+	}
+	if m.hedge_max_ratio != nil {
+		fields = append(fields, group.FieldHedgeMaxRatio)
+	}
+	if m.schedule_rule != nil {
+		fields = append(fields, group.FieldScheduleRule)
+	}
 	return fields
 }
 
@@ -9141,6 +9356,14 @@ func (m *GroupMutation) Field(name string) (ent.Value, bool) {
 		return m.SupportedModelScopes()
 	case group.FieldSortOrder:
 		return m.SortOrder()
+	case group.FieldHedgeEnabled:
+		return m.HedgeEnabled()
+	case group.FieldHedgeDelayMs:
+		return m.HedgeDelayMs()
+	case group.FieldHedgeMaxRatio:
+		return m.HedgeMaxRatio()
+	case group.FieldScheduleRule:
+		return m.ScheduleRule()
 	}
 	return nil, false
 }
@@ -9204,6 +9427,14 @@ func (m *GroupMutation) OldField(ctx context.Context, name string) (ent.Value, e
 		return m.OldSupportedModelScopes(ctx)
 	case group.FieldSortOrder:
 		return m.OldSortOrder(ctx)
+	case group.FieldHedgeEnabled:
+		return m.OldHedgeEnabled(ctx)
+	case group.FieldHedgeDelayMs:
+		return m.OldHedgeDelayMs(ctx)
+	case group.FieldHedgeMaxRatio:
+		return m.OldHedgeMaxRatio(ctx)
+	case group.FieldScheduleRule:
+		return m.OldScheduleRule(ctx)
 	}
 	return nil, fmt.Errorf("unknown Group field %s", name)
 }
@@ -9402,6 +9633,34 @@ func (m *GroupMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetSortOrder(v)
 		return nil
+	case group.FieldHedgeEnabled:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHedgeEnabled(v)
+		return nil
+	case group.FieldHedgeDelayMs:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHedgeDelayMs(v)
+		return nil
+	case group.FieldHedgeMaxRatio:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHedgeMaxRatio(v)
+		return nil
+	case group.FieldScheduleRule:
+		v, ok := value.(*domain.ScheduleRule)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetScheduleRule(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Group field %s", name)
 }
@@ -9449,6 +9708,12 @@ func (m *GroupMutation) AddedFields() []string {
 	if m.addsort_order != nil {
 		fields = append(fields, group.FieldSortOrder)
 	}
+	if m.addhedge_delay_ms != nil {
+		fields = append(fields, group.FieldHedgeDelayMs)
+	}
+	if m.addhedge_max_ratio != nil {
+		fields = append(fields, group.FieldHedgeMaxRatio)
+	}
 	return fields
 }
 
@@ -9483,6 +9748,10 @@ func (m *GroupMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedCacheReadTransferProbability()
 	case group.FieldSortOrder:
 		return m.AddedSortOrder()
+	case group.FieldHedgeDelayMs:
+		return m.AddedHedgeDelayMs()
+	case group.FieldHedgeMaxRatio:
+		return m.AddedHedgeMaxRatio()
 	}
 	return nil, false
 }
@@ -9583,6 +9852,20 @@ func (m *GroupMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddSortOrder(v)
 		return nil
+	case group.FieldHedgeDelayMs:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddHedgeDelayMs(v)
+		return nil
+	case group.FieldHedgeMaxRatio:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddHedgeMaxRatio(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Group numeric field %s", name)
 }
@@ -9624,6 +9907,9 @@ func (m *GroupMutation) ClearedFields() []string {
 	if m.FieldCleared(group.FieldModelRouting) {
 		fields = append(fields, group.FieldModelRouting)
 	}
+	if m.FieldCleared(group.FieldScheduleRule) {
+		fields = append(fields, group.FieldScheduleRule)
+	}
 	return fields
 }
 
@@ -9671,6 +9957,9 @@ func (m *GroupMutation) ClearField(name string) error {
 	case group.FieldModelRouting:
 		m.ClearModelRouting()
 		return nil
+	case group.FieldScheduleRule:
+		m.ClearScheduleRule()
+		return nil
 	}
 	return fmt.Errorf("unknown Group nullable field %s", name)
 }
@@ -9760,6 +10049,18 @@ func (m *GroupMutation) ResetField(name string) error {
 	case group.FieldSortOrder:
 		m.ResetSortOrder()
 		return nil
+	case group.FieldHedgeEnabled:
+		m.ResetHedgeEnabled()
+		return nil
+	case group.FieldHedgeDelayMs:
+		m.ResetHedgeDelayMs()
+		return nil
+	case group.FieldHedgeMaxRatio:
+		m.ResetHedgeMaxRatio()
+		return nil
+	case group.FieldScheduleRule:
+		m.ResetScheduleRule()
+		return nil
 	}
 	return fmt.Errorf("unknown Group field %s", name)
 }
@@ -9987,10 +10288,24 @@ type PromoCodeMutation struct {
 	code                 *string
 	bonus_amount         *float64
 	addbonus_amount      *float64
+	bonus_type           *string
+	bonus_percent        *float64
+	addbonus_percent     *float64
+	max_bonus_amount     *float64
+	addmax_bonus_amount  *float64
 	max_uses             *int
 	addmax_uses          *int
 	used_count           *int
 	addused_count        *int
+	per_user_limit       *int
+	addper_user_limit    *int
+	scope                *string
+	new_users_only       *bool
+	new_user_days        *int
+	addnew_user_days     *int
+	required_group_id    *int64
+	addrequired_group_id *int64
+	starts_at            *time.Time
 	status               *string
 	expires_at           *time.Time
 	notes                *string
@@ -10195,6 +10510,154 @@ func (m *PromoCodeMutation) ResetBonusAmount() {
 	m.addbonus_amount = nil
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (m *PromoCodeMutation) SetBonusType(s string) {
+	m.bonus_type = &s
+}
+
+// BonusType returns the value of the "bonus_type" field in the mutation.
+func (m *PromoCodeMutation) BonusType() (r string, exists bool) {
+	v := m.bonus_type
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBonusType returns the old "bonus_type" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldBonusType(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBonusType is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBonusType requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBonusType: %w", err)
+	}
+	return oldValue.BonusType, nil
+}
+
+// ResetBonusType resets all changes to the "bonus_type" field.
+func (m *PromoCodeMutation) ResetBonusType() {
+	m.bonus_type = nil
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (m *PromoCodeMutation) SetBonusPercent(f float64) {
+	m.bonus_percent = &f
+	m.addbonus_percent = nil
+}
+
+// BonusPercent returns the value of the "bonus_percent" field in the mutation.
+func (m *PromoCodeMutation) BonusPercent() (r float64, exists bool) {
+	v := m.bonus_percent
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBonusPercent returns the old "bonus_percent" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldBonusPercent(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBonusPercent is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBonusPercent requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBonusPercent: %w", err)
+	}
+	return oldValue.BonusPercent, nil
+}
+
+// AddBonusPercent adds f to the "bonus_percent" field.
+func (m *PromoCodeMutation) AddBonusPercent(f float64) {
+	if m.addbonus_percent != nil {
+		*m.addbonus_percent += f
+	} else {
+		m.addbonus_percent = &f
+	}
+}
+
+// AddedBonusPercent returns the value that was added to the "bonus_percent" field in this mutation.
+func (m *PromoCodeMutation) AddedBonusPercent() (r float64, exists bool) {
+	v := m.addbonus_percent
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetBonusPercent resets all changes to the "bonus_percent" field.
+func (m *PromoCodeMutation) ResetBonusPercent() {
+	m.bonus_percent = nil
+	m.addbonus_percent = nil
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (m *PromoCodeMutation) SetMaxBonusAmount(f float64) {
+	m.max_bonus_amount = &f
+	m.addmax_bonus_amount = nil
+}
+
+// MaxBonusAmount returns the value of the "max_bonus_amount" field in the mutation.
+func (m *PromoCodeMutation) MaxBonusAmount() (r float64, exists bool) {
+	v := m.max_bonus_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMaxBonusAmount returns the old "max_bonus_amount" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldMaxBonusAmount(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMaxBonusAmount is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMaxBonusAmount requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMaxBonusAmount: %w", err)
+	}
+	return oldValue.MaxBonusAmount, nil
+}
+
+// AddMaxBonusAmount adds f to the "max_bonus_amount" field.
+func (m *PromoCodeMutation) AddMaxBonusAmount(f float64) {
+	if m.addmax_bonus_amount != nil {
+		*m.addmax_bonus_amount += f
+	} else {
+		m.addmax_bonus_amount = &f
+	}
+}
+
+// AddedMaxBonusAmount returns the value that was added to the "max_bonus_amount" field in this mutation.
+func (m *PromoCodeMutation) AddedMaxBonusAmount() (r float64, exists bool) {
+	v := m.addmax_bonus_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetMaxBonusAmount resets all changes to the "max_bonus_amount" field.
+func (m *PromoCodeMutation) ResetMaxBonusAmount() {
+	m.max_bonus_amount = nil
+	m.addmax_bonus_amount = nil
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (m *PromoCodeMutation) SetMaxUses(i int) {
 	m.max_uses = &i
@@ -10307,48 +10770,351 @@ func (m *PromoCodeMutation) ResetUsedCount() {
 	m.addused_count = nil
 }
 
-// SetStatus sets the "status" field.
-func (m *PromoCodeMutation) SetStatus(s string) {
-	m.status = &s
+// SetPerUserLimit sets the "per_user_limit" field.
+func (m *PromoCodeMutation) SetPerUserLimit(i int) {
+	m.per_user_limit = &i
+	m.addper_user_limit = nil
 }
 
-// Status returns the value of the "status" field in the mutation.
-func (m *PromoCodeMutation) Status() (r string, exists bool) {
-	v := m.status
+// PerUserLimit returns the value of the "per_user_limit" field in the mutation.
+func (m *PromoCodeMutation) PerUserLimit() (r int, exists bool) {
+	v := m.per_user_limit
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldStatus returns the old "status" field's value of the PromoCode entity.
+// OldPerUserLimit returns the old "per_user_limit" field's value of the PromoCode entity.
 // If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PromoCodeMutation) OldStatus(ctx context.Context) (v string, err error) {
+func (m *PromoCodeMutation) OldPerUserLimit(ctx context.Context) (v int, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+		return v, errors.New("OldPerUserLimit is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldStatus requires an ID field in the mutation")
+		return v, errors.New("OldPerUserLimit requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+		return v, fmt.Errorf("querying old value for OldPerUserLimit: %w", err)
 	}
-	return oldValue.Status, nil
-}
-
-// ResetStatus resets all changes to the "status" field.
-func (m *PromoCodeMutation) ResetStatus() {
-	m.status = nil
+	return oldValue.PerUserLimit, nil
 }
 
-// SetExpiresAt sets the "expires_at" field.
-func (m *PromoCodeMutation) SetExpiresAt(t time.Time) {
-	m.expires_at = &t
+// AddPerUserLimit adds i to the "per_user_limit" field.
+func (m *PromoCodeMutation) AddPerUserLimit(i int) {
+	if m.addper_user_limit != nil {
+		*m.addper_user_limit += i
+	} else {
+		m.addper_user_limit = &i
+	}
 }
 
-// ExpiresAt returns the value of the "expires_at" field in the mutation.
+// AddedPerUserLimit returns the value that was added to the "per_user_limit" field in this mutation.
+func (m *PromoCodeMutation) AddedPerUserLimit() (r int, exists bool) {
+	v := m.addper_user_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetPerUserLimit resets all changes to the "per_user_limit" field.
+func (m *PromoCodeMutation) ResetPerUserLimit() {
+	m.per_user_limit = nil
+	m.addper_user_limit = nil
+}
+
+// SetScope sets the "scope" field.
+func (m *PromoCodeMutation) SetScope(s string) {
+	m.scope = &s
+}
+
+// Scope returns the value of the "scope" field in the mutation.
+func (m *PromoCodeMutation) Scope() (r string, exists bool) {
+	v := m.scope
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldScope returns the old "scope" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldScope(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldScope is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldScope requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldScope: %w", err)
+	}
+	return oldValue.Scope, nil
+}
+
+// ResetScope resets all changes to the "scope" field.
+func (m *PromoCodeMutation) ResetScope() {
+	m.scope = nil
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (m *PromoCodeMutation) SetNewUsersOnly(b bool) {
+	m.new_users_only = &b
+}
+
+// NewUsersOnly returns the value of the "new_users_only" field in the mutation.
+func (m *PromoCodeMutation) NewUsersOnly() (r bool, exists bool) {
+	v := m.new_users_only
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldNewUsersOnly returns the old "new_users_only" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldNewUsersOnly(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldNewUsersOnly is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldNewUsersOnly requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldNewUsersOnly: %w", err)
+	}
+	return oldValue.NewUsersOnly, nil
+}
+
+// ResetNewUsersOnly resets all changes to the "new_users_only" field.
+func (m *PromoCodeMutation) ResetNewUsersOnly() {
+	m.new_users_only = nil
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (m *PromoCodeMutation) SetNewUserDays(i int) {
+	m.new_user_days = &i
+	m.addnew_user_days = nil
+}
+
+// NewUserDays returns the value of the "new_user_days" field in the mutation.
+func (m *PromoCodeMutation) NewUserDays() (r int, exists bool) {
+	v := m.new_user_days
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldNewUserDays returns the old "new_user_days" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldNewUserDays(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldNewUserDays is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldNewUserDays requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldNewUserDays: %w", err)
+	}
+	return oldValue.NewUserDays, nil
+}
+
+// AddNewUserDays adds i to the "new_user_days" field.
+func (m *PromoCodeMutation) AddNewUserDays(i int) {
+	if m.addnew_user_days != nil {
+		*m.addnew_user_days += i
+	} else {
+		m.addnew_user_days = &i
+	}
+}
+
+// AddedNewUserDays returns the value that was added to the "new_user_days" field in this mutation.
+func (m *PromoCodeMutation) AddedNewUserDays() (r int, exists bool) {
+	v := m.addnew_user_days
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetNewUserDays resets all changes to the "new_user_days" field.
+func (m *PromoCodeMutation) ResetNewUserDays() {
+	m.new_user_days = nil
+	m.addnew_user_days = nil
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (m *PromoCodeMutation) SetRequiredGroupID(i int64) {
+	m.required_group_id = &i
+	m.addrequired_group_id = nil
+}
+
+// RequiredGroupID returns the value of the "required_group_id" field in the mutation.
+func (m *PromoCodeMutation) RequiredGroupID() (r int64, exists bool) {
+	v := m.required_group_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRequiredGroupID returns the old "required_group_id" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldRequiredGroupID(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRequiredGroupID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRequiredGroupID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRequiredGroupID: %w", err)
+	}
+	return oldValue.RequiredGroupID, nil
+}
+
+// AddRequiredGroupID adds i to the "required_group_id" field.
+func (m *PromoCodeMutation) AddRequiredGroupID(i int64) {
+	if m.addrequired_group_id != nil {
+		*m.addrequired_group_id += i
+	} else {
+		m.addrequired_group_id = &i
+	}
+}
+
+// AddedRequiredGroupID returns the value that was added to the "required_group_id" field in this mutation.
+func (m *PromoCodeMutation) AddedRequiredGroupID() (r int64, exists bool) {
+	v := m.addrequired_group_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearRequiredGroupID clears the value of the "required_group_id" field.
+func (m *PromoCodeMutation) ClearRequiredGroupID() {
+	m.required_group_id = nil
+	m.addrequired_group_id = nil
+	m.clearedFields[promocode.FieldRequiredGroupID] = struct{}{}
+}
+
+// RequiredGroupIDCleared returns if the "required_group_id" field was cleared in this mutation.
+func (m *PromoCodeMutation) RequiredGroupIDCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldRequiredGroupID]
+	return ok
+}
+
+// ResetRequiredGroupID resets all changes to the "required_group_id" field.
+func (m *PromoCodeMutation) ResetRequiredGroupID() {
+	m.required_group_id = nil
+	m.addrequired_group_id = nil
+	delete(m.clearedFields, promocode.FieldRequiredGroupID)
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (m *PromoCodeMutation) SetStartsAt(t time.Time) {
+	m.starts_at = &t
+}
+
+// StartsAt returns the value of the "starts_at" field in the mutation.
+func (m *PromoCodeMutation) StartsAt() (r time.Time, exists bool) {
+	v := m.starts_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStartsAt returns the old "starts_at" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldStartsAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStartsAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStartsAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStartsAt: %w", err)
+	}
+	return oldValue.StartsAt, nil
+}
+
+// ClearStartsAt clears the value of the "starts_at" field.
+func (m *PromoCodeMutation) ClearStartsAt() {
+	m.starts_at = nil
+	m.clearedFields[promocode.FieldStartsAt] = struct{}{}
+}
+
+// StartsAtCleared returns if the "starts_at" field was cleared in this mutation.
+func (m *PromoCodeMutation) StartsAtCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldStartsAt]
+	return ok
+}
+
+// ResetStartsAt resets all changes to the "starts_at" field.
+func (m *PromoCodeMutation) ResetStartsAt() {
+	m.starts_at = nil
+	delete(m.clearedFields, promocode.FieldStartsAt)
+}
+
+// SetStatus sets the "status" field.
+func (m *PromoCodeMutation) SetStatus(s string) {
+	m.status = &s
+}
+
+// Status returns the value of the "status" field in the mutation.
+func (m *PromoCodeMutation) Status() (r string, exists bool) {
+	v := m.status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatus returns the old "status" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+	}
+	return oldValue.Status, nil
+}
+
+// ResetStatus resets all changes to the "status" field.
+func (m *PromoCodeMutation) ResetStatus() {
+	m.status = nil
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (m *PromoCodeMutation) SetExpiresAt(t time.Time) {
+	m.expires_at = &t
+}
+
+// ExpiresAt returns the value of the "expires_at" field in the mutation.
 func (m *PromoCodeMutation) ExpiresAt() (r time.Time, exists bool) {
 	v := m.expires_at
 	if v == nil {
@@ -10601,19 +11367,46 @@ func (m *PromoCodeMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *PromoCodeMutation) Fields() []string {
-	fields := make([]string, 0, 9)
+	fields := make([]string, 0, 18)
 	if m.code != nil {
 		fields = append(fields, promocode.FieldCode)
 	}
 	if m.bonus_amount != nil {
 		fields = append(fields, promocode.FieldBonusAmount)
 	}
+	if m.bonus_type != nil {
+		fields = append(fields, promocode.FieldBonusType)
+	}
+	if m.bonus_percent != nil {
+		fields = append(fields, promocode.FieldBonusPercent)
+	}
+	if m.max_bonus_amount != nil {
+		fields = append(fields, promocode.FieldMaxBonusAmount)
+	}
 	if m.max_uses != nil {
 		fields = append(fields, promocode.FieldMaxUses)
 	}
 	if m.used_count != nil {
 		fields = append(fields, promocode.FieldUsedCount)
 	}
+	if m.per_user_limit != nil {
+		fields = append(fields, promocode.FieldPerUserLimit)
+	}
+	if m.scope != nil {
+		fields = append(fields, promocode.FieldScope)
+	}
+	if m.new_users_only != nil {
+		fields = append(fields, promocode.FieldNewUsersOnly)
+	}
+	if m.new_user_days != nil {
+		fields = append(fields, promocode.FieldNewUserDays)
+	}
+	if m.required_group_id != nil {
+		fields = append(fields, promocode.FieldRequiredGroupID)
+	}
+	if m.starts_at != nil {
+		fields = append(fields, promocode.FieldStartsAt)
+	}
 	if m.status != nil {
 		fields = append(fields, promocode.FieldStatus)
 	}
@@ -10641,10 +11434,28 @@ func (m *PromoCodeMutation) Field(name string) (ent.Value, bool) {
 		return m.Code()
 	case promocode.FieldBonusAmount:
 		return m.BonusAmount()
+	case promocode.FieldBonusType:
+		return m.BonusType()
+	case promocode.FieldBonusPercent:
+		return m.BonusPercent()
+	case promocode.FieldMaxBonusAmount:
+		return m.MaxBonusAmount()
 	case promocode.FieldMaxUses:
 		return m.MaxUses()
 	case promocode.FieldUsedCount:
 		return m.UsedCount()
+	case promocode.FieldPerUserLimit:
+		return m.PerUserLimit()
+	case promocode.FieldScope:
+		return m.Scope()
+	case promocode.FieldNewUsersOnly:
+		return m.NewUsersOnly()
+	case promocode.FieldNewUserDays:
+		return m.NewUserDays()
+	case promocode.FieldRequiredGroupID:
+		return m.RequiredGroupID()
+	case promocode.FieldStartsAt:
+		return m.StartsAt()
 	case promocode.FieldStatus:
 		return m.Status()
 	case promocode.FieldExpiresAt:
@@ -10668,10 +11479,28 @@ func (m *PromoCodeMutation) OldField(ctx context.Context, name string) (ent.Valu
 		return m.OldCode(ctx)
 	case promocode.FieldBonusAmount:
 		return m.OldBonusAmount(ctx)
+	case promocode.FieldBonusType:
+		return m.OldBonusType(ctx)
+	case promocode.FieldBonusPercent:
+		return m.OldBonusPercent(ctx)
+	case promocode.FieldMaxBonusAmount:
+		return m.OldMaxBonusAmount(ctx)
 	case promocode.FieldMaxUses:
 		return m.OldMaxUses(ctx)
 	case promocode.FieldUsedCount:
 		return m.OldUsedCount(ctx)
+	case promocode.FieldPerUserLimit:
+		return m.OldPerUserLimit(ctx)
+	case promocode.FieldScope:
+		return m.OldScope(ctx)
+	case promocode.FieldNewUsersOnly:
+		return m.OldNewUsersOnly(ctx)
+	case promocode.FieldNewUserDays:
+		return m.OldNewUserDays(ctx)
+	case promocode.FieldRequiredGroupID:
+		return m.OldRequiredGroupID(ctx)
+	case promocode.FieldStartsAt:
+		return m.OldStartsAt(ctx)
 	case promocode.FieldStatus:
 		return m.OldStatus(ctx)
 	case promocode.FieldExpiresAt:
@@ -10705,6 +11534,27 @@ func (m *PromoCodeMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetBonusAmount(v)
 		return nil
+	case promocode.FieldBonusType:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBonusType(v)
+		return nil
+	case promocode.FieldBonusPercent:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBonusPercent(v)
+		return nil
+	case promocode.FieldMaxBonusAmount:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMaxBonusAmount(v)
+		return nil
 	case promocode.FieldMaxUses:
 		v, ok := value.(int)
 		if !ok {
@@ -10719,6 +11569,48 @@ func (m *PromoCodeMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetUsedCount(v)
 		return nil
+	case promocode.FieldPerUserLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPerUserLimit(v)
+		return nil
+	case promocode.FieldScope:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetScope(v)
+		return nil
+	case promocode.FieldNewUsersOnly:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetNewUsersOnly(v)
+		return nil
+	case promocode.FieldNewUserDays:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetNewUserDays(v)
+		return nil
+	case promocode.FieldRequiredGroupID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRequiredGroupID(v)
+		return nil
+	case promocode.FieldStartsAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStartsAt(v)
+		return nil
 	case promocode.FieldStatus:
 		v, ok := value.(string)
 		if !ok {
@@ -10765,12 +11657,27 @@ func (m *PromoCodeMutation) AddedFields() []string {
 	if m.addbonus_amount != nil {
 		fields = append(fields, promocode.FieldBonusAmount)
 	}
+	if m.addbonus_percent != nil {
+		fields = append(fields, promocode.FieldBonusPercent)
+	}
+	if m.addmax_bonus_amount != nil {
+		fields = append(fields, promocode.FieldMaxBonusAmount)
+	}
 	if m.addmax_uses != nil {
 		fields = append(fields, promocode.FieldMaxUses)
 	}
 	if m.addused_count != nil {
 		fields = append(fields, promocode.FieldUsedCount)
 	}
+	if m.addper_user_limit != nil {
+		fields = append(fields, promocode.FieldPerUserLimit)
+	}
+	if m.addnew_user_days != nil {
+		fields = append(fields, promocode.FieldNewUserDays)
+	}
+	if m.addrequired_group_id != nil {
+		fields = append(fields, promocode.FieldRequiredGroupID)
+	}
 	return fields
 }
 
@@ -10781,10 +11688,20 @@ func (m *PromoCodeMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
 	case promocode.FieldBonusAmount:
 		return m.AddedBonusAmount()
+	case promocode.FieldBonusPercent:
+		return m.AddedBonusPercent()
+	case promocode.FieldMaxBonusAmount:
+		return m.AddedMaxBonusAmount()
 	case promocode.FieldMaxUses:
 		return m.AddedMaxUses()
 	case promocode.FieldUsedCount:
 		return m.AddedUsedCount()
+	case promocode.FieldPerUserLimit:
+		return m.AddedPerUserLimit()
+	case promocode.FieldNewUserDays:
+		return m.AddedNewUserDays()
+	case promocode.FieldRequiredGroupID:
+		return m.AddedRequiredGroupID()
 	}
 	return nil, false
 }
@@ -10801,6 +11718,20 @@ func (m *PromoCodeMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddBonusAmount(v)
 		return nil
+	case promocode.FieldBonusPercent:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddBonusPercent(v)
+		return nil
+	case promocode.FieldMaxBonusAmount:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddMaxBonusAmount(v)
+		return nil
 	case promocode.FieldMaxUses:
 		v, ok := value.(int)
 		if !ok {
@@ -10815,6 +11746,27 @@ func (m *PromoCodeMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddUsedCount(v)
 		return nil
+	case promocode.FieldPerUserLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddPerUserLimit(v)
+		return nil
+	case promocode.FieldNewUserDays:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddNewUserDays(v)
+		return nil
+	case promocode.FieldRequiredGroupID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddRequiredGroupID(v)
+		return nil
 	}
 	return fmt.Errorf("unknown PromoCode numeric field %s", name)
 }
@@ -10823,6 +11775,12 @@ func (m *PromoCodeMutation) AddField(name string, value ent.Value) error {
 // mutation.
 func (m *PromoCodeMutation) ClearedFields() []string {
 	var fields []string
+	if m.FieldCleared(promocode.FieldRequiredGroupID) {
+		fields = append(fields, promocode.FieldRequiredGroupID)
+	}
+	if m.FieldCleared(promocode.FieldStartsAt) {
+		fields = append(fields, promocode.FieldStartsAt)
+	}
 	if m.FieldCleared(promocode.FieldExpiresAt) {
 		fields = append(fields, promocode.FieldExpiresAt)
 	}
@@ -10843,6 +11801,12 @@ func (m *PromoCodeMutation) FieldCleared(name string) bool {
 // error if the field is not defined in the schema.
 func (m *PromoCodeMutation) ClearField(name string) error {
 	switch name {
+	case promocode.FieldRequiredGroupID:
+		m.ClearRequiredGroupID()
+		return nil
+	case promocode.FieldStartsAt:
+		m.ClearStartsAt()
+		return nil
 	case promocode.FieldExpiresAt:
 		m.ClearExpiresAt()
 		return nil
@@ -10863,12 +11827,39 @@ func (m *PromoCodeMutation) ResetField(name string) error {
 	case promocode.FieldBonusAmount:
 		m.ResetBonusAmount()
 		return nil
+	case promocode.FieldBonusType:
+		m.ResetBonusType()
+		return nil
+	case promocode.FieldBonusPercent:
+		m.ResetBonusPercent()
+		return nil
+	case promocode.FieldMaxBonusAmount:
+		m.ResetMaxBonusAmount()
+		return nil
 	case promocode.FieldMaxUses:
 		m.ResetMaxUses()
 		return nil
 	case promocode.FieldUsedCount:
 		m.ResetUsedCount()
 		return nil
+	case promocode.FieldPerUserLimit:
+		m.ResetPerUserLimit()
+		return nil
+	case promocode.FieldScope:
+		m.ResetScope()
+		return nil
+	case promocode.FieldNewUsersOnly:
+		m.ResetNewUsersOnly()
+		return nil
+	case promocode.FieldNewUserDays:
+		m.ResetNewUserDays()
+		return nil
+	case promocode.FieldRequiredGroupID:
+		m.ResetRequiredGroupID()
+		return nil
+	case promocode.FieldStartsAt:
+		m.ResetStartsAt()
+		return nil
 	case promocode.FieldStatus:
 		m.ResetStatus()
 		return nil
@@ -10975,20 +11966,32 @@ func (m *PromoCodeMutation) ResetEdge(name string) error {
 // PromoCodeUsageMutation represents an operation that mutates the PromoCodeUsage nodes in the graph.
 type PromoCodeUsageMutation struct {
 	config
-	op                Op
-	typ               string
-	id                *int64
-	bonus_amount      *float64
-	addbonus_amount   *float64
-	used_at           *time.Time
-	clearedFields     map[string]struct{}
-	promo_code        *int64
-	clearedpromo_code bool
-	user              *int64
-	cleareduser       bool
-	done              bool
-	oldValue          func(context.Context) (*PromoCodeUsage, error)
-	predicates        []predicate.PromoCodeUsage
+	op                  Op
+	typ                 string
+	id                  *int64
+	bonus_amount        *float64
+	addbonus_amount     *float64
+	bonus_type          *string
+	bonus_percent       *float64
+	addbonus_percent    *float64
+	max_bonus_amount    *float64
+	addmax_bonus_amount *float64
+	status              *string
+	source              *string
+	base_amount         *float64
+	addbase_amount      *float64
+	redeem_code_id      *int64
+	addredeem_code_id   *int64
+	applied_at          *time.Time
+	used_at             *time.Time
+	clearedFields       map[string]struct{}
+	promo_code          *int64
+	clearedpromo_code   bool
+	user                *int64
+	cleareduser         bool
+	done                bool
+	oldValue            func(context.Context) (*PromoCodeUsage, error)
+	predicates          []predicate.PromoCodeUsage
 }
 
 var _ ent.Mutation = (*PromoCodeUsageMutation)(nil)
@@ -11217,6 +12220,401 @@ func (m *PromoCodeUsageMutation) ResetBonusAmount() {
 	m.addbonus_amount = nil
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (m *PromoCodeUsageMutation) SetBonusType(s string) {
+	m.bonus_type = &s
+}
+
+// BonusType returns the value of the "bonus_type" field in the mutation.
+func (m *PromoCodeUsageMutation) BonusType() (r string, exists bool) {
+	v := m.bonus_type
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBonusType returns the old "bonus_type" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldBonusType(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBonusType is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBonusType requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBonusType: %w", err)
+	}
+	return oldValue.BonusType, nil
+}
+
+// ResetBonusType resets all changes to the "bonus_type" field.
+func (m *PromoCodeUsageMutation) ResetBonusType() {
+	m.bonus_type = nil
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (m *PromoCodeUsageMutation) SetBonusPercent(f float64) {
+	m.bonus_percent = &f
+	m.addbonus_percent = nil
+}
+
+// BonusPercent returns the value of the "bonus_percent" field in the mutation.
+func (m *PromoCodeUsageMutation) BonusPercent() (r float64, exists bool) {
+	v := m.bonus_percent
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBonusPercent returns the old "bonus_percent" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldBonusPercent(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBonusPercent is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBonusPercent requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBonusPercent: %w", err)
+	}
+	return oldValue.BonusPercent, nil
+}
+
+// AddBonusPercent adds f to the "bonus_percent" field.
+func (m *PromoCodeUsageMutation) AddBonusPercent(f float64) {
+	if m.addbonus_percent != nil {
+		*m.addbonus_percent += f
+	} else {
+		m.addbonus_percent = &f
+	}
+}
+
+// AddedBonusPercent returns the value that was added to the "bonus_percent" field in this mutation.
+func (m *PromoCodeUsageMutation) AddedBonusPercent() (r float64, exists bool) {
+	v := m.addbonus_percent
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetBonusPercent resets all changes to the "bonus_percent" field.
+func (m *PromoCodeUsageMutation) ResetBonusPercent() {
+	m.bonus_percent = nil
+	m.addbonus_percent = nil
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (m *PromoCodeUsageMutation) SetMaxBonusAmount(f float64) {
+	m.max_bonus_amount = &f
+	m.addmax_bonus_amount = nil
+}
+
+// MaxBonusAmount returns the value of the "max_bonus_amount" field in the mutation.
+func (m *PromoCodeUsageMutation) MaxBonusAmount() (r float64, exists bool) {
+	v := m.max_bonus_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMaxBonusAmount returns the old "max_bonus_amount" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldMaxBonusAmount(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMaxBonusAmount is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMaxBonusAmount requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMaxBonusAmount: %w", err)
+	}
+	return oldValue.MaxBonusAmount, nil
+}
+
+// AddMaxBonusAmount adds f to the "max_bonus_amount" field.
+func (m *PromoCodeUsageMutation) AddMaxBonusAmount(f float64) {
+	if m.addmax_bonus_amount != nil {
+		*m.addmax_bonus_amount += f
+	} else {
+		m.addmax_bonus_amount = &f
+	}
+}
+
+// AddedMaxBonusAmount returns the value that was added to the "max_bonus_amount" field in this mutation.
+func (m *PromoCodeUsageMutation) AddedMaxBonusAmount() (r float64, exists bool) {
+	v := m.addmax_bonus_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetMaxBonusAmount resets all changes to the "max_bonus_amount" field.
+func (m *PromoCodeUsageMutation) ResetMaxBonusAmount() {
+	m.max_bonus_amount = nil
+	m.addmax_bonus_amount = nil
+}
+
+// SetStatus sets the "status" field.
+func (m *PromoCodeUsageMutation) SetStatus(s string) {
+	m.status = &s
+}
+
+// Status returns the value of the "status" field in the mutation.
+func (m *PromoCodeUsageMutation) Status() (r string, exists bool) {
+	v := m.status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatus returns the old "status" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+	}
+	return oldValue.Status, nil
+}
+
+// ResetStatus resets all changes to the "status" field.
+func (m *PromoCodeUsageMutation) ResetStatus() {
+	m.status = nil
+}
+
+// SetSource sets the "source" field.
+func (m *PromoCodeUsageMutation) SetSource(s string) {
+	m.source = &s
+}
+
+// Source returns the value of the "source" field in the mutation.
+func (m *PromoCodeUsageMutation) Source() (r string, exists bool) {
+	v := m.source
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSource returns the old "source" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldSource(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSource is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSource requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSource: %w", err)
+	}
+	return oldValue.Source, nil
+}
+
+// ResetSource resets all changes to the "source" field.
+func (m *PromoCodeUsageMutation) ResetSource() {
+	m.source = nil
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (m *PromoCodeUsageMutation) SetBaseAmount(f float64) {
+	m.base_amount = &f
+	m.addbase_amount = nil
+}
+
+// BaseAmount returns the value of the "base_amount" field in the mutation.
+func (m *PromoCodeUsageMutation) BaseAmount() (r float64, exists bool) {
+	v := m.base_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBaseAmount returns the old "base_amount" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldBaseAmount(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBaseAmount is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBaseAmount requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBaseAmount: %w", err)
+	}
+	return oldValue.BaseAmount, nil
+}
+
+// AddBaseAmount adds f to the "base_amount" field.
+func (m *PromoCodeUsageMutation) AddBaseAmount(f float64) {
+	if m.addbase_amount != nil {
+		*m.addbase_amount += f
+	} else {
+		m.addbase_amount = &f
+	}
+}
+
+// AddedBaseAmount returns the value that was added to the "base_amount" field in this mutation.
+func (m *PromoCodeUsageMutation) AddedBaseAmount() (r float64, exists bool) {
+	v := m.addbase_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetBaseAmount resets all changes to the "base_amount" field.
+func (m *PromoCodeUsageMutation) ResetBaseAmount() {
+	m.base_amount = nil
+	m.addbase_amount = nil
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (m *PromoCodeUsageMutation) SetRedeemCodeID(i int64) {
+	m.redeem_code_id = &i
+	m.addredeem_code_id = nil
+}
+
+// RedeemCodeID returns the value of the "redeem_code_id" field in the mutation.
+func (m *PromoCodeUsageMutation) RedeemCodeID() (r int64, exists bool) {
+	v := m.redeem_code_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRedeemCodeID returns the old "redeem_code_id" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldRedeemCodeID(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRedeemCodeID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRedeemCodeID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRedeemCodeID: %w", err)
+	}
+	return oldValue.RedeemCodeID, nil
+}
+
+// AddRedeemCodeID adds i to the "redeem_code_id" field.
+func (m *PromoCodeUsageMutation) AddRedeemCodeID(i int64) {
+	if m.addredeem_code_id != nil {
+		*m.addredeem_code_id += i
+	} else {
+		m.addredeem_code_id = &i
+	}
+}
+
+// AddedRedeemCodeID returns the value that was added to the "redeem_code_id" field in this mutation.
+func (m *PromoCodeUsageMutation) AddedRedeemCodeID() (r int64, exists bool) {
+	v := m.addredeem_code_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearRedeemCodeID clears the value of the "redeem_code_id" field.
+func (m *PromoCodeUsageMutation) ClearRedeemCodeID() {
+	m.redeem_code_id = nil
+	m.addredeem_code_id = nil
+	m.clearedFields[promocodeusage.FieldRedeemCodeID] = struct{}{}
+}
+
+// RedeemCodeIDCleared returns if the "redeem_code_id" field was cleared in this mutation.
+func (m *PromoCodeUsageMutation) RedeemCodeIDCleared() bool {
+	_, ok := m.clearedFields[promocodeusage.FieldRedeemCodeID]
+	return ok
+}
+
+// ResetRedeemCodeID resets all changes to the "redeem_code_id" field.
+func (m *PromoCodeUsageMutation) ResetRedeemCodeID() {
+	m.redeem_code_id = nil
+	m.addredeem_code_id = nil
+	delete(m.clearedFields, promocodeusage.FieldRedeemCodeID)
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (m *PromoCodeUsageMutation) SetAppliedAt(t time.Time) {
+	m.applied_at = &t
+}
+
+// AppliedAt returns the value of the "applied_at" field in the mutation.
+func (m *PromoCodeUsageMutation) AppliedAt() (r time.Time, exists bool) {
+	v := m.applied_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAppliedAt returns the old "applied_at" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldAppliedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAppliedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAppliedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAppliedAt: %w", err)
+	}
+	return oldValue.AppliedAt, nil
+}
+
+// ClearAppliedAt clears the value of the "applied_at" field.
+func (m *PromoCodeUsageMutation) ClearAppliedAt() {
+	m.applied_at = nil
+	m.clearedFields[promocodeusage.FieldAppliedAt] = struct{}{}
+}
+
+// AppliedAtCleared returns if the "applied_at" field was cleared in this mutation.
+func (m *PromoCodeUsageMutation) AppliedAtCleared() bool {
+	_, ok := m.clearedFields[promocodeusage.FieldAppliedAt]
+	return ok
+}
+
+// ResetAppliedAt resets all changes to the "applied_at" field.
+func (m *PromoCodeUsageMutation) ResetAppliedAt() {
+	m.applied_at = nil
+	delete(m.clearedFields, promocodeusage.FieldAppliedAt)
+}
+
 // SetUsedAt sets the "used_at" field.
 func (m *PromoCodeUsageMutation) SetUsedAt(t time.Time) {
 	m.used_at = &t
@@ -11341,7 +12739,7 @@ func (m *PromoCodeUsageMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *PromoCodeUsageMutation) Fields() []string {
-	fields := make([]string, 0, 4)
+	fields := make([]string, 0, 12)
 	if m.promo_code != nil {
 		fields = append(fields, promocodeusage.FieldPromoCodeID)
 	}
@@ -11351,6 +12749,30 @@ func (m *PromoCodeUsageMutation) Fields() []string {
 	if m.bonus_amount != nil {
 		fields = append(fields, promocodeusage.FieldBonusAmount)
 	}
+	if m.bonus_type != nil {
+		fields = append(fields, promocodeusage.FieldBonusType)
+	}
+	if m.bonus_percent != nil {
+		fields = append(fields, promocodeusage.FieldBonusPercent)
+	}
+	if m.max_bonus_amount != nil {
+		fields = append(fields, promocodeusage.FieldMaxBonusAmount)
+	}
+	if m.status != nil {
+		fields = append(fields, promocodeusage.FieldStatus)
+	}
+	if m.source != nil {
+		fields = append(fields, promocodeusage.FieldSource)
+	}
+	if m.base_amount != nil {
+		fields = append(fields, promocodeusage.FieldBaseAmount)
+	}
+	if m.redeem_code_id != nil {
+		fields = append(fields, promocodeusage.FieldRedeemCodeID)
+	}
+	if m.applied_at != nil {
+		fields = append(fields, promocodeusage.FieldAppliedAt)
+	}
 	if m.used_at != nil {
 		fields = append(fields, promocodeusage.FieldUsedAt)
 	}
@@ -11368,6 +12790,22 @@ func (m *PromoCodeUsageMutation) Field(name string) (ent.Value, bool) {
 		return m.UserID()
 	case promocodeusage.FieldBonusAmount:
 		return m.BonusAmount()
+	case promocodeusage.FieldBonusType:
+		return m.BonusType()
+	case promocodeusage.FieldBonusPercent:
+		return m.BonusPercent()
+	case promocodeusage.FieldMaxBonusAmount:
+		return m.MaxBonusAmount()
+	case promocodeusage.FieldStatus:
+		return m.Status()
+	case promocodeusage.FieldSource:
+		return m.Source()
+	case promocodeusage.FieldBaseAmount:
+		return m.BaseAmount()
+	case promocodeusage.FieldRedeemCodeID:
+		return m.RedeemCodeID()
+	case promocodeusage.FieldAppliedAt:
+		return m.AppliedAt()
 	case promocodeusage.FieldUsedAt:
 		return m.UsedAt()
 	}
@@ -11385,6 +12823,22 @@ func (m *PromoCodeUsageMutation) OldField(ctx context.Context, name string) (ent
 		return m.OldUserID(ctx)
 	case promocodeusage.FieldBonusAmount:
 		return m.OldBonusAmount(ctx)
+	case promocodeusage.FieldBonusType:
+		return m.OldBonusType(ctx)
+	case promocodeusage.FieldBonusPercent:
+		return m.OldBonusPercent(ctx)
+	case promocodeusage.FieldMaxBonusAmount:
+		return m.OldMaxBonusAmount(ctx)
+	case promocodeusage.FieldStatus:
+		return m.OldStatus(ctx)
+	case promocodeusage.FieldSource:
+		return m.OldSource(ctx)
+	case promocodeusage.FieldBaseAmount:
+		return m.OldBaseAmount(ctx)
+	case promocodeusage.FieldRedeemCodeID:
+		return m.OldRedeemCodeID(ctx)
+	case promocodeusage.FieldAppliedAt:
+		return m.OldAppliedAt(ctx)
 	case promocodeusage.FieldUsedAt:
 		return m.OldUsedAt(ctx)
 	}
@@ -11417,6 +12871,62 @@ func (m *PromoCodeUsageMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetBonusAmount(v)
 		return nil
+	case promocodeusage.FieldBonusType:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBonusType(v)
+		return nil
+	case promocodeusage.FieldBonusPercent:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBonusPercent(v)
+		return nil
+	case promocodeusage.FieldMaxBonusAmount:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMaxBonusAmount(v)
+		return nil
+	case promocodeusage.FieldStatus:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStatus(v)
+		return nil
+	case promocodeusage.FieldSource:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSource(v)
+		return nil
+	case promocodeusage.FieldBaseAmount:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBaseAmount(v)
+		return nil
+	case promocodeusage.FieldRedeemCodeID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRedeemCodeID(v)
+		return nil
+	case promocodeusage.FieldAppliedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAppliedAt(v)
+		return nil
 	case promocodeusage.FieldUsedAt:
 		v, ok := value.(time.Time)
 		if !ok {
@@ -11435,6 +12945,18 @@ func (m *PromoCodeUsageMutation) AddedFields() []string {
 	if m.addbonus_amount != nil {
 		fields = append(fields, promocodeusage.FieldBonusAmount)
 	}
+	if m.addbonus_percent != nil {
+		fields = append(fields, promocodeusage.FieldBonusPercent)
+	}
+	if m.addmax_bonus_amount != nil {
+		fields = append(fields, promocodeusage.FieldMaxBonusAmount)
+	}
+	if m.addbase_amount != nil {
+		fields = append(fields, promocodeusage.FieldBaseAmount)
+	}
+	if m.addredeem_code_id != nil {
+		fields = append(fields, promocodeusage.FieldRedeemCodeID)
+	}
 	return fields
 }
 
@@ -11445,6 +12967,14 @@ func (m *PromoCodeUsageMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
 	case promocodeusage.FieldBonusAmount:
 		return m.AddedBonusAmount()
+	case promocodeusage.FieldBonusPercent:
+		return m.AddedBonusPercent()
+	case promocodeusage.FieldMaxBonusAmount:
+		return m.AddedMaxBonusAmount()
+	case promocodeusage.FieldBaseAmount:
+		return m.AddedBaseAmount()
+	case promocodeusage.FieldRedeemCodeID:
+		return m.AddedRedeemCodeID()
 	}
 	return nil, false
 }
@@ -11461,6 +12991,34 @@ func (m *PromoCodeUsageMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddBonusAmount(v)
 		return nil
+	case promocodeusage.FieldBonusPercent:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddBonusPercent(v)
+		return nil
+	case promocodeusage.FieldMaxBonusAmount:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddMaxBonusAmount(v)
+		return nil
+	case promocodeusage.FieldBaseAmount:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddBaseAmount(v)
+		return nil
+	case promocodeusage.FieldRedeemCodeID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddRedeemCodeID(v)
+		return nil
 	}
 	return fmt.Errorf("unknown PromoCodeUsage numeric field %s", name)
 }
@@ -11468,7 +13026,14 @@ func (m *PromoCodeUsageMutation) AddField(name string, value ent.Value) error {
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
 func (m *PromoCodeUsageMutation) ClearedFields() []string {
-	return nil
+	var fields []string
+	if m.FieldCleared(promocodeusage.FieldRedeemCodeID) {
+		fields = append(fields, promocodeusage.FieldRedeemCodeID)
+	}
+	if m.FieldCleared(promocodeusage.FieldAppliedAt) {
+		fields = append(fields, promocodeusage.FieldAppliedAt)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
@@ -11481,6 +13046,14 @@ func (m *PromoCodeUsageMutation) FieldCleared(name string) bool {
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
 func (m *PromoCodeUsageMutation) ClearField(name string) error {
+	switch name {
+	case promocodeusage.FieldRedeemCodeID:
+		m.ClearRedeemCodeID()
+		return nil
+	case promocodeusage.FieldAppliedAt:
+		m.ClearAppliedAt()
+		return nil
+	}
 	return fmt.Errorf("unknown PromoCodeUsage nullable field %s", name)
 }
 
@@ -11497,6 +13070,30 @@ func (m *PromoCodeUsageMutation) ResetField(name string) error {
 	case promocodeusage.FieldBonusAmount:
 		m.ResetBonusAmount()
 		return nil
+	case promocodeusage.FieldBonusType:
+		m.ResetBonusType()
+		return nil
+	case promocodeusage.FieldBonusPercent:
+		m.ResetBonusPercent()
+		return nil
+	case promocodeusage.FieldMaxBonusAmount:
+		m.ResetMaxBonusAmount()
+		return nil
+	case promocodeusage.FieldStatus:
+		m.ResetStatus()
+		return nil
+	case promocodeusage.FieldSource:
+		m.ResetSource()
+		return nil
+	case promocodeusage.FieldBaseAmount:
+		m.ResetBaseAmount()
+		return nil
+	case promocodeusage.FieldRedeemCodeID:
+		m.ResetRedeemCodeID()
+		return nil
+	case promocodeusage.FieldAppliedAt:
+		m.ResetAppliedAt()
+		return nil
 	case promocodeusage.FieldUsedAt:
 		m.ResetUsedAt()
 		return nil
@@ -19,12 +19,30 @@ type PromoCode struct {
 	ID int64 `json:"id,omitempty"`
 	// 优惠码
 	Code string `json:"code,omitempty"`
-	// 赠送余额金额
+	// 赠送余额金额（bonus_type=fixed）
 	BonusAmount float64 `json:"bonus_amount,omitempty"`
+	// 赠送方式: fixed, percent
+	BonusType string `json:"bonus_type,omitempty"`
+	// 下次充值赠送比例（百分比，bonus_type=percent）
+	BonusPercent float64 `json:"bonus_percent,omitempty"`
+	// 比例赠送的单次上限，0表示不限
+	MaxBonusAmount float64 `json:"max_bonus_amount,omitempty"`
 	// 最大使用次数，0表示无限制
 	MaxUses int `json:"max_uses,omitempty"`
 	// 已使用次数
 	UsedCount int `json:"used_count,omitempty"`
+	// 每个用户最多使用次数，0表示无限制
+	PerUserLimit int `json:"per_user_limit,omitempty"`
+	// 使用场景: register, panel, any
+	Scope string `json:"scope,omitempty"`
+	// 仅限新用户
+	NewUsersOnly bool `json:"new_users_only,omitempty"`
+	// 注册后多少天内视为新用户（面板兑换），0表示仅注册时可用
+	NewUserDays int `json:"new_user_days,omitempty"`
+	// 要求持有该分组的有效订阅，null表示不限
+	RequiredGroupID *int64 `json:"required_group_id,omitempty"`
+	// 生效时间，null表示立即生效
+	StartsAt *time.Time `json:"starts_at,omitempty"`
 	// 状态: active, disabled
 	Status string `json:"status,omitempty"`
 	// 过期时间，null表示永不过期
@@ -64,13 +82,15 @@ func (*PromoCode) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case promocode.FieldBonusAmount:
+		case promocode.FieldNewUsersOnly:
+			values[i] = new(sql.NullBool)
+		case promocode.FieldBonusAmount, promocode.FieldBonusPercent, promocode.FieldMaxBonusAmount:
 			values[i] = new(sql.NullFloat64)
-		case promocode.FieldID, promocode.FieldMaxUses, promocode.FieldUsedCount:
+		case promocode.FieldID, promocode.FieldMaxUses, promocode.FieldUsedCount, promocode.FieldPerUserLimit, promocode.FieldNewUserDays, promocode.FieldRequiredGroupID:
 			values[i] = new(sql.NullInt64)
-		case promocode.FieldCode, promocode.FieldStatus, promocode.FieldNotes:
+		case promocode.FieldCode, promocode.FieldBonusType, promocode.FieldScope, promocode.FieldStatus, promocode.FieldNotes:
 			values[i] = new(sql.NullString)
-		case promocode.FieldExpiresAt, promocode.FieldCreatedAt, promocode.FieldUpdatedAt:
+		case promocode.FieldStartsAt, promocode.FieldExpiresAt, promocode.FieldCreatedAt, promocode.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -105,6 +125,24 @@ func (_m *PromoCode) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.BonusAmount = value.Float64
 			}
+		case promocode.FieldBonusType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field bonus_type", values[i])
+			} else if value.Valid {
+				_m.BonusType = value.String
+			}
+		case promocode.FieldBonusPercent:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field bonus_percent", values[i])
+			} else if value.Valid {
+				_m.BonusPercent = value.Float64
+			}
+		case promocode.FieldMaxBonusAmount:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field max_bonus_amount", values[i])
+			} else if value.Valid {
+				_m.MaxBonusAmount = value.Float64
+			}
 		case promocode.FieldMaxUses:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field max_uses", values[i])
@@ -117,6 +155,44 @@ func (_m *PromoCode) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.UsedCount = int(value.Int64)
 			}
+		case promocode.FieldPerUserLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field per_user_limit", values[i])
+			} else if value.Valid {
+				_m.PerUserLimit = int(value.Int64)
+			}
+		case promocode.FieldScope:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field scope", values[i])
+			} else if value.Valid {
+				_m.Scope = value.String
+			}
+		case promocode.FieldNewUsersOnly:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field new_users_only", values[i])
+			} else if value.Valid {
+				_m.NewUsersOnly = value.Bool
+			}
+		case promocode.FieldNewUserDays:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field new_user_days", values[i])
+			} else if value.Valid {
+				_m.NewUserDays = int(value.Int64)
+			}
+		case promocode.FieldRequiredGroupID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field required_group_id", values[i])
+			} else if value.Valid {
+				_m.RequiredGroupID = new(int64)
+				*_m.RequiredGroupID = value.Int64
+			}
+		case promocode.FieldStartsAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field starts_at", values[i])
+			} else if value.Valid {
+				_m.StartsAt = new(time.Time)
+				*_m.StartsAt = value.Time
+			}
 		case promocode.FieldStatus:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field status", values[i])
@@ -196,12 +272,43 @@ func (_m *PromoCode) String() string {
 	builder.WriteString("bonus_amount=")
 	builder.WriteString(fmt.Sprintf("%v", _m.BonusAmount))
 	builder.WriteString(", ")
+	builder.WriteString("bonus_type=")
+	builder.WriteString(_m.BonusType)
+	builder.WriteString(", ")
+	builder.WriteString("bonus_percent=")
+	builder.WriteString(fmt.Sprintf("%v", _m.BonusPercent))
+	builder.WriteString(", ")
+	builder.WriteString("max_bonus_amount=")
+	builder.WriteString(fmt.Sprintf("%v", _m.MaxBonusAmount))
+	builder.WriteString(", ")
 	builder.WriteString("max_uses=")
 	builder.WriteString(fmt.Sprintf("%v", _m.MaxUses))
 	builder.WriteString(", ")
 	builder.WriteString("used_count=")
 	builder.WriteString(fmt.Sprintf("%v", _m.UsedCount))
 	builder.WriteString(", ")
+	builder.WriteString("per_user_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.PerUserLimit))
+	builder.WriteString(", ")
+	builder.WriteString("scope=")
+	builder.WriteString(_m.Scope)
+	builder.WriteString(", ")
+	builder.WriteString("new_users_only=")
+	builder.WriteString(fmt.Sprintf("%v", _m.NewUsersOnly))
+	builder.WriteString(", ")
+	builder.WriteString("new_user_days=")
+	builder.WriteString(fmt.Sprintf("%v", _m.NewUserDays))
+	builder.WriteString(", ")
+	if v := _m.RequiredGroupID; v != nil {
+		builder.WriteString("required_group_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.StartsAt; v != nil {
+		builder.WriteString("starts_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("status=")
 	builder.WriteString(_m.Status)
 	builder.WriteString(", ")
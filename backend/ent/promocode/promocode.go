@@ -18,10 +18,28 @@ const (
 	FieldCode = "code"
 	// FieldBonusAmount holds the string denoting the bonus_amount field in the database.
 	FieldBonusAmount = "bonus_amount"
+	// FieldBonusType holds the string denoting the bonus_type field in the database.
+	FieldBonusType = "bonus_type"
+	// FieldBonusPercent holds the string denoting the bonus_percent field in the database.
+	FieldBonusPercent = "bonus_percent"
+	// FieldMaxBonusAmount holds the string denoting the max_bonus_amount field in the database.
+	FieldMaxBonusAmount = "max_bonus_amount"
 	// FieldMaxUses holds the string denoting the max_uses field in the database.
 	FieldMaxUses = "max_uses"
 	// FieldUsedCount holds the string denoting the used_count field in the database.
 	FieldUsedCount = "used_count"
+	// FieldPerUserLimit holds the string denoting the per_user_limit field in the database.
+	FieldPerUserLimit = "per_user_limit"
+	// FieldScope holds the string denoting the scope field in the database.
+	FieldScope = "scope"
+	// FieldNewUsersOnly holds the string denoting the new_users_only field in the database.
+	FieldNewUsersOnly = "new_users_only"
+	// FieldNewUserDays holds the string denoting the new_user_days field in the database.
+	FieldNewUserDays = "new_user_days"
+	// FieldRequiredGroupID holds the string denoting the required_group_id field in the database.
+	FieldRequiredGroupID = "required_group_id"
+	// FieldStartsAt holds the string denoting the starts_at field in the database.
+	FieldStartsAt = "starts_at"
 	// FieldStatus holds the string denoting the status field in the database.
 	FieldStatus = "status"
 	// FieldExpiresAt holds the string denoting the expires_at field in the database.
@@ -50,8 +68,17 @@ var Columns = []string{
 	FieldID,
 	FieldCode,
 	FieldBonusAmount,
+	FieldBonusType,
+	FieldBonusPercent,
+	FieldMaxBonusAmount,
 	FieldMaxUses,
 	FieldUsedCount,
+	FieldPerUserLimit,
+	FieldScope,
+	FieldNewUsersOnly,
+	FieldNewUserDays,
+	FieldRequiredGroupID,
+	FieldStartsAt,
 	FieldStatus,
 	FieldExpiresAt,
 	FieldNotes,
@@ -74,10 +101,28 @@ var (
 	CodeValidator func(string) error
 	// DefaultBonusAmount holds the default value on creation for the "bonus_amount" field.
 	DefaultBonusAmount float64
+	// DefaultBonusType holds the default value on creation for the "bonus_type" field.
+	DefaultBonusType string
+	// BonusTypeValidator is a validator for the "bonus_type" field. It is called by the builders before save.
+	BonusTypeValidator func(string) error
+	// DefaultBonusPercent holds the default value on creation for the "bonus_percent" field.
+	DefaultBonusPercent float64
+	// DefaultMaxBonusAmount holds the default value on creation for the "max_bonus_amount" field.
+	DefaultMaxBonusAmount float64
 	// DefaultMaxUses holds the default value on creation for the "max_uses" field.
 	DefaultMaxUses int
 	// DefaultUsedCount holds the default value on creation for the "used_count" field.
 	DefaultUsedCount int
+	// DefaultPerUserLimit holds the default value on creation for the "per_user_limit" field.
+	DefaultPerUserLimit int
+	// DefaultScope holds the default value on creation for the "scope" field.
+	DefaultScope string
+	// ScopeValidator is a validator for the "scope" field. It is called by the builders before save.
+	ScopeValidator func(string) error
+	// DefaultNewUsersOnly holds the default value on creation for the "new_users_only" field.
+	DefaultNewUsersOnly bool
+	// DefaultNewUserDays holds the default value on creation for the "new_user_days" field.
+	DefaultNewUserDays int
 	// DefaultStatus holds the default value on creation for the "status" field.
 	DefaultStatus string
 	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
@@ -108,6 +153,21 @@ func ByBonusAmount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldBonusAmount, opts...).ToFunc()
 }
 
+// ByBonusType orders the results by the bonus_type field.
+func ByBonusType(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBonusType, opts...).ToFunc()
+}
+
+// ByBonusPercent orders the results by the bonus_percent field.
+func ByBonusPercent(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBonusPercent, opts...).ToFunc()
+}
+
+// ByMaxBonusAmount orders the results by the max_bonus_amount field.
+func ByMaxBonusAmount(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMaxBonusAmount, opts...).ToFunc()
+}
+
 // ByMaxUses orders the results by the max_uses field.
 func ByMaxUses(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldMaxUses, opts...).ToFunc()
@@ -118,6 +178,36 @@ func ByUsedCount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUsedCount, opts...).ToFunc()
 }
 
+// ByPerUserLimit orders the results by the per_user_limit field.
+func ByPerUserLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPerUserLimit, opts...).ToFunc()
+}
+
+// ByScope orders the results by the scope field.
+func ByScope(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldScope, opts...).ToFunc()
+}
+
+// ByNewUsersOnly orders the results by the new_users_only field.
+func ByNewUsersOnly(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldNewUsersOnly, opts...).ToFunc()
+}
+
+// ByNewUserDays orders the results by the new_user_days field.
+func ByNewUserDays(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldNewUserDays, opts...).ToFunc()
+}
+
+// ByRequiredGroupID orders the results by the required_group_id field.
+func ByRequiredGroupID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRequiredGroupID, opts...).ToFunc()
+}
+
+// ByStartsAt orders the results by the starts_at field.
+func ByStartsAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStartsAt, opts...).ToFunc()
+}
+
 // ByStatus orders the results by the status field.
 func ByStatus(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldStatus, opts...).ToFunc()
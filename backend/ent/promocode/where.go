@@ -65,6 +65,21 @@ func BonusAmount(v float64) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldBonusAmount, v))
 }
 
+// BonusType applies equality check predicate on the "bonus_type" field. It's identical to BonusTypeEQ.
+func BonusType(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldBonusType, v))
+}
+
+// BonusPercent applies equality check predicate on the "bonus_percent" field. It's identical to BonusPercentEQ.
+func BonusPercent(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldBonusPercent, v))
+}
+
+// MaxBonusAmount applies equality check predicate on the "max_bonus_amount" field. It's identical to MaxBonusAmountEQ.
+func MaxBonusAmount(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldMaxBonusAmount, v))
+}
+
 // MaxUses applies equality check predicate on the "max_uses" field. It's identical to MaxUsesEQ.
 func MaxUses(v int) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldMaxUses, v))
@@ -75,6 +90,36 @@ func UsedCount(v int) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldUsedCount, v))
 }
 
+// PerUserLimit applies equality check predicate on the "per_user_limit" field. It's identical to PerUserLimitEQ.
+func PerUserLimit(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldPerUserLimit, v))
+}
+
+// Scope applies equality check predicate on the "scope" field. It's identical to ScopeEQ.
+func Scope(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldScope, v))
+}
+
+// NewUsersOnly applies equality check predicate on the "new_users_only" field. It's identical to NewUsersOnlyEQ.
+func NewUsersOnly(v bool) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldNewUsersOnly, v))
+}
+
+// NewUserDays applies equality check predicate on the "new_user_days" field. It's identical to NewUserDaysEQ.
+func NewUserDays(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldNewUserDays, v))
+}
+
+// RequiredGroupID applies equality check predicate on the "required_group_id" field. It's identical to RequiredGroupIDEQ.
+func RequiredGroupID(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldRequiredGroupID, v))
+}
+
+// StartsAt applies equality check predicate on the "starts_at" field. It's identical to StartsAtEQ.
+func StartsAt(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldStartsAt, v))
+}
+
 // Status applies equality check predicate on the "status" field. It's identical to StatusEQ.
 func Status(v string) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldStatus, v))
@@ -205,6 +250,151 @@ func BonusAmountLTE(v float64) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldLTE(FieldBonusAmount, v))
 }
 
+// BonusTypeEQ applies the EQ predicate on the "bonus_type" field.
+func BonusTypeEQ(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldBonusType, v))
+}
+
+// BonusTypeNEQ applies the NEQ predicate on the "bonus_type" field.
+func BonusTypeNEQ(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldBonusType, v))
+}
+
+// BonusTypeIn applies the In predicate on the "bonus_type" field.
+func BonusTypeIn(vs ...string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldBonusType, vs...))
+}
+
+// BonusTypeNotIn applies the NotIn predicate on the "bonus_type" field.
+func BonusTypeNotIn(vs ...string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldBonusType, vs...))
+}
+
+// BonusTypeGT applies the GT predicate on the "bonus_type" field.
+func BonusTypeGT(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldBonusType, v))
+}
+
+// BonusTypeGTE applies the GTE predicate on the "bonus_type" field.
+func BonusTypeGTE(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldBonusType, v))
+}
+
+// BonusTypeLT applies the LT predicate on the "bonus_type" field.
+func BonusTypeLT(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldBonusType, v))
+}
+
+// BonusTypeLTE applies the LTE predicate on the "bonus_type" field.
+func BonusTypeLTE(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldBonusType, v))
+}
+
+// BonusTypeContains applies the Contains predicate on the "bonus_type" field.
+func BonusTypeContains(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldContains(FieldBonusType, v))
+}
+
+// BonusTypeHasPrefix applies the HasPrefix predicate on the "bonus_type" field.
+func BonusTypeHasPrefix(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldHasPrefix(FieldBonusType, v))
+}
+
+// BonusTypeHasSuffix applies the HasSuffix predicate on the "bonus_type" field.
+func BonusTypeHasSuffix(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldHasSuffix(FieldBonusType, v))
+}
+
+// BonusTypeEqualFold applies the EqualFold predicate on the "bonus_type" field.
+func BonusTypeEqualFold(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEqualFold(FieldBonusType, v))
+}
+
+// BonusTypeContainsFold applies the ContainsFold predicate on the "bonus_type" field.
+func BonusTypeContainsFold(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldContainsFold(FieldBonusType, v))
+}
+
+// BonusPercentEQ applies the EQ predicate on the "bonus_percent" field.
+func BonusPercentEQ(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldBonusPercent, v))
+}
+
+// BonusPercentNEQ applies the NEQ predicate on the "bonus_percent" field.
+func BonusPercentNEQ(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldBonusPercent, v))
+}
+
+// BonusPercentIn applies the In predicate on the "bonus_percent" field.
+func BonusPercentIn(vs ...float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldBonusPercent, vs...))
+}
+
+// BonusPercentNotIn applies the NotIn predicate on the "bonus_percent" field.
+func BonusPercentNotIn(vs ...float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldBonusPercent, vs...))
+}
+
+// BonusPercentGT applies the GT predicate on the "bonus_percent" field.
+func BonusPercentGT(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldBonusPercent, v))
+}
+
+// BonusPercentGTE applies the GTE predicate on the "bonus_percent" field.
+func BonusPercentGTE(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldBonusPercent, v))
+}
+
+// BonusPercentLT applies the LT predicate on the "bonus_percent" field.
+func BonusPercentLT(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldBonusPercent, v))
+}
+
+// BonusPercentLTE applies the LTE predicate on the "bonus_percent" field.
+func BonusPercentLTE(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldBonusPercent, v))
+}
+
+// MaxBonusAmountEQ applies the EQ predicate on the "max_bonus_amount" field.
+func MaxBonusAmountEQ(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldMaxBonusAmount, v))
+}
+
+// MaxBonusAmountNEQ applies the NEQ predicate on the "max_bonus_amount" field.
+func MaxBonusAmountNEQ(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldMaxBonusAmount, v))
+}
+
+// MaxBonusAmountIn applies the In predicate on the "max_bonus_amount" field.
+func MaxBonusAmountIn(vs ...float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldMaxBonusAmount, vs...))
+}
+
+// MaxBonusAmountNotIn applies the NotIn predicate on the "max_bonus_amount" field.
+func MaxBonusAmountNotIn(vs ...float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldMaxBonusAmount, vs...))
+}
+
+// MaxBonusAmountGT applies the GT predicate on the "max_bonus_amount" field.
+func MaxBonusAmountGT(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldMaxBonusAmount, v))
+}
+
+// MaxBonusAmountGTE applies the GTE predicate on the "max_bonus_amount" field.
+func MaxBonusAmountGTE(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldMaxBonusAmount, v))
+}
+
+// MaxBonusAmountLT applies the LT predicate on the "max_bonus_amount" field.
+func MaxBonusAmountLT(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldMaxBonusAmount, v))
+}
+
+// MaxBonusAmountLTE applies the LTE predicate on the "max_bonus_amount" field.
+func MaxBonusAmountLTE(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldMaxBonusAmount, v))
+}
+
 // MaxUsesEQ applies the EQ predicate on the "max_uses" field.
 func MaxUsesEQ(v int) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldMaxUses, v))
@@ -285,6 +475,261 @@ func UsedCountLTE(v int) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldLTE(FieldUsedCount, v))
 }
 
+// PerUserLimitEQ applies the EQ predicate on the "per_user_limit" field.
+func PerUserLimitEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldPerUserLimit, v))
+}
+
+// PerUserLimitNEQ applies the NEQ predicate on the "per_user_limit" field.
+func PerUserLimitNEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldPerUserLimit, v))
+}
+
+// PerUserLimitIn applies the In predicate on the "per_user_limit" field.
+func PerUserLimitIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldPerUserLimit, vs...))
+}
+
+// PerUserLimitNotIn applies the NotIn predicate on the "per_user_limit" field.
+func PerUserLimitNotIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldPerUserLimit, vs...))
+}
+
+// PerUserLimitGT applies the GT predicate on the "per_user_limit" field.
+func PerUserLimitGT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldPerUserLimit, v))
+}
+
+// PerUserLimitGTE applies the GTE predicate on the "per_user_limit" field.
+func PerUserLimitGTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldPerUserLimit, v))
+}
+
+// PerUserLimitLT applies the LT predicate on the "per_user_limit" field.
+func PerUserLimitLT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldPerUserLimit, v))
+}
+
+// PerUserLimitLTE applies the LTE predicate on the "per_user_limit" field.
+func PerUserLimitLTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldPerUserLimit, v))
+}
+
+// ScopeEQ applies the EQ predicate on the "scope" field.
+func ScopeEQ(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldScope, v))
+}
+
+// ScopeNEQ applies the NEQ predicate on the "scope" field.
+func ScopeNEQ(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldScope, v))
+}
+
+// ScopeIn applies the In predicate on the "scope" field.
+func ScopeIn(vs ...string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldScope, vs...))
+}
+
+// ScopeNotIn applies the NotIn predicate on the "scope" field.
+func ScopeNotIn(vs ...string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldScope, vs...))
+}
+
+// ScopeGT applies the GT predicate on the "scope" field.
+func ScopeGT(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldScope, v))
+}
+
+// ScopeGTE applies the GTE predicate on the "scope" field.
+func ScopeGTE(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldScope, v))
+}
+
+// ScopeLT applies the LT predicate on the "scope" field.
+func ScopeLT(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldScope, v))
+}
+
+// ScopeLTE applies the LTE predicate on the "scope" field.
+func ScopeLTE(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldScope, v))
+}
+
+// ScopeContains applies the Contains predicate on the "scope" field.
+func ScopeContains(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldContains(FieldScope, v))
+}
+
+// ScopeHasPrefix applies the HasPrefix predicate on the "scope" field.
+func ScopeHasPrefix(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldHasPrefix(FieldScope, v))
+}
+
+// ScopeHasSuffix applies the HasSuffix predicate on the "scope" field.
+func ScopeHasSuffix(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldHasSuffix(FieldScope, v))
+}
+
+// ScopeEqualFold applies the EqualFold predicate on the "scope" field.
+func ScopeEqualFold(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEqualFold(FieldScope, v))
+}
+
+// ScopeContainsFold applies the ContainsFold predicate on the "scope" field.
+func ScopeContainsFold(v string) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldContainsFold(FieldScope, v))
+}
+
+// NewUsersOnlyEQ applies the EQ predicate on the "new_users_only" field.
+func NewUsersOnlyEQ(v bool) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldNewUsersOnly, v))
+}
+
+// NewUsersOnlyNEQ applies the NEQ predicate on the "new_users_only" field.
+func NewUsersOnlyNEQ(v bool) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldNewUsersOnly, v))
+}
+
+// NewUserDaysEQ applies the EQ predicate on the "new_user_days" field.
+func NewUserDaysEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldNewUserDays, v))
+}
+
+// NewUserDaysNEQ applies the NEQ predicate on the "new_user_days" field.
+func NewUserDaysNEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldNewUserDays, v))
+}
+
+// NewUserDaysIn applies the In predicate on the "new_user_days" field.
+func NewUserDaysIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldNewUserDays, vs...))
+}
+
+// NewUserDaysNotIn applies the NotIn predicate on the "new_user_days" field.
+func NewUserDaysNotIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldNewUserDays, vs...))
+}
+
+// NewUserDaysGT applies the GT predicate on the "new_user_days" field.
+func NewUserDaysGT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldNewUserDays, v))
+}
+
+// NewUserDaysGTE applies the GTE predicate on the "new_user_days" field.
+func NewUserDaysGTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldNewUserDays, v))
+}
+
+// NewUserDaysLT applies the LT predicate on the "new_user_days" field.
+func NewUserDaysLT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldNewUserDays, v))
+}
+
+// NewUserDaysLTE applies the LTE predicate on the "new_user_days" field.
+func NewUserDaysLTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldNewUserDays, v))
+}
+
+// RequiredGroupIDEQ applies the EQ predicate on the "required_group_id" field.
+func RequiredGroupIDEQ(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldRequiredGroupID, v))
+}
+
+// RequiredGroupIDNEQ applies the NEQ predicate on the "required_group_id" field.
+func RequiredGroupIDNEQ(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldRequiredGroupID, v))
+}
+
+// RequiredGroupIDIn applies the In predicate on the "required_group_id" field.
+func RequiredGroupIDIn(vs ...int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldRequiredGroupID, vs...))
+}
+
+// RequiredGroupIDNotIn applies the NotIn predicate on the "required_group_id" field.
+func RequiredGroupIDNotIn(vs ...int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldRequiredGroupID, vs...))
+}
+
+// RequiredGroupIDGT applies the GT predicate on the "required_group_id" field.
+func RequiredGroupIDGT(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldRequiredGroupID, v))
+}
+
+// RequiredGroupIDGTE applies the GTE predicate on the "required_group_id" field.
+func RequiredGroupIDGTE(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldRequiredGroupID, v))
+}
+
+// RequiredGroupIDLT applies the LT predicate on the "required_group_id" field.
+func RequiredGroupIDLT(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldRequiredGroupID, v))
+}
+
+// RequiredGroupIDLTE applies the LTE predicate on the "required_group_id" field.
+func RequiredGroupIDLTE(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldRequiredGroupID, v))
+}
+
+// RequiredGroupIDIsNil applies the IsNil predicate on the "required_group_id" field.
+func RequiredGroupIDIsNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIsNull(FieldRequiredGroupID))
+}
+
+// RequiredGroupIDNotNil applies the NotNil predicate on the "required_group_id" field.
+func RequiredGroupIDNotNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotNull(FieldRequiredGroupID))
+}
+
+// StartsAtEQ applies the EQ predicate on the "starts_at" field.
+func StartsAtEQ(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldStartsAt, v))
+}
+
+// StartsAtNEQ applies the NEQ predicate on the "starts_at" field.
+func StartsAtNEQ(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldStartsAt, v))
+}
+
+// StartsAtIn applies the In predicate on the "starts_at" field.
+func StartsAtIn(vs ...time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldStartsAt, vs...))
+}
+
+// StartsAtNotIn applies the NotIn predicate on the "starts_at" field.
+func StartsAtNotIn(vs ...time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldStartsAt, vs...))
+}
+
+// StartsAtGT applies the GT predicate on the "starts_at" field.
+func StartsAtGT(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldStartsAt, v))
+}
+
+// StartsAtGTE applies the GTE predicate on the "starts_at" field.
+func StartsAtGTE(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldStartsAt, v))
+}
+
+// StartsAtLT applies the LT predicate on the "starts_at" field.
+func StartsAtLT(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldStartsAt, v))
+}
+
+// StartsAtLTE applies the LTE predicate on the "starts_at" field.
+func StartsAtLTE(v time.Time) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldStartsAt, v))
+}
+
+// StartsAtIsNil applies the IsNil predicate on the "starts_at" field.
+func StartsAtIsNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIsNull(FieldStartsAt))
+}
+
+// StartsAtNotNil applies the NotNil predicate on the "starts_at" field.
+func StartsAtNotNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotNull(FieldStartsAt))
+}
+
 // StatusEQ applies the EQ predicate on the "status" field.
 func StatusEQ(v string) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldStatus, v))
@@ -43,6 +43,48 @@ func (_c *PromoCodeCreate) SetNillableBonusAmount(v *float64) *PromoCodeCreate {
 	return _c
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (_c *PromoCodeCreate) SetBonusType(v string) *PromoCodeCreate {
+	_c.mutation.SetBonusType(v)
+	return _c
+}
+
+// SetNillableBonusType sets the "bonus_type" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableBonusType(v *string) *PromoCodeCreate {
+	if v != nil {
+		_c.SetBonusType(*v)
+	}
+	return _c
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (_c *PromoCodeCreate) SetBonusPercent(v float64) *PromoCodeCreate {
+	_c.mutation.SetBonusPercent(v)
+	return _c
+}
+
+// SetNillableBonusPercent sets the "bonus_percent" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableBonusPercent(v *float64) *PromoCodeCreate {
+	if v != nil {
+		_c.SetBonusPercent(*v)
+	}
+	return _c
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (_c *PromoCodeCreate) SetMaxBonusAmount(v float64) *PromoCodeCreate {
+	_c.mutation.SetMaxBonusAmount(v)
+	return _c
+}
+
+// SetNillableMaxBonusAmount sets the "max_bonus_amount" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableMaxBonusAmount(v *float64) *PromoCodeCreate {
+	if v != nil {
+		_c.SetMaxBonusAmount(*v)
+	}
+	return _c
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (_c *PromoCodeCreate) SetMaxUses(v int) *PromoCodeCreate {
 	_c.mutation.SetMaxUses(v)
@@ -71,6 +113,90 @@ func (_c *PromoCodeCreate) SetNillableUsedCount(v *int) *PromoCodeCreate {
 	return _c
 }
 
+// SetPerUserLimit sets the "per_user_limit" field.
+func (_c *PromoCodeCreate) SetPerUserLimit(v int) *PromoCodeCreate {
+	_c.mutation.SetPerUserLimit(v)
+	return _c
+}
+
+// SetNillablePerUserLimit sets the "per_user_limit" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillablePerUserLimit(v *int) *PromoCodeCreate {
+	if v != nil {
+		_c.SetPerUserLimit(*v)
+	}
+	return _c
+}
+
+// SetScope sets the "scope" field.
+func (_c *PromoCodeCreate) SetScope(v string) *PromoCodeCreate {
+	_c.mutation.SetScope(v)
+	return _c
+}
+
+// SetNillableScope sets the "scope" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableScope(v *string) *PromoCodeCreate {
+	if v != nil {
+		_c.SetScope(*v)
+	}
+	return _c
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (_c *PromoCodeCreate) SetNewUsersOnly(v bool) *PromoCodeCreate {
+	_c.mutation.SetNewUsersOnly(v)
+	return _c
+}
+
+// SetNillableNewUsersOnly sets the "new_users_only" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableNewUsersOnly(v *bool) *PromoCodeCreate {
+	if v != nil {
+		_c.SetNewUsersOnly(*v)
+	}
+	return _c
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (_c *PromoCodeCreate) SetNewUserDays(v int) *PromoCodeCreate {
+	_c.mutation.SetNewUserDays(v)
+	return _c
+}
+
+// SetNillableNewUserDays sets the "new_user_days" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableNewUserDays(v *int) *PromoCodeCreate {
+	if v != nil {
+		_c.SetNewUserDays(*v)
+	}
+	return _c
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (_c *PromoCodeCreate) SetRequiredGroupID(v int64) *PromoCodeCreate {
+	_c.mutation.SetRequiredGroupID(v)
+	return _c
+}
+
+// SetNillableRequiredGroupID sets the "required_group_id" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableRequiredGroupID(v *int64) *PromoCodeCreate {
+	if v != nil {
+		_c.SetRequiredGroupID(*v)
+	}
+	return _c
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (_c *PromoCodeCreate) SetStartsAt(v time.Time) *PromoCodeCreate {
+	_c.mutation.SetStartsAt(v)
+	return _c
+}
+
+// SetNillableStartsAt sets the "starts_at" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableStartsAt(v *time.Time) *PromoCodeCreate {
+	if v != nil {
+		_c.SetStartsAt(*v)
+	}
+	return _c
+}
+
 // SetStatus sets the "status" field.
 func (_c *PromoCodeCreate) SetStatus(v string) *PromoCodeCreate {
 	_c.mutation.SetStatus(v)
@@ -195,6 +321,18 @@ func (_c *PromoCodeCreate) defaults() {
 		v := promocode.DefaultBonusAmount
 		_c.mutation.SetBonusAmount(v)
 	}
+	if _, ok := _c.mutation.BonusType(); !ok {
+		v := promocode.DefaultBonusType
+		_c.mutation.SetBonusType(v)
+	}
+	if _, ok := _c.mutation.BonusPercent(); !ok {
+		v := promocode.DefaultBonusPercent
+		_c.mutation.SetBonusPercent(v)
+	}
+	if _, ok := _c.mutation.MaxBonusAmount(); !ok {
+		v := promocode.DefaultMaxBonusAmount
+		_c.mutation.SetMaxBonusAmount(v)
+	}
 	if _, ok := _c.mutation.MaxUses(); !ok {
 		v := promocode.DefaultMaxUses
 		_c.mutation.SetMaxUses(v)
@@ -203,6 +341,22 @@ func (_c *PromoCodeCreate) defaults() {
 		v := promocode.DefaultUsedCount
 		_c.mutation.SetUsedCount(v)
 	}
+	if _, ok := _c.mutation.PerUserLimit(); !ok {
+		v := promocode.DefaultPerUserLimit
+		_c.mutation.SetPerUserLimit(v)
+	}
+	if _, ok := _c.mutation.Scope(); !ok {
+		v := promocode.DefaultScope
+		_c.mutation.SetScope(v)
+	}
+	if _, ok := _c.mutation.NewUsersOnly(); !ok {
+		v := promocode.DefaultNewUsersOnly
+		_c.mutation.SetNewUsersOnly(v)
+	}
+	if _, ok := _c.mutation.NewUserDays(); !ok {
+		v := promocode.DefaultNewUserDays
+		_c.mutation.SetNewUserDays(v)
+	}
 	if _, ok := _c.mutation.Status(); !ok {
 		v := promocode.DefaultStatus
 		_c.mutation.SetStatus(v)
@@ -230,12 +384,43 @@ func (_c *PromoCodeCreate) check() error {
 	if _, ok := _c.mutation.BonusAmount(); !ok {
 		return &ValidationError{Name: "bonus_amount", err: errors.New(`ent: missing required field "PromoCode.bonus_amount"`)}
 	}
+	if _, ok := _c.mutation.BonusType(); !ok {
+		return &ValidationError{Name: "bonus_type", err: errors.New(`ent: missing required field "PromoCode.bonus_type"`)}
+	}
+	if v, ok := _c.mutation.BonusType(); ok {
+		if err := promocode.BonusTypeValidator(v); err != nil {
+			return &ValidationError{Name: "bonus_type", err: fmt.Errorf(`ent: validator failed for field "PromoCode.bonus_type": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.BonusPercent(); !ok {
+		return &ValidationError{Name: "bonus_percent", err: errors.New(`ent: missing required field "PromoCode.bonus_percent"`)}
+	}
+	if _, ok := _c.mutation.MaxBonusAmount(); !ok {
+		return &ValidationError{Name: "max_bonus_amount", err: errors.New(`ent: missing required field "PromoCode.max_bonus_amount"`)}
+	}
 	if _, ok := _c.mutation.MaxUses(); !ok {
 		return &ValidationError{Name: "max_uses", err: errors.New(`ent: missing required field "PromoCode.max_uses"`)}
 	}
 	if _, ok := _c.mutation.UsedCount(); !ok {
 		return &ValidationError{Name: "used_count", err: errors.New(`ent: missing required field "PromoCode.used_count"`)}
 	}
+	if _, ok := _c.mutation.PerUserLimit(); !ok {
+		return &ValidationError{Name: "per_user_limit", err: errors.New(`ent: missing required field "PromoCode.per_user_limit"`)}
+	}
+	if _, ok := _c.mutation.Scope(); !ok {
+		return &ValidationError{Name: "scope", err: errors.New(`ent: missing required field "PromoCode.scope"`)}
+	}
+	if v, ok := _c.mutation.Scope(); ok {
+		if err := promocode.ScopeValidator(v); err != nil {
+			return &ValidationError{Name: "scope", err: fmt.Errorf(`ent: validator failed for field "PromoCode.scope": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.NewUsersOnly(); !ok {
+		return &ValidationError{Name: "new_users_only", err: errors.New(`ent: missing required field "PromoCode.new_users_only"`)}
+	}
+	if _, ok := _c.mutation.NewUserDays(); !ok {
+		return &ValidationError{Name: "new_user_days", err: errors.New(`ent: missing required field "PromoCode.new_user_days"`)}
+	}
 	if _, ok := _c.mutation.Status(); !ok {
 		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "PromoCode.status"`)}
 	}
@@ -285,6 +470,18 @@ func (_c *PromoCodeCreate) createSpec() (*PromoCode, *sqlgraph.CreateSpec) {
 		_spec.SetField(promocode.FieldBonusAmount, field.TypeFloat64, value)
 		_node.BonusAmount = value
 	}
+	if value, ok := _c.mutation.BonusType(); ok {
+		_spec.SetField(promocode.FieldBonusType, field.TypeString, value)
+		_node.BonusType = value
+	}
+	if value, ok := _c.mutation.BonusPercent(); ok {
+		_spec.SetField(promocode.FieldBonusPercent, field.TypeFloat64, value)
+		_node.BonusPercent = value
+	}
+	if value, ok := _c.mutation.MaxBonusAmount(); ok {
+		_spec.SetField(promocode.FieldMaxBonusAmount, field.TypeFloat64, value)
+		_node.MaxBonusAmount = value
+	}
 	if value, ok := _c.mutation.MaxUses(); ok {
 		_spec.SetField(promocode.FieldMaxUses, field.TypeInt, value)
 		_node.MaxUses = value
@@ -293,6 +490,30 @@ func (_c *PromoCodeCreate) createSpec() (*PromoCode, *sqlgraph.CreateSpec) {
 		_spec.SetField(promocode.FieldUsedCount, field.TypeInt, value)
 		_node.UsedCount = value
 	}
+	if value, ok := _c.mutation.PerUserLimit(); ok {
+		_spec.SetField(promocode.FieldPerUserLimit, field.TypeInt, value)
+		_node.PerUserLimit = value
+	}
+	if value, ok := _c.mutation.Scope(); ok {
+		_spec.SetField(promocode.FieldScope, field.TypeString, value)
+		_node.Scope = value
+	}
+	if value, ok := _c.mutation.NewUsersOnly(); ok {
+		_spec.SetField(promocode.FieldNewUsersOnly, field.TypeBool, value)
+		_node.NewUsersOnly = value
+	}
+	if value, ok := _c.mutation.NewUserDays(); ok {
+		_spec.SetField(promocode.FieldNewUserDays, field.TypeInt, value)
+		_node.NewUserDays = value
+	}
+	if value, ok := _c.mutation.RequiredGroupID(); ok {
+		_spec.SetField(promocode.FieldRequiredGroupID, field.TypeInt64, value)
+		_node.RequiredGroupID = &value
+	}
+	if value, ok := _c.mutation.StartsAt(); ok {
+		_spec.SetField(promocode.FieldStartsAt, field.TypeTime, value)
+		_node.StartsAt = &value
+	}
 	if value, ok := _c.mutation.Status(); ok {
 		_spec.SetField(promocode.FieldStatus, field.TypeString, value)
 		_node.Status = value
@@ -411,6 +632,54 @@ func (u *PromoCodeUpsert) AddBonusAmount(v float64) *PromoCodeUpsert {
 	return u
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (u *PromoCodeUpsert) SetBonusType(v string) *PromoCodeUpsert {
+	u.Set(promocode.FieldBonusType, v)
+	return u
+}
+
+// UpdateBonusType sets the "bonus_type" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateBonusType() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldBonusType)
+	return u
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (u *PromoCodeUpsert) SetBonusPercent(v float64) *PromoCodeUpsert {
+	u.Set(promocode.FieldBonusPercent, v)
+	return u
+}
+
+// UpdateBonusPercent sets the "bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateBonusPercent() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldBonusPercent)
+	return u
+}
+
+// AddBonusPercent adds v to the "bonus_percent" field.
+func (u *PromoCodeUpsert) AddBonusPercent(v float64) *PromoCodeUpsert {
+	u.Add(promocode.FieldBonusPercent, v)
+	return u
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (u *PromoCodeUpsert) SetMaxBonusAmount(v float64) *PromoCodeUpsert {
+	u.Set(promocode.FieldMaxBonusAmount, v)
+	return u
+}
+
+// UpdateMaxBonusAmount sets the "max_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateMaxBonusAmount() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldMaxBonusAmount)
+	return u
+}
+
+// AddMaxBonusAmount adds v to the "max_bonus_amount" field.
+func (u *PromoCodeUpsert) AddMaxBonusAmount(v float64) *PromoCodeUpsert {
+	u.Add(promocode.FieldMaxBonusAmount, v)
+	return u
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (u *PromoCodeUpsert) SetMaxUses(v int) *PromoCodeUpsert {
 	u.Set(promocode.FieldMaxUses, v)
@@ -447,6 +716,108 @@ func (u *PromoCodeUpsert) AddUsedCount(v int) *PromoCodeUpsert {
 	return u
 }
 
+// SetPerUserLimit sets the "per_user_limit" field.
+func (u *PromoCodeUpsert) SetPerUserLimit(v int) *PromoCodeUpsert {
+	u.Set(promocode.FieldPerUserLimit, v)
+	return u
+}
+
+// UpdatePerUserLimit sets the "per_user_limit" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdatePerUserLimit() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldPerUserLimit)
+	return u
+}
+
+// AddPerUserLimit adds v to the "per_user_limit" field.
+func (u *PromoCodeUpsert) AddPerUserLimit(v int) *PromoCodeUpsert {
+	u.Add(promocode.FieldPerUserLimit, v)
+	return u
+}
+
+// SetScope sets the "scope" field.
+func (u *PromoCodeUpsert) SetScope(v string) *PromoCodeUpsert {
+	u.Set(promocode.FieldScope, v)
+	return u
+}
+
+// UpdateScope sets the "scope" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateScope() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldScope)
+	return u
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (u *PromoCodeUpsert) SetNewUsersOnly(v bool) *PromoCodeUpsert {
+	u.Set(promocode.FieldNewUsersOnly, v)
+	return u
+}
+
+// UpdateNewUsersOnly sets the "new_users_only" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateNewUsersOnly() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldNewUsersOnly)
+	return u
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (u *PromoCodeUpsert) SetNewUserDays(v int) *PromoCodeUpsert {
+	u.Set(promocode.FieldNewUserDays, v)
+	return u
+}
+
+// UpdateNewUserDays sets the "new_user_days" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateNewUserDays() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldNewUserDays)
+	return u
+}
+
+// AddNewUserDays adds v to the "new_user_days" field.
+func (u *PromoCodeUpsert) AddNewUserDays(v int) *PromoCodeUpsert {
+	u.Add(promocode.FieldNewUserDays, v)
+	return u
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (u *PromoCodeUpsert) SetRequiredGroupID(v int64) *PromoCodeUpsert {
+	u.Set(promocode.FieldRequiredGroupID, v)
+	return u
+}
+
+// UpdateRequiredGroupID sets the "required_group_id" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateRequiredGroupID() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldRequiredGroupID)
+	return u
+}
+
+// AddRequiredGroupID adds v to the "required_group_id" field.
+func (u *PromoCodeUpsert) AddRequiredGroupID(v int64) *PromoCodeUpsert {
+	u.Add(promocode.FieldRequiredGroupID, v)
+	return u
+}
+
+// ClearRequiredGroupID clears the value of the "required_group_id" field.
+func (u *PromoCodeUpsert) ClearRequiredGroupID() *PromoCodeUpsert {
+	u.SetNull(promocode.FieldRequiredGroupID)
+	return u
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (u *PromoCodeUpsert) SetStartsAt(v time.Time) *PromoCodeUpsert {
+	u.Set(promocode.FieldStartsAt, v)
+	return u
+}
+
+// UpdateStartsAt sets the "starts_at" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateStartsAt() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldStartsAt)
+	return u
+}
+
+// ClearStartsAt clears the value of the "starts_at" field.
+func (u *PromoCodeUpsert) ClearStartsAt() *PromoCodeUpsert {
+	u.SetNull(promocode.FieldStartsAt)
+	return u
+}
+
 // SetStatus sets the "status" field.
 func (u *PromoCodeUpsert) SetStatus(v string) *PromoCodeUpsert {
 	u.Set(promocode.FieldStatus, v)
@@ -587,6 +958,62 @@ func (u *PromoCodeUpsertOne) UpdateBonusAmount() *PromoCodeUpsertOne {
 	})
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (u *PromoCodeUpsertOne) SetBonusType(v string) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetBonusType(v)
+	})
+}
+
+// UpdateBonusType sets the "bonus_type" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateBonusType() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateBonusType()
+	})
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (u *PromoCodeUpsertOne) SetBonusPercent(v float64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetBonusPercent(v)
+	})
+}
+
+// AddBonusPercent adds v to the "bonus_percent" field.
+func (u *PromoCodeUpsertOne) AddBonusPercent(v float64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddBonusPercent(v)
+	})
+}
+
+// UpdateBonusPercent sets the "bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateBonusPercent() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateBonusPercent()
+	})
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (u *PromoCodeUpsertOne) SetMaxBonusAmount(v float64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetMaxBonusAmount(v)
+	})
+}
+
+// AddMaxBonusAmount adds v to the "max_bonus_amount" field.
+func (u *PromoCodeUpsertOne) AddMaxBonusAmount(v float64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddMaxBonusAmount(v)
+	})
+}
+
+// UpdateMaxBonusAmount sets the "max_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateMaxBonusAmount() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateMaxBonusAmount()
+	})
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (u *PromoCodeUpsertOne) SetMaxUses(v int) *PromoCodeUpsertOne {
 	return u.Update(func(s *PromoCodeUpsert) {
@@ -629,6 +1056,125 @@ func (u *PromoCodeUpsertOne) UpdateUsedCount() *PromoCodeUpsertOne {
 	})
 }
 
+// SetPerUserLimit sets the "per_user_limit" field.
+func (u *PromoCodeUpsertOne) SetPerUserLimit(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetPerUserLimit(v)
+	})
+}
+
+// AddPerUserLimit adds v to the "per_user_limit" field.
+func (u *PromoCodeUpsertOne) AddPerUserLimit(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddPerUserLimit(v)
+	})
+}
+
+// UpdatePerUserLimit sets the "per_user_limit" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdatePerUserLimit() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdatePerUserLimit()
+	})
+}
+
+// SetScope sets the "scope" field.
+func (u *PromoCodeUpsertOne) SetScope(v string) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetScope(v)
+	})
+}
+
+// UpdateScope sets the "scope" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateScope() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateScope()
+	})
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (u *PromoCodeUpsertOne) SetNewUsersOnly(v bool) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetNewUsersOnly(v)
+	})
+}
+
+// UpdateNewUsersOnly sets the "new_users_only" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateNewUsersOnly() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateNewUsersOnly()
+	})
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (u *PromoCodeUpsertOne) SetNewUserDays(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetNewUserDays(v)
+	})
+}
+
+// AddNewUserDays adds v to the "new_user_days" field.
+func (u *PromoCodeUpsertOne) AddNewUserDays(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddNewUserDays(v)
+	})
+}
+
+// UpdateNewUserDays sets the "new_user_days" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateNewUserDays() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateNewUserDays()
+	})
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (u *PromoCodeUpsertOne) SetRequiredGroupID(v int64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetRequiredGroupID(v)
+	})
+}
+
+// AddRequiredGroupID adds v to the "required_group_id" field.
+func (u *PromoCodeUpsertOne) AddRequiredGroupID(v int64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddRequiredGroupID(v)
+	})
+}
+
+// UpdateRequiredGroupID sets the "required_group_id" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateRequiredGroupID() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateRequiredGroupID()
+	})
+}
+
+// ClearRequiredGroupID clears the value of the "required_group_id" field.
+func (u *PromoCodeUpsertOne) ClearRequiredGroupID() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearRequiredGroupID()
+	})
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (u *PromoCodeUpsertOne) SetStartsAt(v time.Time) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetStartsAt(v)
+	})
+}
+
+// UpdateStartsAt sets the "starts_at" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateStartsAt() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateStartsAt()
+	})
+}
+
+// ClearStartsAt clears the value of the "starts_at" field.
+func (u *PromoCodeUpsertOne) ClearStartsAt() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearStartsAt()
+	})
+}
+
 // SetStatus sets the "status" field.
 func (u *PromoCodeUpsertOne) SetStatus(v string) *PromoCodeUpsertOne {
 	return u.Update(func(s *PromoCodeUpsert) {
@@ -945,6 +1491,62 @@ func (u *PromoCodeUpsertBulk) UpdateBonusAmount() *PromoCodeUpsertBulk {
 	})
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (u *PromoCodeUpsertBulk) SetBonusType(v string) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetBonusType(v)
+	})
+}
+
+// UpdateBonusType sets the "bonus_type" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateBonusType() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateBonusType()
+	})
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (u *PromoCodeUpsertBulk) SetBonusPercent(v float64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetBonusPercent(v)
+	})
+}
+
+// AddBonusPercent adds v to the "bonus_percent" field.
+func (u *PromoCodeUpsertBulk) AddBonusPercent(v float64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddBonusPercent(v)
+	})
+}
+
+// UpdateBonusPercent sets the "bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateBonusPercent() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateBonusPercent()
+	})
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (u *PromoCodeUpsertBulk) SetMaxBonusAmount(v float64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetMaxBonusAmount(v)
+	})
+}
+
+// AddMaxBonusAmount adds v to the "max_bonus_amount" field.
+func (u *PromoCodeUpsertBulk) AddMaxBonusAmount(v float64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddMaxBonusAmount(v)
+	})
+}
+
+// UpdateMaxBonusAmount sets the "max_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateMaxBonusAmount() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateMaxBonusAmount()
+	})
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (u *PromoCodeUpsertBulk) SetMaxUses(v int) *PromoCodeUpsertBulk {
 	return u.Update(func(s *PromoCodeUpsert) {
@@ -987,6 +1589,125 @@ func (u *PromoCodeUpsertBulk) UpdateUsedCount() *PromoCodeUpsertBulk {
 	})
 }
 
+// SetPerUserLimit sets the "per_user_limit" field.
+func (u *PromoCodeUpsertBulk) SetPerUserLimit(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetPerUserLimit(v)
+	})
+}
+
+// AddPerUserLimit adds v to the "per_user_limit" field.
+func (u *PromoCodeUpsertBulk) AddPerUserLimit(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddPerUserLimit(v)
+	})
+}
+
+// UpdatePerUserLimit sets the "per_user_limit" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdatePerUserLimit() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdatePerUserLimit()
+	})
+}
+
+// SetScope sets the "scope" field.
+func (u *PromoCodeUpsertBulk) SetScope(v string) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetScope(v)
+	})
+}
+
+// UpdateScope sets the "scope" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateScope() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateScope()
+	})
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (u *PromoCodeUpsertBulk) SetNewUsersOnly(v bool) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetNewUsersOnly(v)
+	})
+}
+
+// UpdateNewUsersOnly sets the "new_users_only" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateNewUsersOnly() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateNewUsersOnly()
+	})
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (u *PromoCodeUpsertBulk) SetNewUserDays(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetNewUserDays(v)
+	})
+}
+
+// AddNewUserDays adds v to the "new_user_days" field.
+func (u *PromoCodeUpsertBulk) AddNewUserDays(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddNewUserDays(v)
+	})
+}
+
+// UpdateNewUserDays sets the "new_user_days" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateNewUserDays() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateNewUserDays()
+	})
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (u *PromoCodeUpsertBulk) SetRequiredGroupID(v int64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetRequiredGroupID(v)
+	})
+}
+
+// AddRequiredGroupID adds v to the "required_group_id" field.
+func (u *PromoCodeUpsertBulk) AddRequiredGroupID(v int64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddRequiredGroupID(v)
+	})
+}
+
+// UpdateRequiredGroupID sets the "required_group_id" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateRequiredGroupID() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateRequiredGroupID()
+	})
+}
+
+// ClearRequiredGroupID clears the value of the "required_group_id" field.
+func (u *PromoCodeUpsertBulk) ClearRequiredGroupID() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearRequiredGroupID()
+	})
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (u *PromoCodeUpsertBulk) SetStartsAt(v time.Time) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetStartsAt(v)
+	})
+}
+
+// UpdateStartsAt sets the "starts_at" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateStartsAt() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateStartsAt()
+	})
+}
+
+// ClearStartsAt clears the value of the "starts_at" field.
+func (u *PromoCodeUpsertBulk) ClearStartsAt() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearStartsAt()
+	})
+}
+
 // SetStatus sets the "status" field.
 func (u *PromoCodeUpsertBulk) SetStatus(v string) *PromoCodeUpsertBulk {
 	return u.Update(func(s *PromoCodeUpsert) {
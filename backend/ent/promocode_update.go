@@ -64,6 +64,62 @@ func (_u *PromoCodeUpdate) AddBonusAmount(v float64) *PromoCodeUpdate {
 	return _u
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (_u *PromoCodeUpdate) SetBonusType(v string) *PromoCodeUpdate {
+	_u.mutation.SetBonusType(v)
+	return _u
+}
+
+// SetNillableBonusType sets the "bonus_type" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableBonusType(v *string) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetBonusType(*v)
+	}
+	return _u
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (_u *PromoCodeUpdate) SetBonusPercent(v float64) *PromoCodeUpdate {
+	_u.mutation.ResetBonusPercent()
+	_u.mutation.SetBonusPercent(v)
+	return _u
+}
+
+// SetNillableBonusPercent sets the "bonus_percent" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableBonusPercent(v *float64) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetBonusPercent(*v)
+	}
+	return _u
+}
+
+// AddBonusPercent adds value to the "bonus_percent" field.
+func (_u *PromoCodeUpdate) AddBonusPercent(v float64) *PromoCodeUpdate {
+	_u.mutation.AddBonusPercent(v)
+	return _u
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (_u *PromoCodeUpdate) SetMaxBonusAmount(v float64) *PromoCodeUpdate {
+	_u.mutation.ResetMaxBonusAmount()
+	_u.mutation.SetMaxBonusAmount(v)
+	return _u
+}
+
+// SetNillableMaxBonusAmount sets the "max_bonus_amount" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableMaxBonusAmount(v *float64) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetMaxBonusAmount(*v)
+	}
+	return _u
+}
+
+// AddMaxBonusAmount adds value to the "max_bonus_amount" field.
+func (_u *PromoCodeUpdate) AddMaxBonusAmount(v float64) *PromoCodeUpdate {
+	_u.mutation.AddMaxBonusAmount(v)
+	return _u
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (_u *PromoCodeUpdate) SetMaxUses(v int) *PromoCodeUpdate {
 	_u.mutation.ResetMaxUses()
@@ -106,6 +162,123 @@ func (_u *PromoCodeUpdate) AddUsedCount(v int) *PromoCodeUpdate {
 	return _u
 }
 
+// SetPerUserLimit sets the "per_user_limit" field.
+func (_u *PromoCodeUpdate) SetPerUserLimit(v int) *PromoCodeUpdate {
+	_u.mutation.ResetPerUserLimit()
+	_u.mutation.SetPerUserLimit(v)
+	return _u
+}
+
+// SetNillablePerUserLimit sets the "per_user_limit" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillablePerUserLimit(v *int) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetPerUserLimit(*v)
+	}
+	return _u
+}
+
+// AddPerUserLimit adds value to the "per_user_limit" field.
+func (_u *PromoCodeUpdate) AddPerUserLimit(v int) *PromoCodeUpdate {
+	_u.mutation.AddPerUserLimit(v)
+	return _u
+}
+
+// SetScope sets the "scope" field.
+func (_u *PromoCodeUpdate) SetScope(v string) *PromoCodeUpdate {
+	_u.mutation.SetScope(v)
+	return _u
+}
+
+// SetNillableScope sets the "scope" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableScope(v *string) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetScope(*v)
+	}
+	return _u
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (_u *PromoCodeUpdate) SetNewUsersOnly(v bool) *PromoCodeUpdate {
+	_u.mutation.SetNewUsersOnly(v)
+	return _u
+}
+
+// SetNillableNewUsersOnly sets the "new_users_only" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableNewUsersOnly(v *bool) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetNewUsersOnly(*v)
+	}
+	return _u
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (_u *PromoCodeUpdate) SetNewUserDays(v int) *PromoCodeUpdate {
+	_u.mutation.ResetNewUserDays()
+	_u.mutation.SetNewUserDays(v)
+	return _u
+}
+
+// SetNillableNewUserDays sets the "new_user_days" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableNewUserDays(v *int) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetNewUserDays(*v)
+	}
+	return _u
+}
+
+// AddNewUserDays adds value to the "new_user_days" field.
+func (_u *PromoCodeUpdate) AddNewUserDays(v int) *PromoCodeUpdate {
+	_u.mutation.AddNewUserDays(v)
+	return _u
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (_u *PromoCodeUpdate) SetRequiredGroupID(v int64) *PromoCodeUpdate {
+	_u.mutation.ResetRequiredGroupID()
+	_u.mutation.SetRequiredGroupID(v)
+	return _u
+}
+
+// SetNillableRequiredGroupID sets the "required_group_id" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableRequiredGroupID(v *int64) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetRequiredGroupID(*v)
+	}
+	return _u
+}
+
+// AddRequiredGroupID adds value to the "required_group_id" field.
+func (_u *PromoCodeUpdate) AddRequiredGroupID(v int64) *PromoCodeUpdate {
+	_u.mutation.AddRequiredGroupID(v)
+	return _u
+}
+
+// ClearRequiredGroupID clears the value of the "required_group_id" field.
+func (_u *PromoCodeUpdate) ClearRequiredGroupID() *PromoCodeUpdate {
+	_u.mutation.ClearRequiredGroupID()
+	return _u
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (_u *PromoCodeUpdate) SetStartsAt(v time.Time) *PromoCodeUpdate {
+	_u.mutation.SetStartsAt(v)
+	return _u
+}
+
+// SetNillableStartsAt sets the "starts_at" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableStartsAt(v *time.Time) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetStartsAt(*v)
+	}
+	return _u
+}
+
+// ClearStartsAt clears the value of the "starts_at" field.
+func (_u *PromoCodeUpdate) ClearStartsAt() *PromoCodeUpdate {
+	_u.mutation.ClearStartsAt()
+	return _u
+}
+
 // SetStatus sets the "status" field.
 func (_u *PromoCodeUpdate) SetStatus(v string) *PromoCodeUpdate {
 	_u.mutation.SetStatus(v)
@@ -250,6 +423,16 @@ func (_u *PromoCodeUpdate) check() error {
 			return &ValidationError{Name: "code", err: fmt.Errorf(`ent: validator failed for field "PromoCode.code": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.BonusType(); ok {
+		if err := promocode.BonusTypeValidator(v); err != nil {
+			return &ValidationError{Name: "bonus_type", err: fmt.Errorf(`ent: validator failed for field "PromoCode.bonus_type": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Scope(); ok {
+		if err := promocode.ScopeValidator(v); err != nil {
+			return &ValidationError{Name: "scope", err: fmt.Errorf(`ent: validator failed for field "PromoCode.scope": %w`, err)}
+		}
+	}
 	if v, ok := _u.mutation.Status(); ok {
 		if err := promocode.StatusValidator(v); err != nil {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "PromoCode.status": %w`, err)}
@@ -279,6 +462,21 @@ func (_u *PromoCodeUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
 		_spec.AddField(promocode.FieldBonusAmount, field.TypeFloat64, value)
 	}
+	if value, ok := _u.mutation.BonusType(); ok {
+		_spec.SetField(promocode.FieldBonusType, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.BonusPercent(); ok {
+		_spec.SetField(promocode.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedBonusPercent(); ok {
+		_spec.AddField(promocode.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.MaxBonusAmount(); ok {
+		_spec.SetField(promocode.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedMaxBonusAmount(); ok {
+		_spec.AddField(promocode.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
 	if value, ok := _u.mutation.MaxUses(); ok {
 		_spec.SetField(promocode.FieldMaxUses, field.TypeInt, value)
 	}
@@ -291,6 +489,39 @@ func (_u *PromoCodeUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.AddedUsedCount(); ok {
 		_spec.AddField(promocode.FieldUsedCount, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.PerUserLimit(); ok {
+		_spec.SetField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPerUserLimit(); ok {
+		_spec.AddField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Scope(); ok {
+		_spec.SetField(promocode.FieldScope, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.NewUsersOnly(); ok {
+		_spec.SetField(promocode.FieldNewUsersOnly, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.NewUserDays(); ok {
+		_spec.SetField(promocode.FieldNewUserDays, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedNewUserDays(); ok {
+		_spec.AddField(promocode.FieldNewUserDays, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.RequiredGroupID(); ok {
+		_spec.SetField(promocode.FieldRequiredGroupID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedRequiredGroupID(); ok {
+		_spec.AddField(promocode.FieldRequiredGroupID, field.TypeInt64, value)
+	}
+	if _u.mutation.RequiredGroupIDCleared() {
+		_spec.ClearField(promocode.FieldRequiredGroupID, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.StartsAt(); ok {
+		_spec.SetField(promocode.FieldStartsAt, field.TypeTime, value)
+	}
+	if _u.mutation.StartsAtCleared() {
+		_spec.ClearField(promocode.FieldStartsAt, field.TypeTime)
+	}
 	if value, ok := _u.mutation.Status(); ok {
 		_spec.SetField(promocode.FieldStatus, field.TypeString, value)
 	}
@@ -409,6 +640,62 @@ func (_u *PromoCodeUpdateOne) AddBonusAmount(v float64) *PromoCodeUpdateOne {
 	return _u
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (_u *PromoCodeUpdateOne) SetBonusType(v string) *PromoCodeUpdateOne {
+	_u.mutation.SetBonusType(v)
+	return _u
+}
+
+// SetNillableBonusType sets the "bonus_type" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableBonusType(v *string) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetBonusType(*v)
+	}
+	return _u
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (_u *PromoCodeUpdateOne) SetBonusPercent(v float64) *PromoCodeUpdateOne {
+	_u.mutation.ResetBonusPercent()
+	_u.mutation.SetBonusPercent(v)
+	return _u
+}
+
+// SetNillableBonusPercent sets the "bonus_percent" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableBonusPercent(v *float64) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetBonusPercent(*v)
+	}
+	return _u
+}
+
+// AddBonusPercent adds value to the "bonus_percent" field.
+func (_u *PromoCodeUpdateOne) AddBonusPercent(v float64) *PromoCodeUpdateOne {
+	_u.mutation.AddBonusPercent(v)
+	return _u
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (_u *PromoCodeUpdateOne) SetMaxBonusAmount(v float64) *PromoCodeUpdateOne {
+	_u.mutation.ResetMaxBonusAmount()
+	_u.mutation.SetMaxBonusAmount(v)
+	return _u
+}
+
+// SetNillableMaxBonusAmount sets the "max_bonus_amount" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableMaxBonusAmount(v *float64) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetMaxBonusAmount(*v)
+	}
+	return _u
+}
+
+// AddMaxBonusAmount adds value to the "max_bonus_amount" field.
+func (_u *PromoCodeUpdateOne) AddMaxBonusAmount(v float64) *PromoCodeUpdateOne {
+	_u.mutation.AddMaxBonusAmount(v)
+	return _u
+}
+
 // SetMaxUses sets the "max_uses" field.
 func (_u *PromoCodeUpdateOne) SetMaxUses(v int) *PromoCodeUpdateOne {
 	_u.mutation.ResetMaxUses()
@@ -451,6 +738,123 @@ func (_u *PromoCodeUpdateOne) AddUsedCount(v int) *PromoCodeUpdateOne {
 	return _u
 }
 
+// SetPerUserLimit sets the "per_user_limit" field.
+func (_u *PromoCodeUpdateOne) SetPerUserLimit(v int) *PromoCodeUpdateOne {
+	_u.mutation.ResetPerUserLimit()
+	_u.mutation.SetPerUserLimit(v)
+	return _u
+}
+
+// SetNillablePerUserLimit sets the "per_user_limit" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillablePerUserLimit(v *int) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetPerUserLimit(*v)
+	}
+	return _u
+}
+
+// AddPerUserLimit adds value to the "per_user_limit" field.
+func (_u *PromoCodeUpdateOne) AddPerUserLimit(v int) *PromoCodeUpdateOne {
+	_u.mutation.AddPerUserLimit(v)
+	return _u
+}
+
+// SetScope sets the "scope" field.
+func (_u *PromoCodeUpdateOne) SetScope(v string) *PromoCodeUpdateOne {
+	_u.mutation.SetScope(v)
+	return _u
+}
+
+// SetNillableScope sets the "scope" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableScope(v *string) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetScope(*v)
+	}
+	return _u
+}
+
+// SetNewUsersOnly sets the "new_users_only" field.
+func (_u *PromoCodeUpdateOne) SetNewUsersOnly(v bool) *PromoCodeUpdateOne {
+	_u.mutation.SetNewUsersOnly(v)
+	return _u
+}
+
+// SetNillableNewUsersOnly sets the "new_users_only" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableNewUsersOnly(v *bool) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetNewUsersOnly(*v)
+	}
+	return _u
+}
+
+// SetNewUserDays sets the "new_user_days" field.
+func (_u *PromoCodeUpdateOne) SetNewUserDays(v int) *PromoCodeUpdateOne {
+	_u.mutation.ResetNewUserDays()
+	_u.mutation.SetNewUserDays(v)
+	return _u
+}
+
+// SetNillableNewUserDays sets the "new_user_days" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableNewUserDays(v *int) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetNewUserDays(*v)
+	}
+	return _u
+}
+
+// AddNewUserDays adds value to the "new_user_days" field.
+func (_u *PromoCodeUpdateOne) AddNewUserDays(v int) *PromoCodeUpdateOne {
+	_u.mutation.AddNewUserDays(v)
+	return _u
+}
+
+// SetRequiredGroupID sets the "required_group_id" field.
+func (_u *PromoCodeUpdateOne) SetRequiredGroupID(v int64) *PromoCodeUpdateOne {
+	_u.mutation.ResetRequiredGroupID()
+	_u.mutation.SetRequiredGroupID(v)
+	return _u
+}
+
+// SetNillableRequiredGroupID sets the "required_group_id" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableRequiredGroupID(v *int64) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetRequiredGroupID(*v)
+	}
+	return _u
+}
+
+// AddRequiredGroupID adds value to the "required_group_id" field.
+func (_u *PromoCodeUpdateOne) AddRequiredGroupID(v int64) *PromoCodeUpdateOne {
+	_u.mutation.AddRequiredGroupID(v)
+	return _u
+}
+
+// ClearRequiredGroupID clears the value of the "required_group_id" field.
+func (_u *PromoCodeUpdateOne) ClearRequiredGroupID() *PromoCodeUpdateOne {
+	_u.mutation.ClearRequiredGroupID()
+	return _u
+}
+
+// SetStartsAt sets the "starts_at" field.
+func (_u *PromoCodeUpdateOne) SetStartsAt(v time.Time) *PromoCodeUpdateOne {
+	_u.mutation.SetStartsAt(v)
+	return _u
+}
+
+// SetNillableStartsAt sets the "starts_at" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableStartsAt(v *time.Time) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetStartsAt(*v)
+	}
+	return _u
+}
+
+// ClearStartsAt clears the value of the "starts_at" field.
+func (_u *PromoCodeUpdateOne) ClearStartsAt() *PromoCodeUpdateOne {
+	_u.mutation.ClearStartsAt()
+	return _u
+}
+
 // SetStatus sets the "status" field.
 func (_u *PromoCodeUpdateOne) SetStatus(v string) *PromoCodeUpdateOne {
 	_u.mutation.SetStatus(v)
@@ -608,6 +1012,16 @@ func (_u *PromoCodeUpdateOne) check() error {
 			return &ValidationError{Name: "code", err: fmt.Errorf(`ent: validator failed for field "PromoCode.code": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.BonusType(); ok {
+		if err := promocode.BonusTypeValidator(v); err != nil {
+			return &ValidationError{Name: "bonus_type", err: fmt.Errorf(`ent: validator failed for field "PromoCode.bonus_type": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Scope(); ok {
+		if err := promocode.ScopeValidator(v); err != nil {
+			return &ValidationError{Name: "scope", err: fmt.Errorf(`ent: validator failed for field "PromoCode.scope": %w`, err)}
+		}
+	}
 	if v, ok := _u.mutation.Status(); ok {
 		if err := promocode.StatusValidator(v); err != nil {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "PromoCode.status": %w`, err)}
@@ -654,6 +1068,21 @@ func (_u *PromoCodeUpdateOne) sqlSave(ctx context.Context) (_node *PromoCode, er
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
 		_spec.AddField(promocode.FieldBonusAmount, field.TypeFloat64, value)
 	}
+	if value, ok := _u.mutation.BonusType(); ok {
+		_spec.SetField(promocode.FieldBonusType, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.BonusPercent(); ok {
+		_spec.SetField(promocode.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedBonusPercent(); ok {
+		_spec.AddField(promocode.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.MaxBonusAmount(); ok {
+		_spec.SetField(promocode.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedMaxBonusAmount(); ok {
+		_spec.AddField(promocode.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
 	if value, ok := _u.mutation.MaxUses(); ok {
 		_spec.SetField(promocode.FieldMaxUses, field.TypeInt, value)
 	}
@@ -666,6 +1095,39 @@ func (_u *PromoCodeUpdateOne) sqlSave(ctx context.Context) (_node *PromoCode, er
 	if value, ok := _u.mutation.AddedUsedCount(); ok {
 		_spec.AddField(promocode.FieldUsedCount, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.PerUserLimit(); ok {
+		_spec.SetField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPerUserLimit(); ok {
+		_spec.AddField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Scope(); ok {
+		_spec.SetField(promocode.FieldScope, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.NewUsersOnly(); ok {
+		_spec.SetField(promocode.FieldNewUsersOnly, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.NewUserDays(); ok {
+		_spec.SetField(promocode.FieldNewUserDays, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedNewUserDays(); ok {
+		_spec.AddField(promocode.FieldNewUserDays, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.RequiredGroupID(); ok {
+		_spec.SetField(promocode.FieldRequiredGroupID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedRequiredGroupID(); ok {
+		_spec.AddField(promocode.FieldRequiredGroupID, field.TypeInt64, value)
+	}
+	if _u.mutation.RequiredGroupIDCleared() {
+		_spec.ClearField(promocode.FieldRequiredGroupID, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.StartsAt(); ok {
+		_spec.SetField(promocode.FieldStartsAt, field.TypeTime, value)
+	}
+	if _u.mutation.StartsAtCleared() {
+		_spec.ClearField(promocode.FieldStartsAt, field.TypeTime)
+	}
 	if value, ok := _u.mutation.Status(); ok {
 		_spec.SetField(promocode.FieldStatus, field.TypeString, value)
 	}
@@ -23,8 +23,24 @@ type PromoCodeUsage struct {
 	PromoCodeID int64 `json:"promo_code_id,omitempty"`
 	// 使用用户ID
 	UserID int64 `json:"user_id,omitempty"`
-	// 实际赠送金额
+	// 实际赠送金额（比例赠送在充值前为 0）
 	BonusAmount float64 `json:"bonus_amount,omitempty"`
+	// 赠送方式: fixed, percent
+	BonusType string `json:"bonus_type,omitempty"`
+	// 比例赠送的百分比
+	BonusPercent float64 `json:"bonus_percent,omitempty"`
+	// 比例赠送的上限，0表示不限
+	MaxBonusAmount float64 `json:"max_bonus_amount,omitempty"`
+	// 状态: applied, pending（等待下次充值）
+	Status string `json:"status,omitempty"`
+	// 使用场景: register, panel
+	Source string `json:"source,omitempty"`
+	// 触发比例赠送的充值金额
+	BaseAmount float64 `json:"base_amount,omitempty"`
+	// 触发比例赠送的充值兑换码ID
+	RedeemCodeID *int64 `json:"redeem_code_id,omitempty"`
+	// 赠送发放时间
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
 	// 使用时间
 	UsedAt time.Time `json:"used_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -71,11 +87,13 @@ func (*PromoCodeUsage) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case promocodeusage.FieldBonusAmount:
+		case promocodeusage.FieldBonusAmount, promocodeusage.FieldBonusPercent, promocodeusage.FieldMaxBonusAmount, promocodeusage.FieldBaseAmount:
 			values[i] = new(sql.NullFloat64)
-		case promocodeusage.FieldID, promocodeusage.FieldPromoCodeID, promocodeusage.FieldUserID:
+		case promocodeusage.FieldID, promocodeusage.FieldPromoCodeID, promocodeusage.FieldUserID, promocodeusage.FieldRedeemCodeID:
 			values[i] = new(sql.NullInt64)
-		case promocodeusage.FieldUsedAt:
+		case promocodeusage.FieldBonusType, promocodeusage.FieldStatus, promocodeusage.FieldSource:
+			values[i] = new(sql.NullString)
+		case promocodeusage.FieldAppliedAt, promocodeusage.FieldUsedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -116,6 +134,56 @@ func (_m *PromoCodeUsage) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.BonusAmount = value.Float64
 			}
+		case promocodeusage.FieldBonusType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field bonus_type", values[i])
+			} else if value.Valid {
+				_m.BonusType = value.String
+			}
+		case promocodeusage.FieldBonusPercent:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field bonus_percent", values[i])
+			} else if value.Valid {
+				_m.BonusPercent = value.Float64
+			}
+		case promocodeusage.FieldMaxBonusAmount:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field max_bonus_amount", values[i])
+			} else if value.Valid {
+				_m.MaxBonusAmount = value.Float64
+			}
+		case promocodeusage.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				_m.Status = value.String
+			}
+		case promocodeusage.FieldSource:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field source", values[i])
+			} else if value.Valid {
+				_m.Source = value.String
+			}
+		case promocodeusage.FieldBaseAmount:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field base_amount", values[i])
+			} else if value.Valid {
+				_m.BaseAmount = value.Float64
+			}
+		case promocodeusage.FieldRedeemCodeID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field redeem_code_id", values[i])
+			} else if value.Valid {
+				_m.RedeemCodeID = new(int64)
+				*_m.RedeemCodeID = value.Int64
+			}
+		case promocodeusage.FieldAppliedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field applied_at", values[i])
+			} else if value.Valid {
+				_m.AppliedAt = new(time.Time)
+				*_m.AppliedAt = value.Time
+			}
 		case promocodeusage.FieldUsedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field used_at", values[i])
@@ -177,6 +245,34 @@ func (_m *PromoCodeUsage) String() string {
 	builder.WriteString("bonus_amount=")
 	builder.WriteString(fmt.Sprintf("%v", _m.BonusAmount))
 	builder.WriteString(", ")
+	builder.WriteString("bonus_type=")
+	builder.WriteString(_m.BonusType)
+	builder.WriteString(", ")
+	builder.WriteString("bonus_percent=")
+	builder.WriteString(fmt.Sprintf("%v", _m.BonusPercent))
+	builder.WriteString(", ")
+	builder.WriteString("max_bonus_amount=")
+	builder.WriteString(fmt.Sprintf("%v", _m.MaxBonusAmount))
+	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(_m.Status)
+	builder.WriteString(", ")
+	builder.WriteString("source=")
+	builder.WriteString(_m.Source)
+	builder.WriteString(", ")
+	builder.WriteString("base_amount=")
+	builder.WriteString(fmt.Sprintf("%v", _m.BaseAmount))
+	builder.WriteString(", ")
+	if v := _m.RedeemCodeID; v != nil {
+		builder.WriteString("redeem_code_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.AppliedAt; v != nil {
+		builder.WriteString("applied_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("used_at=")
 	builder.WriteString(_m.UsedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
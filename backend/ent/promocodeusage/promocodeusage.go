@@ -20,6 +20,22 @@ const (
 	FieldUserID = "user_id"
 	// FieldBonusAmount holds the string denoting the bonus_amount field in the database.
 	FieldBonusAmount = "bonus_amount"
+	// FieldBonusType holds the string denoting the bonus_type field in the database.
+	FieldBonusType = "bonus_type"
+	// FieldBonusPercent holds the string denoting the bonus_percent field in the database.
+	FieldBonusPercent = "bonus_percent"
+	// FieldMaxBonusAmount holds the string denoting the max_bonus_amount field in the database.
+	FieldMaxBonusAmount = "max_bonus_amount"
+	// FieldStatus holds the string denoting the status field in the database.
+	FieldStatus = "status"
+	// FieldSource holds the string denoting the source field in the database.
+	FieldSource = "source"
+	// FieldBaseAmount holds the string denoting the base_amount field in the database.
+	FieldBaseAmount = "base_amount"
+	// FieldRedeemCodeID holds the string denoting the redeem_code_id field in the database.
+	FieldRedeemCodeID = "redeem_code_id"
+	// FieldAppliedAt holds the string denoting the applied_at field in the database.
+	FieldAppliedAt = "applied_at"
 	// FieldUsedAt holds the string denoting the used_at field in the database.
 	FieldUsedAt = "used_at"
 	// EdgePromoCode holds the string denoting the promo_code edge name in mutations.
@@ -50,6 +66,14 @@ var Columns = []string{
 	FieldPromoCodeID,
 	FieldUserID,
 	FieldBonusAmount,
+	FieldBonusType,
+	FieldBonusPercent,
+	FieldMaxBonusAmount,
+	FieldStatus,
+	FieldSource,
+	FieldBaseAmount,
+	FieldRedeemCodeID,
+	FieldAppliedAt,
 	FieldUsedAt,
 }
 
@@ -64,6 +88,24 @@ func ValidColumn(column string) bool {
 }
 
 var (
+	// DefaultBonusType holds the default value on creation for the "bonus_type" field.
+	DefaultBonusType string
+	// BonusTypeValidator is a validator for the "bonus_type" field. It is called by the builders before save.
+	BonusTypeValidator func(string) error
+	// DefaultBonusPercent holds the default value on creation for the "bonus_percent" field.
+	DefaultBonusPercent float64
+	// DefaultMaxBonusAmount holds the default value on creation for the "max_bonus_amount" field.
+	DefaultMaxBonusAmount float64
+	// DefaultStatus holds the default value on creation for the "status" field.
+	DefaultStatus string
+	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
+	StatusValidator func(string) error
+	// DefaultSource holds the default value on creation for the "source" field.
+	DefaultSource string
+	// SourceValidator is a validator for the "source" field. It is called by the builders before save.
+	SourceValidator func(string) error
+	// DefaultBaseAmount holds the default value on creation for the "base_amount" field.
+	DefaultBaseAmount float64
 	// DefaultUsedAt holds the default value on creation for the "used_at" field.
 	DefaultUsedAt func() time.Time
 )
@@ -91,6 +133,46 @@ func ByBonusAmount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldBonusAmount, opts...).ToFunc()
 }
 
+// ByBonusType orders the results by the bonus_type field.
+func ByBonusType(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBonusType, opts...).ToFunc()
+}
+
+// ByBonusPercent orders the results by the bonus_percent field.
+func ByBonusPercent(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBonusPercent, opts...).ToFunc()
+}
+
+// ByMaxBonusAmount orders the results by the max_bonus_amount field.
+func ByMaxBonusAmount(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMaxBonusAmount, opts...).ToFunc()
+}
+
+// ByStatus orders the results by the status field.
+func ByStatus(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStatus, opts...).ToFunc()
+}
+
+// BySource orders the results by the source field.
+func BySource(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSource, opts...).ToFunc()
+}
+
+// ByBaseAmount orders the results by the base_amount field.
+func ByBaseAmount(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBaseAmount, opts...).ToFunc()
+}
+
+// ByRedeemCodeID orders the results by the redeem_code_id field.
+func ByRedeemCodeID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRedeemCodeID, opts...).ToFunc()
+}
+
+// ByAppliedAt orders the results by the applied_at field.
+func ByAppliedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAppliedAt, opts...).ToFunc()
+}
+
 // ByUsedAt orders the results by the used_at field.
 func ByUsedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUsedAt, opts...).ToFunc()
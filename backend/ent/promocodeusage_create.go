@@ -42,6 +42,118 @@ func (_c *PromoCodeUsageCreate) SetBonusAmount(v float64) *PromoCodeUsageCreate
 	return _c
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (_c *PromoCodeUsageCreate) SetBonusType(v string) *PromoCodeUsageCreate {
+	_c.mutation.SetBonusType(v)
+	return _c
+}
+
+// SetNillableBonusType sets the "bonus_type" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableBonusType(v *string) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetBonusType(*v)
+	}
+	return _c
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (_c *PromoCodeUsageCreate) SetBonusPercent(v float64) *PromoCodeUsageCreate {
+	_c.mutation.SetBonusPercent(v)
+	return _c
+}
+
+// SetNillableBonusPercent sets the "bonus_percent" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableBonusPercent(v *float64) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetBonusPercent(*v)
+	}
+	return _c
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (_c *PromoCodeUsageCreate) SetMaxBonusAmount(v float64) *PromoCodeUsageCreate {
+	_c.mutation.SetMaxBonusAmount(v)
+	return _c
+}
+
+// SetNillableMaxBonusAmount sets the "max_bonus_amount" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableMaxBonusAmount(v *float64) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetMaxBonusAmount(*v)
+	}
+	return _c
+}
+
+// SetStatus sets the "status" field.
+func (_c *PromoCodeUsageCreate) SetStatus(v string) *PromoCodeUsageCreate {
+	_c.mutation.SetStatus(v)
+	return _c
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableStatus(v *string) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetStatus(*v)
+	}
+	return _c
+}
+
+// SetSource sets the "source" field.
+func (_c *PromoCodeUsageCreate) SetSource(v string) *PromoCodeUsageCreate {
+	_c.mutation.SetSource(v)
+	return _c
+}
+
+// SetNillableSource sets the "source" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableSource(v *string) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetSource(*v)
+	}
+	return _c
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (_c *PromoCodeUsageCreate) SetBaseAmount(v float64) *PromoCodeUsageCreate {
+	_c.mutation.SetBaseAmount(v)
+	return _c
+}
+
+// SetNillableBaseAmount sets the "base_amount" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableBaseAmount(v *float64) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetBaseAmount(*v)
+	}
+	return _c
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (_c *PromoCodeUsageCreate) SetRedeemCodeID(v int64) *PromoCodeUsageCreate {
+	_c.mutation.SetRedeemCodeID(v)
+	return _c
+}
+
+// SetNillableRedeemCodeID sets the "redeem_code_id" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableRedeemCodeID(v *int64) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetRedeemCodeID(*v)
+	}
+	return _c
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (_c *PromoCodeUsageCreate) SetAppliedAt(v time.Time) *PromoCodeUsageCreate {
+	_c.mutation.SetAppliedAt(v)
+	return _c
+}
+
+// SetNillableAppliedAt sets the "applied_at" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableAppliedAt(v *time.Time) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetAppliedAt(*v)
+	}
+	return _c
+}
+
 // SetUsedAt sets the "used_at" field.
 func (_c *PromoCodeUsageCreate) SetUsedAt(v time.Time) *PromoCodeUsageCreate {
 	_c.mutation.SetUsedAt(v)
@@ -101,6 +213,30 @@ func (_c *PromoCodeUsageCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (_c *PromoCodeUsageCreate) defaults() {
+	if _, ok := _c.mutation.BonusType(); !ok {
+		v := promocodeusage.DefaultBonusType
+		_c.mutation.SetBonusType(v)
+	}
+	if _, ok := _c.mutation.BonusPercent(); !ok {
+		v := promocodeusage.DefaultBonusPercent
+		_c.mutation.SetBonusPercent(v)
+	}
+	if _, ok := _c.mutation.MaxBonusAmount(); !ok {
+		v := promocodeusage.DefaultMaxBonusAmount
+		_c.mutation.SetMaxBonusAmount(v)
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		v := promocodeusage.DefaultStatus
+		_c.mutation.SetStatus(v)
+	}
+	if _, ok := _c.mutation.Source(); !ok {
+		v := promocodeusage.DefaultSource
+		_c.mutation.SetSource(v)
+	}
+	if _, ok := _c.mutation.BaseAmount(); !ok {
+		v := promocodeusage.DefaultBaseAmount
+		_c.mutation.SetBaseAmount(v)
+	}
 	if _, ok := _c.mutation.UsedAt(); !ok {
 		v := promocodeusage.DefaultUsedAt()
 		_c.mutation.SetUsedAt(v)
@@ -118,6 +254,39 @@ func (_c *PromoCodeUsageCreate) check() error {
 	if _, ok := _c.mutation.BonusAmount(); !ok {
 		return &ValidationError{Name: "bonus_amount", err: errors.New(`ent: missing required field "PromoCodeUsage.bonus_amount"`)}
 	}
+	if _, ok := _c.mutation.BonusType(); !ok {
+		return &ValidationError{Name: "bonus_type", err: errors.New(`ent: missing required field "PromoCodeUsage.bonus_type"`)}
+	}
+	if v, ok := _c.mutation.BonusType(); ok {
+		if err := promocodeusage.BonusTypeValidator(v); err != nil {
+			return &ValidationError{Name: "bonus_type", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.bonus_type": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.BonusPercent(); !ok {
+		return &ValidationError{Name: "bonus_percent", err: errors.New(`ent: missing required field "PromoCodeUsage.bonus_percent"`)}
+	}
+	if _, ok := _c.mutation.MaxBonusAmount(); !ok {
+		return &ValidationError{Name: "max_bonus_amount", err: errors.New(`ent: missing required field "PromoCodeUsage.max_bonus_amount"`)}
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "PromoCodeUsage.status"`)}
+	}
+	if v, ok := _c.mutation.Status(); ok {
+		if err := promocodeusage.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.status": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Source(); !ok {
+		return &ValidationError{Name: "source", err: errors.New(`ent: missing required field "PromoCodeUsage.source"`)}
+	}
+	if v, ok := _c.mutation.Source(); ok {
+		if err := promocodeusage.SourceValidator(v); err != nil {
+			return &ValidationError{Name: "source", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.source": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.BaseAmount(); !ok {
+		return &ValidationError{Name: "base_amount", err: errors.New(`ent: missing required field "PromoCodeUsage.base_amount"`)}
+	}
 	if _, ok := _c.mutation.UsedAt(); !ok {
 		return &ValidationError{Name: "used_at", err: errors.New(`ent: missing required field "PromoCodeUsage.used_at"`)}
 	}
@@ -158,6 +327,38 @@ func (_c *PromoCodeUsageCreate) createSpec() (*PromoCodeUsage, *sqlgraph.CreateS
 		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
 		_node.BonusAmount = value
 	}
+	if value, ok := _c.mutation.BonusType(); ok {
+		_spec.SetField(promocodeusage.FieldBonusType, field.TypeString, value)
+		_node.BonusType = value
+	}
+	if value, ok := _c.mutation.BonusPercent(); ok {
+		_spec.SetField(promocodeusage.FieldBonusPercent, field.TypeFloat64, value)
+		_node.BonusPercent = value
+	}
+	if value, ok := _c.mutation.MaxBonusAmount(); ok {
+		_spec.SetField(promocodeusage.FieldMaxBonusAmount, field.TypeFloat64, value)
+		_node.MaxBonusAmount = value
+	}
+	if value, ok := _c.mutation.Status(); ok {
+		_spec.SetField(promocodeusage.FieldStatus, field.TypeString, value)
+		_node.Status = value
+	}
+	if value, ok := _c.mutation.Source(); ok {
+		_spec.SetField(promocodeusage.FieldSource, field.TypeString, value)
+		_node.Source = value
+	}
+	if value, ok := _c.mutation.BaseAmount(); ok {
+		_spec.SetField(promocodeusage.FieldBaseAmount, field.TypeFloat64, value)
+		_node.BaseAmount = value
+	}
+	if value, ok := _c.mutation.RedeemCodeID(); ok {
+		_spec.SetField(promocodeusage.FieldRedeemCodeID, field.TypeInt64, value)
+		_node.RedeemCodeID = &value
+	}
+	if value, ok := _c.mutation.AppliedAt(); ok {
+		_spec.SetField(promocodeusage.FieldAppliedAt, field.TypeTime, value)
+		_node.AppliedAt = &value
+	}
 	if value, ok := _c.mutation.UsedAt(); ok {
 		_spec.SetField(promocodeusage.FieldUsedAt, field.TypeTime, value)
 		_node.UsedAt = value
@@ -290,6 +491,138 @@ func (u *PromoCodeUsageUpsert) AddBonusAmount(v float64) *PromoCodeUsageUpsert {
 	return u
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (u *PromoCodeUsageUpsert) SetBonusType(v string) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldBonusType, v)
+	return u
+}
+
+// UpdateBonusType sets the "bonus_type" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateBonusType() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldBonusType)
+	return u
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (u *PromoCodeUsageUpsert) SetBonusPercent(v float64) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldBonusPercent, v)
+	return u
+}
+
+// UpdateBonusPercent sets the "bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateBonusPercent() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldBonusPercent)
+	return u
+}
+
+// AddBonusPercent adds v to the "bonus_percent" field.
+func (u *PromoCodeUsageUpsert) AddBonusPercent(v float64) *PromoCodeUsageUpsert {
+	u.Add(promocodeusage.FieldBonusPercent, v)
+	return u
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (u *PromoCodeUsageUpsert) SetMaxBonusAmount(v float64) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldMaxBonusAmount, v)
+	return u
+}
+
+// UpdateMaxBonusAmount sets the "max_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateMaxBonusAmount() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldMaxBonusAmount)
+	return u
+}
+
+// AddMaxBonusAmount adds v to the "max_bonus_amount" field.
+func (u *PromoCodeUsageUpsert) AddMaxBonusAmount(v float64) *PromoCodeUsageUpsert {
+	u.Add(promocodeusage.FieldMaxBonusAmount, v)
+	return u
+}
+
+// SetStatus sets the "status" field.
+func (u *PromoCodeUsageUpsert) SetStatus(v string) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldStatus, v)
+	return u
+}
+
+// UpdateStatus sets the "status" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateStatus() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldStatus)
+	return u
+}
+
+// SetSource sets the "source" field.
+func (u *PromoCodeUsageUpsert) SetSource(v string) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldSource, v)
+	return u
+}
+
+// UpdateSource sets the "source" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateSource() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldSource)
+	return u
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (u *PromoCodeUsageUpsert) SetBaseAmount(v float64) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldBaseAmount, v)
+	return u
+}
+
+// UpdateBaseAmount sets the "base_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateBaseAmount() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldBaseAmount)
+	return u
+}
+
+// AddBaseAmount adds v to the "base_amount" field.
+func (u *PromoCodeUsageUpsert) AddBaseAmount(v float64) *PromoCodeUsageUpsert {
+	u.Add(promocodeusage.FieldBaseAmount, v)
+	return u
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsert) SetRedeemCodeID(v int64) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldRedeemCodeID, v)
+	return u
+}
+
+// UpdateRedeemCodeID sets the "redeem_code_id" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateRedeemCodeID() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldRedeemCodeID)
+	return u
+}
+
+// AddRedeemCodeID adds v to the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsert) AddRedeemCodeID(v int64) *PromoCodeUsageUpsert {
+	u.Add(promocodeusage.FieldRedeemCodeID, v)
+	return u
+}
+
+// ClearRedeemCodeID clears the value of the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsert) ClearRedeemCodeID() *PromoCodeUsageUpsert {
+	u.SetNull(promocodeusage.FieldRedeemCodeID)
+	return u
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (u *PromoCodeUsageUpsert) SetAppliedAt(v time.Time) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldAppliedAt, v)
+	return u
+}
+
+// UpdateAppliedAt sets the "applied_at" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateAppliedAt() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldAppliedAt)
+	return u
+}
+
+// ClearAppliedAt clears the value of the "applied_at" field.
+func (u *PromoCodeUsageUpsert) ClearAppliedAt() *PromoCodeUsageUpsert {
+	u.SetNull(promocodeusage.FieldAppliedAt)
+	return u
+}
+
 // SetUsedAt sets the "used_at" field.
 func (u *PromoCodeUsageUpsert) SetUsedAt(v time.Time) *PromoCodeUsageUpsert {
 	u.Set(promocodeusage.FieldUsedAt, v)
@@ -391,6 +724,160 @@ func (u *PromoCodeUsageUpsertOne) UpdateBonusAmount() *PromoCodeUsageUpsertOne {
 	})
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (u *PromoCodeUsageUpsertOne) SetBonusType(v string) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetBonusType(v)
+	})
+}
+
+// UpdateBonusType sets the "bonus_type" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateBonusType() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateBonusType()
+	})
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (u *PromoCodeUsageUpsertOne) SetBonusPercent(v float64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetBonusPercent(v)
+	})
+}
+
+// AddBonusPercent adds v to the "bonus_percent" field.
+func (u *PromoCodeUsageUpsertOne) AddBonusPercent(v float64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddBonusPercent(v)
+	})
+}
+
+// UpdateBonusPercent sets the "bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateBonusPercent() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateBonusPercent()
+	})
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (u *PromoCodeUsageUpsertOne) SetMaxBonusAmount(v float64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetMaxBonusAmount(v)
+	})
+}
+
+// AddMaxBonusAmount adds v to the "max_bonus_amount" field.
+func (u *PromoCodeUsageUpsertOne) AddMaxBonusAmount(v float64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddMaxBonusAmount(v)
+	})
+}
+
+// UpdateMaxBonusAmount sets the "max_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateMaxBonusAmount() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateMaxBonusAmount()
+	})
+}
+
+// SetStatus sets the "status" field.
+func (u *PromoCodeUsageUpsertOne) SetStatus(v string) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetStatus(v)
+	})
+}
+
+// UpdateStatus sets the "status" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateStatus() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateStatus()
+	})
+}
+
+// SetSource sets the "source" field.
+func (u *PromoCodeUsageUpsertOne) SetSource(v string) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetSource(v)
+	})
+}
+
+// UpdateSource sets the "source" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateSource() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateSource()
+	})
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (u *PromoCodeUsageUpsertOne) SetBaseAmount(v float64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetBaseAmount(v)
+	})
+}
+
+// AddBaseAmount adds v to the "base_amount" field.
+func (u *PromoCodeUsageUpsertOne) AddBaseAmount(v float64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddBaseAmount(v)
+	})
+}
+
+// UpdateBaseAmount sets the "base_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateBaseAmount() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateBaseAmount()
+	})
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsertOne) SetRedeemCodeID(v int64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetRedeemCodeID(v)
+	})
+}
+
+// AddRedeemCodeID adds v to the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsertOne) AddRedeemCodeID(v int64) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddRedeemCodeID(v)
+	})
+}
+
+// UpdateRedeemCodeID sets the "redeem_code_id" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateRedeemCodeID() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateRedeemCodeID()
+	})
+}
+
+// ClearRedeemCodeID clears the value of the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsertOne) ClearRedeemCodeID() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.ClearRedeemCodeID()
+	})
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (u *PromoCodeUsageUpsertOne) SetAppliedAt(v time.Time) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetAppliedAt(v)
+	})
+}
+
+// UpdateAppliedAt sets the "applied_at" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateAppliedAt() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateAppliedAt()
+	})
+}
+
+// ClearAppliedAt clears the value of the "applied_at" field.
+func (u *PromoCodeUsageUpsertOne) ClearAppliedAt() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.ClearAppliedAt()
+	})
+}
+
 // SetUsedAt sets the "used_at" field.
 func (u *PromoCodeUsageUpsertOne) SetUsedAt(v time.Time) *PromoCodeUsageUpsertOne {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
@@ -658,6 +1145,160 @@ func (u *PromoCodeUsageUpsertBulk) UpdateBonusAmount() *PromoCodeUsageUpsertBulk
 	})
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (u *PromoCodeUsageUpsertBulk) SetBonusType(v string) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetBonusType(v)
+	})
+}
+
+// UpdateBonusType sets the "bonus_type" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateBonusType() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateBonusType()
+	})
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (u *PromoCodeUsageUpsertBulk) SetBonusPercent(v float64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetBonusPercent(v)
+	})
+}
+
+// AddBonusPercent adds v to the "bonus_percent" field.
+func (u *PromoCodeUsageUpsertBulk) AddBonusPercent(v float64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddBonusPercent(v)
+	})
+}
+
+// UpdateBonusPercent sets the "bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateBonusPercent() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateBonusPercent()
+	})
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (u *PromoCodeUsageUpsertBulk) SetMaxBonusAmount(v float64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetMaxBonusAmount(v)
+	})
+}
+
+// AddMaxBonusAmount adds v to the "max_bonus_amount" field.
+func (u *PromoCodeUsageUpsertBulk) AddMaxBonusAmount(v float64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddMaxBonusAmount(v)
+	})
+}
+
+// UpdateMaxBonusAmount sets the "max_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateMaxBonusAmount() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateMaxBonusAmount()
+	})
+}
+
+// SetStatus sets the "status" field.
+func (u *PromoCodeUsageUpsertBulk) SetStatus(v string) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetStatus(v)
+	})
+}
+
+// UpdateStatus sets the "status" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateStatus() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateStatus()
+	})
+}
+
+// SetSource sets the "source" field.
+func (u *PromoCodeUsageUpsertBulk) SetSource(v string) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetSource(v)
+	})
+}
+
+// UpdateSource sets the "source" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateSource() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateSource()
+	})
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (u *PromoCodeUsageUpsertBulk) SetBaseAmount(v float64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetBaseAmount(v)
+	})
+}
+
+// AddBaseAmount adds v to the "base_amount" field.
+func (u *PromoCodeUsageUpsertBulk) AddBaseAmount(v float64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddBaseAmount(v)
+	})
+}
+
+// UpdateBaseAmount sets the "base_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateBaseAmount() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateBaseAmount()
+	})
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsertBulk) SetRedeemCodeID(v int64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetRedeemCodeID(v)
+	})
+}
+
+// AddRedeemCodeID adds v to the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsertBulk) AddRedeemCodeID(v int64) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddRedeemCodeID(v)
+	})
+}
+
+// UpdateRedeemCodeID sets the "redeem_code_id" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateRedeemCodeID() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateRedeemCodeID()
+	})
+}
+
+// ClearRedeemCodeID clears the value of the "redeem_code_id" field.
+func (u *PromoCodeUsageUpsertBulk) ClearRedeemCodeID() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.ClearRedeemCodeID()
+	})
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (u *PromoCodeUsageUpsertBulk) SetAppliedAt(v time.Time) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetAppliedAt(v)
+	})
+}
+
+// UpdateAppliedAt sets the "applied_at" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateAppliedAt() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateAppliedAt()
+	})
+}
+
+// ClearAppliedAt clears the value of the "applied_at" field.
+func (u *PromoCodeUsageUpsertBulk) ClearAppliedAt() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.ClearAppliedAt()
+	})
+}
+
 // SetUsedAt sets the "used_at" field.
 func (u *PromoCodeUsageUpsertBulk) SetUsedAt(v time.Time) *PromoCodeUsageUpsertBulk {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
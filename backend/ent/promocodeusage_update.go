@@ -79,6 +79,158 @@ func (_u *PromoCodeUsageUpdate) AddBonusAmount(v float64) *PromoCodeUsageUpdate
 	return _u
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (_u *PromoCodeUsageUpdate) SetBonusType(v string) *PromoCodeUsageUpdate {
+	_u.mutation.SetBonusType(v)
+	return _u
+}
+
+// SetNillableBonusType sets the "bonus_type" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableBonusType(v *string) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetBonusType(*v)
+	}
+	return _u
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (_u *PromoCodeUsageUpdate) SetBonusPercent(v float64) *PromoCodeUsageUpdate {
+	_u.mutation.ResetBonusPercent()
+	_u.mutation.SetBonusPercent(v)
+	return _u
+}
+
+// SetNillableBonusPercent sets the "bonus_percent" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableBonusPercent(v *float64) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetBonusPercent(*v)
+	}
+	return _u
+}
+
+// AddBonusPercent adds value to the "bonus_percent" field.
+func (_u *PromoCodeUsageUpdate) AddBonusPercent(v float64) *PromoCodeUsageUpdate {
+	_u.mutation.AddBonusPercent(v)
+	return _u
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (_u *PromoCodeUsageUpdate) SetMaxBonusAmount(v float64) *PromoCodeUsageUpdate {
+	_u.mutation.ResetMaxBonusAmount()
+	_u.mutation.SetMaxBonusAmount(v)
+	return _u
+}
+
+// SetNillableMaxBonusAmount sets the "max_bonus_amount" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableMaxBonusAmount(v *float64) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetMaxBonusAmount(*v)
+	}
+	return _u
+}
+
+// AddMaxBonusAmount adds value to the "max_bonus_amount" field.
+func (_u *PromoCodeUsageUpdate) AddMaxBonusAmount(v float64) *PromoCodeUsageUpdate {
+	_u.mutation.AddMaxBonusAmount(v)
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *PromoCodeUsageUpdate) SetStatus(v string) *PromoCodeUsageUpdate {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableStatus(v *string) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetSource sets the "source" field.
+func (_u *PromoCodeUsageUpdate) SetSource(v string) *PromoCodeUsageUpdate {
+	_u.mutation.SetSource(v)
+	return _u
+}
+
+// SetNillableSource sets the "source" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableSource(v *string) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetSource(*v)
+	}
+	return _u
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (_u *PromoCodeUsageUpdate) SetBaseAmount(v float64) *PromoCodeUsageUpdate {
+	_u.mutation.ResetBaseAmount()
+	_u.mutation.SetBaseAmount(v)
+	return _u
+}
+
+// SetNillableBaseAmount sets the "base_amount" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableBaseAmount(v *float64) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetBaseAmount(*v)
+	}
+	return _u
+}
+
+// AddBaseAmount adds value to the "base_amount" field.
+func (_u *PromoCodeUsageUpdate) AddBaseAmount(v float64) *PromoCodeUsageUpdate {
+	_u.mutation.AddBaseAmount(v)
+	return _u
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (_u *PromoCodeUsageUpdate) SetRedeemCodeID(v int64) *PromoCodeUsageUpdate {
+	_u.mutation.ResetRedeemCodeID()
+	_u.mutation.SetRedeemCodeID(v)
+	return _u
+}
+
+// SetNillableRedeemCodeID sets the "redeem_code_id" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableRedeemCodeID(v *int64) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetRedeemCodeID(*v)
+	}
+	return _u
+}
+
+// AddRedeemCodeID adds value to the "redeem_code_id" field.
+func (_u *PromoCodeUsageUpdate) AddRedeemCodeID(v int64) *PromoCodeUsageUpdate {
+	_u.mutation.AddRedeemCodeID(v)
+	return _u
+}
+
+// ClearRedeemCodeID clears the value of the "redeem_code_id" field.
+func (_u *PromoCodeUsageUpdate) ClearRedeemCodeID() *PromoCodeUsageUpdate {
+	_u.mutation.ClearRedeemCodeID()
+	return _u
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (_u *PromoCodeUsageUpdate) SetAppliedAt(v time.Time) *PromoCodeUsageUpdate {
+	_u.mutation.SetAppliedAt(v)
+	return _u
+}
+
+// SetNillableAppliedAt sets the "applied_at" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableAppliedAt(v *time.Time) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetAppliedAt(*v)
+	}
+	return _u
+}
+
+// ClearAppliedAt clears the value of the "applied_at" field.
+func (_u *PromoCodeUsageUpdate) ClearAppliedAt() *PromoCodeUsageUpdate {
+	_u.mutation.ClearAppliedAt()
+	return _u
+}
+
 // SetUsedAt sets the "used_at" field.
 func (_u *PromoCodeUsageUpdate) SetUsedAt(v time.Time) *PromoCodeUsageUpdate {
 	_u.mutation.SetUsedAt(v)
@@ -149,6 +301,21 @@ func (_u *PromoCodeUsageUpdate) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (_u *PromoCodeUsageUpdate) check() error {
+	if v, ok := _u.mutation.BonusType(); ok {
+		if err := promocodeusage.BonusTypeValidator(v); err != nil {
+			return &ValidationError{Name: "bonus_type", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.bonus_type": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Status(); ok {
+		if err := promocodeusage.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Source(); ok {
+		if err := promocodeusage.SourceValidator(v); err != nil {
+			return &ValidationError{Name: "source", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.source": %w`, err)}
+		}
+	}
 	if _u.mutation.PromoCodeCleared() && len(_u.mutation.PromoCodeIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "PromoCodeUsage.promo_code"`)
 	}
@@ -176,6 +343,48 @@ func (_u *PromoCodeUsageUpdate) sqlSave(ctx context.Context) (_node int, err err
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
 		_spec.AddField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
 	}
+	if value, ok := _u.mutation.BonusType(); ok {
+		_spec.SetField(promocodeusage.FieldBonusType, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.BonusPercent(); ok {
+		_spec.SetField(promocodeusage.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedBonusPercent(); ok {
+		_spec.AddField(promocodeusage.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.MaxBonusAmount(); ok {
+		_spec.SetField(promocodeusage.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedMaxBonusAmount(); ok {
+		_spec.AddField(promocodeusage.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(promocodeusage.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Source(); ok {
+		_spec.SetField(promocodeusage.FieldSource, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.BaseAmount(); ok {
+		_spec.SetField(promocodeusage.FieldBaseAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedBaseAmount(); ok {
+		_spec.AddField(promocodeusage.FieldBaseAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.RedeemCodeID(); ok {
+		_spec.SetField(promocodeusage.FieldRedeemCodeID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedRedeemCodeID(); ok {
+		_spec.AddField(promocodeusage.FieldRedeemCodeID, field.TypeInt64, value)
+	}
+	if _u.mutation.RedeemCodeIDCleared() {
+		_spec.ClearField(promocodeusage.FieldRedeemCodeID, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AppliedAt(); ok {
+		_spec.SetField(promocodeusage.FieldAppliedAt, field.TypeTime, value)
+	}
+	if _u.mutation.AppliedAtCleared() {
+		_spec.ClearField(promocodeusage.FieldAppliedAt, field.TypeTime)
+	}
 	if value, ok := _u.mutation.UsedAt(); ok {
 		_spec.SetField(promocodeusage.FieldUsedAt, field.TypeTime, value)
 	}
@@ -306,6 +515,158 @@ func (_u *PromoCodeUsageUpdateOne) AddBonusAmount(v float64) *PromoCodeUsageUpda
 	return _u
 }
 
+// SetBonusType sets the "bonus_type" field.
+func (_u *PromoCodeUsageUpdateOne) SetBonusType(v string) *PromoCodeUsageUpdateOne {
+	_u.mutation.SetBonusType(v)
+	return _u
+}
+
+// SetNillableBonusType sets the "bonus_type" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableBonusType(v *string) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetBonusType(*v)
+	}
+	return _u
+}
+
+// SetBonusPercent sets the "bonus_percent" field.
+func (_u *PromoCodeUsageUpdateOne) SetBonusPercent(v float64) *PromoCodeUsageUpdateOne {
+	_u.mutation.ResetBonusPercent()
+	_u.mutation.SetBonusPercent(v)
+	return _u
+}
+
+// SetNillableBonusPercent sets the "bonus_percent" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableBonusPercent(v *float64) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetBonusPercent(*v)
+	}
+	return _u
+}
+
+// AddBonusPercent adds value to the "bonus_percent" field.
+func (_u *PromoCodeUsageUpdateOne) AddBonusPercent(v float64) *PromoCodeUsageUpdateOne {
+	_u.mutation.AddBonusPercent(v)
+	return _u
+}
+
+// SetMaxBonusAmount sets the "max_bonus_amount" field.
+func (_u *PromoCodeUsageUpdateOne) SetMaxBonusAmount(v float64) *PromoCodeUsageUpdateOne {
+	_u.mutation.ResetMaxBonusAmount()
+	_u.mutation.SetMaxBonusAmount(v)
+	return _u
+}
+
+// SetNillableMaxBonusAmount sets the "max_bonus_amount" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableMaxBonusAmount(v *float64) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetMaxBonusAmount(*v)
+	}
+	return _u
+}
+
+// AddMaxBonusAmount adds value to the "max_bonus_amount" field.
+func (_u *PromoCodeUsageUpdateOne) AddMaxBonusAmount(v float64) *PromoCodeUsageUpdateOne {
+	_u.mutation.AddMaxBonusAmount(v)
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *PromoCodeUsageUpdateOne) SetStatus(v string) *PromoCodeUsageUpdateOne {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableStatus(v *string) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetSource sets the "source" field.
+func (_u *PromoCodeUsageUpdateOne) SetSource(v string) *PromoCodeUsageUpdateOne {
+	_u.mutation.SetSource(v)
+	return _u
+}
+
+// SetNillableSource sets the "source" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableSource(v *string) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetSource(*v)
+	}
+	return _u
+}
+
+// SetBaseAmount sets the "base_amount" field.
+func (_u *PromoCodeUsageUpdateOne) SetBaseAmount(v float64) *PromoCodeUsageUpdateOne {
+	_u.mutation.ResetBaseAmount()
+	_u.mutation.SetBaseAmount(v)
+	return _u
+}
+
+// SetNillableBaseAmount sets the "base_amount" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableBaseAmount(v *float64) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetBaseAmount(*v)
+	}
+	return _u
+}
+
+// AddBaseAmount adds value to the "base_amount" field.
+func (_u *PromoCodeUsageUpdateOne) AddBaseAmount(v float64) *PromoCodeUsageUpdateOne {
+	_u.mutation.AddBaseAmount(v)
+	return _u
+}
+
+// SetRedeemCodeID sets the "redeem_code_id" field.
+func (_u *PromoCodeUsageUpdateOne) SetRedeemCodeID(v int64) *PromoCodeUsageUpdateOne {
+	_u.mutation.ResetRedeemCodeID()
+	_u.mutation.SetRedeemCodeID(v)
+	return _u
+}
+
+// SetNillableRedeemCodeID sets the "redeem_code_id" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableRedeemCodeID(v *int64) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetRedeemCodeID(*v)
+	}
+	return _u
+}
+
+// AddRedeemCodeID adds value to the "redeem_code_id" field.
+func (_u *PromoCodeUsageUpdateOne) AddRedeemCodeID(v int64) *PromoCodeUsageUpdateOne {
+	_u.mutation.AddRedeemCodeID(v)
+	return _u
+}
+
+// ClearRedeemCodeID clears the value of the "redeem_code_id" field.
+func (_u *PromoCodeUsageUpdateOne) ClearRedeemCodeID() *PromoCodeUsageUpdateOne {
+	_u.mutation.ClearRedeemCodeID()
+	return _u
+}
+
+// SetAppliedAt sets the "applied_at" field.
+func (_u *PromoCodeUsageUpdateOne) SetAppliedAt(v time.Time) *PromoCodeUsageUpdateOne {
+	_u.mutation.SetAppliedAt(v)
+	return _u
+}
+
+// SetNillableAppliedAt sets the "applied_at" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableAppliedAt(v *time.Time) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetAppliedAt(*v)
+	}
+	return _u
+}
+
+// ClearAppliedAt clears the value of the "applied_at" field.
+func (_u *PromoCodeUsageUpdateOne) ClearAppliedAt() *PromoCodeUsageUpdateOne {
+	_u.mutation.ClearAppliedAt()
+	return _u
+}
+
 // SetUsedAt sets the "used_at" field.
 func (_u *PromoCodeUsageUpdateOne) SetUsedAt(v time.Time) *PromoCodeUsageUpdateOne {
 	_u.mutation.SetUsedAt(v)
@@ -389,6 +750,21 @@ func (_u *PromoCodeUsageUpdateOne) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (_u *PromoCodeUsageUpdateOne) check() error {
+	if v, ok := _u.mutation.BonusType(); ok {
+		if err := promocodeusage.BonusTypeValidator(v); err != nil {
+			return &ValidationError{Name: "bonus_type", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.bonus_type": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Status(); ok {
+		if err := promocodeusage.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Source(); ok {
+		if err := promocodeusage.SourceValidator(v); err != nil {
+			return &ValidationError{Name: "source", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.source": %w`, err)}
+		}
+	}
 	if _u.mutation.PromoCodeCleared() && len(_u.mutation.PromoCodeIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "PromoCodeUsage.promo_code"`)
 	}
@@ -433,6 +809,48 @@ func (_u *PromoCodeUsageUpdateOne) sqlSave(ctx context.Context) (_node *PromoCod
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
 		_spec.AddField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
 	}
+	if value, ok := _u.mutation.BonusType(); ok {
+		_spec.SetField(promocodeusage.FieldBonusType, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.BonusPercent(); ok {
+		_spec.SetField(promocodeusage.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedBonusPercent(); ok {
+		_spec.AddField(promocodeusage.FieldBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.MaxBonusAmount(); ok {
+		_spec.SetField(promocodeusage.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedMaxBonusAmount(); ok {
+		_spec.AddField(promocodeusage.FieldMaxBonusAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(promocodeusage.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Source(); ok {
+		_spec.SetField(promocodeusage.FieldSource, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.BaseAmount(); ok {
+		_spec.SetField(promocodeusage.FieldBaseAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedBaseAmount(); ok {
+		_spec.AddField(promocodeusage.FieldBaseAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.RedeemCodeID(); ok {
+		_spec.SetField(promocodeusage.FieldRedeemCodeID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedRedeemCodeID(); ok {
+		_spec.AddField(promocodeusage.FieldRedeemCodeID, field.TypeInt64, value)
+	}
+	if _u.mutation.RedeemCodeIDCleared() {
+		_spec.ClearField(promocodeusage.FieldRedeemCodeID, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AppliedAt(); ok {
+		_spec.SetField(promocodeusage.FieldAppliedAt, field.TypeTime, value)
+	}
+	if _u.mutation.AppliedAtCleared() {
+		_spec.ClearField(promocodeusage.FieldAppliedAt, field.TypeTime)
+	}
 	if value, ok := _u.mutation.UsedAt(); ok {
 		_spec.SetField(promocodeusage.FieldUsedAt, field.TypeTime, value)
 	}
@@ -425,6 +425,18 @@ func init() {
 	groupDescSortOrder := groupFields[23].Descriptor()
 	// group.DefaultSortOrder holds the default value on creation for the sort_order field.
 	group.DefaultSortOrder = groupDescSortOrder.Default.(int)
+	// groupDescHedgeEnabled is the schema descriptor for hedge_enabled field.
+	groupDescHedgeEnabled := groupFields[24].Descriptor()
+	// group.DefaultHedgeEnabled holds the default value on creation for the hedge_enabled field.
+	group.DefaultHedgeEnabled = groupDescHedgeEnabled.Default.(bool)
+	// groupDescHedgeDelayMs is the schema descriptor for hedge_delay_ms field.
+	groupDescHedgeDelayMs := groupFields[25].Descriptor()
+	// group.DefaultHedgeDelayMs holds the default value on creation for the hedge_delay_ms field.
+	group.DefaultHedgeDelayMs = groupDescHedgeDelayMs.Default.(int)
+	// groupDescHedgeMaxRatio is the schema descriptor for hedge_max_ratio field.
+	groupDescHedgeMaxRatio := groupFields[26].Descriptor()
+	// group.DefaultHedgeMaxRatio holds the default value on creation for the hedge_max_ratio field.
+	group.DefaultHedgeMaxRatio = groupDescHedgeMaxRatio.Default.(float64)
 	promocodeFields := schema.PromoCode{}.Fields()
 	_ = promocodeFields
 	// promocodeDescCode is the schema descriptor for code field.
@@ -449,34 +461,96 @@ func init() {
 	promocodeDescBonusAmount := promocodeFields[1].Descriptor()
 	// promocode.DefaultBonusAmount holds the default value on creation for the bonus_amount field.
 	promocode.DefaultBonusAmount = promocodeDescBonusAmount.Default.(float64)
+	// promocodeDescBonusType is the schema descriptor for bonus_type field.
+	promocodeDescBonusType := promocodeFields[2].Descriptor()
+	// promocode.DefaultBonusType holds the default value on creation for the bonus_type field.
+	promocode.DefaultBonusType = promocodeDescBonusType.Default.(string)
+	// promocode.BonusTypeValidator is a validator for the "bonus_type" field. It is called by the builders before save.
+	promocode.BonusTypeValidator = promocodeDescBonusType.Validators[0].(func(string) error)
+	// promocodeDescBonusPercent is the schema descriptor for bonus_percent field.
+	promocodeDescBonusPercent := promocodeFields[3].Descriptor()
+	// promocode.DefaultBonusPercent holds the default value on creation for the bonus_percent field.
+	promocode.DefaultBonusPercent = promocodeDescBonusPercent.Default.(float64)
+	// promocodeDescMaxBonusAmount is the schema descriptor for max_bonus_amount field.
+	promocodeDescMaxBonusAmount := promocodeFields[4].Descriptor()
+	// promocode.DefaultMaxBonusAmount holds the default value on creation for the max_bonus_amount field.
+	promocode.DefaultMaxBonusAmount = promocodeDescMaxBonusAmount.Default.(float64)
 	// promocodeDescMaxUses is the schema descriptor for max_uses field.
-	promocodeDescMaxUses := promocodeFields[2].Descriptor()
+	promocodeDescMaxUses := promocodeFields[5].Descriptor()
 	// promocode.DefaultMaxUses holds the default value on creation for the max_uses field.
 	promocode.DefaultMaxUses = promocodeDescMaxUses.Default.(int)
 	// promocodeDescUsedCount is the schema descriptor for used_count field.
-	promocodeDescUsedCount := promocodeFields[3].Descriptor()
+	promocodeDescUsedCount := promocodeFields[6].Descriptor()
 	// promocode.DefaultUsedCount holds the default value on creation for the used_count field.
 	promocode.DefaultUsedCount = promocodeDescUsedCount.Default.(int)
+	// promocodeDescPerUserLimit is the schema descriptor for per_user_limit field.
+	promocodeDescPerUserLimit := promocodeFields[7].Descriptor()
+	// promocode.DefaultPerUserLimit holds the default value on creation for the per_user_limit field.
+	promocode.DefaultPerUserLimit = promocodeDescPerUserLimit.Default.(int)
+	// promocodeDescScope is the schema descriptor for scope field.
+	promocodeDescScope := promocodeFields[8].Descriptor()
+	// promocode.DefaultScope holds the default value on creation for the scope field.
+	promocode.DefaultScope = promocodeDescScope.Default.(string)
+	// promocode.ScopeValidator is a validator for the "scope" field. It is called by the builders before save.
+	promocode.ScopeValidator = promocodeDescScope.Validators[0].(func(string) error)
+	// promocodeDescNewUsersOnly is the schema descriptor for new_users_only field.
+	promocodeDescNewUsersOnly := promocodeFields[9].Descriptor()
+	// promocode.DefaultNewUsersOnly holds the default value on creation for the new_users_only field.
+	promocode.DefaultNewUsersOnly = promocodeDescNewUsersOnly.Default.(bool)
+	// promocodeDescNewUserDays is the schema descriptor for new_user_days field.
+	promocodeDescNewUserDays := promocodeFields[10].Descriptor()
+	// promocode.DefaultNewUserDays holds the default value on creation for the new_user_days field.
+	promocode.DefaultNewUserDays = promocodeDescNewUserDays.Default.(int)
 	// promocodeDescStatus is the schema descriptor for status field.
-	promocodeDescStatus := promocodeFields[4].Descriptor()
+	promocodeDescStatus := promocodeFields[13].Descriptor()
 	// promocode.DefaultStatus holds the default value on creation for the status field.
 	promocode.DefaultStatus = promocodeDescStatus.Default.(string)
 	// promocode.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	promocode.StatusValidator = promocodeDescStatus.Validators[0].(func(string) error)
 	// promocodeDescCreatedAt is the schema descriptor for created_at field.
-	promocodeDescCreatedAt := promocodeFields[7].Descriptor()
+	promocodeDescCreatedAt := promocodeFields[16].Descriptor()
 	// promocode.DefaultCreatedAt holds the default value on creation for the created_at field.
 	promocode.DefaultCreatedAt = promocodeDescCreatedAt.Default.(func() time.Time)
 	// promocodeDescUpdatedAt is the schema descriptor for updated_at field.
-	promocodeDescUpdatedAt := promocodeFields[8].Descriptor()
+	promocodeDescUpdatedAt := promocodeFields[17].Descriptor()
 	// promocode.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	promocode.DefaultUpdatedAt = promocodeDescUpdatedAt.Default.(func() time.Time)
 	// promocode.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	promocode.UpdateDefaultUpdatedAt = promocodeDescUpdatedAt.UpdateDefault.(func() time.Time)
 	promocodeusageFields := schema.PromoCodeUsage{}.Fields()
 	_ = promocodeusageFields
+	// promocodeusageDescBonusType is the schema descriptor for bonus_type field.
+	promocodeusageDescBonusType := promocodeusageFields[3].Descriptor()
+	// promocodeusage.DefaultBonusType holds the default value on creation for the bonus_type field.
+	promocodeusage.DefaultBonusType = promocodeusageDescBonusType.Default.(string)
+	// promocodeusage.BonusTypeValidator is a validator for the "bonus_type" field. It is called by the builders before save.
+	promocodeusage.BonusTypeValidator = promocodeusageDescBonusType.Validators[0].(func(string) error)
+	// promocodeusageDescBonusPercent is the schema descriptor for bonus_percent field.
+	promocodeusageDescBonusPercent := promocodeusageFields[4].Descriptor()
+	// promocodeusage.DefaultBonusPercent holds the default value on creation for the bonus_percent field.
+	promocodeusage.DefaultBonusPercent = promocodeusageDescBonusPercent.Default.(float64)
+	// promocodeusageDescMaxBonusAmount is the schema descriptor for max_bonus_amount field.
+	promocodeusageDescMaxBonusAmount := promocodeusageFields[5].Descriptor()
+	// promocodeusage.DefaultMaxBonusAmount holds the default value on creation for the max_bonus_amount field.
+	promocodeusage.DefaultMaxBonusAmount = promocodeusageDescMaxBonusAmount.Default.(float64)
+	// promocodeusageDescStatus is the schema descriptor for status field.
+	promocodeusageDescStatus := promocodeusageFields[6].Descriptor()
+	// promocodeusage.DefaultStatus holds the default value on creation for the status field.
+	promocodeusage.DefaultStatus = promocodeusageDescStatus.Default.(string)
+	// promocodeusage.StatusValidator is a validator for the "status" field. It is called by the builders before save.
+	promocodeusage.StatusValidator = promocodeusageDescStatus.Validators[0].(func(string) error)
+	// promocodeusageDescSource is the schema descriptor for source field.
+	promocodeusageDescSource := promocodeusageFields[7].Descriptor()
+	// promocodeusage.DefaultSource holds the default value on creation for the source field.
+	promocodeusage.DefaultSource = promocodeusageDescSource.Default.(string)
+	// promocodeusage.SourceValidator is a validator for the "source" field. It is called by the builders before save.
+	promocodeusage.SourceValidator = promocodeusageDescSource.Validators[0].(func(string) error)
+	// promocodeusageDescBaseAmount is the schema descriptor for base_amount field.
+	promocodeusageDescBaseAmount := promocodeusageFields[8].Descriptor()
+	// promocodeusage.DefaultBaseAmount holds the default value on creation for the base_amount field.
+	promocodeusage.DefaultBaseAmount = promocodeusageDescBaseAmount.Default.(float64)
 	// promocodeusageDescUsedAt is the schema descriptor for used_at field.
-	promocodeusageDescUsedAt := promocodeusageFields[3].Descriptor()
+	promocodeusageDescUsedAt := promocodeusageFields[11].Descriptor()
 	// promocodeusage.DefaultUsedAt holds the default value on creation for the used_at field.
 	promocodeusage.DefaultUsedAt = promocodeusageDescUsedAt.Default.(func() time.Time)
 	proxyMixin := schema.Proxy{}.Mixin()
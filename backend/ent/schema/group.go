@@ -138,6 +138,24 @@ func (Group) Fields() []ent.Field {
 		field.Int("sort_order").
 			Default(0).
 			Comment("分组显示排序，数值越小越靠前"),
+
+		// 对冲请求配置 (added by migration 055)
+		field.Bool("hedge_enabled").
+			Default(false).
+			Comment("是否启用对冲请求：首账号响应头超时后向第二个账号并发请求"),
+		field.Int("hedge_delay_ms").
+			Default(2000).
+			Comment("触发对冲请求的响应头等待阈值（毫秒）"),
+		field.Float("hedge_max_ratio").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(5,4)"}).
+			Default(0.1).
+			Comment("对冲额外上游 token 占正常用量的比例上限(0~1)"),
+
+		// 调度时间窗口 (added by migration 056)
+		field.JSON("schedule_rule", &domain.ScheduleRule{}).
+			Optional().
+			SchemaType(map[string]string{dialect.Postgres: "jsonb"}).
+			Comment("分组调度时间窗口，作为未单独配置窗口的账号的默认规则"),
 	}
 }
 
@@ -16,7 +16,9 @@ import (
 
 // PromoCode holds the schema definition for the PromoCode entity.
 //
-// 注册优惠码：用户注册时使用，可获得赠送余额
+// 优惠码：注册时或注册后在用户面板使用，可获得赠送余额
+// - fixed：立即赠送固定余额；percent：下次余额充值时按比例额外赠送
+// - 可限制仅新用户 / 持有指定订阅分组的用户使用，并限制每个用户的使用次数
 // 与 RedeemCode 不同，PromoCode 支持多次使用（有使用次数限制）
 //
 // 删除策略：硬删除
@@ -40,13 +42,47 @@ func (PromoCode) Fields() []ent.Field {
 		field.Float("bonus_amount").
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
 			Default(0).
-			Comment("赠送余额金额"),
+			Comment("赠送余额金额（bonus_type=fixed）"),
+		field.String("bonus_type").
+			MaxLen(20).
+			Default(domain.PromoBonusTypeFixed).
+			Comment("赠送方式: fixed, percent"),
+		field.Float("bonus_percent").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(10,4)"}).
+			Default(0).
+			Comment("下次充值赠送比例（百分比，bonus_type=percent）"),
+		field.Float("max_bonus_amount").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0).
+			Comment("比例赠送的单次上限，0表示不限"),
 		field.Int("max_uses").
 			Default(0).
 			Comment("最大使用次数，0表示无限制"),
 		field.Int("used_count").
 			Default(0).
 			Comment("已使用次数"),
+		field.Int("per_user_limit").
+			Default(1).
+			Comment("每个用户最多使用次数，0表示无限制"),
+		field.String("scope").
+			MaxLen(20).
+			Default(domain.PromoScopeRegister).
+			Comment("使用场景: register, panel, any"),
+		field.Bool("new_users_only").
+			Default(false).
+			Comment("仅限新用户"),
+		field.Int("new_user_days").
+			Default(0).
+			Comment("注册后多少天内视为新用户（面板兑换），0表示仅注册时可用"),
+		field.Int64("required_group_id").
+			Optional().
+			Nillable().
+			Comment("要求持有该分组的有效订阅，null表示不限"),
+		field.Time("starts_at").
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
+			Comment("生效时间，null表示立即生效"),
 		field.String("status").
 			MaxLen(20).
 			Default(domain.PromoCodeStatusActive).
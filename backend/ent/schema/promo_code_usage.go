@@ -3,6 +3,8 @@ package schema
 import (
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
@@ -33,7 +35,40 @@ func (PromoCodeUsage) Fields() []ent.Field {
 			Comment("使用用户ID"),
 		field.Float("bonus_amount").
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
-			Comment("实际赠送金额"),
+			Comment("实际赠送金额（比例赠送在充值前为 0）"),
+		field.String("bonus_type").
+			MaxLen(20).
+			Default(domain.PromoBonusTypeFixed).
+			Comment("赠送方式: fixed, percent"),
+		field.Float("bonus_percent").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(10,4)"}).
+			Default(0).
+			Comment("比例赠送的百分比"),
+		field.Float("max_bonus_amount").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0).
+			Comment("比例赠送的上限，0表示不限"),
+		field.String("status").
+			MaxLen(20).
+			Default(domain.PromoUsageStatusApplied).
+			Comment("状态: applied, pending（等待下次充值）"),
+		field.String("source").
+			MaxLen(20).
+			Default(domain.PromoScopeRegister).
+			Comment("使用场景: register, panel"),
+		field.Float("base_amount").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0).
+			Comment("触发比例赠送的充值金额"),
+		field.Int64("redeem_code_id").
+			Optional().
+			Nillable().
+			Comment("触发比例赠送的充值兑换码ID"),
+		field.Time("applied_at").
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
+			Comment("赠送发放时间"),
 		field.Time("used_at").
 			Default(time.Now).
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
@@ -60,7 +95,8 @@ func (PromoCodeUsage) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("promo_code_id"),
 		index.Fields("user_id"),
-		// 每个用户每个优惠码只能使用一次
-		index.Fields("promo_code_id", "user_id").Unique(),
+		// 每个用户的使用次数由 promo_codes.per_user_limit 控制
+		index.Fields("promo_code_id", "user_id"),
+		index.Fields("user_id", "status"),
 	}
 }
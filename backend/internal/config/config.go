@@ -48,6 +48,8 @@ type Config struct {
 	Ops          OpsConfig                  `mapstructure:"ops"`
 	JWT          JWTConfig                  `mapstructure:"jwt"`
 	Totp         TotpConfig                 `mapstructure:"totp"`
+	WebAuthn     WebAuthnConfig             `mapstructure:"webauthn"`
+	LoginGuard   LoginGuardConfig           `mapstructure:"login_guard"`
 	LinuxDo      LinuxDoConnectConfig       `mapstructure:"linuxdo_connect"`
 	Default      DefaultConfig              `mapstructure:"default"`
 	RateLimit    RateLimitConfig            `mapstructure:"rate_limit"`
@@ -57,6 +59,7 @@ type Config struct {
 	Dashboard    DashboardCacheConfig       `mapstructure:"dashboard_cache"`
 	DashboardAgg DashboardAggregationConfig `mapstructure:"dashboard_aggregation"`
 	UsageCleanup UsageCleanupConfig         `mapstructure:"usage_cleanup"`
+	Log          LogConfig                  `mapstructure:"log"`
 	Concurrency  ConcurrencyConfig          `mapstructure:"concurrency"`
 	TokenRefresh TokenRefreshConfig         `mapstructure:"token_refresh"`
 	RunMode      string                     `mapstructure:"run_mode" yaml:"run_mode"`
@@ -498,6 +501,37 @@ type TotpConfig struct {
 	EncryptionKeyConfigured bool `mapstructure:"-"`
 }
 
+// WebAuthnConfig 通行密钥（WebAuthn）依赖方配置
+type WebAuthnConfig struct {
+	// RPID 依赖方 ID（通常为站点域名，如 example.com）；为空时按请求 Host 推导
+	RPID string `mapstructure:"rp_id"`
+	// RPName 在认证器中展示的站点名称；为空时使用站点名称设置
+	RPName string `mapstructure:"rp_name"`
+	// Origins 允许的前端来源（如 https://example.com）；为空时按请求推导
+	Origins []string `mapstructure:"origins"`
+}
+
+// LoginGuardConfig 登录防暴力破解配置（按账号 / IP 统计失败次数）
+type LoginGuardConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// FailureWindowMinutes 失败次数统计窗口（最后一次失败后超过该时长计数清零）
+	FailureWindowMinutes int `mapstructure:"failure_window_minutes"`
+	// AccountMaxFailures 单个账号在窗口内允许的失败次数，达到后锁定账号
+	AccountMaxFailures int `mapstructure:"account_max_failures"`
+	// IPMaxFailures 单个 IP 在窗口内允许的失败次数（跨账号），达到后锁定该 IP
+	IPMaxFailures int `mapstructure:"ip_max_failures"`
+	// LockoutMinutes 锁定时长
+	LockoutMinutes int `mapstructure:"lockout_minutes"`
+	// DelayAfterFailures 失败达到该次数后启用递增等待（1s、2s、4s…），0 表示不启用
+	DelayAfterFailures int `mapstructure:"delay_after_failures"`
+	// MaxDelaySeconds 递增等待上限
+	MaxDelaySeconds int `mapstructure:"max_delay_seconds"`
+	// CaptchaAfterFailures 失败达到该次数后强制 Turnstile 人机验证（即使全局未开启，需已配置密钥），0 表示不启用
+	CaptchaAfterFailures int `mapstructure:"captcha_after_failures"`
+	// NotifyOnLockout 账号被锁定时邮件通知用户
+	NotifyOnLockout bool `mapstructure:"notify_on_lockout"`
+}
+
 type TurnstileConfig struct {
 	Required bool `mapstructure:"required"`
 }
@@ -564,6 +598,16 @@ type DashboardAggregationRetentionConfig struct {
 	DailyDays     int `mapstructure:"daily_days"`
 }
 
+// LogConfig 结构化日志配置
+type LogConfig struct {
+	// Format: 输出格式 json / logfmt（text 为 logfmt 的别名）
+	Format string `mapstructure:"format"`
+	// Level: 默认日志级别 debug / info / warn / error；为空时 release 模式为 info，否则为 debug
+	Level string `mapstructure:"level"`
+	// Modules: 按模块覆盖日志级别，例如 {"gateway": "debug"}（运行时可在管理后台调整）
+	Modules map[string]string `mapstructure:"modules"`
+}
+
 // UsageCleanupConfig 使用记录清理任务配置
 type UsageCleanupConfig struct {
 	// Enabled: 是否启用清理任务执行器
@@ -817,6 +861,22 @@ func setDefaults() {
 	// TOTP
 	viper.SetDefault("totp.encryption_key", "")
 
+	// WebAuthn
+	viper.SetDefault("webauthn.rp_id", "")
+	viper.SetDefault("webauthn.rp_name", "")
+	viper.SetDefault("webauthn.origins", []string{})
+
+	// Login guard
+	viper.SetDefault("login_guard.enabled", true)
+	viper.SetDefault("login_guard.failure_window_minutes", 15)
+	viper.SetDefault("login_guard.account_max_failures", 10)
+	viper.SetDefault("login_guard.ip_max_failures", 50)
+	viper.SetDefault("login_guard.lockout_minutes", 15)
+	viper.SetDefault("login_guard.delay_after_failures", 3)
+	viper.SetDefault("login_guard.max_delay_seconds", 30)
+	viper.SetDefault("login_guard.captcha_after_failures", 3)
+	viper.SetDefault("login_guard.notify_on_lockout", true)
+
 	// Default
 	// Admin credentials are created via the setup flow (web wizard / CLI / AUTO_SETUP).
 	// Do not ship fixed defaults here to avoid insecure "known credentials" in production.
@@ -841,6 +901,10 @@ func setDefaults() {
 	// Timezone (default to Asia/Shanghai for Chinese users)
 	viper.SetDefault("timezone", "Asia/Shanghai")
 
+	// Log
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("log.level", "")
+
 	// API Key auth cache
 	viper.SetDefault("api_key_auth_cache.l1_size", 65535)
 	viper.SetDefault("api_key_auth_cache.l1_ttl_seconds", 15)
@@ -1132,6 +1196,19 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("dashboard_aggregation.recompute_days must be non-negative")
 		}
 	}
+	switch strings.ToLower(strings.TrimSpace(c.Log.Format)) {
+	case "", "text", "logfmt", "json":
+	default:
+		return fmt.Errorf("log.format must be one of: json, logfmt, text")
+	}
+	if !isValidLogLevel(c.Log.Level) {
+		return fmt.Errorf("log.level must be one of: debug, info, warn, error")
+	}
+	for module, level := range c.Log.Modules {
+		if !isValidLogLevel(level) {
+			return fmt.Errorf("log.modules.%s must be one of: debug, info, warn, error", module)
+		}
+	}
 	if c.UsageCleanup.Enabled {
 		if c.UsageCleanup.MaxRangeDays <= 0 {
 			return fmt.Errorf("usage_cleanup.max_range_days must be positive")
@@ -1294,6 +1371,15 @@ func normalizeStringSlice(values []string) []string {
 	return normalized
 }
 
+func isValidLogLevel(level string) bool {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "", "debug", "info", "warn", "warning", "error":
+		return true
+	default:
+		return false
+	}
+}
+
 func isWeakJWTSecret(secret string) bool {
 	lower := strings.ToLower(strings.TrimSpace(secret))
 	if lower == "" {
@@ -46,12 +46,36 @@ const (
 	PromoCodeStatusDisabled = "disabled"
 )
 
+// PromoCode bonus type constants
+const (
+	PromoBonusTypeFixed   = "fixed"   // 立即赠送固定余额
+	PromoBonusTypePercent = "percent" // 下次充值按比例赠送
+)
+
+// PromoCode scope constants（可使用的场景）
+const (
+	PromoScopeRegister = "register" // 仅注册时
+	PromoScopePanel    = "panel"    // 仅注册后在用户面板兑换
+	PromoScopeAny      = "any"      // 均可
+)
+
+// PromoCodeUsage status constants
+const (
+	PromoUsageStatusApplied = "applied" // 已发放
+	PromoUsageStatusPending = "pending" // 比例赠送，等待下次充值
+)
+
 // Admin adjustment type constants
 const (
 	AdjustmentTypeAdminBalance     = "admin_balance"     // 管理员调整余额
 	AdjustmentTypeAdminConcurrency = "admin_concurrency" // 管理员调整并发数
 )
 
+// Balance ledger type constants
+const (
+	LedgerTypePayment = "payment" // 在线支付充值（退款扣回记为负数）
+)
+
 // Group subscription type constants
 const (
 	SubscriptionTypeStandard     = "standard"     // 标准计费模式（按余额扣费）
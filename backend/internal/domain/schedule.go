@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// ScheduleModeAllow 仅在窗口内可调度（如错峰 key）
+	ScheduleModeAllow = "allow"
+	// ScheduleModeDeny 窗口内不可调度（如与其他团队共享、对方工作时间让出）
+	ScheduleModeDeny = "deny"
+)
+
+const (
+	scheduleMaxWindows         = 20
+	scheduleMaxDurationMinutes = 7 * 24 * 60
+)
+
+var ErrScheduleRuleInvalid = infraerrors.BadRequest("SCHEDULE_RULE_INVALID", "invalid schedule rule")
+
+// ScheduleRule 账号/分组的调度时间窗口规则。
+// 每个窗口由标准 5 段 cron 表达式标记开始时间，持续 DurationMinutes 分钟。
+type ScheduleRule struct {
+	// Mode: allow | deny
+	Mode string `json:"mode"`
+	// Timezone 为空时使用全局时区（pkg/timezone）
+	Timezone string           `json:"timezone,omitempty"`
+	Windows  []ScheduleWindow `json:"windows"`
+}
+
+type ScheduleWindow struct {
+	// Cron 窗口开始时间，例如 "0 9 * * 1-5" 表示工作日 09:00
+	Cron            string `json:"cron"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+var (
+	scheduleCronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	scheduleCronCache  sync.Map // expr -> cron.Schedule
+	scheduleLocCache   sync.Map // tz -> *time.Location
+)
+
+func parseScheduleCron(expr string) (cron.Schedule, error) {
+	if cached, ok := scheduleCronCache.Load(expr); ok {
+		return cached.(cron.Schedule), nil
+	}
+	sched, err := scheduleCronParser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	scheduleCronCache.Store(expr, sched)
+	return sched, nil
+}
+
+func loadScheduleLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		return timezone.Location(), nil
+	}
+	if cached, ok := scheduleLocCache.Load(tz); ok {
+		return cached.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+	scheduleLocCache.Store(tz, loc)
+	return loc, nil
+}
+
+// IsEmpty 未配置任何窗口的规则不限制调度
+func (r *ScheduleRule) IsEmpty() bool {
+	return r == nil || len(r.Windows) == 0
+}
+
+// InWindow 判断 now 是否落在任意窗口内
+func (r *ScheduleRule) InWindow(now time.Time) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	loc, err := loadScheduleLocation(r.Timezone)
+	if err != nil {
+		loc = timezone.Location()
+	}
+	now = now.In(loc)
+	for _, w := range r.Windows {
+		sched, err := parseScheduleCron(w.Cron)
+		if err != nil || w.DurationMinutes <= 0 {
+			continue
+		}
+		// Next 返回严格晚于参数的下一次触发时间：
+		// 若 (now - duration) 之后的第一次触发不晚于 now，则 now 处于该次窗口内
+		duration := time.Duration(w.DurationMinutes) * time.Minute
+		if start := sched.Next(now.Add(-duration)); !start.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows 判断规则在 now 时刻是否允许调度；空规则始终允许
+func (r *ScheduleRule) Allows(now time.Time) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	inWindow := r.InWindow(now)
+	if r.Mode == ScheduleModeDeny {
+		return !inWindow
+	}
+	return inWindow
+}
+
+func (r ScheduleRule) NormalizeAndValidate() (ScheduleRule, error) {
+	normalized := ScheduleRule{
+		Mode:     strings.ToLower(strings.TrimSpace(r.Mode)),
+		Timezone: strings.TrimSpace(r.Timezone),
+		Windows:  make([]ScheduleWindow, 0, len(r.Windows)),
+	}
+	if normalized.Mode == "" {
+		normalized.Mode = ScheduleModeAllow
+	}
+	if normalized.Mode != ScheduleModeAllow && normalized.Mode != ScheduleModeDeny {
+		return ScheduleRule{}, ErrScheduleRuleInvalid
+	}
+	if normalized.Timezone != "" {
+		if _, err := loadScheduleLocation(normalized.Timezone); err != nil {
+			return ScheduleRule{}, ErrScheduleRuleInvalid
+		}
+	}
+	if len(r.Windows) > scheduleMaxWindows {
+		return ScheduleRule{}, ErrScheduleRuleInvalid
+	}
+	for _, w := range r.Windows {
+		window := ScheduleWindow{
+			Cron:            strings.TrimSpace(w.Cron),
+			DurationMinutes: w.DurationMinutes,
+		}
+		if window.DurationMinutes <= 0 || window.DurationMinutes > scheduleMaxDurationMinutes {
+			return ScheduleRule{}, ErrScheduleRuleInvalid
+		}
+		if _, err := parseScheduleCron(window.Cron); err != nil {
+			return ScheduleRule{}, ErrScheduleRuleInvalid
+		}
+		normalized.Windows = append(normalized.Windows, window)
+	}
+	return normalized, nil
+}
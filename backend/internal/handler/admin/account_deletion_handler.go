@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountDeletionHandler handles reviewing and restoring self-service account deletions
+type AccountDeletionHandler struct {
+	userDataService *service.UserDataService
+}
+
+// NewAccountDeletionHandler creates a new admin account deletion handler
+func NewAccountDeletionHandler(userDataService *service.UserDataService) *AccountDeletionHandler {
+	return &AccountDeletionHandler{userDataService: userDataService}
+}
+
+// List handles listing account deletion requests
+// GET /api/v1/admin/account-deletions?status=scheduled&user_id=2
+func (h *AccountDeletionHandler) List(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.AccountDeletionFilter{Status: c.Query("status")}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+
+	items, result, err := h.userDataService.ListDeletionRequests(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// Restore handles cancelling a scheduled deletion during the grace period
+// POST /api/v1/admin/account-deletions/:id/restore
+func (h *AccountDeletionHandler) Restore(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid request ID")
+		return
+	}
+
+	adminID := opsActorUserID(c)
+	if adminID == nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	req, err := h.userDataService.RestoreDeletion(c.Request.Context(), id, *adminID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, req)
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AccountProbeHandler 处理账号定时探测（合成健康检查）相关请求
+type AccountProbeHandler struct {
+	probeService *service.AccountProbeService
+}
+
+// NewAccountProbeHandler 创建账号探测处理器
+func NewAccountProbeHandler(probeService *service.AccountProbeService) *AccountProbeHandler {
+	return &AccountProbeHandler{probeService: probeService}
+}
+
+// GetSettings 获取账号探测配置
+// GET /api/v1/admin/accounts/probe-settings
+func (h *AccountProbeHandler) GetSettings(c *gin.Context) {
+	settings, err := h.probeService.GetSettings(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// UpdateSettings 更新账号探测配置
+// PUT /api/v1/admin/accounts/probe-settings
+func (h *AccountProbeHandler) UpdateSettings(c *gin.Context) {
+	var req service.AccountProbeSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.probeService.UpdateSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// GetLatest 获取账号最近一次探测结果
+// GET /api/v1/admin/accounts/probe-status?account_ids=1,2,3
+func (h *AccountProbeHandler) GetLatest(c *gin.Context) {
+	var accountIDs []int64
+	if raw := strings.TrimSpace(c.Query("account_ids")); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil || id <= 0 {
+				response.BadRequest(c, "Invalid account_ids")
+				return
+			}
+			accountIDs = append(accountIDs, id)
+		}
+	}
+
+	results, err := h.probeService.GetLatestResults(c.Request.Context(), accountIDs)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, results)
+}
+
+// ListResults 获取账号探测历史
+// GET /api/v1/admin/accounts/:id/probes?limit=50
+func (h *AccountProbeHandler) ListResults(c *gin.Context) {
+	accountID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid account ID")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	results, err := h.probeService.ListResults(c.Request.Context(), accountID, limit)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, results)
+}
+
+// Probe 立即探测账号
+// POST /api/v1/admin/accounts/:id/probe
+func (h *AccountProbeHandler) Probe(c *gin.Context) {
+	accountID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid account ID")
+		return
+	}
+
+	result, err := h.probeService.ProbeAccount(c.Request.Context(), accountID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
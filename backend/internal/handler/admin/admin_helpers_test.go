@@ -120,6 +120,34 @@ func TestOpsAlertRuleValidation(t *testing.T) {
 	require.False(t, isPercentOrRateMetric("concurrency_queue_depth"))
 }
 
+func TestValidateOpsAlertRulePayloadSLOMetrics(t *testing.T) {
+	base := func(filters string) map[string]json.RawMessage {
+		raw := map[string]json.RawMessage{
+			"name":           json.RawMessage(`"SLO fast burn"`),
+			"metric_type":    json.RawMessage(`"slo_burn_rate"`),
+			"operator":       json.RawMessage(`">="`),
+			"threshold":      json.RawMessage(`14.4`),
+			"window_minutes": json.RawMessage(`60`),
+		}
+		if filters != "" {
+			raw["filters"] = json.RawMessage(filters)
+		}
+		return raw
+	}
+
+	_, err := validateOpsAlertRulePayload(base(`{"slo_id": 3, "short_window_minutes": 5}`))
+	require.NoError(t, err)
+
+	_, err = validateOpsAlertRulePayload(base(""))
+	require.ErrorContains(t, err, "slo_id")
+
+	_, err = validateOpsAlertRulePayload(base(`{"slo_id": 3, "objective": "throughput"}`))
+	require.ErrorContains(t, err, "objective")
+
+	_, err = validateOpsAlertRulePayload(base(`{"slo_id": 3, "short_window_minutes": 2.5}`))
+	require.ErrorContains(t, err, "short_window_minutes")
+}
+
 func TestOpsWSHelpers(t *testing.T) {
 	prefixes, invalid := parseTrustedProxyList("10.0.0.0/8,invalid")
 	require.Len(t, prefixes, 1)
@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAbuseHandler 处理 API Key 共享 / 滥用检测相关请求
+type APIKeyAbuseHandler struct {
+	abuseService *service.APIKeyAbuseService
+}
+
+// NewAPIKeyAbuseHandler 创建 API Key 滥用检测处理器
+func NewAPIKeyAbuseHandler(abuseService *service.APIKeyAbuseService) *APIKeyAbuseHandler {
+	return &APIKeyAbuseHandler{abuseService: abuseService}
+}
+
+// GetSettings 获取滥用检测配置
+// GET /api/v1/admin/api-key-abuse/settings
+func (h *APIKeyAbuseHandler) GetSettings(c *gin.Context) {
+	settings, err := h.abuseService.GetSettings(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// UpdateSettings 更新滥用检测配置
+// PUT /api/v1/admin/api-key-abuse/settings
+func (h *APIKeyAbuseHandler) UpdateSettings(c *gin.Context) {
+	var req service.APIKeyAbuseSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.abuseService.UpdateSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// ListFlags 分页列出可疑 Key 标记
+// GET /api/v1/admin/api-key-abuse/flags?status=open&user_id=1&api_key_id=2
+func (h *APIKeyAbuseHandler) ListFlags(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.APIKeyAbuseFlagFilter{Status: strings.TrimSpace(c.Query("status"))}
+	if raw := strings.TrimSpace(c.Query("user_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+	if raw := strings.TrimSpace(c.Query("api_key_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid api_key_id")
+			return
+		}
+		filter.APIKeyID = id
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	flags, result, err := h.abuseService.ListFlags(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, flags, result.Total, page, pageSize)
+}
+
+// GetFlag 获取标记详情（含分项得分与主要来源 IP / User-Agent）
+// GET /api/v1/admin/api-key-abuse/flags/:id
+func (h *APIKeyAbuseHandler) GetFlag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid flag ID")
+		return
+	}
+
+	detail, err := h.abuseService.GetFlagDetail(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, detail)
+}
+
+// ReviewFlag 处置标记：dismiss / restore / rate_limit / suspend
+// POST /api/v1/admin/api-key-abuse/flags/:id/action
+func (h *APIKeyAbuseHandler) ReviewFlag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid flag ID")
+		return
+	}
+
+	var req service.APIKeyAbuseReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	flag, err := h.abuseService.Review(c.Request.Context(), id, &req, opsActorUserID(c))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, flag)
+}
+
+// Scan 立即执行一次检测
+// POST /api/v1/admin/api-key-abuse/scan
+func (h *APIKeyAbuseHandler) Scan(c *gin.Context) {
+	result, err := h.abuseService.Scan(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
@@ -50,6 +50,12 @@ type CreateGroupRequest struct {
 	MCPXMLInject                 *bool   `json:"mcp_xml_inject"`
 	// 支持的模型系列（仅 antigravity 平台使用）
 	SupportedModelScopes []string `json:"supported_model_scopes"`
+	// 对冲请求配置
+	HedgeEnabled  bool     `json:"hedge_enabled"`
+	HedgeDelayMs  *int     `json:"hedge_delay_ms" binding:"omitempty,min=100,max=60000"`
+	HedgeMaxRatio *float64 `json:"hedge_max_ratio" binding:"omitempty,min=0,max=1"`
+	// 调度时间窗口（windows 为空表示不限制）
+	ScheduleRule *service.ScheduleRule `json:"schedule_rule"`
 	// 从指定分组复制账号（创建后自动绑定）
 	CopyAccountsFromGroupIDs []int64 `json:"copy_accounts_from_group_ids"`
 }
@@ -82,6 +88,12 @@ type UpdateGroupRequest struct {
 	MCPXMLInject                 *bool    `json:"mcp_xml_inject"`
 	// 支持的模型系列（仅 antigravity 平台使用）
 	SupportedModelScopes *[]string `json:"supported_model_scopes"`
+	// 对冲请求配置
+	HedgeEnabled  *bool    `json:"hedge_enabled"`
+	HedgeDelayMs  *int     `json:"hedge_delay_ms" binding:"omitempty,min=100,max=60000"`
+	HedgeMaxRatio *float64 `json:"hedge_max_ratio" binding:"omitempty,min=0,max=1"`
+	// 调度时间窗口（windows 为空表示不限制）
+	ScheduleRule *service.ScheduleRule `json:"schedule_rule"`
 	// 从指定分组复制账号（同步操作：先清空当前分组的账号绑定，再绑定源分组的账号）
 	CopyAccountsFromGroupIDs []int64 `json:"copy_accounts_from_group_ids"`
 }
@@ -194,6 +206,10 @@ Name:                            req.Name,
 		CacheReadTransferProbability:    req.CacheReadTransferProbability,
 		MCPXMLInject:                    req.MCPXMLInject,
 		SupportedModelScopes:            req.SupportedModelScopes,
+		HedgeEnabled:                    req.HedgeEnabled,
+		HedgeDelayMs:                    req.HedgeDelayMs,
+		HedgeMaxRatio:                   req.HedgeMaxRatio,
+		ScheduleRule:                    req.ScheduleRule,
 		CopyAccountsFromGroupIDs:        req.CopyAccountsFromGroupIDs,
 	})
 	if err != nil {
@@ -242,6 +258,10 @@ Name:                            req.Name,
 		CacheReadTransferProbability:    req.CacheReadTransferProbability,
 		MCPXMLInject:                    req.MCPXMLInject,
 		SupportedModelScopes:            req.SupportedModelScopes,
+		HedgeEnabled:                    req.HedgeEnabled,
+		HedgeDelayMs:                    req.HedgeDelayMs,
+		HedgeMaxRatio:                   req.HedgeMaxRatio,
+		ScheduleRule:                    req.ScheduleRule,
 		CopyAccountsFromGroupIDs:        req.CopyAccountsFromGroupIDs,
 	})
 	if err != nil {
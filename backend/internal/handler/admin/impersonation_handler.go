@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler handles admin "act as user" sessions and their audit trail
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new admin impersonation handler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// StartImpersonationRequest represents the request to impersonate a user
+type StartImpersonationRequest struct {
+	Reason          string `json:"reason" binding:"required"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+// Start handles issuing an impersonation token for a user
+// POST /api/v1/admin/users/:id/impersonate
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	adminID := opsActorUserID(c)
+	if adminID == nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.impersonationService.Start(c.Request.Context(), *adminID, userID, req.Reason, req.DurationMinutes, ip.GetClientIP(c), c.GetHeader("User-Agent"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// List handles listing impersonation sessions
+// GET /api/v1/admin/impersonations?admin_id=1&user_id=2&active=true
+func (h *ImpersonationHandler) List(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.ImpersonationSessionFilter{ActiveOnly: c.Query("active") == "true"}
+	if raw := c.Query("admin_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid admin_id")
+			return
+		}
+		filter.AdminUserID = id
+	}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.TargetUserID = id
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.impersonationService.ListSessions(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// ListActions handles listing requests made during an impersonation session
+// GET /api/v1/admin/impersonations/:id/actions
+func (h *ImpersonationHandler) ListActions(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.impersonationService.ListActions(c.Request.Context(), sessionID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// End handles force-ending an impersonation session
+// POST /api/v1/admin/impersonations/:id/end
+func (h *ImpersonationHandler) End(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID")
+		return
+	}
+
+	var actorID int64
+	if id := opsActorUserID(c); id != nil {
+		actorID = *id
+	}
+	session, err := h.impersonationService.End(c.Request.Context(), sessionID, actorID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, session)
+}
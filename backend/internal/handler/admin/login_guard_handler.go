@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginGuardHandler handles admin inspection and release of login lockouts
+type LoginGuardHandler struct {
+	loginGuard *service.LoginGuardService
+}
+
+// NewLoginGuardHandler creates a new admin login guard handler
+func NewLoginGuardHandler(loginGuard *service.LoginGuardService) *LoginGuardHandler {
+	return &LoginGuardHandler{loginGuard: loginGuard}
+}
+
+// UnlockLoginRequest represents the unlock request payload
+type UnlockLoginRequest struct {
+	Scope string `json:"scope" binding:"required,oneof=account ip"`
+	Key   string `json:"key" binding:"required"`
+}
+
+// ListLocks handles listing currently locked accounts and IPs
+// GET /api/v1/admin/login-locks
+func (h *LoginGuardHandler) ListLocks(c *gin.Context) {
+	locks, err := h.loginGuard.ListLocks(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, locks)
+}
+
+// Unlock handles releasing a locked account or IP
+// POST /api/v1/admin/login-locks/unlock
+func (h *LoginGuardHandler) Unlock(c *gin.Context) {
+	var req UnlockLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.loginGuard.Unlock(c.Request.Context(), req.Scope, req.Key); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Unlocked successfully"})
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCProviderHandler 处理通用 OIDC 登录提供方的管理请求
+type OIDCProviderHandler struct {
+	oidcService *service.OIDCService
+}
+
+// NewOIDCProviderHandler 创建 OIDC 登录提供方管理处理器
+func NewOIDCProviderHandler(oidcService *service.OIDCService) *OIDCProviderHandler {
+	return &OIDCProviderHandler{oidcService: oidcService}
+}
+
+// List 列出全部提供方（不返回 client_secret）
+// GET /api/v1/admin/settings/oidc-providers
+func (h *OIDCProviderHandler) List(c *gin.Context) {
+	providers, err := h.oidcService.ListProviders(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, providers)
+}
+
+// Create 新增提供方
+// POST /api/v1/admin/settings/oidc-providers
+func (h *OIDCProviderHandler) Create(c *gin.Context) {
+	var req service.OIDCProviderConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	provider, err := h.oidcService.CreateProvider(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, provider)
+}
+
+// Update 更新提供方（client_secret 留空表示不修改）
+// PUT /api/v1/admin/settings/oidc-providers/:slug
+func (h *OIDCProviderHandler) Update(c *gin.Context) {
+	var req service.OIDCProviderConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	provider, err := h.oidcService.UpdateProvider(c.Request.Context(), c.Param("slug"), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, provider)
+}
+
+// Delete 删除提供方
+// DELETE /api/v1/admin/settings/oidc-providers/:slug
+func (h *OIDCProviderHandler) Delete(c *gin.Context) {
+	if err := h.oidcService.DeleteProvider(c.Request.Context(), c.Param("slug")); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "OIDC provider deleted successfully"})
+}
@@ -23,6 +23,21 @@ var validOpsAlertMetricTypes = []string{
 	"cpu_usage_percent",
 	"memory_usage_percent",
 	"concurrency_queue_depth",
+	"account_probe_failed_count",
+	"duration_p50_ms",
+	"duration_p90_ms",
+	"duration_p95_ms",
+	"duration_p99_ms",
+	"first_token_p50_ms",
+	"first_token_p90_ms",
+	"first_token_p95_ms",
+	"first_token_p99_ms",
+	"requests_per_minute",
+	"tokens_per_minute",
+	"spend_usd",
+	"account_error_rate",
+	service.OpsAlertMetricSLOBurnRate,
+	service.OpsAlertMetricSLOErrorBudgetRemaining,
 }
 
 var validOpsAlertMetricTypeSet = func() map[string]struct{} {
@@ -33,7 +48,7 @@ var validOpsAlertMetricTypeSet = func() map[string]struct{} {
 	return set
 }()
 
-var validOpsAlertOperators = []string{">", "<", ">=", "<=", "==", "!="}
+var validOpsAlertOperators = []string{">", "<", ">=", "<=", "==", "!=", service.OpsAlertOperatorAnomalyAbove, service.OpsAlertOperatorAnomalyBelow}
 
 var validOpsAlertOperatorSet = func() map[string]struct{} {
 	set := make(map[string]struct{}, len(validOpsAlertOperators))
@@ -68,6 +83,9 @@ type opsAlertRuleValidatedInput struct {
 	Enabled     bool
 	NotifyEmail bool
 
+	AnomalyMethod string
+	BaselineDays  int
+
 	WindowProvided    bool
 	SustainedProvided bool
 	CooldownProvided  bool
@@ -81,6 +99,8 @@ func isPercentOrRateMetric(metricType string) bool {
 	case "success_rate",
 		"error_rate",
 		"upstream_error_rate",
+		"account_error_rate",
+		service.OpsAlertMetricSLOErrorBudgetRemaining,
 		"cpu_usage_percent",
 		"memory_usage_percent":
 		return true
@@ -132,7 +152,15 @@ func validateOpsAlertRulePayload(raw map[string]json.RawMessage) (*opsAlertRuleV
 	if math.IsNaN(threshold) || math.IsInf(threshold, 0) {
 		return nil, fmt.Errorf("threshold must be a finite number")
 	}
-	if isPercentOrRateMetric(metricType) {
+	isAnomaly := service.IsOpsAlertAnomalyOperator(operator)
+	if isAnomaly {
+		if !service.OpsAlertMetricSupportsBaseline(metricType) {
+			return nil, fmt.Errorf("metric_type %s does not support anomaly operators", metricType)
+		}
+		if threshold <= 0 {
+			return nil, fmt.Errorf("threshold must be > 0 for anomaly operators")
+		}
+	} else if isPercentOrRateMetric(metricType) {
 		if threshold < 0 || threshold > 100 {
 			return nil, fmt.Errorf("threshold must be between 0 and 100 for metric_type %s", metricType)
 		}
@@ -147,6 +175,51 @@ func validateOpsAlertRulePayload(raw map[string]json.RawMessage) (*opsAlertRuleV
 		Threshold:  threshold,
 	}
 
+	if isAnomaly {
+		validated.AnomalyMethod = service.OpsAlertAnomalyMethodZScore
+		if v, ok := raw["anomaly_method"]; ok {
+			var method string
+			if err := json.Unmarshal(v, &method); err != nil {
+				return nil, fmt.Errorf("anomaly_method must be a string")
+			}
+			switch method = strings.ToLower(strings.TrimSpace(method)); method {
+			case "":
+			case service.OpsAlertAnomalyMethodZScore, service.OpsAlertAnomalyMethodPercent:
+				validated.AnomalyMethod = method
+			default:
+				return nil, fmt.Errorf("anomaly_method must be one of: %s, %s", service.OpsAlertAnomalyMethodZScore, service.OpsAlertAnomalyMethodPercent)
+			}
+		}
+
+		validated.BaselineDays = service.OpsAlertBaselineDaysDefault
+		if v, ok := raw["baseline_days"]; ok {
+			if err := json.Unmarshal(v, &validated.BaselineDays); err != nil {
+				return nil, fmt.Errorf("baseline_days must be an integer")
+			}
+			if validated.BaselineDays < 1 || validated.BaselineDays > service.OpsAlertBaselineDaysMax {
+				return nil, fmt.Errorf("baseline_days must be between 1 and %d", service.OpsAlertBaselineDaysMax)
+			}
+		}
+
+		if v, ok := raw["filters"]; ok {
+			var filters map[string]any
+			if err := json.Unmarshal(v, &filters); err == nil {
+				if _, ok := filters["user_id"]; ok {
+					return nil, fmt.Errorf("anomaly operators do not support user_id filters")
+				}
+				if _, ok := filters["account_id"]; ok {
+					return nil, fmt.Errorf("anomaly operators do not support account_id filters")
+				}
+			}
+		}
+	}
+
+	if service.IsOpsAlertSLOMetric(metricType) {
+		if err := validateOpsAlertSLOFilters(raw["filters"]); err != nil {
+			return nil, err
+		}
+	}
+
 	if v, ok := raw["severity"]; ok {
 		validated.SeverityProvided = true
 		var sev string
@@ -224,6 +297,46 @@ func validateOpsAlertRulePayload(raw map[string]json.RawMessage) (*opsAlertRuleV
 	return validated, nil
 }
 
+// validateOpsAlertSLOFilters checks the filters of SLO-based metrics:
+// slo_id is required, objective is availability/latency, short_window_minutes is optional.
+func validateOpsAlertSLOFilters(raw json.RawMessage) error {
+	var filters map[string]any
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &filters); err != nil {
+			return fmt.Errorf("filters must be an object")
+		}
+	}
+	positiveInt := func(key string) (int64, bool, error) {
+		v, ok := filters[key]
+		if !ok || v == nil {
+			return 0, false, nil
+		}
+		n, isNum := v.(float64)
+		if !isNum || n <= 0 || n != math.Trunc(n) {
+			return 0, true, fmt.Errorf("filters.%s must be a positive integer", key)
+		}
+		return int64(n), true, nil
+	}
+
+	if _, ok, err := positiveInt("slo_id"); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("filters.slo_id is required for SLO metrics")
+	}
+	if v, ok := filters["objective"]; ok && v != nil {
+		objective, _ := v.(string)
+		switch strings.TrimSpace(objective) {
+		case "", service.OpsSLOObjectiveAvailability, service.OpsSLOObjectiveLatency:
+		default:
+			return fmt.Errorf("filters.objective must be one of: %s, %s", service.OpsSLOObjectiveAvailability, service.OpsSLOObjectiveLatency)
+		}
+	}
+	if _, _, err := positiveInt("short_window_minutes"); err != nil {
+		return err
+	}
+	return nil
+}
+
 // ListAlertRules returns all ops alert rules.
 // GET /api/v1/admin/ops/alert-rules
 func (h *OpsHandler) ListAlertRules(c *gin.Context) {
@@ -283,6 +396,8 @@ func (h *OpsHandler) CreateAlertRule(c *gin.Context) {
 	rule.Severity = validated.Severity
 	rule.Enabled = validated.Enabled
 	rule.NotifyEmail = validated.NotifyEmail
+	rule.AnomalyMethod = validated.AnomalyMethod
+	rule.BaselineDays = validated.BaselineDays
 
 	created, err := h.opsService.CreateAlertRule(c.Request.Context(), &rule)
 	if err != nil {
@@ -338,6 +453,8 @@ func (h *OpsHandler) UpdateAlertRule(c *gin.Context) {
 	rule.Severity = validated.Severity
 	rule.Enabled = validated.Enabled
 	rule.NotifyEmail = validated.NotifyEmail
+	rule.AnomalyMethod = validated.AnomalyMethod
+	rule.BaselineDays = validated.BaselineDays
 
 	updated, err := h.opsService.UpdateAlertRule(c.Request.Context(), &rule)
 	if err != nil {
@@ -438,13 +555,92 @@ func (h *OpsHandler) UpdateAlertEventStatus(c *gin.Context) {
 		now := time.Now().UTC()
 		resolvedAt = &now
 	}
-	if err := h.opsService.UpdateAlertEventStatus(c.Request.Context(), id, payload.Status, resolvedAt); err != nil {
+	var actorID *int64
+	if subject, ok := middleware.GetAuthSubjectFromContext(c); ok {
+		uid := subject.UserID
+		actorID = &uid
+	}
+	if err := h.opsService.UpdateAlertEventStatus(c.Request.Context(), id, payload.Status, resolvedAt, actorID); err != nil {
 		response.ErrorFrom(c, err)
 		return
 	}
 	response.Success(c, gin.H{"updated": true})
 }
 
+// AcknowledgeAlertEvent acknowledges a firing ops alert event (stops escalation and repeat notifications).
+// POST /api/v1/admin/ops/alert-events/:id/ack
+func (h *OpsHandler) AcknowledgeAlertEvent(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var payload struct {
+		Assignee string `json:"assignee"`
+		Note     string `json:"note"`
+	}
+	// Body is optional: an empty ack is allowed.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			response.BadRequest(c, "Invalid request body")
+			return
+		}
+	}
+
+	input := &service.OpsAlertEventAckInput{
+		EventID:  id,
+		Assignee: strings.TrimSpace(payload.Assignee),
+		Note:     strings.TrimSpace(payload.Note),
+	}
+	if subject, ok := middleware.GetAuthSubjectFromContext(c); ok {
+		uid := subject.UserID
+		input.AcknowledgedBy = &uid
+	}
+
+	ev, err := h.opsService.AcknowledgeAlertEvent(c.Request.Context(), input)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, ev)
+}
+
+// ListAlertEventTimeline returns the state changes and notifications of an ops alert event.
+// GET /api/v1/admin/ops/alert-events/:id/timeline
+func (h *OpsHandler) ListAlertEventTimeline(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	entries, err := h.opsService.ListAlertEventTimeline(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, entries)
+}
+
 // ListAlertEvents lists recent ops alert events.
 // GET /api/v1/admin/ops/alert-events
 // CreateAlertSilence creates a scoped silence for ops alerts.
@@ -0,0 +1,156 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetRequestCaptureSettings returns the opt-in request/response capture rules.
+// GET /api/v1/admin/ops/request-capture/settings
+func (h *OpsHandler) GetRequestCaptureSettings(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	cfg, err := h.opsService.GetRequestCaptureSettings(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, cfg)
+}
+
+// UpdateRequestCaptureSettings replaces the capture rules.
+// PUT /api/v1/admin/ops/request-capture/settings
+func (h *OpsHandler) UpdateRequestCaptureSettings(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	var req service.OpsRequestCaptureSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updated, err := h.opsService.UpdateRequestCaptureSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// ListRequestCaptures lists captured requests (bodies omitted).
+// GET /api/v1/admin/ops/request-captures
+func (h *OpsHandler) ListRequestCaptures(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	filter := &service.OpsRequestCaptureFilter{
+		Page:            page,
+		PageSize:        pageSize,
+		RequestID:       strings.TrimSpace(c.Query("request_id")),
+		ClientRequestID: strings.TrimSpace(c.Query("client_request_id")),
+	}
+
+	// Lookups by request id (e.g. from a usage log row) ignore the time window.
+	if filter.RequestID == "" && filter.ClientRequestID == "" {
+		startTime, endTime, err := parseOpsTimeRange(c, "24h")
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		filter.StartTime = &startTime
+		filter.EndTime = &endTime
+	}
+
+	if v := strings.TrimSpace(c.Query("user_id")); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = &id
+	}
+	if v := strings.TrimSpace(c.Query("api_key_id")); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid api_key_id")
+			return
+		}
+		filter.APIKeyID = &id
+	}
+	if v := strings.TrimSpace(c.Query("account_id")); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid account_id")
+			return
+		}
+		filter.AccountID = &id
+	}
+
+	out, err := h.opsService.ListRequestCaptures(c.Request.Context(), filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, out.Items, int64(out.Total), out.Page, out.PageSize)
+}
+
+// GetRequestCapture returns a capture with its request/response bodies.
+// GET /api/v1/admin/ops/request-captures/:id
+func (h *OpsHandler) GetRequestCapture(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid capture ID")
+		return
+	}
+
+	capture, err := h.opsService.GetRequestCaptureByID(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, capture)
+}
+
+// DeleteRequestCapture deletes a capture.
+// DELETE /api/v1/admin/ops/request-captures/:id
+func (h *OpsHandler) DeleteRequestCapture(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid capture ID")
+		return
+	}
+
+	if err := h.opsService.DeleteRequestCapture(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": true})
+}
@@ -0,0 +1,154 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type opsShadowRuleRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     *bool  `json:"enabled"`
+
+	GroupID         int64      `json:"group_id"`
+	ShadowAccountID int64      `json:"shadow_account_id"`
+	SampleRate      float64    `json:"sample_rate"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+}
+
+func (r *opsShadowRuleRequest) toRule(id int64) *service.OpsShadowRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return &service.OpsShadowRule{
+		ID:              id,
+		Name:            r.Name,
+		Description:     r.Description,
+		Enabled:         enabled,
+		GroupID:         r.GroupID,
+		ShadowAccountID: r.ShadowAccountID,
+		SampleRate:      r.SampleRate,
+		ExpiresAt:       r.ExpiresAt,
+	}
+}
+
+// ListShadowRules returns all traffic shadowing rules.
+// GET /api/v1/admin/ops/shadow-rules
+func (h *OpsHandler) ListShadowRules(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	rules, err := h.opsService.ListShadowRules(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, rules)
+}
+
+// CreateShadowRule creates a traffic shadowing rule.
+// POST /api/v1/admin/ops/shadow-rules
+func (h *OpsHandler) CreateShadowRule(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	var req opsShadowRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	created, err := h.opsService.CreateShadowRule(c.Request.Context(), req.toRule(0))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, created)
+}
+
+// UpdateShadowRule replaces a traffic shadowing rule.
+// PUT /api/v1/admin/ops/shadow-rules/:id
+func (h *OpsHandler) UpdateShadowRule(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid shadow rule ID")
+		return
+	}
+
+	var req opsShadowRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updated, err := h.opsService.UpdateShadowRule(c.Request.Context(), req.toRule(id))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeleteShadowRule deletes a traffic shadowing rule.
+// DELETE /api/v1/admin/ops/shadow-rules/:id
+func (h *OpsHandler) DeleteShadowRule(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid shadow rule ID")
+		return
+	}
+
+	if err := h.opsService.DeleteShadowRule(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": true})
+}
+
+// GetShadowReport compares shadow and primary responses of a rule.
+// GET /api/v1/admin/ops/shadow-rules/:id/report
+func (h *OpsHandler) GetShadowReport(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid shadow rule ID")
+		return
+	}
+
+	startTime, endTime, err := parseOpsTimeRange(c, "24h")
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	report, err := h.opsService.GetShadowReport(c.Request.Context(), id, startTime, endTime)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, report)
+}
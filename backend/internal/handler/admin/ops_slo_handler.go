@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type opsSLORequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     *bool  `json:"enabled"`
+
+	Platform string `json:"platform"`
+	GroupID  *int64 `json:"group_id"`
+
+	AvailabilityTarget   float64  `json:"availability_target"`
+	LatencyTargetMs      *int     `json:"latency_target_ms"`
+	LatencyTargetPercent *float64 `json:"latency_target_percent"`
+
+	WindowDays int `json:"window_days"`
+}
+
+func (r *opsSLORequest) toSLO(id int64) *service.OpsSLO {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return &service.OpsSLO{
+		ID:                   id,
+		Name:                 r.Name,
+		Description:          r.Description,
+		Enabled:              enabled,
+		Platform:             r.Platform,
+		GroupID:              r.GroupID,
+		AvailabilityTarget:   r.AvailabilityTarget,
+		LatencyTargetMs:      r.LatencyTargetMs,
+		LatencyTargetPercent: r.LatencyTargetPercent,
+		WindowDays:           r.WindowDays,
+	}
+}
+
+// ListSLOs returns all SLO definitions.
+// GET /api/v1/admin/ops/slos
+func (h *OpsHandler) ListSLOs(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	slos, err := h.opsService.ListSLOs(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, slos)
+}
+
+// CreateSLO creates an SLO definition.
+// POST /api/v1/admin/ops/slos
+func (h *OpsHandler) CreateSLO(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	var req opsSLORequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	created, err := h.opsService.CreateSLO(c.Request.Context(), req.toSLO(0))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, created)
+}
+
+// UpdateSLO replaces an SLO definition.
+// PUT /api/v1/admin/ops/slos/:id
+func (h *OpsHandler) UpdateSLO(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid SLO ID")
+		return
+	}
+
+	var req opsSLORequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updated, err := h.opsService.UpdateSLO(c.Request.Context(), req.toSLO(id))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeleteSLO deletes an SLO definition.
+// DELETE /api/v1/admin/ops/slos/:id
+func (h *OpsHandler) DeleteSLO(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid SLO ID")
+		return
+	}
+
+	if err := h.opsService.DeleteSLO(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": true})
+}
+
+// ListSLOStatuses returns error budget and burn rate for all enabled SLOs.
+// GET /api/v1/admin/ops/slos/status
+func (h *OpsHandler) ListSLOStatuses(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	statuses, err := h.opsService.ListSLOStatuses(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, statuses)
+}
+
+// GetSLOStatus returns error budget and burn rate for one SLO.
+// GET /api/v1/admin/ops/slos/:id/status
+func (h *OpsHandler) GetSLOStatus(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid SLO ID")
+		return
+	}
+
+	status, err := h.opsService.GetSLOStatus(c.Request.Context(), id, time.Now().UTC())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, status)
+}
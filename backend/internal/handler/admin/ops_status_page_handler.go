@@ -0,0 +1,213 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetStatusPageSettings returns the public status page config.
+// GET /api/v1/admin/ops/status-page/settings
+func (h *OpsHandler) GetStatusPageSettings(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	cfg, err := h.opsService.GetStatusPageSettings(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to get status page settings")
+		return
+	}
+	response.Success(c, cfg)
+}
+
+// UpdateStatusPageSettings updates the public status page config.
+// PUT /api/v1/admin/ops/status-page/settings
+func (h *OpsHandler) UpdateStatusPageSettings(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	var req service.OpsStatusPageSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updated, err := h.opsService.UpdateStatusPageSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// ListStatusIncidents lists admin-authored status page incidents (with their updates).
+// GET /api/v1/admin/ops/status-incidents
+func (h *OpsHandler) ListStatusIncidents(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	incidents, err := h.opsService.ListStatusIncidents(c.Request.Context(), limit)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, incidents)
+}
+
+// CreateStatusIncident publishes a new incident with its first update.
+// POST /api/v1/admin/ops/status-incidents
+func (h *OpsHandler) CreateStatusIncident(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	var req service.OpsStatusIncidentInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	created, err := h.opsService.CreateStatusIncident(c.Request.Context(), &req, opsActorUserID(c))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, created)
+}
+
+// UpdateStatusIncident edits an incident's title, impact and affected platforms.
+// PUT /api/v1/admin/ops/status-incidents/:id
+func (h *OpsHandler) UpdateStatusIncident(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid incident ID")
+		return
+	}
+
+	var req service.OpsStatusIncidentInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updated, err := h.opsService.UpdateStatusIncident(c.Request.Context(), id, &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// AddStatusIncidentUpdate posts a timeline update (and status change) to an incident.
+// POST /api/v1/admin/ops/status-incidents/:id/updates
+func (h *OpsHandler) AddStatusIncidentUpdate(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid incident ID")
+		return
+	}
+
+	var req service.OpsStatusIncidentUpdateInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updated, err := h.opsService.AddStatusIncidentUpdate(c.Request.Context(), id, &req, opsActorUserID(c))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeleteStatusIncident deletes an incident and its updates.
+// DELETE /api/v1/admin/ops/status-incidents/:id
+func (h *OpsHandler) DeleteStatusIncident(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid incident ID")
+		return
+	}
+
+	if err := h.opsService.DeleteStatusIncident(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": true})
+}
+
+// SetAlertEventPublic shows or hides an alert event on the public status page.
+// PUT /api/v1/admin/ops/alert-events/:id/public
+func (h *OpsHandler) SetAlertEventPublic(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	var req struct {
+		IsPublic      bool   `json:"is_public"`
+		PublicSummary string `json:"public_summary"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	ev, err := h.opsService.SetAlertEventPublic(c.Request.Context(), id, req.IsPublic, req.PublicSummary)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, ev)
+}
+
+func opsActorUserID(c *gin.Context) *int64 {
+	if subject, ok := middleware.GetAuthSubjectFromContext(c); ok {
+		uid := subject.UserID
+		return &uid
+	}
+	return nil
+}
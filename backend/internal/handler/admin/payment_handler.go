@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler 处理在线支付订单查询、退款与支付配置
+type PaymentHandler struct {
+	paymentService *service.PaymentService
+}
+
+// NewPaymentHandler 创建在线支付管理处理器
+func NewPaymentHandler(paymentService *service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// PaymentRefundRequest 退款请求
+type PaymentRefundRequest struct {
+	// Amount 支付币种金额，0 表示退还剩余全部
+	Amount float64 `json:"amount" binding:"gte=0"`
+	Reason string  `json:"reason" binding:"max=200"`
+	// Offline 仅记录退款并扣回余额，不调用支付商退款接口
+	Offline bool `json:"offline"`
+}
+
+// ListOrders 分页列出充值订单
+// GET /api/v1/admin/payments/orders?user_id=1&status=paid&provider=stripe&search=foo
+func (h *PaymentHandler) ListOrders(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.PaymentOrderFilter{
+		Status:   strings.TrimSpace(c.Query("status")),
+		Provider: strings.TrimSpace(c.Query("provider")),
+		Search:   strings.TrimSpace(c.Query("search")),
+	}
+	if len(filter.Search) > 100 {
+		filter.Search = filter.Search[:100]
+	}
+	if raw := strings.TrimSpace(c.Query("user_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.paymentService.ListOrders(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// Refund 退款并按比例扣回到账余额
+// POST /api/v1/admin/payments/orders/:order_no/refund
+func (h *PaymentHandler) Refund(c *gin.Context) {
+	var req PaymentRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	order, err := h.paymentService.Refund(c.Request.Context(), c.Param("order_no"), req.Amount, strings.TrimSpace(req.Reason), req.Offline)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, order)
+}
+
+// GetSettings 获取支付配置（密钥已隐藏）
+// GET /api/v1/admin/payments/settings
+func (h *PaymentHandler) GetSettings(c *gin.Context) {
+	settings, err := h.paymentService.GetMaskedSettings(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// UpdateSettings 更新支付配置；密钥留空表示保持不变
+// PUT /api/v1/admin/payments/settings
+func (h *PaymentHandler) UpdateSettings(c *gin.Context) {
+	var req service.PaymentSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.paymentService.UpdateSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
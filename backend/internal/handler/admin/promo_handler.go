@@ -27,11 +27,21 @@ func NewPromoHandler(promoService *service.PromoService) *PromoHandler {
 
 // CreatePromoCodeRequest represents create promo code request
 type CreatePromoCodeRequest struct {
-	Code        string  `json:"code"`                                  // 可选，为空则自动生成
-	BonusAmount float64 `json:"bonus_amount" binding:"required,min=0"` // 赠送余额
-	MaxUses     int     `json:"max_uses" binding:"min=0"`              // 最大使用次数，0=无限
-	ExpiresAt   *int64  `json:"expires_at"`                            // 过期时间戳（秒）
-	Notes       string  `json:"notes"`                                 // 备注
+	Code        string  `json:"code"`                         // 可选，为空则自动生成
+	BonusAmount float64 `json:"bonus_amount" binding:"min=0"` // 赠送余额（fixed）
+	MaxUses     int     `json:"max_uses" binding:"min=0"`     // 最大使用次数，0=无限
+	ExpiresAt   *int64  `json:"expires_at"`                   // 过期时间戳（秒）
+	Notes       string  `json:"notes"`                        // 备注
+
+	BonusType       string  `json:"bonus_type" binding:"omitempty,oneof=fixed percent"`
+	BonusPercent    float64 `json:"bonus_percent" binding:"min=0,max=1000"`   // 下次充值赠送百分比（percent）
+	MaxBonusAmount  float64 `json:"max_bonus_amount" binding:"min=0"`         // 单次比例赠送上限，0=不限
+	PerUserLimit    *int    `json:"per_user_limit" binding:"omitempty,min=0"` // 每用户使用次数，默认1，0=无限
+	Scope           string  `json:"scope" binding:"omitempty,oneof=register panel any"`
+	NewUsersOnly    bool    `json:"new_users_only"`
+	NewUserDays     int     `json:"new_user_days" binding:"min=0"`               // 注册后N天内可在面板使用
+	RequiredGroupID *int64  `json:"required_group_id" binding:"omitempty,min=1"` // 需要持有的订阅分组
+	StartsAt        *int64  `json:"starts_at"`                                   // 生效时间戳（秒）
 }
 
 // UpdatePromoCodeRequest represents update promo code request
@@ -42,6 +52,16 @@ type UpdatePromoCodeRequest struct {
 	Status      *string  `json:"status" binding:"omitempty,oneof=active disabled"`
 	ExpiresAt   *int64   `json:"expires_at"`
 	Notes       *string  `json:"notes"`
+
+	BonusType       *string  `json:"bonus_type" binding:"omitempty,oneof=fixed percent"`
+	BonusPercent    *float64 `json:"bonus_percent" binding:"omitempty,min=0,max=1000"`
+	MaxBonusAmount  *float64 `json:"max_bonus_amount" binding:"omitempty,min=0"`
+	PerUserLimit    *int     `json:"per_user_limit" binding:"omitempty,min=0"`
+	Scope           *string  `json:"scope" binding:"omitempty,oneof=register panel any"`
+	NewUsersOnly    *bool    `json:"new_users_only"`
+	NewUserDays     *int     `json:"new_user_days" binding:"omitempty,min=0"`
+	RequiredGroupID *int64   `json:"required_group_id" binding:"omitempty,min=0"` // 0=清除
+	StartsAt        *int64   `json:"starts_at"`                                   // 0=清除
 }
 
 // List handles listing all promo codes with pagination
@@ -99,17 +119,35 @@ func (h *PromoHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if req.BonusType != service.PromoBonusTypePercent && req.BonusAmount <= 0 {
+		response.BadRequest(c, "bonus_amount must be greater than 0")
+		return
+	}
+
 	input := &service.CreatePromoCodeInput{
 		Code:        req.Code,
 		BonusAmount: req.BonusAmount,
 		MaxUses:     req.MaxUses,
 		Notes:       req.Notes,
+
+		BonusType:       req.BonusType,
+		BonusPercent:    req.BonusPercent,
+		MaxBonusAmount:  req.MaxBonusAmount,
+		PerUserLimit:    req.PerUserLimit,
+		Scope:           req.Scope,
+		NewUsersOnly:    req.NewUsersOnly,
+		NewUserDays:     req.NewUserDays,
+		RequiredGroupID: req.RequiredGroupID,
 	}
 
 	if req.ExpiresAt != nil {
 		t := time.Unix(*req.ExpiresAt, 0)
 		input.ExpiresAt = &t
 	}
+	if req.StartsAt != nil && *req.StartsAt > 0 {
+		t := time.Unix(*req.StartsAt, 0)
+		input.StartsAt = &t
+	}
 
 	code, err := h.promoService.Create(c.Request.Context(), input)
 	if err != nil {
@@ -141,6 +179,15 @@ func (h *PromoHandler) Update(c *gin.Context) {
 		MaxUses:     req.MaxUses,
 		Status:      req.Status,
 		Notes:       req.Notes,
+
+		BonusType:       req.BonusType,
+		BonusPercent:    req.BonusPercent,
+		MaxBonusAmount:  req.MaxBonusAmount,
+		PerUserLimit:    req.PerUserLimit,
+		Scope:           req.Scope,
+		NewUsersOnly:    req.NewUsersOnly,
+		NewUserDays:     req.NewUserDays,
+		RequiredGroupID: req.RequiredGroupID,
 	}
 
 	if req.ExpiresAt != nil {
@@ -152,6 +199,15 @@ func (h *PromoHandler) Update(c *gin.Context) {
 			input.ExpiresAt = &t
 		}
 	}
+	if req.StartsAt != nil {
+		if *req.StartsAt == 0 {
+			// 0 表示清除生效时间
+			input.ClearStartsAt = true
+		} else {
+			t := time.Unix(*req.StartsAt, 0)
+			input.StartsAt = &t
+		}
+	}
 
 	code, err := h.promoService.Update(c.Request.Context(), codeID, input)
 	if err != nil {
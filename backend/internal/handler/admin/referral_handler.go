@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReferralHandler 处理邀请返利配置与邀请关系审核
+type ReferralHandler struct {
+	referralService *service.ReferralService
+}
+
+// NewReferralHandler 创建邀请返利管理处理器
+func NewReferralHandler(referralService *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// GetSettings 获取配置
+// GET /api/v1/admin/referrals/settings
+func (h *ReferralHandler) GetSettings(c *gin.Context) {
+	settings, err := h.referralService.GetSettings(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// UpdateSettings 更新配置
+// PUT /api/v1/admin/referrals/settings
+func (h *ReferralHandler) UpdateSettings(c *gin.Context) {
+	var req service.ReferralSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.referralService.UpdateSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// List 分页列出邀请关系
+// GET /api/v1/admin/referrals?status=held&flagged=true&referrer_id=1&search=foo
+func (h *ReferralHandler) List(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.ReferralFilter{
+		Status:  strings.TrimSpace(c.Query("status")),
+		Flagged: c.Query("flagged") == "true",
+		Search:  strings.TrimSpace(c.Query("search")),
+	}
+	if len(filter.Search) > 100 {
+		filter.Search = filter.Search[:100]
+	}
+	if raw := strings.TrimSpace(c.Query("referrer_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid referrer_id")
+			return
+		}
+		filter.ReferrerID = id
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.referralService.ListReferrals(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// ReviewReferralRequest 审核请求
+type ReviewReferralRequest struct {
+	Note string `json:"note"`
+}
+
+// Approve 审核通过，恢复发放奖励
+// POST /api/v1/admin/referrals/:id/approve
+func (h *ReferralHandler) Approve(c *gin.Context) {
+	h.review(c, h.referralService.Approve)
+}
+
+// Block 拒绝，停止发放奖励
+// POST /api/v1/admin/referrals/:id/block
+func (h *ReferralHandler) Block(c *gin.Context) {
+	h.review(c, h.referralService.Block)
+}
+
+func (h *ReferralHandler) review(c *gin.Context, action func(ctx context.Context, id, actorID int64, note string) (*service.Referral, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid referral ID")
+		return
+	}
+
+	var req ReviewReferralRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request: "+err.Error())
+			return
+		}
+	}
+
+	var actorID int64
+	if actor := opsActorUserID(c); actor != nil {
+		actorID = *actor
+	}
+	referral, err := action(c.Request.Context(), id, actorID, req.Note)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, referral)
+}
@@ -52,6 +52,7 @@ func (h *SettingHandler) GetSettings(c *gin.Context) {
 		InvitationCodeEnabled:                settings.InvitationCodeEnabled,
 		TotpEnabled:                          settings.TotpEnabled,
 		TotpEncryptionKeyConfigured:          h.settingService.IsTotpEncryptionKeyConfigured(),
+		PasskeyLoginEnabled:                  settings.PasskeyLoginEnabled,
 		SMTPHost:                             settings.SMTPHost,
 		SMTPPort:                             settings.SMTPPort,
 		SMTPUsername:                         settings.SMTPUsername,
@@ -101,7 +102,8 @@ type UpdateSettingsRequest struct {
 	PromoCodeEnabled      bool `json:"promo_code_enabled"`
 	PasswordResetEnabled  bool `json:"password_reset_enabled"`
 	InvitationCodeEnabled bool `json:"invitation_code_enabled"`
-	TotpEnabled           bool `json:"totp_enabled"` // TOTP 双因素认证
+	TotpEnabled           bool `json:"totp_enabled"`          // TOTP 双因素认证
+	PasskeyLoginEnabled   bool `json:"passkey_login_enabled"` // 通行密钥无密码登录
 
 	// 邮件服务设置
 	SMTPHost     string `json:"smtp_host"`
@@ -299,6 +301,7 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		PasswordResetEnabled:        req.PasswordResetEnabled,
 		InvitationCodeEnabled:       req.InvitationCodeEnabled,
 		TotpEnabled:                 req.TotpEnabled,
+		PasskeyLoginEnabled:         req.PasskeyLoginEnabled,
 		SMTPHost:                    req.SMTPHost,
 		SMTPPort:                    req.SMTPPort,
 		SMTPUsername:                req.SMTPUsername,
@@ -386,6 +389,7 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		InvitationCodeEnabled:                updatedSettings.InvitationCodeEnabled,
 		TotpEnabled:                          updatedSettings.TotpEnabled,
 		TotpEncryptionKeyConfigured:          h.settingService.IsTotpEncryptionKeyConfigured(),
+		PasskeyLoginEnabled:                  updatedSettings.PasskeyLoginEnabled,
 		SMTPHost:                             updatedSettings.SMTPHost,
 		SMTPPort:                             updatedSettings.SMTPPort,
 		SMTPUsername:                         updatedSettings.SMTPUsername,
@@ -461,6 +465,9 @@ func diffSettings(before *service.SystemSettings, after *service.SystemSettings,
 	if before.TotpEnabled != after.TotpEnabled {
 		changed = append(changed, "totp_enabled")
 	}
+	if before.PasskeyLoginEnabled != after.PasskeyLoginEnabled {
+		changed = append(changed, "passkey_login_enabled")
+	}
 	if before.SMTPHost != after.SMTPHost {
 		changed = append(changed, "smtp_host")
 	}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SpendGuardHandler 处理消费异常自动停用相关请求
+type SpendGuardHandler struct {
+	spendGuardService *service.SpendGuardService
+}
+
+// NewSpendGuardHandler 创建消费异常自动停用处理器
+func NewSpendGuardHandler(spendGuardService *service.SpendGuardService) *SpendGuardHandler {
+	return &SpendGuardHandler{spendGuardService: spendGuardService}
+}
+
+// GetSettings 获取配置
+// GET /api/v1/admin/spend-guard/settings
+func (h *SpendGuardHandler) GetSettings(c *gin.Context) {
+	settings, err := h.spendGuardService.GetSettings(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// UpdateSettings 更新配置
+// PUT /api/v1/admin/spend-guard/settings
+func (h *SpendGuardHandler) UpdateSettings(c *gin.Context) {
+	var req service.SpendGuardSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.spendGuardService.UpdateSettings(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// ListSuspensions 分页列出停用记录
+// GET /api/v1/admin/spend-guard/suspensions?status=active&user_id=1
+func (h *SpendGuardHandler) ListSuspensions(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.SpendSuspensionFilter{Status: strings.TrimSpace(c.Query("status"))}
+	if raw := strings.TrimSpace(c.Query("user_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.spendGuardService.ListSuspensions(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// LiftSuspensionRequest 解除停用请求
+type LiftSuspensionRequest struct {
+	Note string `json:"note"`
+}
+
+// LiftSuspension 提前解除停用
+// POST /api/v1/admin/spend-guard/suspensions/:id/lift
+func (h *SpendGuardHandler) LiftSuspension(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid suspension ID")
+		return
+	}
+
+	var req LiftSuspensionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request: "+err.Error())
+			return
+		}
+	}
+
+	suspension, err := h.spendGuardService.Lift(c.Request.Context(), id, opsActorUserID(c), req.Note)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, suspension)
+}
@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SubscriptionPlanHandler 处理订阅套餐管理与订单查询
+type SubscriptionPlanHandler struct {
+	planService *service.SubscriptionPlanService
+}
+
+// NewSubscriptionPlanHandler 创建订阅套餐管理处理器
+func NewSubscriptionPlanHandler(planService *service.SubscriptionPlanService) *SubscriptionPlanHandler {
+	return &SubscriptionPlanHandler{planService: planService}
+}
+
+// SubscriptionPlanRequest 创建 / 更新套餐请求
+type SubscriptionPlanRequest struct {
+	GroupID       int64   `json:"group_id" binding:"required"`
+	Name          string  `json:"name" binding:"required"`
+	Description   string  `json:"description"`
+	Price         float64 `json:"price"`
+	ValidityDays  int     `json:"validity_days" binding:"required"`
+	RenewalPolicy string  `json:"renewal_policy"`
+	Status        string  `json:"status"`
+	SortOrder     int     `json:"sort_order"`
+}
+
+func (r *SubscriptionPlanRequest) toInput() *service.SubscriptionPlanInput {
+	return &service.SubscriptionPlanInput{
+		GroupID:       r.GroupID,
+		Name:          r.Name,
+		Description:   r.Description,
+		Price:         r.Price,
+		ValidityDays:  r.ValidityDays,
+		RenewalPolicy: r.RenewalPolicy,
+		Status:        r.Status,
+		SortOrder:     r.SortOrder,
+	}
+}
+
+// List 列出全部套餐
+// GET /api/v1/admin/subscription-plans
+func (h *SubscriptionPlanHandler) List(c *gin.Context) {
+	plans, err := h.planService.ListPlans(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, plans)
+}
+
+// Create 创建套餐
+// POST /api/v1/admin/subscription-plans
+func (h *SubscriptionPlanHandler) Create(c *gin.Context) {
+	var req SubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	plan, err := h.planService.CreatePlan(c.Request.Context(), req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, plan)
+}
+
+// Update 更新套餐
+// PUT /api/v1/admin/subscription-plans/:id
+func (h *SubscriptionPlanHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid plan ID")
+		return
+	}
+
+	var req SubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	plan, err := h.planService.UpdatePlan(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, plan)
+}
+
+// Delete 删除套餐
+// DELETE /api/v1/admin/subscription-plans/:id
+func (h *SubscriptionPlanHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid plan ID")
+		return
+	}
+
+	if err := h.planService.DeletePlan(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Subscription plan deleted successfully"})
+}
+
+// ListOrders 分页列出订单
+// GET /api/v1/admin/subscription-orders?user_id=1&group_id=2&kind=renew&search=foo
+func (h *SubscriptionPlanHandler) ListOrders(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.SubscriptionOrderFilter{
+		Kind:   strings.TrimSpace(c.Query("kind")),
+		Search: strings.TrimSpace(c.Query("search")),
+	}
+	if len(filter.Search) > 100 {
+		filter.Search = filter.Search[:100]
+	}
+	for _, p := range []struct {
+		name string
+		dst  *int64
+	}{
+		{"user_id", &filter.UserID},
+		{"group_id", &filter.GroupID},
+	} {
+		raw := strings.TrimSpace(c.Query(p.name))
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid "+p.name)
+			return
+		}
+		*p.dst = id
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.planService.ListOrders(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
@@ -1,9 +1,12 @@
 package admin
 
 import (
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/sysutil"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -85,3 +88,66 @@ func (h *SystemHandler) RestartService(c *gin.Context) {
 		"message": "Service restart initiated",
 	})
 }
+
+// UpdateLogLevelsRequest 运行时日志级别调整请求
+type UpdateLogLevelsRequest struct {
+	// Default 默认级别；为空表示不修改
+	Default string `json:"default"`
+	// Modules 按模块设置级别；值为空字符串表示移除该模块的覆盖
+	Modules map[string]string `json:"modules"`
+}
+
+// GetLogLevels returns the current default and per-module log levels
+// GET /api/v1/admin/system/log-levels
+func (h *SystemHandler) GetLogLevels(c *gin.Context) {
+	response.Success(c, logger.Levels())
+}
+
+// UpdateLogLevels changes log levels at runtime (not persisted across restarts)
+// PUT /api/v1/admin/system/log-levels
+func (h *SystemHandler) UpdateLogLevels(c *gin.Context) {
+	var req UpdateLogLevelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	// 先整体校验，避免部分生效
+	var defaultLevel *slog.Level
+	if strings.TrimSpace(req.Default) != "" {
+		level, err := logger.ParseLevel(req.Default)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		defaultLevel = &level
+	}
+	moduleLevels := make(map[string]slog.Level, len(req.Modules))
+	for module, raw := range req.Modules {
+		if strings.TrimSpace(module) == "" {
+			response.BadRequest(c, "Module name is required")
+			return
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		level, err := logger.ParseLevel(raw)
+		if err != nil {
+			response.BadRequest(c, "module "+module+": "+err.Error())
+			return
+		}
+		moduleLevels[module] = level
+	}
+
+	if defaultLevel != nil {
+		logger.SetDefaultLevel(*defaultLevel)
+	}
+	for module := range req.Modules {
+		if level, ok := moduleLevels[module]; ok {
+			_ = logger.SetModuleLevel(module, level)
+			continue
+		}
+		logger.ResetModuleLevel(module)
+	}
+	response.Success(c, logger.Levels())
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserSessionHandler handles admin inspection and revocation of user login sessions
+type UserSessionHandler struct {
+	authService *service.AuthService
+}
+
+// NewUserSessionHandler creates a new admin user session handler
+func NewUserSessionHandler(authService *service.AuthService) *UserSessionHandler {
+	return &UserSessionHandler{authService: authService}
+}
+
+// List handles listing active sessions of a user
+// GET /api/v1/admin/users/:id/sessions
+func (h *UserSessionHandler) List(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	sessions, err := h.authService.ListUserSessions(c.Request.Context(), userID, "")
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// Revoke handles revoking a single session of a user
+// DELETE /api/v1/admin/users/:id/sessions/:session_id
+func (h *UserSessionHandler) Revoke(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.authService.RevokeUserSession(c.Request.Context(), userID, c.Param("session_id")); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Session revoked successfully"})
+}
+
+// RevokeAll handles revoking all sessions of a user
+// DELETE /api/v1/admin/users/:id/sessions
+func (h *UserSessionHandler) RevokeAll(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.authService.RevokeAllUserSessions(c.Request.Context(), userID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "All sessions revoked successfully"})
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
@@ -15,25 +17,33 @@ import (
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	cfg           *config.Config
-	authService   *service.AuthService
-	userService   *service.UserService
-	settingSvc    *service.SettingService
-	promoService  *service.PromoService
-	redeemService *service.RedeemService
-	totpService   *service.TotpService
+	cfg             *config.Config
+	authService     *service.AuthService
+	userService     *service.UserService
+	settingSvc      *service.SettingService
+	promoService    *service.PromoService
+	redeemService   *service.RedeemService
+	totpService     *service.TotpService
+	oidcService     *service.OIDCService
+	webauthnService *service.WebAuthnService
+	loginGuard      *service.LoginGuardService
+	referralService *service.ReferralService
 }
 
 // NewAuthHandler creates a new AuthHandler
-func NewAuthHandler(cfg *config.Config, authService *service.AuthService, userService *service.UserService, settingService *service.SettingService, promoService *service.PromoService, redeemService *service.RedeemService, totpService *service.TotpService) *AuthHandler {
+func NewAuthHandler(cfg *config.Config, authService *service.AuthService, userService *service.UserService, settingService *service.SettingService, promoService *service.PromoService, redeemService *service.RedeemService, totpService *service.TotpService, oidcService *service.OIDCService, webauthnService *service.WebAuthnService, loginGuard *service.LoginGuardService, referralService *service.ReferralService) *AuthHandler {
 	return &AuthHandler{
-		cfg:           cfg,
-		authService:   authService,
-		userService:   userService,
-		settingSvc:    settingService,
-		promoService:  promoService,
-		redeemService: redeemService,
-		totpService:   totpService,
+		cfg:             cfg,
+		authService:     authService,
+		userService:     userService,
+		settingSvc:      settingService,
+		promoService:    promoService,
+		redeemService:   redeemService,
+		totpService:     totpService,
+		oidcService:     oidcService,
+		webauthnService: webauthnService,
+		loginGuard:      loginGuard,
+		referralService: referralService,
 	}
 }
 
@@ -45,6 +55,7 @@ type RegisterRequest struct {
 	TurnstileToken string `json:"turnstile_token"`
 	PromoCode      string `json:"promo_code"`      // 注册优惠码
 	InvitationCode string `json:"invitation_code"` // 邀请码
+	ReferralCode   string `json:"referral_code"`   // 推广邀请链接中的邀请人代码
 }
 
 // SendVerifyCodeRequest 发送验证码请求
@@ -78,7 +89,7 @@ type AuthResponse struct {
 // respondWithTokenPair 生成 Token 对并返回认证响应
 // 如果 Token 对生成失败，回退到只返回 Access Token（向后兼容）
 func (h *AuthHandler) respondWithTokenPair(c *gin.Context, user *service.User) {
-	tokenPair, err := h.authService.GenerateTokenPair(c.Request.Context(), user, "")
+	tokenPair, err := h.authService.GenerateTokenPair(sessionClientContext(c), user, "")
 	if err != nil {
 		slog.Error("failed to generate token pair", "error", err, "user_id", user.ID)
 		// 回退到只返回Access Token
@@ -126,6 +137,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// 绑定邀请关系（失败不影响注册）
+	h.referralService.BindOnSignup(c.Request.Context(), user, req.ReferralCode, ip.GetClientIP(c))
+
 	h.respondWithTokenPair(c, user)
 }
 
@@ -165,21 +179,45 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Turnstile 验证
-	if err := h.authService.VerifyTurnstile(c.Request.Context(), req.TurnstileToken, ip.GetClientIP(c)); err != nil {
+	ctx := c.Request.Context()
+	clientIP := ip.GetClientIP(c)
+
+	// 暴力破解防护：锁定 / 递增等待检查
+	captchaRequired, err := h.loginGuard.Check(ctx, req.Email, clientIP)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	// Turnstile 验证（失败次数过多时即使未启用 Turnstile 也强制验证）
+	if captchaRequired {
+		err = h.loginGuard.VerifyCaptcha(ctx, req.TurnstileToken, clientIP)
+	} else {
+		err = h.authService.VerifyTurnstile(ctx, req.TurnstileToken, clientIP)
+	}
+	if err != nil {
 		response.ErrorFrom(c, err)
 		return
 	}
 
-	token, user, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	token, user, err := h.authService.Login(ctx, req.Email, req.Password)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) && h.loginGuard.RecordFailure(ctx, req.Email, clientIP) {
+			err = service.ErrInvalidCredentials.WithMetadata(map[string]string{"captcha_required": "true"})
+		}
 		response.ErrorFrom(c, err)
 		return
 	}
+	h.loginGuard.RecordSuccess(ctx, req.Email)
 	_ = token // token 由 authService.Login 返回但此处由 respondWithTokenPair 重新生成
 
-	// Check if TOTP 2FA is enabled for this user
-	if h.totpService != nil && h.settingSvc.IsTotpEnabled(c.Request.Context()) && user.TotpEnabled {
+	// Check if 2FA (TOTP / passkey) is required for this user
+	methods, err := h.secondFactorMethods(c.Request.Context(), user)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	if h.totpService != nil && len(methods) > 0 {
 		// Create a temporary login session for 2FA
 		tempToken, err := h.totpService.CreateLoginSession(c.Request.Context(), user.ID, user.Email)
 		if err != nil {
@@ -191,6 +229,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			Requires2FA:     true,
 			TempToken:       tempToken,
 			UserEmailMasked: service.MaskEmail(user.Email),
+			Methods:         methods,
 		})
 		return
 	}
@@ -200,15 +239,19 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // TotpLoginResponse represents the response when 2FA is required
 type TotpLoginResponse struct {
-	Requires2FA     bool   `json:"requires_2fa"`
-	TempToken       string `json:"temp_token,omitempty"`
-	UserEmailMasked string `json:"user_email_masked,omitempty"`
+	Requires2FA     bool     `json:"requires_2fa"`
+	TempToken       string   `json:"temp_token,omitempty"`
+	UserEmailMasked string   `json:"user_email_masked,omitempty"`
+	Methods         []string `json:"methods,omitempty"` // 可用的二步验证方式：totp / webauthn / recovery_code
 }
 
 // Login2FARequest represents the 2FA login request
+// Exactly one of TotpCode, RecoveryCode or WebAuthn is used (checked in that order: WebAuthn, RecoveryCode, TotpCode)
 type Login2FARequest struct {
-	TempToken string `json:"temp_token" binding:"required"`
-	TotpCode  string `json:"totp_code" binding:"required,len=6"`
+	TempToken    string                             `json:"temp_token" binding:"required"`
+	TotpCode     string                             `json:"totp_code"`
+	RecoveryCode string                             `json:"recovery_code"`
+	WebAuthn     *service.WebAuthnAssertionResponse `json:"webauthn"`
 }
 
 // Login2FA completes the login with 2FA verification
@@ -242,8 +285,8 @@ func (h *AuthHandler) Login2FA(c *gin.Context) {
 		"user_id", session.UserID,
 		"email", session.Email)
 
-	// Verify the TOTP code
-	if err := h.totpService.VerifyCode(c.Request.Context(), session.UserID, req.TotpCode); err != nil {
+	// Verify the second factor
+	if err := h.verifySecondFactor(c, session.UserID, &req); err != nil {
 		slog.Debug("login_2fa_verify_failed",
 			"user_id", session.UserID,
 			"error", err)
@@ -279,9 +322,19 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
+	type ImpersonationInfo struct {
+		SessionID  int64     `json:"session_id"`
+		AdminID    int64     `json:"admin_id"`
+		AdminEmail string    `json:"admin_email"`
+		Reason     string    `json:"reason"`
+		ExpiresAt  time.Time `json:"expires_at"`
+	}
+
 	type UserResponse struct {
 		*dto.User
 		RunMode string `json:"run_mode"`
+		// Impersonation 管理员模拟登录时返回，前端据此显示提示横幅
+		Impersonation *ImpersonationInfo `json:"impersonation,omitempty"`
 	}
 
 	runMode := config.RunModeStandard
@@ -289,7 +342,17 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		runMode = h.cfg.RunMode
 	}
 
-	response.Success(c, UserResponse{User: dto.UserFromService(user), RunMode: runMode})
+	resp := UserResponse{User: dto.UserFromService(user), RunMode: runMode}
+	if session, ok := middleware2.GetImpersonationFromContext(c); ok {
+		resp.Impersonation = &ImpersonationInfo{
+			SessionID:  session.ID,
+			AdminID:    session.AdminUserID,
+			AdminEmail: session.AdminEmail,
+			Reason:     session.Reason,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+	response.Success(c, resp)
 }
 
 // ValidatePromoCodeRequest 验证优惠码请求
@@ -299,10 +362,13 @@ type ValidatePromoCodeRequest struct {
 
 // ValidatePromoCodeResponse 验证优惠码响应
 type ValidatePromoCodeResponse struct {
-	Valid       bool    `json:"valid"`
-	BonusAmount float64 `json:"bonus_amount,omitempty"`
-	ErrorCode   string  `json:"error_code,omitempty"`
-	Message     string  `json:"message,omitempty"`
+	Valid          bool    `json:"valid"`
+	BonusType      string  `json:"bonus_type,omitempty"`
+	BonusAmount    float64 `json:"bonus_amount,omitempty"`
+	BonusPercent   float64 `json:"bonus_percent,omitempty"`
+	MaxBonusAmount float64 `json:"max_bonus_amount,omitempty"`
+	ErrorCode      string  `json:"error_code,omitempty"`
+	Message        string  `json:"message,omitempty"`
 }
 
 // ValidatePromoCode 验证优惠码（公开接口，注册前调用）
@@ -338,6 +404,12 @@ func (h *AuthHandler) ValidatePromoCode(c *gin.Context) {
 			errorCode = "PROMO_CODE_MAX_USED"
 		case service.ErrPromoCodeAlreadyUsed:
 			errorCode = "PROMO_CODE_ALREADY_USED"
+		case service.ErrPromoCodeNotStarted:
+			errorCode = "PROMO_CODE_NOT_STARTED"
+		case service.ErrPromoCodeScope:
+			errorCode = "PROMO_CODE_SCOPE"
+		case service.ErrPromoCodeGroupRequired:
+			errorCode = "PROMO_CODE_GROUP_REQUIRED"
 		}
 
 		response.Success(c, ValidatePromoCodeResponse{
@@ -355,10 +427,17 @@ func (h *AuthHandler) ValidatePromoCode(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, ValidatePromoCodeResponse{
-		Valid:       true,
-		BonusAmount: promoCode.BonusAmount,
-	})
+	resp := ValidatePromoCodeResponse{
+		Valid:     true,
+		BonusType: promoCode.BonusType,
+	}
+	if promoCode.BonusType == service.PromoBonusTypePercent {
+		resp.BonusPercent = promoCode.BonusPercent
+		resp.MaxBonusAmount = promoCode.MaxBonusAmount
+	} else {
+		resp.BonusAmount = promoCode.BonusAmount
+	}
+	response.Success(c, resp)
 }
 
 // ValidateInvitationCodeRequest 验证邀请码请求
@@ -528,7 +607,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.authService.RefreshTokenPair(c.Request.Context(), req.RefreshToken)
+	tokenPair, err := h.authService.RefreshTokenPair(sessionClientContext(c), req.RefreshToken)
 	if err != nil {
 		response.ErrorFrom(c, err)
 		return
@@ -38,7 +38,7 @@ const (
 	linuxDoOAuthMaxSubjectLen       = 64 - len("linuxdo-")
 )
 
-type linuxDoTokenResponse struct {
+type oauthTokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
@@ -46,14 +46,14 @@ type linuxDoTokenResponse struct {
 	Scope        string `json:"scope,omitempty"`
 }
 
-type linuxDoTokenExchangeError struct {
+type oauthTokenExchangeError struct {
 	StatusCode          int
 	ProviderError       string
 	ProviderDescription string
 	Body                string
 }
 
-func (e *linuxDoTokenExchangeError) Error() string {
+func (e *oauthTokenExchangeError) Error() string {
 	if e == nil {
 		return ""
 	}
@@ -180,7 +180,7 @@ func (h *AuthHandler) LinuxDoOAuthCallback(c *gin.Context) {
 	tokenResp, err := linuxDoExchangeCode(c.Request.Context(), cfg, code, redirectURI, codeVerifier)
 	if err != nil {
 		description := ""
-		var exchangeErr *linuxDoTokenExchangeError
+		var exchangeErr *oauthTokenExchangeError
 		if errors.As(err, &exchangeErr) && exchangeErr != nil {
 			log.Printf(
 				"[LinuxDo OAuth] token exchange failed: status=%d provider_error=%q provider_description=%q body=%s",
@@ -211,7 +211,7 @@ func (h *AuthHandler) LinuxDoOAuthCallback(c *gin.Context) {
 		email = linuxDoSyntheticEmail(subject)
 	}
 
-	tokenPair, _, err := h.authService.LoginOrRegisterOAuthWithTokenPair(c.Request.Context(), email, username)
+	tokenPair, _, err := h.authService.LoginOrRegisterOAuthWithTokenPair(sessionClientContext(c), email, username)
 	if err != nil {
 		// 避免把内部细节泄露给客户端；给前端保留结构化原因与提示信息即可。
 		redirectOAuthError(c, frontendCallback, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
@@ -246,7 +246,34 @@ func linuxDoExchangeCode(
 	code string,
 	redirectURI string,
 	codeVerifier string,
-) (*linuxDoTokenResponse, error) {
+) (*oauthTokenResponse, error) {
+	if !cfg.UsePKCE {
+		codeVerifier = ""
+	}
+	return oauthExchangeCode(ctx, oauthClientConfig{
+		TokenURL:        cfg.TokenURL,
+		ClientID:        cfg.ClientID,
+		ClientSecret:    cfg.ClientSecret,
+		TokenAuthMethod: cfg.TokenAuthMethod,
+	}, code, redirectURI, codeVerifier)
+}
+
+// oauthClientConfig 授权码换取令牌所需的客户端配置
+type oauthClientConfig struct {
+	TokenURL        string
+	ClientID        string
+	ClientSecret    string
+	TokenAuthMethod string
+}
+
+// oauthExchangeCode 使用授权码换取访问令牌；codeVerifier 非空时附带 PKCE 参数。
+func oauthExchangeCode(
+	ctx context.Context,
+	cfg oauthClientConfig,
+	code string,
+	redirectURI string,
+	codeVerifier string,
+) (*oauthTokenResponse, error) {
 	client := req.C().SetTimeout(30 * time.Second)
 
 	form := url.Values{}
@@ -254,7 +281,7 @@ func linuxDoExchangeCode(
 	form.Set("client_id", cfg.ClientID)
 	form.Set("code", code)
 	form.Set("redirect_uri", redirectURI)
-	if cfg.UsePKCE {
+	if codeVerifier != "" {
 		form.Set("code_verifier", codeVerifier)
 	}
 
@@ -279,7 +306,7 @@ func linuxDoExchangeCode(
 	body := strings.TrimSpace(resp.String())
 	if !resp.IsSuccessState() {
 		providerErr, providerDesc := parseOAuthProviderError(body)
-		return nil, &linuxDoTokenExchangeError{
+		return nil, &oauthTokenExchangeError{
 			StatusCode:          resp.StatusCode,
 			ProviderError:       providerErr,
 			ProviderDescription: providerDesc,
@@ -287,9 +314,9 @@ func linuxDoExchangeCode(
 		}
 	}
 
-	tokenResp, ok := parseLinuxDoTokenResponse(body)
+	tokenResp, ok := parseOAuthTokenResponse(body)
 	if !ok || strings.TrimSpace(tokenResp.AccessToken) == "" {
-		return nil, &linuxDoTokenExchangeError{
+		return nil, &oauthTokenExchangeError{
 			StatusCode: resp.StatusCode,
 			Body:       body,
 		}
@@ -303,27 +330,35 @@ func linuxDoExchangeCode(
 func linuxDoFetchUserInfo(
 	ctx context.Context,
 	cfg config.LinuxDoConnectConfig,
-	token *linuxDoTokenResponse,
+	token *oauthTokenResponse,
 ) (email string, username string, subject string, err error) {
+	body, err := oauthFetchUserInfo(ctx, cfg.UserInfoURL, token)
+	if err != nil {
+		return "", "", "", err
+	}
+	return linuxDoParseUserInfo(body, cfg)
+}
+
+// oauthFetchUserInfo 使用访问令牌请求 userinfo 端点，返回原始响应体。
+func oauthFetchUserInfo(ctx context.Context, userInfoURL string, token *oauthTokenResponse) (string, error) {
 	client := req.C().SetTimeout(30 * time.Second)
 	authorization, err := buildBearerAuthorization(token.TokenType, token.AccessToken)
 	if err != nil {
-		return "", "", "", fmt.Errorf("invalid token for userinfo request: %w", err)
+		return "", fmt.Errorf("invalid token for userinfo request: %w", err)
 	}
 
 	resp, err := client.R().
 		SetContext(ctx).
 		SetHeader("Accept", "application/json").
 		SetHeader("Authorization", authorization).
-		Get(cfg.UserInfoURL)
+		Get(userInfoURL)
 	if err != nil {
-		return "", "", "", fmt.Errorf("request userinfo: %w", err)
+		return "", fmt.Errorf("request userinfo: %w", err)
 	}
 	if !resp.IsSuccessState() {
-		return "", "", "", fmt.Errorf("userinfo status=%d", resp.StatusCode)
+		return "", fmt.Errorf("userinfo status=%d", resp.StatusCode)
 	}
-
-	return linuxDoParseUserInfo(resp.String(), cfg)
+	return resp.String(), nil
 }
 
 func linuxDoParseUserInfo(body string, cfg config.LinuxDoConnectConfig) (email string, username string, subject string, err error) {
@@ -374,20 +409,28 @@ func linuxDoParseUserInfo(body string, cfg config.LinuxDoConnectConfig) (email s
 }
 
 func buildLinuxDoAuthorizeURL(cfg config.LinuxDoConnectConfig, state string, codeChallenge string, redirectURI string) (string, error) {
-	u, err := url.Parse(cfg.AuthorizeURL)
+	if !cfg.UsePKCE {
+		codeChallenge = ""
+	}
+	return buildOAuthAuthorizeURL(cfg.AuthorizeURL, cfg.ClientID, cfg.Scopes, state, codeChallenge, redirectURI)
+}
+
+// buildOAuthAuthorizeURL 构造授权码模式的授权地址；codeChallenge 非空时附带 PKCE(S256) 参数。
+func buildOAuthAuthorizeURL(authorizeURL, clientID, scopes, state, codeChallenge, redirectURI string) (string, error) {
+	u, err := url.Parse(authorizeURL)
 	if err != nil {
 		return "", fmt.Errorf("parse authorize_url: %w", err)
 	}
 
 	q := u.Query()
 	q.Set("response_type", "code")
-	q.Set("client_id", cfg.ClientID)
+	q.Set("client_id", clientID)
 	q.Set("redirect_uri", redirectURI)
-	if strings.TrimSpace(cfg.Scopes) != "" {
-		q.Set("scope", cfg.Scopes)
+	if strings.TrimSpace(scopes) != "" {
+		q.Set("scope", scopes)
 	}
 	q.Set("state", state)
-	if cfg.UsePKCE {
+	if codeChallenge != "" {
 		q.Set("code_challenge", codeChallenge)
 		q.Set("code_challenge_method", "S256")
 	}
@@ -466,7 +509,7 @@ func parseOAuthProviderError(body string) (providerErr string, providerDesc stri
 	return providerErr, providerDesc
 }
 
-func parseLinuxDoTokenResponse(body string) (*linuxDoTokenResponse, bool) {
+func parseOAuthTokenResponse(body string) (*oauthTokenResponse, bool) {
 	body = strings.TrimSpace(body)
 	if body == "" {
 		return nil, false
@@ -478,7 +521,7 @@ func parseLinuxDoTokenResponse(body string) (*linuxDoTokenResponse, bool) {
 		refreshToken := strings.TrimSpace(getGJSON(body, "refresh_token"))
 		scope := strings.TrimSpace(getGJSON(body, "scope"))
 		expiresIn := gjson.Get(body, "expires_in").Int()
-		return &linuxDoTokenResponse{
+		return &oauthTokenResponse{
 			AccessToken:  accessToken,
 			TokenType:    tokenType,
 			ExpiresIn:    expiresIn,
@@ -501,7 +544,7 @@ func parseLinuxDoTokenResponse(body string) (*linuxDoTokenResponse, bool) {
 			expiresIn = v
 		}
 	}
-	return &linuxDoTokenResponse{
+	return &oauthTokenResponse{
 		AccessToken:  accessToken,
 		TokenType:    strings.TrimSpace(values.Get("token_type")),
 		ExpiresIn:    expiresIn,
@@ -598,10 +641,18 @@ func readCookieDecoded(c *gin.Context, name string) (string, error) {
 }
 
 func setCookie(c *gin.Context, name string, value string, maxAgeSec int, secure bool) {
+	setCookieWithPath(c, name, value, linuxDoOAuthCookiePath, maxAgeSec, secure)
+}
+
+func clearCookie(c *gin.Context, name string, secure bool) {
+	clearCookieWithPath(c, name, linuxDoOAuthCookiePath, secure)
+}
+
+func setCookieWithPath(c *gin.Context, name string, value string, path string, maxAgeSec int, secure bool) {
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     name,
 		Value:    value,
-		Path:     linuxDoOAuthCookiePath,
+		Path:     path,
 		MaxAge:   maxAgeSec,
 		HttpOnly: true,
 		Secure:   secure,
@@ -609,11 +660,11 @@ func setCookie(c *gin.Context, name string, value string, maxAgeSec int, secure
 	})
 }
 
-func clearCookie(c *gin.Context, name string, secure bool) {
+func clearCookieWithPath(c *gin.Context, name string, path string, secure bool) {
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     name,
 		Value:    "",
-		Path:     linuxDoOAuthCookiePath,
+		Path:     path,
 		MaxAge:   -1,
 		HttpOnly: true,
 		Secure:   secure,
@@ -85,8 +85,8 @@ func TestParseOAuthProviderErrorForm(t *testing.T) {
 	require.Equal(t, "Missing code_verifier", desc)
 }
 
-func TestParseLinuxDoTokenResponseJSON(t *testing.T) {
-	token, ok := parseLinuxDoTokenResponse(`{"access_token":"t1","token_type":"Bearer","expires_in":3600,"scope":"user"}`)
+func TestParseOAuthTokenResponseJSON(t *testing.T) {
+	token, ok := parseOAuthTokenResponse(`{"access_token":"t1","token_type":"Bearer","expires_in":3600,"scope":"user"}`)
 	require.True(t, ok)
 	require.Equal(t, "t1", token.AccessToken)
 	require.Equal(t, "Bearer", token.TokenType)
@@ -94,8 +94,8 @@ func TestParseLinuxDoTokenResponseJSON(t *testing.T) {
 	require.Equal(t, "user", token.Scope)
 }
 
-func TestParseLinuxDoTokenResponseForm(t *testing.T) {
-	token, ok := parseLinuxDoTokenResponse("access_token=t2&token_type=bearer&expires_in=60")
+func TestParseOAuthTokenResponseForm(t *testing.T) {
+	token, ok := parseOAuthTokenResponse("access_token=t2&token_type=bearer&expires_in=60")
 	require.True(t, ok)
 	require.Equal(t, "t2", token.AccessToken)
 	require.Equal(t, "bearer", token.TokenType)
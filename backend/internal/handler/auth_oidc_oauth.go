@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oauth"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcOAuthCookiePathPrefix = "/api/v1/auth/oauth/oidc/"
+	oidcOAuthStateCookieName  = "oidc_oauth_state"
+	oidcOAuthVerifierCookie   = "oidc_oauth_verifier"
+	oidcOAuthRedirectCookie   = "oidc_oauth_redirect"
+)
+
+// OIDCOAuthStart 启动通用 OIDC / OAuth2 登录流程。
+// GET /api/v1/auth/oauth/oidc/:provider/start?redirect=/dashboard
+func (h *AuthHandler) OIDCOAuthStart(c *gin.Context) {
+	provider, err := h.getOIDCProvider(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	endpoints, err := h.oidcService.ResolveEndpoints(c.Request.Context(), provider)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	state, err := oauth.GenerateState()
+	if err != nil {
+		response.ErrorFrom(c, infraerrors.InternalServer("OAUTH_STATE_GEN_FAILED", "failed to generate oauth state").WithCause(err))
+		return
+	}
+
+	redirectTo := sanitizeFrontendRedirectPath(c.Query("redirect"))
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+
+	// cookie 按提供方隔离路径，避免并发发起多个提供方登录时互相覆盖
+	cookiePath := oidcOAuthCookiePathPrefix + provider.Slug
+	secureCookie := isRequestHTTPS(c)
+	setCookieWithPath(c, oidcOAuthStateCookieName, encodeCookieValue(state), cookiePath, linuxDoOAuthCookieMaxAgeSec, secureCookie)
+	setCookieWithPath(c, oidcOAuthRedirectCookie, encodeCookieValue(redirectTo), cookiePath, linuxDoOAuthCookieMaxAgeSec, secureCookie)
+
+	codeChallenge := ""
+	if provider.UsePKCE {
+		verifier, err := oauth.GenerateCodeVerifier()
+		if err != nil {
+			response.ErrorFrom(c, infraerrors.InternalServer("OAUTH_PKCE_GEN_FAILED", "failed to generate pkce verifier").WithCause(err))
+			return
+		}
+		codeChallenge = oauth.GenerateCodeChallenge(verifier)
+		setCookieWithPath(c, oidcOAuthVerifierCookie, encodeCookieValue(verifier), cookiePath, linuxDoOAuthCookieMaxAgeSec, secureCookie)
+	}
+
+	authURL, err := buildOAuthAuthorizeURL(endpoints.AuthorizeURL, provider.ClientID, provider.Scopes, state, codeChallenge, provider.RedirectURL)
+	if err != nil {
+		response.ErrorFrom(c, infraerrors.InternalServer("OAUTH_BUILD_URL_FAILED", "failed to build oauth authorization url").WithCause(err))
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCOAuthCallback 处理 OIDC 回调：绑定外部身份并登录/注册用户，然后重定向到前端。
+// GET /api/v1/auth/oauth/oidc/:provider/callback?code=...&state=...
+func (h *AuthHandler) OIDCOAuthCallback(c *gin.Context) {
+	provider, err := h.getOIDCProvider(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	frontendCallback := provider.FrontendRedirectURL
+
+	if providerErr := strings.TrimSpace(c.Query("error")); providerErr != "" {
+		redirectOAuthError(c, frontendCallback, "provider_error", providerErr, c.Query("error_description"))
+		return
+	}
+
+	code := strings.TrimSpace(c.Query("code"))
+	state := strings.TrimSpace(c.Query("state"))
+	if code == "" || state == "" {
+		redirectOAuthError(c, frontendCallback, "missing_params", "missing code/state", "")
+		return
+	}
+
+	cookiePath := oidcOAuthCookiePathPrefix + provider.Slug
+	secureCookie := isRequestHTTPS(c)
+	defer func() {
+		clearCookieWithPath(c, oidcOAuthStateCookieName, cookiePath, secureCookie)
+		clearCookieWithPath(c, oidcOAuthVerifierCookie, cookiePath, secureCookie)
+		clearCookieWithPath(c, oidcOAuthRedirectCookie, cookiePath, secureCookie)
+	}()
+
+	expectedState, err := readCookieDecoded(c, oidcOAuthStateCookieName)
+	if err != nil || expectedState == "" || state != expectedState {
+		redirectOAuthError(c, frontendCallback, "invalid_state", "invalid oauth state", "")
+		return
+	}
+
+	redirectTo, _ := readCookieDecoded(c, oidcOAuthRedirectCookie)
+	redirectTo = sanitizeFrontendRedirectPath(redirectTo)
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+
+	codeVerifier := ""
+	if provider.UsePKCE {
+		codeVerifier, _ = readCookieDecoded(c, oidcOAuthVerifierCookie)
+		if codeVerifier == "" {
+			redirectOAuthError(c, frontendCallback, "missing_verifier", "missing pkce verifier", "")
+			return
+		}
+	}
+
+	endpoints, err := h.oidcService.ResolveEndpoints(c.Request.Context(), provider)
+	if err != nil {
+		redirectOAuthError(c, frontendCallback, "config_error", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+
+	tokenResp, err := oauthExchangeCode(c.Request.Context(), oauthClientConfig{
+		TokenURL:        endpoints.TokenURL,
+		ClientID:        provider.ClientID,
+		ClientSecret:    provider.ClientSecret,
+		TokenAuthMethod: provider.TokenAuthMethod,
+	}, code, provider.RedirectURL, codeVerifier)
+	if err != nil {
+		description := ""
+		var exchangeErr *oauthTokenExchangeError
+		if errors.As(err, &exchangeErr) && exchangeErr != nil {
+			log.Printf(
+				"[OIDC OAuth] provider=%s token exchange failed: status=%d provider_error=%q provider_description=%q body=%s",
+				provider.Slug,
+				exchangeErr.StatusCode,
+				exchangeErr.ProviderError,
+				exchangeErr.ProviderDescription,
+				truncateLogValue(exchangeErr.Body, 2048),
+			)
+			description = exchangeErr.Error()
+		} else {
+			log.Printf("[OIDC OAuth] provider=%s token exchange failed: %v", provider.Slug, err)
+			description = err.Error()
+		}
+		redirectOAuthError(c, frontendCallback, "token_exchange_failed", "failed to exchange oauth code", singleLine(description))
+		return
+	}
+
+	// 身份声明取自 userinfo 端点（访问令牌鉴权 + TLS），无需本地校验 id_token 签名
+	body, err := oauthFetchUserInfo(c.Request.Context(), endpoints.UserInfoURL, tokenResp)
+	if err != nil {
+		log.Printf("[OIDC OAuth] provider=%s userinfo fetch failed: %v", provider.Slug, err)
+		redirectOAuthError(c, frontendCallback, "userinfo_failed", "failed to fetch user info", "")
+		return
+	}
+	identity, err := service.ParseOIDCUserInfo(body, provider)
+	if err != nil {
+		log.Printf("[OIDC OAuth] provider=%s userinfo parse failed: %v", provider.Slug, err)
+		redirectOAuthError(c, frontendCallback, "userinfo_failed", "failed to parse user info", "")
+		return
+	}
+
+	user, err := h.oidcService.CompleteLogin(sessionClientContext(c), provider, identity)
+	if err != nil {
+		redirectOAuthError(c, frontendCallback, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+	h.redirectOAuthLogin(c, frontendCallback, user, redirectTo)
+}
+
+// redirectOAuthLogin 第三方登录完成后携带登录结果跳转回前端。
+// 用户已启用 TOTP / 通行密钥时与密码登录一致：只下发二步验证临时令牌，由前端调用 /auth/login/2fa 换取令牌。
+func (h *AuthHandler) redirectOAuthLogin(c *gin.Context, frontendCallback string, user *service.User, redirectTo string) {
+	ctx := c.Request.Context()
+	methods, err := h.secondFactorMethods(ctx, user)
+	if err != nil {
+		redirectOAuthError(c, frontendCallback, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+
+	fragment := url.Values{}
+	if len(methods) > 0 {
+		// 无法创建二步验证会话时拒绝登录，不能退回到直接签发令牌
+		if h.totpService == nil {
+			redirectOAuthError(c, frontendCallback, "login_failed", "2FA_UNAVAILABLE", "two-factor authentication is not available")
+			return
+		}
+		tempToken, err := h.totpService.CreateLoginSession(ctx, user.ID, user.Email)
+		if err != nil {
+			log.Printf("[OAuth] create 2fa login session failed: user=%d err=%v", user.ID, err)
+			redirectOAuthError(c, frontendCallback, "login_failed", "2FA_SESSION_FAILED", "failed to create 2fa session")
+			return
+		}
+		fragment.Set("requires_2fa", "true")
+		fragment.Set("temp_token", tempToken)
+		fragment.Set("user_email_masked", service.MaskEmail(user.Email))
+		fragment.Set("methods", strings.Join(methods, ","))
+		fragment.Set("redirect", redirectTo)
+		redirectWithFragment(c, frontendCallback, fragment)
+		return
+	}
+
+	tokenPair, err := h.authService.GenerateTokenPair(sessionClientContext(c), user, "")
+	if err != nil {
+		log.Printf("[OAuth] generate token pair failed: user=%d err=%v", user.ID, err)
+		redirectOAuthError(c, frontendCallback, "login_failed", "TOKEN_FAILED", "failed to generate token")
+		return
+	}
+	fragment.Set("access_token", tokenPair.AccessToken)
+	fragment.Set("refresh_token", tokenPair.RefreshToken)
+	fragment.Set("expires_in", fmt.Sprintf("%d", tokenPair.ExpiresIn))
+	fragment.Set("token_type", "Bearer")
+	fragment.Set("redirect", redirectTo)
+	redirectWithFragment(c, frontendCallback, fragment)
+}
+
+func (h *AuthHandler) getOIDCProvider(ctx context.Context, slug string) (*service.OIDCProviderConfig, error) {
+	if h == nil || h.oidcService == nil {
+		return nil, infraerrors.ServiceUnavailable("CONFIG_NOT_READY", "oidc login not available")
+	}
+	return h.oidcService.GetEnabledProvider(ctx, slug)
+}
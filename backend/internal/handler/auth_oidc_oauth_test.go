@@ -0,0 +1,74 @@
+//go:build unit
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type oauthSettingRepoStub struct {
+	service.SettingRepository
+	values map[string]string
+}
+
+func (r *oauthSettingRepoStub) GetValue(ctx context.Context, key string) (string, error) {
+	if v, ok := r.values[key]; ok {
+		return v, nil
+	}
+	return "", service.ErrSettingNotFound
+}
+
+type oauthTotpCacheStub struct {
+	service.TotpCache
+	sessions map[string]*service.TotpLoginSession
+}
+
+func (c *oauthTotpCacheStub) SetLoginSession(ctx context.Context, tempToken string, session *service.TotpLoginSession, ttl time.Duration) error {
+	c.sessions[tempToken] = session
+	return nil
+}
+
+func TestRedirectOAuthLogin_RequiresSecondFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	settingSvc := service.NewSettingService(&oauthSettingRepoStub{values: map[string]string{
+		service.SettingKeyTotpEnabled: "true",
+	}}, nil)
+	cache := &oauthTotpCacheStub{sessions: map[string]*service.TotpLoginSession{}}
+	h := &AuthHandler{
+		settingSvc:  settingSvc,
+		totpService: service.NewTotpService(nil, nil, cache, settingSvc, nil, nil),
+	}
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/oauth/oidc/corp/callback", nil)
+
+	// OIDC 按邮箱映射到已启用 TOTP 的账号：只下发二步验证临时令牌，不签发访问令牌
+	user := &service.User{ID: 7, Email: "alice@example.com", TotpEnabled: true}
+	h.redirectOAuthLogin(c, "/auth/oidc/callback", user, "/dashboard")
+
+	require.Equal(t, http.StatusFound, rec.Code)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	fragment, err := url.ParseQuery(location.Fragment)
+	require.NoError(t, err)
+
+	require.Equal(t, "true", fragment.Get("requires_2fa"))
+	require.Equal(t, service.TwoFactorMethodTotp, fragment.Get("methods"))
+	require.Equal(t, "/dashboard", fragment.Get("redirect"))
+	require.Empty(t, fragment.Get("access_token"))
+	require.Empty(t, fragment.Get("refresh_token"))
+
+	session := cache.sessions[fragment.Get("temp_token")]
+	require.NotNil(t, session)
+	require.Equal(t, int64(7), session.UserID)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionClientContext 返回携带客户端 IP / User-Agent 的 context，签发 Token 时记录到会话
+func sessionClientContext(c *gin.Context) context.Context {
+	return service.WithSessionClientInfo(c.Request.Context(), service.SessionClientInfo{
+		IPAddress: ip.GetClientIP(c),
+		UserAgent: c.GetHeader("User-Agent"),
+	})
+}
+
+// ListSessions 列出当前用户的活跃会话
+// GET /api/v1/user/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.authService.ListUserSessions(c.Request.Context(), subject.UserID, middleware2.GetSessionIDFromContext(c))
+	if err != nil {
+		slog.Error("failed to list sessions", "user_id", subject.UserID, "error", err)
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession 撤销当前用户的单个会话
+// DELETE /api/v1/user/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.authService.RevokeUserSession(c.Request.Context(), subject.UserID, c.Param("id")); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"success": true})
+}
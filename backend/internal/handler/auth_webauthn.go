@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errWebAuthnUnavailable = infraerrors.ServiceUnavailable("CONFIG_NOT_READY", "passkeys not available")
+
+// secondFactorMethods 返回用户登录时需要完成的二步验证方式；为空表示无需二步验证
+func (h *AuthHandler) secondFactorMethods(ctx context.Context, user *service.User) ([]string, error) {
+	if h.webauthnService != nil {
+		return h.webauthnService.SecondFactorMethods(ctx, user)
+	}
+	if h.totpService != nil && h.settingSvc.IsTotpEnabled(ctx) && user.TotpEnabled {
+		return []string{service.TwoFactorMethodTotp}, nil
+	}
+	return nil, nil
+}
+
+// verifySecondFactor 按请求内容校验通行密钥断言、恢复码或 TOTP 验证码
+func (h *AuthHandler) verifySecondFactor(c *gin.Context, userID int64, req *Login2FARequest) error {
+	ctx := c.Request.Context()
+	switch {
+	case req.WebAuthn != nil:
+		if h.webauthnService == nil {
+			return errWebAuthnUnavailable
+		}
+		return h.webauthnService.FinishSecondFactor(ctx, req.TempToken, userID, req.WebAuthn)
+	case req.RecoveryCode != "":
+		if h.webauthnService == nil {
+			return errWebAuthnUnavailable
+		}
+		return h.webauthnService.ConsumeRecoveryCode(ctx, userID, req.RecoveryCode)
+	default:
+		if len(req.TotpCode) != 6 {
+			return service.ErrTotpInvalidCode
+		}
+		return h.totpService.VerifyCode(ctx, userID, req.TotpCode)
+	}
+}
+
+// Login2FAWebAuthnBeginRequest represents the request to start a passkey 2FA assertion
+type Login2FAWebAuthnBeginRequest struct {
+	TempToken string `json:"temp_token" binding:"required"`
+}
+
+// Login2FAWebAuthnBegin 为待完成二步验证的登录会话生成通行密钥断言参数
+// POST /api/v1/auth/login/2fa/webauthn/begin
+func (h *AuthHandler) Login2FAWebAuthnBegin(c *gin.Context) {
+	if h.webauthnService == nil || h.totpService == nil {
+		response.ErrorFrom(c, errWebAuthnUnavailable)
+		return
+	}
+
+	var req Login2FAWebAuthnBeginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	session, err := h.totpService.GetLoginSession(c.Request.Context(), req.TempToken)
+	if err != nil || session == nil {
+		response.BadRequest(c, "Invalid or expired 2FA session")
+		return
+	}
+
+	rp := webauthnRelyingParty(c, h.webauthnService)
+	options, err := h.webauthnService.BeginSecondFactor(c.Request.Context(), req.TempToken, session.UserID, rp)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"options": options})
+}
+
+// PasskeyLoginBegin 发起通行密钥无密码登录
+// POST /api/v1/auth/passkey/begin
+func (h *AuthHandler) PasskeyLoginBegin(c *gin.Context) {
+	if h.webauthnService == nil {
+		response.ErrorFrom(c, errWebAuthnUnavailable)
+		return
+	}
+
+	rp := webauthnRelyingParty(c, h.webauthnService)
+	result, err := h.webauthnService.BeginPasswordlessLogin(c.Request.Context(), rp)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// PasskeyLoginFinishRequest represents the request to complete passwordless passkey login
+type PasskeyLoginFinishRequest struct {
+	SessionToken string                            `json:"session_token" binding:"required"`
+	Credential   service.WebAuthnAssertionResponse `json:"credential" binding:"required"`
+}
+
+// PasskeyLoginFinish 校验通行密钥断言并登录
+// POST /api/v1/auth/passkey/finish
+func (h *AuthHandler) PasskeyLoginFinish(c *gin.Context) {
+	if h.webauthnService == nil {
+		response.ErrorFrom(c, errWebAuthnUnavailable)
+		return
+	}
+
+	var req PasskeyLoginFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	user, err := h.webauthnService.FinishPasswordlessLogin(c.Request.Context(), req.SessionToken, &req.Credential)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	h.respondWithTokenPair(c, user)
+}
@@ -141,6 +141,10 @@ func GroupFromServiceAdmin(g *service.Group) *AdminGroup {
 		SupportedModelScopes: g.SupportedModelScopes,
 		AccountCount:         g.AccountCount,
 		SortOrder:            g.SortOrder,
+		HedgeEnabled:         g.HedgeEnabled,
+		HedgeDelayMs:         g.HedgeDelayMs,
+		HedgeMaxRatio:        g.HedgeMaxRatio,
+		ScheduleRule:         g.ScheduleRule,
 	}
 	if len(g.AccountGroups) > 0 {
 		out.AccountGroups = make([]AccountGroup, 0, len(g.AccountGroups))
@@ -372,8 +376,8 @@ func redeemCodeFromServiceBase(rc *service.RedeemCode) RedeemCode {
 	}
 
 	// For admin_balance/admin_concurrency types, include notes so users can see
-	// why they were charged or credited by admin
-	if (rc.Type == "admin_balance" || rc.Type == "admin_concurrency") && rc.Notes != "" {
+	// why they were charged or credited by admin; payment records carry the order number
+	if (rc.Type == "admin_balance" || rc.Type == "admin_concurrency" || rc.Type == "payment") && rc.Notes != "" {
 		out.Notes = &rc.Notes
 	}
 
@@ -582,6 +586,16 @@ func PromoCodeFromService(pc *service.PromoCode) *PromoCode {
 		Notes:       pc.Notes,
 		CreatedAt:   pc.CreatedAt,
 		UpdatedAt:   pc.UpdatedAt,
+
+		BonusType:       pc.BonusType,
+		BonusPercent:    pc.BonusPercent,
+		MaxBonusAmount:  pc.MaxBonusAmount,
+		PerUserLimit:    pc.PerUserLimit,
+		Scope:           pc.Scope,
+		NewUsersOnly:    pc.NewUsersOnly,
+		NewUserDays:     pc.NewUserDays,
+		RequiredGroupID: pc.RequiredGroupID,
+		StartsAt:        pc.StartsAt,
 	}
 }
 
@@ -595,6 +609,38 @@ func PromoCodeUsageFromService(u *service.PromoCodeUsage) *PromoCodeUsage {
 		UserID:      u.UserID,
 		BonusAmount: u.BonusAmount,
 		UsedAt:      u.UsedAt,
-		User:        UserFromServiceShallow(u.User),
+
+		BonusType:      u.BonusType,
+		BonusPercent:   u.BonusPercent,
+		MaxBonusAmount: u.MaxBonusAmount,
+		Status:         u.Status,
+		Source:         u.Source,
+		BaseAmount:     u.BaseAmount,
+		RedeemCodeID:   u.RedeemCodeID,
+		AppliedAt:      u.AppliedAt,
+
+		User: UserFromServiceShallow(u.User),
+	}
+}
+
+func UserPromoCodeUsageFromService(u *service.PromoCodeUsage) *UserPromoCodeUsage {
+	if u == nil {
+		return nil
 	}
+	out := &UserPromoCodeUsage{
+		ID:             u.ID,
+		BonusType:      u.BonusType,
+		BonusAmount:    u.BonusAmount,
+		BonusPercent:   u.BonusPercent,
+		MaxBonusAmount: u.MaxBonusAmount,
+		Status:         u.Status,
+		Source:         u.Source,
+		BaseAmount:     u.BaseAmount,
+		UsedAt:         u.UsedAt,
+		AppliedAt:      u.AppliedAt,
+	}
+	if u.PromoCode != nil {
+		out.Code = u.PromoCode.Code
+	}
+	return out
 }
@@ -9,6 +9,7 @@ type SystemSettings struct {
 	InvitationCodeEnabled       bool `json:"invitation_code_enabled"`
 	TotpEnabled                 bool `json:"totp_enabled"`                   // TOTP 双因素认证
 	TotpEncryptionKeyConfigured bool `json:"totp_encryption_key_configured"` // TOTP 加密密钥是否已配置
+	PasskeyLoginEnabled         bool `json:"passkey_login_enabled"`          // 通行密钥无密码登录
 
 	SMTPHost               string `json:"smtp_host"`
 	SMTPPort               int    `json:"smtp_port"`
@@ -63,26 +64,35 @@ type SystemSettings struct {
 }
 
 type PublicSettings struct {
-	RegistrationEnabled         bool   `json:"registration_enabled"`
-	EmailVerifyEnabled          bool   `json:"email_verify_enabled"`
-	PromoCodeEnabled            bool   `json:"promo_code_enabled"`
-	PasswordResetEnabled        bool   `json:"password_reset_enabled"`
-	InvitationCodeEnabled       bool   `json:"invitation_code_enabled"`
-	TotpEnabled                 bool   `json:"totp_enabled"` // TOTP 双因素认证
-	TurnstileEnabled            bool   `json:"turnstile_enabled"`
-	TurnstileSiteKey            string `json:"turnstile_site_key"`
-	SiteName                    string `json:"site_name"`
-	SiteLogo                    string `json:"site_logo"`
-	SiteSubtitle                string `json:"site_subtitle"`
-	APIBaseURL                  string `json:"api_base_url"`
-	ContactInfo                 string `json:"contact_info"`
-	DocURL                      string `json:"doc_url"`
-	HomeContent                 string `json:"home_content"`
-	HideCcsImportButton         bool   `json:"hide_ccs_import_button"`
-	PurchaseSubscriptionEnabled bool   `json:"purchase_subscription_enabled"`
-	PurchaseSubscriptionURL     string `json:"purchase_subscription_url"`
-	LinuxDoOAuthEnabled         bool   `json:"linuxdo_oauth_enabled"`
-	Version                     string `json:"version"`
+	RegistrationEnabled         bool                 `json:"registration_enabled"`
+	EmailVerifyEnabled          bool                 `json:"email_verify_enabled"`
+	PromoCodeEnabled            bool                 `json:"promo_code_enabled"`
+	PasswordResetEnabled        bool                 `json:"password_reset_enabled"`
+	InvitationCodeEnabled       bool                 `json:"invitation_code_enabled"`
+	TotpEnabled                 bool                 `json:"totp_enabled"`          // TOTP 双因素认证
+	PasskeyLoginEnabled         bool                 `json:"passkey_login_enabled"` // 通行密钥无密码登录
+	TurnstileEnabled            bool                 `json:"turnstile_enabled"`
+	TurnstileSiteKey            string               `json:"turnstile_site_key"`
+	SiteName                    string               `json:"site_name"`
+	SiteLogo                    string               `json:"site_logo"`
+	SiteSubtitle                string               `json:"site_subtitle"`
+	APIBaseURL                  string               `json:"api_base_url"`
+	ContactInfo                 string               `json:"contact_info"`
+	DocURL                      string               `json:"doc_url"`
+	HomeContent                 string               `json:"home_content"`
+	HideCcsImportButton         bool                 `json:"hide_ccs_import_button"`
+	PurchaseSubscriptionEnabled bool                 `json:"purchase_subscription_enabled"`
+	PurchaseSubscriptionURL     string               `json:"purchase_subscription_url"`
+	LinuxDoOAuthEnabled         bool                 `json:"linuxdo_oauth_enabled"`
+	OIDCProviders               []OIDCPublicProvider `json:"oidc_providers"`
+	ReferralEnabled             bool                 `json:"referral_enabled"`
+	Version                     string               `json:"version"`
+}
+
+// OIDCPublicProvider 登录页展示的 OIDC 提供方
+type OIDCPublicProvider struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
 }
 
 // StreamTimeoutSettings 流超时处理配置 DTO
@@ -3,6 +3,8 @@ package dto
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
 )
 
 // OptionalFloat64 用于区分 JSON 中"字段不存在"和"字段为 null"的情况。
@@ -152,6 +154,14 @@ type AdminGroup struct {
 
 	// 分组排序
 	SortOrder int `json:"sort_order"`
+
+	// 对冲请求配置
+	HedgeEnabled  bool    `json:"hedge_enabled"`
+	HedgeDelayMs  int     `json:"hedge_delay_ms"`
+	HedgeMaxRatio float64 `json:"hedge_max_ratio"`
+
+	// 调度时间窗口
+	ScheduleRule *service.ScheduleRule `json:"schedule_rule"`
 }
 
 type Account struct {
@@ -446,7 +456,7 @@ type BulkAssignResult struct {
 	Errors        []string                `json:"errors"`
 }
 
-// PromoCode 注册优惠码
+// PromoCode 优惠码
 type PromoCode struct {
 	ID          int64      `json:"id"`
 	Code        string     `json:"code"`
@@ -458,6 +468,16 @@ type PromoCode struct {
 	Notes       string     `json:"notes"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	BonusType       string     `json:"bonus_type"`
+	BonusPercent    float64    `json:"bonus_percent"`
+	MaxBonusAmount  float64    `json:"max_bonus_amount"`
+	PerUserLimit    int        `json:"per_user_limit"`
+	Scope           string     `json:"scope"`
+	NewUsersOnly    bool       `json:"new_users_only"`
+	NewUserDays     int        `json:"new_user_days"`
+	RequiredGroupID *int64     `json:"required_group_id"`
+	StartsAt        *time.Time `json:"starts_at"`
 }
 
 // PromoCodeUsage 优惠码使用记录
@@ -468,5 +488,29 @@ type PromoCodeUsage struct {
 	BonusAmount float64   `json:"bonus_amount"`
 	UsedAt      time.Time `json:"used_at"`
 
+	BonusType      string     `json:"bonus_type"`
+	BonusPercent   float64    `json:"bonus_percent"`
+	MaxBonusAmount float64    `json:"max_bonus_amount"`
+	Status         string     `json:"status"`
+	Source         string     `json:"source"`
+	BaseAmount     float64    `json:"base_amount"`
+	RedeemCodeID   *int64     `json:"redeem_code_id,omitempty"`
+	AppliedAt      *time.Time `json:"applied_at"`
+
 	User *User `json:"user,omitempty"`
 }
+
+// UserPromoCodeUsage 用户自己的优惠码使用记录
+type UserPromoCodeUsage struct {
+	ID             int64      `json:"id"`
+	Code           string     `json:"code"`
+	BonusType      string     `json:"bonus_type"`
+	BonusAmount    float64    `json:"bonus_amount"`
+	BonusPercent   float64    `json:"bonus_percent"`
+	MaxBonusAmount float64    `json:"max_bonus_amount"`
+	Status         string     `json:"status"`
+	Source         string     `json:"source"`
+	BaseAmount     float64    `json:"base_amount"`
+	UsedAt         time.Time  `json:"used_at"`
+	AppliedAt      *time.Time `json:"applied_at"`
+}
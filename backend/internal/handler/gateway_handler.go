@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -21,6 +20,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -39,6 +39,7 @@ type GatewayHandler struct {
 	apiKeyService             *service.APIKeyService
 	errorPassthroughService   *service.ErrorPassthroughService
 	concurrencyHelper         *ConcurrencyHelper
+	hedgeBudget               *hedgeBudget
 	maxAccountSwitches        int
 	maxAccountSwitchesGemini  int
 }
@@ -78,6 +79,7 @@ func NewGatewayHandler(
 		apiKeyService:             apiKeyService,
 		errorPassthroughService:   errorPassthroughService,
 		concurrencyHelper:         NewConcurrencyHelper(concurrencyService, SSEPingFormatClaude, pingInterval),
+		hedgeBudget:               newHedgeBudget(),
 		maxAccountSwitches:        maxAccountSwitches,
 		maxAccountSwitchesGemini:  maxAccountSwitchesGemini,
 	}
@@ -152,6 +154,8 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 
 	// 在请求上下文中记录 thinking 状态，供 Antigravity 最终模型 key 推导/模型维度限流使用
 	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxkey.ThinkingEnabled, parsedReq.ThinkingEnabled))
+	// 记录预估 token 消耗，调度时跳过上游限流余量不足的 API Key 账号
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxkey.RateLimitCostEstimate, service.EstimateRateLimitCost(len(body), parsedReq.MaxTokens)))
 
 	setOpsRequestContext(c, reqModel, reqStream, body)
 
@@ -175,7 +179,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 	canWait, err := h.concurrencyHelper.IncrementWaitCount(c.Request.Context(), subject.UserID, maxWait)
 	waitCounted := false
 	if err != nil {
-		log.Printf("Increment wait count failed: %v", err)
+		logger.Module("gateway").WarnContext(c.Request.Context(), "increment wait count failed", "error", err)
 		// On error, allow request to proceed
 	} else if !canWait {
 		errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Too many pending requests, please retry later", nil, "")
@@ -195,7 +199,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 	// 1. 首先获取用户并发槽位
 	userReleaseFunc, err := h.concurrencyHelper.AcquireUserSlotWithWait(c, subject.UserID, subject.Concurrency, reqStream, &streamStarted)
 	if err != nil {
-		log.Printf("User concurrency acquire failed: %v", err)
+		logger.Module("gateway").WarnContext(c.Request.Context(), "user concurrency acquire failed", "error", err)
 		errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Concurrency limit exceeded for user, please retry later", nil, "")
 		h.handleConcurrencyError(c, err, "user", streamStarted)
 		return
@@ -213,7 +217,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 
 	// 2. 【新增】Wait后二次检查余额/订阅
 	if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
-		log.Printf("Billing eligibility check failed after wait: %v", err)
+		logger.Module("gateway").WarnContext(c.Request.Context(), "billing eligibility check failed after wait", "error", err)
 		status, code, message := billingErrorDetails(err)
 		errCtx.recordError("billing_error", status, message, nil, "")
 		h.handleStreamingAwareError(c, status, code, message, streamStarted)
@@ -276,7 +280,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				// 谷歌上游 503 (MODEL_CAPACITY_EXHAUSTED) 通常是暂时性的，等几秒就能恢复。
 				if lastFailoverErr != nil && lastFailoverErr.StatusCode == http.StatusServiceUnavailable && switchCount <= maxAccountSwitches {
 					if sleepAntigravitySingleAccountBackoff(c.Request.Context(), switchCount) {
-						log.Printf("Antigravity single-account 503 retry: clearing failed accounts, retry %d/%d", switchCount, maxAccountSwitches)
+						logger.Module("gateway").InfoContext(c.Request.Context(), "antigravity single-account 503 retry: clearing failed accounts", "retry", switchCount, "max_retries", maxAccountSwitches)
 						failedAccountIDs = make(map[int64]struct{})
 						// 设置 context 标记，让 Service 层预检查等待限流过期而非直接切换
 						ctx := context.WithValue(c.Request.Context(), ctxkey.SingleAccountRetry, true)
@@ -323,9 +327,9 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				accountWaitCounted := false
 				canWait, err := h.concurrencyHelper.IncrementAccountWaitCount(c.Request.Context(), account.ID, selection.WaitPlan.MaxWaiting)
 				if err != nil {
-					log.Printf("Increment account wait count failed: %v", err)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "increment account wait count failed", "error", err)
 				} else if !canWait {
-					log.Printf("Account wait queue full: account=%d", account.ID)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "account wait queue full")
 					errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Too many pending requests, please retry later", nil, "")
 					h.handleStreamingAwareError(c, http.StatusTooManyRequests, "rate_limit_error", "Too many pending requests, please retry later", streamStarted)
 					return
@@ -344,12 +348,13 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					c,
 					account.ID,
 					selection.WaitPlan.MaxConcurrency,
+					account.GetModelConcurrencyLimit(reqModel),
 					selection.WaitPlan.Timeout,
 					reqStream,
 					&streamStarted,
 				)
 				if err != nil {
-					log.Printf("Account concurrency acquire failed: %v", err)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "account concurrency acquire failed", "error", err)
 					errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Concurrency limit exceeded for account, please retry later", nil, "")
 					h.handleConcurrencyError(c, err, "account", streamStarted)
 					return
@@ -360,7 +365,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					accountWaitCounted = false
 				}
 				if err := h.gatewayService.BindStickySession(c.Request.Context(), apiKey.GroupID, sessionKey, account.ID); err != nil {
-					log.Printf("Bind sticky session failed: %v", err)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "bind sticky session failed", "error", err)
 				}
 			}
 			// 账号槽位/等待计数需要在超时或断开时安全回收
@@ -391,8 +396,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					// 同账号重试：对 RetryableOnSameAccount 的临时性错误，先在同一账号上重试
 					if failoverErr.RetryableOnSameAccount && sameAccountRetryCount[account.ID] < maxSameAccountRetries {
 						sameAccountRetryCount[account.ID]++
-						log.Printf("Account %d: retryable error %d, same-account retry %d/%d",
-							account.ID, failoverErr.StatusCode, sameAccountRetryCount[account.ID], maxSameAccountRetries)
+						logger.Module("gateway").InfoContext(c.Request.Context(), "retryable upstream error, same-account retry", "status", failoverErr.StatusCode, "retry", sameAccountRetryCount[account.ID], "max_retries", maxSameAccountRetries)
 						if !sleepSameAccountRetryDelay(c.Request.Context()) {
 							return
 						}
@@ -411,7 +415,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 						return
 					}
 					switchCount++
-					log.Printf("Account %d: upstream error %d, switching account %d/%d", account.ID, failoverErr.StatusCode, switchCount, maxAccountSwitches)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "upstream error, switching account", "status", failoverErr.StatusCode, "switch", switchCount, "max_switches", maxAccountSwitches)
 					if account.Platform == service.PlatformAntigravity {
 						if !sleepFailoverDelay(c.Request.Context(), switchCount) {
 							return
@@ -421,7 +425,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				}
 				// 错误响应已在Forward中处理，记录 forward_error
 				errCtx.recordError("forward_error", http.StatusBadGateway, err.Error(), nil, "")
-				log.Printf("Forward request failed: %v", err)
+				logger.Module("gateway").ErrorContext(c.Request.Context(), "forward request failed", "error", err)
 				return
 			}
 
@@ -429,6 +433,8 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			userAgent := c.GetHeader("User-Agent")
 			clientIP := ip.GetClientIP(c)
 
+			setOpsForwardResult(c, result.RequestID, result.Duration, result.FirstTokenMs, result.Usage.InputTokens, result.Usage.OutputTokens)
+
 			// 异步记录使用量（subscription已在函数开头获取）
 			go func(result *service.ForwardResult, usedAccount *service.Account, ua, clientIP string, fcb bool) {
 				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -445,7 +451,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					ForceCacheBilling:  fcb,
 					APIKeyService:      h.apiKeyService,
 				}); err != nil {
-					log.Printf("Record usage failed: %v", err)
+					logger.Module("gateway").ErrorContext(ctx, "record usage failed", "account_id", usedAccount.ID, "error", err)
 				}
 			}(result, account, userAgent, clientIP, forceCacheBilling)
 			return
@@ -490,7 +496,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				// 谷歌上游 503 (MODEL_CAPACITY_EXHAUSTED) 通常是暂时性的，等几秒就能恢复。
 				if lastFailoverErr != nil && lastFailoverErr.StatusCode == http.StatusServiceUnavailable && switchCount <= maxAccountSwitches {
 					if sleepAntigravitySingleAccountBackoff(c.Request.Context(), switchCount) {
-						log.Printf("Antigravity single-account 503 retry: clearing failed accounts, retry %d/%d", switchCount, maxAccountSwitches)
+						logger.Module("gateway").InfoContext(c.Request.Context(), "antigravity single-account 503 retry: clearing failed accounts", "retry", switchCount, "max_retries", maxAccountSwitches)
 						failedAccountIDs = make(map[int64]struct{})
 						// 设置 context 标记，让 Service 层预检查等待限流过期而非直接切换
 						ctx := context.WithValue(c.Request.Context(), ctxkey.SingleAccountRetry, true)
@@ -537,9 +543,9 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				accountWaitCounted := false
 				canWait, err := h.concurrencyHelper.IncrementAccountWaitCount(c.Request.Context(), account.ID, selection.WaitPlan.MaxWaiting)
 				if err != nil {
-					log.Printf("Increment account wait count failed: %v", err)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "increment account wait count failed", "error", err)
 				} else if !canWait {
-					log.Printf("Account wait queue full: account=%d", account.ID)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "account wait queue full")
 					errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Too many pending requests, please retry later", nil, "")
 					h.handleStreamingAwareError(c, http.StatusTooManyRequests, "rate_limit_error", "Too many pending requests, please retry later", streamStarted)
 					return
@@ -557,12 +563,13 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					c,
 					account.ID,
 					selection.WaitPlan.MaxConcurrency,
+					account.GetModelConcurrencyLimit(reqModel),
 					selection.WaitPlan.Timeout,
 					reqStream,
 					&streamStarted,
 				)
 				if err != nil {
-					log.Printf("Account concurrency acquire failed: %v", err)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "account concurrency acquire failed", "error", err)
 					errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Concurrency limit exceeded for account, please retry later", nil, "")
 					h.handleConcurrencyError(c, err, "account", streamStarted)
 					return
@@ -572,7 +579,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					accountWaitCounted = false
 				}
 				if err := h.gatewayService.BindStickySession(c.Request.Context(), currentAPIKey.GroupID, sessionKey, account.ID); err != nil {
-					log.Printf("Bind sticky session failed: %v", err)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "bind sticky session failed", "error", err)
 				}
 			}
 			// 账号槽位/等待计数需要在超时或断开时安全回收
@@ -612,9 +619,36 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			if account.Platform == service.PlatformAntigravity && account.Type != service.AccountTypeAPIKey {
 				// Antigravity 平台不支持缓存转移，cacheTransferRatio 保持为 0
 				cacheTransferRatio = 0
-				result, err = h.antigravityGatewayService.Forward(requestCtx, c, account, body, hasBoundSession)
+			}
+			forward := func(ctx context.Context, fc *gin.Context, acc *service.Account) (*service.ForwardResult, error) {
+				if acc.Platform == service.PlatformAntigravity && acc.Type != service.AccountTypeAPIKey {
+					return h.antigravityGatewayService.Forward(ctx, fc, acc, body, hasBoundSession)
+				}
+				return h.gatewayService.Forward(ctx, fc, acc, parsedReq, cacheTransferRatio)
+			}
+			if group := currentAPIKey.Group; group != nil && group.IsHedgeEnabled() {
+				// 对冲请求：首账号响应头超时后向第二个账号并发请求，仅胜出方计费
+				outcome := h.forwardWithHedge(c, currentAPIKey.GroupID, group, account, reqModel, failedAccountIDs, func(_ context.Context, fc *gin.Context, acc *service.Account) (*service.ForwardResult, error) {
+					legCtx := fc.Request.Context()
+					if switchCount > 0 {
+						legCtx = context.WithValue(legCtx, ctxkey.AccountSwitchCount, switchCount)
+					}
+					return forward(legCtx, fc, acc)
+				})
+				for _, id := range outcome.failedAccountIDs {
+					failedAccountIDs[id] = struct{}{}
+				}
+				if outcome.account.ID != account.ID {
+					account = outcome.account
+					errCtx.setAccount(account)
+					setOpsSelectedAccount(c, account.ID)
+					if account.Platform == service.PlatformAntigravity && account.Type != service.AccountTypeAPIKey {
+						cacheTransferRatio = 0
+					}
+				}
+				result, err = outcome.result, outcome.err
 			} else {
-				result, err = h.gatewayService.Forward(requestCtx, c, account, parsedReq, cacheTransferRatio)
+				result, err = forward(requestCtx, c, account)
 			}
 			if accountReleaseFunc != nil {
 				accountReleaseFunc()
@@ -622,18 +656,18 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			if err != nil {
 				var promptTooLongErr *service.PromptTooLongError
 				if errors.As(err, &promptTooLongErr) {
-					log.Printf("Prompt too long from antigravity: group=%d fallback_group_id=%v fallback_used=%v", currentAPIKey.GroupID, fallbackGroupID, fallbackUsed)
+					logger.Module("gateway").InfoContext(c.Request.Context(), "prompt too long from antigravity", "fallback_group_id", fallbackGroupID, "fallback_used", fallbackUsed)
 					if !fallbackUsed && fallbackGroupID != nil && *fallbackGroupID > 0 {
 						fallbackGroup, err := h.gatewayService.ResolveGroupByID(c.Request.Context(), *fallbackGroupID)
 						if err != nil {
-							log.Printf("Resolve fallback group failed: %v", err)
+							logger.Module("gateway").WarnContext(c.Request.Context(), "resolve fallback group failed", "error", err)
 							_ = h.antigravityGatewayService.WriteMappedClaudeError(c, account, promptTooLongErr.StatusCode, promptTooLongErr.RequestID, promptTooLongErr.Body)
 							return
 						}
 						if fallbackGroup.Platform != service.PlatformAnthropic ||
 							fallbackGroup.SubscriptionType == service.SubscriptionTypeSubscription ||
 							fallbackGroup.FallbackGroupIDOnInvalidRequest != nil {
-							log.Printf("Fallback group invalid: group=%d platform=%s subscription=%s", fallbackGroup.ID, fallbackGroup.Platform, fallbackGroup.SubscriptionType)
+							logger.Module("gateway").WarnContext(c.Request.Context(), "fallback group invalid", "fallback_group_id", fallbackGroup.ID, "fallback_platform", fallbackGroup.Platform, "fallback_subscription_type", fallbackGroup.SubscriptionType)
 							_ = h.antigravityGatewayService.WriteMappedClaudeError(c, account, promptTooLongErr.StatusCode, promptTooLongErr.RequestID, promptTooLongErr.Body)
 							return
 						}
@@ -666,8 +700,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					// 同账号重试：对 RetryableOnSameAccount 的临时性错误，先在同一账号上重试
 					if failoverErr.RetryableOnSameAccount && sameAccountRetryCount[account.ID] < maxSameAccountRetries {
 						sameAccountRetryCount[account.ID]++
-						log.Printf("Account %d: retryable error %d, same-account retry %d/%d",
-							account.ID, failoverErr.StatusCode, sameAccountRetryCount[account.ID], maxSameAccountRetries)
+						logger.Module("gateway").InfoContext(c.Request.Context(), "retryable upstream error, same-account retry", "status", failoverErr.StatusCode, "retry", sameAccountRetryCount[account.ID], "max_retries", maxSameAccountRetries)
 						if !sleepSameAccountRetryDelay(c.Request.Context()) {
 							return
 						}
@@ -686,7 +719,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 						return
 					}
 					switchCount++
-					log.Printf("Account %d: upstream error %d, switching account %d/%d", account.ID, failoverErr.StatusCode, switchCount, maxAccountSwitches)
+					logger.Module("gateway").WarnContext(c.Request.Context(), "upstream error, switching account", "status", failoverErr.StatusCode, "switch", switchCount, "max_switches", maxAccountSwitches)
 					if account.Platform == service.PlatformAntigravity {
 						if !sleepFailoverDelay(c.Request.Context(), switchCount) {
 							return
@@ -696,7 +729,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				}
 				// 错误响应已在Forward中处理，记录 forward_error
 				errCtx.recordError("forward_error", http.StatusBadGateway, err.Error(), nil, "")
-				log.Printf("Account %d: Forward request failed: %v", account.ID, err)
+				logger.Module("gateway").ErrorContext(c.Request.Context(), "forward request failed", "error", err)
 				return
 			}
 
@@ -704,6 +737,8 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			userAgent := c.GetHeader("User-Agent")
 			clientIP := ip.GetClientIP(c)
 
+			setOpsForwardResult(c, result.RequestID, result.Duration, result.FirstTokenMs, result.Usage.InputTokens, result.Usage.OutputTokens)
+
 			// 异步记录使用量（subscription已在函数开头获取）
 			go func(result *service.ForwardResult, usedAccount *service.Account, ua, clientIP string, ratio float64, fcb bool) {
 				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -720,7 +755,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					ForceCacheBilling:  fcb,
 					APIKeyService:      h.apiKeyService,
 				}); err != nil {
-					log.Printf("Record usage failed: %v", err)
+					logger.Module("gateway").ErrorContext(ctx, "record usage failed", "account_id", usedAccount.ID, "error", err)
 				}
 			}(result, account, userAgent, clientIP, cacheTransferRatio, forceCacheBilling)
 			return
@@ -1005,7 +1040,7 @@ func sleepAntigravitySingleAccountBackoff(ctx context.Context, retryCount int) b
 	// Handler 层只需短暂间隔后重新进入 Service 层即可。
 	const delay = 2 * time.Second
 
-	log.Printf("Antigravity single-account 503 backoff: waiting %v before retry (attempt %d)", delay, retryCount)
+	logger.Module("gateway").InfoContext(ctx, "antigravity single-account 503 backoff", "delay", delay, "attempt", retryCount)
 
 	select {
 	case <-ctx.Done():
@@ -1215,7 +1250,7 @@ func (e *errorRecordingContext) recordError(errorType string, statusCode int, me
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if err := e.handler.gatewayService.RecordErrorUsage(ctx, input); err != nil {
-			log.Printf("Record error usage failed: %v", err)
+			logger.Module("gateway").ErrorContext(ctx, "record error usage failed", "error", err)
 		}
 	}()
 }
@@ -1303,7 +1338,7 @@ func (h *GatewayHandler) CountTokens(c *gin.Context) {
 
 	// 转发请求（不记录使用量）
 	if err := h.gatewayService.ForwardCountTokens(c.Request.Context(), c, account, parsedReq); err != nil {
-		log.Printf("Forward count_tokens request failed: %v", err)
+		logger.Module("gateway").ErrorContext(c.Request.Context(), "forward count_tokens request failed", "error", err)
 		// 错误响应已在 ForwardCountTokens 中处理
 		return
 	}
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 对冲请求（hedged requests）
+//
+// 对延迟敏感的分组，首账号在阈值内未返回响应头时，通过 SelectAccountForModelWithExclusions
+// 选出第二个账号并发起相同请求。两个请求各自写入独立的 gin.Context，先写出成功响应（< 400）的一方
+// 获得客户端输出权，另一方立即取消；错误响应先缓存，双方都失败时回放首账号的错误。
+// 仅胜出方计费，落败分支的上游用量计入对冲预算，额外上游 token 不超过正常用量的 hedge_max_ratio 倍。
+
+var errHedgeLegLost = errors.New("hedge leg lost the race")
+
+// hedgeBudgetBurstRequests 单个分组最多可累积相当于多少个平均请求的对冲额度（限制突发）
+const hedgeBudgetBurstRequests = 10.0
+
+// hedgeBudgetAvgWeight 分组平均请求 token 数的指数滑动平均权重
+const hedgeBudgetAvgWeight = 0.1
+
+// hedgeResultKeys 分支结束后合并回请求 context 的字段（ops 错误记录所需的上游信息），其余字段只在分支内有效
+var hedgeResultKeys = []string{
+	service.OpsUpstreamStatusCodeKey,
+	service.OpsUpstreamErrorMessageKey,
+	service.OpsUpstreamErrorDetailKey,
+	service.OpsUpstreamErrorsKey,
+	service.OpsUpstreamRequestBodyKey,
+	service.OpsSkipPassthroughKey,
+}
+
+// hedgeBudget 以上游 token 数近似额外开销，限制各分组对冲的额外花费：每个请求按实际用量的 ratio 倍累积额度，
+// 每次对冲先按分组平均请求用量预扣，竞速结束后改按落败分支的实际用量（未返回用量时以胜出分支用量估算）结算，
+// 因此长期额外上游 token 不超过正常用量的 ratio 倍。统计仅在进程内进行，多实例部署时各实例独立计算。
+type hedgeBudget struct {
+	mu     sync.Mutex
+	groups map[int64]*hedgeBudgetState
+}
+
+type hedgeBudgetState struct {
+	// allowance 剩余可用于对冲的上游 token 额度，落败分支用量超出预扣时可为负
+	allowance float64
+	// avgTokens 分组单个请求的平均上游 token 数，用于预扣和限制突发
+	avgTokens float64
+}
+
+func newHedgeBudget() *hedgeBudget {
+	return &hedgeBudget{groups: make(map[int64]*hedgeBudgetState)}
+}
+
+// observe 记录一次请求的上游用量并为分组累积对冲额度
+func (b *hedgeBudget) observe(groupID int64, ratio float64, tokens int) {
+	if ratio <= 0 || tokens <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st, ok := b.groups[groupID]
+	if !ok {
+		st = &hedgeBudgetState{avgTokens: float64(tokens)}
+		b.groups[groupID] = st
+	}
+	st.avgTokens += (float64(tokens) - st.avgTokens) * hedgeBudgetAvgWeight
+	st.allowance += ratio * float64(tokens)
+	if limit := hedgeBudgetBurstRequests * st.avgTokens; st.allowance > limit {
+		st.allowance = limit
+	}
+}
+
+// take 尝试为一次对冲预扣一个平均请求的额度，返回预扣的 token 数
+func (b *hedgeBudget) take(groupID int64) (float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st, ok := b.groups[groupID]
+	if !ok || st.avgTokens <= 0 || st.allowance < st.avgTokens {
+		return 0, false
+	}
+	st.allowance -= st.avgTokens
+	return st.avgTokens, true
+}
+
+// settle 以落败分支的上游用量替换 take 预扣的额度
+func (b *hedgeBudget) settle(groupID int64, reserved float64, tokens int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if st, ok := b.groups[groupID]; ok {
+		st.allowance += reserved - float64(tokens)
+	}
+}
+
+// hedgeUsageTokens 返回转发结果的上游 token 总数，无结果时返回 0
+func hedgeUsageTokens(result *service.ForwardResult) int {
+	if result == nil {
+		return 0
+	}
+	u := result.Usage
+	return u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// hedgeForwardFunc 在指定账号上转发请求（由调用方按账号平台分流）
+type hedgeForwardFunc func(ctx context.Context, c *gin.Context, account *service.Account) (*service.ForwardResult, error)
+
+// hedgeRace 协调多个对冲分支对客户端输出的争用
+type hedgeRace struct {
+	mu     sync.Mutex
+	dst    gin.ResponseWriter
+	legs   []*hedgeLeg
+	winner *hedgeLeg
+}
+
+// claim 尝试让 leg 获得客户端输出权，成功后取消其它分支
+func (r *hedgeRace) claim(leg *hedgeLeg) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.winner != nil {
+		return r.winner == leg
+	}
+	r.winner = leg
+	dstHeader := r.dst.Header()
+	for k, v := range leg.header {
+		dstHeader[k] = v
+	}
+	r.dst.WriteHeader(leg.status)
+	for _, other := range r.legs {
+		if other != leg {
+			other.cancel()
+		}
+	}
+	return true
+}
+
+func (r *hedgeRace) getWinner() *hedgeLeg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.winner
+}
+
+// hedgeLeg 是一次对冲分支，同时实现 http.ResponseWriter 供分支的 gin.Context 使用
+type hedgeLeg struct {
+	race    *hedgeRace
+	account *service.Account
+	release func()
+	cancel  context.CancelFunc
+	c       *gin.Context
+
+	header   http.Header
+	status   int
+	won      bool
+	buffered bytes.Buffer
+
+	firstByte     chan struct{}
+	firstByteOnce sync.Once
+	done          chan struct{}
+	result        *service.ForwardResult
+	err           error
+	// reserved 对冲分支从预算中预扣的 token 数，竞速结束后按落败分支用量结算
+	reserved float64
+	// keys 分支结束时从分支 context 取出的 hedgeResultKeys，done 关闭后可读
+	keys map[string]any
+}
+
+func (l *hedgeLeg) Header() http.Header {
+	return l.header
+}
+
+func (l *hedgeLeg) WriteHeader(code int) {
+	if l.status != 0 {
+		return
+	}
+	l.status = code
+	if code < http.StatusBadRequest {
+		l.won = l.race.claim(l)
+	}
+}
+
+func (l *hedgeLeg) Write(p []byte) (int, error) {
+	if l.status == 0 {
+		l.WriteHeader(http.StatusOK)
+	}
+	if l.won {
+		return l.race.dst.Write(p)
+	}
+	if l.status >= http.StatusBadRequest {
+		return l.buffered.Write(p)
+	}
+	return 0, errHedgeLegLost
+}
+
+func (l *hedgeLeg) Flush() {
+	if l.won {
+		l.race.dst.Flush()
+	}
+}
+
+// replay 将缓存的错误响应写回客户端（仅在没有分支胜出时调用）
+func (l *hedgeLeg) replay() {
+	if l.status == 0 {
+		return
+	}
+	dstHeader := l.race.dst.Header()
+	for k, v := range l.header {
+		dstHeader[k] = v
+	}
+	l.race.dst.WriteHeader(l.status)
+	_, _ = l.race.dst.Write(l.buffered.Bytes())
+}
+
+// startHedgeLeg 在独立的 gin.Context 中异步转发请求
+func startHedgeLeg(c *gin.Context, race *hedgeRace, account *service.Account, release func(), forward hedgeForwardFunc) *hedgeLeg {
+	leg := &hedgeLeg{
+		race:      race,
+		account:   account,
+		release:   release,
+		header:    make(http.Header),
+		firstByte: make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	// 各分支使用独立的日志字段容器，并发写入的 account_id 互不覆盖
+	ctx, cancel := context.WithCancel(logger.ForkRequestFields(c.Request.Context()))
+	leg.cancel = cancel
+	logger.SetAccount(ctx, account.ID, "")
+	// 上游返回首字节（响应头）时通知调度方，不再需要对冲
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			leg.firstByteOnce.Do(func() { close(leg.firstByte) })
+		},
+	})
+
+	legCtx, _ := gin.CreateTestContext(leg)
+	legCtx.Request = c.Request.Clone(ctx)
+	legCtx.Keys = hedgeLegKeys(c)
+	leg.c = legCtx
+
+	race.mu.Lock()
+	race.legs = append(race.legs, leg)
+	race.mu.Unlock()
+
+	go func() {
+		defer close(leg.done)
+		defer cancel()
+		leg.result, leg.err = forward(ctx, legCtx, account)
+		leg.keys = make(map[string]any, len(hedgeResultKeys))
+		for _, k := range hedgeResultKeys {
+			if v, ok := legCtx.Get(k); ok {
+				leg.keys[k] = v
+			}
+		}
+		if leg.release != nil {
+			leg.release()
+		}
+	}()
+	return leg
+}
+
+// hedgeLegKeys 按 gin.Context.Copy 的方式复制请求 context 字段供分支使用；
+// 上游错误列表截断容量，分支追加时各自分配新数组，不会与其它分支共享底层数组
+func hedgeLegKeys(c *gin.Context) map[string]any {
+	keys := c.Copy().Keys
+	if events, ok := keys[service.OpsUpstreamErrorsKey].([]*service.OpsUpstreamErrorEvent); ok {
+		keys[service.OpsUpstreamErrorsKey] = slices.Clip(events)
+	}
+	return keys
+}
+
+// hedgeOutcome 对冲转发的最终结果
+type hedgeOutcome struct {
+	// account/result/err 对应最终写给客户端的分支，调用方据此计费和做故障切换
+	account *service.Account
+	result  *service.ForwardResult
+	err     error
+	hedged  bool
+	// failedAccountIDs 未被采用但返回了可切换错误的对冲账号，调用方应从后续调度中排除
+	failedAccountIDs []int64
+}
+
+// forwardWithHedge 转发请求，首账号在分组阈值内未返回响应头时向第二个账号发起对冲请求。
+// primary 的并发槽位由调用方负责释放；对冲账号的槽位在本函数内获取并释放。
+func (h *GatewayHandler) forwardWithHedge(
+	c *gin.Context,
+	groupID *int64,
+	group *service.Group,
+	primary *service.Account,
+	reqModel string,
+	excludedIDs map[int64]struct{},
+	forward hedgeForwardFunc,
+) hedgeOutcome {
+	race := &hedgeRace{dst: c.Writer}
+	primaryLeg := startHedgeLeg(c, race, primary, nil, forward)
+
+	var secondaryLeg *hedgeLeg
+	timer := time.NewTimer(group.HedgeDelay())
+	select {
+	case <-primaryLeg.firstByte:
+	case <-primaryLeg.done:
+	case <-timer.C:
+		secondaryLeg = h.startSecondaryHedgeLeg(c, race, groupID, group, primary, reqModel, excludedIDs, forward)
+	}
+	timer.Stop()
+
+	<-primaryLeg.done
+	if secondaryLeg != nil {
+		<-secondaryLeg.done
+	}
+
+	reported := race.getWinner()
+	if reported == nil {
+		reported = primaryLeg
+		if reported.status == 0 && secondaryLeg != nil && secondaryLeg.status != 0 && !isUpstreamFailoverError(secondaryLeg.err) {
+			reported = secondaryLeg
+		}
+		reported.replay()
+	}
+
+	// 合并采用分支记录的上游错误信息（供 ops 错误日志使用）
+	for k, v := range reported.keys {
+		c.Set(k, v)
+	}
+
+	out := hedgeOutcome{
+		account: reported.account,
+		result:  reported.result,
+		err:     reported.err,
+		hedged:  secondaryLeg != nil,
+	}
+	for _, leg := range []*hedgeLeg{primaryLeg, secondaryLeg} {
+		if leg == nil || leg == reported {
+			continue
+		}
+		if isUpstreamFailoverError(leg.err) {
+			out.failedAccountIDs = append(out.failedAccountIDs, leg.account.ID)
+		}
+	}
+	h.settleHedgeBudget(group, reported, primaryLeg, secondaryLeg)
+	if out.hedged {
+		log.Printf("[Hedge] group=%d primary=%d hedge=%d winner=%d", group.ID, primary.ID, secondaryLeg.account.ID, reported.account.ID)
+	}
+	return out
+}
+
+// settleHedgeBudget 按采用分支的用量累积对冲额度，并以落败分支的用量结算对冲预扣。
+// 落败分支被取消时通常拿不到用量，按采用分支的用量估算（同一请求，作为上限）
+func (h *GatewayHandler) settleHedgeBudget(group *service.Group, reported, primaryLeg, secondaryLeg *hedgeLeg) {
+	served := hedgeUsageTokens(reported.result)
+	h.hedgeBudget.observe(group.ID, group.HedgeMaxRatio, served)
+	if secondaryLeg == nil {
+		return
+	}
+	loser := primaryLeg
+	if reported == primaryLeg {
+		loser = secondaryLeg
+	}
+	lost := hedgeUsageTokens(loser.result)
+	if lost == 0 {
+		lost = served
+	}
+	h.hedgeBudget.settle(group.ID, secondaryLeg.reserved, lost)
+}
+
+// startSecondaryHedgeLeg 选择对冲账号并发起请求，预算不足或无可用账号时返回 nil
+func (h *GatewayHandler) startSecondaryHedgeLeg(
+	c *gin.Context,
+	race *hedgeRace,
+	groupID *int64,
+	group *service.Group,
+	primary *service.Account,
+	reqModel string,
+	excludedIDs map[int64]struct{},
+	forward hedgeForwardFunc,
+) *hedgeLeg {
+	if race.getWinner() != nil {
+		return nil
+	}
+
+	excluded := make(map[int64]struct{}, len(excludedIDs)+1)
+	for id := range excludedIDs {
+		excluded[id] = struct{}{}
+	}
+	excluded[primary.ID] = struct{}{}
+
+	ctx := c.Request.Context()
+	account, err := h.gatewayService.SelectAccountForModelWithExclusions(ctx, groupID, "", reqModel, excluded)
+	if err != nil || account == nil {
+		return nil
+	}
+	release, ok, err := h.concurrencyHelper.TryAcquireAccountSlot(ctx, account.ID, account.Concurrency, account.GetModelConcurrencyLimit(reqModel))
+	if err != nil || !ok {
+		return nil
+	}
+	reserved, ok := h.hedgeBudget.take(group.ID)
+	if !ok {
+		release()
+		return nil
+	}
+	leg := startHedgeLeg(c, race, account, wrapReleaseOnDone(ctx, release), forward)
+	leg.reserved = reserved
+	return leg
+}
+
+func isUpstreamFailoverError(err error) bool {
+	var failoverErr *service.UpstreamFailoverError
+	return errors.As(err, &failoverErr)
+}
@@ -0,0 +1,230 @@
+//go:build unit
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newHedgeTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+	return c, w
+}
+
+func TestHedgeBudget_ChargesLoserUsage(t *testing.T) {
+	b := newHedgeBudget()
+
+	// 两个 1000 token 的请求按 0.5 累积 1000 token 额度，可预扣一次平均用量
+	b.observe(1, 0.5, 1000)
+	b.observe(1, 0.5, 1000)
+	reserved, ok := b.take(1)
+	require.True(t, ok)
+	require.InDelta(t, 1000, reserved, 0.001)
+	_, ok = b.take(1)
+	require.False(t, ok)
+
+	// 落败分支实际消耗 3000 token，超出预扣的部分需由后续请求偿还
+	b.settle(1, reserved, 3000)
+	for i := 0; i < 4; i++ {
+		b.observe(1, 0.5, 1000)
+	}
+	_, ok = b.take(1)
+	require.False(t, ok)
+	b.observe(1, 0.5, 1000)
+	b.observe(1, 0.5, 1000)
+	_, ok = b.take(1)
+	require.True(t, ok)
+
+	// 未观测到请求的分组没有额度
+	_, ok = b.take(2)
+	require.False(t, ok)
+}
+
+func TestHedgeBudget_CapsBurst(t *testing.T) {
+	b := newHedgeBudget()
+	for i := 0; i < 100; i++ {
+		b.observe(1, 1, 1000)
+	}
+	taken := 0
+	for {
+		if _, ok := b.take(1); !ok {
+			break
+		}
+		taken++
+	}
+	require.Equal(t, int(hedgeBudgetBurstRequests), taken)
+}
+
+func TestHedgeRace_FirstSuccessWinsAndCancelsLoser(t *testing.T) {
+	c, w := newHedgeTestContext()
+	c.Set("existing", "value")
+	race := &hedgeRace{dst: c.Writer}
+
+	slow := startHedgeLeg(c, race, &service.Account{ID: 1}, nil, func(ctx context.Context, fc *gin.Context, _ *service.Account) (*service.ForwardResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	fast := startHedgeLeg(c, race, &service.Account{ID: 2}, nil, func(_ context.Context, fc *gin.Context, _ *service.Account) (*service.ForwardResult, error) {
+		require.Equal(t, "value", fc.GetString("existing"))
+		fc.Header("X-Leg", "fast")
+		fc.String(http.StatusOK, "hello")
+		return &service.ForwardResult{}, nil
+	})
+
+	select {
+	case <-slow.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("loser leg was not cancelled")
+	}
+	<-fast.done
+
+	require.Equal(t, fast, race.getWinner())
+	require.ErrorIs(t, slow.err, context.Canceled)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "hello", w.Body.String())
+	require.Equal(t, "fast", w.Header().Get("X-Leg"))
+}
+
+func TestHedgeRace_ErrorResponsesAreBuffered(t *testing.T) {
+	c, w := newHedgeTestContext()
+	race := &hedgeRace{dst: c.Writer}
+
+	leg := startHedgeLeg(c, race, &service.Account{ID: 1}, nil, func(_ context.Context, fc *gin.Context, _ *service.Account) (*service.ForwardResult, error) {
+		fc.String(http.StatusBadGateway, "upstream failed")
+		return nil, context.DeadlineExceeded
+	})
+	<-leg.done
+
+	require.Nil(t, race.getWinner())
+	require.Equal(t, 0, w.Body.Len())
+
+	leg.replay()
+	require.Equal(t, http.StatusBadGateway, w.Code)
+	require.Equal(t, "upstream failed", w.Body.String())
+}
+
+func TestForwardWithHedge_NoHedgeWhenPrimaryRespondsInTime(t *testing.T) {
+	c, w := newHedgeTestContext()
+	h := &GatewayHandler{hedgeBudget: newHedgeBudget()}
+	group := &service.Group{ID: 1, HedgeEnabled: true, HedgeDelayMs: 60000, HedgeMaxRatio: 1}
+	primary := &service.Account{ID: 7}
+
+	outcome := h.forwardWithHedge(c, &group.ID, group, primary, "claude-sonnet-4-5", nil, func(_ context.Context, fc *gin.Context, acc *service.Account) (*service.ForwardResult, error) {
+		fc.Set(service.OpsUpstreamStatusCodeKey, 529)
+		fc.Set("leg_only", acc.ID)
+		fc.String(http.StatusOK, "ok")
+		return &service.ForwardResult{Model: "claude-sonnet-4-5"}, nil
+	})
+
+	require.NoError(t, outcome.err)
+	require.False(t, outcome.hedged)
+	require.Equal(t, primary, outcome.account)
+	require.Equal(t, "claude-sonnet-4-5", outcome.result.Model)
+	require.Equal(t, "ok", w.Body.String())
+	// 只合并 ops 所需的上游信息
+	require.Equal(t, 529, c.GetInt(service.OpsUpstreamStatusCodeKey))
+	_, ok := c.Get("leg_only")
+	require.False(t, ok)
+}
+
+func TestForwardWithHedge_ReplaysPrimaryErrorWhenNoHedge(t *testing.T) {
+	c, w := newHedgeTestContext()
+	h := &GatewayHandler{hedgeBudget: newHedgeBudget()}
+	group := &service.Group{ID: 1, HedgeEnabled: true, HedgeDelayMs: 60000, HedgeMaxRatio: 1}
+
+	outcome := h.forwardWithHedge(c, &group.ID, group, &service.Account{ID: 7}, "m", nil, func(_ context.Context, fc *gin.Context, _ *service.Account) (*service.ForwardResult, error) {
+		fc.String(http.StatusBadRequest, "bad request")
+		return nil, context.Canceled
+	})
+
+	require.Error(t, outcome.err)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "bad request", w.Body.String())
+}
+
+// hedgeAccountRepoStub 只实现对冲选号用到的方法
+type hedgeAccountRepoStub struct {
+	service.AccountRepository
+	accounts []service.Account
+}
+
+func (r *hedgeAccountRepoStub) ListSchedulableByPlatforms(context.Context, []string) ([]service.Account, error) {
+	return r.accounts, nil
+}
+
+// hedgeConcurrencyCacheStub 槽位总是可用
+type hedgeConcurrencyCacheStub struct {
+	service.ConcurrencyCache
+}
+
+func (hedgeConcurrencyCacheStub) AcquireAccountSlot(context.Context, int64, int, string) (bool, error) {
+	return true, nil
+}
+
+func (hedgeConcurrencyCacheStub) ReleaseAccountSlot(context.Context, int64, string) error {
+	return nil
+}
+
+// 首账号超时后经 startSecondaryHedgeLeg 发起真实的对冲分支；两个分支并发写各自的 context，
+// 需配合 -race 运行以发现分支间共享的 context 数据
+func TestForwardWithHedge_SecondaryLegDoesNotShareContext(t *testing.T) {
+	c, w := newHedgeTestContext()
+	base := make([]*service.OpsUpstreamErrorEvent, 1, 8)
+	base[0] = &service.OpsUpstreamErrorEvent{Kind: "failover"}
+	c.Set(service.OpsUpstreamErrorsKey, base)
+	c.Set("existing", "value")
+
+	accounts := []service.Account{
+		{ID: 7, Platform: service.PlatformAnthropic, Type: service.AccountTypeAPIKey, Status: service.StatusActive, Schedulable: true, Concurrency: 1},
+		{ID: 8, Platform: service.PlatformAnthropic, Type: service.AccountTypeAPIKey, Status: service.StatusActive, Schedulable: true, Concurrency: 1},
+	}
+	h := &GatewayHandler{
+		gatewayService:    service.NewGatewayService(&hedgeAccountRepoStub{accounts: accounts}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
+		concurrencyHelper: NewConcurrencyHelper(service.NewConcurrencyService(hedgeConcurrencyCacheStub{}), SSEPingFormatNone, time.Second),
+		hedgeBudget:       newHedgeBudget(),
+	}
+	group := &service.Group{ID: 1, HedgeEnabled: true, HedgeDelayMs: 20, HedgeMaxRatio: 1}
+	h.hedgeBudget.observe(group.ID, group.HedgeMaxRatio, 1000)
+
+	appendEvent := func(fc *gin.Context, accountID int64) {
+		events := fc.MustGet(service.OpsUpstreamErrorsKey).([]*service.OpsUpstreamErrorEvent)
+		fc.Set(service.OpsUpstreamErrorsKey, append(events, &service.OpsUpstreamErrorEvent{AccountID: accountID}))
+		fc.Set(service.OpsUpstreamStatusCodeKey, int(accountID))
+	}
+	outcome := h.forwardWithHedge(c, nil, group, &accounts[0], "", nil, func(ctx context.Context, fc *gin.Context, acc *service.Account) (*service.ForwardResult, error) {
+		require.Equal(t, "value", fc.GetString("existing"))
+		appendEvent(fc, acc.ID)
+		if acc.ID == 7 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		fc.String(http.StatusOK, "hedged")
+		return &service.ForwardResult{Usage: service.ClaudeUsage{InputTokens: 600, OutputTokens: 400}}, nil
+	})
+
+	require.NoError(t, outcome.err)
+	require.True(t, outcome.hedged)
+	require.Equal(t, int64(8), outcome.account.ID)
+	require.Equal(t, "hedged", w.Body.String())
+
+	// 只合并胜出分支的上游错误，且没有写入原始列表的底层数组
+	events := c.MustGet(service.OpsUpstreamErrorsKey).([]*service.OpsUpstreamErrorEvent)
+	require.Len(t, events, 2)
+	require.Equal(t, int64(8), events[1].AccountID)
+	require.Equal(t, 8, c.GetInt(service.OpsUpstreamStatusCodeKey))
+	require.Nil(t, base[:2][1])
+
+	// 落败分支被取消没有用量，按胜出分支的 1000 token 结算：预扣 1000、累积 1000，额度回到 1000
+	require.InDelta(t, 1000, h.hedgeBudget.groups[group.ID].allowance, 0.001)
+}
@@ -25,6 +25,17 @@ type AdminHandlers struct {
 	Usage            *admin.UsageHandler
 	UserAttribute    *admin.UserAttributeHandler
 	ErrorPassthrough *admin.ErrorPassthroughHandler
+	AccountProbe     *admin.AccountProbeHandler
+	APIKeyAbuse      *admin.APIKeyAbuseHandler
+	SpendGuard       *admin.SpendGuardHandler
+	OIDCProvider     *admin.OIDCProviderHandler
+	UserSession      *admin.UserSessionHandler
+	LoginGuard       *admin.LoginGuardHandler
+	Referral         *admin.ReferralHandler
+	Impersonation    *admin.ImpersonationHandler
+	AccountDeletion  *admin.AccountDeletionHandler
+	SubscriptionPlan *admin.SubscriptionPlanHandler
+	Payment          *admin.PaymentHandler
 }
 
 // Handlers contains all HTTP handlers
@@ -34,13 +45,21 @@ type Handlers struct {
 	APIKey        *APIKeyHandler
 	Usage         *UsageHandler
 	Redeem        *RedeemHandler
+	Promo         *PromoHandler
+	Referral      *ReferralHandler
+	Impersonation *ImpersonationHandler
+	UserData      *UserDataHandler
 	Subscription  *SubscriptionHandler
+	Plan          *SubscriptionPlanHandler
+	Payment       *PaymentHandler
 	Announcement  *AnnouncementHandler
 	Admin         *AdminHandlers
 	Gateway       *GatewayHandler
 	OpenAIGateway *OpenAIGatewayHandler
 	Setting       *SettingHandler
 	Totp          *TotpHandler
+	WebAuthn      *WebAuthnHandler
+	Status        *StatusHandler
 }
 
 // BuildInfo contains build-time information
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler handles ending an admin impersonation session from the impersonated side
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new ImpersonationHandler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// Exit ends the current impersonation session so the token stops working immediately
+// POST /api/v1/auth/impersonation/exit
+func (h *ImpersonationHandler) Exit(c *gin.Context) {
+	session, ok := middleware2.GetImpersonationFromContext(c)
+	if !ok {
+		response.BadRequest(c, "Not impersonating")
+		return
+	}
+
+	if _, err := h.impersonationService.End(c.Request.Context(), session.ID, session.AdminUserID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Impersonation ended"})
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -14,7 +13,9 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -144,6 +145,10 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 
 	setOpsRequestContext(c, reqModel, reqStream, body)
 
+	// 记录预估 token 消耗，调度时跳过上游限流余量不足的 API Key 账号
+	maxOutputTokens, _ := reqBody["max_output_tokens"].(float64)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxkey.RateLimitCostEstimate, service.EstimateRateLimitCost(len(body), int(maxOutputTokens))))
+
 	// 提前校验 function_call_output 是否具备可关联上下文，避免上游 400。
 	// 要求 previous_response_id，或 input 内存在带 call_id 的 tool_call/function_call，
 	// 或带 id 且与 call_id 匹配的 item_reference。
@@ -151,14 +156,14 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		previousResponseID, _ := reqBody["previous_response_id"].(string)
 		if strings.TrimSpace(previousResponseID) == "" && !service.HasToolCallContext(reqBody) {
 			if service.HasFunctionCallOutputMissingCallID(reqBody) {
-				log.Printf("[OpenAI Handler] function_call_output 缺少 call_id: model=%s", reqModel)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "function_call_output missing call_id", "model", reqModel)
 				errCtx.recordError("invalid_request", http.StatusBadRequest, "function_call_output requires call_id or previous_response_id", nil, "")
 				h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "function_call_output requires call_id or previous_response_id; if relying on history, ensure store=true and reuse previous_response_id")
 				return
 			}
 			callIDs := service.FunctionCallOutputCallIDs(reqBody)
 			if !service.HasItemReferenceForCallIDs(reqBody, callIDs) {
-				log.Printf("[OpenAI Handler] function_call_output 缺少匹配的 item_reference: model=%s", reqModel)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "function_call_output missing matching item_reference", "model", reqModel)
 				errCtx.recordError("invalid_request", http.StatusBadRequest, "function_call_output requires item_reference ids matching each call_id", nil, "")
 				h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "function_call_output requires item_reference ids matching each call_id, or previous_response_id/tool_call context; if relying on history, ensure store=true and reuse previous_response_id")
 				return
@@ -179,7 +184,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 	canWait, err := h.concurrencyHelper.IncrementWaitCount(c.Request.Context(), subject.UserID, maxWait)
 	waitCounted := false
 	if err != nil {
-		log.Printf("Increment wait count failed: %v", err)
+		logger.Module("openai_gateway").WarnContext(c.Request.Context(), "increment wait count failed", "error", err)
 		// On error, allow request to proceed
 	} else if !canWait {
 		errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Too many pending requests, please retry later", nil, "")
@@ -198,7 +203,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 	// 1. First acquire user concurrency slot
 	userReleaseFunc, err := h.concurrencyHelper.AcquireUserSlotWithWait(c, subject.UserID, subject.Concurrency, reqStream, &streamStarted)
 	if err != nil {
-		log.Printf("User concurrency acquire failed: %v", err)
+		logger.Module("openai_gateway").WarnContext(c.Request.Context(), "user concurrency acquire failed", "error", err)
 		errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Concurrency limit exceeded for user, please retry later", nil, "")
 		h.handleConcurrencyError(c, err, "user", streamStarted)
 		return
@@ -216,7 +221,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 
 	// 2. Re-check billing eligibility after wait
 	if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
-		log.Printf("Billing eligibility check failed after wait: %v", err)
+		logger.Module("openai_gateway").WarnContext(c.Request.Context(), "billing eligibility check failed after wait", "error", err)
 		status, code, message := billingErrorDetails(err)
 		errCtx.recordError("billing_error", status, message, nil, "")
 		h.handleStreamingAwareError(c, status, code, message, streamStarted)
@@ -233,10 +238,10 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 
 	for {
 		// Select account supporting the requested model
-		log.Printf("[OpenAI Handler] Selecting account: groupID=%v model=%s", apiKey.GroupID, reqModel)
+		logger.Module("openai_gateway").DebugContext(c.Request.Context(), "selecting account", "model", reqModel)
 		selection, err := h.gatewayService.SelectAccountWithLoadAwareness(c.Request.Context(), apiKey.GroupID, sessionHash, reqModel, failedAccountIDs)
 		if err != nil {
-			log.Printf("[OpenAI Handler] SelectAccount failed: %v", err)
+			logger.Module("openai_gateway").WarnContext(c.Request.Context(), "select account failed", "error", err)
 			if len(failedAccountIDs) == 0 {
 				errCtx.recordError("no_account", http.StatusServiceUnavailable, "No available accounts: "+err.Error(), nil, "")
 				h.handleStreamingAwareError(c, http.StatusServiceUnavailable, "api_error", "No available accounts: "+err.Error(), streamStarted)
@@ -252,7 +257,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		}
 		account := selection.Account
 		errCtx.setAccount(account)
-		log.Printf("[OpenAI Handler] Selected account: id=%d name=%s", account.ID, account.Name)
+		logger.Module("openai_gateway").DebugContext(c.Request.Context(), "selected account", "account_name", account.Name)
 		setOpsSelectedAccount(c, account.ID)
 
 		// 3. Acquire account concurrency slot
@@ -266,9 +271,9 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 			accountWaitCounted := false
 			canWait, err := h.concurrencyHelper.IncrementAccountWaitCount(c.Request.Context(), account.ID, selection.WaitPlan.MaxWaiting)
 			if err != nil {
-				log.Printf("Increment account wait count failed: %v", err)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "increment account wait count failed", "error", err)
 			} else if !canWait {
-				log.Printf("Account wait queue full: account=%d", account.ID)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "account wait queue full")
 				errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Too many pending requests, please retry later", nil, "")
 				h.handleStreamingAwareError(c, http.StatusTooManyRequests, "rate_limit_error", "Too many pending requests, please retry later", streamStarted)
 				return
@@ -286,12 +291,13 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 				c,
 				account.ID,
 				selection.WaitPlan.MaxConcurrency,
+				account.GetModelConcurrencyLimit(reqModel),
 				selection.WaitPlan.Timeout,
 				reqStream,
 				&streamStarted,
 			)
 			if err != nil {
-				log.Printf("Account concurrency acquire failed: %v", err)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "account concurrency acquire failed", "error", err)
 				errCtx.recordError("concurrency_limit", http.StatusTooManyRequests, "Concurrency limit exceeded for account, please retry later", nil, "")
 				h.handleConcurrencyError(c, err, "account", streamStarted)
 				return
@@ -301,7 +307,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 				accountWaitCounted = false
 			}
 			if err := h.gatewayService.BindStickySession(c.Request.Context(), apiKey.GroupID, sessionHash, account.ID); err != nil {
-				log.Printf("Bind sticky session failed: %v", err)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "bind sticky session failed", "error", err)
 			}
 		}
 		// 账号槽位/等待计数需要在超时或断开时安全回收
@@ -323,12 +329,12 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 					return
 				}
 				switchCount++
-				log.Printf("Account %d: upstream error %d, switching account %d/%d", account.ID, failoverErr.StatusCode, switchCount, maxAccountSwitches)
+				logger.Module("openai_gateway").WarnContext(c.Request.Context(), "upstream error, switching account", "status", failoverErr.StatusCode, "switch", switchCount, "max_switches", maxAccountSwitches)
 				continue
 			}
 			// Error response already handled in Forward, record forward_error
 			errCtx.recordError("forward_error", http.StatusBadGateway, err.Error(), nil, "")
-			log.Printf("Account %d: Forward request failed: %v", account.ID, err)
+			logger.Module("openai_gateway").ErrorContext(c.Request.Context(), "forward request failed", "error", err)
 			return
 		}
 
@@ -336,6 +342,8 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
 
+		setOpsForwardResult(c, result.RequestID, result.Duration, result.FirstTokenMs, result.Usage.InputTokens, result.Usage.OutputTokens)
+
 		// Async record usage
 		go func(result *service.OpenAIForwardResult, usedAccount *service.Account, ua, ip string) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -350,7 +358,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 				IPAddress:     ip,
 				APIKeyService: h.apiKeyService,
 			}); err != nil {
-				log.Printf("Record usage failed: %v", err)
+				logger.Module("openai_gateway").ErrorContext(ctx, "record usage failed", "account_id", usedAccount.ID, "error", err)
 			}
 		}(result, account, userAgent, clientIP)
 		return
@@ -555,7 +563,7 @@ func (e *openaiErrorRecordingContext) recordError(errorType string, statusCode i
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if err := e.handler.gatewayService.RecordErrorUsage(ctx, input); err != nil {
-			log.Printf("Record error usage failed: %v", err)
+			logger.Module("openai_gateway").ErrorContext(ctx, "record error usage failed", "error", err)
 		}
 	}()
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/gin-gonic/gin"
@@ -26,6 +27,9 @@ const (
 	opsStreamKey      = "ops_stream"
 	opsRequestBodyKey = "ops_request_body"
 	opsAccountIDKey   = "ops_account_id"
+	// opsForwardResultKey carries a summary of the successful forward result (request_id written to usage_logs,
+	// latency and tokens), so captures can be linked to usage rows and shadow requests can be compared.
+	opsForwardResultKey = "ops_forward_result"
 )
 
 const (
@@ -38,17 +42,26 @@ const (
 	opsErrorLogQueueSizePerWorker = 128
 	opsErrorLogMinQueueSize       = 256
 	opsErrorLogMaxQueueSize       = 8192
+
+	// Sampled success captures use their own small queue and workers, so a burst of captures
+	// is dropped on its own instead of crowding error logs out of the error log queue.
+	opsRequestCaptureWorkerCount = 2
+	opsRequestCaptureQueueSize   = 256
 )
 
 type opsErrorLogJob struct {
 	ops         *service.OpsService
 	entry       *service.OpsInsertErrorLogInput
 	requestBody []byte
+
+	// capture is set for sampled captures of successful requests (entry is nil).
+	capture *service.OpsInsertRequestCaptureInput
 }
 
 var (
-	opsErrorLogOnce  sync.Once
-	opsErrorLogQueue chan opsErrorLogJob
+	opsErrorLogOnce        sync.Once
+	opsErrorLogQueue       chan opsErrorLogJob
+	opsRequestCaptureQueue chan opsErrorLogJob
 
 	opsErrorLogStopOnce  sync.Once
 	opsErrorLogWorkersWg sync.WaitGroup
@@ -59,6 +72,8 @@ var (
 	opsErrorLogDropped   atomic.Int64
 	opsErrorLogProcessed atomic.Int64
 
+	opsRequestCaptureDropped atomic.Int64
+
 	opsErrorLogLastDropLogAt atomic.Int64
 
 	opsErrorLogShutdownCh   = make(chan struct{})
@@ -77,28 +92,42 @@ func startOpsErrorLogWorkers() {
 	workerCount, queueSize := opsErrorLogConfig()
 	opsErrorLogQueue = make(chan opsErrorLogJob, queueSize)
 	opsErrorLogQueueLen.Store(0)
+	opsRequestCaptureQueue = make(chan opsErrorLogJob, opsRequestCaptureQueueSize)
 
-	opsErrorLogWorkersWg.Add(workerCount)
+	opsErrorLogWorkersWg.Add(workerCount + opsRequestCaptureWorkerCount)
 	for i := 0; i < workerCount; i++ {
-		go func() {
-			defer opsErrorLogWorkersWg.Done()
-			for job := range opsErrorLogQueue {
-				opsErrorLogQueueLen.Add(-1)
-				if job.ops == nil || job.entry == nil {
-					continue
+		go runOpsJobWorker(opsErrorLogQueue, true)
+	}
+	for i := 0; i < opsRequestCaptureWorkerCount; i++ {
+		go runOpsJobWorker(opsRequestCaptureQueue, false)
+	}
+}
+
+// runOpsJobWorker drains one ops queue; countQueueLen is set for the error log queue,
+// whose length is reported by OpsErrorLogQueueLength.
+func runOpsJobWorker(queue <-chan opsErrorLogJob, countQueueLen bool) {
+	defer opsErrorLogWorkersWg.Done()
+	for job := range queue {
+		if countQueueLen {
+			opsErrorLogQueueLen.Add(-1)
+		}
+		if job.ops == nil || (job.entry == nil && job.capture == nil) {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[OpsErrorLogger] worker panic: %v\n%s", r, debug.Stack())
 				}
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							log.Printf("[OpsErrorLogger] worker panic: %v\n%s", r, debug.Stack())
-						}
-					}()
-					ctx, cancel := context.WithTimeout(context.Background(), opsErrorLogTimeout)
-					_ = job.ops.RecordError(ctx, job.entry, job.requestBody)
-					cancel()
-					opsErrorLogProcessed.Add(1)
-				}()
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), opsErrorLogTimeout)
+			if job.capture != nil {
+				_ = job.ops.RecordRequestCapture(ctx, job.capture)
+			} else {
+				_ = job.ops.RecordError(ctx, job.entry, job.requestBody)
 			}
+			cancel()
+			opsErrorLogProcessed.Add(1)
 		}()
 	}
 }
@@ -107,6 +136,18 @@ func enqueueOpsErrorLog(ops *service.OpsService, entry *service.OpsInsertErrorLo
 	if ops == nil || entry == nil {
 		return
 	}
+	enqueueOpsJob(opsErrorLogJob{ops: ops, entry: entry, requestBody: requestBody})
+}
+
+// enqueueOpsRequestCapture hands a sampled success capture to the dedicated capture queue.
+func enqueueOpsRequestCapture(ops *service.OpsService, capture *service.OpsInsertRequestCaptureInput) {
+	if ops == nil || capture == nil {
+		return
+	}
+	enqueueOpsJob(opsErrorLogJob{ops: ops, capture: capture})
+}
+
+func enqueueOpsJob(job opsErrorLogJob) {
 	select {
 	case <-opsErrorLogShutdownCh:
 		return
@@ -128,8 +169,18 @@ func enqueueOpsErrorLog(ops *service.OpsService, entry *service.OpsInsertErrorLo
 		return
 	}
 
+	if job.capture != nil {
+		select {
+		case opsRequestCaptureQueue <- job:
+		default:
+			// Captures are best-effort samples; drop them without touching the error log counters.
+			opsRequestCaptureDropped.Add(1)
+		}
+		return
+	}
+
 	select {
-	case opsErrorLogQueue <- opsErrorLogJob{ops: ops, entry: entry, requestBody: requestBody}:
+	case opsErrorLogQueue <- job:
 		opsErrorLogQueueLen.Add(1)
 		opsErrorLogEnqueued.Add(1)
 	default:
@@ -157,6 +208,10 @@ func stopOpsErrorLogWorkers() bool {
 		close(ch)
 	}
 	opsErrorLogQueue = nil
+	if opsRequestCaptureQueue != nil {
+		close(opsRequestCaptureQueue)
+		opsRequestCaptureQueue = nil
+	}
 	opsErrorLogMu.Unlock()
 
 	if ch == nil {
@@ -205,6 +260,10 @@ func OpsErrorLogProcessedTotal() int64 {
 	return opsErrorLogProcessed.Load()
 }
 
+func OpsRequestCaptureDroppedTotal() int64 {
+	return opsRequestCaptureDropped.Load()
+}
+
 func maybeLogOpsErrorLogDrop() {
 	now := time.Now().Unix()
 
@@ -267,12 +326,55 @@ func setOpsSelectedAccount(c *gin.Context, accountID int64) {
 		return
 	}
 	c.Set(opsAccountIDKey, accountID)
+	if c.Request != nil {
+		logger.SetAccount(c.Request.Context(), accountID, "")
+	}
+}
+
+type opsForwardSummary struct {
+	RequestID    string
+	Duration     time.Duration
+	FirstTokenMs *int
+	InputTokens  int
+	OutputTokens int
+}
+
+func setOpsForwardResult(c *gin.Context, requestID string, duration time.Duration, firstTokenMs *int, inputTokens, outputTokens int) {
+	if c == nil {
+		return
+	}
+	c.Set(opsForwardResultKey, &opsForwardSummary{
+		RequestID:    strings.TrimSpace(requestID),
+		Duration:     duration,
+		FirstTokenMs: firstTokenMs,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+	})
+}
+
+func getOpsForwardResult(c *gin.Context) *opsForwardSummary {
+	if v, ok := c.Get(opsForwardResultKey); ok {
+		if summary, ok := v.(*opsForwardSummary); ok {
+			return summary
+		}
+	}
+	return nil
 }
 
 type opsCaptureWriter struct {
 	gin.ResponseWriter
 	limit int
 	buf   bytes.Buffer
+
+	// Sampled capture of successful responses (opt-in, see OpsRequestCaptureSettings).
+	// The decision is made lazily on the first successful write, after auth has populated the API key.
+	ops            *service.OpsService
+	c              *gin.Context
+	sampleDecided  bool
+	sample         *service.OpsRequestCaptureDecision
+	sampleBuf      bytes.Buffer
+	sampleTotal    int
+	sampleOverflow bool
 }
 
 func (w *opsCaptureWriter) Write(b []byte) (int, error) {
@@ -283,6 +385,8 @@ func (w *opsCaptureWriter) Write(b []byte) (int, error) {
 		} else {
 			_, _ = w.buf.Write(b)
 		}
+	} else if w.Status() < 400 && w.sampling() {
+		w.captureSample(b)
 	}
 	return w.ResponseWriter.Write(b)
 }
@@ -295,18 +399,160 @@ func (w *opsCaptureWriter) WriteString(s string) (int, error) {
 		} else {
 			_, _ = w.buf.WriteString(s)
 		}
+	} else if w.Status() < 400 && w.sampling() {
+		w.captureSample([]byte(s))
 	}
 	return w.ResponseWriter.WriteString(s)
 }
 
+// sampling reports whether the current successful response should be captured.
+func (w *opsCaptureWriter) sampling() bool {
+	if !w.sampleDecided {
+		w.sampleDecided = true
+		if w.ops == nil || w.c == nil {
+			return false
+		}
+		apiKey, ok := middleware2.GetAPIKeyFromContext(w.c)
+		if !ok || apiKey == nil || apiKey.User == nil {
+			return false
+		}
+		w.sample = w.ops.DecideRequestCapture(w.c.Request.Context(), apiKey.User.ID, apiKey.ID)
+	}
+	return w.sample != nil
+}
+
+func (w *opsCaptureWriter) captureSample(b []byte) {
+	w.sampleTotal += len(b)
+	remaining := w.sample.MaxResponseBytes - w.sampleBuf.Len()
+	if remaining <= 0 {
+		w.sampleOverflow = true
+		return
+	}
+	if len(b) > remaining {
+		_, _ = w.sampleBuf.Write(b[:remaining])
+		w.sampleOverflow = true
+		return
+	}
+	_, _ = w.sampleBuf.Write(b)
+}
+
+// enqueueOpsSampledCapture records a successful request selected by the capture rules.
+func enqueueOpsSampledCapture(ops *service.OpsService, c *gin.Context, w *opsCaptureWriter, status int) {
+	if w.sample == nil {
+		return
+	}
+	apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+	clientRequestID, _ := c.Request.Context().Value(ctxkey.ClientRequestID).(string)
+
+	input := &service.OpsInsertRequestCaptureInput{
+		ClientRequestID:   clientRequestID,
+		Platform:          resolveOpsPlatform(apiKey, guessPlatformFromPath(c.Request.URL.Path)),
+		RequestPath:       c.Request.URL.Path,
+		StatusCode:        status,
+		CaptureReason:     w.sample.Reason,
+		RawResponseBody:   w.sampleBuf.Bytes(),
+		ResponseBytes:     w.sampleTotal,
+		ResponseTruncated: w.sampleOverflow,
+		CreatedAt:         time.Now(),
+	}
+	if summary := getOpsForwardResult(c); summary != nil {
+		input.RequestID = summary.RequestID
+	}
+	if v, ok := c.Get(opsModelKey); ok {
+		input.Model, _ = v.(string)
+	}
+	if v, ok := c.Get(opsStreamKey); ok {
+		input.Stream, _ = v.(bool)
+	}
+	if v, ok := c.Get(opsRequestBodyKey); ok {
+		input.RawRequestBody, _ = v.([]byte)
+	}
+	if v, ok := c.Get(opsAccountIDKey); ok {
+		if id, ok := v.(int64); ok && id > 0 {
+			input.AccountID = &id
+		}
+	}
+	if apiKey != nil {
+		input.APIKeyID = &apiKey.ID
+		if apiKey.User != nil {
+			input.UserID = &apiKey.User.ID
+		}
+		input.GroupID = apiKey.GroupID
+	}
+	enqueueOpsRequestCapture(ops, input)
+}
+
+// maybeStartOpsShadow duplicates a successful request to the shadow account of a matching rule.
+// Body and headers are copied here because gin.Context is recycled once the handler returns.
+func maybeStartOpsShadow(ops *service.OpsService, c *gin.Context, status int) {
+	summary := getOpsForwardResult(c)
+	if summary == nil {
+		return
+	}
+	apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+	if apiKey == nil || apiKey.GroupID == nil {
+		return
+	}
+	var primaryAccountID int64
+	if v, ok := c.Get(opsAccountIDKey); ok {
+		primaryAccountID, _ = v.(int64)
+	}
+	rule := ops.MatchShadowRule(c.Request.Context(), *apiKey.GroupID, primaryAccountID)
+	if rule == nil {
+		return
+	}
+	var body []byte
+	if v, ok := c.Get(opsRequestBodyKey); ok {
+		if b, ok := v.([]byte); ok {
+			body = bytes.Clone(b)
+		}
+	}
+	if len(body) == 0 {
+		return
+	}
+
+	req := &service.OpsShadowRequest{
+		Rule:                rule,
+		RequestID:           summary.RequestID,
+		GroupID:             apiKey.GroupID,
+		Platform:            resolveOpsPlatform(apiKey, guessPlatformFromPath(c.Request.URL.Path)),
+		RequestPath:         c.Request.URL.Path,
+		Body:                body,
+		Header:              c.Request.Header.Clone(),
+		PrimaryStatusCode:   status,
+		PrimaryInputTokens:  summary.InputTokens,
+		PrimaryOutputTokens: summary.OutputTokens,
+	}
+	if v, ok := c.Get(opsModelKey); ok {
+		req.Model, _ = v.(string)
+	}
+	if v, ok := c.Get(opsStreamKey); ok {
+		req.Stream, _ = v.(bool)
+	}
+	if primaryAccountID > 0 {
+		req.PrimaryAccountID = &primaryAccountID
+	}
+	if summary.Duration > 0 {
+		ms := summary.Duration.Milliseconds()
+		req.PrimaryDurationMs = &ms
+	}
+	if summary.FirstTokenMs != nil {
+		ms := int64(*summary.FirstTokenMs)
+		req.PrimaryFirstTokenMs = &ms
+	}
+	ops.StartShadow(req)
+}
+
 // OpsErrorLoggerMiddleware records error responses (status >= 400) into ops_error_logs.
 //
 // Notes:
 // - It buffers response bodies only when status >= 400 to avoid overhead for successful traffic.
+// - Successful responses are buffered only when they match the opt-in capture rules (OpsService.DecideRequestCapture).
+// - Successful responses may be duplicated to a shadow account (OpsService.MatchShadowRule); the client never sees it.
 // - Streaming errors after the response has started (SSE) may still need explicit logging.
 func OpsErrorLoggerMiddleware(ops *service.OpsService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		w := &opsCaptureWriter{ResponseWriter: c.Writer, limit: 64 * 1024}
+		w := &opsCaptureWriter{ResponseWriter: c.Writer, limit: 64 * 1024, ops: ops, c: c}
 		c.Writer = w
 		c.Next()
 
@@ -319,6 +565,9 @@ func OpsErrorLoggerMiddleware(ops *service.OpsService) gin.HandlerFunc {
 
 		status := c.Writer.Status()
 		if status < 400 {
+			enqueueOpsSampledCapture(ops, c, w, status)
+			maybeStartOpsShadow(ops, c, status)
+
 			// Even when the client request succeeds, we still want to persist upstream error attempts
 			// (retries/failover) so ops can observe upstream instability that gets "covered" by retries.
 			var events []*service.OpsUpstreamErrorEvent
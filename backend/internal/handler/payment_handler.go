@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPaymentWebhookBodySize limits the size of provider notifications
+const maxPaymentWebhookBodySize = 1 << 20
+
+// PaymentHandler handles online balance top-up orders and provider webhooks
+type PaymentHandler struct {
+	paymentService *service.PaymentService
+}
+
+// NewPaymentHandler creates a new PaymentHandler
+func NewPaymentHandler(paymentService *service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// CreatePaymentOrderRequest represents the top-up order payload
+type CreatePaymentOrderRequest struct {
+	Provider string  `json:"provider" binding:"required"`
+	Amount   float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// GetConfig returns the enabled payment methods and the allowed amount range
+// GET /api/v1/payments/config
+func (h *PaymentHandler) GetConfig(c *gin.Context) {
+	cfg, err := h.paymentService.GetConfig(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, cfg)
+}
+
+// CreateOrder creates a top-up order and returns the provider payment URL
+// POST /api/v1/payments/orders
+func (h *PaymentHandler) CreateOrder(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CreatePaymentOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	order, err := h.paymentService.CreateOrder(c.Request.Context(), subject.UserID, strings.TrimSpace(req.Provider), req.Amount)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, order)
+}
+
+// ListOrders returns the current user's top-up orders
+// GET /api/v1/payments/orders
+func (h *PaymentHandler) ListOrders(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.paymentService.ListMyOrders(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// GetOrder returns one of the current user's top-up orders, polled after returning from the provider
+// GET /api/v1/payments/orders/:order_no
+func (h *PaymentHandler) GetOrder(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.paymentService.GetMyOrder(c.Request.Context(), subject.UserID, c.Param("order_no"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, order)
+}
+
+// Webhook receives provider payment notifications; a non-2xx response makes the provider retry
+// POST /api/v1/payments/webhook/:provider
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPaymentWebhookBodySize+1))
+	if err != nil || len(body) > maxPaymentWebhookBodySize {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request.Header, body); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	c.String(http.StatusOK, "success")
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromoHandler handles promo code redemption from the user panel
+type PromoHandler struct {
+	promoService   *service.PromoService
+	settingService *service.SettingService
+}
+
+// NewPromoHandler creates a new PromoHandler
+func NewPromoHandler(promoService *service.PromoService, settingService *service.SettingService) *PromoHandler {
+	return &PromoHandler{
+		promoService:   promoService,
+		settingService: settingService,
+	}
+}
+
+// RedeemPromoCodeRequest represents the promo code redemption payload
+type RedeemPromoCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Redeem applies a promo code to the current user
+// POST /api/v1/user/promo-codes/redeem
+func (h *PromoHandler) Redeem(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if h.settingService != nil && !h.settingService.IsPromoCodeEnabled(c.Request.Context()) {
+		response.ErrorFrom(c, infraerrors.Forbidden("PROMO_CODE_FEATURE_DISABLED", "promo codes are disabled"))
+		return
+	}
+
+	var req RedeemPromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	usage, err := h.promoService.RedeemPromoCode(c.Request.Context(), subject.UserID, req.Code)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.UserPromoCodeUsageFromService(usage))
+}
+
+// List returns the current user's promo code usages and pending top-up bonus
+// GET /api/v1/user/promo-codes
+func (h *PromoHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	usages, result, err := h.promoService.ListMyUsages(c.Request.Context(), subject.UserID, pagination.PaginationParams{Page: page, PageSize: pageSize})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.UserPromoCodeUsage, 0, len(usages))
+	for i := range usages {
+		out = append(out, *dto.UserPromoCodeUsageFromService(&usages[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetPending returns the current user's pending top-up bonus, if any
+// GET /api/v1/user/promo-codes/pending
+func (h *PromoHandler) GetPending(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	pending, err := h.promoService.GetPendingBonus(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"enabled": h.settingService == nil || h.settingService.IsPromoCodeEnabled(c.Request.Context()),
+		"pending": dto.UserPromoCodeUsageFromService(pending),
+	})
+}
@@ -38,6 +38,7 @@ func (h *SettingHandler) GetPublicSettings(c *gin.Context) {
 		PasswordResetEnabled:        settings.PasswordResetEnabled,
 		InvitationCodeEnabled:       settings.InvitationCodeEnabled,
 		TotpEnabled:                 settings.TotpEnabled,
+		PasskeyLoginEnabled:         settings.PasskeyLoginEnabled,
 		TurnstileEnabled:            settings.TurnstileEnabled,
 		TurnstileSiteKey:            settings.TurnstileSiteKey,
 		SiteName:                    settings.SiteName,
@@ -51,6 +52,16 @@ func (h *SettingHandler) GetPublicSettings(c *gin.Context) {
 		PurchaseSubscriptionEnabled: settings.PurchaseSubscriptionEnabled,
 		PurchaseSubscriptionURL:     settings.PurchaseSubscriptionURL,
 		LinuxDoOAuthEnabled:         settings.LinuxDoOAuthEnabled,
+		OIDCProviders:               oidcPublicProvidersToDTO(settings.OIDCProviders),
+		ReferralEnabled:             settings.ReferralEnabled,
 		Version:                     h.version,
 	})
 }
+
+func oidcPublicProvidersToDTO(providers []service.OIDCPublicProvider) []dto.OIDCPublicProvider {
+	out := make([]dto.OIDCPublicProvider, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, dto.OIDCPublicProvider{Slug: p.Slug, Name: p.Name})
+	}
+	return out
+}
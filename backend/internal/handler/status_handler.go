@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusHandler serves the public (unauthenticated) status page data
+type StatusHandler struct {
+	opsService *service.OpsService
+}
+
+// NewStatusHandler creates a new StatusHandler
+func NewStatusHandler(opsService *service.OpsService) *StatusHandler {
+	return &StatusHandler{opsService: opsService}
+}
+
+// GetStatus returns per-platform/model availability, latency and current incidents
+// GET /api/v1/status
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusNotFound, "Status page is disabled")
+		return
+	}
+
+	status, err := h.opsService.GetPublicStatus(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	// Embeddable from other origins (e.g. a marketing site); the payload contains no private data.
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Header("Cache-Control", "public, max-age=30")
+	response.Success(c, status)
+}
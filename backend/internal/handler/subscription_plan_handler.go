@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubscriptionPlanHandler handles self-service subscription plan purchase and auto-renewal
+type SubscriptionPlanHandler struct {
+	planService *service.SubscriptionPlanService
+}
+
+// NewSubscriptionPlanHandler creates a new SubscriptionPlanHandler
+func NewSubscriptionPlanHandler(planService *service.SubscriptionPlanService) *SubscriptionPlanHandler {
+	return &SubscriptionPlanHandler{
+		planService: planService,
+	}
+}
+
+// ChangePlanRequest represents the plan upgrade / downgrade payload
+type ChangePlanRequest struct {
+	FromGroupID int64 `json:"from_group_id" binding:"required"`
+}
+
+// SetAutoRenewRequest represents the auto-renewal toggle payload
+type SetAutoRenewRequest struct {
+	Enabled bool  `json:"enabled"`
+	PlanID  int64 `json:"plan_id"`
+}
+
+// ListPlans returns the plans available for purchase
+// GET /api/v1/subscriptions/plans
+func (h *SubscriptionPlanHandler) ListPlans(c *gin.Context) {
+	plans, err := h.planService.ListAvailablePlans(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, plans)
+}
+
+// Quote returns the price and resulting expiry for purchasing a plan
+// GET /api/v1/subscriptions/plans/:id/quote?from_group_id=2
+func (h *SubscriptionPlanHandler) Quote(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	planID, ok := parsePlanID(c)
+	if !ok {
+		return
+	}
+	var fromGroupID int64
+	if raw := c.Query("from_group_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid from_group_id")
+			return
+		}
+		fromGroupID = id
+	}
+
+	quote, err := h.planService.Quote(c.Request.Context(), subject.UserID, planID, fromGroupID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, quote)
+}
+
+// Purchase buys or renews a plan using the account balance
+// POST /api/v1/subscriptions/plans/:id/purchase
+func (h *SubscriptionPlanHandler) Purchase(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	planID, ok := parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.planService.Purchase(c.Request.Context(), subject.UserID, planID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// Change switches an active subscription in another group to this plan, crediting its remaining time
+// POST /api/v1/subscriptions/plans/:id/change
+func (h *SubscriptionPlanHandler) Change(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	planID, ok := parsePlanID(c)
+	if !ok {
+		return
+	}
+
+	var req ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	result, err := h.planService.ChangePlan(c.Request.Context(), subject.UserID, planID, req.FromGroupID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// ListOrders returns the current user's subscription orders
+// GET /api/v1/subscriptions/orders
+func (h *SubscriptionPlanHandler) ListOrders(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	items, result, err := h.planService.ListMyOrders(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, items, result.Total, page, pageSize)
+}
+
+// ListAutoRenew returns the current user's auto-renewal settings per group
+// GET /api/v1/subscriptions/auto-renew
+func (h *SubscriptionPlanHandler) ListAutoRenew(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	renewals, err := h.planService.ListMyRenewals(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, renewals)
+}
+
+// SetAutoRenew turns auto-renewal on or off for a subscription group
+// PUT /api/v1/subscriptions/auto-renew/:group_id
+func (h *SubscriptionPlanHandler) SetAutoRenew(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	groupID, err := strconv.ParseInt(c.Param("group_id"), 10, 64)
+	if err != nil || groupID <= 0 {
+		response.BadRequest(c, "Invalid group ID")
+		return
+	}
+
+	var req SetAutoRenewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	renewal, err := h.planService.SetAutoRenew(c.Request.Context(), subject.UserID, groupID, req.Enabled, req.PlanID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, renewal)
+}
+
+func parsePlanID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid plan ID")
+		return 0, false
+	}
+	return id, true
+}
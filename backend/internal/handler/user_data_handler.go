@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserDataHandler handles self-service data export and account deletion
+type UserDataHandler struct {
+	userDataService *service.UserDataService
+}
+
+// NewUserDataHandler creates a new UserDataHandler
+func NewUserDataHandler(userDataService *service.UserDataService) *UserDataHandler {
+	return &UserDataHandler{
+		userDataService: userDataService,
+	}
+}
+
+// RequestAccountDeletionRequest represents the account deletion request payload
+type RequestAccountDeletionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ConfirmAccountDeletionRequest represents the account deletion confirmation payload
+type ConfirmAccountDeletionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ListExports returns the current user's recent data exports
+// GET /api/v1/user/data-export
+func (h *UserDataHandler) ListExports(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	exports, err := h.userDataService.ListExports(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, exports)
+}
+
+// RequestExport queues a new data export for the current user
+// POST /api/v1/user/data-export
+func (h *UserDataHandler) RequestExport(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	export, err := h.userDataService.RequestExport(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, export)
+}
+
+// DownloadExport streams a completed export archive
+// GET /api/v1/user/data-export/:id/download
+func (h *UserDataHandler) DownloadExport(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	exportID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || exportID <= 0 {
+		response.BadRequest(c, "Invalid export ID")
+		return
+	}
+
+	archive, filename, err := h.userDataService.DownloadExport(c.Request.Context(), subject.UserID, exportID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// GetDeletionStatus returns the current user's open account deletion request, if any
+// GET /api/v1/user/account-deletion
+func (h *UserDataHandler) GetDeletionStatus(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	req, err := h.userDataService.GetDeletionStatus(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"request":           req,
+		"grace_period_days": int(service.AccountDeletionGracePeriod.Hours() / 24),
+	})
+}
+
+// RequestDeletion sends an account deletion confirmation email
+// POST /api/v1/user/account-deletion
+func (h *UserDataHandler) RequestDeletion(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req RequestAccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	// Build frontend base URL from request
+	scheme := "https"
+	if c.Request.TLS == nil {
+		if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		} else {
+			scheme = "http"
+		}
+	}
+	frontendBaseURL := scheme + "://" + c.Request.Host
+
+	result, err := h.userDataService.RequestDeletion(c.Request.Context(), subject.UserID, req.Reason, frontendBaseURL, ip.GetClientIP(c))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// CancelDeletion cancels a deletion request that has not been confirmed yet
+// DELETE /api/v1/user/account-deletion
+func (h *UserDataHandler) CancelDeletion(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.userDataService.CancelDeletion(c.Request.Context(), subject.UserID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Account deletion cancelled"})
+}
+
+// ConfirmDeletion confirms account deletion using the emailed token
+// POST /api/v1/auth/account-deletion/confirm
+func (h *UserDataHandler) ConfirmDeletion(c *gin.Context) {
+	var req ConfirmAccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	result, err := h.userDataService.ConfirmDeletion(c.Request.Context(), req.Token)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"scheduled_for": result.ScheduledFor,
+	})
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// WebAuthnHandler handles passkey management and recovery code requests
+type WebAuthnHandler struct {
+	webauthnService *service.WebAuthnService
+}
+
+// NewWebAuthnHandler creates a new WebAuthnHandler
+func NewWebAuthnHandler(webauthnService *service.WebAuthnService) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		webauthnService: webauthnService,
+	}
+}
+
+// WebAuthnCredentialResponse represents a registered passkey
+type WebAuthnCredentialResponse struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Transports []string `json:"transports"`
+	LastUsedAt *int64   `json:"last_used_at,omitempty"` // Unix timestamp
+	CreatedAt  int64    `json:"created_at"`             // Unix timestamp
+}
+
+func webauthnCredentialToResponse(c *service.WebAuthnCredential) WebAuthnCredentialResponse {
+	resp := WebAuthnCredentialResponse{
+		ID:         c.ID,
+		Name:       c.Name,
+		Transports: c.Transports,
+		CreatedAt:  c.CreatedAt.Unix(),
+	}
+	if resp.Transports == nil {
+		resp.Transports = []string{}
+	}
+	if c.LastUsedAt != nil {
+		ts := c.LastUsedAt.Unix()
+		resp.LastUsedAt = &ts
+	}
+	return resp
+}
+
+// webauthnRelyingParty resolves the relying party for the current request
+func webauthnRelyingParty(c *gin.Context, svc *service.WebAuthnService) service.WebAuthnRelyingParty {
+	return svc.ResolveRelyingParty(c.Request.Context(), c.Request.Host, c.GetHeader("Origin"), isRequestHTTPS(c))
+}
+
+// ListCredentials lists the current user's passkeys
+// GET /api/v1/user/webauthn/credentials
+func (h *WebAuthnHandler) ListCredentials(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	credentials, err := h.webauthnService.ListCredentials(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]WebAuthnCredentialResponse, 0, len(credentials))
+	for i := range credentials {
+		out = append(out, webauthnCredentialToResponse(&credentials[i]))
+	}
+	response.Success(c, out)
+}
+
+// WebAuthnVerifyIdentityRequest carries identity re-verification for sensitive passkey operations
+type WebAuthnVerifyIdentityRequest struct {
+	EmailCode string `json:"email_code"`
+	Password  string `json:"password"`
+}
+
+// BeginRegistration starts passkey registration
+// POST /api/v1/user/webauthn/register/begin
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WebAuthnVerifyIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		req = WebAuthnVerifyIdentityRequest{}
+	}
+
+	rp := webauthnRelyingParty(c, h.webauthnService)
+	result, err := h.webauthnService.BeginRegistration(c.Request.Context(), subject.UserID, rp, req.EmailCode, req.Password)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// WebAuthnFinishRegistrationRequest represents the request to complete passkey registration
+type WebAuthnFinishRegistrationRequest struct {
+	SessionToken string                              `json:"session_token" binding:"required"`
+	Name         string                              `json:"name"`
+	Credential   service.WebAuthnAttestationResponse `json:"credential" binding:"required"`
+}
+
+// FinishRegistration completes passkey registration
+// POST /api/v1/user/webauthn/register/finish
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WebAuthnFinishRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	credential, err := h.webauthnService.FinishRegistration(c.Request.Context(), subject.UserID, req.SessionToken, req.Name, &req.Credential)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, webauthnCredentialToResponse(credential))
+}
+
+// WebAuthnRenameRequest represents the request to rename a passkey
+type WebAuthnRenameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// RenameCredential renames a passkey
+// PUT /api/v1/user/webauthn/credentials/:id
+func (h *WebAuthnHandler) RenameCredential(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid passkey ID")
+		return
+	}
+
+	var req WebAuthnRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.webauthnService.RenameCredential(c.Request.Context(), subject.UserID, id, req.Name); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"success": true})
+}
+
+// DeleteCredential removes a passkey
+// DELETE /api/v1/user/webauthn/credentials/:id
+func (h *WebAuthnHandler) DeleteCredential(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid passkey ID")
+		return
+	}
+
+	var req WebAuthnVerifyIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.webauthnService.DeleteCredential(c.Request.Context(), subject.UserID, id, req.EmailCode, req.Password); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"success": true})
+}
+
+// RecoveryCodeStatusResponse represents the recovery code status
+type RecoveryCodeStatusResponse struct {
+	Remaining int `json:"remaining"`
+}
+
+// GetRecoveryCodeStatus returns how many unused recovery codes the user has
+// GET /api/v1/user/recovery-codes
+func (h *WebAuthnHandler) GetRecoveryCodeStatus(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	remaining, err := h.webauthnService.CountRecoveryCodes(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, RecoveryCodeStatusResponse{Remaining: remaining})
+}
+
+// GenerateRecoveryCodes replaces the user's recovery codes; plaintext codes are returned only once
+// POST /api/v1/user/recovery-codes
+func (h *WebAuthnHandler) GenerateRecoveryCodes(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WebAuthnVerifyIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	codes, err := h.webauthnService.GenerateRecoveryCodes(c.Request.Context(), subject.UserID, req.EmailCode, req.Password)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"codes": codes})
+}
@@ -28,6 +28,17 @@ func ProvideAdminHandlers(
 	usageHandler *admin.UsageHandler,
 	userAttributeHandler *admin.UserAttributeHandler,
 	errorPassthroughHandler *admin.ErrorPassthroughHandler,
+	accountProbeHandler *admin.AccountProbeHandler,
+	apiKeyAbuseHandler *admin.APIKeyAbuseHandler,
+	spendGuardHandler *admin.SpendGuardHandler,
+	oidcProviderHandler *admin.OIDCProviderHandler,
+	userSessionHandler *admin.UserSessionHandler,
+	loginGuardHandler *admin.LoginGuardHandler,
+	referralHandler *admin.ReferralHandler,
+	impersonationHandler *admin.ImpersonationHandler,
+	accountDeletionHandler *admin.AccountDeletionHandler,
+	subscriptionPlanHandler *admin.SubscriptionPlanHandler,
+	paymentHandler *admin.PaymentHandler,
 ) *AdminHandlers {
 	return &AdminHandlers{
 		Dashboard:        dashboardHandler,
@@ -49,6 +60,17 @@ func ProvideAdminHandlers(
 		Usage:            usageHandler,
 		UserAttribute:    userAttributeHandler,
 		ErrorPassthrough: errorPassthroughHandler,
+		AccountProbe:     accountProbeHandler,
+		APIKeyAbuse:      apiKeyAbuseHandler,
+		SpendGuard:       spendGuardHandler,
+		OIDCProvider:     oidcProviderHandler,
+		UserSession:      userSessionHandler,
+		LoginGuard:       loginGuardHandler,
+		Referral:         referralHandler,
+		Impersonation:    impersonationHandler,
+		AccountDeletion:  accountDeletionHandler,
+		SubscriptionPlan: subscriptionPlanHandler,
+		Payment:          paymentHandler,
 	}
 }
 
@@ -69,13 +91,21 @@ func ProvideHandlers(
 	apiKeyHandler *APIKeyHandler,
 	usageHandler *UsageHandler,
 	redeemHandler *RedeemHandler,
+	promoHandler *PromoHandler,
+	referralHandler *ReferralHandler,
+	impersonationHandler *ImpersonationHandler,
+	userDataHandler *UserDataHandler,
 	subscriptionHandler *SubscriptionHandler,
+	planHandler *SubscriptionPlanHandler,
+	paymentHandler *PaymentHandler,
 	announcementHandler *AnnouncementHandler,
 	adminHandlers *AdminHandlers,
 	gatewayHandler *GatewayHandler,
 	openaiGatewayHandler *OpenAIGatewayHandler,
 	settingHandler *SettingHandler,
 	totpHandler *TotpHandler,
+	webauthnHandler *WebAuthnHandler,
+	statusHandler *StatusHandler,
 ) *Handlers {
 	return &Handlers{
 		Auth:          authHandler,
@@ -83,13 +113,21 @@ func ProvideHandlers(
 		APIKey:        apiKeyHandler,
 		Usage:         usageHandler,
 		Redeem:        redeemHandler,
+		Promo:         promoHandler,
+		Referral:      referralHandler,
+		Impersonation: impersonationHandler,
+		UserData:      userDataHandler,
 		Subscription:  subscriptionHandler,
+		Plan:          planHandler,
+		Payment:       paymentHandler,
 		Announcement:  announcementHandler,
 		Admin:         adminHandlers,
 		Gateway:       gatewayHandler,
 		OpenAIGateway: openaiGatewayHandler,
 		Setting:       settingHandler,
 		Totp:          totpHandler,
+		WebAuthn:      webauthnHandler,
+		Status:        statusHandler,
 	}
 }
 
@@ -101,11 +139,19 @@ var ProviderSet = wire.NewSet(
 	NewAPIKeyHandler,
 	NewUsageHandler,
 	NewRedeemHandler,
+	NewPromoHandler,
+	NewReferralHandler,
+	NewImpersonationHandler,
+	NewUserDataHandler,
 	NewSubscriptionHandler,
+	NewSubscriptionPlanHandler,
+	NewPaymentHandler,
 	NewAnnouncementHandler,
 	NewGatewayHandler,
 	NewOpenAIGatewayHandler,
 	NewTotpHandler,
+	NewWebAuthnHandler,
+	NewStatusHandler,
 	ProvideSettingHandler,
 
 	// Admin handlers
@@ -128,6 +174,17 @@ var ProviderSet = wire.NewSet(
 	admin.NewUsageHandler,
 	admin.NewUserAttributeHandler,
 	admin.NewErrorPassthroughHandler,
+	admin.NewAccountProbeHandler,
+	admin.NewAPIKeyAbuseHandler,
+	admin.NewSpendGuardHandler,
+	admin.NewOIDCProviderHandler,
+	admin.NewUserSessionHandler,
+	admin.NewLoginGuardHandler,
+	admin.NewReferralHandler,
+	admin.NewImpersonationHandler,
+	admin.NewAccountDeletionHandler,
+	admin.NewSubscriptionPlanHandler,
+	admin.NewPaymentHandler,
 
 	// AdminHandlers and Handlers constructors
 	ProvideAdminHandlers,
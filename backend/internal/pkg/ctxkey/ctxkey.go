@@ -32,4 +32,11 @@ const (
 	// SingleAccountRetry 标识当前请求处于单账号 503 退避重试模式。
 	// 在此模式下，Service 层的模型限流预检查将等待限流过期而非直接切换账号。
 	SingleAccountRetry Key = "ctx_single_account_retry"
+
+	// RateLimitCostEstimate 当前请求的预估 token 消耗（service.RateLimitCostEstimate），
+	// 用于调度时跳过上游限流余量不足的账号
+	RateLimitCostEstimate Key = "ctx_rate_limit_cost_estimate"
+
+	// SessionClient 登录/刷新请求的客户端信息（service.SessionClientInfo），用于记录会话设备与 IP
+	SessionClient Key = "ctx_session_client"
 )
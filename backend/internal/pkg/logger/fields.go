@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+)
+
+type requestFieldsKey struct{}
+
+// requestFields 请求级日志字段。
+//
+// 以可变容器的形式放入 context：账号等信息在调用链深处（调度之后）才确定，
+// 写入后同一请求中任意位置的日志都能带上这些字段。
+type requestFields struct {
+	mu        sync.RWMutex
+	userID    int64
+	apiKeyID  int64
+	groupID   int64
+	accountID int64
+	platform  string
+}
+
+// WithRequestFields 为 ctx 挂载请求级字段容器；已挂载时原样返回。
+func WithRequestFields(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if fieldsFromContext(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestFieldsKey{}, &requestFields{})
+}
+
+// ForkRequestFields 为 ctx 挂载一份当前字段的独立副本，用于同一请求内并发执行的分支（如对冲请求），
+// 分支写入的账号等字段不会覆盖其它分支与原请求；未挂载容器时原样返回。
+func ForkRequestFields(ctx context.Context) context.Context {
+	f := fieldsFromContext(ctx)
+	if f == nil {
+		return ctx
+	}
+	f.mu.RLock()
+	clone := &requestFields{
+		userID:    f.userID,
+		apiKeyID:  f.apiKeyID,
+		groupID:   f.groupID,
+		accountID: f.accountID,
+		platform:  f.platform,
+	}
+	f.mu.RUnlock()
+	return context.WithValue(ctx, requestFieldsKey{}, clone)
+}
+
+func fieldsFromContext(ctx context.Context) *requestFields {
+	if ctx == nil {
+		return nil
+	}
+	f, _ := ctx.Value(requestFieldsKey{}).(*requestFields)
+	return f
+}
+
+// SetAuth 记录认证后的用户、API Key 与分组（groupID 为 nil 表示未分组）。
+func SetAuth(ctx context.Context, userID, apiKeyID int64, groupID *int64) {
+	f := fieldsFromContext(ctx)
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.userID = userID
+	f.apiKeyID = apiKeyID
+	f.groupID = 0
+	if groupID != nil {
+		f.groupID = *groupID
+	}
+}
+
+// SetAccount 记录当前请求调度到的上游账号；账号切换时覆盖旧值。
+func SetAccount(ctx context.Context, accountID int64, platform string) {
+	f := fieldsFromContext(ctx)
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.accountID = accountID
+	if platform != "" {
+		f.platform = platform
+	}
+}
+
+// SetPlatform 记录当前请求的平台。
+func SetPlatform(ctx context.Context, platform string) {
+	f := fieldsFromContext(ctx)
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.platform = platform
+}
+
+// contextAttrs 返回 ctx 中的请求级字段（零值字段省略）。
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	var attrs []slog.Attr
+	if id, _ := ctx.Value(ctxkey.ClientRequestID).(string); id != "" {
+		attrs = append(attrs, slog.String("client_request_id", id))
+	}
+	f := fieldsFromContext(ctx)
+	if f == nil {
+		return attrs
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.userID > 0 {
+		attrs = append(attrs, slog.Int64("user_id", f.userID))
+	}
+	if f.apiKeyID > 0 {
+		attrs = append(attrs, slog.Int64("api_key_id", f.apiKeyID))
+	}
+	if f.groupID > 0 {
+		attrs = append(attrs, slog.Int64("group_id", f.groupID))
+	}
+	if f.accountID > 0 {
+		attrs = append(attrs, slog.Int64("account_id", f.accountID))
+	}
+	if f.platform != "" {
+		attrs = append(attrs, slog.String("platform", f.platform))
+	}
+	return attrs
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/util/logredact"
+)
+
+const moduleKey = "module"
+
+// maxLegacyPrefixLen 旧式 "[Module] msg" 前缀中模块名的最大长度。
+const maxLegacyPrefixLen = 48
+
+// sensitiveLogKeys 日志中额外需要脱敏的字段（在 logredact 默认字段之外）。
+var sensitiveLogKeys = []string{
+	"api_key",
+	"x-api-key",
+	"x-goog-api-key",
+	"authorization",
+	"cookie",
+	"set-cookie",
+	"session_key",
+	"secret",
+	"token",
+}
+
+// Handler 包装底层 slog.Handler：按模块过滤级别、注入请求级字段并对敏感字段脱敏。
+type Handler struct {
+	inner  slog.Handler
+	module string
+}
+
+// NewHandler 创建 Handler；级别过滤使用包级的模块级别配置。
+func NewHandler(inner slog.Handler) *Handler {
+	return &Handler{inner: inner}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= defaultRegistry.min.Level()
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	module := h.module
+	msg := r.Message
+	addModule := false
+	if module == "" {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == moduleKey {
+				module = normalizeModule(a.Value.String())
+				return false
+			}
+			return true
+		})
+	}
+	if module == "" {
+		if name, rest, ok := splitLegacyPrefix(msg); ok {
+			module = normalizeModule(name)
+			msg = rest
+			addModule = true
+		}
+	}
+	if r.Level < defaultRegistry.levelFor(module) {
+		return nil
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	if addModule {
+		out.AddAttrs(slog.String(moduleKey, module))
+	}
+	out.AddAttrs(contextAttrs(ctx)...)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	module := h.module
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Key == moduleKey {
+			module = normalizeModule(a.Value.String())
+		}
+		redacted = append(redacted, redactAttr(a))
+	}
+	return &Handler{inner: h.inner.WithAttrs(redacted), module: module}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), module: h.module}
+}
+
+// splitLegacyPrefix 解析 "[Module] message" 形式的旧日志前缀。
+func splitLegacyPrefix(msg string) (string, string, bool) {
+	if !strings.HasPrefix(msg, "[") {
+		return "", msg, false
+	}
+	end := strings.IndexByte(msg, ']')
+	if end <= 1 || end > maxLegacyPrefixLen {
+		return "", msg, false
+	}
+	name := msg[1:end]
+	if strings.ContainsAny(name, " \t:=") {
+		return "", msg, false
+	}
+	return name, strings.TrimSpace(msg[end+1:]), true
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	if logredact.IsSensitiveKey(a.Key, sensitiveLogKeys...) {
+		return slog.String(a.Key, "***")
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		out := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			out = append(out, redactAttr(ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}
+	case slog.KindAny:
+		switch raw := v.Any().(type) {
+		case map[string]any:
+			return slog.Any(a.Key, logredact.RedactMap(raw, sensitiveLogKeys...))
+		case json.RawMessage:
+			return slog.String(a.Key, logredact.RedactJSON(raw, sensitiveLogKeys...))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
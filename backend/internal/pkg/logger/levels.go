@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// LevelSnapshot 当前生效的日志级别配置
+type LevelSnapshot struct {
+	Default string            `json:"default"`
+	Modules map[string]string `json:"modules"`
+}
+
+type levelRegistry struct {
+	mu      sync.RWMutex
+	def     slog.Level
+	modules map[string]slog.Level
+	// min 为默认级别与所有模块级别中的最低值，用于 Enabled 快速路径。
+	min slog.LevelVar
+}
+
+var defaultRegistry = newLevelRegistry()
+
+func newLevelRegistry() *levelRegistry {
+	r := &levelRegistry{modules: map[string]slog.Level{}}
+	r.reset(slog.LevelInfo, nil)
+	return r
+}
+
+func (r *levelRegistry) reset(def slog.Level, modules map[string]slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.def = def
+	r.modules = make(map[string]slog.Level, len(modules))
+	for module, level := range modules {
+		if name := normalizeModule(module); name != "" {
+			r.modules[name] = level
+		}
+	}
+	r.recomputeMinLocked()
+}
+
+func (r *levelRegistry) recomputeMinLocked() {
+	min := r.def
+	for _, level := range r.modules {
+		if level < min {
+			min = level
+		}
+	}
+	r.min.Set(min)
+}
+
+func (r *levelRegistry) levelFor(module string) slog.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if module != "" {
+		if level, ok := r.modules[module]; ok {
+			return level
+		}
+	}
+	return r.def
+}
+
+// ParseLevel 解析日志级别（debug / info / warn / warning / error，忽略大小写）；空值视为 info。
+func ParseLevel(raw string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q", raw)
+	}
+}
+
+// SetDefaultLevel 设置未单独配置模块的默认日志级别。
+func SetDefaultLevel(level slog.Level) {
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+	defaultRegistry.def = level
+	defaultRegistry.recomputeMinLocked()
+}
+
+// SetModuleLevel 设置指定模块的日志级别。
+func SetModuleLevel(module string, level slog.Level) error {
+	name := normalizeModule(module)
+	if name == "" {
+		return fmt.Errorf("module is required")
+	}
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+	defaultRegistry.modules[name] = level
+	defaultRegistry.recomputeMinLocked()
+	return nil
+}
+
+// ResetModuleLevel 移除指定模块的级别覆盖，使其回落到默认级别。
+func ResetModuleLevel(module string) {
+	name := normalizeModule(module)
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+	delete(defaultRegistry.modules, name)
+	defaultRegistry.recomputeMinLocked()
+}
+
+// Levels 返回当前日志级别配置快照。
+func Levels() LevelSnapshot {
+	defaultRegistry.mu.RLock()
+	defer defaultRegistry.mu.RUnlock()
+	out := LevelSnapshot{
+		Default: levelName(defaultRegistry.def),
+		Modules: make(map[string]string, len(defaultRegistry.modules)),
+	}
+	for module, level := range defaultRegistry.modules {
+		out.Modules[module] = levelName(level)
+	}
+	return out
+}
+
+func levelName(level slog.Level) string {
+	switch {
+	case level <= slog.LevelDebug:
+		return "debug"
+	case level <= slog.LevelInfo:
+		return "info"
+	case level <= slog.LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+func normalizeModule(module string) string {
+	return strings.ToLower(strings.TrimSpace(module))
+}
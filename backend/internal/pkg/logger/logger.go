@@ -0,0 +1,104 @@
+// Package logger 提供基于 log/slog 的结构化日志。
+//
+// 功能：
+//   - JSON / logfmt 两种输出格式；
+//   - 从 context 注入请求级字段（client_request_id、user_id、api_key_id、group_id、account_id、platform）；
+//   - 按模块（module）在运行时调整日志级别；
+//   - 通过 util/logredact 对敏感字段脱敏。
+//
+// 旧的 log.Printf("[Module] ...") 调用在 Init 之后同样经过该 Handler：
+// 前缀会被解析为 module 字段，因此也受模块级别控制。
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	FormatText   = "text"
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+// Options 日志初始化参数
+type Options struct {
+	// Format 输出格式：json / logfmt（text 为 logfmt 的别名）
+	Format string
+	// Level 默认日志级别：debug / info / warn / error
+	Level string
+	// Modules 按模块覆盖日志级别，key 为模块名（忽略大小写）
+	Modules map[string]string
+	// Output 输出目标，默认 os.Stderr
+	Output io.Writer
+}
+
+// ValidateFormat 校验日志格式（空值视为 text）。
+func ValidateFormat(format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatText, FormatLogfmt, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q (expected json or logfmt)", format)
+	}
+}
+
+// Init 构建结构化日志 Handler 并设置为 slog 默认 Logger。
+// 标准库 log 包的输出也会经由该 Handler（级别为 Info）。
+func Init(opts Options) error {
+	if err := ValidateFormat(opts.Format); err != nil {
+		return err
+	}
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	modules := make(map[string]slog.Level, len(opts.Modules))
+	for module, raw := range opts.Modules {
+		l, err := ParseLevel(raw)
+		if err != nil {
+			return fmt.Errorf("module %q: %w", module, err)
+		}
+		modules[module] = l
+	}
+
+	out := opts.Output
+	if out == nil {
+		out = os.Stderr
+	}
+	// 级别过滤由 Handler 按模块完成，内部 Handler 放行全部级别。
+	innerOpts := &slog.HandlerOptions{Level: slog.Level(-1 << 10)}
+	var inner slog.Handler
+	if strings.EqualFold(strings.TrimSpace(opts.Format), FormatJSON) {
+		inner = slog.NewJSONHandler(out, innerOpts)
+	} else {
+		inner = slog.NewTextHandler(out, innerOpts)
+	}
+
+	defaultRegistry.reset(level, modules)
+	slog.SetDefault(slog.New(NewHandler(inner)))
+	return nil
+}
+
+// Module 返回绑定 module 字段的 Logger。
+// 应在使用处调用（而非缓存为包级变量），以便始终使用 Init 之后的默认 Handler。
+func Module(module string) *slog.Logger {
+	return slog.Default().With(slog.String(moduleKey, normalizeModule(module)))
+}
+
+// Printf 以 Info 级别输出格式化消息并携带 ctx 中的请求字段。
+// 用于迁移旧的 log.Printf("[Module] ...") 调用：消息前缀会被解析为 module。
+func Printf(ctx context.Context, format string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	l := slog.Default()
+	if !l.Enabled(ctx, slog.LevelInfo) {
+		return
+	}
+	l.InfoContext(ctx, fmt.Sprintf(format, args...))
+}
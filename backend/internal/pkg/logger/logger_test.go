@@ -0,0 +1,162 @@
+//go:build unit
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/stretchr/testify/require"
+)
+
+func initTestLogger(t *testing.T, opts Options) *bytes.Buffer {
+	t.Helper()
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var buf bytes.Buffer
+	opts.Format = FormatJSON
+	opts.Output = &buf
+	require.NoError(t, Init(opts))
+	return &buf
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestModuleLevels(t *testing.T) {
+	buf := initTestLogger(t, Options{Level: "info", Modules: map[string]string{"Gateway": "debug"}})
+
+	Module("gateway").Debug("gateway debug")
+	Module("ops").Debug("ops debug")
+	require.Len(t, decodeLines(t, buf), 1)
+
+	buf.Reset()
+	require.NoError(t, SetModuleLevel("ops", slog.LevelError))
+	Module("ops").Warn("ops warn")
+	require.Empty(t, buf.String())
+
+	ResetModuleLevel("ops")
+	SetDefaultLevel(slog.LevelWarn)
+	Module("ops").Warn("ops warn")
+	Module("ops").Info("ops info")
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, "ops", lines[0]["module"])
+
+	levels := Levels()
+	require.Equal(t, "warn", levels.Default)
+	require.Equal(t, map[string]string{"gateway": "debug"}, levels.Modules)
+}
+
+func TestLegacyPrefixBecomesModule(t *testing.T) {
+	buf := initTestLogger(t, Options{Level: "info", Modules: map[string]string{"quiet": "error"}})
+
+	log.Printf("[OpsAlertEvaluator] evaluated %d rules", 3)
+	log.Printf("[Quiet] suppressed")
+	Printf(context.Background(), "[not a module] kept as is")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 2)
+	require.Equal(t, "opsalertevaluator", lines[0]["module"])
+	require.Equal(t, "evaluated 3 rules", lines[0]["msg"])
+	require.NotContains(t, lines[1], "module")
+	require.Equal(t, "[not a module] kept as is", lines[1]["msg"])
+}
+
+func TestRequestFieldsFromContext(t *testing.T) {
+	buf := initTestLogger(t, Options{})
+
+	ctx := context.WithValue(context.Background(), ctxkey.ClientRequestID, "req-1")
+	ctx = WithRequestFields(ctx)
+	groupID := int64(7)
+	SetAuth(ctx, 1, 2, &groupID)
+	SetPlatform(ctx, "anthropic")
+	// 容器可变：调度后写入的账号对后续日志可见
+	SetAccount(ctx, 3, "")
+	require.Equal(t, ctx, WithRequestFields(ctx))
+
+	Printf(ctx, "[Gateway] forwarded")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, "req-1", lines[0]["client_request_id"])
+	require.EqualValues(t, 1, lines[0]["user_id"])
+	require.EqualValues(t, 2, lines[0]["api_key_id"])
+	require.EqualValues(t, 7, lines[0]["group_id"])
+	require.EqualValues(t, 3, lines[0]["account_id"])
+	require.Equal(t, "anthropic", lines[0]["platform"])
+	require.Equal(t, "gateway", lines[0]["module"])
+}
+
+func TestForkRequestFields(t *testing.T) {
+	buf := initTestLogger(t, Options{})
+
+	ctx := WithRequestFields(context.Background())
+	SetAuth(ctx, 1, 2, nil)
+	SetAccount(ctx, 3, "anthropic")
+
+	// 并发分支各自记录账号，互不覆盖，也不影响原请求
+	legA := ForkRequestFields(ctx)
+	legB := ForkRequestFields(ctx)
+	SetAccount(legA, 4, "")
+	SetAccount(legB, 5, "")
+
+	Printf(legA, "[Hedge] leg a")
+	Printf(legB, "[Hedge] leg b")
+	Printf(ctx, "[Gateway] request")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 3)
+	require.EqualValues(t, 4, lines[0]["account_id"])
+	require.EqualValues(t, 5, lines[1]["account_id"])
+	require.EqualValues(t, 3, lines[2]["account_id"])
+	for _, line := range lines {
+		require.EqualValues(t, 1, line["user_id"])
+		require.Equal(t, "anthropic", line["platform"])
+	}
+
+	plain := context.Background()
+	require.Equal(t, plain, ForkRequestFields(plain))
+}
+
+func TestSensitiveAttrsRedacted(t *testing.T) {
+	buf := initTestLogger(t, Options{})
+
+	Module("auth").With("api_key", "sk-secret").Info("login",
+		"password", "hunter2",
+		"payload", map[string]any{"refresh_token": "rt", "user": "alice"},
+		slog.Group("headers", slog.String("Authorization", "Bearer x"), slog.String("accept", "*/*")),
+	)
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, "***", lines[0]["api_key"])
+	require.Equal(t, "***", lines[0]["password"])
+	require.Equal(t, map[string]any{"refresh_token": "***", "user": "alice"}, lines[0]["payload"])
+	require.Equal(t, map[string]any{"Authorization": "***", "accept": "*/*"}, lines[0]["headers"])
+	require.NotContains(t, buf.String(), "sk-secret")
+}
+
+func TestInitRejectsInvalidOptions(t *testing.T) {
+	require.Error(t, Init(Options{Format: "xml"}))
+	require.Error(t, Init(Options{Level: "verbose"}))
+	require.Error(t, Init(Options{Modules: map[string]string{"gateway": "loud"}}))
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+type accountProbeRepository struct {
+	db *sql.DB
+}
+
+func NewAccountProbeRepository(db *sql.DB) service.AccountProbeRepository {
+	return &accountProbeRepository{db: db}
+}
+
+const accountProbeResultColumns = `id, account_id, platform, model, success, latency_ms, first_token_ms, COALESCE(error_message, ''), source, created_at`
+
+func (r *accountProbeRepository) InsertResult(ctx context.Context, result *service.AccountProbeResult) error {
+	if result == nil {
+		return nil
+	}
+	createdAt := result.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	q := `
+INSERT INTO account_probe_results (
+  account_id, platform, model, success, latency_ms, first_token_ms, error_message, source, created_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+RETURNING id`
+	return r.db.QueryRowContext(
+		ctx,
+		q,
+		result.AccountID,
+		result.Platform,
+		result.Model,
+		result.Success,
+		result.LatencyMs,
+		opsNullInt64(result.FirstTokenMs),
+		opsNullString(result.ErrorMessage),
+		result.Source,
+		createdAt,
+	).Scan(&result.ID)
+}
+
+func (r *accountProbeRepository) ListResults(ctx context.Context, accountID int64, limit int) ([]service.AccountProbeResult, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	q := `SELECT ` + accountProbeResultColumns + `
+FROM account_probe_results
+WHERE account_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, q, accountID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.AccountProbeResult, 0, limit)
+	for rows.Next() {
+		item, err := scanAccountProbeResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *accountProbeRepository) GetLatestResults(ctx context.Context, accountIDs []int64) (map[int64]*service.AccountProbeResult, error) {
+	q := `SELECT DISTINCT ON (account_id) ` + accountProbeResultColumns + `
+FROM account_probe_results`
+	args := []any{}
+	if len(accountIDs) > 0 {
+		q += ` WHERE account_id = ANY($1)`
+		args = append(args, pq.Array(accountIDs))
+	}
+	q += ` ORDER BY account_id, created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make(map[int64]*service.AccountProbeResult, len(accountIDs))
+	for rows.Next() {
+		item, err := scanAccountProbeResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[item.AccountID] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *accountProbeRepository) ListProbeErroredAccountIDs(ctx context.Context) ([]int64, error) {
+	q := `
+SELECT id
+FROM accounts
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND error_message LIKE $2
+ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, q, service.StatusError, service.AccountProbeErrorPrefix+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *accountProbeRepository) DeleteResultsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM account_probe_results WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete account probe results: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func scanAccountProbeResult(rows *sql.Rows) (*service.AccountProbeResult, error) {
+	var item service.AccountProbeResult
+	var firstTokenMs sql.NullInt64
+	if err := rows.Scan(
+		&item.ID,
+		&item.AccountID,
+		&item.Platform,
+		&item.Model,
+		&item.Success,
+		&item.LatencyMs,
+		&firstTokenMs,
+		&item.ErrorMessage,
+		&item.Source,
+		&item.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if firstTokenMs.Valid {
+		v := firstTokenMs.Int64
+		item.FirstTokenMs = &v
+	}
+	return &item, nil
+}
@@ -1496,7 +1496,7 @@ func accountEntityToService(m *dbent.Account) *service.Account {
 
 	rateMultiplier := m.RateMultiplier
 
-	return &service.Account{
+	account := &service.Account{
 		ID:                  m.ID,
 		Name:                m.Name,
 		Notes:               m.Notes,
@@ -1523,6 +1523,8 @@ func accountEntityToService(m *dbent.Account) *service.Account {
 		SessionWindowEnd:    m.SessionWindowEnd,
 		SessionWindowStatus: derefString(m.SessionWindowStatus),
 	}
+	account.CacheScheduleRule()
+	return account
 }
 
 func normalizeJSONMap(in map[string]any) map[string]any {
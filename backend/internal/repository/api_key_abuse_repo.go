@@ -0,0 +1,415 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type apiKeyAbuseRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyAbuseRepository(db *sql.DB) service.APIKeyAbuseRepository {
+	return &apiKeyAbuseRepository{db: db}
+}
+
+const apiKeyAbuseFlagColumns = `
+  f.id, f.api_key_id, f.user_id, f.score, f.window_hours,
+  f.request_count, f.distinct_ips, f.distinct_networks, f.peak_concurrent_ips, f.distinct_user_agents, f.active_hours,
+  f.status, f.action, f.rate_limit_rpm, f.auto_actioned, f.owner_notified_at,
+  f.first_detected_at, f.last_detected_at,
+  f.reviewed_by, f.reviewed_at, COALESCE(f.review_note, ''),
+  f.created_at, f.updated_at,
+  COALESCE(k.name, ''), COALESCE(k.status, ''), COALESCE(u.email, '')
+FROM api_key_abuse_flags f
+LEFT JOIN api_keys k ON k.id = f.api_key_id
+LEFT JOIN users u ON u.id = f.user_id`
+
+// ScanUsageStats 按 Key 聚合窗口内的来源特征。
+// 网段：IPv4 取前两段（/16），IPv6 取前三组（/48），作为地理分布的近似；
+// 并发 IP：按 5 分钟分桶统计不同 IP 数，取最大值。
+func (r *apiKeyAbuseRepository) ScanUsageStats(ctx context.Context, start, end time.Time, minRequests int) ([]service.APIKeyUsageStats, error) {
+	q := `
+WITH logs AS (
+  SELECT api_key_id, user_id, created_at,
+         NULLIF(ip_address, '') AS ip,
+         NULLIF(user_agent, '') AS ua
+  FROM usage_logs
+  WHERE created_at >= $1 AND created_at < $2
+),
+totals AS (
+  SELECT
+    api_key_id,
+    MAX(user_id) AS user_id,
+    COUNT(*) AS request_count,
+    COUNT(DISTINCT ip) AS distinct_ips,
+    COUNT(DISTINCT CASE
+      WHEN ip LIKE '%:%' THEN array_to_string((string_to_array(ip, ':'))[1:3], ':')
+      ELSE split_part(ip, '.', 1) || '.' || split_part(ip, '.', 2)
+    END) AS distinct_networks,
+    COUNT(DISTINCT ua) AS distinct_user_agents,
+    COUNT(DISTINCT date_trunc('hour', created_at)) AS active_hours
+  FROM logs
+  GROUP BY api_key_id
+  HAVING COUNT(*) >= $3 AND COUNT(DISTINCT ip) >= 2
+),
+peaks AS (
+  SELECT api_key_id, MAX(ips) AS peak_concurrent_ips
+  FROM (
+    SELECT l.api_key_id, COUNT(DISTINCT l.ip) AS ips
+    FROM logs l
+    JOIN totals t ON t.api_key_id = l.api_key_id
+    GROUP BY l.api_key_id, floor(extract(epoch FROM l.created_at) / 300)
+  ) buckets
+  GROUP BY api_key_id
+)
+SELECT
+  t.api_key_id, t.user_id, t.request_count, t.distinct_ips, t.distinct_networks,
+  COALESCE(p.peak_concurrent_ips, 0), t.distinct_user_agents, t.active_hours
+FROM totals t
+LEFT JOIN peaks p ON p.api_key_id = t.api_key_id
+ORDER BY t.api_key_id`
+
+	rows, err := r.db.QueryContext(ctx, q, start, end, minRequests)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.APIKeyUsageStats, 0)
+	for rows.Next() {
+		var item service.APIKeyUsageStats
+		if err := rows.Scan(
+			&item.APIKeyID,
+			&item.UserID,
+			&item.RequestCount,
+			&item.DistinctIPs,
+			&item.DistinctNetworks,
+			&item.PeakConcurrentIPs,
+			&item.DistinctUserAgents,
+			&item.ActiveHours,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *apiKeyAbuseRepository) ListTopSources(ctx context.Context, apiKeyID int64, start, end time.Time, limit int) ([]service.APIKeyAbuseSource, []service.APIKeyAbuseSource, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	ips, err := r.listTopSources(ctx, "ip_address", apiKeyID, start, end, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	userAgents, err := r.listTopSources(ctx, "user_agent", apiKeyID, start, end, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ips, userAgents, nil
+}
+
+// listTopSources column 仅为内部常量（ip_address / user_agent）
+func (r *apiKeyAbuseRepository) listTopSources(ctx context.Context, column string, apiKeyID int64, start, end time.Time, limit int) ([]service.APIKeyAbuseSource, error) {
+	q := fmt.Sprintf(`
+SELECT %[1]s, COUNT(*), MAX(created_at)
+FROM usage_logs
+WHERE api_key_id = $1 AND created_at >= $2 AND created_at <= $3
+  AND %[1]s IS NOT NULL AND %[1]s <> ''
+GROUP BY %[1]s
+ORDER BY COUNT(*) DESC, %[1]s
+LIMIT $4`, column)
+
+	rows, err := r.db.QueryContext(ctx, q, apiKeyID, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.APIKeyAbuseSource, 0, limit)
+	for rows.Next() {
+		var item service.APIKeyAbuseSource
+		if err := rows.Scan(&item.Value, &item.RequestCount, &item.LastSeenAt); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *apiKeyAbuseRepository) UpsertOpenFlag(ctx context.Context, flag *service.APIKeyAbuseFlag) (*service.APIKeyAbuseFlag, error) {
+	if flag == nil {
+		return nil, fmt.Errorf("nil flag")
+	}
+	detectedAt := flag.LastDetectedAt
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+	q := `
+INSERT INTO api_key_abuse_flags (
+  api_key_id, user_id, score, window_hours,
+  request_count, distinct_ips, distinct_networks, peak_concurrent_ips, distinct_user_agents, active_hours,
+  status, action, first_detected_at, last_detected_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,'open','none',$11,$11)
+ON CONFLICT (api_key_id) WHERE status = 'open' DO UPDATE SET
+  user_id = EXCLUDED.user_id,
+  score = EXCLUDED.score,
+  window_hours = EXCLUDED.window_hours,
+  request_count = EXCLUDED.request_count,
+  distinct_ips = EXCLUDED.distinct_ips,
+  distinct_networks = EXCLUDED.distinct_networks,
+  peak_concurrent_ips = EXCLUDED.peak_concurrent_ips,
+  distinct_user_agents = EXCLUDED.distinct_user_agents,
+  active_hours = EXCLUDED.active_hours,
+  last_detected_at = EXCLUDED.last_detected_at,
+  updated_at = NOW()
+RETURNING id`
+
+	var id int64
+	if err := r.db.QueryRowContext(
+		ctx,
+		q,
+		flag.APIKeyID,
+		flag.UserID,
+		flag.Score,
+		flag.WindowHours,
+		flag.RequestCount,
+		flag.DistinctIPs,
+		flag.DistinctNetworks,
+		flag.PeakConcurrentIPs,
+		flag.DistinctUserAgents,
+		flag.ActiveHours,
+		detectedAt,
+	).Scan(&id); err != nil {
+		return nil, err
+	}
+	return r.GetFlagByID(ctx, id)
+}
+
+func (r *apiKeyAbuseRepository) ListSuppressedKeyIDs(ctx context.Context, since time.Time) ([]int64, error) {
+	q := `
+SELECT DISTINCT api_key_id
+FROM api_key_abuse_flags
+WHERE status = 'dismissed' AND reviewed_at >= $1`
+	rows, err := r.db.QueryContext(ctx, q, since)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *apiKeyAbuseRepository) GetFlagByID(ctx context.Context, id int64) (*service.APIKeyAbuseFlag, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+apiKeyAbuseFlagColumns+"\nWHERE f.id = $1", id)
+	flag, err := scanAPIKeyAbuseFlag(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrAPIKeyAbuseFlagNotFound
+		}
+		return nil, err
+	}
+	return flag, nil
+}
+
+func (r *apiKeyAbuseRepository) ListFlags(ctx context.Context, params pagination.PaginationParams, filter service.APIKeyAbuseFlagFilter) ([]service.APIKeyAbuseFlag, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 3)
+	args := make([]any, 0, 5)
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("f.status = $%d", len(args)))
+	}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("f.user_id = $%d", len(args)))
+	}
+	if filter.APIKeyID > 0 {
+		args = append(args, filter.APIKeyID)
+		conditions = append(conditions, fmt.Sprintf("f.api_key_id = $%d", len(args)))
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM api_key_abuse_flags f "+where, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + apiKeyAbuseFlagColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY f.score DESC, f.id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.APIKeyAbuseFlag, 0, params.Limit())
+	for rows.Next() {
+		flag, err := scanAPIKeyAbuseFlag(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *apiKeyAbuseRepository) UpdateFlagAction(ctx context.Context, id int64, action string, rateLimitRPM *int, auto bool) error {
+	q := `
+UPDATE api_key_abuse_flags
+SET action = $2, rate_limit_rpm = $3, auto_actioned = $4, updated_at = NOW()
+WHERE id = $1 AND status = 'open'`
+	res, err := r.db.ExecContext(ctx, q, id, action, nullInt(rateLimitRPM), auto)
+	if err != nil {
+		return err
+	}
+	return requireAPIKeyAbuseFlagAffected(res)
+}
+
+func (r *apiKeyAbuseRepository) ReviewFlag(ctx context.Context, id int64, status, action string, rateLimitRPM *int, reviewerID *int64, note string) error {
+	q := `
+UPDATE api_key_abuse_flags
+SET status = $2,
+    action = $3,
+    rate_limit_rpm = $4,
+    auto_actioned = false,
+    reviewed_by = $5,
+    reviewed_at = NOW(),
+    review_note = $6,
+    updated_at = NOW()
+WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, q, id, status, action, nullInt(rateLimitRPM), opsNullInt64(reviewerID), opsNullString(note))
+	if err != nil {
+		return err
+	}
+	return requireAPIKeyAbuseFlagAffected(res)
+}
+
+func (r *apiKeyAbuseRepository) MarkOwnerNotified(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_key_abuse_flags SET owner_notified_at = $2, updated_at = NOW() WHERE id = $1`, id, at)
+	return err
+}
+
+func (r *apiKeyAbuseRepository) ListRateLimitedKeys(ctx context.Context) (map[int64]int, error) {
+	q := `
+SELECT api_key_id, rate_limit_rpm
+FROM api_key_abuse_flags
+WHERE status = 'open' AND action = 'rate_limited' AND rate_limit_rpm IS NOT NULL`
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make(map[int64]int)
+	for rows.Next() {
+		var (
+			apiKeyID int64
+			rpm      int
+		)
+		if err := rows.Scan(&apiKeyID, &rpm); err != nil {
+			return nil, err
+		}
+		out[apiKeyID] = rpm
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func requireAPIKeyAbuseFlagAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrAPIKeyAbuseFlagNotFound
+	}
+	return nil
+}
+
+func scanAPIKeyAbuseFlag(row interface{ Scan(dest ...any) error }) (*service.APIKeyAbuseFlag, error) {
+	var (
+		flag            service.APIKeyAbuseFlag
+		rateLimitRPM    sql.NullInt64
+		ownerNotifiedAt sql.NullTime
+		reviewedBy      sql.NullInt64
+		reviewedAt      sql.NullTime
+	)
+	if err := row.Scan(
+		&flag.ID,
+		&flag.APIKeyID,
+		&flag.UserID,
+		&flag.Score,
+		&flag.WindowHours,
+		&flag.RequestCount,
+		&flag.DistinctIPs,
+		&flag.DistinctNetworks,
+		&flag.PeakConcurrentIPs,
+		&flag.DistinctUserAgents,
+		&flag.ActiveHours,
+		&flag.Status,
+		&flag.Action,
+		&rateLimitRPM,
+		&flag.AutoActioned,
+		&ownerNotifiedAt,
+		&flag.FirstDetectedAt,
+		&flag.LastDetectedAt,
+		&reviewedBy,
+		&reviewedAt,
+		&flag.ReviewNote,
+		&flag.CreatedAt,
+		&flag.UpdatedAt,
+		&flag.APIKeyName,
+		&flag.APIKeyStatus,
+		&flag.UserEmail,
+	); err != nil {
+		return nil, err
+	}
+	if rateLimitRPM.Valid {
+		v := int(rateLimitRPM.Int64)
+		flag.RateLimitRPM = &v
+	}
+	if ownerNotifiedAt.Valid {
+		t := ownerNotifiedAt.Time
+		flag.OwnerNotifiedAt = &t
+	}
+	if reviewedBy.Valid {
+		v := reviewedBy.Int64
+		flag.ReviewedBy = &v
+	}
+	if reviewedAt.Valid {
+		t := reviewedAt.Time
+		flag.ReviewedAt = &t
+	}
+	return &flag, nil
+}
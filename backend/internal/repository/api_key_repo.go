@@ -151,6 +151,10 @@ group.FieldCacheReadTransferRatio,
 				group.FieldCacheReadTransferProbability,
 				group.FieldMcpXMLInject,
 				group.FieldSupportedModelScopes,
+				group.FieldHedgeEnabled,
+				group.FieldHedgeDelayMs,
+				group.FieldHedgeMaxRatio,
+				group.FieldScheduleRule,
 			)
 		}).
 		Only(ctx)
@@ -494,6 +498,10 @@ ID:                              g.ID,
 		MCPXMLInject:                    g.McpXMLInject,
 		SupportedModelScopes:            g.SupportedModelScopes,
 		SortOrder:                       g.SortOrder,
+		HedgeEnabled:                    g.HedgeEnabled,
+		HedgeDelayMs:                    g.HedgeDelayMs,
+		HedgeMaxRatio:                   g.HedgeMaxRatio,
+		ScheduleRule:                    g.ScheduleRule,
 		CreatedAt:                       g.CreatedAt,
 		UpdatedAt:                       g.UpdatedAt,
 	}
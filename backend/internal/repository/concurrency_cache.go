@@ -25,6 +25,8 @@ const (
 	// 并发槽位键前缀（有序集合）
 	// 格式: concurrency:account:{accountID}
 	accountSlotKeyPrefix = "concurrency:account:"
+	// 格式: concurrency:account_model:{accountID}:{pattern}
+	accountModelSlotKeyPrefix = "concurrency:account_model:"
 	// 格式: concurrency:user:{userID}
 	userSlotKeyPrefix = "concurrency:user:"
 	// 等待队列计数器格式: concurrency:wait:{userID}
@@ -282,6 +284,10 @@ func accountSlotKey(accountID int64) string {
 	return fmt.Sprintf("%s%d", accountSlotKeyPrefix, accountID)
 }
 
+func accountModelSlotKey(accountID int64, pattern string) string {
+	return fmt.Sprintf("%s%d:%s", accountModelSlotKeyPrefix, accountID, pattern)
+}
+
 func userSlotKey(userID int64) string {
 	return fmt.Sprintf("%s%d", userSlotKeyPrefix, userID)
 }
@@ -321,6 +327,31 @@ func (c *concurrencyCache) GetAccountConcurrency(ctx context.Context, accountID
 	return result, nil
 }
 
+// Account model slot operations
+
+func (c *concurrencyCache) AcquireAccountModelSlot(ctx context.Context, accountID int64, pattern string, maxConcurrency int, requestID string) (bool, error) {
+	key := accountModelSlotKey(accountID, pattern)
+	result, err := acquireScript.Run(ctx, c.rdb, []string{key}, maxConcurrency, c.slotTTLSeconds, requestID).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+func (c *concurrencyCache) ReleaseAccountModelSlot(ctx context.Context, accountID int64, pattern string, requestID string) error {
+	key := accountModelSlotKey(accountID, pattern)
+	return c.rdb.ZRem(ctx, key, requestID).Err()
+}
+
+func (c *concurrencyCache) GetAccountModelConcurrency(ctx context.Context, accountID int64, pattern string) (int, error) {
+	key := accountModelSlotKey(accountID, pattern)
+	result, err := getCountScript.Run(ctx, c.rdb, []string{key}, c.slotTTLSeconds).Int()
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
 // User slot operations
 
 func (c *concurrencyCache) AcquireUserSlot(ctx context.Context, userID int64, maxConcurrency int, requestID string) (bool, error) {
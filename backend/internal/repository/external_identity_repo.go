@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type externalIdentityRepository struct {
+	db *sql.DB
+}
+
+func NewExternalIdentityRepository(db *sql.DB) service.ExternalIdentityRepository {
+	return &externalIdentityRepository{db: db}
+}
+
+func (r *externalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*service.ExternalIdentity, error) {
+	q := `
+SELECT id, user_id, provider, subject, COALESCE(email, ''), COALESCE(username, ''),
+  last_login_at, created_at, updated_at
+FROM user_external_identities
+WHERE provider = $1 AND subject = $2`
+
+	var (
+		identity    service.ExternalIdentity
+		lastLoginAt sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, q, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.Username,
+		&lastLoginAt,
+		&identity.CreatedAt,
+		&identity.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if lastLoginAt.Valid {
+		t := lastLoginAt.Time
+		identity.LastLoginAt = &t
+	}
+	return &identity, nil
+}
+
+func (r *externalIdentityRepository) Create(ctx context.Context, identity *service.ExternalIdentity) error {
+	q := `
+INSERT INTO user_external_identities (user_id, provider, subject, email, username, last_login_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (provider, subject) DO NOTHING
+RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx, q,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		opsNullString(identity.Email),
+		opsNullString(identity.Username),
+	).Scan(&identity.ID, &identity.CreatedAt, &identity.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+func (r *externalIdentityRepository) TouchLogin(ctx context.Context, id int64, email, username string) error {
+	q := `
+UPDATE user_external_identities
+SET email = COALESCE($2, email), username = COALESCE($3, username), last_login_at = NOW(), updated_at = NOW()
+WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, q, id, opsNullString(email), opsNullString(username))
+	return err
+}
+
+func (r *externalIdentityRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_external_identities WHERE id = $1`, id)
+	return err
+}
@@ -54,13 +54,21 @@ SetNillableFallbackGroupIDOnInvalidRequest(groupIn.FallbackGroupIDOnInvalidReque
 		SetModelRoutingEnabled(groupIn.ModelRoutingEnabled).
 		SetCacheReadTransferRatio(groupIn.CacheReadTransferRatio).
 		SetCacheReadTransferProbability(groupIn.CacheReadTransferProbability).
-		SetMcpXMLInject(groupIn.MCPXMLInject)
+		SetMcpXMLInject(groupIn.MCPXMLInject).
+		SetHedgeEnabled(groupIn.HedgeEnabled).
+		SetHedgeDelayMs(groupIn.HedgeDelayMs).
+		SetHedgeMaxRatio(groupIn.HedgeMaxRatio)
 
 	// 设置模型路由配置
 	if groupIn.ModelRouting != nil {
 		builder = builder.SetModelRouting(groupIn.ModelRouting)
 	}
 
+	// 设置调度时间窗口
+	if groupIn.ScheduleRule != nil {
+		builder = builder.SetScheduleRule(groupIn.ScheduleRule)
+	}
+
 	// 设置支持的模型系列（始终设置，空数组表示不限制）
 	builder = builder.SetSupportedModelScopes(groupIn.SupportedModelScopes)
 
@@ -117,7 +125,10 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 		SetModelRoutingEnabled(groupIn.ModelRoutingEnabled).
 SetCacheReadTransferRatio(groupIn.CacheReadTransferRatio).
 		SetCacheReadTransferProbability(groupIn.CacheReadTransferProbability).
-		SetMcpXMLInject(groupIn.MCPXMLInject)
+		SetMcpXMLInject(groupIn.MCPXMLInject).
+		SetHedgeEnabled(groupIn.HedgeEnabled).
+		SetHedgeDelayMs(groupIn.HedgeDelayMs).
+		SetHedgeMaxRatio(groupIn.HedgeMaxRatio)
 
 	// 处理 FallbackGroupID：nil 时清除，否则设置
 	if groupIn.FallbackGroupID != nil {
@@ -139,6 +150,13 @@ SetCacheReadTransferRatio(groupIn.CacheReadTransferRatio).
 		builder = builder.ClearModelRouting()
 	}
 
+	// 处理 ScheduleRule：nil 时清除，否则设置
+	if groupIn.ScheduleRule != nil {
+		builder = builder.SetScheduleRule(groupIn.ScheduleRule)
+	} else {
+		builder = builder.ClearScheduleRule()
+	}
+
 	// 处理 SupportedModelScopes（始终设置，空数组表示不限制）
 	builder = builder.SetSupportedModelScopes(groupIn.SupportedModelScopes)
 
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type impersonationRepository struct {
+	db *sql.DB
+}
+
+func NewImpersonationRepository(db *sql.DB) service.ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+const impersonationSessionColumns = `
+  s.id, s.admin_user_id, s.target_user_id, s.reason,
+  COALESCE(s.ip_address, ''), COALESCE(s.user_agent, ''),
+  s.expires_at, s.ended_at, s.ended_by, s.created_at,
+  COALESCE(a.email, ''), COALESCE(t.email, ''),
+  (SELECT COUNT(*) FROM impersonation_actions ia WHERE ia.session_id = s.id)
+FROM impersonation_sessions s
+LEFT JOIN users a ON a.id = s.admin_user_id
+LEFT JOIN users t ON t.id = s.target_user_id`
+
+func (r *impersonationRepository) CreateSession(ctx context.Context, session *service.ImpersonationSession) error {
+	if session == nil {
+		return fmt.Errorf("nil impersonation session")
+	}
+	q := `
+INSERT INTO impersonation_sessions (admin_user_id, target_user_id, reason, ip_address, user_agent, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at`
+	return r.db.QueryRowContext(
+		ctx,
+		q,
+		session.AdminUserID,
+		session.TargetUserID,
+		session.Reason,
+		opsNullString(session.IPAddress),
+		opsNullString(session.UserAgent),
+		session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt)
+}
+
+func (r *impersonationRepository) GetSession(ctx context.Context, id int64) (*service.ImpersonationSession, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+impersonationSessionColumns+"\nWHERE s.id = $1", id)
+	session, err := scanImpersonationSession(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrImpersonationNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *impersonationRepository) EndSession(ctx context.Context, id, endedBy int64, endedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE impersonation_sessions
+SET ended_at = $2, ended_by = $3
+WHERE id = $1 AND ended_at IS NULL`, id, endedAt, endedBy)
+	return err
+}
+
+func (r *impersonationRepository) ListSessions(ctx context.Context, params pagination.PaginationParams, filter service.ImpersonationSessionFilter) ([]service.ImpersonationSession, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 3)
+	args := make([]any, 0, 5)
+	if filter.AdminUserID > 0 {
+		args = append(args, filter.AdminUserID)
+		conditions = append(conditions, fmt.Sprintf("s.admin_user_id = $%d", len(args)))
+	}
+	if filter.TargetUserID > 0 {
+		args = append(args, filter.TargetUserID)
+		conditions = append(conditions, fmt.Sprintf("s.target_user_id = $%d", len(args)))
+	}
+	if filter.ActiveOnly {
+		conditions = append(conditions, "s.ended_at IS NULL AND s.expires_at > NOW()")
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM impersonation_sessions s "+where, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + impersonationSessionColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY s.created_at DESC, s.id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ImpersonationSession, 0, params.Limit())
+	for rows.Next() {
+		session, err := scanImpersonationSession(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *impersonationRepository) CreateAction(ctx context.Context, action *service.ImpersonationAction) error {
+	if action == nil {
+		return fmt.Errorf("nil impersonation action")
+	}
+	q := `
+INSERT INTO impersonation_actions (session_id, admin_user_id, target_user_id, method, path, status_code, ip_address)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at`
+	return r.db.QueryRowContext(
+		ctx,
+		q,
+		action.SessionID,
+		action.AdminUserID,
+		action.TargetUserID,
+		action.Method,
+		action.Path,
+		action.StatusCode,
+		opsNullString(action.IPAddress),
+	).Scan(&action.ID, &action.CreatedAt)
+}
+
+func (r *impersonationRepository) ListActions(ctx context.Context, sessionID int64, params pagination.PaginationParams) ([]service.ImpersonationAction, *pagination.PaginationResult, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM impersonation_actions WHERE session_id = $1", sessionID).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := `
+SELECT id, session_id, admin_user_id, target_user_id, method, path, status_code, COALESCE(ip_address, ''), created_at
+FROM impersonation_actions
+WHERE session_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, q, sessionID, params.Limit(), params.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ImpersonationAction, 0, params.Limit())
+	for rows.Next() {
+		var action service.ImpersonationAction
+		if err := rows.Scan(
+			&action.ID,
+			&action.SessionID,
+			&action.AdminUserID,
+			&action.TargetUserID,
+			&action.Method,
+			&action.Path,
+			&action.StatusCode,
+			&action.IPAddress,
+			&action.CreatedAt,
+		); err != nil {
+			return nil, nil, err
+		}
+		out = append(out, action)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func scanImpersonationSession(row interface{ Scan(dest ...any) error }) (*service.ImpersonationSession, error) {
+	var (
+		session service.ImpersonationSession
+		endedAt sql.NullTime
+		endedBy sql.NullInt64
+	)
+	if err := row.Scan(
+		&session.ID,
+		&session.AdminUserID,
+		&session.TargetUserID,
+		&session.Reason,
+		&session.IPAddress,
+		&session.UserAgent,
+		&session.ExpiresAt,
+		&endedAt,
+		&endedBy,
+		&session.CreatedAt,
+		&session.AdminEmail,
+		&session.TargetEmail,
+		&session.ActionCount,
+	); err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		t := endedAt.Time
+		session.EndedAt = &t
+	}
+	if endedBy.Valid {
+		v := endedBy.Int64
+		session.EndedBy = &v
+	}
+	return &session, nil
+}
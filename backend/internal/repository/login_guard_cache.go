@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginGuardFailPrefix  = "login_guard:fail:"
+	loginGuardLockPrefix  = "login_guard:lock:"
+	loginGuardLocksPrefix = "login_guard:locks:"
+)
+
+// loginGuardFailKey 失败计数 Hash：count / last（毫秒时间戳）
+func loginGuardFailKey(scope, key string) string {
+	return fmt.Sprintf("%s%s:%s", loginGuardFailPrefix, scope, key)
+}
+
+func loginGuardLockKey(scope, key string) string {
+	return fmt.Sprintf("%s%s:%s", loginGuardLockPrefix, scope, key)
+}
+
+// loginGuardLocksKey 锁定索引 ZSET，score 为锁定截止时间（秒），供管理员列表使用
+func loginGuardLocksKey(scope string) string {
+	return loginGuardLocksPrefix + scope
+}
+
+// recordLoginFailureScript 原子地累加失败次数、记录时间并刷新窗口
+var recordLoginFailureScript = redis.NewScript(`
+local count = redis.call('HINCRBY', KEYS[1], 'count', 1)
+redis.call('HSET', KEYS[1], 'last', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return count
+`)
+
+type loginGuardCache struct {
+	rdb *redis.Client
+}
+
+// NewLoginGuardCache creates a new LoginGuardCache implementation.
+func NewLoginGuardCache(rdb *redis.Client) service.LoginGuardCache {
+	return &loginGuardCache{rdb: rdb}
+}
+
+func (c *loginGuardCache) GetFailures(ctx context.Context, scope, key string) (*service.LoginFailureState, error) {
+	vals, err := c.rdb.HMGet(ctx, loginGuardFailKey(scope, key), "count", "last").Result()
+	if err != nil {
+		return nil, err
+	}
+	state := &service.LoginFailureState{}
+	if s, ok := vals[0].(string); ok {
+		state.Count, _ = strconv.Atoi(s)
+	}
+	if s, ok := vals[1].(string); ok {
+		if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+			state.LastFailedAt = time.UnixMilli(ms)
+		}
+	}
+	return state, nil
+}
+
+func (c *loginGuardCache) RecordFailure(ctx context.Context, scope, key string, at time.Time, window time.Duration) (*service.LoginFailureState, error) {
+	count, err := recordLoginFailureScript.Run(ctx, c.rdb, []string{loginGuardFailKey(scope, key)}, at.UnixMilli(), window.Milliseconds()).Int()
+	if err != nil {
+		return nil, err
+	}
+	return &service.LoginFailureState{Count: count, LastFailedAt: at}, nil
+}
+
+func (c *loginGuardCache) ResetFailures(ctx context.Context, scope, key string) error {
+	return c.rdb.Del(ctx, loginGuardFailKey(scope, key)).Err()
+}
+
+func (c *loginGuardCache) SetLock(ctx context.Context, scope, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	pipe := c.rdb.TxPipeline()
+	pipe.Set(ctx, loginGuardLockKey(scope, key), until.Unix(), ttl)
+	pipe.ZAdd(ctx, loginGuardLocksKey(scope), redis.Z{Score: float64(until.Unix()), Member: key})
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *loginGuardCache) GetLock(ctx context.Context, scope, key string) (time.Time, error) {
+	val, err := c.rdb.Get(ctx, loginGuardLockKey(scope, key)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.Unix(val, 0), nil
+}
+
+func (c *loginGuardCache) DeleteLock(ctx context.Context, scope, key string) (bool, error) {
+	pipe := c.rdb.TxPipeline()
+	del := pipe.Del(ctx, loginGuardLockKey(scope, key))
+	pipe.ZRem(ctx, loginGuardLocksKey(scope), key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return del.Val() > 0, nil
+}
+
+func (c *loginGuardCache) ListLocks(ctx context.Context, scope string, now time.Time) ([]service.LoginLock, error) {
+	indexKey := loginGuardLocksKey(scope)
+	// 顺带清理已过期的索引项
+	if err := c.rdb.ZRemRangeByScore(ctx, indexKey, "-inf", strconv.FormatInt(now.Unix(), 10)).Err(); err != nil {
+		return nil, err
+	}
+	members, err := c.rdb.ZRangeWithScores(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	locks := make([]service.LoginLock, 0, len(members))
+	for _, m := range members {
+		key, ok := m.Member.(string)
+		if !ok {
+			continue
+		}
+		locks = append(locks, service.LoginLock{
+			Scope:       scope,
+			Key:         key,
+			LockedUntil: time.Unix(int64(m.Score), 0),
+		})
+	}
+	return locks, nil
+}
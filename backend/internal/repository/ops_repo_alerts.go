@@ -31,6 +31,9 @@ SELECT
   cooldown_minutes,
   COALESCE(notify_email, true),
   filters,
+  COALESCE(anomaly_method, ''),
+  COALESCE(baseline_days, 0),
+  public_status,
   last_triggered_at,
   created_at,
   updated_at
@@ -62,6 +65,9 @@ ORDER BY id DESC`
 			&rule.CooldownMinutes,
 			&rule.NotifyEmail,
 			&filtersRaw,
+			&rule.AnomalyMethod,
+			&rule.BaselineDays,
+			&rule.PublicStatus,
 			&lastTriggeredAt,
 			&rule.CreatedAt,
 			&rule.UpdatedAt,
@@ -113,10 +119,13 @@ INSERT INTO ops_alert_rules (
   cooldown_minutes,
   notify_email,
   filters,
+  anomaly_method,
+  baseline_days,
+  public_status,
   created_at,
   updated_at
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW(),NOW()
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,NOW(),NOW()
 )
 RETURNING
   id,
@@ -132,6 +141,9 @@ RETURNING
   cooldown_minutes,
   COALESCE(notify_email, true),
   filters,
+  COALESCE(anomaly_method, ''),
+  COALESCE(baseline_days, 0),
+  public_status,
   last_triggered_at,
   created_at,
   updated_at`
@@ -155,6 +167,9 @@ RETURNING
 		input.CooldownMinutes,
 		input.NotifyEmail,
 		filtersArg,
+		opsNullString(input.AnomalyMethod),
+		opsNullInt(input.BaselineDays),
+		input.PublicStatus,
 	).Scan(
 		&out.ID,
 		&out.Name,
@@ -169,6 +184,9 @@ RETURNING
 		&out.CooldownMinutes,
 		&out.NotifyEmail,
 		&filtersRaw,
+		&out.AnomalyMethod,
+		&out.BaselineDays,
+		&out.PublicStatus,
 		&lastTriggeredAt,
 		&out.CreatedAt,
 		&out.UpdatedAt,
@@ -220,6 +238,9 @@ SET
   cooldown_minutes = $11,
   notify_email = $12,
   filters = $13,
+  anomaly_method = $14,
+  baseline_days = $15,
+  public_status = $16,
   updated_at = NOW()
 WHERE id = $1
 RETURNING
@@ -236,6 +257,9 @@ RETURNING
   cooldown_minutes,
   COALESCE(notify_email, true),
   filters,
+  COALESCE(anomaly_method, ''),
+  COALESCE(baseline_days, 0),
+  public_status,
   last_triggered_at,
   created_at,
   updated_at`
@@ -260,6 +284,9 @@ RETURNING
 		input.CooldownMinutes,
 		input.NotifyEmail,
 		filtersArg,
+		opsNullString(input.AnomalyMethod),
+		opsNullInt(input.BaselineDays),
+		input.PublicStatus,
 	).Scan(
 		&out.ID,
 		&out.Name,
@@ -274,6 +301,9 @@ RETURNING
 		&out.CooldownMinutes,
 		&out.NotifyEmail,
 		&filtersRaw,
+		&out.AnomalyMethod,
+		&out.BaselineDays,
+		&out.PublicStatus,
 		&lastTriggeredAt,
 		&out.CreatedAt,
 		&out.UpdatedAt,
@@ -351,7 +381,16 @@ SELECT
   fired_at,
   resolved_at,
   email_sent,
-  created_at
+  created_at,
+  acknowledged_at,
+  acknowledged_by,
+  COALESCE(assignee, ''),
+  COALESCE(ack_note, ''),
+  escalation_level,
+  last_notified_at,
+  notification_count,
+  is_public,
+  COALESCE(public_summary, '')
 FROM ops_alert_events
 ` + where + `
 ORDER BY fired_at DESC, id DESC
@@ -365,47 +404,11 @@ LIMIT ` + limitArg
 
 	out := []*service.OpsAlertEvent{}
 	for rows.Next() {
-		var ev service.OpsAlertEvent
-		var metricValue sql.NullFloat64
-		var thresholdValue sql.NullFloat64
-		var dimensionsRaw []byte
-		var resolvedAt sql.NullTime
-		if err := rows.Scan(
-			&ev.ID,
-			&ev.RuleID,
-			&ev.Severity,
-			&ev.Status,
-			&ev.Title,
-			&ev.Description,
-			&metricValue,
-			&thresholdValue,
-			&dimensionsRaw,
-			&ev.FiredAt,
-			&resolvedAt,
-			&ev.EmailSent,
-			&ev.CreatedAt,
-		); err != nil {
+		ev, err := scanOpsAlertEvent(rows)
+		if err != nil {
 			return nil, err
 		}
-		if metricValue.Valid {
-			v := metricValue.Float64
-			ev.MetricValue = &v
-		}
-		if thresholdValue.Valid {
-			v := thresholdValue.Float64
-			ev.ThresholdValue = &v
-		}
-		if resolvedAt.Valid {
-			v := resolvedAt.Time
-			ev.ResolvedAt = &v
-		}
-		if len(dimensionsRaw) > 0 && string(dimensionsRaw) != "null" {
-			var decoded map[string]any
-			if err := json.Unmarshal(dimensionsRaw, &decoded); err == nil {
-				ev.Dimensions = decoded
-			}
-		}
-		out = append(out, &ev)
+		out = append(out, ev)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -435,7 +438,16 @@ SELECT
   fired_at,
   resolved_at,
   email_sent,
-  created_at
+  created_at,
+  acknowledged_at,
+  acknowledged_by,
+  COALESCE(assignee, ''),
+  COALESCE(ack_note, ''),
+  escalation_level,
+  last_notified_at,
+  notification_count,
+  is_public,
+  COALESCE(public_summary, '')
 FROM ops_alert_events
 WHERE id = $1`
 
@@ -472,7 +484,16 @@ SELECT
   fired_at,
   resolved_at,
   email_sent,
-  created_at
+  created_at,
+  acknowledged_at,
+  acknowledged_by,
+  COALESCE(assignee, ''),
+  COALESCE(ack_note, ''),
+  escalation_level,
+  last_notified_at,
+  notification_count,
+  is_public,
+  COALESCE(public_summary, '')
 FROM ops_alert_events
 WHERE rule_id = $1 AND status = $2
 ORDER BY fired_at DESC
@@ -511,7 +532,16 @@ SELECT
   fired_at,
   resolved_at,
   email_sent,
-  created_at
+  created_at,
+  acknowledged_at,
+  acknowledged_by,
+  COALESCE(assignee, ''),
+  COALESCE(ack_note, ''),
+  escalation_level,
+  last_notified_at,
+  notification_count,
+  is_public,
+  COALESCE(public_summary, '')
 FROM ops_alert_events
 WHERE rule_id = $1
 ORDER BY fired_at DESC
@@ -554,9 +584,10 @@ INSERT INTO ops_alert_events (
   fired_at,
   resolved_at,
   email_sent,
+  is_public,
   created_at
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW()
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW()
 )
 RETURNING
   id,
@@ -571,7 +602,16 @@ RETURNING
   fired_at,
   resolved_at,
   email_sent,
-  created_at`
+  created_at,
+  acknowledged_at,
+  acknowledged_by,
+  COALESCE(assignee, ''),
+  COALESCE(ack_note, ''),
+  escalation_level,
+  last_notified_at,
+  notification_count,
+  is_public,
+  COALESCE(public_summary, '')`
 
 	row := r.db.QueryRowContext(
 		ctx,
@@ -587,6 +627,7 @@ RETURNING
 		event.FiredAt,
 		opsNullTime(event.ResolvedAt),
 		event.EmailSent,
+		event.IsPublic,
 	)
 	return scanOpsAlertEvent(row)
 }
@@ -624,6 +665,196 @@ func (r *opsRepository) UpdateAlertEventEmailSent(ctx context.Context, eventID i
 	return err
 }
 
+func (r *opsRepository) UpdateAlertEventPublic(ctx context.Context, eventID int64, isPublic bool, summary string) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if eventID <= 0 {
+		return fmt.Errorf("invalid event id")
+	}
+
+	res, err := r.db.ExecContext(ctx, "UPDATE ops_alert_events SET is_public = $2, public_summary = $3 WHERE id = $1", eventID, isPublic, opsNullString(summary))
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AcknowledgeAlertEvent records acknowledgement on a firing event.
+func (r *opsRepository) AcknowledgeAlertEvent(ctx context.Context, input *service.OpsAlertEventAckInput, ackAt time.Time) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if input == nil || input.EventID <= 0 {
+		return fmt.Errorf("invalid event id")
+	}
+
+	q := `
+UPDATE ops_alert_events
+SET acknowledged_at = $2,
+    acknowledged_by = $3,
+    assignee = $4,
+    ack_note = $5
+WHERE id = $1 AND status = $6`
+
+	res, err := r.db.ExecContext(ctx, q,
+		input.EventID,
+		ackAt,
+		opsNullInt64(input.AcknowledgedBy),
+		opsNullString(input.Assignee),
+		opsNullString(input.Note),
+		service.OpsAlertStatusFiring,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) UpdateAlertEventEscalationLevel(ctx context.Context, eventID int64, level int) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if eventID <= 0 {
+		return fmt.Errorf("invalid event id")
+	}
+
+	_, err := r.db.ExecContext(ctx, "UPDATE ops_alert_events SET escalation_level = $2 WHERE id = $1", eventID, level)
+	return err
+}
+
+func (r *opsRepository) RecordAlertEventNotification(ctx context.Context, eventID int64, notifiedAt time.Time) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if eventID <= 0 {
+		return fmt.Errorf("invalid event id")
+	}
+
+	q := `
+UPDATE ops_alert_events
+SET email_sent = true,
+    last_notified_at = $2,
+    notification_count = notification_count + 1
+WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, eventID, notifiedAt)
+	return err
+}
+
+func (r *opsRepository) CreateAlertEventTimelineEntry(ctx context.Context, entry *service.OpsAlertEventTimelineEntry) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if entry == nil || entry.EventID <= 0 {
+		return fmt.Errorf("invalid event id")
+	}
+	if strings.TrimSpace(entry.Action) == "" {
+		return fmt.Errorf("invalid action")
+	}
+
+	detailsArg, err := opsNullJSONMap(entry.Details)
+	if err != nil {
+		return err
+	}
+
+	q := `
+INSERT INTO ops_alert_event_timeline (
+  event_id,
+  action,
+  actor_user_id,
+  message,
+  details,
+  created_at
+) VALUES (
+  $1,$2,$3,$4,$5,NOW()
+)`
+
+	_, err = r.db.ExecContext(ctx, q,
+		entry.EventID,
+		strings.TrimSpace(entry.Action),
+		opsNullInt64(entry.ActorUserID),
+		opsNullString(entry.Message),
+		detailsArg,
+	)
+	return err
+}
+
+func (r *opsRepository) ListAlertEventTimeline(ctx context.Context, eventID int64) ([]*service.OpsAlertEventTimelineEntry, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if eventID <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	q := `
+SELECT
+  id,
+  event_id,
+  action,
+  actor_user_id,
+  COALESCE(message, ''),
+  details,
+  created_at
+FROM ops_alert_event_timeline
+WHERE event_id = $1
+ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsAlertEventTimelineEntry{}
+	for rows.Next() {
+		var entry service.OpsAlertEventTimelineEntry
+		var actor sql.NullInt64
+		var detailsRaw []byte
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.EventID,
+			&entry.Action,
+			&actor,
+			&entry.Message,
+			&detailsRaw,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if actor.Valid {
+			v := actor.Int64
+			entry.ActorUserID = &v
+		}
+		if len(detailsRaw) > 0 && string(detailsRaw) != "null" {
+			var decoded map[string]any
+			if err := json.Unmarshal(detailsRaw, &decoded); err == nil {
+				entry.Details = decoded
+			}
+		}
+		out = append(out, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type opsAlertEventRow interface {
 	Scan(dest ...any) error
 }
@@ -749,6 +980,9 @@ func scanOpsAlertEvent(row opsAlertEventRow) (*service.OpsAlertEvent, error) {
 	var thresholdValue sql.NullFloat64
 	var dimensionsRaw []byte
 	var resolvedAt sql.NullTime
+	var acknowledgedAt sql.NullTime
+	var acknowledgedBy sql.NullInt64
+	var lastNotifiedAt sql.NullTime
 
 	if err := row.Scan(
 		&ev.ID,
@@ -764,9 +998,30 @@ func scanOpsAlertEvent(row opsAlertEventRow) (*service.OpsAlertEvent, error) {
 		&resolvedAt,
 		&ev.EmailSent,
 		&ev.CreatedAt,
+		&acknowledgedAt,
+		&acknowledgedBy,
+		&ev.Assignee,
+		&ev.AckNote,
+		&ev.EscalationLevel,
+		&lastNotifiedAt,
+		&ev.NotificationCount,
+		&ev.IsPublic,
+		&ev.PublicSummary,
 	); err != nil {
 		return nil, err
 	}
+	if acknowledgedAt.Valid {
+		v := acknowledgedAt.Time
+		ev.AcknowledgedAt = &v
+	}
+	if acknowledgedBy.Valid {
+		v := acknowledgedBy.Int64
+		ev.AcknowledgedBy = &v
+	}
+	if lastNotifiedAt.Valid {
+		v := lastNotifiedAt.Time
+		ev.LastNotifiedAt = &v
+	}
 	if metricValue.Valid {
 		v := metricValue.Float64
 		ev.MetricValue = &v
@@ -808,6 +1063,9 @@ func buildOpsAlertEventsWhere(filter *service.OpsAlertEventFilter) (string, []an
 		args = append(args, *filter.EmailSent)
 		clauses = append(clauses, "email_sent = $"+itoa(len(args)))
 	}
+	if filter.PublicOnly {
+		clauses = append(clauses, "is_public = true")
+	}
 	if filter.StartTime != nil && !filter.StartTime.IsZero() {
 		args = append(args, *filter.StartTime)
 		clauses = append(clauses, "fired_at >= $"+itoa(len(args)))
@@ -851,3 +1109,238 @@ func opsNullJSONMap(v map[string]any) (any, error) {
 	}
 	return sql.NullString{String: string(b), Valid: true}, nil
 }
+
+// CountFailedAccountProbes counts accounts whose latest synthetic probe within [start, end) failed.
+func (r *opsRepository) CountFailedAccountProbes(ctx context.Context, start, end time.Time, platform string, groupID *int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, fmt.Errorf("nil ops repository")
+	}
+
+	args := []any{start, end}
+	clauses := []string{"p.created_at >= $1", "p.created_at < $2"}
+	if platform = strings.TrimSpace(platform); platform != "" {
+		args = append(args, platform)
+		clauses = append(clauses, "p.platform = $"+itoa(len(args)))
+	}
+	if groupID != nil && *groupID > 0 {
+		args = append(args, *groupID)
+		clauses = append(clauses, "EXISTS (SELECT 1 FROM account_groups ag WHERE ag.account_id = p.account_id AND ag.group_id = $"+itoa(len(args))+")")
+	}
+
+	q := `
+SELECT COUNT(*)
+FROM (
+  SELECT DISTINCT ON (p.account_id) p.success
+  FROM account_probe_results p
+  WHERE ` + strings.Join(clauses, " AND ") + `
+  ORDER BY p.account_id, p.created_at DESC, p.id DESC
+) latest
+WHERE latest.success = false`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, q, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAlertUsageMetrics aggregates successful usage logs for usage-based alert metrics.
+// Latency percentiles come from the pre-aggregated latency histogram (see getAlertLatencyHistograms).
+func (r *opsRepository) GetAlertUsageMetrics(ctx context.Context, filter *service.OpsAlertMetricFilter) (*service.OpsAlertUsageMetrics, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("nil filter")
+	}
+
+	join, where, args := buildAlertUsageWhere(filter)
+	q := `
+SELECT
+  COUNT(*) AS request_count,
+  COALESCE(SUM(input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens), 0) AS token_consumed,
+  COALESCE(SUM(actual_cost), 0) AS spend_usd
+FROM usage_logs ul
+` + join + `
+` + where
+
+	var (
+		out    service.OpsAlertUsageMetrics
+		tokens sql.NullInt64
+		spend  sql.NullFloat64
+	)
+	if err := r.db.QueryRowContext(ctx, q, args...).Scan(
+		&out.RequestCount,
+		&tokens,
+		&spend,
+	); err != nil {
+		return nil, err
+	}
+	if tokens.Valid {
+		out.TokenConsumed = tokens.Int64
+	}
+	if spend.Valid {
+		out.SpendUSD = spend.Float64
+	}
+
+	duration, ttft, err := r.getAlertLatencyHistograms(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	out.Duration = duration.percentiles()
+	out.TTFT = ttft.percentiles()
+	return &out, nil
+}
+
+// ListAlertAccountErrorStats returns per-account success and SLA error counts within the alert window.
+func (r *opsRepository) ListAlertAccountErrorStats(ctx context.Context, filter *service.OpsAlertMetricFilter) ([]*service.OpsAlertAccountErrorStats, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("nil filter")
+	}
+
+	usageJoin, usageWhere, usageArgs := buildAlertUsageWhere(filter)
+	errorWhere, errorArgs := buildAlertErrorWhere(filter, len(usageArgs)+1)
+
+	q := `
+WITH usage_counts AS (
+  SELECT ul.account_id, COUNT(*) AS cnt
+  FROM usage_logs ul
+  ` + usageJoin + `
+  ` + usageWhere + `
+  GROUP BY 1
+),
+error_counts AS (
+  SELECT account_id, COUNT(*) AS cnt
+  FROM ops_error_logs
+  ` + errorWhere + `
+    AND account_id IS NOT NULL
+    AND COALESCE(status_code, 0) >= 400
+    AND NOT is_business_limited
+  GROUP BY 1
+)
+SELECT COALESCE(u.account_id, e.account_id), COALESCE(u.cnt, 0), COALESCE(e.cnt, 0)
+FROM usage_counts u
+FULL OUTER JOIN error_counts e ON u.account_id = e.account_id`
+
+	rows, err := r.db.QueryContext(ctx, q, append(usageArgs, errorArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsAlertAccountErrorStats{}
+	for rows.Next() {
+		var item service.OpsAlertAccountErrorStats
+		if err := rows.Scan(&item.AccountID, &item.SuccessCount, &item.ErrorCountSLA); err != nil {
+			return nil, err
+		}
+		out = append(out, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func buildAlertUsageWhere(filter *service.OpsAlertMetricFilter) (join string, where string, args []any) {
+	join, where, args, idx := buildUsageWhere(&service.OpsDashboardFilter{
+		Platform: filter.Platform,
+		GroupID:  filter.GroupID,
+	}, filter.StartTime.UTC(), filter.EndTime.UTC(), 1)
+	if filter.UserID != nil && *filter.UserID > 0 {
+		args = append(args, *filter.UserID)
+		where += fmt.Sprintf(" AND ul.user_id = $%d", idx)
+		idx++
+	}
+	if filter.AccountID != nil && *filter.AccountID > 0 {
+		args = append(args, *filter.AccountID)
+		where += fmt.Sprintf(" AND ul.account_id = $%d", idx)
+	}
+	return join, where, args
+}
+
+func buildAlertErrorWhere(filter *service.OpsAlertMetricFilter, startIndex int) (where string, args []any) {
+	where, args, idx := buildErrorWhere(&service.OpsDashboardFilter{
+		Platform: filter.Platform,
+		GroupID:  filter.GroupID,
+	}, filter.StartTime.UTC(), filter.EndTime.UTC(), startIndex)
+	if filter.UserID != nil && *filter.UserID > 0 {
+		args = append(args, *filter.UserID)
+		where += fmt.Sprintf(" AND user_id = $%d", idx)
+		idx++
+	}
+	if filter.AccountID != nil && *filter.AccountID > 0 {
+		args = append(args, *filter.AccountID)
+		where += fmt.Sprintf(" AND account_id = $%d", idx)
+	}
+	return where, args
+}
+
+// ListAlertBaselineBuckets returns pre-aggregated hourly rows at the requested bucket starts.
+// Scope follows listHourlyMetricsRows (overall / platform / group).
+func (r *opsRepository) ListAlertBaselineBuckets(ctx context.Context, platform string, groupID *int64, bucketStarts []time.Time) ([]*service.OpsAlertBaselineBucket, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if len(bucketStarts) == 0 {
+		return []*service.OpsAlertBaselineBucket{}, nil
+	}
+
+	wanted := make(map[int64]struct{}, len(bucketStarts))
+	start := bucketStarts[0].UTC()
+	end := start
+	for _, b := range bucketStarts {
+		b = b.UTC()
+		wanted[b.Unix()] = struct{}{}
+		if b.Before(start) {
+			start = b
+		}
+		if b.After(end) {
+			end = b
+		}
+	}
+
+	rows, err := r.listHourlyMetricsRows(ctx, &service.OpsDashboardFilter{Platform: platform, GroupID: groupID}, start, end.Add(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*service.OpsAlertBaselineBucket, 0, len(bucketStarts))
+	for _, row := range rows {
+		if _, ok := wanted[row.bucketStart.UTC().Unix()]; !ok {
+			continue
+		}
+		out = append(out, &service.OpsAlertBaselineBucket{
+			BucketStart:                  row.bucketStart.UTC(),
+			SuccessCount:                 row.successCount,
+			ErrorCountTotal:              row.errorCountTotal,
+			ErrorCountSLA:                row.errorCountSLA,
+			UpstreamErrorCountExcl429529: row.upstreamErrorCountExcl429529,
+			TokenConsumed:                row.tokenConsumed,
+			Duration: service.OpsPercentiles{
+				P50: nullInt64ToIntPtr(row.durationP50),
+				P90: nullInt64ToIntPtr(row.durationP90),
+				P95: nullInt64ToIntPtr(row.durationP95),
+				P99: nullInt64ToIntPtr(row.durationP99),
+			},
+			TTFT: service.OpsPercentiles{
+				P50: nullInt64ToIntPtr(row.ttftP50),
+				P90: nullInt64ToIntPtr(row.ttftP90),
+				P95: nullInt64ToIntPtr(row.ttftP95),
+				P99: nullInt64ToIntPtr(row.ttftP99),
+			},
+		})
+	}
+	return out, nil
+}
+
+func nullInt64ToIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// Latency percentiles for alert rules are derived from per-minute latency histograms instead of
+// sorting raw usage_logs rows. The histogram reuses the dashboard buckets (latencyHistogramBuckets,
+// keyed by latencyHistogramRangeOrderCaseExpr) and keeps the min/max latency seen in each bucket,
+// so percentiles are interpolated inside the observed range of a bucket (including the open-ended
+// last bucket).
+const (
+	opsLatencyMetricDuration = "duration"
+	opsLatencyMetricTTFT     = "ttft"
+)
+
+// opsLatencyHistogramBin aggregates the samples of one bucket.
+type opsLatencyHistogramBin struct {
+	count int64
+	minMs int64
+	maxMs int64
+}
+
+// opsLatencyHistogram maps bucket order -> bin.
+type opsLatencyHistogram map[int]*opsLatencyHistogramBin
+
+func (h opsLatencyHistogram) add(order int, count, minMs, maxMs int64) {
+	if count <= 0 {
+		return
+	}
+	bin, ok := h[order]
+	if !ok {
+		h[order] = &opsLatencyHistogramBin{count: count, minMs: minMs, maxMs: maxMs}
+		return
+	}
+	bin.count += count
+	if minMs < bin.minMs {
+		bin.minMs = minMs
+	}
+	if maxMs > bin.maxMs {
+		bin.maxMs = maxMs
+	}
+}
+
+// percentiles uses the percentile_cont rank definition and interpolates linearly between the
+// min and max latency of the bucket holding each rank.
+func (h opsLatencyHistogram) percentiles() service.OpsPercentiles {
+	orders := make([]int, 0, len(h))
+	var total int64
+	for order, bin := range h {
+		orders = append(orders, order)
+		total += bin.count
+	}
+	if total == 0 {
+		return service.OpsPercentiles{}
+	}
+	sort.Ints(orders)
+
+	at := func(q float64) *int {
+		rank := q * float64(total-1)
+		var before int64
+		for i, order := range orders {
+			bin := h[order]
+			if rank < float64(before+bin.count) || i == len(orders)-1 {
+				frac := 0.0
+				if bin.count > 1 {
+					frac = math.Min(math.Max((rank-float64(before))/float64(bin.count-1), 0), 1)
+				}
+				v := int(math.Round(float64(bin.minMs) + float64(bin.maxMs-bin.minMs)*frac))
+				return &v
+			}
+			before += bin.count
+		}
+		return nil
+	}
+	return service.OpsPercentiles{P50: at(0.50), P90: at(0.90), P95: at(0.95), P99: at(0.99)}
+}
+
+// UpsertLatencyBucketsMinutely rebuilds the latency histograms of every complete minute in
+// [startTime, endTime) and marks those minutes as covered. Rows are emitted for the same three
+// granularities as ops_metrics_hourly (overall / platform / group).
+func (r *opsRepository) UpsertLatencyBucketsMinutely(ctx context.Context, startTime, endTime time.Time) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if startTime.IsZero() || endTime.IsZero() {
+		return nil
+	}
+	// Only complete minutes: a partial minute would overwrite the stored counts with a subset.
+	start := startTime.UTC().Truncate(time.Minute)
+	end := endTime.UTC().Truncate(time.Minute)
+	if !end.After(start) {
+		return nil
+	}
+
+	q := `
+WITH usage_base AS (
+  SELECT
+    date_trunc('minute', ul.created_at) AS bucket_start,
+    COALESCE(NULLIF(g.platform, ''), a.platform) AS platform,
+    ul.group_id AS group_id,
+    ul.duration_ms AS duration_ms,
+    ul.first_token_ms AS first_token_ms
+  FROM usage_logs ul
+  LEFT JOIN groups g ON g.id = ul.group_id
+  LEFT JOIN accounts a ON a.id = ul.account_id
+  WHERE ul.created_at >= $1 AND ul.created_at < $2
+),
+samples AS (
+  SELECT bucket_start, platform, group_id, '` + opsLatencyMetricDuration + `' AS metric,
+    ` + latencyHistogramRangeOrderCaseExpr("duration_ms") + ` AS range_order,
+    duration_ms AS latency_ms
+  FROM usage_base
+  WHERE duration_ms IS NOT NULL
+  UNION ALL
+  SELECT bucket_start, platform, group_id, '` + opsLatencyMetricTTFT + `' AS metric,
+    ` + latencyHistogramRangeOrderCaseExpr("first_token_ms") + ` AS range_order,
+    first_token_ms AS latency_ms
+  FROM usage_base
+  WHERE first_token_ms IS NOT NULL
+),
+agg AS (
+  SELECT
+    bucket_start,
+    CASE WHEN GROUPING(platform) = 1 THEN NULL ELSE platform END AS platform,
+    CASE WHEN GROUPING(group_id) = 1 THEN NULL ELSE group_id END AS group_id,
+    GROUPING(platform, group_id) AS grouping_level,
+    metric,
+    range_order,
+    COUNT(*) AS cnt,
+    MIN(latency_ms) AS min_ms,
+    MAX(latency_ms) AS max_ms
+  FROM samples
+  GROUP BY GROUPING SETS (
+    (bucket_start, metric, range_order),
+    (bucket_start, platform, metric, range_order),
+    (bucket_start, platform, group_id, metric, range_order)
+  )
+)
+INSERT INTO ops_latency_histogram_minutely (bucket_start, platform, group_id, metric, range_order, count, min_ms, max_ms, computed_at)
+SELECT bucket_start, platform, group_id, metric, range_order, cnt, min_ms, max_ms, NOW()
+FROM agg
+-- Skip platform/group rows whose key is NULL: they would collide with the coarser rollup row.
+WHERE grouping_level = 3
+   OR (grouping_level = 1 AND platform IS NOT NULL AND platform <> '')
+   OR (grouping_level = 0 AND group_id IS NOT NULL)
+ON CONFLICT (bucket_start, COALESCE(platform, ''), COALESCE(group_id, 0), metric, range_order) DO UPDATE SET
+  count = EXCLUDED.count,
+  min_ms = EXCLUDED.min_ms,
+  max_ms = EXCLUDED.max_ms,
+  computed_at = NOW()
+`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, q, start, end); err != nil {
+		return err
+	}
+	// Minutes without traffic are covered too, so readers can tell "no samples" from "not rolled up".
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO ops_latency_histogram_coverage (bucket_start, computed_at)
+SELECT gs, NOW()
+FROM generate_series($1::timestamptz, $2::timestamptz - INTERVAL '1 minute', INTERVAL '1 minute') AS gs
+ON CONFLICT (bucket_start) DO UPDATE SET computed_at = NOW()`, start, end); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getAlertLatencyHistograms returns duration/TTFT histograms for [StartTime, EndTime).
+//
+// Complete minutes the collector has covered are read from ops_latency_histogram_minutely; the
+// partial first/last minutes, uncovered minutes, and rules filtered by user/account (not
+// pre-aggregated) are bucketed on the fly from usage_logs.
+func (r *opsRepository) getAlertLatencyHistograms(ctx context.Context, filter *service.OpsAlertMetricFilter) (opsLatencyHistogram, opsLatencyHistogram, error) {
+	duration, ttft := opsLatencyHistogram{}, opsLatencyHistogram{}
+	start := filter.StartTime.UTC()
+	end := filter.EndTime.UTC()
+	if !end.After(start) {
+		return duration, ttft, nil
+	}
+	rawRanges := []opsTimeRange{{start: start, end: end}}
+
+	scoped := (filter.UserID == nil || *filter.UserID <= 0) && (filter.AccountID == nil || *filter.AccountID <= 0)
+	if scoped {
+		fullStart, fullEnd := opsLatencyFullMinutes(start, end)
+		if fullEnd.After(fullStart) {
+			covered, err := r.listLatencyHistogramCoverage(ctx, fullStart, fullEnd)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(covered) > 0 {
+				// Read exactly the minutes listed above (the collector may cover more in the meantime),
+				// so no minute is counted both here and in the raw fallback.
+				coveredUnix := make([]int64, 0, len(covered))
+				for _, m := range covered {
+					coveredUnix = append(coveredUnix, m.Unix())
+				}
+				scopeWhere, scopeArgs := opsLatencyBucketScopeWhere(filter.Platform, filter.GroupID, 4)
+				q := `
+SELECT metric, range_order, SUM(count), MIN(min_ms), MAX(max_ms)
+FROM ops_latency_histogram_minutely
+WHERE bucket_start >= $1 AND bucket_start < $2
+  AND EXTRACT(EPOCH FROM bucket_start)::bigint = ANY($3)` + scopeWhere + `
+GROUP BY 1, 2`
+				args := append([]any{fullStart, fullEnd, pq.Array(coveredUnix)}, scopeArgs...)
+				if err := r.scanLatencyHistogramRows(ctx, q, args, duration, ttft); err != nil {
+					return nil, nil, err
+				}
+			}
+			rawRanges = opsLatencyUncoveredRanges(start, end, covered)
+		}
+	}
+	if len(rawRanges) == 0 {
+		return duration, ttft, nil
+	}
+
+	rawFilter := *filter
+	rawFilter.StartTime = rawRanges[0].start
+	rawFilter.EndTime = rawRanges[len(rawRanges)-1].end
+	join, where, args := buildAlertUsageWhere(&rawFilter)
+	if len(rawRanges) > 1 {
+		clauses := make([]string, 0, len(rawRanges))
+		for _, rg := range rawRanges {
+			args = append(args, rg.start, rg.end)
+			clauses = append(clauses, fmt.Sprintf("(ul.created_at >= $%d AND ul.created_at < $%d)", len(args)-1, len(args)))
+		}
+		where += " AND (" + strings.Join(clauses, " OR ") + ")"
+	}
+
+	q := `
+SELECT '` + opsLatencyMetricDuration + `', ` + latencyHistogramRangeOrderCaseExpr("ul.duration_ms") + `, COUNT(*), MIN(ul.duration_ms), MAX(ul.duration_ms)
+FROM usage_logs ul
+` + join + `
+` + where + ` AND ul.duration_ms IS NOT NULL
+GROUP BY 2
+UNION ALL
+SELECT '` + opsLatencyMetricTTFT + `', ` + latencyHistogramRangeOrderCaseExpr("ul.first_token_ms") + `, COUNT(*), MIN(ul.first_token_ms), MAX(ul.first_token_ms)
+FROM usage_logs ul
+` + join + `
+` + where + ` AND ul.first_token_ms IS NOT NULL
+GROUP BY 2`
+	if err := r.scanLatencyHistogramRows(ctx, q, args, duration, ttft); err != nil {
+		return nil, nil, err
+	}
+	return duration, ttft, nil
+}
+
+func (r *opsRepository) listLatencyHistogramCoverage(ctx context.Context, start, end time.Time) ([]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT bucket_start
+FROM ops_latency_histogram_coverage
+WHERE bucket_start >= $1 AND bucket_start < $2
+ORDER BY bucket_start`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]time.Time, 0, int(end.Sub(start)/time.Minute))
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		out = append(out, t.UTC())
+	}
+	return out, rows.Err()
+}
+
+func (r *opsRepository) scanLatencyHistogramRows(ctx context.Context, q string, args []any, duration, ttft opsLatencyHistogram) error {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			metric       string
+			order        int
+			count        int64
+			minMs, maxMs int64
+		)
+		if err := rows.Scan(&metric, &order, &count, &minMs, &maxMs); err != nil {
+			return err
+		}
+		switch metric {
+		case opsLatencyMetricDuration:
+			duration.add(order, count, minMs, maxMs)
+		case opsLatencyMetricTTFT:
+			ttft.add(order, count, minMs, maxMs)
+		}
+	}
+	return rows.Err()
+}
+
+type opsTimeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+// opsLatencyFullMinutes returns the complete minutes inside [start, end).
+func opsLatencyFullMinutes(start, end time.Time) (time.Time, time.Time) {
+	fullStart := start.Truncate(time.Minute)
+	if fullStart.Before(start) {
+		fullStart = fullStart.Add(time.Minute)
+	}
+	return fullStart, end.Truncate(time.Minute)
+}
+
+// opsLatencyUncoveredRanges returns the parts of [start, end) that must be read from usage_logs:
+// the partial first/last minutes and every complete minute missing from covered (sorted).
+// Adjacent ranges are merged.
+func opsLatencyUncoveredRanges(start, end time.Time, covered []time.Time) []opsTimeRange {
+	fullStart, fullEnd := opsLatencyFullMinutes(start, end)
+	if !fullEnd.After(fullStart) {
+		return []opsTimeRange{{start: start, end: end}}
+	}
+
+	var out []opsTimeRange
+	appendRange := func(from, to time.Time) {
+		if !to.After(from) {
+			return
+		}
+		if n := len(out); n > 0 && out[n-1].end.Equal(from) {
+			out[n-1].end = to
+			return
+		}
+		out = append(out, opsTimeRange{start: from, end: to})
+	}
+
+	appendRange(start, fullStart)
+	i := 0
+	for m := fullStart; m.Before(fullEnd); m = m.Add(time.Minute) {
+		for i < len(covered) && covered[i].Before(m) {
+			i++
+		}
+		if i < len(covered) && covered[i].Equal(m) {
+			continue
+		}
+		appendRange(m, m.Add(time.Minute))
+	}
+	appendRange(fullEnd, end)
+	return out
+}
+
+// opsLatencyBucketScopeWhere selects the pre-aggregated rows for a scope, like listHourlyMetricsRows.
+func opsLatencyBucketScopeWhere(platform string, groupID *int64, startIndex int) (string, []any) {
+	platform = strings.TrimSpace(strings.ToLower(platform))
+	switch {
+	case groupID != nil && *groupID > 0:
+		where := fmt.Sprintf(" AND group_id = $%d", startIndex)
+		args := []any{*groupID}
+		if platform != "" {
+			where += fmt.Sprintf(" AND platform = $%d", startIndex+1)
+			args = append(args, platform)
+		}
+		return where, args
+	case platform != "":
+		return fmt.Sprintf(" AND platform = $%d AND group_id IS NULL", startIndex), []any{platform}
+	default:
+		return " AND platform IS NULL AND group_id IS NULL", nil
+	}
+}
@@ -0,0 +1,86 @@
+//go:build unit
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// opsLatencyHistogramOf buckets samples like latencyHistogramRangeOrderCaseExpr.
+func opsLatencyHistogramOf(samples ...int64) opsLatencyHistogram {
+	h := opsLatencyHistogram{}
+	for _, ms := range samples {
+		order := len(latencyHistogramBuckets)
+		for i, b := range latencyHistogramBuckets {
+			if b.upperMs > 0 && ms < int64(b.upperMs) {
+				order = i + 1
+				break
+			}
+		}
+		h.add(order, 1, ms, ms)
+	}
+	return h
+}
+
+func TestOpsLatencyHistogramPercentiles(t *testing.T) {
+	require.Nil(t, opsLatencyHistogram{}.percentiles().P50)
+
+	// 1..1000ms uniformly: interpolation inside each bucket's [min, max] matches percentile_cont.
+	samples := make([]int64, 0, 1000)
+	for ms := int64(1); ms <= 1000; ms++ {
+		samples = append(samples, ms)
+	}
+	p := opsLatencyHistogramOf(samples...).percentiles()
+	for _, c := range []struct {
+		got  *int
+		want float64
+	}{
+		{p.P50, 500.5},
+		{p.P90, 900.1},
+		{p.P95, 950.05},
+		{p.P99, 990.01},
+	} {
+		require.NotNil(t, c.got)
+		require.InDelta(t, c.want, float64(*c.got), 1)
+	}
+
+	// The open-ended last bucket is bounded by the slowest sample (same result as percentile_cont here).
+	slow := opsLatencyHistogramOf(50, 60, 70, 9000, 30000).percentiles()
+	require.Equal(t, 29160, *slow.P99)
+	require.Equal(t, 70, *slow.P50)
+
+	// Merging bins keeps the widest range.
+	h := opsLatencyHistogram{}
+	h.add(1, 2, 10, 20)
+	h.add(1, 1, 5, 15)
+	h.add(2, 0, 150, 150)
+	require.Len(t, h, 1)
+	require.Equal(t, opsLatencyHistogramBin{count: 3, minMs: 5, maxMs: 20}, *h[1])
+}
+
+func TestOpsLatencyUncoveredRanges(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	start := base.Add(30 * time.Second)
+	end := base.Add(5*time.Minute + 15*time.Second)
+
+	// Nothing covered: the whole window comes from usage_logs.
+	require.Equal(t, []opsTimeRange{{start: start, end: end}}, opsLatencyUncoveredRanges(start, end, nil))
+
+	// Partial head/tail minutes and the missing 10:03 minute are read raw; nothing before start.
+	covered := []time.Time{base.Add(time.Minute), base.Add(2 * time.Minute), base.Add(4 * time.Minute)}
+	require.Equal(t, []opsTimeRange{
+		{start: start, end: base.Add(time.Minute)},
+		{start: base.Add(3 * time.Minute), end: base.Add(4 * time.Minute)},
+		{start: base.Add(5 * time.Minute), end: end},
+	}, opsLatencyUncoveredRanges(start, end, covered))
+
+	// Aligned and fully covered window: no raw reads.
+	require.Empty(t, opsLatencyUncoveredRanges(base.Add(time.Minute), base.Add(3*time.Minute), covered[:2]))
+
+	// Window shorter than a minute.
+	short := opsLatencyUncoveredRanges(start, base.Add(45*time.Second), covered)
+	require.Equal(t, []opsTimeRange{{start: start, end: base.Add(45 * time.Second)}}, short)
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const opsRequestCaptureSummaryColumns = `
+  id,
+  created_at,
+  expires_at,
+  COALESCE(request_id, ''),
+  COALESCE(client_request_id, ''),
+  user_id,
+  api_key_id,
+  account_id,
+  group_id,
+  COALESCE(platform, ''),
+  COALESCE(model, ''),
+  COALESCE(request_path, ''),
+  stream,
+  COALESCE(status_code, 0),
+  capture_reason,
+  COALESCE(request_body_bytes, 0),
+  request_truncated,
+  COALESCE(response_format, ''),
+  COALESCE(response_body_bytes, 0),
+  response_truncated`
+
+func (r *opsRepository) InsertRequestCapture(ctx context.Context, input *service.OpsRequestCapture) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return 0, fmt.Errorf("nil input")
+	}
+
+	q := `
+INSERT INTO ops_request_captures (
+  request_id,
+  client_request_id,
+  user_id,
+  api_key_id,
+  account_id,
+  group_id,
+  platform,
+  model,
+  request_path,
+  stream,
+  status_code,
+  capture_reason,
+  request_body,
+  request_body_bytes,
+  request_truncated,
+  response_body,
+  response_format,
+  response_body_bytes,
+  response_truncated,
+  created_at,
+  expires_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21
+) RETURNING id`
+
+	var id int64
+	err := r.db.QueryRowContext(ctx, q,
+		opsNullString(input.RequestID),
+		opsNullString(input.ClientRequestID),
+		opsNullInt64(input.UserID),
+		opsNullInt64(input.APIKeyID),
+		opsNullInt64(input.AccountID),
+		opsNullInt64(input.GroupID),
+		opsNullString(input.Platform),
+		opsNullString(input.Model),
+		opsNullString(input.RequestPath),
+		input.Stream,
+		opsNullInt(input.StatusCode),
+		input.CaptureReason,
+		opsNullString(input.RequestBody),
+		input.RequestBodyBytes,
+		input.RequestTruncated,
+		opsNullString(input.ResponseBody),
+		opsNullString(input.ResponseFormat),
+		input.ResponseBodyBytes,
+		input.ResponseTruncated,
+		input.CreatedAt,
+		input.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *opsRepository) ListRequestCaptures(ctx context.Context, filter *service.OpsRequestCaptureFilter) (*service.OpsRequestCaptureList, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if filter == nil {
+		filter = &service.OpsRequestCaptureFilter{}
+	}
+
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	where, args := buildOpsRequestCapturesWhere(filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ops_request_captures "+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	argsWithLimit := append(args, pageSize, (page-1)*pageSize)
+	q := "SELECT" + opsRequestCaptureSummaryColumns + "\nFROM ops_request_captures\n" + where +
+		"\nORDER BY created_at DESC, id DESC\nLIMIT $" + itoa(len(args)+1) + " OFFSET $" + itoa(len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, q, argsWithLimit...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	items := make([]*service.OpsRequestCapture, 0, pageSize)
+	for rows.Next() {
+		item, err := scanOpsRequestCaptureSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &service.OpsRequestCaptureList{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (r *opsRepository) GetRequestCaptureByID(ctx context.Context, id int64) (*service.OpsRequestCapture, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	q := "SELECT" + opsRequestCaptureSummaryColumns + `,
+  COALESCE(request_body, ''),
+  COALESCE(response_body, '')
+FROM ops_request_captures
+WHERE id = $1`
+
+	var requestBody, responseBody string
+	item, err := scanOpsRequestCaptureSummary(r.db.QueryRowContext(ctx, q, id), &requestBody, &responseBody)
+	if err != nil {
+		return nil, err
+	}
+	item.RequestBody = requestBody
+	item.ResponseBody = responseBody
+	return item, nil
+}
+
+func (r *opsRepository) DeleteRequestCapture(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_request_captures WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) PruneRequestCaptures(ctx context.Context, now time.Time, maxRows int) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, fmt.Errorf("nil ops repository")
+	}
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_request_captures WHERE expires_at <= $1", now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	deleted, _ := res.RowsAffected()
+	if maxRows <= 0 {
+		return deleted, nil
+	}
+
+	// Size cap: keep only the newest maxRows captures.
+	q := `
+DELETE FROM ops_request_captures
+WHERE id < (
+  SELECT id FROM ops_request_captures
+  ORDER BY id DESC
+  OFFSET $1
+  LIMIT 1
+)`
+	res, err = r.db.ExecContext(ctx, q, maxRows-1)
+	if err != nil {
+		return deleted, err
+	}
+	n, _ := res.RowsAffected()
+	return deleted + n, nil
+}
+
+func buildOpsRequestCapturesWhere(filter *service.OpsRequestCaptureFilter) (string, []any) {
+	clauses := make([]string, 0, 8)
+	args := make([]any, 0, 8)
+	clauses = append(clauses, "1=1")
+
+	if filter.StartTime != nil && !filter.StartTime.IsZero() {
+		args = append(args, filter.StartTime.UTC())
+		clauses = append(clauses, "created_at >= $"+itoa(len(args)))
+	}
+	if filter.EndTime != nil && !filter.EndTime.IsZero() {
+		args = append(args, filter.EndTime.UTC())
+		clauses = append(clauses, "created_at < $"+itoa(len(args)))
+	}
+	if v := strings.TrimSpace(filter.RequestID); v != "" {
+		args = append(args, v)
+		clauses = append(clauses, "request_id = $"+itoa(len(args)))
+	}
+	if v := strings.TrimSpace(filter.ClientRequestID); v != "" {
+		args = append(args, v)
+		clauses = append(clauses, "client_request_id = $"+itoa(len(args)))
+	}
+	if filter.UserID != nil && *filter.UserID > 0 {
+		args = append(args, *filter.UserID)
+		clauses = append(clauses, "user_id = $"+itoa(len(args)))
+	}
+	if filter.APIKeyID != nil && *filter.APIKeyID > 0 {
+		args = append(args, *filter.APIKeyID)
+		clauses = append(clauses, "api_key_id = $"+itoa(len(args)))
+	}
+	if filter.AccountID != nil && *filter.AccountID > 0 {
+		args = append(args, *filter.AccountID)
+		clauses = append(clauses, "account_id = $"+itoa(len(args)))
+	}
+
+	return "WHERE " + strings.Join(clauses, " AND "), args
+}
+
+type opsRequestCaptureRow interface {
+	Scan(dest ...any) error
+}
+
+func scanOpsRequestCaptureSummary(row opsRequestCaptureRow, extra ...any) (*service.OpsRequestCapture, error) {
+	var item service.OpsRequestCapture
+	var userID, apiKeyID, accountID, groupID sql.NullInt64
+
+	dest := []any{
+		&item.ID,
+		&item.CreatedAt,
+		&item.ExpiresAt,
+		&item.RequestID,
+		&item.ClientRequestID,
+		&userID,
+		&apiKeyID,
+		&accountID,
+		&groupID,
+		&item.Platform,
+		&item.Model,
+		&item.RequestPath,
+		&item.Stream,
+		&item.StatusCode,
+		&item.CaptureReason,
+		&item.RequestBodyBytes,
+		&item.RequestTruncated,
+		&item.ResponseFormat,
+		&item.ResponseBodyBytes,
+		&item.ResponseTruncated,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	item.UserID = opsNullInt64Ptr(userID)
+	item.APIKeyID = opsNullInt64Ptr(apiKeyID)
+	item.AccountID = opsNullInt64Ptr(accountID)
+	item.GroupID = opsNullInt64Ptr(groupID)
+	return &item, nil
+}
+
+func opsNullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	out := v.Int64
+	return &out
+}
@@ -0,0 +1,426 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const opsShadowRuleSelectColumns = `
+  id,
+  name,
+  COALESCE(description, ''),
+  enabled,
+  group_id,
+  shadow_account_id,
+  sample_rate,
+  expires_at,
+  created_at,
+  updated_at`
+
+const opsShadowResultSelectColumns = `
+  id,
+  rule_id,
+  group_id,
+  COALESCE(request_id, ''),
+  COALESCE(platform, ''),
+  COALESCE(model, ''),
+  COALESCE(request_path, ''),
+  stream,
+  primary_account_id,
+  COALESCE(primary_status_code, 0),
+  primary_duration_ms,
+  primary_first_token_ms,
+  COALESCE(primary_input_tokens, 0),
+  COALESCE(primary_output_tokens, 0),
+  shadow_account_id,
+  COALESCE(shadow_status_code, 0),
+  shadow_duration_ms,
+  shadow_first_token_ms,
+  COALESCE(shadow_input_tokens, 0),
+  COALESCE(shadow_output_tokens, 0),
+  COALESCE(shadow_error, ''),
+  created_at`
+
+func (r *opsRepository) ListShadowRules(ctx context.Context) ([]*service.OpsShadowRule, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT"+opsShadowRuleSelectColumns+"\nFROM ops_shadow_rules\nORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsShadowRule{}
+	for rows.Next() {
+		rule, err := scanOpsShadowRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) GetShadowRuleByID(ctx context.Context, id int64) (*service.OpsShadowRule, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT"+opsShadowRuleSelectColumns+"\nFROM ops_shadow_rules\nWHERE id = $1", id)
+	return scanOpsShadowRule(row)
+}
+
+func (r *opsRepository) CreateShadowRule(ctx context.Context, input *service.OpsShadowRule) (*service.OpsShadowRule, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+
+	q := `
+INSERT INTO ops_shadow_rules (
+  name,
+  description,
+  enabled,
+  group_id,
+  shadow_account_id,
+  sample_rate,
+  expires_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,NOW(),NOW()
+)
+RETURNING` + opsShadowRuleSelectColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		strings.TrimSpace(input.Name),
+		opsNullString(input.Description),
+		input.Enabled,
+		input.GroupID,
+		input.ShadowAccountID,
+		input.SampleRate,
+		opsNullTime(input.ExpiresAt),
+	)
+	return scanOpsShadowRule(row)
+}
+
+func (r *opsRepository) UpdateShadowRule(ctx context.Context, input *service.OpsShadowRule) (*service.OpsShadowRule, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil || input.ID <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	q := `
+UPDATE ops_shadow_rules
+SET
+  name = $2,
+  description = $3,
+  enabled = $4,
+  group_id = $5,
+  shadow_account_id = $6,
+  sample_rate = $7,
+  expires_at = $8,
+  updated_at = NOW()
+WHERE id = $1
+RETURNING` + opsShadowRuleSelectColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		input.ID,
+		strings.TrimSpace(input.Name),
+		opsNullString(input.Description),
+		input.Enabled,
+		input.GroupID,
+		input.ShadowAccountID,
+		input.SampleRate,
+		opsNullTime(input.ExpiresAt),
+	)
+	return scanOpsShadowRule(row)
+}
+
+func (r *opsRepository) DeleteShadowRule(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_shadow_rules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) InsertShadowResult(ctx context.Context, input *service.OpsShadowResult) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return fmt.Errorf("nil input")
+	}
+
+	q := `
+INSERT INTO ops_shadow_results (
+  rule_id,
+  group_id,
+  request_id,
+  platform,
+  model,
+  request_path,
+  stream,
+  primary_account_id,
+  primary_status_code,
+  primary_duration_ms,
+  primary_first_token_ms,
+  primary_input_tokens,
+  primary_output_tokens,
+  shadow_account_id,
+  shadow_status_code,
+  shadow_duration_ms,
+  shadow_first_token_ms,
+  shadow_input_tokens,
+  shadow_output_tokens,
+  shadow_error,
+  created_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21
+)`
+
+	createdAt := input.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err := r.db.ExecContext(ctx, q,
+		input.RuleID,
+		opsNullInt64(input.GroupID),
+		opsNullString(input.RequestID),
+		opsNullString(input.Platform),
+		opsNullString(input.Model),
+		opsNullString(input.RequestPath),
+		input.Stream,
+		opsNullInt64(input.PrimaryAccountID),
+		opsNullInt(input.PrimaryStatusCode),
+		opsNullInt64(input.PrimaryDurationMs),
+		opsNullInt64(input.PrimaryFirstTokenMs),
+		input.PrimaryInputTokens,
+		input.PrimaryOutputTokens,
+		input.ShadowAccountID,
+		opsNullInt(input.ShadowStatusCode),
+		opsNullInt64(input.ShadowDurationMs),
+		opsNullInt64(input.ShadowFirstTokenMs),
+		input.ShadowInputTokens,
+		input.ShadowOutputTokens,
+		opsNullString(input.ShadowError),
+		createdAt,
+	)
+	return err
+}
+
+// GetShadowReportStats aggregates both legs of a rule's results in [start, end).
+// A leg counts as successful when its status code is 2xx/3xx and (for the shadow leg) no error was recorded.
+func (r *opsRepository) GetShadowReportStats(ctx context.Context, ruleID int64, start, end time.Time) (*service.OpsShadowReportStats, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if ruleID <= 0 {
+		return nil, fmt.Errorf("invalid rule id")
+	}
+
+	q := `
+SELECT
+  COUNT(*) AS sample_count,
+  COUNT(*) FILTER (WHERE primary_status_code BETWEEN 200 AND 399) AS primary_success,
+  AVG(primary_duration_ms) AS primary_avg,
+  percentile_cont(0.50) WITHIN GROUP (ORDER BY primary_duration_ms) AS primary_p50,
+  percentile_cont(0.95) WITHIN GROUP (ORDER BY primary_duration_ms) AS primary_p95,
+  AVG(primary_first_token_ms) AS primary_first_token_avg,
+  COALESCE(SUM(primary_input_tokens), 0) AS primary_input_tokens,
+  COALESCE(SUM(primary_output_tokens), 0) AS primary_output_tokens,
+  COUNT(*) FILTER (WHERE shadow_status_code BETWEEN 200 AND 399 AND COALESCE(shadow_error, '') = '') AS shadow_success,
+  AVG(shadow_duration_ms) AS shadow_avg,
+  percentile_cont(0.50) WITHIN GROUP (ORDER BY shadow_duration_ms) AS shadow_p50,
+  percentile_cont(0.95) WITHIN GROUP (ORDER BY shadow_duration_ms) AS shadow_p95,
+  AVG(shadow_first_token_ms) AS shadow_first_token_avg,
+  COALESCE(SUM(shadow_input_tokens), 0) AS shadow_input_tokens,
+  COALESCE(SUM(shadow_output_tokens), 0) AS shadow_output_tokens
+FROM ops_shadow_results
+WHERE rule_id = $1 AND created_at >= $2 AND created_at < $3`
+
+	out := &service.OpsShadowReportStats{}
+	var (
+		primaryAvg, primaryP50, primaryP95, primaryFirstToken sql.NullFloat64
+		shadowAvg, shadowP50, shadowP95, shadowFirstToken     sql.NullFloat64
+	)
+	if err := r.db.QueryRowContext(ctx, q, ruleID, start.UTC(), end.UTC()).Scan(
+		&out.SampleCount,
+		&out.Primary.SuccessCount,
+		&primaryAvg,
+		&primaryP50,
+		&primaryP95,
+		&primaryFirstToken,
+		&out.Primary.InputTokens,
+		&out.Primary.OutputTokens,
+		&out.Shadow.SuccessCount,
+		&shadowAvg,
+		&shadowP50,
+		&shadowP95,
+		&shadowFirstToken,
+		&out.Shadow.InputTokens,
+		&out.Shadow.OutputTokens,
+	); err != nil {
+		return nil, err
+	}
+	out.Primary.DurationAvgMs = nullFloat64Ptr(primaryAvg)
+	out.Primary.DurationP50Ms = nullFloat64Ptr(primaryP50)
+	out.Primary.DurationP95Ms = nullFloat64Ptr(primaryP95)
+	out.Primary.FirstTokenAvgMs = nullFloat64Ptr(primaryFirstToken)
+	out.Shadow.DurationAvgMs = nullFloat64Ptr(shadowAvg)
+	out.Shadow.DurationP50Ms = nullFloat64Ptr(shadowP50)
+	out.Shadow.DurationP95Ms = nullFloat64Ptr(shadowP95)
+	out.Shadow.FirstTokenAvgMs = nullFloat64Ptr(shadowFirstToken)
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT COALESCE(shadow_status_code, 0) AS status_code, COUNT(*) AS cnt
+FROM ops_shadow_results
+WHERE rule_id = $1 AND created_at >= $2 AND created_at < $3
+GROUP BY 1
+ORDER BY cnt DESC, status_code ASC`, ruleID, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out.ShadowStatusCounts = []service.OpsShadowStatusCount{}
+	for rows.Next() {
+		var item service.OpsShadowStatusCount
+		if err := rows.Scan(&item.StatusCode, &item.Count); err != nil {
+			return nil, err
+		}
+		out.ShadowStatusCounts = append(out.ShadowStatusCounts, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) ListShadowResults(ctx context.Context, ruleID int64, start, end time.Time, limit int) ([]*service.OpsShadowResult, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if ruleID <= 0 {
+		return nil, fmt.Errorf("invalid rule id")
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+
+	q := "SELECT" + opsShadowResultSelectColumns + `
+FROM ops_shadow_results
+WHERE rule_id = $1 AND created_at >= $2 AND created_at < $3
+ORDER BY created_at DESC, id DESC
+LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, q, ruleID, start.UTC(), end.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsShadowResult{}
+	for rows.Next() {
+		var item service.OpsShadowResult
+		var groupID, primaryAccountID, primaryDuration, primaryFirstToken, shadowDuration, shadowFirstToken sql.NullInt64
+		if err := rows.Scan(
+			&item.ID,
+			&item.RuleID,
+			&groupID,
+			&item.RequestID,
+			&item.Platform,
+			&item.Model,
+			&item.RequestPath,
+			&item.Stream,
+			&primaryAccountID,
+			&item.PrimaryStatusCode,
+			&primaryDuration,
+			&primaryFirstToken,
+			&item.PrimaryInputTokens,
+			&item.PrimaryOutputTokens,
+			&item.ShadowAccountID,
+			&item.ShadowStatusCode,
+			&shadowDuration,
+			&shadowFirstToken,
+			&item.ShadowInputTokens,
+			&item.ShadowOutputTokens,
+			&item.ShadowError,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		item.GroupID = opsNullInt64Ptr(groupID)
+		item.PrimaryAccountID = opsNullInt64Ptr(primaryAccountID)
+		item.PrimaryDurationMs = opsNullInt64Ptr(primaryDuration)
+		item.PrimaryFirstTokenMs = opsNullInt64Ptr(primaryFirstToken)
+		item.ShadowDurationMs = opsNullInt64Ptr(shadowDuration)
+		item.ShadowFirstTokenMs = opsNullInt64Ptr(shadowFirstToken)
+		out = append(out, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type opsShadowRuleRow interface {
+	Scan(dest ...any) error
+}
+
+func scanOpsShadowRule(row opsShadowRuleRow) (*service.OpsShadowRule, error) {
+	var rule service.OpsShadowRule
+	var expiresAt sql.NullTime
+
+	if err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Description,
+		&rule.Enabled,
+		&rule.GroupID,
+		&rule.ShadowAccountID,
+		&rule.SampleRate,
+		&expiresAt,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		v := expiresAt.Time
+		rule.ExpiresAt = &v
+	}
+	return &rule, nil
+}
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const opsSLOSelectColumns = `
+  id,
+  name,
+  COALESCE(description, ''),
+  enabled,
+  COALESCE(platform, ''),
+  group_id,
+  availability_target,
+  latency_target_ms,
+  latency_target_percent,
+  window_days,
+  created_at,
+  updated_at`
+
+func (r *opsRepository) ListSLOs(ctx context.Context) ([]*service.OpsSLO, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT"+opsSLOSelectColumns+"\nFROM ops_slos\nORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsSLO{}
+	for rows.Next() {
+		slo, err := scanOpsSLO(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, slo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) GetSLOByID(ctx context.Context, id int64) (*service.OpsSLO, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT"+opsSLOSelectColumns+"\nFROM ops_slos\nWHERE id = $1", id)
+	return scanOpsSLO(row)
+}
+
+func (r *opsRepository) CreateSLO(ctx context.Context, input *service.OpsSLO) (*service.OpsSLO, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+
+	q := `
+INSERT INTO ops_slos (
+  name,
+  description,
+  enabled,
+  platform,
+  group_id,
+  availability_target,
+  latency_target_ms,
+  latency_target_percent,
+  window_days,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,NOW(),NOW()
+)
+RETURNING` + opsSLOSelectColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		strings.TrimSpace(input.Name),
+		opsNullString(input.Description),
+		input.Enabled,
+		opsNullString(input.Platform),
+		opsNullInt64(input.GroupID),
+		input.AvailabilityTarget,
+		opsNullInt(input.LatencyTargetMs),
+		opsNullFloat64(input.LatencyTargetPercent),
+		input.WindowDays,
+	)
+	return scanOpsSLO(row)
+}
+
+func (r *opsRepository) UpdateSLO(ctx context.Context, input *service.OpsSLO) (*service.OpsSLO, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil || input.ID <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	q := `
+UPDATE ops_slos
+SET
+  name = $2,
+  description = $3,
+  enabled = $4,
+  platform = $5,
+  group_id = $6,
+  availability_target = $7,
+  latency_target_ms = $8,
+  latency_target_percent = $9,
+  window_days = $10,
+  updated_at = NOW()
+WHERE id = $1
+RETURNING` + opsSLOSelectColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		input.ID,
+		strings.TrimSpace(input.Name),
+		opsNullString(input.Description),
+		input.Enabled,
+		opsNullString(input.Platform),
+		opsNullInt64(input.GroupID),
+		input.AvailabilityTarget,
+		opsNullInt(input.LatencyTargetMs),
+		opsNullFloat64(input.LatencyTargetPercent),
+		input.WindowDays,
+	)
+	return scanOpsSLO(row)
+}
+
+func (r *opsRepository) DeleteSLO(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_slos WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetSLOWindowStats returns availability counts (same semantics as the dashboard SLA) and,
+// when latencyTargetMs is set, first-token latency good/total counts for the window.
+func (r *opsRepository) GetSLOWindowStats(ctx context.Context, filter *service.OpsDashboardFilter, latencyTargetMs *int) (*service.OpsSLOWindowStats, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("nil filter")
+	}
+	if filter.StartTime.IsZero() || filter.EndTime.IsZero() {
+		return nil, fmt.Errorf("start_time/end_time required")
+	}
+
+	start := filter.StartTime.UTC()
+	end := filter.EndTime.UTC()
+	if start.After(end) {
+		return nil, fmt.Errorf("start_time must be <= end_time")
+	}
+	if end.Sub(start) > time.Duration(service.OpsSLOWindowDaysMax)*24*time.Hour {
+		return nil, fmt.Errorf("window too large")
+	}
+
+	successCount, _, err := r.queryUsageCounts(ctx, filter, start, end)
+	if err != nil {
+		return nil, err
+	}
+	_, _, errorCountSLA, _, _, _, err := r.queryErrorCounts(ctx, filter, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &service.OpsSLOWindowStats{
+		SuccessCount:  successCount,
+		ErrorCountSLA: errorCountSLA,
+	}
+	if latencyTargetMs == nil || *latencyTargetMs <= 0 {
+		return out, nil
+	}
+
+	join, where, args, next := buildUsageWhere(filter, start, end, 1)
+	args = append(args, *latencyTargetMs)
+	q := `
+SELECT
+  COALESCE(COUNT(*), 0) AS sample_count,
+  COALESCE(COUNT(*) FILTER (WHERE first_token_ms <= $` + itoa(next) + `), 0) AS good_count
+FROM usage_logs ul
+` + join + `
+` + where + `
+AND first_token_ms IS NOT NULL`
+
+	if err := r.db.QueryRowContext(ctx, q, args...).Scan(&out.LatencySampleCount, &out.LatencyGoodCount); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type opsSLORow interface {
+	Scan(dest ...any) error
+}
+
+func scanOpsSLO(row opsSLORow) (*service.OpsSLO, error) {
+	var slo service.OpsSLO
+	var groupID sql.NullInt64
+	var latencyMs sql.NullInt64
+	var latencyPercent sql.NullFloat64
+
+	if err := row.Scan(
+		&slo.ID,
+		&slo.Name,
+		&slo.Description,
+		&slo.Enabled,
+		&slo.Platform,
+		&groupID,
+		&slo.AvailabilityTarget,
+		&latencyMs,
+		&latencyPercent,
+		&slo.WindowDays,
+		&slo.CreatedAt,
+		&slo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if groupID.Valid {
+		v := groupID.Int64
+		slo.GroupID = &v
+	}
+	slo.LatencyTargetMs = nullInt64ToIntPtr(latencyMs)
+	if latencyPercent.Valid {
+		v := latencyPercent.Float64
+		slo.LatencyTargetPercent = &v
+	}
+	return &slo, nil
+}
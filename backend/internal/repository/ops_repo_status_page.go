@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// GetStatusPageStats aggregates success (usage_logs) and failure (ops_error_logs) counts per platform and
+// per (platform, model). Platform totals are returned with Model = "".
+//
+// Only provider/platform-owned, non business-limited errors count against availability: client mistakes
+// (bad requests, invalid keys) say nothing about upstream health.
+func (r *opsRepository) GetStatusPageStats(ctx context.Context, start, end time.Time) ([]*service.OpsStatusPageStatsRow, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+
+	q := `
+WITH usage_base AS (
+  SELECT COALESCE(NULLIF(g.platform,''), NULLIF(a.platform,''), '') AS platform,
+         COALESCE(NULLIF(ul.model,''), 'unknown') AS model,
+         ul.duration_ms
+  FROM usage_logs ul
+  LEFT JOIN groups g ON g.id = ul.group_id
+  LEFT JOIN accounts a ON a.id = ul.account_id
+  WHERE ul.created_at >= $1 AND ul.created_at < $2
+),
+usage_totals AS (
+  SELECT platform,
+         CASE WHEN GROUPING(model) = 1 THEN '' ELSE model END AS model,
+         COUNT(*) AS success_count,
+         percentile_cont(0.50) WITHIN GROUP (ORDER BY duration_ms) AS latency_p50
+  FROM usage_base
+  GROUP BY GROUPING SETS ((platform, model), (platform))
+),
+error_base AS (
+  SELECT COALESCE(platform, '') AS platform,
+         COALESCE(NULLIF(model,''), 'unknown') AS model
+  FROM ops_error_logs
+  WHERE created_at >= $1 AND created_at < $2
+    AND is_count_tokens = FALSE
+    AND COALESCE(status_code, 0) >= 400
+    AND error_owner IN ('provider', 'platform')
+    AND NOT is_business_limited
+),
+error_totals AS (
+  SELECT platform,
+         CASE WHEN GROUPING(model) = 1 THEN '' ELSE model END AS model,
+         COUNT(*) AS error_count
+  FROM error_base
+  GROUP BY GROUPING SETS ((platform, model), (platform))
+)
+SELECT COALESCE(u.platform, e.platform) AS platform,
+       COALESCE(u.model, e.model) AS model,
+       COALESCE(u.success_count, 0) AS success_count,
+       COALESCE(e.error_count, 0) AS error_count,
+       u.latency_p50
+FROM usage_totals u
+FULL OUTER JOIN error_totals e ON u.platform = e.platform AND u.model = e.model
+ORDER BY 1, 2`
+
+	rows, err := r.db.QueryContext(ctx, q, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsStatusPageStatsRow{}
+	for rows.Next() {
+		var (
+			row service.OpsStatusPageStatsRow
+			p50 sql.NullFloat64
+		)
+		if err := rows.Scan(&row.Platform, &row.Model, &row.SuccessCount, &row.ErrorCount, &p50); err != nil {
+			return nil, err
+		}
+		row.LatencyP50Ms = nullFloat64Ptr(p50)
+		out = append(out, &row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const opsStatusIncidentSelectColumns = `
+  id,
+  title,
+  status,
+  impact,
+  platforms,
+  started_at,
+  resolved_at,
+  created_by,
+  created_at,
+  updated_at`
+
+func (r *opsRepository) ListStatusIncidents(ctx context.Context, filter *service.OpsStatusIncidentFilter) ([]*service.OpsStatusIncident, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if filter == nil {
+		filter = &service.OpsStatusIncidentFilter{}
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	clauses := []string{}
+	args := []any{}
+	if filter.ActiveOnly {
+		clauses = append(clauses, "status <> 'resolved'")
+	}
+	if filter.Since != nil && !filter.Since.IsZero() {
+		args = append(args, *filter.Since)
+		clauses = append(clauses, "(started_at >= $"+itoa(len(args))+" OR resolved_at >= $"+itoa(len(args))+")")
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "\nWHERE " + strings.Join(clauses, " AND ")
+	}
+	args = append(args, limit)
+
+	q := "SELECT" + opsStatusIncidentSelectColumns + "\nFROM ops_status_incidents" + where +
+		"\nORDER BY started_at DESC, id DESC\nLIMIT $" + itoa(len(args))
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsStatusIncident{}
+	for rows.Next() {
+		incident, err := scanOpsStatusIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.attachStatusIncidentUpdates(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) GetStatusIncidentByID(ctx context.Context, id int64) (*service.OpsStatusIncident, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT"+opsStatusIncidentSelectColumns+"\nFROM ops_status_incidents\nWHERE id = $1", id)
+	incident, err := scanOpsStatusIncident(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStatusIncidentUpdates(ctx, []*service.OpsStatusIncident{incident}); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+func (r *opsRepository) CreateStatusIncident(ctx context.Context, input *service.OpsStatusIncident) (*service.OpsStatusIncident, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+
+	platformsArg, err := marshalOpsStatusPlatforms(input.Platforms)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+INSERT INTO ops_status_incidents (
+  title,
+  status,
+  impact,
+  platforms,
+  started_at,
+  resolved_at,
+  created_by,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,NOW(),NOW()
+)
+RETURNING` + opsStatusIncidentSelectColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		strings.TrimSpace(input.Title),
+		input.Status,
+		input.Impact,
+		platformsArg,
+		input.StartedAt,
+		opsNullTime(input.ResolvedAt),
+		opsNullInt64(input.CreatedBy),
+	)
+	return scanOpsStatusIncident(row)
+}
+
+func (r *opsRepository) UpdateStatusIncident(ctx context.Context, input *service.OpsStatusIncident) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if input == nil || input.ID <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	platformsArg, err := marshalOpsStatusPlatforms(input.Platforms)
+	if err != nil {
+		return err
+	}
+
+	q := `
+UPDATE ops_status_incidents
+SET
+  title = $2,
+  status = $3,
+  impact = $4,
+  platforms = $5,
+  started_at = $6,
+  resolved_at = $7,
+  updated_at = NOW()
+WHERE id = $1`
+
+	res, err := r.db.ExecContext(ctx, q,
+		input.ID,
+		strings.TrimSpace(input.Title),
+		input.Status,
+		input.Impact,
+		platformsArg,
+		input.StartedAt,
+		opsNullTime(input.ResolvedAt),
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) DeleteStatusIncident(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM ops_status_incident_updates WHERE incident_id = $1", id); err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_status_incidents WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) CreateStatusIncidentUpdate(ctx context.Context, input *service.OpsStatusIncidentUpdate) (*service.OpsStatusIncidentUpdate, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil || input.IncidentID <= 0 {
+		return nil, fmt.Errorf("invalid incident id")
+	}
+
+	q := `
+INSERT INTO ops_status_incident_updates (
+  incident_id,
+  status,
+  message,
+  created_by,
+  created_at
+) VALUES (
+  $1,$2,$3,$4,NOW()
+)
+RETURNING id, incident_id, status, message, created_by, created_at`
+
+	var (
+		out       service.OpsStatusIncidentUpdate
+		createdBy sql.NullInt64
+	)
+	if err := r.db.QueryRowContext(ctx, q,
+		input.IncidentID,
+		input.Status,
+		input.Message,
+		opsNullInt64(input.CreatedBy),
+	).Scan(&out.ID, &out.IncidentID, &out.Status, &out.Message, &createdBy, &out.CreatedAt); err != nil {
+		return nil, err
+	}
+	out.CreatedBy = opsNullInt64Ptr(createdBy)
+	return &out, nil
+}
+
+// attachStatusIncidentUpdates loads the update timeline (newest first) for the given incidents in one query.
+func (r *opsRepository) attachStatusIncidentUpdates(ctx context.Context, incidents []*service.OpsStatusIncident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(incidents))
+	byID := make(map[int64]*service.OpsStatusIncident, len(incidents))
+	for _, incident := range incidents {
+		incident.Updates = []*service.OpsStatusIncidentUpdate{}
+		ids = append(ids, incident.ID)
+		byID[incident.ID] = incident
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, incident_id, status, message, created_by, created_at
+FROM ops_status_incident_updates
+WHERE incident_id = ANY($1)
+ORDER BY created_at DESC, id DESC`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			u         service.OpsStatusIncidentUpdate
+			createdBy sql.NullInt64
+		)
+		if err := rows.Scan(&u.ID, &u.IncidentID, &u.Status, &u.Message, &createdBy, &u.CreatedAt); err != nil {
+			return err
+		}
+		u.CreatedBy = opsNullInt64Ptr(createdBy)
+		if incident := byID[u.IncidentID]; incident != nil {
+			incident.Updates = append(incident.Updates, &u)
+		}
+	}
+	return rows.Err()
+}
+
+func marshalOpsStatusPlatforms(platforms []string) (any, error) {
+	if len(platforms) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(platforms)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func scanOpsStatusIncident(row interface{ Scan(dest ...any) error }) (*service.OpsStatusIncident, error) {
+	var (
+		incident     service.OpsStatusIncident
+		platformsRaw []byte
+		resolvedAt   sql.NullTime
+		createdBy    sql.NullInt64
+	)
+	if err := row.Scan(
+		&incident.ID,
+		&incident.Title,
+		&incident.Status,
+		&incident.Impact,
+		&platformsRaw,
+		&incident.StartedAt,
+		&resolvedAt,
+		&createdBy,
+		&incident.CreatedAt,
+		&incident.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	incident.Platforms = []string{}
+	if len(platformsRaw) > 0 && string(platformsRaw) != "null" {
+		_ = json.Unmarshal(platformsRaw, &incident.Platforms)
+	}
+	if resolvedAt.Valid {
+		v := resolvedAt.Time
+		incident.ResolvedAt = &v
+	}
+	incident.CreatedBy = opsNullInt64Ptr(createdBy)
+	return &incident, nil
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type paymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) service.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+const paymentOrderColumns = `
+  o.id, o.order_no, o.user_id, o.provider, o.provider_order_id, o.provider_payment_id,
+  o.amount, o.pay_amount, o.currency, o.bonus_amount, o.status, o.pay_url, o.failure_reason,
+  o.refunded_amount, o.refunded_credit, o.expires_at, o.paid_at, o.refunded_at, o.created_at, o.updated_at,
+  COALESCE(u.email, '')
+FROM payment_orders o
+LEFT JOIN users u ON u.id = o.user_id`
+
+// executor 回调处理在 ent 事务中执行，此时使用事务连接
+func (r *paymentRepository) executor(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.db
+}
+
+func (r *paymentRepository) CreateOrder(ctx context.Context, order *service.PaymentOrder) error {
+	q := `
+INSERT INTO payment_orders (order_no, user_id, provider, amount, pay_amount, currency, status, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at, updated_at`
+	return scanSingleRow(ctx, r.executor(ctx), q, []any{
+		order.OrderNo, order.UserID, order.Provider, order.Amount, order.PayAmount, order.Currency, order.Status, order.ExpiresAt,
+	}, &order.ID, &order.CreatedAt, &order.UpdatedAt)
+}
+
+func (r *paymentRepository) UpdateCheckout(ctx context.Context, id int64, providerOrderID, payURL string) error {
+	_, err := r.executor(ctx).ExecContext(ctx, `
+UPDATE payment_orders SET provider_order_id = $2, pay_url = $3, updated_at = NOW()
+WHERE id = $1`, id, providerOrderID, payURL)
+	return err
+}
+
+func (r *paymentRepository) GetOrder(ctx context.Context, orderNo string) (*service.PaymentOrder, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, "SELECT"+paymentOrderColumns+"\nWHERE o.order_no = $1", orderNo)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrPaymentOrderNotFound
+	}
+	return scanPaymentOrder(rows)
+}
+
+func (r *paymentRepository) GetOrderForUpdate(ctx context.Context, provider, orderNo, providerPaymentID string) (*service.PaymentOrder, error) {
+	var (
+		where string
+		args  []any
+	)
+	switch {
+	case orderNo != "":
+		where, args = "o.order_no = $1 AND o.provider = $2", []any{orderNo, provider}
+	case providerPaymentID != "":
+		where, args = "o.provider = $1 AND o.provider_payment_id = $2", []any{provider, providerPaymentID}
+	default:
+		return nil, nil
+	}
+
+	rows, err := r.executor(ctx).QueryContext(ctx, "SELECT"+paymentOrderColumns+"\nWHERE "+where+"\nFOR UPDATE OF o", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanPaymentOrder(rows)
+}
+
+func (r *paymentRepository) UpdateOrderState(ctx context.Context, order *service.PaymentOrder) error {
+	q := `
+UPDATE payment_orders
+SET status = $2, provider_order_id = $3, provider_payment_id = $4, bonus_amount = $5, failure_reason = $6,
+    refunded_amount = $7, refunded_credit = $8, paid_at = $9, refunded_at = $10, updated_at = NOW()
+WHERE id = $1`
+	res, err := r.executor(ctx).ExecContext(ctx, q,
+		order.ID, order.Status, order.ProviderOrderID, order.ProviderPaymentID, order.BonusAmount, order.FailureReason,
+		order.RefundedAmount, order.RefundedCredit, order.PaidAt, order.RefundedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return service.ErrPaymentOrderNotFound
+	}
+	return nil
+}
+
+func (r *paymentRepository) ListOrders(ctx context.Context, params pagination.PaginationParams, filter service.PaymentOrderFilter) ([]service.PaymentOrder, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 4)
+	args := make([]any, 0, 6)
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("o.user_id = $%d", len(args)))
+	}
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if provider := strings.TrimSpace(filter.Provider); provider != "" {
+		args = append(args, provider)
+		conditions = append(conditions, fmt.Sprintf("o.provider = $%d", len(args)))
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		args = append(args, "%"+search+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(u.email ILIKE $%d OR o.order_no ILIKE $%d OR o.provider_payment_id ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	countQ := `SELECT COUNT(*) FROM payment_orders o
+LEFT JOIN users u ON u.id = o.user_id ` + where
+	if err := r.db.QueryRowContext(ctx, countQ, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + paymentOrderColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY o.id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.PaymentOrder, 0, params.Limit())
+	for rows.Next() {
+		order, err := scanPaymentOrder(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *paymentRepository) CountPending(ctx context.Context, userID int64, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM payment_orders
+WHERE user_id = $1 AND status = $2 AND expires_at > $3`, userID, service.PaymentOrderStatusPending, now).Scan(&count)
+	return count, err
+}
+
+func (r *paymentRepository) LockUserBalance(ctx context.Context, userID int64) (float64, error) {
+	var balance float64
+	err := scanSingleRow(ctx, r.executor(ctx), "SELECT balance FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", []any{userID}, &balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, service.ErrUserNotFound
+	}
+	return balance, err
+}
+
+func (r *paymentRepository) RecordEvent(ctx context.Context, provider string, event *service.PaymentEvent) (bool, error) {
+	res, err := r.executor(ctx).ExecContext(ctx, `
+INSERT INTO payment_events (provider, event_id, order_no, event_type)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (provider, event_id) DO NOTHING`, provider, event.ID, event.OrderNo, event.Type)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *paymentRepository) ExpireOrders(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+UPDATE payment_orders SET status = $1, updated_at = NOW()
+WHERE status = $2 AND expires_at <= $3`, service.PaymentOrderStatusExpired, service.PaymentOrderStatusPending, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const paymentRefundColumns = `
+  id, refund_no, order_no, amount, refunded_amount, reason, offline, status,
+  provider_refund_id, last_error, created_at, updated_at
+FROM payment_refunds`
+
+// CreateRefund 写入 refund_pending 记录；同一退款单号此前被支付商拒绝（failed）时恢复为 refund_pending
+func (r *paymentRepository) CreateRefund(ctx context.Context, refund *service.PaymentRefundRecord) error {
+	q := `
+INSERT INTO payment_refunds (refund_no, order_no, amount, refunded_amount, reason, offline, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (refund_no) DO UPDATE SET
+  status = EXCLUDED.status, reason = EXCLUDED.reason, offline = EXCLUDED.offline, last_error = '', updated_at = NOW()
+WHERE payment_refunds.status = $8
+RETURNING id, created_at, updated_at`
+	err := scanSingleRow(ctx, r.executor(ctx), q, []any{
+		refund.RefundNo, refund.OrderNo, refund.Amount, refund.RefundedAmount, refund.Reason, refund.Offline,
+		service.PaymentRefundStatusPending, service.PaymentRefundStatusFailed,
+	}, &refund.ID, &refund.CreatedAt, &refund.UpdatedAt)
+	// 无返回行：退款单号处理中 / 已完成；唯一约束冲突：订单已有其他处理中的退款
+	if errors.Is(err, sql.ErrNoRows) || isUniqueConstraintViolation(err) {
+		return service.ErrPaymentRefundInProgress
+	}
+	if err != nil {
+		return err
+	}
+	refund.Status = service.PaymentRefundStatusPending
+	refund.LastError = ""
+	return nil
+}
+
+func (r *paymentRepository) GetRefundForUpdate(ctx context.Context, refundNo string) (*service.PaymentRefundRecord, error) {
+	var refund service.PaymentRefundRecord
+	err := scanSingleRow(ctx, r.executor(ctx), "SELECT"+paymentRefundColumns+"\nWHERE refund_no = $1\nFOR UPDATE", []any{refundNo},
+		paymentRefundDest(&refund)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrPaymentRefundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (r *paymentRepository) UpdateRefund(ctx context.Context, refund *service.PaymentRefundRecord) error {
+	res, err := r.executor(ctx).ExecContext(ctx, `
+UPDATE payment_refunds SET status = $2, provider_refund_id = $3, last_error = $4, updated_at = NOW()
+WHERE refund_no = $1`, refund.RefundNo, refund.Status, refund.ProviderRefundID, refund.LastError)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return service.ErrPaymentRefundNotFound
+	}
+	return nil
+}
+
+func (r *paymentRepository) ListPendingRefunds(ctx context.Context, before time.Time, limit int) ([]service.PaymentRefundRecord, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT"+paymentRefundColumns+`
+WHERE status = $1 AND updated_at < $2
+ORDER BY id
+LIMIT $3`, service.PaymentRefundStatusPending, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.PaymentRefundRecord, 0)
+	for rows.Next() {
+		var refund service.PaymentRefundRecord
+		if err := rows.Scan(paymentRefundDest(&refund)...); err != nil {
+			return nil, err
+		}
+		out = append(out, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func paymentRefundDest(refund *service.PaymentRefundRecord) []any {
+	return []any{
+		&refund.ID,
+		&refund.RefundNo,
+		&refund.OrderNo,
+		&refund.Amount,
+		&refund.RefundedAmount,
+		&refund.Reason,
+		&refund.Offline,
+		&refund.Status,
+		&refund.ProviderRefundID,
+		&refund.LastError,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	}
+}
+
+func scanPaymentOrder(row interface{ Scan(dest ...any) error }) (*service.PaymentOrder, error) {
+	var (
+		order      service.PaymentOrder
+		paidAt     sql.NullTime
+		refundedAt sql.NullTime
+	)
+	if err := row.Scan(
+		&order.ID,
+		&order.OrderNo,
+		&order.UserID,
+		&order.Provider,
+		&order.ProviderOrderID,
+		&order.ProviderPaymentID,
+		&order.Amount,
+		&order.PayAmount,
+		&order.Currency,
+		&order.BonusAmount,
+		&order.Status,
+		&order.PayURL,
+		&order.FailureReason,
+		&order.RefundedAmount,
+		&order.RefundedCredit,
+		&order.ExpiresAt,
+		&paidAt,
+		&refundedAt,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.UserEmail,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrPaymentOrderNotFound
+		}
+		return nil, err
+	}
+	if paidAt.Valid {
+		order.PaidAt = &paidAt.Time
+	}
+	if refundedAt.Valid {
+		order.RefundedAt = &refundedAt.Time
+	}
+	return &order, nil
+}
@@ -26,7 +26,16 @@ func (r *promoCodeRepository) Create(ctx context.Context, code *service.PromoCod
 		SetMaxUses(code.MaxUses).
 		SetUsedCount(code.UsedCount).
 		SetStatus(code.Status).
-		SetNotes(code.Notes)
+		SetNotes(code.Notes).
+		SetBonusType(code.BonusType).
+		SetBonusPercent(code.BonusPercent).
+		SetMaxBonusAmount(code.MaxBonusAmount).
+		SetPerUserLimit(code.PerUserLimit).
+		SetScope(code.Scope).
+		SetNewUsersOnly(code.NewUsersOnly).
+		SetNewUserDays(code.NewUserDays).
+		SetNillableRequiredGroupID(code.RequiredGroupID).
+		SetNillableStartsAt(code.StartsAt)
 
 	if code.ExpiresAt != nil {
 		builder.SetExpiresAt(*code.ExpiresAt)
@@ -92,13 +101,30 @@ func (r *promoCodeRepository) Update(ctx context.Context, code *service.PromoCod
 		SetMaxUses(code.MaxUses).
 		SetUsedCount(code.UsedCount).
 		SetStatus(code.Status).
-		SetNotes(code.Notes)
+		SetNotes(code.Notes).
+		SetBonusType(code.BonusType).
+		SetBonusPercent(code.BonusPercent).
+		SetMaxBonusAmount(code.MaxBonusAmount).
+		SetPerUserLimit(code.PerUserLimit).
+		SetScope(code.Scope).
+		SetNewUsersOnly(code.NewUsersOnly).
+		SetNewUserDays(code.NewUserDays)
 
 	if code.ExpiresAt != nil {
 		builder.SetExpiresAt(*code.ExpiresAt)
 	} else {
 		builder.ClearExpiresAt()
 	}
+	if code.StartsAt != nil {
+		builder.SetStartsAt(*code.StartsAt)
+	} else {
+		builder.ClearStartsAt()
+	}
+	if code.RequiredGroupID != nil {
+		builder.SetRequiredGroupID(*code.RequiredGroupID)
+	} else {
+		builder.ClearRequiredGroupID()
+	}
 
 	updated, err := builder.Save(ctx)
 	if err != nil {
@@ -157,6 +183,14 @@ func (r *promoCodeRepository) CreateUsage(ctx context.Context, usage *service.Pr
 		SetPromoCodeID(usage.PromoCodeID).
 		SetUserID(usage.UserID).
 		SetBonusAmount(usage.BonusAmount).
+		SetBonusType(usage.BonusType).
+		SetBonusPercent(usage.BonusPercent).
+		SetMaxBonusAmount(usage.MaxBonusAmount).
+		SetStatus(usage.Status).
+		SetSource(usage.Source).
+		SetBaseAmount(usage.BaseAmount).
+		SetNillableRedeemCodeID(usage.RedeemCodeID).
+		SetNillableAppliedAt(usage.AppliedAt).
 		SetUsedAt(usage.UsedAt).
 		Save(ctx)
 	if err != nil {
@@ -167,12 +201,36 @@ func (r *promoCodeRepository) CreateUsage(ctx context.Context, usage *service.Pr
 	return nil
 }
 
-func (r *promoCodeRepository) GetUsageByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (*service.PromoCodeUsage, error) {
-	m, err := r.client.PromoCodeUsage.Query().
+func (r *promoCodeRepository) UpdateUsage(ctx context.Context, usage *service.PromoCodeUsage) error {
+	client := clientFromContext(ctx, r.client)
+	_, err := client.PromoCodeUsage.UpdateOneID(usage.ID).
+		SetBonusAmount(usage.BonusAmount).
+		SetStatus(usage.Status).
+		SetBaseAmount(usage.BaseAmount).
+		SetNillableRedeemCodeID(usage.RedeemCodeID).
+		SetNillableAppliedAt(usage.AppliedAt).
+		Save(ctx)
+	return err
+}
+
+func (r *promoCodeRepository) CountUsagesByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (int, error) {
+	client := clientFromContext(ctx, r.client)
+	return client.PromoCodeUsage.Query().
 		Where(
 			promocodeusage.PromoCodeIDEQ(promoCodeID),
 			promocodeusage.UserIDEQ(userID),
 		).
+		Count(ctx)
+}
+
+func (r *promoCodeRepository) GetPendingUsageForUpdate(ctx context.Context, userID int64) (*service.PromoCodeUsage, error) {
+	client := clientFromContext(ctx, r.client)
+	m, err := client.PromoCodeUsage.Query().
+		Where(
+			promocodeusage.UserIDEQ(userID),
+			promocodeusage.StatusEQ(service.PromoUsageStatusPending),
+		).
+		ForUpdate().
 		Only(ctx)
 	if err != nil {
 		if dbent.IsNotFound(err) {
@@ -207,6 +265,34 @@ func (r *promoCodeRepository) ListUsagesByPromoCode(ctx context.Context, promoCo
 	return outUsages, paginationResultFromTotal(int64(total), params), nil
 }
 
+func (r *promoCodeRepository) ListUsagesByUser(ctx context.Context, userID int64, status string, params pagination.PaginationParams) ([]service.PromoCodeUsage, *pagination.PaginationResult, error) {
+	q := r.client.PromoCodeUsage.Query().
+		Where(promocodeusage.UserIDEQ(userID))
+
+	if status != "" {
+		q = q.Where(promocodeusage.StatusEQ(status))
+	}
+
+	total, err := q.Count(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usages, err := q.
+		WithPromoCode().
+		Offset(params.Offset()).
+		Limit(params.Limit()).
+		Order(dbent.Desc(promocodeusage.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outUsages := promoCodeUsageEntitiesToService(usages)
+
+	return outUsages, paginationResultFromTotal(int64(total), params), nil
+}
+
 func (r *promoCodeRepository) IncrementUsedCount(ctx context.Context, id int64) error {
 	client := clientFromContext(ctx, r.client)
 	_, err := client.PromoCode.UpdateOneID(id).
@@ -232,6 +318,16 @@ func promoCodeEntityToService(m *dbent.PromoCode) *service.PromoCode {
 		Notes:       derefString(m.Notes),
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
+
+		BonusType:       m.BonusType,
+		BonusPercent:    m.BonusPercent,
+		MaxBonusAmount:  m.MaxBonusAmount,
+		PerUserLimit:    m.PerUserLimit,
+		Scope:           m.Scope,
+		NewUsersOnly:    m.NewUsersOnly,
+		NewUserDays:     m.NewUserDays,
+		RequiredGroupID: m.RequiredGroupID,
+		StartsAt:        m.StartsAt,
 	}
 }
 
@@ -255,10 +351,22 @@ func promoCodeUsageEntityToService(m *dbent.PromoCodeUsage) *service.PromoCodeUs
 		UserID:      m.UserID,
 		BonusAmount: m.BonusAmount,
 		UsedAt:      m.UsedAt,
+
+		BonusType:      m.BonusType,
+		BonusPercent:   m.BonusPercent,
+		MaxBonusAmount: m.MaxBonusAmount,
+		Status:         m.Status,
+		Source:         m.Source,
+		BaseAmount:     m.BaseAmount,
+		RedeemCodeID:   m.RedeemCodeID,
+		AppliedAt:      m.AppliedAt,
 	}
 	if m.Edges.User != nil {
 		out.User = userEntityToService(m.Edges.User)
 	}
+	if m.Edges.PromoCode != nil {
+		out.PromoCode = promoCodeEntityToService(m.Edges.PromoCode)
+	}
 	return out
 }
 
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitBudgetKeyPrefix = "ratelimit_budget:account:"
+
+func rateLimitBudgetKey(accountID int64) string {
+	return fmt.Sprintf("%s%d", rateLimitBudgetKeyPrefix, accountID)
+}
+
+type rateLimitBudgetCache struct {
+	rdb *redis.Client
+}
+
+// NewRateLimitBudgetCache 创建账号上游限流余量缓存实例
+func NewRateLimitBudgetCache(rdb *redis.Client) service.RateLimitBudgetCache {
+	return &rateLimitBudgetCache{rdb: rdb}
+}
+
+// SetRateLimitBudget 覆盖写入账号余量（以最新响应为准）
+func (c *rateLimitBudgetCache) SetRateLimitBudget(ctx context.Context, accountID int64, budget *service.RateLimitBudget, ttl time.Duration) error {
+	if budget == nil {
+		return nil
+	}
+	payload, err := json.Marshal(budget)
+	if err != nil {
+		return fmt.Errorf("marshal budget: %w", err)
+	}
+	return c.rdb.Set(ctx, rateLimitBudgetKey(accountID), payload, ttl).Err()
+}
+
+// GetRateLimitBudgets 批量获取账号余量
+func (c *rateLimitBudgetCache) GetRateLimitBudgets(ctx context.Context, accountIDs []int64) (map[int64]*service.RateLimitBudget, error) {
+	results := make(map[int64]*service.RateLimitBudget)
+	if len(accountIDs) == 0 {
+		return results, nil
+	}
+
+	keys := make([]string, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		keys = append(keys, rateLimitBudgetKey(id))
+	}
+
+	values, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return results, err
+	}
+
+	for i, raw := range values {
+		if raw == nil {
+			continue
+		}
+		var payload []byte
+		switch v := raw.(type) {
+		case string:
+			payload = []byte(v)
+		case []byte:
+			payload = v
+		default:
+			continue
+		}
+		var budget service.RateLimitBudget
+		if err := json.Unmarshal(payload, &budget); err != nil {
+			continue
+		}
+		results[accountIDs[i]] = &budget
+	}
+
+	return results, nil
+}
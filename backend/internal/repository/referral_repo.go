@@ -0,0 +1,454 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+type referralRepository struct {
+	db *sql.DB
+}
+
+func NewReferralRepository(db *sql.DB) service.ReferralRepository {
+	return &referralRepository{db: db}
+}
+
+const referralColumns = `
+  r.id, r.referrer_user_id, r.referee_user_id, r.code, r.status, r.flags,
+  COALESCE(r.signup_ip, ''), COALESCE(r.email_domain, ''),
+  r.signup_bonus_paid, r.referee_spend, r.referee_funded_spend, r.reward_total, r.settled_until,
+  r.reviewed_by, r.reviewed_at, COALESCE(r.review_note, ''),
+  r.created_at, r.updated_at,
+  COALESCE(ru.email, ''), COALESCE(eu.email, '')
+FROM user_referrals r
+LEFT JOIN users ru ON ru.id = r.referrer_user_id
+LEFT JOIN users eu ON eu.id = r.referee_user_id`
+
+func (r *referralRepository) GetCodeByUserID(ctx context.Context, userID int64) (string, error) {
+	var code string
+	err := r.db.QueryRowContext(ctx, "SELECT code FROM user_referral_codes WHERE user_id = $1", userID).Scan(&code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", service.ErrReferralCodeNotFound
+	}
+	return code, err
+}
+
+func (r *referralRepository) CreateCode(ctx context.Context, userID int64, code string) (string, error) {
+	// 并发生成时以先写入的为准
+	q := `
+INSERT INTO user_referral_codes (user_id, code) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING code`
+	var saved string
+	if err := r.db.QueryRowContext(ctx, q, userID, code).Scan(&saved); err != nil {
+		if isUniqueConstraintViolation(err) {
+			return "", service.ErrReferralCodeConflict
+		}
+		return "", err
+	}
+	return saved, nil
+}
+
+func (r *referralRepository) GetUserIDByCode(ctx context.Context, code string) (int64, error) {
+	var userID int64
+	err := r.db.QueryRowContext(ctx, "SELECT user_id FROM user_referral_codes WHERE code = $1", code).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, service.ErrReferralCodeNotFound
+	}
+	return userID, err
+}
+
+func (r *referralRepository) CreateReferral(ctx context.Context, referral *service.Referral) error {
+	q := `
+INSERT INTO user_referrals (
+  referrer_user_id, referee_user_id, code, status, flags, signup_ip, email_domain, settled_until
+) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8)
+RETURNING id, created_at, updated_at`
+	flags := referral.Flags
+	if flags == nil {
+		flags = []string{}
+	}
+	err := r.db.QueryRowContext(ctx, q,
+		referral.ReferrerID,
+		referral.RefereeID,
+		referral.Code,
+		referral.Status,
+		pq.Array(flags),
+		referral.SignupIP,
+		referral.EmailDomain,
+		referral.SettledUntil,
+	).Scan(&referral.ID, &referral.CreatedAt, &referral.UpdatedAt)
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return service.ErrReferralExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *referralRepository) GetReferralByID(ctx context.Context, id int64) (*service.Referral, error) {
+	referral, err := scanReferral(r.db.QueryRowContext(ctx, "SELECT"+referralColumns+"\nWHERE r.id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrReferralNotFound
+	}
+	return referral, err
+}
+
+func (r *referralRepository) GetReferralByReferee(ctx context.Context, refereeID int64) (*service.Referral, error) {
+	referral, err := scanReferral(r.db.QueryRowContext(ctx, "SELECT"+referralColumns+"\nWHERE r.referee_user_id = $1", refereeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return referral, err
+}
+
+func (r *referralRepository) ListReferrals(ctx context.Context, params pagination.PaginationParams, filter service.ReferralFilter) ([]service.Referral, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 4)
+	args := make([]any, 0, 6)
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("r.status = $%d", len(args)))
+	}
+	if filter.Flagged {
+		conditions = append(conditions, "cardinality(r.flags) > 0")
+	}
+	if filter.ReferrerID > 0 {
+		args = append(args, filter.ReferrerID)
+		conditions = append(conditions, fmt.Sprintf("r.referrer_user_id = $%d", len(args)))
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		args = append(args, "%"+search+"%")
+		conditions = append(conditions, fmt.Sprintf("(ru.email ILIKE $%d OR eu.email ILIKE $%d OR r.signup_ip ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	countQ := `SELECT COUNT(*) FROM user_referrals r
+LEFT JOIN users ru ON ru.id = r.referrer_user_id
+LEFT JOIN users eu ON eu.id = r.referee_user_id ` + where
+	if err := r.db.QueryRowContext(ctx, countQ, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + referralColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY r.created_at DESC, r.id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.Referral, 0, params.Limit())
+	for rows.Next() {
+		referral, err := scanReferral(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *referral)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *referralRepository) UpdateReferralStatus(ctx context.Context, id int64, status string, reviewedBy int64, note string) error {
+	q := `
+UPDATE user_referrals
+SET status = $2, reviewed_by = $3, reviewed_at = NOW(), review_note = NULLIF($4, ''), updated_at = NOW()
+WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, q, id, status, reviewedBy, note)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return service.ErrReferralNotFound
+	}
+	return nil
+}
+
+func (r *referralRepository) ListRewards(ctx context.Context, referrerID int64, params pagination.PaginationParams) ([]service.ReferralReward, *pagination.PaginationResult, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM referral_rewards WHERE referrer_user_id = $1", referrerID).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := `
+SELECT w.id, w.referral_id, w.referrer_user_id, w.referee_user_id, w.kind, w.amount, w.base_amount, w.created_at,
+  COALESCE(u.email, '')
+FROM referral_rewards w
+LEFT JOIN users u ON u.id = w.referee_user_id
+WHERE w.referrer_user_id = $1
+ORDER BY w.created_at DESC, w.id DESC
+LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, q, referrerID, params.Limit(), params.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralReward, 0, params.Limit())
+	for rows.Next() {
+		var item service.ReferralReward
+		if err := rows.Scan(
+			&item.ID,
+			&item.ReferralID,
+			&item.ReferrerID,
+			&item.RefereeID,
+			&item.Kind,
+			&item.Amount,
+			&item.BaseAmount,
+			&item.CreatedAt,
+			&item.RefereeEmail,
+		); err != nil {
+			return nil, nil, err
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *referralRepository) GetReferrerStats(ctx context.Context, referrerID int64) (*service.ReferralStats, error) {
+	stats := &service.ReferralStats{}
+	q := `
+SELECT COUNT(*), COUNT(*) FILTER (WHERE status = 'active')
+FROM user_referrals
+WHERE referrer_user_id = $1`
+	if err := r.db.QueryRowContext(ctx, q, referrerID).Scan(&stats.TotalReferrals, &stats.ActiveReferrals); err != nil {
+		return nil, err
+	}
+	q = `
+SELECT
+  COALESCE(SUM(amount), 0),
+  COALESCE(SUM(amount) FILTER (WHERE kind = 'signup_bonus'), 0),
+  COALESCE(SUM(amount) FILTER (WHERE kind = 'commission'), 0)
+FROM referral_rewards
+WHERE referrer_user_id = $1`
+	if err := r.db.QueryRowContext(ctx, q, referrerID).Scan(&stats.TotalRewards, &stats.SignupBonusTotal, &stats.CommissionTotal); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *referralRepository) UserUsedIPSince(ctx context.Context, userID int64, ip string, since time.Time) (bool, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM usage_logs WHERE user_id = $1 AND ip_address = $2 AND created_at >= $3)`
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, q, userID, ip, since).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *referralRepository) CountReferralsBySignupIP(ctx context.Context, referrerID int64, ip string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_referrals WHERE referrer_user_id = $1 AND signup_ip = $2", referrerID, ip).Scan(&n)
+	return n, err
+}
+
+func (r *referralRepository) ListSettleCandidateIDs(ctx context.Context, upTo time.Time, bonusMinSpend float64, afterID int64, limit int) ([]int64, error) {
+	q := `
+SELECT r.id
+FROM user_referrals r
+WHERE r.status = 'active'
+  AND r.id > $3
+  AND r.settled_until < $1
+  AND (
+    (NOT r.signup_bonus_paid AND r.referee_funded_spend >= $2)
+    OR EXISTS (
+      SELECT 1 FROM usage_logs l
+      WHERE l.user_id = r.referee_user_id
+        AND l.billing_type = 0
+        AND l.created_at > r.settled_until AND l.created_at <= $1
+    )
+  )
+ORDER BY r.id
+LIMIT $4`
+	rows, err := r.db.QueryContext(ctx, q, upTo, bonusMinSpend, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (r *referralRepository) Settle(ctx context.Context, referralID int64, upTo time.Time, settings *service.ReferralSettings) (*service.ReferralSettlement, *service.Referral, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	referral := &service.Referral{ID: referralID}
+	err = tx.QueryRowContext(ctx, `
+SELECT referrer_user_id, referee_user_id, status, signup_bonus_paid, referee_spend, referee_funded_spend, reward_total, settled_until, created_at
+FROM user_referrals
+WHERE id = $1
+FOR UPDATE`, referralID).Scan(
+		&referral.ReferrerID,
+		&referral.RefereeID,
+		&referral.Status,
+		&referral.SignupBonusPaid,
+		&referral.RefereeSpend,
+		&referral.RefereeFundedSpend,
+		&referral.RewardTotal,
+		&referral.SettledUntil,
+		&referral.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, service.ErrReferralNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if referral.Status != service.ReferralStatusActive || !referral.SettledUntil.Before(upTo) {
+		return &service.ReferralSettlement{}, referral, nil
+	}
+
+	// 返佣有效期外的消费只计入累计消费（用于注册奖励门槛），不参与返佣
+	commissionUntil := settings.CommissionUntil(referral)
+	if commissionUntil.IsZero() || commissionUntil.After(upTo) {
+		commissionUntil = upTo
+	}
+	state := &service.ReferralSettlementState{Referral: referral}
+	err = tx.QueryRowContext(ctx, `
+SELECT
+  COALESCE(SUM(actual_cost), 0),
+  COALESCE(SUM(actual_cost) FILTER (WHERE created_at <= $4), 0)
+FROM usage_logs
+WHERE user_id = $1 AND billing_type = 0 AND created_at > $2 AND created_at <= $3`,
+		referral.RefereeID, referral.SettledUntil, upTo, commissionUntil,
+	).Scan(&state.PeriodSpend, &state.CommissionableSpend)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sum referee spend: %w", err)
+	}
+
+	// 充值到账额按未退款比例折算（refunded_amount 与 pay_amount 同为支付币种），不含充值赠送 bonus_amount；
+	// 赠送余额（注册赠送、优惠码、兑换码、邀请奖励、管理员调整）不产生支付订单，自然不计入
+	err = tx.QueryRowContext(ctx, `
+SELECT COALESCE(SUM(amount * GREATEST(pay_amount - refunded_amount, 0) / pay_amount), 0)
+FROM payment_orders
+WHERE user_id = $1 AND pay_amount > 0 AND paid_at IS NOT NULL AND paid_at <= $2`,
+		referral.RefereeID, upTo,
+	).Scan(&state.TopUpCredit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sum referee top-ups: %w", err)
+	}
+
+	// 锁定邀请人，保证邀请人累计奖励上限在并发结算下仍然准确
+	var referrerExists bool
+	err = tx.QueryRowContext(ctx, "SELECT TRUE FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", referral.ReferrerID).Scan(&referrerExists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("lock referrer: %w", err)
+	}
+
+	settlement := &service.ReferralSettlement{}
+	if referrerExists {
+		if err := tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(amount), 0) FROM referral_rewards WHERE referrer_user_id = $1", referral.ReferrerID).Scan(&state.ReferrerRewardTotal); err != nil {
+			return nil, nil, fmt.Errorf("sum referrer rewards: %w", err)
+		}
+		settlement = settings.Decide(state)
+	}
+
+	for _, grant := range settlement.Grants {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO referral_rewards (referral_id, referrer_user_id, referee_user_id, kind, amount, base_amount)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+			referral.ID, referral.ReferrerID, referral.RefereeID, grant.Kind, grant.Amount, grant.BaseAmount,
+		); err != nil {
+			return nil, nil, fmt.Errorf("insert referral reward: %w", err)
+		}
+	}
+	total := settlement.Total()
+	if total > 0 {
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET balance = balance + $2, updated_at = NOW() WHERE id = $1", referral.ReferrerID, total); err != nil {
+			return nil, nil, fmt.Errorf("credit referrer balance: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+UPDATE user_referrals
+SET settled_until = $2,
+    referee_spend = referee_spend + $3,
+    referee_funded_spend = referee_funded_spend + $6,
+    reward_total = reward_total + $4,
+    signup_bonus_paid = signup_bonus_paid OR $5,
+    updated_at = NOW()
+WHERE id = $1`,
+		referral.ID, upTo, state.PeriodSpend, total, settlement.BonusSettled, settlement.FundedSpend,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("advance referral cursor: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return settlement, referral, nil
+}
+
+func scanReferral(row interface{ Scan(dest ...any) error }) (*service.Referral, error) {
+	var (
+		referral   service.Referral
+		flags      pq.StringArray
+		reviewedBy sql.NullInt64
+		reviewedAt sql.NullTime
+	)
+	if err := row.Scan(
+		&referral.ID,
+		&referral.ReferrerID,
+		&referral.RefereeID,
+		&referral.Code,
+		&referral.Status,
+		&flags,
+		&referral.SignupIP,
+		&referral.EmailDomain,
+		&referral.SignupBonusPaid,
+		&referral.RefereeSpend,
+		&referral.RefereeFundedSpend,
+		&referral.RewardTotal,
+		&referral.SettledUntil,
+		&reviewedBy,
+		&reviewedAt,
+		&referral.ReviewNote,
+		&referral.CreatedAt,
+		&referral.UpdatedAt,
+		&referral.ReferrerEmail,
+		&referral.RefereeEmail,
+	); err != nil {
+		return nil, err
+	}
+	referral.Flags = []string(flags)
+	if referral.Flags == nil {
+		referral.Flags = []string{}
+	}
+	if reviewedBy.Valid {
+		v := reviewedBy.Int64
+		referral.ReviewedBy = &v
+	}
+	if reviewedAt.Valid {
+		t := reviewedAt.Time
+		referral.ReviewedAt = &t
+	}
+	return &referral, nil
+}
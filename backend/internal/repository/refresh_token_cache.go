@@ -14,6 +14,8 @@ const (
 	refreshTokenKeyPrefix   = "refresh_token:"
 	userRefreshTokensPrefix = "user_refresh_tokens:"
 	tokenFamilyPrefix       = "token_family:"
+	revokedSessionPrefix    = "revoked_session:"
+	userKnownDevicesPrefix  = "user_known_devices:"
 )
 
 // refreshTokenKey generates the Redis key for a refresh token.
@@ -156,3 +158,38 @@ func (c *refreshTokenCache) IsTokenInFamily(ctx context.Context, familyID string
 	key := tokenFamilyKey(familyID)
 	return c.rdb.SIsMember(ctx, key, tokenHash).Result()
 }
+
+func (c *refreshTokenCache) RemoveFromUserTokenSet(ctx context.Context, userID int64, tokenHashes ...string) error {
+	if len(tokenHashes) == 0 {
+		return nil
+	}
+	members := make([]any, 0, len(tokenHashes))
+	for _, hash := range tokenHashes {
+		members = append(members, hash)
+	}
+	return c.rdb.SRem(ctx, userRefreshTokensKey(userID), members...).Err()
+}
+
+func (c *refreshTokenCache) MarkSessionRevoked(ctx context.Context, familyID string, ttl time.Duration) error {
+	return c.rdb.Set(ctx, revokedSessionPrefix+familyID, "1", ttl).Err()
+}
+
+func (c *refreshTokenCache) IsSessionRevoked(ctx context.Context, familyID string) (bool, error) {
+	n, err := c.rdb.Exists(ctx, revokedSessionPrefix+familyID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (c *refreshTokenCache) RememberUserDevice(ctx context.Context, userID int64, fingerprint string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%d", userKnownDevicesPrefix, userID)
+	pipe := c.rdb.TxPipeline()
+	card := pipe.SCard(ctx, key)
+	added := pipe.SAdd(ctx, key, fingerprint)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return card.Val() > 0 && added.Val() > 0, nil
+}
@@ -274,5 +274,6 @@ func decodeCachedAccount(val any) (*service.Account, error) {
 	if err := json.Unmarshal(payload, &account); err != nil {
 		return nil, err
 	}
+	account.CacheScheduleRule()
 	return &account, nil
 }
@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+type spendGuardRepository struct {
+	db *sql.DB
+}
+
+func NewSpendGuardRepository(db *sql.DB) service.SpendGuardRepository {
+	return &spendGuardRepository{db: db}
+}
+
+const spendSuspensionColumns = `
+  s.id, s.api_key_id, s.user_id, s.scope, s.rule,
+  s.window_spend, s.baseline_spend, s.threshold_spend,
+  s.status, s.suspended_until, s.alert_event_id, s.user_notified_at,
+  s.lifted_by, s.lifted_at, COALESCE(s.lift_note, ''),
+  s.created_at, s.updated_at,
+  COALESCE(k.name, ''), COALESCE(k.status, ''), COALESCE(u.email, '')
+FROM api_key_spend_suspensions s
+LEFT JOIN api_keys k ON k.id = s.api_key_id
+LEFT JOIN users u ON u.id = s.user_id`
+
+func (r *spendGuardRepository) ListRecentSpend(ctx context.Context, start, end time.Time) ([]service.APIKeySpend, error) {
+	q := `
+SELECT api_key_id, MAX(user_id), COALESCE(SUM(actual_cost), 0)
+FROM usage_logs
+WHERE created_at >= $1 AND created_at < $2
+GROUP BY api_key_id
+HAVING SUM(actual_cost) > 0
+ORDER BY api_key_id`
+
+	rows, err := r.db.QueryContext(ctx, q, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.APIKeySpend, 0)
+	for rows.Next() {
+		var item service.APIKeySpend
+		if err := rows.Scan(&item.APIKeyID, &item.UserID, &item.Spend); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetPeakSpend 按 10 分钟分桶统计消费，取每个 Key / 用户的最大桶
+func (r *spendGuardRepository) GetPeakSpend(ctx context.Context, scope string, ids []int64, start, end time.Time) (map[int64]float64, error) {
+	out := make(map[int64]float64, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	var column string
+	switch scope {
+	case service.SpendGuardScopeAPIKey:
+		column = "api_key_id"
+	case service.SpendGuardScopeUser:
+		column = "user_id"
+	default:
+		return nil, fmt.Errorf("invalid spend guard scope: %s", scope)
+	}
+
+	q := fmt.Sprintf(`
+SELECT id, MAX(spend)
+FROM (
+  SELECT %[1]s AS id, SUM(actual_cost) AS spend
+  FROM usage_logs
+  WHERE %[1]s = ANY($1) AND created_at >= $2 AND created_at < $3
+  GROUP BY %[1]s, floor(extract(epoch FROM created_at) / 600)
+) buckets
+GROUP BY id`, column)
+
+	rows, err := r.db.QueryContext(ctx, q, pq.Array(ids), start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			id   int64
+			peak float64
+		)
+		if err := rows.Scan(&id, &peak); err != nil {
+			return nil, err
+		}
+		out[id] = peak
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *spendGuardRepository) CreateSuspension(ctx context.Context, suspension *service.SpendSuspension) (*service.SpendSuspension, error) {
+	if suspension == nil {
+		return nil, fmt.Errorf("nil suspension")
+	}
+	q := `
+INSERT INTO api_key_spend_suspensions (
+  api_key_id, user_id, scope, rule,
+  window_spend, baseline_spend, threshold_spend,
+  status, suspended_until
+) VALUES ($1,$2,$3,$4,$5,$6,$7,'active',$8)
+ON CONFLICT (api_key_id) WHERE status = 'active' DO NOTHING
+RETURNING id`
+
+	var id int64
+	err := r.db.QueryRowContext(
+		ctx,
+		q,
+		suspension.APIKeyID,
+		suspension.UserID,
+		suspension.Scope,
+		suspension.Rule,
+		suspension.WindowSpend,
+		suspension.BaselineSpend,
+		suspension.ThresholdSpend,
+		suspension.SuspendedUntil,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.GetSuspensionByID(ctx, id)
+}
+
+func (r *spendGuardRepository) GetSuspensionByID(ctx context.Context, id int64) (*service.SpendSuspension, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+spendSuspensionColumns+"\nWHERE s.id = $1", id)
+	suspension, err := scanSpendSuspension(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrSpendSuspensionNotFound
+		}
+		return nil, err
+	}
+	return suspension, nil
+}
+
+func (r *spendGuardRepository) ListSuspensions(ctx context.Context, params pagination.PaginationParams, filter service.SpendSuspensionFilter) ([]service.SpendSuspension, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]any, 0, 4)
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("s.status = $%d", len(args)))
+	}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("s.user_id = $%d", len(args)))
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM api_key_spend_suspensions s "+where, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + spendSuspensionColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY s.created_at DESC, s.id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SpendSuspension, 0, params.Limit())
+	for rows.Next() {
+		suspension, err := scanSpendSuspension(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *suspension)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *spendGuardRepository) ListExpiredSuspensions(ctx context.Context, now time.Time) ([]service.SpendSuspension, error) {
+	q := "SELECT" + spendSuspensionColumns + `
+WHERE s.status = 'active' AND s.suspended_until IS NOT NULL AND s.suspended_until <= $1
+ORDER BY s.suspended_until
+LIMIT 500`
+
+	rows, err := r.db.QueryContext(ctx, q, now)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SpendSuspension, 0)
+	for rows.Next() {
+		suspension, err := scanSpendSuspension(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *suspension)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *spendGuardRepository) CloseSuspension(ctx context.Context, id int64, status string, liftedBy *int64, note string) error {
+	q := `
+UPDATE api_key_spend_suspensions
+SET status = $2,
+    lifted_by = $3,
+    lifted_at = CASE WHEN $2 = 'lifted' THEN NOW() ELSE lifted_at END,
+    lift_note = $4,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'active'`
+	res, err := r.db.ExecContext(ctx, q, id, status, opsNullInt64(liftedBy), opsNullString(note))
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrSpendSuspensionClosed
+	}
+	return nil
+}
+
+func (r *spendGuardRepository) SetAlertEventID(ctx context.Context, id, eventID int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_key_spend_suspensions SET alert_event_id = $2, updated_at = NOW() WHERE id = $1`, id, eventID)
+	return err
+}
+
+func (r *spendGuardRepository) MarkUserNotified(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_key_spend_suspensions SET user_notified_at = $2, updated_at = NOW() WHERE id = $1`, id, at)
+	return err
+}
+
+// HasOtherSuspensionHold 共享检测对该 Key 的停用处置仍在生效时返回 true
+func (r *spendGuardRepository) HasOtherSuspensionHold(ctx context.Context, apiKeyID int64) (bool, error) {
+	q := `
+SELECT EXISTS (
+  SELECT 1 FROM api_key_abuse_flags
+  WHERE api_key_id = $1 AND status = 'open' AND action = 'suspended'
+)`
+	var hold bool
+	if err := r.db.QueryRowContext(ctx, q, apiKeyID).Scan(&hold); err != nil {
+		return false, err
+	}
+	return hold, nil
+}
+
+func scanSpendSuspension(row interface{ Scan(dest ...any) error }) (*service.SpendSuspension, error) {
+	var (
+		suspension     service.SpendSuspension
+		suspendedUntil sql.NullTime
+		alertEventID   sql.NullInt64
+		userNotifiedAt sql.NullTime
+		liftedBy       sql.NullInt64
+		liftedAt       sql.NullTime
+	)
+	if err := row.Scan(
+		&suspension.ID,
+		&suspension.APIKeyID,
+		&suspension.UserID,
+		&suspension.Scope,
+		&suspension.Rule,
+		&suspension.WindowSpend,
+		&suspension.BaselineSpend,
+		&suspension.ThresholdSpend,
+		&suspension.Status,
+		&suspendedUntil,
+		&alertEventID,
+		&userNotifiedAt,
+		&liftedBy,
+		&liftedAt,
+		&suspension.LiftNote,
+		&suspension.CreatedAt,
+		&suspension.UpdatedAt,
+		&suspension.APIKeyName,
+		&suspension.APIKeyStatus,
+		&suspension.UserEmail,
+	); err != nil {
+		return nil, err
+	}
+	if suspendedUntil.Valid {
+		t := suspendedUntil.Time
+		suspension.SuspendedUntil = &t
+	}
+	if alertEventID.Valid {
+		v := alertEventID.Int64
+		suspension.AlertEventID = &v
+	}
+	if userNotifiedAt.Valid {
+		t := userNotifiedAt.Time
+		suspension.UserNotifiedAt = &t
+	}
+	if liftedBy.Valid {
+		v := liftedBy.Int64
+		suspension.LiftedBy = &v
+	}
+	if liftedAt.Valid {
+		t := liftedAt.Time
+		suspension.LiftedAt = &t
+	}
+	return &suspension, nil
+}
@@ -0,0 +1,561 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type subscriptionPlanRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionPlanRepository(db *sql.DB) service.SubscriptionPlanRepository {
+	return &subscriptionPlanRepository{db: db}
+}
+
+const subscriptionPlanColumns = `
+  p.id, p.group_id, p.name, p.description, p.price, p.validity_days, p.renewal_policy, p.status, p.sort_order,
+  p.created_at, p.updated_at, COALESCE(g.name, '')
+FROM subscription_plans p
+LEFT JOIN groups g ON g.id = p.group_id`
+
+const subscriptionOrderColumns = `
+  o.id, o.user_id, o.plan_id, o.plan_name, o.group_id, o.subscription_id, o.kind, o.from_group_id,
+  o.price, o.credit, o.amount, o.validity_days, o.expires_at, o.created_at,
+  COALESCE(u.email, ''), COALESCE(g.name, ''), COALESCE(fg.name, '')
+FROM subscription_orders o
+LEFT JOIN users u ON u.id = o.user_id
+LEFT JOIN groups g ON g.id = o.group_id
+LEFT JOIN groups fg ON fg.id = o.from_group_id`
+
+func (r *subscriptionPlanRepository) CreatePlan(ctx context.Context, plan *service.SubscriptionPlan) error {
+	q := `
+INSERT INTO subscription_plans (group_id, name, description, price, validity_days, renewal_policy, status, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, q,
+		plan.GroupID, plan.Name, plan.Description, plan.Price, plan.ValidityDays, plan.RenewalPolicy, plan.Status, plan.SortOrder,
+	).Scan(&plan.ID, &plan.CreatedAt, &plan.UpdatedAt)
+}
+
+func (r *subscriptionPlanRepository) UpdatePlan(ctx context.Context, plan *service.SubscriptionPlan) error {
+	q := `
+UPDATE subscription_plans
+SET group_id = $2, name = $3, description = $4, price = $5, validity_days = $6,
+    renewal_policy = $7, status = $8, sort_order = $9, updated_at = NOW()
+WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, q,
+		plan.ID, plan.GroupID, plan.Name, plan.Description, plan.Price, plan.ValidityDays, plan.RenewalPolicy, plan.Status, plan.SortOrder,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return service.ErrSubscriptionPlanNotFound
+	}
+	return nil
+}
+
+func (r *subscriptionPlanRepository) DeletePlan(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM subscription_plans WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return service.ErrSubscriptionPlanNotFound
+	}
+	return nil
+}
+
+func (r *subscriptionPlanRepository) GetPlan(ctx context.Context, id int64) (*service.SubscriptionPlan, error) {
+	plan, err := scanSubscriptionPlan(r.db.QueryRowContext(ctx, "SELECT"+subscriptionPlanColumns+"\nWHERE p.id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrSubscriptionPlanNotFound
+	}
+	return plan, err
+}
+
+func (r *subscriptionPlanRepository) ListPlans(ctx context.Context, activeOnly bool) ([]service.SubscriptionPlan, error) {
+	where := ""
+	if activeOnly {
+		where = "\nWHERE p.status = 'active' AND g.deleted_at IS NULL AND g.status = 'active' AND g.subscription_type = 'subscription'"
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT"+subscriptionPlanColumns+where+"\nORDER BY p.sort_order ASC, p.id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SubscriptionPlan, 0)
+	for rows.Next() {
+		plan, err := scanSubscriptionPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *plan)
+	}
+	return out, rows.Err()
+}
+
+func (r *subscriptionPlanRepository) Purchase(ctx context.Context, input *service.SubscriptionPurchaseInput) (*service.SubscriptionPurchaseResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 先锁用户，串行化同一用户的所有购买，保证余额校验与订阅唯一性
+	state := &service.SubscriptionPurchaseState{Intent: input.Intent}
+	err = tx.QueryRowContext(ctx, "SELECT balance FROM users WHERE id = $1 AND deleted_at IS NULL AND status = 'active' FOR UPDATE", input.UserID).Scan(&state.Balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock user: %w", err)
+	}
+
+	state.Plan, err = scanSubscriptionPlan(tx.QueryRowContext(ctx, "SELECT"+subscriptionPlanColumns+"\nWHERE p.id = $1", input.PlanID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrSubscriptionPlanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get plan: %w", err)
+	}
+	plan := state.Plan
+
+	if state.Current, err = lockUserSubscription(ctx, tx, input.UserID, plan.GroupID); err != nil {
+		return nil, err
+	}
+	if input.Intent == service.SubscriptionOrderKindChange && input.FromGroupID > 0 && input.FromGroupID != plan.GroupID {
+		if state.From, err = lockUserSubscription(ctx, tx, input.UserID, input.FromGroupID); err != nil {
+			return nil, err
+		}
+		if state.From != nil {
+			if state.FromOrders, err = listSubscriptionOrders(ctx, tx, state.From.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	state.Now = time.Now()
+
+	decision, err := service.DecideSubscriptionPurchase(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if decision.Amount > 0 {
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET balance = balance - $2, updated_at = NOW() WHERE id = $1", input.UserID, decision.Amount); err != nil {
+			return nil, fmt.Errorf("update balance: %w", err)
+		}
+	}
+
+	note := service.SubscriptionOrderNote(decision.Kind, plan.Name)
+	var subscriptionID int64
+	if current := state.Current; current != nil {
+		subscriptionID = current.ID
+		startsAt := current.StartsAt
+		if !decision.Extend {
+			startsAt = decision.StartsAt
+		}
+		_, err = tx.ExecContext(ctx, `
+UPDATE user_subscriptions
+SET starts_at = $2, expires_at = $3, status = 'active',
+    notes = CASE WHEN COALESCE(notes, '') = '' THEN $4 ELSE notes || E'\n' || $4 END,
+    updated_at = NOW()
+WHERE id = $1`, current.ID, startsAt, decision.ExpiresAt, note)
+		if err != nil {
+			return nil, fmt.Errorf("update subscription: %w", err)
+		}
+	} else {
+		err = tx.QueryRowContext(ctx, `
+INSERT INTO user_subscriptions (user_id, group_id, starts_at, expires_at, status, assigned_at, notes, created_at, updated_at)
+VALUES ($1, $2, $3, $4, 'active', NOW(), $5, NOW(), NOW())
+RETURNING id`, input.UserID, plan.GroupID, decision.StartsAt, decision.ExpiresAt, note).Scan(&subscriptionID)
+		if err != nil {
+			if isUniqueConstraintViolation(err) {
+				return nil, service.ErrSubscriptionAlreadyExists
+			}
+			return nil, fmt.Errorf("create subscription: %w", err)
+		}
+	}
+
+	order := &service.SubscriptionOrder{
+		UserID:         input.UserID,
+		PlanID:         &plan.ID,
+		PlanName:       plan.Name,
+		GroupID:        plan.GroupID,
+		SubscriptionID: subscriptionID,
+		Kind:           decision.Kind,
+		Price:          plan.Price,
+		Credit:         decision.Credit,
+		Amount:         decision.Amount,
+		ValidityDays:   plan.ValidityDays,
+		ExpiresAt:      decision.ExpiresAt,
+		GroupName:      plan.GroupName,
+	}
+	if decision.Kind == service.SubscriptionOrderKindChange {
+		from := state.From
+		order.FromGroupID = &from.GroupID
+		_, err = tx.ExecContext(ctx, `
+UPDATE user_subscriptions
+SET status = 'expired', expires_at = $2,
+    notes = CASE WHEN COALESCE(notes, '') = '' THEN $3 ELSE notes || E'\n' || $3 END,
+    updated_at = NOW()
+WHERE id = $1`, from.ID, state.Now, note)
+		if err != nil {
+			return nil, fmt.Errorf("end previous subscription: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE subscription_renewals SET auto_renew = FALSE, updated_at = NOW() WHERE user_id = $1 AND group_id = $2", input.UserID, from.GroupID); err != nil {
+			return nil, fmt.Errorf("disable previous auto-renew: %w", err)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+INSERT INTO subscription_orders (
+  user_id, plan_id, plan_name, group_id, subscription_id, kind, from_group_id,
+  price, credit, amount, validity_days, expires_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, created_at`,
+		order.UserID, order.PlanID, order.PlanName, order.GroupID, order.SubscriptionID, order.Kind, order.FromGroupID,
+		order.Price, order.Credit, order.Amount, order.ValidityDays, order.ExpiresAt,
+	).Scan(&order.ID, &order.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert order: %w", err)
+	}
+
+	// 记录续费套餐；新套餐不支持自动续费时关闭自动续费
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO subscription_renewals (user_id, group_id, plan_id) VALUES ($1, $2, $3)
+ON CONFLICT (user_id, group_id) DO UPDATE
+SET plan_id = EXCLUDED.plan_id,
+    auto_renew = subscription_renewals.auto_renew AND $4,
+    last_error = NULL,
+    updated_at = NOW()`, input.UserID, plan.GroupID, plan.ID, plan.AllowsAutoRenew())
+	if err != nil {
+		return nil, fmt.Errorf("upsert renewal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return &service.SubscriptionPurchaseResult{
+		Order:   order,
+		Balance: state.Balance - decision.Amount,
+	}, nil
+}
+
+// lockUserSubscription 锁定用户在分组上未删除的订阅（含用量窗口与分组限额），不存在时返回 nil
+func lockUserSubscription(ctx context.Context, tx *sql.Tx, userID, groupID int64) (*service.UserSubscription, error) {
+	sub := &service.UserSubscription{UserID: userID, GroupID: groupID, Group: &service.Group{ID: groupID}}
+	var (
+		dailyStart, weeklyStart, monthlyStart sql.NullTime
+		dailyLimit, weeklyLimit, monthlyLimit sql.NullFloat64
+	)
+	err := tx.QueryRowContext(ctx, `
+SELECT s.id, s.status, s.starts_at, s.expires_at,
+       s.daily_window_start, s.weekly_window_start, s.monthly_window_start,
+       s.daily_usage_usd, s.weekly_usage_usd, s.monthly_usage_usd,
+       g.daily_limit_usd, g.weekly_limit_usd, g.monthly_limit_usd
+FROM user_subscriptions s
+LEFT JOIN groups g ON g.id = s.group_id
+WHERE s.user_id = $1 AND s.group_id = $2 AND s.deleted_at IS NULL
+FOR UPDATE OF s`, userID, groupID).Scan(
+		&sub.ID, &sub.Status, &sub.StartsAt, &sub.ExpiresAt,
+		&dailyStart, &weeklyStart, &monthlyStart,
+		&sub.DailyUsageUSD, &sub.WeeklyUsageUSD, &sub.MonthlyUsageUSD,
+		&dailyLimit, &weeklyLimit, &monthlyLimit,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock subscription: %w", err)
+	}
+	sub.DailyWindowStart = nullTimePtr(dailyStart)
+	sub.WeeklyWindowStart = nullTimePtr(weeklyStart)
+	sub.MonthlyWindowStart = nullTimePtr(monthlyStart)
+	sub.Group.DailyLimitUSD = nullFloat64Ptr(dailyLimit)
+	sub.Group.WeeklyLimitUSD = nullFloat64Ptr(weeklyLimit)
+	sub.Group.MonthlyLimitUSD = nullFloat64Ptr(monthlyLimit)
+	return sub, nil
+}
+
+func (r *subscriptionPlanRepository) ListSubscriptionOrders(ctx context.Context, subscriptionID int64) ([]service.SubscriptionOrder, error) {
+	return listSubscriptionOrders(ctx, r.db, subscriptionID)
+}
+
+// subscriptionQueryer 兼容 *sql.DB 与 *sql.Tx
+type subscriptionQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func listSubscriptionOrders(ctx context.Context, q subscriptionQueryer, subscriptionID int64) ([]service.SubscriptionOrder, error) {
+	rows, err := q.QueryContext(ctx, "SELECT"+subscriptionOrderColumns+`
+WHERE o.subscription_id = $1
+ORDER BY o.id DESC`, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("list subscription orders: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SubscriptionOrder, 0)
+	for rows.Next() {
+		order, err := scanSubscriptionOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *order)
+	}
+	return out, rows.Err()
+}
+
+func (r *subscriptionPlanRepository) ListOrders(ctx context.Context, params pagination.PaginationParams, filter service.SubscriptionOrderFilter) ([]service.SubscriptionOrder, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 4)
+	args := make([]any, 0, 6)
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("o.user_id = $%d", len(args)))
+	}
+	if filter.GroupID > 0 {
+		args = append(args, filter.GroupID)
+		conditions = append(conditions, fmt.Sprintf("(o.group_id = $%d OR o.from_group_id = $%d)", len(args), len(args)))
+	}
+	if kind := strings.TrimSpace(filter.Kind); kind != "" {
+		args = append(args, kind)
+		conditions = append(conditions, fmt.Sprintf("o.kind = $%d", len(args)))
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		args = append(args, "%"+search+"%")
+		conditions = append(conditions, fmt.Sprintf("(u.email ILIKE $%d OR o.plan_name ILIKE $%d)", len(args), len(args)))
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	countQ := `SELECT COUNT(*) FROM subscription_orders o
+LEFT JOIN users u ON u.id = o.user_id ` + where
+	if err := r.db.QueryRowContext(ctx, countQ, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + subscriptionOrderColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY o.id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SubscriptionOrder, 0, params.Limit())
+	for rows.Next() {
+		order, err := scanSubscriptionOrder(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *subscriptionPlanRepository) ListRenewals(ctx context.Context, userID int64) ([]service.SubscriptionRenewal, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT user_id, group_id, plan_id, auto_renew, last_attempt_at, COALESCE(last_error, ''), reminder_sent_for, created_at, updated_at
+FROM subscription_renewals
+WHERE user_id = $1
+ORDER BY group_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SubscriptionRenewal, 0)
+	for rows.Next() {
+		var (
+			renewal       service.SubscriptionRenewal
+			planID        sql.NullInt64
+			lastAttemptAt sql.NullTime
+			reminderFor   sql.NullTime
+		)
+		if err := rows.Scan(
+			&renewal.UserID,
+			&renewal.GroupID,
+			&planID,
+			&renewal.AutoRenew,
+			&lastAttemptAt,
+			&renewal.LastError,
+			&reminderFor,
+			&renewal.CreatedAt,
+			&renewal.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if planID.Valid {
+			renewal.PlanID = &planID.Int64
+		}
+		if lastAttemptAt.Valid {
+			renewal.LastAttemptAt = &lastAttemptAt.Time
+		}
+		if reminderFor.Valid {
+			renewal.ReminderSentFor = &reminderFor.Time
+		}
+		out = append(out, renewal)
+	}
+	return out, rows.Err()
+}
+
+func (r *subscriptionPlanRepository) SetAutoRenew(ctx context.Context, userID, groupID int64, planID *int64, enabled bool) error {
+	// 开启时清除上次失败记录，立即进入下一轮续费检查
+	q := `
+INSERT INTO subscription_renewals (user_id, group_id, plan_id, auto_renew) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, group_id) DO UPDATE
+SET plan_id = COALESCE(EXCLUDED.plan_id, subscription_renewals.plan_id),
+    auto_renew = EXCLUDED.auto_renew,
+    last_attempt_at = CASE WHEN EXCLUDED.auto_renew THEN NULL ELSE subscription_renewals.last_attempt_at END,
+    last_error = CASE WHEN EXCLUDED.auto_renew THEN NULL ELSE subscription_renewals.last_error END,
+    updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, q, userID, groupID, planID, enabled)
+	return err
+}
+
+func (r *subscriptionPlanRepository) ListAutoRenewDue(ctx context.Context, expiredAfter, dueBefore, retryBefore time.Time, limit int) ([]service.SubscriptionExpiringItem, error) {
+	q := `
+SELECT s.user_id, s.group_id, s.expires_at, rn.plan_id, COALESCE(rn.last_error, ''), u.email, COALESCE(g.name, '')
+FROM subscription_renewals rn
+JOIN user_subscriptions s ON s.user_id = rn.user_id AND s.group_id = rn.group_id AND s.deleted_at IS NULL
+JOIN users u ON u.id = rn.user_id AND u.deleted_at IS NULL AND u.status = 'active'
+LEFT JOIN groups g ON g.id = rn.group_id
+WHERE rn.auto_renew
+  AND s.status <> 'suspended'
+  AND s.expires_at > $1 AND s.expires_at < $2
+  AND (rn.last_attempt_at IS NULL OR rn.last_attempt_at < $3)
+ORDER BY s.expires_at ASC
+LIMIT $4`
+	return r.queryExpiring(ctx, q, expiredAfter, dueBefore, retryBefore, limit)
+}
+
+func (r *subscriptionPlanRepository) RecordRenewalAttempt(ctx context.Context, userID, groupID int64, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE subscription_renewals
+SET last_attempt_at = NOW(), last_error = NULLIF($3, ''), updated_at = NOW()
+WHERE user_id = $1 AND group_id = $2`, userID, groupID, errMsg)
+	return err
+}
+
+func (r *subscriptionPlanRepository) ListReminderDue(ctx context.Context, now, dueBefore time.Time, limit int) ([]service.SubscriptionExpiringItem, error) {
+	q := `
+SELECT s.user_id, s.group_id, s.expires_at, rn.plan_id, COALESCE(rn.last_error, ''), u.email, COALESCE(g.name, '')
+FROM user_subscriptions s
+JOIN users u ON u.id = s.user_id AND u.deleted_at IS NULL AND u.status = 'active'
+LEFT JOIN groups g ON g.id = s.group_id
+LEFT JOIN subscription_renewals rn ON rn.user_id = s.user_id AND rn.group_id = s.group_id
+WHERE s.deleted_at IS NULL
+  AND s.status = 'active'
+  AND s.expires_at >= $1 AND s.expires_at < $2
+  AND NOT COALESCE(rn.auto_renew, FALSE)
+  AND (rn.reminder_sent_for IS NULL OR rn.reminder_sent_for <> s.expires_at)
+  AND EXISTS (
+    SELECT 1 FROM subscription_plans p
+    WHERE p.group_id = s.group_id AND p.status = 'active' AND p.renewal_policy <> 'none'
+  )
+ORDER BY s.expires_at ASC
+LIMIT $3`
+	return r.queryExpiring(ctx, q, now, dueBefore, limit)
+}
+
+func (r *subscriptionPlanRepository) MarkReminderSent(ctx context.Context, userID, groupID int64, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO subscription_renewals (user_id, group_id, reminder_sent_for) VALUES ($1, $2, $3)
+ON CONFLICT (user_id, group_id) DO UPDATE
+SET reminder_sent_for = EXCLUDED.reminder_sent_for, updated_at = NOW()`, userID, groupID, expiresAt)
+	return err
+}
+
+func (r *subscriptionPlanRepository) queryExpiring(ctx context.Context, q string, args ...any) ([]service.SubscriptionExpiringItem, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SubscriptionExpiringItem, 0)
+	for rows.Next() {
+		var (
+			item   service.SubscriptionExpiringItem
+			planID sql.NullInt64
+		)
+		if err := rows.Scan(&item.UserID, &item.GroupID, &item.ExpiresAt, &planID, &item.LastError, &item.Email, &item.GroupName); err != nil {
+			return nil, err
+		}
+		if planID.Valid {
+			item.PlanID = &planID.Int64
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+func scanSubscriptionPlan(row interface{ Scan(dest ...any) error }) (*service.SubscriptionPlan, error) {
+	var plan service.SubscriptionPlan
+	if err := row.Scan(
+		&plan.ID,
+		&plan.GroupID,
+		&plan.Name,
+		&plan.Description,
+		&plan.Price,
+		&plan.ValidityDays,
+		&plan.RenewalPolicy,
+		&plan.Status,
+		&plan.SortOrder,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+		&plan.GroupName,
+	); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func scanSubscriptionOrder(row interface{ Scan(dest ...any) error }) (*service.SubscriptionOrder, error) {
+	var (
+		order       service.SubscriptionOrder
+		planID      sql.NullInt64
+		fromGroupID sql.NullInt64
+	)
+	if err := row.Scan(
+		&order.ID,
+		&order.UserID,
+		&planID,
+		&order.PlanName,
+		&order.GroupID,
+		&order.SubscriptionID,
+		&order.Kind,
+		&fromGroupID,
+		&order.Price,
+		&order.Credit,
+		&order.Amount,
+		&order.ValidityDays,
+		&order.ExpiresAt,
+		&order.CreatedAt,
+		&order.UserEmail,
+		&order.GroupName,
+		&order.FromGroupName,
+	); err != nil {
+		return nil, err
+	}
+	if planID.Valid {
+		order.PlanID = &planID.Int64
+	}
+	if fromGroupID.Valid {
+		order.FromGroupID = &fromGroupID.Int64
+	}
+	return &order, nil
+}
@@ -2465,6 +2465,14 @@ func nullFloat64Ptr(v sql.NullFloat64) *float64 {
 	return &out
 }
 
+func nullTimePtr(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	out := v.Time
+	return &out
+}
+
 func nullString(v *string) sql.NullString {
 	if v == nil || *v == "" {
 		return sql.NullString{}
@@ -0,0 +1,555 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+type userDataRepository struct {
+	db *sql.DB
+}
+
+func NewUserDataRepository(db *sql.DB) service.UserDataRepository {
+	return &userDataRepository{db: db}
+}
+
+// ==================== 数据导出 ====================
+
+const userDataExportColumns = `
+  id, user_id, status, file_size, COALESCE(error_message, ''),
+  started_at, completed_at, expires_at, created_at
+FROM user_data_exports`
+
+func (r *userDataRepository) CreateExport(ctx context.Context, export *service.UserDataExport) error {
+	if export == nil {
+		return fmt.Errorf("nil data export")
+	}
+	return r.db.QueryRowContext(ctx, `
+INSERT INTO user_data_exports (user_id, status)
+VALUES ($1, $2)
+RETURNING id, created_at`, export.UserID, export.Status).Scan(&export.ID, &export.CreatedAt)
+}
+
+func (r *userDataRepository) GetExport(ctx context.Context, id int64) (*service.UserDataExport, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+userDataExportColumns+"\nWHERE id = $1", id)
+	export, err := scanUserDataExport(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrUserDataExportNotFound
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *userDataRepository) GetExportArchive(ctx context.Context, id int64) ([]byte, error) {
+	var archive []byte
+	err := r.db.QueryRowContext(ctx, "SELECT archive FROM user_data_exports WHERE id = $1 AND archive IS NOT NULL", id).Scan(&archive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrUserDataExportNotFound
+		}
+		return nil, err
+	}
+	return archive, nil
+}
+
+func (r *userDataRepository) ListExportsByUser(ctx context.Context, userID int64, limit int) ([]service.UserDataExport, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT"+userDataExportColumns+"\nWHERE user_id = $1\nORDER BY created_at DESC, id DESC\nLIMIT $2", userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.UserDataExport, 0, limit)
+	for rows.Next() {
+		export, err := scanUserDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *export)
+	}
+	return out, rows.Err()
+}
+
+func (r *userDataRepository) CountExportsSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_data_exports WHERE user_id = $1 AND created_at >= $2", userID, since).Scan(&count)
+	return count, err
+}
+
+func (r *userDataRepository) HasActiveExport(ctx context.Context, userID int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+SELECT EXISTS (
+  SELECT 1 FROM user_data_exports
+  WHERE user_id = $1 AND status IN ($2, $3)
+)`, userID, service.UserDataExportStatusPending, service.UserDataExportStatusProcessing).Scan(&exists)
+	return exists, err
+}
+
+func (r *userDataRepository) ClaimNextExport(ctx context.Context, staleAfter time.Duration) (*service.UserDataExport, error) {
+	if staleAfter <= 0 {
+		staleAfter = 30 * time.Minute
+	}
+	q := `
+WITH next AS (
+  SELECT id
+  FROM user_data_exports
+  WHERE status = $1
+     OR (status = $2 AND started_at IS NOT NULL AND started_at < NOW() - ($3 * interval '1 second'))
+  ORDER BY created_at ASC
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE user_data_exports AS e
+SET status = $2, started_at = NOW(), error_message = NULL
+FROM next
+WHERE e.id = next.id
+RETURNING e.id, e.user_id, e.status, e.file_size, COALESCE(e.error_message, ''),
+  e.started_at, e.completed_at, e.expires_at, e.created_at`
+	row := r.db.QueryRowContext(
+		ctx,
+		q,
+		service.UserDataExportStatusPending,
+		service.UserDataExportStatusProcessing,
+		int64(staleAfter/time.Second),
+	)
+	export, err := scanUserDataExport(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *userDataRepository) CompleteExport(ctx context.Context, id int64, archive []byte, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE user_data_exports
+SET status = $2, archive = $3, file_size = $4, completed_at = NOW(), expires_at = $5, error_message = NULL
+WHERE id = $1`, id, service.UserDataExportStatusCompleted, archive, len(archive), expiresAt)
+	return err
+}
+
+func (r *userDataRepository) FailExport(ctx context.Context, id int64, message string) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE user_data_exports
+SET status = $2, error_message = $3, completed_at = NOW()
+WHERE id = $1`, id, service.UserDataExportStatusFailed, message)
+	return err
+}
+
+func (r *userDataRepository) DeleteExpiredExports(ctx context.Context, now time.Time) (int64, error) {
+	// 失败记录保留 7 天用于限频统计与排查
+	res, err := r.db.ExecContext(ctx, `
+DELETE FROM user_data_exports
+WHERE (expires_at IS NOT NULL AND expires_at < $1)
+   OR (status = $2 AND created_at < $1 - interval '7 days')`, now, service.UserDataExportStatusFailed)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *userDataRepository) ListUsageLogsForExport(ctx context.Context, userID, afterID int64, limit int) ([]service.UsageLog, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	q := "SELECT " + usageLogSelectColumns + " FROM usage_logs WHERE user_id = $1 AND id > $2 ORDER BY id ASC LIMIT $3"
+	rows, err := r.db.QueryContext(ctx, q, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.UsageLog, 0, limit)
+	for rows.Next() {
+		log, err := scanUsageLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *log)
+	}
+	return out, rows.Err()
+}
+
+func scanUserDataExport(row interface{ Scan(dest ...any) error }) (*service.UserDataExport, error) {
+	var (
+		export      service.UserDataExport
+		startedAt   sql.NullTime
+		completedAt sql.NullTime
+		expiresAt   sql.NullTime
+	)
+	if err := row.Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.FileSize,
+		&export.ErrorMessage,
+		&startedAt,
+		&completedAt,
+		&expiresAt,
+		&export.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if startedAt.Valid {
+		t := startedAt.Time
+		export.StartedAt = &t
+	}
+	if completedAt.Valid {
+		t := completedAt.Time
+		export.CompletedAt = &t
+	}
+	if expiresAt.Valid {
+		t := expiresAt.Time
+		export.ExpiresAt = &t
+	}
+	return &export, nil
+}
+
+// ==================== 账号注销 ====================
+
+const accountDeletionColumns = `
+  id, user_id, email, status, reason, token_hash, token_expires_at,
+  previous_status, revoked_api_key_ids, COALESCE(ip_address, ''),
+  confirmed_at, scheduled_for, cancelled_at, restored_at, restored_by, completed_at,
+  created_at, updated_at
+FROM account_deletion_requests`
+
+func (r *userDataRepository) CreateDeletionRequest(ctx context.Context, req *service.AccountDeletionRequest) error {
+	if req == nil {
+		return fmt.Errorf("nil account deletion request")
+	}
+	ids := req.RevokedAPIKeyIDs
+	if ids == nil {
+		ids = []int64{}
+	}
+	q := `
+INSERT INTO account_deletion_requests (user_id, email, status, reason, token_hash, token_expires_at, previous_status, revoked_api_key_ids, ip_address)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(
+		ctx,
+		q,
+		req.UserID,
+		req.Email,
+		req.Status,
+		req.Reason,
+		req.TokenHash,
+		req.TokenExpiresAt,
+		req.PreviousStatus,
+		pq.Array(ids),
+		opsNullString(req.IPAddress),
+	).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+}
+
+func (r *userDataRepository) GetDeletionRequest(ctx context.Context, id int64) (*service.AccountDeletionRequest, error) {
+	return r.getDeletionRequest(ctx, "id = $1", id)
+}
+
+func (r *userDataRepository) GetDeletionRequestByTokenHash(ctx context.Context, tokenHash string) (*service.AccountDeletionRequest, error) {
+	return r.getDeletionRequest(ctx, "token_hash = $1", tokenHash)
+}
+
+func (r *userDataRepository) GetOpenDeletionRequest(ctx context.Context, userID int64) (*service.AccountDeletionRequest, error) {
+	return r.getDeletionRequest(
+		ctx,
+		"user_id = $1 AND status IN ($2, $3)",
+		userID,
+		service.AccountDeletionStatusPending,
+		service.AccountDeletionStatusScheduled,
+	)
+}
+
+func (r *userDataRepository) getDeletionRequest(ctx context.Context, where string, args ...any) (*service.AccountDeletionRequest, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+accountDeletionColumns+"\nWHERE "+where+"\nORDER BY id DESC\nLIMIT 1", args...)
+	req, err := scanAccountDeletionRequest(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrAccountDeletionNotFound
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+func (r *userDataRepository) UpdateDeletionRequest(ctx context.Context, req *service.AccountDeletionRequest) error {
+	if req == nil {
+		return fmt.Errorf("nil account deletion request")
+	}
+	return updateDeletionRequest(ctx, r.db, req, "")
+}
+
+// ScheduleDeletion 在同一事务内停用用户及其启用中的 API Key，并把注销申请更新为已确认；
+// 申请须仍处于 pending 状态（并发重复确认时返回 ErrAccountDeletionNotFound），
+// req.PreviousStatus 与 req.RevokedAPIKeyIDs 在此回填，供管理员恢复时使用
+func (r *userDataRepository) ScheduleDeletion(ctx context.Context, req *service.AccountDeletionRequest) error {
+	if req == nil {
+		return fmt.Errorf("nil account deletion request")
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var previousStatus string
+	err = scanSingleRow(ctx, tx, "SELECT status FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", []any{req.UserID}, &previousStatus)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET status = $2, updated_at = NOW() WHERE id = $1", req.UserID, service.StatusDisabled); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+UPDATE api_keys SET status = $2, updated_at = NOW()
+WHERE user_id = $1 AND status = $3 AND deleted_at IS NULL
+RETURNING id`, req.UserID, service.StatusAPIKeyDisabled, service.StatusAPIKeyActive)
+	if err != nil {
+		return err
+	}
+	keyIDs := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		keyIDs = append(keyIDs, id)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	sort.Slice(keyIDs, func(i, j int) bool { return keyIDs[i] < keyIDs[j] })
+
+	req.PreviousStatus = previousStatus
+	req.RevokedAPIKeyIDs = keyIDs
+	if err := updateDeletionRequest(ctx, tx, req, service.AccountDeletionStatusPending); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateDeletionRequest 写回注销申请；expectedStatus 非空时仅在当前状态一致时更新
+func updateDeletionRequest(ctx context.Context, exec sqlQueryer, req *service.AccountDeletionRequest, expectedStatus string) error {
+	ids := req.RevokedAPIKeyIDs
+	if ids == nil {
+		ids = []int64{}
+	}
+	args := []any{
+		req.ID,
+		req.Email,
+		req.Status,
+		req.Reason,
+		req.TokenHash,
+		req.TokenExpiresAt,
+		req.PreviousStatus,
+		pq.Array(ids),
+		opsNullString(req.IPAddress),
+		req.ConfirmedAt,
+		req.ScheduledFor,
+		req.CancelledAt,
+		req.RestoredAt,
+		req.RestoredBy,
+		req.CompletedAt,
+	}
+	where := "id = $1"
+	if expectedStatus != "" {
+		args = append(args, expectedStatus)
+		where += " AND status = $16"
+	}
+	q := `
+UPDATE account_deletion_requests
+SET email = $2, status = $3, reason = $4, token_hash = $5, token_expires_at = $6,
+    previous_status = $7, revoked_api_key_ids = $8, ip_address = $9,
+    confirmed_at = $10, scheduled_for = $11, cancelled_at = $12,
+    restored_at = $13, restored_by = $14, completed_at = $15,
+    updated_at = NOW()
+WHERE ` + where + `
+RETURNING updated_at`
+	err := scanSingleRow(ctx, exec, q, args, &req.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrAccountDeletionNotFound
+	}
+	return err
+}
+
+func (r *userDataRepository) ListDeletionRequests(ctx context.Context, params pagination.PaginationParams, filter service.AccountDeletionFilter) ([]service.AccountDeletionRequest, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]any, 0, 4)
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	where := buildWhere(conditions)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM account_deletion_requests "+where, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	q := "SELECT" + accountDeletionColumns + "\n" + where +
+		fmt.Sprintf("\nORDER BY created_at DESC, id DESC\nLIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, q, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.AccountDeletionRequest, 0, params.Limit())
+	for rows.Next() {
+		req, err := scanAccountDeletionRequest(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *userDataRepository) ListDueDeletionRequestIDs(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id FROM account_deletion_requests
+WHERE status = $1 AND scheduled_for IS NOT NULL AND scheduled_for <= $2
+ORDER BY scheduled_for ASC
+LIMIT $3`, service.AccountDeletionStatusScheduled, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make([]int64, 0, limit)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PurgeUser 在同一事务内匿名化用户：
+// - 删除个人资料相关数据（自定义属性、第三方登录、Passkey、恢复码、导出归档、请求抓取）
+// - 清除使用记录 / 错误日志中的 IP、UA、请求头与请求体，保留计费字段
+// - 用户邮箱改写为占位地址（邮箱唯一索引仅约束未删除用户），清空用户名、备注、密码与 2FA，并软删除
+// - API Key 停用并软删除
+func (r *userDataRepository) PurgeUser(ctx context.Context, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	statements := []string{
+		`UPDATE usage_logs SET ip_address = NULL, user_agent = NULL, request_headers = NULL, error_body = NULL WHERE user_id = $1`,
+		`DELETE FROM ops_request_captures WHERE user_id = $1`,
+		`UPDATE ops_error_logs SET client_ip = NULL, user_agent = NULL, request_body = NULL, request_headers = NULL WHERE user_id = $1`,
+		`DELETE FROM user_attribute_values WHERE user_id = $1`,
+		`DELETE FROM user_external_identities WHERE user_id = $1`,
+		`DELETE FROM user_webauthn_credentials WHERE user_id = $1`,
+		`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_data_exports WHERE user_id = $1`,
+		`UPDATE user_referrals SET signup_ip = NULL WHERE referee_user_id = $1`,
+		`DELETE FROM subscription_renewals WHERE user_id = $1`,
+		`UPDATE api_keys SET status = 'disabled', deleted_at = COALESCE(deleted_at, NOW()), updated_at = NOW() WHERE user_id = $1`,
+		`UPDATE users
+SET email = 'deleted-' || id || '@deleted.invalid',
+    username = '', notes = '', password_hash = '!deleted',
+    totp_secret_encrypted = NULL, totp_enabled = false, totp_enabled_at = NULL,
+    status = 'disabled', deleted_at = COALESCE(deleted_at, NOW()), updated_at = NOW()
+WHERE id = $1`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt, userID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func scanAccountDeletionRequest(row interface{ Scan(dest ...any) error }) (*service.AccountDeletionRequest, error) {
+	var (
+		req          service.AccountDeletionRequest
+		keyIDs       pq.Int64Array
+		confirmedAt  sql.NullTime
+		scheduledFor sql.NullTime
+		cancelledAt  sql.NullTime
+		restoredAt   sql.NullTime
+		restoredBy   sql.NullInt64
+		completedAt  sql.NullTime
+	)
+	if err := row.Scan(
+		&req.ID,
+		&req.UserID,
+		&req.Email,
+		&req.Status,
+		&req.Reason,
+		&req.TokenHash,
+		&req.TokenExpiresAt,
+		&req.PreviousStatus,
+		&keyIDs,
+		&req.IPAddress,
+		&confirmedAt,
+		&scheduledFor,
+		&cancelledAt,
+		&restoredAt,
+		&restoredBy,
+		&completedAt,
+		&req.CreatedAt,
+		&req.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	req.RevokedAPIKeyIDs = []int64(keyIDs)
+	if req.RevokedAPIKeyIDs == nil {
+		req.RevokedAPIKeyIDs = []int64{}
+	}
+	nullTime := func(v sql.NullTime) *time.Time {
+		if !v.Valid {
+			return nil
+		}
+		t := v.Time
+		return &t
+	}
+	req.ConfirmedAt = nullTime(confirmedAt)
+	req.ScheduledFor = nullTime(scheduledFor)
+	req.CancelledAt = nullTime(cancelledAt)
+	req.RestoredAt = nullTime(restoredAt)
+	req.CompletedAt = nullTime(completedAt)
+	if restoredBy.Valid {
+		v := restoredBy.Int64
+		req.RestoredBy = &v
+	}
+	return &req, nil
+}
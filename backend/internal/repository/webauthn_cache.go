@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const webauthnChallengeKeyPrefix = "webauthn:challenge:"
+
+// WebAuthnCache implements service.WebAuthnCache using Redis
+type WebAuthnCache struct {
+	rdb *redis.Client
+}
+
+// NewWebAuthnCache creates a new WebAuthn challenge cache
+func NewWebAuthnCache(rdb *redis.Client) service.WebAuthnCache {
+	return &WebAuthnCache{rdb: rdb}
+}
+
+// SetChallenge stores a challenge session
+func (c *WebAuthnCache) SetChallenge(ctx context.Context, key string, session *service.WebAuthnChallengeSession, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("marshal challenge session: %w", err)
+	}
+	if err := c.rdb.Set(ctx, webauthnChallengeKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("set challenge session: %w", err)
+	}
+	return nil
+}
+
+// ConsumeChallenge atomically reads and deletes a challenge session (single use)
+func (c *WebAuthnCache) ConsumeChallenge(ctx context.Context, key string) (*service.WebAuthnChallengeSession, error) {
+	data, err := c.rdb.GetDel(ctx, webauthnChallengeKeyPrefix+key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get challenge session: %w", err)
+	}
+
+	var session service.WebAuthnChallengeSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("unmarshal challenge session: %w", err)
+	}
+	return &session, nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type webAuthnCredentialRepository struct {
+	db *sql.DB
+}
+
+func NewWebAuthnCredentialRepository(db *sql.DB) service.WebAuthnCredentialRepository {
+	return &webAuthnCredentialRepository{db: db}
+}
+
+const webAuthnCredentialColumns = `id, user_id, name, credential_id, public_key, sign_count, aaguid, transports, backup_eligible, backup_state, last_used_at, created_at`
+
+func scanWebAuthnCredential(row interface{ Scan(...any) error }) (*service.WebAuthnCredential, error) {
+	var (
+		credential     service.WebAuthnCredential
+		transports     string
+		backupEligible sql.NullBool
+		lastUsedAt     sql.NullTime
+	)
+	if err := row.Scan(
+		&credential.ID,
+		&credential.UserID,
+		&credential.Name,
+		&credential.CredentialID,
+		&credential.PublicKey,
+		&credential.SignCount,
+		&credential.AAGUID,
+		&transports,
+		&backupEligible,
+		&credential.BackupState,
+		&lastUsedAt,
+		&credential.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if transports != "" {
+		credential.Transports = strings.Split(transports, ",")
+	}
+	if backupEligible.Valid {
+		b := backupEligible.Bool
+		credential.BackupEligible = &b
+	}
+	if lastUsedAt.Valid {
+		t := lastUsedAt.Time
+		credential.LastUsedAt = &t
+	}
+	return &credential, nil
+}
+
+func (r *webAuthnCredentialRepository) ListByUser(ctx context.Context, userID int64) ([]service.WebAuthnCredential, error) {
+	q := `SELECT ` + webAuthnCredentialColumns + `
+FROM user_webauthn_credentials
+WHERE user_id = $1
+ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.WebAuthnCredential, 0)
+	for rows.Next() {
+		credential, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *credential)
+	}
+	return out, rows.Err()
+}
+
+func (r *webAuthnCredentialRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_webauthn_credentials WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+func (r *webAuthnCredentialRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*service.WebAuthnCredential, error) {
+	q := `SELECT ` + webAuthnCredentialColumns + `
+FROM user_webauthn_credentials
+WHERE credential_id = $1`
+
+	credential, err := scanWebAuthnCredential(r.db.QueryRowContext(ctx, q, credentialID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (r *webAuthnCredentialRepository) Create(ctx context.Context, credential *service.WebAuthnCredential) error {
+	q := `
+INSERT INTO user_webauthn_credentials (user_id, name, credential_id, public_key, sign_count, aaguid, transports, backup_eligible, backup_state)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(
+		ctx, q,
+		credential.UserID,
+		credential.Name,
+		credential.CredentialID,
+		credential.PublicKey,
+		credential.SignCount,
+		credential.AAGUID,
+		strings.Join(credential.Transports, ","),
+		credential.BackupEligible,
+		credential.BackupState,
+	).Scan(&credential.ID, &credential.CreatedAt)
+	if isUniqueConstraintViolation(err) {
+		return service.ErrWebAuthnCredentialExists
+	}
+	return err
+}
+
+func (r *webAuthnCredentialRepository) Rename(ctx context.Context, userID, id int64, name string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE user_webauthn_credentials SET name = $3 WHERE id = $1 AND user_id = $2`, id, userID, name)
+	if err != nil {
+		return err
+	}
+	return webAuthnRequireAffected(res)
+}
+
+func (r *webAuthnCredentialRepository) UpdateUsage(ctx context.Context, id int64, signCount int64, backupEligible, backupState bool) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE user_webauthn_credentials
+SET sign_count = $2, backup_eligible = $3, backup_state = $4, last_used_at = NOW()
+WHERE id = $1`, id, signCount, backupEligible, backupState)
+	return err
+}
+
+func (r *webAuthnCredentialRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM user_webauthn_credentials WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	return webAuthnRequireAffected(res)
+}
+
+func webAuthnRequireAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrWebAuthnCredentialNotFound
+	}
+	return nil
+}
+
+type recoveryCodeRepository struct {
+	db *sql.DB
+}
+
+func NewRecoveryCodeRepository(db *sql.DB) service.RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID int64, hashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *recoveryCodeRepository) Consume(ctx context.Context, userID int64, hash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+UPDATE user_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *recoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
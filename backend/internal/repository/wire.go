@@ -68,6 +68,17 @@ var ProviderSet = wire.NewSet(
 	NewUserAttributeValueRepository,
 	NewUserGroupRateRepository,
 	NewErrorPassthroughRepository,
+	NewAccountProbeRepository,
+	NewAPIKeyAbuseRepository,
+	NewSpendGuardRepository,
+	NewReferralRepository,
+	NewImpersonationRepository,
+	NewUserDataRepository,
+	NewSubscriptionPlanRepository,
+	NewPaymentRepository,
+	NewExternalIdentityRepository,
+	NewWebAuthnCredentialRepository,
+	NewRecoveryCodeRepository,
 
 	// Cache implementations
 	NewGatewayCache,
@@ -75,6 +86,7 @@ var ProviderSet = wire.NewSet(
 	NewAPIKeyCache,
 	NewTempUnschedCache,
 	NewTimeoutCounterCache,
+	NewRateLimitBudgetCache,
 	ProvideConcurrencyCache,
 	ProvideSessionLimitCache,
 	NewDashboardCache,
@@ -87,7 +99,9 @@ var ProviderSet = wire.NewSet(
 	NewSchedulerOutboxRepository,
 	NewProxyLatencyCache,
 	NewTotpCache,
+	NewWebAuthnCache,
 	NewRefreshTokenCache,
+	NewLoginGuardCache,
 	NewErrorPassthroughCache,
 
 	// Encryptors
@@ -176,6 +176,18 @@ func validateJWTForAdmin(
 		return false
 	}
 
+	// 会话被单独撤销后，其尚未过期的 Access Token 也立即失效
+	if authService.IsSessionRevoked(c.Request.Context(), claims.SessionID) {
+		AbortWithError(c, 401, "SESSION_REVOKED", "Session has been revoked")
+		return false
+	}
+
+	// 模拟登录 Token 不能访问管理接口
+	if claims.ImpersonatorID != 0 {
+		AbortWithError(c, 403, "IMPERSONATION_FORBIDDEN", "Admin endpoints are not available while impersonating a user")
+		return false
+	}
+
 	// 检查管理员权限
 	if !user.IsAdmin() {
 		AbortWithError(c, 403, "FORBIDDEN", "Admin access required")
@@ -187,6 +199,7 @@ func validateJWTForAdmin(
 		Concurrency: user.Concurrency,
 	})
 	c.Set(string(ContextKeyUserRole), user.Role)
+	c.Set(string(ContextKeySessionID), claims.SessionID)
 	c.Set("auth_method", "jwt")
 
 	return true
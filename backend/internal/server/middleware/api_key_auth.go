@@ -9,6 +9,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -76,6 +77,8 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 				AbortWithError(c, 429, "API_KEY_QUOTA_EXHAUSTED", "API key 额度已用完")
 			case service.StatusAPIKeyExpired:
 				AbortWithError(c, 403, "API_KEY_EXPIRED", "API key 已过期")
+			case service.StatusAPIKeySuspended:
+				AbortWithError(c, 403, "API_KEY_SUSPENDED", "API key has been suspended due to suspected sharing or unusual spending, please contact the administrator")
 			default:
 				AbortWithError(c, 401, "API_KEY_DISABLED", "API key is disabled")
 			}
@@ -105,6 +108,12 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 			}
 		}
 
+		// 检查滥用检测限流（仅对被处置的 Key 生效）
+		if !apiKeyService.AllowAbuseLimitedRequest(c.Request.Context(), apiKey.ID) {
+			AbortWithError(c, 429, "API_KEY_RATE_LIMITED", "API key request rate is limited due to suspected sharing")
+			return
+		}
+
 		// 检查关联的用户
 		if apiKey.User == nil {
 			AbortWithError(c, 401, "USER_NOT_FOUND", "User associated with API key not found")
@@ -126,6 +135,7 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 			})
 			c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 			setGroupContext(c, apiKey.Group)
+			setRequestLogFields(c, apiKey)
 			c.Next()
 			return
 		}
@@ -185,6 +195,7 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 		})
 		c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 		setGroupContext(c, apiKey.Group)
+		setRequestLogFields(c, apiKey)
 
 		c.Next()
 	}
@@ -220,3 +231,18 @@ func setGroupContext(c *gin.Context, group *service.Group) {
 	ctx := context.WithValue(c.Request.Context(), ctxkey.Group, group)
 	c.Request = c.Request.WithContext(ctx)
 }
+
+// setRequestLogFields 将认证后的用户、API Key、分组与平台写入请求级日志字段。
+func setRequestLogFields(c *gin.Context, apiKey *service.APIKey) {
+	if apiKey == nil {
+		return
+	}
+	ctx := logger.WithRequestFields(c.Request.Context())
+	if ctx != c.Request.Context() {
+		c.Request = c.Request.WithContext(ctx)
+	}
+	logger.SetAuth(ctx, apiKey.UserID, apiKey.ID, apiKey.GroupID)
+	if apiKey.Group != nil && apiKey.Group.Platform != "" {
+		logger.SetPlatform(ctx, apiKey.Group.Platform)
+	}
+}
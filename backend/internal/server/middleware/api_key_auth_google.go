@@ -43,9 +43,17 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 		}
 
 		if !apiKey.IsActive() {
+			if apiKey.Status == service.StatusAPIKeySuspended {
+				abortWithGoogleError(c, 403, "API key has been suspended due to suspected sharing or unusual spending")
+				return
+			}
 			abortWithGoogleError(c, 401, "API key is disabled")
 			return
 		}
+		if !apiKeyService.AllowAbuseLimitedRequest(c.Request.Context(), apiKey.ID) {
+			abortWithGoogleError(c, 429, "API key request rate is limited due to suspected sharing")
+			return
+		}
 		if apiKey.User == nil {
 			abortWithGoogleError(c, 401, "User associated with API key not found")
 			return
@@ -64,6 +72,7 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 			})
 			c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 			setGroupContext(c, apiKey.Group)
+			setRequestLogFields(c, apiKey)
 			c.Next()
 			return
 		}
@@ -104,6 +113,7 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 		})
 		c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 		setGroupContext(c, apiKey.Group)
+		setRequestLogFields(c, apiKey)
 		c.Next()
 	}
 }
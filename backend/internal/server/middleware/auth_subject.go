@@ -1,6 +1,10 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
 
 // AuthSubject is the minimal authenticated identity stored in gin context.
 // Decision: {UserID int64, Concurrency int}
@@ -26,3 +30,34 @@ func GetUserRoleFromContext(c *gin.Context) (string, bool) {
 	role, ok := value.(string)
 	return role, ok
 }
+
+// GetSessionIDFromContext 返回当前 Access Token 所属会话ID（旧 Token 没有会话ID）
+func GetSessionIDFromContext(c *gin.Context) string {
+	value, exists := c.Get(string(ContextKeySessionID))
+	if !exists {
+		return ""
+	}
+	sessionID, _ := value.(string)
+	return sessionID
+}
+
+// GetImpersonationFromContext 返回当前请求的管理员模拟登录会话（非模拟 Token 返回 false）
+func GetImpersonationFromContext(c *gin.Context) (*service.ImpersonationSession, bool) {
+	value, exists := c.Get(string(ContextKeyImpersonation))
+	if !exists {
+		return nil, false
+	}
+	session, ok := value.(*service.ImpersonationSession)
+	return session, ok && session != nil
+}
+
+// DenyImpersonation 模拟登录期间禁止访问的接口（修改密码 / 2FA / 创建 API Key 等）
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetImpersonationFromContext(c); ok {
+			AbortWithError(c, 403, "IMPERSONATION_FORBIDDEN", "This action is not allowed while impersonating a user")
+			return
+		}
+		c.Next()
+	}
+}
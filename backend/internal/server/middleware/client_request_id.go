@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -18,13 +19,14 @@ func ClientRequestID() gin.HandlerFunc {
 			return
 		}
 
-		if v := c.Request.Context().Value(ctxkey.ClientRequestID); v != nil {
-			c.Next()
-			return
+		// 挂载请求级日志字段容器，后续认证与调度阶段会写入用户、账号等字段。
+		ctx := logger.WithRequestFields(c.Request.Context())
+		if v := ctx.Value(ctxkey.ClientRequestID); v == nil {
+			ctx = context.WithValue(ctx, ctxkey.ClientRequestID, uuid.New().String())
+		}
+		if ctx != c.Request.Context() {
+			c.Request = c.Request.WithContext(ctx)
 		}
-
-		id := uuid.New().String()
-		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxkey.ClientRequestID, id))
 		c.Next()
 	}
 }
@@ -4,18 +4,19 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // NewJWTAuthMiddleware 创建 JWT 认证中间件
-func NewJWTAuthMiddleware(authService *service.AuthService, userService *service.UserService) JWTAuthMiddleware {
-	return JWTAuthMiddleware(jwtAuth(authService, userService))
+func NewJWTAuthMiddleware(authService *service.AuthService, userService *service.UserService, impersonationService *service.ImpersonationService) JWTAuthMiddleware {
+	return JWTAuthMiddleware(jwtAuth(authService, userService, impersonationService))
 }
 
 // jwtAuth JWT认证中间件实现
-func jwtAuth(authService *service.AuthService, userService *service.UserService) gin.HandlerFunc {
+func jwtAuth(authService *service.AuthService, userService *service.UserService, impersonationService *service.ImpersonationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从Authorization header中提取token
 		authHeader := c.GetHeader("Authorization")
@@ -68,13 +69,51 @@ func jwtAuth(authService *service.AuthService, userService *service.UserService)
 			return
 		}
 
+		// 会话被单独撤销后，其尚未过期的 Access Token 也立即失效
+		if authService.IsSessionRevoked(c.Request.Context(), claims.SessionID) {
+			AbortWithError(c, 401, "SESSION_REVOKED", "Session has been revoked")
+			return
+		}
+
+		// 管理员模拟登录：校验会话仍有效，并记录本次请求
+		var impersonation *service.ImpersonationSession
+		if claims.ImpersonatorID != 0 {
+			if impersonationService == nil {
+				AbortWithError(c, 401, "INVALID_TOKEN", "Invalid token")
+				return
+			}
+			impersonation, err = impersonationService.Authorize(c.Request.Context(), claims)
+			if err != nil {
+				if errors.Is(err, service.ErrImpersonationEnded) {
+					AbortWithError(c, 401, "IMPERSONATION_ENDED", "Impersonation session has ended")
+					return
+				}
+				AbortWithError(c, 401, "INVALID_TOKEN", "Invalid token")
+				return
+			}
+			c.Set(string(ContextKeyImpersonation), impersonation)
+		}
+
 		c.Set(string(ContextKeyUser), AuthSubject{
 			UserID:      user.ID,
 			Concurrency: user.Concurrency,
 		})
 		c.Set(string(ContextKeyUserRole), user.Role)
+		c.Set(string(ContextKeySessionID), claims.SessionID)
 
 		c.Next()
+
+		if impersonation != nil {
+			impersonationService.RecordAction(c.Request.Context(), &service.ImpersonationAction{
+				SessionID:    impersonation.ID,
+				AdminUserID:  impersonation.AdminUserID,
+				TargetUserID: impersonation.TargetUserID,
+				Method:       c.Request.Method,
+				Path:         c.Request.URL.RequestURI(),
+				StatusCode:   c.Writer.Status(),
+				IPAddress:    ip.GetClientIP(c),
+			})
+		}
 	}
 }
 
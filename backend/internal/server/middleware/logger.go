@@ -1,9 +1,10 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,41 +17,27 @@ func Logger() gin.HandlerFunc {
 		// 处理请求
 		c.Next()
 
-		// 结束时间
-		endTime := time.Now()
-
 		// 执行时间
-		latency := endTime.Sub(startTime)
-
-		// 请求方法
-		method := c.Request.Method
-
-		// 请求路径
-		path := c.Request.URL.Path
+		latency := time.Since(startTime)
 
 		// 状态码
 		statusCode := c.Writer.Status()
 
-		// 客户端IP
-		clientIP := c.ClientIP()
-
-		// 协议版本
-		protocol := c.Request.Proto
-
-		// 日志格式: [时间] 状态码 | 延迟 | IP | 协议 | 方法 路径
-		log.Printf("[GIN] %v | %3d | %13v | %15s | %-6s | %-7s %s",
-			endTime.Format("2006/01/02 - 15:04:05"),
-			statusCode,
-			latency,
-			clientIP,
-			protocol,
-			method,
-			path,
-		)
+		// 结构化字段：状态码 | 延迟 | IP | 协议 | 方法 路径（请求级字段由 logger 从 context 注入）
+		attrs := []slog.Attr{
+			slog.Int("status", statusCode),
+			slog.Int64("latency_ms", latency.Milliseconds()),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("protocol", c.Request.Proto),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+		}
 
 		// 如果有错误，额外记录错误信息
 		if len(c.Errors) > 0 {
-			log.Printf("[GIN] Errors: %v", c.Errors.String())
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
 		}
+
+		logger.Module("gin").LogAttrs(c.Request.Context(), slog.LevelInfo, "request", attrs...)
 	}
 }
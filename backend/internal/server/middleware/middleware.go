@@ -15,6 +15,10 @@ const (
 	ContextKeyUser ContextKey = "user"
 	// ContextKeyUserRole 当前用户角色（string）
 	ContextKeyUserRole ContextKey = "user_role"
+	// ContextKeySessionID 当前 Access Token 所属会话ID（string，旧 Token 为空）
+	ContextKeySessionID ContextKey = "session_id"
+	// ContextKeyImpersonation 管理员模拟登录会话（*service.ImpersonationSession），仅模拟 Token 设置
+	ContextKeyImpersonation ContextKey = "impersonation"
 	// ContextKeyAPIKey API密钥上下文键
 	ContextKeyAPIKey ContextKey = "api_key"
 	// ContextKeySubscription 订阅上下文键
@@ -70,6 +70,25 @@ func RegisterAdminRoutes(
 
 		// 错误透传规则管理
 		registerErrorPassthroughRoutes(admin, h)
+
+		// API Key 共享/滥用检测
+		registerAPIKeyAbuseRoutes(admin, h)
+
+		// 消费异常自动停用
+		registerSpendGuardRoutes(admin, h)
+		registerReferralRoutes(admin, h)
+
+		// 模拟登录审计
+		registerImpersonationRoutes(admin, h)
+
+		// 自助注销申请
+		registerAccountDeletionRoutes(admin, h)
+
+		// 订阅套餐与订单
+		registerSubscriptionPlanRoutes(admin, h)
+
+		// 在线支付订单与配置
+		registerPaymentRoutes(admin, h)
 	}
 }
 
@@ -90,8 +109,42 @@ func registerOpsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		ops.GET("/alert-events", h.Admin.Ops.ListAlertEvents)
 		ops.GET("/alert-events/:id", h.Admin.Ops.GetAlertEvent)
 		ops.PUT("/alert-events/:id/status", h.Admin.Ops.UpdateAlertEventStatus)
+		ops.POST("/alert-events/:id/ack", h.Admin.Ops.AcknowledgeAlertEvent)
+		ops.GET("/alert-events/:id/timeline", h.Admin.Ops.ListAlertEventTimeline)
+		ops.PUT("/alert-events/:id/public", h.Admin.Ops.SetAlertEventPublic)
 		ops.POST("/alert-silences", h.Admin.Ops.CreateAlertSilence)
 
+		// SLOs (error budget + burn rate)
+		ops.GET("/slos", h.Admin.Ops.ListSLOs)
+		ops.POST("/slos", h.Admin.Ops.CreateSLO)
+		ops.GET("/slos/status", h.Admin.Ops.ListSLOStatuses)
+		ops.PUT("/slos/:id", h.Admin.Ops.UpdateSLO)
+		ops.DELETE("/slos/:id", h.Admin.Ops.DeleteSLO)
+		ops.GET("/slos/:id/status", h.Admin.Ops.GetSLOStatus)
+
+		// Sampled request/response captures (opt-in, successful requests)
+		ops.GET("/request-capture/settings", h.Admin.Ops.GetRequestCaptureSettings)
+		ops.PUT("/request-capture/settings", h.Admin.Ops.UpdateRequestCaptureSettings)
+		ops.GET("/request-captures", h.Admin.Ops.ListRequestCaptures)
+		ops.GET("/request-captures/:id", h.Admin.Ops.GetRequestCapture)
+		ops.DELETE("/request-captures/:id", h.Admin.Ops.DeleteRequestCapture)
+
+		// Traffic shadowing (duplicate sampled requests to candidate accounts)
+		ops.GET("/shadow-rules", h.Admin.Ops.ListShadowRules)
+		ops.POST("/shadow-rules", h.Admin.Ops.CreateShadowRule)
+		ops.PUT("/shadow-rules/:id", h.Admin.Ops.UpdateShadowRule)
+		ops.DELETE("/shadow-rules/:id", h.Admin.Ops.DeleteShadowRule)
+		ops.GET("/shadow-rules/:id/report", h.Admin.Ops.GetShadowReport)
+
+		// Public status page (settings + admin-authored incidents)
+		ops.GET("/status-page/settings", h.Admin.Ops.GetStatusPageSettings)
+		ops.PUT("/status-page/settings", h.Admin.Ops.UpdateStatusPageSettings)
+		ops.GET("/status-incidents", h.Admin.Ops.ListStatusIncidents)
+		ops.POST("/status-incidents", h.Admin.Ops.CreateStatusIncident)
+		ops.PUT("/status-incidents/:id", h.Admin.Ops.UpdateStatusIncident)
+		ops.DELETE("/status-incidents/:id", h.Admin.Ops.DeleteStatusIncident)
+		ops.POST("/status-incidents/:id/updates", h.Admin.Ops.AddStatusIncidentUpdate)
+
 		// Email notification config (DB-backed)
 		ops.GET("/email-notification/config", h.Admin.Ops.GetEmailNotificationConfig)
 		ops.PUT("/email-notification/config", h.Admin.Ops.UpdateEmailNotificationConfig)
@@ -180,11 +233,22 @@ func registerUserManagementRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		users.GET("/:id/api-keys", h.Admin.User.GetUserAPIKeys)
 		users.GET("/:id/usage", h.Admin.User.GetUserUsage)
 		users.GET("/:id/balance-history", h.Admin.User.GetBalanceHistory)
+		users.GET("/:id/sessions", h.Admin.UserSession.List)
+		users.DELETE("/:id/sessions", h.Admin.UserSession.RevokeAll)
+		users.DELETE("/:id/sessions/:session_id", h.Admin.UserSession.Revoke)
+		users.POST("/:id/impersonate", h.Admin.Impersonation.Start)
 
 		// User attribute values
 		users.GET("/:id/attributes", h.Admin.UserAttribute.GetUserAttributes)
 		users.PUT("/:id/attributes", h.Admin.UserAttribute.UpdateUserAttributes)
 	}
+
+	// 登录锁定（暴力破解防护）
+	loginLocks := admin.Group("/login-locks")
+	{
+		loginLocks.GET("", h.Admin.LoginGuard.ListLocks)
+		loginLocks.POST("/unlock", h.Admin.LoginGuard.Unlock)
+	}
 }
 
 func registerGroupRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
@@ -231,6 +295,13 @@ func registerAccountRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		accounts.POST("/batch-refresh-tier", h.Admin.Account.BatchRefreshTier)
 		accounts.POST("/bulk-update", h.Admin.Account.BulkUpdate)
 
+		// 定时探测（合成健康检查）
+		accounts.GET("/probe-settings", h.Admin.AccountProbe.GetSettings)
+		accounts.PUT("/probe-settings", h.Admin.AccountProbe.UpdateSettings)
+		accounts.GET("/probe-status", h.Admin.AccountProbe.GetLatest)
+		accounts.GET("/:id/probes", h.Admin.AccountProbe.ListResults)
+		accounts.POST("/:id/probe", h.Admin.AccountProbe.Probe)
+
 		// Antigravity 默认模型映射
 		accounts.GET("/antigravity/default-model-mapping", h.Admin.Account.GetAntigravityDefaultModelMapping)
 
@@ -344,6 +415,11 @@ func registerSettingsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		// 流超时处理配置
 		adminSettings.GET("/stream-timeout", h.Admin.Setting.GetStreamTimeoutSettings)
 		adminSettings.PUT("/stream-timeout", h.Admin.Setting.UpdateStreamTimeoutSettings)
+		// 通用 OIDC 登录提供方
+		adminSettings.GET("/oidc-providers", h.Admin.OIDCProvider.List)
+		adminSettings.POST("/oidc-providers", h.Admin.OIDCProvider.Create)
+		adminSettings.PUT("/oidc-providers/:slug", h.Admin.OIDCProvider.Update)
+		adminSettings.DELETE("/oidc-providers/:slug", h.Admin.OIDCProvider.Delete)
 	}
 }
 
@@ -355,6 +431,8 @@ func registerSystemRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		system.POST("/update", h.Admin.System.PerformUpdate)
 		system.POST("/rollback", h.Admin.System.Rollback)
 		system.POST("/restart", h.Admin.System.RestartService)
+		system.GET("/log-levels", h.Admin.System.GetLogLevels)
+		system.PUT("/log-levels", h.Admin.System.UpdateLogLevels)
 	}
 }
 
@@ -412,3 +490,74 @@ func registerErrorPassthroughRoutes(admin *gin.RouterGroup, h *handler.Handlers)
 		rules.DELETE("/:id", h.Admin.ErrorPassthrough.Delete)
 	}
 }
+
+func registerAPIKeyAbuseRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	abuse := admin.Group("/api-key-abuse")
+	{
+		abuse.GET("/settings", h.Admin.APIKeyAbuse.GetSettings)
+		abuse.PUT("/settings", h.Admin.APIKeyAbuse.UpdateSettings)
+		abuse.GET("/flags", h.Admin.APIKeyAbuse.ListFlags)
+		abuse.GET("/flags/:id", h.Admin.APIKeyAbuse.GetFlag)
+		abuse.POST("/flags/:id/action", h.Admin.APIKeyAbuse.ReviewFlag)
+		abuse.POST("/scan", h.Admin.APIKeyAbuse.Scan)
+	}
+}
+
+func registerSpendGuardRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	guard := admin.Group("/spend-guard")
+	{
+		guard.GET("/settings", h.Admin.SpendGuard.GetSettings)
+		guard.PUT("/settings", h.Admin.SpendGuard.UpdateSettings)
+		guard.GET("/suspensions", h.Admin.SpendGuard.ListSuspensions)
+		guard.POST("/suspensions/:id/lift", h.Admin.SpendGuard.LiftSuspension)
+	}
+}
+
+func registerSubscriptionPlanRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	plans := admin.Group("/subscription-plans")
+	{
+		plans.GET("", h.Admin.SubscriptionPlan.List)
+		plans.POST("", h.Admin.SubscriptionPlan.Create)
+		plans.PUT("/:id", h.Admin.SubscriptionPlan.Update)
+		plans.DELETE("/:id", h.Admin.SubscriptionPlan.Delete)
+	}
+	admin.GET("/subscription-orders", h.Admin.SubscriptionPlan.ListOrders)
+}
+
+func registerPaymentRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	payments := admin.Group("/payments")
+	{
+		payments.GET("/orders", h.Admin.Payment.ListOrders)
+		payments.POST("/orders/:order_no/refund", h.Admin.Payment.Refund)
+		payments.GET("/settings", h.Admin.Payment.GetSettings)
+		payments.PUT("/settings", h.Admin.Payment.UpdateSettings)
+	}
+}
+
+func registerReferralRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	referrals := admin.Group("/referrals")
+	{
+		referrals.GET("/settings", h.Admin.Referral.GetSettings)
+		referrals.PUT("/settings", h.Admin.Referral.UpdateSettings)
+		referrals.GET("", h.Admin.Referral.List)
+		referrals.POST("/:id/approve", h.Admin.Referral.Approve)
+		referrals.POST("/:id/block", h.Admin.Referral.Block)
+	}
+}
+
+func registerImpersonationRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	impersonations := admin.Group("/impersonations")
+	{
+		impersonations.GET("", h.Admin.Impersonation.List)
+		impersonations.GET("/:id/actions", h.Admin.Impersonation.ListActions)
+		impersonations.POST("/:id/end", h.Admin.Impersonation.End)
+	}
+}
+
+func registerAccountDeletionRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	deletions := admin.Group("/account-deletions")
+	{
+		deletions.GET("", h.Admin.AccountDeletion.List)
+		deletions.POST("/:id/restore", h.Admin.AccountDeletion.Restore)
+	}
+}
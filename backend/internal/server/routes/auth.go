@@ -27,6 +27,13 @@ func RegisterAuthRoutes(
 		auth.POST("/register", h.Auth.Register)
 		auth.POST("/login", h.Auth.Login)
 		auth.POST("/login/2fa", h.Auth.Login2FA)
+		// 通行密钥：二步验证断言 / 无密码登录（每分钟最多 30 次，Redis 故障时 fail-close）
+		passkeyLimit := rateLimiter.LimitWithOptions("passkey-login", 30, time.Minute, middleware.RateLimitOptions{
+			FailureMode: middleware.RateLimitFailClose,
+		})
+		auth.POST("/login/2fa/webauthn/begin", passkeyLimit, h.Auth.Login2FAWebAuthnBegin)
+		auth.POST("/passkey/begin", passkeyLimit, h.Auth.PasskeyLoginBegin)
+		auth.POST("/passkey/finish", passkeyLimit, h.Auth.PasskeyLoginFinish)
 		auth.POST("/send-verify-code", h.Auth.SendVerifyCode)
 		// Token刷新接口添加速率限制：每分钟最多 30 次（Redis 故障时 fail-close）
 		auth.POST("/refresh", rateLimiter.LimitWithOptions("refresh-token", 30, time.Minute, middleware.RateLimitOptions{
@@ -50,8 +57,15 @@ func RegisterAuthRoutes(
 		auth.POST("/reset-password", rateLimiter.LimitWithOptions("reset-password", 10, time.Minute, middleware.RateLimitOptions{
 			FailureMode: middleware.RateLimitFailClose,
 		}), h.Auth.ResetPassword)
+		// 确认注销账号（邮件链接）：每分钟最多 10 次（Redis 故障时 fail-close）
+		auth.POST("/account-deletion/confirm", rateLimiter.LimitWithOptions("account-deletion-confirm", 10, time.Minute, middleware.RateLimitOptions{
+			FailureMode: middleware.RateLimitFailClose,
+		}), h.UserData.ConfirmDeletion)
 		auth.GET("/oauth/linuxdo/start", h.Auth.LinuxDoOAuthStart)
 		auth.GET("/oauth/linuxdo/callback", h.Auth.LinuxDoOAuthCallback)
+		// 通用 OIDC / OAuth2 登录
+		auth.GET("/oauth/oidc/:provider/start", h.Auth.OIDCOAuthStart)
+		auth.GET("/oauth/oidc/:provider/callback", h.Auth.OIDCOAuthCallback)
 	}
 
 	// 公开设置（无需认证）
@@ -60,12 +74,20 @@ func RegisterAuthRoutes(
 		settings.GET("/public", h.Setting.GetPublicSettings)
 	}
 
+	// 公开状态页（无需认证；服务端缓存 + 每分钟最多 60 次，Redis 故障时放行）
+	v1.GET("/status", rateLimiter.Limit("status-page", 60, time.Minute), h.Status.GetStatus)
+
+	// 支付商回调（签名校验，无需认证；每分钟最多 300 次，Redis 故障时放行）
+	v1.POST("/payments/webhook/:provider", rateLimiter.Limit("payment-webhook", 300, time.Minute), h.Payment.Webhook)
+
 	// 需要认证的当前用户信息
 	authenticated := v1.Group("")
 	authenticated.Use(gin.HandlerFunc(jwtAuth))
 	{
 		authenticated.GET("/auth/me", h.Auth.GetCurrentUser)
 		// 撤销所有会话（需要认证）
-		authenticated.POST("/auth/revoke-all-sessions", h.Auth.RevokeAllSessions)
+		authenticated.POST("/auth/revoke-all-sessions", servermiddleware.DenyImpersonation(), h.Auth.RevokeAllSessions)
+		// 退出模拟登录（仅模拟 Token 可用）
+		authenticated.POST("/auth/impersonation/exit", h.Impersonation.Exit)
 	}
 }
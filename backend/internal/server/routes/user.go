@@ -15,23 +15,76 @@ func RegisterUserRoutes(
 ) {
 	authenticated := v1.Group("")
 	authenticated.Use(gin.HandlerFunc(jwtAuth))
+
+	// 模拟登录（管理员以用户身份查看）期间禁止的敏感操作
+	noImp := middleware.DenyImpersonation()
 	{
 		// 用户接口
 		user := authenticated.Group("/user")
 		{
 			user.GET("/profile", h.User.GetProfile)
-			user.PUT("/password", h.User.ChangePassword)
-			user.PUT("", h.User.UpdateProfile)
+			user.PUT("/password", noImp, h.User.ChangePassword)
+			user.PUT("", noImp, h.User.UpdateProfile)
 
 			// TOTP 双因素认证
 			totp := user.Group("/totp")
 			{
 				totp.GET("/status", h.Totp.GetStatus)
 				totp.GET("/verification-method", h.Totp.GetVerificationMethod)
-				totp.POST("/send-code", h.Totp.SendVerifyCode)
-				totp.POST("/setup", h.Totp.InitiateSetup)
-				totp.POST("/enable", h.Totp.Enable)
-				totp.POST("/disable", h.Totp.Disable)
+				totp.POST("/send-code", noImp, h.Totp.SendVerifyCode)
+				totp.POST("/setup", noImp, h.Totp.InitiateSetup)
+				totp.POST("/enable", noImp, h.Totp.Enable)
+				totp.POST("/disable", noImp, h.Totp.Disable)
+			}
+
+			// 通行密钥（WebAuthn）
+			webauthn := user.Group("/webauthn")
+			{
+				webauthn.GET("/credentials", h.WebAuthn.ListCredentials)
+				webauthn.POST("/register/begin", noImp, h.WebAuthn.BeginRegistration)
+				webauthn.POST("/register/finish", noImp, h.WebAuthn.FinishRegistration)
+				webauthn.PUT("/credentials/:id", noImp, h.WebAuthn.RenameCredential)
+				webauthn.DELETE("/credentials/:id", noImp, h.WebAuthn.DeleteCredential)
+			}
+
+			// 二步验证恢复码（TOTP / 通行密钥通用）
+			user.GET("/recovery-codes", h.WebAuthn.GetRecoveryCodeStatus)
+			user.POST("/recovery-codes", noImp, h.WebAuthn.GenerateRecoveryCodes)
+
+			// 登录会话（按设备查看 / 单独撤销）
+			user.GET("/sessions", h.Auth.ListSessions)
+			user.DELETE("/sessions/:id", noImp, h.Auth.RevokeSession)
+
+			// 邀请返利
+			referral := user.Group("/referral")
+			{
+				referral.GET("", h.Referral.GetOverview)
+				referral.GET("/referrals", h.Referral.ListReferrals)
+				referral.GET("/rewards", h.Referral.ListRewards)
+			}
+
+			// 优惠码（用户面板使用）
+			promoCodes := user.Group("/promo-codes")
+			{
+				promoCodes.GET("", h.Promo.List)
+				promoCodes.GET("/pending", h.Promo.GetPending)
+				promoCodes.POST("/redeem", noImp, h.Promo.Redeem)
+			}
+
+			// 个人数据导出
+			dataExport := user.Group("/data-export")
+			{
+				dataExport.GET("", h.UserData.ListExports)
+				dataExport.POST("", noImp, h.UserData.RequestExport)
+				dataExport.GET("/:id/download", noImp, h.UserData.DownloadExport)
+			}
+
+			// 账号注销（邮件确认 + 宽限期）
+			accountDeletion := user.Group("/account-deletion")
+			{
+				accountDeletion.GET("", h.UserData.GetDeletionStatus)
+				accountDeletion.POST("", noImp, h.UserData.RequestDeletion)
+				accountDeletion.DELETE("", noImp, h.UserData.CancelDeletion)
 			}
 		}
 
@@ -40,9 +93,9 @@ func RegisterUserRoutes(
 		{
 			keys.GET("", h.APIKey.List)
 			keys.GET("/:id", h.APIKey.GetByID)
-			keys.POST("", h.APIKey.Create)
-			keys.PUT("/:id", h.APIKey.Update)
-			keys.DELETE("/:id", h.APIKey.Delete)
+			keys.POST("", noImp, h.APIKey.Create)
+			keys.PUT("/:id", noImp, h.APIKey.Update)
+			keys.DELETE("/:id", noImp, h.APIKey.Delete)
 		}
 
 		// 用户可用分组（非管理员接口）
@@ -75,7 +128,7 @@ func RegisterUserRoutes(
 		// 卡密兑换
 		redeem := authenticated.Group("/redeem")
 		{
-			redeem.POST("", h.Redeem.Redeem)
+			redeem.POST("", noImp, h.Redeem.Redeem)
 			redeem.GET("/history", h.Redeem.GetHistory)
 		}
 
@@ -86,6 +139,24 @@ func RegisterUserRoutes(
 			subscriptions.GET("/active", h.Subscription.GetActive)
 			subscriptions.GET("/progress", h.Subscription.GetProgress)
 			subscriptions.GET("/summary", h.Subscription.GetSummary)
+
+			// 自助购买套餐 / 续费 / 升降级 / 自动续费
+			subscriptions.GET("/plans", h.Plan.ListPlans)
+			subscriptions.GET("/plans/:id/quote", h.Plan.Quote)
+			subscriptions.POST("/plans/:id/purchase", noImp, h.Plan.Purchase)
+			subscriptions.POST("/plans/:id/change", noImp, h.Plan.Change)
+			subscriptions.GET("/orders", h.Plan.ListOrders)
+			subscriptions.GET("/auto-renew", h.Plan.ListAutoRenew)
+			subscriptions.PUT("/auto-renew/:group_id", noImp, h.Plan.SetAutoRenew)
+		}
+
+		// 在线支付充值
+		payments := authenticated.Group("/payments")
+		{
+			payments.GET("/config", h.Payment.GetConfig)
+			payments.POST("/orders", noImp, h.Payment.CreateOrder)
+			payments.GET("/orders", h.Payment.ListOrders)
+			payments.GET("/orders/:order_no", h.Payment.GetOrder)
 		}
 	}
 }
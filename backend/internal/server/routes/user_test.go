@@ -0,0 +1,95 @@
+//go:build unit
+
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// 模拟登录期间禁止的用户接口（会修改账号、余额或凭据）
+var impersonationDeniedUserRoutes = []string{
+	"PUT /api/v1/user",
+	"PUT /api/v1/user/password",
+	"POST /api/v1/user/totp/send-code",
+	"POST /api/v1/user/totp/setup",
+	"POST /api/v1/user/totp/enable",
+	"POST /api/v1/user/totp/disable",
+	"POST /api/v1/user/webauthn/register/begin",
+	"POST /api/v1/user/webauthn/register/finish",
+	"PUT /api/v1/user/webauthn/credentials/:id",
+	"DELETE /api/v1/user/webauthn/credentials/:id",
+	"POST /api/v1/user/recovery-codes",
+	"DELETE /api/v1/user/sessions/:id",
+	"POST /api/v1/user/promo-codes/redeem",
+	"POST /api/v1/user/data-export",
+	"POST /api/v1/user/account-deletion",
+	"DELETE /api/v1/user/account-deletion",
+	"POST /api/v1/keys",
+	"PUT /api/v1/keys/:id",
+	"DELETE /api/v1/keys/:id",
+	"POST /api/v1/redeem",
+	"POST /api/v1/subscriptions/plans/:id/purchase",
+	"POST /api/v1/subscriptions/plans/:id/change",
+	"PUT /api/v1/subscriptions/auto-renew/:group_id",
+	"POST /api/v1/payments/orders",
+}
+
+// 模拟登录期间允许的非 GET 接口（只读查询或仅影响查看状态）
+var impersonationAllowedUserRoutes = []string{
+	"POST /api/v1/usage/dashboard/api-keys-usage",
+	"POST /api/v1/announcements/:id/read",
+}
+
+func TestRegisterUserRoutes_DenyImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	impersonate := middleware.JWTAuthMiddleware(func(c *gin.Context) {
+		c.Set(string(middleware.ContextKeyImpersonation), &service.ImpersonationSession{})
+		c.Next()
+	})
+	RegisterUserRoutes(r.Group("/api/v1"), &handler.Handlers{}, impersonate)
+
+	denied := make(map[string]bool, len(impersonationDeniedUserRoutes))
+	for _, route := range impersonationDeniedUserRoutes {
+		denied[route] = true
+	}
+	allowed := make(map[string]bool, len(impersonationAllowedUserRoutes))
+	for _, route := range impersonationAllowedUserRoutes {
+		allowed[route] = true
+	}
+
+	registered := make(map[string]bool)
+	for _, info := range r.Routes() {
+		if info.Method == http.MethodGet {
+			continue
+		}
+		route := info.Method + " " + info.Path
+		registered[route] = true
+		// 新增的写接口必须明确归类
+		require.True(t, denied[route] || allowed[route], "unclassified mutating route %s", route)
+		if !denied[route] {
+			continue
+		}
+
+		// 处理器为 nil：请求到达处理器会 panic，返回 403 说明在中间件处被拦截
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(info.Method, info.Path, nil))
+		require.Equal(t, http.StatusForbidden, w.Code, route)
+		require.Contains(t, w.Body.String(), "IMPERSONATION_FORBIDDEN", route)
+	}
+	for route := range denied {
+		require.True(t, registered[route], "route %s not registered", route)
+	}
+	for route := range allowed {
+		require.True(t, registered[route], "route %s not registered", route)
+	}
+}
@@ -50,6 +50,10 @@ type Account struct {
 	AccountGroups []AccountGroup
 	GroupIDs      []int64
 	Groups        []*Group
+
+	// scheduleRule 由 CacheScheduleRule 从 Extra 解析的账号级调度时间窗口，调度时直接读取
+	scheduleRule       *ScheduleRule
+	scheduleRuleCached bool
 }
 
 type TempUnschedulableRule struct {
@@ -94,6 +98,9 @@ func (a *Account) IsSchedulable() bool {
 	if a.TempUnschedulableUntil != nil && now.Before(*a.TempUnschedulableUntil) {
 		return false
 	}
+	if !a.IsWithinSchedule(now) {
+		return false
+	}
 	return true
 }
 
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// accountExtraModelConcurrencyKey 模型级并发上限（Extra 字段），格式: {"claude-opus-*": 2, "claude-haiku-*": 20}
+const accountExtraModelConcurrencyKey = "model_concurrency"
+
+var ErrModelConcurrencyInvalid = infraerrors.BadRequest("MODEL_CONCURRENCY_INVALID", "model_concurrency must map model patterns to positive integers")
+
+// ModelConcurrencyLimit 请求模型命中的模型级并发上限。
+// Pattern 为命中的配置项（支持末尾 * 通配），同一 Pattern 下的所有模型共享槽位。
+type ModelConcurrencyLimit struct {
+	Pattern        string
+	MaxConcurrency int
+}
+
+// IsZero 未配置模型级上限
+func (l ModelConcurrencyLimit) IsZero() bool {
+	return l.Pattern == "" || l.MaxConcurrency <= 0
+}
+
+// GetModelConcurrencyLimits 获取账号的模型级并发上限配置（pattern -> 上限），忽略无效项
+func (a *Account) GetModelConcurrencyLimits() map[string]int {
+	if a.Extra == nil {
+		return nil
+	}
+	raw, ok := a.Extra[accountExtraModelConcurrencyKey].(map[string]any)
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+	limits := make(map[string]int, len(raw))
+	for pattern, v := range raw {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if n := parseExtraInt(v); n > 0 {
+			limits[pattern] = n
+		}
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+	return limits
+}
+
+// GetModelConcurrencyLimit 返回请求模型命中的模型级并发上限（精确匹配优先，其次最长通配）
+func (a *Account) GetModelConcurrencyLimit(requestedModel string) ModelConcurrencyLimit {
+	if requestedModel == "" {
+		return ModelConcurrencyLimit{}
+	}
+	limits := a.GetModelConcurrencyLimits()
+	if len(limits) == 0 {
+		return ModelConcurrencyLimit{}
+	}
+	if n, ok := limits[requestedModel]; ok {
+		return ModelConcurrencyLimit{Pattern: requestedModel, MaxConcurrency: n}
+	}
+
+	patterns := make([]string, 0, len(limits))
+	for pattern := range limits {
+		if strings.HasSuffix(pattern, "*") && matchWildcard(pattern, requestedModel) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return ModelConcurrencyLimit{}
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	return ModelConcurrencyLimit{Pattern: patterns[0], MaxConcurrency: limits[patterns[0]]}
+}
+
+// validateAccountModelConcurrencyExtra 校验 Extra 中的模型级并发上限配置
+func validateAccountModelConcurrencyExtra(extra map[string]any) error {
+	if extra == nil {
+		return nil
+	}
+	raw, ok := extra[accountExtraModelConcurrencyKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	limits, ok := raw.(map[string]any)
+	if !ok {
+		return ErrModelConcurrencyInvalid
+	}
+	for pattern, v := range limits {
+		if strings.TrimSpace(pattern) == "" || parseExtraInt(v) <= 0 {
+			return ErrModelConcurrencyInvalid
+		}
+	}
+	return nil
+}
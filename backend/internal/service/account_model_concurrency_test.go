@@ -0,0 +1,103 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type modelSlotCacheStub struct {
+	ConcurrencyCache
+	accountSlots map[string]struct{}
+	modelSlots   map[string]int
+	modelFull    bool
+}
+
+func (s *modelSlotCacheStub) AcquireAccountSlot(_ context.Context, _ int64, _ int, requestID string) (bool, error) {
+	s.accountSlots[requestID] = struct{}{}
+	return true, nil
+}
+
+func (s *modelSlotCacheStub) ReleaseAccountSlot(_ context.Context, _ int64, requestID string) error {
+	delete(s.accountSlots, requestID)
+	return nil
+}
+
+func (s *modelSlotCacheStub) AcquireAccountModelSlot(_ context.Context, _ int64, pattern string, _ int, _ string) (bool, error) {
+	if s.modelFull {
+		return false, nil
+	}
+	s.modelSlots[pattern]++
+	return true, nil
+}
+
+func (s *modelSlotCacheStub) ReleaseAccountModelSlot(_ context.Context, _ int64, pattern string, _ string) error {
+	s.modelSlots[pattern]--
+	return nil
+}
+
+func TestAccount_GetModelConcurrencyLimit(t *testing.T) {
+	account := &Account{Extra: map[string]any{
+		accountExtraModelConcurrencyKey: map[string]any{
+			"claude-opus-*":     float64(2),
+			"claude-opus-4-5-*": float64(1),
+			"claude-haiku-4-5":  "20",
+			"gemini-*":          float64(0),
+		},
+	}}
+
+	require.Equal(t, ModelConcurrencyLimit{Pattern: "claude-opus-4-5-*", MaxConcurrency: 1}, account.GetModelConcurrencyLimit("claude-opus-4-5-20251101"))
+	require.Equal(t, ModelConcurrencyLimit{Pattern: "claude-opus-*", MaxConcurrency: 2}, account.GetModelConcurrencyLimit("claude-opus-4-1"))
+	require.Equal(t, ModelConcurrencyLimit{Pattern: "claude-haiku-4-5", MaxConcurrency: 20}, account.GetModelConcurrencyLimit("claude-haiku-4-5"))
+	require.True(t, account.GetModelConcurrencyLimit("gemini-2.5-pro").IsZero())
+	require.True(t, account.GetModelConcurrencyLimit("claude-sonnet-4-5").IsZero())
+	require.True(t, (&Account{}).GetModelConcurrencyLimit("claude-opus-4-1").IsZero())
+}
+
+func TestValidateAccountModelConcurrencyExtra(t *testing.T) {
+	require.NoError(t, validateAccountModelConcurrencyExtra(nil))
+	require.NoError(t, validateAccountModelConcurrencyExtra(map[string]any{
+		accountExtraModelConcurrencyKey: map[string]any{"claude-opus-*": float64(2)},
+	}))
+	require.ErrorIs(t, validateAccountModelConcurrencyExtra(map[string]any{
+		accountExtraModelConcurrencyKey: map[string]any{"claude-opus-*": float64(0)},
+	}), ErrModelConcurrencyInvalid)
+	require.ErrorIs(t, validateAccountModelConcurrencyExtra(map[string]any{
+		accountExtraModelConcurrencyKey: map[string]any{" ": float64(1)},
+	}), ErrModelConcurrencyInvalid)
+	require.ErrorIs(t, validateAccountModelConcurrencyExtra(map[string]any{
+		accountExtraModelConcurrencyKey: []any{"claude-opus-*"},
+	}), ErrModelConcurrencyInvalid)
+}
+
+func TestConcurrencyService_AcquireAccountSlotForModel(t *testing.T) {
+	cache := &modelSlotCacheStub{accountSlots: map[string]struct{}{}, modelSlots: map[string]int{}}
+	svc := NewConcurrencyService(cache)
+	limit := ModelConcurrencyLimit{Pattern: "claude-opus-*", MaxConcurrency: 1}
+
+	result, err := svc.AcquireAccountSlotForModel(context.Background(), 1, 5, limit)
+	require.NoError(t, err)
+	require.True(t, result.Acquired)
+	require.Len(t, cache.accountSlots, 1)
+	require.Equal(t, 1, cache.modelSlots["claude-opus-*"])
+
+	result.ReleaseFunc()
+	require.Empty(t, cache.accountSlots)
+	require.Equal(t, 0, cache.modelSlots["claude-opus-*"])
+
+	// 模型槽位已满时不应占用账号槽位
+	cache.modelFull = true
+	result, err = svc.AcquireAccountSlotForModel(context.Background(), 1, 5, limit)
+	require.NoError(t, err)
+	require.False(t, result.Acquired)
+	require.Empty(t, cache.accountSlots)
+
+	// 未命中模型级上限时只占用账号槽位
+	result, err = svc.AcquireAccountSlotForModel(context.Background(), 1, 5, ModelConcurrencyLimit{})
+	require.NoError(t, err)
+	require.True(t, result.Acquired)
+	require.Len(t, cache.accountSlots, 1)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	AccountProbeSourceScheduled = "scheduled"
+	AccountProbeSourceManual    = "manual"
+)
+
+// AccountProbeErrorPrefix marks account errors set by the prober so that only
+// those errors are cleared automatically when the account recovers.
+const AccountProbeErrorPrefix = "synthetic_probe_failed:"
+
+// AccountProbeResult 账号定时探测结果
+type AccountProbeResult struct {
+	ID           int64     `json:"id"`
+	AccountID    int64     `json:"account_id"`
+	Platform     string    `json:"platform"`
+	Model        string    `json:"model"`
+	Success      bool      `json:"success"`
+	LatencyMs    int64     `json:"latency_ms"`
+	FirstTokenMs *int64    `json:"first_token_ms,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AccountProbeSettings 账号定时探测配置（存储于 settings 表，JSON）
+type AccountProbeSettings struct {
+	Enabled bool `json:"enabled"`
+	// IntervalSeconds 每个账号的探测间隔
+	IntervalSeconds int `json:"interval_seconds"`
+	// TimeoutSeconds 单次探测超时
+	TimeoutSeconds int `json:"timeout_seconds"`
+	// MaxTokens 探测请求的最大输出 token（控制探测成本）
+	MaxTokens int `json:"max_tokens"`
+	// Concurrency 单轮探测的并发数
+	Concurrency int `json:"concurrency"`
+	// Platforms 仅探测指定平台，为空表示全部平台
+	Platforms []string `json:"platforms"`
+	// Models 按平台指定探测模型（platform -> model），未配置时使用平台默认测试模型
+	Models map[string]string `json:"models"`
+	// AutoMarkError 连续失败达到 FailureThreshold 次后将账号标记为 error
+	AutoMarkError    bool `json:"auto_mark_error"`
+	FailureThreshold int  `json:"failure_threshold"`
+	// AutoRecover 被探测标记为 error 的账号连续成功 RecoveryThreshold 次后自动恢复
+	AutoRecover       bool `json:"auto_recover"`
+	RecoveryThreshold int  `json:"recovery_threshold"`
+	// RetentionDays 探测历史保留天数
+	RetentionDays int `json:"retention_days"`
+}
+
+// DefaultAccountProbeSettings 返回默认的账号探测配置（默认关闭）
+func DefaultAccountProbeSettings() *AccountProbeSettings {
+	return &AccountProbeSettings{
+		Enabled:           false,
+		IntervalSeconds:   600,
+		TimeoutSeconds:    30,
+		MaxTokens:         16,
+		Concurrency:       4,
+		Platforms:         []string{},
+		Models:            map[string]string{},
+		AutoMarkError:     false,
+		FailureThreshold:  3,
+		AutoRecover:       true,
+		RecoveryThreshold: 1,
+		RetentionDays:     7,
+	}
+}
+
+// AccountProbeRepository 账号探测结果持久层接口
+type AccountProbeRepository interface {
+	InsertResult(ctx context.Context, result *AccountProbeResult) error
+	// ListResults 返回账号最近的探测记录（按时间倒序）
+	ListResults(ctx context.Context, accountID int64, limit int) ([]AccountProbeResult, error)
+	// GetLatestResults 返回每个账号最近一次探测结果；accountIDs 为空时返回全部账号
+	GetLatestResults(ctx context.Context, accountIDs []int64) (map[int64]*AccountProbeResult, error)
+	// ListProbeErroredAccountIDs 返回因探测失败被标记为 error 的账号
+	ListProbeErroredAccountIDs(ctx context.Context) ([]int64, error)
+	DeleteResultsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,559 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	accountProbeTickInterval   = time.Minute
+	accountProbeLeaderLockKey  = "account:probe:leader"
+	accountProbeLeaderLockTTL  = 10 * time.Minute
+	accountProbeCleanupEvery   = time.Hour
+	accountProbeErrorMaxLength = 500
+)
+
+var accountProbeReleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AccountProbeService 账号定时探测服务
+//
+// 周期性地向每个可用账号发送一次最小化请求（复用 AccountTestService.RunAccountTest 的测试链路），
+// 记录探测历史，并根据配置在连续失败时标记账号错误、在恢复后自动清除由探测设置的错误。
+//
+// - 调度：每分钟检查一次，按 interval_seconds 判断账号是否到期（以最近一次探测记录为准）
+// - 多实例：Redis leader 锁保证同一时间只有一个实例执行探测
+type AccountProbeService struct {
+	accountRepo        AccountRepository
+	probeRepo          AccountProbeRepository
+	settingRepo        SettingRepository
+	accountTestService *AccountTestService
+	redisClient        *redis.Client
+
+	instanceID string
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+
+	cleanupMu     sync.Mutex
+	lastCleanupAt time.Time
+
+	warnNoRedisOnce sync.Once
+}
+
+// NewAccountProbeService 创建账号探测服务
+func NewAccountProbeService(
+	accountRepo AccountRepository,
+	probeRepo AccountProbeRepository,
+	settingRepo SettingRepository,
+	accountTestService *AccountTestService,
+	redisClient *redis.Client,
+) *AccountProbeService {
+	return &AccountProbeService{
+		accountRepo:        accountRepo,
+		probeRepo:          probeRepo,
+		settingRepo:        settingRepo,
+		accountTestService: accountTestService,
+		redisClient:        redisClient,
+		instanceID:         uuid.NewString(),
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start 启动后台探测循环
+func (s *AccountProbeService) Start() {
+	if s == nil || s.probeRepo == nil || s.accountTestService == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台探测循环
+func (s *AccountProbeService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *AccountProbeService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(accountProbeTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// GetSettings 读取探测配置，未配置或解析失败时返回默认配置
+func (s *AccountProbeService) GetSettings(ctx context.Context) (*AccountProbeSettings, error) {
+	if s.settingRepo == nil {
+		return DefaultAccountProbeSettings(), nil
+	}
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyAccountProbeSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return DefaultAccountProbeSettings(), nil
+		}
+		return nil, fmt.Errorf("get account probe settings: %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return DefaultAccountProbeSettings(), nil
+	}
+
+	settings := DefaultAccountProbeSettings()
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return DefaultAccountProbeSettings(), nil
+	}
+	normalizeAccountProbeSettings(settings)
+	return settings, nil
+}
+
+// UpdateSettings 校验并保存探测配置
+func (s *AccountProbeService) UpdateSettings(ctx context.Context, settings *AccountProbeSettings) (*AccountProbeSettings, error) {
+	if settings == nil {
+		return nil, infraerrors.BadRequest("ACCOUNT_PROBE_INVALID_SETTINGS", "settings cannot be nil")
+	}
+	if err := validateAccountProbeSettings(settings); err != nil {
+		return nil, infraerrors.BadRequest("ACCOUNT_PROBE_INVALID_SETTINGS", err.Error())
+	}
+	normalizeAccountProbeSettings(settings)
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("marshal account probe settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyAccountProbeSettings, string(data)); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func validateAccountProbeSettings(settings *AccountProbeSettings) error {
+	if settings.IntervalSeconds < 60 || settings.IntervalSeconds > 86400 {
+		return errors.New("interval_seconds must be between 60-86400")
+	}
+	if settings.TimeoutSeconds < 5 || settings.TimeoutSeconds > 300 {
+		return errors.New("timeout_seconds must be between 5-300")
+	}
+	if settings.MaxTokens < 16 || settings.MaxTokens > 1024 {
+		return errors.New("max_tokens must be between 16-1024")
+	}
+	if settings.Concurrency < 1 || settings.Concurrency > 32 {
+		return errors.New("concurrency must be between 1-32")
+	}
+	if settings.FailureThreshold < 1 || settings.FailureThreshold > 20 {
+		return errors.New("failure_threshold must be between 1-20")
+	}
+	if settings.RecoveryThreshold < 1 || settings.RecoveryThreshold > 20 {
+		return errors.New("recovery_threshold must be between 1-20")
+	}
+	if settings.RetentionDays < 1 || settings.RetentionDays > 365 {
+		return errors.New("retention_days must be between 1-365")
+	}
+	for _, p := range settings.Platforms {
+		if !isValidAccountProbePlatform(p) {
+			return fmt.Errorf("invalid platform: %s", p)
+		}
+	}
+	for p := range settings.Models {
+		if !isValidAccountProbePlatform(p) {
+			return fmt.Errorf("invalid platform in models: %s", p)
+		}
+	}
+	return nil
+}
+
+func isValidAccountProbePlatform(platform string) bool {
+	switch strings.TrimSpace(platform) {
+	case PlatformAnthropic, PlatformOpenAI, PlatformGemini, PlatformAntigravity:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeAccountProbeSettings 修正越界值，保证后台任务始终拿到可用配置
+func normalizeAccountProbeSettings(settings *AccountProbeSettings) {
+	defaults := DefaultAccountProbeSettings()
+	if settings.IntervalSeconds < 60 {
+		settings.IntervalSeconds = defaults.IntervalSeconds
+	}
+	if settings.TimeoutSeconds < 5 || settings.TimeoutSeconds > 300 {
+		settings.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+	if settings.MaxTokens < 16 || settings.MaxTokens > 1024 {
+		settings.MaxTokens = defaults.MaxTokens
+	}
+	if settings.Concurrency < 1 || settings.Concurrency > 32 {
+		settings.Concurrency = defaults.Concurrency
+	}
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = defaults.FailureThreshold
+	}
+	if settings.RecoveryThreshold < 1 {
+		settings.RecoveryThreshold = defaults.RecoveryThreshold
+	}
+	if settings.RetentionDays < 1 {
+		settings.RetentionDays = defaults.RetentionDays
+	}
+	if settings.Platforms == nil {
+		settings.Platforms = []string{}
+	}
+	models := make(map[string]string, len(settings.Models))
+	for platform, model := range settings.Models {
+		platform = strings.TrimSpace(platform)
+		model = strings.TrimSpace(model)
+		if platform == "" || model == "" {
+			continue
+		}
+		models[platform] = model
+	}
+	settings.Models = models
+}
+
+// ListResults 返回账号最近的探测记录
+func (s *AccountProbeService) ListResults(ctx context.Context, accountID int64, limit int) ([]AccountProbeResult, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	return s.probeRepo.ListResults(ctx, accountID, limit)
+}
+
+// GetLatestResults 返回账号最近一次探测结果（用于账号列表展示）
+func (s *AccountProbeService) GetLatestResults(ctx context.Context, accountIDs []int64) (map[int64]*AccountProbeResult, error) {
+	return s.probeRepo.GetLatestResults(ctx, accountIDs)
+}
+
+// ProbeAccount 立即探测指定账号（管理员手动触发），结果同样写入历史并参与自动标记
+func (s *AccountProbeService) ProbeAccount(ctx context.Context, accountID int64) (*AccountProbeResult, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.probeAndRecord(ctx, account, settings, AccountProbeSourceManual), nil
+}
+
+func (s *AccountProbeService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), accountProbeLeaderLockTTL)
+	defer cancel()
+
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		log.Printf("[AccountProbe] load settings failed: %v", err)
+		return
+	}
+	if !settings.Enabled {
+		return
+	}
+
+	release, ok := s.tryAcquireLeaderLock(ctx)
+	if !ok {
+		return
+	}
+	if release != nil {
+		defer release()
+	}
+
+	accounts, err := s.listDueAccounts(ctx, settings, time.Now())
+	if err != nil {
+		log.Printf("[AccountProbe] list accounts failed: %v", err)
+		return
+	}
+
+	if len(accounts) > 0 {
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+			failed    int
+		)
+		sem := make(chan struct{}, settings.Concurrency)
+		for _, account := range accounts {
+			select {
+			case <-ctx.Done():
+			case sem <- struct{}{}:
+				wg.Add(1)
+				go func(account *Account) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					result := s.probeAndRecord(ctx, account, settings, AccountProbeSourceScheduled)
+					mu.Lock()
+					if result.Success {
+						succeeded++
+					} else {
+						failed++
+					}
+					mu.Unlock()
+				}(account)
+			}
+		}
+		wg.Wait()
+		log.Printf("[AccountProbe] probed %d accounts: success=%d failed=%d", len(accounts), succeeded, failed)
+	}
+
+	s.maybeCleanup(ctx, settings)
+}
+
+// listDueAccounts 返回本轮需要探测的账号：
+// - active 且可调度的账号（跳过限流/过载/临时不可调度，避免浪费额度）
+// - 因探测失败被标记为 error 的账号（用于自动恢复）
+func (s *AccountProbeService) listDueAccounts(ctx context.Context, settings *AccountProbeSettings, now time.Time) ([]*Account, error) {
+	active, err := s.accountRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	platforms := make(map[string]struct{}, len(settings.Platforms))
+	for _, p := range settings.Platforms {
+		platforms[strings.TrimSpace(p)] = struct{}{}
+	}
+	platformAllowed := func(platform string) bool {
+		if len(platforms) == 0 {
+			return true
+		}
+		_, ok := platforms[platform]
+		return ok
+	}
+
+	candidates := make([]*Account, 0, len(active))
+	seen := make(map[int64]struct{}, len(active))
+	for i := range active {
+		account := &active[i]
+		if !account.IsSchedulable() || !platformAllowed(account.Platform) {
+			continue
+		}
+		candidates = append(candidates, account)
+		seen[account.ID] = struct{}{}
+	}
+
+	if settings.AutoRecover {
+		erroredIDs, err := s.probeRepo.ListProbeErroredAccountIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(erroredIDs) > 0 {
+			errored, err := s.accountRepo.GetByIDs(ctx, erroredIDs)
+			if err != nil {
+				return nil, err
+			}
+			for _, account := range errored {
+				if account == nil || !platformAllowed(account.Platform) {
+					continue
+				}
+				if _, ok := seen[account.ID]; ok {
+					continue
+				}
+				candidates = append(candidates, account)
+				seen[account.ID] = struct{}{}
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, 0, len(candidates))
+	for _, account := range candidates {
+		ids = append(ids, account.ID)
+	}
+	latest, err := s.probeRepo.GetLatestResults(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(settings.IntervalSeconds) * time.Second
+	due := make([]*Account, 0, len(candidates))
+	for _, account := range candidates {
+		if last, ok := latest[account.ID]; ok && last != nil && now.Sub(last.CreatedAt) < interval {
+			continue
+		}
+		due = append(due, account)
+	}
+	return due, nil
+}
+
+// probeAndRecord 执行一次探测、写入历史并应用自动标记/恢复规则
+func (s *AccountProbeService) probeAndRecord(ctx context.Context, account *Account, settings *AccountProbeSettings, source string) *AccountProbeResult {
+	result := s.probe(ctx, account, settings)
+	result.Source = source
+
+	if err := s.probeRepo.InsertResult(ctx, result); err != nil {
+		log.Printf("[AccountProbe] save result failed: account=%d err=%v", account.ID, err)
+		return result
+	}
+
+	if result.Success {
+		s.maybeRecover(ctx, account, settings)
+	} else {
+		s.maybeMarkError(ctx, account, settings, result)
+	}
+	return result
+}
+
+func (s *AccountProbeService) probe(ctx context.Context, account *Account, settings *AccountProbeSettings) *AccountProbeResult {
+	model := settings.Models[account.Platform]
+
+	probeCtx, cancel := context.WithTimeout(ctx, time.Duration(settings.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	tested := s.accountTestService.RunAccountTest(probeCtx, account.ID, model, AccountTestOptions{MaxTokens: settings.MaxTokens}, nil)
+	result := &AccountProbeResult{
+		AccountID:    account.ID,
+		Platform:     account.Platform,
+		Model:        tested.Model,
+		Success:      tested.Success,
+		LatencyMs:    tested.LatencyMs,
+		FirstTokenMs: tested.FirstTokenMs,
+		CreatedAt:    time.Now(),
+	}
+	if result.Model == "" {
+		result.Model = model
+	}
+	if !result.Success {
+		result.ErrorMessage = truncateString(tested.ErrorMessage, accountProbeErrorMaxLength)
+	}
+	return result
+}
+
+func (s *AccountProbeService) maybeMarkError(ctx context.Context, account *Account, settings *AccountProbeSettings, result *AccountProbeResult) {
+	if !settings.AutoMarkError || account.Status != StatusActive {
+		return
+	}
+	recent, err := s.probeRepo.ListResults(ctx, account.ID, settings.FailureThreshold)
+	if err != nil {
+		log.Printf("[AccountProbe] list recent results failed: account=%d err=%v", account.ID, err)
+		return
+	}
+	if !allAccountProbeResults(recent, settings.FailureThreshold, false) {
+		return
+	}
+	msg := fmt.Sprintf("%s %d consecutive probe failures, last: %s", AccountProbeErrorPrefix, settings.FailureThreshold, result.ErrorMessage)
+	if err := s.accountRepo.SetError(ctx, account.ID, msg); err != nil {
+		log.Printf("[AccountProbe] set error failed: account=%d err=%v", account.ID, err)
+		return
+	}
+	log.Printf("[AccountProbe] account %d marked as error after %d consecutive failures", account.ID, settings.FailureThreshold)
+}
+
+func (s *AccountProbeService) maybeRecover(ctx context.Context, account *Account, settings *AccountProbeSettings) {
+	if !settings.AutoRecover || account.Status != StatusError || !strings.HasPrefix(account.ErrorMessage, AccountProbeErrorPrefix) {
+		return
+	}
+	recent, err := s.probeRepo.ListResults(ctx, account.ID, settings.RecoveryThreshold)
+	if err != nil {
+		log.Printf("[AccountProbe] list recent results failed: account=%d err=%v", account.ID, err)
+		return
+	}
+	if !allAccountProbeResults(recent, settings.RecoveryThreshold, true) {
+		return
+	}
+
+	// 与管理员手动清除错误保持一致：通过 Update 写回，触发调度快照同步
+	current, err := s.accountRepo.GetByID(ctx, account.ID)
+	if err != nil {
+		log.Printf("[AccountProbe] reload account failed: account=%d err=%v", account.ID, err)
+		return
+	}
+	if current.Status != StatusError || !strings.HasPrefix(current.ErrorMessage, AccountProbeErrorPrefix) {
+		return
+	}
+	current.Status = StatusActive
+	current.ErrorMessage = ""
+	if err := s.accountRepo.Update(ctx, current); err != nil {
+		log.Printf("[AccountProbe] clear error failed: account=%d err=%v", account.ID, err)
+		return
+	}
+	log.Printf("[AccountProbe] account %d recovered after %d successful probes", account.ID, settings.RecoveryThreshold)
+}
+
+// allAccountProbeResults 判断最近 n 次探测结果是否全部等于 success
+func allAccountProbeResults(results []AccountProbeResult, n int, success bool) bool {
+	if n <= 0 || len(results) < n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if results[i].Success != success {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *AccountProbeService) maybeCleanup(ctx context.Context, settings *AccountProbeSettings) {
+	s.cleanupMu.Lock()
+	if !s.lastCleanupAt.IsZero() && time.Since(s.lastCleanupAt) < accountProbeCleanupEvery {
+		s.cleanupMu.Unlock()
+		return
+	}
+	s.lastCleanupAt = time.Now()
+	s.cleanupMu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -settings.RetentionDays)
+	deleted, err := s.probeRepo.DeleteResultsBefore(ctx, cutoff)
+	if err != nil {
+		log.Printf("[AccountProbe] cleanup failed: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[AccountProbe] cleanup removed %d results older than %d days", deleted, settings.RetentionDays)
+	}
+}
+
+func (s *AccountProbeService) tryAcquireLeaderLock(ctx context.Context) (func(), bool) {
+	if s.redisClient == nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[AccountProbe] redis not configured; running without distributed lock")
+		})
+		return nil, true
+	}
+	ok, err := s.redisClient.SetNX(ctx, accountProbeLeaderLockKey, s.instanceID, accountProbeLeaderLockTTL).Result()
+	if err != nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[AccountProbe] leader lock SetNX failed; skipping this cycle: %v", err)
+		})
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	return func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, _ = accountProbeReleaseScript.Run(releaseCtx, s.redisClient, []string{accountProbeLeaderLockKey}, s.instanceID).Result()
+	}, true
+}
@@ -0,0 +1,142 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type accountTestRepoStub struct {
+	AccountRepository
+	account *Account
+}
+
+func (r *accountTestRepoStub) GetByID(_ context.Context, id int64) (*Account, error) {
+	if r.account == nil || r.account.ID != id {
+		return nil, ErrAccountNotFound
+	}
+	return r.account, nil
+}
+
+type accountTestUpstreamStub struct {
+	status int
+	body   string
+	req    []byte
+}
+
+func (u *accountTestUpstreamStub) Do(req *http.Request, _ string, _ int64, _ int) (*http.Response, error) {
+	u.req, _ = io.ReadAll(req.Body)
+	return &http.Response{
+		StatusCode: u.status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(u.body)),
+	}, nil
+}
+
+func (u *accountTestUpstreamStub) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, _ bool) (*http.Response, error) {
+	return u.Do(req, proxyURL, accountID, accountConcurrency)
+}
+
+func newAccountTestServiceForTest(upstream HTTPUpstream) *AccountTestService {
+	account := &Account{
+		ID:          1,
+		Platform:    PlatformAnthropic,
+		Type:        AccountTypeOAuth,
+		Status:      StatusActive,
+		Credentials: map[string]any{"access_token": "token"},
+	}
+	return NewAccountTestService(&accountTestRepoStub{account: account}, nil, nil, upstream, nil)
+}
+
+func TestRunAccountTest_CollectsStreamResult(t *testing.T) {
+	upstream := &accountTestUpstreamStub{
+		status: http.StatusOK,
+		body: "data: {\"type\":\"content_block_delta\",\"delta\":{\"text\":\"hi\"}}\n\n" +
+			"data: {\"type\":\"message_stop\"}\n\n",
+	}
+	svc := newAccountTestServiceForTest(upstream)
+
+	var events []string
+	result := svc.RunAccountTest(context.Background(), 1, "claude-haiku", AccountTestOptions{MaxTokens: 32}, func(event TestEvent) {
+		events = append(events, event.Type)
+	})
+
+	require.True(t, result.Success)
+	require.Equal(t, "claude-haiku", result.Model)
+	require.Empty(t, result.ErrorMessage)
+	require.NotNil(t, result.FirstTokenMs)
+	require.Equal(t, []string{"test_start", "content", "test_complete"}, events)
+	require.Equal(t, int64(32), gjson.GetBytes(upstream.req, "max_tokens").Int())
+}
+
+func TestRunAccountTest_ReportsUpstreamError(t *testing.T) {
+	svc := newAccountTestServiceForTest(&accountTestUpstreamStub{status: http.StatusUnauthorized, body: "invalid token"})
+
+	result := svc.RunAccountTest(context.Background(), 1, "", AccountTestOptions{}, nil)
+
+	require.False(t, result.Success)
+	require.Equal(t, "API returned 401: invalid token", result.ErrorMessage)
+	require.Nil(t, result.FirstTokenMs)
+}
+
+func TestRunAccountTest_AccountNotFound(t *testing.T) {
+	svc := newAccountTestServiceForTest(&accountTestUpstreamStub{})
+
+	result := svc.RunAccountTest(context.Background(), 2, "claude-haiku", AccountTestOptions{}, nil)
+
+	require.False(t, result.Success)
+	require.Equal(t, "Account not found", result.ErrorMessage)
+}
+
+func TestAllAccountProbeResults(t *testing.T) {
+	results := []AccountProbeResult{{Success: false}, {Success: false}, {Success: true}}
+
+	require.True(t, allAccountProbeResults(results, 2, false))
+	require.False(t, allAccountProbeResults(results, 3, false))
+	require.False(t, allAccountProbeResults(results, 4, false))
+	require.False(t, allAccountProbeResults(results, 0, false))
+	require.False(t, allAccountProbeResults(results, 1, true))
+}
+
+func TestValidateAccountProbeSettings(t *testing.T) {
+	settings := DefaultAccountProbeSettings()
+	require.NoError(t, validateAccountProbeSettings(settings))
+
+	settings.IntervalSeconds = 30
+	require.Error(t, validateAccountProbeSettings(settings))
+
+	settings = DefaultAccountProbeSettings()
+	settings.Platforms = []string{PlatformAnthropic, "unknown"}
+	require.Error(t, validateAccountProbeSettings(settings))
+
+	settings = DefaultAccountProbeSettings()
+	settings.Models = map[string]string{"unknown": "m"}
+	require.Error(t, validateAccountProbeSettings(settings))
+}
+
+func TestNormalizeAccountProbeSettings(t *testing.T) {
+	settings := &AccountProbeSettings{
+		IntervalSeconds: 10,
+		MaxTokens:       5000,
+		Models:          map[string]string{PlatformOpenAI: " gpt-5 ", PlatformGemini: " "},
+	}
+	normalizeAccountProbeSettings(settings)
+
+	defaults := DefaultAccountProbeSettings()
+	require.Equal(t, defaults.IntervalSeconds, settings.IntervalSeconds)
+	require.Equal(t, defaults.TimeoutSeconds, settings.TimeoutSeconds)
+	require.Equal(t, defaults.MaxTokens, settings.MaxTokens)
+	require.Equal(t, defaults.Concurrency, settings.Concurrency)
+	require.Equal(t, defaults.FailureThreshold, settings.FailureThreshold)
+	require.Equal(t, defaults.RecoveryThreshold, settings.RecoveryThreshold)
+	require.Equal(t, defaults.RetentionDays, settings.RetentionDays)
+	require.NotNil(t, settings.Platforms)
+	require.Equal(t, map[string]string{PlatformOpenAI: "gpt-5"}, settings.Models)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+)
+
+type ScheduleRule = domain.ScheduleRule
+type ScheduleWindow = domain.ScheduleWindow
+
+const (
+	// accountExtraScheduleRuleKey 账号调度时间窗口（Extra 字段）
+	accountExtraScheduleRuleKey = "schedule_rule"
+	// accountExtraStandbyKey 备用账号标记（Extra 字段）
+	accountExtraStandbyKey = "standby"
+)
+
+// GetScheduleRule 获取账号级调度时间窗口，未配置或配置无效时返回 nil
+//
+// 调度热路径上每个候选账号都会调用：已通过 CacheScheduleRule 缓存时直接返回，不再解析 Extra。
+func (a *Account) GetScheduleRule() *ScheduleRule {
+	if a.scheduleRuleCached {
+		return a.scheduleRule
+	}
+	return parseAccountScheduleRule(a.Extra)
+}
+
+// CacheScheduleRule 解析 Extra 中的调度时间窗口并缓存到账号上。
+// 加载账号（数据库 / 调度缓存）以及修改 Extra 后调用；未调用时 GetScheduleRule 每次按需解析。
+func (a *Account) CacheScheduleRule() {
+	a.scheduleRule = parseAccountScheduleRule(a.Extra)
+	a.scheduleRuleCached = true
+}
+
+func parseAccountScheduleRule(extra map[string]any) *ScheduleRule {
+	if extra == nil {
+		return nil
+	}
+	raw, ok := extra[accountExtraScheduleRuleKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	rule, err := parseScheduleRule(raw)
+	if err != nil || rule.IsEmpty() {
+		return nil
+	}
+	return rule
+}
+
+// IsStandby 检查是否为备用账号
+// 备用账号仅在分组内所有主账号都不可用时参与调度
+func (a *Account) IsStandby() bool {
+	if a.Extra == nil {
+		return false
+	}
+	if v, ok := a.Extra[accountExtraStandbyKey]; ok {
+		if enabled, ok := v.(bool); ok {
+			return enabled
+		}
+	}
+	return false
+}
+
+// IsWithinSchedule 检查账号级调度时间窗口是否允许在 now 调度
+func (a *Account) IsWithinSchedule(now time.Time) bool {
+	rule := a.GetScheduleRule()
+	return rule == nil || rule.Allows(now)
+}
+
+func parseScheduleRule(raw any) (*ScheduleRule, error) {
+	var rule ScheduleRule
+	switch v := raw.(type) {
+	case *ScheduleRule:
+		if v == nil {
+			return nil, nil
+		}
+		rule = *v
+	case ScheduleRule:
+		rule = v
+	default:
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return nil, err
+		}
+	}
+	return &rule, nil
+}
+
+// validateAccountScheduleExtra 校验并规范化 Extra 中的调度时间窗口配置
+func validateAccountScheduleExtra(extra map[string]any) error {
+	if extra == nil {
+		return nil
+	}
+	if v, ok := extra[accountExtraStandbyKey]; ok && v != nil {
+		if _, isBool := v.(bool); !isBool {
+			return domain.ErrScheduleRuleInvalid
+		}
+	}
+	raw, ok := extra[accountExtraScheduleRuleKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	rule, err := parseScheduleRule(raw)
+	if err != nil || rule == nil {
+		return domain.ErrScheduleRuleInvalid
+	}
+	normalized, err := rule.NormalizeAndValidate()
+	if err != nil {
+		return err
+	}
+	if normalized.IsEmpty() {
+		delete(extra, accountExtraScheduleRuleKey)
+		return nil
+	}
+	extra[accountExtraScheduleRuleKey] = normalized
+	return nil
+}
+
+// normalizeGroupScheduleRule 校验分组调度时间窗口，空规则返回 nil（表示清除）
+func normalizeGroupScheduleRule(rule *ScheduleRule) (*ScheduleRule, error) {
+	if rule == nil {
+		return nil, nil
+	}
+	normalized, err := rule.NormalizeAndValidate()
+	if err != nil {
+		return nil, err
+	}
+	if normalized.IsEmpty() {
+		return nil, nil
+	}
+	return &normalized, nil
+}
+
+// applyAccountScheduleRules 按调度时间窗口和备用账号规则过滤候选账号：
+//  1. 账号级窗口已在 IsSchedulable 中生效；未配置账号级窗口的账号按所在分组的窗口过滤
+//  2. 只要还有可用的主账号，就排除备用账号；主账号全部不可用时备用账号才参与调度
+//
+// isAvailable 判断主账号对当前请求是否可用（排除列表、限流、模型支持等）。
+func applyAccountScheduleRules(ctx context.Context, groupID *int64, accounts []Account, isAvailable func(*Account) bool) []Account {
+	if len(accounts) == 0 {
+		return accounts
+	}
+	now := time.Now()
+
+	var groupRule *ScheduleRule
+	if groupID != nil {
+		if group, ok := ctx.Value(ctxkey.Group).(*Group); ok && group != nil && group.ID == *groupID && !group.ScheduleRule.IsEmpty() {
+			groupRule = group.ScheduleRule
+		}
+	}
+	groupAllows := groupRule == nil || groupRule.Allows(now)
+
+	filtered := accounts[:0:0]
+	hasStandby := false
+	hasPrimary := false
+	for i := range accounts {
+		acc := &accounts[i]
+		if !groupAllows && acc.GetScheduleRule() == nil {
+			continue
+		}
+		filtered = append(filtered, *acc)
+		if acc.IsStandby() {
+			hasStandby = true
+			continue
+		}
+		if !hasPrimary && acc.IsSchedulable() && (isAvailable == nil || isAvailable(acc)) {
+			hasPrimary = true
+		}
+	}
+
+	if !hasStandby || !hasPrimary {
+		return filtered
+	}
+	primaries := filtered[:0:0]
+	for _, acc := range filtered {
+		if !acc.IsStandby() {
+			primaries = append(primaries, acc)
+		}
+	}
+	return primaries
+}
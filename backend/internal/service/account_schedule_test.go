@@ -0,0 +1,192 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/stretchr/testify/require"
+)
+
+func officeHoursRule(mode string) *ScheduleRule {
+	return &ScheduleRule{
+		Mode:     mode,
+		Timezone: "UTC",
+		Windows:  []ScheduleWindow{{Cron: "0 9 * * 1-5", DurationMinutes: 8 * 60}},
+	}
+}
+
+func TestScheduleRule_Allows(t *testing.T) {
+	// 2026-10-14 是周三，2026-10-17 是周六
+	wednesdayNoon := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	wednesdayStart := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	wednesdayEvening := time.Date(2026, 10, 14, 17, 30, 0, 0, time.UTC)
+	saturdayNoon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	allow := officeHoursRule(domain.ScheduleModeAllow)
+	require.True(t, allow.Allows(wednesdayNoon))
+	require.True(t, allow.Allows(wednesdayStart))
+	require.False(t, allow.Allows(wednesdayEvening))
+	require.False(t, allow.Allows(saturdayNoon))
+
+	deny := officeHoursRule(domain.ScheduleModeDeny)
+	require.False(t, deny.Allows(wednesdayNoon))
+	require.True(t, deny.Allows(wednesdayEvening))
+	require.True(t, deny.Allows(saturdayNoon))
+
+	var empty *ScheduleRule
+	require.True(t, empty.Allows(wednesdayNoon))
+	require.True(t, (&ScheduleRule{Mode: domain.ScheduleModeAllow}).Allows(wednesdayNoon))
+}
+
+func TestScheduleRule_RespectsTimezone(t *testing.T) {
+	rule := &ScheduleRule{
+		Timezone: "Asia/Shanghai",
+		Windows:  []ScheduleWindow{{Cron: "0 9 * * *", DurationMinutes: 60}},
+	}
+	// 上海 09:30 = UTC 01:30
+	require.True(t, rule.Allows(time.Date(2026, 10, 14, 1, 30, 0, 0, time.UTC)))
+	require.False(t, rule.Allows(time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)))
+}
+
+func TestScheduleRule_NormalizeAndValidate(t *testing.T) {
+	normalized, err := ScheduleRule{
+		Mode:    " DENY ",
+		Windows: []ScheduleWindow{{Cron: " @daily ", DurationMinutes: 30}},
+	}.NormalizeAndValidate()
+	require.NoError(t, err)
+	require.Equal(t, domain.ScheduleModeDeny, normalized.Mode)
+	require.Equal(t, "@daily", normalized.Windows[0].Cron)
+
+	normalized, err = ScheduleRule{}.NormalizeAndValidate()
+	require.NoError(t, err)
+	require.Equal(t, domain.ScheduleModeAllow, normalized.Mode)
+	require.True(t, normalized.IsEmpty())
+
+	invalid := []ScheduleRule{
+		{Mode: "sometimes"},
+		{Timezone: "Mars/Olympus"},
+		{Windows: []ScheduleWindow{{Cron: "not a cron", DurationMinutes: 10}}},
+		{Windows: []ScheduleWindow{{Cron: "0 9 * * *", DurationMinutes: 0}}},
+		{Windows: []ScheduleWindow{{Cron: "0 9 * * *", DurationMinutes: 8 * 24 * 60}}},
+	}
+	for _, rule := range invalid {
+		_, err := rule.NormalizeAndValidate()
+		require.ErrorIs(t, err, domain.ErrScheduleRuleInvalid)
+	}
+}
+
+func TestAccount_CacheScheduleRule(t *testing.T) {
+	// 从数据库 / 调度缓存加载后 Extra 中是 JSON 解码出的 map
+	account := &Account{Extra: map[string]any{
+		accountExtraScheduleRuleKey: map[string]any{
+			"mode":     "allow",
+			"timezone": "UTC",
+			"windows":  []any{map[string]any{"cron": "0 9 * * 1-5", "duration_minutes": float64(8 * 60)}},
+		},
+	}}
+	require.Equal(t, officeHoursRule(domain.ScheduleModeAllow), account.GetScheduleRule())
+
+	account.CacheScheduleRule()
+	require.Equal(t, officeHoursRule(domain.ScheduleModeAllow), account.GetScheduleRule())
+	// 缓存后调度检查不再解析 Extra
+	require.Zero(t, testing.AllocsPerRun(100, func() { _ = account.GetScheduleRule() }))
+
+	// 修改 Extra 后重新缓存
+	delete(account.Extra, accountExtraScheduleRuleKey)
+	account.CacheScheduleRule()
+	require.Nil(t, account.GetScheduleRule())
+	require.True(t, account.IsWithinSchedule(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestValidateAccountScheduleExtra(t *testing.T) {
+	extra := map[string]any{
+		accountExtraScheduleRuleKey: map[string]any{
+			"windows": []any{map[string]any{"cron": "0 9 * * *", "duration_minutes": 60}},
+		},
+		accountExtraStandbyKey: true,
+	}
+	require.NoError(t, validateAccountScheduleExtra(extra))
+	rule, ok := extra[accountExtraScheduleRuleKey].(ScheduleRule)
+	require.True(t, ok)
+	require.Equal(t, domain.ScheduleModeAllow, rule.Mode)
+
+	extra = map[string]any{accountExtraScheduleRuleKey: map[string]any{"windows": []any{}}}
+	require.NoError(t, validateAccountScheduleExtra(extra))
+	require.NotContains(t, extra, accountExtraScheduleRuleKey)
+
+	require.Error(t, validateAccountScheduleExtra(map[string]any{accountExtraStandbyKey: "yes"}))
+	require.Error(t, validateAccountScheduleExtra(map[string]any{accountExtraScheduleRuleKey: "0 9 * * *"}))
+	require.NoError(t, validateAccountScheduleExtra(nil))
+}
+
+func TestAccount_IsSchedulableRespectsScheduleRule(t *testing.T) {
+	account := &Account{
+		Status:      StatusActive,
+		Schedulable: true,
+		Extra: map[string]any{
+			accountExtraScheduleRuleKey: map[string]any{
+				"mode":    domain.ScheduleModeDeny,
+				"windows": []any{map[string]any{"cron": "* * * * *", "duration_minutes": 5}},
+			},
+		},
+	}
+	require.False(t, account.IsSchedulable())
+
+	account.Extra[accountExtraScheduleRuleKey] = map[string]any{
+		"mode":    domain.ScheduleModeAllow,
+		"windows": []any{map[string]any{"cron": "* * * * *", "duration_minutes": 5}},
+	}
+	require.True(t, account.IsSchedulable())
+}
+
+func TestApplyAccountScheduleRules_Standby(t *testing.T) {
+	primary := Account{ID: 1, Status: StatusActive, Schedulable: true}
+	standby := Account{ID: 2, Status: StatusActive, Schedulable: true, Extra: map[string]any{accountExtraStandbyKey: true}}
+	accounts := []Account{primary, standby}
+
+	got := applyAccountScheduleRules(context.Background(), nil, accounts, nil)
+	require.Len(t, got, 1)
+	require.Equal(t, int64(1), got[0].ID)
+
+	// 主账号不可用（例如已被排除）时启用备用账号
+	got = applyAccountScheduleRules(context.Background(), nil, accounts, func(acc *Account) bool { return acc.ID != 1 })
+	require.Len(t, got, 2)
+
+	// 仅剩备用账号
+	got = applyAccountScheduleRules(context.Background(), nil, []Account{standby}, nil)
+	require.Len(t, got, 1)
+	require.Equal(t, int64(2), got[0].ID)
+}
+
+func TestApplyAccountScheduleRules_GroupRule(t *testing.T) {
+	groupID := int64(10)
+	closed := &ScheduleRule{
+		Mode:    domain.ScheduleModeDeny,
+		Windows: []ScheduleWindow{{Cron: "* * * * *", DurationMinutes: 5}},
+	}
+	open := &ScheduleRule{
+		Mode:    domain.ScheduleModeAllow,
+		Windows: []ScheduleWindow{{Cron: "* * * * *", DurationMinutes: 5}},
+	}
+	inherits := Account{ID: 1, Status: StatusActive, Schedulable: true}
+	ownRule := Account{ID: 2, Status: StatusActive, Schedulable: true, Extra: map[string]any{accountExtraScheduleRuleKey: open}}
+
+	ctx := context.WithValue(context.Background(), ctxkey.Group, &Group{ID: groupID, ScheduleRule: closed})
+	got := applyAccountScheduleRules(ctx, &groupID, []Account{inherits, ownRule}, nil)
+	require.Len(t, got, 1)
+	require.Equal(t, int64(2), got[0].ID)
+
+	// context 中的分组与请求分组不一致时不应用分组规则
+	otherGroupID := int64(11)
+	got = applyAccountScheduleRules(ctx, &otherGroupID, []Account{inherits, ownRule}, nil)
+	require.Len(t, got, 2)
+
+	ctx = context.WithValue(context.Background(), ctxkey.Group, &Group{ID: groupID, ScheduleRule: open})
+	got = applyAccountScheduleRules(ctx, &groupID, []Account{inherits, ownRule}, nil)
+	require.Len(t, got, 2)
+}
@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/claude"
@@ -42,6 +43,22 @@ type TestEvent struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// AccountTestOptions controls how a connection test is run
+type AccountTestOptions struct {
+	// MaxTokens caps the test response size, 0 keeps the default payload.
+	// The admin UI leaves it unset, background probes set a small budget.
+	MaxTokens int
+}
+
+// AccountTestResult is the outcome of a connection test
+type AccountTestResult struct {
+	Model        string
+	Success      bool
+	ErrorMessage string
+	FirstTokenMs *int64
+	LatencyMs    int64
+}
+
 // AccountTestService handles account testing operations
 type AccountTestService struct {
 	accountRepo               AccountRepository
@@ -138,38 +155,63 @@ func createTestPayload(modelID string) (map[string]any, error) {
 	}, nil
 }
 
-// TestAccountConnection tests an account's connection by sending a test request
-// All account types use full Claude Code client characteristics, only auth header differs
-// modelID is optional - if empty, defaults to claude.DefaultTestModel
+// TestAccountConnection tests an account's connection and streams progress to the client as SSE events
 func (s *AccountTestService) TestAccountConnection(c *gin.Context, accountID int64, modelID string) error {
-	ctx := c.Request.Context()
+	headerSent := false
+	result := s.RunAccountTest(c.Request.Context(), accountID, modelID, AccountTestOptions{}, func(event TestEvent) {
+		if !headerSent {
+			headerSent = true
+			c.Writer.Header().Set("Content-Type", "text/event-stream")
+			c.Writer.Header().Set("Cache-Control", "no-cache")
+			c.Writer.Header().Set("Connection", "keep-alive")
+			c.Writer.Header().Set("X-Accel-Buffering", "no")
+		}
+		s.sendEvent(c, event)
+	})
+	if !result.Success {
+		return errors.New(result.ErrorMessage)
+	}
+	return nil
+}
+
+// RunAccountTest tests an account's connection by sending a test request and returns a structured result.
+// All account types use full Claude Code client characteristics, only auth header differs.
+// modelID is optional - if empty, defaults to the platform test model.
+// onEvent is optional and receives progress events as they happen.
+func (s *AccountTestService) RunAccountTest(ctx context.Context, accountID int64, modelID string, opts AccountTestOptions, onEvent func(TestEvent)) *AccountTestResult {
+	run := &accountTestRun{
+		startedAt: time.Now(),
+		maxTokens: opts.MaxTokens,
+		onEvent:   onEvent,
+		result:    &AccountTestResult{Model: modelID},
+	}
 
 	// Get account
 	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
-		return s.sendErrorAndEnd(c, "Account not found")
+		_ = run.fail("Account not found")
+		return run.finish()
 	}
 
 	// Route to platform-specific test method
-	if account.IsOpenAI() {
-		return s.testOpenAIAccountConnection(c, account, modelID)
+	switch {
+	case account.IsOpenAI():
+		err = s.testOpenAIAccountConnection(ctx, run, account, modelID)
+	case account.IsGemini():
+		err = s.testGeminiAccountConnection(ctx, run, account, modelID)
+	case account.Platform == PlatformAntigravity:
+		err = s.testAntigravityAccountConnection(ctx, run, account, modelID)
+	default:
+		err = s.testClaudeAccountConnection(ctx, run, account, modelID)
 	}
-
-	if account.IsGemini() {
-		return s.testGeminiAccountConnection(c, account, modelID)
+	if err != nil && run.result.ErrorMessage == "" {
+		run.result.ErrorMessage = err.Error()
 	}
-
-	if account.Platform == PlatformAntigravity {
-		return s.testAntigravityAccountConnection(c, account, modelID)
-	}
-
-	return s.testClaudeAccountConnection(c, account, modelID)
+	return run.finish()
 }
 
 // testClaudeAccountConnection tests an Anthropic Claude account's connection
-func (s *AccountTestService) testClaudeAccountConnection(c *gin.Context, account *Account, modelID string) error {
-	ctx := c.Request.Context()
-
+func (s *AccountTestService) testClaudeAccountConnection(ctx context.Context, run *accountTestRun, account *Account, modelID string) error {
 	// Determine the model to use
 	testModelID := modelID
 	if testModelID == "" {
@@ -197,14 +239,14 @@ func (s *AccountTestService) testClaudeAccountConnection(c *gin.Context, account
 		apiURL = testClaudeAPIURL
 		authToken = account.GetCredential("access_token")
 		if authToken == "" {
-			return s.sendErrorAndEnd(c, "No access token available")
+			return run.fail("No access token available")
 		}
 	} else if account.Type == "apikey" {
 		// API Key - use x-api-key header
 		useBearer = false
 		authToken = account.GetCredential("api_key")
 		if authToken == "" {
-			return s.sendErrorAndEnd(c, "No API key available")
+			return run.fail("No API key available")
 		}
 
 		baseURL := account.GetBaseURL()
@@ -213,33 +255,29 @@ func (s *AccountTestService) testClaudeAccountConnection(c *gin.Context, account
 		}
 		normalizedBaseURL, err := s.validateUpstreamBaseURL(baseURL)
 		if err != nil {
-			return s.sendErrorAndEnd(c, fmt.Sprintf("Invalid base URL: %s", err.Error()))
+			return run.fail(fmt.Sprintf("Invalid base URL: %s", err.Error()))
 		}
 		apiURL = strings.TrimSuffix(normalizedBaseURL, "/") + "/v1/messages"
 	} else {
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Unsupported account type: %s", account.Type))
+		return run.fail(fmt.Sprintf("Unsupported account type: %s", account.Type))
 	}
 
-	// Set SSE headers
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("X-Accel-Buffering", "no")
-	c.Writer.Flush()
-
 	// Create Claude Code style payload (same for all account types)
 	payload, err := createTestPayload(testModelID)
 	if err != nil {
-		return s.sendErrorAndEnd(c, "Failed to create test payload")
+		return run.fail("Failed to create test payload")
+	}
+	if maxTokens := run.maxTokens; maxTokens > 0 {
+		payload["max_tokens"] = maxTokens
 	}
 	payloadBytes, _ := json.Marshal(payload)
 
 	// Send test_start event
-	s.sendEvent(c, TestEvent{Type: "test_start", Model: testModelID})
+	run.emit(TestEvent{Type: "test_start", Model: testModelID})
 
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(payloadBytes))
 	if err != nil {
-		return s.sendErrorAndEnd(c, "Failed to create request")
+		return run.fail("Failed to create request")
 	}
 
 	// Set common headers
@@ -268,23 +306,21 @@ func (s *AccountTestService) testClaudeAccountConnection(c *gin.Context, account
 
 	resp, err := s.httpUpstream.DoWithTLS(req, proxyURL, account.ID, account.Concurrency, account.IsTLSFingerprintEnabled())
 	if err != nil {
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Request failed: %s", err.Error()))
+		return run.fail(fmt.Sprintf("Request failed: %s", err.Error()))
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return s.sendErrorAndEnd(c, fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
+		return run.fail(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Process SSE stream
-	return s.processClaudeStream(c, resp.Body)
+	return s.processClaudeStream(run, resp.Body)
 }
 
 // testOpenAIAccountConnection tests an OpenAI account's connection
-func (s *AccountTestService) testOpenAIAccountConnection(c *gin.Context, account *Account, modelID string) error {
-	ctx := c.Request.Context()
-
+func (s *AccountTestService) testOpenAIAccountConnection(ctx context.Context, run *accountTestRun, account *Account, modelID string) error {
 	// Default to openai.DefaultTestModel for OpenAI testing
 	testModelID := modelID
 	if testModelID == "" {
@@ -312,7 +348,7 @@ func (s *AccountTestService) testOpenAIAccountConnection(c *gin.Context, account
 		// OAuth - use Bearer token with ChatGPT internal API
 		authToken = account.GetOpenAIAccessToken()
 		if authToken == "" {
-			return s.sendErrorAndEnd(c, "No access token available")
+			return run.fail("No access token available")
 		}
 
 		// OAuth uses ChatGPT internal API
@@ -322,7 +358,7 @@ func (s *AccountTestService) testOpenAIAccountConnection(c *gin.Context, account
 		// API Key - use Platform API
 		authToken = account.GetOpenAIApiKey()
 		if authToken == "" {
-			return s.sendErrorAndEnd(c, "No API key available")
+			return run.fail("No API key available")
 		}
 
 		baseURL := account.GetOpenAIBaseURL()
@@ -331,30 +367,27 @@ func (s *AccountTestService) testOpenAIAccountConnection(c *gin.Context, account
 		}
 		normalizedBaseURL, err := s.validateUpstreamBaseURL(baseURL)
 		if err != nil {
-			return s.sendErrorAndEnd(c, fmt.Sprintf("Invalid base URL: %s", err.Error()))
+			return run.fail(fmt.Sprintf("Invalid base URL: %s", err.Error()))
 		}
 		apiURL = strings.TrimSuffix(normalizedBaseURL, "/") + "/responses"
 	} else {
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Unsupported account type: %s", account.Type))
+		return run.fail(fmt.Sprintf("Unsupported account type: %s", account.Type))
 	}
 
-	// Set SSE headers
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("X-Accel-Buffering", "no")
-	c.Writer.Flush()
-
 	// Create OpenAI Responses API payload
 	payload := createOpenAITestPayload(testModelID, isOAuth)
+	// ChatGPT internal API rejects max_output_tokens, only API Key accounts honor the budget.
+	if maxTokens := run.maxTokens; maxTokens > 0 && !isOAuth {
+		payload["max_output_tokens"] = maxTokens
+	}
 	payloadBytes, _ := json.Marshal(payload)
 
 	// Send test_start event
-	s.sendEvent(c, TestEvent{Type: "test_start", Model: testModelID})
+	run.emit(TestEvent{Type: "test_start", Model: testModelID})
 
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(payloadBytes))
 	if err != nil {
-		return s.sendErrorAndEnd(c, "Failed to create request")
+		return run.fail("Failed to create request")
 	}
 
 	// Set common headers
@@ -378,23 +411,21 @@ func (s *AccountTestService) testOpenAIAccountConnection(c *gin.Context, account
 
 	resp, err := s.httpUpstream.DoWithTLS(req, proxyURL, account.ID, account.Concurrency, account.IsTLSFingerprintEnabled())
 	if err != nil {
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Request failed: %s", err.Error()))
+		return run.fail(fmt.Sprintf("Request failed: %s", err.Error()))
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return s.sendErrorAndEnd(c, fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
+		return run.fail(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Process SSE stream
-	return s.processOpenAIStream(c, resp.Body)
+	return s.processOpenAIStream(run, resp.Body)
 }
 
 // testGeminiAccountConnection tests a Gemini account's connection
-func (s *AccountTestService) testGeminiAccountConnection(c *gin.Context, account *Account, modelID string) error {
-	ctx := c.Request.Context()
-
+func (s *AccountTestService) testGeminiAccountConnection(ctx context.Context, run *accountTestRun, account *Account, modelID string) error {
 	// Determine the model to use
 	testModelID := modelID
 	if testModelID == "" {
@@ -411,15 +442,11 @@ func (s *AccountTestService) testGeminiAccountConnection(c *gin.Context, account
 		}
 	}
 
-	// Set SSE headers
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("X-Accel-Buffering", "no")
-	c.Writer.Flush()
-
 	// Create test payload (Gemini format)
 	payload := createGeminiTestPayload()
+	if maxTokens := run.maxTokens; maxTokens > 0 {
+		payload = withGeminiMaxOutputTokens(payload, maxTokens)
+	}
 
 	// Build request based on account type
 	var req *http.Request
@@ -431,15 +458,15 @@ func (s *AccountTestService) testGeminiAccountConnection(c *gin.Context, account
 	case AccountTypeOAuth:
 		req, err = s.buildGeminiOAuthRequest(ctx, account, testModelID, payload)
 	default:
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Unsupported account type: %s", account.Type))
+		return run.fail(fmt.Sprintf("Unsupported account type: %s", account.Type))
 	}
 
 	if err != nil {
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Failed to build request: %s", err.Error()))
+		return run.fail(fmt.Sprintf("Failed to build request: %s", err.Error()))
 	}
 
 	// Send test_start event
-	s.sendEvent(c, TestEvent{Type: "test_start", Model: testModelID})
+	run.emit(TestEvent{Type: "test_start", Model: testModelID})
 
 	// Get proxy and execute request
 	proxyURL := ""
@@ -449,24 +476,22 @@ func (s *AccountTestService) testGeminiAccountConnection(c *gin.Context, account
 
 	resp, err := s.httpUpstream.DoWithTLS(req, proxyURL, account.ID, account.Concurrency, account.IsTLSFingerprintEnabled())
 	if err != nil {
-		return s.sendErrorAndEnd(c, fmt.Sprintf("Request failed: %s", err.Error()))
+		return run.fail(fmt.Sprintf("Request failed: %s", err.Error()))
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return s.sendErrorAndEnd(c, fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
+		return run.fail(fmt.Sprintf("API returned %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Process SSE stream
-	return s.processGeminiStream(c, resp.Body)
+	return s.processGeminiStream(run, resp.Body)
 }
 
 // testAntigravityAccountConnection tests an Antigravity account's connection
 // 支持 Claude 和 Gemini 两种协议，使用非流式请求
-func (s *AccountTestService) testAntigravityAccountConnection(c *gin.Context, account *Account, modelID string) error {
-	ctx := c.Request.Context()
-
+func (s *AccountTestService) testAntigravityAccountConnection(ctx context.Context, run *accountTestRun, account *Account, modelID string) error {
 	// 默认模型：Claude 使用 claude-sonnet-4-5，Gemini 使用 gemini-3-pro-preview
 	testModelID := modelID
 	if testModelID == "" {
@@ -474,31 +499,24 @@ func (s *AccountTestService) testAntigravityAccountConnection(c *gin.Context, ac
 	}
 
 	if s.antigravityGatewayService == nil {
-		return s.sendErrorAndEnd(c, "Antigravity gateway service not configured")
+		return run.fail("Antigravity gateway service not configured")
 	}
 
-	// Set SSE headers
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("X-Accel-Buffering", "no")
-	c.Writer.Flush()
-
 	// Send test_start event
-	s.sendEvent(c, TestEvent{Type: "test_start", Model: testModelID})
+	run.emit(TestEvent{Type: "test_start", Model: testModelID})
 
 	// 调用 AntigravityGatewayService.TestConnection（复用协议转换逻辑）
 	result, err := s.antigravityGatewayService.TestConnection(ctx, account, testModelID)
 	if err != nil {
-		return s.sendErrorAndEnd(c, err.Error())
+		return run.fail(err.Error())
 	}
 
 	// 发送响应内容
 	if result.Text != "" {
-		s.sendEvent(c, TestEvent{Type: "content", Text: result.Text})
+		run.emit(TestEvent{Type: "content", Text: result.Text})
 	}
 
-	s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+	run.emit(TestEvent{Type: "test_complete", Success: true})
 	return nil
 }
 
@@ -624,18 +642,32 @@ func createGeminiTestPayload() []byte {
 	return bytes
 }
 
+// withGeminiMaxOutputTokens sets generationConfig.maxOutputTokens on a Gemini payload
+func withGeminiMaxOutputTokens(payload []byte, maxTokens int) []byte {
+	var body map[string]any
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return payload
+	}
+	body["generationConfig"] = map[string]any{"maxOutputTokens": maxTokens}
+	out, err := json.Marshal(body)
+	if err != nil {
+		return payload
+	}
+	return out
+}
+
 // processGeminiStream processes SSE stream from Gemini API
-func (s *AccountTestService) processGeminiStream(c *gin.Context, body io.Reader) error {
+func (s *AccountTestService) processGeminiStream(run *accountTestRun, body io.Reader) error {
 	reader := bufio.NewReader(body)
 
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
 			if err == io.EOF {
-				s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+				run.emit(TestEvent{Type: "test_complete", Success: true})
 				return nil
 			}
-			return s.sendErrorAndEnd(c, fmt.Sprintf("Stream read error: %s", err.Error()))
+			return run.fail(fmt.Sprintf("Stream read error: %s", err.Error()))
 		}
 
 		line = strings.TrimSpace(line)
@@ -645,7 +677,7 @@ func (s *AccountTestService) processGeminiStream(c *gin.Context, body io.Reader)
 
 		jsonStr := strings.TrimPrefix(line, "data: ")
 		if jsonStr == "[DONE]" {
-			s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+			run.emit(TestEvent{Type: "test_complete", Success: true})
 			return nil
 		}
 
@@ -668,7 +700,7 @@ func (s *AccountTestService) processGeminiStream(c *gin.Context, body io.Reader)
 						for _, part := range parts {
 							if partMap, ok := part.(map[string]any); ok {
 								if text, ok := partMap["text"].(string); ok && text != "" {
-									s.sendEvent(c, TestEvent{Type: "content", Text: text})
+									run.emit(TestEvent{Type: "content", Text: text})
 								}
 							}
 						}
@@ -677,7 +709,7 @@ func (s *AccountTestService) processGeminiStream(c *gin.Context, body io.Reader)
 
 				// Check for completion after extracting content
 				if finishReason, ok := candidate["finishReason"].(string); ok && finishReason != "" {
-					s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+					run.emit(TestEvent{Type: "test_complete", Success: true})
 					return nil
 				}
 			}
@@ -689,7 +721,7 @@ func (s *AccountTestService) processGeminiStream(c *gin.Context, body io.Reader)
 			if msg, ok := errData["message"].(string); ok {
 				errorMsg = msg
 			}
-			return s.sendErrorAndEnd(c, errorMsg)
+			return run.fail(errorMsg)
 		}
 	}
 }
@@ -724,17 +756,17 @@ func createOpenAITestPayload(modelID string, isOAuth bool) map[string]any {
 }
 
 // processClaudeStream processes the SSE stream from Claude API
-func (s *AccountTestService) processClaudeStream(c *gin.Context, body io.Reader) error {
+func (s *AccountTestService) processClaudeStream(run *accountTestRun, body io.Reader) error {
 	reader := bufio.NewReader(body)
 
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
 			if err == io.EOF {
-				s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+				run.emit(TestEvent{Type: "test_complete", Success: true})
 				return nil
 			}
-			return s.sendErrorAndEnd(c, fmt.Sprintf("Stream read error: %s", err.Error()))
+			return run.fail(fmt.Sprintf("Stream read error: %s", err.Error()))
 		}
 
 		line = strings.TrimSpace(line)
@@ -744,7 +776,7 @@ func (s *AccountTestService) processClaudeStream(c *gin.Context, body io.Reader)
 
 		jsonStr := sseDataPrefix.ReplaceAllString(line, "")
 		if jsonStr == "[DONE]" {
-			s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+			run.emit(TestEvent{Type: "test_complete", Success: true})
 			return nil
 		}
 
@@ -759,11 +791,11 @@ func (s *AccountTestService) processClaudeStream(c *gin.Context, body io.Reader)
 		case "content_block_delta":
 			if delta, ok := data["delta"].(map[string]any); ok {
 				if text, ok := delta["text"].(string); ok {
-					s.sendEvent(c, TestEvent{Type: "content", Text: text})
+					run.emit(TestEvent{Type: "content", Text: text})
 				}
 			}
 		case "message_stop":
-			s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+			run.emit(TestEvent{Type: "test_complete", Success: true})
 			return nil
 		case "error":
 			errorMsg := "Unknown error"
@@ -772,23 +804,23 @@ func (s *AccountTestService) processClaudeStream(c *gin.Context, body io.Reader)
 					errorMsg = msg
 				}
 			}
-			return s.sendErrorAndEnd(c, errorMsg)
+			return run.fail(errorMsg)
 		}
 	}
 }
 
 // processOpenAIStream processes the SSE stream from OpenAI Responses API
-func (s *AccountTestService) processOpenAIStream(c *gin.Context, body io.Reader) error {
+func (s *AccountTestService) processOpenAIStream(run *accountTestRun, body io.Reader) error {
 	reader := bufio.NewReader(body)
 
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
 			if err == io.EOF {
-				s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+				run.emit(TestEvent{Type: "test_complete", Success: true})
 				return nil
 			}
-			return s.sendErrorAndEnd(c, fmt.Sprintf("Stream read error: %s", err.Error()))
+			return run.fail(fmt.Sprintf("Stream read error: %s", err.Error()))
 		}
 
 		line = strings.TrimSpace(line)
@@ -798,7 +830,7 @@ func (s *AccountTestService) processOpenAIStream(c *gin.Context, body io.Reader)
 
 		jsonStr := sseDataPrefix.ReplaceAllString(line, "")
 		if jsonStr == "[DONE]" {
-			s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+			run.emit(TestEvent{Type: "test_complete", Success: true})
 			return nil
 		}
 
@@ -813,10 +845,10 @@ func (s *AccountTestService) processOpenAIStream(c *gin.Context, body io.Reader)
 		case "response.output_text.delta":
 			// OpenAI Responses API uses "delta" field for text content
 			if delta, ok := data["delta"].(string); ok && delta != "" {
-				s.sendEvent(c, TestEvent{Type: "content", Text: delta})
+				run.emit(TestEvent{Type: "content", Text: delta})
 			}
 		case "response.completed":
-			s.sendEvent(c, TestEvent{Type: "test_complete", Success: true})
+			run.emit(TestEvent{Type: "test_complete", Success: true})
 			return nil
 		case "error":
 			errorMsg := "Unknown error"
@@ -825,11 +857,55 @@ func (s *AccountTestService) processOpenAIStream(c *gin.Context, body io.Reader)
 					errorMsg = msg
 				}
 			}
-			return s.sendErrorAndEnd(c, errorMsg)
+			return run.fail(errorMsg)
 		}
 	}
 }
 
+// accountTestRun collects the events of a single test into an AccountTestResult
+type accountTestRun struct {
+	startedAt time.Time
+	maxTokens int
+	onEvent   func(TestEvent)
+	result    *AccountTestResult
+}
+
+// emit records the event in the result and forwards it to the listener
+func (r *accountTestRun) emit(event TestEvent) {
+	switch event.Type {
+	case "test_start":
+		r.result.Model = event.Model
+	case "content":
+		if r.result.FirstTokenMs == nil {
+			ms := time.Since(r.startedAt).Milliseconds()
+			r.result.FirstTokenMs = &ms
+		}
+	case "test_complete":
+		r.result.Success = event.Success
+	case "error":
+		r.result.Success = false
+		r.result.ErrorMessage = event.Error
+	}
+	if r.onEvent != nil {
+		r.onEvent(event)
+	}
+}
+
+// fail emits an error event and ends the test
+func (r *accountTestRun) fail(errorMsg string) error {
+	log.Printf("Account test error: %s", errorMsg)
+	r.emit(TestEvent{Type: "error", Error: errorMsg})
+	return errors.New(errorMsg)
+}
+
+func (r *accountTestRun) finish() *AccountTestResult {
+	r.result.LatencyMs = time.Since(r.startedAt).Milliseconds()
+	if !r.result.Success && r.result.ErrorMessage == "" {
+		r.result.ErrorMessage = "test finished without completion event"
+	}
+	return r.result
+}
+
 // sendEvent sends a SSE event to the client
 func (s *AccountTestService) sendEvent(c *gin.Context, event TestEvent) {
 	eventJSON, _ := json.Marshal(event)
@@ -839,10 +915,3 @@ func (s *AccountTestService) sendEvent(c *gin.Context, event TestEvent) {
 	}
 	c.Writer.Flush()
 }
-
-// sendErrorAndEnd sends an error event and ends the stream
-func (s *AccountTestService) sendErrorAndEnd(c *gin.Context, errorMsg string) error {
-	log.Printf("Account test error: %s", errorMsg)
-	s.sendEvent(c, TestEvent{Type: "error", Error: errorMsg})
-	return fmt.Errorf("%s", errorMsg)
-}
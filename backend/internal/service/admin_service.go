@@ -135,6 +135,12 @@ type CreateGroupInput struct {
 	MCPXMLInject                 *bool
 	// 支持的模型系列（仅 antigravity 平台使用）
 	SupportedModelScopes []string
+	// 对冲请求配置
+	HedgeEnabled  bool
+	HedgeDelayMs  *int
+	HedgeMaxRatio *float64
+	// 调度时间窗口（nil 或无窗口表示不限制）
+	ScheduleRule *ScheduleRule
 	// 从指定分组复制账号（创建分组后在同一事务内绑定）
 	CopyAccountsFromGroupIDs []int64
 }
@@ -166,6 +172,12 @@ type UpdateGroupInput struct {
 	MCPXMLInject                   *bool
 	// 支持的模型系列（仅 antigravity 平台使用）
 	SupportedModelScopes *[]string
+	// 对冲请求配置
+	HedgeEnabled  *bool
+	HedgeDelayMs  *int
+	HedgeMaxRatio *float64
+	// 调度时间窗口：nil 表示不修改，windows 为空表示清除
+	ScheduleRule *ScheduleRule
 	// 从指定分组复制账号（同步操作：先清空当前分组的账号绑定，再绑定源分组的账号）
 	CopyAccountsFromGroupIDs []int64
 }
@@ -693,6 +705,20 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 		cacheTransferProbability = 1.0
 	}
 
+	hedgeDelayMs := DefaultHedgeDelayMs
+	if input.HedgeDelayMs != nil {
+		hedgeDelayMs = *input.HedgeDelayMs
+	}
+	hedgeMaxRatio := DefaultHedgeMaxRatio
+	if input.HedgeMaxRatio != nil {
+		hedgeMaxRatio = *input.HedgeMaxRatio
+	}
+
+	scheduleRule, err := normalizeGroupScheduleRule(input.ScheduleRule)
+	if err != nil {
+		return nil, err
+	}
+
 	// 如果指定了复制账号的源分组，先获取账号 ID 列表
 	var accountIDsToCopy []int64
 	if len(input.CopyAccountsFromGroupIDs) > 0 {
@@ -747,6 +773,10 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 		CacheReadTransferProbability:    cacheTransferProbability,
 		MCPXMLInject:                    mcpXMLInject,
 		SupportedModelScopes:            input.SupportedModelScopes,
+		HedgeEnabled:                    input.HedgeEnabled,
+		HedgeDelayMs:                    hedgeDelayMs,
+		HedgeMaxRatio:                   hedgeMaxRatio,
+		ScheduleRule:                    scheduleRule,
 	}
 	if err := s.groupRepo.Create(ctx, group); err != nil {
 		return nil, err
@@ -954,6 +984,26 @@ func (s *adminServiceImpl) UpdateGroup(ctx context.Context, id int64, input *Upd
 		group.CacheReadTransferProbability = *input.CacheReadTransferProbability
 	}
 
+	// 对冲请求配置
+	if input.HedgeEnabled != nil {
+		group.HedgeEnabled = *input.HedgeEnabled
+	}
+	if input.HedgeDelayMs != nil {
+		group.HedgeDelayMs = *input.HedgeDelayMs
+	}
+	if input.HedgeMaxRatio != nil {
+		group.HedgeMaxRatio = *input.HedgeMaxRatio
+	}
+
+	// 调度时间窗口
+	if input.ScheduleRule != nil {
+		scheduleRule, err := normalizeGroupScheduleRule(input.ScheduleRule)
+		if err != nil {
+			return nil, err
+		}
+		group.ScheduleRule = scheduleRule
+	}
+
 	if err := s.groupRepo.Update(ctx, group); err != nil {
 		return nil, err
 	}
@@ -1112,6 +1162,14 @@ func (s *adminServiceImpl) CreateAccount(ctx context.Context, input *CreateAccou
 		}
 	}
 
+	// 校验调度时间窗口 / 备用账号 / 模型级并发配置
+	if err := validateAccountScheduleExtra(input.Extra); err != nil {
+		return nil, err
+	}
+	if err := validateAccountModelConcurrencyExtra(input.Extra); err != nil {
+		return nil, err
+	}
+
 	account := &Account{
 		Name:        input.Name,
 		Notes:       normalizeAccountNotes(input.Notes),
@@ -1173,7 +1231,14 @@ func (s *adminServiceImpl) UpdateAccount(ctx context.Context, id int64, input *U
 		account.Credentials = input.Credentials
 	}
 	if len(input.Extra) > 0 {
+		if err := validateAccountScheduleExtra(input.Extra); err != nil {
+			return nil, err
+		}
+		if err := validateAccountModelConcurrencyExtra(input.Extra); err != nil {
+			return nil, err
+		}
 		account.Extra = input.Extra
+		account.CacheScheduleRule()
 	}
 	if input.ProxyID != nil {
 		// 0 表示清除代理（前端发送 0 而不是 null 来表达清除意图）
@@ -1276,6 +1341,12 @@ func (s *adminServiceImpl) BulkUpdateAccounts(ctx context.Context, input *BulkUp
 			return nil, errors.New("rate_multiplier must be >= 0")
 		}
 	}
+	if err := validateAccountScheduleExtra(input.Extra); err != nil {
+		return nil, err
+	}
+	if err := validateAccountModelConcurrencyExtra(input.Extra); err != nil {
+		return nil, err
+	}
 
 	// Prepare bulk updates for columns and JSONB fields.
 	repoUpdates := AccountBulkUpdate{
@@ -8,6 +8,8 @@ const (
 	StatusAPIKeyDisabled       = "disabled"
 	StatusAPIKeyQuotaExhausted = "quota_exhausted"
 	StatusAPIKeyExpired        = "expired"
+	// StatusAPIKeySuspended 因共享/滥用检测被停用，仅管理员可恢复
+	StatusAPIKeySuspended = "suspended"
 )
 
 type APIKey struct {
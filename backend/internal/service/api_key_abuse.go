@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// API Key 滥用标记状态
+const (
+	APIKeyAbuseFlagOpen      = "open"
+	APIKeyAbuseFlagDismissed = "dismissed"
+	APIKeyAbuseFlagResolved  = "resolved"
+)
+
+// API Key 滥用标记上已执行的处置
+const (
+	APIKeyAbuseActionNone        = "none"
+	APIKeyAbuseActionRateLimited = "rate_limited"
+	APIKeyAbuseActionSuspended   = "suspended"
+)
+
+// 自动处置模式（配置项 auto_action）
+const (
+	APIKeyAbuseAutoActionNone      = "none"
+	APIKeyAbuseAutoActionRateLimit = "rate_limit"
+	APIKeyAbuseAutoActionSuspend   = "suspend"
+)
+
+// 管理员对标记执行的操作
+const (
+	APIKeyAbuseReviewDismiss   = "dismiss"
+	APIKeyAbuseReviewRateLimit = "rate_limit"
+	APIKeyAbuseReviewSuspend   = "suspend"
+	APIKeyAbuseReviewRestore   = "restore"
+)
+
+// 可疑分各维度权重（合计 100）
+const (
+	apiKeyAbuseWeightIPs         = 35.0
+	apiKeyAbuseWeightNetworks    = 25.0
+	apiKeyAbuseWeightConcurrency = 20.0
+	apiKeyAbuseWeightUserAgents  = 10.0
+	apiKeyAbuseWeightActiveHours = 10.0
+
+	// apiKeyAbuseActiveRatioFloor 活跃小时占比低于该值不计分（单人使用很少全天候持续请求）
+	apiKeyAbuseActiveRatioFloor = 0.6
+)
+
+// APIKeyUsageStats 时间窗口内单个 API Key 的使用特征（由 usage_logs 聚合）
+type APIKeyUsageStats struct {
+	APIKeyID           int64 `json:"api_key_id"`
+	UserID             int64 `json:"user_id"`
+	RequestCount       int64 `json:"request_count"`
+	DistinctIPs        int   `json:"distinct_ips"`
+	DistinctNetworks   int   `json:"distinct_networks"`
+	PeakConcurrentIPs  int   `json:"peak_concurrent_ips"`
+	DistinctUserAgents int   `json:"distinct_user_agents"`
+	ActiveHours        int   `json:"active_hours"`
+}
+
+// APIKeyAbuseScore 可疑分及各维度得分
+type APIKeyAbuseScore struct {
+	Total       float64 `json:"total"`
+	IPs         float64 `json:"ips"`
+	Networks    float64 `json:"networks"`
+	Concurrency float64 `json:"concurrency"`
+	UserAgents  float64 `json:"user_agents"`
+	ActiveHours float64 `json:"active_hours"`
+}
+
+// APIKeyAbuseFlag 可疑 API Key 标记
+type APIKeyAbuseFlag struct {
+	ID          int64 `json:"id"`
+	APIKeyID    int64 `json:"api_key_id"`
+	UserID      int64 `json:"user_id"`
+	WindowHours int   `json:"window_hours"`
+
+	RequestCount       int64 `json:"request_count"`
+	DistinctIPs        int   `json:"distinct_ips"`
+	DistinctNetworks   int   `json:"distinct_networks"`
+	PeakConcurrentIPs  int   `json:"peak_concurrent_ips"`
+	DistinctUserAgents int   `json:"distinct_user_agents"`
+	ActiveHours        int   `json:"active_hours"`
+
+	Score     float64           `json:"score"`
+	Breakdown *APIKeyAbuseScore `json:"breakdown,omitempty"`
+
+	Status          string     `json:"status"`
+	Action          string     `json:"action"`
+	RateLimitRPM    *int       `json:"rate_limit_rpm,omitempty"`
+	AutoActioned    bool       `json:"auto_actioned"`
+	OwnerNotifiedAt *time.Time `json:"owner_notified_at,omitempty"`
+
+	FirstDetectedAt time.Time `json:"first_detected_at"`
+	LastDetectedAt  time.Time `json:"last_detected_at"`
+
+	ReviewedBy *int64     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 关联信息（列表展示用）
+	APIKeyName   string `json:"api_key_name"`
+	APIKeyStatus string `json:"api_key_status"`
+	UserEmail    string `json:"user_email"`
+}
+
+// APIKeyAbuseFlagFilter 标记列表筛选条件
+type APIKeyAbuseFlagFilter struct {
+	Status   string
+	UserID   int64
+	APIKeyID int64
+}
+
+// APIKeyAbuseSource 来源聚合（IP / User-Agent）
+type APIKeyAbuseSource struct {
+	Value        string    `json:"value"`
+	RequestCount int64     `json:"request_count"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+}
+
+// APIKeyAbuseFlagDetail 标记详情（附带窗口内的主要来源）
+type APIKeyAbuseFlagDetail struct {
+	*APIKeyAbuseFlag
+	TopIPs        []APIKeyAbuseSource `json:"top_ips"`
+	TopUserAgents []APIKeyAbuseSource `json:"top_user_agents"`
+}
+
+// APIKeyAbuseReviewRequest 管理员处置请求
+type APIKeyAbuseReviewRequest struct {
+	Action       string `json:"action"`
+	Note         string `json:"note"`
+	RateLimitRPM int    `json:"rate_limit_rpm"`
+}
+
+// APIKeyAbuseScanResult 单次扫描结果
+type APIKeyAbuseScanResult struct {
+	Scanned  int `json:"scanned"`
+	Flagged  int `json:"flagged"`
+	Actioned int `json:"actioned"`
+}
+
+// APIKeyAbuseSettings API Key 共享/滥用检测配置（存储于 settings 表，JSON）
+type APIKeyAbuseSettings struct {
+	Enabled bool `json:"enabled"`
+	// ScanIntervalMinutes 扫描间隔
+	ScanIntervalMinutes int `json:"scan_interval_minutes"`
+	// WindowHours 统计窗口
+	WindowHours int `json:"window_hours"`
+	// MinRequests 窗口内请求数低于该值的 Key 不参与评分
+	MinRequests int `json:"min_requests"`
+
+	// 各维度达到满分的阈值
+	MaxDistinctIPs   int `json:"max_distinct_ips"`
+	MaxNetworks      int `json:"max_networks"`
+	MaxConcurrentIPs int `json:"max_concurrent_ips"`
+	MaxUserAgents    int `json:"max_user_agents"`
+
+	// FlagScore 可疑分达到该值时生成标记
+	FlagScore float64 `json:"flag_score"`
+	// AutoAction 可疑分达到 ActionScore 时的自动处置：none / rate_limit / suspend
+	AutoAction   string  `json:"auto_action"`
+	ActionScore  float64 `json:"action_score"`
+	RateLimitRPM int     `json:"rate_limit_rpm"`
+	// NotifyOwner 自动处置后邮件通知 Key 所有者
+	NotifyOwner bool `json:"notify_owner"`
+	// DismissSuppressDays 管理员忽略标记后，该 Key 在此期间内不再被标记
+	DismissSuppressDays int `json:"dismiss_suppress_days"`
+}
+
+// DefaultAPIKeyAbuseSettings 返回默认配置（默认关闭，开启后默认仅标记不处置）
+func DefaultAPIKeyAbuseSettings() *APIKeyAbuseSettings {
+	return &APIKeyAbuseSettings{
+		Enabled:             false,
+		ScanIntervalMinutes: 15,
+		WindowHours:         24,
+		MinRequests:         50,
+		MaxDistinctIPs:      20,
+		MaxNetworks:         8,
+		MaxConcurrentIPs:    5,
+		MaxUserAgents:       6,
+		FlagScore:           60,
+		AutoAction:          APIKeyAbuseAutoActionNone,
+		ActionScore:         85,
+		RateLimitRPM:        10,
+		NotifyOwner:         true,
+		DismissSuppressDays: 7,
+	}
+}
+
+// APIKeyAbuseRepository API Key 滥用检测持久层接口
+type APIKeyAbuseRepository interface {
+	// ScanUsageStats 聚合 [start, end) 内请求数不少于 minRequests 且来自多个 IP 的 Key
+	ScanUsageStats(ctx context.Context, start, end time.Time, minRequests int) ([]APIKeyUsageStats, error)
+	// ListTopSources 返回 Key 在 [start, end) 内请求最多的 IP 与 User-Agent
+	ListTopSources(ctx context.Context, apiKeyID int64, start, end time.Time, limit int) (ips []APIKeyAbuseSource, userAgents []APIKeyAbuseSource, err error)
+
+	// UpsertOpenFlag 创建或刷新 Key 的 open 标记，返回最新记录
+	UpsertOpenFlag(ctx context.Context, flag *APIKeyAbuseFlag) (*APIKeyAbuseFlag, error)
+	// ListSuppressedKeyIDs 返回 since 之后被忽略过的 Key
+	ListSuppressedKeyIDs(ctx context.Context, since time.Time) ([]int64, error)
+	GetFlagByID(ctx context.Context, id int64) (*APIKeyAbuseFlag, error)
+	ListFlags(ctx context.Context, params pagination.PaginationParams, filter APIKeyAbuseFlagFilter) ([]APIKeyAbuseFlag, *pagination.PaginationResult, error)
+	// UpdateFlagAction 记录对标记执行的处置（限流 / 停用）
+	UpdateFlagAction(ctx context.Context, id int64, action string, rateLimitRPM *int, auto bool) error
+	// ReviewFlag 管理员审核标记（忽略 / 恢复 / 处置），同时写入审核人与备注
+	ReviewFlag(ctx context.Context, id int64, status, action string, rateLimitRPM *int, reviewerID *int64, note string) error
+	MarkOwnerNotified(ctx context.Context, id int64, at time.Time) error
+	// ListRateLimitedKeys 返回当前处于限流处置中的 Key（api_key_id -> rpm）
+	ListRateLimitedKeys(ctx context.Context) (map[int64]int, error)
+}
+
+// APIKeyAbuseLimiter 对被限流处置的 Key 执行每分钟请求上限
+type APIKeyAbuseLimiter interface {
+	AllowRequest(ctx context.Context, apiKeyID int64) bool
+}
+
+// scoreAPIKeyUsage 根据使用特征计算 0-100 的可疑分。
+//
+// 每个维度按 (x-1)/(threshold-1) 线性计分并截断到 [0,1]：单一来源不计分，达到阈值记满分。
+// 活跃小时以窗口占比计，超过 60% 后线性增长（多人共享的 Key 往往全天候有请求）。
+func scoreAPIKeyUsage(stats *APIKeyUsageStats, settings *APIKeyAbuseSettings, windowHours int) APIKeyAbuseScore {
+	var score APIKeyAbuseScore
+	if stats == nil || settings == nil {
+		return score
+	}
+	score.IPs = apiKeyAbuseWeightIPs * apiKeyAbuseRatio(stats.DistinctIPs, settings.MaxDistinctIPs)
+	score.Networks = apiKeyAbuseWeightNetworks * apiKeyAbuseRatio(stats.DistinctNetworks, settings.MaxNetworks)
+	score.Concurrency = apiKeyAbuseWeightConcurrency * apiKeyAbuseRatio(stats.PeakConcurrentIPs, settings.MaxConcurrentIPs)
+	score.UserAgents = apiKeyAbuseWeightUserAgents * apiKeyAbuseRatio(stats.DistinctUserAgents, settings.MaxUserAgents)
+	if windowHours > 0 {
+		activeRatio := float64(stats.ActiveHours) / float64(windowHours)
+		score.ActiveHours = apiKeyAbuseWeightActiveHours * clampFloat64((activeRatio-apiKeyAbuseActiveRatioFloor)/(1-apiKeyAbuseActiveRatioFloor), 0, 1)
+	}
+	total := score.IPs + score.Networks + score.Concurrency + score.UserAgents + score.ActiveHours
+	score.Total = math.Round(total*10) / 10
+	return score
+}
+
+func apiKeyAbuseRatio(value, threshold int) float64 {
+	if threshold <= 1 {
+		return 0
+	}
+	return clampFloat64(float64(value-1)/float64(threshold-1), 0, 1)
+}
@@ -0,0 +1,671 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	apiKeyAbuseTickInterval   = time.Minute
+	apiKeyAbuseScanSlotKey    = "api_key:abuse:scan"
+	apiKeyAbuseScanTimeout    = 10 * time.Minute
+	apiKeyAbuseLimitKeyPrefix = "api_key:abuse:rpm:"
+	// apiKeyAbuseLimitRefresh 限流名单快照的刷新间隔（其他实例上的处置最迟在该间隔后生效）
+	apiKeyAbuseLimitRefresh = 30 * time.Second
+	apiKeyAbuseTopSources   = 20
+	apiKeyAbuseMaxRPM       = 10000
+)
+
+var (
+	ErrAPIKeyAbuseFlagNotFound = infraerrors.NotFound("API_KEY_ABUSE_FLAG_NOT_FOUND", "abuse flag not found")
+	ErrAPIKeyAbuseFlagClosed   = infraerrors.Conflict("API_KEY_ABUSE_FLAG_CLOSED", "abuse flag has already been closed")
+)
+
+// apiKeyAbuseLimitSnapshot 当前被限流的 Key（api_key_id -> rpm）
+type apiKeyAbuseLimitSnapshot struct {
+	rpm      map[int64]int
+	loadedAt time.Time
+}
+
+// APIKeyAbuseService API Key 共享 / 滥用检测服务
+//
+// 周期性地从 usage_logs 聚合每个 Key 在统计窗口内的来源 IP 数、网段数（近似地理分布）、
+// 5 分钟内并发 IP 数、User-Agent 数与活跃小时数，计算可疑分并生成待管理员处理的标记；
+// 可疑分超过处置阈值时可按配置自动限流或停用 Key，并邮件通知 Key 所有者。
+//
+// - 调度：每分钟检查一次，Redis 扫描槽位（TTL = 扫描间隔）保证多实例下每个间隔只扫描一次
+// - 限流：被限流的 Key 名单缓存在内存中定期刷新，计数基于 Redis，Redis 不可用时放行
+// - 停用：使用独立状态 suspended，用户无法自行重新启用，只能由管理员恢复
+type APIKeyAbuseService struct {
+	abuseRepo     APIKeyAbuseRepository
+	apiKeyRepo    APIKeyRepository
+	userRepo      UserRepository
+	settingRepo   SettingRepository
+	apiKeyService *APIKeyService
+	emailService  *EmailService
+	redisClient   *redis.Client
+
+	instanceID string
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+
+	scanMu     sync.Mutex
+	lastScanAt time.Time
+
+	limits           atomic.Pointer[apiKeyAbuseLimitSnapshot]
+	limitsRefreshing atomic.Bool
+
+	warnNoRedisOnce sync.Once
+}
+
+// NewAPIKeyAbuseService 创建 API Key 滥用检测服务
+func NewAPIKeyAbuseService(
+	abuseRepo APIKeyAbuseRepository,
+	apiKeyRepo APIKeyRepository,
+	userRepo UserRepository,
+	settingRepo SettingRepository,
+	apiKeyService *APIKeyService,
+	emailService *EmailService,
+	redisClient *redis.Client,
+) *APIKeyAbuseService {
+	return &APIKeyAbuseService{
+		abuseRepo:     abuseRepo,
+		apiKeyRepo:    apiKeyRepo,
+		userRepo:      userRepo,
+		settingRepo:   settingRepo,
+		apiKeyService: apiKeyService,
+		emailService:  emailService,
+		redisClient:   redisClient,
+		instanceID:    uuid.NewString(),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start 启动后台扫描循环
+func (s *APIKeyAbuseService) Start() {
+	if s == nil || s.abuseRepo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台扫描循环
+func (s *APIKeyAbuseService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *APIKeyAbuseService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(apiKeyAbuseTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// GetSettings 读取检测配置，未配置或解析失败时返回默认配置
+func (s *APIKeyAbuseService) GetSettings(ctx context.Context) (*APIKeyAbuseSettings, error) {
+	if s.settingRepo == nil {
+		return DefaultAPIKeyAbuseSettings(), nil
+	}
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyAPIKeyAbuseSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return DefaultAPIKeyAbuseSettings(), nil
+		}
+		return nil, fmt.Errorf("get api key abuse settings: %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return DefaultAPIKeyAbuseSettings(), nil
+	}
+
+	settings := DefaultAPIKeyAbuseSettings()
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return DefaultAPIKeyAbuseSettings(), nil
+	}
+	normalizeAPIKeyAbuseSettings(settings)
+	return settings, nil
+}
+
+// UpdateSettings 校验并保存检测配置
+func (s *APIKeyAbuseService) UpdateSettings(ctx context.Context, settings *APIKeyAbuseSettings) (*APIKeyAbuseSettings, error) {
+	if settings == nil {
+		return nil, infraerrors.BadRequest("API_KEY_ABUSE_INVALID_SETTINGS", "settings cannot be nil")
+	}
+	settings.AutoAction = strings.TrimSpace(settings.AutoAction)
+	if err := validateAPIKeyAbuseSettings(settings); err != nil {
+		return nil, infraerrors.BadRequest("API_KEY_ABUSE_INVALID_SETTINGS", err.Error())
+	}
+	normalizeAPIKeyAbuseSettings(settings)
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("marshal api key abuse settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyAPIKeyAbuseSettings, string(data)); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func validateAPIKeyAbuseSettings(settings *APIKeyAbuseSettings) error {
+	if settings.ScanIntervalMinutes < 5 || settings.ScanIntervalMinutes > 1440 {
+		return errors.New("scan_interval_minutes must be between 5-1440")
+	}
+	if settings.WindowHours < 1 || settings.WindowHours > 168 {
+		return errors.New("window_hours must be between 1-168")
+	}
+	if settings.MinRequests < 1 || settings.MinRequests > 1000000 {
+		return errors.New("min_requests must be between 1-1000000")
+	}
+	for name, v := range map[string]int{
+		"max_distinct_ips":   settings.MaxDistinctIPs,
+		"max_networks":       settings.MaxNetworks,
+		"max_concurrent_ips": settings.MaxConcurrentIPs,
+		"max_user_agents":    settings.MaxUserAgents,
+	} {
+		if v < 2 || v > 10000 {
+			return fmt.Errorf("%s must be between 2-10000", name)
+		}
+	}
+	if settings.FlagScore < 1 || settings.FlagScore > 100 {
+		return errors.New("flag_score must be between 1-100")
+	}
+	switch settings.AutoAction {
+	case APIKeyAbuseAutoActionNone, APIKeyAbuseAutoActionRateLimit, APIKeyAbuseAutoActionSuspend:
+	default:
+		return fmt.Errorf("invalid auto_action: %s", settings.AutoAction)
+	}
+	if settings.ActionScore < settings.FlagScore || settings.ActionScore > 100 {
+		return errors.New("action_score must be between flag_score and 100")
+	}
+	if settings.RateLimitRPM < 1 || settings.RateLimitRPM > apiKeyAbuseMaxRPM {
+		return fmt.Errorf("rate_limit_rpm must be between 1-%d", apiKeyAbuseMaxRPM)
+	}
+	if settings.DismissSuppressDays < 0 || settings.DismissSuppressDays > 365 {
+		return errors.New("dismiss_suppress_days must be between 0-365")
+	}
+	return nil
+}
+
+// normalizeAPIKeyAbuseSettings 修正越界值，保证后台任务始终拿到可用配置
+func normalizeAPIKeyAbuseSettings(settings *APIKeyAbuseSettings) {
+	defaults := DefaultAPIKeyAbuseSettings()
+	if settings.ScanIntervalMinutes < 5 {
+		settings.ScanIntervalMinutes = defaults.ScanIntervalMinutes
+	}
+	if settings.WindowHours < 1 || settings.WindowHours > 168 {
+		settings.WindowHours = defaults.WindowHours
+	}
+	if settings.MinRequests < 1 {
+		settings.MinRequests = defaults.MinRequests
+	}
+	if settings.MaxDistinctIPs < 2 {
+		settings.MaxDistinctIPs = defaults.MaxDistinctIPs
+	}
+	if settings.MaxNetworks < 2 {
+		settings.MaxNetworks = defaults.MaxNetworks
+	}
+	if settings.MaxConcurrentIPs < 2 {
+		settings.MaxConcurrentIPs = defaults.MaxConcurrentIPs
+	}
+	if settings.MaxUserAgents < 2 {
+		settings.MaxUserAgents = defaults.MaxUserAgents
+	}
+	if settings.FlagScore <= 0 || settings.FlagScore > 100 {
+		settings.FlagScore = defaults.FlagScore
+	}
+	if settings.ActionScore < settings.FlagScore || settings.ActionScore > 100 {
+		settings.ActionScore = max(defaults.ActionScore, settings.FlagScore)
+	}
+	switch settings.AutoAction {
+	case APIKeyAbuseAutoActionNone, APIKeyAbuseAutoActionRateLimit, APIKeyAbuseAutoActionSuspend:
+	default:
+		settings.AutoAction = defaults.AutoAction
+	}
+	if settings.RateLimitRPM < 1 || settings.RateLimitRPM > apiKeyAbuseMaxRPM {
+		settings.RateLimitRPM = defaults.RateLimitRPM
+	}
+	if settings.DismissSuppressDays < 0 {
+		settings.DismissSuppressDays = defaults.DismissSuppressDays
+	}
+}
+
+// ListFlags 分页列出标记
+func (s *APIKeyAbuseService) ListFlags(ctx context.Context, params pagination.PaginationParams, filter APIKeyAbuseFlagFilter) ([]APIKeyAbuseFlag, *pagination.PaginationResult, error) {
+	return s.abuseRepo.ListFlags(ctx, params, filter)
+}
+
+// GetFlagDetail 返回标记详情：按当前配置拆解可疑分，并列出统计窗口内的主要来源 IP / User-Agent
+func (s *APIKeyAbuseService) GetFlagDetail(ctx context.Context, id int64) (*APIKeyAbuseFlagDetail, error) {
+	flag, err := s.abuseRepo.GetFlagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	breakdown := scoreAPIKeyUsage(&APIKeyUsageStats{
+		RequestCount:       flag.RequestCount,
+		DistinctIPs:        flag.DistinctIPs,
+		DistinctNetworks:   flag.DistinctNetworks,
+		PeakConcurrentIPs:  flag.PeakConcurrentIPs,
+		DistinctUserAgents: flag.DistinctUserAgents,
+		ActiveHours:        flag.ActiveHours,
+	}, settings, flag.WindowHours)
+	flag.Breakdown = &breakdown
+
+	end := flag.LastDetectedAt
+	start := end.Add(-time.Duration(flag.WindowHours) * time.Hour)
+	ips, userAgents, err := s.abuseRepo.ListTopSources(ctx, flag.APIKeyID, start, end, apiKeyAbuseTopSources)
+	if err != nil {
+		return nil, err
+	}
+	return &APIKeyAbuseFlagDetail{APIKeyAbuseFlag: flag, TopIPs: ips, TopUserAgents: userAgents}, nil
+}
+
+// Scan 立即执行一次检测（管理员手动触发，不受 enabled 开关限制）
+func (s *APIKeyAbuseService) Scan(ctx context.Context) (*APIKeyAbuseScanResult, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.scan(ctx, settings, time.Now())
+}
+
+func (s *APIKeyAbuseService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyAbuseScanTimeout)
+	defer cancel()
+
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		log.Printf("[APIKeyAbuse] load settings failed: %v", err)
+		return
+	}
+	if !settings.Enabled {
+		return
+	}
+
+	interval := time.Duration(settings.ScanIntervalMinutes) * time.Minute
+	s.scanMu.Lock()
+	due := s.lastScanAt.IsZero() || time.Since(s.lastScanAt) >= interval
+	if due {
+		s.lastScanAt = time.Now()
+	}
+	s.scanMu.Unlock()
+	if !due || !s.tryAcquireScanSlot(ctx, interval) {
+		return
+	}
+
+	result, err := s.scan(ctx, settings, time.Now())
+	if err != nil {
+		log.Printf("[APIKeyAbuse] scan failed: %v", err)
+		return
+	}
+	if result.Flagged > 0 {
+		log.Printf("[APIKeyAbuse] scanned %d keys: flagged=%d actioned=%d", result.Scanned, result.Flagged, result.Actioned)
+	}
+}
+
+func (s *APIKeyAbuseService) scan(ctx context.Context, settings *APIKeyAbuseSettings, now time.Time) (*APIKeyAbuseScanResult, error) {
+	start := now.Add(-time.Duration(settings.WindowHours) * time.Hour)
+	stats, err := s.abuseRepo.ScanUsageStats(ctx, start, now, settings.MinRequests)
+	if err != nil {
+		return nil, fmt.Errorf("scan usage stats: %w", err)
+	}
+
+	suppressed := map[int64]struct{}{}
+	if settings.DismissSuppressDays > 0 {
+		ids, err := s.abuseRepo.ListSuppressedKeyIDs(ctx, now.AddDate(0, 0, -settings.DismissSuppressDays))
+		if err != nil {
+			return nil, fmt.Errorf("list suppressed keys: %w", err)
+		}
+		for _, id := range ids {
+			suppressed[id] = struct{}{}
+		}
+	}
+
+	result := &APIKeyAbuseScanResult{Scanned: len(stats)}
+	for i := range stats {
+		stat := &stats[i]
+		if _, ok := suppressed[stat.APIKeyID]; ok {
+			continue
+		}
+		score := scoreAPIKeyUsage(stat, settings, settings.WindowHours)
+		if score.Total < settings.FlagScore {
+			continue
+		}
+
+		flag, err := s.abuseRepo.UpsertOpenFlag(ctx, &APIKeyAbuseFlag{
+			APIKeyID:           stat.APIKeyID,
+			UserID:             stat.UserID,
+			WindowHours:        settings.WindowHours,
+			RequestCount:       stat.RequestCount,
+			DistinctIPs:        stat.DistinctIPs,
+			DistinctNetworks:   stat.DistinctNetworks,
+			PeakConcurrentIPs:  stat.PeakConcurrentIPs,
+			DistinctUserAgents: stat.DistinctUserAgents,
+			ActiveHours:        stat.ActiveHours,
+			Score:              score.Total,
+			LastDetectedAt:     now,
+		})
+		if err != nil {
+			log.Printf("[APIKeyAbuse] upsert flag failed: api_key=%d err=%v", stat.APIKeyID, err)
+			continue
+		}
+		result.Flagged++
+
+		if s.maybeAutoAction(ctx, flag, settings) {
+			result.Actioned++
+		}
+	}
+	return result, nil
+}
+
+// maybeAutoAction 对达到处置阈值、尚未处置且未经管理员审核的标记执行自动处置
+func (s *APIKeyAbuseService) maybeAutoAction(ctx context.Context, flag *APIKeyAbuseFlag, settings *APIKeyAbuseSettings) bool {
+	if settings.AutoAction == APIKeyAbuseAutoActionNone || flag.Score < settings.ActionScore {
+		return false
+	}
+	if flag.Action != APIKeyAbuseActionNone || flag.ReviewedAt != nil {
+		return false
+	}
+
+	var action string
+	var rpm *int
+	switch settings.AutoAction {
+	case APIKeyAbuseAutoActionRateLimit:
+		action = APIKeyAbuseActionRateLimited
+		v := settings.RateLimitRPM
+		rpm = &v
+	case APIKeyAbuseAutoActionSuspend:
+		if err := s.setKeySuspended(ctx, flag.APIKeyID, true); err != nil {
+			log.Printf("[APIKeyAbuse] suspend key failed: api_key=%d err=%v", flag.APIKeyID, err)
+			return false
+		}
+		action = APIKeyAbuseActionSuspended
+	default:
+		return false
+	}
+
+	if err := s.abuseRepo.UpdateFlagAction(ctx, flag.ID, action, rpm, true); err != nil {
+		log.Printf("[APIKeyAbuse] record action failed: flag=%d err=%v", flag.ID, err)
+		return false
+	}
+	if action == APIKeyAbuseActionRateLimited {
+		s.refreshLimits(ctx)
+	}
+	log.Printf("[APIKeyAbuse] api key %d %s automatically (score=%.1f)", flag.APIKeyID, action, flag.Score)
+
+	if settings.NotifyOwner {
+		s.notifyOwner(ctx, flag, action, rpm)
+	}
+	return true
+}
+
+// Review 管理员处置标记：
+// - dismiss：误报，解除限流/停用，该 Key 在 dismiss_suppress_days 内不再被标记
+// - restore：解除限流/停用并关闭标记
+// - rate_limit / suspend：对 Key 执行（或变更）处置，标记保持 open 以便继续观察
+func (s *APIKeyAbuseService) Review(ctx context.Context, id int64, req *APIKeyAbuseReviewRequest, reviewerID *int64) (*APIKeyAbuseFlag, error) {
+	if req == nil {
+		return nil, infraerrors.BadRequest("API_KEY_ABUSE_INVALID_ACTION", "request cannot be nil")
+	}
+	flag, err := s.abuseRepo.GetFlagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if flag.Status != APIKeyAbuseFlagOpen {
+		return nil, ErrAPIKeyAbuseFlagClosed
+	}
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	note := strings.TrimSpace(req.Note)
+
+	switch strings.TrimSpace(req.Action) {
+	case APIKeyAbuseReviewDismiss, APIKeyAbuseReviewRestore:
+		status := APIKeyAbuseFlagResolved
+		if req.Action == APIKeyAbuseReviewDismiss {
+			status = APIKeyAbuseFlagDismissed
+		}
+		if flag.Action == APIKeyAbuseActionSuspended {
+			if err := s.setKeySuspended(ctx, flag.APIKeyID, false); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.abuseRepo.ReviewFlag(ctx, id, status, APIKeyAbuseActionNone, nil, reviewerID, note); err != nil {
+			return nil, err
+		}
+
+	case APIKeyAbuseReviewRateLimit:
+		rpm := req.RateLimitRPM
+		if rpm == 0 {
+			rpm = settings.RateLimitRPM
+		}
+		if rpm < 1 || rpm > apiKeyAbuseMaxRPM {
+			return nil, infraerrors.BadRequest("API_KEY_ABUSE_INVALID_ACTION", fmt.Sprintf("rate_limit_rpm must be between 1-%d", apiKeyAbuseMaxRPM))
+		}
+		if flag.Action == APIKeyAbuseActionSuspended {
+			if err := s.setKeySuspended(ctx, flag.APIKeyID, false); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.abuseRepo.ReviewFlag(ctx, id, APIKeyAbuseFlagOpen, APIKeyAbuseActionRateLimited, &rpm, reviewerID, note); err != nil {
+			return nil, err
+		}
+		if settings.NotifyOwner && flag.Action != APIKeyAbuseActionRateLimited {
+			s.notifyOwner(ctx, flag, APIKeyAbuseActionRateLimited, &rpm)
+		}
+
+	case APIKeyAbuseReviewSuspend:
+		if err := s.setKeySuspended(ctx, flag.APIKeyID, true); err != nil {
+			return nil, err
+		}
+		if err := s.abuseRepo.ReviewFlag(ctx, id, APIKeyAbuseFlagOpen, APIKeyAbuseActionSuspended, nil, reviewerID, note); err != nil {
+			return nil, err
+		}
+		if settings.NotifyOwner && flag.Action != APIKeyAbuseActionSuspended {
+			s.notifyOwner(ctx, flag, APIKeyAbuseActionSuspended, nil)
+		}
+
+	default:
+		return nil, infraerrors.BadRequest("API_KEY_ABUSE_INVALID_ACTION", fmt.Sprintf("invalid action: %s", req.Action))
+	}
+
+	s.refreshLimits(ctx)
+	return s.abuseRepo.GetFlagByID(ctx, id)
+}
+
+func (s *APIKeyAbuseService) setKeySuspended(ctx context.Context, apiKeyID int64, suspended bool) error {
+	return setAPIKeySuspended(ctx, s.apiKeyRepo, s.apiKeyService, apiKeyID, suspended)
+}
+
+// setAPIKeySuspended 停用 / 恢复 Key，并清除认证缓存使其立即生效。
+// 恢复时仅处理仍处于 suspended 状态的 Key，避免覆盖期间的其他状态变更。
+func setAPIKeySuspended(ctx context.Context, apiKeyRepo APIKeyRepository, apiKeyService *APIKeyService, apiKeyID int64, suspended bool) error {
+	apiKey, err := apiKeyRepo.GetByID(ctx, apiKeyID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case suspended && apiKey.Status != StatusAPIKeySuspended:
+		apiKey.Status = StatusAPIKeySuspended
+	case !suspended && apiKey.Status == StatusAPIKeySuspended:
+		apiKey.Status = StatusAPIKeyActive
+	default:
+		return nil
+	}
+	if err := apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return fmt.Errorf("update api key: %w", err)
+	}
+	if apiKeyService != nil {
+		apiKeyService.InvalidateAuthCacheByKey(ctx, apiKey.Key)
+	}
+	return nil
+}
+
+// notifyOwner 邮件通知 Key 所有者（best-effort）
+func (s *APIKeyAbuseService) notifyOwner(ctx context.Context, flag *APIKeyAbuseFlag, action string, rpm *int) {
+	if s.emailService == nil || s.userRepo == nil {
+		return
+	}
+	user, err := s.userRepo.GetByID(ctx, flag.UserID)
+	if err != nil || user == nil || strings.TrimSpace(user.Email) == "" {
+		return
+	}
+	keyName := flag.APIKeyName
+	if keyName == "" {
+		if apiKey, err := s.apiKeyRepo.GetByID(ctx, flag.APIKeyID); err == nil {
+			keyName = apiKey.Name
+		}
+	}
+	siteName := "Sub2API"
+	if s.settingRepo != nil {
+		if v, err := s.settingRepo.GetValue(ctx, SettingKeySiteName); err == nil && strings.TrimSpace(v) != "" {
+			siteName = strings.TrimSpace(v)
+		}
+	}
+
+	subject, body := buildAPIKeyAbuseNoticeEmail(siteName, keyName, action, rpm)
+	if err := s.emailService.SendEmail(ctx, user.Email, subject, body); err != nil {
+		log.Printf("[APIKeyAbuse] notify owner failed: user=%d err=%v", flag.UserID, err)
+		return
+	}
+	if err := s.abuseRepo.MarkOwnerNotified(ctx, flag.ID, time.Now()); err != nil {
+		log.Printf("[APIKeyAbuse] mark owner notified failed: flag=%d err=%v", flag.ID, err)
+	}
+}
+
+func buildAPIKeyAbuseNoticeEmail(siteName, keyName, action string, rpm *int) (string, string) {
+	var subject, detail string
+	if action == APIKeyAbuseActionSuspended {
+		subject = fmt.Sprintf("[%s] Your API key has been suspended", siteName)
+		detail = "The key has been suspended and requests using it will be rejected until an administrator restores it."
+	} else {
+		limit := 0
+		if rpm != nil {
+			limit = *rpm
+		}
+		subject = fmt.Sprintf("[%s] Your API key has been rate limited", siteName)
+		detail = fmt.Sprintf("Requests using this key are now limited to %d per minute.", limit)
+	}
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>Your API key <strong>%s</strong> shows usage patterns consistent with key sharing (requests from many different IP addresses, networks or clients at the same time).</p>
+<p>%s</p>
+<p>If you believe this is a mistake, please contact the administrator of %s. Do not share API keys; create a separate key for each device or person instead.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(keyName), html.EscapeString(detail), html.EscapeString(siteName))
+	return subject, body
+}
+
+// AllowRequest 检查被限流处置的 Key 是否超出每分钟请求上限；
+// 未被限流、名单尚未加载或 Redis 不可用时一律放行。
+func (s *APIKeyAbuseService) AllowRequest(ctx context.Context, apiKeyID int64) bool {
+	if s == nil || s.abuseRepo == nil {
+		return true
+	}
+	limits := s.currentLimits()
+	rpm, ok := limits[apiKeyID]
+	if !ok || rpm <= 0 {
+		return true
+	}
+	if s.redisClient == nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[APIKeyAbuse] redis not configured; running without scan lock and flagged-key rate limits")
+		})
+		return true
+	}
+
+	key := fmt.Sprintf("%s%d:%d", apiKeyAbuseLimitKeyPrefix, apiKeyID, time.Now().Unix()/60)
+	pipe := s.redisClient.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return true
+	}
+	return incr.Val() <= int64(rpm)
+}
+
+// currentLimits 返回限流名单快照；过期时在后台刷新，不阻塞请求
+func (s *APIKeyAbuseService) currentLimits() map[int64]int {
+	snap := s.limits.Load()
+	if snap == nil || time.Since(snap.loadedAt) >= apiKeyAbuseLimitRefresh {
+		if s.limitsRefreshing.CompareAndSwap(false, true) {
+			go func() {
+				defer s.limitsRefreshing.Store(false)
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				s.refreshLimits(ctx)
+			}()
+		}
+	}
+	if snap == nil {
+		return nil
+	}
+	return snap.rpm
+}
+
+func (s *APIKeyAbuseService) refreshLimits(ctx context.Context) {
+	rpm, err := s.abuseRepo.ListRateLimitedKeys(ctx)
+	if err != nil {
+		log.Printf("[APIKeyAbuse] load rate limited keys failed: %v", err)
+		// 保留旧名单，避免在数据库故障时每个请求都触发刷新
+		if old := s.limits.Load(); old != nil {
+			rpm = old.rpm
+		}
+	}
+	s.limits.Store(&apiKeyAbuseLimitSnapshot{rpm: rpm, loadedAt: time.Now()})
+}
+
+// tryAcquireScanSlot 占用本扫描间隔的执行槽位（不主动释放，TTL 到期后下一间隔才能再次扫描）
+func (s *APIKeyAbuseService) tryAcquireScanSlot(ctx context.Context, interval time.Duration) bool {
+	if s.redisClient == nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[APIKeyAbuse] redis not configured; running without scan lock and flagged-key rate limits")
+		})
+		return true
+	}
+	// 略短于扫描间隔，避免与 ticker 的时间抖动叠加导致跳过一个周期
+	ttl := interval - apiKeyAbuseTickInterval/2
+	ok, err := s.redisClient.SetNX(ctx, apiKeyAbuseScanSlotKey, s.instanceID, ttl).Result()
+	if err != nil {
+		log.Printf("[APIKeyAbuse] scan slot SetNX failed; skipping this cycle: %v", err)
+		return false
+	}
+	return ok
+}
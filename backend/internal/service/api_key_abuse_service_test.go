@@ -0,0 +1,141 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type apiKeyAbuseRepoStub struct {
+	APIKeyAbuseRepository
+	stats      []APIKeyUsageStats
+	suppressed []int64
+	upserted   []*APIKeyAbuseFlag
+	actions    map[int64]string
+}
+
+func (r *apiKeyAbuseRepoStub) ScanUsageStats(ctx context.Context, start, end time.Time, minRequests int) ([]APIKeyUsageStats, error) {
+	return r.stats, nil
+}
+
+func (r *apiKeyAbuseRepoStub) ListSuppressedKeyIDs(ctx context.Context, since time.Time) ([]int64, error) {
+	return r.suppressed, nil
+}
+
+func (r *apiKeyAbuseRepoStub) UpsertOpenFlag(ctx context.Context, flag *APIKeyAbuseFlag) (*APIKeyAbuseFlag, error) {
+	out := *flag
+	out.ID = int64(len(r.upserted) + 1)
+	out.Status = APIKeyAbuseFlagOpen
+	out.Action = APIKeyAbuseActionNone
+	r.upserted = append(r.upserted, &out)
+	return &out, nil
+}
+
+func (r *apiKeyAbuseRepoStub) UpdateFlagAction(ctx context.Context, id int64, action string, rateLimitRPM *int, auto bool) error {
+	if r.actions == nil {
+		r.actions = map[int64]string{}
+	}
+	r.actions[id] = action
+	return nil
+}
+
+type apiKeyAbuseKeyRepoStub struct {
+	APIKeyRepository
+	keys map[int64]*APIKey
+}
+
+func (r *apiKeyAbuseKeyRepoStub) GetByID(ctx context.Context, id int64) (*APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	clone := *key
+	return &clone, nil
+}
+
+func (r *apiKeyAbuseKeyRepoStub) Update(ctx context.Context, key *APIKey) error {
+	clone := *key
+	r.keys[key.ID] = &clone
+	return nil
+}
+
+func TestScoreAPIKeyUsage(t *testing.T) {
+	settings := DefaultAPIKeyAbuseSettings()
+
+	single := scoreAPIKeyUsage(&APIKeyUsageStats{DistinctIPs: 1, DistinctNetworks: 1, PeakConcurrentIPs: 1, DistinctUserAgents: 1, ActiveHours: 8}, settings, 24)
+	require.Zero(t, single.Total)
+
+	shared := scoreAPIKeyUsage(&APIKeyUsageStats{DistinctIPs: 40, DistinctNetworks: 12, PeakConcurrentIPs: 9, DistinctUserAgents: 10, ActiveHours: 24}, settings, 24)
+	require.InDelta(t, 100.0, shared.Total, 1e-9)
+
+	// 家庭 + 公司两个出口、两台设备：轻微得分，远低于默认标记阈值
+	travel := scoreAPIKeyUsage(&APIKeyUsageStats{DistinctIPs: 3, DistinctNetworks: 2, PeakConcurrentIPs: 1, DistinctUserAgents: 2, ActiveHours: 12}, settings, 24)
+	require.Greater(t, travel.Total, 0.0)
+	require.Less(t, travel.Total, settings.FlagScore)
+	require.Zero(t, travel.ActiveHours)
+	require.Zero(t, travel.Concurrency)
+}
+
+func TestValidateAPIKeyAbuseSettings(t *testing.T) {
+	require.NoError(t, validateAPIKeyAbuseSettings(DefaultAPIKeyAbuseSettings()))
+
+	s := DefaultAPIKeyAbuseSettings()
+	s.ActionScore = s.FlagScore - 1
+	require.Error(t, validateAPIKeyAbuseSettings(s))
+
+	s = DefaultAPIKeyAbuseSettings()
+	s.AutoAction = "ban"
+	require.Error(t, validateAPIKeyAbuseSettings(s))
+
+	s = DefaultAPIKeyAbuseSettings()
+	s.MaxConcurrentIPs = 1
+	require.Error(t, validateAPIKeyAbuseSettings(s))
+}
+
+func TestAPIKeyAbuseService_ScanFlagsAndSuspends(t *testing.T) {
+	heavy := APIKeyUsageStats{APIKeyID: 1, UserID: 10, RequestCount: 5000, DistinctIPs: 30, DistinctNetworks: 10, PeakConcurrentIPs: 6, DistinctUserAgents: 8, ActiveHours: 24}
+	light := APIKeyUsageStats{APIKeyID: 2, UserID: 11, RequestCount: 200, DistinctIPs: 2, DistinctNetworks: 1, PeakConcurrentIPs: 1, DistinctUserAgents: 1, ActiveHours: 6}
+	dismissed := heavy
+	dismissed.APIKeyID = 3
+
+	repo := &apiKeyAbuseRepoStub{stats: []APIKeyUsageStats{heavy, light, dismissed}, suppressed: []int64{3}}
+	keys := &apiKeyAbuseKeyRepoStub{keys: map[int64]*APIKey{1: {ID: 1, UserID: 10, Status: StatusAPIKeyActive}}}
+	svc := NewAPIKeyAbuseService(repo, keys, nil, nil, nil, nil, nil)
+
+	settings := DefaultAPIKeyAbuseSettings()
+	settings.AutoAction = APIKeyAbuseAutoActionSuspend
+	settings.NotifyOwner = false
+
+	result, err := svc.scan(context.Background(), settings, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, &APIKeyAbuseScanResult{Scanned: 3, Flagged: 1, Actioned: 1}, result)
+	require.Len(t, repo.upserted, 1)
+	require.Equal(t, int64(1), repo.upserted[0].APIKeyID)
+	require.Equal(t, APIKeyAbuseActionSuspended, repo.actions[repo.upserted[0].ID])
+	require.Equal(t, StatusAPIKeySuspended, keys.keys[1].Status)
+}
+
+func TestAPIKeyService_UpdateRejectsReactivatingSuspendedKey(t *testing.T) {
+	repo := &apiKeyRepoStub{apiKey: &APIKey{ID: 1, UserID: 10, Key: "sk-test", Status: StatusAPIKeySuspended}}
+	svc := &APIKeyService{apiKeyRepo: repo}
+
+	status := StatusAPIKeyActive
+	_, err := svc.Update(context.Background(), 1, 10, UpdateAPIKeyRequest{Status: &status})
+	require.ErrorIs(t, err, ErrAPIKeySuspended)
+}
+
+func TestAPIKeyAbuseService_AllowRequestFailsOpen(t *testing.T) {
+	svc := NewAPIKeyAbuseService(&apiKeyAbuseRepoStub{}, nil, nil, nil, nil, nil, nil)
+	svc.limits.Store(&apiKeyAbuseLimitSnapshot{rpm: map[int64]int{1: 1}, loadedAt: time.Now()})
+
+	// 没有 Redis 时不强制限流
+	require.True(t, svc.AllowRequest(context.Background(), 1))
+	require.True(t, svc.AllowRequest(context.Background(), 2))
+
+	var nilSvc *APIKeyAbuseService
+	require.True(t, nilSvc.AllowRequest(context.Background(), 1))
+}
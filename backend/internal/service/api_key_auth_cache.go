@@ -65,6 +65,14 @@ type APIKeyAuthGroupSnapshot struct {
 
 	// 支持的模型系列（仅 antigravity 平台使用）
 	SupportedModelScopes []string `json:"supported_model_scopes,omitempty"`
+
+	// Hedging is decided per request in the gateway, so it must be part of auth cache snapshot.
+	HedgeEnabled  bool    `json:"hedge_enabled"`
+	HedgeDelayMs  int     `json:"hedge_delay_ms"`
+	HedgeMaxRatio float64 `json:"hedge_max_ratio"`
+
+	// Group schedule windows are evaluated at account selection time.
+	ScheduleRule *ScheduleRule `json:"schedule_rule,omitempty"`
 }
 
 // APIKeyAuthCacheEntry 缓存条目，支持负缓存
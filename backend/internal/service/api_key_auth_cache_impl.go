@@ -249,6 +249,10 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 			CacheReadTransferProbability:    apiKey.Group.CacheReadTransferProbability,
 			MCPXMLInject:                    apiKey.Group.MCPXMLInject,
 			SupportedModelScopes:            apiKey.Group.SupportedModelScopes,
+			HedgeEnabled:                    apiKey.Group.HedgeEnabled,
+			HedgeDelayMs:                    apiKey.Group.HedgeDelayMs,
+			HedgeMaxRatio:                   apiKey.Group.HedgeMaxRatio,
+			ScheduleRule:                    apiKey.Group.ScheduleRule,
 		}
 	}
 	return snapshot
@@ -303,6 +307,10 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 			CacheReadTransferProbability:    snapshot.Group.CacheReadTransferProbability,
 			MCPXMLInject:                    snapshot.Group.MCPXMLInject,
 			SupportedModelScopes:            snapshot.Group.SupportedModelScopes,
+			HedgeEnabled:                    snapshot.Group.HedgeEnabled,
+			HedgeDelayMs:                    snapshot.Group.HedgeDelayMs,
+			HedgeMaxRatio:                   snapshot.Group.HedgeMaxRatio,
+			ScheduleRule:                    snapshot.Group.ScheduleRule,
 		}
 	}
 	return apiKey
@@ -24,6 +24,7 @@ var (
 	ErrAPIKeyInvalidChars = infraerrors.BadRequest("API_KEY_INVALID_CHARS", "api key can only contain letters, numbers, underscores, and hyphens")
 	ErrAPIKeyRateLimited  = infraerrors.TooManyRequests("API_KEY_RATE_LIMITED", "too many failed attempts, please try again later")
 	ErrInvalidIPPattern   = infraerrors.BadRequest("INVALID_IP_PATTERN", "invalid IP or CIDR pattern")
+	ErrAPIKeySuspended    = infraerrors.Forbidden("API_KEY_SUSPENDED", "api key has been suspended by an administrator")
 	// ErrAPIKeyExpired        = infraerrors.Forbidden("API_KEY_EXPIRED", "api key has expired")
 	ErrAPIKeyExpired = infraerrors.Forbidden("API_KEY_EXPIRED", "api key 已过期")
 	// ErrAPIKeyQuotaExhausted = infraerrors.TooManyRequests("API_KEY_QUOTA_EXHAUSTED", "api key quota exhausted")
@@ -125,6 +126,7 @@ type APIKeyService struct {
 	authCacheL1       *ristretto.Cache
 	authCfg           apiKeyAuthCacheConfig
 	authGroup         singleflight.Group
+	abuseLimiter      APIKeyAbuseLimiter
 }
 
 // NewAPIKeyService 创建API Key服务实例
@@ -150,6 +152,19 @@ func NewAPIKeyService(
 	return svc
 }
 
+// SetAbuseLimiter 注入滥用检测限流器（由 APIKeyAbuseService 在初始化时设置）
+func (s *APIKeyService) SetAbuseLimiter(limiter APIKeyAbuseLimiter) {
+	s.abuseLimiter = limiter
+}
+
+// AllowAbuseLimitedRequest 检查被滥用检测限流的 Key 是否允许本次请求
+func (s *APIKeyService) AllowAbuseLimitedRequest(ctx context.Context, apiKeyID int64) bool {
+	if s == nil || s.abuseLimiter == nil {
+		return true
+	}
+	return s.abuseLimiter.AllowRequest(ctx, apiKeyID)
+}
+
 // GenerateKey 生成随机API Key
 func (s *APIKeyService) GenerateKey() (string, error) {
 	// 生成32字节随机数据
@@ -457,6 +472,10 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 	}
 
 	if req.Status != nil {
+		// 被滥用检测停用的 Key 只能由管理员恢复
+		if apiKey.Status == StatusAPIKeySuspended && *req.Status != StatusAPIKeySuspended {
+			return nil, ErrAPIKeySuspended
+		}
 		apiKey.Status = *req.Status
 		// 如果状态改变，清除Redis缓存
 		if s.cache != nil {
@@ -51,6 +51,10 @@ type JWTClaims struct {
 	Email        string `json:"email"`
 	Role         string `json:"role"`
 	TokenVersion int64  `json:"token_version"` // Used to invalidate tokens on password change
+	SessionID    string `json:"sid,omitempty"` // Refresh Token家族ID，用于识别/撤销单个会话
+	// 管理员模拟登录：ImpersonatorID 为真实管理员ID，ImpersonationID 为模拟会话ID；普通 Token 均为 0
+	ImpersonatorID  int64 `json:"imp,omitempty"`
+	ImpersonationID int64 `json:"imp_sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -647,6 +651,11 @@ func isReservedEmail(email string) bool {
 // GenerateToken 生成JWT access token
 // 使用新的access_token_expire_minutes配置项（如果配置了），否则回退到expire_hour
 func (s *AuthService) GenerateToken(user *User) (string, error) {
+	return s.generateAccessToken(user, "")
+}
+
+// generateAccessToken 生成JWT access token，sessionID 非空时写入 sid 声明
+func (s *AuthService) generateAccessToken(user *User, sessionID string) (string, error) {
 	now := time.Now()
 	var expiresAt time.Time
 	if s.cfg.JWT.AccessTokenExpireMinutes > 0 {
@@ -661,6 +670,7 @@ func (s *AuthService) GenerateToken(user *User) (string, error) {
 		Email:        user.Email,
 		Role:         user.Role,
 		TokenVersion: user.TokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -677,6 +687,31 @@ func (s *AuthService) GenerateToken(user *User) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateImpersonationToken 为目标用户签发管理员模拟登录 Token（带 imp 标记，固定到期时间，不签发 Refresh Token）
+func (s *AuthService) GenerateImpersonationToken(user *User, adminID, sessionID int64, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	claims := &JWTClaims{
+		UserID:          user.ID,
+		Email:           user.Email,
+		Role:            user.Role,
+		TokenVersion:    user.TokenVersion,
+		ImpersonatorID:  adminID,
+		ImpersonationID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.cfg.JWT.Secret))
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
 // GetAccessTokenExpiresIn 返回Access Token的有效期（秒）
 // 用于前端设置刷新定时器
 func (s *AuthService) GetAccessTokenExpiresIn() int {
@@ -708,6 +743,10 @@ func (s *AuthService) RefreshToken(ctx context.Context, oldTokenString string) (
 	if err != nil && !errors.Is(err, ErrTokenExpired) {
 		return "", err
 	}
+	// 模拟登录 Token 到期即失效，不能换取普通 Token
+	if claims.ImpersonatorID != 0 {
+		return "", ErrInvalidToken
+	}
 
 	// 获取最新的用户信息
 	user, err := s.userRepo.GetByID(ctx, claims.UserID)
@@ -898,21 +937,26 @@ type TokenPair struct {
 // GenerateTokenPair 生成Access Token和Refresh Token对
 // familyID: 可选的Token家族ID，用于Token轮转时保持家族关系
 func (s *AuthService) GenerateTokenPair(ctx context.Context, user *User, familyID string) (*TokenPair, error) {
+	return s.generateTokenPair(ctx, user, familyID, nil)
+}
+
+// generateTokenPair 生成Token对；prev 为轮转前的Refresh Token数据，用于沿用会话元数据
+func (s *AuthService) generateTokenPair(ctx context.Context, user *User, familyID string, prev *RefreshTokenData) (*TokenPair, error) {
 	// 检查 refreshTokenCache 是否可用
 	if s.refreshTokenCache == nil {
 		return nil, errors.New("refresh token cache not configured")
 	}
 
-	// 生成Access Token
-	accessToken, err := s.GenerateToken(user)
+	// 生成Refresh Token（先生成以确定家族ID）
+	refreshToken, familyID, err := s.generateRefreshToken(ctx, user, familyID, prev)
 	if err != nil {
-		return nil, fmt.Errorf("generate access token: %w", err)
+		return nil, fmt.Errorf("generate refresh token: %w", err)
 	}
 
-	// 生成Refresh Token
-	refreshToken, err := s.generateRefreshToken(ctx, user, familyID)
+	// 生成Access Token，携带会话ID
+	accessToken, err := s.generateAccessToken(user, familyID)
 	if err != nil {
-		return nil, fmt.Errorf("generate refresh token: %w", err)
+		return nil, fmt.Errorf("generate access token: %w", err)
 	}
 
 	return &TokenPair{
@@ -922,23 +966,24 @@ func (s *AuthService) GenerateTokenPair(ctx context.Context, user *User, familyI
 	}, nil
 }
 
-// generateRefreshToken 生成并存储Refresh Token
-func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, familyID string) (string, error) {
+// generateRefreshToken 生成并存储Refresh Token，返回原始Token与家族ID
+func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, familyID string, prev *RefreshTokenData) (string, string, error) {
 	// 生成随机Token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
-		return "", fmt.Errorf("generate random bytes: %w", err)
+		return "", "", fmt.Errorf("generate random bytes: %w", err)
 	}
 	rawToken := refreshTokenPrefix + hex.EncodeToString(tokenBytes)
 
 	// 计算Token哈希（存储哈希而非原始Token）
 	tokenHash := hashToken(rawToken)
 
-	// 如果没有提供familyID，生成新的
-	if familyID == "" {
+	// 如果没有提供familyID，生成新的（即新登录会话）
+	newSession := familyID == ""
+	if newSession {
 		familyBytes := make([]byte, 16)
 		if _, err := rand.Read(familyBytes); err != nil {
-			return "", fmt.Errorf("generate family id: %w", err)
+			return "", "", fmt.Errorf("generate family id: %w", err)
 		}
 		familyID = hex.EncodeToString(familyBytes)
 	}
@@ -947,16 +992,29 @@ func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, fami
 	ttl := time.Duration(s.cfg.JWT.RefreshTokenExpireDays) * 24 * time.Hour
 
 	data := &RefreshTokenData{
-		UserID:       user.ID,
-		TokenVersion: user.TokenVersion,
-		FamilyID:     familyID,
-		CreatedAt:    now,
-		ExpiresAt:    now.Add(ttl),
+		UserID:           user.ID,
+		TokenVersion:     user.TokenVersion,
+		FamilyID:         familyID,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(ttl),
+		SessionCreatedAt: now,
+	}
+	if prev != nil {
+		data.SessionCreatedAt = prev.sessionCreatedAt()
+		data.UserAgent = prev.UserAgent
+		data.IPAddress = prev.IPAddress
+	}
+	client := sessionClientFromContext(ctx)
+	if client.UserAgent != "" {
+		data.UserAgent = client.UserAgent
+	}
+	if client.IPAddress != "" {
+		data.IPAddress = client.IPAddress
 	}
 
 	// 存储Token数据
 	if err := s.refreshTokenCache.StoreRefreshToken(ctx, tokenHash, data, ttl); err != nil {
-		return "", fmt.Errorf("store refresh token: %w", err)
+		return "", "", fmt.Errorf("store refresh token: %w", err)
 	}
 
 	// 添加到用户Token集合
@@ -971,7 +1029,11 @@ func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, fami
 		// 不影响主流程
 	}
 
-	return rawToken, nil
+	if newSession {
+		s.checkNewDeviceLogin(ctx, user, data)
+	}
+
+	return rawToken, familyID, nil
 }
 
 // RefreshTokenPair 使用Refresh Token刷新Token对
@@ -1041,7 +1103,7 @@ func (s *AuthService) RefreshTokenPair(ctx context.Context, refreshToken string)
 	}
 
 	// 生成新的Token对，保持同一个家族ID
-	return s.GenerateTokenPair(ctx, user, data.FamilyID)
+	return s.generateTokenPair(ctx, user, data.FamilyID, data)
 }
 
 // RevokeRefreshToken 撤销单个Refresh Token
@@ -1063,6 +1125,12 @@ func (s *AuthService) RevokeAllUserSessions(ctx context.Context, userID int64) e
 	if s.refreshTokenCache == nil {
 		return nil // No-op if cache not configured
 	}
+	// 先标记各会话已撤销，使已签发的Access Token立即失效
+	if sessions, err := s.ListUserSessions(ctx, userID, ""); err == nil {
+		for i := range sessions {
+			s.markSessionRevoked(ctx, sessions[i].ID)
+		}
+	}
 	return s.refreshTokenCache.DeleteUserRefreshTokens(ctx, userID)
 }
 
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var ErrSessionNotFound = infraerrors.NotFound("SESSION_NOT_FOUND", "session not found")
+
+const (
+	// knownDeviceTTL 已知设备指纹的保留时长，超过后再次登录视为新设备
+	knownDeviceTTL = 180 * 24 * time.Hour
+	// maxSessionUserAgentLen User-Agent 最大保存长度
+	maxSessionUserAgentLen = 512
+	// newDeviceNotifyTimeout 新设备登录邮件发送超时
+	newDeviceNotifyTimeout = 30 * time.Second
+)
+
+// SessionClientInfo 发起登录/刷新请求的客户端信息
+type SessionClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// WithSessionClientInfo 将客户端信息写入 context，签发 Refresh Token 时记录到会话
+func WithSessionClientInfo(ctx context.Context, info SessionClientInfo) context.Context {
+	info.IPAddress = strings.TrimSpace(info.IPAddress)
+	info.UserAgent = truncateString(strings.TrimSpace(info.UserAgent), maxSessionUserAgentLen)
+	return context.WithValue(ctx, ctxkey.SessionClient, info)
+}
+
+func sessionClientFromContext(ctx context.Context) SessionClientInfo {
+	if ctx == nil {
+		return SessionClientInfo{}
+	}
+	info, _ := ctx.Value(ctxkey.SessionClient).(SessionClientInfo)
+	return info
+}
+
+// sessionCreatedAt 兼容升级前签发的 Token（没有 SessionCreatedAt）
+func (d *RefreshTokenData) sessionCreatedAt() time.Time {
+	if d.SessionCreatedAt.IsZero() {
+		return d.CreatedAt
+	}
+	return d.SessionCreatedAt
+}
+
+// UserSession 一个活跃的登录会话（即一个 Refresh Token 家族）
+type UserSession struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// ListUserSessions 列出用户的活跃会话，按最近使用时间倒序
+// currentSessionID 为当前请求所属会话，用于标记 Current
+func (s *AuthService) ListUserSessions(ctx context.Context, userID int64, currentSessionID string) ([]UserSession, error) {
+	if s.refreshTokenCache == nil {
+		return []UserSession{}, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := s.refreshTokenCache.GetUserTokenHashes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user token hashes: %w", err)
+	}
+
+	now := time.Now()
+	latest := make(map[string]*RefreshTokenData)
+	stale := make([]string, 0)
+	for _, hash := range hashes {
+		data, err := s.refreshTokenCache.GetRefreshToken(ctx, hash)
+		if err != nil {
+			if errors.Is(err, ErrRefreshTokenNotFound) {
+				// 已轮转或已过期的Token
+				stale = append(stale, hash)
+				continue
+			}
+			return nil, fmt.Errorf("get refresh token: %w", err)
+		}
+		if data.UserID != userID || data.TokenVersion != user.TokenVersion || now.After(data.ExpiresAt) {
+			stale = append(stale, hash)
+			continue
+		}
+		if cur, ok := latest[data.FamilyID]; !ok || data.CreatedAt.After(cur.CreatedAt) {
+			latest[data.FamilyID] = data
+		}
+	}
+
+	// 清理集合中失效的引用（best-effort）
+	if len(stale) > 0 {
+		if err := s.refreshTokenCache.RemoveFromUserTokenSet(ctx, userID, stale...); err != nil {
+			log.Printf("[Auth] Failed to prune user token set: user=%d err=%v", userID, err)
+		}
+	}
+
+	sessions := make([]UserSession, 0, len(latest))
+	for familyID, data := range latest {
+		sessions = append(sessions, UserSession{
+			ID:         familyID,
+			UserAgent:  data.UserAgent,
+			IPAddress:  data.IPAddress,
+			CreatedAt:  data.sessionCreatedAt(),
+			LastUsedAt: data.CreatedAt,
+			ExpiresAt:  data.ExpiresAt,
+			Current:    currentSessionID != "" && familyID == currentSessionID,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+// RevokeUserSession 撤销用户的单个会话
+func (s *AuthService) RevokeUserSession(ctx context.Context, userID int64, sessionID string) error {
+	sessionID = strings.TrimSpace(sessionID)
+	if s.refreshTokenCache == nil || sessionID == "" {
+		return ErrSessionNotFound
+	}
+
+	sessions, err := s.ListUserSessions(ctx, userID, "")
+	if err != nil {
+		return err
+	}
+	found := false
+	for i := range sessions {
+		if sessions[i].ID == sessionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrSessionNotFound
+	}
+
+	hashes, err := s.refreshTokenCache.GetFamilyTokenHashes(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("get family token hashes: %w", err)
+	}
+	if err := s.refreshTokenCache.DeleteTokenFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("delete token family: %w", err)
+	}
+	if err := s.refreshTokenCache.RemoveFromUserTokenSet(ctx, userID, hashes...); err != nil {
+		log.Printf("[Auth] Failed to remove revoked tokens from user set: user=%d err=%v", userID, err)
+	}
+	s.markSessionRevoked(ctx, sessionID)
+	return nil
+}
+
+// IsSessionRevoked 检查 Access Token 所属会话是否已被撤销
+// 缓存不可用时放行（fail-open），避免 Redis 故障导致全站登录失效
+func (s *AuthService) IsSessionRevoked(ctx context.Context, sessionID string) bool {
+	if s.refreshTokenCache == nil || sessionID == "" {
+		return false
+	}
+	revoked, err := s.refreshTokenCache.IsSessionRevoked(ctx, sessionID)
+	if err != nil {
+		log.Printf("[Auth] Failed to check session revocation: %v", err)
+		return false
+	}
+	return revoked
+}
+
+// markSessionRevoked 在 Access Token 有效期内拒绝该会话的请求
+func (s *AuthService) markSessionRevoked(ctx context.Context, sessionID string) {
+	ttl := time.Duration(s.GetAccessTokenExpiresIn())*time.Second + time.Minute
+	if err := s.refreshTokenCache.MarkSessionRevoked(ctx, sessionID, ttl); err != nil {
+		log.Printf("[Auth] Failed to mark session revoked: %v", err)
+	}
+}
+
+// checkNewDeviceLogin 新会话创建时检查设备是否陌生，陌生设备发送邮件提醒（best-effort）
+func (s *AuthService) checkNewDeviceLogin(ctx context.Context, user *User, data *RefreshTokenData) {
+	if s.emailService == nil || data.UserAgent == "" || strings.TrimSpace(user.Email) == "" || isReservedEmail(user.Email) {
+		return
+	}
+	fingerprint := sha256.Sum256([]byte(data.UserAgent))
+	isNew, err := s.refreshTokenCache.RememberUserDevice(ctx, user.ID, hex.EncodeToString(fingerprint[:16]), knownDeviceTTL)
+	if err != nil {
+		log.Printf("[Auth] Failed to record login device: user=%d err=%v", user.ID, err)
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	siteName := "Sub2API"
+	if s.settingService != nil {
+		siteName = s.settingService.GetSiteName(ctx)
+	}
+	subject, body := buildNewDeviceLoginEmail(siteName, data)
+	email := user.Email
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.Background(), newDeviceNotifyTimeout)
+		defer cancel()
+		if err := s.emailService.SendEmail(sendCtx, email, subject, body); err != nil {
+			log.Printf("[Auth] Failed to send new device login email: user=%d err=%v", user.ID, err)
+		}
+	}()
+}
+
+func buildNewDeviceLoginEmail(siteName string, data *RefreshTokenData) (string, string) {
+	subject := fmt.Sprintf("[%s] New sign-in from an unfamiliar device", siteName)
+	ip := data.IPAddress
+	if ip == "" {
+		ip = "unknown"
+	}
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>Your %s account was just signed in from a device we have not seen before:</p>
+<ul>
+<li><strong>Time:</strong> %s</li>
+<li><strong>IP address:</strong> %s</li>
+<li><strong>Device:</strong> %s</li>
+</ul>
+<p>If this was you, no action is needed. Otherwise, change your password and sign out the session from the Sessions section of your profile.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(siteName), data.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), html.EscapeString(ip), html.EscapeString(data.UserAgent))
+	return subject, body
+}
@@ -0,0 +1,241 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshTokenCacheStub 内存版 RefreshTokenCache
+type refreshTokenCacheStub struct {
+	tokens   map[string]*RefreshTokenData
+	users    map[int64]map[string]bool
+	families map[string]map[string]bool
+	revoked  map[string]bool
+	devices  map[int64]map[string]bool
+}
+
+func newRefreshTokenCacheStub() *refreshTokenCacheStub {
+	return &refreshTokenCacheStub{
+		tokens:   map[string]*RefreshTokenData{},
+		users:    map[int64]map[string]bool{},
+		families: map[string]map[string]bool{},
+		revoked:  map[string]bool{},
+		devices:  map[int64]map[string]bool{},
+	}
+}
+
+func (s *refreshTokenCacheStub) StoreRefreshToken(ctx context.Context, tokenHash string, data *RefreshTokenData, ttl time.Duration) error {
+	copied := *data
+	s.tokens[tokenHash] = &copied
+	return nil
+}
+
+func (s *refreshTokenCacheStub) GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshTokenData, error) {
+	data, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+	copied := *data
+	return &copied, nil
+}
+
+func (s *refreshTokenCacheStub) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
+	delete(s.tokens, tokenHash)
+	return nil
+}
+
+func (s *refreshTokenCacheStub) DeleteUserRefreshTokens(ctx context.Context, userID int64) error {
+	for hash := range s.users[userID] {
+		delete(s.tokens, hash)
+	}
+	delete(s.users, userID)
+	return nil
+}
+
+func (s *refreshTokenCacheStub) DeleteTokenFamily(ctx context.Context, familyID string) error {
+	for hash := range s.families[familyID] {
+		delete(s.tokens, hash)
+	}
+	delete(s.families, familyID)
+	return nil
+}
+
+func (s *refreshTokenCacheStub) AddToUserTokenSet(ctx context.Context, userID int64, tokenHash string, ttl time.Duration) error {
+	if s.users[userID] == nil {
+		s.users[userID] = map[string]bool{}
+	}
+	s.users[userID][tokenHash] = true
+	return nil
+}
+
+func (s *refreshTokenCacheStub) AddToFamilyTokenSet(ctx context.Context, familyID string, tokenHash string, ttl time.Duration) error {
+	if s.families[familyID] == nil {
+		s.families[familyID] = map[string]bool{}
+	}
+	s.families[familyID][tokenHash] = true
+	return nil
+}
+
+func (s *refreshTokenCacheStub) GetUserTokenHashes(ctx context.Context, userID int64) ([]string, error) {
+	out := make([]string, 0, len(s.users[userID]))
+	for hash := range s.users[userID] {
+		out = append(out, hash)
+	}
+	return out, nil
+}
+
+func (s *refreshTokenCacheStub) GetFamilyTokenHashes(ctx context.Context, familyID string) ([]string, error) {
+	out := make([]string, 0, len(s.families[familyID]))
+	for hash := range s.families[familyID] {
+		out = append(out, hash)
+	}
+	return out, nil
+}
+
+func (s *refreshTokenCacheStub) IsTokenInFamily(ctx context.Context, familyID string, tokenHash string) (bool, error) {
+	return s.families[familyID][tokenHash], nil
+}
+
+func (s *refreshTokenCacheStub) RemoveFromUserTokenSet(ctx context.Context, userID int64, tokenHashes ...string) error {
+	for _, hash := range tokenHashes {
+		delete(s.users[userID], hash)
+	}
+	return nil
+}
+
+func (s *refreshTokenCacheStub) MarkSessionRevoked(ctx context.Context, familyID string, ttl time.Duration) error {
+	s.revoked[familyID] = true
+	return nil
+}
+
+func (s *refreshTokenCacheStub) IsSessionRevoked(ctx context.Context, familyID string) (bool, error) {
+	return s.revoked[familyID], nil
+}
+
+func (s *refreshTokenCacheStub) RememberUserDevice(ctx context.Context, userID int64, fingerprint string, ttl time.Duration) (bool, error) {
+	if s.devices[userID] == nil {
+		s.devices[userID] = map[string]bool{}
+	}
+	known := len(s.devices[userID]) > 0
+	added := !s.devices[userID][fingerprint]
+	s.devices[userID][fingerprint] = true
+	return known && added, nil
+}
+
+func newSessionTestAuthService(user *User) (*AuthService, *refreshTokenCacheStub) {
+	cfg := &config.Config{
+		JWT: config.JWTConfig{
+			Secret:                   "test-secret",
+			AccessTokenExpireMinutes: 15,
+			RefreshTokenExpireDays:   7,
+		},
+	}
+	cache := newRefreshTokenCacheStub()
+	svc := NewAuthService(&userRepoStub{user: user}, nil, cache, cfg, nil, nil, nil, nil, nil)
+	return svc, cache
+}
+
+func clientCtx(ip, ua string) context.Context {
+	return WithSessionClientInfo(context.Background(), SessionClientInfo{IPAddress: ip, UserAgent: ua})
+}
+
+func TestAuthService_ListUserSessions(t *testing.T) {
+	user := &User{ID: 1, Email: "user@example.com", Status: StatusActive}
+	svc, cache := newSessionTestAuthService(user)
+
+	laptop, err := svc.GenerateTokenPair(clientCtx("10.0.0.1", "Laptop Browser"), user, "")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = svc.GenerateTokenPair(clientCtx("10.0.0.2", "Phone Browser"), user, "")
+	require.NoError(t, err)
+
+	// Access Token 携带会话ID
+	claims, err := svc.ValidateToken(laptop.AccessToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.SessionID)
+	laptopSession := claims.SessionID
+
+	// 轮转后沿用会话创建时间与设备，IP 更新为最新值
+	time.Sleep(5 * time.Millisecond)
+	rotated, err := svc.RefreshTokenPair(clientCtx("10.0.0.9", ""), laptop.RefreshToken)
+	require.NoError(t, err)
+	rotatedClaims, err := svc.ValidateToken(rotated.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, laptopSession, rotatedClaims.SessionID)
+
+	// 其他用户的Token不应出现
+	cache.tokens["foreign"] = &RefreshTokenData{UserID: 2, FamilyID: "foreign-family", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	cache.users[1]["foreign"] = true
+
+	sessions, err := svc.ListUserSessions(context.Background(), user.ID, laptopSession)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	require.Equal(t, laptopSession, sessions[0].ID)
+	require.True(t, sessions[0].Current)
+	require.Equal(t, "Laptop Browser", sessions[0].UserAgent)
+	require.Equal(t, "10.0.0.9", sessions[0].IPAddress)
+	require.True(t, sessions[0].LastUsedAt.After(sessions[0].CreatedAt))
+
+	require.False(t, sessions[1].Current)
+	require.Equal(t, "Phone Browser", sessions[1].UserAgent)
+
+	// 已轮转的旧Token与外部Token引用被清理
+	require.Len(t, cache.users[1], 2)
+}
+
+func TestAuthService_RevokeUserSession(t *testing.T) {
+	user := &User{ID: 1, Email: "user@example.com", Status: StatusActive}
+	svc, cache := newSessionTestAuthService(user)
+
+	first, err := svc.GenerateTokenPair(clientCtx("10.0.0.1", "A"), user, "")
+	require.NoError(t, err)
+	second, err := svc.GenerateTokenPair(clientCtx("10.0.0.2", "B"), user, "")
+	require.NoError(t, err)
+	firstClaims, err := svc.ValidateToken(first.AccessToken)
+	require.NoError(t, err)
+
+	cache.tokens["foreign"] = &RefreshTokenData{UserID: 2, FamilyID: "foreign-family", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	require.ErrorIs(t, svc.RevokeUserSession(context.Background(), user.ID, "foreign-family"), ErrSessionNotFound)
+	require.ErrorIs(t, svc.RevokeUserSession(context.Background(), user.ID, ""), ErrSessionNotFound)
+
+	require.NoError(t, svc.RevokeUserSession(context.Background(), user.ID, firstClaims.SessionID))
+	require.True(t, svc.IsSessionRevoked(context.Background(), firstClaims.SessionID))
+
+	_, err = svc.RefreshTokenPair(context.Background(), first.RefreshToken)
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+	_, err = svc.RefreshTokenPair(context.Background(), second.RefreshToken)
+	require.NoError(t, err)
+
+	sessions, err := svc.ListUserSessions(context.Background(), user.ID, "")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, "B", sessions[0].UserAgent)
+
+	// 撤销全部会话时，已签发的 Access Token 同样失效
+	secondClaims, err := svc.ValidateToken(second.AccessToken)
+	require.NoError(t, err)
+	require.NoError(t, svc.RevokeAllUserSessions(context.Background(), user.ID))
+	require.True(t, svc.IsSessionRevoked(context.Background(), secondClaims.SessionID))
+	sessions, err = svc.ListUserSessions(context.Background(), user.ID, "")
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestBuildNewDeviceLoginEmail(t *testing.T) {
+	subject, body := buildNewDeviceLoginEmail("Site", &RefreshTokenData{
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		UserAgent: "<script>alert(1)</script>",
+	})
+	require.Equal(t, "[Site] New sign-in from an unfamiliar device", subject)
+	require.Contains(t, body, "2026-01-02 03:04 UTC")
+	require.Contains(t, body, "&lt;script&gt;")
+	require.NotContains(t, body, "<script>")
+	require.Contains(t, body, "unknown")
+}
@@ -18,6 +18,12 @@ type ConcurrencyCache interface {
 	ReleaseAccountSlot(ctx context.Context, accountID int64, requestID string) error
 	GetAccountConcurrency(ctx context.Context, accountID int64) (int, error)
 
+	// 账号模型级槽位管理（与账号槽位叠加生效）
+	// 键格式: concurrency:account_model:{accountID}:{pattern}（有序集合，成员为 requestID）
+	AcquireAccountModelSlot(ctx context.Context, accountID int64, pattern string, maxConcurrency int, requestID string) (bool, error)
+	ReleaseAccountModelSlot(ctx context.Context, accountID int64, pattern string, requestID string) error
+	GetAccountModelConcurrency(ctx context.Context, accountID int64, pattern string) (int, error)
+
 	// 账号等待队列（账号级）
 	IncrementAccountWaitCount(ctx context.Context, accountID int64, maxWait int) (bool, error)
 	DecrementAccountWaitCount(ctx context.Context, accountID int64) error
@@ -136,6 +142,44 @@ func (s *ConcurrencyService) AcquireAccountSlot(ctx context.Context, accountID i
 	}, nil
 }
 
+// AcquireAccountSlotForModel acquires the account slot and, when the requested model
+// hits a per-model limit on the account, the model slot as well.
+// Both slots are released together; if the model slot is full the account slot is returned immediately.
+func (s *ConcurrencyService) AcquireAccountSlotForModel(ctx context.Context, accountID int64, maxConcurrency int, modelLimit ModelConcurrencyLimit) (*AcquireResult, error) {
+	accountResult, err := s.AcquireAccountSlot(ctx, accountID, maxConcurrency)
+	if err != nil || !accountResult.Acquired || modelLimit.IsZero() {
+		return accountResult, err
+	}
+
+	requestID := generateRequestID()
+	acquired, err := s.cache.AcquireAccountModelSlot(ctx, accountID, modelLimit.Pattern, modelLimit.MaxConcurrency, requestID)
+	if err != nil || !acquired {
+		accountResult.ReleaseFunc()
+		if err != nil {
+			return nil, err
+		}
+		return &AcquireResult{Acquired: false}, nil
+	}
+
+	releaseAccount := accountResult.ReleaseFunc
+	return &AcquireResult{
+		Acquired: true,
+		ReleaseFunc: func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.cache.ReleaseAccountModelSlot(bgCtx, accountID, modelLimit.Pattern, requestID); err != nil {
+				log.Printf("Warning: failed to release model slot for %d/%s (req=%s): %v", accountID, modelLimit.Pattern, requestID, err)
+			}
+			releaseAccount()
+		},
+	}, nil
+}
+
+// GetAccountModelConcurrency returns the current in-use count of a per-model slot.
+func (s *ConcurrencyService) GetAccountModelConcurrency(ctx context.Context, accountID int64, pattern string) (int, error) {
+	return s.cache.GetAccountModelConcurrency(ctx, accountID, pattern)
+}
+
 // AcquireUserSlot attempts to acquire a concurrency slot for a user.
 // If the user is at max concurrency, it waits until a slot is available or timeout.
 // Returns a release function that MUST be called when the request completes.
@@ -48,12 +48,36 @@ const (
 	PromoCodeStatusDisabled = domain.PromoCodeStatusDisabled
 )
 
+// PromoCode bonus type constants
+const (
+	PromoBonusTypeFixed   = domain.PromoBonusTypeFixed
+	PromoBonusTypePercent = domain.PromoBonusTypePercent
+)
+
+// PromoCode scope constants
+const (
+	PromoScopeRegister = domain.PromoScopeRegister
+	PromoScopePanel    = domain.PromoScopePanel
+	PromoScopeAny      = domain.PromoScopeAny
+)
+
+// PromoCodeUsage status constants
+const (
+	PromoUsageStatusApplied = domain.PromoUsageStatusApplied
+	PromoUsageStatusPending = domain.PromoUsageStatusPending
+)
+
 // Admin adjustment type constants
 const (
 	AdjustmentTypeAdminBalance     = domain.AdjustmentTypeAdminBalance     // 管理员调整余额
 	AdjustmentTypeAdminConcurrency = domain.AdjustmentTypeAdminConcurrency // 管理员调整并发数
 )
 
+// Balance ledger type constants
+const (
+	LedgerTypePayment = domain.LedgerTypePayment // 在线支付充值（退款扣回记为负数）
+)
+
 // Group subscription type constants
 const (
 	SubscriptionTypeStandard     = domain.SubscriptionTypeStandard     // 标准计费模式（按余额扣费）
@@ -96,12 +120,18 @@ const (
 	// TOTP 双因素认证设置
 	SettingKeyTotpEnabled = "totp_enabled" // 是否启用 TOTP 2FA 功能
 
+	// 通行密钥（WebAuthn）设置
+	SettingKeyPasskeyLoginEnabled = "passkey_login_enabled" // 是否允许使用通行密钥无密码登录
+
 	// LinuxDo Connect OAuth 登录设置
 	SettingKeyLinuxDoConnectEnabled      = "linuxdo_connect_enabled"
 	SettingKeyLinuxDoConnectClientID     = "linuxdo_connect_client_id"
 	SettingKeyLinuxDoConnectClientSecret = "linuxdo_connect_client_secret"
 	SettingKeyLinuxDoConnectRedirectURL  = "linuxdo_connect_redirect_url"
 
+	// 通用 OIDC / OAuth2 登录提供方（JSON 数组）
+	SettingKeyOIDCProviders = "oidc_providers"
+
 	// OEM设置
 	SettingKeySiteName                    = "site_name"                     // 网站名称
 	SettingKeySiteLogo                    = "site_logo"                     // 网站Logo (base64)
@@ -167,6 +197,41 @@ const (
 	// SettingKeyStreamTimeoutSettings stores JSON config for stream timeout handling.
 	SettingKeyStreamTimeoutSettings = "stream_timeout_settings"
 
+	// =========================
+	// Account Probe (synthetic health checks)
+	// =========================
+
+	// SettingKeyAccountProbeSettings stores JSON config for scheduled account probes.
+	SettingKeyAccountProbeSettings = "account_probe_settings"
+
+	// =========================
+	// API Key Abuse Detection
+	// =========================
+
+	// SettingKeyAPIKeyAbuseSettings stores JSON config for API key sharing/abuse detection.
+	SettingKeyAPIKeyAbuseSettings = "api_key_abuse_settings"
+
+	// =========================
+	// Spend Guard
+	// =========================
+
+	// SettingKeySpendGuardSettings stores JSON config for spend anomaly auto-suspension.
+	SettingKeySpendGuardSettings = "spend_guard_settings"
+
+	// =========================
+	// Referral Program
+	// =========================
+
+	// SettingKeyReferralSettings stores JSON config for the referral program (signup bonus, commission, caps, fraud controls).
+	SettingKeyReferralSettings = "referral_settings"
+
+	// =========================
+	// Online Payment
+	// =========================
+
+	// SettingKeyPaymentSettings stores JSON config for online balance top-up (providers, amount range, order expiry).
+	SettingKeyPaymentSettings = "payment_settings"
+
 	// =========================
 	// Sensitive Settings
 	// =========================
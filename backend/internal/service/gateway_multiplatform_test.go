@@ -1800,6 +1800,18 @@ func (m *mockConcurrencyCache) AcquireAccountSlot(ctx context.Context, accountID
 	return true, nil
 }
 
+func (m *mockConcurrencyCache) AcquireAccountModelSlot(ctx context.Context, accountID int64, pattern string, maxConcurrency int, requestID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockConcurrencyCache) ReleaseAccountModelSlot(ctx context.Context, accountID int64, pattern string, requestID string) error {
+	return nil
+}
+
+func (m *mockConcurrencyCache) GetAccountModelConcurrency(ctx context.Context, accountID int64, pattern string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockConcurrencyCache) ReleaseAccountSlot(ctx context.Context, accountID int64, requestID string) error {
 	return nil
 }
@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+// 对冲请求默认配置
+const (
+	DefaultHedgeDelayMs  = 2000
+	DefaultHedgeMaxRatio = 0.1
+)
+
 type Group struct {
 	ID             int64
 	Name           string
@@ -52,6 +58,14 @@ type Group struct {
 	// 分组排序
 	SortOrder int
 
+	// 对冲请求配置：首账号在 HedgeDelayMs 内未返回响应头时，向第二个账号发起相同请求
+	HedgeEnabled  bool
+	HedgeDelayMs  int
+	HedgeMaxRatio float64 // 对冲落败分支消耗的上游 token 占正常用量的比例上限（0~1）
+
+	// 调度时间窗口：未单独配置窗口的账号按分组规则启停调度，nil 表示不限制
+	ScheduleRule *ScheduleRule
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -99,6 +113,19 @@ func (g *Group) GetImagePrice(imageSize string) *float64 {
 	}
 }
 
+// IsHedgeEnabled 是否对该分组的请求启用对冲
+func (g *Group) IsHedgeEnabled() bool {
+	return g.HedgeEnabled && g.HedgeMaxRatio > 0
+}
+
+// HedgeDelay 返回触发对冲请求的响应头等待阈值
+func (g *Group) HedgeDelay() time.Duration {
+	if g.HedgeDelayMs <= 0 {
+		return DefaultHedgeDelayMs * time.Millisecond
+	}
+	return time.Duration(g.HedgeDelayMs) * time.Millisecond
+}
+
 // IsGroupContextValid reports whether a group from context has the fields required for routing decisions.
 func IsGroupContextValid(group *Group) bool {
 	if group == nil {
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+var (
+	ErrImpersonationNotFound       = infraerrors.NotFound("IMPERSONATION_NOT_FOUND", "impersonation session not found")
+	ErrImpersonationEnded          = infraerrors.Unauthorized("IMPERSONATION_ENDED", "impersonation session has ended")
+	ErrImpersonationTargetAdmin    = infraerrors.BadRequest("IMPERSONATION_TARGET_ADMIN", "cannot impersonate an admin user")
+	ErrImpersonationTargetSelf     = infraerrors.BadRequest("IMPERSONATION_TARGET_SELF", "cannot impersonate yourself")
+	ErrImpersonationTargetInactive = infraerrors.BadRequest("IMPERSONATION_TARGET_INACTIVE", "cannot impersonate an inactive user")
+	ErrImpersonationReasonRequired = infraerrors.BadRequest("IMPERSONATION_REASON_REQUIRED", "a reason is required to impersonate a user")
+)
+
+// 模拟登录时长（分钟）
+const (
+	DefaultImpersonationMinutes = 30
+	MinImpersonationMinutes     = 5
+	MaxImpersonationMinutes     = 120
+)
+
+// impersonationRecordTimeout 记录模拟操作的超时时间（请求结束后写入，不阻塞太久）
+const impersonationRecordTimeout = 3 * time.Second
+
+// ImpersonationSession 一次管理员模拟登录会话
+type ImpersonationSession struct {
+	ID           int64      `json:"id"`
+	AdminUserID  int64      `json:"admin_user_id"`
+	TargetUserID int64      `json:"target_user_id"`
+	Reason       string     `json:"reason"`
+	IPAddress    string     `json:"ip_address"`
+	UserAgent    string     `json:"user_agent"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	EndedBy      *int64     `json:"ended_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	// 关联信息（列表展示用）
+	AdminEmail  string `json:"admin_email"`
+	TargetEmail string `json:"target_email"`
+	ActionCount int    `json:"action_count"`
+}
+
+// IsActive 会话未被结束且未过期
+func (s *ImpersonationSession) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ImpersonationAction 模拟期间的一次请求
+type ImpersonationAction struct {
+	ID           int64     `json:"id"`
+	SessionID    int64     `json:"session_id"`
+	AdminUserID  int64     `json:"admin_user_id"`
+	TargetUserID int64     `json:"target_user_id"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	StatusCode   int       `json:"status_code"`
+	IPAddress    string    `json:"ip_address"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ImpersonationSessionFilter 模拟会话列表过滤条件
+type ImpersonationSessionFilter struct {
+	AdminUserID  int64
+	TargetUserID int64
+	ActiveOnly   bool
+}
+
+// ImpersonationStartResult 开始模拟登录的结果
+type ImpersonationStartResult struct {
+	Token     string                `json:"token"`
+	ExpiresAt time.Time             `json:"expires_at"`
+	Session   *ImpersonationSession `json:"session"`
+}
+
+// ImpersonationRepository 模拟登录会话与审计记录的数据访问接口
+type ImpersonationRepository interface {
+	CreateSession(ctx context.Context, session *ImpersonationSession) error
+	// GetSession 不存在时返回 ErrImpersonationNotFound
+	GetSession(ctx context.Context, id int64) (*ImpersonationSession, error)
+	// EndSession 结束会话；已结束的会话保持原结束时间不变
+	EndSession(ctx context.Context, id, endedBy int64, endedAt time.Time) error
+	ListSessions(ctx context.Context, params pagination.PaginationParams, filter ImpersonationSessionFilter) ([]ImpersonationSession, *pagination.PaginationResult, error)
+
+	CreateAction(ctx context.Context, action *ImpersonationAction) error
+	ListActions(ctx context.Context, sessionID int64, params pagination.PaginationParams) ([]ImpersonationAction, *pagination.PaginationResult, error)
+}
+
+// ImpersonationService 管理员"以用户身份查看"
+//
+// - 管理员为普通用户签发短期模拟 Token（带 imp 标记，无 Refresh Token，到期自动失效）
+// - 每次请求都会校验会话仍有效、发起管理员仍是启用状态的管理员，并记录请求（含真实管理员 ID）
+// - 修改密码 / 2FA / 创建 API Key 等敏感操作在路由层禁止
+type ImpersonationService struct {
+	impersonationRepo ImpersonationRepository
+	userRepo          UserRepository
+	authService       *AuthService
+}
+
+// NewImpersonationService 创建模拟登录服务
+func NewImpersonationService(impersonationRepo ImpersonationRepository, userRepo UserRepository, authService *AuthService) *ImpersonationService {
+	return &ImpersonationService{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		authService:       authService,
+	}
+}
+
+// Start 为目标用户签发模拟 Token
+func (s *ImpersonationService) Start(ctx context.Context, adminID, targetUserID int64, reason string, minutes int, ipAddress, userAgent string) (*ImpersonationStartResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrImpersonationReasonRequired
+	}
+	if adminID == targetUserID {
+		return nil, ErrImpersonationTargetSelf
+	}
+	if minutes <= 0 {
+		minutes = DefaultImpersonationMinutes
+	}
+	if minutes < MinImpersonationMinutes || minutes > MaxImpersonationMinutes {
+		return nil, infraerrors.BadRequest("IMPERSONATION_INVALID_DURATION",
+			fmt.Sprintf("duration_minutes must be between %d-%d", MinImpersonationMinutes, MaxImpersonationMinutes))
+	}
+
+	target, err := s.userRepo.GetByID(ctx, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsAdmin() {
+		return nil, ErrImpersonationTargetAdmin
+	}
+	if !target.IsActive() {
+		return nil, ErrImpersonationTargetInactive
+	}
+
+	now := time.Now()
+	session := &ImpersonationSession{
+		AdminUserID:  adminID,
+		TargetUserID: target.ID,
+		Reason:       truncateString(reason, 500),
+		IPAddress:    ipAddress,
+		UserAgent:    truncateString(userAgent, 500),
+		ExpiresAt:    now.Add(time.Duration(minutes) * time.Minute),
+	}
+	if err := s.impersonationRepo.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("create impersonation session: %w", err)
+	}
+
+	token, err := s.authService.GenerateImpersonationToken(target, adminID, session.ID, session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Impersonation] started: session=%d admin=%d target=%d expires_at=%s", session.ID, adminID, target.ID, session.ExpiresAt.Format(time.RFC3339))
+
+	session.TargetEmail = target.Email
+	return &ImpersonationStartResult{Token: token, ExpiresAt: session.ExpiresAt, Session: session}, nil
+}
+
+// Authorize 校验模拟 Token 对应的会话：未结束、未过期、与 Token 一致，且发起管理员仍为启用状态的管理员
+func (s *ImpersonationService) Authorize(ctx context.Context, claims *JWTClaims) (*ImpersonationSession, error) {
+	if claims == nil || claims.ImpersonatorID == 0 {
+		return nil, ErrInvalidToken
+	}
+	session, err := s.impersonationRepo.GetSession(ctx, claims.ImpersonationID)
+	if err != nil {
+		if errors.Is(err, ErrImpersonationNotFound) {
+			return nil, ErrImpersonationEnded
+		}
+		return nil, err
+	}
+	if session.AdminUserID != claims.ImpersonatorID || session.TargetUserID != claims.UserID {
+		return nil, ErrInvalidToken
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, ErrImpersonationEnded
+	}
+
+	admin, err := s.userRepo.GetByID(ctx, session.AdminUserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrImpersonationEnded
+		}
+		return nil, err
+	}
+	if !admin.IsActive() || !admin.IsAdmin() {
+		return nil, ErrImpersonationEnded
+	}
+	return session, nil
+}
+
+// End 结束模拟会话（管理员主动退出，或在后台强制结束）
+func (s *ImpersonationService) End(ctx context.Context, sessionID, actorID int64) (*ImpersonationSession, error) {
+	if _, err := s.impersonationRepo.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	if err := s.impersonationRepo.EndSession(ctx, sessionID, actorID, time.Now()); err != nil {
+		return nil, err
+	}
+	log.Printf("[Impersonation] ended: session=%d by=%d", sessionID, actorID)
+	return s.impersonationRepo.GetSession(ctx, sessionID)
+}
+
+// RecordAction 记录模拟期间的一次请求（best-effort，失败只记录日志）
+func (s *ImpersonationService) RecordAction(ctx context.Context, action *ImpersonationAction) {
+	if s == nil || action == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), impersonationRecordTimeout)
+	defer cancel()
+
+	action.Method = truncateString(action.Method, 10)
+	action.Path = truncateString(action.Path, 1000)
+	if err := s.impersonationRepo.CreateAction(ctx, action); err != nil {
+		log.Printf("[Impersonation] record action failed: session=%d admin=%d %s %s: %v",
+			action.SessionID, action.AdminUserID, action.Method, action.Path, err)
+	}
+}
+
+// ListSessions 管理员分页查看模拟会话
+func (s *ImpersonationService) ListSessions(ctx context.Context, params pagination.PaginationParams, filter ImpersonationSessionFilter) ([]ImpersonationSession, *pagination.PaginationResult, error) {
+	return s.impersonationRepo.ListSessions(ctx, params, filter)
+}
+
+// ListActions 管理员查看某次模拟会话的操作记录
+func (s *ImpersonationService) ListActions(ctx context.Context, sessionID int64, params pagination.PaginationParams) ([]ImpersonationAction, *pagination.PaginationResult, error) {
+	if _, err := s.impersonationRepo.GetSession(ctx, sessionID); err != nil {
+		return nil, nil, err
+	}
+	return s.impersonationRepo.ListActions(ctx, sessionID, params)
+}
@@ -0,0 +1,168 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type impersonationRepoStub struct {
+	ImpersonationRepository
+
+	sessions map[int64]*ImpersonationSession
+	nextID   int64
+}
+
+func newImpersonationRepoStub() *impersonationRepoStub {
+	return &impersonationRepoStub{sessions: map[int64]*ImpersonationSession{}}
+}
+
+func (s *impersonationRepoStub) CreateSession(ctx context.Context, session *ImpersonationSession) error {
+	s.nextID++
+	session.ID = s.nextID
+	session.CreatedAt = time.Now()
+	copied := *session
+	s.sessions[session.ID] = &copied
+	return nil
+}
+
+func (s *impersonationRepoStub) GetSession(ctx context.Context, id int64) (*ImpersonationSession, error) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrImpersonationNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (s *impersonationRepoStub) EndSession(ctx context.Context, id, endedBy int64, endedAt time.Time) error {
+	if session, ok := s.sessions[id]; ok && session.EndedAt == nil {
+		session.EndedAt = &endedAt
+		session.EndedBy = &endedBy
+	}
+	return nil
+}
+
+type impersonationUserRepoStub struct {
+	UserRepository
+
+	users map[int64]*User
+}
+
+func (s *impersonationUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func newImpersonationTestService() (*ImpersonationService, *impersonationRepoStub, *impersonationUserRepoStub) {
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", AccessTokenExpireMinutes: 15}}
+	users := &impersonationUserRepoStub{users: map[int64]*User{
+		1: {ID: 1, Email: "admin@example.com", Role: RoleAdmin, Status: StatusActive},
+		2: {ID: 2, Email: "user@example.com", Role: RoleUser, Status: StatusActive},
+		3: {ID: 3, Email: "other-admin@example.com", Role: RoleAdmin, Status: StatusActive},
+		4: {ID: 4, Email: "disabled@example.com", Role: RoleUser, Status: StatusDisabled},
+	}}
+	repo := newImpersonationRepoStub()
+	authService := NewAuthService(users, nil, nil, cfg, nil, nil, nil, nil, nil)
+	return NewImpersonationService(repo, users, authService), repo, users
+}
+
+func TestImpersonationService_StartValidation(t *testing.T) {
+	svc, _, _ := newImpersonationTestService()
+	ctx := context.Background()
+
+	_, err := svc.Start(ctx, 1, 2, "   ", 0, "", "")
+	require.ErrorIs(t, err, ErrImpersonationReasonRequired)
+
+	_, err = svc.Start(ctx, 1, 1, "debug", 0, "", "")
+	require.ErrorIs(t, err, ErrImpersonationTargetSelf)
+
+	_, err = svc.Start(ctx, 1, 3, "debug", 0, "", "")
+	require.ErrorIs(t, err, ErrImpersonationTargetAdmin)
+
+	_, err = svc.Start(ctx, 1, 4, "debug", 0, "", "")
+	require.ErrorIs(t, err, ErrImpersonationTargetInactive)
+
+	_, err = svc.Start(ctx, 1, 2, "debug", MaxImpersonationMinutes+1, "", "")
+	require.Error(t, err)
+	_, err = svc.Start(ctx, 1, 2, "debug", MinImpersonationMinutes-1, "", "")
+	require.Error(t, err)
+}
+
+func TestImpersonationService_StartIssuesMarkedToken(t *testing.T) {
+	svc, _, _ := newImpersonationTestService()
+
+	before := time.Now()
+	result, err := svc.Start(context.Background(), 1, 2, "  ticket #42  ", 0, "10.0.0.1", "Browser")
+	require.NoError(t, err)
+	require.Equal(t, "ticket #42", result.Session.Reason)
+	require.WithinDuration(t, before.Add(DefaultImpersonationMinutes*time.Minute), result.ExpiresAt, 2*time.Second)
+
+	claims, err := svc.authService.ValidateToken(result.Token)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), claims.UserID)
+	require.Equal(t, int64(1), claims.ImpersonatorID)
+	require.Equal(t, result.Session.ID, claims.ImpersonationID)
+	require.Empty(t, claims.SessionID)
+
+	// 模拟 Token 不可用于刷新
+	_, err = svc.authService.RefreshToken(context.Background(), result.Token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestImpersonationService_Authorize(t *testing.T) {
+	svc, repo, users := newImpersonationTestService()
+	ctx := context.Background()
+
+	result, err := svc.Start(ctx, 1, 2, "debug", 0, "", "")
+	require.NoError(t, err)
+	claims, err := svc.authService.ValidateToken(result.Token)
+	require.NoError(t, err)
+
+	session, err := svc.Authorize(ctx, claims)
+	require.NoError(t, err)
+	require.Equal(t, result.Session.ID, session.ID)
+
+	// 普通 Token 不是模拟 Token
+	_, err = svc.Authorize(ctx, &JWTClaims{UserID: 2})
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Token 与会话不一致
+	forged := *claims
+	forged.ImpersonatorID = 3
+	_, err = svc.Authorize(ctx, &forged)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// 会话不存在
+	missing := *claims
+	missing.ImpersonationID = 999
+	_, err = svc.Authorize(ctx, &missing)
+	require.ErrorIs(t, err, ErrImpersonationEnded)
+
+	// 发起管理员被降级后立即失效
+	users.users[1].Role = RoleUser
+	_, err = svc.Authorize(ctx, claims)
+	require.ErrorIs(t, err, ErrImpersonationEnded)
+	users.users[1].Role = RoleAdmin
+
+	// 会话过期
+	repo.sessions[session.ID].ExpiresAt = time.Now().Add(-time.Second)
+	_, err = svc.Authorize(ctx, claims)
+	require.ErrorIs(t, err, ErrImpersonationEnded)
+	repo.sessions[session.ID].ExpiresAt = time.Now().Add(time.Minute)
+
+	// 主动结束
+	ended, err := svc.End(ctx, session.ID, 1)
+	require.NoError(t, err)
+	require.NotNil(t, ended.EndedAt)
+	require.Equal(t, int64(1), *ended.EndedBy)
+	_, err = svc.Authorize(ctx, claims)
+	require.ErrorIs(t, err, ErrImpersonationEnded)
+}
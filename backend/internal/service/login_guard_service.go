@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var (
+	ErrLoginLocked          = infraerrors.TooManyRequests("LOGIN_LOCKED", "too many failed login attempts, please try again later")
+	ErrLoginThrottled       = infraerrors.TooManyRequests("LOGIN_THROTTLED", "please wait before trying again")
+	ErrLoginCaptchaRequired = infraerrors.BadRequest("LOGIN_CAPTCHA_REQUIRED", "please complete the captcha verification")
+	ErrLoginLockNotFound    = infraerrors.NotFound("LOGIN_LOCK_NOT_FOUND", "login lock not found")
+	ErrLoginLockInvalidKind = infraerrors.BadRequest("LOGIN_LOCK_INVALID_SCOPE", "scope must be account or ip")
+)
+
+// 登录失败统计维度
+const (
+	LoginGuardScopeAccount = "account"
+	LoginGuardScopeIP      = "ip"
+)
+
+// LoginFailureState 某个维度在统计窗口内的失败情况
+type LoginFailureState struct {
+	Count        int
+	LastFailedAt time.Time
+}
+
+// LoginLock 一条锁定记录
+type LoginLock struct {
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	LockedUntil time.Time `json:"locked_until"`
+	UserID      int64     `json:"user_id,omitempty"`
+}
+
+// LoginGuardCache 登录失败计数与锁定状态（Redis）
+type LoginGuardCache interface {
+	// GetFailures 返回失败计数；没有记录时返回零值
+	GetFailures(ctx context.Context, scope, key string) (*LoginFailureState, error)
+	// RecordFailure 失败计数 +1 并刷新窗口，返回最新状态
+	RecordFailure(ctx context.Context, scope, key string, at time.Time, window time.Duration) (*LoginFailureState, error)
+	ResetFailures(ctx context.Context, scope, key string) error
+	SetLock(ctx context.Context, scope, key string, until time.Time) error
+	// GetLock 返回锁定截止时间；未锁定时返回零值
+	GetLock(ctx context.Context, scope, key string) (time.Time, error)
+	// DeleteLock 解除锁定，返回是否存在锁定记录
+	DeleteLock(ctx context.Context, scope, key string) (bool, error)
+	// ListLocks 列出尚未到期的锁定
+	ListLocks(ctx context.Context, scope string, now time.Time) ([]LoginLock, error)
+}
+
+// LoginGuardService 登录防暴力破解：按账号 / IP 统计失败，递增等待、强制人机验证与临时锁定
+type LoginGuardService struct {
+	cfg              config.LoginGuardConfig
+	cache            LoginGuardCache
+	userRepo         UserRepository
+	emailService     *EmailService
+	settingService   *SettingService
+	turnstileService *TurnstileService
+}
+
+// NewLoginGuardService 创建登录防护服务
+func NewLoginGuardService(
+	cfg *config.Config,
+	cache LoginGuardCache,
+	userRepo UserRepository,
+	emailService *EmailService,
+	settingService *SettingService,
+	turnstileService *TurnstileService,
+) *LoginGuardService {
+	s := &LoginGuardService{
+		cache:            cache,
+		userRepo:         userRepo,
+		emailService:     emailService,
+		settingService:   settingService,
+		turnstileService: turnstileService,
+	}
+	if cfg != nil {
+		s.cfg = cfg.LoginGuard
+	}
+	return s
+}
+
+func (s *LoginGuardService) enabled() bool {
+	return s != nil && s.cfg.Enabled && s.cache != nil
+}
+
+func (s *LoginGuardService) window() time.Duration {
+	if s.cfg.FailureWindowMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(s.cfg.FailureWindowMinutes) * time.Minute
+}
+
+func (s *LoginGuardService) lockoutDuration() time.Duration {
+	if s.cfg.LockoutMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(s.cfg.LockoutMinutes) * time.Minute
+}
+
+func normalizeLoginAccount(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// progressiveDelay 失败 n 次后下一次尝试前需等待的时长：1s、2s、4s…，不超过上限
+func (s *LoginGuardService) progressiveDelay(failures int) time.Duration {
+	if s.cfg.DelayAfterFailures <= 0 || failures < s.cfg.DelayAfterFailures {
+		return 0
+	}
+	maxDelay := time.Duration(s.cfg.MaxDelaySeconds) * time.Second
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+	exp := failures - s.cfg.DelayAfterFailures
+	if exp > 30 {
+		return maxDelay
+	}
+	delay := time.Duration(1<<exp) * time.Second
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func retryAfterMetadata(wait time.Duration) map[string]string {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return map[string]string{"retry_after": strconv.Itoa(seconds)}
+}
+
+// Check 登录前检查锁定与递增等待，返回本次是否需要人机验证
+// 缓存故障时放行（fail-open），仍保留路由级限流
+func (s *LoginGuardService) Check(ctx context.Context, email, ip string) (bool, error) {
+	if !s.enabled() {
+		return false, nil
+	}
+	now := time.Now()
+	account := normalizeLoginAccount(email)
+
+	for _, target := range []struct{ scope, key string }{
+		{LoginGuardScopeAccount, account},
+		{LoginGuardScopeIP, ip},
+	} {
+		if target.key == "" {
+			continue
+		}
+		until, err := s.cache.GetLock(ctx, target.scope, target.key)
+		if err != nil {
+			log.Printf("[LoginGuard] get lock failed: scope=%s err=%v", target.scope, err)
+			return false, nil
+		}
+		if until.After(now) {
+			return false, ErrLoginLocked.WithMetadata(retryAfterMetadata(until.Sub(now)))
+		}
+	}
+
+	accountState, err := s.cache.GetFailures(ctx, LoginGuardScopeAccount, account)
+	if err != nil {
+		log.Printf("[LoginGuard] get account failures failed: %v", err)
+		return false, nil
+	}
+	if delay := s.progressiveDelay(accountState.Count); delay > 0 {
+		if next := accountState.LastFailedAt.Add(delay); next.After(now) {
+			return false, ErrLoginThrottled.WithMetadata(retryAfterMetadata(next.Sub(now)))
+		}
+	}
+
+	ipCount := 0
+	if ip != "" {
+		ipState, err := s.cache.GetFailures(ctx, LoginGuardScopeIP, ip)
+		if err != nil {
+			log.Printf("[LoginGuard] get ip failures failed: %v", err)
+		} else {
+			ipCount = ipState.Count
+		}
+	}
+	return s.captchaRequired(ctx, max(accountState.Count, ipCount)), nil
+}
+
+// captchaRequired 失败次数达到阈值且 Turnstile 密钥已配置时要求人机验证
+func (s *LoginGuardService) captchaRequired(ctx context.Context, failures int) bool {
+	if s.cfg.CaptchaAfterFailures <= 0 || failures < s.cfg.CaptchaAfterFailures {
+		return false
+	}
+	return s.turnstileService != nil && s.turnstileService.IsChallengeAvailable(ctx)
+}
+
+// VerifyCaptcha 校验强制人机验证（不受 Turnstile 全局开关影响）
+func (s *LoginGuardService) VerifyCaptcha(ctx context.Context, token, ip string) error {
+	if strings.TrimSpace(token) == "" {
+		return ErrLoginCaptchaRequired
+	}
+	return s.turnstileService.VerifyChallenge(ctx, token, ip)
+}
+
+// RecordFailure 记录一次密码错误，达到阈值时锁定；返回下一次尝试是否需要人机验证
+func (s *LoginGuardService) RecordFailure(ctx context.Context, email, ip string) bool {
+	if !s.enabled() {
+		return false
+	}
+	now := time.Now()
+	account := normalizeLoginAccount(email)
+	failures := 0
+
+	if account != "" {
+		state, err := s.cache.RecordFailure(ctx, LoginGuardScopeAccount, account, now, s.window())
+		if err != nil {
+			log.Printf("[LoginGuard] record account failure failed: %v", err)
+		} else {
+			failures = state.Count
+			if s.cfg.AccountMaxFailures > 0 && state.Count >= s.cfg.AccountMaxFailures {
+				s.lock(ctx, LoginGuardScopeAccount, account, now)
+				s.notifyLocked(ctx, account, ip, now.Add(s.lockoutDuration()))
+			}
+		}
+	}
+
+	if ip != "" {
+		state, err := s.cache.RecordFailure(ctx, LoginGuardScopeIP, ip, now, s.window())
+		if err != nil {
+			log.Printf("[LoginGuard] record ip failure failed: %v", err)
+		} else {
+			failures = max(failures, state.Count)
+			if s.cfg.IPMaxFailures > 0 && state.Count >= s.cfg.IPMaxFailures {
+				s.lock(ctx, LoginGuardScopeIP, ip, now)
+			}
+		}
+	}
+
+	return s.captchaRequired(ctx, failures)
+}
+
+// lock 锁定并清零计数，解锁后重新开始统计
+func (s *LoginGuardService) lock(ctx context.Context, scope, key string, now time.Time) {
+	until := now.Add(s.lockoutDuration())
+	if err := s.cache.SetLock(ctx, scope, key, until); err != nil {
+		log.Printf("[LoginGuard] set lock failed: scope=%s err=%v", scope, err)
+		return
+	}
+	if err := s.cache.ResetFailures(ctx, scope, key); err != nil {
+		log.Printf("[LoginGuard] reset failures failed: scope=%s err=%v", scope, err)
+	}
+	log.Printf("[LoginGuard] locked: scope=%s key=%s until=%s", scope, key, until.UTC().Format(time.RFC3339))
+}
+
+// RecordSuccess 登录成功后清零账号计数（IP 计数保留，避免撞库时穿插成功登录绕过）
+func (s *LoginGuardService) RecordSuccess(ctx context.Context, email string) {
+	if !s.enabled() {
+		return
+	}
+	if err := s.cache.ResetFailures(ctx, LoginGuardScopeAccount, normalizeLoginAccount(email)); err != nil {
+		log.Printf("[LoginGuard] reset account failures failed: %v", err)
+	}
+}
+
+// ListLocks 列出当前被锁定的账号与 IP（管理员查看）
+func (s *LoginGuardService) ListLocks(ctx context.Context) ([]LoginLock, error) {
+	if s.cache == nil {
+		return []LoginLock{}, nil
+	}
+	now := time.Now()
+	out := make([]LoginLock, 0)
+	for _, scope := range []string{LoginGuardScopeAccount, LoginGuardScopeIP} {
+		locks, err := s.cache.ListLocks(ctx, scope, now)
+		if err != nil {
+			return nil, fmt.Errorf("list %s locks: %w", scope, err)
+		}
+		for i := range locks {
+			if scope == LoginGuardScopeAccount && s.userRepo != nil {
+				if user, err := s.userRepo.GetByEmail(ctx, locks[i].Key); err == nil && user != nil {
+					locks[i].UserID = user.ID
+				}
+			}
+		}
+		out = append(out, locks...)
+	}
+	return out, nil
+}
+
+// Unlock 解除锁定并清零计数（管理员操作）
+func (s *LoginGuardService) Unlock(ctx context.Context, scope, key string) error {
+	if scope != LoginGuardScopeAccount && scope != LoginGuardScopeIP {
+		return ErrLoginLockInvalidKind
+	}
+	key = strings.TrimSpace(key)
+	if scope == LoginGuardScopeAccount {
+		key = normalizeLoginAccount(key)
+	}
+	if s.cache == nil || key == "" {
+		return ErrLoginLockNotFound
+	}
+	existed, err := s.cache.DeleteLock(ctx, scope, key)
+	if err != nil {
+		return fmt.Errorf("delete lock: %w", err)
+	}
+	if err := s.cache.ResetFailures(ctx, scope, key); err != nil {
+		log.Printf("[LoginGuard] reset failures failed: scope=%s err=%v", scope, err)
+	}
+	if !existed {
+		return ErrLoginLockNotFound
+	}
+	return nil
+}
+
+// notifyLocked 账号锁定时邮件通知用户（best-effort；不存在的账号不发送）
+func (s *LoginGuardService) notifyLocked(ctx context.Context, account, ip string, until time.Time) {
+	if !s.cfg.NotifyOnLockout || s.emailService == nil || s.userRepo == nil {
+		return
+	}
+	user, err := s.userRepo.GetByEmail(ctx, account)
+	if err != nil || user == nil || strings.TrimSpace(user.Email) == "" || isReservedEmail(user.Email) {
+		return
+	}
+	siteName := "Sub2API"
+	if s.settingService != nil {
+		siteName = s.settingService.GetSiteName(ctx)
+	}
+	subject, body := buildLoginLockedEmail(siteName, ip, until)
+	email := user.Email
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.emailService.SendEmail(sendCtx, email, subject, body); err != nil {
+			log.Printf("[LoginGuard] send lockout email failed: user=%d err=%v", user.ID, err)
+		}
+	}()
+}
+
+func buildLoginLockedEmail(siteName, ip string, until time.Time) (string, string) {
+	subject := fmt.Sprintf("[%s] Sign-in temporarily locked after failed attempts", siteName)
+	if ip == "" {
+		ip = "unknown"
+	}
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>We noticed repeated failed sign-in attempts on your %s account (last from IP %s). To protect your account, password sign-in is locked until %s.</p>
+<p>If these attempts were not made by you, someone may be trying to guess your password. We recommend choosing a strong, unique password and enabling two-factor authentication.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(siteName), html.EscapeString(ip), until.UTC().Format("2006-01-02 15:04 UTC"))
+	return subject, body
+}
@@ -0,0 +1,190 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// loginGuardCacheStub 内存版 LoginGuardCache
+type loginGuardCacheStub struct {
+	failures map[string]*LoginFailureState
+	locks    map[string]time.Time
+}
+
+func newLoginGuardCacheStub() *loginGuardCacheStub {
+	return &loginGuardCacheStub{
+		failures: map[string]*LoginFailureState{},
+		locks:    map[string]time.Time{},
+	}
+}
+
+func (s *loginGuardCacheStub) GetFailures(ctx context.Context, scope, key string) (*LoginFailureState, error) {
+	if state, ok := s.failures[scope+":"+key]; ok {
+		copied := *state
+		return &copied, nil
+	}
+	return &LoginFailureState{}, nil
+}
+
+func (s *loginGuardCacheStub) RecordFailure(ctx context.Context, scope, key string, at time.Time, window time.Duration) (*LoginFailureState, error) {
+	state, ok := s.failures[scope+":"+key]
+	if !ok {
+		state = &LoginFailureState{}
+		s.failures[scope+":"+key] = state
+	}
+	state.Count++
+	state.LastFailedAt = at
+	copied := *state
+	return &copied, nil
+}
+
+func (s *loginGuardCacheStub) ResetFailures(ctx context.Context, scope, key string) error {
+	delete(s.failures, scope+":"+key)
+	return nil
+}
+
+func (s *loginGuardCacheStub) SetLock(ctx context.Context, scope, key string, until time.Time) error {
+	s.locks[scope+":"+key] = until
+	return nil
+}
+
+func (s *loginGuardCacheStub) GetLock(ctx context.Context, scope, key string) (time.Time, error) {
+	return s.locks[scope+":"+key], nil
+}
+
+func (s *loginGuardCacheStub) DeleteLock(ctx context.Context, scope, key string) (bool, error) {
+	_, ok := s.locks[scope+":"+key]
+	delete(s.locks, scope+":"+key)
+	return ok, nil
+}
+
+func (s *loginGuardCacheStub) ListLocks(ctx context.Context, scope string, now time.Time) ([]LoginLock, error) {
+	out := make([]LoginLock, 0)
+	for k, until := range s.locks {
+		if len(k) > len(scope) && k[:len(scope)+1] == scope+":" && until.After(now) {
+			out = append(out, LoginLock{Scope: scope, Key: k[len(scope)+1:], LockedUntil: until})
+		}
+	}
+	return out, nil
+}
+
+func newTestLoginGuard(turnstileKeys bool) (*LoginGuardService, *loginGuardCacheStub) {
+	cfg := &config.Config{LoginGuard: config.LoginGuardConfig{
+		Enabled:              true,
+		FailureWindowMinutes: 15,
+		AccountMaxFailures:   5,
+		IPMaxFailures:        8,
+		LockoutMinutes:       15,
+		DelayAfterFailures:   3,
+		MaxDelaySeconds:      30,
+		CaptchaAfterFailures: 2,
+	}}
+	values := map[string]string{}
+	if turnstileKeys {
+		values[SettingKeyTurnstileSiteKey] = "site"
+		values[SettingKeyTurnstileSecretKey] = "secret"
+	}
+	settingService := NewSettingService(&settingRepoStub{values: values}, cfg)
+	cache := newLoginGuardCacheStub()
+	return NewLoginGuardService(cfg, cache, nil, nil, settingService, NewTurnstileService(settingService, nil)), cache
+}
+
+func TestLoginGuardService_ProgressiveDelay(t *testing.T) {
+	svc, _ := newTestLoginGuard(false)
+	require.Equal(t, time.Duration(0), svc.progressiveDelay(2))
+	require.Equal(t, time.Second, svc.progressiveDelay(3))
+	require.Equal(t, 4*time.Second, svc.progressiveDelay(5))
+	require.Equal(t, 30*time.Second, svc.progressiveDelay(9))
+	require.Equal(t, 30*time.Second, svc.progressiveDelay(100))
+}
+
+func TestLoginGuardService_CaptchaAndThrottle(t *testing.T) {
+	ctx := context.Background()
+	svc, cache := newTestLoginGuard(true)
+
+	require.False(t, svc.RecordFailure(ctx, "User@Example.com", "1.1.1.1"))
+	require.True(t, svc.RecordFailure(ctx, "user@example.com ", "1.1.1.1"))
+
+	captcha, err := svc.Check(ctx, "user@example.com", "1.1.1.1")
+	require.NoError(t, err)
+	require.True(t, captcha)
+
+	// 达到递增等待阈值后，短时间内再次尝试被拒绝
+	svc.RecordFailure(ctx, "user@example.com", "1.1.1.1")
+	_, err = svc.Check(ctx, "user@example.com", "1.1.1.1")
+	require.ErrorIs(t, err, ErrLoginThrottled)
+	require.Equal(t, "1", infraerrors.FromError(err).Metadata["retry_after"])
+
+	// 等待结束后放行
+	cache.failures[LoginGuardScopeAccount+":user@example.com"].LastFailedAt = time.Now().Add(-2 * time.Second)
+	_, err = svc.Check(ctx, "user@example.com", "1.1.1.1")
+	require.NoError(t, err)
+
+	// 登录成功只清零账号计数，IP 仍需人机验证
+	svc.RecordSuccess(ctx, "user@example.com")
+	captcha, err = svc.Check(ctx, "other@example.com", "1.1.1.1")
+	require.NoError(t, err)
+	require.True(t, captcha)
+
+	// 未配置 Turnstile 密钥时不强制人机验证
+	noKeys, _ := newTestLoginGuard(false)
+	noKeys.RecordFailure(ctx, "user@example.com", "")
+	require.False(t, noKeys.RecordFailure(ctx, "user@example.com", ""))
+	require.ErrorIs(t, noKeys.VerifyCaptcha(ctx, "", ""), ErrLoginCaptchaRequired)
+}
+
+func TestLoginGuardService_LockoutAndUnlock(t *testing.T) {
+	ctx := context.Background()
+	svc, cache := newTestLoginGuard(false)
+
+	for i := 0; i < 5; i++ {
+		svc.RecordFailure(ctx, "user@example.com", "")
+	}
+	_, err := svc.Check(ctx, "USER@example.com", "2.2.2.2")
+	require.ErrorIs(t, err, ErrLoginLocked)
+	require.NotEmpty(t, infraerrors.FromError(err).Metadata["retry_after"])
+	require.NotContains(t, cache.failures, LoginGuardScopeAccount+":user@example.com")
+
+	locks, err := svc.ListLocks(ctx)
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	require.Equal(t, LoginGuardScopeAccount, locks[0].Scope)
+	require.Equal(t, "user@example.com", locks[0].Key)
+
+	require.ErrorIs(t, svc.Unlock(ctx, "device", "x"), ErrLoginLockInvalidKind)
+	require.NoError(t, svc.Unlock(ctx, LoginGuardScopeAccount, " User@Example.com "))
+	require.ErrorIs(t, svc.Unlock(ctx, LoginGuardScopeAccount, "user@example.com"), ErrLoginLockNotFound)
+	_, err = svc.Check(ctx, "user@example.com", "2.2.2.2")
+	require.NoError(t, err)
+
+	// IP 维度按独立阈值锁定
+	for i := 0; i < 8; i++ {
+		svc.RecordFailure(ctx, "", "3.3.3.3")
+	}
+	_, err = svc.Check(ctx, "someone@example.com", "3.3.3.3")
+	require.ErrorIs(t, err, ErrLoginLocked)
+}
+
+func TestLoginGuardService_Disabled(t *testing.T) {
+	ctx := context.Background()
+	var nilGuard *LoginGuardService
+	captcha, err := nilGuard.Check(ctx, "a@b.c", "1.1.1.1")
+	require.NoError(t, err)
+	require.False(t, captcha)
+	require.False(t, nilGuard.RecordFailure(ctx, "a@b.c", "1.1.1.1"))
+	nilGuard.RecordSuccess(ctx, "a@b.c")
+}
+
+func TestBuildLoginLockedEmail(t *testing.T) {
+	subject, body := buildLoginLockedEmail("Site", "<b>", time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC))
+	require.Equal(t, "[Site] Sign-in temporarily locked after failed attempts", subject)
+	require.Contains(t, body, "2026-01-02 03:04 UTC")
+	require.Contains(t, body, "&lt;b&gt;")
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/tidwall/gjson"
+)
+
+// OIDCSyntheticEmailDomain 是通用 OIDC 登录用户的合成邮箱后缀（RFC 保留域名）。
+const OIDCSyntheticEmailDomain = "@oidc-connect.invalid"
+
+const (
+	oidcDefaultScopes             = "openid email profile"
+	oidcDefaultFrontendCallback   = "/auth/oidc/callback"
+	oidcDefaultSubjectClaim       = "sub"
+	oidcDefaultEmailClaim         = "email"
+	oidcDefaultEmailVerifiedClaim = "email_verified"
+	oidcDefaultUsernameClaim      = "preferred_username"
+
+	oidcMaxProviders     = 20
+	oidcMaxSubjectLen    = 255
+	oidcMaxNameLen       = 50
+	oidcMaxAllowedDomain = 50
+)
+
+var oidcProviderSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,23}$`)
+
+// OIDCProviderConfig 管理员配置的 OIDC / OAuth2 登录提供方。
+//
+// 配置 DiscoveryURL 时自动从 /.well-known/openid-configuration 获取端点；
+// 对于不支持 discovery 的纯 OAuth2 提供方（如 GitHub），可手动配置 AuthorizeURL/TokenURL/UserInfoURL，
+// 手动配置的端点优先于 discovery 结果。
+type OIDCProviderConfig struct {
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+
+	DiscoveryURL string `json:"discovery_url"`
+	AuthorizeURL string `json:"authorize_url"`
+	TokenURL     string `json:"token_url"`
+	UserInfoURL  string `json:"userinfo_url"`
+
+	ClientID               string `json:"client_id"`
+	ClientSecret           string `json:"client_secret,omitempty"`
+	ClientSecretConfigured bool   `json:"client_secret_configured"`
+	TokenAuthMethod        string `json:"token_auth_method"` // client_secret_post / client_secret_basic / none
+	Scopes                 string `json:"scopes"`
+	UsePKCE                bool   `json:"use_pkce"`
+
+	RedirectURL         string `json:"redirect_url"`
+	FrontendRedirectURL string `json:"frontend_redirect_url"`
+
+	// 声明映射（gjson 路径，作用于 userinfo 响应）
+	SubjectClaim       string `json:"subject_claim"`
+	EmailClaim         string `json:"email_claim"`
+	EmailVerifiedClaim string `json:"email_verified_claim"` // 默认 email_verified，声明缺失视为未验证
+	UsernameClaim      string `json:"username_claim"`
+
+	// TrustAllEmails 为 true 时忽略 EmailVerifiedClaim，提供方返回的邮箱均视为已验证；
+	// 仅用于不返回验证状态、且只提供已验证邮箱的提供方。
+	TrustAllEmails bool `json:"trust_all_emails"`
+
+	// AllowedEmailDomains 非空时仅允许这些域名的已验证邮箱登录
+	AllowedEmailDomains []string `json:"allowed_email_domains"`
+	// TrustEmail 为 true 时，已验证邮箱会直接绑定到同邮箱的本地账号；
+	// 仅应对可信的企业 IdP 开启，否则存在账号接管风险。
+	TrustEmail bool `json:"trust_email"`
+}
+
+// OIDCPublicProvider 登录页展示的提供方信息
+type OIDCPublicProvider struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// OIDCEndpoints 解析后的授权端点
+type OIDCEndpoints struct {
+	AuthorizeURL string
+	TokenURL     string
+	UserInfoURL  string
+}
+
+// OIDCIdentity 从 userinfo 解析出的外部身份
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// ExternalIdentity 外部身份与本地用户的绑定关系
+type ExternalIdentity struct {
+	ID          int64
+	UserID      int64
+	Provider    string
+	Subject     string
+	Email       string
+	Username    string
+	LastLoginAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ExternalIdentityRepository 外部身份绑定数据访问接口
+type ExternalIdentityRepository interface {
+	// GetByProviderSubject 按 (provider, subject) 查询绑定，不存在时返回 nil, nil
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*ExternalIdentity, error)
+	// Create 创建绑定；(provider, subject) 已存在时忽略
+	Create(ctx context.Context, identity *ExternalIdentity) error
+	TouchLogin(ctx context.Context, id int64, email, username string) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// ParseOIDCUserInfo 按提供方的声明映射解析 userinfo 响应
+func ParseOIDCUserInfo(body string, provider *OIDCProviderConfig) (*OIDCIdentity, error) {
+	if !gjson.Valid(body) {
+		return nil, errors.New("userinfo is not valid json")
+	}
+
+	subject := strings.TrimSpace(oidcClaim(body, provider.SubjectClaim, oidcDefaultSubjectClaim))
+	if subject == "" {
+		return nil, errors.New("userinfo missing subject claim")
+	}
+	if !isSafeOIDCSubject(subject) {
+		return nil, errors.New("userinfo returned invalid subject claim")
+	}
+
+	identity := &OIDCIdentity{
+		Subject:  subject,
+		Email:    strings.ToLower(strings.TrimSpace(oidcClaim(body, provider.EmailClaim, oidcDefaultEmailClaim))),
+		Username: strings.TrimSpace(oidcClaim(body, provider.UsernameClaim, oidcDefaultUsernameClaim)),
+	}
+	if identity.Email != "" {
+		if _, err := mail.ParseAddress(identity.Email); err != nil {
+			identity.Email = ""
+		}
+	}
+	if identity.Email != "" {
+		if provider.TrustAllEmails {
+			identity.EmailVerified = true
+		} else {
+			verifiedPath := strings.TrimSpace(provider.EmailVerifiedClaim)
+			if verifiedPath == "" {
+				verifiedPath = oidcDefaultEmailVerifiedClaim
+			}
+			res := gjson.Get(body, verifiedPath)
+			// 声明缺失视为未验证；部分提供方以字符串形式返回 "true"
+			identity.EmailVerified = res.Exists() && (res.Bool() || strings.EqualFold(res.String(), "true"))
+		}
+	}
+	if identity.Username == "" {
+		identity.Username = firstNonEmptyString(
+			gjson.Get(body, "name").String(),
+			gjson.Get(body, "nickname").String(),
+			gjson.Get(body, "login").String(),
+		)
+	}
+	if identity.Username == "" {
+		identity.Username = provider.Slug + "_" + truncateString(subject, 32)
+	}
+	return identity, nil
+}
+
+func oidcClaim(body, path, fallback string) string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = fallback
+	}
+	res := gjson.Get(body, path)
+	if !res.Exists() {
+		return ""
+	}
+	return res.String()
+}
+
+func isSafeOIDCSubject(subject string) bool {
+	if len(subject) > oidcMaxSubjectLen {
+		return false
+	}
+	for _, r := range subject {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// oidcEmailDomainAllowed 检查邮箱域名是否在白名单内（白名单为空表示不限制）
+func oidcEmailDomainAllowed(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range domains {
+		if domain == d {
+			return true
+		}
+	}
+	return false
+}
+
+// oidcSyntheticEmail 基于 (provider, subject) 生成稳定的合成邮箱，用于未信任邮箱时的账号绑定
+func oidcSyntheticEmail(slug, subject string) string {
+	sum := sha256.Sum256([]byte(slug + ":" + subject))
+	return "oidc-" + slug + "-" + hex.EncodeToString(sum[:])[:32] + OIDCSyntheticEmailDomain
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalizeOIDCProvider 填充默认值并规范化字段
+func normalizeOIDCProvider(p *OIDCProviderConfig) {
+	p.Slug = strings.ToLower(strings.TrimSpace(p.Slug))
+	p.Name = strings.TrimSpace(p.Name)
+	p.DiscoveryURL = strings.TrimSpace(p.DiscoveryURL)
+	p.AuthorizeURL = strings.TrimSpace(p.AuthorizeURL)
+	p.TokenURL = strings.TrimSpace(p.TokenURL)
+	p.UserInfoURL = strings.TrimSpace(p.UserInfoURL)
+	p.ClientID = strings.TrimSpace(p.ClientID)
+	p.ClientSecret = strings.TrimSpace(p.ClientSecret)
+	p.TokenAuthMethod = strings.ToLower(strings.TrimSpace(p.TokenAuthMethod))
+	if p.TokenAuthMethod == "" {
+		p.TokenAuthMethod = "client_secret_post"
+	}
+	p.Scopes = strings.Join(strings.Fields(p.Scopes), " ")
+	if p.Scopes == "" {
+		p.Scopes = oidcDefaultScopes
+	}
+	p.RedirectURL = strings.TrimSpace(p.RedirectURL)
+	p.FrontendRedirectURL = strings.TrimSpace(p.FrontendRedirectURL)
+	if p.FrontendRedirectURL == "" {
+		p.FrontendRedirectURL = oidcDefaultFrontendCallback
+	}
+	p.SubjectClaim = strings.TrimSpace(p.SubjectClaim)
+	if p.SubjectClaim == "" {
+		p.SubjectClaim = oidcDefaultSubjectClaim
+	}
+	p.EmailClaim = strings.TrimSpace(p.EmailClaim)
+	if p.EmailClaim == "" {
+		p.EmailClaim = oidcDefaultEmailClaim
+	}
+	p.EmailVerifiedClaim = strings.TrimSpace(p.EmailVerifiedClaim)
+	if p.EmailVerifiedClaim == "" {
+		p.EmailVerifiedClaim = oidcDefaultEmailVerifiedClaim
+	}
+	p.UsernameClaim = strings.TrimSpace(p.UsernameClaim)
+	if p.UsernameClaim == "" {
+		p.UsernameClaim = oidcDefaultUsernameClaim
+	}
+
+	seen := make(map[string]struct{}, len(p.AllowedEmailDomains))
+	domains := make([]string, 0, len(p.AllowedEmailDomains))
+	for _, d := range p.AllowedEmailDomains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "@")
+		if d == "" {
+			continue
+		}
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		domains = append(domains, d)
+	}
+	p.AllowedEmailDomains = domains
+}
+
+// validateOIDCProvider 校验规范化后的提供方配置
+func validateOIDCProvider(p *OIDCProviderConfig) error {
+	if !oidcProviderSlugPattern.MatchString(p.Slug) {
+		return errors.New("slug must be 1-24 lowercase letters, digits or hyphens")
+	}
+	if p.Name == "" || len([]rune(p.Name)) > oidcMaxNameLen {
+		return fmt.Errorf("name is required and must be at most %d characters", oidcMaxNameLen)
+	}
+	if p.ClientID == "" {
+		return errors.New("client_id is required")
+	}
+
+	if p.DiscoveryURL == "" && (p.AuthorizeURL == "" || p.TokenURL == "" || p.UserInfoURL == "") {
+		return errors.New("discovery_url or authorize_url/token_url/userinfo_url is required")
+	}
+	for name, raw := range map[string]string{
+		"discovery_url": p.DiscoveryURL,
+		"authorize_url": p.AuthorizeURL,
+		"token_url":     p.TokenURL,
+		"userinfo_url":  p.UserInfoURL,
+	} {
+		if raw == "" {
+			continue
+		}
+		if err := config.ValidateAbsoluteHTTPURL(raw); err != nil {
+			return fmt.Errorf("%s invalid: %v", name, err)
+		}
+	}
+
+	if err := config.ValidateAbsoluteHTTPURL(p.RedirectURL); err != nil {
+		return fmt.Errorf("redirect_url invalid: %v", err)
+	}
+	if err := config.ValidateFrontendRedirectURL(p.FrontendRedirectURL); err != nil {
+		return fmt.Errorf("frontend_redirect_url invalid: %v", err)
+	}
+
+	switch p.TokenAuthMethod {
+	case "client_secret_post", "client_secret_basic":
+		if p.ClientSecret == "" {
+			return errors.New("client_secret is required")
+		}
+	case "none":
+		// 公共客户端没有 client_secret，必须使用 PKCE 防止授权码被截获后滥用
+		if !p.UsePKCE {
+			return errors.New("use_pkce is required when token_auth_method is none")
+		}
+	default:
+		return fmt.Errorf("unsupported token_auth_method: %s", p.TokenAuthMethod)
+	}
+
+	if len(p.AllowedEmailDomains) > oidcMaxAllowedDomain {
+		return fmt.Errorf("at most %d allowed_email_domains", oidcMaxAllowedDomain)
+	}
+	for _, d := range p.AllowedEmailDomains {
+		if strings.ContainsAny(d, " @/\t") || !strings.Contains(d, ".") {
+			return fmt.Errorf("invalid email domain: %s", d)
+		}
+	}
+	return nil
+}
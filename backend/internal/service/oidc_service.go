@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+const (
+	oidcDiscoveryCacheTTL  = time.Hour
+	oidcDiscoveryMaxBody   = 1 << 20
+	oidcDiscoveryWellKnown = "/.well-known/openid-configuration"
+)
+
+var (
+	ErrOIDCProviderNotFound = infraerrors.NotFound("OIDC_PROVIDER_NOT_FOUND", "oidc provider not found")
+	ErrOIDCProviderExists   = infraerrors.Conflict("OIDC_PROVIDER_EXISTS", "oidc provider slug already exists")
+	ErrOIDCProviderDisabled = infraerrors.NotFound("OAUTH_DISABLED", "oauth login is disabled")
+	ErrOIDCDiscoveryFailed  = infraerrors.ServiceUnavailable("OIDC_DISCOVERY_FAILED", "failed to load oidc provider metadata")
+	ErrOIDCEmailNotAllowed  = infraerrors.Forbidden("OIDC_EMAIL_DOMAIN_NOT_ALLOWED", "email domain is not allowed for this login provider")
+)
+
+// oidcLoginer 抽象 OAuth 登录/注册，便于测试
+type oidcLoginer interface {
+	LoginOrRegisterOAuth(ctx context.Context, email, username string) (string, *User, error)
+}
+
+type oidcDiscoveryEntry struct {
+	endpoints OIDCEndpoints
+	expiresAt time.Time
+}
+
+// OIDCService 管理通用 OIDC / OAuth2 登录提供方并完成外部身份绑定
+type OIDCService struct {
+	settingRepo  SettingRepository
+	identityRepo ExternalIdentityRepository
+	userRepo     UserRepository
+	loginer      oidcLoginer
+	httpClient   *http.Client
+
+	discoveryMu sync.Mutex
+	discovery   map[string]oidcDiscoveryEntry
+}
+
+// NewOIDCService 创建 OIDC 登录服务
+func NewOIDCService(
+	settingRepo SettingRepository,
+	identityRepo ExternalIdentityRepository,
+	userRepo UserRepository,
+	authService *AuthService,
+) *OIDCService {
+	return &OIDCService{
+		settingRepo:  settingRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		loginer:      authService,
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+		discovery:    make(map[string]oidcDiscoveryEntry),
+	}
+}
+
+// loadProviders 读取全部提供方配置（含密钥）
+func (s *OIDCService) loadProviders(ctx context.Context) ([]OIDCProviderConfig, error) {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyOIDCProviders)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return []OIDCProviderConfig{}, nil
+		}
+		return nil, fmt.Errorf("get oidc providers: %w", err)
+	}
+	return parseOIDCProviders(value), nil
+}
+
+func (s *OIDCService) saveProviders(ctx context.Context, providers []OIDCProviderConfig) error {
+	for i := range providers {
+		providers[i].ClientSecretConfigured = false
+	}
+	data, err := json.Marshal(providers)
+	if err != nil {
+		return fmt.Errorf("marshal oidc providers: %w", err)
+	}
+	return s.settingRepo.Set(ctx, SettingKeyOIDCProviders, string(data))
+}
+
+// parseOIDCProviders 解析存储的提供方列表，格式错误时视为未配置
+func parseOIDCProviders(value string) []OIDCProviderConfig {
+	if strings.TrimSpace(value) == "" {
+		return []OIDCProviderConfig{}
+	}
+	var providers []OIDCProviderConfig
+	if err := json.Unmarshal([]byte(value), &providers); err != nil {
+		log.Printf("[OIDC] Failed to parse %s: %v", SettingKeyOIDCProviders, err)
+		return []OIDCProviderConfig{}
+	}
+	for i := range providers {
+		normalizeOIDCProvider(&providers[i])
+	}
+	return providers
+}
+
+// publicOIDCProviders 返回登录页可展示的已启用提供方
+func publicOIDCProviders(value string) []OIDCPublicProvider {
+	out := make([]OIDCPublicProvider, 0)
+	for _, p := range parseOIDCProviders(value) {
+		if p.Enabled {
+			out = append(out, OIDCPublicProvider{Slug: p.Slug, Name: p.Name})
+		}
+	}
+	return out
+}
+
+func maskOIDCProvider(p OIDCProviderConfig) OIDCProviderConfig {
+	p.ClientSecretConfigured = p.ClientSecret != ""
+	p.ClientSecret = ""
+	return p
+}
+
+// ListProviders 列出全部提供方（密钥已隐藏）
+func (s *OIDCService) ListProviders(ctx context.Context) ([]OIDCProviderConfig, error) {
+	providers, err := s.loadProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]OIDCProviderConfig, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, maskOIDCProvider(p))
+	}
+	return out, nil
+}
+
+// CreateProvider 新增提供方
+func (s *OIDCService) CreateProvider(ctx context.Context, provider *OIDCProviderConfig) (*OIDCProviderConfig, error) {
+	if provider == nil {
+		return nil, infraerrors.BadRequest("OIDC_PROVIDER_INVALID", "provider cannot be nil")
+	}
+	p := *provider
+	normalizeOIDCProvider(&p)
+	if err := validateOIDCProvider(&p); err != nil {
+		return nil, infraerrors.BadRequest("OIDC_PROVIDER_INVALID", err.Error())
+	}
+
+	providers, err := s.loadProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) >= oidcMaxProviders {
+		return nil, infraerrors.BadRequest("OIDC_PROVIDER_LIMIT", fmt.Sprintf("at most %d oidc providers", oidcMaxProviders))
+	}
+	for _, existing := range providers {
+		if existing.Slug == p.Slug {
+			return nil, ErrOIDCProviderExists
+		}
+	}
+
+	providers = append(providers, p)
+	if err := s.saveProviders(ctx, providers); err != nil {
+		return nil, err
+	}
+	out := maskOIDCProvider(p)
+	return &out, nil
+}
+
+// UpdateProvider 更新提供方；client_secret 为空时保留原值，slug 不可修改
+func (s *OIDCService) UpdateProvider(ctx context.Context, slug string, provider *OIDCProviderConfig) (*OIDCProviderConfig, error) {
+	if provider == nil {
+		return nil, infraerrors.BadRequest("OIDC_PROVIDER_INVALID", "provider cannot be nil")
+	}
+	providers, err := s.loadProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := findOIDCProvider(providers, slug)
+	if idx < 0 {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	p := *provider
+	p.Slug = providers[idx].Slug
+	if strings.TrimSpace(p.ClientSecret) == "" {
+		p.ClientSecret = providers[idx].ClientSecret
+	}
+	normalizeOIDCProvider(&p)
+	if err := validateOIDCProvider(&p); err != nil {
+		return nil, infraerrors.BadRequest("OIDC_PROVIDER_INVALID", err.Error())
+	}
+
+	providers[idx] = p
+	if err := s.saveProviders(ctx, providers); err != nil {
+		return nil, err
+	}
+	s.invalidateDiscovery(p.Slug)
+	out := maskOIDCProvider(p)
+	return &out, nil
+}
+
+// DeleteProvider 删除提供方；已绑定的外部身份保留，重新创建同 slug 的提供方后可继续登录
+func (s *OIDCService) DeleteProvider(ctx context.Context, slug string) error {
+	providers, err := s.loadProviders(ctx)
+	if err != nil {
+		return err
+	}
+	idx := findOIDCProvider(providers, slug)
+	if idx < 0 {
+		return ErrOIDCProviderNotFound
+	}
+	providers = append(providers[:idx], providers[idx+1:]...)
+	if err := s.saveProviders(ctx, providers); err != nil {
+		return err
+	}
+	s.invalidateDiscovery(slug)
+	return nil
+}
+
+func findOIDCProvider(providers []OIDCProviderConfig, slug string) int {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	for i := range providers {
+		if providers[i].Slug == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetEnabledProvider 获取用于登录流程的已启用提供方（含密钥）
+func (s *OIDCService) GetEnabledProvider(ctx context.Context, slug string) (*OIDCProviderConfig, error) {
+	providers, err := s.loadProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := findOIDCProvider(providers, slug)
+	if idx < 0 || !providers[idx].Enabled {
+		return nil, ErrOIDCProviderDisabled
+	}
+	p := providers[idx]
+	return &p, nil
+}
+
+// ResolveEndpoints 解析授权端点：手动配置优先，其余从 discovery 文档获取（带缓存）
+func (s *OIDCService) ResolveEndpoints(ctx context.Context, provider *OIDCProviderConfig) (*OIDCEndpoints, error) {
+	endpoints := OIDCEndpoints{
+		AuthorizeURL: provider.AuthorizeURL,
+		TokenURL:     provider.TokenURL,
+		UserInfoURL:  provider.UserInfoURL,
+	}
+	if endpoints.AuthorizeURL != "" && endpoints.TokenURL != "" && endpoints.UserInfoURL != "" {
+		return &endpoints, nil
+	}
+
+	discovered, err := s.discover(ctx, provider)
+	if err != nil {
+		log.Printf("[OIDC] Discovery failed for provider %s: %v", provider.Slug, err)
+		return nil, ErrOIDCDiscoveryFailed.WithCause(err)
+	}
+	endpoints.AuthorizeURL = firstNonEmptyString(endpoints.AuthorizeURL, discovered.AuthorizeURL)
+	endpoints.TokenURL = firstNonEmptyString(endpoints.TokenURL, discovered.TokenURL)
+	endpoints.UserInfoURL = firstNonEmptyString(endpoints.UserInfoURL, discovered.UserInfoURL)
+	if endpoints.AuthorizeURL == "" || endpoints.TokenURL == "" || endpoints.UserInfoURL == "" {
+		return nil, ErrOIDCDiscoveryFailed.WithCause(errors.New("discovery document missing required endpoints"))
+	}
+	return &endpoints, nil
+}
+
+func (s *OIDCService) discover(ctx context.Context, provider *OIDCProviderConfig) (*OIDCEndpoints, error) {
+	if provider.DiscoveryURL == "" {
+		return nil, errors.New("discovery_url not configured")
+	}
+	discoveryURL := provider.DiscoveryURL
+	if !strings.Contains(discoveryURL, "/.well-known/") {
+		discoveryURL = strings.TrimRight(discoveryURL, "/") + oidcDiscoveryWellKnown
+	}
+
+	cacheKey := provider.Slug + "|" + discoveryURL
+	now := time.Now()
+	s.discoveryMu.Lock()
+	if entry, ok := s.discovery[cacheKey]; ok && now.Before(entry.expiresAt) {
+		s.discoveryMu.Unlock()
+		endpoints := entry.endpoints
+		return &endpoints, nil
+	}
+	s.discoveryMu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request discovery: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery status=%d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcDiscoveryMaxBody))
+	if err != nil {
+		return nil, fmt.Errorf("read discovery: %w", err)
+	}
+
+	var doc struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parse discovery: %w", err)
+	}
+	endpoints := OIDCEndpoints{
+		AuthorizeURL: strings.TrimSpace(doc.AuthorizationEndpoint),
+		TokenURL:     strings.TrimSpace(doc.TokenEndpoint),
+		UserInfoURL:  strings.TrimSpace(doc.UserInfoEndpoint),
+	}
+	for _, raw := range []string{endpoints.AuthorizeURL, endpoints.TokenURL, endpoints.UserInfoURL} {
+		if raw == "" {
+			continue
+		}
+		if err := config.ValidateAbsoluteHTTPURL(raw); err != nil {
+			return nil, fmt.Errorf("discovery returned invalid endpoint %q: %w", raw, err)
+		}
+	}
+
+	s.discoveryMu.Lock()
+	s.discovery[cacheKey] = oidcDiscoveryEntry{endpoints: endpoints, expiresAt: now.Add(oidcDiscoveryCacheTTL)}
+	s.discoveryMu.Unlock()
+	return &endpoints, nil
+}
+
+func (s *OIDCService) invalidateDiscovery(slug string) {
+	s.discoveryMu.Lock()
+	defer s.discoveryMu.Unlock()
+	for key := range s.discovery {
+		if strings.HasPrefix(key, slug+"|") {
+			delete(s.discovery, key)
+		}
+	}
+}
+
+// CompleteLogin 将外部身份映射到本地用户（登录或注册）。
+//
+// 不签发令牌：用户已启用 TOTP / 通行密钥时，调用方需先完成二步验证，
+// 否则开启 TrustEmail 后按邮箱映射到已有账号即可绕过该账号的二步验证。
+//
+// 绑定规则：
+//  1. (provider, subject) 已绑定时直接登录绑定的用户；
+//  2. 开启 TrustEmail 且邮箱已验证时，按邮箱登录/注册（可绑定到已有的同邮箱账号）；
+//  3. 否则使用基于 subject 的稳定合成邮箱，避免第三方邮箱接管本地账号。
+func (s *OIDCService) CompleteLogin(ctx context.Context, provider *OIDCProviderConfig, identity *OIDCIdentity) (*User, error) {
+	if provider == nil || identity == nil || identity.Subject == "" {
+		return nil, infraerrors.BadRequest("OIDC_IDENTITY_INVALID", "invalid oidc identity")
+	}
+	if len(provider.AllowedEmailDomains) > 0 {
+		if identity.Email == "" || !identity.EmailVerified || !oidcEmailDomainAllowed(identity.Email, provider.AllowedEmailDomains) {
+			return nil, ErrOIDCEmailNotAllowed
+		}
+	}
+
+	existing, err := s.identityRepo.GetByProviderSubject(ctx, provider.Slug, identity.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("get external identity: %w", err)
+	}
+
+	email := ""
+	if existing != nil {
+		user, err := s.userRepo.GetByID(ctx, existing.UserID)
+		switch {
+		case err == nil:
+			email = user.Email
+		case errors.Is(err, ErrUserNotFound):
+			// 绑定的用户已被删除：清理失效绑定，按首次登录处理
+			if err := s.identityRepo.Delete(ctx, existing.ID); err != nil {
+				return nil, fmt.Errorf("delete stale external identity: %w", err)
+			}
+			existing = nil
+		default:
+			return nil, fmt.Errorf("get identity user: %w", err)
+		}
+	}
+	if email == "" {
+		if provider.TrustEmail && identity.EmailVerified && identity.Email != "" {
+			email = identity.Email
+		} else {
+			email = oidcSyntheticEmail(provider.Slug, identity.Subject)
+		}
+	}
+
+	_, user, err := s.loginer.LoginOrRegisterOAuth(ctx, email, identity.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if err := s.identityRepo.TouchLogin(ctx, existing.ID, identity.Email, identity.Username); err != nil {
+			log.Printf("[OIDC] Failed to update external identity %d: %v", existing.ID, err)
+		}
+		return user, nil
+	}
+	if err := s.identityRepo.Create(ctx, &ExternalIdentity{
+		UserID:   user.ID,
+		Provider: provider.Slug,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		Username: identity.Username,
+	}); err != nil {
+		// 绑定失败不影响本次登录；下次登录会按相同规则重新映射到同一用户
+		log.Printf("[OIDC] Failed to link external identity provider=%s user=%d: %v", provider.Slug, user.ID, err)
+	}
+	return user, nil
+}
@@ -0,0 +1,218 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type externalIdentityRepoStub struct {
+	identities map[string]*ExternalIdentity
+	touched    []int64
+	deleted    []int64
+}
+
+func (r *externalIdentityRepoStub) GetByProviderSubject(ctx context.Context, provider, subject string) (*ExternalIdentity, error) {
+	return r.identities[provider+"|"+subject], nil
+}
+
+func (r *externalIdentityRepoStub) Create(ctx context.Context, identity *ExternalIdentity) error {
+	if r.identities == nil {
+		r.identities = map[string]*ExternalIdentity{}
+	}
+	out := *identity
+	out.ID = int64(len(r.identities) + 1)
+	r.identities[identity.Provider+"|"+identity.Subject] = &out
+	return nil
+}
+
+func (r *externalIdentityRepoStub) TouchLogin(ctx context.Context, id int64, email, username string) error {
+	r.touched = append(r.touched, id)
+	return nil
+}
+
+func (r *externalIdentityRepoStub) Delete(ctx context.Context, id int64) error {
+	r.deleted = append(r.deleted, id)
+	for key, identity := range r.identities {
+		if identity.ID == id {
+			delete(r.identities, key)
+		}
+	}
+	return nil
+}
+
+type oidcLoginerStub struct {
+	emails []string
+	userID int64
+}
+
+func (l *oidcLoginerStub) LoginOrRegisterOAuth(ctx context.Context, email, username string) (string, *User, error) {
+	l.emails = append(l.emails, email)
+	return "access", &User{ID: l.userID, Email: email}, nil
+}
+
+func testOIDCProvider() *OIDCProviderConfig {
+	p := &OIDCProviderConfig{
+		Slug:               "corp",
+		Name:               "Corp SSO",
+		Enabled:            true,
+		DiscoveryURL:       "https://sso.example.com",
+		ClientID:           "client",
+		ClientSecret:       "secret",
+		UsePKCE:            true,
+		RedirectURL:        "https://api.example.com/api/v1/auth/oauth/oidc/corp/callback",
+		EmailVerifiedClaim: "email_verified",
+	}
+	normalizeOIDCProvider(p)
+	return p
+}
+
+func TestParseOIDCUserInfo(t *testing.T) {
+	p := testOIDCProvider()
+
+	identity, err := ParseOIDCUserInfo(`{"sub":"u-1","email":"Alice@Example.com","email_verified":true,"preferred_username":"alice"}`, p)
+	require.NoError(t, err)
+	require.Equal(t, &OIDCIdentity{Subject: "u-1", Email: "alice@example.com", EmailVerified: true, Username: "alice"}, identity)
+
+	// 未验证邮箱 / 缺少用户名时回退
+	identity, err = ParseOIDCUserInfo(`{"sub":"u-2","email":"bob@example.com","email_verified":"false"}`, p)
+	require.NoError(t, err)
+	require.False(t, identity.EmailVerified)
+	require.Equal(t, "corp_u-2", identity.Username)
+
+	// 自定义声明映射（GitHub 风格的数字 id）；未返回验证声明时视为未验证
+	p.SubjectClaim = "id"
+	p.UsernameClaim = "login"
+	p.EmailVerifiedClaim = ""
+	identity, err = ParseOIDCUserInfo(`{"id":42,"login":"octo","email":"octo@example.com"}`, p)
+	require.NoError(t, err)
+	require.Equal(t, "42", identity.Subject)
+	require.Equal(t, "octo", identity.Username)
+	require.False(t, identity.EmailVerified)
+
+	// 显式信任提供方返回的全部邮箱
+	p.TrustAllEmails = true
+	identity, err = ParseOIDCUserInfo(`{"id":42,"login":"octo","email":"octo@example.com","email_verified":false}`, p)
+	require.NoError(t, err)
+	require.True(t, identity.EmailVerified)
+	p.TrustAllEmails = false
+
+	_, err = ParseOIDCUserInfo(`{"login":"octo"}`, p)
+	require.Error(t, err)
+	_, err = ParseOIDCUserInfo(`{"id":"a b"}`, p)
+	require.Error(t, err)
+	_, err = ParseOIDCUserInfo(`not json`, p)
+	require.Error(t, err)
+}
+
+func TestValidateOIDCProvider(t *testing.T) {
+	require.NoError(t, validateOIDCProvider(testOIDCProvider()))
+
+	p := testOIDCProvider()
+	p.Slug = "Bad Slug"
+	require.Error(t, validateOIDCProvider(p))
+
+	p = testOIDCProvider()
+	p.DiscoveryURL = ""
+	require.Error(t, validateOIDCProvider(p))
+	p.AuthorizeURL = "https://github.com/login/oauth/authorize"
+	p.TokenURL = "https://github.com/login/oauth/access_token"
+	p.UserInfoURL = "https://api.github.com/user"
+	require.NoError(t, validateOIDCProvider(p))
+
+	p = testOIDCProvider()
+	p.TokenAuthMethod = "none"
+	p.UsePKCE = false
+	require.Error(t, validateOIDCProvider(p))
+
+	p = testOIDCProvider()
+	p.ClientSecret = ""
+	require.Error(t, validateOIDCProvider(p))
+
+	p = testOIDCProvider()
+	p.RedirectURL = "/callback"
+	require.Error(t, validateOIDCProvider(p))
+
+	p = testOIDCProvider()
+	p.AllowedEmailDomains = []string{" @Example.COM ", "example.com", ""}
+	normalizeOIDCProvider(p)
+	require.Equal(t, []string{"example.com"}, p.AllowedEmailDomains)
+	require.NoError(t, validateOIDCProvider(p))
+}
+
+func TestOIDCService_CompleteLoginLinksIdentity(t *testing.T) {
+	identities := &externalIdentityRepoStub{}
+	loginer := &oidcLoginerStub{userID: 7}
+	svc := &OIDCService{identityRepo: identities, loginer: loginer}
+	p := testOIDCProvider()
+
+	identity := &OIDCIdentity{Subject: "u-1", Email: "alice@example.com", EmailVerified: true, Username: "alice"}
+	user, err := svc.CompleteLogin(context.Background(), p, identity)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), user.ID)
+	// 未开启 TrustEmail：使用合成邮箱，不会映射到同邮箱的本地账号
+	require.True(t, strings.HasSuffix(loginer.emails[0], OIDCSyntheticEmailDomain))
+	require.Equal(t, oidcSyntheticEmail("corp", "u-1"), loginer.emails[0])
+	require.Equal(t, int64(7), identities.identities["corp|u-1"].UserID)
+
+	// 已绑定：按绑定用户的邮箱登录，并刷新登录时间
+	svc.userRepo = &userRepoStub{user: &User{ID: 7, Email: "renamed@example.com"}}
+	_, err = svc.CompleteLogin(context.Background(), p, identity)
+	require.NoError(t, err)
+	require.Equal(t, "renamed@example.com", loginer.emails[1])
+	require.Equal(t, []int64{1}, identities.touched)
+}
+
+func TestOIDCService_CompleteLoginTrustEmail(t *testing.T) {
+	identities := &externalIdentityRepoStub{}
+	loginer := &oidcLoginerStub{userID: 3}
+	svc := &OIDCService{identityRepo: identities, loginer: loginer}
+	p := testOIDCProvider()
+	p.TrustEmail = true
+
+	_, err := svc.CompleteLogin(context.Background(), p, &OIDCIdentity{Subject: "u-1", Email: "alice@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", loginer.emails[0])
+
+	// 邮箱未验证时不信任
+	_, err = svc.CompleteLogin(context.Background(), p, &OIDCIdentity{Subject: "u-2", Email: "bob@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, oidcSyntheticEmail("corp", "u-2"), loginer.emails[1])
+}
+
+func TestOIDCService_CompleteLoginStaleIdentity(t *testing.T) {
+	identities := &externalIdentityRepoStub{identities: map[string]*ExternalIdentity{
+		"corp|u-1": {ID: 5, UserID: 99, Provider: "corp", Subject: "u-1"},
+	}}
+	loginer := &oidcLoginerStub{userID: 8}
+	svc := &OIDCService{identityRepo: identities, userRepo: &userRepoStub{}, loginer: loginer}
+
+	user, err := svc.CompleteLogin(context.Background(), testOIDCProvider(), &OIDCIdentity{Subject: "u-1"})
+	require.NoError(t, err)
+	require.Equal(t, int64(8), user.ID)
+	require.Equal(t, []int64{5}, identities.deleted)
+	require.Equal(t, int64(8), identities.identities["corp|u-1"].UserID)
+}
+
+func TestOIDCService_CompleteLoginEmailDomain(t *testing.T) {
+	loginer := &oidcLoginerStub{userID: 1}
+	svc := &OIDCService{identityRepo: &externalIdentityRepoStub{}, loginer: loginer}
+	p := testOIDCProvider()
+	p.AllowedEmailDomains = []string{"example.com"}
+
+	_, err := svc.CompleteLogin(context.Background(), p, &OIDCIdentity{Subject: "u-1", Email: "eve@evil.com", EmailVerified: true})
+	require.Error(t, err)
+	require.Equal(t, "OIDC_EMAIL_DOMAIN_NOT_ALLOWED", infraerrors.Reason(err))
+
+	_, err = svc.CompleteLogin(context.Background(), p, &OIDCIdentity{Subject: "u-1", Email: "eve@example.com"})
+	require.Error(t, err)
+
+	_, err = svc.CompleteLogin(context.Background(), p, &OIDCIdentity{Subject: "u-1", Email: "alice@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	require.Len(t, loginer.emails, 1)
+}
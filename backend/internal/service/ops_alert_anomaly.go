@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// 告警基线异常检测
+//
+// operator 为 anomaly_above / anomaly_below 时，规则不再与固定阈值比较，而是将当前窗口的指标值
+// 与 ops_metrics_hourly 中过去 N 天同一小时（UTC）的基线比较：
+//   - zscore：偏离 = (当前值 - 基线均值) / 基线标准差，threshold 为标准差倍数（例如 3 表示 3σ）
+//   - percent：偏离 = (当前值 - 基线均值) / 基线均值 * 100，threshold 为偏离百分比（例如 60 表示偏离 60%）
+//
+// anomaly_above 在偏离 >= threshold 时触发，anomaly_below 在偏离 <= -threshold 时触发。
+// 基线样本不足时跳过评估（等同于没有数据），sustained/cooldown 语义与普通规则一致。
+
+const (
+	OpsAlertOperatorAnomalyAbove = "anomaly_above"
+	OpsAlertOperatorAnomalyBelow = "anomaly_below"
+
+	OpsAlertAnomalyMethodZScore  = "zscore"
+	OpsAlertAnomalyMethodPercent = "percent"
+
+	OpsAlertBaselineDaysDefault = 7
+	OpsAlertBaselineDaysMax     = 30
+
+	// opsAlertBaselineMinSamples 计算基线所需的最少样本数（baseline_days 更小时以其为准）
+	opsAlertBaselineMinSamples = 3
+	// opsAlertBaselineStdDevFloorRatio 基线几乎无波动时，以均值的 1% 作为标准差下限，避免 z-score 失真
+	opsAlertBaselineStdDevFloorRatio = 0.01
+	opsAlertBaselineStdDevMin        = 1e-6
+)
+
+// IsOpsAlertAnomalyOperator 判断 operator 是否为基线异常检测
+func IsOpsAlertAnomalyOperator(operator string) bool {
+	switch strings.TrimSpace(operator) {
+	case OpsAlertOperatorAnomalyAbove, OpsAlertOperatorAnomalyBelow:
+		return true
+	default:
+		return false
+	}
+}
+
+// OpsAlertMetricSupportsBaseline 判断指标能否从 ops_metrics_hourly 计算基线
+func OpsAlertMetricSupportsBaseline(metricType string) bool {
+	switch strings.TrimSpace(metricType) {
+	case "success_rate", "error_rate", "upstream_error_rate",
+		"requests_per_minute", "tokens_per_minute",
+		"duration_p50_ms", "duration_p90_ms", "duration_p95_ms", "duration_p99_ms",
+		"first_token_p50_ms", "first_token_p90_ms", "first_token_p95_ms", "first_token_p99_ms":
+		return true
+	default:
+		return false
+	}
+}
+
+// OpsAlertAnomaly 一次基线异常评估的结果
+type OpsAlertAnomaly struct {
+	Method    string
+	Current   float64
+	Mean      float64
+	StdDev    float64
+	Deviation float64
+	Samples   int
+}
+
+func (a *OpsAlertAnomaly) breached(operator string, threshold float64) bool {
+	if a == nil {
+		return false
+	}
+	switch strings.TrimSpace(operator) {
+	case OpsAlertOperatorAnomalyAbove:
+		return a.Deviation >= threshold
+	case OpsAlertOperatorAnomalyBelow:
+		return a.Deviation <= -threshold
+	default:
+		return false
+	}
+}
+
+func normalizeOpsAlertAnomalyMethod(method string) string {
+	if strings.TrimSpace(strings.ToLower(method)) == OpsAlertAnomalyMethodPercent {
+		return OpsAlertAnomalyMethodPercent
+	}
+	return OpsAlertAnomalyMethodZScore
+}
+
+func normalizeOpsAlertBaselineDays(days int) int {
+	if days <= 0 {
+		return OpsAlertBaselineDaysDefault
+	}
+	if days > OpsAlertBaselineDaysMax {
+		return OpsAlertBaselineDaysMax
+	}
+	return days
+}
+
+// opsAlertBaselineBucketStarts 返回过去 days 天与窗口结束时刻同一小时（UTC）的小时桶起点
+func opsAlertBaselineBucketStarts(windowEnd time.Time, days int) []time.Time {
+	// windowEnd 为开区间，往前 1 分钟定位当前窗口所在的小时
+	hour := windowEnd.UTC().Add(-time.Minute).Truncate(time.Hour)
+	out := make([]time.Time, 0, days)
+	for d := 1; d <= days; d++ {
+		out = append(out, hour.AddDate(0, 0, -d))
+	}
+	return out
+}
+
+// opsAlertBaselineValue 按与 computeRuleMetric 相同的口径从小时聚合中计算指标值
+func opsAlertBaselineValue(metricType string, b *OpsAlertBaselineBucket) (float64, bool) {
+	pick := func(v *int) (float64, bool) {
+		if v == nil {
+			return 0, false
+		}
+		return float64(*v), true
+	}
+	requestCountSLA := b.SuccessCount + b.ErrorCountSLA
+	switch metricType {
+	case "success_rate":
+		if requestCountSLA <= 0 {
+			return 0, false
+		}
+		return float64(b.SuccessCount) / float64(requestCountSLA) * 100, true
+	case "error_rate":
+		if requestCountSLA <= 0 {
+			return 0, false
+		}
+		return float64(b.ErrorCountSLA) / float64(requestCountSLA) * 100, true
+	case "upstream_error_rate":
+		if requestCountSLA <= 0 {
+			return 0, false
+		}
+		return float64(b.UpstreamErrorCountExcl429529) / float64(requestCountSLA) * 100, true
+	case "requests_per_minute":
+		return float64(b.SuccessCount+b.ErrorCountTotal) / 60, true
+	case "tokens_per_minute":
+		return float64(b.TokenConsumed) / 60, true
+	case "duration_p50_ms":
+		return pick(b.Duration.P50)
+	case "duration_p90_ms":
+		return pick(b.Duration.P90)
+	case "duration_p95_ms":
+		return pick(b.Duration.P95)
+	case "duration_p99_ms":
+		return pick(b.Duration.P99)
+	case "first_token_p50_ms":
+		return pick(b.TTFT.P50)
+	case "first_token_p90_ms":
+		return pick(b.TTFT.P90)
+	case "first_token_p95_ms":
+		return pick(b.TTFT.P95)
+	case "first_token_p99_ms":
+		return pick(b.TTFT.P99)
+	default:
+		return 0, false
+	}
+}
+
+// computeOpsAlertAnomaly 根据基线样本计算当前值的偏离程度
+func computeOpsAlertAnomaly(current float64, samples []float64, method string, minSamples int) (*OpsAlertAnomaly, bool) {
+	if len(samples) == 0 || len(samples) < minSamples {
+		return nil, false
+	}
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	mean := sum / float64(len(samples))
+
+	var variance float64
+	if len(samples) > 1 {
+		for _, v := range samples {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(len(samples) - 1)
+	}
+	stddev := math.Sqrt(variance)
+
+	anomaly := &OpsAlertAnomaly{
+		Method:  method,
+		Current: current,
+		Mean:    mean,
+		StdDev:  stddev,
+		Samples: len(samples),
+	}
+	switch method {
+	case OpsAlertAnomalyMethodPercent:
+		if mean == 0 {
+			return nil, false
+		}
+		anomaly.Deviation = (current - mean) / math.Abs(mean) * 100
+	default:
+		floor := math.Max(math.Abs(mean)*opsAlertBaselineStdDevFloorRatio, opsAlertBaselineStdDevMin)
+		anomaly.Deviation = (current - mean) / math.Max(stddev, floor)
+	}
+	return anomaly, true
+}
+
+// evaluateRuleAnomaly 加载基线并计算当前窗口指标值的偏离
+func (s *OpsAlertEvaluatorService) evaluateRuleAnomaly(
+	ctx context.Context,
+	rule *OpsAlertRule,
+	current float64,
+	windowEnd time.Time,
+	platform string,
+	groupID *int64,
+) (*OpsAlertAnomaly, bool) {
+	if s == nil || s.opsRepo == nil || rule == nil {
+		return nil, false
+	}
+	metricType := strings.TrimSpace(rule.MetricType)
+	if !OpsAlertMetricSupportsBaseline(metricType) {
+		return nil, false
+	}
+	// 小时聚合只有 overall/platform/group 维度，无法为用户/账号级过滤计算基线
+	if parseOpsAlertFilterID(rule.Filters, "user_id") != nil || parseOpsAlertFilterID(rule.Filters, "account_id") != nil {
+		return nil, false
+	}
+
+	days := normalizeOpsAlertBaselineDays(rule.BaselineDays)
+	buckets, err := s.opsRepo.ListAlertBaselineBuckets(ctx, platform, groupID, opsAlertBaselineBucketStarts(windowEnd, days))
+	if err != nil {
+		return nil, false
+	}
+
+	samples := make([]float64, 0, len(buckets))
+	for _, b := range buckets {
+		if b == nil {
+			continue
+		}
+		if v, ok := opsAlertBaselineValue(metricType, b); ok {
+			samples = append(samples, v)
+		}
+	}
+	minSamples := opsAlertBaselineMinSamples
+	if days < minSamples {
+		minSamples = days
+	}
+	return computeOpsAlertAnomaly(current, samples, normalizeOpsAlertAnomalyMethod(rule.AnomalyMethod), minSamples)
+}
+
+func buildOpsAlertAnomalyDescription(rule *OpsAlertRule, anomaly *OpsAlertAnomaly, windowMinutes int, platform string, groupID *int64) string {
+	if rule == nil || anomaly == nil {
+		return ""
+	}
+	direction := "above"
+	if strings.TrimSpace(rule.Operator) == OpsAlertOperatorAnomalyBelow {
+		direction = "below"
+	}
+	deviation := fmt.Sprintf("%.2fσ", anomaly.Deviation)
+	if anomaly.Method == OpsAlertAnomalyMethodPercent {
+		deviation = fmt.Sprintf("%.1f%%", anomaly.Deviation)
+	}
+	base := buildOpsAlertDescription(rule, anomaly.Current, windowMinutes, platform, groupID)
+	return fmt.Sprintf("%s; %s %s baseline (mean %.2f, stddev %.2f, %d samples)",
+		base, deviation, direction, anomaly.Mean, anomaly.StdDev, anomaly.Samples)
+}
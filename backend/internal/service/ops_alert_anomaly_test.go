@@ -0,0 +1,105 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type baselineOpsRepo struct {
+	OpsRepository
+	overview     *OpsDashboardOverview
+	buckets      []*OpsAlertBaselineBucket
+	bucketStarts []time.Time
+}
+
+func (s *baselineOpsRepo) GetDashboardOverview(_ context.Context, _ *OpsDashboardFilter) (*OpsDashboardOverview, error) {
+	return s.overview, nil
+}
+
+func (s *baselineOpsRepo) ListAlertBaselineBuckets(_ context.Context, _ string, _ *int64, bucketStarts []time.Time) ([]*OpsAlertBaselineBucket, error) {
+	s.bucketStarts = bucketStarts
+	return s.buckets, nil
+}
+
+func TestOpsAlertBaselineBucketStarts(t *testing.T) {
+	windowEnd := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	got := opsAlertBaselineBucketStarts(windowEnd, 3)
+	// 窗口结束于整点时，当前窗口属于上一个小时
+	require.Equal(t, []time.Time{
+		time.Date(2026, 10, 13, 14, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 12, 14, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 11, 14, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func TestComputeOpsAlertAnomaly(t *testing.T) {
+	samples := []float64{1, 2, 3}
+
+	z, ok := computeOpsAlertAnomaly(5, samples, OpsAlertAnomalyMethodZScore, 3)
+	require.True(t, ok)
+	require.InDelta(t, 2.0, z.Mean, 1e-9)
+	require.InDelta(t, 1.0, z.StdDev, 1e-9)
+	require.InDelta(t, 3.0, z.Deviation, 1e-9)
+	require.True(t, z.breached(OpsAlertOperatorAnomalyAbove, 3))
+	require.False(t, z.breached(OpsAlertOperatorAnomalyBelow, 3))
+
+	pct, ok := computeOpsAlertAnomaly(0.8, samples, OpsAlertAnomalyMethodPercent, 3)
+	require.True(t, ok)
+	require.InDelta(t, -60.0, pct.Deviation, 1e-9)
+	require.True(t, pct.breached(OpsAlertOperatorAnomalyBelow, 60))
+	require.False(t, pct.breached(OpsAlertOperatorAnomalyAbove, 60))
+
+	// 样本不足 / 百分比基线为 0 时无法判定
+	_, ok = computeOpsAlertAnomaly(5, samples[:2], OpsAlertAnomalyMethodZScore, 3)
+	require.False(t, ok)
+	_, ok = computeOpsAlertAnomaly(5, []float64{0, 0, 0}, OpsAlertAnomalyMethodPercent, 3)
+	require.False(t, ok)
+
+	// 基线无波动时按均值 1% 作为标准差下限
+	flat, ok := computeOpsAlertAnomaly(110, []float64{100, 100, 100}, OpsAlertAnomalyMethodZScore, 3)
+	require.True(t, ok)
+	require.InDelta(t, 10.0, flat.Deviation, 1e-9)
+}
+
+func TestEvaluateRuleAnomaly_RequestsPerMinuteDrop(t *testing.T) {
+	repo := &baselineOpsRepo{
+		// 5 分钟窗口内共 200 个请求 => 40 rpm
+		overview: &OpsDashboardOverview{RequestCountTotal: 200},
+		buckets: []*OpsAlertBaselineBucket{
+			{SuccessCount: 6000},
+			{SuccessCount: 5400, ErrorCountTotal: 600},
+			{SuccessCount: 6000},
+		},
+	}
+	svc := &OpsAlertEvaluatorService{opsRepo: repo}
+	rule := &OpsAlertRule{
+		MetricType:    "requests_per_minute",
+		Operator:      OpsAlertOperatorAnomalyBelow,
+		Threshold:     60,
+		AnomalyMethod: OpsAlertAnomalyMethodPercent,
+		BaselineDays:  3,
+	}
+
+	end := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+	start := end.Add(-5 * time.Minute)
+	current, ok := svc.computeRuleMetric(context.Background(), rule, nil, start, end, "", nil)
+	require.True(t, ok)
+	require.InDelta(t, 40.0, current, 1e-9)
+
+	anomaly, ok := svc.evaluateRuleAnomaly(context.Background(), rule, current, end, "", nil)
+	require.True(t, ok)
+	require.Len(t, repo.bucketStarts, 3)
+	require.InDelta(t, 100.0, anomaly.Mean, 1e-9)
+	require.InDelta(t, -60.0, anomaly.Deviation, 1e-9)
+	require.True(t, anomaly.breached(rule.Operator, rule.Threshold))
+
+	// 用户级过滤无法计算基线
+	rule.Filters = map[string]any{"user_id": float64(1)}
+	_, ok = svc.evaluateRuleAnomaly(context.Background(), rule, current, end, "", nil)
+	require.False(t, ok)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// 告警确认、升级与重复通知
+//
+// 触发中且未确认（acknowledged_at 为空）的事件在每轮评估时继续跟进：
+//   - 升级：按事件 severity 匹配升级策略，自 fired_at 起超过某级 after_minutes 仍未确认时，
+//     通知该级收件人；escalation_level 记录已经通知到的级数，每级只通知一次
+//   - 重复通知：距上次通知超过 interval_minutes 时向默认告警收件人重发，max_repeats 为 0 表示不限次数
+//
+// 事件被确认、恢复或处于静默时停止跟进。每次状态变化与通知都写入 ops_alert_event_timeline。
+
+// recordOpsAlertEventTimeline 写入时间线（best-effort，失败只记日志）
+func recordOpsAlertEventTimeline(ctx context.Context, repo OpsRepository, entry *OpsAlertEventTimelineEntry) {
+	if repo == nil || entry == nil || entry.EventID <= 0 {
+		return
+	}
+	if err := repo.CreateAlertEventTimelineEntry(ctx, entry); err != nil {
+		log.Printf("[OpsAlert] record timeline failed (event=%d action=%s): %v", entry.EventID, entry.Action, err)
+	}
+}
+
+// findOpsAlertEscalationPolicy 返回与 severity 匹配的升级策略（不区分大小写）
+func findOpsAlertEscalationPolicy(cfg OpsAlertEscalationSettings, severity string) *OpsAlertEscalationPolicy {
+	severity = strings.TrimSpace(severity)
+	if !cfg.Enabled || severity == "" {
+		return nil
+	}
+	for i := range cfg.Policies {
+		if strings.EqualFold(strings.TrimSpace(cfg.Policies[i].Severity), severity) {
+			return &cfg.Policies[i]
+		}
+	}
+	return nil
+}
+
+// opsAlertDueEscalationLevels 返回截至 now 应已通知的升级级数（levels 按 after_minutes 升序）
+func opsAlertDueEscalationLevels(policy *OpsAlertEscalationPolicy, firedAt, now time.Time) int {
+	if policy == nil || firedAt.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(firedAt)
+	due := 0
+	for _, level := range policy.Levels {
+		if elapsed < time.Duration(level.AfterMinutes)*time.Minute {
+			break
+		}
+		due++
+	}
+	return due
+}
+
+// opsAlertRepeatNotificationDue 判断是否需要重复通知；只有首次通知成功后才会重复
+func opsAlertRepeatNotificationDue(cfg OpsAlertRepeatNotificationSettings, event *OpsAlertEvent, now time.Time) bool {
+	if !cfg.Enabled || event == nil || event.LastNotifiedAt == nil {
+		return false
+	}
+	interval := cfg.IntervalMinutes
+	if interval <= 0 {
+		interval = opsAlertRepeatIntervalMinutesDefault
+	}
+	if now.Sub(*event.LastNotifiedAt) < time.Duration(interval)*time.Minute {
+		return false
+	}
+	if cfg.MaxRepeats > 0 {
+		// 首次通知与升级通知不计入重复次数
+		repeats := event.NotificationCount - 1 - event.EscalationLevel
+		if repeats >= cfg.MaxRepeats {
+			return false
+		}
+	}
+	return true
+}
+
+// followUpActiveAlertEvent 对仍在触发的事件执行升级与重复通知，返回发送成功的邮件数
+func (s *OpsAlertEvaluatorService) followUpActiveAlertEvent(ctx context.Context, runtimeCfg *OpsAlertRuntimeSettings, rule *OpsAlertRule, event *OpsAlertEvent, now time.Time) int {
+	if s == nil || runtimeCfg == nil || rule == nil || event == nil || event.ID <= 0 {
+		return 0
+	}
+	if event.Status != OpsAlertStatusFiring || event.AcknowledgedAt != nil {
+		return 0
+	}
+	if runtimeCfg.Silencing.Enabled && isOpsAlertSilenced(now, rule, event, runtimeCfg.Silencing) {
+		return 0
+	}
+
+	emailsSent := 0
+	if policy := findOpsAlertEscalationPolicy(runtimeCfg.Escalation, event.Severity); policy != nil {
+		due := opsAlertDueEscalationLevels(policy, event.FiredAt, now)
+		for level := event.EscalationLevel; level < due; level++ {
+			recipients := policy.Levels[level].Recipients
+			subject := fmt.Sprintf("[Ops Alert][%s][Escalation L%d] %s", strings.TrimSpace(rule.Severity), level+1, strings.TrimSpace(rule.Name))
+			sent := s.sendAlertEmail(ctx, recipients, subject, buildOpsAlertEmailBody(rule, event))
+
+			if err := s.opsRepo.UpdateAlertEventEscalationLevel(ctx, event.ID, level+1); err != nil {
+				log.Printf("[OpsAlertEvaluator] update escalation level failed (event=%d): %v", event.ID, err)
+				break
+			}
+			event.EscalationLevel = level + 1
+			recordOpsAlertEventTimeline(ctx, s.opsRepo, &OpsAlertEventTimelineEntry{
+				EventID: event.ID,
+				Action:  OpsAlertTimelineEscalated,
+				Message: fmt.Sprintf("not acknowledged after %d minutes", policy.Levels[level].AfterMinutes),
+				Details: map[string]any{
+					"level":      level + 1,
+					"recipients": recipients,
+				},
+			})
+			if len(sent) > 0 {
+				s.recordAlertEventNotified(event, OpsAlertTimelineNotified, sent, map[string]any{"escalation_level": level + 1})
+				emailsSent += len(sent)
+			}
+		}
+	}
+
+	if opsAlertRepeatNotificationDue(runtimeCfg.RepeatNotification, event, now) {
+		if recipients := s.alertEmailRecipients(ctx, rule); len(recipients) > 0 {
+			subject := fmt.Sprintf("[Ops Alert][%s][Still firing] %s", strings.TrimSpace(rule.Severity), strings.TrimSpace(rule.Name))
+			if sent := s.sendAlertEmail(ctx, recipients, subject, buildOpsAlertEmailBody(rule, event)); len(sent) > 0 {
+				s.recordAlertEventNotified(event, OpsAlertTimelineRepeatNotified, sent, nil)
+				emailsSent += len(sent)
+			}
+		}
+	}
+	return emailsSent
+}
@@ -0,0 +1,119 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type escalationOpsRepo struct {
+	OpsRepository
+	levels   []int
+	timeline []*OpsAlertEventTimelineEntry
+}
+
+func (s *escalationOpsRepo) UpdateAlertEventEscalationLevel(_ context.Context, _ int64, level int) error {
+	s.levels = append(s.levels, level)
+	return nil
+}
+
+func (s *escalationOpsRepo) CreateAlertEventTimelineEntry(_ context.Context, entry *OpsAlertEventTimelineEntry) error {
+	s.timeline = append(s.timeline, entry)
+	return nil
+}
+
+func testOpsAlertEscalationPolicy() OpsAlertEscalationSettings {
+	return OpsAlertEscalationSettings{
+		Enabled: true,
+		Policies: []OpsAlertEscalationPolicy{{
+			Severity: "P0",
+			Levels: []OpsAlertEscalationLevel{
+				{AfterMinutes: 10, Recipients: []string{"oncall@example.com"}},
+				{AfterMinutes: 30, Recipients: []string{"lead@example.com"}},
+			},
+		}},
+	}
+}
+
+func TestOpsAlertDueEscalationLevels(t *testing.T) {
+	cfg := testOpsAlertEscalationPolicy()
+	policy := findOpsAlertEscalationPolicy(cfg, "p0")
+	require.NotNil(t, policy)
+	require.Nil(t, findOpsAlertEscalationPolicy(cfg, "P1"))
+
+	firedAt := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	require.Equal(t, 0, opsAlertDueEscalationLevels(policy, firedAt, firedAt.Add(9*time.Minute)))
+	require.Equal(t, 1, opsAlertDueEscalationLevels(policy, firedAt, firedAt.Add(10*time.Minute)))
+	require.Equal(t, 2, opsAlertDueEscalationLevels(policy, firedAt, firedAt.Add(2*time.Hour)))
+
+	cfg.Enabled = false
+	require.Nil(t, findOpsAlertEscalationPolicy(cfg, "P0"))
+}
+
+func TestOpsAlertRepeatNotificationDue(t *testing.T) {
+	now := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	last := now.Add(-20 * time.Minute)
+	cfg := OpsAlertRepeatNotificationSettings{Enabled: true, IntervalMinutes: 15, MaxRepeats: 2}
+
+	// 首次通知未成功时不重复
+	require.False(t, opsAlertRepeatNotificationDue(cfg, &OpsAlertEvent{NotificationCount: 0}, now))
+
+	ev := &OpsAlertEvent{LastNotifiedAt: &last, NotificationCount: 1}
+	require.True(t, opsAlertRepeatNotificationDue(cfg, ev, now))
+	require.False(t, opsAlertRepeatNotificationDue(cfg, ev, last.Add(10*time.Minute)))
+
+	ev.NotificationCount = 3
+	require.False(t, opsAlertRepeatNotificationDue(cfg, ev, now))
+
+	// 升级通知不计入重复次数
+	ev.EscalationLevel = 1
+	require.True(t, opsAlertRepeatNotificationDue(cfg, ev, now))
+
+	cfg.Enabled = false
+	require.False(t, opsAlertRepeatNotificationDue(cfg, ev, now))
+}
+
+func TestFollowUpActiveAlertEventEscalatesOnce(t *testing.T) {
+	repo := &escalationOpsRepo{}
+	svc := &OpsAlertEvaluatorService{opsRepo: repo, emailLimiter: newSlidingWindowLimiter(0, time.Hour)}
+	runtimeCfg := &OpsAlertRuntimeSettings{Escalation: testOpsAlertEscalationPolicy()}
+	rule := &OpsAlertRule{ID: 1, Name: "error rate", Severity: "P0"}
+
+	firedAt := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	ev := &OpsAlertEvent{ID: 7, RuleID: 1, Severity: "P0", Status: OpsAlertStatusFiring, FiredAt: firedAt}
+
+	svc.followUpActiveAlertEvent(context.Background(), runtimeCfg, rule, ev, firedAt.Add(45*time.Minute))
+	require.Equal(t, []int{1, 2}, repo.levels)
+	require.Equal(t, 2, ev.EscalationLevel)
+	require.Len(t, repo.timeline, 2)
+	require.Equal(t, OpsAlertTimelineEscalated, repo.timeline[0].Action)
+	require.Equal(t, 2, repo.timeline[1].Details["level"])
+
+	// 已通知过的级别不会重复升级
+	svc.followUpActiveAlertEvent(context.Background(), runtimeCfg, rule, ev, firedAt.Add(50*time.Minute))
+	require.Len(t, repo.levels, 2)
+
+	// 已确认的事件停止升级
+	acked := firedAt.Add(5 * time.Minute)
+	ev2 := &OpsAlertEvent{ID: 8, RuleID: 1, Severity: "P0", Status: OpsAlertStatusFiring, FiredAt: firedAt, AcknowledgedAt: &acked}
+	svc.followUpActiveAlertEvent(context.Background(), runtimeCfg, rule, ev2, firedAt.Add(45*time.Minute))
+	require.Len(t, repo.levels, 2)
+}
+
+func TestValidateOpsAlertEscalationSettings(t *testing.T) {
+	require.NoError(t, validateOpsAlertEscalationSettings(testOpsAlertEscalationPolicy()))
+
+	missingRecipients := testOpsAlertEscalationPolicy()
+	missingRecipients.Policies[0].Levels[0].Recipients = []string{" "}
+	require.Error(t, validateOpsAlertEscalationSettings(missingRecipients))
+
+	duplicate := testOpsAlertEscalationPolicy()
+	duplicate.Policies = append(duplicate.Policies, duplicate.Policies[0])
+	require.Error(t, validateOpsAlertEscalationSettings(duplicate))
+
+	require.Error(t, validateOpsAlertRepeatNotificationSettings(OpsAlertRepeatNotificationSettings{Enabled: true}))
+}
@@ -53,6 +53,8 @@ type OpsAlertEvaluatorService struct {
 	skipLogMu sync.Mutex
 	skipLogAt time.Time
 
+	sloStatsCache opsSLOStatsCache
+
 	warnNoRedisOnce sync.Once
 }
 
@@ -228,9 +230,21 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 			s.resetRuleState(rule.ID, now)
 			continue
 		}
+
+		var anomaly *OpsAlertAnomaly
+		if IsOpsAlertAnomalyOperator(rule.Operator) {
+			anomaly, ok = s.evaluateRuleAnomaly(ctx, rule, metricValue, windowEnd, scopePlatform, scopeGroupID)
+			if !ok {
+				s.resetRuleState(rule.ID, now)
+				continue
+			}
+		}
 		rulesEvaluated++
 
 		breachedNow := compareMetric(metricValue, rule.Operator, rule.Threshold)
+		if anomaly != nil {
+			breachedNow = anomaly.breached(rule.Operator, rule.Threshold)
+		}
 		required := requiredSustainedBreaches(rule.SustainedMinutes, interval)
 		consecutive := s.updateRuleBreaches(rule.ID, now, interval, breachedNow)
 
@@ -242,6 +256,8 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 
 		if breachedNow && consecutive >= required {
 			if activeEvent != nil {
+				// Still firing: escalate / repeat notifications until acknowledged.
+				emailsSent += s.followUpActiveAlertEvent(ctx, runtimeCfg, rule, activeEvent, now)
 				continue
 			}
 
@@ -268,17 +284,26 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				}
 			}
 
+			description := buildOpsAlertDescription(rule, metricValue, windowMinutes, scopePlatform, scopeGroupID)
+			eventValue := metricValue
+			if anomaly != nil {
+				// 异常规则的 threshold 为偏离程度，事件记录偏离值以便与阈值对照
+				description = buildOpsAlertAnomalyDescription(rule, anomaly, windowMinutes, scopePlatform, scopeGroupID)
+				eventValue = anomaly.Deviation
+			}
+
 			firedEvent := &OpsAlertEvent{
 				RuleID:         rule.ID,
 				Severity:       strings.TrimSpace(rule.Severity),
 				Status:         OpsAlertStatusFiring,
 				Title:          fmt.Sprintf("%s: %s", strings.TrimSpace(rule.Severity), strings.TrimSpace(rule.Name)),
-				Description:    buildOpsAlertDescription(rule, metricValue, windowMinutes, scopePlatform, scopeGroupID),
-				MetricValue:    float64Ptr(metricValue),
+				Description:    description,
+				MetricValue:    float64Ptr(eventValue),
 				ThresholdValue: float64Ptr(rule.Threshold),
-				Dimensions:     buildOpsAlertDimensions(scopePlatform, scopeGroupID),
+				Dimensions:     buildOpsAlertDimensions(scopePlatform, scopeGroupID, rule.Filters),
 				FiredAt:        now,
 				CreatedAt:      now,
+				IsPublic:       rule.PublicStatus,
 			}
 
 			created, err := s.opsRepo.CreateAlertEvent(ctx, firedEvent)
@@ -289,6 +314,11 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 
 			eventsCreated++
 			if created != nil && created.ID > 0 {
+				recordOpsAlertEventTimeline(ctx, s.opsRepo, &OpsAlertEventTimelineEntry{
+					EventID: created.ID,
+					Action:  OpsAlertTimelineFired,
+					Message: description,
+				})
 				if s.maybeSendAlertEmail(ctx, runtimeCfg, rule, created) {
 					emailsSent++
 				}
@@ -303,6 +333,10 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				log.Printf("[OpsAlertEvaluator] resolve event failed (event=%d): %v", activeEvent.ID, err)
 			} else {
 				eventsResolved++
+				recordOpsAlertEventTimeline(ctx, s.opsRepo, &OpsAlertEventTimelineEntry{
+					EventID: activeEvent.ID,
+					Action:  OpsAlertTimelineResolved,
+				})
 			}
 		}
 	}
@@ -394,30 +428,7 @@ func parseOpsAlertRuleScope(filters map[string]any) (platform string, groupID *i
 			platform = strings.TrimSpace(s)
 		}
 	}
-	if v, ok := filters["group_id"]; ok {
-		switch t := v.(type) {
-		case float64:
-			if t > 0 {
-				id := int64(t)
-				groupID = &id
-			}
-		case int64:
-			if t > 0 {
-				id := t
-				groupID = &id
-			}
-		case int:
-			if t > 0 {
-				id := int64(t)
-				groupID = &id
-			}
-		case string:
-			n, err := strconv.ParseInt(strings.TrimSpace(t), 10, 64)
-			if err == nil && n > 0 {
-				groupID = &n
-			}
-		}
-	}
+	groupID = parseOpsAlertFilterID(filters, "group_id")
 	if v, ok := filters["region"]; ok {
 		if s, ok := v.(string); ok {
 			vv := strings.TrimSpace(s)
@@ -429,6 +440,33 @@ func parseOpsAlertRuleScope(filters map[string]any) (platform string, groupID *i
 	return platform, groupID, region
 }
 
+// parseOpsAlertFilterID parses a positive ID (group_id / user_id / account_id) from rule filters.
+func parseOpsAlertFilterID(filters map[string]any, key string) *int64 {
+	v, ok := filters[key]
+	if !ok {
+		return nil
+	}
+	var id int64
+	switch t := v.(type) {
+	case float64:
+		id = int64(t)
+	case int64:
+		id = t
+	case int:
+		id = int64(t)
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(t), 10, 64)
+		if err != nil {
+			return nil
+		}
+		id = n
+	}
+	if id <= 0 {
+		return nil
+	}
+	return &id
+}
+
 func (s *OpsAlertEvaluatorService) computeRuleMetric(
 	ctx context.Context,
 	rule *OpsAlertRule,
@@ -506,6 +544,33 @@ func (s *OpsAlertEvaluatorService) computeRuleMetric(
 		return float64(countAccountsByCondition(availability.Accounts, func(acc *AccountAvailability) bool {
 			return acc.HasError && acc.TempUnschedulableUntil == nil
 		})), true
+	case "account_probe_failed_count":
+		count, err := s.opsRepo.CountFailedAccountProbes(ctx, start, end, platform, groupID)
+		if err != nil {
+			return 0, false
+		}
+		return float64(count), true
+	case "tokens_per_minute", "spend_usd",
+		"duration_p50_ms", "duration_p90_ms", "duration_p95_ms", "duration_p99_ms",
+		"first_token_p50_ms", "first_token_p90_ms", "first_token_p95_ms", "first_token_p99_ms":
+		usage, err := s.opsRepo.GetAlertUsageMetrics(ctx, buildOpsAlertMetricFilter(rule, start, end, platform, groupID))
+		if err != nil || usage == nil {
+			return 0, false
+		}
+		return opsAlertUsageMetricValue(strings.TrimSpace(rule.MetricType), usage, end.Sub(start))
+	case OpsAlertMetricSLOBurnRate, OpsAlertMetricSLOErrorBudgetRemaining:
+		return s.computeSLORuleMetric(ctx, rule, start, end)
+	case "account_error_rate":
+		filter := buildOpsAlertMetricFilter(rule, start, end, platform, groupID)
+		stats, err := s.opsRepo.ListAlertAccountErrorStats(ctx, filter)
+		if err != nil {
+			return 0, false
+		}
+		minRequests := int64(opsAlertAccountErrorRateMinRequests)
+		if filter.AccountID != nil {
+			minRequests = 1
+		}
+		return maxOpsAlertAccountErrorRate(stats, minRequests)
 	}
 
 	overview, err := s.opsRepo.GetDashboardOverview(ctx, &OpsDashboardFilter{
@@ -523,6 +588,12 @@ func (s *OpsAlertEvaluatorService) computeRuleMetric(
 	}
 
 	switch strings.TrimSpace(rule.MetricType) {
+	case "requests_per_minute":
+		minutes := end.Sub(start).Minutes()
+		if minutes <= 0 {
+			minutes = 1
+		}
+		return float64(overview.RequestCountTotal) / minutes, true
 	case "success_rate":
 		if overview.RequestCountSLA <= 0 {
 			return 0, false
@@ -543,6 +614,79 @@ func (s *OpsAlertEvaluatorService) computeRuleMetric(
 	}
 }
 
+// opsAlertAccountErrorRateMinRequests 未指定 account_id 时，参与 "account_error_rate" 计算的账号最少请求数（避免小样本误报）
+const opsAlertAccountErrorRateMinRequests = 10
+
+func buildOpsAlertMetricFilter(rule *OpsAlertRule, start, end time.Time, platform string, groupID *int64) *OpsAlertMetricFilter {
+	return &OpsAlertMetricFilter{
+		StartTime: start,
+		EndTime:   end,
+		Platform:  platform,
+		GroupID:   groupID,
+		UserID:    parseOpsAlertFilterID(rule.Filters, "user_id"),
+		AccountID: parseOpsAlertFilterID(rule.Filters, "account_id"),
+	}
+}
+
+// opsAlertUsageMetricValue 从窗口内的用量聚合中取出指定指标；窗口内没有延迟样本时返回 false。
+func opsAlertUsageMetricValue(metricType string, usage *OpsAlertUsageMetrics, window time.Duration) (float64, bool) {
+	pick := func(v *int) (float64, bool) {
+		if v == nil {
+			return 0, false
+		}
+		return float64(*v), true
+	}
+	switch metricType {
+	case "tokens_per_minute":
+		minutes := window.Minutes()
+		if minutes <= 0 {
+			minutes = 1
+		}
+		return float64(usage.TokenConsumed) / minutes, true
+	case "spend_usd":
+		return usage.SpendUSD, true
+	case "duration_p50_ms":
+		return pick(usage.Duration.P50)
+	case "duration_p90_ms":
+		return pick(usage.Duration.P90)
+	case "duration_p95_ms":
+		return pick(usage.Duration.P95)
+	case "duration_p99_ms":
+		return pick(usage.Duration.P99)
+	case "first_token_p50_ms":
+		return pick(usage.TTFT.P50)
+	case "first_token_p90_ms":
+		return pick(usage.TTFT.P90)
+	case "first_token_p95_ms":
+		return pick(usage.TTFT.P95)
+	case "first_token_p99_ms":
+		return pick(usage.TTFT.P99)
+	default:
+		return 0, false
+	}
+}
+
+// maxOpsAlertAccountErrorRate 返回请求数不少于 minRequests 的账号中最高的错误率（百分比）
+func maxOpsAlertAccountErrorRate(stats []*OpsAlertAccountErrorStats, minRequests int64) (float64, bool) {
+	found := false
+	maxRate := 0.0
+	for _, item := range stats {
+		if item == nil {
+			continue
+		}
+		total := item.SuccessCount + item.ErrorCountSLA
+		if total <= 0 || total < minRequests {
+			continue
+		}
+		rate := float64(item.ErrorCountSLA) / float64(total) * 100
+		if !found || rate > maxRate {
+			maxRate = rate
+			found = true
+		}
+	}
+	return maxRate, found
+}
+
 func compareMetric(value float64, operator string, threshold float64) bool {
 	switch strings.TrimSpace(operator) {
 	case ">":
@@ -562,7 +706,7 @@ func compareMetric(value float64, operator string, threshold float64) bool {
 	}
 }
 
-func buildOpsAlertDimensions(platform string, groupID *int64) map[string]any {
+func buildOpsAlertDimensions(platform string, groupID *int64, filters map[string]any) map[string]any {
 	dims := map[string]any{}
 	if strings.TrimSpace(platform) != "" {
 		dims["platform"] = strings.TrimSpace(platform)
@@ -570,6 +714,11 @@ func buildOpsAlertDimensions(platform string, groupID *int64) map[string]any {
 	if groupID != nil && *groupID > 0 {
 		dims["group_id"] = *groupID
 	}
+	for _, key := range []string{"user_id", "account_id"} {
+		if id := parseOpsAlertFilterID(filters, key); id != nil {
+			dims[key] = *id
+		}
+	}
 	if len(dims) == 0 {
 		return nil
 	}
@@ -587,6 +736,11 @@ func buildOpsAlertDescription(rule *OpsAlertRule, value float64, windowMinutes i
 	if groupID != nil && *groupID > 0 {
 		scope = fmt.Sprintf("%s group_id=%d", scope, *groupID)
 	}
+	for _, key := range []string{"user_id", "account_id"} {
+		if id := parseOpsAlertFilterID(rule.Filters, key); id != nil {
+			scope = fmt.Sprintf("%s %s=%d", scope, key, *id)
+		}
+	}
 	if windowMinutes <= 0 {
 		windowMinutes = 1
 	}
@@ -601,42 +755,67 @@ func buildOpsAlertDescription(rule *OpsAlertRule, value float64, windowMinutes i
 }
 
 func (s *OpsAlertEvaluatorService) maybeSendAlertEmail(ctx context.Context, runtimeCfg *OpsAlertRuntimeSettings, rule *OpsAlertRule, event *OpsAlertEvent) bool {
-	if s == nil || s.emailService == nil || s.opsService == nil || event == nil || rule == nil {
+	if s == nil || event == nil || rule == nil {
 		return false
 	}
 	if event.EmailSent {
 		return false
 	}
-	if !rule.NotifyEmail {
+
+	if runtimeCfg != nil && runtimeCfg.Silencing.Enabled {
+		if isOpsAlertSilenced(time.Now().UTC(), rule, event, runtimeCfg.Silencing) {
+			return false
+		}
+	}
+
+	recipients := s.alertEmailRecipients(ctx, rule)
+	if len(recipients) == 0 {
 		return false
 	}
 
+	subject := fmt.Sprintf("[Ops Alert][%s] %s", strings.TrimSpace(rule.Severity), strings.TrimSpace(rule.Name))
+	sent := s.sendAlertEmail(ctx, recipients, subject, buildOpsAlertEmailBody(rule, event))
+	if len(sent) == 0 {
+		return false
+	}
+	s.recordAlertEventNotified(event, OpsAlertTimelineNotified, sent, nil)
+	return true
+}
+
+// alertEmailRecipients returns the default alert recipients for the rule, or nil when
+// email notification is disabled for it. It also applies the configured hourly rate limit.
+func (s *OpsAlertEvaluatorService) alertEmailRecipients(ctx context.Context, rule *OpsAlertRule) []string {
+	if s == nil || s.emailService == nil || s.opsService == nil || rule == nil {
+		return nil
+	}
+	if !rule.NotifyEmail {
+		return nil
+	}
+
 	emailCfg, err := s.opsService.GetEmailNotificationConfig(ctx)
 	if err != nil || emailCfg == nil || !emailCfg.Alert.Enabled {
-		return false
+		return nil
 	}
 
 	if len(emailCfg.Alert.Recipients) == 0 {
-		return false
+		return nil
 	}
 	if !shouldSendOpsAlertEmailByMinSeverity(strings.TrimSpace(emailCfg.Alert.MinSeverity), strings.TrimSpace(rule.Severity)) {
-		return false
-	}
-
-	if runtimeCfg != nil && runtimeCfg.Silencing.Enabled {
-		if isOpsAlertSilenced(time.Now().UTC(), rule, event, runtimeCfg.Silencing) {
-			return false
-		}
+		return nil
 	}
 
 	// Apply/update rate limiter.
 	s.emailLimiter.SetLimit(emailCfg.Alert.RateLimitPerHour)
+	return emailCfg.Alert.Recipients
+}
 
-	subject := fmt.Sprintf("[Ops Alert][%s] %s", strings.TrimSpace(rule.Severity), strings.TrimSpace(rule.Name))
-	body := buildOpsAlertEmailBody(rule, event)
-
-	anySent := false
-	for _, to := range emailCfg.Alert.Recipients {
+// sendAlertEmail sends the email to each recipient (best-effort) and returns the addresses that were sent.
+func (s *OpsAlertEvaluatorService) sendAlertEmail(ctx context.Context, recipients []string, subject, body string) []string {
+	if s == nil || s.emailService == nil {
+		return nil
+	}
+	sent := []string{}
+	for _, to := range recipients {
 		addr := strings.TrimSpace(to)
 		if addr == "" {
 			continue
@@ -648,13 +827,31 @@ func (s *OpsAlertEvaluatorService) maybeSendAlertEmail(ctx context.Context, runt
 			// Ignore per-recipient failures; continue best-effort.
 			continue
 		}
-		anySent = true
+		sent = append(sent, addr)
 	}
+	return sent
+}
 
-	if anySent {
-		_ = s.opsRepo.UpdateAlertEventEmailSent(context.Background(), event.ID, true)
+// recordAlertEventNotified updates notification counters and writes a timeline entry.
+func (s *OpsAlertEvaluatorService) recordAlertEventNotified(event *OpsAlertEvent, action string, recipients []string, details map[string]any) {
+	if s == nil || s.opsRepo == nil || event == nil || event.ID <= 0 {
+		return
 	}
-	return anySent
+	now := time.Now().UTC()
+	_ = s.opsRepo.RecordAlertEventNotification(context.Background(), event.ID, now)
+	event.EmailSent = true
+	event.LastNotifiedAt = &now
+	event.NotificationCount++
+
+	if details == nil {
+		details = map[string]any{}
+	}
+	details["recipients"] = recipients
+	recordOpsAlertEventTimeline(context.Background(), s.opsRepo, &OpsAlertEventTimelineEntry{
+		EventID: event.ID,
+		Action:  action,
+		Details: details,
+	})
 }
 
 func buildOpsAlertEmailBody(rule *OpsAlertRule, event *OpsAlertEvent) string {
@@ -208,3 +208,82 @@ func TestComputeRuleMetricNewIndicators(t *testing.T) {
 		})
 	}
 }
+
+type usageMetricsOpsRepo struct {
+	OpsRepository
+	usage        *OpsAlertUsageMetrics
+	accountStats []*OpsAlertAccountErrorStats
+	lastFilter   *OpsAlertMetricFilter
+}
+
+func (s *usageMetricsOpsRepo) GetAlertUsageMetrics(_ context.Context, filter *OpsAlertMetricFilter) (*OpsAlertUsageMetrics, error) {
+	s.lastFilter = filter
+	return s.usage, nil
+}
+
+func (s *usageMetricsOpsRepo) ListAlertAccountErrorStats(_ context.Context, filter *OpsAlertMetricFilter) ([]*OpsAlertAccountErrorStats, error) {
+	s.lastFilter = filter
+	return s.accountStats, nil
+}
+
+func TestComputeRuleMetricUsageIndicators(t *testing.T) {
+	p95 := 4200
+	repo := &usageMetricsOpsRepo{
+		usage: &OpsAlertUsageMetrics{
+			TokenConsumed: 50000,
+			SpendUSD:      12.5,
+			Duration:      OpsPercentiles{P95: &p95},
+		},
+		accountStats: []*OpsAlertAccountErrorStats{
+			{AccountID: 1, SuccessCount: 90, ErrorCountSLA: 10},
+			{AccountID: 2, SuccessCount: 2, ErrorCountSLA: 3},
+			{AccountID: 3, SuccessCount: 6, ErrorCountSLA: 4},
+		},
+	}
+	svc := &OpsAlertEvaluatorService{opsRepo: repo}
+
+	end := time.Now().UTC()
+	start := end.Add(-5 * time.Minute)
+	ctx := context.Background()
+
+	got, ok := svc.computeRuleMetric(ctx, &OpsAlertRule{MetricType: "tokens_per_minute"}, nil, start, end, "", nil)
+	require.True(t, ok)
+	require.InDelta(t, 10000.0, got, 0.0001)
+
+	got, ok = svc.computeRuleMetric(ctx, &OpsAlertRule{
+		MetricType: "spend_usd",
+		Filters:    map[string]any{"user_id": float64(42)},
+	}, nil, start, end, "anthropic", nil)
+	require.True(t, ok)
+	require.InDelta(t, 12.5, got, 0.0001)
+	require.NotNil(t, repo.lastFilter.UserID)
+	require.Equal(t, int64(42), *repo.lastFilter.UserID)
+	require.Equal(t, "anthropic", repo.lastFilter.Platform)
+
+	got, ok = svc.computeRuleMetric(ctx, &OpsAlertRule{MetricType: "duration_p95_ms"}, nil, start, end, "", nil)
+	require.True(t, ok)
+	require.InDelta(t, 4200.0, got, 0.0001)
+
+	// 窗口内没有首 Token 样本时不参与判定
+	_, ok = svc.computeRuleMetric(ctx, &OpsAlertRule{MetricType: "first_token_p95_ms"}, nil, start, end, "", nil)
+	require.False(t, ok)
+
+	// 未指定 account_id 时忽略请求数不足的账号（账号 2 只有 5 个请求）
+	got, ok = svc.computeRuleMetric(ctx, &OpsAlertRule{MetricType: "account_error_rate"}, nil, start, end, "", nil)
+	require.True(t, ok)
+	require.InDelta(t, 40.0, got, 0.0001)
+
+	got, ok = svc.computeRuleMetric(ctx, &OpsAlertRule{
+		MetricType: "account_error_rate",
+		Filters:    map[string]any{"account_id": "2"},
+	}, nil, start, end, "", nil)
+	require.True(t, ok)
+	require.InDelta(t, 60.0, got, 0.0001)
+	require.Equal(t, int64(2), *repo.lastFilter.AccountID)
+}
+
+func TestBuildOpsAlertDimensionsIncludesUserAndAccount(t *testing.T) {
+	groupID := int64(7)
+	dims := buildOpsAlertDimensions("openai", &groupID, map[string]any{"user_id": float64(3), "account_id": "0"})
+	require.Equal(t, map[string]any{"platform": "openai", "group_id": int64(7), "user_id": int64(3)}, dims)
+}
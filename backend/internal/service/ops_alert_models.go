@@ -33,6 +33,13 @@ type OpsAlertRule struct {
 
 	Filters map[string]any `json:"filters,omitempty"`
 
+	// Anomaly settings, only used by operators "anomaly_above" / "anomaly_below".
+	AnomalyMethod string `json:"anomaly_method,omitempty"`
+	BaselineDays  int    `json:"baseline_days,omitempty"`
+
+	// PublicStatus publishes events fired by this rule as incidents on the public status page.
+	PublicStatus bool `json:"public_status"`
+
 	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
@@ -57,6 +64,53 @@ type OpsAlertEvent struct {
 
 	EmailSent bool      `json:"email_sent"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Acknowledgement stops escalation and repeat notifications for this event.
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *int64     `json:"acknowledged_by,omitempty"`
+	Assignee       string     `json:"assignee,omitempty"`
+	AckNote        string     `json:"ack_note,omitempty"`
+
+	// EscalationLevel is the number of escalation levels already notified.
+	EscalationLevel   int        `json:"escalation_level"`
+	LastNotifiedAt    *time.Time `json:"last_notified_at,omitempty"`
+	NotificationCount int        `json:"notification_count"`
+
+	// IsPublic shows the event on the public status page while it is firing.
+	// PublicSummary replaces the internal title there when set.
+	IsPublic      bool   `json:"is_public"`
+	PublicSummary string `json:"public_summary,omitempty"`
+}
+
+// Alert event timeline actions.
+const (
+	OpsAlertTimelineFired          = "fired"
+	OpsAlertTimelineNotified       = "notified"
+	OpsAlertTimelineRepeatNotified = "repeat_notified"
+	OpsAlertTimelineEscalated      = "escalated"
+	OpsAlertTimelineAcknowledged   = "acknowledged"
+	OpsAlertTimelineResolved       = "resolved"
+	OpsAlertTimelineManualResolved = "manual_resolved"
+)
+
+type OpsAlertEventTimelineEntry struct {
+	ID      int64  `json:"id"`
+	EventID int64  `json:"event_id"`
+	Action  string `json:"action"`
+
+	ActorUserID *int64         `json:"actor_user_id,omitempty"`
+	Message     string         `json:"message,omitempty"`
+	Details     map[string]any `json:"details,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OpsAlertEventAckInput acknowledges a firing event.
+type OpsAlertEventAckInput struct {
+	EventID        int64
+	AcknowledgedBy *int64
+	Assignee       string
+	Note           string
 }
 
 type OpsAlertSilence struct {
@@ -82,9 +136,10 @@ type OpsAlertEventFilter struct {
 	BeforeID      *int64
 
 	// Optional filters.
-	Status    string
-	Severity  string
-	EmailSent *bool
+	Status     string
+	Severity   string
+	EmailSent  *bool
+	PublicOnly bool
 
 	StartTime *time.Time
 	EndTime   *time.Time
@@ -93,3 +148,46 @@ type OpsAlertEventFilter struct {
 	Platform string
 	GroupID  *int64
 }
+
+// OpsAlertMetricFilter scopes usage-based alert metrics (latency / tokens / spend / per-account errors).
+// UserID and AccountID come from rule filters and are only honored by these metrics.
+type OpsAlertMetricFilter struct {
+	StartTime time.Time
+	EndTime   time.Time
+
+	Platform  string
+	GroupID   *int64
+	UserID    *int64
+	AccountID *int64
+}
+
+// OpsAlertUsageMetrics aggregates successful usage logs within an alert window.
+type OpsAlertUsageMetrics struct {
+	RequestCount  int64
+	TokenConsumed int64
+	SpendUSD      float64
+
+	Duration OpsPercentiles
+	TTFT     OpsPercentiles
+}
+
+// OpsAlertAccountErrorStats is the per-account success/error breakdown used by "account_error_rate".
+type OpsAlertAccountErrorStats struct {
+	AccountID     int64
+	SuccessCount  int64
+	ErrorCountSLA int64
+}
+
+// OpsAlertBaselineBucket is one ops_metrics_hourly row used as an anomaly baseline sample.
+type OpsAlertBaselineBucket struct {
+	BucketStart time.Time
+
+	SuccessCount                 int64
+	ErrorCountTotal              int64
+	ErrorCountSLA                int64
+	UpstreamErrorCountExcl429529 int64
+	TokenConsumed                int64
+
+	Duration OpsPercentiles
+	TTFT     OpsPercentiles
+}
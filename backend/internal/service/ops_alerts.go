@@ -198,7 +198,7 @@ func (s *OpsService) CreateAlertEvent(ctx context.Context, event *OpsAlertEvent)
 	return created, nil
 }
 
-func (s *OpsService) UpdateAlertEventStatus(ctx context.Context, eventID int64, status string, resolvedAt *time.Time) error {
+func (s *OpsService) UpdateAlertEventStatus(ctx context.Context, eventID int64, status string, resolvedAt *time.Time, actorUserID *int64) error {
 	if err := s.RequireMonitoringEnabled(ctx); err != nil {
 		return err
 	}
@@ -215,7 +215,76 @@ func (s *OpsService) UpdateAlertEventStatus(ctx context.Context, eventID int64,
 	if status != OpsAlertStatusResolved && status != OpsAlertStatusManualResolved {
 		return infraerrors.BadRequest("INVALID_STATUS", "invalid status")
 	}
-	return s.opsRepo.UpdateAlertEventStatus(ctx, eventID, status, resolvedAt)
+	if err := s.opsRepo.UpdateAlertEventStatus(ctx, eventID, status, resolvedAt); err != nil {
+		return err
+	}
+	s.recordAlertEventTimeline(ctx, &OpsAlertEventTimelineEntry{
+		EventID:     eventID,
+		Action:      status,
+		ActorUserID: actorUserID,
+	})
+	return nil
+}
+
+// AcknowledgeAlertEvent 确认一个触发中的告警事件，确认后不再升级和重复通知
+func (s *OpsService) AcknowledgeAlertEvent(ctx context.Context, input *OpsAlertEventAckInput) (*OpsAlertEvent, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if input == nil || input.EventID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_EVENT_ID", "invalid event id")
+	}
+	input.Assignee = strings.TrimSpace(input.Assignee)
+	input.Note = strings.TrimSpace(input.Note)
+	if len(input.Assignee) > 255 {
+		return nil, infraerrors.BadRequest("INVALID_ASSIGNEE", "assignee is too long")
+	}
+
+	ev, err := s.GetAlertEventByID(ctx, input.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status != OpsAlertStatusFiring {
+		return nil, infraerrors.Conflict("OPS_ALERT_EVENT_NOT_FIRING", "only firing alert events can be acknowledged")
+	}
+
+	if err := s.opsRepo.AcknowledgeAlertEvent(ctx, input, time.Now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.Conflict("OPS_ALERT_EVENT_NOT_FIRING", "only firing alert events can be acknowledged")
+		}
+		return nil, err
+	}
+
+	details := map[string]any{}
+	if input.Assignee != "" {
+		details["assignee"] = input.Assignee
+	}
+	s.recordAlertEventTimeline(ctx, &OpsAlertEventTimelineEntry{
+		EventID:     input.EventID,
+		Action:      OpsAlertTimelineAcknowledged,
+		ActorUserID: input.AcknowledgedBy,
+		Message:     input.Note,
+		Details:     details,
+	})
+	return s.GetAlertEventByID(ctx, input.EventID)
+}
+
+func (s *OpsService) ListAlertEventTimeline(ctx context.Context, eventID int64) ([]*OpsAlertEventTimelineEntry, error) {
+	if _, err := s.GetAlertEventByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.opsRepo.ListAlertEventTimeline(ctx, eventID)
+}
+
+// recordAlertEventTimeline 记录时间线（best-effort，失败只记日志）
+func (s *OpsService) recordAlertEventTimeline(ctx context.Context, entry *OpsAlertEventTimelineEntry) {
+	if s == nil || s.opsRepo == nil {
+		return
+	}
+	recordOpsAlertEventTimeline(ctx, s.opsRepo, entry)
 }
 
 func (s *OpsService) UpdateAlertEventEmailSent(ctx context.Context, eventID int64, emailSent bool) error {
@@ -154,23 +154,27 @@ func (s *OpsCleanupService) runScheduled() {
 }
 
 type opsCleanupDeletedCounts struct {
-	errorLogs     int64
-	retryAttempts int64
-	alertEvents   int64
-	systemMetrics int64
-	hourlyPreagg  int64
-	dailyPreagg   int64
+	errorLogs       int64
+	retryAttempts   int64
+	alertEvents     int64
+	systemMetrics   int64
+	hourlyPreagg    int64
+	dailyPreagg     int64
+	requestCaptures int64
+	shadowResults   int64
 }
 
 func (c opsCleanupDeletedCounts) String() string {
 	return fmt.Sprintf(
-		"error_logs=%d retry_attempts=%d alert_events=%d system_metrics=%d hourly_preagg=%d daily_preagg=%d",
+		"error_logs=%d retry_attempts=%d alert_events=%d system_metrics=%d hourly_preagg=%d daily_preagg=%d request_captures=%d shadow_results=%d",
 		c.errorLogs,
 		c.retryAttempts,
 		c.alertEvents,
 		c.systemMetrics,
 		c.hourlyPreagg,
 		c.dailyPreagg,
+		c.requestCaptures,
+		c.shadowResults,
 	)
 }
 
@@ -204,6 +208,20 @@ func (s *OpsCleanupService) runCleanupOnce(ctx context.Context) (opsCleanupDelet
 			return out, err
 		}
 		out.alertEvents = n
+
+		// Event timeline rows follow the same retention as their events.
+		n, err = deleteOldRowsByID(ctx, s.db, "ops_alert_event_timeline", "created_at", cutoff, batchSize, false)
+		if err != nil {
+			return out, err
+		}
+		out.alertEvents += n
+
+		// Shadow comparison results follow the error log retention.
+		n, err = deleteOldRowsByID(ctx, s.db, "ops_shadow_results", "created_at", cutoff, batchSize, false)
+		if err != nil {
+			return out, err
+		}
+		out.shadowResults = n
 	}
 
 	// Minute-level metrics snapshots.
@@ -214,6 +232,14 @@ func (s *OpsCleanupService) runCleanupOnce(ctx context.Context) (opsCleanupDelet
 			return out, err
 		}
 		out.systemMetrics = n
+
+		for _, table := range []string{"ops_latency_histogram_minutely", "ops_latency_histogram_coverage"} {
+			n, err = deleteOldRowsByID(ctx, s.db, table, "bucket_start", cutoff, batchSize, false)
+			if err != nil {
+				return out, err
+			}
+			out.systemMetrics += n
+		}
 	}
 
 	// Pre-aggregation tables (hourly/daily).
@@ -232,6 +258,13 @@ func (s *OpsCleanupService) runCleanupOnce(ctx context.Context) (opsCleanupDelet
 		out.dailyPreagg = n
 	}
 
+	// Request captures carry their own expiry (capture settings retention_hours).
+	n, err := deleteOldRowsByID(ctx, s.db, "ops_request_captures", "expires_at", now, batchSize, false)
+	if err != nil {
+		return out, err
+	}
+	out.requestCaptures = n
+
 	return out, nil
 }
 
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
@@ -97,6 +98,33 @@ func (s *OpsService) getAccountsLoadMapBestEffort(ctx context.Context, accounts
 	return out
 }
 
+// getAccountModelSlotsBestEffort returns per-model slot usage for accounts with model_concurrency configured.
+func (s *OpsService) getAccountModelSlotsBestEffort(ctx context.Context, acc *Account) []AccountModelConcurrencyInfo {
+	limits := acc.GetModelConcurrencyLimits()
+	if len(limits) == 0 || s.concurrencyService == nil {
+		return nil
+	}
+	patterns := make([]string, 0, len(limits))
+	for pattern := range limits {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	out := make([]AccountModelConcurrencyInfo, 0, len(patterns))
+	for _, pattern := range patterns {
+		inUse, err := s.concurrencyService.GetAccountModelConcurrency(ctx, acc.ID, pattern)
+		if err != nil {
+			log.Printf("[Ops] GetAccountModelConcurrency failed: account=%d pattern=%s err=%v", acc.ID, pattern, err)
+		}
+		out = append(out, AccountModelConcurrencyInfo{
+			Pattern:      pattern,
+			CurrentInUse: int64(inUse),
+			MaxCapacity:  int64(limits[pattern]),
+		})
+	}
+	return out
+}
+
 // GetConcurrencyStats returns real-time concurrency usage aggregated by platform/group/account.
 //
 // Optional filters:
@@ -178,6 +206,7 @@ func (s *OpsService) GetConcurrencyStats(
 			if info.MaxCapacity > 0 {
 				info.LoadPercentage = float64(info.CurrentInUse) / float64(info.MaxCapacity) * 100
 			}
+			info.ModelSlots = s.getAccountModelSlotsBestEffort(ctx, &acc)
 			account[acc.ID] = info
 		}
 
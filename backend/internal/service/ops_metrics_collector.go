@@ -280,6 +280,13 @@ func (c *OpsMetricsCollector) collectAndPersist(ctx context.Context) error {
 		return fmt.Errorf("query usage latency: %w", err)
 	}
 
+	// Latency histograms back the alert percentile metrics. Rebuild every minute since the previous
+	// run (the interval is configurable) plus one extra minute to pick up late usage logs.
+	// Best-effort: alert evaluation falls back to raw usage_logs for minutes that were not rolled up.
+	if err := c.opsRepo.UpsertLatencyBucketsMinutely(ctx, windowEnd.Add(-c.getInterval()-time.Minute), windowEnd); err != nil {
+		log.Printf("[OpsMetricsCollector] upsert latency buckets failed: %v", err)
+	}
+
 	errorTotal, businessLimited, errorSLA, upstreamExcl, upstream429, upstream529, err := c.queryErrorCounts(ctx, windowStart, windowEnd)
 	if err != nil {
 		return fmt.Errorf("query error counts: %w", err)
@@ -47,6 +47,57 @@ type OpsRepository interface {
 	CreateAlertEvent(ctx context.Context, event *OpsAlertEvent) (*OpsAlertEvent, error)
 	UpdateAlertEventStatus(ctx context.Context, eventID int64, status string, resolvedAt *time.Time) error
 	UpdateAlertEventEmailSent(ctx context.Context, eventID int64, emailSent bool) error
+	AcknowledgeAlertEvent(ctx context.Context, input *OpsAlertEventAckInput, ackAt time.Time) error
+	UpdateAlertEventEscalationLevel(ctx context.Context, eventID int64, level int) error
+	// RecordAlertEventNotification marks the event as emailed and bumps notification_count / last_notified_at.
+	RecordAlertEventNotification(ctx context.Context, eventID int64, notifiedAt time.Time) error
+	CreateAlertEventTimelineEntry(ctx context.Context, entry *OpsAlertEventTimelineEntry) error
+	ListAlertEventTimeline(ctx context.Context, eventID int64) ([]*OpsAlertEventTimelineEntry, error)
+
+	// Synthetic account probes (see AccountProbeService), used by alert metric "account_probe_failed_count".
+	CountFailedAccountProbes(ctx context.Context, start, end time.Time, platform string, groupID *int64) (int64, error)
+
+	// Usage-based alert metrics (latency percentiles, tokens per minute, spend, per-account error rate).
+	GetAlertUsageMetrics(ctx context.Context, filter *OpsAlertMetricFilter) (*OpsAlertUsageMetrics, error)
+	ListAlertAccountErrorStats(ctx context.Context, filter *OpsAlertMetricFilter) ([]*OpsAlertAccountErrorStats, error)
+	// ListAlertBaselineBuckets returns ops_metrics_hourly rows at the given bucket starts (anomaly baselines).
+	ListAlertBaselineBuckets(ctx context.Context, platform string, groupID *int64, bucketStarts []time.Time) ([]*OpsAlertBaselineBucket, error)
+
+	// SLOs (error budget / burn rate are computed from usage_logs + ops_error_logs)
+	ListSLOs(ctx context.Context) ([]*OpsSLO, error)
+	GetSLOByID(ctx context.Context, id int64) (*OpsSLO, error)
+	CreateSLO(ctx context.Context, input *OpsSLO) (*OpsSLO, error)
+	UpdateSLO(ctx context.Context, input *OpsSLO) (*OpsSLO, error)
+	DeleteSLO(ctx context.Context, id int64) error
+	GetSLOWindowStats(ctx context.Context, filter *OpsDashboardFilter, latencyTargetMs *int) (*OpsSLOWindowStats, error)
+
+	// Sampled request/response captures (successful requests, opt-in)
+	InsertRequestCapture(ctx context.Context, input *OpsRequestCapture) (int64, error)
+	ListRequestCaptures(ctx context.Context, filter *OpsRequestCaptureFilter) (*OpsRequestCaptureList, error)
+	GetRequestCaptureByID(ctx context.Context, id int64) (*OpsRequestCapture, error)
+	DeleteRequestCapture(ctx context.Context, id int64) error
+	// PruneRequestCaptures deletes expired captures and the oldest rows beyond maxRows.
+	PruneRequestCaptures(ctx context.Context, now time.Time, maxRows int) (int64, error)
+
+	// Traffic shadowing (rules + primary-vs-shadow comparison results)
+	ListShadowRules(ctx context.Context) ([]*OpsShadowRule, error)
+	GetShadowRuleByID(ctx context.Context, id int64) (*OpsShadowRule, error)
+	CreateShadowRule(ctx context.Context, input *OpsShadowRule) (*OpsShadowRule, error)
+	UpdateShadowRule(ctx context.Context, input *OpsShadowRule) (*OpsShadowRule, error)
+	DeleteShadowRule(ctx context.Context, id int64) error
+	InsertShadowResult(ctx context.Context, input *OpsShadowResult) error
+	GetShadowReportStats(ctx context.Context, ruleID int64, start, end time.Time) (*OpsShadowReportStats, error)
+	ListShadowResults(ctx context.Context, ruleID int64, start, end time.Time, limit int) ([]*OpsShadowResult, error)
+
+	// Public status page
+	GetStatusPageStats(ctx context.Context, start, end time.Time) ([]*OpsStatusPageStatsRow, error)
+	UpdateAlertEventPublic(ctx context.Context, eventID int64, isPublic bool, summary string) error
+	ListStatusIncidents(ctx context.Context, filter *OpsStatusIncidentFilter) ([]*OpsStatusIncident, error)
+	GetStatusIncidentByID(ctx context.Context, id int64) (*OpsStatusIncident, error)
+	CreateStatusIncident(ctx context.Context, input *OpsStatusIncident) (*OpsStatusIncident, error)
+	UpdateStatusIncident(ctx context.Context, input *OpsStatusIncident) error
+	DeleteStatusIncident(ctx context.Context, id int64) error
+	CreateStatusIncidentUpdate(ctx context.Context, input *OpsStatusIncidentUpdate) (*OpsStatusIncidentUpdate, error)
 
 	// Alert silences
 	CreateAlertSilence(ctx context.Context, input *OpsAlertSilence) (*OpsAlertSilence, error)
@@ -57,6 +108,9 @@ type OpsRepository interface {
 	UpsertDailyMetrics(ctx context.Context, startTime, endTime time.Time) error
 	GetLatestHourlyBucketStart(ctx context.Context) (time.Time, bool, error)
 	GetLatestDailyBucketDate(ctx context.Context) (time.Time, bool, error)
+	// UpsertLatencyBucketsMinutely rebuilds the per-minute latency histograms used by alert percentile metrics
+	// and records the complete minutes it covered.
+	UpsertLatencyBucketsMinutely(ctx context.Context, startTime, endTime time.Time) error
 }
 
 type OpsInsertErrorLogInput struct {
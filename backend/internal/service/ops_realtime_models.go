@@ -35,6 +35,16 @@ type AccountConcurrencyInfo struct {
 	MaxCapacity    int64   `json:"max_capacity"`
 	LoadPercentage float64 `json:"load_percentage"`
 	WaitingInQueue int64   `json:"waiting_in_queue"`
+
+	// ModelSlots 模型级并发槽位使用情况（仅配置了 model_concurrency 的账号）
+	ModelSlots []AccountModelConcurrencyInfo `json:"model_slots,omitempty"`
+}
+
+// AccountModelConcurrencyInfo represents real-time usage of a per-model concurrency slot on an account.
+type AccountModelConcurrencyInfo struct {
+	Pattern      string `json:"pattern"`
+	CurrentInUse int64  `json:"current_in_use"`
+	MaxCapacity  int64  `json:"max_capacity"`
 }
 
 // UserConcurrencyInfo represents real-time concurrency usage for a single user.
@@ -0,0 +1,561 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// SettingKeyOpsRequestCaptureSettings stores JSON config for sampled request/response capture.
+const SettingKeyOpsRequestCaptureSettings = "ops_request_capture_settings"
+
+// Capture reasons (which rule matched).
+const (
+	OpsRequestCaptureReasonAPIKey = "api_key"
+	OpsRequestCaptureReasonUser   = "user"
+	OpsRequestCaptureReasonSample = "sample"
+)
+
+// Capture target types.
+const (
+	OpsRequestCaptureTargetAPIKey = "api_key"
+	OpsRequestCaptureTargetUser   = "user"
+)
+
+// Stored response formats.
+const (
+	OpsRequestCaptureFormatJSON             = "json"
+	OpsRequestCaptureFormatSSEReconstructed = "sse_reconstructed"
+	OpsRequestCaptureFormatSSERaw           = "sse_raw"
+	OpsRequestCaptureFormatText             = "text"
+)
+
+const (
+	opsRequestCaptureRetentionHoursDefault = 72
+	opsRequestCaptureRetentionHoursMax     = 30 * 24
+	opsRequestCaptureBodyBytesDefault      = 64 * 1024
+	opsRequestCaptureBodyBytesMax          = 1024 * 1024
+	opsRequestCaptureMaxRowsDefault        = 10000
+	opsRequestCaptureMaxRowsMax            = 1000000
+	opsRequestCaptureMaxTargets            = 200
+
+	// opsRequestCaptureSettingsCacheTTL bounds how long the gateway hot path uses cached rules.
+	opsRequestCaptureSettingsCacheTTL = 10 * time.Second
+	// opsRequestCapturePruneEvery triggers a size-cap prune after this many inserts.
+	opsRequestCapturePruneEvery = 100
+
+	opsRequestCaptureRedacted = "[REDACTED]"
+)
+
+// OpsRequestCaptureTarget enables capture for one API key or user until ExpiresAt (nil = no expiry).
+type OpsRequestCaptureTarget struct {
+	Type      string     `json:"type"`
+	ID        int64      `json:"id"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// OpsRequestCaptureSettings controls opt-in capture of successful gateway requests.
+type OpsRequestCaptureSettings struct {
+	Enabled bool `json:"enabled"`
+
+	// SampleRate is the global sampling probability (0-1) applied to all successful requests.
+	SampleRate float64 `json:"sample_rate"`
+	// SampleUntil stops global sampling after this time (nil = no expiry).
+	SampleUntil *time.Time `json:"sample_until,omitempty"`
+
+	Targets []OpsRequestCaptureTarget `json:"targets"`
+
+	RetentionHours   int `json:"retention_hours"`
+	MaxRequestBytes  int `json:"max_request_bytes"`
+	MaxResponseBytes int `json:"max_response_bytes"`
+	// MaxCaptures caps the number of stored captures; the oldest rows are pruned first.
+	MaxCaptures int `json:"max_captures"`
+
+	// RedactKeys are extra JSON field names (case-insensitive) whose values are replaced.
+	RedactKeys []string `json:"redact_keys"`
+	// RedactPatterns are regular expressions applied to string values.
+	RedactPatterns []string `json:"redact_patterns"`
+}
+
+// OpsRequestCaptureDecision is the per-request capture decision made on the gateway hot path.
+type OpsRequestCaptureDecision struct {
+	Reason           string
+	MaxResponseBytes int
+}
+
+// OpsInsertRequestCaptureInput is the raw capture handed over by the gateway middleware.
+type OpsInsertRequestCaptureInput struct {
+	RequestID       string
+	ClientRequestID string
+
+	UserID    *int64
+	APIKeyID  *int64
+	AccountID *int64
+	GroupID   *int64
+
+	Platform    string
+	Model       string
+	RequestPath string
+	Stream      bool
+	StatusCode  int
+
+	CaptureReason string
+
+	RawRequestBody    []byte
+	RawResponseBody   []byte
+	ResponseBytes     int
+	ResponseTruncated bool
+
+	CreatedAt time.Time
+}
+
+// OpsRequestCapture is a stored (sanitized) capture.
+type OpsRequestCapture struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	RequestID       string `json:"request_id"`
+	ClientRequestID string `json:"client_request_id"`
+
+	UserID    *int64 `json:"user_id"`
+	APIKeyID  *int64 `json:"api_key_id"`
+	AccountID *int64 `json:"account_id"`
+	GroupID   *int64 `json:"group_id"`
+
+	Platform    string `json:"platform"`
+	Model       string `json:"model"`
+	RequestPath string `json:"request_path"`
+	Stream      bool   `json:"stream"`
+	StatusCode  int    `json:"status_code"`
+
+	CaptureReason string `json:"capture_reason"`
+
+	// Bodies are omitted in list responses.
+	RequestBody       string `json:"request_body,omitempty"`
+	RequestBodyBytes  int    `json:"request_body_bytes"`
+	RequestTruncated  bool   `json:"request_truncated"`
+	ResponseBody      string `json:"response_body,omitempty"`
+	ResponseFormat    string `json:"response_format"`
+	ResponseBodyBytes int    `json:"response_body_bytes"`
+	ResponseTruncated bool   `json:"response_truncated"`
+}
+
+type OpsRequestCaptureFilter struct {
+	StartTime *time.Time
+	EndTime   *time.Time
+
+	RequestID       string
+	ClientRequestID string
+	UserID          *int64
+	APIKeyID        *int64
+	AccountID       *int64
+
+	Page     int
+	PageSize int
+}
+
+type OpsRequestCaptureList struct {
+	Items    []*OpsRequestCapture `json:"items"`
+	Total    int                  `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+}
+
+type opsRequestCaptureSettingsCache struct {
+	settings *OpsRequestCaptureSettings
+	patterns []*regexp.Regexp
+	loadedAt time.Time
+}
+
+func defaultOpsRequestCaptureSettings() *OpsRequestCaptureSettings {
+	return &OpsRequestCaptureSettings{
+		Enabled:          false,
+		SampleRate:       0,
+		Targets:          []OpsRequestCaptureTarget{},
+		RetentionHours:   opsRequestCaptureRetentionHoursDefault,
+		MaxRequestBytes:  opsRequestCaptureBodyBytesDefault,
+		MaxResponseBytes: opsRequestCaptureBodyBytesDefault,
+		MaxCaptures:      opsRequestCaptureMaxRowsDefault,
+		RedactKeys:       []string{},
+		RedactPatterns:   []string{},
+	}
+}
+
+func normalizeOpsRequestCaptureSettings(cfg *OpsRequestCaptureSettings) {
+	if cfg == nil {
+		return
+	}
+	if cfg.RetentionHours <= 0 {
+		cfg.RetentionHours = opsRequestCaptureRetentionHoursDefault
+	}
+	if cfg.MaxRequestBytes <= 0 {
+		cfg.MaxRequestBytes = opsRequestCaptureBodyBytesDefault
+	}
+	if cfg.MaxResponseBytes <= 0 {
+		cfg.MaxResponseBytes = opsRequestCaptureBodyBytesDefault
+	}
+	if cfg.MaxCaptures <= 0 {
+		cfg.MaxCaptures = opsRequestCaptureMaxRowsDefault
+	}
+	if cfg.Targets == nil {
+		cfg.Targets = []OpsRequestCaptureTarget{}
+	}
+	for i := range cfg.Targets {
+		cfg.Targets[i].Type = strings.ToLower(strings.TrimSpace(cfg.Targets[i].Type))
+		cfg.Targets[i].Note = strings.TrimSpace(cfg.Targets[i].Note)
+	}
+	cfg.RedactKeys = normalizeOpsStringList(cfg.RedactKeys)
+	cfg.RedactPatterns = normalizeOpsStringList(cfg.RedactPatterns)
+}
+
+func normalizeOpsStringList(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+func validateOpsRequestCaptureSettings(cfg *OpsRequestCaptureSettings) error {
+	if cfg == nil {
+		return errors.New("invalid config")
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return errors.New("sample_rate must be between 0 and 1")
+	}
+	if cfg.RetentionHours < 1 || cfg.RetentionHours > opsRequestCaptureRetentionHoursMax {
+		return fmt.Errorf("retention_hours must be between 1 and %d", opsRequestCaptureRetentionHoursMax)
+	}
+	if cfg.MaxRequestBytes < 1024 || cfg.MaxRequestBytes > opsRequestCaptureBodyBytesMax {
+		return fmt.Errorf("max_request_bytes must be between 1024 and %d", opsRequestCaptureBodyBytesMax)
+	}
+	if cfg.MaxResponseBytes < 1024 || cfg.MaxResponseBytes > opsRequestCaptureBodyBytesMax {
+		return fmt.Errorf("max_response_bytes must be between 1024 and %d", opsRequestCaptureBodyBytesMax)
+	}
+	if cfg.MaxCaptures < 1 || cfg.MaxCaptures > opsRequestCaptureMaxRowsMax {
+		return fmt.Errorf("max_captures must be between 1 and %d", opsRequestCaptureMaxRowsMax)
+	}
+	if len(cfg.Targets) > opsRequestCaptureMaxTargets {
+		return fmt.Errorf("at most %d capture targets are allowed", opsRequestCaptureMaxTargets)
+	}
+	for _, t := range cfg.Targets {
+		if t.Type != OpsRequestCaptureTargetAPIKey && t.Type != OpsRequestCaptureTargetUser {
+			return fmt.Errorf("invalid capture target type %q (expected api_key or user)", t.Type)
+		}
+		if t.ID <= 0 {
+			return errors.New("capture target id must be positive")
+		}
+	}
+	if _, err := compileOpsRedactPatterns(cfg.RedactPatterns); err != nil {
+		return err
+	}
+	return nil
+}
+
+func compileOpsRedactPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %v", p, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// decide returns the matched capture reason ("" = do not capture).
+// Explicit targets win over sampling; rnd is a uniform random number in [0,1).
+func (cfg *OpsRequestCaptureSettings) decide(userID, apiKeyID int64, now time.Time, rnd float64) string {
+	if cfg == nil || !cfg.Enabled {
+		return ""
+	}
+	for _, t := range cfg.Targets {
+		if t.ExpiresAt != nil && !now.Before(*t.ExpiresAt) {
+			continue
+		}
+		switch {
+		case t.Type == OpsRequestCaptureTargetAPIKey && apiKeyID > 0 && t.ID == apiKeyID:
+			return OpsRequestCaptureReasonAPIKey
+		case t.Type == OpsRequestCaptureTargetUser && userID > 0 && t.ID == userID:
+			return OpsRequestCaptureReasonUser
+		}
+	}
+	if cfg.SampleRate <= 0 {
+		return ""
+	}
+	if cfg.SampleUntil != nil && !now.Before(*cfg.SampleUntil) {
+		return ""
+	}
+	if rnd < cfg.SampleRate {
+		return OpsRequestCaptureReasonSample
+	}
+	return ""
+}
+
+func (s *OpsService) GetRequestCaptureSettings(ctx context.Context) (*OpsRequestCaptureSettings, error) {
+	defaultCfg := defaultOpsRequestCaptureSettings()
+	if s == nil || s.settingRepo == nil {
+		return defaultCfg, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	raw, err := s.settingRepo.GetValue(ctx, SettingKeyOpsRequestCaptureSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	cfg := &OpsRequestCaptureSettings{}
+	if err := json.Unmarshal([]byte(raw), cfg); err != nil {
+		return defaultCfg, nil
+	}
+	normalizeOpsRequestCaptureSettings(cfg)
+	return cfg, nil
+}
+
+func (s *OpsService) UpdateRequestCaptureSettings(ctx context.Context, cfg *OpsRequestCaptureSettings) (*OpsRequestCaptureSettings, error) {
+	if s == nil || s.settingRepo == nil {
+		return nil, errors.New("setting repository not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if cfg == nil {
+		return nil, errors.New("invalid config")
+	}
+
+	normalizeOpsRequestCaptureSettings(cfg)
+	if err := validateOpsRequestCaptureSettings(cfg); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyOpsRequestCaptureSettings, string(raw)); err != nil {
+		return nil, err
+	}
+	// Apply immediately on this instance.
+	s.captureSettingsCache.Store(nil)
+
+	updated := &OpsRequestCaptureSettings{}
+	_ = json.Unmarshal(raw, updated)
+	return updated, nil
+}
+
+// cachedRequestCaptureSettings returns capture settings from a short-lived cache (gateway hot path).
+func (s *OpsService) cachedRequestCaptureSettings(ctx context.Context) *opsRequestCaptureSettingsCache {
+	now := time.Now()
+	if cached := s.captureSettingsCache.Load(); cached != nil && now.Sub(cached.loadedAt) < opsRequestCaptureSettingsCacheTTL {
+		return cached
+	}
+	cfg, err := s.GetRequestCaptureSettings(ctx)
+	if err != nil || cfg == nil {
+		// Fail closed: never capture on settings errors.
+		cfg = defaultOpsRequestCaptureSettings()
+	}
+	patterns, err := compileOpsRedactPatterns(cfg.RedactPatterns)
+	if err != nil {
+		patterns = nil
+	}
+	entry := &opsRequestCaptureSettingsCache{settings: cfg, patterns: patterns, loadedAt: now}
+	s.captureSettingsCache.Store(entry)
+	return entry
+}
+
+// DecideRequestCapture decides whether a successful gateway request should be captured.
+// Returns nil when the request should not be captured.
+func (s *OpsService) DecideRequestCapture(ctx context.Context, userID, apiKeyID int64) *OpsRequestCaptureDecision {
+	if s == nil || s.opsRepo == nil {
+		return nil
+	}
+	cached := s.cachedRequestCaptureSettings(ctx)
+	reason := cached.settings.decide(userID, apiKeyID, time.Now(), rand.Float64())
+	if reason == "" {
+		return nil
+	}
+	return &OpsRequestCaptureDecision{Reason: reason, MaxResponseBytes: cached.settings.MaxResponseBytes}
+}
+
+// RecordRequestCapture sanitizes and stores a capture. Called from the async ops worker.
+func (s *OpsService) RecordRequestCapture(ctx context.Context, input *OpsInsertRequestCaptureInput) error {
+	if s == nil || s.opsRepo == nil || input == nil {
+		return nil
+	}
+	if input.CreatedAt.IsZero() {
+		input.CreatedAt = time.Now()
+	}
+	cached := s.cachedRequestCaptureSettings(ctx)
+	cfg := cached.settings
+	redactKeys := make(map[string]struct{}, len(cfg.RedactKeys))
+	for _, k := range cfg.RedactKeys {
+		redactKeys[strings.ToLower(k)] = struct{}{}
+	}
+
+	capture := &OpsRequestCapture{
+		CreatedAt:       input.CreatedAt,
+		ExpiresAt:       input.CreatedAt.Add(time.Duration(cfg.RetentionHours) * time.Hour),
+		RequestID:       truncateString(strings.TrimSpace(input.RequestID), 128),
+		ClientRequestID: truncateString(strings.TrimSpace(input.ClientRequestID), 64),
+		UserID:          input.UserID,
+		APIKeyID:        input.APIKeyID,
+		AccountID:       input.AccountID,
+		GroupID:         input.GroupID,
+		Platform:        input.Platform,
+		Model:           truncateString(input.Model, 100),
+		RequestPath:     truncateString(input.RequestPath, 256),
+		Stream:          input.Stream,
+		StatusCode:      input.StatusCode,
+		CaptureReason:   input.CaptureReason,
+	}
+
+	if len(input.RawRequestBody) > 0 {
+		body, truncated, n := sanitizeOpsCaptureJSON(input.RawRequestBody, redactKeys, cached.patterns, cfg.MaxRequestBytes)
+		capture.RequestBody = body
+		capture.RequestTruncated = truncated
+		capture.RequestBodyBytes = n
+	}
+
+	if len(input.RawResponseBody) > 0 {
+		body, format := reconstructOpsCapturedResponse(input.RawResponseBody, input.Stream)
+		capture.ResponseFormat = format
+		capture.ResponseBodyBytes = input.ResponseBytes
+		if format == OpsRequestCaptureFormatJSON || format == OpsRequestCaptureFormatSSEReconstructed {
+			sanitized, truncated, _ := sanitizeOpsCaptureJSON([]byte(body), redactKeys, cached.patterns, cfg.MaxResponseBytes)
+			capture.ResponseBody = sanitized
+			capture.ResponseTruncated = truncated || input.ResponseTruncated
+		} else {
+			text := redactOpsCaptureString(body, cached.patterns)
+			capture.ResponseTruncated = input.ResponseTruncated || len(text) > cfg.MaxResponseBytes
+			capture.ResponseBody = truncateString(text, cfg.MaxResponseBytes)
+		}
+	}
+
+	if _, err := s.opsRepo.InsertRequestCapture(ctx, capture); err != nil {
+		log.Printf("[Ops] RecordRequestCapture failed: %v", err)
+		return err
+	}
+
+	if s.captureInserts.Add(1)%opsRequestCapturePruneEvery == 0 {
+		if _, err := s.opsRepo.PruneRequestCaptures(ctx, time.Now(), cfg.MaxCaptures); err != nil {
+			log.Printf("[Ops] PruneRequestCaptures failed: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *OpsService) ListRequestCaptures(ctx context.Context, filter *OpsRequestCaptureFilter) (*OpsRequestCaptureList, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return &OpsRequestCaptureList{Items: []*OpsRequestCapture{}, Total: 0, Page: 1, PageSize: 20}, nil
+	}
+	return s.opsRepo.ListRequestCaptures(ctx, filter)
+}
+
+func (s *OpsService) GetRequestCaptureByID(ctx context.Context, id int64) (*OpsRequestCapture, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	capture, err := s.opsRepo.GetRequestCaptureByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_REQUEST_CAPTURE_NOT_FOUND", "request capture not found")
+		}
+		return nil, infraerrors.InternalServer("OPS_REQUEST_CAPTURE_LOAD_FAILED", "Failed to load request capture").WithCause(err)
+	}
+	return capture, nil
+}
+
+func (s *OpsService) DeleteRequestCapture(ctx context.Context, id int64) error {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return err
+	}
+	if s.opsRepo == nil {
+		return infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if err := s.opsRepo.DeleteRequestCapture(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return infraerrors.NotFound("OPS_REQUEST_CAPTURE_NOT_FOUND", "request capture not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// sanitizeOpsCaptureJSON applies capture redaction rules, then the standard ops sanitization
+// (credential redaction + conversation trimming). Non-JSON payloads are stored as redacted text.
+func sanitizeOpsCaptureJSON(raw []byte, redactKeys map[string]struct{}, patterns []*regexp.Regexp, maxBytes int) (string, bool, int) {
+	bytesLen := len(raw)
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		text := redactOpsCaptureString(string(raw), patterns)
+		return truncateString(text, maxBytes), len(text) > maxBytes, bytesLen
+	}
+	decoded = redactOpsCaptureValue(decoded, redactKeys, patterns)
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return "", false, bytesLen
+	}
+	out, truncated, _ := sanitizeAndTrimRequestBody(encoded, maxBytes)
+	return out, truncated, bytesLen
+}
+
+func redactOpsCaptureValue(v any, keys map[string]struct{}, patterns []*regexp.Regexp) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, vv := range t {
+			if _, ok := keys[strings.ToLower(strings.TrimSpace(k))]; ok {
+				t[k] = opsRequestCaptureRedacted
+				continue
+			}
+			t[k] = redactOpsCaptureValue(vv, keys, patterns)
+		}
+		return t
+	case []any:
+		for i, vv := range t {
+			t[i] = redactOpsCaptureValue(vv, keys, patterns)
+		}
+		return t
+	case string:
+		return redactOpsCaptureString(t, patterns)
+	default:
+		return v
+	}
+}
+
+func redactOpsCaptureString(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllString(s, opsRequestCaptureRedacted)
+	}
+	return s
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// reconstructOpsCapturedResponse turns a captured response into a readable body.
+//
+// Streaming (SSE) responses are folded back into a single response object for the
+// Anthropic Messages, OpenAI Chat Completions, OpenAI Responses and Gemini formats,
+// so the viewer shows the final answer / tool calls instead of hundreds of deltas.
+// Unknown streams are kept as raw SSE text.
+func reconstructOpsCapturedResponse(raw []byte, stream bool) (string, string) {
+	trimmed := bytes.TrimSpace(raw)
+	if !stream || !looksLikeSSE(trimmed) {
+		if json.Valid(trimmed) {
+			return string(trimmed), OpsRequestCaptureFormatJSON
+		}
+		return string(trimmed), OpsRequestCaptureFormatText
+	}
+
+	events := parseOpsSSEDataEvents(trimmed)
+	if len(events) == 0 {
+		return string(trimmed), OpsRequestCaptureFormatSSERaw
+	}
+
+	var out map[string]any
+	switch first := events[0]; {
+	case first["type"] == "message_start":
+		out = reconstructAnthropicStream(events)
+	case strings.HasPrefix(opsCaptureString(first["type"]), "response."):
+		out = reconstructOpenAIResponsesStream(events)
+	case first["choices"] != nil:
+		out = reconstructOpenAIChatStream(events)
+	case first["candidates"] != nil || first["response"] != nil:
+		out = reconstructGeminiStream(events)
+	}
+	if out == nil {
+		return string(trimmed), OpsRequestCaptureFormatSSERaw
+	}
+	encoded, err := json.Marshal(out)
+	if err != nil {
+		return string(trimmed), OpsRequestCaptureFormatSSERaw
+	}
+	return string(encoded), OpsRequestCaptureFormatSSEReconstructed
+}
+
+func looksLikeSSE(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("data:")) || bytes.HasPrefix(b, []byte("event:")) || bytes.Contains(b, []byte("\ndata:"))
+}
+
+// parseOpsSSEDataEvents returns the JSON objects from "data:" lines. Partial lines (capture cap) and
+// non-JSON payloads such as "[DONE]" are skipped.
+func parseOpsSSEDataEvents(b []byte) []map[string]any {
+	var events []map[string]any
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		payload := bytes.TrimSpace(line[len("data:"):])
+		if len(payload) == 0 || payload[0] != '{' {
+			continue
+		}
+		var ev map[string]any
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func reconstructAnthropicStream(events []map[string]any) map[string]any {
+	msg := map[string]any{}
+	blocks := map[int]map[string]any{}
+	partialJSON := map[int]*strings.Builder{}
+
+	for _, ev := range events {
+		switch ev["type"] {
+		case "message_start":
+			if m, ok := ev["message"].(map[string]any); ok {
+				msg = m
+			}
+		case "content_block_start":
+			idx := opsCaptureInt(ev["index"])
+			if cb, ok := ev["content_block"].(map[string]any); ok {
+				blocks[idx] = cb
+			}
+		case "content_block_delta":
+			idx := opsCaptureInt(ev["index"])
+			block := blocks[idx]
+			if block == nil {
+				block = map[string]any{}
+				blocks[idx] = block
+			}
+			delta, _ := ev["delta"].(map[string]any)
+			switch delta["type"] {
+			case "text_delta":
+				block["text"] = opsCaptureString(block["text"]) + opsCaptureString(delta["text"])
+			case "thinking_delta":
+				block["thinking"] = opsCaptureString(block["thinking"]) + opsCaptureString(delta["thinking"])
+			case "signature_delta":
+				block["signature"] = delta["signature"]
+			case "input_json_delta":
+				sb := partialJSON[idx]
+				if sb == nil {
+					sb = &strings.Builder{}
+					partialJSON[idx] = sb
+				}
+				sb.WriteString(opsCaptureString(delta["partial_json"]))
+			}
+		case "message_delta":
+			if delta, ok := ev["delta"].(map[string]any); ok {
+				for k, v := range delta {
+					msg[k] = v
+				}
+			}
+			if usage, ok := ev["usage"].(map[string]any); ok {
+				merged, _ := msg["usage"].(map[string]any)
+				if merged == nil {
+					merged = map[string]any{}
+				}
+				for k, v := range usage {
+					merged[k] = v
+				}
+				msg["usage"] = merged
+			}
+		}
+	}
+
+	for idx, sb := range partialJSON {
+		block := blocks[idx]
+		var input any
+		if err := json.Unmarshal([]byte(sb.String()), &input); err == nil {
+			block["input"] = input
+		} else {
+			block["input"] = sb.String()
+		}
+	}
+	msg["content"] = orderedBlocks(blocks)
+	return msg
+}
+
+func reconstructOpenAIChatStream(events []map[string]any) map[string]any {
+	type toolCall struct {
+		id, typ, name string
+		args          strings.Builder
+	}
+	type choice struct {
+		role         string
+		content      strings.Builder
+		toolCalls    map[int]*toolCall
+		finishReason any
+	}
+
+	out := map[string]any{"object": "chat.completion"}
+	choices := map[int]*choice{}
+	for _, ev := range events {
+		for _, k := range []string{"id", "model", "created", "system_fingerprint"} {
+			if v, ok := ev[k]; ok && v != nil {
+				out[k] = v
+			}
+		}
+		if usage, ok := ev["usage"].(map[string]any); ok {
+			out["usage"] = usage
+		}
+		list, _ := ev["choices"].([]any)
+		for _, item := range list {
+			c, _ := item.(map[string]any)
+			if c == nil {
+				continue
+			}
+			idx := opsCaptureInt(c["index"])
+			ch := choices[idx]
+			if ch == nil {
+				ch = &choice{toolCalls: map[int]*toolCall{}}
+				choices[idx] = ch
+			}
+			if fr := c["finish_reason"]; fr != nil {
+				ch.finishReason = fr
+			}
+			delta, _ := c["delta"].(map[string]any)
+			if role := opsCaptureString(delta["role"]); role != "" {
+				ch.role = role
+			}
+			ch.content.WriteString(opsCaptureString(delta["content"]))
+			calls, _ := delta["tool_calls"].([]any)
+			for _, rawCall := range calls {
+				call, _ := rawCall.(map[string]any)
+				if call == nil {
+					continue
+				}
+				ci := opsCaptureInt(call["index"])
+				tc := ch.toolCalls[ci]
+				if tc == nil {
+					tc = &toolCall{}
+					ch.toolCalls[ci] = tc
+				}
+				if id := opsCaptureString(call["id"]); id != "" {
+					tc.id = id
+				}
+				if typ := opsCaptureString(call["type"]); typ != "" {
+					tc.typ = typ
+				}
+				fn, _ := call["function"].(map[string]any)
+				if name := opsCaptureString(fn["name"]); name != "" {
+					tc.name = name
+				}
+				tc.args.WriteString(opsCaptureString(fn["arguments"]))
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(choices))
+	for idx := range choices {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	outChoices := make([]any, 0, len(indexes))
+	for _, idx := range indexes {
+		ch := choices[idx]
+		message := map[string]any{"role": ch.role, "content": ch.content.String()}
+		if ch.role == "" {
+			message["role"] = "assistant"
+		}
+		if len(ch.toolCalls) > 0 {
+			callIdx := make([]int, 0, len(ch.toolCalls))
+			for ci := range ch.toolCalls {
+				callIdx = append(callIdx, ci)
+			}
+			sort.Ints(callIdx)
+			calls := make([]any, 0, len(callIdx))
+			for _, ci := range callIdx {
+				tc := ch.toolCalls[ci]
+				calls = append(calls, map[string]any{
+					"id":       tc.id,
+					"type":     tc.typ,
+					"function": map[string]any{"name": tc.name, "arguments": tc.args.String()},
+				})
+			}
+			message["tool_calls"] = calls
+		}
+		outChoices = append(outChoices, map[string]any{"index": idx, "message": message, "finish_reason": ch.finishReason})
+	}
+	out["choices"] = outChoices
+	return out
+}
+
+// reconstructOpenAIResponsesStream uses the terminal response event, which already carries
+// the full response object; output_text deltas are used only when the stream was cut short.
+func reconstructOpenAIResponsesStream(events []map[string]any) map[string]any {
+	var text strings.Builder
+	for i := len(events) - 1; i >= 0; i-- {
+		switch events[i]["type"] {
+		case "response.completed", "response.done", "response.incomplete", "response.failed":
+			if resp, ok := events[i]["response"].(map[string]any); ok {
+				return resp
+			}
+		}
+	}
+	var base map[string]any
+	for _, ev := range events {
+		if ev["type"] == "response.created" {
+			base, _ = ev["response"].(map[string]any)
+		}
+		if ev["type"] == "response.output_text.delta" {
+			text.WriteString(opsCaptureString(ev["delta"]))
+		}
+	}
+	if base == nil {
+		base = map[string]any{}
+	}
+	base["output_text"] = text.String()
+	base["stream_incomplete"] = true
+	return base
+}
+
+func reconstructGeminiStream(events []map[string]any) map[string]any {
+	var parts []any
+	var text strings.Builder
+	var finishReason any
+	out := map[string]any{}
+
+	flushText := func() {
+		if text.Len() > 0 {
+			parts = append(parts, map[string]any{"text": text.String()})
+			text.Reset()
+		}
+	}
+
+	for _, ev := range events {
+		// Gemini CLI / Code Assist wraps the payload in {"response": {...}}.
+		if inner, ok := ev["response"].(map[string]any); ok {
+			ev = inner
+		}
+		for _, k := range []string{"usageMetadata", "modelVersion", "responseId"} {
+			if v, ok := ev[k]; ok && v != nil {
+				out[k] = v
+			}
+		}
+		candidates, _ := ev["candidates"].([]any)
+		if len(candidates) == 0 {
+			continue
+		}
+		cand, _ := candidates[0].(map[string]any)
+		if fr := cand["finishReason"]; fr != nil {
+			finishReason = fr
+		}
+		content, _ := cand["content"].(map[string]any)
+		rawParts, _ := content["parts"].([]any)
+		for _, rp := range rawParts {
+			part, _ := rp.(map[string]any)
+			if part == nil {
+				continue
+			}
+			if t, ok := part["text"].(string); ok && part["thought"] != true && len(part) == 1 {
+				text.WriteString(t)
+				continue
+			}
+			flushText()
+			parts = append(parts, part)
+		}
+	}
+	flushText()
+
+	out["candidates"] = []any{map[string]any{
+		"content":      map[string]any{"role": "model", "parts": parts},
+		"finishReason": finishReason,
+	}}
+	return out
+}
+
+func orderedBlocks(blocks map[int]map[string]any) []any {
+	indexes := make([]int, 0, len(blocks))
+	for idx := range blocks {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	out := make([]any, 0, len(indexes))
+	for _, idx := range indexes {
+		out = append(out, blocks[idx])
+	}
+	return out
+}
+
+func opsCaptureString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func opsCaptureInt(v any) int {
+	switch t := v.(type) {
+	case float64:
+		return int(t)
+	case int:
+		return t
+	default:
+		return 0
+	}
+}
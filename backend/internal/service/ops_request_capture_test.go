@@ -0,0 +1,150 @@
+//go:build unit
+
+package service
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpsRequestCaptureSettingsDecide(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	cfg := &OpsRequestCaptureSettings{
+		Enabled: true,
+		Targets: []OpsRequestCaptureTarget{
+			{Type: OpsRequestCaptureTargetAPIKey, ID: 10, ExpiresAt: &future},
+			{Type: OpsRequestCaptureTargetUser, ID: 20},
+			{Type: OpsRequestCaptureTargetAPIKey, ID: 30, ExpiresAt: &past},
+		},
+		SampleRate: 0.1,
+	}
+
+	require.Equal(t, OpsRequestCaptureReasonAPIKey, cfg.decide(1, 10, now, 0.99))
+	require.Equal(t, OpsRequestCaptureReasonUser, cfg.decide(20, 99, now, 0.99))
+	// Expired target falls through to sampling.
+	require.Equal(t, "", cfg.decide(1, 30, now, 0.5))
+	require.Equal(t, OpsRequestCaptureReasonSample, cfg.decide(1, 30, now, 0.05))
+
+	cfg.SampleUntil = &past
+	require.Equal(t, "", cfg.decide(1, 2, now, 0.01))
+
+	cfg.Enabled = false
+	require.Equal(t, "", cfg.decide(20, 10, now, 0))
+}
+
+func TestValidateOpsRequestCaptureSettings(t *testing.T) {
+	cfg := defaultOpsRequestCaptureSettings()
+	require.NoError(t, validateOpsRequestCaptureSettings(cfg))
+
+	cfg.SampleRate = 1.5
+	require.Error(t, validateOpsRequestCaptureSettings(cfg))
+
+	cfg = defaultOpsRequestCaptureSettings()
+	cfg.Targets = []OpsRequestCaptureTarget{{Type: "group", ID: 1}}
+	require.Error(t, validateOpsRequestCaptureSettings(cfg))
+
+	cfg = defaultOpsRequestCaptureSettings()
+	cfg.RedactPatterns = []string{"("}
+	require.Error(t, validateOpsRequestCaptureSettings(cfg))
+}
+
+func TestReconstructOpsCapturedResponse_Anthropic(t *testing.T) {
+	raw := strings.Join([]string{
+		`event: message_start`,
+		`data: {"type":"message_start","message":{"id":"msg_1","model":"claude","role":"assistant","content":[],"usage":{"input_tokens":5}}}`,
+		`data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}`,
+		`data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"tu_1","name":"get_weather","input":{}}}`,
+		`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+		`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+		`data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":7}}`,
+		`data: {"type":"message_stop"}`,
+	}, "\n")
+
+	body, format := reconstructOpsCapturedResponse([]byte(raw), true)
+	require.Equal(t, OpsRequestCaptureFormatSSEReconstructed, format)
+
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &msg))
+	require.Equal(t, "tool_use", msg["stop_reason"])
+	require.Equal(t, map[string]any{"input_tokens": float64(5), "output_tokens": float64(7)}, msg["usage"])
+	content := msg["content"].([]any)
+	require.Len(t, content, 2)
+	require.Equal(t, "Hello", content[0].(map[string]any)["text"])
+	require.Equal(t, map[string]any{"city": "Paris"}, content[1].(map[string]any)["input"])
+}
+
+func TestReconstructOpsCapturedResponse_OpenAIChat(t *testing.T) {
+	raw := strings.Join([]string{
+		`data: {"id":"c1","model":"gpt","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}}]}`,
+		`data: {"id":"c1","model":"gpt","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"f","arguments":"{\"a\":"}}]}}]}`,
+		`data: {"id":"c1","model":"gpt","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"1}"}}]},"finish_reason":"tool_calls"}]}`,
+		`data: [DONE]`,
+	}, "\n")
+
+	body, format := reconstructOpsCapturedResponse([]byte(raw), true)
+	require.Equal(t, OpsRequestCaptureFormatSSEReconstructed, format)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &out))
+	choice := out["choices"].([]any)[0].(map[string]any)
+	require.Equal(t, "tool_calls", choice["finish_reason"])
+	message := choice["message"].(map[string]any)
+	require.Equal(t, "Hi", message["content"])
+	call := message["tool_calls"].([]any)[0].(map[string]any)
+	require.Equal(t, "call_1", call["id"])
+	require.Equal(t, `{"a":1}`, call["function"].(map[string]any)["arguments"])
+}
+
+func TestReconstructOpsCapturedResponse_Gemini(t *testing.T) {
+	raw := strings.Join([]string{
+		`data: {"response":{"candidates":[{"content":{"role":"model","parts":[{"text":"A"}]}}]}}`,
+		`data: {"response":{"candidates":[{"content":{"role":"model","parts":[{"text":"B"}]},"finishReason":"STOP"}],"usageMetadata":{"totalTokenCount":3}}}`,
+	}, "\n\n")
+
+	body, format := reconstructOpsCapturedResponse([]byte(raw), true)
+	require.Equal(t, OpsRequestCaptureFormatSSEReconstructed, format)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &out))
+	cand := out["candidates"].([]any)[0].(map[string]any)
+	require.Equal(t, "STOP", cand["finishReason"])
+	parts := cand["content"].(map[string]any)["parts"].([]any)
+	require.Equal(t, []any{map[string]any{"text": "AB"}}, parts)
+	require.NotNil(t, out["usageMetadata"])
+}
+
+func TestReconstructOpsCapturedResponse_NonStream(t *testing.T) {
+	body, format := reconstructOpsCapturedResponse([]byte(` {"ok":true} `), false)
+	require.Equal(t, OpsRequestCaptureFormatJSON, format)
+	require.Equal(t, `{"ok":true}`, body)
+
+	_, format = reconstructOpsCapturedResponse([]byte("data: not json\n"), true)
+	require.Equal(t, OpsRequestCaptureFormatSSERaw, format)
+}
+
+func TestSanitizeOpsCaptureJSON_Redaction(t *testing.T) {
+	patterns := []*regexp.Regexp{regexp.MustCompile(`\b\d{16}\b`)}
+	keys := map[string]struct{}{"email": {}}
+	raw := []byte(`{"email":"a@b.c","api_key":"sk-123","messages":[{"role":"user","content":"card 4111111111111111 please"}]}`)
+
+	out, truncated, n := sanitizeOpsCaptureJSON(raw, keys, patterns, 64*1024)
+	require.False(t, truncated)
+	require.Equal(t, len(raw), n)
+	require.NotContains(t, out, "a@b.c")
+	require.NotContains(t, out, "sk-123")
+	require.NotContains(t, out, "4111111111111111")
+	require.Contains(t, out, "card [REDACTED] please")
+
+	out, _, _ = sanitizeOpsCaptureJSON([]byte("plain 4111111111111111"), keys, patterns, 8)
+	require.Equal(t, "plain [R", out)
+}
@@ -419,9 +419,15 @@ func (s *OpsService) executePinnedRetry(ctx context.Context, reqType opsRetryReq
 		}
 	}
 
+	// Hold the per-model slot as well, like the gateway handlers do.
+	model, _, parsedErr := extractRetryModelAndStream(reqType, errorLog, body)
+	if parsedErr != nil {
+		return &opsRetryExecution{status: opsRetryStatusFailed, errorMessage: parsedErr.Error()}
+	}
+
 	var release func()
 	if s.concurrencyService != nil {
-		acq, err := s.concurrencyService.AcquireAccountSlot(ctx, account.ID, account.Concurrency)
+		acq, err := s.concurrencyService.AcquireAccountSlotForModel(ctx, account.ID, account.Concurrency, account.GetModelConcurrencyLimit(model))
 		if err != nil {
 			return &opsRetryExecution{status: opsRetryStatusFailed, errorMessage: fmt.Sprintf("acquire account slot failed: %v", err)}
 		}
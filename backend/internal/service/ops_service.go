@@ -7,10 +7,12 @@ import (
 	"errors"
 	"log"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"golang.org/x/sync/singleflight"
 )
 
 var ErrOpsDisabled = infraerrors.NotFound("OPS_DISABLED", "Ops monitoring is disabled")
@@ -37,6 +39,21 @@ type OpsService struct {
 	openAIGatewayService      *OpenAIGatewayService
 	geminiCompatService       *GeminiMessagesCompatService
 	antigravityGatewayService *AntigravityGatewayService
+
+	// Request capture: cached rules for the gateway hot path and an insert counter for size-cap pruning.
+	captureSettingsCache atomic.Pointer[opsRequestCaptureSettingsCache]
+	captureInserts       atomic.Int64
+
+	// Traffic shadowing: cached enabled rules and the number of in-flight shadow requests.
+	shadowRulesCache atomic.Pointer[opsShadowRulesCache]
+	shadowInflight   atomic.Int64
+
+	// Public status page: cached snapshot and a singleflight group so concurrent visitors share one computation.
+	statusPageCache  atomic.Pointer[opsStatusPageCache]
+	statusPageFlight singleflight.Group
+
+	// SLO: cached long-window stats (see opsSLOStatsCache).
+	sloStatsCache opsSLOStatsCache
 }
 
 func NewOpsService(
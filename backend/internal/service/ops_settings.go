@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sort"
 	"strings"
 	"time"
 )
@@ -209,6 +210,15 @@ func defaultOpsAlertRuntimeSettings() *OpsAlertRuntimeSettings {
 			GlobalReason:       "",
 			Entries:            []OpsAlertSilenceEntry{},
 		},
+		Escalation: OpsAlertEscalationSettings{
+			Enabled:  false,
+			Policies: []OpsAlertEscalationPolicy{},
+		},
+		RepeatNotification: OpsAlertRepeatNotificationSettings{
+			Enabled:         false,
+			IntervalMinutes: opsAlertRepeatIntervalMinutesDefault,
+			MaxRepeats:      0,
+		},
 	}
 }
 
@@ -240,6 +250,94 @@ func normalizeOpsAlertSilencingSettings(s *OpsAlertSilencingSettings) {
 	}
 }
 
+const (
+	opsAlertRepeatIntervalMinutesDefault = 30
+	opsAlertEscalationMaxMinutes         = 7 * 24 * 60
+)
+
+func normalizeOpsAlertEscalationSettings(s *OpsAlertEscalationSettings) {
+	if s == nil {
+		return
+	}
+	if s.Policies == nil {
+		s.Policies = []OpsAlertEscalationPolicy{}
+	}
+	for i := range s.Policies {
+		p := &s.Policies[i]
+		p.Severity = strings.TrimSpace(p.Severity)
+		if p.Levels == nil {
+			p.Levels = []OpsAlertEscalationLevel{}
+		}
+		for j := range p.Levels {
+			recipients := make([]string, 0, len(p.Levels[j].Recipients))
+			for _, r := range p.Levels[j].Recipients {
+				if r = strings.TrimSpace(r); r != "" {
+					recipients = append(recipients, r)
+				}
+			}
+			p.Levels[j].Recipients = recipients
+		}
+		sort.SliceStable(p.Levels, func(a, b int) bool {
+			return p.Levels[a].AfterMinutes < p.Levels[b].AfterMinutes
+		})
+	}
+}
+
+func normalizeOpsAlertRepeatNotificationSettings(s *OpsAlertRepeatNotificationSettings) {
+	if s == nil {
+		return
+	}
+	if s.IntervalMinutes <= 0 {
+		s.IntervalMinutes = opsAlertRepeatIntervalMinutesDefault
+	}
+	if s.MaxRepeats < 0 {
+		s.MaxRepeats = 0
+	}
+}
+
+func validateOpsAlertEscalationSettings(s OpsAlertEscalationSettings) error {
+	seen := map[string]struct{}{}
+	for _, p := range s.Policies {
+		severity := strings.TrimSpace(p.Severity)
+		if severity == "" {
+			return errors.New("escalation.policies.severity is required")
+		}
+		if _, ok := seen[severity]; ok {
+			return errors.New("escalation.policies.severity must be unique")
+		}
+		seen[severity] = struct{}{}
+		if len(p.Levels) == 0 {
+			return errors.New("escalation.policies.levels is required")
+		}
+		for _, level := range p.Levels {
+			if level.AfterMinutes < 1 || level.AfterMinutes > opsAlertEscalationMaxMinutes {
+				return errors.New("escalation.policies.levels.after_minutes must be between 1 and 10080")
+			}
+			hasRecipient := false
+			for _, r := range level.Recipients {
+				if strings.TrimSpace(r) != "" {
+					hasRecipient = true
+					break
+				}
+			}
+			if !hasRecipient {
+				return errors.New("escalation.policies.levels.recipients is required")
+			}
+		}
+	}
+	return nil
+}
+
+func validateOpsAlertRepeatNotificationSettings(s OpsAlertRepeatNotificationSettings) error {
+	if s.IntervalMinutes < 1 || s.IntervalMinutes > 24*60 {
+		return errors.New("repeat_notification.interval_minutes must be between 1 and 1440")
+	}
+	if s.MaxRepeats < 0 {
+		return errors.New("repeat_notification.max_repeats must be >= 0")
+	}
+	return nil
+}
+
 func validateOpsDistributedLockSettings(s OpsDistributedLockSettings) error {
 	if strings.TrimSpace(s.Key) == "" {
 		return errors.New("distributed_lock.key is required")
@@ -305,6 +403,8 @@ func (s *OpsService) GetOpsAlertRuntimeSettings(ctx context.Context) (*OpsAlertR
 	}
 	normalizeOpsDistributedLockSettings(&cfg.DistributedLock, opsAlertEvaluatorLeaderLockKeyDefault, defaultCfg.DistributedLock.TTLSeconds)
 	normalizeOpsAlertSilencingSettings(&cfg.Silencing)
+	normalizeOpsAlertEscalationSettings(&cfg.Escalation)
+	normalizeOpsAlertRepeatNotificationSettings(&cfg.RepeatNotification)
 
 	return cfg, nil
 }
@@ -333,10 +433,22 @@ func (s *OpsService) UpdateOpsAlertRuntimeSettings(ctx context.Context, cfg *Ops
 			return nil, err
 		}
 	}
+	if cfg.Escalation.Enabled {
+		if err := validateOpsAlertEscalationSettings(cfg.Escalation); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.RepeatNotification.Enabled {
+		if err := validateOpsAlertRepeatNotificationSettings(cfg.RepeatNotification); err != nil {
+			return nil, err
+		}
+	}
 
 	defaultCfg := defaultOpsAlertRuntimeSettings()
 	normalizeOpsDistributedLockSettings(&cfg.DistributedLock, opsAlertEvaluatorLeaderLockKeyDefault, defaultCfg.DistributedLock.TTLSeconds)
 	normalizeOpsAlertSilencingSettings(&cfg.Silencing)
+	normalizeOpsAlertEscalationSettings(&cfg.Escalation)
+	normalizeOpsAlertRepeatNotificationSettings(&cfg.RepeatNotification)
 
 	raw, err := json.Marshal(cfg)
 	if err != nil {
@@ -74,6 +74,35 @@ type OpsAlertRuntimeSettings struct {
 	DistributedLock OpsDistributedLockSettings `json:"distributed_lock"`
 	Silencing       OpsAlertSilencingSettings  `json:"silencing"`
 	Thresholds      OpsMetricThresholds        `json:"thresholds"` // 指标阈值配置
+
+	Escalation         OpsAlertEscalationSettings         `json:"escalation"`          // 未确认事件的升级策略
+	RepeatNotification OpsAlertRepeatNotificationSettings `json:"repeat_notification"` // 持续触发时的重复通知
+}
+
+// OpsAlertEscalationLevel notifies Recipients when a firing event stays unacknowledged
+// for AfterMinutes since it fired.
+type OpsAlertEscalationLevel struct {
+	AfterMinutes int      `json:"after_minutes"`
+	Recipients   []string `json:"recipients"`
+}
+
+// OpsAlertEscalationPolicy applies to events of one severity (e.g. P0). Levels are sorted by AfterMinutes.
+type OpsAlertEscalationPolicy struct {
+	Severity string                    `json:"severity"`
+	Levels   []OpsAlertEscalationLevel `json:"levels"`
+}
+
+type OpsAlertEscalationSettings struct {
+	Enabled  bool                       `json:"enabled"`
+	Policies []OpsAlertEscalationPolicy `json:"policies,omitempty"`
+}
+
+// OpsAlertRepeatNotificationSettings re-sends the alert email while an unacknowledged event keeps firing.
+// MaxRepeats = 0 means unlimited.
+type OpsAlertRepeatNotificationSettings struct {
+	Enabled         bool `json:"enabled"`
+	IntervalMinutes int  `json:"interval_minutes"`
+	MaxRepeats      int  `json:"max_repeats"`
 }
 
 // OpsAdvancedSettings stores advanced ops configuration (data retention, aggregation).
@@ -0,0 +1,568 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// 影子流量（traffic shadowing）
+//
+// 规则绑定分组：分组内成功的请求按 sample_rate 采样，异步复制到规则指定的候选账号。
+// 影子请求写入独立的 gin.Context（与 ops 重试相同的执行方式），响应不返回客户端、不记录 usage、不计费，
+// 只记录状态码 / 延迟 / token 数，与主请求对比后写入 ops_shadow_results。
+// 候选账号无需在分组内，也可以设为不可调度；但仍需处于 active 状态，并占用该账号的并发槽位（槽位已满时跳过）。
+
+const (
+	opsShadowTimeout = 5 * time.Minute
+	// opsShadowMaxInflight bounds concurrent shadow requests per instance; extra samples are dropped.
+	opsShadowMaxInflight = 32
+	// opsShadowRulesCacheTTL bounds how long the gateway hot path uses cached rules.
+	opsShadowRulesCacheTTL     = 10 * time.Second
+	opsShadowInsertTimeout     = 5 * time.Second
+	opsShadowErrorPreviewMax   = 1024
+	opsShadowRecentResultLimit = 20
+)
+
+type OpsShadowRule struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+
+	GroupID         int64 `json:"group_id"`
+	ShadowAccountID int64 `json:"shadow_account_id"`
+	// SampleRate is the probability (0-1] that a successful request in the group is shadowed.
+	SampleRate float64    `json:"sample_rate"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *OpsShadowRule) activeAt(now time.Time) bool {
+	return r != nil && r.Enabled && r.SampleRate > 0 && (r.ExpiresAt == nil || now.Before(*r.ExpiresAt))
+}
+
+// OpsShadowRequest is a sampled successful request handed over by the gateway middleware.
+type OpsShadowRequest struct {
+	Rule *OpsShadowRule
+
+	RequestID   string
+	GroupID     *int64
+	Platform    string
+	Model       string
+	RequestPath string
+	Stream      bool
+	Body        []byte
+	Header      http.Header
+
+	PrimaryAccountID    *int64
+	PrimaryStatusCode   int
+	PrimaryDurationMs   *int64
+	PrimaryFirstTokenMs *int64
+	PrimaryInputTokens  int
+	PrimaryOutputTokens int
+}
+
+// OpsShadowResult is one primary-vs-shadow comparison.
+type OpsShadowResult struct {
+	ID        int64     `json:"id"`
+	RuleID    int64     `json:"rule_id"`
+	GroupID   *int64    `json:"group_id"`
+	RequestID string    `json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Platform    string `json:"platform"`
+	Model       string `json:"model"`
+	RequestPath string `json:"request_path"`
+	Stream      bool   `json:"stream"`
+
+	PrimaryAccountID    *int64 `json:"primary_account_id"`
+	PrimaryStatusCode   int    `json:"primary_status_code"`
+	PrimaryDurationMs   *int64 `json:"primary_duration_ms"`
+	PrimaryFirstTokenMs *int64 `json:"primary_first_token_ms"`
+	PrimaryInputTokens  int    `json:"primary_input_tokens"`
+	PrimaryOutputTokens int    `json:"primary_output_tokens"`
+
+	ShadowAccountID    int64  `json:"shadow_account_id"`
+	ShadowStatusCode   int    `json:"shadow_status_code"`
+	ShadowDurationMs   *int64 `json:"shadow_duration_ms"`
+	ShadowFirstTokenMs *int64 `json:"shadow_first_token_ms"`
+	ShadowInputTokens  int    `json:"shadow_input_tokens"`
+	ShadowOutputTokens int    `json:"shadow_output_tokens"`
+	ShadowError        string `json:"shadow_error,omitempty"`
+}
+
+// OpsShadowLegStats aggregates one side (primary or shadow) of the comparison.
+type OpsShadowLegStats struct {
+	SuccessCount int64    `json:"success_count"`
+	SuccessRate  *float64 `json:"success_rate"`
+
+	DurationAvgMs   *float64 `json:"duration_avg_ms"`
+	DurationP50Ms   *float64 `json:"duration_p50_ms"`
+	DurationP95Ms   *float64 `json:"duration_p95_ms"`
+	FirstTokenAvgMs *float64 `json:"first_token_avg_ms"`
+
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+type OpsShadowStatusCount struct {
+	StatusCode int   `json:"status_code"`
+	Count      int64 `json:"count"`
+}
+
+// OpsShadowReportStats is the raw aggregation returned by the repository.
+type OpsShadowReportStats struct {
+	SampleCount        int64
+	Primary            OpsShadowLegStats
+	Shadow             OpsShadowLegStats
+	ShadowStatusCounts []OpsShadowStatusCount
+}
+
+type OpsShadowReport struct {
+	Rule      *OpsShadowRule `json:"rule"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+
+	SampleCount int64             `json:"sample_count"`
+	Primary     OpsShadowLegStats `json:"primary"`
+	Shadow      OpsShadowLegStats `json:"shadow"`
+
+	// Deltas are shadow relative to primary, in percent (nil when the primary value is 0 / missing).
+	DurationP50DeltaPercent  *float64 `json:"duration_p50_delta_percent"`
+	OutputTokensDeltaPercent *float64 `json:"output_tokens_delta_percent"`
+
+	ShadowStatusCounts []OpsShadowStatusCount `json:"shadow_status_counts"`
+	Recent             []*OpsShadowResult     `json:"recent"`
+}
+
+type opsShadowRulesCache struct {
+	byGroup  map[int64][]*OpsShadowRule
+	loadedAt time.Time
+}
+
+func validateOpsShadowRule(rule *OpsShadowRule) error {
+	if rule == nil {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE", "invalid shadow rule")
+	}
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Description = strings.TrimSpace(rule.Description)
+	if rule.Name == "" {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE_NAME", "name is required")
+	}
+	if rule.GroupID <= 0 {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE_GROUP", "group_id is required")
+	}
+	if rule.ShadowAccountID <= 0 {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE_ACCOUNT", "shadow_account_id is required")
+	}
+	if math.IsNaN(rule.SampleRate) || rule.SampleRate <= 0 || rule.SampleRate > 1 {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE_SAMPLE_RATE", "sample_rate must be in (0, 1]")
+	}
+	return nil
+}
+
+// checkShadowAccount ensures the candidate account exists (it may be unschedulable or outside the group).
+func (s *OpsService) checkShadowAccount(ctx context.Context, accountID int64) error {
+	if s.accountRepo == nil {
+		return nil
+	}
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil || account == nil {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE_ACCOUNT", "shadow account not found")
+	}
+	return nil
+}
+
+func (s *OpsService) ListShadowRules(ctx context.Context) ([]*OpsShadowRule, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsShadowRule{}, nil
+	}
+	return s.opsRepo.ListShadowRules(ctx)
+}
+
+func (s *OpsService) CreateShadowRule(ctx context.Context, rule *OpsShadowRule) (*OpsShadowRule, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if err := validateOpsShadowRule(rule); err != nil {
+		return nil, err
+	}
+	if err := s.checkShadowAccount(ctx, rule.ShadowAccountID); err != nil {
+		return nil, err
+	}
+	created, err := s.opsRepo.CreateShadowRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	s.shadowRulesCache.Store(nil)
+	return created, nil
+}
+
+func (s *OpsService) UpdateShadowRule(ctx context.Context, rule *OpsShadowRule) (*OpsShadowRule, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if rule == nil || rule.ID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_SHADOW_RULE_ID", "invalid shadow rule id")
+	}
+	if err := validateOpsShadowRule(rule); err != nil {
+		return nil, err
+	}
+	if err := s.checkShadowAccount(ctx, rule.ShadowAccountID); err != nil {
+		return nil, err
+	}
+	updated, err := s.opsRepo.UpdateShadowRule(ctx, rule)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_SHADOW_RULE_NOT_FOUND", "shadow rule not found")
+		}
+		return nil, err
+	}
+	s.shadowRulesCache.Store(nil)
+	return updated, nil
+}
+
+func (s *OpsService) DeleteShadowRule(ctx context.Context, id int64) error {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return err
+	}
+	if s.opsRepo == nil {
+		return infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return infraerrors.BadRequest("INVALID_SHADOW_RULE_ID", "invalid shadow rule id")
+	}
+	if err := s.opsRepo.DeleteShadowRule(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return infraerrors.NotFound("OPS_SHADOW_RULE_NOT_FOUND", "shadow rule not found")
+		}
+		return err
+	}
+	s.shadowRulesCache.Store(nil)
+	return nil
+}
+
+// GetShadowReport compares shadow and primary responses of a rule within [start, end).
+func (s *OpsService) GetShadowReport(ctx context.Context, ruleID int64, start, end time.Time) (*OpsShadowReport, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if ruleID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_SHADOW_RULE_ID", "invalid shadow rule id")
+	}
+	rule, err := s.opsRepo.GetShadowRuleByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_SHADOW_RULE_NOT_FOUND", "shadow rule not found")
+		}
+		return nil, err
+	}
+
+	stats, err := s.opsRepo.GetShadowReportStats(ctx, ruleID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	recent, err := s.opsRepo.ListShadowResults(ctx, ruleID, start, end, opsShadowRecentResultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OpsShadowReport{
+		Rule:               rule,
+		StartTime:          start,
+		EndTime:            end,
+		SampleCount:        stats.SampleCount,
+		Primary:            stats.Primary,
+		Shadow:             stats.Shadow,
+		ShadowStatusCounts: stats.ShadowStatusCounts,
+		Recent:             recent,
+	}
+	if report.ShadowStatusCounts == nil {
+		report.ShadowStatusCounts = []OpsShadowStatusCount{}
+	}
+	if report.Recent == nil {
+		report.Recent = []*OpsShadowResult{}
+	}
+	if stats.SampleCount > 0 {
+		report.Primary.SuccessRate = opsShadowRate(stats.Primary.SuccessCount, stats.SampleCount)
+		report.Shadow.SuccessRate = opsShadowRate(stats.Shadow.SuccessCount, stats.SampleCount)
+	}
+	if p, sh := stats.Primary.DurationP50Ms, stats.Shadow.DurationP50Ms; p != nil && sh != nil {
+		report.DurationP50DeltaPercent = opsShadowDeltaPercent(*p, *sh)
+	}
+	report.OutputTokensDeltaPercent = opsShadowDeltaPercent(float64(stats.Primary.OutputTokens), float64(stats.Shadow.OutputTokens))
+	return report, nil
+}
+
+func opsShadowRate(n, total int64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	v := float64(n) / float64(total) * 100
+	return &v
+}
+
+func opsShadowDeltaPercent(primary, shadow float64) *float64 {
+	if primary <= 0 {
+		return nil
+	}
+	v := (shadow - primary) / primary * 100
+	return &v
+}
+
+// cachedShadowRules returns enabled rules grouped by group id from a short-lived cache (gateway hot path).
+func (s *OpsService) cachedShadowRules(ctx context.Context) map[int64][]*OpsShadowRule {
+	now := time.Now()
+	if cached := s.shadowRulesCache.Load(); cached != nil && now.Sub(cached.loadedAt) < opsShadowRulesCacheTTL {
+		return cached.byGroup
+	}
+	byGroup := map[int64][]*OpsShadowRule{}
+	rules, err := s.opsRepo.ListShadowRules(ctx)
+	if err != nil {
+		// Fail closed: never shadow on load errors.
+		log.Printf("[OpsShadow] load rules failed: %v", err)
+	}
+	for _, rule := range rules {
+		if rule != nil && rule.Enabled {
+			byGroup[rule.GroupID] = append(byGroup[rule.GroupID], rule)
+		}
+	}
+	s.shadowRulesCache.Store(&opsShadowRulesCache{byGroup: byGroup, loadedAt: now})
+	return byGroup
+}
+
+// MatchShadowRule samples the group's shadow rules for one successful request.
+// Returns nil when the request should not be shadowed.
+func (s *OpsService) MatchShadowRule(ctx context.Context, groupID int64, primaryAccountID int64) *OpsShadowRule {
+	if s == nil || s.opsRepo == nil || groupID <= 0 {
+		return nil
+	}
+	now := time.Now()
+	for _, rule := range s.cachedShadowRules(ctx)[groupID] {
+		if !rule.activeAt(now) || rule.ShadowAccountID == primaryAccountID {
+			continue
+		}
+		if rand.Float64() < rule.SampleRate {
+			return rule
+		}
+	}
+	return nil
+}
+
+// StartShadow runs the shadow request in the background. Returns false when dropped (too many in flight).
+func (s *OpsService) StartShadow(req *OpsShadowRequest) bool {
+	if s == nil || req == nil || req.Rule == nil || len(req.Body) == 0 {
+		return false
+	}
+	if s.shadowInflight.Add(1) > opsShadowMaxInflight {
+		s.shadowInflight.Add(-1)
+		return false
+	}
+	go func() {
+		defer s.shadowInflight.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[OpsShadow] panic: %v", r)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), opsShadowTimeout)
+		defer cancel()
+		s.runShadow(ctx, req)
+	}()
+	return true
+}
+
+func (s *OpsService) runShadow(ctx context.Context, req *OpsShadowRequest) {
+	result := &OpsShadowResult{
+		RuleID:              req.Rule.ID,
+		GroupID:             req.GroupID,
+		RequestID:           truncateString(req.RequestID, 128),
+		CreatedAt:           time.Now(),
+		Platform:            req.Platform,
+		Model:               truncateString(req.Model, 100),
+		RequestPath:         truncateString(req.RequestPath, 256),
+		Stream:              req.Stream,
+		PrimaryAccountID:    req.PrimaryAccountID,
+		PrimaryStatusCode:   req.PrimaryStatusCode,
+		PrimaryDurationMs:   req.PrimaryDurationMs,
+		PrimaryFirstTokenMs: req.PrimaryFirstTokenMs,
+		PrimaryInputTokens:  req.PrimaryInputTokens,
+		PrimaryOutputTokens: req.PrimaryOutputTokens,
+		ShadowAccountID:     req.Rule.ShadowAccountID,
+	}
+
+	if err := s.executeShadow(ctx, req, result); err != nil {
+		result.ShadowError = truncateString(err.Error(), opsShadowErrorPreviewMax)
+	}
+
+	insertCtx, cancel := context.WithTimeout(context.Background(), opsShadowInsertTimeout)
+	defer cancel()
+	if err := s.opsRepo.InsertShadowResult(insertCtx, result); err != nil {
+		log.Printf("[OpsShadow] insert result failed (rule=%d): %v", req.Rule.ID, err)
+	}
+}
+
+// executeShadow forwards the request to the shadow account and fills the shadow side of result.
+// Returned errors are local failures (account unavailable, slot busy, ...) recorded as shadow_error.
+func (s *OpsService) executeShadow(ctx context.Context, req *OpsShadowRequest, result *OpsShadowResult) error {
+	if s.accountRepo == nil {
+		return errors.New("account repository not available")
+	}
+	account, err := s.accountRepo.GetByID(ctx, req.Rule.ShadowAccountID)
+	if err != nil || account == nil {
+		return errors.New("shadow account not found")
+	}
+	if !account.IsActive() {
+		return errors.New("shadow account is not active")
+	}
+
+	reqType := detectOpsRetryType(req.RequestPath)
+	if (reqType == opsRetryTypeOpenAI) != (account.Platform == PlatformOpenAI) {
+		return fmt.Errorf("shadow account platform %s cannot serve %s requests", account.Platform, reqType)
+	}
+
+	if s.concurrencyService != nil {
+		acq, err := s.concurrencyService.AcquireAccountSlotForModel(ctx, account.ID, account.Concurrency, account.GetModelConcurrencyLimit(req.Model))
+		if err != nil {
+			return fmt.Errorf("acquire account slot failed: %w", err)
+		}
+		if acq == nil || !acq.Acquired {
+			return errors.New("shadow account concurrency limit reached")
+		}
+		if acq.ReleaseFunc != nil {
+			defer acq.ReleaseFunc()
+		}
+	}
+
+	c, w := newOpsRetryContext(ctx, &OpsErrorLogDetail{OpsErrorLog: OpsErrorLog{RequestPath: req.RequestPath}})
+	for key, values := range req.Header {
+		if opsRetryRequestHeaderAllowlist[strings.ToLower(key)] || strings.EqualFold(key, "user-agent") {
+			for _, v := range values {
+				c.Request.Header.Add(key, v)
+			}
+		}
+	}
+
+	startedAt := time.Now()
+	var (
+		duration     time.Duration
+		firstTokenMs *int
+		inputTokens  int
+		outputTokens int
+	)
+	switch reqType {
+	case opsRetryTypeOpenAI:
+		if s.openAIGatewayService == nil {
+			return errors.New("openai gateway service not available")
+		}
+		var res *OpenAIForwardResult
+		res, err = s.openAIGatewayService.Forward(ctx, c, account, req.Body)
+		if res != nil {
+			duration, firstTokenMs = res.Duration, res.FirstTokenMs
+			inputTokens, outputTokens = res.Usage.InputTokens, res.Usage.OutputTokens
+		}
+	default:
+		var res *ForwardResult
+		res, err = s.forwardShadowMessages(ctx, c, reqType, req, account)
+		if res != nil {
+			duration, firstTokenMs = res.Duration, res.FirstTokenMs
+			inputTokens, outputTokens = res.Usage.InputTokens, res.Usage.OutputTokens
+		}
+	}
+	if duration <= 0 {
+		duration = time.Since(startedAt)
+	}
+
+	durationMs := duration.Milliseconds()
+	result.ShadowDurationMs = &durationMs
+	if firstTokenMs != nil {
+		v := int64(*firstTokenMs)
+		result.ShadowFirstTokenMs = &v
+	}
+	result.ShadowInputTokens = inputTokens
+	result.ShadowOutputTokens = outputTokens
+	result.ShadowStatusCode = c.Writer.Status()
+
+	var failoverErr *UpstreamFailoverError
+	if errors.As(err, &failoverErr) && failoverErr.StatusCode > 0 {
+		result.ShadowStatusCode = failoverErr.StatusCode
+	}
+	if err == nil && result.ShadowStatusCode < 400 {
+		return nil
+	}
+	if result.ShadowStatusCode < 400 {
+		// Forward failed after the response started (e.g. stream interrupted).
+		return err
+	}
+	if preview, _ := extractResponsePreview(w); strings.TrimSpace(preview) != "" {
+		return errors.New(preview)
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("upstream returned status %d", result.ShadowStatusCode)
+}
+
+func (s *OpsService) forwardShadowMessages(ctx context.Context, c *gin.Context, reqType opsRetryRequestType, req *OpsShadowRequest, account *Account) (*ForwardResult, error) {
+	if reqType == opsRetryTypeGeminiV1B {
+		if s.geminiCompatService == nil || s.antigravityGatewayService == nil {
+			return nil, errors.New("gemini services not available")
+		}
+		action := "generateContent"
+		if req.Stream {
+			action = "streamGenerateContent"
+		}
+		if account.Platform == PlatformAntigravity {
+			return s.antigravityGatewayService.ForwardGemini(ctx, c, account, req.Model, action, req.Stream, req.Body, false)
+		}
+		return s.geminiCompatService.ForwardNative(ctx, c, account, req.Model, action, req.Stream, req.Body)
+	}
+
+	switch account.Platform {
+	case PlatformAntigravity:
+		if s.antigravityGatewayService == nil {
+			return nil, errors.New("antigravity gateway service not available")
+		}
+		return s.antigravityGatewayService.Forward(ctx, c, account, req.Body, false)
+	case PlatformGemini:
+		if s.geminiCompatService == nil {
+			return nil, errors.New("gemini gateway service not available")
+		}
+		return s.geminiCompatService.Forward(ctx, c, account, req.Body)
+	default:
+		if s.gatewayService == nil {
+			return nil, errors.New("gateway service not available")
+		}
+		parsedReq, err := ParseGatewayRequest(req.Body, domain.PlatformAnthropic)
+		if err != nil {
+			return nil, errors.New("failed to parse request body")
+		}
+		return s.gatewayService.Forward(ctx, c, account, parsedReq, 0)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type opsShadowRepoStub struct {
+	OpsRepository
+	rules []*OpsShadowRule
+	stats *OpsShadowReportStats
+}
+
+func (r *opsShadowRepoStub) ListShadowRules(ctx context.Context) ([]*OpsShadowRule, error) {
+	return r.rules, nil
+}
+
+func (r *opsShadowRepoStub) GetShadowRuleByID(ctx context.Context, id int64) (*OpsShadowRule, error) {
+	return r.rules[0], nil
+}
+
+func (r *opsShadowRepoStub) GetShadowReportStats(ctx context.Context, ruleID int64, start, end time.Time) (*OpsShadowReportStats, error) {
+	return r.stats, nil
+}
+
+func (r *opsShadowRepoStub) ListShadowResults(ctx context.Context, ruleID int64, start, end time.Time, limit int) ([]*OpsShadowResult, error) {
+	return nil, nil
+}
+
+func TestValidateOpsShadowRule(t *testing.T) {
+	valid := func() *OpsShadowRule {
+		return &OpsShadowRule{Name: " canary ", GroupID: 1, ShadowAccountID: 2, SampleRate: 0.1}
+	}
+
+	rule := valid()
+	require.NoError(t, validateOpsShadowRule(rule))
+	require.Equal(t, "canary", rule.Name)
+
+	rule = valid()
+	rule.Name = " "
+	require.Error(t, validateOpsShadowRule(rule))
+
+	rule = valid()
+	rule.GroupID = 0
+	require.Error(t, validateOpsShadowRule(rule))
+
+	rule = valid()
+	rule.ShadowAccountID = 0
+	require.Error(t, validateOpsShadowRule(rule))
+
+	for _, rate := range []float64{0, -0.1, 1.01, math.NaN()} {
+		rule = valid()
+		rule.SampleRate = rate
+		require.Error(t, validateOpsShadowRule(rule))
+	}
+}
+
+func TestOpsServiceMatchShadowRule(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	repo := &opsShadowRepoStub{rules: []*OpsShadowRule{
+		{ID: 1, Enabled: false, GroupID: 10, ShadowAccountID: 100, SampleRate: 1},
+		{ID: 2, Enabled: true, GroupID: 10, ShadowAccountID: 100, SampleRate: 1, ExpiresAt: &past},
+		{ID: 3, Enabled: true, GroupID: 10, ShadowAccountID: 200, SampleRate: 1},
+	}}
+	svc := &OpsService{opsRepo: repo}
+	ctx := context.Background()
+
+	rule := svc.MatchShadowRule(ctx, 10, 1)
+	require.NotNil(t, rule)
+	require.Equal(t, int64(3), rule.ID)
+
+	// Never shadow to the account that served the primary request.
+	require.Nil(t, svc.MatchShadowRule(ctx, 10, 200))
+	require.Nil(t, svc.MatchShadowRule(ctx, 11, 1))
+	require.Nil(t, svc.MatchShadowRule(ctx, 0, 1))
+}
+
+func TestOpsServiceGetShadowReport_Deltas(t *testing.T) {
+	p50Primary, p50Shadow := 1000.0, 1500.0
+	repo := &opsShadowRepoStub{
+		rules: []*OpsShadowRule{{ID: 1, Name: "r", Enabled: true}},
+		stats: &OpsShadowReportStats{
+			SampleCount: 4,
+			Primary:     OpsShadowLegStats{SuccessCount: 4, DurationP50Ms: &p50Primary, OutputTokens: 200},
+			Shadow:      OpsShadowLegStats{SuccessCount: 3, DurationP50Ms: &p50Shadow, OutputTokens: 150},
+		},
+	}
+	svc := &OpsService{opsRepo: repo}
+
+	report, err := svc.GetShadowReport(context.Background(), 1, time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	require.InDelta(t, 100.0, *report.Primary.SuccessRate, 1e-9)
+	require.InDelta(t, 75.0, *report.Shadow.SuccessRate, 1e-9)
+	require.InDelta(t, 50.0, *report.DurationP50DeltaPercent, 1e-9)
+	require.InDelta(t, -25.0, *report.OutputTokensDeltaPercent, 1e-9)
+	require.NotNil(t, report.Recent)
+	require.NotNil(t, report.ShadowStatusCounts)
+}
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// SLO 与错误预算
+//
+// 可用性：good = 成功请求（usage_logs），bad = SLA 口径错误（ops_error_logs，不含业务限流）。
+// 延迟：在有 first_token_ms 的成功请求中，good = first_token_ms <= latency_target_ms。
+//
+// 对目标 T（百分比）与窗口内 good/total：
+//   - 错误预算（允许的坏请求数）= total * (1 - T/100)
+//   - 剩余预算百分比 = 100 * (1 - bad / 错误预算)，可以为负（预算已超支）
+//   - burn rate = (bad / total) / (1 - T/100)；1 表示恰好在窗口结束时耗尽预算
+
+const (
+	OpsSLOObjectiveAvailability = "availability"
+	OpsSLOObjectiveLatency      = "latency"
+
+	OpsSLOWindowDaysDefault = 30
+	OpsSLOWindowDaysMax     = 90
+)
+
+// SLO 告警指标（规则 filters.slo_id 必填，filters.objective 为 availability（默认）或 latency）：
+//   - slo_burn_rate：规则窗口内的 burn rate；设置 filters.short_window_minutes 时取长短两个窗口中较小的
+//     burn rate，即两个窗口同时超过阈值才触发（多窗口 burn rate 告警，例如 1h + 5m 超过 14.4）
+//   - slo_error_budget_remaining：SLO 窗口内的剩余错误预算百分比
+const (
+	OpsAlertMetricSLOBurnRate             = "slo_burn_rate"
+	OpsAlertMetricSLOErrorBudgetRemaining = "slo_error_budget_remaining"
+)
+
+// opsSLOBurnRateWindows 仪表盘展示的 burn rate 窗口（对应常见的多窗口告警：1h/6h）
+var opsSLOBurnRateWindows = []time.Duration{time.Hour, 6 * time.Hour}
+
+// SLO 周期窗口（以天计）的统计需要扫描整个窗口的 usage_logs，且延迟口径依赖 latency_target_ms，
+// 无法从小时预聚合表得到；窗口不短于 opsSLOStatsCacheMinWindow 的结果缓存 opsSLOStatsCacheTTL，
+// 几分钟的滑动对天级窗口的影响可以忽略。burn rate 等短窗口仍实时查询。
+const (
+	opsSLOStatsCacheMinWindow = 24 * time.Hour
+	opsSLOStatsCacheTTL       = 5 * time.Minute
+)
+
+type OpsSLO struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+
+	Platform string `json:"platform,omitempty"`
+	GroupID  *int64 `json:"group_id,omitempty"`
+
+	AvailabilityTarget   float64  `json:"availability_target"`
+	LatencyTargetMs      *int     `json:"latency_target_ms,omitempty"`
+	LatencyTargetPercent *float64 `json:"latency_target_percent,omitempty"`
+
+	WindowDays int `json:"window_days"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HasLatencyObjective reports whether the SLO defines a first-token latency target.
+func (s *OpsSLO) HasLatencyObjective() bool {
+	return s != nil && s.LatencyTargetMs != nil && *s.LatencyTargetMs > 0 && s.LatencyTargetPercent != nil && *s.LatencyTargetPercent > 0
+}
+
+func (s *OpsSLO) dashboardFilter(start, end time.Time) *OpsDashboardFilter {
+	return &OpsDashboardFilter{
+		StartTime: start,
+		EndTime:   end,
+		Platform:  strings.TrimSpace(s.Platform),
+		GroupID:   s.GroupID,
+		QueryMode: OpsQueryModeRaw,
+	}
+}
+
+// OpsSLOWindowStats good/total counts for one window.
+type OpsSLOWindowStats struct {
+	SuccessCount  int64
+	ErrorCountSLA int64
+
+	// LatencySampleCount counts successful requests with first_token_ms; LatencyGoodCount those within the target.
+	LatencySampleCount int64
+	LatencyGoodCount   int64
+}
+
+type OpsSLOBurnRate struct {
+	WindowMinutes int      `json:"window_minutes"`
+	BurnRate      *float64 `json:"burn_rate"`
+}
+
+type OpsSLOObjectiveStatus struct {
+	Objective string  `json:"objective"`
+	Target    float64 `json:"target"`
+
+	TotalCount int64    `json:"total_count"`
+	BadCount   int64    `json:"bad_count"`
+	Actual     *float64 `json:"actual"`
+
+	ErrorBudgetRemainingPercent *float64 `json:"error_budget_remaining_percent"`
+	// BurnRate is over the whole SLO window; BurnRates are the short windows (1h/6h).
+	BurnRate  *float64         `json:"burn_rate"`
+	BurnRates []OpsSLOBurnRate `json:"burn_rates"`
+}
+
+type OpsSLOStatus struct {
+	SLO         *OpsSLO   `json:"slo"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+
+	Availability OpsSLOObjectiveStatus  `json:"availability"`
+	Latency      *OpsSLOObjectiveStatus `json:"latency,omitempty"`
+}
+
+// opsSLOObjectiveCounts 返回指定目标的 (total, bad)
+func opsSLOObjectiveCounts(objective string, stats *OpsSLOWindowStats) (total int64, bad int64) {
+	if stats == nil {
+		return 0, 0
+	}
+	if objective == OpsSLOObjectiveLatency {
+		return stats.LatencySampleCount, stats.LatencySampleCount - stats.LatencyGoodCount
+	}
+	return stats.SuccessCount + stats.ErrorCountSLA, stats.ErrorCountSLA
+}
+
+// computeOpsSLOBurnRate burn rate = 坏请求占比 / 允许的坏请求占比
+func computeOpsSLOBurnRate(target float64, total, bad int64) (float64, bool) {
+	allowed := 1 - target/100
+	if total <= 0 || allowed <= 0 {
+		return 0, false
+	}
+	return float64(bad) / float64(total) / allowed, true
+}
+
+func computeOpsSLOObjectiveStatus(objective string, target float64, stats *OpsSLOWindowStats) OpsSLOObjectiveStatus {
+	total, bad := opsSLOObjectiveCounts(objective, stats)
+	out := OpsSLOObjectiveStatus{
+		Objective:  objective,
+		Target:     target,
+		TotalCount: total,
+		BadCount:   bad,
+		BurnRates:  []OpsSLOBurnRate{},
+	}
+	if total <= 0 {
+		return out
+	}
+	actual := float64(total-bad) / float64(total) * 100
+	out.Actual = &actual
+
+	budget := float64(total) * (1 - target/100)
+	if budget > 0 {
+		remaining := roundTo4DP(100 * (1 - float64(bad)/budget))
+		out.ErrorBudgetRemainingPercent = &remaining
+	}
+	if burn, ok := computeOpsSLOBurnRate(target, total, bad); ok {
+		burn = roundTo4DP(burn)
+		out.BurnRate = &burn
+	}
+	return out
+}
+
+func roundTo4DP(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
+
+func normalizeOpsSLOObjective(raw string) string {
+	if strings.TrimSpace(strings.ToLower(raw)) == OpsSLOObjectiveLatency {
+		return OpsSLOObjectiveLatency
+	}
+	return OpsSLOObjectiveAvailability
+}
+
+func validateOpsSLO(slo *OpsSLO) error {
+	if slo == nil {
+		return infraerrors.BadRequest("INVALID_SLO", "invalid slo")
+	}
+	slo.Name = strings.TrimSpace(slo.Name)
+	slo.Description = strings.TrimSpace(slo.Description)
+	slo.Platform = strings.TrimSpace(strings.ToLower(slo.Platform))
+	if slo.Name == "" {
+		return infraerrors.BadRequest("INVALID_SLO_NAME", "name is required")
+	}
+	if slo.GroupID != nil && *slo.GroupID <= 0 {
+		slo.GroupID = nil
+	}
+	if math.IsNaN(slo.AvailabilityTarget) || slo.AvailabilityTarget <= 0 || slo.AvailabilityTarget >= 100 {
+		return infraerrors.BadRequest("INVALID_SLO_TARGET", "availability_target must be between 0 and 100 (exclusive)")
+	}
+	if slo.LatencyTargetMs != nil || slo.LatencyTargetPercent != nil {
+		if slo.LatencyTargetMs == nil || *slo.LatencyTargetMs <= 0 {
+			return infraerrors.BadRequest("INVALID_SLO_LATENCY", "latency_target_ms must be > 0")
+		}
+		if slo.LatencyTargetPercent == nil || math.IsNaN(*slo.LatencyTargetPercent) || *slo.LatencyTargetPercent <= 0 || *slo.LatencyTargetPercent >= 100 {
+			return infraerrors.BadRequest("INVALID_SLO_LATENCY", "latency_target_percent must be between 0 and 100 (exclusive)")
+		}
+	}
+	if slo.WindowDays == 0 {
+		slo.WindowDays = OpsSLOWindowDaysDefault
+	}
+	if slo.WindowDays < 1 || slo.WindowDays > OpsSLOWindowDaysMax {
+		return infraerrors.BadRequest("INVALID_SLO_WINDOW", "window_days must be between 1 and 90")
+	}
+	return nil
+}
+
+func (s *OpsService) ListSLOs(ctx context.Context) ([]*OpsSLO, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsSLO{}, nil
+	}
+	return s.opsRepo.ListSLOs(ctx)
+}
+
+func (s *OpsService) GetSLOByID(ctx context.Context, id int64) (*OpsSLO, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_SLO_ID", "invalid slo id")
+	}
+	slo, err := s.opsRepo.GetSLOByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_SLO_NOT_FOUND", "slo not found")
+		}
+		return nil, err
+	}
+	if slo == nil {
+		return nil, infraerrors.NotFound("OPS_SLO_NOT_FOUND", "slo not found")
+	}
+	return slo, nil
+}
+
+func (s *OpsService) CreateSLO(ctx context.Context, slo *OpsSLO) (*OpsSLO, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if err := validateOpsSLO(slo); err != nil {
+		return nil, err
+	}
+	return s.opsRepo.CreateSLO(ctx, slo)
+}
+
+func (s *OpsService) UpdateSLO(ctx context.Context, slo *OpsSLO) (*OpsSLO, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if slo == nil || slo.ID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_SLO_ID", "invalid slo id")
+	}
+	if err := validateOpsSLO(slo); err != nil {
+		return nil, err
+	}
+	updated, err := s.opsRepo.UpdateSLO(ctx, slo)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_SLO_NOT_FOUND", "slo not found")
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *OpsService) DeleteSLO(ctx context.Context, id int64) error {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return err
+	}
+	if s.opsRepo == nil {
+		return infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return infraerrors.BadRequest("INVALID_SLO_ID", "invalid slo id")
+	}
+	if err := s.opsRepo.DeleteSLO(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return infraerrors.NotFound("OPS_SLO_NOT_FOUND", "slo not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// ListSLOStatuses 计算所有启用 SLO 在各自窗口内的达成情况、剩余错误预算与 burn rate
+func (s *OpsService) ListSLOStatuses(ctx context.Context, now time.Time) ([]*OpsSLOStatus, error) {
+	slos, err := s.ListSLOs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*OpsSLOStatus, 0, len(slos))
+	for _, slo := range slos {
+		if slo == nil || !slo.Enabled {
+			continue
+		}
+		status, err := s.computeSLOStatus(ctx, slo, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+func (s *OpsService) GetSLOStatus(ctx context.Context, id int64, now time.Time) (*OpsSLOStatus, error) {
+	slo, err := s.GetSLOByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeSLOStatus(ctx, slo, now)
+}
+
+type opsSLOStatsCache struct {
+	mu      sync.Mutex
+	entries map[string]opsSLOStatsCacheEntry
+}
+
+type opsSLOStatsCacheEntry struct {
+	stats     *OpsSLOWindowStats
+	expiresAt time.Time
+}
+
+// get 查询窗口统计，长窗口结果按 平台/分组/延迟目标/窗口长度 缓存
+func (c *opsSLOStatsCache) get(ctx context.Context, repo OpsRepository, filter *OpsDashboardFilter, latencyTargetMs *int) (*OpsSLOWindowStats, error) {
+	window := filter.EndTime.Sub(filter.StartTime)
+	if window < opsSLOStatsCacheMinWindow {
+		return repo.GetSLOWindowStats(ctx, filter, latencyTargetMs)
+	}
+
+	var groupID int64
+	if filter.GroupID != nil {
+		groupID = *filter.GroupID
+	}
+	var latency int
+	if latencyTargetMs != nil {
+		latency = *latencyTargetMs
+	}
+	key := fmt.Sprintf("%s|%d|%d|%d", filter.Platform, groupID, latency, int64(window/time.Second))
+
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.stats, nil
+	}
+
+	stats, err := repo.GetSLOWindowStats(ctx, filter, latencyTargetMs)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]opsSLOStatsCacheEntry)
+	}
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = opsSLOStatsCacheEntry{stats: stats, expiresAt: now.Add(opsSLOStatsCacheTTL)}
+	return stats, nil
+}
+
+func (s *OpsService) computeSLOStatus(ctx context.Context, slo *OpsSLO, now time.Time) (*OpsSLOStatus, error) {
+	end := now.UTC()
+	start := end.Add(-time.Duration(normalizeOpsSLOWindowDays(slo.WindowDays)) * 24 * time.Hour)
+
+	stats, err := s.sloStatsCache.get(ctx, s.opsRepo, slo.dashboardFilter(start, end), slo.LatencyTargetMs)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &OpsSLOStatus{
+		SLO:          slo,
+		WindowStart:  start,
+		WindowEnd:    end,
+		Availability: computeOpsSLOObjectiveStatus(OpsSLOObjectiveAvailability, slo.AvailabilityTarget, stats),
+	}
+	if slo.HasLatencyObjective() {
+		latency := computeOpsSLOObjectiveStatus(OpsSLOObjectiveLatency, *slo.LatencyTargetPercent, stats)
+		status.Latency = &latency
+	}
+
+	for _, window := range opsSLOBurnRateWindows {
+		short, err := s.opsRepo.GetSLOWindowStats(ctx, slo.dashboardFilter(end.Add(-window), end), slo.LatencyTargetMs)
+		if err != nil {
+			return nil, err
+		}
+		status.Availability.BurnRates = append(status.Availability.BurnRates, opsSLOWindowBurnRate(OpsSLOObjectiveAvailability, slo.AvailabilityTarget, short, window))
+		if status.Latency != nil {
+			status.Latency.BurnRates = append(status.Latency.BurnRates, opsSLOWindowBurnRate(OpsSLOObjectiveLatency, *slo.LatencyTargetPercent, short, window))
+		}
+	}
+	return status, nil
+}
+
+func opsSLOWindowBurnRate(objective string, target float64, stats *OpsSLOWindowStats, window time.Duration) OpsSLOBurnRate {
+	out := OpsSLOBurnRate{WindowMinutes: int(window / time.Minute)}
+	total, bad := opsSLOObjectiveCounts(objective, stats)
+	if burn, ok := computeOpsSLOBurnRate(target, total, bad); ok {
+		burn = roundTo4DP(burn)
+		out.BurnRate = &burn
+	}
+	return out
+}
+
+func normalizeOpsSLOWindowDays(days int) int {
+	if days <= 0 {
+		return OpsSLOWindowDaysDefault
+	}
+	if days > OpsSLOWindowDaysMax {
+		return OpsSLOWindowDaysMax
+	}
+	return days
+}
+
+// IsOpsAlertSLOMetric 判断告警指标是否基于 SLO
+func IsOpsAlertSLOMetric(metricType string) bool {
+	switch strings.TrimSpace(metricType) {
+	case OpsAlertMetricSLOBurnRate, OpsAlertMetricSLOErrorBudgetRemaining:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *OpsAlertEvaluatorService) computeSLORuleMetric(ctx context.Context, rule *OpsAlertRule, start, end time.Time) (float64, bool) {
+	if s == nil || s.opsRepo == nil || rule == nil {
+		return 0, false
+	}
+	sloID := parseOpsAlertFilterID(rule.Filters, "slo_id")
+	if sloID == nil {
+		return 0, false
+	}
+	slo, err := s.opsRepo.GetSLOByID(ctx, *sloID)
+	if err != nil || slo == nil || !slo.Enabled {
+		return 0, false
+	}
+
+	objective := OpsSLOObjectiveAvailability
+	if raw, ok := rule.Filters["objective"].(string); ok {
+		objective = normalizeOpsSLOObjective(raw)
+	}
+	target := slo.AvailabilityTarget
+	if objective == OpsSLOObjectiveLatency {
+		if !slo.HasLatencyObjective() {
+			return 0, false
+		}
+		target = *slo.LatencyTargetPercent
+	}
+
+	burnRate := func(from time.Time) (float64, bool) {
+		stats, err := s.sloStatsCache.get(ctx, s.opsRepo, slo.dashboardFilter(from, end), slo.LatencyTargetMs)
+		if err != nil {
+			return 0, false
+		}
+		total, bad := opsSLOObjectiveCounts(objective, stats)
+		return computeOpsSLOBurnRate(target, total, bad)
+	}
+
+	switch strings.TrimSpace(rule.MetricType) {
+	case OpsAlertMetricSLOErrorBudgetRemaining:
+		windowStart := end.Add(-time.Duration(normalizeOpsSLOWindowDays(slo.WindowDays)) * 24 * time.Hour)
+		stats, err := s.sloStatsCache.get(ctx, s.opsRepo, slo.dashboardFilter(windowStart, end), slo.LatencyTargetMs)
+		if err != nil {
+			return 0, false
+		}
+		status := computeOpsSLOObjectiveStatus(objective, target, stats)
+		if status.ErrorBudgetRemainingPercent == nil {
+			return 0, false
+		}
+		return *status.ErrorBudgetRemainingPercent, true
+	case OpsAlertMetricSLOBurnRate:
+		long, ok := burnRate(start)
+		if !ok {
+			return 0, false
+		}
+		shortMinutes := parseOpsAlertFilterID(rule.Filters, "short_window_minutes")
+		if shortMinutes == nil || time.Duration(*shortMinutes)*time.Minute >= end.Sub(start) {
+			return long, true
+		}
+		short, ok := burnRate(end.Add(-time.Duration(*shortMinutes) * time.Minute))
+		if !ok {
+			return 0, false
+		}
+		return math.Min(long, short), true
+	default:
+		return 0, false
+	}
+}
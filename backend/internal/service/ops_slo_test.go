@@ -0,0 +1,138 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sloOpsRepo struct {
+	OpsRepository
+	slo *OpsSLO
+	// statsByWindow maps window length to the stats returned for it.
+	statsByWindow map[time.Duration]*OpsSLOWindowStats
+	statsCalls    int
+}
+
+func (s *sloOpsRepo) GetSLOByID(_ context.Context, _ int64) (*OpsSLO, error) {
+	return s.slo, nil
+}
+
+func (s *sloOpsRepo) GetSLOWindowStats(_ context.Context, filter *OpsDashboardFilter, _ *int) (*OpsSLOWindowStats, error) {
+	s.statsCalls++
+	return s.statsByWindow[filter.EndTime.Sub(filter.StartTime)], nil
+}
+
+func TestComputeOpsSLOObjectiveStatus(t *testing.T) {
+	// 99.9% target over 100k requests allows 100 bad requests; 25 used.
+	stats := &OpsSLOWindowStats{SuccessCount: 99975, ErrorCountSLA: 25, LatencySampleCount: 1000, LatencyGoodCount: 900}
+
+	availability := computeOpsSLOObjectiveStatus(OpsSLOObjectiveAvailability, 99.9, stats)
+	require.Equal(t, int64(100000), availability.TotalCount)
+	require.Equal(t, int64(25), availability.BadCount)
+	require.InDelta(t, 99.975, *availability.Actual, 1e-9)
+	require.InDelta(t, 75, *availability.ErrorBudgetRemainingPercent, 1e-9)
+	require.InDelta(t, 0.25, *availability.BurnRate, 1e-9)
+
+	// 95% latency target, 10% slow: budget overspent.
+	latency := computeOpsSLOObjectiveStatus(OpsSLOObjectiveLatency, 95, stats)
+	require.InDelta(t, -100, *latency.ErrorBudgetRemainingPercent, 1e-9)
+	require.InDelta(t, 2, *latency.BurnRate, 1e-9)
+
+	empty := computeOpsSLOObjectiveStatus(OpsSLOObjectiveAvailability, 99.9, &OpsSLOWindowStats{})
+	require.Nil(t, empty.Actual)
+	require.Nil(t, empty.BurnRate)
+}
+
+func TestComputeSLORuleMetricMultiWindow(t *testing.T) {
+	repo := &sloOpsRepo{
+		slo: &OpsSLO{ID: 1, Enabled: true, AvailabilityTarget: 99, WindowDays: 30},
+		statsByWindow: map[time.Duration]*OpsSLOWindowStats{
+			time.Hour:           {SuccessCount: 800, ErrorCountSLA: 200}, // 20x
+			5 * time.Minute:     {SuccessCount: 95, ErrorCountSLA: 5},    // 5x
+			30 * 24 * time.Hour: {SuccessCount: 9990, ErrorCountSLA: 10},
+		},
+	}
+	svc := &OpsAlertEvaluatorService{opsRepo: repo}
+	end := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	start := end.Add(-time.Hour)
+
+	rule := &OpsAlertRule{MetricType: OpsAlertMetricSLOBurnRate, Filters: map[string]any{"slo_id": float64(1)}}
+	v, ok := svc.computeSLORuleMetric(context.Background(), rule, start, end)
+	require.True(t, ok)
+	require.InDelta(t, 20, v, 1e-9)
+
+	// The short window has recovered, so the multi-window burn rate is the lower of the two.
+	rule.Filters["short_window_minutes"] = float64(5)
+	v, ok = svc.computeSLORuleMetric(context.Background(), rule, start, end)
+	require.True(t, ok)
+	require.InDelta(t, 5, v, 1e-9)
+
+	budgetRule := &OpsAlertRule{MetricType: OpsAlertMetricSLOErrorBudgetRemaining, Filters: map[string]any{"slo_id": float64(1)}}
+	v, ok = svc.computeSLORuleMetric(context.Background(), budgetRule, start, end)
+	require.True(t, ok)
+	require.InDelta(t, 90, v, 1e-9)
+
+	// Latency objective is skipped when the SLO has no latency target.
+	budgetRule.Filters["objective"] = OpsSLOObjectiveLatency
+	_, ok = svc.computeSLORuleMetric(context.Background(), budgetRule, start, end)
+	require.False(t, ok)
+
+	repo.slo.Enabled = false
+	_, ok = svc.computeSLORuleMetric(context.Background(), rule, start, end)
+	require.False(t, ok)
+}
+
+func TestOpsSLOStatsCacheLongWindowOnly(t *testing.T) {
+	repo := &sloOpsRepo{
+		statsByWindow: map[time.Duration]*OpsSLOWindowStats{
+			time.Hour:           {SuccessCount: 1},
+			30 * 24 * time.Hour: {SuccessCount: 2},
+		},
+	}
+	slo := &OpsSLO{ID: 1, Platform: "anthropic"}
+	end := time.Now().UTC()
+	var cache opsSLOStatsCache
+
+	// The SLO window is served from the cache after the first query, even as the end time slides.
+	for i := 0; i < 3; i++ {
+		at := end.Add(time.Duration(i) * time.Minute)
+		stats, err := cache.get(context.Background(), repo, slo.dashboardFilter(at.Add(-30*24*time.Hour), at), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stats.SuccessCount)
+	}
+	require.Equal(t, 1, repo.statsCalls)
+
+	// A different latency target is a different cache entry.
+	target := 500
+	_, err := cache.get(context.Background(), repo, slo.dashboardFilter(end.Add(-30*24*time.Hour), end), &target)
+	require.NoError(t, err)
+	require.Equal(t, 2, repo.statsCalls)
+
+	// Burn-rate windows always hit the repository.
+	for i := 0; i < 2; i++ {
+		stats, err := cache.get(context.Background(), repo, slo.dashboardFilter(end.Add(-time.Hour), end), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stats.SuccessCount)
+	}
+	require.Equal(t, 4, repo.statsCalls)
+}
+
+func TestValidateOpsSLO(t *testing.T) {
+	slo := &OpsSLO{Name: " Claude group ", AvailabilityTarget: 99.5}
+	require.NoError(t, validateOpsSLO(slo))
+	require.Equal(t, "Claude group", slo.Name)
+	require.Equal(t, OpsSLOWindowDaysDefault, slo.WindowDays)
+
+	require.Error(t, validateOpsSLO(&OpsSLO{Name: "x", AvailabilityTarget: 100}))
+
+	ms := 2000
+	require.Error(t, validateOpsSLO(&OpsSLO{Name: "x", AvailabilityTarget: 99, LatencyTargetMs: &ms}))
+
+	pct := 95.0
+	require.NoError(t, validateOpsSLO(&OpsSLO{Name: "x", AvailabilityTarget: 99, LatencyTargetMs: &ms, LatencyTargetPercent: &pct}))
+}
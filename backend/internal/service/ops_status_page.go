@@ -0,0 +1,877 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// 公开状态页（无需登录）
+//
+// 按平台 / 模型统计最近 1 小时与 24 小时的可用性与中位延迟：
+// - 成功数来自 usage_logs，失败数来自 ops_error_logs（仅计入 provider / platform 侧错误，不含客户端错误与业务限制）
+// - 当前事件 = firing 且标记为公开的告警事件 + 管理员发布的未解决事件公告
+// 结果在进程内缓存 cache_seconds 秒，并发请求通过 singleflight 合并，公开接口无法放大数据库负载。
+
+// SettingKeyOpsStatusPageSettings stores JSON config for the public status page.
+const SettingKeyOpsStatusPageSettings = "ops_status_page_settings"
+
+// Component statuses (worst last).
+const (
+	OpsStatusOperational   = "operational"
+	OpsStatusDegraded      = "degraded"
+	OpsStatusPartialOutage = "partial_outage"
+	OpsStatusMajorOutage   = "major_outage"
+	OpsStatusNoData        = "no_data"
+)
+
+// Incident lifecycle.
+const (
+	OpsIncidentInvestigating = "investigating"
+	OpsIncidentIdentified    = "identified"
+	OpsIncidentMonitoring    = "monitoring"
+	OpsIncidentResolved      = "resolved"
+)
+
+// Incident impact.
+const (
+	OpsIncidentImpactMinor    = "minor"
+	OpsIncidentImpactMajor    = "major"
+	OpsIncidentImpactCritical = "critical"
+)
+
+// Incident sources on the public page.
+const (
+	OpsPublicIncidentSourceAlert  = "alert"
+	OpsPublicIncidentSourceManual = "manual"
+)
+
+const (
+	opsStatusPageDefaultCacheSeconds = 60
+	opsStatusPageMinCacheSeconds     = 15
+	opsStatusPageMaxCacheSeconds     = 600
+	opsStatusPageComputeTimeout      = 15 * time.Second
+	opsStatusPageMaxModels           = 20
+	opsStatusPageRecentIncidentDays  = 7
+	opsStatusPageMaxIncidents        = 20
+	opsStatusIncidentTitleMaxLen     = 200
+	opsStatusIncidentMessageMaxLen   = 5000
+)
+
+type OpsStatusPageSettings struct {
+	Enabled bool   `json:"enabled"`
+	Title   string `json:"title"`
+	// Platforms limits which platforms are listed (empty = every platform with traffic).
+	Platforms  []string `json:"platforms"`
+	ShowModels bool     `json:"show_models"`
+	// MinModelRequests hides models with fewer requests in the last 24h (reduces noise from rare models).
+	MinModelRequests int `json:"min_model_requests"`
+	CacheSeconds     int `json:"cache_seconds"`
+}
+
+func defaultOpsStatusPageSettings() *OpsStatusPageSettings {
+	return &OpsStatusPageSettings{
+		Enabled:          false,
+		Platforms:        []string{},
+		ShowModels:       true,
+		MinModelRequests: 10,
+		CacheSeconds:     opsStatusPageDefaultCacheSeconds,
+	}
+}
+
+func normalizeOpsStatusPageSettings(cfg *OpsStatusPageSettings) {
+	if cfg == nil {
+		return
+	}
+	cfg.Title = strings.TrimSpace(cfg.Title)
+	platforms := make([]string, 0, len(cfg.Platforms))
+	seen := map[string]struct{}{}
+	for _, p := range cfg.Platforms {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		platforms = append(platforms, p)
+	}
+	cfg.Platforms = platforms
+	if cfg.CacheSeconds == 0 {
+		cfg.CacheSeconds = opsStatusPageDefaultCacheSeconds
+	}
+}
+
+func validateOpsStatusPageSettings(cfg *OpsStatusPageSettings) error {
+	if cfg == nil {
+		return infraerrors.BadRequest("INVALID_STATUS_PAGE_SETTINGS", "invalid settings")
+	}
+	if len(cfg.Title) > 100 {
+		return infraerrors.BadRequest("INVALID_STATUS_PAGE_TITLE", "title is too long")
+	}
+	if cfg.CacheSeconds < opsStatusPageMinCacheSeconds || cfg.CacheSeconds > opsStatusPageMaxCacheSeconds {
+		return infraerrors.BadRequest("INVALID_STATUS_PAGE_CACHE_SECONDS", "cache_seconds must be between 15 and 600")
+	}
+	if cfg.MinModelRequests < 0 {
+		return infraerrors.BadRequest("INVALID_STATUS_PAGE_MIN_MODEL_REQUESTS", "min_model_requests must be >= 0")
+	}
+	return nil
+}
+
+// OpsStatusPageStatsRow is one aggregated (platform, model) row; Model is empty for the platform total.
+type OpsStatusPageStatsRow struct {
+	Platform     string
+	Model        string
+	SuccessCount int64
+	ErrorCount   int64
+	LatencyP50Ms *float64
+}
+
+type OpsStatusWindow struct {
+	// Availability is a percentage (nil when there was no traffic).
+	Availability *float64 `json:"availability"`
+	LatencyP50Ms *float64 `json:"latency_p50_ms"`
+}
+
+type OpsStatusComponent struct {
+	Platform string                `json:"platform"`
+	Model    string                `json:"model,omitempty"`
+	Status   string                `json:"status"`
+	LastHour OpsStatusWindow       `json:"last_hour"`
+	LastDay  OpsStatusWindow       `json:"last_day"`
+	Models   []*OpsStatusComponent `json:"models,omitempty"`
+}
+
+type OpsStatusIncidentUpdate struct {
+	ID         int64     `json:"id"`
+	IncidentID int64     `json:"incident_id"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message"`
+	CreatedBy  *int64    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OpsStatusIncident is an admin-authored incident announcement.
+type OpsStatusIncident struct {
+	ID         int64      `json:"id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	Impact     string     `json:"impact"`
+	Platforms  []string   `json:"platforms"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedBy  *int64     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	Updates []*OpsStatusIncidentUpdate `json:"updates"`
+}
+
+type OpsStatusIncidentFilter struct {
+	// ActiveOnly returns unresolved incidents; otherwise incidents started or resolved after Since.
+	ActiveOnly bool
+	Since      *time.Time
+	Limit      int
+}
+
+type OpsStatusIncidentInput struct {
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Impact    string   `json:"impact"`
+	Platforms []string `json:"platforms"`
+	// Message is the first update (create only).
+	Message string `json:"message"`
+	// StartedAt defaults to now (create only).
+	StartedAt *time.Time `json:"started_at"`
+}
+
+type OpsStatusIncidentUpdateInput struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// OpsPublicIncident is what the public page shows for both alert events and manual incidents.
+type OpsPublicIncident struct {
+	Source     string     `json:"source"`
+	ID         int64      `json:"id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	Impact     string     `json:"impact"`
+	Platforms  []string   `json:"platforms"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+
+	Updates []*OpsPublicIncidentUpdate `json:"updates,omitempty"`
+}
+
+type OpsPublicIncidentUpdate struct {
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OpsPublicStatus struct {
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Platforms       []*OpsStatusComponent `json:"platforms"`
+	Incidents       []*OpsPublicIncident  `json:"incidents"`
+	RecentIncidents []*OpsPublicIncident  `json:"recent_incidents"`
+}
+
+type opsStatusPageCache struct {
+	status    *OpsPublicStatus
+	expiresAt time.Time
+}
+
+var opsStatusSeverityRank = map[string]int{
+	OpsStatusNoData:        0,
+	OpsStatusOperational:   1,
+	OpsStatusDegraded:      2,
+	OpsStatusPartialOutage: 3,
+	OpsStatusMajorOutage:   4,
+}
+
+func worseOpsStatus(a, b string) string {
+	if opsStatusSeverityRank[b] > opsStatusSeverityRank[a] {
+		return b
+	}
+	return a
+}
+
+// opsStatusFromAvailability maps availability (percent) to a component status.
+func opsStatusFromAvailability(availability *float64) string {
+	if availability == nil {
+		return OpsStatusNoData
+	}
+	switch v := *availability; {
+	case v >= 99:
+		return OpsStatusOperational
+	case v >= 95:
+		return OpsStatusDegraded
+	case v >= 80:
+		return OpsStatusPartialOutage
+	default:
+		return OpsStatusMajorOutage
+	}
+}
+
+func opsStatusFromImpact(impact string) string {
+	switch impact {
+	case OpsIncidentImpactCritical:
+		return OpsStatusMajorOutage
+	case OpsIncidentImpactMajor:
+		return OpsStatusPartialOutage
+	default:
+		return OpsStatusDegraded
+	}
+}
+
+func opsImpactFromAlertSeverity(severity string) string {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "P0":
+		return OpsIncidentImpactCritical
+	case "P1":
+		return OpsIncidentImpactMajor
+	default:
+		return OpsIncidentImpactMinor
+	}
+}
+
+func opsStatusWindowFromRow(row *OpsStatusPageStatsRow) OpsStatusWindow {
+	if row == nil {
+		return OpsStatusWindow{}
+	}
+	out := OpsStatusWindow{LatencyP50Ms: row.LatencyP50Ms}
+	if total := row.SuccessCount + row.ErrorCount; total > 0 {
+		v := float64(row.SuccessCount) / float64(total) * 100
+		out.Availability = &v
+	}
+	return out
+}
+
+// buildOpsStatusComponents merges hourly and daily stats into platform components (with per-model children).
+func buildOpsStatusComponents(cfg *OpsStatusPageSettings, hourRows, dayRows []*OpsStatusPageStatsRow) []*OpsStatusComponent {
+	type key struct{ platform, model string }
+	hour := make(map[key]*OpsStatusPageStatsRow, len(hourRows))
+	for _, row := range hourRows {
+		hour[key{row.Platform, row.Model}] = row
+	}
+
+	allowed := map[string]struct{}{}
+	for _, p := range cfg.Platforms {
+		allowed[p] = struct{}{}
+	}
+
+	byPlatform := map[string]*OpsStatusComponent{}
+	dayRequests := map[key]int64{}
+	for _, row := range dayRows {
+		if row.Platform == "" {
+			continue
+		}
+		if _, ok := allowed[row.Platform]; len(allowed) > 0 && !ok {
+			continue
+		}
+		k := key{row.Platform, row.Model}
+		dayRequests[k] = row.SuccessCount + row.ErrorCount
+		comp := &OpsStatusComponent{
+			Platform: row.Platform,
+			Model:    row.Model,
+			LastHour: opsStatusWindowFromRow(hour[k]),
+			LastDay:  opsStatusWindowFromRow(row),
+		}
+		comp.Status = opsStatusFromAvailability(comp.LastHour.Availability)
+
+		if row.Model == "" {
+			if existing := byPlatform[row.Platform]; existing != nil {
+				comp.Models = existing.Models
+			}
+			byPlatform[row.Platform] = comp
+			continue
+		}
+		if !cfg.ShowModels || dayRequests[k] < int64(cfg.MinModelRequests) {
+			continue
+		}
+		parent := byPlatform[row.Platform]
+		if parent == nil {
+			parent = &OpsStatusComponent{Platform: row.Platform}
+			byPlatform[row.Platform] = parent
+		}
+		parent.Models = append(parent.Models, comp)
+	}
+
+	// Configured platforms without traffic are still listed (no_data).
+	for _, p := range cfg.Platforms {
+		if byPlatform[p] == nil {
+			byPlatform[p] = &OpsStatusComponent{Platform: p, Status: OpsStatusNoData}
+		}
+	}
+
+	out := make([]*OpsStatusComponent, 0, len(byPlatform))
+	for _, comp := range byPlatform {
+		if comp.Status == "" {
+			comp.Status = OpsStatusNoData
+		}
+		sort.SliceStable(comp.Models, func(i, j int) bool {
+			return dayRequests[key{comp.Platform, comp.Models[i].Model}] > dayRequests[key{comp.Platform, comp.Models[j].Model}]
+		})
+		if len(comp.Models) > opsStatusPageMaxModels {
+			comp.Models = comp.Models[:opsStatusPageMaxModels]
+		}
+		out = append(out, comp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Platform < out[j].Platform })
+	return out
+}
+
+func validateOpsIncidentStatus(status string) bool {
+	switch status {
+	case OpsIncidentInvestigating, OpsIncidentIdentified, OpsIncidentMonitoring, OpsIncidentResolved:
+		return true
+	}
+	return false
+}
+
+func validateOpsIncidentImpact(impact string) bool {
+	switch impact {
+	case OpsIncidentImpactMinor, OpsIncidentImpactMajor, OpsIncidentImpactCritical:
+		return true
+	}
+	return false
+}
+
+func normalizeOpsIncidentPlatforms(platforms []string) []string {
+	out := make([]string, 0, len(platforms))
+	for _, p := range platforms {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ==================== Settings ====================
+
+func (s *OpsService) GetStatusPageSettings(ctx context.Context) (*OpsStatusPageSettings, error) {
+	defaultCfg := defaultOpsStatusPageSettings()
+	if s == nil || s.settingRepo == nil {
+		return defaultCfg, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	raw, err := s.settingRepo.GetValue(ctx, SettingKeyOpsStatusPageSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	cfg := &OpsStatusPageSettings{}
+	if err := json.Unmarshal([]byte(raw), cfg); err != nil {
+		return defaultCfg, nil
+	}
+	normalizeOpsStatusPageSettings(cfg)
+	return cfg, nil
+}
+
+func (s *OpsService) UpdateStatusPageSettings(ctx context.Context, cfg *OpsStatusPageSettings) (*OpsStatusPageSettings, error) {
+	if s == nil || s.settingRepo == nil {
+		return nil, errors.New("setting repository not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if cfg == nil {
+		return nil, errors.New("invalid config")
+	}
+
+	normalizeOpsStatusPageSettings(cfg)
+	if err := validateOpsStatusPageSettings(cfg); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyOpsStatusPageSettings, string(raw)); err != nil {
+		return nil, err
+	}
+	s.statusPageCache.Store(nil)
+
+	updated := &OpsStatusPageSettings{}
+	_ = json.Unmarshal(raw, updated)
+	return updated, nil
+}
+
+// ==================== Public status ====================
+
+var ErrOpsStatusPageDisabled = infraerrors.NotFound("STATUS_PAGE_DISABLED", "status page is disabled")
+
+// GetPublicStatus returns the cached public status; at most one computation runs at a time per instance.
+func (s *OpsService) GetPublicStatus(ctx context.Context) (*OpsPublicStatus, error) {
+	if s == nil || s.opsRepo == nil || !s.IsMonitoringEnabled(ctx) {
+		return nil, ErrOpsStatusPageDisabled
+	}
+
+	if cached := s.statusPageCache.Load(); cached != nil && time.Now().Before(cached.expiresAt) {
+		return cached.status, nil
+	}
+
+	v, err, _ := s.statusPageFlight.Do("status", func() (any, error) {
+		if cached := s.statusPageCache.Load(); cached != nil && time.Now().Before(cached.expiresAt) {
+			return cached.status, nil
+		}
+		// Detached from the caller so one client disconnecting does not fail the shared computation.
+		computeCtx, cancel := context.WithTimeout(context.Background(), opsStatusPageComputeTimeout)
+		defer cancel()
+
+		cfg, err := s.GetStatusPageSettings(computeCtx)
+		if err != nil {
+			return nil, err
+		}
+		if !cfg.Enabled {
+			return nil, ErrOpsStatusPageDisabled
+		}
+		status, err := s.computePublicStatus(computeCtx, cfg, time.Now().UTC())
+		if err != nil {
+			// Serve stale data rather than failing the public page.
+			if cached := s.statusPageCache.Load(); cached != nil {
+				log.Printf("[OpsStatusPage] refresh failed, serving stale status: %v", err)
+				return cached.status, nil
+			}
+			return nil, err
+		}
+		s.statusPageCache.Store(&opsStatusPageCache{
+			status:    status,
+			expiresAt: time.Now().Add(time.Duration(cfg.CacheSeconds) * time.Second),
+		})
+		return status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*OpsPublicStatus), nil
+}
+
+func (s *OpsService) computePublicStatus(ctx context.Context, cfg *OpsStatusPageSettings, now time.Time) (*OpsPublicStatus, error) {
+	hourRows, err := s.opsRepo.GetStatusPageStats(ctx, now.Add(-time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+	dayRows, err := s.opsRepo.GetStatusPageStats(ctx, now.Add(-24*time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &OpsPublicStatus{
+		Title:           cfg.Title,
+		GeneratedAt:     now,
+		Platforms:       buildOpsStatusComponents(cfg, hourRows, dayRows),
+		Incidents:       []*OpsPublicIncident{},
+		RecentIncidents: []*OpsPublicIncident{},
+	}
+
+	events, err := s.opsRepo.ListAlertEvents(ctx, &OpsAlertEventFilter{
+		Status:     OpsAlertStatusFiring,
+		PublicOnly: true,
+		Limit:      opsStatusPageMaxIncidents,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		out.Incidents = append(out.Incidents, publicIncidentFromAlertEvent(ev))
+	}
+
+	active, err := s.opsRepo.ListStatusIncidents(ctx, &OpsStatusIncidentFilter{ActiveOnly: true, Limit: opsStatusPageMaxIncidents})
+	if err != nil {
+		return nil, err
+	}
+	for _, inc := range active {
+		out.Incidents = append(out.Incidents, publicIncidentFromManual(inc))
+	}
+
+	since := now.AddDate(0, 0, -opsStatusPageRecentIncidentDays)
+	recent, err := s.opsRepo.ListStatusIncidents(ctx, &OpsStatusIncidentFilter{Since: &since, Limit: opsStatusPageMaxIncidents})
+	if err != nil {
+		return nil, err
+	}
+	for _, inc := range recent {
+		if inc.Status == OpsIncidentResolved {
+			out.RecentIncidents = append(out.RecentIncidents, publicIncidentFromManual(inc))
+		}
+	}
+
+	applyOpsIncidentImpact(out)
+	return out, nil
+}
+
+// applyOpsIncidentImpact lowers component statuses affected by active incidents and derives the overall status.
+func applyOpsIncidentImpact(status *OpsPublicStatus) {
+	overall := OpsStatusOperational
+	for _, inc := range status.Incidents {
+		incStatus := opsStatusFromImpact(inc.Impact)
+		matched := false
+		for _, comp := range status.Platforms {
+			if len(inc.Platforms) == 0 || containsString(inc.Platforms, comp.Platform) {
+				comp.Status = worseOpsStatus(comp.Status, incStatus)
+				matched = true
+			}
+		}
+		if !matched {
+			overall = worseOpsStatus(overall, incStatus)
+		}
+	}
+	for _, comp := range status.Platforms {
+		if comp.Status != OpsStatusNoData {
+			overall = worseOpsStatus(overall, comp.Status)
+		}
+	}
+	status.Status = overall
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func publicIncidentFromAlertEvent(ev *OpsAlertEvent) *OpsPublicIncident {
+	title := strings.TrimSpace(ev.PublicSummary)
+	if title == "" {
+		title = ev.Title
+	}
+	status := OpsIncidentInvestigating
+	if ev.AcknowledgedAt != nil {
+		status = OpsIncidentIdentified
+	}
+	inc := &OpsPublicIncident{
+		Source:    OpsPublicIncidentSourceAlert,
+		ID:        ev.ID,
+		Title:     title,
+		Status:    status,
+		Impact:    opsImpactFromAlertSeverity(ev.Severity),
+		Platforms: []string{},
+		StartedAt: ev.FiredAt,
+	}
+	if p, ok := ev.Dimensions["platform"].(string); ok && strings.TrimSpace(p) != "" {
+		inc.Platforms = []string{strings.ToLower(strings.TrimSpace(p))}
+	}
+	return inc
+}
+
+func publicIncidentFromManual(inc *OpsStatusIncident) *OpsPublicIncident {
+	out := &OpsPublicIncident{
+		Source:     OpsPublicIncidentSourceManual,
+		ID:         inc.ID,
+		Title:      inc.Title,
+		Status:     inc.Status,
+		Impact:     inc.Impact,
+		Platforms:  inc.Platforms,
+		StartedAt:  inc.StartedAt,
+		ResolvedAt: inc.ResolvedAt,
+	}
+	if out.Platforms == nil {
+		out.Platforms = []string{}
+	}
+	for _, u := range inc.Updates {
+		out.Updates = append(out.Updates, &OpsPublicIncidentUpdate{Status: u.Status, Message: u.Message, CreatedAt: u.CreatedAt})
+	}
+	return out
+}
+
+// ==================== Alert events ====================
+
+// SetAlertEventPublic publishes (or hides) an alert event on the status page.
+func (s *OpsService) SetAlertEventPublic(ctx context.Context, eventID int64, public bool, summary string) (*OpsAlertEvent, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if eventID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_EVENT_ID", "invalid event id")
+	}
+	summary = strings.TrimSpace(summary)
+	if len(summary) > opsStatusIncidentTitleMaxLen {
+		return nil, infraerrors.BadRequest("INVALID_PUBLIC_SUMMARY", "public summary is too long")
+	}
+	if err := s.opsRepo.UpdateAlertEventPublic(ctx, eventID, public, summary); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_ALERT_EVENT_NOT_FOUND", "alert event not found")
+		}
+		return nil, err
+	}
+	s.statusPageCache.Store(nil)
+	return s.GetAlertEventByID(ctx, eventID)
+}
+
+// ==================== Incidents ====================
+
+func (s *OpsService) ListStatusIncidents(ctx context.Context, limit int) ([]*OpsStatusIncident, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsStatusIncident{}, nil
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.opsRepo.ListStatusIncidents(ctx, &OpsStatusIncidentFilter{Limit: limit})
+}
+
+func (s *OpsService) CreateStatusIncident(ctx context.Context, input *OpsStatusIncidentInput, actorUserID *int64) (*OpsStatusIncident, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if input == nil {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT", "invalid incident")
+	}
+	if strings.TrimSpace(input.Status) == "" {
+		input.Status = OpsIncidentInvestigating
+	}
+	if err := validateOpsStatusIncidentInput(input); err != nil {
+		return nil, err
+	}
+	message := strings.TrimSpace(input.Message)
+	if message == "" {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_MESSAGE", "message is required")
+	}
+	if len(message) > opsStatusIncidentMessageMaxLen {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_MESSAGE", "message is too long")
+	}
+
+	now := time.Now().UTC()
+	incident := &OpsStatusIncident{
+		Title:     strings.TrimSpace(input.Title),
+		Status:    input.Status,
+		Impact:    input.Impact,
+		Platforms: normalizeOpsIncidentPlatforms(input.Platforms),
+		StartedAt: now,
+		CreatedBy: actorUserID,
+	}
+	if input.StartedAt != nil && !input.StartedAt.IsZero() {
+		incident.StartedAt = input.StartedAt.UTC()
+	}
+	if incident.Status == OpsIncidentResolved {
+		incident.ResolvedAt = &now
+	}
+
+	created, err := s.opsRepo.CreateStatusIncident(ctx, incident)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.opsRepo.CreateStatusIncidentUpdate(ctx, &OpsStatusIncidentUpdate{
+		IncidentID: created.ID,
+		Status:     created.Status,
+		Message:    message,
+		CreatedBy:  actorUserID,
+	}); err != nil {
+		return nil, err
+	}
+	s.statusPageCache.Store(nil)
+	return s.getStatusIncident(ctx, created.ID)
+}
+
+// UpdateStatusIncident edits title / impact / platforms; status changes go through AddStatusIncidentUpdate.
+func (s *OpsService) UpdateStatusIncident(ctx context.Context, id int64, input *OpsStatusIncidentInput) (*OpsStatusIncident, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 || input == nil {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_ID", "invalid incident id")
+	}
+
+	existing, err := s.getStatusIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	input.Status = existing.Status
+	if err := validateOpsStatusIncidentInput(input); err != nil {
+		return nil, err
+	}
+	existing.Title = strings.TrimSpace(input.Title)
+	existing.Impact = input.Impact
+	existing.Platforms = normalizeOpsIncidentPlatforms(input.Platforms)
+	if input.StartedAt != nil && !input.StartedAt.IsZero() {
+		existing.StartedAt = input.StartedAt.UTC()
+	}
+	if err := s.opsRepo.UpdateStatusIncident(ctx, existing); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_STATUS_INCIDENT_NOT_FOUND", "incident not found")
+		}
+		return nil, err
+	}
+	s.statusPageCache.Store(nil)
+	return s.getStatusIncident(ctx, id)
+}
+
+// AddStatusIncidentUpdate posts a timeline update and moves the incident to the update's status.
+func (s *OpsService) AddStatusIncidentUpdate(ctx context.Context, id int64, input *OpsStatusIncidentUpdateInput, actorUserID *int64) (*OpsStatusIncident, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 || input == nil {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_ID", "invalid incident id")
+	}
+	input.Status = strings.TrimSpace(input.Status)
+	input.Message = strings.TrimSpace(input.Message)
+	if !validateOpsIncidentStatus(input.Status) {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_STATUS", "invalid incident status")
+	}
+	if input.Message == "" {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_MESSAGE", "message is required")
+	}
+	if len(input.Message) > opsStatusIncidentMessageMaxLen {
+		return nil, infraerrors.BadRequest("INVALID_INCIDENT_MESSAGE", "message is too long")
+	}
+
+	existing, err := s.getStatusIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	existing.Status = input.Status
+	if input.Status == OpsIncidentResolved {
+		if existing.ResolvedAt == nil {
+			now := time.Now().UTC()
+			existing.ResolvedAt = &now
+		}
+	} else {
+		existing.ResolvedAt = nil
+	}
+	if err := s.opsRepo.UpdateStatusIncident(ctx, existing); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_STATUS_INCIDENT_NOT_FOUND", "incident not found")
+		}
+		return nil, err
+	}
+	if _, err := s.opsRepo.CreateStatusIncidentUpdate(ctx, &OpsStatusIncidentUpdate{
+		IncidentID: id,
+		Status:     input.Status,
+		Message:    input.Message,
+		CreatedBy:  actorUserID,
+	}); err != nil {
+		return nil, err
+	}
+	s.statusPageCache.Store(nil)
+	return s.getStatusIncident(ctx, id)
+}
+
+func (s *OpsService) DeleteStatusIncident(ctx context.Context, id int64) error {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return err
+	}
+	if s.opsRepo == nil {
+		return infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return infraerrors.BadRequest("INVALID_INCIDENT_ID", "invalid incident id")
+	}
+	if err := s.opsRepo.DeleteStatusIncident(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return infraerrors.NotFound("OPS_STATUS_INCIDENT_NOT_FOUND", "incident not found")
+		}
+		return err
+	}
+	s.statusPageCache.Store(nil)
+	return nil
+}
+
+func (s *OpsService) getStatusIncident(ctx context.Context, id int64) (*OpsStatusIncident, error) {
+	incident, err := s.opsRepo.GetStatusIncidentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_STATUS_INCIDENT_NOT_FOUND", "incident not found")
+		}
+		return nil, err
+	}
+	return incident, nil
+}
+
+func validateOpsStatusIncidentInput(input *OpsStatusIncidentInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return infraerrors.BadRequest("INVALID_INCIDENT_TITLE", "title is required")
+	}
+	if len(title) > opsStatusIncidentTitleMaxLen {
+		return infraerrors.BadRequest("INVALID_INCIDENT_TITLE", "title is too long")
+	}
+	input.Status = strings.TrimSpace(input.Status)
+	if !validateOpsIncidentStatus(input.Status) {
+		return infraerrors.BadRequest("INVALID_INCIDENT_STATUS", "invalid incident status")
+	}
+	input.Impact = strings.TrimSpace(input.Impact)
+	if input.Impact == "" {
+		input.Impact = OpsIncidentImpactMinor
+	}
+	if !validateOpsIncidentImpact(input.Impact) {
+		return infraerrors.BadRequest("INVALID_INCIDENT_IMPACT", "invalid incident impact")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type opsStatusPageRepoStub struct {
+	OpsRepository
+	rows       []*OpsStatusPageStatsRow
+	events     []*OpsAlertEvent
+	incidents  []*OpsStatusIncident
+	statsCalls int
+}
+
+func (r *opsStatusPageRepoStub) GetStatusPageStats(ctx context.Context, start, end time.Time) ([]*OpsStatusPageStatsRow, error) {
+	r.statsCalls++
+	return r.rows, nil
+}
+
+func (r *opsStatusPageRepoStub) ListAlertEvents(ctx context.Context, filter *OpsAlertEventFilter) ([]*OpsAlertEvent, error) {
+	return r.events, nil
+}
+
+func (r *opsStatusPageRepoStub) ListStatusIncidents(ctx context.Context, filter *OpsStatusIncidentFilter) ([]*OpsStatusIncident, error) {
+	out := []*OpsStatusIncident{}
+	for _, inc := range r.incidents {
+		if filter.ActiveOnly && inc.Status == OpsIncidentResolved {
+			continue
+		}
+		out = append(out, inc)
+	}
+	return out, nil
+}
+
+func opsStatusTestFloat(v float64) *float64 { return &v }
+
+func TestOpsStatusFromAvailability(t *testing.T) {
+	require.Equal(t, OpsStatusNoData, opsStatusFromAvailability(nil))
+	require.Equal(t, OpsStatusOperational, opsStatusFromAvailability(opsStatusTestFloat(99.5)))
+	require.Equal(t, OpsStatusDegraded, opsStatusFromAvailability(opsStatusTestFloat(97)))
+	require.Equal(t, OpsStatusPartialOutage, opsStatusFromAvailability(opsStatusTestFloat(85)))
+	require.Equal(t, OpsStatusMajorOutage, opsStatusFromAvailability(opsStatusTestFloat(50)))
+}
+
+func TestBuildOpsStatusComponents(t *testing.T) {
+	cfg := &OpsStatusPageSettings{ShowModels: true, MinModelRequests: 10, Platforms: []string{"anthropic", "openai", "gemini"}}
+	hour := []*OpsStatusPageStatsRow{
+		{Platform: "anthropic", SuccessCount: 90, ErrorCount: 10},
+		{Platform: "anthropic", Model: "claude-a", SuccessCount: 90, ErrorCount: 10},
+	}
+	day := []*OpsStatusPageStatsRow{
+		{Platform: "anthropic", SuccessCount: 995, ErrorCount: 5, LatencyP50Ms: opsStatusTestFloat(800)},
+		{Platform: "anthropic", Model: "claude-a", SuccessCount: 900, ErrorCount: 5},
+		{Platform: "anthropic", Model: "claude-rare", SuccessCount: 3},
+		{Platform: "openai", SuccessCount: 100},
+		{Platform: "antigravity", SuccessCount: 100},
+	}
+
+	comps := buildOpsStatusComponents(cfg, hour, day)
+	require.Len(t, comps, 3)
+
+	anthropic := comps[0]
+	require.Equal(t, "anthropic", anthropic.Platform)
+	require.Equal(t, OpsStatusPartialOutage, anthropic.Status)
+	require.InDelta(t, 90.0, *anthropic.LastHour.Availability, 1e-9)
+	require.InDelta(t, 99.5, *anthropic.LastDay.Availability, 1e-9)
+	require.InDelta(t, 800.0, *anthropic.LastDay.LatencyP50Ms, 1e-9)
+	// Rare models are hidden.
+	require.Len(t, anthropic.Models, 1)
+	require.Equal(t, "claude-a", anthropic.Models[0].Model)
+
+	// Configured platform without traffic is listed as no_data; unlisted platforms are dropped.
+	require.Equal(t, "gemini", comps[1].Platform)
+	require.Equal(t, OpsStatusNoData, comps[1].Status)
+	require.Equal(t, "openai", comps[2].Platform)
+	require.Equal(t, OpsStatusNoData, comps[2].Status)
+}
+
+func TestApplyOpsIncidentImpact(t *testing.T) {
+	status := &OpsPublicStatus{
+		Platforms: []*OpsStatusComponent{
+			{Platform: "anthropic", Status: OpsStatusOperational},
+			{Platform: "openai", Status: OpsStatusOperational},
+			{Platform: "gemini", Status: OpsStatusNoData},
+		},
+		Incidents: []*OpsPublicIncident{
+			{Impact: OpsIncidentImpactCritical, Platforms: []string{"openai"}},
+		},
+	}
+	applyOpsIncidentImpact(status)
+	require.Equal(t, OpsStatusOperational, status.Platforms[0].Status)
+	require.Equal(t, OpsStatusMajorOutage, status.Platforms[1].Status)
+	require.Equal(t, OpsStatusMajorOutage, status.Status)
+
+	// An incident without platforms affects every component.
+	status.Incidents = []*OpsPublicIncident{{Impact: OpsIncidentImpactMinor}}
+	status.Platforms[1].Status = OpsStatusOperational
+	applyOpsIncidentImpact(status)
+	require.Equal(t, OpsStatusDegraded, status.Platforms[0].Status)
+	require.Equal(t, OpsStatusDegraded, status.Platforms[2].Status)
+	require.Equal(t, OpsStatusDegraded, status.Status)
+}
+
+func TestOpsServiceGetPublicStatus_CachedAndIncidents(t *testing.T) {
+	resolvedAt := time.Now().Add(-time.Hour)
+	repo := &opsStatusPageRepoStub{
+		rows: []*OpsStatusPageStatsRow{{Platform: "anthropic", SuccessCount: 100}},
+		events: []*OpsAlertEvent{{
+			ID:            7,
+			Title:         "internal: error rate > 5% on group 12",
+			PublicSummary: "Elevated errors",
+			Severity:      "P1",
+			Dimensions:    map[string]any{"platform": "anthropic"},
+		}},
+		incidents: []*OpsStatusIncident{
+			{ID: 1, Title: "Maintenance", Status: OpsIncidentMonitoring, Impact: OpsIncidentImpactMinor},
+			{ID: 2, Title: "Old", Status: OpsIncidentResolved, Impact: OpsIncidentImpactMajor, ResolvedAt: &resolvedAt},
+		},
+	}
+	settingRepo := &settingRepoStub{values: map[string]string{
+		SettingKeyOpsStatusPageSettings: `{"enabled":true,"cache_seconds":60}`,
+	}}
+	svc := &OpsService{opsRepo: repo, settingRepo: settingRepo}
+
+	status, err := svc.GetPublicStatus(context.Background())
+	require.NoError(t, err)
+	require.Len(t, status.Incidents, 2)
+	require.Equal(t, OpsPublicIncidentSourceAlert, status.Incidents[0].Source)
+	require.Equal(t, "Elevated errors", status.Incidents[0].Title)
+	require.Equal(t, OpsIncidentImpactMajor, status.Incidents[0].Impact)
+	require.Len(t, status.RecentIncidents, 1)
+	require.Equal(t, OpsStatusPartialOutage, status.Platforms[0].Status)
+	require.Equal(t, OpsStatusPartialOutage, status.Status)
+
+	_, err = svc.GetPublicStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, repo.statsCalls, "second call must be served from cache")
+}
+
+func TestOpsServiceGetPublicStatus_Disabled(t *testing.T) {
+	svc := &OpsService{opsRepo: &opsStatusPageRepoStub{}, settingRepo: &settingRepoStub{values: map[string]string{}}}
+	_, err := svc.GetPublicStatus(context.Background())
+	require.ErrorIs(t, err, ErrOpsStatusPageDisabled)
+}
@@ -0,0 +1,543 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 支付订单状态
+const (
+	PaymentOrderStatusPending           = "pending"
+	PaymentOrderStatusPaid              = "paid"
+	PaymentOrderStatusPartiallyRefunded = "partially_refunded"
+	PaymentOrderStatusRefunded          = "refunded"
+	PaymentOrderStatusExpired           = "expired"
+	PaymentOrderStatusFailed            = "failed"
+)
+
+// 退款记录状态
+const (
+	// PaymentRefundStatusPending 已校验并记录，等待支付商退款与扣回余额
+	PaymentRefundStatusPending   = "refund_pending"
+	PaymentRefundStatusCompleted = "completed"
+	// PaymentRefundStatusFailed 支付商拒绝退款；以相同退款单号重试时恢复为 refund_pending
+	PaymentRefundStatusFailed = "failed"
+)
+
+// 支付商
+const (
+	// PaymentProviderStripe Stripe Checkout
+	PaymentProviderStripe = "stripe"
+	// PaymentProviderGeneric 通用签名回调支付商（本地聚合支付 / 收银台）
+	PaymentProviderGeneric = "generic"
+)
+
+// 支付回调事件类型
+const (
+	PaymentEventPaid     = "paid"
+	PaymentEventRefunded = "refunded"
+	PaymentEventExpired  = "expired"
+	PaymentEventFailed   = "failed"
+	// PaymentEventIgnored 与充值无关的通知（如 Stripe 的其他事件类型），直接确认
+	PaymentEventIgnored = "ignored"
+)
+
+var (
+	ErrPaymentDisabled             = infraerrors.Forbidden("PAYMENT_DISABLED", "online payment is disabled")
+	ErrPaymentProviderUnavailable  = infraerrors.BadRequest("PAYMENT_PROVIDER_UNAVAILABLE", "payment provider is not available")
+	ErrPaymentOrderNotFound        = infraerrors.NotFound("PAYMENT_ORDER_NOT_FOUND", "payment order not found")
+	ErrPaymentTooManyPending       = infraerrors.TooManyRequests("PAYMENT_TOO_MANY_PENDING", "too many unpaid orders, complete them or wait for them to expire")
+	ErrPaymentCheckoutFailed       = infraerrors.ServiceUnavailable("PAYMENT_CHECKOUT_FAILED", "failed to create payment, please try again later")
+	ErrPaymentWebhookInvalid       = infraerrors.BadRequest("PAYMENT_WEBHOOK_INVALID", "invalid payment notification")
+	ErrPaymentRefundInvalid        = infraerrors.BadRequest("PAYMENT_REFUND_INVALID", "only paid orders can be refunded, up to the remaining paid amount")
+	ErrPaymentRefundUnsupported    = infraerrors.BadRequest("PAYMENT_REFUND_UNSUPPORTED", "provider does not support refunds via API, record an offline refund instead")
+	ErrPaymentRefundFailed         = infraerrors.ServiceUnavailable("PAYMENT_REFUND_FAILED", "provider refund failed")
+	ErrPaymentRefundExceedsBalance = infraerrors.Conflict("PAYMENT_REFUND_EXCEEDS_BALANCE", "user balance is lower than the amount this refund would deduct")
+	ErrPaymentRefundInProgress     = infraerrors.Conflict("PAYMENT_REFUND_IN_PROGRESS", "another refund of this order is still being processed")
+	ErrPaymentRefundNotFound       = infraerrors.NotFound("PAYMENT_REFUND_NOT_FOUND", "payment refund not found")
+)
+
+// paymentZeroDecimalCurrencies 无小数位的币种（金额以元为最小单位）
+var paymentZeroDecimalCurrencies = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true, "krw": true, "mga": true,
+	"pyg": true, "rwf": true, "ugx": true, "vnd": true, "vuv": true, "xaf": true, "xof": true, "xpf": true,
+}
+
+// PaymentOrder 一笔在线充值订单
+type PaymentOrder struct {
+	ID                int64  `json:"id"`
+	OrderNo           string `json:"order_no"`
+	UserID            int64  `json:"user_id"`
+	Provider          string `json:"provider"`
+	ProviderOrderID   string `json:"provider_order_id"`
+	ProviderPaymentID string `json:"provider_payment_id"`
+
+	// Amount 到账余额（USD）；PayAmount / Currency 为实付金额与币种
+	Amount    float64 `json:"amount"`
+	PayAmount float64 `json:"pay_amount"`
+	Currency  string  `json:"currency"`
+	// BonusAmount 入账时发放的优惠码比例赠送
+	BonusAmount float64 `json:"bonus_amount"`
+
+	Status        string `json:"status"`
+	PayURL        string `json:"pay_url,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+
+	// RefundedAmount 累计退款（支付币种）；RefundedCredit 累计扣回余额
+	RefundedAmount float64 `json:"refunded_amount"`
+	RefundedCredit float64 `json:"refunded_credit"`
+
+	ExpiresAt  time.Time  `json:"expires_at"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// 关联信息（管理员列表展示用）
+	UserEmail string `json:"user_email,omitempty"`
+}
+
+// IsRefundable 已支付且未全额退款
+func (o *PaymentOrder) IsRefundable() bool {
+	return o.Status == PaymentOrderStatusPaid || o.Status == PaymentOrderStatusPartiallyRefunded
+}
+
+// PaymentOrderFilter 订单列表过滤条件（管理员）
+type PaymentOrderFilter struct {
+	UserID   int64
+	Status   string
+	Provider string
+	Search   string
+}
+
+// PaymentRefundRecord 管理员发起的一次退款
+//
+// 先在事务内校验并记录（refund_pending），再在事务外调用支付商，最后按 RefundNo 幂等地扣回余额。
+type PaymentRefundRecord struct {
+	ID       int64
+	RefundNo string
+	OrderNo  string
+	// Amount 本次退款金额；RefundedAmount 退款完成后的累计退款额（均为支付币种）
+	Amount         float64
+	RefundedAmount float64
+	Reason         string
+	// Offline 线下退款，不调用支付商
+	Offline bool
+
+	Status           string
+	ProviderRefundID string
+	LastError        string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Event 退款对应的订单事件，事件 ID 由退款单号派生，保证扣回只执行一次
+func (r *PaymentRefundRecord) Event() *PaymentEvent {
+	return &PaymentEvent{
+		ID:             "refund:" + r.RefundNo,
+		Type:           PaymentEventRefunded,
+		OrderNo:        r.OrderNo,
+		RefundedAmount: r.RefundedAmount,
+		Reason:         r.Reason,
+	}
+}
+
+// PaymentEvent 支付商回调解析结果（金额均为支付币种）
+type PaymentEvent struct {
+	// ID 支付商事件 ID，用于幂等去重
+	ID                string
+	Type              string
+	OrderNo           string
+	ProviderOrderID   string
+	ProviderPaymentID string
+	Amount            float64
+	Currency          string
+	// RefundedAmount 退款事件中的累计退款金额
+	RefundedAmount float64
+	Reason         string
+}
+
+// PaymentCheckoutRequest 创建支付会话参数
+type PaymentCheckoutRequest struct {
+	OrderNo   string
+	PayAmount float64
+	Currency  string
+	Subject   string
+	NotifyURL string
+	ReturnURL string
+	ExpiresAt time.Time
+}
+
+// PaymentCheckout 支付会话
+type PaymentCheckout struct {
+	ProviderOrderID string
+	PayURL          string
+}
+
+// PaymentRefund 支付商退款结果
+type PaymentRefund struct {
+	ID string
+}
+
+// PaymentProvider 支付商适配接口
+type PaymentProvider interface {
+	Name() string
+	// CreateCheckout 创建支付会话，返回用户跳转的支付地址
+	CreateCheckout(ctx context.Context, req *PaymentCheckoutRequest) (*PaymentCheckout, error)
+	// ParseWebhook 校验回调签名并解析为事件；签名无效时返回错误
+	ParseWebhook(header http.Header, body []byte, now time.Time) (*PaymentEvent, error)
+	// Refund 发起退款；amount 为支付币种，refundNo 用作支付商侧幂等键
+	Refund(ctx context.Context, order *PaymentOrder, amount float64, refundNo, reason string) (*PaymentRefund, error)
+}
+
+// PaymentRepository 支付订单数据访问接口
+//
+// 回调处理在 ent 事务中调用，实现需通过 dbent.TxFromContext 使用同一事务。
+type PaymentRepository interface {
+	CreateOrder(ctx context.Context, order *PaymentOrder) error
+	// UpdateCheckout 保存支付商会话 ID 与支付地址
+	UpdateCheckout(ctx context.Context, id int64, providerOrderID, payURL string) error
+	GetOrder(ctx context.Context, orderNo string) (*PaymentOrder, error)
+	// GetOrderForUpdate 锁定订单；orderNo 为空时按 (provider, provider_payment_id) 查找，不存在时返回 nil, nil
+	GetOrderForUpdate(ctx context.Context, provider, orderNo, providerPaymentID string) (*PaymentOrder, error)
+	// UpdateOrderState 保存状态、交易号、赠送、退款与时间字段
+	UpdateOrderState(ctx context.Context, order *PaymentOrder) error
+	ListOrders(ctx context.Context, params pagination.PaginationParams, filter PaymentOrderFilter) ([]PaymentOrder, *pagination.PaginationResult, error)
+	// CountPending 用户未过期的待支付订单数
+	CountPending(ctx context.Context, userID int64, now time.Time) (int, error)
+	// LockUserBalance 在事务内锁定用户行并返回当前余额
+	LockUserBalance(ctx context.Context, userID int64) (float64, error)
+	// RecordEvent 记录回调事件；(provider, event_id) 已存在时返回 false
+	RecordEvent(ctx context.Context, provider string, event *PaymentEvent) (bool, error)
+	// ExpireOrders 将超时未支付的订单标记为 expired
+	ExpireOrders(ctx context.Context, now time.Time) (int64, error)
+
+	// CreateRefund 记录 refund_pending 退款；同一退款单号此前失败时恢复为 refund_pending。
+	// 订单已有处理中的退款、或退款单号已处理中 / 已完成时返回 ErrPaymentRefundInProgress
+	CreateRefund(ctx context.Context, refund *PaymentRefundRecord) error
+	// GetRefundForUpdate 锁定退款记录；不存在时返回 ErrPaymentRefundNotFound
+	GetRefundForUpdate(ctx context.Context, refundNo string) (*PaymentRefundRecord, error)
+	// UpdateRefund 保存状态、支付商退款号与错误信息
+	UpdateRefund(ctx context.Context, refund *PaymentRefundRecord) error
+	// ListPendingRefunds 返回 updated_at 早于 before 的 refund_pending 记录，按 ID 升序
+	ListPendingRefunds(ctx context.Context, before time.Time, limit int) ([]PaymentRefundRecord, error)
+}
+
+// PaymentSettings 在线支付配置（存储在 settings 表，key = payment_settings）
+type PaymentSettings struct {
+	Enabled bool `json:"enabled"`
+	// PublicBaseURL 站点公开访问地址，用于生成支付回调地址与支付完成后的返回地址
+	PublicBaseURL string `json:"public_base_url"`
+	// MinAmount / MaxAmount 单笔充值金额范围（USD 余额）
+	MinAmount float64 `json:"min_amount"`
+	MaxAmount float64 `json:"max_amount"`
+	// OrderExpireMinutes 未支付订单有效期（30-1440 分钟，Stripe 要求至少 30 分钟）
+	OrderExpireMinutes int `json:"order_expire_minutes"`
+
+	Stripe  PaymentStripeConfig  `json:"stripe"`
+	Generic PaymentGenericConfig `json:"generic"`
+}
+
+// PaymentStripeConfig Stripe Checkout 配置
+type PaymentStripeConfig struct {
+	Enabled bool `json:"enabled"`
+	// APIBase 默认 https://api.stripe.com，可指向兼容的测试服务
+	APIBase                 string `json:"api_base"`
+	SecretKey               string `json:"secret_key,omitempty"`
+	SecretKeyConfigured     bool   `json:"secret_key_configured"`
+	WebhookSecret           string `json:"webhook_secret,omitempty"`
+	WebhookSecretConfigured bool   `json:"webhook_secret_configured"`
+	// Currency 支付币种（ISO 4217）；ExchangeRate 为每 1 美元余额对应的支付金额
+	Currency     string  `json:"currency"`
+	ExchangeRate float64 `json:"exchange_rate"`
+}
+
+// PaymentGenericConfig 通用签名回调支付商配置
+type PaymentGenericConfig struct {
+	Enabled     bool   `json:"enabled"`
+	DisplayName string `json:"display_name"`
+	// GatewayURL 收银台地址，下单参数与签名以 query 形式附加
+	GatewayURL string `json:"gateway_url"`
+	// RefundURL 退款接口地址；为空时仅支持线下退款
+	RefundURL           string  `json:"refund_url"`
+	MerchantID          string  `json:"merchant_id"`
+	SecretKey           string  `json:"secret_key,omitempty"`
+	SecretKeyConfigured bool    `json:"secret_key_configured"`
+	Currency            string  `json:"currency"`
+	ExchangeRate        float64 `json:"exchange_rate"`
+}
+
+// DefaultPaymentSettings 返回默认配置（默认关闭）
+func DefaultPaymentSettings() *PaymentSettings {
+	return &PaymentSettings{
+		Enabled:            false,
+		MinAmount:          1,
+		MaxAmount:          1000,
+		OrderExpireMinutes: 30,
+		Stripe: PaymentStripeConfig{
+			APIBase:      stripeDefaultAPIBase,
+			Currency:     "usd",
+			ExchangeRate: 1,
+		},
+		Generic: PaymentGenericConfig{
+			DisplayName:  "Online Payment",
+			Currency:     "usd",
+			ExchangeRate: 1,
+		},
+	}
+}
+
+// PaymentConfig 用户充值页展示的配置
+type PaymentConfig struct {
+	Enabled   bool                  `json:"enabled"`
+	MinAmount float64               `json:"min_amount"`
+	MaxAmount float64               `json:"max_amount"`
+	Providers []PaymentProviderInfo `json:"providers"`
+}
+
+// PaymentProviderInfo 可用的支付方式
+type PaymentProviderInfo struct {
+	Name         string  `json:"name"`
+	DisplayName  string  `json:"display_name"`
+	Currency     string  `json:"currency"`
+	ExchangeRate float64 `json:"exchange_rate"`
+}
+
+// PaymentTransition 回调事件对订单的影响
+type PaymentTransition struct {
+	Status        string
+	FailureReason string
+	// Credit 入账余额（支付成功）
+	Credit float64
+	// Debit 扣回余额（退款）
+	Debit float64
+	// RefundedAmount 退款后的累计退款（支付币种）
+	RefundedAmount float64
+	// Changed 为 false 表示事件不改变订单（重复、过时或乱序的通知）
+	Changed bool
+}
+
+// DecidePaymentEvent 根据订单当前状态计算回调事件的处理结果
+//
+// - 支付成功：待支付或已过期的订单入账（过期后支付的款项同样到账）；金额或币种不符时标记为 failed 且不入账
+// - 退款：事件携带累计退款额，仅处理超出已记录部分；按比例扣回到账余额与赠送，全额退款时扣回剩余全部
+// - 过期 / 失败：仅作用于待支付订单
+func DecidePaymentEvent(order *PaymentOrder, event *PaymentEvent) *PaymentTransition {
+	unchanged := &PaymentTransition{Status: order.Status, RefundedAmount: order.RefundedAmount}
+
+	switch event.Type {
+	case PaymentEventPaid:
+		if order.Status != PaymentOrderStatusPending && order.Status != PaymentOrderStatusExpired {
+			return unchanged
+		}
+		currencyOK := event.Currency == "" || strings.EqualFold(event.Currency, order.Currency)
+		if !currencyOK || paymentMinorUnits(event.Amount, order.Currency) != paymentMinorUnits(order.PayAmount, order.Currency) {
+			return &PaymentTransition{
+				Status: PaymentOrderStatusFailed,
+				FailureReason: fmt.Sprintf("amount mismatch: paid %s %s, expected %s %s",
+					formatPaymentAmount(event.Amount, order.Currency), strings.ToUpper(event.Currency),
+					formatPaymentAmount(order.PayAmount, order.Currency), strings.ToUpper(order.Currency)),
+				RefundedAmount: order.RefundedAmount,
+				Changed:        true,
+			}
+		}
+		return &PaymentTransition{Status: PaymentOrderStatusPaid, Credit: order.Amount, RefundedAmount: order.RefundedAmount, Changed: true}
+
+	case PaymentEventRefunded:
+		if !order.IsRefundable() {
+			return unchanged
+		}
+		total := paymentMinorUnits(order.PayAmount, order.Currency)
+		refunded := paymentMinorUnits(event.RefundedAmount, order.Currency)
+		if refunded > total {
+			refunded = total
+		}
+		if refunded <= paymentMinorUnits(order.RefundedAmount, order.Currency) {
+			return unchanged
+		}
+		credited := order.Amount + order.BonusAmount
+		transition := &PaymentTransition{
+			Status:         PaymentOrderStatusPartiallyRefunded,
+			RefundedAmount: paymentFromMinorUnits(refunded, order.Currency),
+			Changed:        true,
+		}
+		if refunded == total {
+			transition.Status = PaymentOrderStatusRefunded
+			transition.Debit = roundPaymentCredit(credited - order.RefundedCredit)
+		} else {
+			delta := transition.RefundedAmount - order.RefundedAmount
+			transition.Debit = roundPaymentCredit(credited * delta / order.PayAmount)
+		}
+		return transition
+
+	case PaymentEventExpired, PaymentEventFailed:
+		if order.Status != PaymentOrderStatusPending {
+			return unchanged
+		}
+		status := PaymentOrderStatusExpired
+		if event.Type == PaymentEventFailed {
+			status = PaymentOrderStatusFailed
+		}
+		return &PaymentTransition{Status: status, FailureReason: event.Reason, Changed: true}
+	}
+	return unchanged
+}
+
+// PaymentLedgerNote 余额流水备注
+func PaymentLedgerNote(order *PaymentOrder, refund bool) string {
+	if refund {
+		return fmt.Sprintf("在线充值退款（订单 %s）", order.OrderNo)
+	}
+	return fmt.Sprintf("在线充值（订单 %s，%s）", order.OrderNo, order.Provider)
+}
+
+// paymentCurrencyDecimals 币种的小数位数
+func paymentCurrencyDecimals(currency string) int {
+	if paymentZeroDecimalCurrencies[strings.ToLower(currency)] {
+		return 0
+	}
+	return 2
+}
+
+// paymentMinorUnits 转换为最小货币单位（分）
+func paymentMinorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(paymentCurrencyDecimals(currency))))
+}
+
+func paymentFromMinorUnits(units int64, currency string) float64 {
+	return float64(units) / math.Pow10(paymentCurrencyDecimals(currency))
+}
+
+// paymentPayAmount 按汇率换算实付金额并按币种精度取整
+func paymentPayAmount(amount, exchangeRate float64, currency string) float64 {
+	return paymentFromMinorUnits(paymentMinorUnits(amount*exchangeRate, currency), currency)
+}
+
+func formatPaymentAmount(amount float64, currency string) string {
+	return fmt.Sprintf("%.*f", paymentCurrencyDecimals(currency), amount)
+}
+
+func roundPaymentCredit(v float64) float64 {
+	return math.Round(v*1e8) / 1e8
+}
+
+// newPaymentOrderNo 生成订单号：PAY + UTC 时间 + 8 位随机十六进制
+func newPaymentOrderNo(now time.Time) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "PAY" + now.UTC().Format("20060102150405") + strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// normalizePaymentSettings 清理输入并补全默认值
+func normalizePaymentSettings(s *PaymentSettings) {
+	s.PublicBaseURL = strings.TrimRight(strings.TrimSpace(s.PublicBaseURL), "/")
+
+	s.Stripe.APIBase = strings.TrimRight(strings.TrimSpace(s.Stripe.APIBase), "/")
+	if s.Stripe.APIBase == "" {
+		s.Stripe.APIBase = stripeDefaultAPIBase
+	}
+	s.Stripe.SecretKey = strings.TrimSpace(s.Stripe.SecretKey)
+	s.Stripe.WebhookSecret = strings.TrimSpace(s.Stripe.WebhookSecret)
+	s.Stripe.Currency = strings.ToLower(strings.TrimSpace(s.Stripe.Currency))
+
+	s.Generic.DisplayName = strings.TrimSpace(s.Generic.DisplayName)
+	if s.Generic.DisplayName == "" {
+		s.Generic.DisplayName = "Online Payment"
+	}
+	s.Generic.GatewayURL = strings.TrimSpace(s.Generic.GatewayURL)
+	s.Generic.RefundURL = strings.TrimSpace(s.Generic.RefundURL)
+	s.Generic.MerchantID = strings.TrimSpace(s.Generic.MerchantID)
+	s.Generic.SecretKey = strings.TrimSpace(s.Generic.SecretKey)
+	s.Generic.Currency = strings.ToLower(strings.TrimSpace(s.Generic.Currency))
+}
+
+func validatePaymentSettings(s *PaymentSettings) error {
+	if s.MinAmount <= 0 || s.MaxAmount < s.MinAmount || s.MaxAmount > 100000 {
+		return errors.New("amount range must satisfy 0 < min_amount <= max_amount <= 100000")
+	}
+	if s.OrderExpireMinutes < 30 || s.OrderExpireMinutes > 1440 {
+		return errors.New("order_expire_minutes must be between 30-1440")
+	}
+	if s.PublicBaseURL != "" && !isHTTPURL(s.PublicBaseURL) {
+		return errors.New("public_base_url must be an http(s) URL")
+	}
+
+	if s.Stripe.Enabled {
+		if s.Stripe.SecretKey == "" || s.Stripe.WebhookSecret == "" {
+			return errors.New("stripe secret_key and webhook_secret are required")
+		}
+		if !isHTTPURL(s.Stripe.APIBase) {
+			return errors.New("stripe api_base must be an http(s) URL")
+		}
+		if err := validatePaymentCurrency(s.Stripe.Currency, s.Stripe.ExchangeRate); err != nil {
+			return fmt.Errorf("stripe %w", err)
+		}
+	}
+	if s.Generic.Enabled {
+		if s.Generic.MerchantID == "" || s.Generic.SecretKey == "" {
+			return errors.New("generic merchant_id and secret_key are required")
+		}
+		if !isHTTPURL(s.Generic.GatewayURL) {
+			return errors.New("generic gateway_url must be an http(s) URL")
+		}
+		if s.Generic.RefundURL != "" && !isHTTPURL(s.Generic.RefundURL) {
+			return errors.New("generic refund_url must be an http(s) URL")
+		}
+		if err := validatePaymentCurrency(s.Generic.Currency, s.Generic.ExchangeRate); err != nil {
+			return fmt.Errorf("generic %w", err)
+		}
+	}
+
+	if s.Enabled {
+		if s.PublicBaseURL == "" {
+			return errors.New("public_base_url is required when payment is enabled")
+		}
+		if !s.Stripe.Enabled && !s.Generic.Enabled {
+			return errors.New("enable at least one payment provider")
+		}
+	}
+	return nil
+}
+
+func validatePaymentCurrency(currency string, exchangeRate float64) error {
+	if len(currency) != 3 {
+		return errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+	if exchangeRate <= 0 || exchangeRate > 1000000 {
+		return errors.New("exchange_rate must be between 0-1000000")
+	}
+	return nil
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// maskPaymentSettings 隐藏密钥，仅返回是否已配置
+func maskPaymentSettings(s PaymentSettings) PaymentSettings {
+	s.Stripe.SecretKeyConfigured = s.Stripe.SecretKey != ""
+	s.Stripe.WebhookSecretConfigured = s.Stripe.WebhookSecret != ""
+	s.Stripe.SecretKey = ""
+	s.Stripe.WebhookSecret = ""
+	s.Generic.SecretKeyConfigured = s.Generic.SecretKey != ""
+	s.Generic.SecretKey = ""
+	return s
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	genericPaymentMaxResponseBytes = 64 << 10
+	// genericPaymentTimestampTolerance 回调 timestamp 允许的最大偏差，防止重放
+	genericPaymentTimestampTolerance = 5 * time.Minute
+)
+
+// genericProvider 通用签名回调支付商
+//
+// 适配常见的本地聚合支付 / 收银台协议：
+//   - 下单：跳转 GatewayURL，参数以 query 附加并签名
+//   - 回调：form 或扁平 JSON，status 为 paid / refunded / closed / failed，refund_amount 为累计退款额；
+//     必须携带参与签名的 timestamp（Unix 秒），与本地时间偏差超过 5 分钟的回调被拒绝
+//   - 退款：向 RefundURL 发送签名的 form POST，2xx 且 code = 0（或 success = true）视为成功
+//
+// 签名：除 sign 外的非空参数按 key 排序拼接为 k1=v1&k2=v2，使用 SecretKey 计算 HMAC-SHA256 十六进制小写。
+type genericProvider struct {
+	cfg        PaymentGenericConfig
+	httpClient *http.Client
+}
+
+func newGenericProvider(cfg PaymentGenericConfig, httpClient *http.Client) *genericProvider {
+	return &genericProvider{cfg: cfg, httpClient: httpClient}
+}
+
+func (p *genericProvider) Name() string {
+	return PaymentProviderGeneric
+}
+
+func (p *genericProvider) CreateCheckout(_ context.Context, req *PaymentCheckoutRequest) (*PaymentCheckout, error) {
+	gateway, err := url.Parse(p.cfg.GatewayURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse gateway url: %w", err)
+	}
+	params := url.Values{}
+	params.Set("merchant_id", p.cfg.MerchantID)
+	params.Set("out_trade_no", req.OrderNo)
+	params.Set("amount", formatPaymentAmount(req.PayAmount, req.Currency))
+	params.Set("currency", strings.ToUpper(req.Currency))
+	params.Set("subject", req.Subject)
+	params.Set("notify_url", req.NotifyURL)
+	params.Set("return_url", req.ReturnURL)
+	if !req.ExpiresAt.IsZero() {
+		params.Set("expire_time", strconv.FormatInt(req.ExpiresAt.Unix(), 10))
+	}
+	params.Set("timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+	params.Set("sign", signGenericPayment(params, p.cfg.SecretKey))
+
+	// 保留 GatewayURL 自带的 query 参数（不参与签名）
+	query := gateway.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	gateway.RawQuery = query.Encode()
+	return &PaymentCheckout{PayURL: gateway.String()}, nil
+}
+
+func (p *genericProvider) ParseWebhook(header http.Header, body []byte, now time.Time) (*PaymentEvent, error) {
+	params, err := parseGenericPaymentParams(header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, ErrPaymentWebhookInvalid.WithCause(err)
+	}
+	sign := params.Get("sign")
+	if sign == "" || !hmac.Equal([]byte(strings.ToLower(sign)), []byte(signGenericPayment(params, p.cfg.SecretKey))) {
+		return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("signature mismatch"))
+	}
+	ts, err := strconv.ParseInt(params.Get("timestamp"), 10, 64)
+	if err != nil {
+		return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("missing or invalid timestamp"))
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > genericPaymentTimestampTolerance || d < -genericPaymentTimestampTolerance {
+		return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("timestamp outside tolerance"))
+	}
+	if params.Get("merchant_id") != p.cfg.MerchantID {
+		return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("merchant mismatch"))
+	}
+	orderNo := params.Get("out_trade_no")
+	if orderNo == "" {
+		return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("missing out_trade_no"))
+	}
+
+	status := strings.ToLower(params.Get("status"))
+	event := &PaymentEvent{
+		ID:                params.Get("event_id"),
+		Type:              PaymentEventIgnored,
+		OrderNo:           orderNo,
+		ProviderPaymentID: params.Get("trade_no"),
+		Currency:          strings.ToLower(params.Get("currency")),
+		Reason:            params.Get("reason"),
+	}
+	switch status {
+	case "paid", "success":
+		event.Type = PaymentEventPaid
+		if event.Amount, err = strconv.ParseFloat(params.Get("amount"), 64); err != nil {
+			return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("invalid amount"))
+		}
+	case "refunded":
+		event.Type = PaymentEventRefunded
+		if event.RefundedAmount, err = strconv.ParseFloat(params.Get("refund_amount"), 64); err != nil {
+			return nil, ErrPaymentWebhookInvalid.WithCause(fmt.Errorf("invalid refund_amount"))
+		}
+	case "closed", "expired":
+		event.Type = PaymentEventExpired
+	case "failed":
+		event.Type = PaymentEventFailed
+	}
+	// 未提供事件 ID 时以订单、状态与累计退款额去重：同一状态的重复通知只处理一次
+	if event.ID == "" {
+		event.ID = orderNo + ":" + status + ":" + params.Get("refund_amount")
+	}
+	return event, nil
+}
+
+func (p *genericProvider) Refund(ctx context.Context, order *PaymentOrder, amount float64, refundNo, reason string) (*PaymentRefund, error) {
+	if p.cfg.RefundURL == "" {
+		return nil, ErrPaymentRefundUnsupported
+	}
+	params := url.Values{}
+	params.Set("merchant_id", p.cfg.MerchantID)
+	params.Set("out_trade_no", order.OrderNo)
+	params.Set("trade_no", order.ProviderPaymentID)
+	params.Set("out_refund_no", refundNo)
+	params.Set("refund_amount", formatPaymentAmount(amount, order.Currency))
+	params.Set("currency", strings.ToUpper(order.Currency))
+	params.Set("reason", reason)
+	params.Set("timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+	params.Set("sign", signGenericPayment(params, p.cfg.SecretKey))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.RefundURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("generic refund request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, genericPaymentMaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read generic refund response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("generic refund: status=%d", resp.StatusCode)
+	}
+	result := gjson.ParseBytes(body)
+	if code := result.Get("code"); code.Exists() && code.String() != "0" {
+		return nil, fmt.Errorf("generic refund: code=%s msg=%s", code.String(), result.Get("msg").String())
+	}
+	if success := result.Get("success"); success.Exists() && !success.Bool() {
+		return nil, fmt.Errorf("generic refund: %s", result.Get("msg").String())
+	}
+	return &PaymentRefund{ID: result.Get("refund_no").String()}, nil
+}
+
+// parseGenericPaymentParams 解析 form 或扁平 JSON 回调参数
+func parseGenericPaymentParams(contentType string, body []byte) (url.Values, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" {
+		var raw map[string]any
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, err
+		}
+		params := url.Values{}
+		for k, v := range raw {
+			switch val := v.(type) {
+			case string:
+				params.Set(k, val)
+			case float64:
+				params.Set(k, strconv.FormatFloat(val, 'f', -1, 64))
+			case bool:
+				params.Set(k, strconv.FormatBool(val))
+			case nil:
+			default:
+				return nil, fmt.Errorf("unsupported value for %q", k)
+			}
+		}
+		return params, nil
+	}
+	return url.ParseQuery(string(body))
+}
+
+// signGenericPayment 计算签名（排除 sign 与空值）
+func signGenericPayment(params url.Values, secret string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "sign" || params.Get(k) == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(params.Get(k))
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(b.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
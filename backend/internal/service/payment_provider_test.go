@@ -0,0 +1,238 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func stripeSignatureHeader(secret string, ts time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + signStripePayload(secret, timestamp, body)
+}
+
+func TestStripeProvider_CheckoutAndRefund(t *testing.T) {
+	var checkoutForm, refundForm url.Values
+	var idempotencyKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get("Idempotency-Key"))
+		body, _ := io.ReadAll(r.Body)
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		switch r.URL.Path {
+		case "/v1/checkout/sessions":
+			checkoutForm = form
+			_, _ = w.Write([]byte(`{"id":"cs_test_1","url":"https://checkout.stripe.test/cs_test_1"}`))
+		case "/v1/refunds":
+			refundForm = form
+			_, _ = w.Write([]byte(`{"id":"re_1","status":"succeeded"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"unknown path"}}`))
+		}
+	}))
+	defer server.Close()
+
+	p := newStripeProvider(PaymentStripeConfig{APIBase: server.URL, SecretKey: "sk_test", WebhookSecret: "whsec"}, server.Client())
+	expiresAt := time.Unix(1770000000, 0)
+	checkout, err := p.CreateCheckout(context.Background(), &PaymentCheckoutRequest{
+		OrderNo:   "PAY1",
+		PayAmount: 12.34,
+		Currency:  "usd",
+		Subject:   "Top-up",
+		ReturnURL: "https://example.com/redeem?payment_order=PAY1",
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "cs_test_1", checkout.ProviderOrderID)
+	require.Equal(t, "https://checkout.stripe.test/cs_test_1", checkout.PayURL)
+	require.Equal(t, "payment", checkoutForm.Get("mode"))
+	require.Equal(t, "PAY1", checkoutForm.Get("metadata[order_no]"))
+	require.Equal(t, "PAY1", checkoutForm.Get("payment_intent_data[metadata][order_no]"))
+	require.Equal(t, "1234", checkoutForm.Get("line_items[0][price_data][unit_amount]"))
+	require.Equal(t, "1770000000", checkoutForm.Get("expires_at"))
+
+	order := &PaymentOrder{OrderNo: "PAY1", ProviderPaymentID: "pi_1", Currency: "usd"}
+	refund, err := p.Refund(context.Background(), order, 5, "PAY1-R500", "requested")
+	require.NoError(t, err)
+	require.Equal(t, "re_1", refund.ID)
+	require.Equal(t, "pi_1", refundForm.Get("payment_intent"))
+	require.Equal(t, "500", refundForm.Get("amount"))
+	require.Equal(t, []string{"checkout-PAY1", "PAY1-R500"}, idempotencyKeys)
+
+	// 支付商返回错误
+	p.cfg.APIBase = server.URL + "/bad"
+	_, err = p.CreateCheckout(context.Background(), &PaymentCheckoutRequest{OrderNo: "PAY2", PayAmount: 1, Currency: "usd"})
+	require.ErrorContains(t, err, "unknown path")
+}
+
+func TestStripeProvider_ParseWebhook(t *testing.T) {
+	p := newStripeProvider(PaymentStripeConfig{WebhookSecret: "whsec"}, http.DefaultClient)
+	now := time.Now()
+
+	body := []byte(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","payment_status":"paid","payment_intent":"pi_1","currency":"usd","amount_total":1234,"metadata":{"order_no":"PAY1"}}}}`)
+	header := http.Header{}
+	header.Set("Stripe-Signature", stripeSignatureHeader("whsec", now, body))
+	event, err := p.ParseWebhook(header, body, now)
+	require.NoError(t, err)
+	require.Equal(t, "evt_1", event.ID)
+	require.Equal(t, PaymentEventPaid, event.Type)
+	require.Equal(t, "PAY1", event.OrderNo)
+	require.Equal(t, "pi_1", event.ProviderPaymentID)
+	require.Equal(t, 12.34, event.Amount)
+
+	// 签名错误、篡改正文、时间戳过期均拒绝
+	header.Set("Stripe-Signature", stripeSignatureHeader("other", now, body))
+	_, err = p.ParseWebhook(header, body, now)
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+	header.Set("Stripe-Signature", stripeSignatureHeader("whsec", now, body))
+	_, err = p.ParseWebhook(header, append([]byte{' '}, body...), now)
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+	header.Set("Stripe-Signature", stripeSignatureHeader("whsec", now.Add(-10*time.Minute), body))
+	_, err = p.ParseWebhook(header, body, now)
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+
+	// 异步支付未到账时忽略
+	body = []byte(`{"id":"evt_2","type":"checkout.session.completed","data":{"object":{"id":"cs_1","payment_status":"unpaid","client_reference_id":"PAY1"}}}`)
+	header.Set("Stripe-Signature", stripeSignatureHeader("whsec", now, body))
+	event, err = p.ParseWebhook(header, body, now)
+	require.NoError(t, err)
+	require.Equal(t, PaymentEventIgnored, event.Type)
+
+	// 退款事件携带累计退款额
+	body = []byte(`{"id":"evt_3","type":"charge.refunded","data":{"object":{"payment_intent":"pi_1","currency":"jpy","amount_refunded":500}}}`)
+	header.Set("Stripe-Signature", stripeSignatureHeader("whsec", now, body))
+	event, err = p.ParseWebhook(header, body, now)
+	require.NoError(t, err)
+	require.Equal(t, PaymentEventRefunded, event.Type)
+	require.Empty(t, event.OrderNo)
+	require.Equal(t, "pi_1", event.ProviderPaymentID)
+	require.Equal(t, 500.0, event.RefundedAmount)
+}
+
+func TestGenericProvider_CheckoutAndWebhook(t *testing.T) {
+	cfg := PaymentGenericConfig{GatewayURL: "https://pay.example.com/submit?channel=alipay", MerchantID: "m1", SecretKey: "secret", Currency: "cny"}
+	p := newGenericProvider(cfg, http.DefaultClient)
+
+	checkout, err := p.CreateCheckout(context.Background(), &PaymentCheckoutRequest{
+		OrderNo:   "PAY1",
+		PayAmount: 72.5,
+		Currency:  "cny",
+		Subject:   "Top-up",
+		NotifyURL: "https://example.com/api/v1/payments/webhook/generic",
+	})
+	require.NoError(t, err)
+	payURL, err := url.Parse(checkout.PayURL)
+	require.NoError(t, err)
+	query := payURL.Query()
+	require.Equal(t, "alipay", query.Get("channel"))
+	require.Equal(t, "72.50", query.Get("amount"))
+	require.Equal(t, "CNY", query.Get("currency"))
+	signed := url.Values{}
+	for k, v := range query {
+		if k != "channel" {
+			signed[k] = v
+		}
+	}
+	require.Equal(t, signGenericPayment(signed, "secret"), query.Get("sign"))
+
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	params := url.Values{
+		"merchant_id":  {"m1"},
+		"out_trade_no": {"PAY1"},
+		"trade_no":     {"T100"},
+		"status":       {"paid"},
+		"amount":       {"72.50"},
+		"currency":     {"CNY"},
+		"timestamp":    {timestamp},
+	}
+	params.Set("sign", signGenericPayment(params, "secret"))
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	event, err := p.ParseWebhook(header, []byte(params.Encode()), now)
+	require.NoError(t, err)
+	require.Equal(t, PaymentEventPaid, event.Type)
+	require.Equal(t, "PAY1:paid:", event.ID)
+	require.Equal(t, "T100", event.ProviderPaymentID)
+	require.Equal(t, 72.5, event.Amount)
+	require.Equal(t, "cny", event.Currency)
+
+	// JSON 回调，数值字段同样参与签名
+	jsonBody := []byte(`{"merchant_id":"m1","out_trade_no":"PAY1","status":"refunded","refund_amount":10.5,"timestamp":` + timestamp + `,"sign":"` +
+		signGenericPayment(url.Values{"merchant_id": {"m1"}, "out_trade_no": {"PAY1"}, "status": {"refunded"}, "refund_amount": {"10.5"}, "timestamp": {timestamp}}, "secret") + `"}`)
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	event, err = p.ParseWebhook(header, jsonBody, now)
+	require.NoError(t, err)
+	require.Equal(t, PaymentEventRefunded, event.Type)
+	require.Equal(t, 10.5, event.RefundedAmount)
+	require.Equal(t, "PAY1:refunded:10.5", event.ID)
+
+	// 重放过期回调 / 缺少或篡改时间戳
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = p.ParseWebhook(header, []byte(params.Encode()), now.Add(10*time.Minute))
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+	unstamped := url.Values{}
+	for k, v := range params {
+		if k != "timestamp" {
+			unstamped[k] = v
+		}
+	}
+	unstamped.Set("sign", signGenericPayment(unstamped, "secret"))
+	_, err = p.ParseWebhook(header, []byte(unstamped.Encode()), now)
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+	tampered := url.Values{}
+	for k, v := range params {
+		tampered[k] = v
+	}
+	tampered.Set("timestamp", strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10))
+	_, err = p.ParseWebhook(header, []byte(tampered.Encode()), now.Add(10*time.Minute))
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+
+	// 篡改金额或商户号不符
+	params.Set("amount", "0.01")
+	_, err = p.ParseWebhook(header, []byte(params.Encode()), now)
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+	params.Set("merchant_id", "m2")
+	params.Set("sign", signGenericPayment(params, "secret"))
+	_, err = p.ParseWebhook(header, []byte(params.Encode()), now)
+	require.ErrorIs(t, err, ErrPaymentWebhookInvalid)
+}
+
+func TestGenericProvider_Refund(t *testing.T) {
+	var received url.Values
+	reply := `{"code":0,"refund_no":"R1"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		received = r.PostForm
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer server.Close()
+
+	order := &PaymentOrder{OrderNo: "PAY1", ProviderPaymentID: "T100", Currency: "cny"}
+	p := newGenericProvider(PaymentGenericConfig{MerchantID: "m1", SecretKey: "secret"}, server.Client())
+	_, err := p.Refund(context.Background(), order, 5, "PAY1-R500", "")
+	require.ErrorIs(t, err, ErrPaymentRefundUnsupported)
+
+	p.cfg.RefundURL = server.URL
+	refund, err := p.Refund(context.Background(), order, 5, "PAY1-R500", "requested")
+	require.NoError(t, err)
+	require.Equal(t, "R1", refund.ID)
+	require.Equal(t, "PAY1-R500", received.Get("out_refund_no"))
+	require.Equal(t, "5.00", received.Get("refund_amount"))
+	require.Equal(t, signGenericPayment(received, "secret"), received.Get("sign"))
+
+	reply = `{"code":1001,"msg":"insufficient funds"}`
+	_, err = p.Refund(context.Background(), order, 5, "PAY1-R500", "")
+	require.ErrorContains(t, err, "insufficient funds")
+}
@@ -0,0 +1,768 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	paymentWorkerInterval = 5 * time.Minute
+	paymentWorkerTimeout  = time.Minute
+	paymentHTTPTimeout    = 15 * time.Second
+	// paymentMaxPendingOrders 每个用户同时存在的待支付订单上限
+	paymentMaxPendingOrders = 5
+	// paymentRefundReconcileAfter refund_pending 超过该时长仍未完成时由后台任务补齐（远大于支付商请求超时）
+	paymentRefundReconcileAfter = 10 * time.Minute
+	paymentRefundReconcileBatch = 10
+)
+
+// PaymentService 在线支付充值
+//
+// - 用户选择金额与支付方式下单，跳转支付商页面完成支付
+// - 回调按 (provider, event_id) 去重，在同一事务内锁定订单、入账余额、写入余额流水（type = payment）并发放优惠码比例赠送
+// - 退款（管理员发起或支付商通知）按退款比例扣回到账余额与赠送，流水记为负数
+// - 后台每 5 分钟将超时未支付的订单标记为 expired，并补齐停留在 refund_pending 的管理员退款
+type PaymentService struct {
+	repo                 PaymentRepository
+	userRepo             UserRepository
+	redeemRepo           RedeemCodeRepository
+	promoService         *PromoService
+	settingRepo          SettingRepository
+	settingService       *SettingService
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+	entClient            *dbent.Client
+
+	httpClient *http.Client
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewPaymentService 创建在线支付服务
+func NewPaymentService(
+	repo PaymentRepository,
+	userRepo UserRepository,
+	redeemRepo RedeemCodeRepository,
+	promoService *PromoService,
+	settingRepo SettingRepository,
+	settingService *SettingService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	entClient *dbent.Client,
+) *PaymentService {
+	return &PaymentService{
+		repo:                 repo,
+		userRepo:             userRepo,
+		redeemRepo:           redeemRepo,
+		promoService:         promoService,
+		settingRepo:          settingRepo,
+		settingService:       settingService,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		entClient:            entClient,
+		httpClient:           &http.Client{Timeout: paymentHTTPTimeout},
+		stopCh:               make(chan struct{}),
+	}
+}
+
+// Start 启动过期订单清理循环
+func (s *PaymentService) Start() {
+	if s == nil || s.repo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台循环
+func (s *PaymentService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *PaymentService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(paymentWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *PaymentService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), paymentWorkerTimeout)
+	defer cancel()
+
+	// 批量 UPDATE 本身幂等，多实例同时执行无副作用，无需分布式锁
+	expired, err := s.repo.ExpireOrders(ctx, time.Now())
+	if err != nil {
+		log.Printf("[Payment] expire orders failed: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("[Payment] expired %d unpaid orders", expired)
+	}
+
+	// 退款按退款单号幂等，多实例同时补齐同一笔退款也只会扣回一次
+	s.reconcileRefunds(ctx)
+}
+
+// ==================== 配置 ====================
+
+// GetSettings 读取配置（含密钥，仅内部使用）
+func (s *PaymentService) GetSettings(ctx context.Context) (*PaymentSettings, error) {
+	if s.settingRepo == nil {
+		return DefaultPaymentSettings(), nil
+	}
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyPaymentSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return DefaultPaymentSettings(), nil
+		}
+		return nil, fmt.Errorf("get payment settings: %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return DefaultPaymentSettings(), nil
+	}
+
+	settings := DefaultPaymentSettings()
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return DefaultPaymentSettings(), nil
+	}
+	return settings, nil
+}
+
+// GetMaskedSettings 管理员查看配置（隐藏密钥）
+func (s *PaymentService) GetMaskedSettings(ctx context.Context) (*PaymentSettings, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	masked := maskPaymentSettings(*settings)
+	return &masked, nil
+}
+
+// UpdateSettings 校验并保存配置；密钥留空表示保持不变
+func (s *PaymentService) UpdateSettings(ctx context.Context, settings *PaymentSettings) (*PaymentSettings, error) {
+	if settings == nil {
+		return nil, infraerrors.BadRequest("PAYMENT_SETTINGS_INVALID", "settings cannot be nil")
+	}
+	current, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	normalizePaymentSettings(settings)
+	if settings.Stripe.SecretKey == "" {
+		settings.Stripe.SecretKey = current.Stripe.SecretKey
+	}
+	if settings.Stripe.WebhookSecret == "" {
+		settings.Stripe.WebhookSecret = current.Stripe.WebhookSecret
+	}
+	if settings.Generic.SecretKey == "" {
+		settings.Generic.SecretKey = current.Generic.SecretKey
+	}
+	if err := validatePaymentSettings(settings); err != nil {
+		return nil, infraerrors.BadRequest("PAYMENT_SETTINGS_INVALID", err.Error())
+	}
+
+	stored := *settings
+	stored.Stripe.SecretKeyConfigured = false
+	stored.Stripe.WebhookSecretConfigured = false
+	stored.Generic.SecretKeyConfigured = false
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payment settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyPaymentSettings, string(data)); err != nil {
+		return nil, err
+	}
+	masked := maskPaymentSettings(stored)
+	return &masked, nil
+}
+
+// GetConfig 用户充值页配置
+func (s *PaymentService) GetConfig(ctx context.Context) (*PaymentConfig, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &PaymentConfig{
+		Enabled:   settings.Enabled,
+		MinAmount: settings.MinAmount,
+		MaxAmount: settings.MaxAmount,
+		Providers: []PaymentProviderInfo{},
+	}
+	if !settings.Enabled {
+		return cfg, nil
+	}
+	if settings.Stripe.Enabled {
+		cfg.Providers = append(cfg.Providers, PaymentProviderInfo{
+			Name:         PaymentProviderStripe,
+			DisplayName:  "Stripe",
+			Currency:     settings.Stripe.Currency,
+			ExchangeRate: settings.Stripe.ExchangeRate,
+		})
+	}
+	if settings.Generic.Enabled {
+		cfg.Providers = append(cfg.Providers, PaymentProviderInfo{
+			Name:         PaymentProviderGeneric,
+			DisplayName:  settings.Generic.DisplayName,
+			Currency:     settings.Generic.Currency,
+			ExchangeRate: settings.Generic.ExchangeRate,
+		})
+	}
+	return cfg, nil
+}
+
+// provider 按名称构造支付商；requireEnabled 为 false 时只要求已配置密钥（处理已停用支付方式的回调与退款）
+func (s *PaymentService) provider(settings *PaymentSettings, name string, requireEnabled bool) PaymentProvider {
+	switch name {
+	case PaymentProviderStripe:
+		cfg := settings.Stripe
+		if (requireEnabled && !cfg.Enabled) || cfg.SecretKey == "" || cfg.WebhookSecret == "" {
+			return nil
+		}
+		return newStripeProvider(cfg, s.httpClient)
+	case PaymentProviderGeneric:
+		cfg := settings.Generic
+		if (requireEnabled && !cfg.Enabled) || cfg.SecretKey == "" || cfg.MerchantID == "" {
+			return nil
+		}
+		return newGenericProvider(cfg, s.httpClient)
+	}
+	return nil
+}
+
+// ==================== 用户 ====================
+
+// CreateOrder 创建充值订单并返回支付地址
+func (s *PaymentService) CreateOrder(ctx context.Context, userID int64, providerName string, amount float64) (*PaymentOrder, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, ErrPaymentDisabled
+	}
+	provider := s.provider(settings, providerName, true)
+	if provider == nil {
+		return nil, ErrPaymentProviderUnavailable
+	}
+	amount = roundPaymentCredit(amount)
+	if amount < settings.MinAmount || amount > settings.MaxAmount {
+		return nil, infraerrors.BadRequest("PAYMENT_AMOUNT_INVALID",
+			fmt.Sprintf("amount must be between %.2f and %.2f", settings.MinAmount, settings.MaxAmount)).
+			WithMetadata(map[string]string{
+				"min": fmt.Sprintf("%.2f", settings.MinAmount),
+				"max": fmt.Sprintf("%.2f", settings.MaxAmount),
+			})
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+
+	now := time.Now()
+	pending, err := s.repo.CountPending(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("count pending orders: %w", err)
+	}
+	if pending >= paymentMaxPendingOrders {
+		return nil, ErrPaymentTooManyPending
+	}
+
+	currency, rate := settings.Stripe.Currency, settings.Stripe.ExchangeRate
+	if providerName == PaymentProviderGeneric {
+		currency, rate = settings.Generic.Currency, settings.Generic.ExchangeRate
+	}
+	payAmount := paymentPayAmount(amount, rate, currency)
+	if payAmount <= 0 {
+		return nil, infraerrors.BadRequest("PAYMENT_AMOUNT_INVALID", "amount is too small")
+	}
+
+	orderNo, err := newPaymentOrderNo(now)
+	if err != nil {
+		return nil, fmt.Errorf("generate order no: %w", err)
+	}
+	order := &PaymentOrder{
+		OrderNo:   orderNo,
+		UserID:    userID,
+		Provider:  providerName,
+		Amount:    amount,
+		PayAmount: payAmount,
+		Currency:  currency,
+		Status:    PaymentOrderStatusPending,
+		ExpiresAt: now.Add(time.Duration(settings.OrderExpireMinutes) * time.Minute),
+	}
+	if err := s.repo.CreateOrder(ctx, order); err != nil {
+		return nil, fmt.Errorf("create payment order: %w", err)
+	}
+
+	checkout, err := provider.CreateCheckout(ctx, &PaymentCheckoutRequest{
+		OrderNo:   orderNo,
+		PayAmount: payAmount,
+		Currency:  currency,
+		Subject:   fmt.Sprintf("%s balance top-up $%.2f", s.siteName(ctx), amount),
+		NotifyURL: settings.PublicBaseURL + "/api/v1/payments/webhook/" + providerName,
+		ReturnURL: settings.PublicBaseURL + "/redeem?payment_order=" + orderNo,
+		ExpiresAt: order.ExpiresAt,
+	})
+	if err != nil {
+		log.Printf("[Payment] create checkout failed: order=%s provider=%s err=%v", orderNo, providerName, err)
+		order.Status = PaymentOrderStatusFailed
+		order.FailureReason = "checkout creation failed"
+		if updateErr := s.repo.UpdateOrderState(ctx, order); updateErr != nil {
+			log.Printf("[Payment] mark order failed: order=%s err=%v", orderNo, updateErr)
+		}
+		return nil, ErrPaymentCheckoutFailed
+	}
+	if err := s.repo.UpdateCheckout(ctx, order.ID, checkout.ProviderOrderID, checkout.PayURL); err != nil {
+		return nil, fmt.Errorf("save checkout: %w", err)
+	}
+	order.ProviderOrderID = checkout.ProviderOrderID
+	order.PayURL = checkout.PayURL
+	return order, nil
+}
+
+// GetMyOrder 用户查看自己的订单（支付完成返回后轮询状态）
+func (s *PaymentService) GetMyOrder(ctx context.Context, userID int64, orderNo string) (*PaymentOrder, error) {
+	order, err := s.repo.GetOrder(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrPaymentOrderNotFound
+	}
+	return order, nil
+}
+
+// ListMyOrders 用户的充值订单
+func (s *PaymentService) ListMyOrders(ctx context.Context, userID int64, params pagination.PaginationParams) ([]PaymentOrder, *pagination.PaginationResult, error) {
+	return s.repo.ListOrders(ctx, params, PaymentOrderFilter{UserID: userID})
+}
+
+// ==================== 管理员 ====================
+
+// ListOrders 管理员查看订单
+func (s *PaymentService) ListOrders(ctx context.Context, params pagination.PaginationParams, filter PaymentOrderFilter) ([]PaymentOrder, *pagination.PaginationResult, error) {
+	return s.repo.ListOrders(ctx, params, filter)
+}
+
+// Refund 管理员发起退款
+//
+// amount 为支付币种金额，0 表示退还剩余全部；offline 为 true 时只记录退款并扣回余额（款项已在线下或支付商后台退还）。
+// 支付商退款是外部调用，不能放在持有订单 / 用户行锁的事务内，因此分三步：
+//  1. 事务内锁定订单与用户、校验余额（已被消费时不允许退款，避免扣成负数），写入 refund_pending 记录
+//  2. 事务外调用支付商；退款单号按累计退款额生成，作为支付商侧幂等键
+//  3. 第二个事务按退款单号幂等地扣回余额并更新订单
+//
+// 第三步失败时记录保持 refund_pending：支付商的退款回调会按累计退款额入账，后台任务也会以同一退款单号补齐。
+func (s *PaymentService) Refund(ctx context.Context, orderNo string, amount float64, reason string, offline bool) (*PaymentOrder, error) {
+	order, err := s.repo.GetOrder(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsRefundable() {
+		return nil, ErrPaymentRefundInvalid
+	}
+	remaining := paymentMinorUnits(order.PayAmount, order.Currency) - paymentMinorUnits(order.RefundedAmount, order.Currency)
+	refundUnits := paymentMinorUnits(amount, order.Currency)
+	if amount == 0 {
+		refundUnits = remaining
+	}
+	if refundUnits <= 0 || refundUnits > remaining {
+		return nil, ErrPaymentRefundInvalid
+	}
+	cumulativeUnits := paymentMinorUnits(order.RefundedAmount, order.Currency) + refundUnits
+	refund := &PaymentRefundRecord{
+		RefundNo:       fmt.Sprintf("%s-R%d", order.OrderNo, cumulativeUnits),
+		OrderNo:        order.OrderNo,
+		Amount:         paymentFromMinorUnits(refundUnits, order.Currency),
+		RefundedAmount: paymentFromMinorUnits(cumulativeUnits, order.Currency),
+		Reason:         reason,
+		Offline:        offline,
+	}
+
+	var provider PaymentProvider
+	if !offline {
+		settings, err := s.GetSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if provider = s.provider(settings, order.Provider, false); provider == nil {
+			return nil, ErrPaymentProviderUnavailable
+		}
+	}
+
+	if err := s.reserveRefund(ctx, order, refund); err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		if err := s.callProviderRefund(ctx, provider, order, refund); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.completeRefund(ctx, refund.RefundNo, refund.ProviderRefundID); err != nil {
+		log.Printf("[Payment] refund %s left pending, will be reconciled: %v", refund.RefundNo, err)
+		return nil, err
+	}
+	return s.repo.GetOrder(ctx, orderNo)
+}
+
+// reserveRefund 第一步：锁定订单与用户、校验余额并记录 refund_pending
+//
+// order 为发起时读取的订单，锁定后累计退款额不一致说明存在并发退款。
+func (s *PaymentService) reserveRefund(ctx context.Context, order *PaymentOrder, refund *PaymentRefundRecord) error {
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := dbent.NewTxContext(ctx, tx)
+
+	locked, err := s.repo.GetOrderForUpdate(txCtx, order.Provider, order.OrderNo, "")
+	if err != nil {
+		return fmt.Errorf("lock payment order: %w", err)
+	}
+	if locked == nil {
+		return ErrPaymentOrderNotFound
+	}
+	transition := DecidePaymentEvent(locked, refund.Event())
+	if !transition.Changed ||
+		paymentMinorUnits(locked.RefundedAmount, locked.Currency) != paymentMinorUnits(order.RefundedAmount, order.Currency) {
+		return ErrPaymentRefundInvalid
+	}
+	balance, err := s.repo.LockUserBalance(txCtx, locked.UserID)
+	if err != nil {
+		return fmt.Errorf("lock user balance: %w", err)
+	}
+	if balance < transition.Debit {
+		return ErrPaymentRefundExceedsBalance.WithMetadata(map[string]string{
+			"debit":   fmt.Sprintf("%.2f", transition.Debit),
+			"balance": fmt.Sprintf("%.2f", balance),
+		})
+	}
+	if err := s.repo.CreateRefund(txCtx, refund); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// callProviderRefund 第二步：事务外调用支付商；支付商拒绝时将记录标记为 failed
+func (s *PaymentService) callProviderRefund(ctx context.Context, provider PaymentProvider, order *PaymentOrder, refund *PaymentRefundRecord) error {
+	result, err := provider.Refund(ctx, order, refund.Amount, refund.RefundNo, refund.Reason)
+	if err == nil {
+		if result != nil {
+			refund.ProviderRefundID = result.ID
+		}
+		return nil
+	}
+
+	log.Printf("[Payment] provider refund failed: order=%s refund=%s err=%v", order.OrderNo, refund.RefundNo, err)
+	refund.Status = PaymentRefundStatusFailed
+	refund.LastError = truncateString(err.Error(), 1024)
+	if updateErr := s.repo.UpdateRefund(ctx, refund); updateErr != nil {
+		log.Printf("[Payment] mark refund %s failed: %v", refund.RefundNo, updateErr)
+	}
+	if errors.Is(err, ErrPaymentRefundUnsupported) {
+		return err
+	}
+	return ErrPaymentRefundFailed
+}
+
+// completeRefund 第三步：按退款单号幂等地扣回余额、更新订单并标记记录完成
+//
+// 退款回调可能先于本步到达并已按累计退款额扣回，此时订单不再变化，只标记记录完成。
+func (s *PaymentService) completeRefund(ctx context.Context, refundNo, providerRefundID string) error {
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := dbent.NewTxContext(ctx, tx)
+
+	refund, err := s.repo.GetRefundForUpdate(txCtx, refundNo)
+	if err != nil {
+		return fmt.Errorf("lock payment refund: %w", err)
+	}
+	if refund.Status != PaymentRefundStatusPending {
+		return nil
+	}
+	order, err := s.repo.GetOrder(txCtx, refund.OrderNo)
+	if err != nil {
+		return err
+	}
+	if order, err = s.repo.GetOrderForUpdate(txCtx, order.Provider, order.OrderNo, ""); err != nil {
+		return fmt.Errorf("lock payment order: %w", err)
+	}
+	if order == nil {
+		return ErrPaymentOrderNotFound
+	}
+
+	event := refund.Event()
+	fresh, err := s.repo.RecordEvent(txCtx, order.Provider, event)
+	if err != nil {
+		return fmt.Errorf("record payment event: %w", err)
+	}
+	transition := &PaymentTransition{}
+	if fresh {
+		transition = DecidePaymentEvent(order, event)
+		if err := s.applyTransitionLocked(txCtx, order, event, transition); err != nil {
+			return err
+		}
+	}
+
+	refund.Status = PaymentRefundStatusCompleted
+	refund.LastError = ""
+	if providerRefundID != "" {
+		refund.ProviderRefundID = providerRefundID
+	}
+	if err := s.repo.UpdateRefund(txCtx, refund); err != nil {
+		return fmt.Errorf("update payment refund: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	if transition.Debit > 0 {
+		s.invalidateCaches(ctx, order.UserID)
+	}
+	return nil
+}
+
+// reconcileRefunds 补齐停留在 refund_pending 的退款（调用支付商后进程退出，或第三步提交失败）：
+// 以同一退款单号重新调用支付商（支付商侧幂等，已退款时不会重复退款），再按退款单号扣回余额
+func (s *PaymentService) reconcileRefunds(ctx context.Context) {
+	refunds, err := s.repo.ListPendingRefunds(ctx, time.Now().Add(-paymentRefundReconcileAfter), paymentRefundReconcileBatch)
+	if err != nil {
+		log.Printf("[Payment] list pending refunds failed: %v", err)
+		return
+	}
+	if len(refunds) == 0 {
+		return
+	}
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		log.Printf("[Payment] reconcile refunds: load settings failed: %v", err)
+		return
+	}
+
+	for i := range refunds {
+		refund := &refunds[i]
+		if !refund.Offline {
+			order, err := s.repo.GetOrder(ctx, refund.OrderNo)
+			if err != nil {
+				log.Printf("[Payment] reconcile refund %s: load order failed: %v", refund.RefundNo, err)
+				continue
+			}
+			provider := s.provider(settings, order.Provider, false)
+			if provider == nil {
+				log.Printf("[Payment] reconcile refund %s: provider %s unavailable", refund.RefundNo, order.Provider)
+				continue
+			}
+			if err := s.callProviderRefund(ctx, provider, order, refund); err != nil {
+				continue
+			}
+		}
+		if err := s.completeRefund(ctx, refund.RefundNo, refund.ProviderRefundID); err != nil {
+			log.Printf("[Payment] reconcile refund %s failed: %v", refund.RefundNo, err)
+			continue
+		}
+		log.Printf("[Payment] reconciled refund %s", refund.RefundNo)
+	}
+}
+
+// ==================== 回调 ====================
+
+// HandleWebhook 校验并处理支付商回调；返回 nil 表示可以向支付商确认
+func (s *PaymentService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+	provider := s.provider(settings, providerName, false)
+	if provider == nil {
+		return ErrPaymentProviderUnavailable
+	}
+	event, err := provider.ParseWebhook(header, body, time.Now())
+	if err != nil {
+		log.Printf("[Payment] reject webhook: provider=%s err=%v", providerName, err)
+		return err
+	}
+	if event.Type == PaymentEventIgnored {
+		return nil
+	}
+	return s.applyEvent(ctx, providerName, event)
+}
+
+// applyEvent 在同一事务内去重事件、锁定订单并入账 / 扣回余额
+func (s *PaymentService) applyEvent(ctx context.Context, providerName string, event *PaymentEvent) error {
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := dbent.NewTxContext(ctx, tx)
+
+	fresh, err := s.repo.RecordEvent(txCtx, providerName, event)
+	if err != nil {
+		return fmt.Errorf("record payment event: %w", err)
+	}
+	if !fresh {
+		return nil
+	}
+
+	order, err := s.repo.GetOrderForUpdate(txCtx, providerName, event.OrderNo, event.ProviderPaymentID)
+	if err != nil {
+		return fmt.Errorf("lock payment order: %w", err)
+	}
+	if order == nil {
+		// 不属于本站的通知（如同一 Stripe 账号下的其他业务），记录后确认，避免支付商反复重试
+		log.Printf("[Payment] webhook for unknown order: provider=%s event=%s order=%s payment=%s",
+			providerName, event.ID, event.OrderNo, event.ProviderPaymentID)
+		return tx.Commit()
+	}
+
+	transition := DecidePaymentEvent(order, event)
+	if err := s.applyTransitionLocked(txCtx, order, event, transition); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if transition.FailureReason != "" {
+		log.Printf("[Payment] order %s marked %s: %s", order.OrderNo, order.Status, transition.FailureReason)
+	}
+	if transition.Credit > 0 || transition.Debit > 0 {
+		s.invalidateCaches(ctx, order.UserID)
+	}
+	return nil
+}
+
+// applyTransitionLocked 在已锁定订单的事务内入账 / 扣回余额并保存订单
+func (s *PaymentService) applyTransitionLocked(txCtx context.Context, order *PaymentOrder, event *PaymentEvent, transition *PaymentTransition) error {
+	if !transition.Changed {
+		return nil
+	}
+
+	now := time.Now()
+	if event.ProviderOrderID != "" {
+		order.ProviderOrderID = event.ProviderOrderID
+	}
+	if event.ProviderPaymentID != "" {
+		order.ProviderPaymentID = event.ProviderPaymentID
+	}
+	order.Status = transition.Status
+	order.FailureReason = transition.FailureReason
+	order.RefundedAmount = transition.RefundedAmount
+
+	if transition.Credit > 0 {
+		ledger, err := s.writeLedger(txCtx, order, transition.Credit, PaymentLedgerNote(order, false), now)
+		if err != nil {
+			return err
+		}
+		if s.promoService != nil {
+			bonus, err := s.promoService.ApplyTopUpBonus(txCtx, order.UserID, ledger.ID, transition.Credit)
+			if err != nil {
+				return fmt.Errorf("apply promo top-up bonus: %w", err)
+			}
+			order.BonusAmount = bonus
+		}
+		order.PaidAt = &now
+	}
+	if transition.Debit > 0 {
+		if _, err := s.writeLedger(txCtx, order, -transition.Debit, PaymentLedgerNote(order, true), now); err != nil {
+			return err
+		}
+		order.RefundedCredit = roundPaymentCredit(order.RefundedCredit + transition.Debit)
+		order.RefundedAt = &now
+	}
+
+	if err := s.repo.UpdateOrderState(txCtx, order); err != nil {
+		return fmt.Errorf("update payment order: %w", err)
+	}
+	return nil
+}
+
+// writeLedger 变更余额并写入余额流水
+func (s *PaymentService) writeLedger(txCtx context.Context, order *PaymentOrder, value float64, notes string, now time.Time) (*RedeemCode, error) {
+	if err := s.userRepo.UpdateBalance(txCtx, order.UserID, value); err != nil {
+		return nil, fmt.Errorf("update user balance: %w", err)
+	}
+	code, err := GenerateRedeemCode()
+	if err != nil {
+		return nil, fmt.Errorf("generate ledger code: %w", err)
+	}
+	userID := order.UserID
+	ledger := &RedeemCode{
+		Code:   code,
+		Type:   LedgerTypePayment,
+		Value:  value,
+		Status: StatusUsed,
+		UsedBy: &userID,
+		UsedAt: &now,
+		Notes:  notes,
+	}
+	if err := s.redeemRepo.Create(txCtx, ledger); err != nil {
+		return nil, fmt.Errorf("create ledger record: %w", err)
+	}
+	return ledger, nil
+}
+
+func (s *PaymentService) invalidateCaches(ctx context.Context, userID int64) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
+	}
+	if s.billingCacheService == nil {
+		return
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.billingCacheService.InvalidateUserBalance(cacheCtx, userID); err != nil {
+			log.Printf("[Payment] invalidate user balance cache failed: user=%d err=%v", userID, err)
+		}
+	}()
+}
+
+func (s *PaymentService) siteName(ctx context.Context) string {
+	if s.settingService != nil {
+		return s.settingService.GetSiteName(ctx)
+	}
+	return "Sub2API"
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	stripeDefaultAPIBase = "https://api.stripe.com"
+	// stripeSignatureTolerance 回调时间戳允许的最大偏差，防止重放
+	stripeSignatureTolerance = 5 * time.Minute
+	stripeMaxResponseBytes   = 1 << 20
+)
+
+// stripeProvider Stripe Checkout 适配
+//
+// 下单创建 Checkout Session 并跳转其托管页面；回调使用 Stripe-Signature 校验，
+// 支付成功以 checkout.session.completed / async_payment_succeeded 为准，退款以 charge.refunded 的累计退款额为准。
+type stripeProvider struct {
+	cfg        PaymentStripeConfig
+	httpClient *http.Client
+}
+
+func newStripeProvider(cfg PaymentStripeConfig, httpClient *http.Client) *stripeProvider {
+	return &stripeProvider{cfg: cfg, httpClient: httpClient}
+}
+
+func (p *stripeProvider) Name() string {
+	return PaymentProviderStripe
+}
+
+func (p *stripeProvider) CreateCheckout(ctx context.Context, req *PaymentCheckoutRequest) (*PaymentCheckout, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", req.ReturnURL)
+	form.Set("cancel_url", req.ReturnURL)
+	form.Set("client_reference_id", req.OrderNo)
+	form.Set("metadata[order_no]", req.OrderNo)
+	form.Set("payment_intent_data[metadata][order_no]", req.OrderNo)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", strings.ToLower(req.Currency))
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(paymentMinorUnits(req.PayAmount, req.Currency), 10))
+	form.Set("line_items[0][price_data][product_data][name]", req.Subject)
+	if !req.ExpiresAt.IsZero() {
+		form.Set("expires_at", strconv.FormatInt(req.ExpiresAt.Unix(), 10))
+	}
+
+	body, err := p.post(ctx, "/v1/checkout/sessions", form, "checkout-"+req.OrderNo)
+	if err != nil {
+		return nil, err
+	}
+	result := gjson.ParseBytes(body)
+	checkout := &PaymentCheckout{
+		ProviderOrderID: result.Get("id").String(),
+		PayURL:          result.Get("url").String(),
+	}
+	if checkout.ProviderOrderID == "" || checkout.PayURL == "" {
+		return nil, fmt.Errorf("stripe checkout: missing session id or url")
+	}
+	return checkout, nil
+}
+
+func (p *stripeProvider) Refund(ctx context.Context, order *PaymentOrder, amount float64, refundNo, reason string) (*PaymentRefund, error) {
+	if order.ProviderPaymentID == "" {
+		return nil, ErrPaymentRefundUnsupported
+	}
+	form := url.Values{}
+	form.Set("payment_intent", order.ProviderPaymentID)
+	form.Set("amount", strconv.FormatInt(paymentMinorUnits(amount, order.Currency), 10))
+	form.Set("metadata[order_no]", order.OrderNo)
+	form.Set("metadata[refund_no]", refundNo)
+	if reason != "" {
+		form.Set("metadata[reason]", reason)
+	}
+
+	body, err := p.post(ctx, "/v1/refunds", form, refundNo)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentRefund{ID: gjson.GetBytes(body, "id").String()}, nil
+}
+
+func (p *stripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.APIBase+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stripe request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, stripeMaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read stripe response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := gjson.GetBytes(body, "error.message").String()
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("stripe %s: status=%d: %s", path, resp.StatusCode, msg)
+	}
+	return body, nil
+}
+
+func (p *stripeProvider) ParseWebhook(header http.Header, body []byte, now time.Time) (*PaymentEvent, error) {
+	if err := verifyStripeSignature(header.Get("Stripe-Signature"), body, p.cfg.WebhookSecret, now); err != nil {
+		return nil, ErrPaymentWebhookInvalid.WithCause(err)
+	}
+
+	payload := gjson.ParseBytes(body)
+	eventID := payload.Get("id").String()
+	if eventID == "" {
+		return nil, ErrPaymentWebhookInvalid
+	}
+	object := payload.Get("data.object")
+	event := &PaymentEvent{ID: eventID, Type: PaymentEventIgnored}
+
+	switch payload.Get("type").String() {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		// 异步支付方式在 completed 时尚未到账（payment_status = unpaid），等待 async_payment_succeeded
+		if object.Get("payment_status").String() != "paid" {
+			return event, nil
+		}
+		event.Type = PaymentEventPaid
+		event.OrderNo = stripeOrderNo(object)
+		event.ProviderOrderID = object.Get("id").String()
+		event.ProviderPaymentID = object.Get("payment_intent").String()
+		event.Currency = object.Get("currency").String()
+		event.Amount = paymentFromMinorUnits(object.Get("amount_total").Int(), event.Currency)
+	case "checkout.session.expired":
+		event.Type = PaymentEventExpired
+		event.OrderNo = stripeOrderNo(object)
+		event.ProviderOrderID = object.Get("id").String()
+	case "checkout.session.async_payment_failed":
+		event.Type = PaymentEventFailed
+		event.OrderNo = stripeOrderNo(object)
+		event.ProviderOrderID = object.Get("id").String()
+		event.Reason = "asynchronous payment failed"
+	case "charge.refunded":
+		// charge 上可能没有订单号（metadata 写在 PaymentIntent 上），此时按 payment_intent 查找订单
+		event.Type = PaymentEventRefunded
+		event.OrderNo = object.Get("metadata.order_no").String()
+		event.ProviderPaymentID = object.Get("payment_intent").String()
+		event.Currency = object.Get("currency").String()
+		event.RefundedAmount = paymentFromMinorUnits(object.Get("amount_refunded").Int(), event.Currency)
+	}
+	return event, nil
+}
+
+func stripeOrderNo(session gjson.Result) string {
+	if orderNo := session.Get("metadata.order_no").String(); orderNo != "" {
+		return orderNo
+	}
+	return session.Get("client_reference_id").String()
+}
+
+// verifyStripeSignature 校验 Stripe-Signature: t=<ts>,v1=<hex(hmac_sha256(secret, "<ts>.<body>"))>
+func verifyStripeSignature(header string, body []byte, secret string, now time.Time) error {
+	if header == "" || secret == "" {
+		return fmt.Errorf("missing signature")
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("malformed signature header")
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > stripeSignatureTolerance || d < -stripeSignatureTolerance {
+		return fmt.Errorf("signature timestamp outside tolerance")
+	}
+
+	expected := signStripePayload(secret, timestamp, body)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature mismatch")
+}
+
+func signStripePayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,145 @@
+//go:build unit
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecidePaymentEvent_Paid(t *testing.T) {
+	order := &PaymentOrder{OrderNo: "PAY1", Amount: 10, PayAmount: 72.5, Currency: "cny", Status: PaymentOrderStatusPending}
+
+	transition := DecidePaymentEvent(order, &PaymentEvent{Type: PaymentEventPaid, Amount: 72.5, Currency: "CNY"})
+	require.True(t, transition.Changed)
+	require.Equal(t, PaymentOrderStatusPaid, transition.Status)
+	require.Equal(t, 10.0, transition.Credit)
+
+	// 过期后才完成的支付同样入账
+	expired := *order
+	expired.Status = PaymentOrderStatusExpired
+	transition = DecidePaymentEvent(&expired, &PaymentEvent{Type: PaymentEventPaid, Amount: 72.5})
+	require.Equal(t, PaymentOrderStatusPaid, transition.Status)
+	require.Equal(t, 10.0, transition.Credit)
+
+	// 金额或币种不符：标记失败，不入账
+	transition = DecidePaymentEvent(order, &PaymentEvent{Type: PaymentEventPaid, Amount: 72.49, Currency: "cny"})
+	require.True(t, transition.Changed)
+	require.Equal(t, PaymentOrderStatusFailed, transition.Status)
+	require.Zero(t, transition.Credit)
+	require.Contains(t, transition.FailureReason, "72.49")
+	transition = DecidePaymentEvent(order, &PaymentEvent{Type: PaymentEventPaid, Amount: 72.5, Currency: "usd"})
+	require.Equal(t, PaymentOrderStatusFailed, transition.Status)
+
+	// 已支付订单的重复通知不再入账
+	paid := *order
+	paid.Status = PaymentOrderStatusPaid
+	transition = DecidePaymentEvent(&paid, &PaymentEvent{Type: PaymentEventPaid, Amount: 72.5})
+	require.False(t, transition.Changed)
+	require.Zero(t, transition.Credit)
+}
+
+func TestDecidePaymentEvent_Refund(t *testing.T) {
+	order := &PaymentOrder{Amount: 10, BonusAmount: 2, PayAmount: 20, Currency: "usd", Status: PaymentOrderStatusPaid}
+
+	// 部分退款：按比例扣回到账余额与赠送
+	transition := DecidePaymentEvent(order, &PaymentEvent{Type: PaymentEventRefunded, RefundedAmount: 5})
+	require.True(t, transition.Changed)
+	require.Equal(t, PaymentOrderStatusPartiallyRefunded, transition.Status)
+	require.InDelta(t, 3, transition.Debit, 1e-9)
+	require.Equal(t, 5.0, transition.RefundedAmount)
+
+	// 累计退款额未增加（重复或乱序通知）：不处理
+	partial := *order
+	partial.Status = PaymentOrderStatusPartiallyRefunded
+	partial.RefundedAmount = 5
+	partial.RefundedCredit = 3
+	transition = DecidePaymentEvent(&partial, &PaymentEvent{Type: PaymentEventRefunded, RefundedAmount: 5})
+	require.False(t, transition.Changed)
+
+	// 退完剩余：扣回剩余全部，避免按比例累计的舍入误差
+	partial.RefundedCredit = 2.99999999
+	transition = DecidePaymentEvent(&partial, &PaymentEvent{Type: PaymentEventRefunded, RefundedAmount: 25})
+	require.Equal(t, PaymentOrderStatusRefunded, transition.Status)
+	require.Equal(t, 20.0, transition.RefundedAmount)
+	require.InDelta(t, 9.00000001, transition.Debit, 1e-9)
+
+	// 未支付订单不能退款
+	pending := *order
+	pending.Status = PaymentOrderStatusPending
+	require.False(t, DecidePaymentEvent(&pending, &PaymentEvent{Type: PaymentEventRefunded, RefundedAmount: 5}).Changed)
+}
+
+func TestPaymentRefundRecord_Event(t *testing.T) {
+	order := &PaymentOrder{OrderNo: "PAY1", Amount: 10, PayAmount: 20, Currency: "usd", Status: PaymentOrderStatusPaid}
+	refund := &PaymentRefundRecord{RefundNo: "PAY1-R500", OrderNo: "PAY1", Amount: 5, RefundedAmount: 5, Reason: "dup"}
+
+	event := refund.Event()
+	require.Equal(t, "refund:PAY1-R500", event.ID)
+	require.Equal(t, PaymentEventRefunded, event.Type)
+	require.Equal(t, 5.0, event.RefundedAmount)
+	require.InDelta(t, 2.5, DecidePaymentEvent(order, event).Debit, 1e-9)
+
+	// 支付商退款回调先于第三步入账：累计退款额已一致，第三步不再扣回
+	refunded := *order
+	refunded.Status = PaymentOrderStatusPartiallyRefunded
+	refunded.RefundedAmount = 5
+	require.False(t, DecidePaymentEvent(&refunded, event).Changed)
+}
+
+func TestDecidePaymentEvent_ExpireAndFail(t *testing.T) {
+	order := &PaymentOrder{Amount: 10, PayAmount: 10, Currency: "usd", Status: PaymentOrderStatusPending}
+
+	transition := DecidePaymentEvent(order, &PaymentEvent{Type: PaymentEventExpired})
+	require.True(t, transition.Changed)
+	require.Equal(t, PaymentOrderStatusExpired, transition.Status)
+
+	transition = DecidePaymentEvent(order, &PaymentEvent{Type: PaymentEventFailed, Reason: "card declined"})
+	require.Equal(t, PaymentOrderStatusFailed, transition.Status)
+	require.Equal(t, "card declined", transition.FailureReason)
+
+	paid := *order
+	paid.Status = PaymentOrderStatusPaid
+	require.False(t, DecidePaymentEvent(&paid, &PaymentEvent{Type: PaymentEventExpired}).Changed)
+	require.False(t, DecidePaymentEvent(&paid, &PaymentEvent{Type: PaymentEventFailed}).Changed)
+}
+
+func TestPaymentAmounts(t *testing.T) {
+	require.Equal(t, int64(1050), paymentMinorUnits(10.5, "usd"))
+	require.Equal(t, int64(1051), paymentMinorUnits(10.505, "usd"))
+	require.Equal(t, int64(1500), paymentMinorUnits(1500, "JPY"))
+	require.Equal(t, 72.46, paymentPayAmount(10, 7.2456, "cny"))
+	require.Equal(t, 1501.0, paymentPayAmount(10, 150.06, "jpy"))
+	require.Equal(t, "1501", formatPaymentAmount(1501, "jpy"))
+
+	orderNo, err := newPaymentOrderNo(time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Regexp(t, `^PAY20260301083000[0-9A-F]{8}$`, orderNo)
+}
+
+func TestValidatePaymentSettings(t *testing.T) {
+	settings := DefaultPaymentSettings()
+	require.NoError(t, validatePaymentSettings(settings))
+
+	settings.Enabled = true
+	require.ErrorContains(t, validatePaymentSettings(settings), "public_base_url")
+	settings.PublicBaseURL = "https://example.com"
+	require.ErrorContains(t, validatePaymentSettings(settings), "at least one")
+
+	settings.Stripe.Enabled = true
+	require.ErrorContains(t, validatePaymentSettings(settings), "stripe")
+	settings.Stripe.SecretKey = "sk_test"
+	settings.Stripe.WebhookSecret = "whsec"
+	require.NoError(t, validatePaymentSettings(settings))
+
+	settings.OrderExpireMinutes = 10
+	require.ErrorContains(t, validatePaymentSettings(settings), "order_expire_minutes")
+
+	masked := maskPaymentSettings(*settings)
+	require.Empty(t, masked.Stripe.SecretKey)
+	require.True(t, masked.Stripe.SecretKeyConfigured)
+	require.True(t, masked.Stripe.WebhookSecretConfigured)
+	require.Equal(t, "sk_test", settings.Stripe.SecretKey)
+}
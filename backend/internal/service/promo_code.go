@@ -4,7 +4,7 @@ import (
 	"time"
 )
 
-// PromoCode 注册优惠码
+// PromoCode 优惠码
 type PromoCode struct {
 	ID          int64
 	Code        string
@@ -17,6 +17,19 @@ type PromoCode struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 
+	// 赠送方式：fixed 立即赠送 BonusAmount；percent 下次充值赠送 BonusPercent%（不超过 MaxBonusAmount）
+	BonusType      string
+	BonusPercent   float64
+	MaxBonusAmount float64
+
+	// 使用条件
+	PerUserLimit    int    // 每用户最多使用次数，0 表示不限
+	Scope           string // register / panel / any
+	NewUsersOnly    bool
+	NewUserDays     int // 注册后多少天内可在面板使用，0 表示仅注册时
+	RequiredGroupID *int64
+	StartsAt        *time.Time
+
 	// 关联
 	UsageRecords []PromoCodeUsage
 }
@@ -29,6 +42,16 @@ type PromoCodeUsage struct {
 	BonusAmount float64
 	UsedAt      time.Time
 
+	BonusType      string
+	BonusPercent   float64
+	MaxBonusAmount float64
+	Status         string // applied / pending
+	Source         string // register / panel
+	// 比例赠送：触发的充值金额与兑换码
+	BaseAmount   float64
+	RedeemCodeID *int64
+	AppliedAt    *time.Time
+
 	// 关联
 	PromoCode *PromoCode
 	User      *User
@@ -42,6 +65,9 @@ func (p *PromoCode) CanUse() bool {
 	if p.ExpiresAt != nil && time.Now().After(*p.ExpiresAt) {
 		return false
 	}
+	if !p.IsStarted() {
+		return false
+	}
 	if p.MaxUses > 0 && p.UsedCount >= p.MaxUses {
 		return false
 	}
@@ -53,6 +79,23 @@ func (p *PromoCode) IsExpired() bool {
 	return p.ExpiresAt != nil && time.Now().After(*p.ExpiresAt)
 }
 
+// IsStarted 检查是否已到生效时间
+func (p *PromoCode) IsStarted() bool {
+	return p.StartsAt == nil || !time.Now().Before(*p.StartsAt)
+}
+
+// AllowsSource 检查优惠码是否可在指定场景（register / panel）使用
+func (p *PromoCode) AllowsSource(source string) bool {
+	switch p.Scope {
+	case PromoScopeAny:
+		return true
+	case PromoScopePanel:
+		return source == PromoScopePanel
+	default:
+		return source == PromoScopeRegister
+	}
+}
+
 // CreatePromoCodeInput 创建优惠码输入
 type CreatePromoCodeInput struct {
 	Code        string
@@ -60,6 +103,16 @@ type CreatePromoCodeInput struct {
 	MaxUses     int
 	ExpiresAt   *time.Time
 	Notes       string
+
+	BonusType       string
+	BonusPercent    float64
+	MaxBonusAmount  float64
+	PerUserLimit    *int
+	Scope           string
+	NewUsersOnly    bool
+	NewUserDays     int
+	RequiredGroupID *int64
+	StartsAt        *time.Time
 }
 
 // UpdatePromoCodeInput 更新优惠码输入
@@ -70,4 +123,16 @@ type UpdatePromoCodeInput struct {
 	Status      *string
 	ExpiresAt   *time.Time
 	Notes       *string
+
+	BonusType      *string
+	BonusPercent   *float64
+	MaxBonusAmount *float64
+	PerUserLimit   *int
+	Scope          *string
+	NewUsersOnly   *bool
+	NewUserDays    *int
+	// RequiredGroupID 为 0 表示清除分组要求
+	RequiredGroupID *int64
+	StartsAt        *time.Time
+	ClearStartsAt   bool
 }
@@ -22,8 +22,13 @@ type PromoCodeRepository interface {
 
 	// 使用记录
 	CreateUsage(ctx context.Context, usage *PromoCodeUsage) error
-	GetUsageByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (*PromoCodeUsage, error)
+	UpdateUsage(ctx context.Context, usage *PromoCodeUsage) error
+	CountUsagesByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (int, error)
+	// GetPendingUsageForUpdate 返回用户待发放的比例赠送（带行锁）；没有时返回 nil, nil
+	GetPendingUsageForUpdate(ctx context.Context, userID int64) (*PromoCodeUsage, error)
 	ListUsagesByPromoCode(ctx context.Context, promoCodeID int64, params pagination.PaginationParams) ([]PromoCodeUsage, *pagination.PaginationResult, error)
+	// ListUsagesByUser 返回用户的使用记录（带优惠码信息），status 为空表示全部
+	ListUsagesByUser(ctx context.Context, userID int64, status string, params pagination.PaginationParams) ([]PromoCodeUsage, *pagination.PaginationResult, error)
 
 	// 计数操作
 	IncrementUsedCount(ctx context.Context, id int64) error
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -20,8 +22,18 @@ var (
 	ErrPromoCodeMaxUsed     = infraerrors.BadRequest("PROMO_CODE_MAX_USED", "promo code has reached maximum uses")
 	ErrPromoCodeAlreadyUsed = infraerrors.Conflict("PROMO_CODE_ALREADY_USED", "you have already used this promo code")
 	ErrPromoCodeInvalid     = infraerrors.BadRequest("PROMO_CODE_INVALID", "invalid promo code")
+
+	ErrPromoCodeNotStarted    = infraerrors.BadRequest("PROMO_CODE_NOT_STARTED", "promo code is not active yet")
+	ErrPromoCodeScope         = infraerrors.BadRequest("PROMO_CODE_SCOPE", "promo code cannot be used here")
+	ErrPromoCodeNewUsersOnly  = infraerrors.Forbidden("PROMO_CODE_NEW_USERS_ONLY", "promo code is only available to new users")
+	ErrPromoCodeGroupRequired = infraerrors.Forbidden("PROMO_CODE_GROUP_REQUIRED", "promo code requires an active subscription to a specific group")
+	ErrPromoCodeBonusPending  = infraerrors.Conflict("PROMO_CODE_BONUS_PENDING", "you already have a pending top-up bonus")
+	ErrPromoCodeConfigInvalid = infraerrors.BadRequest("PROMO_CODE_CONFIG_INVALID", "invalid promo code configuration")
 )
 
+// promoMaxBonusPercent 比例赠送的最大百分比
+const promoMaxBonusPercent = 1000
+
 // PromoService 优惠码服务
 type PromoService struct {
 	promoRepo            PromoCodeRepository
@@ -29,6 +41,7 @@ type PromoService struct {
 	billingCacheService  *BillingCacheService
 	entClient            *dbent.Client
 	authCacheInvalidator APIKeyAuthCacheInvalidator
+	userSubRepo          UserSubscriptionRepository
 }
 
 // NewPromoService 创建优惠码服务实例
@@ -38,10 +51,12 @@ func NewPromoService(
 	billingCacheService *BillingCacheService,
 	entClient *dbent.Client,
 	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	userSubRepo UserSubscriptionRepository,
 ) *PromoService {
 	return &PromoService{
 		promoRepo:            promoRepo,
 		userRepo:             userRepo,
+		userSubRepo:          userSubRepo,
 		billingCacheService:  billingCacheService,
 		entClient:            entClient,
 		authCacheInvalidator: authCacheInvalidator,
@@ -66,6 +81,14 @@ func (s *PromoService) ValidatePromoCode(ctx context.Context, code string) (*Pro
 		return nil, err
 	}
 
+	// 注册前尚无订阅，要求分组的优惠码无法在注册时使用
+	if !promoCode.AllowsSource(PromoScopeRegister) {
+		return nil, ErrPromoCodeScope
+	}
+	if promoCode.RequiredGroupID != nil {
+		return nil, ErrPromoCodeGroupRequired
+	}
+
 	return promoCode, nil
 }
 
@@ -78,6 +101,9 @@ func (s *PromoService) validatePromoCodeStatus(promoCode *PromoCode) error {
 		if promoCode.Status == PromoCodeStatusDisabled {
 			return ErrPromoCodeDisabled
 		}
+		if !promoCode.IsStarted() {
+			return ErrPromoCodeNotStarted
+		}
 		if promoCode.MaxUses > 0 && promoCode.UsedCount >= promoCode.MaxUses {
 			return ErrPromoCodeMaxUsed
 		}
@@ -86,18 +112,76 @@ func (s *PromoService) validatePromoCodeStatus(promoCode *PromoCode) error {
 	return nil
 }
 
+// checkPromoEligibility 检查用户是否满足优惠码的使用条件（分组要求除外）
+func checkPromoEligibility(promoCode *PromoCode, user *User, source string, now time.Time) error {
+	if !promoCode.AllowsSource(source) {
+		return ErrPromoCodeScope
+	}
+	if promoCode.NewUsersOnly && source != PromoScopeRegister {
+		// 面板使用时，仅注册后 NewUserDays 天内的用户可用
+		if promoCode.NewUserDays <= 0 || now.Sub(user.CreatedAt) > time.Duration(promoCode.NewUserDays)*24*time.Hour {
+			return ErrPromoCodeNewUsersOnly
+		}
+	}
+	return nil
+}
+
+// calculatePercentBonus 计算比例赠送金额，maxBonus 为 0 表示不限
+func calculatePercentBonus(baseAmount, percent, maxBonus float64) float64 {
+	if baseAmount <= 0 || percent <= 0 {
+		return 0
+	}
+	bonus := math.Round(baseAmount*percent/100*1e8) / 1e8
+	if maxBonus > 0 && bonus > maxBonus {
+		bonus = maxBonus
+	}
+	return bonus
+}
+
+// checkRequiredGroup 检查用户是否持有优惠码要求的有效订阅
+func (s *PromoService) checkRequiredGroup(ctx context.Context, promoCode *PromoCode, userID int64) error {
+	if promoCode.RequiredGroupID == nil {
+		return nil
+	}
+	if s.userSubRepo == nil {
+		return ErrPromoCodeGroupRequired
+	}
+	if _, err := s.userSubRepo.GetActiveByUserIDAndGroupID(ctx, userID, *promoCode.RequiredGroupID); err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return ErrPromoCodeGroupRequired
+		}
+		return fmt.Errorf("check required subscription: %w", err)
+	}
+	return nil
+}
+
 // ApplyPromoCode 应用优惠码（注册成功后调用）
 // 使用事务和行锁确保并发安全
 func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code string) error {
+	_, err := s.usePromoCode(ctx, userID, code, PromoScopeRegister)
+	return err
+}
+
+// RedeemPromoCode 用户在面板中使用优惠码
+func (s *PromoService) RedeemPromoCode(ctx context.Context, userID int64, code string) (*PromoCodeUsage, error) {
+	if strings.TrimSpace(code) == "" {
+		return nil, ErrPromoCodeInvalid
+	}
+	return s.usePromoCode(ctx, userID, code, PromoScopePanel)
+}
+
+// usePromoCode 在事务中校验并使用优惠码：
+// 固定金额立即发放；比例赠送记录为待发放，在下次余额充值时发放
+func (s *PromoService) usePromoCode(ctx context.Context, userID int64, code, source string) (*PromoCodeUsage, error) {
 	code = strings.TrimSpace(code)
 	if code == "" {
-		return nil
+		return nil, nil
 	}
 
 	// 开启事务
 	tx, err := s.entClient.Tx(ctx)
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return nil, fmt.Errorf("begin transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
@@ -106,49 +190,89 @@ func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code st
 	// 在事务中获取并锁定优惠码记录（FOR UPDATE）
 	promoCode, err := s.promoRepo.GetByCodeForUpdate(txCtx, code)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 在事务中验证优惠码状态
 	if err := s.validatePromoCodeStatus(promoCode); err != nil {
-		return err
+		return nil, err
 	}
 
-	// 在事务中检查用户是否已使用过此优惠码
-	existing, err := s.promoRepo.GetUsageByPromoCodeAndUser(txCtx, promoCode.ID, userID)
+	user, err := s.userRepo.GetByID(txCtx, userID)
 	if err != nil {
-		return fmt.Errorf("check existing usage: %w", err)
+		return nil, fmt.Errorf("get user: %w", err)
 	}
-	if existing != nil {
-		return ErrPromoCodeAlreadyUsed
+	if err := checkPromoEligibility(promoCode, user, source, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkRequiredGroup(txCtx, promoCode, userID); err != nil {
+		return nil, err
 	}
 
-	// 增加用户余额
-	if err := s.userRepo.UpdateBalance(txCtx, userID, promoCode.BonusAmount); err != nil {
-		return fmt.Errorf("update user balance: %w", err)
+	// 在事务中检查用户使用次数
+	if promoCode.PerUserLimit > 0 {
+		used, err := s.promoRepo.CountUsagesByPromoCodeAndUser(txCtx, promoCode.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("check existing usage: %w", err)
+		}
+		if used >= promoCode.PerUserLimit {
+			return nil, ErrPromoCodeAlreadyUsed
+		}
 	}
 
-	// 创建使用记录
+	now := time.Now()
 	usage := &PromoCodeUsage{
-		PromoCodeID: promoCode.ID,
-		UserID:      userID,
-		BonusAmount: promoCode.BonusAmount,
-		UsedAt:      time.Now(),
+		PromoCodeID:    promoCode.ID,
+		UserID:         userID,
+		BonusType:      promoCode.BonusType,
+		BonusPercent:   promoCode.BonusPercent,
+		MaxBonusAmount: promoCode.MaxBonusAmount,
+		Source:         source,
+		UsedAt:         now,
+	}
+
+	if promoCode.BonusType == PromoBonusTypePercent {
+		// 同一时间只允许一个待发放的比例赠送
+		pending, err := s.promoRepo.GetPendingUsageForUpdate(txCtx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("check pending bonus: %w", err)
+		}
+		if pending != nil {
+			return nil, ErrPromoCodeBonusPending
+		}
+		usage.Status = PromoUsageStatusPending
+	} else {
+		usage.BonusType = PromoBonusTypeFixed
+		usage.BonusAmount = promoCode.BonusAmount
+		usage.Status = PromoUsageStatusApplied
+		usage.AppliedAt = &now
+
+		// 增加用户余额
+		if err := s.userRepo.UpdateBalance(txCtx, userID, promoCode.BonusAmount); err != nil {
+			return nil, fmt.Errorf("update user balance: %w", err)
+		}
 	}
+
+	// 创建使用记录
 	if err := s.promoRepo.CreateUsage(txCtx, usage); err != nil {
-		return fmt.Errorf("create usage record: %w", err)
+		return nil, fmt.Errorf("create usage record: %w", err)
 	}
 
 	// 增加使用次数
 	if err := s.promoRepo.IncrementUsedCount(txCtx, promoCode.ID); err != nil {
-		return fmt.Errorf("increment used count: %w", err)
+		return nil, fmt.Errorf("increment used count: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
 
-	s.invalidatePromoCaches(ctx, userID, promoCode.BonusAmount)
+	usage.PromoCode = promoCode
+	if usage.Status != PromoUsageStatusApplied {
+		return usage, nil
+	}
+
+	s.invalidatePromoCaches(ctx, userID, usage.BonusAmount)
 
 	// 失效余额缓存
 	if s.billingCacheService != nil {
@@ -159,7 +283,42 @@ func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code st
 		}()
 	}
 
-	return nil
+	return usage, nil
+}
+
+// ApplyTopUpBonus 在余额充值时发放待发放的比例赠送，返回赠送金额
+// 必须在充值事务的 context 中调用，缓存由调用方在提交后失效
+func (s *PromoService) ApplyTopUpBonus(txCtx context.Context, userID, redeemCodeID int64, amount float64) (float64, error) {
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	pending, err := s.promoRepo.GetPendingUsageForUpdate(txCtx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("get pending bonus: %w", err)
+	}
+	if pending == nil {
+		return 0, nil
+	}
+
+	bonus := calculatePercentBonus(amount, pending.BonusPercent, pending.MaxBonusAmount)
+	if bonus > 0 {
+		if err := s.userRepo.UpdateBalance(txCtx, userID, bonus); err != nil {
+			return 0, fmt.Errorf("update user balance: %w", err)
+		}
+	}
+
+	now := time.Now()
+	pending.BonusAmount = bonus
+	pending.BaseAmount = amount
+	pending.Status = PromoUsageStatusApplied
+	pending.RedeemCodeID = &redeemCodeID
+	pending.AppliedAt = &now
+	if err := s.promoRepo.UpdateUsage(txCtx, pending); err != nil {
+		return 0, fmt.Errorf("update usage record: %w", err)
+	}
+
+	return bonus, nil
 }
 
 func (s *PromoService) invalidatePromoCaches(ctx context.Context, userID int64, bonusAmount float64) {
@@ -198,6 +357,28 @@ func (s *PromoService) Create(ctx context.Context, input *CreatePromoCodeInput)
 		Status:      PromoCodeStatusActive,
 		ExpiresAt:   input.ExpiresAt,
 		Notes:       input.Notes,
+
+		BonusType:       input.BonusType,
+		BonusPercent:    input.BonusPercent,
+		MaxBonusAmount:  input.MaxBonusAmount,
+		PerUserLimit:    1,
+		Scope:           input.Scope,
+		NewUsersOnly:    input.NewUsersOnly,
+		NewUserDays:     input.NewUserDays,
+		RequiredGroupID: input.RequiredGroupID,
+		StartsAt:        input.StartsAt,
+	}
+	if input.PerUserLimit != nil {
+		promoCode.PerUserLimit = *input.PerUserLimit
+	}
+	if promoCode.BonusType == "" {
+		promoCode.BonusType = PromoBonusTypeFixed
+	}
+	if promoCode.Scope == "" {
+		promoCode.Scope = PromoScopeRegister
+	}
+	if err := validatePromoCodeConfig(promoCode); err != nil {
+		return nil, err
 	}
 
 	if err := s.promoRepo.Create(ctx, promoCode); err != nil {
@@ -207,6 +388,46 @@ func (s *PromoService) Create(ctx context.Context, input *CreatePromoCodeInput)
 	return promoCode, nil
 }
 
+// validatePromoCodeConfig 校验优惠码配置
+func validatePromoCodeConfig(p *PromoCode) error {
+	switch p.BonusType {
+	case PromoBonusTypeFixed:
+		if p.BonusAmount < 0 {
+			return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "bonus_amount"})
+		}
+	case PromoBonusTypePercent:
+		if p.BonusPercent <= 0 || p.BonusPercent > promoMaxBonusPercent {
+			return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "bonus_percent"})
+		}
+		if p.MaxBonusAmount < 0 {
+			return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "max_bonus_amount"})
+		}
+	default:
+		return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "bonus_type"})
+	}
+
+	switch p.Scope {
+	case PromoScopeRegister, PromoScopePanel, PromoScopeAny:
+	default:
+		return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "scope"})
+	}
+
+	if p.PerUserLimit < 0 {
+		return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "per_user_limit"})
+	}
+	if p.NewUserDays < 0 {
+		return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "new_user_days"})
+	}
+	// 仅新用户 + 仅面板使用时必须指定天数，否则永远无法使用
+	if p.NewUsersOnly && p.Scope == PromoScopePanel && p.NewUserDays == 0 {
+		return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "new_user_days"})
+	}
+	if p.StartsAt != nil && p.ExpiresAt != nil && !p.ExpiresAt.After(*p.StartsAt) {
+		return ErrPromoCodeConfigInvalid.WithMetadata(map[string]string{"field": "expires_at"})
+	}
+	return nil
+}
+
 // GetByID 根据ID获取优惠码
 func (s *PromoService) GetByID(ctx context.Context, id int64) (*PromoCode, error) {
 	code, err := s.promoRepo.GetByID(ctx, id)
@@ -241,6 +462,42 @@ func (s *PromoService) Update(ctx context.Context, id int64, input *UpdatePromoC
 	if input.Notes != nil {
 		promoCode.Notes = *input.Notes
 	}
+	if input.BonusType != nil {
+		promoCode.BonusType = *input.BonusType
+	}
+	if input.BonusPercent != nil {
+		promoCode.BonusPercent = *input.BonusPercent
+	}
+	if input.MaxBonusAmount != nil {
+		promoCode.MaxBonusAmount = *input.MaxBonusAmount
+	}
+	if input.PerUserLimit != nil {
+		promoCode.PerUserLimit = *input.PerUserLimit
+	}
+	if input.Scope != nil {
+		promoCode.Scope = *input.Scope
+	}
+	if input.NewUsersOnly != nil {
+		promoCode.NewUsersOnly = *input.NewUsersOnly
+	}
+	if input.NewUserDays != nil {
+		promoCode.NewUserDays = *input.NewUserDays
+	}
+	if input.RequiredGroupID != nil {
+		if *input.RequiredGroupID > 0 {
+			promoCode.RequiredGroupID = input.RequiredGroupID
+		} else {
+			promoCode.RequiredGroupID = nil
+		}
+	}
+	if input.ClearStartsAt {
+		promoCode.StartsAt = nil
+	} else if input.StartsAt != nil {
+		promoCode.StartsAt = input.StartsAt
+	}
+	if err := validatePromoCodeConfig(promoCode); err != nil {
+		return nil, err
+	}
 
 	if err := s.promoRepo.Update(ctx, promoCode); err != nil {
 		return nil, fmt.Errorf("update promo code: %w", err)
@@ -266,3 +523,20 @@ func (s *PromoService) List(ctx context.Context, params pagination.PaginationPar
 func (s *PromoService) ListUsages(ctx context.Context, promoCodeID int64, params pagination.PaginationParams) ([]PromoCodeUsage, *pagination.PaginationResult, error) {
 	return s.promoRepo.ListUsagesByPromoCode(ctx, promoCodeID, params)
 }
+
+// ListMyUsages 获取用户自己的优惠码使用记录
+func (s *PromoService) ListMyUsages(ctx context.Context, userID int64, params pagination.PaginationParams) ([]PromoCodeUsage, *pagination.PaginationResult, error) {
+	return s.promoRepo.ListUsagesByUser(ctx, userID, "", params)
+}
+
+// GetPendingBonus 获取用户待发放的比例赠送，没有时返回 nil
+func (s *PromoService) GetPendingBonus(ctx context.Context, userID int64) (*PromoCodeUsage, error) {
+	usages, _, err := s.promoRepo.ListUsagesByUser(ctx, userID, PromoUsageStatusPending, pagination.PaginationParams{Page: 1, PageSize: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(usages) == 0 {
+		return nil, nil
+	}
+	return &usages[0], nil
+}
@@ -0,0 +1,155 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type promoRepoStub struct {
+	PromoCodeRepository
+
+	codes   map[string]*PromoCode
+	pending *PromoCodeUsage
+	updated []PromoCodeUsage
+}
+
+func (s *promoRepoStub) GetByCode(ctx context.Context, code string) (*PromoCode, error) {
+	if c, ok := s.codes[code]; ok {
+		return c, nil
+	}
+	return nil, ErrPromoCodeNotFound
+}
+
+func (s *promoRepoStub) GetPendingUsageForUpdate(ctx context.Context, userID int64) (*PromoCodeUsage, error) {
+	if s.pending == nil || s.pending.UserID != userID {
+		return nil, nil
+	}
+	copied := *s.pending
+	return &copied, nil
+}
+
+func (s *promoRepoStub) UpdateUsage(ctx context.Context, usage *PromoCodeUsage) error {
+	s.updated = append(s.updated, *usage)
+	return nil
+}
+
+type promoUserRepoStub struct {
+	UserRepository
+
+	balances map[int64]float64
+}
+
+func (s *promoUserRepoStub) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+	s.balances[id] += amount
+	return nil
+}
+
+func TestPromoCode_AllowsSource(t *testing.T) {
+	register := &PromoCode{Scope: PromoScopeRegister}
+	require.True(t, register.AllowsSource(PromoScopeRegister))
+	require.False(t, register.AllowsSource(PromoScopePanel))
+
+	panel := &PromoCode{Scope: PromoScopePanel}
+	require.False(t, panel.AllowsSource(PromoScopeRegister))
+	require.True(t, panel.AllowsSource(PromoScopePanel))
+
+	anyScope := &PromoCode{Scope: PromoScopeAny}
+	require.True(t, anyScope.AllowsSource(PromoScopeRegister))
+	require.True(t, anyScope.AllowsSource(PromoScopePanel))
+}
+
+func TestCheckPromoEligibility_NewUsersOnly(t *testing.T) {
+	now := time.Now()
+	code := &PromoCode{Scope: PromoScopeAny, NewUsersOnly: true, NewUserDays: 7}
+
+	require.NoError(t, checkPromoEligibility(code, &User{CreatedAt: now.Add(-30 * 24 * time.Hour)}, PromoScopeRegister, now))
+	require.NoError(t, checkPromoEligibility(code, &User{CreatedAt: now.Add(-6 * 24 * time.Hour)}, PromoScopePanel, now))
+	require.ErrorIs(t, checkPromoEligibility(code, &User{CreatedAt: now.Add(-8 * 24 * time.Hour)}, PromoScopePanel, now), ErrPromoCodeNewUsersOnly)
+
+	code.NewUserDays = 0
+	require.ErrorIs(t, checkPromoEligibility(code, &User{CreatedAt: now}, PromoScopePanel, now), ErrPromoCodeNewUsersOnly)
+
+	code.Scope = PromoScopeRegister
+	require.ErrorIs(t, checkPromoEligibility(code, &User{CreatedAt: now}, PromoScopePanel, now), ErrPromoCodeScope)
+}
+
+func TestCalculatePercentBonus(t *testing.T) {
+	require.InDelta(t, 2.5, calculatePercentBonus(25, 10, 0), 1e-9)
+	require.InDelta(t, 3, calculatePercentBonus(100, 10, 3), 1e-9)
+	require.Zero(t, calculatePercentBonus(0, 10, 0))
+	require.Zero(t, calculatePercentBonus(100, 0, 0))
+}
+
+func TestValidatePromoCodeConfig(t *testing.T) {
+	valid := &PromoCode{BonusType: PromoBonusTypePercent, BonusPercent: 20, Scope: PromoScopeAny, PerUserLimit: 1}
+	require.NoError(t, validatePromoCodeConfig(valid))
+
+	cases := []*PromoCode{
+		{BonusType: "bogus", Scope: PromoScopeRegister},
+		{BonusType: PromoBonusTypePercent, BonusPercent: 0, Scope: PromoScopeRegister},
+		{BonusType: PromoBonusTypePercent, BonusPercent: promoMaxBonusPercent + 1, Scope: PromoScopeRegister},
+		{BonusType: PromoBonusTypeFixed, BonusAmount: 1, Scope: "everywhere"},
+		{BonusType: PromoBonusTypeFixed, BonusAmount: 1, Scope: PromoScopeRegister, PerUserLimit: -1},
+		{BonusType: PromoBonusTypeFixed, BonusAmount: 1, Scope: PromoScopePanel, NewUsersOnly: true},
+	}
+	for _, c := range cases {
+		require.ErrorIs(t, validatePromoCodeConfig(c), ErrPromoCodeConfigInvalid)
+	}
+}
+
+func TestPromoService_ValidatePromoCodeRejectsPanelOnlyAndGroupCodes(t *testing.T) {
+	groupID := int64(3)
+	repo := &promoRepoStub{codes: map[string]*PromoCode{
+		"PANEL": {Code: "PANEL", Status: PromoCodeStatusActive, Scope: PromoScopePanel},
+		"GROUP": {Code: "GROUP", Status: PromoCodeStatusActive, Scope: PromoScopeAny, RequiredGroupID: &groupID},
+		"OK":    {Code: "OK", Status: PromoCodeStatusActive, Scope: PromoScopeRegister, BonusAmount: 5},
+	}}
+	svc := NewPromoService(repo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := svc.ValidatePromoCode(ctx, "PANEL")
+	require.ErrorIs(t, err, ErrPromoCodeScope)
+	_, err = svc.ValidatePromoCode(ctx, "GROUP")
+	require.ErrorIs(t, err, ErrPromoCodeGroupRequired)
+
+	code, err := svc.ValidatePromoCode(ctx, "OK")
+	require.NoError(t, err)
+	require.Equal(t, 5.0, code.BonusAmount)
+}
+
+func TestPromoService_ApplyTopUpBonus(t *testing.T) {
+	repo := &promoRepoStub{pending: &PromoCodeUsage{
+		ID:             9,
+		UserID:         1,
+		BonusType:      PromoBonusTypePercent,
+		BonusPercent:   20,
+		MaxBonusAmount: 5,
+		Status:         PromoUsageStatusPending,
+	}}
+	users := &promoUserRepoStub{balances: map[int64]float64{}}
+	svc := NewPromoService(repo, users, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	// 其他用户没有待发放赠送
+	bonus, err := svc.ApplyTopUpBonus(ctx, 2, 100, 50)
+	require.NoError(t, err)
+	require.Zero(t, bonus)
+
+	bonus, err = svc.ApplyTopUpBonus(ctx, 1, 100, 50)
+	require.NoError(t, err)
+	require.InDelta(t, 5, bonus, 1e-9)
+	require.InDelta(t, 5, users.balances[1], 1e-9)
+
+	require.Len(t, repo.updated, 1)
+	applied := repo.updated[0]
+	require.Equal(t, PromoUsageStatusApplied, applied.Status)
+	require.Equal(t, 50.0, applied.BaseAmount)
+	require.NotNil(t, applied.RedeemCodeID)
+	require.Equal(t, int64(100), *applied.RedeemCodeID)
+	require.NotNil(t, applied.AppliedAt)
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+)
+
+// 预测性限流规避
+//
+// API Key 账号的上游在每个响应中返回限流余量（Anthropic: anthropic-ratelimit-*，OpenAI: x-ratelimit-*）。
+// 成功响应后将余量写入 Redis，调度时跳过剩余额度不足以承载本次请求的账号，从而在触发 429 之前切换账号。
+// 余量按 limit/reset 线性回补估算；所有候选账号额度都不足时不做过滤（仅降低优先级，不拒绝请求）。
+
+const (
+	rateLimitBudgetMinTTL = time.Second
+	rateLimitBudgetMaxTTL = 10 * time.Minute
+)
+
+// RateLimitBudgetCache 账号上游限流余量缓存接口
+type RateLimitBudgetCache interface {
+	SetRateLimitBudget(ctx context.Context, accountID int64, budget *RateLimitBudget, ttl time.Duration) error
+	// GetRateLimitBudgets 批量获取账号余量，未缓存的账号不包含在结果中
+	GetRateLimitBudgets(ctx context.Context, accountIDs []int64) (map[int64]*RateLimitBudget, error)
+}
+
+// RateLimitBudgetDimension 单个限流维度（请求数 / token 数）的余量
+type RateLimitBudgetDimension struct {
+	Limit       int64 `json:"limit,omitempty"`
+	Remaining   int64 `json:"remaining"`
+	ResetUnixMs int64 `json:"reset_unix_ms,omitempty"`
+}
+
+// RateLimitBudget 上游响应头中解析出的账号限流余量
+type RateLimitBudget struct {
+	Requests     *RateLimitBudgetDimension `json:"requests,omitempty"`
+	Tokens       *RateLimitBudgetDimension `json:"tokens,omitempty"`
+	InputTokens  *RateLimitBudgetDimension `json:"input_tokens,omitempty"`
+	OutputTokens *RateLimitBudgetDimension `json:"output_tokens,omitempty"`
+	// ObservedUnixMs 余量的观测时间，用于估算回补
+	ObservedUnixMs int64 `json:"observed_unix_ms"`
+}
+
+// RateLimitCostEstimate 单次请求的预估消耗，由 Handler 写入 context（ctxkey.RateLimitCostEstimate）
+type RateLimitCostEstimate struct {
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// EstimateRateLimitCost 按请求体大小（约 4 字节/token）和 max_tokens 粗略估算请求消耗
+func EstimateRateLimitCost(bodyBytes int, maxTokens int) RateLimitCostEstimate {
+	est := RateLimitCostEstimate{InputTokens: int64(bodyBytes / 4)}
+	if maxTokens > 0 {
+		est.OutputTokens = int64(maxTokens)
+	}
+	return est
+}
+
+func rateLimitCostFromContext(ctx context.Context) RateLimitCostEstimate {
+	if est, ok := ctx.Value(ctxkey.RateLimitCostEstimate).(RateLimitCostEstimate); ok {
+		return est
+	}
+	return RateLimitCostEstimate{}
+}
+
+// available 估算 now 时刻的可用余量：reset 之前按线性回补，reset 之后视为完全回补
+func (d *RateLimitBudgetDimension) available(observedUnixMs int64, now time.Time) int64 {
+	nowMs := now.UnixMilli()
+	if d.ResetUnixMs <= 0 {
+		return d.Remaining
+	}
+	if nowMs >= d.ResetUnixMs {
+		return math.MaxInt64
+	}
+	if d.Limit > d.Remaining && d.ResetUnixMs > observedUnixMs && nowMs > observedUnixMs {
+		refill := float64(d.Limit-d.Remaining) * float64(nowMs-observedUnixMs) / float64(d.ResetUnixMs-observedUnixMs)
+		return d.Remaining + int64(refill)
+	}
+	return d.Remaining
+}
+
+// Covers 判断余量是否足以承载一次预估消耗为 cost 的请求
+func (b *RateLimitBudget) Covers(cost RateLimitCostEstimate, now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	check := func(d *RateLimitBudgetDimension, need int64) bool {
+		if d == nil {
+			return true
+		}
+		if need < 1 {
+			need = 1
+		}
+		return d.available(b.ObservedUnixMs, now) >= need
+	}
+	return check(b.Requests, 1) &&
+		check(b.Tokens, cost.InputTokens+cost.OutputTokens) &&
+		check(b.InputTokens, cost.InputTokens) &&
+		check(b.OutputTokens, cost.OutputTokens)
+}
+
+// ttl 余量在最晚的 reset 时间后失效
+func (b *RateLimitBudget) ttl(now time.Time) time.Duration {
+	var latest int64
+	for _, d := range []*RateLimitBudgetDimension{b.Requests, b.Tokens, b.InputTokens, b.OutputTokens} {
+		if d != nil && d.ResetUnixMs > latest {
+			latest = d.ResetUnixMs
+		}
+	}
+	ttl := time.Duration(latest-now.UnixMilli()) * time.Millisecond
+	if latest == 0 {
+		ttl = time.Minute
+	}
+	if ttl < rateLimitBudgetMinTTL {
+		ttl = rateLimitBudgetMinTTL
+	}
+	if ttl > rateLimitBudgetMaxTTL {
+		ttl = rateLimitBudgetMaxTTL
+	}
+	return ttl
+}
+
+// ParseAnthropicRateLimitBudget 解析 anthropic-ratelimit-{requests,tokens,input-tokens,output-tokens}-{limit,remaining,reset}
+// reset 为 RFC 3339 时间
+func ParseAnthropicRateLimitBudget(headers http.Header, now time.Time) *RateLimitBudget {
+	parse := func(name string) *RateLimitBudgetDimension {
+		prefix := "anthropic-ratelimit-" + name + "-"
+		remaining, ok := parseRateLimitHeaderInt(headers.Get(prefix + "remaining"))
+		if !ok {
+			return nil
+		}
+		d := &RateLimitBudgetDimension{Remaining: remaining}
+		d.Limit, _ = parseRateLimitHeaderInt(headers.Get(prefix + "limit"))
+		if reset := strings.TrimSpace(headers.Get(prefix + "reset")); reset != "" {
+			if t, err := time.Parse(time.RFC3339, reset); err == nil {
+				d.ResetUnixMs = t.UnixMilli()
+			}
+		}
+		return d
+	}
+	return newRateLimitBudget(now, parse("requests"), parse("tokens"), parse("input-tokens"), parse("output-tokens"))
+}
+
+// ParseOpenAIRateLimitBudget 解析 x-ratelimit-{limit,remaining,reset}-{requests,tokens}
+// reset 为相对时长，例如 "1s"、"6m0s"、"20ms"
+func ParseOpenAIRateLimitBudget(headers http.Header, now time.Time) *RateLimitBudget {
+	parse := func(name string) *RateLimitBudgetDimension {
+		remaining, ok := parseRateLimitHeaderInt(headers.Get("x-ratelimit-remaining-" + name))
+		if !ok {
+			return nil
+		}
+		d := &RateLimitBudgetDimension{Remaining: remaining}
+		d.Limit, _ = parseRateLimitHeaderInt(headers.Get("x-ratelimit-limit-" + name))
+		if reset := strings.TrimSpace(headers.Get("x-ratelimit-reset-" + name)); reset != "" {
+			if dur, err := time.ParseDuration(reset); err == nil && dur >= 0 {
+				d.ResetUnixMs = now.Add(dur).UnixMilli()
+			}
+		}
+		return d
+	}
+	return newRateLimitBudget(now, parse("requests"), parse("tokens"), nil, nil)
+}
+
+func newRateLimitBudget(now time.Time, requests, tokens, inputTokens, outputTokens *RateLimitBudgetDimension) *RateLimitBudget {
+	if requests == nil && tokens == nil && inputTokens == nil && outputTokens == nil {
+		return nil
+	}
+	return &RateLimitBudget{
+		Requests:       requests,
+		Tokens:         tokens,
+		InputTokens:    inputTokens,
+		OutputTokens:   outputTokens,
+		ObservedUnixMs: now.UnixMilli(),
+	}
+}
+
+func parseRateLimitHeaderInt(v string) (int64, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// SetRateLimitBudgetCache 设置限流余量缓存（可选依赖）
+func (s *RateLimitService) SetRateLimitBudgetCache(cache RateLimitBudgetCache) {
+	s.budgetCache = cache
+}
+
+// UpdateRateLimitBudget 从上游响应头更新 API Key 账号的限流余量
+func (s *RateLimitService) UpdateRateLimitBudget(ctx context.Context, account *Account, headers http.Header) {
+	if s == nil || s.budgetCache == nil || account == nil || account.Type != AccountTypeAPIKey {
+		return
+	}
+	now := time.Now()
+	var budget *RateLimitBudget
+	switch account.Platform {
+	case PlatformAnthropic:
+		budget = ParseAnthropicRateLimitBudget(headers, now)
+	case PlatformOpenAI:
+		budget = ParseOpenAIRateLimitBudget(headers, now)
+	}
+	if budget == nil {
+		return
+	}
+	if err := s.budgetCache.SetRateLimitBudget(ctx, account.ID, budget, budget.ttl(now)); err != nil {
+		slog.Warn("rate_limit_budget_update_failed", "account_id", account.ID, "error", err)
+	}
+}
+
+// FilterByRateLimitBudget 过滤掉上游余量不足以承载本次请求的 API Key 账号。
+// 所有账号余量都不足时返回原列表：余量仅为预测，不应导致请求直接失败。
+func (s *RateLimitService) FilterByRateLimitBudget(ctx context.Context, accounts []Account) []Account {
+	if s == nil || s.budgetCache == nil || len(accounts) == 0 {
+		return accounts
+	}
+	ids := make([]int64, 0, len(accounts))
+	for i := range accounts {
+		if accounts[i].Type == AccountTypeAPIKey {
+			ids = append(ids, accounts[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return accounts
+	}
+	budgets, err := s.budgetCache.GetRateLimitBudgets(ctx, ids)
+	if err != nil || len(budgets) == 0 {
+		return accounts
+	}
+
+	cost := rateLimitCostFromContext(ctx)
+	now := time.Now()
+	filtered := accounts[:0:0]
+	for i := range accounts {
+		if budget, ok := budgets[accounts[i].ID]; ok && !budget.Covers(cost, now) {
+			continue
+		}
+		filtered = append(filtered, accounts[i])
+	}
+	if len(filtered) == 0 {
+		return accounts
+	}
+	return filtered
+}
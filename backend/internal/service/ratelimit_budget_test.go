@@ -0,0 +1,146 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/stretchr/testify/require"
+)
+
+type rateLimitBudgetCacheStub struct {
+	budgets map[int64]*RateLimitBudget
+	ttls    map[int64]time.Duration
+}
+
+func (s *rateLimitBudgetCacheStub) SetRateLimitBudget(_ context.Context, accountID int64, budget *RateLimitBudget, ttl time.Duration) error {
+	if s.budgets == nil {
+		s.budgets = map[int64]*RateLimitBudget{}
+		s.ttls = map[int64]time.Duration{}
+	}
+	s.budgets[accountID] = budget
+	s.ttls[accountID] = ttl
+	return nil
+}
+
+func (s *rateLimitBudgetCacheStub) GetRateLimitBudgets(_ context.Context, accountIDs []int64) (map[int64]*RateLimitBudget, error) {
+	out := map[int64]*RateLimitBudget{}
+	for _, id := range accountIDs {
+		if b, ok := s.budgets[id]; ok {
+			out[id] = b
+		}
+	}
+	return out, nil
+}
+
+func TestParseAnthropicRateLimitBudget(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	h := http.Header{}
+	h.Set("anthropic-ratelimit-requests-limit", "50")
+	h.Set("anthropic-ratelimit-requests-remaining", "3")
+	h.Set("anthropic-ratelimit-requests-reset", "2026-10-14T12:00:30Z")
+	h.Set("anthropic-ratelimit-input-tokens-limit", "40000")
+	h.Set("anthropic-ratelimit-input-tokens-remaining", "1000")
+	h.Set("anthropic-ratelimit-input-tokens-reset", "2026-10-14T12:01:00Z")
+
+	budget := ParseAnthropicRateLimitBudget(h, now)
+	require.NotNil(t, budget)
+	require.Equal(t, &RateLimitBudgetDimension{Limit: 50, Remaining: 3, ResetUnixMs: now.Add(30 * time.Second).UnixMilli()}, budget.Requests)
+	require.Equal(t, int64(1000), budget.InputTokens.Remaining)
+	require.Nil(t, budget.Tokens)
+	require.Nil(t, budget.OutputTokens)
+	require.Equal(t, time.Minute, budget.ttl(now))
+
+	require.Nil(t, ParseAnthropicRateLimitBudget(http.Header{}, now))
+}
+
+func TestParseOpenAIRateLimitBudget(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	h := http.Header{}
+	h.Set("x-ratelimit-limit-requests", "500")
+	h.Set("x-ratelimit-remaining-requests", "499")
+	h.Set("x-ratelimit-reset-requests", "120ms")
+	h.Set("x-ratelimit-limit-tokens", "30000")
+	h.Set("x-ratelimit-remaining-tokens", "100")
+	h.Set("x-ratelimit-reset-tokens", "6m0s")
+
+	budget := ParseOpenAIRateLimitBudget(h, now)
+	require.NotNil(t, budget)
+	require.Equal(t, now.Add(120*time.Millisecond).UnixMilli(), budget.Requests.ResetUnixMs)
+	require.Equal(t, int64(100), budget.Tokens.Remaining)
+	require.Equal(t, now.Add(6*time.Minute).UnixMilli(), budget.Tokens.ResetUnixMs)
+}
+
+func TestRateLimitBudget_CoversWithRefill(t *testing.T) {
+	observed := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	budget := &RateLimitBudget{
+		Tokens: &RateLimitBudgetDimension{
+			Limit:       10000,
+			Remaining:   0,
+			ResetUnixMs: observed.Add(100 * time.Second).UnixMilli(),
+		},
+		ObservedUnixMs: observed.UnixMilli(),
+	}
+	cost := RateLimitCostEstimate{InputTokens: 1500, OutputTokens: 500}
+
+	require.False(t, budget.Covers(cost, observed))
+	require.False(t, budget.Covers(cost, observed.Add(10*time.Second)))
+	// 20 秒后线性回补约 2000 token
+	require.True(t, budget.Covers(cost, observed.Add(20*time.Second)))
+	require.True(t, budget.Covers(cost, observed.Add(101*time.Second)))
+
+	noRequests := &RateLimitBudget{
+		Requests:       &RateLimitBudgetDimension{Remaining: 0, ResetUnixMs: observed.Add(time.Minute).UnixMilli()},
+		ObservedUnixMs: observed.UnixMilli(),
+	}
+	require.False(t, noRequests.Covers(RateLimitCostEstimate{}, observed))
+}
+
+func TestRateLimitService_FilterByRateLimitBudget(t *testing.T) {
+	reset := time.Now().Add(time.Minute).UnixMilli()
+	cache := &rateLimitBudgetCacheStub{budgets: map[int64]*RateLimitBudget{
+		1: {Tokens: &RateLimitBudgetDimension{Remaining: 100, ResetUnixMs: reset}, ObservedUnixMs: time.Now().UnixMilli()},
+		2: {Tokens: &RateLimitBudgetDimension{Remaining: 100000, ResetUnixMs: reset}, ObservedUnixMs: time.Now().UnixMilli()},
+	}}
+	svc := &RateLimitService{}
+	svc.SetRateLimitBudgetCache(cache)
+
+	accounts := []Account{
+		{ID: 1, Type: AccountTypeAPIKey},
+		{ID: 2, Type: AccountTypeAPIKey},
+		{ID: 3, Type: AccountTypeOAuth},
+	}
+	ctx := context.WithValue(context.Background(), ctxkey.RateLimitCostEstimate, EstimateRateLimitCost(4000, 1000))
+
+	got := svc.FilterByRateLimitBudget(ctx, accounts)
+	require.Len(t, got, 2)
+	require.Equal(t, int64(2), got[0].ID)
+	require.Equal(t, int64(3), got[1].ID)
+
+	// 所有账号余量都不足时不过滤
+	got = svc.FilterByRateLimitBudget(ctx, accounts[:1])
+	require.Len(t, got, 1)
+
+	var nilSvc *RateLimitService
+	require.Len(t, nilSvc.FilterByRateLimitBudget(ctx, accounts), 3)
+}
+
+func TestRateLimitService_UpdateRateLimitBudgetOnlyForAPIKeyAccounts(t *testing.T) {
+	cache := &rateLimitBudgetCacheStub{}
+	svc := &RateLimitService{}
+	svc.SetRateLimitBudgetCache(cache)
+
+	h := http.Header{}
+	h.Set("x-ratelimit-remaining-requests", "10")
+
+	svc.UpdateRateLimitBudget(context.Background(), &Account{ID: 1, Platform: PlatformOpenAI, Type: AccountTypeOAuth}, h)
+	require.Empty(t, cache.budgets)
+
+	svc.UpdateRateLimitBudget(context.Background(), &Account{ID: 2, Platform: PlatformOpenAI, Type: AccountTypeAPIKey}, h)
+	require.Contains(t, cache.budgets, int64(2))
+	require.Equal(t, time.Minute, cache.ttls[2])
+}
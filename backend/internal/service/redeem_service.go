@@ -80,6 +80,7 @@ type RedeemService struct {
 	billingCacheService  *BillingCacheService
 	entClient            *dbent.Client
 	authCacheInvalidator APIKeyAuthCacheInvalidator
+	promoService         *PromoService
 }
 
 // NewRedeemService 创建兑换码服务实例
@@ -91,6 +92,7 @@ func NewRedeemService(
 	billingCacheService *BillingCacheService,
 	entClient *dbent.Client,
 	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	promoService *PromoService,
 ) *RedeemService {
 	return &RedeemService{
 		redeemRepo:           redeemRepo,
@@ -100,6 +102,7 @@ func NewRedeemService(
 		billingCacheService:  billingCacheService,
 		entClient:            entClient,
 		authCacheInvalidator: authCacheInvalidator,
+		promoService:         promoService,
 	}
 }
 
@@ -293,6 +296,12 @@ func (s *RedeemService) Redeem(ctx context.Context, userID int64, code string) (
 		if err := s.userRepo.UpdateBalance(txCtx, userID, redeemCode.Value); err != nil {
 			return nil, fmt.Errorf("update user balance: %w", err)
 		}
+		// 发放待发放的优惠码比例赠送
+		if s.promoService != nil && redeemCode.Value > 0 {
+			if _, err := s.promoService.ApplyTopUpBonus(txCtx, userID, redeemCode.ID, redeemCode.Value); err != nil {
+				return nil, fmt.Errorf("apply promo top-up bonus: %w", err)
+			}
+		}
 
 	case RedeemTypeConcurrency:
 		// 增加用户并发数
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 邀请关系状态
+const (
+	ReferralStatusActive  = "active"
+	ReferralStatusHeld    = "held"
+	ReferralStatusBlocked = "blocked"
+)
+
+// 邀请风控标记
+const (
+	// ReferralFlagSameIP 被邀请人注册 IP 与邀请人近期使用的 IP 相同
+	ReferralFlagSameIP = "same_ip"
+	// ReferralFlagSharedSignupIP 同一邀请人的多个被邀请人使用相同注册 IP
+	ReferralFlagSharedSignupIP = "shared_signup_ip"
+	// ReferralFlagSameEmailDomain 被邀请人与邀请人邮箱域名相同（公共邮箱除外）
+	ReferralFlagSameEmailDomain = "same_email_domain"
+)
+
+// 邀请奖励类型
+const (
+	ReferralRewardSignupBonus = "signup_bonus"
+	ReferralRewardCommission  = "commission"
+)
+
+// referralIPLookback 判定"同 IP"时回溯邀请人使用记录的时长
+const referralIPLookback = 30 * 24 * time.Hour
+
+// Referral 一条邀请关系
+type Referral struct {
+	ID         int64    `json:"id"`
+	ReferrerID int64    `json:"referrer_id"`
+	RefereeID  int64    `json:"referee_id"`
+	Code       string   `json:"code"`
+	Status     string   `json:"status"`
+	Flags      []string `json:"flags"`
+
+	SignupIP    string `json:"signup_ip"`
+	EmailDomain string `json:"email_domain"`
+
+	SignupBonusPaid bool    `json:"signup_bonus_paid"`
+	RefereeSpend    float64 `json:"referee_spend"`
+	// RefereeFundedSpend 已结算消费中由充值余额支付的部分；仅这部分计入返佣与注册奖励门槛
+	RefereeFundedSpend float64   `json:"referee_funded_spend"`
+	RewardTotal        float64   `json:"reward_total"`
+	SettledUntil       time.Time `json:"settled_until"`
+
+	ReviewedBy *int64     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 关联信息（列表展示用）
+	ReferrerEmail string `json:"referrer_email"`
+	RefereeEmail  string `json:"referee_email"`
+}
+
+// ReferralReward 一条邀请奖励流水
+type ReferralReward struct {
+	ID         int64     `json:"id"`
+	ReferralID int64     `json:"referral_id"`
+	ReferrerID int64     `json:"referrer_id"`
+	RefereeID  int64     `json:"referee_id"`
+	Kind       string    `json:"kind"`
+	Amount     float64   `json:"amount"`
+	BaseAmount float64   `json:"base_amount"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	RefereeEmail string `json:"referee_email"`
+}
+
+// ReferralStats 邀请人的汇总数据
+type ReferralStats struct {
+	TotalReferrals   int     `json:"total_referrals"`
+	ActiveReferrals  int     `json:"active_referrals"`
+	TotalRewards     float64 `json:"total_rewards"`
+	SignupBonusTotal float64 `json:"signup_bonus_total"`
+	CommissionTotal  float64 `json:"commission_total"`
+}
+
+// ReferralFilter 邀请关系列表过滤条件（管理员）
+type ReferralFilter struct {
+	Status     string
+	Flagged    bool
+	ReferrerID int64
+	Search     string
+}
+
+// ReferralSettings 邀请返利配置（存储在 settings 表，key = referral_settings）
+type ReferralSettings struct {
+	Enabled bool `json:"enabled"`
+	// SignupBonusUSD 每邀请一位用户，邀请人获得的奖励；0 表示不发放
+	SignupBonusUSD float64 `json:"signup_bonus_usd"`
+	// SignupBonusMinSpendUSD 被邀请人由充值余额支付的累计消费达到该金额后才发放注册奖励，防止批量注册刷奖励
+	SignupBonusMinSpendUSD float64 `json:"signup_bonus_min_spend_usd"`
+	// CommissionPercent 被邀请人由充值余额支付的消费的返佣比例（0-100）；0 表示不返佣
+	CommissionPercent float64 `json:"commission_percent"`
+	// CommissionDays 注册后多少天内的消费参与返佣；0 表示不限
+	CommissionDays int `json:"commission_days"`
+	// PerRefereeCapUSD 单个被邀请人为邀请人带来的奖励上限；0 表示不限
+	PerRefereeCapUSD float64 `json:"per_referee_cap_usd"`
+	// PerReferrerCapUSD 单个邀请人累计奖励上限；0 表示不限
+	PerReferrerCapUSD float64 `json:"per_referrer_cap_usd"`
+	// HoldFlagged 命中风控标记的邀请需管理员审核通过后才发放奖励
+	HoldFlagged bool `json:"hold_flagged"`
+	// IgnoredEmailDomains 不参与"同邮箱域名"判定的公共邮箱域名
+	IgnoredEmailDomains []string `json:"ignored_email_domains"`
+}
+
+// DefaultReferralSettings 返回默认配置（默认关闭）
+func DefaultReferralSettings() *ReferralSettings {
+	return &ReferralSettings{
+		Enabled:                false,
+		SignupBonusUSD:         1,
+		SignupBonusMinSpendUSD: 1,
+		CommissionPercent:      10,
+		CommissionDays:         90,
+		PerRefereeCapUSD:       20,
+		PerReferrerCapUSD:      0,
+		HoldFlagged:            true,
+		IgnoredEmailDomains: []string{
+			"gmail.com", "outlook.com", "hotmail.com", "live.com", "yahoo.com", "icloud.com",
+			"proton.me", "protonmail.com", "qq.com", "163.com", "126.com", "foxmail.com", "sina.com",
+		},
+	}
+}
+
+// referralEnabled 从 settings 原始值解析邀请功能是否开启（公开设置使用）
+func referralEnabled(raw string) bool {
+	if strings.TrimSpace(raw) == "" {
+		return false
+	}
+	var settings ReferralSettings
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return false
+	}
+	return settings.Enabled
+}
+
+// ReferralSettlementState 结算时锁定读取的邀请状态
+type ReferralSettlementState struct {
+	Referral *Referral
+	// PeriodSpend 本次结算区间内被邀请人的付费消费
+	PeriodSpend float64
+	// CommissionableSpend 其中位于返佣有效期内的部分
+	CommissionableSpend float64
+	// TopUpCredit 截至 upTo 被邀请人通过支付订单充值到账的余额（扣除退款，不含充值赠送）；
+	// 注册赠送、优惠码、兑换码、邀请奖励与管理员调整等赠送余额不计入
+	TopUpCredit float64
+	// ReferrerRewardTotal 邀请人此前累计获得的奖励
+	ReferrerRewardTotal float64
+}
+
+// ReferralRewardGrant 一次结算产生的奖励
+type ReferralRewardGrant struct {
+	Kind       string
+	Amount     float64
+	BaseAmount float64
+}
+
+// ReferralSettlement 结算决策：发放的奖励以及注册奖励是否已了结
+type ReferralSettlement struct {
+	Grants []ReferralRewardGrant
+	// BonusSettled 注册奖励已发放（或无需发放），后续不再检查
+	BonusSettled bool
+	// FundedSpend 本次结算区间内由充值余额支付的消费，累加到 referee_funded_spend
+	FundedSpend float64
+}
+
+// Total 本次结算发放的奖励总额
+func (s *ReferralSettlement) Total() float64 {
+	total := 0.0
+	for _, g := range s.Grants {
+		total += g.Amount
+	}
+	return roundReferralAmount(total)
+}
+
+// ReferralRepository 邀请返利数据访问接口
+type ReferralRepository interface {
+	// GetCodeByUserID 返回用户的邀请码；未生成时返回 ErrReferralCodeNotFound
+	GetCodeByUserID(ctx context.Context, userID int64) (string, error)
+	// CreateCode 保存邀请码；邀请码已被占用时返回 ErrReferralCodeConflict，用户已有邀请码时返回已有的邀请码
+	CreateCode(ctx context.Context, userID int64, code string) (string, error)
+	// GetUserIDByCode 返回邀请码所属用户；不存在时返回 ErrReferralCodeNotFound
+	GetUserIDByCode(ctx context.Context, code string) (int64, error)
+
+	// CreateReferral 创建邀请关系；被邀请人已存在邀请关系时返回 ErrReferralExists
+	CreateReferral(ctx context.Context, referral *Referral) error
+	GetReferralByID(ctx context.Context, id int64) (*Referral, error)
+	// GetReferralByReferee 返回用户被邀请的记录；不存在时返回 nil, nil
+	GetReferralByReferee(ctx context.Context, refereeID int64) (*Referral, error)
+	ListReferrals(ctx context.Context, params pagination.PaginationParams, filter ReferralFilter) ([]Referral, *pagination.PaginationResult, error)
+	UpdateReferralStatus(ctx context.Context, id int64, status string, reviewedBy int64, note string) error
+
+	ListRewards(ctx context.Context, referrerID int64, params pagination.PaginationParams) ([]ReferralReward, *pagination.PaginationResult, error)
+	GetReferrerStats(ctx context.Context, referrerID int64) (*ReferralStats, error)
+
+	// UserUsedIPSince 用户自 since 起是否有来自该 IP 的使用记录
+	UserUsedIPSince(ctx context.Context, userID int64, ip string, since time.Time) (bool, error)
+	// CountReferralsBySignupIP 统计同一邀请人名下使用该注册 IP 的邀请数
+	CountReferralsBySignupIP(ctx context.Context, referrerID int64, ip string) (int, error)
+
+	// ListSettleCandidateIDs 返回需要结算的邀请，按 ID 升序：
+	// 状态 active，且 (settled_until, upTo] 内有新的付费消费，或注册奖励未了结但已结算的充值消费达到 bonusMinSpend
+	ListSettleCandidateIDs(ctx context.Context, upTo time.Time, bonusMinSpend float64, afterID int64, limit int) ([]int64, error)
+	// Settle 在事务中锁定邀请记录，统计 (settled_until, upTo] 的付费消费与截至 upTo 的充值到账额，
+	// 按 settings.Decide 发放奖励（计入邀请人余额）并推进游标。
+	// 邀请不是 active 状态时不做任何处理，返回空结算
+	Settle(ctx context.Context, referralID int64, upTo time.Time, settings *ReferralSettings) (*ReferralSettlement, *Referral, error)
+}
+
+// Decide 根据配置计算一次结算应发放的奖励：
+// 先发注册奖励（被邀请人累计充值消费达到门槛后），再按比例返佣；两者都受单个被邀请人与单个邀请人的奖励上限约束。
+// 只有由充值余额支付的消费参与计算：消费优先视为使用尚未被已结算消费抵扣的充值余额，超出部分视为使用赠送余额。
+func (settings *ReferralSettings) Decide(state *ReferralSettlementState) *ReferralSettlement {
+	result := &ReferralSettlement{}
+	if state == nil || state.Referral == nil || settings == nil {
+		return result
+	}
+	referral := state.Referral
+
+	remaining := math.Inf(1)
+	if settings.PerRefereeCapUSD > 0 {
+		remaining = math.Min(remaining, settings.PerRefereeCapUSD-referral.RewardTotal)
+	}
+	if settings.PerReferrerCapUSD > 0 {
+		remaining = math.Min(remaining, settings.PerReferrerCapUSD-state.ReferrerRewardTotal)
+	}
+	grant := func(kind string, amount, base float64) {
+		amount = roundReferralAmount(math.Min(amount, remaining))
+		if amount <= 0 {
+			return
+		}
+		remaining -= amount
+		result.Grants = append(result.Grants, ReferralRewardGrant{Kind: kind, Amount: amount, BaseAmount: roundReferralAmount(base)})
+	}
+
+	funded := math.Max(0, math.Min(state.PeriodSpend, state.TopUpCredit-referral.RefereeFundedSpend))
+	result.FundedSpend = funded
+
+	if referral.SignupBonusPaid {
+		result.BonusSettled = true
+	} else if settings.SignupBonusUSD <= 0 {
+		result.BonusSettled = true
+	} else if referral.RefereeFundedSpend+funded >= settings.SignupBonusMinSpendUSD {
+		grant(ReferralRewardSignupBonus, settings.SignupBonusUSD, 0)
+		result.BonusSettled = true
+	}
+
+	if commissionable := math.Min(state.CommissionableSpend, funded); settings.CommissionPercent > 0 && commissionable > 0 {
+		grant(ReferralRewardCommission, commissionable*settings.CommissionPercent/100, commissionable)
+	}
+	return result
+}
+
+// CommissionUntil 返佣有效期截止时间；不限期时返回零值
+func (settings *ReferralSettings) CommissionUntil(referral *Referral) time.Time {
+	if settings == nil || settings.CommissionDays <= 0 {
+		return time.Time{}
+	}
+	return referral.CreatedAt.Add(time.Duration(settings.CommissionDays) * 24 * time.Hour)
+}
+
+// referralEmailDomain 返回邮箱域名（小写）
+func referralEmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// isIgnoredReferralDomain 公共邮箱域名不参与同域名判定
+func isIgnoredReferralDomain(domain string, ignored []string) bool {
+	for _, d := range ignored {
+		if strings.EqualFold(strings.TrimSpace(d), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func roundReferralAmount(v float64) float64 {
+	return math.Round(v*1e8) / 1e8
+}
@@ -0,0 +1,516 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	referralSettleInterval = time.Hour
+	referralSettleSlotKey  = "referral:settle"
+	referralSettleTimeout  = 10 * time.Minute
+	// referralSettleDelay 只结算该时间之前的消费，避免遗漏写入稍有延迟的使用记录
+	referralSettleDelay     = 5 * time.Minute
+	referralSettleBatchSize = 200
+
+	referralCodeLength   = 8
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referralCodeAttempts = 5
+)
+
+var (
+	ErrReferralDisabled     = infraerrors.Forbidden("REFERRAL_DISABLED", "referral program is disabled")
+	ErrReferralCodeNotFound = infraerrors.NotFound("REFERRAL_CODE_NOT_FOUND", "referral code not found")
+	ErrReferralCodeConflict = infraerrors.Conflict("REFERRAL_CODE_CONFLICT", "referral code already exists")
+	ErrReferralExists       = infraerrors.Conflict("REFERRAL_EXISTS", "user has already been referred")
+	ErrReferralNotFound     = infraerrors.NotFound("REFERRAL_NOT_FOUND", "referral not found")
+	ErrReferralSelf         = infraerrors.BadRequest("REFERRAL_SELF", "cannot use your own referral code")
+)
+
+// ReferralOverview 用户邀请页数据
+type ReferralOverview struct {
+	Enabled                bool           `json:"enabled"`
+	Code                   string         `json:"code,omitempty"`
+	SignupBonusUSD         float64        `json:"signup_bonus_usd"`
+	SignupBonusMinSpendUSD float64        `json:"signup_bonus_min_spend_usd"`
+	CommissionPercent      float64        `json:"commission_percent"`
+	CommissionDays         int            `json:"commission_days"`
+	PerRefereeCapUSD       float64        `json:"per_referee_cap_usd"`
+	Stats                  *ReferralStats `json:"stats"`
+}
+
+// ReferralService 邀请返利服务
+//
+// - 注册时通过邀请码绑定邀请关系，并按同 IP / 同邮箱域名打风控标记（可配置为待审核）
+// - 后台每小时结算一次：注册奖励（被邀请人消费达到门槛后）与付费消费返佣，计入邀请人余额
+// - 调度：Redis 结算槽位保证多实例下每个周期只执行一次；结算本身按游标加行锁，重复执行也不会重复发放
+type ReferralService struct {
+	referralRepo         ReferralRepository
+	userRepo             UserRepository
+	settingRepo          SettingRepository
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+	redisClient          *redis.Client
+
+	instanceID string
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+
+	warnNoRedisOnce sync.Once
+}
+
+// NewReferralService 创建邀请返利服务
+func NewReferralService(
+	referralRepo ReferralRepository,
+	userRepo UserRepository,
+	settingRepo SettingRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	redisClient *redis.Client,
+) *ReferralService {
+	return &ReferralService{
+		referralRepo:         referralRepo,
+		userRepo:             userRepo,
+		settingRepo:          settingRepo,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		redisClient:          redisClient,
+		instanceID:           uuid.NewString(),
+		stopCh:               make(chan struct{}),
+	}
+}
+
+// Start 启动后台结算循环
+func (s *ReferralService) Start() {
+	if s == nil || s.referralRepo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台结算循环
+func (s *ReferralService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *ReferralService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(referralSettleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// GetSettings 读取配置，未配置或解析失败时返回默认配置
+func (s *ReferralService) GetSettings(ctx context.Context) (*ReferralSettings, error) {
+	if s.settingRepo == nil {
+		return DefaultReferralSettings(), nil
+	}
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyReferralSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return DefaultReferralSettings(), nil
+		}
+		return nil, fmt.Errorf("get referral settings: %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return DefaultReferralSettings(), nil
+	}
+
+	settings := DefaultReferralSettings()
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return DefaultReferralSettings(), nil
+	}
+	return settings, nil
+}
+
+// UpdateSettings 校验并保存配置
+func (s *ReferralService) UpdateSettings(ctx context.Context, settings *ReferralSettings) (*ReferralSettings, error) {
+	if settings == nil {
+		return nil, infraerrors.BadRequest("REFERRAL_INVALID_SETTINGS", "settings cannot be nil")
+	}
+	if err := validateReferralSettings(settings); err != nil {
+		return nil, infraerrors.BadRequest("REFERRAL_INVALID_SETTINGS", err.Error())
+	}
+	settings.IgnoredEmailDomains = normalizeReferralDomains(settings.IgnoredEmailDomains)
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("marshal referral settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyReferralSettings, string(data)); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func validateReferralSettings(settings *ReferralSettings) error {
+	if settings.SignupBonusUSD < 0 || settings.SignupBonusUSD > 10000 {
+		return errors.New("signup_bonus_usd must be between 0-10000")
+	}
+	if settings.SignupBonusMinSpendUSD < 0 || settings.SignupBonusMinSpendUSD > 100000 {
+		return errors.New("signup_bonus_min_spend_usd must be between 0-100000")
+	}
+	if settings.CommissionPercent < 0 || settings.CommissionPercent > 100 {
+		return errors.New("commission_percent must be between 0-100")
+	}
+	if settings.CommissionDays < 0 || settings.CommissionDays > 3650 {
+		return errors.New("commission_days must be between 0-3650")
+	}
+	if settings.PerRefereeCapUSD < 0 || settings.PerReferrerCapUSD < 0 {
+		return errors.New("reward caps cannot be negative")
+	}
+	if settings.Enabled && settings.SignupBonusUSD == 0 && settings.CommissionPercent == 0 {
+		return errors.New("at least one of signup_bonus_usd or commission_percent must be set when enabled")
+	}
+	return nil
+}
+
+func normalizeReferralDomains(domains []string) []string {
+	out := make([]string, 0, len(domains))
+	seen := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(d), "@")))
+		if d == "" {
+			continue
+		}
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		out = append(out, d)
+	}
+	return out
+}
+
+// GetOverview 用户邀请页：开启时自动生成邀请码
+func (s *ReferralService) GetOverview(ctx context.Context, userID int64) (*ReferralOverview, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.referralRepo.GetReferrerStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get referrer stats: %w", err)
+	}
+	overview := &ReferralOverview{
+		Enabled:                settings.Enabled,
+		SignupBonusUSD:         settings.SignupBonusUSD,
+		SignupBonusMinSpendUSD: settings.SignupBonusMinSpendUSD,
+		CommissionPercent:      settings.CommissionPercent,
+		CommissionDays:         settings.CommissionDays,
+		PerRefereeCapUSD:       settings.PerRefereeCapUSD,
+		Stats:                  stats,
+	}
+	if !settings.Enabled {
+		return overview, nil
+	}
+	code, err := s.ensureCode(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	overview.Code = code
+	return overview, nil
+}
+
+// ensureCode 返回用户的邀请码，不存在时生成
+func (s *ReferralService) ensureCode(ctx context.Context, userID int64) (string, error) {
+	code, err := s.referralRepo.GetCodeByUserID(ctx, userID)
+	if err == nil {
+		return code, nil
+	}
+	if !errors.Is(err, ErrReferralCodeNotFound) {
+		return "", fmt.Errorf("get referral code: %w", err)
+	}
+	for i := 0; i < referralCodeAttempts; i++ {
+		candidate, err := generateReferralCode()
+		if err != nil {
+			return "", fmt.Errorf("generate referral code: %w", err)
+		}
+		code, err = s.referralRepo.CreateCode(ctx, userID, candidate)
+		if err == nil {
+			return code, nil
+		}
+		if !errors.Is(err, ErrReferralCodeConflict) {
+			return "", fmt.Errorf("create referral code: %w", err)
+		}
+	}
+	return "", ErrReferralCodeConflict
+}
+
+func generateReferralCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(referralCodeAlphabet)))
+	for i := 0; i < referralCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(referralCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func normalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ListMyReferrals 用户查看自己邀请的用户（邮箱脱敏）
+func (s *ReferralService) ListMyReferrals(ctx context.Context, userID int64, params pagination.PaginationParams) ([]Referral, *pagination.PaginationResult, error) {
+	items, result, err := s.referralRepo.ListReferrals(ctx, params, ReferralFilter{ReferrerID: userID})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range items {
+		items[i].RefereeEmail = MaskEmail(items[i].RefereeEmail)
+		items[i].ReferrerEmail = ""
+		items[i].SignupIP = ""
+		items[i].EmailDomain = ""
+		items[i].Flags = nil
+		items[i].ReviewNote = ""
+	}
+	return items, result, nil
+}
+
+// ListMyRewards 用户查看奖励流水（邮箱脱敏）
+func (s *ReferralService) ListMyRewards(ctx context.Context, userID int64, params pagination.PaginationParams) ([]ReferralReward, *pagination.PaginationResult, error) {
+	items, result, err := s.referralRepo.ListRewards(ctx, userID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range items {
+		items[i].RefereeEmail = MaskEmail(items[i].RefereeEmail)
+	}
+	return items, result, nil
+}
+
+// BindOnSignup 新用户注册后绑定邀请关系（best-effort：邀请码无效时只记录日志，不影响注册）
+func (s *ReferralService) BindOnSignup(ctx context.Context, referee *User, code, signupIP string) {
+	if s == nil || referee == nil {
+		return
+	}
+	code = normalizeReferralCode(code)
+	if code == "" {
+		return
+	}
+	referral, err := s.bind(ctx, referee, code, strings.TrimSpace(signupIP))
+	if err != nil {
+		log.Printf("[Referral] bind failed: user=%d code=%s err=%v", referee.ID, code, err)
+		return
+	}
+	if len(referral.Flags) > 0 {
+		log.Printf("[Referral] referral flagged: referrer=%d referee=%d flags=%v status=%s", referral.ReferrerID, referral.RefereeID, referral.Flags, referral.Status)
+	}
+}
+
+func (s *ReferralService) bind(ctx context.Context, referee *User, code, signupIP string) (*Referral, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, ErrReferralDisabled
+	}
+	referrerID, err := s.referralRepo.GetUserIDByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if referrerID == referee.ID {
+		return nil, ErrReferralSelf
+	}
+	referrer, err := s.userRepo.GetByID(ctx, referrerID)
+	if err != nil {
+		return nil, fmt.Errorf("get referrer: %w", err)
+	}
+	if !referrer.IsActive() {
+		return nil, ErrReferralCodeNotFound
+	}
+
+	now := time.Now()
+	referral := &Referral{
+		ReferrerID:   referrerID,
+		RefereeID:    referee.ID,
+		Code:         code,
+		Status:       ReferralStatusActive,
+		SignupIP:     signupIP,
+		EmailDomain:  referralEmailDomain(referee.Email),
+		SettledUntil: now,
+	}
+	referral.Flags = s.detectFlags(ctx, referrer, referral, settings, now)
+	if len(referral.Flags) > 0 && settings.HoldFlagged {
+		referral.Status = ReferralStatusHeld
+	}
+	if err := s.referralRepo.CreateReferral(ctx, referral); err != nil {
+		return nil, err
+	}
+	return referral, nil
+}
+
+// detectFlags 风控标记：同 IP（邀请人近期使用过该 IP，或邀请人自身的注册 IP）、多个被邀请人共用注册 IP、同邮箱域名
+func (s *ReferralService) detectFlags(ctx context.Context, referrer *User, referral *Referral, settings *ReferralSettings, now time.Time) []string {
+	flags := make([]string, 0)
+	if ip := referral.SignupIP; ip != "" {
+		sameIP, err := s.referralRepo.UserUsedIPSince(ctx, referrer.ID, ip, now.Add(-referralIPLookback))
+		if err != nil {
+			log.Printf("[Referral] check referrer ip failed: %v", err)
+		}
+		if !sameIP {
+			if own, err := s.referralRepo.GetReferralByReferee(ctx, referrer.ID); err == nil && own != nil && own.SignupIP == ip {
+				sameIP = true
+			}
+		}
+		if sameIP {
+			flags = append(flags, ReferralFlagSameIP)
+		}
+		if n, err := s.referralRepo.CountReferralsBySignupIP(ctx, referrer.ID, ip); err != nil {
+			log.Printf("[Referral] count referrals by ip failed: %v", err)
+		} else if n > 0 {
+			flags = append(flags, ReferralFlagSharedSignupIP)
+		}
+	}
+	if domain := referral.EmailDomain; domain != "" &&
+		domain == referralEmailDomain(referrer.Email) &&
+		!isIgnoredReferralDomain(domain, settings.IgnoredEmailDomains) {
+		flags = append(flags, ReferralFlagSameEmailDomain)
+	}
+	return flags
+}
+
+// ListReferrals 管理员分页查看邀请关系
+func (s *ReferralService) ListReferrals(ctx context.Context, params pagination.PaginationParams, filter ReferralFilter) ([]Referral, *pagination.PaginationResult, error) {
+	return s.referralRepo.ListReferrals(ctx, params, filter)
+}
+
+// Approve 管理员审核通过（held / blocked -> active）；此前累积的消费会在下次结算时补发
+func (s *ReferralService) Approve(ctx context.Context, id, actorID int64, note string) (*Referral, error) {
+	return s.review(ctx, id, ReferralStatusActive, actorID, note)
+}
+
+// Block 管理员拒绝，不再发放任何奖励（已发放的奖励不追回）
+func (s *ReferralService) Block(ctx context.Context, id, actorID int64, note string) (*Referral, error) {
+	return s.review(ctx, id, ReferralStatusBlocked, actorID, note)
+}
+
+func (s *ReferralService) review(ctx context.Context, id int64, status string, actorID int64, note string) (*Referral, error) {
+	if _, err := s.referralRepo.GetReferralByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.referralRepo.UpdateReferralStatus(ctx, id, status, actorID, truncateString(strings.TrimSpace(note), 500)); err != nil {
+		return nil, err
+	}
+	return s.referralRepo.GetReferralByID(ctx, id)
+}
+
+func (s *ReferralService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), referralSettleTimeout)
+	defer cancel()
+
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		log.Printf("[Referral] load settings failed: %v", err)
+		return
+	}
+	if !settings.Enabled || !s.tryAcquireSettleSlot(ctx) {
+		return
+	}
+	settled, credited, err := s.Settle(ctx, settings, time.Now().Add(-referralSettleDelay))
+	if err != nil {
+		log.Printf("[Referral] settle failed: %v", err)
+	}
+	if settled > 0 {
+		log.Printf("[Referral] settled %d referrals, credited %.4f USD", settled, credited)
+	}
+}
+
+// Settle 结算 upTo 之前的消费，返回发放了奖励的邀请数与奖励总额
+func (s *ReferralService) Settle(ctx context.Context, settings *ReferralSettings, upTo time.Time) (int, float64, error) {
+	rewarded := 0
+	credited := 0.0
+	afterID := int64(0)
+	for {
+		ids, err := s.referralRepo.ListSettleCandidateIDs(ctx, upTo, settings.SignupBonusMinSpendUSD, afterID, referralSettleBatchSize)
+		if err != nil {
+			return rewarded, credited, fmt.Errorf("list settle candidates: %w", err)
+		}
+		for _, id := range ids {
+			afterID = id
+			settlement, referral, err := s.referralRepo.Settle(ctx, id, upTo, settings)
+			if err != nil {
+				log.Printf("[Referral] settle referral %d failed: %v", id, err)
+				continue
+			}
+			if total := settlement.Total(); total > 0 {
+				rewarded++
+				credited += total
+				s.invalidateBalanceCaches(ctx, referral.ReferrerID)
+			}
+		}
+		if len(ids) < referralSettleBatchSize {
+			return rewarded, credited, nil
+		}
+	}
+}
+
+func (s *ReferralService) invalidateBalanceCaches(ctx context.Context, userID int64) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
+	}
+	if s.billingCacheService != nil {
+		go func() {
+			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.billingCacheService.InvalidateUserBalance(cacheCtx, userID); err != nil {
+				log.Printf("[Referral] invalidate user balance cache failed: user=%d err=%v", userID, err)
+			}
+		}()
+	}
+}
+
+func (s *ReferralService) tryAcquireSettleSlot(ctx context.Context) bool {
+	if s.redisClient == nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[Referral] redis not configured; running without settle lock")
+		})
+		return true
+	}
+	ttl := referralSettleInterval - referralSettleInterval/6
+	ok, err := s.redisClient.SetNX(ctx, referralSettleSlotKey, s.instanceID, ttl).Result()
+	if err != nil {
+		log.Printf("[Referral] settle slot SetNX failed; skipping this cycle: %v", err)
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,279 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type referralRepoStub struct {
+	ReferralRepository
+
+	codes       map[string]int64
+	usedIP      bool
+	ownReferral *Referral
+	ipCount     int
+	created     []*Referral
+}
+
+func (s *referralRepoStub) GetUserIDByCode(ctx context.Context, code string) (int64, error) {
+	if id, ok := s.codes[code]; ok {
+		return id, nil
+	}
+	return 0, ErrReferralCodeNotFound
+}
+
+func (s *referralRepoStub) UserUsedIPSince(ctx context.Context, userID int64, ip string, since time.Time) (bool, error) {
+	return s.usedIP, nil
+}
+
+func (s *referralRepoStub) GetReferralByReferee(ctx context.Context, refereeID int64) (*Referral, error) {
+	return s.ownReferral, nil
+}
+
+func (s *referralRepoStub) CountReferralsBySignupIP(ctx context.Context, referrerID int64, ip string) (int, error) {
+	return s.ipCount, nil
+}
+
+func (s *referralRepoStub) CreateReferral(ctx context.Context, referral *Referral) error {
+	referral.ID = int64(len(s.created) + 1)
+	s.created = append(s.created, referral)
+	return nil
+}
+
+func newReferralServiceForTest(t *testing.T, settings *ReferralSettings, repo *referralRepoStub, referrer *User) *ReferralService {
+	t.Helper()
+	data, err := json.Marshal(settings)
+	require.NoError(t, err)
+	settingRepo := &settingRepoStub{values: map[string]string{SettingKeyReferralSettings: string(data)}}
+	return NewReferralService(repo, &userRepoStub{user: referrer}, settingRepo, nil, nil, nil)
+}
+
+func enabledReferralSettings() *ReferralSettings {
+	settings := DefaultReferralSettings()
+	settings.Enabled = true
+	return settings
+}
+
+func TestReferralSettingsDecide_BonusWaitsForMinSpend(t *testing.T) {
+	settings := &ReferralSettings{SignupBonusUSD: 2, SignupBonusMinSpendUSD: 5}
+
+	result := settings.Decide(&ReferralSettlementState{
+		Referral:    &Referral{RefereeSpend: 1, RefereeFundedSpend: 1},
+		PeriodSpend: 3,
+		TopUpCredit: 10,
+	})
+	require.Empty(t, result.Grants)
+	require.False(t, result.BonusSettled)
+
+	result = settings.Decide(&ReferralSettlementState{
+		Referral:    &Referral{RefereeSpend: 3, RefereeFundedSpend: 3},
+		PeriodSpend: 2,
+		TopUpCredit: 10,
+	})
+	require.Len(t, result.Grants, 1)
+	require.Equal(t, ReferralRewardSignupBonus, result.Grants[0].Kind)
+	require.Equal(t, 2.0, result.Grants[0].Amount)
+	require.True(t, result.BonusSettled)
+}
+
+func TestReferralSettingsDecide_BonusAlreadyPaidOrDisabled(t *testing.T) {
+	settings := &ReferralSettings{SignupBonusUSD: 2}
+	result := settings.Decide(&ReferralSettlementState{Referral: &Referral{SignupBonusPaid: true}, PeriodSpend: 10})
+	require.Empty(t, result.Grants)
+	require.True(t, result.BonusSettled)
+
+	settings = &ReferralSettings{SignupBonusUSD: 0}
+	result = settings.Decide(&ReferralSettlementState{Referral: &Referral{}})
+	require.Empty(t, result.Grants)
+	require.True(t, result.BonusSettled)
+}
+
+func TestReferralSettingsDecide_Commission(t *testing.T) {
+	settings := &ReferralSettings{CommissionPercent: 10}
+	result := settings.Decide(&ReferralSettlementState{
+		Referral:            &Referral{},
+		PeriodSpend:         12.5,
+		CommissionableSpend: 12.5,
+		TopUpCredit:         20,
+	})
+	require.Len(t, result.Grants, 1)
+	require.Equal(t, ReferralRewardCommission, result.Grants[0].Kind)
+	require.InDelta(t, 1.25, result.Grants[0].Amount, 1e-9)
+	require.InDelta(t, 12.5, result.Grants[0].BaseAmount, 1e-9)
+	require.InDelta(t, 1.25, result.Total(), 1e-9)
+}
+
+func TestReferralSettingsDecide_CapsApplyBonusFirst(t *testing.T) {
+	settings := &ReferralSettings{SignupBonusUSD: 1, CommissionPercent: 50, PerRefereeCapUSD: 3}
+	result := settings.Decide(&ReferralSettlementState{
+		Referral:            &Referral{RewardTotal: 0.5},
+		PeriodSpend:         10,
+		CommissionableSpend: 10,
+		TopUpCredit:         10,
+	})
+	require.Len(t, result.Grants, 2)
+	require.Equal(t, 1.0, result.Grants[0].Amount)
+	require.InDelta(t, 1.5, result.Grants[1].Amount, 1e-9)
+	require.InDelta(t, 2.5, result.Total(), 1e-9)
+
+	settings = &ReferralSettings{CommissionPercent: 50, PerReferrerCapUSD: 100}
+	result = settings.Decide(&ReferralSettlementState{
+		Referral:            &Referral{SignupBonusPaid: true},
+		PeriodSpend:         10,
+		CommissionableSpend: 10,
+		TopUpCredit:         10,
+		ReferrerRewardTotal: 100,
+	})
+	require.Empty(t, result.Grants)
+}
+
+func TestReferralSettingsDecide_GrantedCreditSpendEarnsNothing(t *testing.T) {
+	settings := &ReferralSettings{SignupBonusUSD: 2, SignupBonusMinSpendUSD: 5, CommissionPercent: 10}
+
+	// 没有充值：消费全部来自赠送余额
+	result := settings.Decide(&ReferralSettlementState{
+		Referral:            &Referral{},
+		PeriodSpend:         50,
+		CommissionableSpend: 50,
+	})
+	require.Empty(t, result.Grants)
+	require.False(t, result.BonusSettled)
+	require.Zero(t, result.FundedSpend)
+
+	// 充值 8，其中 6 已被此前结算的消费抵扣：本期只有 2 计入
+	result = settings.Decide(&ReferralSettlementState{
+		Referral:            &Referral{RefereeSpend: 30, RefereeFundedSpend: 6},
+		PeriodSpend:         20,
+		CommissionableSpend: 20,
+		TopUpCredit:         8,
+	})
+	require.InDelta(t, 2, result.FundedSpend, 1e-9)
+	require.True(t, result.BonusSettled)
+	require.Len(t, result.Grants, 2)
+	require.Equal(t, ReferralRewardSignupBonus, result.Grants[0].Kind)
+	require.Equal(t, ReferralRewardCommission, result.Grants[1].Kind)
+	require.InDelta(t, 0.2, result.Grants[1].Amount, 1e-9)
+	require.InDelta(t, 2, result.Grants[1].BaseAmount, 1e-9)
+
+	// 退款后充值额低于已抵扣额：不产生负数
+	result = settings.Decide(&ReferralSettlementState{
+		Referral:            &Referral{SignupBonusPaid: true, RefereeFundedSpend: 8},
+		PeriodSpend:         5,
+		CommissionableSpend: 5,
+		TopUpCredit:         4,
+	})
+	require.Empty(t, result.Grants)
+	require.Zero(t, result.FundedSpend)
+}
+
+func TestReferralSettingsCommissionUntil(t *testing.T) {
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	referral := &Referral{CreatedAt: createdAt}
+
+	require.True(t, (&ReferralSettings{}).CommissionUntil(referral).IsZero())
+	require.Equal(t, createdAt.Add(30*24*time.Hour), (&ReferralSettings{CommissionDays: 30}).CommissionUntil(referral))
+}
+
+func TestValidateReferralSettings(t *testing.T) {
+	require.NoError(t, validateReferralSettings(enabledReferralSettings()))
+
+	settings := enabledReferralSettings()
+	settings.CommissionPercent = 120
+	require.Error(t, validateReferralSettings(settings))
+
+	settings = enabledReferralSettings()
+	settings.SignupBonusUSD = 0
+	settings.CommissionPercent = 0
+	require.Error(t, validateReferralSettings(settings))
+
+	settings.Enabled = false
+	require.NoError(t, validateReferralSettings(settings))
+}
+
+func TestNormalizeReferralDomains(t *testing.T) {
+	require.Equal(t, []string{"gmail.com", "example.org"},
+		normalizeReferralDomains([]string{" Gmail.com", "@gmail.com", "", "example.org"}))
+}
+
+func TestReferralEnabled(t *testing.T) {
+	require.False(t, referralEnabled(""))
+	require.False(t, referralEnabled("not-json"))
+	require.False(t, referralEnabled(`{"enabled":false}`))
+	require.True(t, referralEnabled(`{"enabled":true}`))
+}
+
+func TestReferralBind_CleanReferralIsActive(t *testing.T) {
+	repo := &referralRepoStub{codes: map[string]int64{"ABCD2345": 1}}
+	referrer := &User{ID: 1, Email: "alice@company.com", Status: StatusActive}
+	svc := newReferralServiceForTest(t, enabledReferralSettings(), repo, referrer)
+
+	svc.BindOnSignup(context.Background(), &User{ID: 2, Email: "bob@gmail.com"}, " abcd2345 ", "10.0.0.2")
+
+	require.Len(t, repo.created, 1)
+	referral := repo.created[0]
+	require.Equal(t, int64(1), referral.ReferrerID)
+	require.Equal(t, int64(2), referral.RefereeID)
+	require.Equal(t, "ABCD2345", referral.Code)
+	require.Equal(t, ReferralStatusActive, referral.Status)
+	require.Empty(t, referral.Flags)
+	require.Equal(t, "gmail.com", referral.EmailDomain)
+}
+
+func TestReferralBind_FlaggedReferralIsHeld(t *testing.T) {
+	repo := &referralRepoStub{codes: map[string]int64{"ABCD2345": 1}, usedIP: true, ipCount: 1}
+	referrer := &User{ID: 1, Email: "alice@company.com", Status: StatusActive}
+	svc := newReferralServiceForTest(t, enabledReferralSettings(), repo, referrer)
+
+	svc.BindOnSignup(context.Background(), &User{ID: 2, Email: "bob@Company.com"}, "ABCD2345", "10.0.0.1")
+
+	require.Len(t, repo.created, 1)
+	referral := repo.created[0]
+	require.Equal(t, ReferralStatusHeld, referral.Status)
+	require.Equal(t, []string{ReferralFlagSameIP, ReferralFlagSharedSignupIP, ReferralFlagSameEmailDomain}, referral.Flags)
+}
+
+func TestReferralBind_PublicDomainIgnoredAndHoldDisabled(t *testing.T) {
+	settings := enabledReferralSettings()
+	settings.HoldFlagged = false
+	repo := &referralRepoStub{
+		codes:       map[string]int64{"ABCD2345": 1},
+		ownReferral: &Referral{SignupIP: "10.0.0.1"},
+	}
+	referrer := &User{ID: 1, Email: "alice@gmail.com", Status: StatusActive}
+	svc := newReferralServiceForTest(t, settings, repo, referrer)
+
+	svc.BindOnSignup(context.Background(), &User{ID: 2, Email: "bob@gmail.com"}, "ABCD2345", "10.0.0.1")
+
+	require.Len(t, repo.created, 1)
+	require.Equal(t, ReferralStatusActive, repo.created[0].Status)
+	require.Equal(t, []string{ReferralFlagSameIP}, repo.created[0].Flags)
+}
+
+func TestReferralBind_Rejected(t *testing.T) {
+	referrer := &User{ID: 1, Email: "alice@company.com", Status: StatusActive}
+	ctx := context.Background()
+
+	repo := &referralRepoStub{codes: map[string]int64{"ABCD2345": 1}}
+	svc := newReferralServiceForTest(t, enabledReferralSettings(), repo, referrer)
+	_, err := svc.bind(ctx, &User{ID: 1, Email: "alice@company.com"}, "ABCD2345", "")
+	require.ErrorIs(t, err, ErrReferralSelf)
+	_, err = svc.bind(ctx, &User{ID: 2, Email: "bob@gmail.com"}, "UNKNOWN1", "")
+	require.ErrorIs(t, err, ErrReferralCodeNotFound)
+
+	svc = newReferralServiceForTest(t, DefaultReferralSettings(), repo, referrer)
+	_, err = svc.bind(ctx, &User{ID: 2, Email: "bob@gmail.com"}, "ABCD2345", "")
+	require.ErrorIs(t, err, ErrReferralDisabled)
+
+	disabledReferrer := &User{ID: 1, Email: "alice@company.com", Status: StatusDisabled}
+	svc = newReferralServiceForTest(t, enabledReferralSettings(), repo, disabledReferrer)
+	_, err = svc.bind(ctx, &User{ID: 2, Email: "bob@gmail.com"}, "ABCD2345", "")
+	require.ErrorIs(t, err, ErrReferralCodeNotFound)
+
+	require.Empty(t, repo.created)
+}
@@ -17,6 +17,11 @@ type RefreshTokenData struct {
 	FamilyID     string    `json:"family_id"`     // Token家族ID，用于防重放攻击
 	CreatedAt    time.Time `json:"created_at"`
 	ExpiresAt    time.Time `json:"expires_at"`
+
+	// 会话（Token家族）元数据，Token轮转时沿用
+	SessionCreatedAt time.Time `json:"session_created_at,omitempty"`
+	UserAgent        string    `json:"user_agent,omitempty"`
+	IPAddress        string    `json:"ip_address,omitempty"`
 }
 
 // RefreshTokenCache 管理Refresh Token的Redis缓存
@@ -26,6 +31,8 @@ type RefreshTokenData struct {
 //   - refresh_token:{token_hash}     -> RefreshTokenData (JSON)
 //   - user_refresh_tokens:{user_id}  -> Set<token_hash>
 //   - token_family:{family_id}       -> Set<token_hash>
+//   - revoked_session:{family_id}    -> "1"（会话被撤销后，在Access Token有效期内拒绝其访问）
+//   - user_known_devices:{user_id}   -> Set<device_fingerprint>
 type RefreshTokenCache interface {
 	// StoreRefreshToken 存储Refresh Token
 	// tokenHash: Token的SHA256哈希值（不存储原始Token）
@@ -70,4 +77,19 @@ type RefreshTokenCache interface {
 	// IsTokenInFamily 检查Token是否属于指定家族
 	// 用于验证Token家族关系
 	IsTokenInFamily(ctx context.Context, familyID string, tokenHash string) (bool, error)
+
+	// RemoveFromUserTokenSet 从用户的Token集合中移除指定Token哈希
+	// 用于清理已轮转/过期的Token引用
+	RemoveFromUserTokenSet(ctx context.Context, userID int64, tokenHashes ...string) error
+
+	// MarkSessionRevoked 标记会话已撤销
+	// ttl 应不短于Access Token有效期，使已签发的Access Token立即失效
+	MarkSessionRevoked(ctx context.Context, familyID string, ttl time.Duration) error
+
+	// IsSessionRevoked 检查会话是否已被撤销
+	IsSessionRevoked(ctx context.Context, familyID string) (bool, error)
+
+	// RememberUserDevice 记录用户登录过的设备指纹
+	// 返回 true 表示这是一个此前未见过的设备（用户首次登录记录的设备不算新设备）
+	RememberUserDevice(ctx context.Context, userID int64, fingerprint string, ttl time.Duration) (bool, error)
 }
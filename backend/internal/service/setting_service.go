@@ -64,6 +64,7 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		SettingKeyPasswordResetEnabled,
 		SettingKeyInvitationCodeEnabled,
 		SettingKeyTotpEnabled,
+		SettingKeyPasskeyLoginEnabled,
 		SettingKeyTurnstileEnabled,
 		SettingKeyTurnstileSiteKey,
 		SettingKeySiteName,
@@ -77,6 +78,8 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		SettingKeyPurchaseSubscriptionEnabled,
 		SettingKeyPurchaseSubscriptionURL,
 		SettingKeyLinuxDoConnectEnabled,
+		SettingKeyOIDCProviders,
+		SettingKeyReferralSettings,
 	}
 
 	settings, err := s.settingRepo.GetMultiple(ctx, keys)
@@ -102,6 +105,7 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		PasswordResetEnabled:        passwordResetEnabled,
 		InvitationCodeEnabled:       settings[SettingKeyInvitationCodeEnabled] == "true",
 		TotpEnabled:                 settings[SettingKeyTotpEnabled] == "true",
+		PasskeyLoginEnabled:         settings[SettingKeyPasskeyLoginEnabled] == "true",
 		TurnstileEnabled:            settings[SettingKeyTurnstileEnabled] == "true",
 		TurnstileSiteKey:            settings[SettingKeyTurnstileSiteKey],
 		SiteName:                    s.getStringOrDefault(settings, SettingKeySiteName, "Sub2API"),
@@ -115,6 +119,8 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		PurchaseSubscriptionEnabled: settings[SettingKeyPurchaseSubscriptionEnabled] == "true",
 		PurchaseSubscriptionURL:     strings.TrimSpace(settings[SettingKeyPurchaseSubscriptionURL]),
 		LinuxDoOAuthEnabled:         linuxDoEnabled,
+		OIDCProviders:               publicOIDCProviders(settings[SettingKeyOIDCProviders]),
+		ReferralEnabled:             referralEnabled(settings[SettingKeyReferralSettings]),
 	}, nil
 }
 
@@ -139,26 +145,29 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 
 	// Return a struct that matches the frontend's expected format
 	return &struct {
-		RegistrationEnabled         bool   `json:"registration_enabled"`
-		EmailVerifyEnabled          bool   `json:"email_verify_enabled"`
-		PromoCodeEnabled            bool   `json:"promo_code_enabled"`
-		PasswordResetEnabled        bool   `json:"password_reset_enabled"`
-		InvitationCodeEnabled       bool   `json:"invitation_code_enabled"`
-		TotpEnabled                 bool   `json:"totp_enabled"`
-		TurnstileEnabled            bool   `json:"turnstile_enabled"`
-		TurnstileSiteKey            string `json:"turnstile_site_key,omitempty"`
-		SiteName                    string `json:"site_name"`
-		SiteLogo                    string `json:"site_logo,omitempty"`
-		SiteSubtitle                string `json:"site_subtitle,omitempty"`
-		APIBaseURL                  string `json:"api_base_url,omitempty"`
-		ContactInfo                 string `json:"contact_info,omitempty"`
-		DocURL                      string `json:"doc_url,omitempty"`
-		HomeContent                 string `json:"home_content,omitempty"`
-		HideCcsImportButton         bool   `json:"hide_ccs_import_button"`
-		PurchaseSubscriptionEnabled bool   `json:"purchase_subscription_enabled"`
-		PurchaseSubscriptionURL     string `json:"purchase_subscription_url,omitempty"`
-		LinuxDoOAuthEnabled         bool   `json:"linuxdo_oauth_enabled"`
-		Version                     string `json:"version,omitempty"`
+		RegistrationEnabled         bool                 `json:"registration_enabled"`
+		EmailVerifyEnabled          bool                 `json:"email_verify_enabled"`
+		PromoCodeEnabled            bool                 `json:"promo_code_enabled"`
+		PasswordResetEnabled        bool                 `json:"password_reset_enabled"`
+		InvitationCodeEnabled       bool                 `json:"invitation_code_enabled"`
+		TotpEnabled                 bool                 `json:"totp_enabled"`
+		PasskeyLoginEnabled         bool                 `json:"passkey_login_enabled"`
+		TurnstileEnabled            bool                 `json:"turnstile_enabled"`
+		TurnstileSiteKey            string               `json:"turnstile_site_key,omitempty"`
+		SiteName                    string               `json:"site_name"`
+		SiteLogo                    string               `json:"site_logo,omitempty"`
+		SiteSubtitle                string               `json:"site_subtitle,omitempty"`
+		APIBaseURL                  string               `json:"api_base_url,omitempty"`
+		ContactInfo                 string               `json:"contact_info,omitempty"`
+		DocURL                      string               `json:"doc_url,omitempty"`
+		HomeContent                 string               `json:"home_content,omitempty"`
+		HideCcsImportButton         bool                 `json:"hide_ccs_import_button"`
+		PurchaseSubscriptionEnabled bool                 `json:"purchase_subscription_enabled"`
+		PurchaseSubscriptionURL     string               `json:"purchase_subscription_url,omitempty"`
+		LinuxDoOAuthEnabled         bool                 `json:"linuxdo_oauth_enabled"`
+		OIDCProviders               []OIDCPublicProvider `json:"oidc_providers"`
+		ReferralEnabled             bool                 `json:"referral_enabled"`
+		Version                     string               `json:"version,omitempty"`
 	}{
 		RegistrationEnabled:         settings.RegistrationEnabled,
 		EmailVerifyEnabled:          settings.EmailVerifyEnabled,
@@ -166,6 +175,7 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 		PasswordResetEnabled:        settings.PasswordResetEnabled,
 		InvitationCodeEnabled:       settings.InvitationCodeEnabled,
 		TotpEnabled:                 settings.TotpEnabled,
+		PasskeyLoginEnabled:         settings.PasskeyLoginEnabled,
 		TurnstileEnabled:            settings.TurnstileEnabled,
 		TurnstileSiteKey:            settings.TurnstileSiteKey,
 		SiteName:                    settings.SiteName,
@@ -179,6 +189,8 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 		PurchaseSubscriptionEnabled: settings.PurchaseSubscriptionEnabled,
 		PurchaseSubscriptionURL:     settings.PurchaseSubscriptionURL,
 		LinuxDoOAuthEnabled:         settings.LinuxDoOAuthEnabled,
+		OIDCProviders:               settings.OIDCProviders,
+		ReferralEnabled:             settings.ReferralEnabled,
 		Version:                     s.version,
 	}, nil
 }
@@ -194,6 +206,7 @@ func (s *SettingService) UpdateSettings(ctx context.Context, settings *SystemSet
 	updates[SettingKeyPasswordResetEnabled] = strconv.FormatBool(settings.PasswordResetEnabled)
 	updates[SettingKeyInvitationCodeEnabled] = strconv.FormatBool(settings.InvitationCodeEnabled)
 	updates[SettingKeyTotpEnabled] = strconv.FormatBool(settings.TotpEnabled)
+	updates[SettingKeyPasskeyLoginEnabled] = strconv.FormatBool(settings.PasskeyLoginEnabled)
 
 	// 邮件服务设置（只有非空才更新密码）
 	updates[SettingKeySMTPHost] = settings.SMTPHost
@@ -326,6 +339,15 @@ func (s *SettingService) IsTotpEnabled(ctx context.Context) bool {
 	return value == "true"
 }
 
+// IsPasskeyLoginEnabled 检查是否允许使用通行密钥无密码登录
+func (s *SettingService) IsPasskeyLoginEnabled(ctx context.Context) bool {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyPasskeyLoginEnabled)
+	if err != nil {
+		return false // 默认关闭
+	}
+	return value == "true"
+}
+
 // IsTotpEncryptionKeyConfigured 检查 TOTP 加密密钥是否已手动配置
 // 只有手动配置了密钥才允许在管理后台启用 TOTP 功能
 func (s *SettingService) IsTotpEncryptionKeyConfigured() bool {
@@ -420,6 +442,7 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 		PasswordResetEnabled:         emailVerifyEnabled && settings[SettingKeyPasswordResetEnabled] == "true",
 		InvitationCodeEnabled:        settings[SettingKeyInvitationCodeEnabled] == "true",
 		TotpEnabled:                  settings[SettingKeyTotpEnabled] == "true",
+		PasskeyLoginEnabled:          settings[SettingKeyPasskeyLoginEnabled] == "true",
 		SMTPHost:                     settings[SettingKeySMTPHost],
 		SMTPUsername:                 settings[SettingKeySMTPUsername],
 		SMTPFrom:                     settings[SettingKeySMTPFrom],
@@ -570,6 +593,15 @@ func (s *SettingService) GetTurnstileSecretKey(ctx context.Context) string {
 	return value
 }
 
+// GetTurnstileSiteKey 获取 Turnstile Site Key
+func (s *SettingService) GetTurnstileSiteKey(ctx context.Context) string {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyTurnstileSiteKey)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
 // IsIdentityPatchEnabled 检查是否启用身份补丁（Claude -> Gemini systemInstruction 注入）
 func (s *SettingService) IsIdentityPatchEnabled(ctx context.Context) bool {
 	value, err := s.settingRepo.GetValue(ctx, SettingKeyEnableIdentityPatch)
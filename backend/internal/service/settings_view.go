@@ -7,6 +7,7 @@ type SystemSettings struct {
 	PasswordResetEnabled  bool
 	InvitationCodeEnabled bool
 	TotpEnabled           bool // TOTP 双因素认证
+	PasskeyLoginEnabled   bool // 通行密钥无密码登录
 
 	SMTPHost               string
 	SMTPPort               int
@@ -71,6 +72,7 @@ type PublicSettings struct {
 	PasswordResetEnabled  bool
 	InvitationCodeEnabled bool
 	TotpEnabled           bool // TOTP 双因素认证
+	PasskeyLoginEnabled   bool // 通行密钥无密码登录
 	TurnstileEnabled      bool
 	TurnstileSiteKey      string
 	SiteName              string
@@ -86,6 +88,8 @@ type PublicSettings struct {
 	PurchaseSubscriptionURL     string
 
 	LinuxDoOAuthEnabled bool
+	OIDCProviders       []OIDCPublicProvider
+	ReferralEnabled     bool // 邀请返利
 	Version             string
 }
 
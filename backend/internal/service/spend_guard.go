@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 消费异常检测范围
+const (
+	SpendGuardScopeAPIKey = "api_key"
+	SpendGuardScopeUser   = "user"
+)
+
+// 消费异常触发规则
+const (
+	SpendGuardRuleMultiplier = "multiplier"
+	SpendGuardRuleAbsolute   = "absolute"
+)
+
+// 消费异常停用记录状态
+const (
+	SpendSuspensionActive  = "active"
+	SpendSuspensionExpired = "expired"
+	SpendSuspensionLifted  = "lifted"
+)
+
+// spendGuardWindow 消费速率统计窗口（同时也是历史峰值的分桶粒度）
+const spendGuardWindow = 10 * time.Minute
+
+// APIKeySpend 统计窗口内单个 Key 的消费
+type APIKeySpend struct {
+	APIKeyID int64   `json:"api_key_id"`
+	UserID   int64   `json:"user_id"`
+	Spend    float64 `json:"spend"`
+}
+
+// SpendGuardTrigger 一次消费异常判定结果
+type SpendGuardTrigger struct {
+	Rule      string  `json:"rule"`
+	Spend     float64 `json:"spend"`
+	Baseline  float64 `json:"baseline"`
+	Threshold float64 `json:"threshold"`
+}
+
+// SpendSuspension 消费异常导致的 Key 临时停用记录
+type SpendSuspension struct {
+	ID       int64  `json:"id"`
+	APIKeyID int64  `json:"api_key_id"`
+	UserID   int64  `json:"user_id"`
+	Scope    string `json:"scope"`
+	Rule     string `json:"rule"`
+
+	WindowSpend    float64 `json:"window_spend"`
+	BaselineSpend  float64 `json:"baseline_spend"`
+	ThresholdSpend float64 `json:"threshold_spend"`
+
+	Status         string     `json:"status"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+
+	AlertEventID   *int64     `json:"alert_event_id,omitempty"`
+	UserNotifiedAt *time.Time `json:"user_notified_at,omitempty"`
+
+	LiftedBy *int64     `json:"lifted_by,omitempty"`
+	LiftedAt *time.Time `json:"lifted_at,omitempty"`
+	LiftNote string     `json:"lift_note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 关联信息（列表展示用）
+	APIKeyName   string `json:"api_key_name"`
+	APIKeyStatus string `json:"api_key_status"`
+	UserEmail    string `json:"user_email"`
+}
+
+// SpendSuspensionFilter 停用记录列表过滤条件
+type SpendSuspensionFilter struct {
+	Status string
+	UserID int64
+}
+
+// SpendGuardScanResult 一次检测的结果汇总
+type SpendGuardScanResult struct {
+	Checked   int `json:"checked"`
+	Suspended int `json:"suspended"`
+	Expired   int `json:"expired"`
+}
+
+// SpendGuardSettings 消费异常自动停用配置（存储在 settings 表，key = spend_guard_settings）
+type SpendGuardSettings struct {
+	Enabled bool `json:"enabled"`
+	// BaselineDays 历史基线回溯天数：取该期间内 10 分钟消费的峰值作为基线
+	BaselineDays int `json:"baseline_days"`
+	// Multiplier 最近 10 分钟消费超过 max(基线, BaselineFloorUSD) 的倍数时触发；0 表示关闭倍数规则
+	Multiplier float64 `json:"multiplier"`
+	// BaselineFloorUSD 基线下限，避免新用户或低消费用户的基线过低导致误报
+	BaselineFloorUSD float64 `json:"baseline_floor_usd"`
+	// AbsoluteUSD 最近 10 分钟消费达到该金额时触发；0 表示关闭绝对金额规则
+	AbsoluteUSD float64 `json:"absolute_usd"`
+	// SuspendMinutes 临时停用时长；0 表示需管理员手动解除
+	SuspendMinutes int `json:"suspend_minutes"`
+	// NotifyUser 停用后邮件通知用户
+	NotifyUser bool `json:"notify_user"`
+}
+
+// DefaultSpendGuardSettings 返回默认配置（默认关闭）
+func DefaultSpendGuardSettings() *SpendGuardSettings {
+	return &SpendGuardSettings{
+		Enabled:          false,
+		BaselineDays:     7,
+		Multiplier:       5,
+		BaselineFloorUSD: 1,
+		AbsoluteUSD:      50,
+		SuspendMinutes:   60,
+		NotifyUser:       true,
+	}
+}
+
+// SpendGuardRepository 消费异常检测数据访问接口
+type SpendGuardRepository interface {
+	// ListRecentSpend 返回 [start, end) 内有消费的 Key
+	ListRecentSpend(ctx context.Context, start, end time.Time) ([]APIKeySpend, error)
+	// GetPeakSpend 返回 [start, end) 内按 10 分钟分桶的消费峰值（scope 为 api_key 时按 Key，为 user 时按用户）
+	GetPeakSpend(ctx context.Context, scope string, ids []int64, start, end time.Time) (map[int64]float64, error)
+
+	// CreateSuspension 创建停用记录；Key 已有生效中的记录时返回 nil, nil
+	CreateSuspension(ctx context.Context, suspension *SpendSuspension) (*SpendSuspension, error)
+	GetSuspensionByID(ctx context.Context, id int64) (*SpendSuspension, error)
+	ListSuspensions(ctx context.Context, params pagination.PaginationParams, filter SpendSuspensionFilter) ([]SpendSuspension, *pagination.PaginationResult, error)
+	ListExpiredSuspensions(ctx context.Context, now time.Time) ([]SpendSuspension, error)
+	// CloseSuspension 将生效中的记录置为 expired / lifted
+	CloseSuspension(ctx context.Context, id int64, status string, liftedBy *int64, note string) error
+	SetAlertEventID(ctx context.Context, id, eventID int64) error
+	MarkUserNotified(ctx context.Context, id int64, at time.Time) error
+	// HasOtherSuspensionHold Key 是否仍因其他原因（如共享检测）处于停用状态，此时到期不自动恢复
+	HasOtherSuspensionHold(ctx context.Context, apiKeyID int64) (bool, error)
+}
+
+// evaluateSpendVelocity 判定最近 10 分钟消费是否异常。
+// 绝对金额规则优先；倍数规则以 max(历史峰值, 基线下限) 为基线。
+func evaluateSpendVelocity(spend, peak float64, settings *SpendGuardSettings) *SpendGuardTrigger {
+	if spend <= 0 || settings == nil {
+		return nil
+	}
+	if settings.AbsoluteUSD > 0 && spend >= settings.AbsoluteUSD {
+		return &SpendGuardTrigger{Rule: SpendGuardRuleAbsolute, Spend: spend, Baseline: peak, Threshold: settings.AbsoluteUSD}
+	}
+	if settings.Multiplier > 0 {
+		threshold := math.Max(peak, settings.BaselineFloorUSD) * settings.Multiplier
+		if threshold > 0 && spend > threshold {
+			return &SpendGuardTrigger{Rule: SpendGuardRuleMultiplier, Spend: spend, Baseline: peak, Threshold: threshold}
+		}
+	}
+	return nil
+}
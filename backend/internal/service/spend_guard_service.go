@@ -0,0 +1,579 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	spendGuardTickInterval = time.Minute
+	spendGuardScanSlotKey  = "spend_guard:scan"
+	spendGuardScanTimeout  = 50 * time.Second
+	spendGuardAlertSource  = "spend_guard"
+	spendGuardAlertLevel   = "P1"
+)
+
+var (
+	ErrSpendSuspensionNotFound = infraerrors.NotFound("SPEND_SUSPENSION_NOT_FOUND", "spend suspension not found")
+	ErrSpendSuspensionClosed   = infraerrors.Conflict("SPEND_SUSPENSION_CLOSED", "spend suspension is no longer active")
+)
+
+// spendGuardBaseline 缓存的历史峰值（基线不含当前窗口，短时间内变化很小）
+type spendGuardBaseline struct {
+	peak     float64
+	loadedAt time.Time
+}
+
+// SpendGuardService 消费速率异常自动停用服务
+//
+// 每分钟统计最近 10 分钟每个 Key 及每个用户的消费，与其自身历史 10 分钟消费峰值比较，
+// 超过配置倍数或绝对金额时临时停用相关 Key（经认证缓存失效立即生效），邮件通知用户，
+// 并生成运维告警事件供管理员审核。停用到期后自动恢复，管理员也可提前解除。
+//
+// - Key 维度：停用该 Key
+// - 用户维度：停用该用户在窗口内有消费的所有 Key
+// - 调度：Redis 扫描槽位保证多实例下每分钟只执行一次；Redis 不可用时各实例各自执行
+type SpendGuardService struct {
+	guardRepo     SpendGuardRepository
+	apiKeyRepo    APIKeyRepository
+	userRepo      UserRepository
+	settingRepo   SettingRepository
+	opsRepo       OpsRepository
+	apiKeyService *APIKeyService
+	emailService  *EmailService
+	redisClient   *redis.Client
+
+	instanceID string
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+
+	baselineMu sync.Mutex
+	baselines  map[string]spendGuardBaseline
+
+	warnNoRedisOnce sync.Once
+}
+
+// NewSpendGuardService 创建消费异常自动停用服务
+func NewSpendGuardService(
+	guardRepo SpendGuardRepository,
+	apiKeyRepo APIKeyRepository,
+	userRepo UserRepository,
+	settingRepo SettingRepository,
+	opsRepo OpsRepository,
+	apiKeyService *APIKeyService,
+	emailService *EmailService,
+	redisClient *redis.Client,
+) *SpendGuardService {
+	return &SpendGuardService{
+		guardRepo:     guardRepo,
+		apiKeyRepo:    apiKeyRepo,
+		userRepo:      userRepo,
+		settingRepo:   settingRepo,
+		opsRepo:       opsRepo,
+		apiKeyService: apiKeyService,
+		emailService:  emailService,
+		redisClient:   redisClient,
+		instanceID:    uuid.NewString(),
+		stopCh:        make(chan struct{}),
+		baselines:     make(map[string]spendGuardBaseline),
+	}
+}
+
+// Start 启动后台检测循环
+func (s *SpendGuardService) Start() {
+	if s == nil || s.guardRepo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台检测循环
+func (s *SpendGuardService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *SpendGuardService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(spendGuardTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// GetSettings 读取配置，未配置或解析失败时返回默认配置
+func (s *SpendGuardService) GetSettings(ctx context.Context) (*SpendGuardSettings, error) {
+	if s.settingRepo == nil {
+		return DefaultSpendGuardSettings(), nil
+	}
+	value, err := s.settingRepo.GetValue(ctx, SettingKeySpendGuardSettings)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return DefaultSpendGuardSettings(), nil
+		}
+		return nil, fmt.Errorf("get spend guard settings: %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return DefaultSpendGuardSettings(), nil
+	}
+
+	settings := DefaultSpendGuardSettings()
+	if err := json.Unmarshal([]byte(value), settings); err != nil {
+		return DefaultSpendGuardSettings(), nil
+	}
+	normalizeSpendGuardSettings(settings)
+	return settings, nil
+}
+
+// UpdateSettings 校验并保存配置
+func (s *SpendGuardService) UpdateSettings(ctx context.Context, settings *SpendGuardSettings) (*SpendGuardSettings, error) {
+	if settings == nil {
+		return nil, infraerrors.BadRequest("SPEND_GUARD_INVALID_SETTINGS", "settings cannot be nil")
+	}
+	if err := validateSpendGuardSettings(settings); err != nil {
+		return nil, infraerrors.BadRequest("SPEND_GUARD_INVALID_SETTINGS", err.Error())
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("marshal spend guard settings: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeySpendGuardSettings, string(data)); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func validateSpendGuardSettings(settings *SpendGuardSettings) error {
+	if settings.BaselineDays < 1 || settings.BaselineDays > 90 {
+		return errors.New("baseline_days must be between 1-90")
+	}
+	if settings.Multiplier != 0 && (settings.Multiplier <= 1 || settings.Multiplier > 1000) {
+		return errors.New("multiplier must be 0 (disabled) or greater than 1 and at most 1000")
+	}
+	if settings.BaselineFloorUSD < 0 || settings.BaselineFloorUSD > 1000000 {
+		return errors.New("baseline_floor_usd must be between 0-1000000")
+	}
+	if settings.AbsoluteUSD < 0 || settings.AbsoluteUSD > 1000000 {
+		return errors.New("absolute_usd must be between 0-1000000")
+	}
+	if settings.Enabled && settings.Multiplier == 0 && settings.AbsoluteUSD == 0 {
+		return errors.New("at least one of multiplier or absolute_usd must be set when enabled")
+	}
+	if settings.SuspendMinutes < 0 || settings.SuspendMinutes > 10080 {
+		return errors.New("suspend_minutes must be between 0-10080")
+	}
+	return nil
+}
+
+// normalizeSpendGuardSettings 修正越界值，保证后台任务始终拿到可用配置
+func normalizeSpendGuardSettings(settings *SpendGuardSettings) {
+	defaults := DefaultSpendGuardSettings()
+	if settings.BaselineDays < 1 || settings.BaselineDays > 90 {
+		settings.BaselineDays = defaults.BaselineDays
+	}
+	if settings.Multiplier < 0 || (settings.Multiplier > 0 && settings.Multiplier <= 1) {
+		settings.Multiplier = defaults.Multiplier
+	}
+	if settings.BaselineFloorUSD < 0 {
+		settings.BaselineFloorUSD = defaults.BaselineFloorUSD
+	}
+	if settings.AbsoluteUSD < 0 {
+		settings.AbsoluteUSD = defaults.AbsoluteUSD
+	}
+	if settings.SuspendMinutes < 0 {
+		settings.SuspendMinutes = defaults.SuspendMinutes
+	}
+}
+
+// ListSuspensions 分页列出停用记录
+func (s *SpendGuardService) ListSuspensions(ctx context.Context, params pagination.PaginationParams, filter SpendSuspensionFilter) ([]SpendSuspension, *pagination.PaginationResult, error) {
+	return s.guardRepo.ListSuspensions(ctx, params, filter)
+}
+
+// Lift 管理员提前解除停用，并将关联的告警事件标记为已处理
+func (s *SpendGuardService) Lift(ctx context.Context, id int64, actorUserID *int64, note string) (*SpendSuspension, error) {
+	suspension, err := s.guardRepo.GetSuspensionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if suspension.Status != SpendSuspensionActive {
+		return nil, ErrSpendSuspensionClosed
+	}
+	if err := s.restoreKey(ctx, suspension.APIKeyID); err != nil {
+		return nil, err
+	}
+	if err := s.guardRepo.CloseSuspension(ctx, id, SpendSuspensionLifted, actorUserID, strings.TrimSpace(note)); err != nil {
+		return nil, err
+	}
+	if suspension.AlertEventID != nil && s.opsRepo != nil {
+		now := time.Now()
+		if err := s.opsRepo.UpdateAlertEventStatus(ctx, *suspension.AlertEventID, OpsAlertStatusManualResolved, &now); err != nil {
+			log.Printf("[SpendGuard] resolve alert event failed: event=%d err=%v", *suspension.AlertEventID, err)
+		}
+	}
+	return s.guardRepo.GetSuspensionByID(ctx, id)
+}
+
+func (s *SpendGuardService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), spendGuardScanTimeout)
+	defer cancel()
+
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		log.Printf("[SpendGuard] load settings failed: %v", err)
+		return
+	}
+	if !s.tryAcquireScanSlot(ctx) {
+		return
+	}
+
+	// 到期恢复不受 enabled 开关影响，避免关闭检测后已停用的 Key 无法自动恢复
+	expired := s.expireSuspensions(ctx, time.Now())
+	if expired > 0 {
+		log.Printf("[SpendGuard] restored %d api keys after temporary suspension", expired)
+	}
+	if !settings.Enabled {
+		return
+	}
+
+	result, err := s.check(ctx, settings, time.Now())
+	if err != nil {
+		log.Printf("[SpendGuard] check failed: %v", err)
+		return
+	}
+	if result.Suspended > 0 {
+		log.Printf("[SpendGuard] checked %d keys: suspended=%d", result.Checked, result.Suspended)
+	}
+}
+
+// check 检测最近 10 分钟的消费并停用异常 Key
+func (s *SpendGuardService) check(ctx context.Context, settings *SpendGuardSettings, now time.Time) (*SpendGuardScanResult, error) {
+	windowStart := now.Add(-spendGuardWindow)
+	spends, err := s.guardRepo.ListRecentSpend(ctx, windowStart, now)
+	if err != nil {
+		return nil, fmt.Errorf("list recent spend: %w", err)
+	}
+	result := &SpendGuardScanResult{Checked: len(spends)}
+	if len(spends) == 0 {
+		return result, nil
+	}
+
+	keyIDs := make([]int64, 0, len(spends))
+	userSpend := make(map[int64]float64)
+	userKeys := make(map[int64][]int64)
+	for _, item := range spends {
+		keyIDs = append(keyIDs, item.APIKeyID)
+		userSpend[item.UserID] += item.Spend
+		userKeys[item.UserID] = append(userKeys[item.UserID], item.APIKeyID)
+	}
+	userIDs := make([]int64, 0, len(userSpend))
+	for id := range userSpend {
+		userIDs = append(userIDs, id)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	baselineStart := now.AddDate(0, 0, -settings.BaselineDays)
+	keyPeaks, err := s.peaks(ctx, SpendGuardScopeAPIKey, keyIDs, baselineStart, windowStart, now)
+	if err != nil {
+		return nil, fmt.Errorf("load api key baselines: %w", err)
+	}
+	userPeaks, err := s.peaks(ctx, SpendGuardScopeUser, userIDs, baselineStart, windowStart, now)
+	if err != nil {
+		return nil, fmt.Errorf("load user baselines: %w", err)
+	}
+
+	for _, item := range spends {
+		if trigger := evaluateSpendVelocity(item.Spend, keyPeaks[item.APIKeyID], settings); trigger != nil {
+			result.Suspended += s.suspendKeys(ctx, item.UserID, SpendGuardScopeAPIKey, trigger, []int64{item.APIKeyID}, settings, now)
+		}
+	}
+	for _, userID := range userIDs {
+		if trigger := evaluateSpendVelocity(userSpend[userID], userPeaks[userID], settings); trigger != nil {
+			result.Suspended += s.suspendKeys(ctx, userID, SpendGuardScopeUser, trigger, userKeys[userID], settings, now)
+		}
+	}
+	return result, nil
+}
+
+// peaks 返回历史 10 分钟消费峰值，未过期的缓存值直接复用
+func (s *SpendGuardService) peaks(ctx context.Context, scope string, ids []int64, start, end, now time.Time) (map[int64]float64, error) {
+	out := make(map[int64]float64, len(ids))
+	missing := make([]int64, 0, len(ids))
+
+	s.baselineMu.Lock()
+	for _, id := range ids {
+		cached, ok := s.baselines[spendGuardBaselineKey(scope, id)]
+		if ok && now.Sub(cached.loadedAt) < spendGuardWindow {
+			out[id] = cached.peak
+			continue
+		}
+		missing = append(missing, id)
+	}
+	s.baselineMu.Unlock()
+
+	if len(missing) == 0 {
+		return out, nil
+	}
+	loaded, err := s.guardRepo.GetPeakSpend(ctx, scope, missing, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	s.baselineMu.Lock()
+	defer s.baselineMu.Unlock()
+	for key, cached := range s.baselines {
+		if now.Sub(cached.loadedAt) >= spendGuardWindow {
+			delete(s.baselines, key)
+		}
+	}
+	for _, id := range missing {
+		out[id] = loaded[id]
+		s.baselines[spendGuardBaselineKey(scope, id)] = spendGuardBaseline{peak: loaded[id], loadedAt: now}
+	}
+	return out, nil
+}
+
+func spendGuardBaselineKey(scope string, id int64) string {
+	return fmt.Sprintf("%s:%d", scope, id)
+}
+
+// suspendKeys 停用一组 Key 并记录、告警、通知；返回本次新停用的 Key 数
+func (s *SpendGuardService) suspendKeys(ctx context.Context, userID int64, scope string, trigger *SpendGuardTrigger, keyIDs []int64, settings *SpendGuardSettings, now time.Time) int {
+	var until *time.Time
+	if settings.SuspendMinutes > 0 {
+		t := now.Add(time.Duration(settings.SuspendMinutes) * time.Minute)
+		until = &t
+	}
+
+	created := make([]*SpendSuspension, 0, len(keyIDs))
+	keyNames := make([]string, 0, len(keyIDs))
+	for _, keyID := range keyIDs {
+		apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+		if err != nil {
+			log.Printf("[SpendGuard] load api key failed: api_key=%d err=%v", keyID, err)
+			continue
+		}
+		// 已停用 / 禁用 / 过期的 Key 无法继续消费，无需处理
+		if apiKey.Status != StatusAPIKeyActive {
+			continue
+		}
+		suspension, err := s.guardRepo.CreateSuspension(ctx, &SpendSuspension{
+			APIKeyID:       keyID,
+			UserID:         userID,
+			Scope:          scope,
+			Rule:           trigger.Rule,
+			WindowSpend:    trigger.Spend,
+			BaselineSpend:  trigger.Baseline,
+			ThresholdSpend: trigger.Threshold,
+			SuspendedUntil: until,
+		})
+		if err != nil {
+			log.Printf("[SpendGuard] create suspension failed: api_key=%d err=%v", keyID, err)
+			continue
+		}
+		if suspension == nil {
+			continue
+		}
+		if err := setAPIKeySuspended(ctx, s.apiKeyRepo, s.apiKeyService, keyID, true); err != nil {
+			log.Printf("[SpendGuard] suspend api key failed: api_key=%d err=%v", keyID, err)
+			_ = s.guardRepo.CloseSuspension(ctx, suspension.ID, SpendSuspensionExpired, nil, "suspend failed")
+			continue
+		}
+		created = append(created, suspension)
+		keyNames = append(keyNames, apiKey.Name)
+	}
+	if len(created) == 0 {
+		return 0
+	}
+	log.Printf("[SpendGuard] suspended %d api keys of user %d (scope=%s rule=%s spend=%.4f threshold=%.4f)",
+		len(created), userID, scope, trigger.Rule, trigger.Spend, trigger.Threshold)
+
+	s.createAlertEvent(ctx, userID, scope, trigger, created, now)
+	if settings.NotifyUser {
+		s.notifyUser(ctx, userID, keyNames, until, created)
+	}
+	return len(created)
+}
+
+// createAlertEvent 生成运维告警事件（无关联规则），供管理员在告警事件中审核
+func (s *SpendGuardService) createAlertEvent(ctx context.Context, userID int64, scope string, trigger *SpendGuardTrigger, suspensions []*SpendSuspension, now time.Time) {
+	if s.opsRepo == nil {
+		return
+	}
+	keyIDs := make([]int64, 0, len(suspensions))
+	for _, item := range suspensions {
+		keyIDs = append(keyIDs, item.APIKeyID)
+	}
+	title := fmt.Sprintf("Spend anomaly: API key %d suspended", keyIDs[0])
+	if scope == SpendGuardScopeUser {
+		title = fmt.Sprintf("Spend anomaly: %d API keys of user %d suspended", len(keyIDs), userID)
+	}
+	description := fmt.Sprintf("Spent $%.4f in the last 10 minutes (threshold $%.4f, historical 10-minute peak $%.4f, rule %s).",
+		trigger.Spend, trigger.Threshold, trigger.Baseline, trigger.Rule)
+
+	metric := trigger.Spend
+	threshold := trigger.Threshold
+	event, err := s.opsRepo.CreateAlertEvent(ctx, &OpsAlertEvent{
+		Severity:       spendGuardAlertLevel,
+		Status:         OpsAlertStatusFiring,
+		Title:          title,
+		Description:    description,
+		MetricValue:    &metric,
+		ThresholdValue: &threshold,
+		Dimensions: map[string]any{
+			"source":      spendGuardAlertSource,
+			"scope":       scope,
+			"rule":        trigger.Rule,
+			"user_id":     userID,
+			"api_key_ids": keyIDs,
+		},
+		FiredAt: now,
+	})
+	if err != nil || event == nil {
+		log.Printf("[SpendGuard] create alert event failed: user=%d err=%v", userID, err)
+		return
+	}
+	for _, item := range suspensions {
+		if err := s.guardRepo.SetAlertEventID(ctx, item.ID, event.ID); err != nil {
+			log.Printf("[SpendGuard] link alert event failed: suspension=%d err=%v", item.ID, err)
+		}
+	}
+}
+
+// notifyUser 邮件通知用户（best-effort）
+func (s *SpendGuardService) notifyUser(ctx context.Context, userID int64, keyNames []string, until *time.Time, suspensions []*SpendSuspension) {
+	if s.emailService == nil || s.userRepo == nil {
+		return
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil || strings.TrimSpace(user.Email) == "" {
+		return
+	}
+	siteName := "Sub2API"
+	if s.settingRepo != nil {
+		if v, err := s.settingRepo.GetValue(ctx, SettingKeySiteName); err == nil && strings.TrimSpace(v) != "" {
+			siteName = strings.TrimSpace(v)
+		}
+	}
+
+	subject, body := buildSpendGuardNoticeEmail(siteName, keyNames, until)
+	if err := s.emailService.SendEmail(ctx, user.Email, subject, body); err != nil {
+		log.Printf("[SpendGuard] notify user failed: user=%d err=%v", userID, err)
+		return
+	}
+	now := time.Now()
+	for _, item := range suspensions {
+		if err := s.guardRepo.MarkUserNotified(ctx, item.ID, now); err != nil {
+			log.Printf("[SpendGuard] mark user notified failed: suspension=%d err=%v", item.ID, err)
+		}
+	}
+}
+
+func buildSpendGuardNoticeEmail(siteName string, keyNames []string, until *time.Time) (string, string) {
+	subject := fmt.Sprintf("[%s] API key suspended due to unusual spending", siteName)
+
+	items := make([]string, 0, len(keyNames))
+	for _, name := range keyNames {
+		items = append(items, "<li><strong>"+html.EscapeString(name)+"</strong></li>")
+	}
+	duration := "until an administrator reviews and restores them"
+	if until != nil {
+		duration = "until " + until.UTC().Format("2006-01-02 15:04 UTC")
+	}
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>We detected spending on your account that is far above your usual rate. To protect your balance, the following API keys have been temporarily suspended %s:</p>
+<ul>%s</ul>
+<p>If you did not make these requests, your key may have been leaked. Please delete it and create a new one after it is restored. If the usage was expected, please contact the administrator of %s.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(duration), strings.Join(items, ""), html.EscapeString(siteName))
+	return subject, body
+}
+
+// expireSuspensions 恢复停用到期的 Key；返回恢复的记录数
+func (s *SpendGuardService) expireSuspensions(ctx context.Context, now time.Time) int {
+	expired, err := s.guardRepo.ListExpiredSuspensions(ctx, now)
+	if err != nil {
+		log.Printf("[SpendGuard] list expired suspensions failed: %v", err)
+		return 0
+	}
+	count := 0
+	for i := range expired {
+		item := &expired[i]
+		if err := s.restoreKey(ctx, item.APIKeyID); err != nil {
+			log.Printf("[SpendGuard] restore api key failed: api_key=%d err=%v", item.APIKeyID, err)
+			continue
+		}
+		if err := s.guardRepo.CloseSuspension(ctx, item.ID, SpendSuspensionExpired, nil, ""); err != nil {
+			log.Printf("[SpendGuard] close suspension failed: suspension=%d err=%v", item.ID, err)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// restoreKey 恢复 Key；Key 仍因其他原因被停用时保持停用
+func (s *SpendGuardService) restoreKey(ctx context.Context, apiKeyID int64) error {
+	hold, err := s.guardRepo.HasOtherSuspensionHold(ctx, apiKeyID)
+	if err != nil {
+		return err
+	}
+	if hold {
+		return nil
+	}
+	err = setAPIKeySuspended(ctx, s.apiKeyRepo, s.apiKeyService, apiKeyID, false)
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return nil
+	}
+	return err
+}
+
+// tryAcquireScanSlot 占用本分钟的执行槽位
+func (s *SpendGuardService) tryAcquireScanSlot(ctx context.Context) bool {
+	if s.redisClient == nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[SpendGuard] redis not configured; running without scan lock")
+		})
+		return true
+	}
+	ttl := spendGuardTickInterval - spendGuardTickInterval/6
+	ok, err := s.redisClient.SetNX(ctx, spendGuardScanSlotKey, s.instanceID, ttl).Result()
+	if err != nil {
+		log.Printf("[SpendGuard] scan slot SetNX failed; skipping this cycle: %v", err)
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,165 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type spendGuardRepoStub struct {
+	SpendGuardRepository
+	spends    []APIKeySpend
+	keyPeaks  map[int64]float64
+	userPeaks map[int64]float64
+	active    map[int64]*SpendSuspension
+	expired   []SpendSuspension
+	closed    map[int64]string
+	holds     map[int64]bool
+}
+
+func (r *spendGuardRepoStub) ListRecentSpend(ctx context.Context, start, end time.Time) ([]APIKeySpend, error) {
+	return r.spends, nil
+}
+
+func (r *spendGuardRepoStub) GetPeakSpend(ctx context.Context, scope string, ids []int64, start, end time.Time) (map[int64]float64, error) {
+	if scope == SpendGuardScopeUser {
+		return r.userPeaks, nil
+	}
+	return r.keyPeaks, nil
+}
+
+func (r *spendGuardRepoStub) CreateSuspension(ctx context.Context, suspension *SpendSuspension) (*SpendSuspension, error) {
+	if r.active == nil {
+		r.active = map[int64]*SpendSuspension{}
+	}
+	if _, ok := r.active[suspension.APIKeyID]; ok {
+		return nil, nil
+	}
+	out := *suspension
+	out.ID = int64(len(r.active) + 1)
+	out.Status = SpendSuspensionActive
+	r.active[suspension.APIKeyID] = &out
+	return &out, nil
+}
+
+func (r *spendGuardRepoStub) ListExpiredSuspensions(ctx context.Context, now time.Time) ([]SpendSuspension, error) {
+	return r.expired, nil
+}
+
+func (r *spendGuardRepoStub) CloseSuspension(ctx context.Context, id int64, status string, liftedBy *int64, note string) error {
+	if r.closed == nil {
+		r.closed = map[int64]string{}
+	}
+	r.closed[id] = status
+	return nil
+}
+
+func (r *spendGuardRepoStub) HasOtherSuspensionHold(ctx context.Context, apiKeyID int64) (bool, error) {
+	return r.holds[apiKeyID], nil
+}
+
+func TestEvaluateSpendVelocity(t *testing.T) {
+	settings := DefaultSpendGuardSettings()
+
+	// 基线峰值 $4，阈值 $20
+	require.Nil(t, evaluateSpendVelocity(15, 4, settings))
+	trigger := evaluateSpendVelocity(25, 4, settings)
+	require.NotNil(t, trigger)
+	require.Equal(t, SpendGuardRuleMultiplier, trigger.Rule)
+	require.InDelta(t, 20.0, trigger.Threshold, 1e-9)
+
+	// 无历史时使用基线下限：$1 * 5
+	require.Nil(t, evaluateSpendVelocity(4, 0, settings))
+	require.NotNil(t, evaluateSpendVelocity(6, 0, settings))
+
+	// 高消费用户不触发倍数规则，但仍受绝对金额限制
+	trigger = evaluateSpendVelocity(60, 30, settings)
+	require.NotNil(t, trigger)
+	require.Equal(t, SpendGuardRuleAbsolute, trigger.Rule)
+
+	settings.AbsoluteUSD = 0
+	require.Nil(t, evaluateSpendVelocity(60, 30, settings))
+}
+
+func TestValidateSpendGuardSettings(t *testing.T) {
+	require.NoError(t, validateSpendGuardSettings(DefaultSpendGuardSettings()))
+
+	s := DefaultSpendGuardSettings()
+	s.Multiplier = 1
+	require.Error(t, validateSpendGuardSettings(s))
+
+	s = DefaultSpendGuardSettings()
+	s.Enabled = true
+	s.Multiplier = 0
+	s.AbsoluteUSD = 0
+	require.Error(t, validateSpendGuardSettings(s))
+
+	s = DefaultSpendGuardSettings()
+	s.SuspendMinutes = -1
+	require.Error(t, validateSpendGuardSettings(s))
+}
+
+func TestSpendGuardService_CheckSuspendsKeys(t *testing.T) {
+	repo := &spendGuardRepoStub{
+		spends: []APIKeySpend{
+			{APIKeyID: 1, UserID: 10, Spend: 30},
+			{APIKeyID: 2, UserID: 20, Spend: 3},
+			{APIKeyID: 3, UserID: 20, Spend: 3},
+			{APIKeyID: 4, UserID: 30, Spend: 8},
+		},
+		keyPeaks:  map[int64]float64{1: 2, 2: 2, 3: 2, 4: 10},
+		userPeaks: map[int64]float64{10: 2, 20: 1, 30: 10},
+	}
+	keys := &apiKeyAbuseKeyRepoStub{keys: map[int64]*APIKey{
+		1: {ID: 1, UserID: 10, Status: StatusAPIKeyActive},
+		2: {ID: 2, UserID: 20, Status: StatusAPIKeyActive},
+		3: {ID: 3, UserID: 20, Status: StatusAPIKeyActive},
+		4: {ID: 4, UserID: 30, Status: StatusAPIKeyActive},
+	}}
+	svc := NewSpendGuardService(repo, keys, nil, nil, nil, nil, nil, nil)
+
+	settings := DefaultSpendGuardSettings()
+	settings.NotifyUser = false
+	now := time.Now()
+
+	result, err := svc.check(context.Background(), settings, now)
+	require.NoError(t, err)
+	// Key 1 自身异常；用户 20 的两个 Key 单独正常但合计 $6 > $5
+	require.Equal(t, &SpendGuardScanResult{Checked: 4, Suspended: 3}, result)
+	require.Equal(t, StatusAPIKeySuspended, keys.keys[1].Status)
+	require.Equal(t, StatusAPIKeySuspended, keys.keys[2].Status)
+	require.Equal(t, StatusAPIKeySuspended, keys.keys[3].Status)
+	require.Equal(t, StatusAPIKeyActive, keys.keys[4].Status)
+
+	require.Equal(t, SpendGuardScopeAPIKey, repo.active[1].Scope)
+	require.Equal(t, SpendGuardScopeUser, repo.active[2].Scope)
+	require.NotNil(t, repo.active[1].SuspendedUntil)
+	require.WithinDuration(t, now.Add(time.Hour), *repo.active[1].SuspendedUntil, time.Second)
+
+	// 再次检测不会重复停用
+	result, err = svc.check(context.Background(), settings, now)
+	require.NoError(t, err)
+	require.Zero(t, result.Suspended)
+}
+
+func TestSpendGuardService_ExpireRestoresKeys(t *testing.T) {
+	repo := &spendGuardRepoStub{
+		expired: []SpendSuspension{{ID: 1, APIKeyID: 1}, {ID: 2, APIKeyID: 2}},
+		holds:   map[int64]bool{2: true},
+	}
+	keys := &apiKeyAbuseKeyRepoStub{keys: map[int64]*APIKey{
+		1: {ID: 1, Status: StatusAPIKeySuspended},
+		2: {ID: 2, Status: StatusAPIKeySuspended},
+	}}
+	svc := NewSpendGuardService(repo, keys, nil, nil, nil, nil, nil, nil)
+
+	require.Equal(t, 2, svc.expireSuspensions(context.Background(), time.Now()))
+	require.Equal(t, StatusAPIKeyActive, keys.keys[1].Status)
+	// 共享检测的停用仍在生效，保持停用
+	require.Equal(t, StatusAPIKeySuspended, keys.keys[2].Status)
+	require.Equal(t, map[int64]string{1: SpendSuspensionExpired, 2: SpendSuspensionExpired}, repo.closed)
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 续费策略
+const (
+	SubscriptionRenewalNone   = "none"   // 一次性套餐，有效期内不可续费
+	SubscriptionRenewalManual = "manual" // 可手动续费
+	SubscriptionRenewalAuto   = "auto"   // 可手动续费，也可开启到期自动续费
+)
+
+const (
+	SubscriptionPlanStatusActive   = "active"
+	SubscriptionPlanStatusDisabled = "disabled"
+)
+
+// 订单类型
+const (
+	SubscriptionOrderKindPurchase  = "purchase"
+	SubscriptionOrderKindRenew     = "renew"
+	SubscriptionOrderKindAutoRenew = "auto_renew"
+	SubscriptionOrderKindChange    = "change"
+)
+
+var (
+	ErrSubscriptionPlanNotFound      = infraerrors.NotFound("SUBSCRIPTION_PLAN_NOT_FOUND", "subscription plan not found")
+	ErrSubscriptionPlanUnavailable   = infraerrors.BadRequest("SUBSCRIPTION_PLAN_UNAVAILABLE", "subscription plan is not available")
+	ErrSubscriptionPlanNotRenewable  = infraerrors.BadRequest("SUBSCRIPTION_PLAN_NOT_RENEWABLE", "subscription plan cannot be renewed while the subscription is active")
+	ErrSubscriptionPlanNoAutoRenew   = infraerrors.BadRequest("SUBSCRIPTION_PLAN_NO_AUTO_RENEW", "subscription plan does not support auto-renewal")
+	ErrSubscriptionPlanInvalid       = infraerrors.BadRequest("SUBSCRIPTION_PLAN_INVALID", "invalid subscription plan")
+	ErrSubscriptionChangeInvalid     = infraerrors.BadRequest("SUBSCRIPTION_CHANGE_INVALID", "plan change requires an active subscription in another group")
+	ErrSubscriptionChangeTargetOwned = infraerrors.Conflict("SUBSCRIPTION_CHANGE_TARGET_ACTIVE", "an active subscription for the target group already exists, renew it instead")
+)
+
+// SubscriptionPlan 订阅套餐：在订阅分组之上定义余额售价、有效天数与续费策略
+type SubscriptionPlan struct {
+	ID            int64     `json:"id"`
+	GroupID       int64     `json:"group_id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	Price         float64   `json:"price"`
+	ValidityDays  int       `json:"validity_days"`
+	RenewalPolicy string    `json:"renewal_policy"`
+	Status        string    `json:"status"`
+	SortOrder     int       `json:"sort_order"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	GroupName string `json:"group_name"`
+}
+
+// IsActive 套餐是否上架
+func (p *SubscriptionPlan) IsActive() bool {
+	return p.Status == SubscriptionPlanStatusActive
+}
+
+// AllowsRenewal 有效期内是否允许续费
+func (p *SubscriptionPlan) AllowsRenewal() bool {
+	return p.RenewalPolicy == SubscriptionRenewalManual || p.RenewalPolicy == SubscriptionRenewalAuto
+}
+
+// AllowsAutoRenew 是否允许开启自动续费
+func (p *SubscriptionPlan) AllowsAutoRenew() bool {
+	return p.RenewalPolicy == SubscriptionRenewalAuto
+}
+
+// SubscriptionOrder 订阅订单（购买 / 续费 / 自动续费 / 升降级）
+type SubscriptionOrder struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	PlanID         *int64    `json:"plan_id,omitempty"`
+	PlanName       string    `json:"plan_name"`
+	GroupID        int64     `json:"group_id"`
+	SubscriptionID int64     `json:"subscription_id"`
+	Kind           string    `json:"kind"`
+	FromGroupID    *int64    `json:"from_group_id,omitempty"`
+	Price          float64   `json:"price"`
+	Credit         float64   `json:"credit"`
+	Amount         float64   `json:"amount"`
+	ValidityDays   int       `json:"validity_days"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// 关联信息（列表展示用）
+	UserEmail     string `json:"user_email,omitempty"`
+	GroupName     string `json:"group_name"`
+	FromGroupName string `json:"from_group_name,omitempty"`
+}
+
+// SubscriptionRenewal 用户在某分组上的自动续费设置与到期提醒状态
+type SubscriptionRenewal struct {
+	UserID          int64      `json:"user_id"`
+	GroupID         int64      `json:"group_id"`
+	PlanID          *int64     `json:"plan_id,omitempty"`
+	AutoRenew       bool       `json:"auto_renew"`
+	LastAttemptAt   *time.Time `json:"last_attempt_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	ReminderSentFor *time.Time `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// SubscriptionOrderFilter 订单列表筛选
+type SubscriptionOrderFilter struct {
+	UserID  int64
+	GroupID int64
+	Kind    string
+	Search  string
+}
+
+// SubscriptionPurchaseInput 购买请求（Intent: purchase / auto_renew / change）
+type SubscriptionPurchaseInput struct {
+	UserID      int64
+	PlanID      int64
+	Intent      string
+	FromGroupID int64
+}
+
+// SubscriptionPurchaseResult 购买结果
+type SubscriptionPurchaseResult struct {
+	Order   *SubscriptionOrder `json:"order"`
+	Balance float64            `json:"balance"`
+}
+
+// SubscriptionExpiringItem 即将到期的订阅（自动续费 / 到期提醒候选）
+type SubscriptionExpiringItem struct {
+	UserID    int64
+	GroupID   int64
+	ExpiresAt time.Time
+	PlanID    *int64
+	LastError string
+	Email     string
+	GroupName string
+}
+
+// SubscriptionPurchaseState 购买决策所需的当前状态（在事务内加锁读取）
+type SubscriptionPurchaseState struct {
+	Plan    *SubscriptionPlan
+	Intent  string
+	Balance float64
+	// Current 目标分组上未删除的订阅（可能已过期），没有则为 nil
+	Current *UserSubscription
+	// From / FromOrders 升降级时的原订阅及其全部订单（用于折算抵扣）
+	From       *UserSubscription
+	FromOrders []SubscriptionOrder
+	Now        time.Time
+}
+
+// SubscriptionPurchaseDecision 购买决策结果
+type SubscriptionPurchaseDecision struct {
+	Kind      string    `json:"kind"`
+	Credit    float64   `json:"credit"`
+	Amount    float64   `json:"amount"`
+	StartsAt  time.Time `json:"starts_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Extend 为 true 时在原有效期上累加，否则从 StartsAt 重新开始
+	Extend bool `json:"extend"`
+}
+
+// DecideSubscriptionPurchase 根据当前状态计算订单类型、抵扣、扣款与新的有效期
+//
+// - 目标分组订阅有效：续费，在原到期时间上累加（需套餐允许续费）
+// - 目标分组订阅已过期或不存在：从当前时间开始
+// - 升降级：原订阅中已付费且未使用的部分作为抵扣（见 subscriptionChangeCredit），原订阅立即结束；抵扣最多抵满新套餐价格，超出部分不退回余额
+func DecideSubscriptionPurchase(state *SubscriptionPurchaseState) (*SubscriptionPurchaseDecision, error) {
+	plan := state.Plan
+	if plan == nil || !plan.IsActive() || plan.ValidityDays <= 0 {
+		return nil, ErrSubscriptionPlanUnavailable
+	}
+	now := state.Now
+	current := state.Current
+	if current != nil && current.Status == SubscriptionStatusSuspended {
+		return nil, ErrSubscriptionSuspended
+	}
+	currentActive := current != nil && current.Status == SubscriptionStatusActive && now.Before(current.ExpiresAt)
+
+	decision := &SubscriptionPurchaseDecision{StartsAt: now}
+	switch state.Intent {
+	case SubscriptionOrderKindChange:
+		from := state.From
+		if from == nil || from.GroupID == plan.GroupID || from.Status != SubscriptionStatusActive || !now.Before(from.ExpiresAt) {
+			return nil, ErrSubscriptionChangeInvalid
+		}
+		if currentActive {
+			return nil, ErrSubscriptionChangeTargetOwned
+		}
+		decision.Kind = SubscriptionOrderKindChange
+		decision.Credit = math.Min(subscriptionChangeCredit(from, state.FromOrders, now), plan.Price)
+	case SubscriptionOrderKindAutoRenew:
+		if !plan.AllowsAutoRenew() {
+			return nil, ErrSubscriptionPlanNoAutoRenew
+		}
+		if current == nil {
+			return nil, ErrSubscriptionNotFound
+		}
+		decision.Kind = SubscriptionOrderKindAutoRenew
+		decision.Extend = currentActive
+	case SubscriptionOrderKindPurchase:
+		decision.Kind = SubscriptionOrderKindPurchase
+		if currentActive {
+			if !plan.AllowsRenewal() {
+				return nil, ErrSubscriptionPlanNotRenewable
+			}
+			decision.Kind = SubscriptionOrderKindRenew
+			decision.Extend = true
+		}
+	default:
+		return nil, ErrSubscriptionPlanInvalid
+	}
+
+	base := now
+	if decision.Extend {
+		base = current.ExpiresAt
+	}
+	decision.ExpiresAt = base.AddDate(0, 0, plan.ValidityDays)
+	if decision.ExpiresAt.After(MaxExpiresAt) {
+		decision.ExpiresAt = MaxExpiresAt
+	}
+
+	decision.Amount = math.Max(roundSubscriptionAmount(plan.Price-decision.Credit), 0)
+	if decision.Amount > 0 && decision.Amount > state.Balance {
+		return nil, ErrInsufficientBalance
+	}
+	return decision, nil
+}
+
+// subscriptionChangeCredit 原订阅中已付费且未使用部分的价值
+//
+// 每笔订单覆盖 [到期时间-有效天数, 到期时间] 这段付费时长，价值为实际支付的金额（含当时的抵扣，不超过售价）。
+// 从最新订单往前依次截取，各段互不重叠且不超过原订阅的到期时间；管理员分配、兑换码等未付费获得的时长不抵扣。
+// 当前用量窗口内已消耗的额度按比例从窗口剩余时长的价值中扣除。
+func subscriptionChangeCredit(from *UserSubscription, orders []SubscriptionOrder, now time.Time) float64 {
+	type paidSegment struct {
+		start, end time.Time
+		perHour    float64
+	}
+	segments := make([]paidSegment, 0, len(orders))
+	cursor := from.ExpiresAt
+	for i := range orders {
+		order := &orders[i]
+		paid := math.Min(order.Price, order.Amount+order.Credit)
+		if paid <= 0 || order.ValidityDays <= 0 {
+			continue
+		}
+		periodStart := order.ExpiresAt.AddDate(0, 0, -order.ValidityDays)
+		period := order.ExpiresAt.Sub(periodStart)
+		end := order.ExpiresAt
+		if end.After(cursor) {
+			end = cursor
+		}
+		start := periodStart
+		if start.Before(now) {
+			start = now
+		}
+		if !end.After(start) {
+			continue
+		}
+		segments = append(segments, paidSegment{start: start, end: end, perHour: paid / period.Hours()})
+		cursor = start
+	}
+
+	var credit float64
+	for _, seg := range segments {
+		credit += seg.perHour * seg.end.Sub(seg.start).Hours()
+	}
+
+	// valueUntil 付费时长在 [now, t) 内的价值
+	valueUntil := func(t time.Time) float64 {
+		var v float64
+		for _, seg := range segments {
+			end := seg.end
+			if end.After(t) {
+				end = t
+			}
+			if end.After(seg.start) {
+				v += seg.perHour * end.Sub(seg.start).Hours()
+			}
+		}
+		return v
+	}
+	var usedValue float64
+	if group := from.Group; group != nil {
+		windows := []struct {
+			hasLimit bool
+			limit    *float64
+			usage    float64
+			resetAt  *time.Time
+		}{
+			{group.HasDailyLimit(), group.DailyLimitUSD, from.DailyUsageUSD, from.DailyResetTime()},
+			{group.HasWeeklyLimit(), group.WeeklyLimitUSD, from.WeeklyUsageUSD, from.WeeklyResetTime()},
+			{group.HasMonthlyLimit(), group.MonthlyLimitUSD, from.MonthlyUsageUSD, from.MonthlyResetTime()},
+		}
+		for _, w := range windows {
+			if !w.hasLimit || w.resetAt == nil || !w.resetAt.After(now) {
+				continue
+			}
+			usedRatio := math.Min(math.Max(w.usage / *w.limit, 0), 1)
+			usedValue = math.Max(usedValue, valueUntil(*w.resetAt)*usedRatio)
+		}
+	}
+	return roundSubscriptionAmount(math.Max(credit-usedValue, 0))
+}
+
+// SubscriptionOrderNote 写入订阅备注的订单说明
+func SubscriptionOrderNote(kind, planName string) string {
+	action := "购买"
+	switch kind {
+	case SubscriptionOrderKindRenew:
+		action = "续费"
+	case SubscriptionOrderKindAutoRenew:
+		action = "自动续费"
+	case SubscriptionOrderKindChange:
+		action = "变更"
+	}
+	return fmt.Sprintf("通过套餐 %s %s", planName, action)
+}
+
+func roundSubscriptionAmount(v float64) float64 {
+	return math.Round(v*1e8) / 1e8
+}
+
+// SubscriptionPlanRepository 订阅套餐数据访问
+type SubscriptionPlanRepository interface {
+	CreatePlan(ctx context.Context, plan *SubscriptionPlan) error
+	UpdatePlan(ctx context.Context, plan *SubscriptionPlan) error
+	DeletePlan(ctx context.Context, id int64) error
+	GetPlan(ctx context.Context, id int64) (*SubscriptionPlan, error)
+	ListPlans(ctx context.Context, activeOnly bool) ([]SubscriptionPlan, error)
+
+	// Purchase 在单个事务内锁定余额与订阅、计算决策（DecideSubscriptionPurchase）、扣款、写入订阅与订单
+	Purchase(ctx context.Context, input *SubscriptionPurchaseInput) (*SubscriptionPurchaseResult, error)
+	// ListSubscriptionOrders 某条订阅上的全部订单，按时间倒序
+	ListSubscriptionOrders(ctx context.Context, subscriptionID int64) ([]SubscriptionOrder, error)
+	ListOrders(ctx context.Context, params pagination.PaginationParams, filter SubscriptionOrderFilter) ([]SubscriptionOrder, *pagination.PaginationResult, error)
+
+	ListRenewals(ctx context.Context, userID int64) ([]SubscriptionRenewal, error)
+	// SetAutoRenew 开启 / 关闭自动续费，planID 为 nil 时保留原套餐
+	SetAutoRenew(ctx context.Context, userID, groupID int64, planID *int64, enabled bool) error
+	// ListAutoRenewDue 开启了自动续费、在 (expiredAfter, dueBefore) 内到期且上次尝试早于 retryBefore 的订阅
+	ListAutoRenewDue(ctx context.Context, expiredAfter, dueBefore, retryBefore time.Time, limit int) ([]SubscriptionExpiringItem, error)
+	RecordRenewalAttempt(ctx context.Context, userID, groupID int64, errMsg string) error
+	// ListReminderDue 未开启自动续费、在 [now, dueBefore) 内到期且尚未提醒过的订阅（仅限有可续费套餐的分组）
+	ListReminderDue(ctx context.Context, now, dueBefore time.Time, limit int) ([]SubscriptionExpiringItem, error)
+	MarkReminderSent(ctx context.Context, userID, groupID int64, expiresAt time.Time) error
+}
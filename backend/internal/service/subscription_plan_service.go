@@ -0,0 +1,614 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	subscriptionPlanWorkerInterval = 10 * time.Minute
+	subscriptionPlanWorkerSlotKey  = "subscription_plan:renew"
+	subscriptionPlanWorkerTimeout  = 5 * time.Minute
+	subscriptionPlanWorkerBatch    = 200
+	subscriptionPlanEmailTimeout   = 30 * time.Second
+
+	// 到期前该时间内尝试自动续费
+	subscriptionAutoRenewLead = 24 * time.Hour
+	// 自动续费失败后的重试间隔
+	subscriptionAutoRenewRetry = 6 * time.Hour
+	// 到期超过该时间仍未续费成功则不再尝试
+	subscriptionAutoRenewGrace = 3 * 24 * time.Hour
+	// 到期前该时间内发送到期提醒（未开启自动续费的订阅）
+	subscriptionReminderLead = 3 * 24 * time.Hour
+
+	subscriptionPlanMaxPrice = 1000000
+)
+
+// SubscriptionPlanInput 创建 / 更新套餐输入
+type SubscriptionPlanInput struct {
+	GroupID       int64
+	Name          string
+	Description   string
+	Price         float64
+	ValidityDays  int
+	RenewalPolicy string
+	Status        string
+	SortOrder     int
+}
+
+// SubscriptionQuote 购买 / 升降级报价
+type SubscriptionQuote struct {
+	Plan       *SubscriptionPlan             `json:"plan"`
+	Decision   *SubscriptionPurchaseDecision `json:"decision"`
+	Balance    float64                       `json:"balance"`
+	Sufficient bool                          `json:"sufficient"`
+}
+
+// SubscriptionPlanService 自助订阅套餐
+//
+// - 用户使用余额购买 / 续费套餐，在同一事务内扣款并分配或延长订阅
+// - 升降级：原订阅剩余时长按日均价格折算抵扣新套餐，原订阅立即结束
+// - 后台每 10 分钟执行一次：到期前 24 小时内自动续费（失败每 6 小时重试并邮件通知），到期前 3 天发送到期提醒
+type SubscriptionPlanService struct {
+	repo                 SubscriptionPlanRepository
+	groupRepo            GroupRepository
+	userRepo             UserRepository
+	userSubRepo          UserSubscriptionRepository
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+	emailService         *EmailService
+	settingService       *SettingService
+	redisClient          *redis.Client
+
+	instanceID string
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+
+	warnNoRedisOnce sync.Once
+}
+
+// NewSubscriptionPlanService 创建订阅套餐服务
+func NewSubscriptionPlanService(
+	repo SubscriptionPlanRepository,
+	groupRepo GroupRepository,
+	userRepo UserRepository,
+	userSubRepo UserSubscriptionRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	emailService *EmailService,
+	settingService *SettingService,
+	redisClient *redis.Client,
+) *SubscriptionPlanService {
+	return &SubscriptionPlanService{
+		repo:                 repo,
+		groupRepo:            groupRepo,
+		userRepo:             userRepo,
+		userSubRepo:          userSubRepo,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		emailService:         emailService,
+		settingService:       settingService,
+		redisClient:          redisClient,
+		instanceID:           uuid.NewString(),
+		stopCh:               make(chan struct{}),
+	}
+}
+
+// Start 启动后台续费 / 提醒循环
+func (s *SubscriptionPlanService) Start() {
+	if s == nil || s.repo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台循环
+func (s *SubscriptionPlanService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *SubscriptionPlanService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(subscriptionPlanWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *SubscriptionPlanService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), subscriptionPlanWorkerTimeout)
+	defer cancel()
+
+	if !s.tryAcquireWorkerSlot(ctx) {
+		return
+	}
+	now := time.Now()
+	if renewed, failed, err := s.ProcessAutoRenewals(ctx, now); err != nil {
+		log.Printf("[SubscriptionPlan] auto-renew failed: %v", err)
+	} else if renewed > 0 || failed > 0 {
+		log.Printf("[SubscriptionPlan] auto-renewed %d subscriptions, %d failed", renewed, failed)
+	}
+	if sent, err := s.SendExpiryReminders(ctx, now); err != nil {
+		log.Printf("[SubscriptionPlan] send reminders failed: %v", err)
+	} else if sent > 0 {
+		log.Printf("[SubscriptionPlan] sent %d expiry reminders", sent)
+	}
+}
+
+// ==================== 管理员 ====================
+
+// ListPlans 管理员查看全部套餐
+func (s *SubscriptionPlanService) ListPlans(ctx context.Context) ([]SubscriptionPlan, error) {
+	return s.repo.ListPlans(ctx, false)
+}
+
+// CreatePlan 创建套餐
+func (s *SubscriptionPlanService) CreatePlan(ctx context.Context, input *SubscriptionPlanInput) (*SubscriptionPlan, error) {
+	plan := &SubscriptionPlan{}
+	if err := s.applyPlanInput(ctx, plan, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPlan(ctx, plan.ID)
+}
+
+// UpdatePlan 更新套餐；已售出的订阅不受影响，新价格从下一次购买 / 续费开始生效
+func (s *SubscriptionPlanService) UpdatePlan(ctx context.Context, id int64, input *SubscriptionPlanInput) (*SubscriptionPlan, error) {
+	plan, err := s.repo.GetPlan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPlanInput(ctx, plan, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPlan(ctx, id)
+}
+
+// DeletePlan 删除套餐；订单保留套餐名称快照，引用该套餐的自动续费会在下次续费时失败并通知用户
+func (s *SubscriptionPlanService) DeletePlan(ctx context.Context, id int64) error {
+	return s.repo.DeletePlan(ctx, id)
+}
+
+// ListOrders 管理员分页查看订单
+func (s *SubscriptionPlanService) ListOrders(ctx context.Context, params pagination.PaginationParams, filter SubscriptionOrderFilter) ([]SubscriptionOrder, *pagination.PaginationResult, error) {
+	return s.repo.ListOrders(ctx, params, filter)
+}
+
+func (s *SubscriptionPlanService) applyPlanInput(ctx context.Context, plan *SubscriptionPlan, input *SubscriptionPlanInput) error {
+	if input == nil {
+		return ErrSubscriptionPlanInvalid
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len([]rune(name)) > 100 {
+		return subscriptionPlanInvalid("name", "name is required and must be at most 100 characters")
+	}
+	if input.Price < 0 || input.Price > subscriptionPlanMaxPrice || math.IsNaN(input.Price) {
+		return subscriptionPlanInvalid("price", "price must be between 0 and 1000000")
+	}
+	if input.ValidityDays <= 0 || input.ValidityDays > MaxValidityDays {
+		return subscriptionPlanInvalid("validity_days", fmt.Sprintf("validity_days must be between 1 and %d", MaxValidityDays))
+	}
+	policy := strings.TrimSpace(input.RenewalPolicy)
+	if policy == "" {
+		policy = SubscriptionRenewalAuto
+	}
+	switch policy {
+	case SubscriptionRenewalNone, SubscriptionRenewalManual, SubscriptionRenewalAuto:
+	default:
+		return subscriptionPlanInvalid("renewal_policy", "renewal_policy must be none, manual or auto")
+	}
+	status := strings.TrimSpace(input.Status)
+	if status == "" {
+		status = SubscriptionPlanStatusActive
+	}
+	if status != SubscriptionPlanStatusActive && status != SubscriptionPlanStatusDisabled {
+		return subscriptionPlanInvalid("status", "status must be active or disabled")
+	}
+
+	group, err := s.groupRepo.GetByID(ctx, input.GroupID)
+	if err != nil {
+		return err
+	}
+	if !group.IsSubscriptionType() {
+		return ErrGroupNotSubscriptionType
+	}
+
+	plan.GroupID = input.GroupID
+	plan.Name = name
+	plan.Description = truncateString(strings.TrimSpace(input.Description), 1000)
+	plan.Price = roundSubscriptionAmount(input.Price)
+	plan.ValidityDays = input.ValidityDays
+	plan.RenewalPolicy = policy
+	plan.Status = status
+	plan.SortOrder = input.SortOrder
+	return nil
+}
+
+func subscriptionPlanInvalid(field, message string) error {
+	return infraerrors.BadRequest("SUBSCRIPTION_PLAN_INVALID", message).WithMetadata(map[string]string{"field": field})
+}
+
+// ==================== 用户 ====================
+
+// ListAvailablePlans 用户可购买的套餐（已上架且分组可用）
+func (s *SubscriptionPlanService) ListAvailablePlans(ctx context.Context) ([]SubscriptionPlan, error) {
+	return s.repo.ListPlans(ctx, true)
+}
+
+// Quote 计算购买 / 续费 / 升降级的价格与新的到期时间（不加锁，仅供展示，以实际下单为准）
+func (s *SubscriptionPlanService) Quote(ctx context.Context, userID, planID, fromGroupID int64) (*SubscriptionQuote, error) {
+	plan, err := s.getAvailablePlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	state := &SubscriptionPurchaseState{
+		Plan:    plan,
+		Intent:  SubscriptionOrderKindPurchase,
+		Balance: math.MaxFloat64,
+		Current: s.findSubscription(ctx, userID, plan.GroupID),
+		Now:     time.Now(),
+	}
+	if fromGroupID > 0 {
+		state.Intent = SubscriptionOrderKindChange
+		state.From = s.findSubscription(ctx, userID, fromGroupID)
+		if state.From != nil {
+			if state.FromOrders, err = s.repo.ListSubscriptionOrders(ctx, state.From.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	decision, err := DecideSubscriptionPurchase(state)
+	if err != nil {
+		return nil, err
+	}
+	return &SubscriptionQuote{
+		Plan:       plan,
+		Decision:   decision,
+		Balance:    user.Balance,
+		Sufficient: decision.Amount <= 0 || decision.Amount <= user.Balance,
+	}, nil
+}
+
+// Purchase 使用余额购买套餐；已有有效订阅时为续费（在原到期时间上累加）
+func (s *SubscriptionPlanService) Purchase(ctx context.Context, userID, planID int64) (*SubscriptionPurchaseResult, error) {
+	if _, err := s.getAvailablePlan(ctx, planID); err != nil {
+		return nil, err
+	}
+	return s.purchase(ctx, &SubscriptionPurchaseInput{UserID: userID, PlanID: planID, Intent: SubscriptionOrderKindPurchase})
+}
+
+// ChangePlan 从 fromGroupID 的订阅升级 / 降级到新套餐
+func (s *SubscriptionPlanService) ChangePlan(ctx context.Context, userID, planID, fromGroupID int64) (*SubscriptionPurchaseResult, error) {
+	if fromGroupID <= 0 {
+		return nil, ErrSubscriptionChangeInvalid
+	}
+	if _, err := s.getAvailablePlan(ctx, planID); err != nil {
+		return nil, err
+	}
+	return s.purchase(ctx, &SubscriptionPurchaseInput{UserID: userID, PlanID: planID, Intent: SubscriptionOrderKindChange, FromGroupID: fromGroupID})
+}
+
+func (s *SubscriptionPlanService) purchase(ctx context.Context, input *SubscriptionPurchaseInput) (*SubscriptionPurchaseResult, error) {
+	result, err := s.repo.Purchase(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	order := result.Order
+	log.Printf("[SubscriptionPlan] order created: user=%d order=%d kind=%s group=%d amount=%.4f", order.UserID, order.ID, order.Kind, order.GroupID, order.Amount)
+	s.invalidateCaches(ctx, order)
+	return result, nil
+}
+
+// getAvailablePlan 套餐需已上架且所属分组为可用的订阅分组
+func (s *SubscriptionPlanService) getAvailablePlan(ctx context.Context, planID int64) (*SubscriptionPlan, error) {
+	plan, err := s.repo.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsActive() {
+		return nil, ErrSubscriptionPlanUnavailable
+	}
+	group, err := s.groupRepo.GetByID(ctx, plan.GroupID)
+	if err != nil {
+		if errors.Is(err, ErrGroupNotFound) {
+			return nil, ErrSubscriptionPlanUnavailable
+		}
+		return nil, err
+	}
+	if !group.IsActive() || !group.IsSubscriptionType() {
+		return nil, ErrSubscriptionPlanUnavailable
+	}
+	return plan, nil
+}
+
+// findSubscription 查询用户在分组上的订阅，不存在时返回 nil
+func (s *SubscriptionPlanService) findSubscription(ctx context.Context, userID, groupID int64) *UserSubscription {
+	sub, err := s.userSubRepo.GetByUserIDAndGroupID(ctx, userID, groupID)
+	if err != nil {
+		return nil
+	}
+	return sub
+}
+
+// ListMyOrders 用户查看自己的订单
+func (s *SubscriptionPlanService) ListMyOrders(ctx context.Context, userID int64, params pagination.PaginationParams) ([]SubscriptionOrder, *pagination.PaginationResult, error) {
+	items, result, err := s.repo.ListOrders(ctx, params, SubscriptionOrderFilter{UserID: userID})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range items {
+		items[i].UserEmail = ""
+	}
+	return items, result, nil
+}
+
+// ListMyRenewals 用户各分组的自动续费设置
+func (s *SubscriptionPlanService) ListMyRenewals(ctx context.Context, userID int64) ([]SubscriptionRenewal, error) {
+	return s.repo.ListRenewals(ctx, userID)
+}
+
+// SetAutoRenew 开启 / 关闭自动续费；开启时需持有该分组的订阅，且续费套餐支持自动续费
+// planID 为 0 时沿用最近一次购买的套餐
+func (s *SubscriptionPlanService) SetAutoRenew(ctx context.Context, userID, groupID int64, enabled bool, planID int64) (*SubscriptionRenewal, error) {
+	if !enabled {
+		if err := s.repo.SetAutoRenew(ctx, userID, groupID, nil, false); err != nil {
+			return nil, err
+		}
+		return s.getRenewal(ctx, userID, groupID)
+	}
+
+	sub := s.findSubscription(ctx, userID, groupID)
+	if sub == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if sub.Status == SubscriptionStatusSuspended {
+		return nil, ErrSubscriptionSuspended
+	}
+	if planID <= 0 {
+		current, err := s.getRenewal(ctx, userID, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil || current.PlanID == nil {
+			return nil, ErrSubscriptionPlanNoAutoRenew
+		}
+		planID = *current.PlanID
+	}
+	plan, err := s.getAvailablePlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.GroupID != groupID {
+		return nil, ErrSubscriptionPlanInvalid
+	}
+	if !plan.AllowsAutoRenew() {
+		return nil, ErrSubscriptionPlanNoAutoRenew
+	}
+	if err := s.repo.SetAutoRenew(ctx, userID, groupID, &plan.ID, true); err != nil {
+		return nil, err
+	}
+	return s.getRenewal(ctx, userID, groupID)
+}
+
+func (s *SubscriptionPlanService) getRenewal(ctx context.Context, userID, groupID int64) (*SubscriptionRenewal, error) {
+	renewals, err := s.repo.ListRenewals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range renewals {
+		if renewals[i].GroupID == groupID {
+			return &renewals[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ==================== 后台任务 ====================
+
+// ProcessAutoRenewals 对即将到期且开启了自动续费的订阅执行续费，返回成功与失败数
+// 失败会记录原因并在每 6 小时后重试；首次失败时邮件通知用户
+func (s *SubscriptionPlanService) ProcessAutoRenewals(ctx context.Context, now time.Time) (int, int, error) {
+	items, err := s.repo.ListAutoRenewDue(ctx, now.Add(-subscriptionAutoRenewGrace), now.Add(subscriptionAutoRenewLead), now.Add(-subscriptionAutoRenewRetry), subscriptionPlanWorkerBatch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("list auto-renew due: %w", err)
+	}
+	renewed, failed := 0, 0
+	for i := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		item := &items[i]
+		var err error = ErrSubscriptionPlanNoAutoRenew
+		if item.PlanID != nil {
+			_, err = s.purchase(ctx, &SubscriptionPurchaseInput{UserID: item.UserID, PlanID: *item.PlanID, Intent: SubscriptionOrderKindAutoRenew})
+		}
+		if err == nil {
+			renewed++
+			if recErr := s.repo.RecordRenewalAttempt(ctx, item.UserID, item.GroupID, ""); recErr != nil {
+				log.Printf("[SubscriptionPlan] record renewal attempt failed: user=%d group=%d err=%v", item.UserID, item.GroupID, recErr)
+			}
+			continue
+		}
+
+		failed++
+		reason := subscriptionRenewFailureReason(err)
+		log.Printf("[SubscriptionPlan] auto-renew failed: user=%d group=%d err=%v", item.UserID, item.GroupID, err)
+		if recErr := s.repo.RecordRenewalAttempt(ctx, item.UserID, item.GroupID, reason); recErr != nil {
+			log.Printf("[SubscriptionPlan] record renewal attempt failed: user=%d group=%d err=%v", item.UserID, item.GroupID, recErr)
+		}
+		if item.LastError == "" {
+			subject, body := buildSubscriptionRenewFailedEmail(s.siteName(ctx), item.GroupName, item.ExpiresAt, reason)
+			s.sendEmail(ctx, item.Email, subject, body)
+		}
+	}
+	return renewed, failed, nil
+}
+
+// SendExpiryReminders 向未开启自动续费、3 天内到期的订阅发送到期提醒，每个到期时间只提醒一次
+func (s *SubscriptionPlanService) SendExpiryReminders(ctx context.Context, now time.Time) (int, error) {
+	if s.emailService == nil {
+		return 0, nil
+	}
+	if _, err := s.emailService.GetSMTPConfig(ctx); err != nil {
+		return 0, nil
+	}
+	items, err := s.repo.ListReminderDue(ctx, now, now.Add(subscriptionReminderLead), subscriptionPlanWorkerBatch)
+	if err != nil {
+		return 0, fmt.Errorf("list reminder due: %w", err)
+	}
+	sent := 0
+	siteName := s.siteName(ctx)
+	for i := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		item := &items[i]
+		subject, body := buildSubscriptionReminderEmail(siteName, item.GroupName, item.ExpiresAt)
+		if !s.sendEmail(ctx, item.Email, subject, body) {
+			continue
+		}
+		if err := s.repo.MarkReminderSent(ctx, item.UserID, item.GroupID, item.ExpiresAt); err != nil {
+			log.Printf("[SubscriptionPlan] mark reminder sent failed: user=%d group=%d err=%v", item.UserID, item.GroupID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *SubscriptionPlanService) sendEmail(ctx context.Context, to, subject, body string) bool {
+	if s.emailService == nil || strings.TrimSpace(to) == "" || isReservedEmail(to) {
+		return false
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, subscriptionPlanEmailTimeout)
+	defer cancel()
+	if err := s.emailService.SendEmail(sendCtx, to, subject, body); err != nil {
+		log.Printf("[SubscriptionPlan] send email failed: to=%s err=%v", MaskEmail(to), err)
+		return false
+	}
+	return true
+}
+
+func subscriptionRenewFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInsufficientBalance):
+		return "insufficient balance"
+	case errors.Is(err, ErrSubscriptionPlanUnavailable), errors.Is(err, ErrSubscriptionPlanNotFound), errors.Is(err, ErrSubscriptionPlanNoAutoRenew):
+		return "the renewal plan is no longer available"
+	case errors.Is(err, ErrSubscriptionSuspended):
+		return "the subscription is suspended"
+	case errors.Is(err, ErrSubscriptionNotFound):
+		return "the subscription no longer exists"
+	default:
+		return "internal error"
+	}
+}
+
+func (s *SubscriptionPlanService) invalidateCaches(ctx context.Context, order *SubscriptionOrder) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, order.UserID)
+	}
+	if s.billingCacheService == nil {
+		return
+	}
+	userID, groupID := order.UserID, order.GroupID
+	var fromGroupID int64
+	if order.FromGroupID != nil {
+		fromGroupID = *order.FromGroupID
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.billingCacheService.InvalidateUserBalance(cacheCtx, userID); err != nil {
+			log.Printf("[SubscriptionPlan] invalidate user balance cache failed: user=%d err=%v", userID, err)
+		}
+		_ = s.billingCacheService.InvalidateSubscription(cacheCtx, userID, groupID)
+		if fromGroupID > 0 {
+			_ = s.billingCacheService.InvalidateSubscription(cacheCtx, userID, fromGroupID)
+		}
+	}()
+}
+
+func (s *SubscriptionPlanService) tryAcquireWorkerSlot(ctx context.Context) bool {
+	if s.redisClient == nil {
+		s.warnNoRedisOnce.Do(func() {
+			log.Printf("[SubscriptionPlan] redis not configured; running without worker lock")
+		})
+		return true
+	}
+	ttl := subscriptionPlanWorkerInterval - subscriptionPlanWorkerInterval/6
+	ok, err := s.redisClient.SetNX(ctx, subscriptionPlanWorkerSlotKey, s.instanceID, ttl).Result()
+	if err != nil {
+		log.Printf("[SubscriptionPlan] worker slot SetNX failed; skipping this cycle: %v", err)
+		return false
+	}
+	return ok
+}
+
+func (s *SubscriptionPlanService) siteName(ctx context.Context) string {
+	if s.settingService != nil {
+		return s.settingService.GetSiteName(ctx)
+	}
+	return "Sub2API"
+}
+
+func buildSubscriptionReminderEmail(siteName, groupName string, expiresAt time.Time) (string, string) {
+	subject := fmt.Sprintf("[%s] Your %s subscription expires soon", siteName, groupName)
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>Your <strong>%s</strong> subscription on %s expires on <strong>%s</strong>.</p>
+<p>To keep access, renew it from the Subscriptions page using your account balance, or turn on auto-renewal so it is renewed automatically before it expires.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(groupName), html.EscapeString(siteName), expiresAt.UTC().Format("2006-01-02 15:04 UTC"))
+	return subject, body
+}
+
+func buildSubscriptionRenewFailedEmail(siteName, groupName string, expiresAt time.Time, reason string) (string, string) {
+	subject := fmt.Sprintf("[%s] Auto-renewal of your %s subscription failed", siteName, groupName)
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>We could not auto-renew your <strong>%s</strong> subscription on %s: %s.</p>
+<p>The subscription expires on <strong>%s</strong>. We will retry automatically; you can also top up your balance or renew it manually from the Subscriptions page.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(groupName), html.EscapeString(siteName), html.EscapeString(reason), expiresAt.UTC().Format("2006-01-02 15:04 UTC"))
+	return subject, body
+}
@@ -0,0 +1,166 @@
+//go:build unit
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecideSubscriptionPurchase_NewAndRenew(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	plan := &SubscriptionPlan{ID: 1, GroupID: 10, Price: 30, ValidityDays: 30, RenewalPolicy: SubscriptionRenewalManual, Status: SubscriptionPlanStatusActive}
+
+	// 没有订阅：新购，从当前时间开始
+	decision, err := DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: plan, Intent: SubscriptionOrderKindPurchase, Balance: 50, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionOrderKindPurchase, decision.Kind)
+	require.False(t, decision.Extend)
+	require.Equal(t, now.AddDate(0, 0, 30), decision.ExpiresAt)
+	require.Equal(t, 30.0, decision.Amount)
+
+	// 有效订阅：续费，在原到期时间上累加
+	current := &UserSubscription{ID: 5, GroupID: 10, Status: SubscriptionStatusActive, ExpiresAt: now.Add(48 * time.Hour)}
+	decision, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: plan, Intent: SubscriptionOrderKindPurchase, Balance: 50, Current: current, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionOrderKindRenew, decision.Kind)
+	require.True(t, decision.Extend)
+	require.Equal(t, current.ExpiresAt.AddDate(0, 0, 30), decision.ExpiresAt)
+
+	// 已过期订阅：重新开始
+	expired := &UserSubscription{ID: 5, GroupID: 10, Status: SubscriptionStatusExpired, ExpiresAt: now.Add(-time.Hour)}
+	decision, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: plan, Intent: SubscriptionOrderKindPurchase, Balance: 50, Current: expired, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionOrderKindPurchase, decision.Kind)
+	require.Equal(t, now.AddDate(0, 0, 30), decision.ExpiresAt)
+
+	// 余额不足
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: plan, Intent: SubscriptionOrderKindPurchase, Balance: 29.99, Now: now})
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
+
+func TestDecideSubscriptionPurchase_PolicyAndStatus(t *testing.T) {
+	now := time.Now()
+	current := &UserSubscription{GroupID: 10, Status: SubscriptionStatusActive, ExpiresAt: now.Add(time.Hour)}
+
+	oneTime := &SubscriptionPlan{GroupID: 10, Price: 1, ValidityDays: 7, RenewalPolicy: SubscriptionRenewalNone, Status: SubscriptionPlanStatusActive}
+	_, err := DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: oneTime, Intent: SubscriptionOrderKindPurchase, Balance: 10, Current: current, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionPlanNotRenewable)
+
+	manual := &SubscriptionPlan{GroupID: 10, Price: 1, ValidityDays: 7, RenewalPolicy: SubscriptionRenewalManual, Status: SubscriptionPlanStatusActive}
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: manual, Intent: SubscriptionOrderKindAutoRenew, Balance: 10, Current: current, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionPlanNoAutoRenew)
+
+	disabled := &SubscriptionPlan{GroupID: 10, Price: 1, ValidityDays: 7, RenewalPolicy: SubscriptionRenewalAuto, Status: SubscriptionPlanStatusDisabled}
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: disabled, Intent: SubscriptionOrderKindPurchase, Balance: 10, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionPlanUnavailable)
+
+	suspended := &UserSubscription{GroupID: 10, Status: SubscriptionStatusSuspended, ExpiresAt: now.Add(time.Hour)}
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: manual, Intent: SubscriptionOrderKindPurchase, Balance: 10, Current: suspended, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionSuspended)
+
+	auto := &SubscriptionPlan{GroupID: 10, Price: 1, ValidityDays: 7, RenewalPolicy: SubscriptionRenewalAuto, Status: SubscriptionPlanStatusActive}
+	decision, err := DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: auto, Intent: SubscriptionOrderKindAutoRenew, Balance: 10, Current: current, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionOrderKindAutoRenew, decision.Kind)
+	require.True(t, decision.Extend)
+}
+
+func TestDecideSubscriptionPurchase_ChangeCredit(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	from := &UserSubscription{ID: 3, GroupID: 1, Status: SubscriptionStatusActive, ExpiresAt: now.AddDate(0, 0, 15)}
+	fromOrders := []SubscriptionOrder{{Price: 30, Amount: 30, ValidityDays: 30, ExpiresAt: from.ExpiresAt}}
+
+	// 升级：剩余 15 天按 1/天 抵扣
+	upgrade := &SubscriptionPlan{GroupID: 2, Price: 60, ValidityDays: 30, RenewalPolicy: SubscriptionRenewalAuto, Status: SubscriptionPlanStatusActive}
+	decision, err := DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: upgrade, Intent: SubscriptionOrderKindChange, Balance: 45, From: from, FromOrders: fromOrders, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionOrderKindChange, decision.Kind)
+	require.InDelta(t, 15, decision.Credit, 1e-9)
+	require.InDelta(t, 45, decision.Amount, 1e-9)
+	require.Equal(t, now.AddDate(0, 0, 30), decision.ExpiresAt)
+
+	// 降级：抵扣最多抵满新套餐价格，差额不退回余额
+	downgrade := &SubscriptionPlan{GroupID: 2, Price: 5, ValidityDays: 30, RenewalPolicy: SubscriptionRenewalAuto, Status: SubscriptionPlanStatusActive}
+	decision, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: downgrade, Intent: SubscriptionOrderKindChange, Balance: 0, From: from, FromOrders: fromOrders, Now: now})
+	require.NoError(t, err)
+	require.InDelta(t, 5, decision.Credit, 1e-9)
+	require.Zero(t, decision.Amount)
+
+	// 没有订单（管理员分配）的订阅不抵扣
+	decision, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: downgrade, Intent: SubscriptionOrderKindChange, Balance: 5, From: from, Now: now})
+	require.NoError(t, err)
+	require.Zero(t, decision.Credit)
+
+	// 同分组、原订阅失效、目标分组已有有效订阅均不允许
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: &SubscriptionPlan{GroupID: 1, Price: 1, ValidityDays: 1, Status: SubscriptionPlanStatusActive}, Intent: SubscriptionOrderKindChange, Balance: 5, From: from, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionChangeInvalid)
+	expiredFrom := &UserSubscription{GroupID: 1, Status: SubscriptionStatusActive, ExpiresAt: now.Add(-time.Minute)}
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: downgrade, Intent: SubscriptionOrderKindChange, Balance: 5, From: expiredFrom, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionChangeInvalid)
+	owned := &UserSubscription{GroupID: 2, Status: SubscriptionStatusActive, ExpiresAt: now.Add(time.Hour)}
+	_, err = DecideSubscriptionPurchase(&SubscriptionPurchaseState{Plan: downgrade, Intent: SubscriptionOrderKindChange, Balance: 5, Current: owned, From: from, Now: now})
+	require.ErrorIs(t, err, ErrSubscriptionChangeTargetOwned)
+}
+
+func TestSubscriptionChangeCredit_PaidTimeOnly(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// 365 天赠送时长之后续费一笔 10/30 天：只有这 30 天可抵扣
+	gifted := &UserSubscription{GroupID: 1, Status: SubscriptionStatusActive, ExpiresAt: now.AddDate(0, 0, 395)}
+	orders := []SubscriptionOrder{{Price: 10, Amount: 10, ValidityDays: 30, ExpiresAt: gifted.ExpiresAt}}
+	require.InDelta(t, 10, subscriptionChangeCredit(gifted, orders, now), 1e-9)
+
+	// 续费后管理员缩短了有效期：超出到期时间的部分不抵扣，各订单时长不重叠计算
+	shortened := &UserSubscription{GroupID: 1, Status: SubscriptionStatusActive, ExpiresAt: now.AddDate(0, 0, 20)}
+	orders = []SubscriptionOrder{
+		{Price: 30, Amount: 30, ValidityDays: 30, ExpiresAt: now.AddDate(0, 0, 40)},
+		{Price: 30, Amount: 30, ValidityDays: 30, ExpiresAt: now.AddDate(0, 0, 25)},
+	}
+	require.InDelta(t, 20, subscriptionChangeCredit(shortened, orders, now), 1e-9)
+
+	// 以抵扣换来的订单按售价计价；历史上退回过差额（Amount 为负）的订单同样不超过售价
+	changed := &UserSubscription{GroupID: 1, Status: SubscriptionStatusActive, ExpiresAt: now.AddDate(0, 0, 30)}
+	orders = []SubscriptionOrder{{Price: 30, Credit: 40, Amount: -10, ValidityDays: 30, ExpiresAt: changed.ExpiresAt}}
+	require.InDelta(t, 30, subscriptionChangeCredit(changed, orders, now), 1e-9)
+}
+
+func TestSubscriptionChangeCredit_DeductsUsedQuota(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	monthlyLimit := 100.0
+	windowStart := now.AddDate(0, 0, -20)
+	from := &UserSubscription{
+		GroupID:            1,
+		Status:             SubscriptionStatusActive,
+		ExpiresAt:          now.AddDate(0, 0, 30),
+		MonthlyWindowStart: &windowStart,
+		MonthlyUsageUSD:    50,
+		Group:              &Group{MonthlyLimitUSD: &monthlyLimit},
+	}
+	orders := []SubscriptionOrder{{Price: 60, Amount: 60, ValidityDays: 60, ExpiresAt: from.ExpiresAt}}
+
+	// 当前月窗口还剩 10 天（价值 10），额度已用一半：扣除 5
+	require.InDelta(t, 25, subscriptionChangeCredit(from, orders, now), 1e-9)
+
+	// 额度用尽：窗口剩余时长不抵扣
+	from.MonthlyUsageUSD = 120
+	require.InDelta(t, 20, subscriptionChangeCredit(from, orders, now), 1e-9)
+
+	// 窗口已过期（等待重置）不扣除
+	expiredStart := now.AddDate(0, 0, -31)
+	from.MonthlyWindowStart = &expiredStart
+	require.InDelta(t, 30, subscriptionChangeCredit(from, orders, now), 1e-9)
+}
+
+func TestBuildSubscriptionEmails_EscapeHTML(t *testing.T) {
+	expiresAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, body := buildSubscriptionReminderEmail("<Site>", "<b>Pro</b>", expiresAt)
+	require.NotContains(t, body, "<b>Pro</b>")
+	require.Contains(t, body, "&lt;b&gt;Pro&lt;/b&gt;")
+	require.Contains(t, body, "2026-03-01 00:00 UTC")
+
+	_, body = buildSubscriptionRenewFailedEmail("Site", "Pro", expiresAt, "insufficient balance")
+	require.Contains(t, body, "insufficient balance")
+}
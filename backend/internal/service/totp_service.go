@@ -150,22 +150,8 @@ func (s *TotpService) InitiateSetup(ctx context.Context, userID int64, emailCode
 	}
 
 	// Verify identity based on email verification setting
-	if s.settingService.IsEmailVerifyEnabled(ctx) {
-		// Email verification enabled - verify email code
-		if emailCode == "" {
-			return nil, ErrVerifyCodeRequired
-		}
-		if err := s.emailService.VerifyCode(ctx, user.Email, emailCode); err != nil {
-			return nil, err
-		}
-	} else {
-		// Email verification disabled - verify password
-		if password == "" {
-			return nil, ErrPasswordRequired
-		}
-		if !user.CheckPassword(password) {
-			return nil, ErrPasswordIncorrect
-		}
+	if err := verifyUserIdentity(ctx, s.settingService, s.emailService, user, emailCode, password); err != nil {
+		return nil, err
 	}
 
 	// Generate a new TOTP key
@@ -297,22 +283,8 @@ func (s *TotpService) Disable(ctx context.Context, userID int64, emailCode, pass
 	}
 
 	// Verify identity based on email verification setting
-	if s.settingService.IsEmailVerifyEnabled(ctx) {
-		// Email verification enabled - verify email code
-		if emailCode == "" {
-			return ErrVerifyCodeRequired
-		}
-		if err := s.emailService.VerifyCode(ctx, user.Email, emailCode); err != nil {
-			return err
-		}
-	} else {
-		// Email verification disabled - verify password
-		if password == "" {
-			return ErrPasswordRequired
-		}
-		if !user.CheckPassword(password) {
-			return ErrPasswordIncorrect
-		}
+	if err := verifyUserIdentity(ctx, s.settingService, s.emailService, user, emailCode, password); err != nil {
+		return err
 	}
 
 	// Disable TOTP
@@ -472,6 +444,27 @@ func generateRandomToken(byteLength int) (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
+// verifyUserIdentity re-verifies the user before sensitive 2FA changes
+// If email verification is enabled, emailCode is required; otherwise password is required
+func verifyUserIdentity(ctx context.Context, settingService *SettingService, emailService *EmailService, user *User, emailCode, password string) error {
+	if settingService.IsEmailVerifyEnabled(ctx) {
+		// Email verification enabled - verify email code
+		if emailCode == "" {
+			return ErrVerifyCodeRequired
+		}
+		return emailService.VerifyCode(ctx, user.Email, emailCode)
+	}
+
+	// Email verification disabled - verify password
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	if !user.CheckPassword(password) {
+		return ErrPasswordIncorrect
+	}
+	return nil
+}
+
 // VerificationMethod represents the method required for TOTP operations
 type VerificationMethod struct {
 	Method string `json:"method"` // "email" or "password"
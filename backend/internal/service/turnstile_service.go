@@ -80,6 +80,32 @@ func (s *TurnstileService) VerifyToken(ctx context.Context, token string, remote
 	return nil
 }
 
+// IsChallengeAvailable 检查是否已配置 Turnstile 密钥（不论全局开关），可用于按需强制人机验证
+func (s *TurnstileService) IsChallengeAvailable(ctx context.Context) bool {
+	return s.settingService.GetTurnstileSiteKey(ctx) != "" && s.settingService.GetTurnstileSecretKey(ctx) != ""
+}
+
+// VerifyChallenge 按需强制的人机验证：不受 Turnstile 全局开关影响
+func (s *TurnstileService) VerifyChallenge(ctx context.Context, token string, remoteIP string) error {
+	secretKey := s.settingService.GetTurnstileSecretKey(ctx)
+	if secretKey == "" {
+		return ErrTurnstileNotConfigured
+	}
+	if token == "" {
+		return ErrTurnstileVerificationFailed
+	}
+	result, err := s.verifier.VerifyToken(ctx, secretKey, token, remoteIP)
+	if err != nil {
+		log.Printf("[Turnstile] Challenge request failed: %v", err)
+		return fmt.Errorf("send request: %w", err)
+	}
+	if !result.Success {
+		log.Printf("[Turnstile] Challenge verification failed, error codes: %v", result.ErrorCodes)
+		return ErrTurnstileVerificationFailed
+	}
+	return nil
+}
+
 // IsEnabled 检查 Turnstile 是否启用
 func (s *TurnstileService) IsEnabled(ctx context.Context) bool {
 	return s.settingService.IsTurnstileEnabled(ctx)
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+var (
+	ErrUserDataExportNotFound    = infraerrors.NotFound("DATA_EXPORT_NOT_FOUND", "data export not found")
+	ErrUserDataExportInProgress  = infraerrors.Conflict("DATA_EXPORT_IN_PROGRESS", "a data export is already being prepared")
+	ErrUserDataExportTooFrequent = infraerrors.TooManyRequests("DATA_EXPORT_TOO_FREQUENT", "too many data exports requested, please try again later")
+	ErrUserDataExportNotReady    = infraerrors.BadRequest("DATA_EXPORT_NOT_READY", "data export is not ready for download")
+	ErrUserDataExportExpired     = infraerrors.New(http.StatusGone, "DATA_EXPORT_EXPIRED", "data export has expired")
+
+	ErrAccountDeletionNotFound         = infraerrors.NotFound("ACCOUNT_DELETION_NOT_FOUND", "account deletion request not found")
+	ErrAccountDeletionAdmin            = infraerrors.BadRequest("ACCOUNT_DELETION_ADMIN", "admin accounts cannot be deleted by self-service")
+	ErrAccountDeletionScheduled        = infraerrors.Conflict("ACCOUNT_DELETION_SCHEDULED", "account deletion is already scheduled")
+	ErrAccountDeletionInvalidToken     = infraerrors.BadRequest("ACCOUNT_DELETION_INVALID_TOKEN", "invalid or expired confirmation link")
+	ErrAccountDeletionNotRestorable    = infraerrors.BadRequest("ACCOUNT_DELETION_NOT_RESTORABLE", "only scheduled deletions can be restored")
+	ErrAccountDeletionEmailUnavailable = infraerrors.BadRequest("ACCOUNT_DELETION_EMAIL_UNAVAILABLE", "account has no deliverable email address for confirmation")
+)
+
+// 数据导出状态
+const (
+	UserDataExportStatusPending    = "pending"
+	UserDataExportStatusProcessing = "processing"
+	UserDataExportStatusCompleted  = "completed"
+	UserDataExportStatusFailed     = "failed"
+)
+
+// 账号注销申请状态
+const (
+	// AccountDeletionStatusPending 已发送确认邮件，等待用户点击确认链接
+	AccountDeletionStatusPending = "pending_confirmation"
+	// AccountDeletionStatusScheduled 已确认：账号已停用，宽限期结束后执行匿名化
+	AccountDeletionStatusScheduled = "scheduled"
+	// AccountDeletionStatusCancelled 用户在确认前取消
+	AccountDeletionStatusCancelled = "cancelled"
+	// AccountDeletionStatusRestored 管理员在宽限期内恢复了账号
+	AccountDeletionStatusRestored = "restored"
+	// AccountDeletionStatusCompleted 已完成匿名化删除
+	AccountDeletionStatusCompleted = "completed"
+)
+
+// UserDataExport 用户数据导出任务；归档内容单独按需读取
+type UserDataExport struct {
+	ID           int64      `json:"id"`
+	UserID       int64      `json:"user_id"`
+	Status       string     `json:"status"`
+	FileSize     int64      `json:"file_size"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// AccountDeletionRequest 用户自助注销申请
+type AccountDeletionRequest struct {
+	ID     int64  `json:"id"`
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+
+	TokenHash      string    `json:"-"`
+	TokenExpiresAt time.Time `json:"-"`
+
+	// 确认注销前的用户状态与被停用的 API Key，管理员恢复时据此还原
+	PreviousStatus   string  `json:"previous_status,omitempty"`
+	RevokedAPIKeyIDs []int64 `json:"revoked_api_key_ids"`
+
+	IPAddress    string     `json:"ip_address"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	RestoredAt   *time.Time `json:"restored_at,omitempty"`
+	RestoredBy   *int64     `json:"restored_by,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// AccountDeletionFilter 管理端注销申请列表过滤条件
+type AccountDeletionFilter struct {
+	Status string
+	UserID int64
+}
+
+// UserDataRepository 数据导出与账号注销的数据访问接口
+type UserDataRepository interface {
+	CreateExport(ctx context.Context, export *UserDataExport) error
+	// GetExport 不存在时返回 ErrUserDataExportNotFound
+	GetExport(ctx context.Context, id int64) (*UserDataExport, error)
+	GetExportArchive(ctx context.Context, id int64) ([]byte, error)
+	ListExportsByUser(ctx context.Context, userID int64, limit int) ([]UserDataExport, error)
+	CountExportsSince(ctx context.Context, userID int64, since time.Time) (int, error)
+	HasActiveExport(ctx context.Context, userID int64) (bool, error)
+	// ClaimNextExport 领取一个待处理（或处理超时）的导出任务；没有任务时返回 nil, nil
+	ClaimNextExport(ctx context.Context, staleAfter time.Duration) (*UserDataExport, error)
+	CompleteExport(ctx context.Context, id int64, archive []byte, expiresAt time.Time) error
+	FailExport(ctx context.Context, id int64, message string) error
+	DeleteExpiredExports(ctx context.Context, now time.Time) (int64, error)
+	// ListUsageLogsForExport 按 ID 升序分批读取用户的使用记录
+	ListUsageLogsForExport(ctx context.Context, userID, afterID int64, limit int) ([]UsageLog, error)
+
+	CreateDeletionRequest(ctx context.Context, req *AccountDeletionRequest) error
+	// GetDeletionRequest / GetDeletionRequestByTokenHash / GetOpenDeletionRequest 不存在时返回 ErrAccountDeletionNotFound
+	GetDeletionRequest(ctx context.Context, id int64) (*AccountDeletionRequest, error)
+	GetDeletionRequestByTokenHash(ctx context.Context, tokenHash string) (*AccountDeletionRequest, error)
+	// GetOpenDeletionRequest 返回用户处于 pending_confirmation / scheduled 状态的申请
+	GetOpenDeletionRequest(ctx context.Context, userID int64) (*AccountDeletionRequest, error)
+	UpdateDeletionRequest(ctx context.Context, req *AccountDeletionRequest) error
+	// ScheduleDeletion 在同一事务内停用用户与其启用中的 API Key，并将 pending 申请更新为 req 的内容；
+	// 回填 req.PreviousStatus 与 req.RevokedAPIKeyIDs。申请已不是 pending 时返回 ErrAccountDeletionNotFound
+	ScheduleDeletion(ctx context.Context, req *AccountDeletionRequest) error
+	ListDeletionRequests(ctx context.Context, params pagination.PaginationParams, filter AccountDeletionFilter) ([]AccountDeletionRequest, *pagination.PaginationResult, error)
+	ListDueDeletionRequestIDs(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	// PurgeUser 匿名化并软删除用户：清除个人信息与使用记录中的 IP / UA 等，保留计费记录
+	PurgeUser(ctx context.Context, userID int64) error
+}
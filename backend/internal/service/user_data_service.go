@@ -0,0 +1,835 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	// 同一用户 24 小时内最多申请的导出次数
+	userDataExportDailyLimit = 3
+	// 导出文件保留时长，过期后删除
+	userDataExportRetention = 7 * 24 * time.Hour
+	// 处理中超过该时长视为中断，允许重新领取
+	userDataExportStaleAfter = 30 * time.Minute
+	// 单个归档最多包含的使用记录条数
+	userDataExportMaxUsageLogs = 200000
+	userDataExportUsageBatch   = 1000
+	userDataExportRedeemLimit  = 10000
+
+	// AccountDeletionGracePeriod 确认注销后的宽限期，期间管理员可恢复账号
+	AccountDeletionGracePeriod = 14 * 24 * time.Hour
+	// 确认链接有效期
+	accountDeletionTokenTTL = 24 * time.Hour
+
+	userDataWorkerInterval = 30 * time.Second
+	userDataWorkerTimeout  = 10 * time.Minute
+	userDataWorkerBatch    = 20
+	userDataEmailTimeout   = 30 * time.Second
+)
+
+// UserDataService 用户自助数据导出与账号注销
+//
+// - 导出：用户申请后由后台任务异步打包（资料、属性、API Key 元数据、订阅、兑换记录、使用记录），完成后可下载，保留 7 天
+// - 注销：发送确认邮件 → 用户确认后立即停用账号、停用 API Key 并撤销会话 → 宽限期结束后匿名化删除
+// - 宽限期内管理员可恢复账号；计费相关记录（使用记录费用、兑换记录）保留，仅清除个人信息
+type UserDataService struct {
+	repo               UserDataRepository
+	userRepo           UserRepository
+	attributeDefRepo   UserAttributeDefinitionRepository
+	attributeValueRepo UserAttributeValueRepository
+	apiKeyRepo         APIKeyRepository
+	apiKeyService      *APIKeyService
+	userSubRepo        UserSubscriptionRepository
+	redeemRepo         RedeemCodeRepository
+	authService        *AuthService
+	emailService       *EmailService
+	settingService     *SettingService
+
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewUserDataService 创建用户数据服务
+func NewUserDataService(
+	repo UserDataRepository,
+	userRepo UserRepository,
+	attributeDefRepo UserAttributeDefinitionRepository,
+	attributeValueRepo UserAttributeValueRepository,
+	apiKeyRepo APIKeyRepository,
+	apiKeyService *APIKeyService,
+	userSubRepo UserSubscriptionRepository,
+	redeemRepo RedeemCodeRepository,
+	authService *AuthService,
+	emailService *EmailService,
+	settingService *SettingService,
+) *UserDataService {
+	return &UserDataService{
+		repo:               repo,
+		userRepo:           userRepo,
+		attributeDefRepo:   attributeDefRepo,
+		attributeValueRepo: attributeValueRepo,
+		apiKeyRepo:         apiKeyRepo,
+		apiKeyService:      apiKeyService,
+		userSubRepo:        userSubRepo,
+		redeemRepo:         redeemRepo,
+		authService:        authService,
+		emailService:       emailService,
+		settingService:     settingService,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start 启动后台任务（处理导出、清理过期导出、执行到期注销）
+func (s *UserDataService) Start() {
+	if s == nil || s.repo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// Stop 停止后台任务
+func (s *UserDataService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *UserDataService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(userDataWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *UserDataService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), userDataWorkerTimeout)
+	defer cancel()
+
+	if n, err := s.repo.DeleteExpiredExports(ctx, time.Now()); err != nil {
+		log.Printf("[UserData] delete expired exports failed: %v", err)
+	} else if n > 0 {
+		log.Printf("[UserData] deleted %d expired exports", n)
+	}
+
+	for i := 0; i < userDataWorkerBatch; i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		export, err := s.repo.ClaimNextExport(ctx, userDataExportStaleAfter)
+		if err != nil {
+			log.Printf("[UserData] claim export failed: %v", err)
+			break
+		}
+		if export == nil {
+			break
+		}
+		s.processExport(ctx, export)
+	}
+
+	if _, err := s.FinalizeDueDeletions(ctx, time.Now()); err != nil {
+		log.Printf("[UserData] finalize deletions failed: %v", err)
+	}
+}
+
+// ==================== 数据导出 ====================
+
+// RequestExport 申请导出数据，归档由后台任务生成
+func (s *UserDataService) RequestExport(ctx context.Context, userID int64) (*UserDataExport, error) {
+	active, err := s.repo.HasActiveExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, ErrUserDataExportInProgress
+	}
+	count, err := s.repo.CountExportsSince(ctx, userID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if count >= userDataExportDailyLimit {
+		return nil, ErrUserDataExportTooFrequent
+	}
+
+	export := &UserDataExport{UserID: userID, Status: UserDataExportStatusPending}
+	if err := s.repo.CreateExport(ctx, export); err != nil {
+		return nil, fmt.Errorf("create data export: %w", err)
+	}
+	log.Printf("[UserData] export requested: user=%d export=%d", userID, export.ID)
+	return export, nil
+}
+
+// ListExports 用户最近的导出任务
+func (s *UserDataService) ListExports(ctx context.Context, userID int64) ([]UserDataExport, error) {
+	return s.repo.ListExportsByUser(ctx, userID, 10)
+}
+
+// DownloadExport 返回已完成导出的归档内容与文件名
+func (s *UserDataService) DownloadExport(ctx context.Context, userID, exportID int64) ([]byte, string, error) {
+	export, err := s.repo.GetExport(ctx, exportID)
+	if err != nil {
+		return nil, "", err
+	}
+	if export.UserID != userID {
+		return nil, "", ErrUserDataExportNotFound
+	}
+	if export.Status != UserDataExportStatusCompleted {
+		return nil, "", ErrUserDataExportNotReady
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return nil, "", ErrUserDataExportExpired
+	}
+	archive, err := s.repo.GetExportArchive(ctx, exportID)
+	if err != nil {
+		return nil, "", err
+	}
+	filename := fmt.Sprintf("account-data-%d-%s.zip", userID, export.CreatedAt.UTC().Format("20060102"))
+	return archive, filename, nil
+}
+
+func (s *UserDataService) processExport(ctx context.Context, export *UserDataExport) {
+	archive, err := s.BuildArchive(ctx, export.UserID)
+	if err != nil {
+		log.Printf("[UserData] build export failed: export=%d user=%d err=%v", export.ID, export.UserID, err)
+		if ferr := s.repo.FailExport(ctx, export.ID, truncateString(err.Error(), 500)); ferr != nil {
+			log.Printf("[UserData] mark export failed: export=%d err=%v", export.ID, ferr)
+		}
+		return
+	}
+	if err := s.repo.CompleteExport(ctx, export.ID, archive, time.Now().Add(userDataExportRetention)); err != nil {
+		log.Printf("[UserData] save export failed: export=%d err=%v", export.ID, err)
+		return
+	}
+	log.Printf("[UserData] export completed: export=%d user=%d bytes=%d", export.ID, export.UserID, len(archive))
+}
+
+// 归档中各文件的结构（仅包含用户本人可见的信息，API Key 只保留掩码）
+
+type exportProfile struct {
+	ID          int64      `json:"id"`
+	Email       string     `json:"email"`
+	Username    string     `json:"username"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	Balance     float64    `json:"balance"`
+	Concurrency int        `json:"concurrency"`
+	TotpEnabled bool       `json:"totp_enabled"`
+	TotpSince   *time.Time `json:"totp_enabled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type exportAttribute struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type exportAPIKey struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	KeyMasked   string     `json:"key_masked"`
+	Status      string     `json:"status"`
+	GroupID     *int64     `json:"group_id,omitempty"`
+	GroupName   string     `json:"group_name,omitempty"`
+	IPWhitelist []string   `json:"ip_whitelist,omitempty"`
+	IPBlacklist []string   `json:"ip_blacklist,omitempty"`
+	Quota       float64    `json:"quota"`
+	QuotaUsed   float64    `json:"quota_used"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type exportSubscription struct {
+	ID              int64     `json:"id"`
+	GroupID         int64     `json:"group_id"`
+	GroupName       string    `json:"group_name,omitempty"`
+	Status          string    `json:"status"`
+	StartsAt        time.Time `json:"starts_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	DailyUsageUSD   float64   `json:"daily_usage_usd"`
+	WeeklyUsageUSD  float64   `json:"weekly_usage_usd"`
+	MonthlyUsageUSD float64   `json:"monthly_usage_usd"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type exportRedeem struct {
+	ID           int64      `json:"id"`
+	Type         string     `json:"type"`
+	Value        float64    `json:"value"`
+	GroupID      *int64     `json:"group_id,omitempty"`
+	ValidityDays int        `json:"validity_days,omitempty"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+}
+
+type exportManifest struct {
+	GeneratedAt        time.Time `json:"generated_at"`
+	UserID             int64     `json:"user_id"`
+	Files              []string  `json:"files"`
+	UsageLogCount      int       `json:"usage_log_count"`
+	UsageLogsTruncated bool      `json:"usage_logs_truncated"`
+}
+
+// BuildArchive 生成用户数据 zip 归档
+func (s *UserDataService) BuildArchive(ctx context.Context, userID int64) ([]byte, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	manifest := exportManifest{GeneratedAt: time.Now().UTC(), UserID: userID}
+
+	writeJSON := func(name string, v any) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		manifest.Files = append(manifest.Files, name)
+		return nil
+	}
+
+	if err := writeJSON("profile.json", exportProfile{
+		ID:          user.ID,
+		Email:       user.Email,
+		Username:    user.Username,
+		Role:        user.Role,
+		Status:      user.Status,
+		Balance:     user.Balance,
+		Concurrency: user.Concurrency,
+		TotpEnabled: user.TotpEnabled,
+		TotpSince:   user.TotpEnabledAt,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	attributes, err := s.exportAttributes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeJSON("attributes.json", attributes); err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := s.exportAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeJSON("api_keys.json", apiKeys); err != nil {
+		return nil, err
+	}
+
+	subs, err := s.userSubRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+	subscriptions := make([]exportSubscription, 0, len(subs))
+	for i := range subs {
+		sub := &subs[i]
+		item := exportSubscription{
+			ID:              sub.ID,
+			GroupID:         sub.GroupID,
+			Status:          sub.Status,
+			StartsAt:        sub.StartsAt,
+			ExpiresAt:       sub.ExpiresAt,
+			DailyUsageUSD:   sub.DailyUsageUSD,
+			WeeklyUsageUSD:  sub.WeeklyUsageUSD,
+			MonthlyUsageUSD: sub.MonthlyUsageUSD,
+			CreatedAt:       sub.CreatedAt,
+		}
+		if sub.Group != nil {
+			item.GroupName = sub.Group.Name
+		}
+		subscriptions = append(subscriptions, item)
+	}
+	if err := writeJSON("subscriptions.json", subscriptions); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.redeemRepo.ListByUser(ctx, userID, userDataExportRedeemLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list redeem history: %w", err)
+	}
+	redeems := make([]exportRedeem, 0, len(codes))
+	for i := range codes {
+		redeems = append(redeems, exportRedeem{
+			ID:           codes[i].ID,
+			Type:         codes[i].Type,
+			Value:        codes[i].Value,
+			GroupID:      codes[i].GroupID,
+			ValidityDays: codes[i].ValidityDays,
+			UsedAt:       codes[i].UsedAt,
+		})
+	}
+	if err := writeJSON("redeem_history.json", redeems); err != nil {
+		return nil, err
+	}
+
+	count, truncated, err := s.writeUsageLogs(ctx, zw, userID)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Files = append(manifest.Files, "usage_logs.csv")
+	manifest.UsageLogCount = count
+	manifest.UsageLogsTruncated = truncated
+
+	if err := writeJSON("manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *UserDataService) exportAttributes(ctx context.Context, userID int64) ([]exportAttribute, error) {
+	out := []exportAttribute{}
+	if s.attributeValueRepo == nil {
+		return out, nil
+	}
+	values, err := s.attributeValueRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list attributes: %w", err)
+	}
+	defs := map[int64]UserAttributeDefinition{}
+	if s.attributeDefRepo != nil && len(values) > 0 {
+		list, err := s.attributeDefRepo.List(ctx, false)
+		if err != nil {
+			return nil, fmt.Errorf("list attribute definitions: %w", err)
+		}
+		for i := range list {
+			defs[list[i].ID] = list[i]
+		}
+	}
+	for i := range values {
+		def := defs[values[i].AttributeID]
+		out = append(out, exportAttribute{Key: def.Key, Name: def.Name, Value: values[i].Value, UpdatedAt: values[i].UpdatedAt})
+	}
+	return out, nil
+}
+
+func (s *UserDataService) exportAPIKeys(ctx context.Context, userID int64) ([]exportAPIKey, error) {
+	out := []exportAPIKey{}
+	for page := 1; ; page++ {
+		keys, result, err := s.apiKeyRepo.ListByUserID(ctx, userID, pagination.PaginationParams{Page: page, PageSize: 100})
+		if err != nil {
+			return nil, fmt.Errorf("list api keys: %w", err)
+		}
+		for i := range keys {
+			k := &keys[i]
+			item := exportAPIKey{
+				ID:          k.ID,
+				Name:        k.Name,
+				KeyMasked:   maskAPIKeyForExport(k.Key),
+				Status:      k.Status,
+				GroupID:     k.GroupID,
+				IPWhitelist: k.IPWhitelist,
+				IPBlacklist: k.IPBlacklist,
+				Quota:       k.Quota,
+				QuotaUsed:   k.QuotaUsed,
+				ExpiresAt:   k.ExpiresAt,
+				CreatedAt:   k.CreatedAt,
+			}
+			if k.Group != nil {
+				item.GroupName = k.Group.Name
+			}
+			out = append(out, item)
+		}
+		if result == nil || len(keys) == 0 || int64(page*100) >= result.Total {
+			return out, nil
+		}
+	}
+}
+
+// maskAPIKeyForExport 归档中不包含完整密钥
+func maskAPIKeyForExport(key string) string {
+	if len(key) <= 10 {
+		return "****"
+	}
+	return key[:6] + "..." + key[len(key)-4:]
+}
+
+var usageLogExportHeader = []string{
+	"id", "created_at", "request_id", "api_key_id", "group_id", "model",
+	"input_tokens", "output_tokens", "cache_creation_tokens", "cache_read_tokens",
+	"total_cost", "actual_cost", "rate_multiplier", "billing_type", "stream",
+	"duration_ms", "is_error", "ip_address", "user_agent",
+}
+
+func (s *UserDataService) writeUsageLogs(ctx context.Context, zw *zip.Writer, userID int64) (int, bool, error) {
+	w, err := zw.Create("usage_logs.csv")
+	if err != nil {
+		return 0, false, err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(usageLogExportHeader); err != nil {
+		return 0, false, err
+	}
+
+	count := 0
+	afterID := int64(0)
+	for count < userDataExportMaxUsageLogs {
+		logs, err := s.repo.ListUsageLogsForExport(ctx, userID, afterID, userDataExportUsageBatch)
+		if err != nil {
+			return 0, false, fmt.Errorf("list usage logs: %w", err)
+		}
+		for i := range logs {
+			l := &logs[i]
+			if err := cw.Write(usageLogExportRow(l)); err != nil {
+				return 0, false, err
+			}
+			afterID = l.ID
+			count++
+		}
+		if len(logs) < userDataExportUsageBatch {
+			cw.Flush()
+			return count, false, cw.Error()
+		}
+	}
+	cw.Flush()
+	return count, true, cw.Error()
+}
+
+func usageLogExportRow(l *UsageLog) []string {
+	optInt64 := func(v *int64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatInt(*v, 10)
+	}
+	optInt := func(v *int) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	}
+	optString := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return []string{
+		strconv.FormatInt(l.ID, 10),
+		l.CreatedAt.UTC().Format(time.RFC3339),
+		l.RequestID,
+		strconv.FormatInt(l.APIKeyID, 10),
+		optInt64(l.GroupID),
+		l.Model,
+		strconv.Itoa(l.InputTokens),
+		strconv.Itoa(l.OutputTokens),
+		strconv.Itoa(l.CacheCreationTokens),
+		strconv.Itoa(l.CacheReadTokens),
+		money(l.TotalCost),
+		money(l.ActualCost),
+		money(l.RateMultiplier),
+		strconv.Itoa(int(l.BillingType)),
+		strconv.FormatBool(l.Stream),
+		optInt(l.DurationMs),
+		strconv.FormatBool(l.IsError),
+		optString(l.IPAddress),
+		optString(l.UserAgent),
+	}
+}
+
+// ==================== 账号注销 ====================
+
+// GetDeletionStatus 返回用户当前未结束的注销申请；没有时返回 nil
+func (s *UserDataService) GetDeletionStatus(ctx context.Context, userID int64) (*AccountDeletionRequest, error) {
+	req, err := s.repo.GetOpenDeletionRequest(ctx, userID)
+	if errors.Is(err, ErrAccountDeletionNotFound) {
+		return nil, nil
+	}
+	return req, err
+}
+
+// RequestDeletion 申请注销账号：向账号邮箱发送确认链接（重复申请会重新生成链接）
+func (s *UserDataService) RequestDeletion(ctx context.Context, userID int64, reason, frontendBaseURL, ipAddress string) (*AccountDeletionRequest, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() {
+		return nil, ErrAccountDeletionAdmin
+	}
+	if strings.TrimSpace(user.Email) == "" || isReservedEmail(user.Email) {
+		return nil, ErrAccountDeletionEmailUnavailable
+	}
+	if s.emailService == nil {
+		return nil, ErrEmailNotConfigured
+	}
+	if _, err := s.emailService.GetSMTPConfig(ctx); err != nil {
+		return nil, err
+	}
+
+	token, err := randomHexString(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+	now := time.Now()
+
+	req, err := s.repo.GetOpenDeletionRequest(ctx, userID)
+	switch {
+	case err == nil && req.Status == AccountDeletionStatusScheduled:
+		return nil, ErrAccountDeletionScheduled
+	case err == nil:
+		req.TokenHash = hashToken(token)
+		req.TokenExpiresAt = now.Add(accountDeletionTokenTTL)
+		req.Reason = truncateString(strings.TrimSpace(reason), 1000)
+		req.IPAddress = ipAddress
+		if err := s.repo.UpdateDeletionRequest(ctx, req); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, ErrAccountDeletionNotFound):
+		req = &AccountDeletionRequest{
+			UserID:         userID,
+			Email:          user.Email,
+			Status:         AccountDeletionStatusPending,
+			Reason:         truncateString(strings.TrimSpace(reason), 1000),
+			TokenHash:      hashToken(token),
+			TokenExpiresAt: now.Add(accountDeletionTokenTTL),
+			IPAddress:      ipAddress,
+		}
+		if err := s.repo.CreateDeletionRequest(ctx, req); err != nil {
+			return nil, fmt.Errorf("create deletion request: %w", err)
+		}
+	default:
+		return nil, err
+	}
+
+	confirmURL := fmt.Sprintf("%s/account-deletion/confirm?token=%s", strings.TrimSuffix(frontendBaseURL, "/"), token)
+	subject, body := buildAccountDeletionEmail(s.siteName(ctx), confirmURL)
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), userDataEmailTimeout)
+	defer cancel()
+	if err := s.emailService.SendEmail(sendCtx, user.Email, subject, body); err != nil {
+		return nil, fmt.Errorf("send confirmation email: %w", err)
+	}
+	log.Printf("[UserData] deletion requested: user=%d request=%d", userID, req.ID)
+	return req, nil
+}
+
+// CancelDeletion 用户在确认前取消注销申请
+func (s *UserDataService) CancelDeletion(ctx context.Context, userID int64) error {
+	req, err := s.repo.GetOpenDeletionRequest(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if req.Status != AccountDeletionStatusPending {
+		return ErrAccountDeletionScheduled
+	}
+	now := time.Now()
+	req.Status = AccountDeletionStatusCancelled
+	req.CancelledAt = &now
+	return s.repo.UpdateDeletionRequest(ctx, req)
+}
+
+// ConfirmDeletion 通过邮件链接确认注销：立即停用账号与 API Key、撤销全部会话，宽限期后匿名化
+func (s *UserDataService) ConfirmDeletion(ctx context.Context, token string) (*AccountDeletionRequest, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrAccountDeletionInvalidToken
+	}
+	req, err := s.repo.GetDeletionRequestByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, ErrAccountDeletionNotFound) {
+			return nil, ErrAccountDeletionInvalidToken
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if req.Status != AccountDeletionStatusPending || now.After(req.TokenExpiresAt) {
+		return nil, ErrAccountDeletionInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() {
+		return nil, ErrAccountDeletionAdmin
+	}
+
+	scheduledFor := now.Add(AccountDeletionGracePeriod)
+	req.Status = AccountDeletionStatusScheduled
+	req.ConfirmedAt = &now
+	req.ScheduledFor = &scheduledFor
+	// 确认链接一次性使用
+	req.TokenHash = hashToken(token + ":used")
+	// 停用账号、停用 API Key 与记录恢复信息在同一事务内完成，避免留下部分停用且无法恢复的状态
+	if err := s.repo.ScheduleDeletion(ctx, req); err != nil {
+		if errors.Is(err, ErrAccountDeletionNotFound) {
+			return nil, ErrAccountDeletionInvalidToken
+		}
+		return nil, err
+	}
+
+	if s.apiKeyService != nil {
+		s.apiKeyService.InvalidateAuthCacheByUserID(ctx, user.ID)
+	}
+	if s.authService != nil {
+		if err := s.authService.RevokeAllUserSessions(ctx, user.ID); err != nil {
+			log.Printf("[UserData] revoke sessions failed: user=%d err=%v", user.ID, err)
+		}
+	}
+	log.Printf("[UserData] deletion confirmed: user=%d request=%d scheduled_for=%s keys_disabled=%d",
+		user.ID, req.ID, scheduledFor.Format(time.RFC3339), len(req.RevokedAPIKeyIDs))
+	return req, nil
+}
+
+// ListDeletionRequests 管理员查看注销申请
+func (s *UserDataService) ListDeletionRequests(ctx context.Context, params pagination.PaginationParams, filter AccountDeletionFilter) ([]AccountDeletionRequest, *pagination.PaginationResult, error) {
+	return s.repo.ListDeletionRequests(ctx, params, filter)
+}
+
+// RestoreDeletion 管理员在宽限期内撤销注销：恢复账号状态与被停用的 API Key
+func (s *UserDataService) RestoreDeletion(ctx context.Context, requestID, adminID int64) (*AccountDeletionRequest, error) {
+	req, err := s.repo.GetDeletionRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != AccountDeletionStatusScheduled {
+		return nil, ErrAccountDeletionNotRestorable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	status := req.PreviousStatus
+	if status == "" {
+		status = StatusActive
+	}
+	if user.Status != status {
+		user.Status = status
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("restore user: %w", err)
+		}
+	}
+	for _, id := range req.RevokedAPIKeyIDs {
+		key, err := s.apiKeyRepo.GetByID(ctx, id)
+		if err != nil || key.Status != StatusAPIKeyDisabled {
+			continue
+		}
+		key.Status = StatusAPIKeyActive
+		if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+			log.Printf("[UserData] restore api key failed: key=%d err=%v", id, err)
+		}
+	}
+	if s.apiKeyService != nil {
+		s.apiKeyService.InvalidateAuthCacheByUserID(ctx, user.ID)
+	}
+
+	now := time.Now()
+	req.Status = AccountDeletionStatusRestored
+	req.RestoredAt = &now
+	req.RestoredBy = &adminID
+	if err := s.repo.UpdateDeletionRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	log.Printf("[UserData] deletion restored: user=%d request=%d admin=%d", user.ID, req.ID, adminID)
+	return req, nil
+}
+
+// FinalizeDueDeletions 对宽限期已结束的申请执行匿名化删除，返回完成数量
+func (s *UserDataService) FinalizeDueDeletions(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.repo.ListDueDeletionRequestIDs(ctx, now, userDataWorkerBatch)
+	if err != nil {
+		return 0, err
+	}
+	done := 0
+	for _, id := range ids {
+		if err := s.finalizeDeletion(ctx, id, now); err != nil {
+			log.Printf("[UserData] finalize deletion failed: request=%d err=%v", id, err)
+			continue
+		}
+		done++
+	}
+	return done, nil
+}
+
+func (s *UserDataService) finalizeDeletion(ctx context.Context, requestID int64, now time.Time) error {
+	req, err := s.repo.GetDeletionRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if req.Status != AccountDeletionStatusScheduled || req.ScheduledFor == nil || now.Before(*req.ScheduledFor) {
+		return nil
+	}
+	if s.apiKeyService != nil {
+		s.apiKeyService.InvalidateAuthCacheByUserID(ctx, req.UserID)
+	}
+	if err := s.repo.PurgeUser(ctx, req.UserID); err != nil {
+		return fmt.Errorf("purge user: %w", err)
+	}
+	req.Status = AccountDeletionStatusCompleted
+	req.CompletedAt = &now
+	req.Email = MaskEmail(req.Email)
+	req.Reason = ""
+	req.IPAddress = ""
+	if err := s.repo.UpdateDeletionRequest(ctx, req); err != nil {
+		return err
+	}
+	log.Printf("[UserData] deletion completed: user=%d request=%d", req.UserID, req.ID)
+	return nil
+}
+
+func (s *UserDataService) siteName(ctx context.Context) string {
+	if s.settingService != nil {
+		return s.settingService.GetSiteName(ctx)
+	}
+	return "Sub2API"
+}
+
+func buildAccountDeletionEmail(siteName, confirmURL string) (string, string) {
+	subject := fmt.Sprintf("[%s] Confirm account deletion", siteName)
+	graceDays := int(AccountDeletionGracePeriod / (24 * time.Hour))
+	body := fmt.Sprintf(`<p>Hello,</p>
+<p>We received a request to delete your %s account. To confirm, open the link below within 24 hours:</p>
+<p><a href="%s">Confirm account deletion</a></p>
+<p>After you confirm, your account, API keys and sign-in sessions are disabled immediately. Your personal data is permanently removed after %d days; during that period an administrator can still restore the account.</p>
+<p>If you did not request this, ignore this email and your account will stay unchanged.</p>
+<p style="color:#999;font-size:12px;">This is an automated message, please do not reply.</p>
+`, html.EscapeString(siteName), html.EscapeString(confirmURL), graceDays)
+	return subject, body
+}
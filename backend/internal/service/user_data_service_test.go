@@ -0,0 +1,418 @@
+//go:build unit
+
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type userDataRepoStub struct {
+	UserDataRepository
+
+	exports   map[int64]*UserDataExport
+	archives  map[int64][]byte
+	deletions map[int64]*AccountDeletionRequest
+	usageLogs []UsageLog
+	purged    []int64
+	nextID    int64
+
+	// ScheduleDeletion 在 users / keys 上模拟事务内的停用；scheduleErr 非空时整体失败
+	users       *userDataUserRepoStub
+	keys        *userDataAPIKeyRepoStub
+	scheduleErr error
+}
+
+func newUserDataRepoStub() *userDataRepoStub {
+	return &userDataRepoStub{
+		exports:   map[int64]*UserDataExport{},
+		archives:  map[int64][]byte{},
+		deletions: map[int64]*AccountDeletionRequest{},
+	}
+}
+
+func (s *userDataRepoStub) CreateExport(ctx context.Context, export *UserDataExport) error {
+	s.nextID++
+	export.ID = s.nextID
+	export.CreatedAt = time.Now()
+	copied := *export
+	s.exports[export.ID] = &copied
+	return nil
+}
+
+func (s *userDataRepoStub) GetExport(ctx context.Context, id int64) (*UserDataExport, error) {
+	export, ok := s.exports[id]
+	if !ok {
+		return nil, ErrUserDataExportNotFound
+	}
+	copied := *export
+	return &copied, nil
+}
+
+func (s *userDataRepoStub) GetExportArchive(ctx context.Context, id int64) ([]byte, error) {
+	archive, ok := s.archives[id]
+	if !ok {
+		return nil, ErrUserDataExportNotFound
+	}
+	return archive, nil
+}
+
+func (s *userDataRepoStub) HasActiveExport(ctx context.Context, userID int64) (bool, error) {
+	for _, export := range s.exports {
+		if export.UserID == userID && (export.Status == UserDataExportStatusPending || export.Status == UserDataExportStatusProcessing) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *userDataRepoStub) CountExportsSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	count := 0
+	for _, export := range s.exports {
+		if export.UserID == userID && !export.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *userDataRepoStub) ListUsageLogsForExport(ctx context.Context, userID, afterID int64, limit int) ([]UsageLog, error) {
+	out := []UsageLog{}
+	for _, l := range s.usageLogs {
+		if l.UserID == userID && l.ID > afterID && len(out) < limit {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (s *userDataRepoStub) CreateDeletionRequest(ctx context.Context, req *AccountDeletionRequest) error {
+	s.nextID++
+	req.ID = s.nextID
+	req.CreatedAt = time.Now()
+	copied := *req
+	s.deletions[req.ID] = &copied
+	return nil
+}
+
+func (s *userDataRepoStub) GetDeletionRequest(ctx context.Context, id int64) (*AccountDeletionRequest, error) {
+	req, ok := s.deletions[id]
+	if !ok {
+		return nil, ErrAccountDeletionNotFound
+	}
+	copied := *req
+	return &copied, nil
+}
+
+func (s *userDataRepoStub) GetDeletionRequestByTokenHash(ctx context.Context, tokenHash string) (*AccountDeletionRequest, error) {
+	for _, req := range s.deletions {
+		if req.TokenHash == tokenHash {
+			copied := *req
+			return &copied, nil
+		}
+	}
+	return nil, ErrAccountDeletionNotFound
+}
+
+func (s *userDataRepoStub) UpdateDeletionRequest(ctx context.Context, req *AccountDeletionRequest) error {
+	copied := *req
+	s.deletions[req.ID] = &copied
+	return nil
+}
+
+func (s *userDataRepoStub) ScheduleDeletion(ctx context.Context, req *AccountDeletionRequest) error {
+	if s.scheduleErr != nil {
+		return s.scheduleErr
+	}
+	current, ok := s.deletions[req.ID]
+	if !ok || current.Status != AccountDeletionStatusPending {
+		return ErrAccountDeletionNotFound
+	}
+	user := s.users.users[req.UserID]
+	req.PreviousStatus = user.Status
+	user.Status = StatusDisabled
+	req.RevokedAPIKeyIDs = []int64{}
+	for _, key := range s.keys.keys {
+		if key.UserID == req.UserID && key.Status == StatusAPIKeyActive {
+			key.Status = StatusAPIKeyDisabled
+			req.RevokedAPIKeyIDs = append(req.RevokedAPIKeyIDs, key.ID)
+		}
+	}
+	return s.UpdateDeletionRequest(ctx, req)
+}
+
+func (s *userDataRepoStub) ListDueDeletionRequestIDs(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	ids := []int64{}
+	for id, req := range s.deletions {
+		if req.Status == AccountDeletionStatusScheduled && req.ScheduledFor != nil && !req.ScheduledFor.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *userDataRepoStub) PurgeUser(ctx context.Context, userID int64) error {
+	s.purged = append(s.purged, userID)
+	return nil
+}
+
+type userDataUserRepoStub struct {
+	UserRepository
+
+	users map[int64]*User
+}
+
+func (s *userDataUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	if user, ok := s.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func (s *userDataUserRepoStub) Update(ctx context.Context, user *User) error {
+	copied := *user
+	s.users[user.ID] = &copied
+	return nil
+}
+
+type userDataAPIKeyRepoStub struct {
+	APIKeyRepository
+
+	keys map[int64]*APIKey
+}
+
+func (s *userDataAPIKeyRepoStub) ListByUserID(ctx context.Context, userID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error) {
+	out := []APIKey{}
+	for _, key := range s.keys {
+		if key.UserID == userID {
+			out = append(out, *key)
+		}
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: 1, PageSize: params.PageSize}, nil
+}
+
+func (s *userDataAPIKeyRepoStub) GetByID(ctx context.Context, id int64) (*APIKey, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (s *userDataAPIKeyRepoStub) Update(ctx context.Context, key *APIKey) error {
+	copied := *key
+	s.keys[key.ID] = &copied
+	return nil
+}
+
+type userDataSubRepoStub struct {
+	UserSubscriptionRepository
+}
+
+func (s *userDataSubRepoStub) ListByUserID(ctx context.Context, userID int64) ([]UserSubscription, error) {
+	return nil, nil
+}
+
+type userDataRedeemRepoStub struct {
+	RedeemCodeRepository
+}
+
+func (s *userDataRedeemRepoStub) ListByUser(ctx context.Context, userID int64, limit int) ([]RedeemCode, error) {
+	return nil, nil
+}
+
+func newUserDataTestService() (*UserDataService, *userDataRepoStub, *userDataUserRepoStub, *userDataAPIKeyRepoStub) {
+	repo := newUserDataRepoStub()
+	users := &userDataUserRepoStub{users: map[int64]*User{
+		1: {ID: 1, Email: "admin@example.com", Role: RoleAdmin, Status: StatusActive},
+		2: {ID: 2, Email: "user@example.com", Role: RoleUser, Status: StatusActive},
+	}}
+	keys := &userDataAPIKeyRepoStub{keys: map[int64]*APIKey{
+		10: {ID: 10, UserID: 2, Key: "sk-abcdefghijklmnop", Name: "active", Status: StatusAPIKeyActive},
+		11: {ID: 11, UserID: 2, Key: "sk-qrstuvwxyz012345", Name: "disabled", Status: StatusAPIKeyDisabled},
+	}}
+	repo.users, repo.keys = users, keys
+	svc := NewUserDataService(repo, users, nil, nil, keys, nil, &userDataSubRepoStub{}, &userDataRedeemRepoStub{}, nil, nil, nil)
+	return svc, repo, users, keys
+}
+
+func TestUserDataService_RequestExportLimits(t *testing.T) {
+	svc, repo, _, _ := newUserDataTestService()
+	ctx := context.Background()
+
+	export, err := svc.RequestExport(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, UserDataExportStatusPending, export.Status)
+
+	// 已有进行中的导出
+	_, err = svc.RequestExport(ctx, 2)
+	require.ErrorIs(t, err, ErrUserDataExportInProgress)
+
+	repo.exports[export.ID].Status = UserDataExportStatusCompleted
+	for i := 1; i < userDataExportDailyLimit; i++ {
+		next, err := svc.RequestExport(ctx, 2)
+		require.NoError(t, err)
+		repo.exports[next.ID].Status = UserDataExportStatusCompleted
+	}
+	_, err = svc.RequestExport(ctx, 2)
+	require.ErrorIs(t, err, ErrUserDataExportTooFrequent)
+}
+
+func TestUserDataService_DownloadExport(t *testing.T) {
+	svc, repo, _, _ := newUserDataTestService()
+	ctx := context.Background()
+
+	export, err := svc.RequestExport(ctx, 2)
+	require.NoError(t, err)
+
+	_, _, err = svc.DownloadExport(ctx, 2, export.ID)
+	require.ErrorIs(t, err, ErrUserDataExportNotReady)
+
+	expiresAt := time.Now().Add(time.Hour)
+	repo.exports[export.ID].Status = UserDataExportStatusCompleted
+	repo.exports[export.ID].ExpiresAt = &expiresAt
+	repo.archives[export.ID] = []byte("zip")
+
+	// 其他用户不可下载
+	_, _, err = svc.DownloadExport(ctx, 3, export.ID)
+	require.ErrorIs(t, err, ErrUserDataExportNotFound)
+
+	archive, filename, err := svc.DownloadExport(ctx, 2, export.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("zip"), archive)
+	require.Contains(t, filename, "account-data-2-")
+
+	expired := time.Now().Add(-time.Minute)
+	repo.exports[export.ID].ExpiresAt = &expired
+	_, _, err = svc.DownloadExport(ctx, 2, export.ID)
+	require.ErrorIs(t, err, ErrUserDataExportExpired)
+}
+
+func TestUserDataService_BuildArchiveMasksKeys(t *testing.T) {
+	svc, repo, _, _ := newUserDataTestService()
+	repo.usageLogs = []UsageLog{
+		{ID: 1, UserID: 2, APIKeyID: 10, Model: "claude-sonnet-4", TotalCost: 0.5, CreatedAt: time.Now()},
+		{ID: 2, UserID: 1, APIKeyID: 99, Model: "other-user", CreatedAt: time.Now()},
+	}
+
+	data, err := svc.BuildArchive(context.Background(), 2)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	require.Contains(t, files, "profile.json")
+	require.Contains(t, files, "manifest.json")
+	require.Contains(t, files["profile.json"], "user@example.com")
+	require.Contains(t, files["api_keys.json"], "sk-abc...mnop")
+	require.NotContains(t, files["api_keys.json"], "sk-abcdefghijklmnop")
+	require.Contains(t, files["usage_logs.csv"], "claude-sonnet-4")
+	require.NotContains(t, files["usage_logs.csv"], "other-user")
+}
+
+func TestUserDataService_ConfirmRestoreAndFinalize(t *testing.T) {
+	svc, repo, users, keys := newUserDataTestService()
+	ctx := context.Background()
+
+	token := "confirm-token"
+	req := &AccountDeletionRequest{
+		UserID:         2,
+		Email:          "user@example.com",
+		Status:         AccountDeletionStatusPending,
+		TokenHash:      hashToken(token),
+		TokenExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, repo.CreateDeletionRequest(ctx, req))
+
+	_, err := svc.ConfirmDeletion(ctx, "wrong-token")
+	require.ErrorIs(t, err, ErrAccountDeletionInvalidToken)
+
+	confirmed, err := svc.ConfirmDeletion(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, AccountDeletionStatusScheduled, confirmed.Status)
+	require.NotNil(t, confirmed.ScheduledFor)
+	require.Equal(t, StatusActive, confirmed.PreviousStatus)
+	require.Equal(t, []int64{10}, confirmed.RevokedAPIKeyIDs)
+	require.Equal(t, StatusDisabled, users.users[2].Status)
+	require.Equal(t, StatusAPIKeyDisabled, keys.keys[10].Status)
+
+	// 确认链接只能使用一次
+	_, err = svc.ConfirmDeletion(ctx, token)
+	require.ErrorIs(t, err, ErrAccountDeletionInvalidToken)
+
+	// 管理员恢复：账号与此前启用的 Key 恢复，原本停用的 Key 保持停用
+	restored, err := svc.RestoreDeletion(ctx, req.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, AccountDeletionStatusRestored, restored.Status)
+	require.Equal(t, int64(1), *restored.RestoredBy)
+	require.Equal(t, StatusActive, users.users[2].Status)
+	require.Equal(t, StatusAPIKeyActive, keys.keys[10].Status)
+	require.Equal(t, StatusAPIKeyDisabled, keys.keys[11].Status)
+
+	_, err = svc.RestoreDeletion(ctx, req.ID, 1)
+	require.ErrorIs(t, err, ErrAccountDeletionNotRestorable)
+
+	// 宽限期结束后匿名化
+	past := time.Now().Add(-time.Minute)
+	scheduled := &AccountDeletionRequest{
+		UserID:       2,
+		Email:        "user@example.com",
+		Status:       AccountDeletionStatusScheduled,
+		TokenHash:    "used",
+		ScheduledFor: &past,
+	}
+	require.NoError(t, repo.CreateDeletionRequest(ctx, scheduled))
+
+	done, err := svc.FinalizeDueDeletions(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 1, done)
+	require.Equal(t, []int64{2}, repo.purged)
+	require.Equal(t, AccountDeletionStatusCompleted, repo.deletions[scheduled.ID].Status)
+	require.NotEqual(t, "user@example.com", repo.deletions[scheduled.ID].Email)
+}
+
+func TestUserDataService_ConfirmDeletionFailureLeavesRequestPending(t *testing.T) {
+	svc, repo, users, keys := newUserDataTestService()
+	ctx := context.Background()
+
+	token := "confirm-token"
+	req := &AccountDeletionRequest{
+		UserID:         2,
+		Email:          "user@example.com",
+		Status:         AccountDeletionStatusPending,
+		TokenHash:      hashToken(token),
+		TokenExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, repo.CreateDeletionRequest(ctx, req))
+
+	repo.scheduleErr = errors.New("db down")
+	_, err := svc.ConfirmDeletion(ctx, token)
+	require.Error(t, err)
+	require.Equal(t, AccountDeletionStatusPending, repo.deletions[req.ID].Status)
+	require.Equal(t, StatusActive, users.users[2].Status)
+	require.Equal(t, StatusAPIKeyActive, keys.keys[10].Status)
+
+	// 并发确认：申请在读取后已被另一请求处理，仓储的状态条件不再匹配
+	repo.scheduleErr = ErrAccountDeletionNotFound
+	_, err = svc.ConfirmDeletion(ctx, token)
+	require.ErrorIs(t, err, ErrAccountDeletionInvalidToken)
+}
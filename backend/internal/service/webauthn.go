@@ -0,0 +1,200 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// WebAuthn 协议校验（CBOR / COSE 解析、challenge / origin / rpIdHash / 标志位 / 签名）由 go-webauthn 完成，
+// 这里只负责依赖方配置、用户与凭据的适配，以及前端请求格式与库类型之间的转换。
+// attestation 固定请求 "none"，不校验证明链。
+
+// webauthnSupportedAlgs 按优先级排列的公钥算法（用于 pubKeyCredParams）
+var webauthnSupportedAlgs = []webauthncose.COSEAlgorithmIdentifier{
+	webauthncose.AlgES256,
+	webauthncose.AlgEdDSA,
+	webauthncose.AlgRS256,
+}
+
+// WebAuthnRelyingParty 依赖方（站点）信息
+type WebAuthnRelyingParty struct {
+	ID      string
+	Name    string
+	Origins []string
+}
+
+// WebAuthnCreationOptions 对应浏览器 PublicKeyCredentialCreationOptions（二进制字段为 base64url）
+type WebAuthnCreationOptions = protocol.PublicKeyCredentialCreationOptions
+
+// WebAuthnRequestOptions 对应浏览器 PublicKeyCredentialRequestOptions（二进制字段为 base64url）
+type WebAuthnRequestOptions = protocol.PublicKeyCredentialRequestOptions
+
+// WebAuthnAttestationResponse 前端提交的注册结果（二进制字段为 base64url）
+type WebAuthnAttestationResponse struct {
+	CredentialID      string   `json:"credential_id" binding:"required"`
+	ClientDataJSON    string   `json:"client_data_json" binding:"required"`
+	AttestationObject string   `json:"attestation_object" binding:"required"`
+	Transports        []string `json:"transports"`
+}
+
+// WebAuthnAssertionResponse 前端提交的断言结果（二进制字段为 base64url）
+type WebAuthnAssertionResponse struct {
+	CredentialID      string `json:"credential_id" binding:"required"`
+	ClientDataJSON    string `json:"client_data_json" binding:"required"`
+	AuthenticatorData string `json:"authenticator_data" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+	UserHandle        string `json:"user_handle"`
+}
+
+// decodeWebAuthnBase64 解码 base64url（兼容带填充 / 标准字母表的输入）
+func decodeWebAuthnBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func encodeWebAuthnBase64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// webauthnUserHandle 用户句柄：用户 ID 的 8 字节大端编码（不含邮箱等个人信息）
+func webauthnUserHandle(userID int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(userID))
+	return b
+}
+
+// newWebAuthn 按依赖方信息构造校验器（依赖方按请求解析，因此每次调用单独构造）
+func newWebAuthn(rp WebAuthnRelyingParty, timeout time.Duration) (*webauthn.WebAuthn, error) {
+	name := rp.Name
+	if name == "" {
+		name = rp.ID
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:          rp.ID,
+		RPDisplayName: name,
+		RPOrigins:     rp.Origins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Timeout: timeout, TimeoutUVD: timeout},
+			Registration: webauthn.TimeoutConfig{Timeout: timeout, TimeoutUVD: timeout},
+		},
+	})
+}
+
+// webauthnUser 适配 webauthn.User
+type webauthnUser struct {
+	id          int64
+	name        string
+	displayName string
+	credentials []WebAuthnCredential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte          { return webauthnUserHandle(u.id) }
+func (u *webauthnUser) WebAuthnName() string        { return u.name }
+func (u *webauthnUser) WebAuthnDisplayName() string { return u.displayName }
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	out := make([]webauthn.Credential, 0, len(u.credentials))
+	for i := range u.credentials {
+		out = append(out, u.credentials[i].toLibrary())
+	}
+	return out
+}
+
+// toLibrary 转换为库的凭据记录
+func (c *WebAuthnCredential) toLibrary() webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, 0, len(c.Transports))
+	for _, t := range c.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(t))
+	}
+	return webauthn.Credential{
+		ID:        c.CredentialID,
+		PublicKey: c.PublicKey,
+		Transport: transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: c.BackupEligible != nil && *c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    c.AAGUID,
+			SignCount: uint32(c.SignCount),
+		},
+	}
+}
+
+func credentialDescriptors(credentials []WebAuthnCredential) []protocol.CredentialDescriptor {
+	out := make([]protocol.CredentialDescriptor, 0, len(credentials))
+	for i := range credentials {
+		out = append(out, credentials[i].toLibrary().Descriptor())
+	}
+	return out
+}
+
+// parseWebAuthnAttestation 将前端提交的注册结果转换为库的解析结果
+func parseWebAuthnAttestation(resp *WebAuthnAttestationResponse) (*protocol.ParsedCredentialCreationData, error) {
+	rawID, err := decodeWebAuthnBase64(resp.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+	clientDataJSON, err := decodeWebAuthnBase64(resp.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	attestationObject, err := decodeWebAuthnBase64(resp.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	return protocol.CredentialCreationResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{ID: encodeWebAuthnBase64(rawID), Type: string(protocol.PublicKeyCredentialType)},
+			RawID:      rawID,
+		},
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AttestationObject:     attestationObject,
+			Transports:            normalizeWebAuthnTransports(resp.Transports),
+		},
+	}.Parse()
+}
+
+// parseWebAuthnAssertion 将前端提交的断言结果转换为库的解析结果
+func parseWebAuthnAssertion(resp *WebAuthnAssertionResponse) (*protocol.ParsedCredentialAssertionData, error) {
+	rawID, err := decodeWebAuthnBase64(resp.CredentialID)
+	if err != nil {
+		return nil, err
+	}
+	clientDataJSON, err := decodeWebAuthnBase64(resp.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	authenticatorData, err := decodeWebAuthnBase64(resp.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeWebAuthnBase64(resp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	userHandle, err := decodeWebAuthnBase64(resp.UserHandle)
+	if err != nil {
+		return nil, err
+	}
+	return protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{ID: encodeWebAuthnBase64(rawID), Type: string(protocol.PublicKeyCredentialType)},
+			RawID:      rawID,
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AuthenticatorData:     authenticatorData,
+			Signature:             signature,
+			UserHandle:            userHandle,
+		},
+	}.Parse()
+}
@@ -0,0 +1,661 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+var (
+	ErrWebAuthnChallengeExpired   = infraerrors.BadRequest("WEBAUTHN_CHALLENGE_EXPIRED", "passkey request expired, please try again")
+	ErrWebAuthnVerificationFailed = infraerrors.BadRequest("WEBAUTHN_VERIFICATION_FAILED", "passkey verification failed")
+	ErrWebAuthnCredentialNotFound = infraerrors.NotFound("WEBAUTHN_CREDENTIAL_NOT_FOUND", "passkey not found")
+	ErrWebAuthnCredentialExists   = infraerrors.Conflict("WEBAUTHN_CREDENTIAL_EXISTS", "this passkey is already registered")
+	ErrWebAuthnTooManyCredentials = infraerrors.BadRequest("WEBAUTHN_TOO_MANY_CREDENTIALS", "too many passkeys registered")
+	ErrWebAuthnInvalidName        = infraerrors.BadRequest("WEBAUTHN_INVALID_NAME", "passkey name must be 1-64 characters")
+	ErrPasskeyLoginDisabled       = infraerrors.Forbidden("PASSKEY_LOGIN_DISABLED", "passkey login is not enabled")
+	ErrRecoveryCodeInvalid        = infraerrors.BadRequest("RECOVERY_CODE_INVALID", "invalid recovery code")
+	ErrRecoveryCodesUnavailable   = infraerrors.BadRequest("RECOVERY_CODES_UNAVAILABLE", "enable two-factor authentication before generating recovery codes")
+)
+
+// 二步验证方式（登录响应中返回给前端）
+const (
+	TwoFactorMethodTotp         = "totp"
+	TwoFactorMethodWebAuthn     = "webauthn"
+	TwoFactorMethodRecoveryCode = "recovery_code"
+)
+
+const (
+	webauthnChallengeTTL      = 5 * time.Minute
+	webauthnMaxCredentials    = 10
+	webauthnMaxNameLength     = 64
+	webauthnDefaultName       = "Passkey"
+	webauthnPurposeRegister   = "register"
+	webauthnPurpose2FA        = "2fa"
+	webauthnPurposeLogin      = "login"
+	recoveryCodeCount         = 10
+	recoveryCodeAlphabet      = "abcdefghijkmnpqrstuvwxyz23456789" // 去除易混淆字符 l / o / 0 / 1
+	recoveryCodeHalfLength    = 5
+	webauthnSessionTokenBytes = 32
+)
+
+// WebAuthnCredential 用户注册的通行密钥
+type WebAuthnCredential struct {
+	ID           int64
+	UserID       int64
+	Name         string
+	CredentialID []byte
+	PublicKey    []byte // COSE 编码
+	SignCount    int64
+	AAGUID       []byte
+	Transports   []string
+	// BackupEligible 为 nil 表示早期注册、尚未记录（首次使用时按断言结果补齐）
+	BackupEligible *bool
+	BackupState    bool
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// WebAuthnCredentialRepository 通行密钥存储
+type WebAuthnCredentialRepository interface {
+	ListByUser(ctx context.Context, userID int64) ([]WebAuthnCredential, error)
+	CountByUser(ctx context.Context, userID int64) (int, error)
+	// GetByCredentialID 不存在时返回 (nil, nil)
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*WebAuthnCredential, error)
+	// Create 凭据 ID 已存在时返回 ErrWebAuthnCredentialExists
+	Create(ctx context.Context, credential *WebAuthnCredential) error
+	Rename(ctx context.Context, userID, id int64, name string) error
+	// UpdateUsage 保存签名计数与备份标志，并记录使用时间
+	UpdateUsage(ctx context.Context, id int64, signCount int64, backupEligible, backupState bool) error
+	Delete(ctx context.Context, userID, id int64) error
+}
+
+// RecoveryCodeRepository 二步验证恢复码存储（仅保存哈希）
+type RecoveryCodeRepository interface {
+	// Replace 作废用户现有恢复码并写入新的哈希
+	Replace(ctx context.Context, userID int64, hashes []string) error
+	// Consume 标记一个未使用的恢复码为已使用，返回是否匹配
+	Consume(ctx context.Context, userID int64, hash string) (bool, error)
+	CountUnused(ctx context.Context, userID int64) (int, error)
+}
+
+// WebAuthnCache 通行密钥 challenge 会话缓存（一次性）
+type WebAuthnCache interface {
+	SetChallenge(ctx context.Context, key string, session *WebAuthnChallengeSession, ttl time.Duration) error
+	// ConsumeChallenge 读取并删除会话，不存在时返回 (nil, nil)
+	ConsumeChallenge(ctx context.Context, key string) (*WebAuthnChallengeSession, error)
+}
+
+// WebAuthnChallengeSession 发起注册 / 断言时保存的库会话数据与依赖方信息
+type WebAuthnChallengeSession struct {
+	Data      webauthn.SessionData
+	UserID    int64
+	RPID      string
+	Origins   []string
+	CreatedAt time.Time
+}
+
+// WebAuthnRegistrationBegin 发起注册的结果
+type WebAuthnRegistrationBegin struct {
+	SessionToken string                   `json:"session_token"`
+	Options      *WebAuthnCreationOptions `json:"options"`
+}
+
+// WebAuthnLoginBegin 发起无密码登录的结果
+type WebAuthnLoginBegin struct {
+	SessionToken string                  `json:"session_token"`
+	Options      *WebAuthnRequestOptions `json:"options"`
+}
+
+// WebAuthnService 通行密钥（WebAuthn）注册、二步验证、无密码登录与恢复码
+type WebAuthnService struct {
+	cfg            *config.Config
+	credentialRepo WebAuthnCredentialRepository
+	recoveryRepo   RecoveryCodeRepository
+	cache          WebAuthnCache
+	userRepo       UserRepository
+	settingService *SettingService
+	emailService   *EmailService
+}
+
+// NewWebAuthnService creates a new WebAuthn service
+func NewWebAuthnService(
+	cfg *config.Config,
+	credentialRepo WebAuthnCredentialRepository,
+	recoveryRepo RecoveryCodeRepository,
+	cache WebAuthnCache,
+	userRepo UserRepository,
+	settingService *SettingService,
+	emailService *EmailService,
+) *WebAuthnService {
+	return &WebAuthnService{
+		cfg:            cfg,
+		credentialRepo: credentialRepo,
+		recoveryRepo:   recoveryRepo,
+		cache:          cache,
+		userRepo:       userRepo,
+		settingService: settingService,
+		emailService:   emailService,
+	}
+}
+
+// ResolveRelyingParty 解析依赖方信息：优先使用配置，否则按请求 Host / Origin 推导（前后端同域部署）
+func (s *WebAuthnService) ResolveRelyingParty(ctx context.Context, host, origin string, secure bool) WebAuthnRelyingParty {
+	var rp WebAuthnRelyingParty
+	if s.cfg != nil {
+		rp.ID = strings.ToLower(strings.TrimSpace(s.cfg.WebAuthn.RPID))
+		rp.Name = strings.TrimSpace(s.cfg.WebAuthn.RPName)
+		for _, o := range s.cfg.WebAuthn.Origins {
+			if o = strings.TrimSpace(o); o != "" {
+				rp.Origins = append(rp.Origins, o)
+			}
+		}
+	}
+	if rp.Name == "" && s.settingService != nil {
+		rp.Name = s.settingService.GetSiteName(ctx)
+	}
+
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if rp.ID == "" {
+		rp.ID = strings.ToLower(hostname)
+	}
+	if len(rp.Origins) == 0 {
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		rp.Origins = []string{scheme + "://" + host}
+		// 前端经反向代理 / 开发服务器访问时 Host 可能不同：接受与 RP ID 同域的 Origin
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			originHost := strings.ToLower(u.Hostname())
+			origin = strings.TrimRight(origin, "/")
+			if (originHost == rp.ID || strings.HasSuffix(originHost, "."+rp.ID)) && !strings.EqualFold(origin, rp.Origins[0]) {
+				rp.Origins = append(rp.Origins, origin)
+			}
+		}
+	}
+	return rp
+}
+
+// ListCredentials 列出用户的通行密钥
+func (s *WebAuthnService) ListCredentials(ctx context.Context, userID int64) ([]WebAuthnCredential, error) {
+	return s.credentialRepo.ListByUser(ctx, userID)
+}
+
+// BeginRegistration 发起通行密钥注册（需重新验证身份）
+func (s *WebAuthnService) BeginRegistration(ctx context.Context, userID int64, rp WebAuthnRelyingParty, emailCode, password string) (*WebAuthnRegistrationBegin, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := verifyUserIdentity(ctx, s.settingService, s.emailService, user, emailCode, password); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.credentialRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list credentials: %w", err)
+	}
+	if len(existing) >= webauthnMaxCredentials {
+		return nil, ErrWebAuthnTooManyCredentials
+	}
+
+	w, err := newWebAuthn(rp, webauthnChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("init webauthn: %w", err)
+	}
+	params := make([]protocol.CredentialParameter, 0, len(webauthnSupportedAlgs))
+	for _, alg := range webauthnSupportedAlgs {
+		params = append(params, protocol.CredentialParameter{Type: protocol.PublicKeyCredentialType, Algorithm: alg})
+	}
+	creation, data, err := w.BeginRegistration(
+		&webauthnUser{id: user.ID, name: user.Email, displayName: firstNonEmptyString(user.Username, user.Email)},
+		webauthn.WithCredentialParameters(params),
+		webauthn.WithExclusions(credentialDescriptors(existing)),
+		webauthn.WithConveyancePreference(protocol.PreferNoAttestation),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementPreferred,
+			UserVerification: protocol.VerificationPreferred,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("begin registration: %w", err)
+	}
+
+	sessionToken, err := s.saveSession(ctx, webauthnPurposeRegister, "", userID, rp, data)
+	if err != nil {
+		return nil, err
+	}
+	return &WebAuthnRegistrationBegin{SessionToken: sessionToken, Options: &creation.Response}, nil
+}
+
+// FinishRegistration 校验注册响应并保存通行密钥
+func (s *WebAuthnService) FinishRegistration(ctx context.Context, userID int64, sessionToken, name string, resp *WebAuthnAttestationResponse) (*WebAuthnCredential, error) {
+	name, err := normalizeWebAuthnName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.consumeChallenge(ctx, webauthnPurposeRegister, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, ErrWebAuthnChallengeExpired
+	}
+
+	w, err := newWebAuthn(WebAuthnRelyingParty{ID: session.RPID, Origins: session.Origins}, webauthnChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("init webauthn: %w", err)
+	}
+	parsed, err := parseWebAuthnAttestation(resp)
+	if err != nil {
+		log.Printf("[WebAuthn] registration parse failed: user=%d err=%v", userID, err)
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	registration, err := w.CreateCredential(&webauthnUser{id: userID}, session.Data, parsed)
+	if err != nil {
+		log.Printf("[WebAuthn] registration verification failed: user=%d err=%v", userID, webauthnErrorDetail(err))
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	count, err := s.credentialRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count credentials: %w", err)
+	}
+	if count >= webauthnMaxCredentials {
+		return nil, ErrWebAuthnTooManyCredentials
+	}
+
+	backupEligible := registration.Flags.BackupEligible
+	credential := &WebAuthnCredential{
+		UserID:         userID,
+		Name:           name,
+		CredentialID:   registration.ID,
+		PublicKey:      registration.PublicKey,
+		SignCount:      int64(registration.Authenticator.SignCount),
+		AAGUID:         registration.Authenticator.AAGUID,
+		Transports:     normalizeWebAuthnTransports(resp.Transports),
+		BackupEligible: &backupEligible,
+		BackupState:    registration.Flags.BackupState,
+	}
+	if err := s.credentialRepo.Create(ctx, credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// RenameCredential 重命名通行密钥
+func (s *WebAuthnService) RenameCredential(ctx context.Context, userID, id int64, name string) error {
+	name, err := normalizeWebAuthnName(name)
+	if err != nil {
+		return err
+	}
+	return s.credentialRepo.Rename(ctx, userID, id, name)
+}
+
+// DeleteCredential 删除通行密钥（需重新验证身份）
+func (s *WebAuthnService) DeleteCredential(ctx context.Context, userID, id int64, emailCode, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if err := verifyUserIdentity(ctx, s.settingService, s.emailService, user, emailCode, password); err != nil {
+		return err
+	}
+	return s.credentialRepo.Delete(ctx, userID, id)
+}
+
+// SecondFactorMethods 返回用户登录时可用的二步验证方式；为空表示无需二步验证
+func (s *WebAuthnService) SecondFactorMethods(ctx context.Context, user *User) ([]string, error) {
+	var methods []string
+	if user.TotpEnabled && s.settingService.IsTotpEnabled(ctx) {
+		methods = append(methods, TwoFactorMethodTotp)
+	}
+	count, err := s.credentialRepo.CountByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("count credentials: %w", err)
+	}
+	if count > 0 {
+		methods = append(methods, TwoFactorMethodWebAuthn)
+	}
+	if len(methods) == 0 {
+		return nil, nil
+	}
+
+	remaining, err := s.recoveryRepo.CountUnused(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("count recovery codes: %w", err)
+	}
+	if remaining > 0 {
+		methods = append(methods, TwoFactorMethodRecoveryCode)
+	}
+	return methods, nil
+}
+
+// BeginSecondFactor 为二步验证登录会话发起通行密钥断言
+func (s *WebAuthnService) BeginSecondFactor(ctx context.Context, tempToken string, userID int64, rp WebAuthnRelyingParty) (*WebAuthnRequestOptions, error) {
+	credentials, err := s.credentialRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list credentials: %w", err)
+	}
+	if len(credentials) == 0 {
+		return nil, ErrWebAuthnCredentialNotFound
+	}
+
+	w, err := newWebAuthn(rp, webauthnChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("init webauthn: %w", err)
+	}
+	assertion, data, err := w.BeginLogin(
+		&webauthnUser{id: userID, credentials: credentials},
+		webauthn.WithUserVerification(protocol.VerificationPreferred),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("begin login: %w", err)
+	}
+	if _, err := s.saveSession(ctx, webauthnPurpose2FA, tempToken, userID, rp, data); err != nil {
+		return nil, err
+	}
+	return &assertion.Response, nil
+}
+
+// FinishSecondFactor 校验二步验证登录会话的通行密钥断言
+func (s *WebAuthnService) FinishSecondFactor(ctx context.Context, tempToken string, userID int64, resp *WebAuthnAssertionResponse) error {
+	session, err := s.consumeChallenge(ctx, webauthnPurpose2FA, tempToken)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrWebAuthnChallengeExpired
+	}
+	_, err = s.verifyAssertion(ctx, session, resp)
+	return err
+}
+
+// BeginPasswordlessLogin 发起通行密钥无密码登录（可发现凭据，不指定 allowCredentials）
+func (s *WebAuthnService) BeginPasswordlessLogin(ctx context.Context, rp WebAuthnRelyingParty) (*WebAuthnLoginBegin, error) {
+	if !s.settingService.IsPasskeyLoginEnabled(ctx) {
+		return nil, ErrPasskeyLoginDisabled
+	}
+	w, err := newWebAuthn(rp, webauthnChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("init webauthn: %w", err)
+	}
+	assertion, data, err := w.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, fmt.Errorf("begin login: %w", err)
+	}
+	sessionToken, err := s.saveSession(ctx, webauthnPurposeLogin, "", 0, rp, data)
+	if err != nil {
+		return nil, err
+	}
+	return &WebAuthnLoginBegin{SessionToken: sessionToken, Options: &assertion.Response}, nil
+}
+
+// FinishPasswordlessLogin 校验无密码登录断言并返回对应用户。
+// 通行密钥本身同时具备持有因素与用户验证（UV），因此不再要求额外的二步验证。
+func (s *WebAuthnService) FinishPasswordlessLogin(ctx context.Context, sessionToken string, resp *WebAuthnAssertionResponse) (*User, error) {
+	if !s.settingService.IsPasskeyLoginEnabled(ctx) {
+		return nil, ErrPasskeyLoginDisabled
+	}
+	session, err := s.consumeChallenge(ctx, webauthnPurposeLogin, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.verifyAssertion(ctx, session, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, credential.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrWebAuthnVerificationFailed
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+	return user, nil
+}
+
+// verifyAssertion 校验断言（会话指定用户时为二步验证，否则为可发现凭据的无密码登录），成功后保存签名计数
+func (s *WebAuthnService) verifyAssertion(ctx context.Context, session *WebAuthnChallengeSession, resp *WebAuthnAssertionResponse) (*WebAuthnCredential, error) {
+	parsed, err := parseWebAuthnAssertion(resp)
+	if err != nil {
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	stored, err := s.credentialRepo.GetByCredentialID(ctx, parsed.RawID)
+	if err != nil {
+		return nil, fmt.Errorf("get credential: %w", err)
+	}
+	if stored == nil || (session.UserID != 0 && stored.UserID != session.UserID) {
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	// 早期注册的凭据未记录备份标志：以本次断言为准
+	if stored.BackupEligible == nil {
+		backupEligible := parsed.Response.AuthenticatorData.Flags.HasBackupEligible()
+		stored.BackupEligible = &backupEligible
+	}
+
+	w, err := newWebAuthn(WebAuthnRelyingParty{ID: session.RPID, Origins: session.Origins}, webauthnChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("init webauthn: %w", err)
+	}
+	user := &webauthnUser{id: stored.UserID, credentials: []WebAuthnCredential{*stored}}
+	var validated *webauthn.Credential
+	if session.UserID != 0 {
+		validated, err = w.ValidateLogin(user, session.Data, parsed)
+	} else {
+		validated, err = w.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			if !bytes.Equal(userHandle, user.WebAuthnID()) {
+				return nil, errors.New("user handle does not match credential owner")
+			}
+			return user, nil
+		}, session.Data, parsed)
+	}
+	if err != nil {
+		log.Printf("[WebAuthn] assertion verification failed: credential=%d err=%v", stored.ID, webauthnErrorDetail(err))
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	// 签名计数未递增说明认证器可能被克隆（两者均为 0 表示认证器不支持计数）
+	if validated.Authenticator.CloneWarning {
+		log.Printf("[WebAuthn] sign count regression: credential=%d stored=%d got=%d", stored.ID, stored.SignCount, parsed.Response.AuthenticatorData.Counter)
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	stored.SignCount = int64(validated.Authenticator.SignCount)
+	stored.BackupState = validated.Flags.BackupState
+	// 签名计数必须落库，否则无法发现之后的克隆认证器
+	if err := s.credentialRepo.UpdateUsage(ctx, stored.ID, stored.SignCount, validated.Flags.BackupEligible, stored.BackupState); err != nil {
+		return nil, fmt.Errorf("update credential usage: %w", err)
+	}
+	return stored, nil
+}
+
+// GenerateRecoveryCodes 生成新的恢复码（旧恢复码全部作废，需重新验证身份）。
+// 明文只在此处返回一次。
+func (s *WebAuthnService) GenerateRecoveryCodes(ctx context.Context, userID int64, emailCode, password string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := verifyUserIdentity(ctx, s.settingService, s.emailService, user, emailCode, password); err != nil {
+		return nil, err
+	}
+
+	count, err := s.credentialRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count credentials: %w", err)
+	}
+	if !user.TotpEnabled && count == 0 {
+		return nil, ErrRecoveryCodesUnavailable
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := s.recoveryRepo.Replace(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// CountRecoveryCodes 返回剩余可用的恢复码数量
+func (s *WebAuthnService) CountRecoveryCodes(ctx context.Context, userID int64) (int, error) {
+	return s.recoveryRepo.CountUnused(ctx, userID)
+}
+
+// ConsumeRecoveryCode 使用一个恢复码完成二步验证
+func (s *WebAuthnService) ConsumeRecoveryCode(ctx context.Context, userID int64, code string) error {
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeHalfLength*2 {
+		return ErrRecoveryCodeInvalid
+	}
+	ok, err := s.recoveryRepo.Consume(ctx, userID, hashRecoveryCode(normalized))
+	if err != nil {
+		return fmt.Errorf("consume recovery code: %w", err)
+	}
+	if !ok {
+		return ErrRecoveryCodeInvalid
+	}
+	return nil
+}
+
+// saveSession 保存库会话数据，token 为空时生成新的会话 token
+func (s *WebAuthnService) saveSession(ctx context.Context, purpose, token string, userID int64, rp WebAuthnRelyingParty, data *webauthn.SessionData) (string, error) {
+	sessionToken := token
+	if sessionToken == "" {
+		var err error
+		sessionToken, err = generateRandomToken(webauthnSessionTokenBytes)
+		if err != nil {
+			return "", fmt.Errorf("generate session token: %w", err)
+		}
+	}
+
+	session := &WebAuthnChallengeSession{
+		Data:      *data,
+		UserID:    userID,
+		RPID:      rp.ID,
+		Origins:   rp.Origins,
+		CreatedAt: time.Now(),
+	}
+	if err := s.cache.SetChallenge(ctx, purpose+":"+sessionToken, session, webauthnChallengeTTL); err != nil {
+		return "", fmt.Errorf("store challenge: %w", err)
+	}
+	return sessionToken, nil
+}
+
+func (s *WebAuthnService) consumeChallenge(ctx context.Context, purpose, token string) (*WebAuthnChallengeSession, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, ErrWebAuthnChallengeExpired
+	}
+	session, err := s.cache.ConsumeChallenge(ctx, purpose+":"+token)
+	if err != nil {
+		return nil, fmt.Errorf("get challenge: %w", err)
+	}
+	if session == nil {
+		return nil, ErrWebAuthnChallengeExpired
+	}
+	return session, nil
+}
+
+// webauthnErrorDetail 库错误的详细信息（仅用于日志）
+func webauthnErrorDetail(err error) string {
+	var perr *protocol.Error
+	if errors.As(err, &perr) {
+		return fmt.Sprintf("%s: %s %s", perr.Type, perr.Details, perr.DevInfo)
+	}
+	return err.Error()
+}
+
+func normalizeWebAuthnName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return webauthnDefaultName, nil
+	}
+	if utf8.RuneCountInString(name) > webauthnMaxNameLength {
+		return "", ErrWebAuthnInvalidName
+	}
+	return name, nil
+}
+
+var webauthnKnownTransports = map[string]struct{}{
+	"usb": {}, "nfc": {}, "ble": {}, "internal": {}, "hybrid": {}, "smart-card": {},
+}
+
+func normalizeWebAuthnTransports(transports []string) []string {
+	out := make([]string, 0, len(transports))
+	seen := make(map[string]struct{}, len(transports))
+	for _, t := range transports {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if _, ok := webauthnKnownTransports[t]; !ok {
+			continue
+		}
+		if _, dup := seen[t]; dup {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
+
+// generateRecoveryCode 生成形如 "abcde-fghij" 的恢复码（约 50 bit 熵）
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeHalfLength*2)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i, v := range raw {
+		if i == recoveryCodeHalfLength {
+			b.WriteByte('-')
+		}
+		b.WriteByte(recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格与连字符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
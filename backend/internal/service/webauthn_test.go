@@ -0,0 +1,482 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// --- 测试用认证器 ---
+
+type testAuthenticator struct {
+	credentialID []byte
+	ecKey        *ecdsa.PrivateKey
+	edKey        ed25519.PrivateKey
+	signCount    uint32
+}
+
+func newTestAuthenticator(t *testing.T, eddsa bool) *testAuthenticator {
+	a := &testAuthenticator{credentialID: make([]byte, 16)}
+	_, err := rand.Read(a.credentialID)
+	require.NoError(t, err)
+	if eddsa {
+		_, a.edKey, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		a.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	require.NoError(t, err)
+	return a
+}
+
+func (a *testAuthenticator) coseKey(t *testing.T) []byte {
+	var key map[int]any
+	if a.edKey != nil {
+		key = map[int]any{1: 1, 3: int(webauthncose.AlgEdDSA), -1: 6, -2: []byte(a.edKey.Public().(ed25519.PublicKey))}
+	} else {
+		x := a.ecKey.PublicKey.X.FillBytes(make([]byte, 32))
+		y := a.ecKey.PublicKey.Y.FillBytes(make([]byte, 32))
+		key = map[int]any{1: 2, 3: int(webauthncose.AlgES256), -1: 1, -2: x, -3: y}
+	}
+	b, err := webauthncbor.Marshal(key)
+	require.NoError(t, err)
+	return b
+}
+
+func (a *testAuthenticator) authData(t *testing.T, rpID string, flags protocol.AuthenticatorFlags, attested bool) []byte {
+	hash := sha256.Sum256([]byte(rpID))
+	out := append([]byte{}, hash[:]...)
+	out = append(out, byte(flags))
+	out = binary.BigEndian.AppendUint32(out, a.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credentialID)))
+		out = append(out, a.credentialID...)
+		out = append(out, a.coseKey(t)...)
+	}
+	return out
+}
+
+func testClientData(typ, challenge, origin string) []byte {
+	b, _ := json.Marshal(map[string]any{"type": typ, "challenge": challenge, "origin": origin, "crossOrigin": false})
+	return b
+}
+
+func (a *testAuthenticator) register(t *testing.T, rpID, challenge, origin string, flags protocol.AuthenticatorFlags) *WebAuthnAttestationResponse {
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(t, rpID, flags|protocol.FlagAttestedCredentialData, true),
+	})
+	require.NoError(t, err)
+	return &WebAuthnAttestationResponse{
+		CredentialID:      encodeWebAuthnBase64(a.credentialID),
+		ClientDataJSON:    encodeWebAuthnBase64(testClientData("webauthn.create", challenge, origin)),
+		AttestationObject: encodeWebAuthnBase64(attestation),
+		Transports:        []string{"internal", "hybrid", "bogus"},
+	}
+}
+
+func (a *testAuthenticator) assert(t *testing.T, rpID, challenge, origin string, flags protocol.AuthenticatorFlags) *WebAuthnAssertionResponse {
+	a.signCount++
+	authData := a.authData(t, rpID, flags, false)
+	clientData := testClientData("webauthn.get", challenge, origin)
+	clientHash := sha256.Sum256(clientData)
+	signed := append(append([]byte{}, authData...), clientHash[:]...)
+
+	var sig []byte
+	if a.edKey != nil {
+		sig = ed25519.Sign(a.edKey, signed)
+	} else {
+		digest := sha256.Sum256(signed)
+		var err error
+		sig, err = ecdsa.SignASN1(rand.Reader, a.ecKey, digest[:])
+		require.NoError(t, err)
+	}
+	return &WebAuthnAssertionResponse{
+		CredentialID:      encodeWebAuthnBase64(a.credentialID),
+		ClientDataJSON:    encodeWebAuthnBase64(clientData),
+		AuthenticatorData: encodeWebAuthnBase64(authData),
+		Signature:         encodeWebAuthnBase64(sig),
+		UserHandle:        encodeWebAuthnBase64(webauthnUserHandle(7)),
+	}
+}
+
+// credential 直接构造已注册的凭据记录（跳过注册流程）
+func (a *testAuthenticator) credential(t *testing.T, id, userID int64) WebAuthnCredential {
+	return WebAuthnCredential{ID: id, UserID: userID, CredentialID: a.credentialID, PublicKey: a.coseKey(t)}
+}
+
+var testRP = WebAuthnRelyingParty{ID: "example.com", Name: "Example", Origins: []string{"https://example.com"}}
+
+const (
+	testFlagsUP = protocol.FlagUserPresent
+	testFlagsUV = protocol.FlagUserPresent | protocol.FlagUserVerified
+)
+
+// --- WebAuthnService ---
+
+type webauthnCredentialRepoStub struct {
+	credentials []WebAuthnCredential
+	updateErr   error
+}
+
+func (r *webauthnCredentialRepoStub) ListByUser(ctx context.Context, userID int64) ([]WebAuthnCredential, error) {
+	var out []WebAuthnCredential
+	for _, c := range r.credentials {
+		if c.UserID == userID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *webauthnCredentialRepoStub) CountByUser(ctx context.Context, userID int64) (int, error) {
+	list, _ := r.ListByUser(ctx, userID)
+	return len(list), nil
+}
+
+func (r *webauthnCredentialRepoStub) GetByCredentialID(ctx context.Context, credentialID []byte) (*WebAuthnCredential, error) {
+	for i := range r.credentials {
+		if string(r.credentials[i].CredentialID) == string(credentialID) {
+			c := r.credentials[i]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *webauthnCredentialRepoStub) Create(ctx context.Context, credential *WebAuthnCredential) error {
+	if existing, _ := r.GetByCredentialID(ctx, credential.CredentialID); existing != nil {
+		return ErrWebAuthnCredentialExists
+	}
+	credential.ID = int64(len(r.credentials) + 1)
+	r.credentials = append(r.credentials, *credential)
+	return nil
+}
+
+func (r *webauthnCredentialRepoStub) Rename(ctx context.Context, userID, id int64, name string) error {
+	panic("unexpected Rename call")
+}
+
+func (r *webauthnCredentialRepoStub) UpdateUsage(ctx context.Context, id int64, signCount int64, backupEligible, backupState bool) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	for i := range r.credentials {
+		if r.credentials[i].ID == id {
+			r.credentials[i].SignCount = signCount
+			r.credentials[i].BackupEligible = &backupEligible
+			r.credentials[i].BackupState = backupState
+		}
+	}
+	return nil
+}
+
+func (r *webauthnCredentialRepoStub) Delete(ctx context.Context, userID, id int64) error {
+	panic("unexpected Delete call")
+}
+
+type recoveryCodeRepoStub struct {
+	unused map[string]bool
+}
+
+func (r *recoveryCodeRepoStub) Replace(ctx context.Context, userID int64, hashes []string) error {
+	r.unused = map[string]bool{}
+	for _, h := range hashes {
+		r.unused[h] = true
+	}
+	return nil
+}
+
+func (r *recoveryCodeRepoStub) Consume(ctx context.Context, userID int64, hash string) (bool, error) {
+	if !r.unused[hash] {
+		return false, nil
+	}
+	delete(r.unused, hash)
+	return true, nil
+}
+
+func (r *recoveryCodeRepoStub) CountUnused(ctx context.Context, userID int64) (int, error) {
+	return len(r.unused), nil
+}
+
+type webauthnCacheStub struct {
+	sessions map[string]*WebAuthnChallengeSession
+}
+
+func (c *webauthnCacheStub) SetChallenge(ctx context.Context, key string, session *WebAuthnChallengeSession, ttl time.Duration) error {
+	c.sessions[key] = session
+	return nil
+}
+
+func (c *webauthnCacheStub) ConsumeChallenge(ctx context.Context, key string) (*WebAuthnChallengeSession, error) {
+	session := c.sessions[key]
+	delete(c.sessions, key)
+	return session, nil
+}
+
+func newTestWebAuthnService(t *testing.T, settings map[string]string) (*WebAuthnService, *webauthnCredentialRepoStub, *recoveryCodeRepoStub) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	credentials := &webauthnCredentialRepoStub{}
+	recovery := &recoveryCodeRepoStub{}
+	svc := NewWebAuthnService(
+		nil,
+		credentials,
+		recovery,
+		&webauthnCacheStub{sessions: map[string]*WebAuthnChallengeSession{}},
+		&userRepoStub{user: &User{ID: 7, Email: "alice@example.com", PasswordHash: string(hash), Status: StatusActive}},
+		NewSettingService(&settingRepoStub{values: settings}, nil),
+		nil,
+	)
+	return svc, credentials, recovery
+}
+
+func TestWebAuthnService_RegisterAndSecondFactor(t *testing.T) {
+	ctx := context.Background()
+	svc, credentials, _ := newTestWebAuthnService(t, nil)
+	a := newTestAuthenticator(t, false)
+
+	_, err := svc.BeginRegistration(ctx, 7, testRP, "", "wrong")
+	require.ErrorIs(t, err, ErrPasswordIncorrect)
+
+	begin, err := svc.BeginRegistration(ctx, 7, testRP, "", "secret")
+	require.NoError(t, err)
+	require.Equal(t, protocol.URLEncodedBase64(webauthnUserHandle(7)), begin.Options.User.ID)
+	challenge := begin.Options.Challenge.String()
+
+	cred, err := svc.FinishRegistration(ctx, 7, begin.SessionToken, "  ", a.register(t, "example.com", challenge, "https://example.com", testFlagsUV|protocol.FlagBackupEligible))
+	require.NoError(t, err)
+	require.Equal(t, webauthnDefaultName, cred.Name)
+	require.Equal(t, []string{"internal", "hybrid"}, cred.Transports)
+	require.NotNil(t, cred.BackupEligible)
+	require.True(t, *cred.BackupEligible)
+
+	// challenge 一次性
+	_, err = svc.FinishRegistration(ctx, 7, begin.SessionToken, "", a.register(t, "example.com", challenge, "https://example.com", testFlagsUV))
+	require.ErrorIs(t, err, ErrWebAuthnChallengeExpired)
+
+	// 已注册的凭据在再次注册时被排除
+	begin, err = svc.BeginRegistration(ctx, 7, testRP, "", "secret")
+	require.NoError(t, err)
+	require.Len(t, begin.Options.CredentialExcludeList, 1)
+
+	methods, err := svc.SecondFactorMethods(ctx, &User{ID: 7})
+	require.NoError(t, err)
+	require.Equal(t, []string{TwoFactorMethodWebAuthn}, methods)
+
+	// 二步验证不要求 UV
+	options, err := svc.BeginSecondFactor(ctx, "temp", 7, testRP)
+	require.NoError(t, err)
+	require.Len(t, options.AllowedCredentials, 1)
+	resp := a.assert(t, "example.com", options.Challenge.String(), "https://example.com", testFlagsUP|protocol.FlagBackupEligible)
+	require.NoError(t, svc.FinishSecondFactor(ctx, "temp", 7, resp))
+	require.Equal(t, int64(1), credentials.credentials[0].SignCount)
+
+	// 签名计数回退（疑似克隆）被拒绝
+	options, err = svc.BeginSecondFactor(ctx, "temp", 7, testRP)
+	require.NoError(t, err)
+	a.signCount = 0
+	resp = a.assert(t, "example.com", options.Challenge.String(), "https://example.com", testFlagsUP|protocol.FlagBackupEligible)
+	require.ErrorIs(t, svc.FinishSecondFactor(ctx, "temp", 7, resp), ErrWebAuthnVerificationFailed)
+	require.Equal(t, int64(1), credentials.credentials[0].SignCount)
+}
+
+func TestWebAuthnService_RegistrationRejects(t *testing.T) {
+	ctx := context.Background()
+	svc, credentials, _ := newTestWebAuthnService(t, nil)
+	a := newTestAuthenticator(t, false)
+
+	cases := map[string]func(challenge string) *WebAuthnAttestationResponse{
+		"challenge mismatch": func(string) *WebAuthnAttestationResponse {
+			return a.register(t, "example.com", "other", "https://example.com", testFlagsUV)
+		},
+		"origin mismatch": func(challenge string) *WebAuthnAttestationResponse {
+			return a.register(t, "example.com", challenge, "https://evil.com", testFlagsUV)
+		},
+		"rp id mismatch": func(challenge string) *WebAuthnAttestationResponse {
+			return a.register(t, "evil.com", challenge, "https://example.com", testFlagsUV)
+		},
+		"user not present": func(challenge string) *WebAuthnAttestationResponse {
+			return a.register(t, "example.com", challenge, "https://example.com", 0)
+		},
+		"malformed attestation": func(challenge string) *WebAuthnAttestationResponse {
+			resp := a.register(t, "example.com", challenge, "https://example.com", testFlagsUV)
+			resp.AttestationObject = encodeWebAuthnBase64([]byte{0xbf})
+			return resp
+		},
+	}
+	for name, build := range cases {
+		begin, err := svc.BeginRegistration(ctx, 7, testRP, "", "secret")
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(ctx, 7, begin.SessionToken, "", build(begin.Options.Challenge.String()))
+		require.ErrorIs(t, err, ErrWebAuthnVerificationFailed, name)
+	}
+	require.Empty(t, credentials.credentials)
+}
+
+func TestWebAuthnService_AssertionRejects(t *testing.T) {
+	ctx := context.Background()
+	svc, credentials, _ := newTestWebAuthnService(t, nil)
+	a := newTestAuthenticator(t, false)
+	credentials.credentials = append(credentials.credentials, a.credential(t, 1, 7))
+
+	finish := func(build func(challenge string) *WebAuthnAssertionResponse) error {
+		options, err := svc.BeginSecondFactor(ctx, "temp", 7, testRP)
+		require.NoError(t, err)
+		return svc.FinishSecondFactor(ctx, "temp", 7, build(options.Challenge.String()))
+	}
+
+	// 签名不匹配
+	err := finish(func(challenge string) *WebAuthnAssertionResponse {
+		resp := a.assert(t, "example.com", challenge, "https://example.com", testFlagsUP)
+		resp.Signature = a.assert(t, "example.com", "other", "https://example.com", testFlagsUP).Signature
+		return resp
+	})
+	require.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+
+	// 其他认证器的签名
+	b := newTestAuthenticator(t, false)
+	b.credentialID = a.credentialID
+	err = finish(func(challenge string) *WebAuthnAssertionResponse {
+		return b.assert(t, "example.com", challenge, "https://example.com", testFlagsUP)
+	})
+	require.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+
+	// 凭据属于其他用户
+	credentials.credentials[0].UserID = 8
+	credentials.credentials = append(credentials.credentials, newTestAuthenticator(t, true).credential(t, 2, 7))
+	err = finish(func(challenge string) *WebAuthnAssertionResponse {
+		return a.assert(t, "example.com", challenge, "https://example.com", testFlagsUP)
+	})
+	require.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+	require.Zero(t, credentials.credentials[0].SignCount)
+}
+
+func TestWebAuthnService_AssertionUpdatesUsage(t *testing.T) {
+	ctx := context.Background()
+	svc, credentials, _ := newTestWebAuthnService(t, nil)
+	a := newTestAuthenticator(t, false)
+	// 早期注册的凭据未记录备份标志：以首次断言为准
+	credentials.credentials = append(credentials.credentials, a.credential(t, 1, 7))
+
+	options, err := svc.BeginSecondFactor(ctx, "temp", 7, testRP)
+	require.NoError(t, err)
+	flags := testFlagsUP | protocol.FlagBackupEligible | protocol.FlagBackupState
+	require.NoError(t, svc.FinishSecondFactor(ctx, "temp", 7, a.assert(t, "example.com", options.Challenge.String(), "https://example.com", flags)))
+	require.NotNil(t, credentials.credentials[0].BackupEligible)
+	require.True(t, *credentials.credentials[0].BackupEligible)
+	require.True(t, credentials.credentials[0].BackupState)
+
+	// 记录之后备份资格不可改变
+	options, err = svc.BeginSecondFactor(ctx, "temp", 7, testRP)
+	require.NoError(t, err)
+	err = svc.FinishSecondFactor(ctx, "temp", 7, a.assert(t, "example.com", options.Challenge.String(), "https://example.com", testFlagsUP))
+	require.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+
+	// 签名计数保存失败时验证不能通过
+	credentials.updateErr = errors.New("db down")
+	options, err = svc.BeginSecondFactor(ctx, "temp", 7, testRP)
+	require.NoError(t, err)
+	err = svc.FinishSecondFactor(ctx, "temp", 7, a.assert(t, "example.com", options.Challenge.String(), "https://example.com", flags))
+	require.ErrorIs(t, err, credentials.updateErr)
+}
+
+func TestWebAuthnService_PasswordlessLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, credentials, _ := newTestWebAuthnService(t, nil)
+	a := newTestAuthenticator(t, true)
+	credentials.credentials = append(credentials.credentials, a.credential(t, 1, 7))
+
+	_, err := svc.BeginPasswordlessLogin(ctx, testRP)
+	require.Equal(t, "PASSKEY_LOGIN_DISABLED", infraerrors.Reason(err))
+
+	svc, _, _ = newTestWebAuthnService(t, map[string]string{SettingKeyPasskeyLoginEnabled: "true"})
+	svc.credentialRepo = credentials
+	begin, err := svc.BeginPasswordlessLogin(ctx, testRP)
+	require.NoError(t, err)
+	require.Equal(t, protocol.VerificationRequired, begin.Options.UserVerification)
+	require.Empty(t, begin.Options.AllowedCredentials)
+
+	user, err := svc.FinishPasswordlessLogin(ctx, begin.SessionToken, a.assert(t, "example.com", begin.Options.Challenge.String(), "https://example.com", testFlagsUV))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), user.ID)
+
+	// 无密码登录要求 UV
+	begin, err = svc.BeginPasswordlessLogin(ctx, testRP)
+	require.NoError(t, err)
+	_, err = svc.FinishPasswordlessLogin(ctx, begin.SessionToken, a.assert(t, "example.com", begin.Options.Challenge.String(), "https://example.com", testFlagsUP))
+	require.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+
+	// 用户句柄与凭据所属用户不一致
+	begin, err = svc.BeginPasswordlessLogin(ctx, testRP)
+	require.NoError(t, err)
+	resp := a.assert(t, "example.com", begin.Options.Challenge.String(), "https://example.com", testFlagsUV)
+	resp.UserHandle = encodeWebAuthnBase64(webauthnUserHandle(8))
+	_, err = svc.FinishPasswordlessLogin(ctx, begin.SessionToken, resp)
+	require.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+}
+
+func TestWebAuthnService_RecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	svc, credentials, recovery := newTestWebAuthnService(t, nil)
+
+	// 未启用任何二步验证方式时不能生成
+	_, err := svc.GenerateRecoveryCodes(ctx, 7, "", "secret")
+	require.ErrorIs(t, err, ErrRecoveryCodesUnavailable)
+
+	credentials.credentials = append(credentials.credentials, WebAuthnCredential{ID: 1, UserID: 7, CredentialID: []byte("c")})
+	codes, err := svc.GenerateRecoveryCodes(ctx, 7, "", "secret")
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, recovery.unused, recoveryCodeCount)
+	require.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, codes[0])
+
+	methods, err := svc.SecondFactorMethods(ctx, &User{ID: 7})
+	require.NoError(t, err)
+	require.Equal(t, []string{TwoFactorMethodWebAuthn, TwoFactorMethodRecoveryCode}, methods)
+
+	// 忽略大小写与分隔符，且只能使用一次
+	require.NoError(t, svc.ConsumeRecoveryCode(ctx, 7, " "+codes[0][:5]+codes[0][6:]+" "))
+	require.ErrorIs(t, svc.ConsumeRecoveryCode(ctx, 7, codes[0]), ErrRecoveryCodeInvalid)
+	require.ErrorIs(t, svc.ConsumeRecoveryCode(ctx, 7, "nope"), ErrRecoveryCodeInvalid)
+	remaining, err := svc.CountRecoveryCodes(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, recoveryCodeCount-1, remaining)
+}
+
+func TestWebAuthnService_ResolveRelyingParty(t *testing.T) {
+	svc := &WebAuthnService{}
+	rp := svc.ResolveRelyingParty(context.Background(), "app.example.com:8443", "https://app.example.com:8443", true)
+	require.Equal(t, "app.example.com", rp.ID)
+	require.Equal(t, []string{"https://app.example.com:8443"}, rp.Origins)
+
+	// 开发服务器 / 反向代理：同域不同端口的 Origin 被接受
+	rp = svc.ResolveRelyingParty(context.Background(), "127.0.0.1:8080", "http://localhost:3000", false)
+	require.Equal(t, "127.0.0.1", rp.ID)
+	require.Equal(t, []string{"http://127.0.0.1:8080"}, rp.Origins)
+	rp = svc.ResolveRelyingParty(context.Background(), "localhost:8080", "http://localhost:3000", false)
+	require.Equal(t, []string{"http://localhost:8080", "http://localhost:3000"}, rp.Origins)
+
+	// 与 RP ID 不同域的 Origin 不被接受
+	rp = svc.ResolveRelyingParty(context.Background(), "example.com", "https://evil.com", false)
+	require.Equal(t, []string{"http://example.com"}, rp.Origins)
+}
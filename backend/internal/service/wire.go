@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"time"
 
+	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/google/wire"
 	"github.com/redis/go-redis/v9"
@@ -129,11 +130,13 @@ func ProvideRateLimitService(
 	timeoutCounterCache TimeoutCounterCache,
 	settingService *SettingService,
 	tokenCacheInvalidator TokenCacheInvalidator,
+	budgetCache RateLimitBudgetCache,
 ) *RateLimitService {
 	svc := NewRateLimitService(accountRepo, usageRepo, cfg, geminiQuotaService, tempUnschedCache)
 	svc.SetTimeoutCounterCache(timeoutCounterCache)
 	svc.SetSettingService(settingService)
 	svc.SetTokenCacheInvalidator(tokenCacheInvalidator)
+	svc.SetRateLimitBudgetCache(budgetCache)
 	return svc
 }
 
@@ -203,6 +206,119 @@ func ProvideOpsScheduledReportService(
 	return svc
 }
 
+// ProvideAccountProbeService creates and starts AccountProbeService.
+func ProvideAccountProbeService(
+	accountRepo AccountRepository,
+	probeRepo AccountProbeRepository,
+	settingRepo SettingRepository,
+	accountTestService *AccountTestService,
+	redisClient *redis.Client,
+) *AccountProbeService {
+	svc := NewAccountProbeService(accountRepo, probeRepo, settingRepo, accountTestService, redisClient)
+	svc.Start()
+	return svc
+}
+
+// ProvideAPIKeyAbuseService creates and starts APIKeyAbuseService and registers it as the
+// API key auth rate limiter for keys under abuse rate limiting.
+func ProvideAPIKeyAbuseService(
+	abuseRepo APIKeyAbuseRepository,
+	apiKeyRepo APIKeyRepository,
+	userRepo UserRepository,
+	settingRepo SettingRepository,
+	apiKeyService *APIKeyService,
+	emailService *EmailService,
+	redisClient *redis.Client,
+) *APIKeyAbuseService {
+	svc := NewAPIKeyAbuseService(abuseRepo, apiKeyRepo, userRepo, settingRepo, apiKeyService, emailService, redisClient)
+	apiKeyService.SetAbuseLimiter(svc)
+	svc.Start()
+	return svc
+}
+
+// ProvideSpendGuardService creates and starts SpendGuardService
+func ProvideSpendGuardService(
+	guardRepo SpendGuardRepository,
+	apiKeyRepo APIKeyRepository,
+	userRepo UserRepository,
+	settingRepo SettingRepository,
+	opsRepo OpsRepository,
+	apiKeyService *APIKeyService,
+	emailService *EmailService,
+	redisClient *redis.Client,
+) *SpendGuardService {
+	svc := NewSpendGuardService(guardRepo, apiKeyRepo, userRepo, settingRepo, opsRepo, apiKeyService, emailService, redisClient)
+	svc.Start()
+	return svc
+}
+
+// ProvideReferralService creates and starts ReferralService
+func ProvideReferralService(
+	referralRepo ReferralRepository,
+	userRepo UserRepository,
+	settingRepo SettingRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	redisClient *redis.Client,
+) *ReferralService {
+	svc := NewReferralService(referralRepo, userRepo, settingRepo, billingCacheService, authCacheInvalidator, redisClient)
+	svc.Start()
+	return svc
+}
+
+// ProvideUserDataService creates and starts UserDataService
+func ProvideUserDataService(
+	repo UserDataRepository,
+	userRepo UserRepository,
+	attributeDefRepo UserAttributeDefinitionRepository,
+	attributeValueRepo UserAttributeValueRepository,
+	apiKeyRepo APIKeyRepository,
+	apiKeyService *APIKeyService,
+	userSubRepo UserSubscriptionRepository,
+	redeemRepo RedeemCodeRepository,
+	authService *AuthService,
+	emailService *EmailService,
+	settingService *SettingService,
+) *UserDataService {
+	svc := NewUserDataService(repo, userRepo, attributeDefRepo, attributeValueRepo, apiKeyRepo, apiKeyService, userSubRepo, redeemRepo, authService, emailService, settingService)
+	svc.Start()
+	return svc
+}
+
+// ProvideSubscriptionPlanService creates and starts SubscriptionPlanService
+func ProvideSubscriptionPlanService(
+	repo SubscriptionPlanRepository,
+	groupRepo GroupRepository,
+	userRepo UserRepository,
+	userSubRepo UserSubscriptionRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	emailService *EmailService,
+	settingService *SettingService,
+	redisClient *redis.Client,
+) *SubscriptionPlanService {
+	svc := NewSubscriptionPlanService(repo, groupRepo, userRepo, userSubRepo, billingCacheService, authCacheInvalidator, emailService, settingService, redisClient)
+	svc.Start()
+	return svc
+}
+
+// ProvidePaymentService creates PaymentService and starts the expired order cleanup
+func ProvidePaymentService(
+	repo PaymentRepository,
+	userRepo UserRepository,
+	redeemRepo RedeemCodeRepository,
+	promoService *PromoService,
+	settingRepo SettingRepository,
+	settingService *SettingService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	entClient *dbent.Client,
+) *PaymentService {
+	svc := NewPaymentService(repo, userRepo, redeemRepo, promoService, settingRepo, settingService, billingCacheService, authCacheInvalidator, entClient)
+	svc.Start()
+	return svc
+}
+
 // ProvideAPIKeyAuthCacheInvalidator 提供 API Key 认证缓存失效能力
 func ProvideAPIKeyAuthCacheInvalidator(apiKeyService *APIKeyService) APIKeyAuthCacheInvalidator {
 	// Start Pub/Sub subscriber for L1 cache invalidation across instances
@@ -247,6 +363,15 @@ var ProviderSet = wire.NewSet(
 	ProvideRateLimitService,
 	NewAccountUsageService,
 	NewAccountTestService,
+	ProvideAccountProbeService,
+	ProvideAPIKeyAbuseService,
+	ProvideSpendGuardService,
+	ProvideReferralService,
+	NewImpersonationService,
+	ProvideUserDataService,
+	ProvideSubscriptionPlanService,
+	ProvidePaymentService,
+	NewOIDCService,
 	NewSettingService,
 	NewOpsService,
 	ProvideOpsMetricsCollector,
@@ -257,6 +382,7 @@ var ProviderSet = wire.NewSet(
 	NewEmailService,
 	ProvideEmailQueueService,
 	NewTurnstileService,
+	NewLoginGuardService,
 	NewSubscriptionService,
 	ProvideConcurrencyService,
 	ProvideSchedulerSnapshotService,
@@ -274,6 +400,7 @@ var ProviderSet = wire.NewSet(
 	NewUserAttributeService,
 	NewUsageCache,
 	NewTotpService,
+	NewWebAuthnService,
 	NewErrorPassthroughService,
 	NewDigestSessionStore,
 )
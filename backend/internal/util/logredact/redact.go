@@ -48,6 +48,20 @@ func RedactJSON(raw []byte, extraKeys ...string) string {
 	return string(encoded)
 }
 
+// IsSensitiveKey 判断 key 是否属于默认敏感字段或 extraKeys（忽略大小写与首尾空白）。
+func IsSensitiveKey(key string, extraKeys ...string) bool {
+	normalized := normalizeKey(key)
+	if _, ok := defaultSensitiveKeys[normalized]; ok {
+		return true
+	}
+	for _, extra := range extraKeys {
+		if normalizeKey(extra) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
 func buildKeySet(extraKeys []string) map[string]struct{} {
 	keys := make(map[string]struct{}, len(defaultSensitiveKeys)+len(extraKeys))
 	for k := range defaultSensitiveKeys {
@@ -0,0 +1,22 @@
+-- 054_add_account_probe_results.sql
+-- 账号定时探测（合成健康检查）结果历史表
+
+CREATE TABLE IF NOT EXISTS account_probe_results (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL,
+    platform VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    first_token_ms BIGINT,
+    error_message TEXT,
+    -- source: scheduled | manual
+    source VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_probe_results_account_created_at
+    ON account_probe_results(account_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_account_probe_results_created_at
+    ON account_probe_results(created_at);
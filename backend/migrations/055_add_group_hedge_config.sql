@@ -0,0 +1,15 @@
+-- 055_add_group_hedge_config.sql
+-- 分组对冲请求配置：首账号在阈值内未返回响应头时，向第二个账号发起相同请求，先响应者胜出
+
+ALTER TABLE groups
+ADD COLUMN IF NOT EXISTS hedge_enabled BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE groups
+ADD COLUMN IF NOT EXISTS hedge_delay_ms INTEGER NOT NULL DEFAULT 2000;
+
+ALTER TABLE groups
+ADD COLUMN IF NOT EXISTS hedge_max_ratio DECIMAL(5,4) NOT NULL DEFAULT 0.1;
+
+COMMENT ON COLUMN groups.hedge_enabled IS '是否启用对冲请求';
+COMMENT ON COLUMN groups.hedge_delay_ms IS '触发对冲请求的响应头等待阈值（毫秒）';
+COMMENT ON COLUMN groups.hedge_max_ratio IS '允许触发对冲的请求占比上限(0~1)，用于限制额外上游开销';
@@ -0,0 +1,8 @@
+-- 056_add_group_schedule_rule.sql
+-- 分组调度时间窗口：未单独配置窗口的账号在该分组内按分组规则自动切换可调度状态
+-- 账号级规则与备用（standby）标记存储在 accounts.extra 中（schedule_rule / standby）
+
+ALTER TABLE groups
+ADD COLUMN IF NOT EXISTS schedule_rule JSONB DEFAULT NULL;
+
+COMMENT ON COLUMN groups.schedule_rule IS '分组调度时间窗口规则：{mode, timezone, windows:[{cron, duration_minutes}]}';
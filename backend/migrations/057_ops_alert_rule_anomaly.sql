@@ -0,0 +1,13 @@
+-- 057_ops_alert_rule_anomaly.sql
+-- 告警规则基线异常检测：operator 支持 anomaly_above / anomaly_below，
+-- 将当前窗口与 ops_metrics_hourly 中过去 N 天同一小时的基线比较（z-score 或百分比偏离）
+
+ALTER TABLE ops_alert_rules
+    ALTER COLUMN operator TYPE VARCHAR(16);
+
+ALTER TABLE ops_alert_rules
+    ADD COLUMN IF NOT EXISTS anomaly_method VARCHAR(16) DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS baseline_days INT DEFAULT NULL;
+
+COMMENT ON COLUMN ops_alert_rules.anomaly_method IS '异常检测方式：zscore（threshold 为标准差倍数）/ percent（threshold 为偏离百分比）';
+COMMENT ON COLUMN ops_alert_rules.baseline_days IS '基线天数：取过去 N 天同一小时（UTC）的 ops_metrics_hourly 数据';
@@ -0,0 +1,29 @@
+-- 058_ops_alert_ack_escalation.sql
+-- 告警确认、升级与时间线：
+-- - ops_alert_events 增加确认信息（确认人/负责人/备注）、升级级别与通知计数
+-- - ops_alert_event_timeline 记录事件的每次状态变化与通知发送
+-- 升级策略与重复通知配置存储在 ops 告警运行设置中（settings 表）
+
+ALTER TABLE ops_alert_events
+    ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS acknowledged_by BIGINT,
+    ADD COLUMN IF NOT EXISTS assignee VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS ack_note TEXT,
+    ADD COLUMN IF NOT EXISTS escalation_level INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_notified_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS notification_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS ops_alert_event_timeline (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor_user_id BIGINT,
+    message TEXT,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_alert_event_timeline_event
+    ON ops_alert_event_timeline (event_id, created_at, id);
+
+COMMENT ON TABLE ops_alert_event_timeline IS '告警事件时间线：fired / notified / repeat_notified / escalated / acknowledged / resolved / manual_resolved';
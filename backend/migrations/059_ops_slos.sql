@@ -0,0 +1,34 @@
+-- 059_ops_slos.sql
+-- SLO 定义与错误预算：
+-- - 可用性目标（成功请求占比，口径与 ops 仪表盘 SLA 一致：业务限流错误不计入）
+-- - 可选的首 Token 延迟目标（first_token_ms <= latency_target_ms 的请求占比 >= latency_target_percent）
+-- - 作用范围为全局 / 平台 / 分组，评估窗口为最近 window_days 天（滚动窗口）
+-- 错误预算与 burn rate 由 usage_logs / ops_error_logs 实时计算，不做持久化
+
+CREATE TABLE IF NOT EXISTS ops_slos (
+    id BIGSERIAL PRIMARY KEY,
+
+    name VARCHAR(128) NOT NULL,
+    description TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+
+    -- Optional scope (empty platform + NULL group_id = all traffic)
+    platform VARCHAR(32),
+    group_id BIGINT,
+
+    availability_target DOUBLE PRECISION NOT NULL,
+    latency_target_ms INT,
+    latency_target_percent DOUBLE PRECISION,
+
+    window_days INT NOT NULL DEFAULT 30,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_slos_group_id ON ops_slos (group_id);
+
+COMMENT ON COLUMN ops_slos.availability_target IS '可用性目标百分比，例如 99.9';
+COMMENT ON COLUMN ops_slos.latency_target_ms IS '首 Token 延迟阈值（毫秒），为空表示不设延迟目标';
+COMMENT ON COLUMN ops_slos.latency_target_percent IS '首 Token 延迟不超过阈值的请求占比目标，例如 95';
+COMMENT ON COLUMN ops_slos.window_days IS 'SLO 评估窗口（天），错误预算按该滚动窗口计算';
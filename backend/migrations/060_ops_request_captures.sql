@@ -0,0 +1,50 @@
+-- 060_ops_request_captures.sql
+-- 成功请求的采样抓取（用于排查"回答不对"、工具调用异常等问题）：
+-- - 抓取规则（按 API Key / 用户 / 全局采样率，均可设置过期时间）保存在 settings 表 ops_request_capture_settings 中
+-- - 请求体与重建后的响应体经过脱敏与截断后存储；每条记录带 expires_at，过期后由清理任务删除
+-- - 通过 request_id 与 usage_logs 关联，通过 client_request_id 与 ops_error_logs 关联
+
+CREATE TABLE IF NOT EXISTS ops_request_captures (
+    id BIGSERIAL PRIMARY KEY,
+
+    request_id VARCHAR(128),
+    client_request_id VARCHAR(64),
+
+    user_id BIGINT,
+    api_key_id BIGINT,
+    account_id BIGINT,
+    group_id BIGINT,
+
+    platform VARCHAR(32),
+    model VARCHAR(100),
+    request_path VARCHAR(256),
+    stream BOOLEAN NOT NULL DEFAULT false,
+    status_code INT,
+
+    -- api_key / user / sample
+    capture_reason VARCHAR(16) NOT NULL,
+
+    request_body TEXT,
+    request_body_bytes INT,
+    request_truncated BOOLEAN NOT NULL DEFAULT false,
+
+    response_body TEXT,
+    -- json / sse_reconstructed / sse_raw / text
+    response_format VARCHAR(32),
+    response_body_bytes INT,
+    response_truncated BOOLEAN NOT NULL DEFAULT false,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_request_captures_created_at ON ops_request_captures (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ops_request_captures_expires_at ON ops_request_captures (expires_at);
+CREATE INDEX IF NOT EXISTS idx_ops_request_captures_request_id ON ops_request_captures (request_id);
+CREATE INDEX IF NOT EXISTS idx_ops_request_captures_client_request_id ON ops_request_captures (client_request_id);
+CREATE INDEX IF NOT EXISTS idx_ops_request_captures_user_created ON ops_request_captures (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ops_request_captures_api_key_created ON ops_request_captures (api_key_id, created_at DESC);
+
+COMMENT ON COLUMN ops_request_captures.capture_reason IS '命中的抓取规则：api_key / user / sample';
+COMMENT ON COLUMN ops_request_captures.response_format IS '响应体格式：json / sse_reconstructed（由 SSE 事件重建）/ sse_raw / text';
+COMMENT ON COLUMN ops_request_captures.expires_at IS '过期时间，过期记录由 ops 清理任务删除';
@@ -0,0 +1,60 @@
+-- 061_ops_shadow_traffic.sql
+-- 影子流量（traffic shadowing）：
+-- - 规则绑定分组：按 sample_rate 采样该分组的成功请求，异步复制到指定的候选账号（shadow_account_id）
+-- - 影子请求的响应不返回给客户端、不计费，仅记录状态码 / 延迟 / token 数，用于与主请求对比
+-- - 候选账号可以不在分组内、也可以设为不可调度，避免被正常流量选中
+
+CREATE TABLE IF NOT EXISTS ops_shadow_rules (
+    id BIGSERIAL PRIMARY KEY,
+
+    name VARCHAR(128) NOT NULL,
+    description TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+
+    group_id BIGINT NOT NULL,
+    shadow_account_id BIGINT NOT NULL,
+    sample_rate DOUBLE PRECISION NOT NULL,
+    -- 过期后规则不再采样（NULL = 不过期）
+    expires_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_shadow_rules_group_id ON ops_shadow_rules (group_id);
+
+CREATE TABLE IF NOT EXISTS ops_shadow_results (
+    id BIGSERIAL PRIMARY KEY,
+
+    rule_id BIGINT NOT NULL,
+    group_id BIGINT,
+    request_id VARCHAR(128),
+
+    platform VARCHAR(32),
+    model VARCHAR(100),
+    request_path VARCHAR(256),
+    stream BOOLEAN NOT NULL DEFAULT false,
+
+    primary_account_id BIGINT,
+    primary_status_code INT,
+    primary_duration_ms BIGINT,
+    primary_first_token_ms BIGINT,
+    primary_input_tokens INT,
+    primary_output_tokens INT,
+
+    shadow_account_id BIGINT NOT NULL,
+    shadow_status_code INT,
+    shadow_duration_ms BIGINT,
+    shadow_first_token_ms BIGINT,
+    shadow_input_tokens INT,
+    shadow_output_tokens INT,
+    shadow_error TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_shadow_results_rule_created ON ops_shadow_results (rule_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ops_shadow_results_created_at ON ops_shadow_results (created_at DESC);
+
+COMMENT ON TABLE ops_shadow_results IS '影子请求与主请求的对比记录（影子响应不返回客户端、不计费）';
+COMMENT ON COLUMN ops_shadow_results.shadow_error IS '影子请求失败原因（上游错误摘要或本地错误）';
@@ -0,0 +1,52 @@
+-- 062_ops_status_page.sql
+-- 公开状态页（无需登录）：
+-- - 可用性 / 中位延迟按平台、模型从 usage_logs + ops_error_logs 实时聚合（服务端缓存）
+-- - 当前事件来自标记为公开的 firing 告警事件（规则 public_status 或事件 is_public），以及管理员发布的事件公告
+
+ALTER TABLE ops_alert_rules
+    ADD COLUMN IF NOT EXISTS public_status BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE ops_alert_events
+    ADD COLUMN IF NOT EXISTS is_public BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS public_summary TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_ops_alert_events_public_firing
+    ON ops_alert_events (fired_at DESC)
+    WHERE is_public = true;
+
+COMMENT ON COLUMN ops_alert_rules.public_status IS '该规则触发的事件自动展示在公开状态页';
+COMMENT ON COLUMN ops_alert_events.public_summary IS '公开状态页展示的摘要（为空时使用告警标题）';
+
+CREATE TABLE IF NOT EXISTS ops_status_incidents (
+    id BIGSERIAL PRIMARY KEY,
+
+    title VARCHAR(200) NOT NULL,
+    -- investigating / identified / monitoring / resolved
+    status VARCHAR(20) NOT NULL DEFAULT 'investigating',
+    -- minor / major / critical
+    impact VARCHAR(20) NOT NULL DEFAULT 'minor',
+    -- 受影响平台（JSON 字符串数组，空 = 全部）
+    platforms JSONB,
+
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+
+    created_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_status_incidents_started_at ON ops_status_incidents (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS ops_status_incident_updates (
+    id BIGSERIAL PRIMARY KEY,
+
+    incident_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    message TEXT NOT NULL,
+
+    created_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_status_incident_updates_incident ON ops_status_incident_updates (incident_id, created_at DESC);
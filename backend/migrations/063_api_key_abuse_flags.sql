@@ -0,0 +1,56 @@
+-- 063_api_key_abuse_flags.sql
+-- API Key 共享 / 滥用检测：
+-- - 后台任务按时间窗口从 usage_logs 聚合每个 Key 的来源 IP、网段、并发 IP、User-Agent 与活跃时段，计算可疑分
+-- - 超过阈值的 Key 记录为待处理标记（每个 Key 最多一条 open 标记），可选自动限流或停用并通知所有者
+
+CREATE TABLE IF NOT EXISTS api_key_abuse_flags (
+    id BIGSERIAL PRIMARY KEY,
+
+    api_key_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+
+    -- 0-100
+    score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    window_hours INT NOT NULL DEFAULT 24,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    distinct_ips INT NOT NULL DEFAULT 0,
+    -- 不同网段数（IPv4 /16，IPv6 前 48 位），用于近似地理分布
+    distinct_networks INT NOT NULL DEFAULT 0,
+    -- 任意 5 分钟内同时出现的最大 IP 数（并发会话近似）
+    peak_concurrent_ips INT NOT NULL DEFAULT 0,
+    distinct_user_agents INT NOT NULL DEFAULT 0,
+    -- 窗口内有请求的小时数
+    active_hours INT NOT NULL DEFAULT 0,
+
+    -- open / dismissed / resolved
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    -- none / rate_limited / suspended
+    action VARCHAR(20) NOT NULL DEFAULT 'none',
+    rate_limit_rpm INT,
+    auto_actioned BOOLEAN NOT NULL DEFAULT false,
+    owner_notified_at TIMESTAMPTZ,
+
+    first_detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    reviewed_by BIGINT,
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_abuse_flags_open_key
+    ON api_key_abuse_flags (api_key_id)
+    WHERE status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_api_key_abuse_flags_status_score
+    ON api_key_abuse_flags (status, score DESC);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_abuse_flags_user
+    ON api_key_abuse_flags (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_abuse_flags_reviewed
+    ON api_key_abuse_flags (api_key_id, reviewed_at DESC)
+    WHERE status = 'dismissed';
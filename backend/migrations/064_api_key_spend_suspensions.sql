@@ -0,0 +1,50 @@
+-- 064_api_key_spend_suspensions.sql
+-- 消费速率异常自动停用：
+-- - 后台任务每分钟统计最近 10 分钟每个 Key / 用户的消费，与其自身历史峰值（10 分钟桶）比较
+-- - 超过历史峰值的配置倍数或绝对金额时临时停用相关 Key，邮件通知用户并生成运维告警事件供管理员审核
+
+CREATE TABLE IF NOT EXISTS api_key_spend_suspensions (
+    id BIGSERIAL PRIMARY KEY,
+
+    api_key_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+
+    -- api_key：Key 自身消费异常；user：用户整体消费异常（停用窗口内有消费的所有 Key）
+    scope VARCHAR(20) NOT NULL,
+    -- multiplier：超过历史峰值倍数；absolute：超过绝对金额
+    rule VARCHAR(20) NOT NULL,
+
+    -- 触发时最近 10 分钟消费 / 历史 10 分钟峰值 / 触发阈值（USD）
+    window_spend DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    baseline_spend DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    threshold_spend DECIMAL(20, 8) NOT NULL DEFAULT 0,
+
+    -- active / expired / lifted
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    -- NULL 表示需管理员手动解除
+    suspended_until TIMESTAMPTZ,
+
+    alert_event_id BIGINT,
+    user_notified_at TIMESTAMPTZ,
+
+    lifted_by BIGINT,
+    lifted_at TIMESTAMPTZ,
+    lift_note TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_spend_suspensions_active_key
+    ON api_key_spend_suspensions (api_key_id)
+    WHERE status = 'active';
+
+CREATE INDEX IF NOT EXISTS idx_api_key_spend_suspensions_status_created
+    ON api_key_spend_suspensions (status, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_spend_suspensions_user
+    ON api_key_spend_suspensions (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_spend_suspensions_until
+    ON api_key_spend_suspensions (suspended_until)
+    WHERE status = 'active' AND suspended_until IS NOT NULL;
@@ -0,0 +1,26 @@
+-- 065_user_external_identities.sql
+-- 第三方 OIDC / OAuth2 登录身份绑定：
+-- - (provider, subject) 唯一标识一个外部身份，绑定到一个本地用户
+-- - provider 为管理员配置的 OIDC 提供方标识（slug）
+
+CREATE TABLE IF NOT EXISTS user_external_identities (
+    id BIGSERIAL PRIMARY KEY,
+
+    user_id BIGINT NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+
+    -- 最近一次登录时提供方返回的邮箱 / 用户名（仅用于展示与排查）
+    email VARCHAR(255),
+    username VARCHAR(255),
+
+    last_login_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_external_identities_provider_subject
+    ON user_external_identities (provider, subject);
+
+CREATE INDEX IF NOT EXISTS idx_user_external_identities_user
+    ON user_external_identities (user_id);
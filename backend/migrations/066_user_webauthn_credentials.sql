@@ -0,0 +1,39 @@
+-- 066_user_webauthn_credentials.sql
+-- 通行密钥（WebAuthn）与二步验证恢复码：
+-- - 每个用户可注册多个认证器，credential_id 全局唯一
+-- - public_key 保存 COSE 编码的公钥，sign_count 用于检测克隆认证器
+-- - 恢复码仅保存 SHA-256 哈希，可替代 TOTP / 通行密钥完成二步验证，一次性使用
+
+CREATE TABLE IF NOT EXISTS user_webauthn_credentials (
+    id BIGSERIAL PRIMARY KEY,
+
+    user_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    credential_id BYTEA NOT NULL,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid BYTEA,
+    -- 认证器支持的传输方式（usb / nfc / ble / internal / hybrid），逗号分隔
+    transports VARCHAR(128) NOT NULL DEFAULT '',
+
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_webauthn_credentials_credential_id
+    ON user_webauthn_credentials (credential_id);
+
+CREATE INDEX IF NOT EXISTS idx_user_webauthn_credentials_user
+    ON user_webauthn_credentials (user_id);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+
+    user_id BIGINT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_recovery_codes_user_hash
+    ON user_recovery_codes (user_id, code_hash);
@@ -0,0 +1,76 @@
+-- 067_user_referrals.sql
+-- 邀请返利：
+-- - 每个用户拥有一个邀请码（首次查看邀请页时生成），通过 /register?ref=CODE 注册的新用户与邀请人绑定
+-- - 邀请人可获得注册奖励，以及被邀请人付费消费（usage_logs 余额扣费部分）的返佣比例
+-- - 后台任务定期按 settled_until 游标结算，奖励直接计入邀请人余额并写入 referral_rewards 流水
+-- - 同 IP / 同邮箱域名的邀请会被标记，可配置为待管理员审核后再发放奖励
+
+CREATE TABLE IF NOT EXISTS user_referral_codes (
+    user_id BIGINT PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_referral_codes_code
+    ON user_referral_codes (code);
+
+CREATE TABLE IF NOT EXISTS user_referrals (
+    id BIGSERIAL PRIMARY KEY,
+
+    referrer_user_id BIGINT NOT NULL,
+    referee_user_id BIGINT NOT NULL,
+    code VARCHAR(32) NOT NULL,
+
+    -- active：正常结算；held：命中风控标记，待管理员审核；blocked：管理员拒绝，不再发放奖励
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    -- 风控标记：same_ip / shared_signup_ip / same_email_domain
+    flags TEXT[] NOT NULL DEFAULT '{}',
+    signup_ip VARCHAR(45),
+    email_domain VARCHAR(255),
+
+    signup_bonus_paid BOOLEAN NOT NULL DEFAULT FALSE,
+    -- 已结算的被邀请人付费消费 / 该被邀请人累计带来的奖励（USD）
+    referee_spend DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    reward_total DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    -- 结算游标：该时间点之前的消费已结算
+    settled_until TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    reviewed_by BIGINT,
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 每个用户只能被邀请一次
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_referrals_referee
+    ON user_referrals (referee_user_id);
+
+CREATE INDEX IF NOT EXISTS idx_user_referrals_referrer
+    ON user_referrals (referrer_user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_user_referrals_status
+    ON user_referrals (status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS referral_rewards (
+    id BIGSERIAL PRIMARY KEY,
+
+    referral_id BIGINT NOT NULL,
+    referrer_user_id BIGINT NOT NULL,
+    referee_user_id BIGINT NOT NULL,
+
+    -- signup_bonus / commission
+    kind VARCHAR(20) NOT NULL,
+    amount DECIMAL(20, 8) NOT NULL,
+    -- commission：本次结算的被邀请人付费消费
+    base_amount DECIMAL(20, 8) NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_referrer
+    ON referral_rewards (referrer_user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_referral
+    ON referral_rewards (referral_id);
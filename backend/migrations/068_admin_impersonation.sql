@@ -0,0 +1,50 @@
+-- 068_admin_impersonation.sql
+-- 管理员"以用户身份查看"：
+-- - 管理员为目标用户签发短期、带 imp 标记的 JWT（无 Refresh Token，到期自动失效）
+-- - impersonation_sessions 记录每次模拟会话（发起管理员、目标用户、原因、到期/结束时间）
+-- - impersonation_actions 记录模拟期间的每一次请求，并保留真实管理员 ID
+
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id BIGSERIAL PRIMARY KEY,
+
+    admin_user_id BIGINT NOT NULL,
+    target_user_id BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+
+    expires_at TIMESTAMPTZ NOT NULL,
+    -- 主动结束（管理员退出 / 后台强制结束）的时间；NULL 表示未主动结束
+    ended_at TIMESTAMPTZ,
+    ended_by BIGINT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_admin
+    ON impersonation_sessions (admin_user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_target
+    ON impersonation_sessions (target_user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS impersonation_actions (
+    id BIGSERIAL PRIMARY KEY,
+
+    session_id BIGINT NOT NULL,
+    admin_user_id BIGINT NOT NULL,
+    target_user_id BIGINT NOT NULL,
+
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    ip_address VARCHAR(45),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_actions_session
+    ON impersonation_actions (session_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_actions_admin
+    ON impersonation_actions (admin_user_id, created_at DESC);
@@ -0,0 +1,74 @@
+-- 069_user_data_export_and_deletion.sql
+-- 用户自助数据导出与账号注销：
+-- - user_data_exports 记录导出任务，由后台任务生成 zip 归档并存入 archive，过期（默认 7 天）后整行删除
+-- - account_deletion_requests 记录注销申请：邮件确认 → 停用账号并进入宽限期 → 到期后匿名化删除
+-- - 宽限期内管理员可恢复账号；previous_status / revoked_api_key_ids 用于还原
+
+CREATE TABLE IF NOT EXISTS user_data_exports (
+    id BIGSERIAL PRIMARY KEY,
+
+    user_id BIGINT NOT NULL,
+    -- pending / processing / completed / failed
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+
+    archive BYTEA,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error_message TEXT,
+
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_data_exports_user
+    ON user_data_exports (user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_user_data_exports_status
+    ON user_data_exports (status, created_at)
+    WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS account_deletion_requests (
+    id BIGSERIAL PRIMARY KEY,
+
+    user_id BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    -- pending_confirmation / scheduled / cancelled / restored / completed
+    status VARCHAR(32) NOT NULL DEFAULT 'pending_confirmation',
+    reason TEXT NOT NULL DEFAULT '',
+
+    -- 确认链接 token 的 SHA-256
+    token_hash VARCHAR(64) NOT NULL,
+    token_expires_at TIMESTAMPTZ NOT NULL,
+
+    previous_status VARCHAR(20) NOT NULL DEFAULT '',
+    revoked_api_key_ids BIGINT[] NOT NULL DEFAULT '{}',
+
+    ip_address VARCHAR(45),
+
+    confirmed_at TIMESTAMPTZ,
+    scheduled_for TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    restored_at TIMESTAMPTZ,
+    restored_by BIGINT,
+    completed_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 每个用户同时最多一个未结束的注销申请
+CREATE UNIQUE INDEX IF NOT EXISTS uq_account_deletion_requests_open_user
+    ON account_deletion_requests (user_id)
+    WHERE status IN ('pending_confirmation', 'scheduled');
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_account_deletion_requests_token
+    ON account_deletion_requests (token_hash);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_due
+    ON account_deletion_requests (scheduled_for)
+    WHERE status = 'scheduled';
+
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_created
+    ON account_deletion_requests (created_at DESC);
@@ -0,0 +1,46 @@
+-- 070_promo_code_rules.sql
+-- 优惠码扩展：
+-- - bonus_type=percent：兑换后在下次余额充值时按比例额外赠送（可设置单次上限）
+-- - 适用条件：仅新用户（注册时或注册后 N 天内）、持有指定订阅分组、生效时间
+-- - 每用户使用次数限制（取代原 promo_code_id + user_id 唯一约束）
+-- - scope 控制注册时 / 用户面板 / 均可使用
+
+ALTER TABLE promo_codes
+    ADD COLUMN IF NOT EXISTS bonus_type VARCHAR(20) NOT NULL DEFAULT 'fixed',
+    ADD COLUMN IF NOT EXISTS bonus_percent DECIMAL(10,4) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_bonus_amount DECIMAL(20,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS per_user_limit INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS scope VARCHAR(20) NOT NULL DEFAULT 'register',
+    ADD COLUMN IF NOT EXISTS new_users_only BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS new_user_days INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS required_group_id BIGINT,
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN promo_codes.bonus_type IS '赠送方式: fixed（立即赠送 bonus_amount）, percent（下次充值赠送 bonus_percent%）';
+COMMENT ON COLUMN promo_codes.per_user_limit IS '每个用户最多使用次数，0表示无限制';
+COMMENT ON COLUMN promo_codes.scope IS '使用场景: register, panel, any';
+
+ALTER TABLE promo_code_usages
+    ADD COLUMN IF NOT EXISTS bonus_type VARCHAR(20) NOT NULL DEFAULT 'fixed',
+    ADD COLUMN IF NOT EXISTS bonus_percent DECIMAL(10,4) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_bonus_amount DECIMAL(20,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'applied',
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'register',
+    ADD COLUMN IF NOT EXISTS base_amount DECIMAL(20,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS redeem_code_id BIGINT,
+    ADD COLUMN IF NOT EXISTS applied_at TIMESTAMPTZ;
+
+-- 历史记录均为注册时立即发放
+UPDATE promo_code_usages SET applied_at = used_at WHERE applied_at IS NULL AND status = 'applied';
+
+-- 允许同一用户多次使用同一优惠码（次数由 per_user_limit 控制）
+ALTER TABLE promo_code_usages DROP CONSTRAINT IF EXISTS promo_code_usages_promo_code_id_user_id_key;
+DROP INDEX IF EXISTS promocodeusage_promo_code_id_user_id;
+
+CREATE INDEX IF NOT EXISTS idx_promo_code_usages_code_user
+    ON promo_code_usages (promo_code_id, user_id);
+
+-- 每个用户同时最多一个待发放的比例赠送
+CREATE UNIQUE INDEX IF NOT EXISTS uq_promo_code_usages_pending_user
+    ON promo_code_usages (user_id)
+    WHERE status = 'pending';
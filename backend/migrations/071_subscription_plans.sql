@@ -0,0 +1,81 @@
+-- 071_subscription_plans.sql
+-- 自助订阅套餐：
+-- - subscription_plans 在订阅分组之上定义售价（余额）、有效天数与续费策略
+-- - subscription_orders 记录每次购买 / 续费 / 自动续费 / 升降级，升降级时按剩余时长折算抵扣
+-- - subscription_renewals 按 (user_id, group_id) 记录自动续费设置与到期提醒状态
+
+CREATE TABLE IF NOT EXISTS subscription_plans (
+    id BIGSERIAL PRIMARY KEY,
+
+    group_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+
+    price DECIMAL(20,8) NOT NULL,
+    validity_days INT NOT NULL,
+    -- none（一次性，不可续费）/ manual（可手动续费）/ auto（可手动续费，也可开启自动续费）
+    renewal_policy VARCHAR(20) NOT NULL DEFAULT 'auto',
+
+    -- active / disabled
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    sort_order INT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_plans_group
+    ON subscription_plans (group_id);
+
+CREATE TABLE IF NOT EXISTS subscription_orders (
+    id BIGSERIAL PRIMARY KEY,
+
+    user_id BIGINT NOT NULL,
+    plan_id BIGINT,
+    -- 下单时的套餐名称快照（套餐可能被修改或删除）
+    plan_name VARCHAR(100) NOT NULL DEFAULT '',
+    group_id BIGINT NOT NULL,
+    subscription_id BIGINT NOT NULL,
+    -- purchase / renew / auto_renew / change
+    kind VARCHAR(20) NOT NULL,
+    -- 升降级时的原分组
+    from_group_id BIGINT,
+
+    price DECIMAL(20,8) NOT NULL,
+    -- 升降级折算抵扣
+    credit DECIMAL(20,8) NOT NULL DEFAULT 0,
+    -- 实际扣除余额（price - credit，负数表示退回余额）
+    amount DECIMAL(20,8) NOT NULL,
+    validity_days INT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_orders_user
+    ON subscription_orders (user_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_orders_user_group
+    ON subscription_orders (user_id, group_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS subscription_renewals (
+    user_id BIGINT NOT NULL,
+    group_id BIGINT NOT NULL,
+
+    -- 续费使用的套餐（最近一次购买的套餐，或用户指定）
+    plan_id BIGINT,
+    auto_renew BOOLEAN NOT NULL DEFAULT FALSE,
+    last_attempt_at TIMESTAMPTZ,
+    last_error TEXT,
+    -- 已发送到期提醒对应的 expires_at，续费后 expires_at 变化即可再次提醒
+    reminder_sent_for TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, group_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_renewals_auto
+    ON subscription_renewals (group_id, user_id)
+    WHERE auto_renew;
@@ -0,0 +1,69 @@
+-- 072_payment_orders.sql
+-- 在线支付充值：
+-- - payment_orders 记录每笔充值订单（到账余额、实付金额 / 币种、支付商会话与交易号、累计退款）
+-- - payment_events 按 (provider, event_id) 去重支付回调，重复通知不会重复入账或扣回
+-- 入账与退款扣回同时写入 redeem_codes（type = payment），与兑换 / 管理员调整共用余额流水
+
+CREATE TABLE IF NOT EXISTS payment_orders (
+    id BIGSERIAL PRIMARY KEY,
+
+    order_no VARCHAR(64) NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL,
+    -- stripe / generic
+    provider VARCHAR(20) NOT NULL,
+    -- 支付商侧的会话 ID（如 Stripe Checkout Session）
+    provider_order_id VARCHAR(255) NOT NULL DEFAULT '',
+    -- 支付商侧的交易号（如 Stripe PaymentIntent），退款时使用
+    provider_payment_id VARCHAR(255) NOT NULL DEFAULT '',
+
+    -- 到账余额（USD）
+    amount DECIMAL(20,8) NOT NULL,
+    -- 实付金额与币种（按下单时的汇率换算）
+    pay_amount DECIMAL(20,2) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    -- 入账时发放的优惠码比例赠送，退款时按比例一并扣回
+    bonus_amount DECIMAL(20,8) NOT NULL DEFAULT 0,
+
+    -- pending / paid / partially_refunded / refunded / expired / failed
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    pay_url TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+
+    -- 累计退款（支付币种）与累计扣回余额
+    refunded_amount DECIMAL(20,2) NOT NULL DEFAULT 0,
+    refunded_credit DECIMAL(20,8) NOT NULL DEFAULT 0,
+
+    expires_at TIMESTAMPTZ NOT NULL,
+    paid_at TIMESTAMPTZ,
+    refunded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_orders_user
+    ON payment_orders (user_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_payment_orders_provider_payment
+    ON payment_orders (provider, provider_payment_id)
+    WHERE provider_payment_id <> '';
+
+CREATE INDEX IF NOT EXISTS idx_payment_orders_pending
+    ON payment_orders (expires_at)
+    WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    id BIGSERIAL PRIMARY KEY,
+
+    provider VARCHAR(20) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    order_no VARCHAR(64) NOT NULL DEFAULT '',
+    -- paid / refunded / expired / failed
+    event_type VARCHAR(20) NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (provider, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_events_order
+    ON payment_events (order_no);
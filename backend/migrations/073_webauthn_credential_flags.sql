@@ -0,0 +1,8 @@
+-- 073_webauthn_credential_flags.sql
+-- 通行密钥备份标志（BE / BS）：
+-- - backup_eligible 为注册时认证器声明的可备份标志，之后的断言必须一致
+-- - 早期注册的凭据未记录（NULL），首次断言成功时补齐
+-- - backup_state 为最近一次断言时的备份状态
+
+ALTER TABLE user_webauthn_credentials ADD COLUMN IF NOT EXISTS backup_eligible BOOLEAN;
+ALTER TABLE user_webauthn_credentials ADD COLUMN IF NOT EXISTS backup_state BOOLEAN NOT NULL DEFAULT FALSE;
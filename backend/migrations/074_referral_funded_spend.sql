@@ -0,0 +1,7 @@
+-- 074_referral_funded_spend.sql
+-- 邀请返利只对被邀请人用充值余额支付的消费生效：
+-- - 结算时以截至结算点的支付订单到账额（扣除退款，不含充值赠送）作为可返佣消费的上限
+-- - 注册赠送、优惠码、兑换码、邀请奖励与管理员调整等赠送余额支付的消费不返佣，也不计入注册奖励门槛
+-- - referee_funded_spend 为已结算消费中由充值余额支付的累计额，已有记录从 0 开始累计
+
+ALTER TABLE user_referrals ADD COLUMN IF NOT EXISTS referee_funded_spend DECIMAL(20, 8) NOT NULL DEFAULT 0;
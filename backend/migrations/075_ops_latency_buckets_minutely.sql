@@ -0,0 +1,26 @@
+-- 075_ops_latency_buckets_minutely.sql
+-- 分钟级延迟直方图预聚合：
+-- - 由 ops 指标采集任务每分钟写入，按 overall / platform / group 三个粒度记录 duration 与 TTFT 的分桶计数
+-- - 分桶为对数刻度：第 i 桶覆盖 [1.1^i, 1.1^(i+1)) ms（第 0 桶同时覆盖 [0, 1)），最后一桶收纳更慢的样本
+-- - 告警的延迟分位数指标对窗口内的分桶计数求和后插值得到，不再对 usage_logs 原始数据排序
+
+CREATE TABLE IF NOT EXISTS ops_latency_buckets_minutely (
+    id BIGSERIAL PRIMARY KEY,
+
+    bucket_start TIMESTAMPTZ NOT NULL,
+    platform VARCHAR(32),
+    group_id BIGINT,
+
+    -- duration：请求耗时；ttft：首 token 耗时
+    metric VARCHAR(16) NOT NULL,
+    bucket_index SMALLINT NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ops_latency_buckets_minutely_unique_dim
+    ON ops_latency_buckets_minutely (bucket_start, COALESCE(platform, ''), COALESCE(group_id, 0), metric, bucket_index);
+
+CREATE INDEX IF NOT EXISTS idx_ops_latency_buckets_minutely_bucket
+    ON ops_latency_buckets_minutely (bucket_start);
@@ -0,0 +1,36 @@
+-- 076_payment_refunds.sql
+-- 管理员退款分三步执行，避免在持有订单 / 用户行锁的事务内调用支付商：
+-- 1) 事务内锁定订单与用户、校验余额，写入 refund_pending 记录后提交
+-- 2) 事务外调用支付商退款（refund_no 作为支付商侧幂等键）
+-- 3) 第二个事务按 refund_no 幂等地扣回余额、更新订单并将记录标记为 completed
+-- 第三步失败时记录保持 refund_pending，由退款回调或后台任务以同一 refund_no 补齐
+
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id BIGSERIAL PRIMARY KEY,
+
+    refund_no VARCHAR(96) NOT NULL UNIQUE,
+    order_no VARCHAR(64) NOT NULL,
+    -- 本次退款金额与退款完成后的累计退款额（支付币种）
+    amount DECIMAL(20,2) NOT NULL,
+    refunded_amount DECIMAL(20,2) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    -- 线下退款只扣回余额，不调用支付商
+    offline BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- refund_pending / completed / failed
+    status VARCHAR(20) NOT NULL DEFAULT 'refund_pending',
+    provider_refund_id VARCHAR(255) NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 每个订单同一时间只允许一笔处理中的退款
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_refunds_order_pending
+    ON payment_refunds (order_no)
+    WHERE status = 'refund_pending';
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_pending
+    ON payment_refunds (updated_at)
+    WHERE status = 'refund_pending';
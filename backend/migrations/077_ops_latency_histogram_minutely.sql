@@ -0,0 +1,38 @@
+-- 077_ops_latency_histogram_minutely.sql
+-- 分钟级延迟直方图改为复用 ops 仪表盘的延迟分桶（0-100ms / 100-200ms / ... / 2000ms+），
+-- 不再维护 075 中单独的对数刻度分桶：
+-- - 每个 (分钟, 粒度, 指标, 分桶) 记录样本数以及桶内最小 / 最大耗时，分位数在桶内 [min, max] 区间插值
+-- - ops_latency_histogram_coverage 记录采集任务已完整聚合的分钟（无流量的分钟同样记录），
+--   告警计算只读取已覆盖分钟的预聚合数据，未覆盖的分钟与窗口首尾不足一分钟的部分回退到 usage_logs
+
+DROP TABLE IF EXISTS ops_latency_buckets_minutely;
+
+CREATE TABLE IF NOT EXISTS ops_latency_histogram_minutely (
+    id BIGSERIAL PRIMARY KEY,
+
+    bucket_start TIMESTAMPTZ NOT NULL,
+    platform VARCHAR(32),
+    group_id BIGINT,
+
+    -- duration：请求耗时；ttft：首 token 耗时
+    metric VARCHAR(16) NOT NULL,
+    -- 与仪表盘延迟直方图的分桶顺序一致（从 1 开始）
+    range_order SMALLINT NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    min_ms INT NOT NULL DEFAULT 0,
+    max_ms INT NOT NULL DEFAULT 0,
+
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ops_latency_histogram_minutely_unique_dim
+    ON ops_latency_histogram_minutely (bucket_start, COALESCE(platform, ''), COALESCE(group_id, 0), metric, range_order);
+
+CREATE INDEX IF NOT EXISTS idx_ops_latency_histogram_minutely_bucket
+    ON ops_latency_histogram_minutely (bucket_start);
+
+CREATE TABLE IF NOT EXISTS ops_latency_histogram_coverage (
+    id BIGSERIAL PRIMARY KEY,
+    bucket_start TIMESTAMPTZ NOT NULL UNIQUE,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
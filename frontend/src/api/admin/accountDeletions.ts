@@ -0,0 +1,33 @@
+/**
+ * Admin Account Deletion API endpoints
+ * Handles reviewing self-service deletion requests and restoring accounts during the grace period
+ */
+
+import { apiClient } from '../client'
+import type { AccountDeletionRequest, AccountDeletionStatus, BasePaginationResponse } from '@/types'
+
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    status?: AccountDeletionStatus | ''
+    user_id?: number
+  }
+): Promise<BasePaginationResponse<AccountDeletionRequest>> {
+  const { data } = await apiClient.get<BasePaginationResponse<AccountDeletionRequest>>('/admin/account-deletions', {
+    params: { page, page_size: pageSize, ...filters }
+  })
+  return data
+}
+
+export async function restore(id: number): Promise<AccountDeletionRequest> {
+  const { data } = await apiClient.post<AccountDeletionRequest>(`/admin/account-deletions/${id}/restore`)
+  return data
+}
+
+const accountDeletionsAPI = {
+  list,
+  restore
+}
+
+export default accountDeletionsAPI
@@ -0,0 +1,142 @@
+/**
+ * Admin API Key Abuse Detection API endpoints
+ * Handles key sharing/abuse flags, review actions and detector settings
+ */
+
+import { apiClient } from '../client'
+import type { BasePaginationResponse } from '@/types'
+
+export type APIKeyAbuseFlagStatus = 'open' | 'dismissed' | 'resolved'
+export type APIKeyAbuseAction = 'none' | 'rate_limited' | 'suspended'
+export type APIKeyAbuseAutoAction = 'none' | 'rate_limit' | 'suspend'
+export type APIKeyAbuseReviewAction = 'dismiss' | 'restore' | 'rate_limit' | 'suspend'
+
+export interface APIKeyAbuseScore {
+  total: number
+  ips: number
+  networks: number
+  concurrency: number
+  user_agents: number
+  active_hours: number
+}
+
+export interface APIKeyAbuseFlag {
+  id: number
+  api_key_id: number
+  user_id: number
+  window_hours: number
+  request_count: number
+  distinct_ips: number
+  distinct_networks: number
+  peak_concurrent_ips: number
+  distinct_user_agents: number
+  active_hours: number
+  score: number
+  breakdown?: APIKeyAbuseScore
+  status: APIKeyAbuseFlagStatus
+  action: APIKeyAbuseAction
+  rate_limit_rpm?: number
+  auto_actioned: boolean
+  owner_notified_at?: string
+  first_detected_at: string
+  last_detected_at: string
+  reviewed_by?: number
+  reviewed_at?: string
+  review_note?: string
+  created_at: string
+  updated_at: string
+  api_key_name: string
+  api_key_status: string
+  user_email: string
+}
+
+export interface APIKeyAbuseSource {
+  value: string
+  request_count: number
+  last_seen_at: string
+}
+
+export interface APIKeyAbuseFlagDetail extends APIKeyAbuseFlag {
+  top_ips: APIKeyAbuseSource[]
+  top_user_agents: APIKeyAbuseSource[]
+}
+
+export interface APIKeyAbuseSettings {
+  enabled: boolean
+  scan_interval_minutes: number
+  window_hours: number
+  min_requests: number
+  max_distinct_ips: number
+  max_networks: number
+  max_concurrent_ips: number
+  max_user_agents: number
+  flag_score: number
+  auto_action: APIKeyAbuseAutoAction
+  action_score: number
+  rate_limit_rpm: number
+  notify_owner: boolean
+  dismiss_suppress_days: number
+}
+
+export interface APIKeyAbuseReviewRequest {
+  action: APIKeyAbuseReviewAction
+  note?: string
+  rate_limit_rpm?: number
+}
+
+export interface APIKeyAbuseScanResult {
+  scanned: number
+  flagged: number
+  actioned: number
+}
+
+export async function listFlags(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    status?: string
+    user_id?: number
+    api_key_id?: number
+  }
+): Promise<BasePaginationResponse<APIKeyAbuseFlag>> {
+  const { data } = await apiClient.get<BasePaginationResponse<APIKeyAbuseFlag>>('/admin/api-key-abuse/flags', {
+    params: { page, page_size: pageSize, ...filters }
+  })
+  return data
+}
+
+export async function getFlag(id: number): Promise<APIKeyAbuseFlagDetail> {
+  const { data } = await apiClient.get<APIKeyAbuseFlagDetail>(`/admin/api-key-abuse/flags/${id}`)
+  return data
+}
+
+export async function reviewFlag(id: number, request: APIKeyAbuseReviewRequest): Promise<APIKeyAbuseFlag> {
+  const { data } = await apiClient.post<APIKeyAbuseFlag>(`/admin/api-key-abuse/flags/${id}/action`, request)
+  return data
+}
+
+export async function scan(): Promise<APIKeyAbuseScanResult> {
+  const { data } = await apiClient.post<APIKeyAbuseScanResult>('/admin/api-key-abuse/scan')
+  return data
+}
+
+export async function getSettings(): Promise<APIKeyAbuseSettings> {
+  const { data } = await apiClient.get<APIKeyAbuseSettings>('/admin/api-key-abuse/settings')
+  return data
+}
+
+export async function updateSettings(settings: APIKeyAbuseSettings): Promise<APIKeyAbuseSettings> {
+  const { data } = await apiClient.put<APIKeyAbuseSettings>('/admin/api-key-abuse/settings', settings)
+  return data
+}
+
+const apiKeyAbuseAPI = {
+  listFlags,
+  getFlag,
+  reviewFlag,
+  scan,
+  getSettings,
+  updateSettings
+}
+
+export default apiKeyAbuseAPI
@@ -0,0 +1,65 @@
+/**
+ * Admin Impersonation API endpoints
+ * Handles "act as user" sessions and their audit trail
+ */
+
+import { apiClient } from '../client'
+import type {
+  BasePaginationResponse,
+  ImpersonationAction,
+  ImpersonationSession,
+  ImpersonationStartResult
+} from '@/types'
+
+export async function start(
+  userId: number,
+  reason: string,
+  durationMinutes?: number
+): Promise<ImpersonationStartResult> {
+  const { data } = await apiClient.post<ImpersonationStartResult>(`/admin/users/${userId}/impersonate`, {
+    reason,
+    duration_minutes: durationMinutes
+  })
+  return data
+}
+
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    admin_id?: number
+    user_id?: number
+    active?: boolean
+  }
+): Promise<BasePaginationResponse<ImpersonationSession>> {
+  const { data } = await apiClient.get<BasePaginationResponse<ImpersonationSession>>('/admin/impersonations', {
+    params: { page, page_size: pageSize, ...filters }
+  })
+  return data
+}
+
+export async function listActions(
+  sessionId: number,
+  page: number = 1,
+  pageSize: number = 20
+): Promise<BasePaginationResponse<ImpersonationAction>> {
+  const { data } = await apiClient.get<BasePaginationResponse<ImpersonationAction>>(
+    `/admin/impersonations/${sessionId}/actions`,
+    { params: { page, page_size: pageSize } }
+  )
+  return data
+}
+
+export async function end(sessionId: number): Promise<ImpersonationSession> {
+  const { data } = await apiClient.post<ImpersonationSession>(`/admin/impersonations/${sessionId}/end`)
+  return data
+}
+
+const impersonationAPI = {
+  start,
+  list,
+  listActions,
+  end
+}
+
+export default impersonationAPI
@@ -20,6 +20,13 @@ import antigravityAPI from './antigravity'
 import userAttributesAPI from './userAttributes'
 import opsAPI from './ops'
 import errorPassthroughAPI from './errorPassthrough'
+import apiKeyAbuseAPI from './apiKeyAbuse'
+import spendGuardAPI from './spendGuard'
+import referralsAPI from './referrals'
+import impersonationAPI from './impersonation'
+import accountDeletionsAPI from './accountDeletions'
+import subscriptionPlansAPI from './subscriptionPlans'
+import paymentsAPI from './payments'
 
 /**
  * Unified admin API object for convenient access
@@ -41,7 +48,14 @@ export const adminAPI = {
   antigravity: antigravityAPI,
   userAttributes: userAttributesAPI,
   ops: opsAPI,
-  errorPassthrough: errorPassthroughAPI
+  errorPassthrough: errorPassthroughAPI,
+  apiKeyAbuse: apiKeyAbuseAPI,
+  spendGuard: spendGuardAPI,
+  referrals: referralsAPI,
+  impersonation: impersonationAPI,
+  accountDeletions: accountDeletionsAPI,
+  subscriptionPlans: subscriptionPlansAPI,
+  payments: paymentsAPI
 }
 
 export {
@@ -61,7 +75,14 @@ export {
   antigravityAPI,
   userAttributesAPI,
   opsAPI,
-  errorPassthroughAPI
+  errorPassthroughAPI,
+  apiKeyAbuseAPI,
+  spendGuardAPI,
+  referralsAPI,
+  impersonationAPI,
+  accountDeletionsAPI,
+  subscriptionPlansAPI,
+  paymentsAPI
 }
 
 export default adminAPI
@@ -327,6 +327,13 @@ export interface AccountConcurrencyInfo {
   max_capacity: number
   load_percentage: number
   waiting_in_queue: number
+  model_slots?: AccountModelConcurrencyInfo[]
+}
+
+export interface AccountModelConcurrencyInfo {
+  pattern: string
+  current_in_use: number
+  max_capacity: number
 }
 
 export interface OpsConcurrencyStatsResponse {
@@ -694,14 +701,25 @@ export type MetricType =
   | 'cpu_usage_percent'
   | 'memory_usage_percent'
   | 'concurrency_queue_depth'
+  | 'duration_p95_ms'
+  | 'duration_p99_ms'
+  | 'first_token_p95_ms'
+  | 'first_token_p99_ms'
+  | 'requests_per_minute'
+  | 'tokens_per_minute'
+  | 'spend_usd'
   | 'group_available_accounts'
   | 'group_available_ratio'
   | 'group_rate_limit_ratio'
   | 'account_rate_limited_count'
   | 'account_error_count'
   | 'account_error_ratio'
+  | 'account_error_rate'
   | 'overload_account_count'
-export type Operator = '>' | '>=' | '<' | '<=' | '==' | '!='
+  | 'slo_burn_rate'
+  | 'slo_error_budget_remaining'
+export type Operator = '>' | '>=' | '<' | '<=' | '==' | '!=' | 'anomaly_above' | 'anomaly_below'
+export type AnomalyMethod = 'zscore' | 'percent'
 
 export interface AlertRule {
   id?: number
@@ -717,6 +735,9 @@ export interface AlertRule {
   cooldown_minutes: number
   notify_email: boolean
   filters?: Record<string, any>
+  anomaly_method?: AnomalyMethod
+  baseline_days?: number
+  public_status?: boolean
   created_at?: string
   updated_at?: string
   last_triggered_at?: string | null
@@ -736,6 +757,241 @@ export interface AlertEvent {
   resolved_at?: string | null
   email_sent: boolean
   created_at: string
+  acknowledged_at?: string | null
+  acknowledged_by?: number | null
+  assignee?: string
+  ack_note?: string
+  escalation_level: number
+  last_notified_at?: string | null
+  notification_count: number
+  is_public?: boolean
+  public_summary?: string
+}
+
+export type AlertEventTimelineAction =
+  | 'fired'
+  | 'notified'
+  | 'repeat_notified'
+  | 'escalated'
+  | 'acknowledged'
+  | 'resolved'
+  | 'manual_resolved'
+
+export interface AlertEventTimelineEntry {
+  id: number
+  event_id: number
+  action: AlertEventTimelineAction | string
+  actor_user_id?: number | null
+  message?: string
+  details?: Record<string, any>
+  created_at: string
+}
+
+export type SLOObjective = 'availability' | 'latency'
+
+export interface OpsSLO {
+  id?: number
+  name: string
+  description?: string
+  enabled: boolean
+  platform?: string
+  group_id?: number | null
+  availability_target: number // percent, e.g. 99.9
+  latency_target_ms?: number | null // first_token_ms threshold
+  latency_target_percent?: number | null // percent of requests within latency_target_ms
+  window_days: number
+  created_at?: string
+  updated_at?: string
+}
+
+export interface OpsSLOBurnRate {
+  window_minutes: number
+  burn_rate: number | null
+}
+
+export interface OpsSLOObjectiveStatus {
+  objective: SLOObjective
+  target: number
+  total_count: number
+  bad_count: number
+  actual: number | null
+  error_budget_remaining_percent: number | null
+  burn_rate: number | null
+  burn_rates: OpsSLOBurnRate[]
+}
+
+export interface OpsSLOStatus {
+  slo: OpsSLO
+  window_start: string
+  window_end: string
+  availability: OpsSLOObjectiveStatus
+  latency?: OpsSLOObjectiveStatus
+}
+
+export type OpsRequestCaptureTargetType = 'api_key' | 'user'
+export type OpsRequestCaptureReason = 'api_key' | 'user' | 'sample'
+export type OpsRequestCaptureFormat = 'json' | 'sse_reconstructed' | 'sse_raw' | 'text'
+
+export interface OpsRequestCaptureTarget {
+  type: OpsRequestCaptureTargetType
+  id: number
+  expires_at?: string | null
+  note?: string
+}
+
+export interface OpsRequestCaptureSettings {
+  enabled: boolean
+  sample_rate: number // 0-1
+  sample_until?: string | null
+  targets: OpsRequestCaptureTarget[]
+  retention_hours: number
+  max_request_bytes: number
+  max_response_bytes: number
+  max_captures: number
+  redact_keys: string[]
+  redact_patterns: string[]
+}
+
+export interface OpsRequestCapture {
+  id: number
+  created_at: string
+  expires_at: string
+  request_id: string
+  client_request_id: string
+  user_id?: number | null
+  api_key_id?: number | null
+  account_id?: number | null
+  group_id?: number | null
+  platform: string
+  model: string
+  request_path: string
+  stream: boolean
+  status_code: number
+  capture_reason: OpsRequestCaptureReason
+  request_body?: string
+  request_body_bytes: number
+  request_truncated: boolean
+  response_body?: string
+  response_format: OpsRequestCaptureFormat | ''
+  response_body_bytes: number
+  response_truncated: boolean
+}
+
+export interface OpsRequestCaptureQueryParams {
+  page?: number
+  page_size?: number
+  time_range?: string
+  request_id?: string
+  client_request_id?: string
+  user_id?: number
+  api_key_id?: number
+  account_id?: number
+}
+
+export interface OpsShadowRule {
+  id?: number
+  name: string
+  description?: string
+  enabled: boolean
+  group_id: number
+  shadow_account_id: number
+  sample_rate: number
+  expires_at?: string | null
+  created_at?: string
+  updated_at?: string
+}
+
+export interface OpsShadowResult {
+  id: number
+  rule_id: number
+  group_id?: number | null
+  request_id: string
+  created_at: string
+  platform: string
+  model: string
+  request_path: string
+  stream: boolean
+  primary_account_id?: number | null
+  primary_status_code: number
+  primary_duration_ms?: number | null
+  primary_first_token_ms?: number | null
+  primary_input_tokens: number
+  primary_output_tokens: number
+  shadow_account_id: number
+  shadow_status_code: number
+  shadow_duration_ms?: number | null
+  shadow_first_token_ms?: number | null
+  shadow_input_tokens: number
+  shadow_output_tokens: number
+  shadow_error?: string
+}
+
+export interface OpsShadowLegStats {
+  success_count: number
+  success_rate?: number | null
+  duration_avg_ms?: number | null
+  duration_p50_ms?: number | null
+  duration_p95_ms?: number | null
+  first_token_avg_ms?: number | null
+  input_tokens: number
+  output_tokens: number
+}
+
+export interface OpsShadowReport {
+  rule: OpsShadowRule
+  start_time: string
+  end_time: string
+  sample_count: number
+  primary: OpsShadowLegStats
+  shadow: OpsShadowLegStats
+  duration_p50_delta_percent?: number | null
+  output_tokens_delta_percent?: number | null
+  shadow_status_counts: Array<{ status_code: number; count: number }>
+  recent: OpsShadowResult[]
+}
+
+export interface OpsStatusPageSettings {
+  enabled: boolean
+  title: string
+  platforms: string[]
+  show_models: boolean
+  min_model_requests: number
+  cache_seconds: number
+}
+
+export type OpsStatusIncidentStatus = 'investigating' | 'identified' | 'monitoring' | 'resolved'
+export type OpsStatusIncidentImpact = 'minor' | 'major' | 'critical'
+
+export interface OpsStatusIncidentUpdate {
+  id: number
+  incident_id: number
+  status: OpsStatusIncidentStatus
+  message: string
+  created_by?: number | null
+  created_at: string
+}
+
+export interface OpsStatusIncident {
+  id: number
+  title: string
+  status: OpsStatusIncidentStatus
+  impact: OpsStatusIncidentImpact
+  platforms: string[]
+  started_at: string
+  resolved_at?: string | null
+  created_by?: number | null
+  created_at: string
+  updated_at: string
+  updates: OpsStatusIncidentUpdate[]
+}
+
+export interface OpsStatusIncidentInput {
+  title: string
+  status?: OpsStatusIncidentStatus
+  impact: OpsStatusIncidentImpact
+  platforms: string[]
+  message?: string
+  started_at?: string | null
 }
 
 export interface EmailNotificationConfig {
@@ -791,6 +1047,21 @@ export interface OpsAlertRuntimeSettings {
     }>
   }
   thresholds: OpsMetricThresholds // 指标阈值配置
+  escalation?: {
+    enabled: boolean
+    policies?: Array<{
+      severity: OpsSeverity | string
+      levels: Array<{
+        after_minutes: number
+        recipients: string[]
+      }>
+    }>
+  }
+  repeat_notification?: {
+    enabled: boolean
+    interval_minutes: number
+    max_repeats: number // 0 = unlimited
+  }
 }
 
 export interface OpsAdvancedSettings {
@@ -1127,6 +1398,16 @@ export async function updateAlertEventStatus(id: number, status: 'resolved' | 'm
   await apiClient.put(`/admin/ops/alert-events/${id}/status`, { status })
 }
 
+export async function acknowledgeAlertEvent(id: number, payload: { assignee?: string; note?: string } = {}): Promise<AlertEvent> {
+  const { data } = await apiClient.post<AlertEvent>(`/admin/ops/alert-events/${id}/ack`, payload)
+  return data
+}
+
+export async function listAlertEventTimeline(id: number): Promise<AlertEventTimelineEntry[]> {
+  const { data } = await apiClient.get<AlertEventTimelineEntry[]>(`/admin/ops/alert-events/${id}/timeline`)
+  return data
+}
+
 export async function createAlertSilence(payload: {
   rule_id: number
   platform: string
@@ -1138,6 +1419,129 @@ export async function createAlertSilence(payload: {
   await apiClient.post('/admin/ops/alert-silences', payload)
 }
 
+// SLOs
+export async function listSLOs(): Promise<OpsSLO[]> {
+  const { data } = await apiClient.get<OpsSLO[]>('/admin/ops/slos')
+  return data
+}
+
+export async function createSLO(slo: OpsSLO): Promise<OpsSLO> {
+  const { data } = await apiClient.post<OpsSLO>('/admin/ops/slos', slo)
+  return data
+}
+
+export async function updateSLO(id: number, slo: OpsSLO): Promise<OpsSLO> {
+  const { data } = await apiClient.put<OpsSLO>(`/admin/ops/slos/${id}`, slo)
+  return data
+}
+
+export async function deleteSLO(id: number): Promise<void> {
+  await apiClient.delete(`/admin/ops/slos/${id}`)
+}
+
+export async function listSLOStatuses(): Promise<OpsSLOStatus[]> {
+  const { data } = await apiClient.get<OpsSLOStatus[]>('/admin/ops/slos/status')
+  return data
+}
+
+// Request captures (opt-in capture of successful requests)
+export async function getRequestCaptureSettings(): Promise<OpsRequestCaptureSettings> {
+  const { data } = await apiClient.get<OpsRequestCaptureSettings>('/admin/ops/request-capture/settings')
+  return data
+}
+
+export async function updateRequestCaptureSettings(config: OpsRequestCaptureSettings): Promise<OpsRequestCaptureSettings> {
+  const { data } = await apiClient.put<OpsRequestCaptureSettings>('/admin/ops/request-capture/settings', config)
+  return data
+}
+
+export async function listRequestCaptures(params: OpsRequestCaptureQueryParams = {}): Promise<PaginatedResponse<OpsRequestCapture>> {
+  const { data } = await apiClient.get<PaginatedResponse<OpsRequestCapture>>('/admin/ops/request-captures', { params })
+  return data
+}
+
+export async function getRequestCapture(id: number): Promise<OpsRequestCapture> {
+  const { data } = await apiClient.get<OpsRequestCapture>(`/admin/ops/request-captures/${id}`)
+  return data
+}
+
+export async function deleteRequestCapture(id: number): Promise<void> {
+  await apiClient.delete(`/admin/ops/request-captures/${id}`)
+}
+
+// Traffic shadowing
+export async function listShadowRules(): Promise<OpsShadowRule[]> {
+  const { data } = await apiClient.get<OpsShadowRule[]>('/admin/ops/shadow-rules')
+  return data
+}
+
+export async function createShadowRule(rule: OpsShadowRule): Promise<OpsShadowRule> {
+  const { data } = await apiClient.post<OpsShadowRule>('/admin/ops/shadow-rules', rule)
+  return data
+}
+
+export async function updateShadowRule(id: number, rule: OpsShadowRule): Promise<OpsShadowRule> {
+  const { data } = await apiClient.put<OpsShadowRule>(`/admin/ops/shadow-rules/${id}`, rule)
+  return data
+}
+
+export async function deleteShadowRule(id: number): Promise<void> {
+  await apiClient.delete(`/admin/ops/shadow-rules/${id}`)
+}
+
+export async function getShadowReport(id: number, timeRange = '24h'): Promise<OpsShadowReport> {
+  const { data } = await apiClient.get<OpsShadowReport>(`/admin/ops/shadow-rules/${id}/report`, {
+    params: { time_range: timeRange }
+  })
+  return data
+}
+
+// Public status page
+export async function getStatusPageSettings(): Promise<OpsStatusPageSettings> {
+  const { data } = await apiClient.get<OpsStatusPageSettings>('/admin/ops/status-page/settings')
+  return data
+}
+
+export async function updateStatusPageSettings(config: OpsStatusPageSettings): Promise<OpsStatusPageSettings> {
+  const { data } = await apiClient.put<OpsStatusPageSettings>('/admin/ops/status-page/settings', config)
+  return data
+}
+
+export async function listStatusIncidents(limit = 50): Promise<OpsStatusIncident[]> {
+  const { data } = await apiClient.get<OpsStatusIncident[]>('/admin/ops/status-incidents', { params: { limit } })
+  return data
+}
+
+export async function createStatusIncident(input: OpsStatusIncidentInput): Promise<OpsStatusIncident> {
+  const { data } = await apiClient.post<OpsStatusIncident>('/admin/ops/status-incidents', input)
+  return data
+}
+
+export async function updateStatusIncident(id: number, input: OpsStatusIncidentInput): Promise<OpsStatusIncident> {
+  const { data } = await apiClient.put<OpsStatusIncident>(`/admin/ops/status-incidents/${id}`, input)
+  return data
+}
+
+export async function addStatusIncidentUpdate(
+  id: number,
+  payload: { status: OpsStatusIncidentStatus; message: string }
+): Promise<OpsStatusIncident> {
+  const { data } = await apiClient.post<OpsStatusIncident>(`/admin/ops/status-incidents/${id}/updates`, payload)
+  return data
+}
+
+export async function deleteStatusIncident(id: number): Promise<void> {
+  await apiClient.delete(`/admin/ops/status-incidents/${id}`)
+}
+
+export async function setAlertEventPublic(
+  id: number,
+  payload: { is_public: boolean; public_summary?: string }
+): Promise<AlertEvent> {
+  const { data } = await apiClient.put<AlertEvent>(`/admin/ops/alert-events/${id}/public`, payload)
+  return data
+}
+
 // Email notification config
 export async function getEmailNotificationConfig(): Promise<EmailNotificationConfig> {
   const { data } = await apiClient.get<EmailNotificationConfig>('/admin/ops/email-notification/config')
@@ -1221,7 +1625,32 @@ export const opsAPI = {
   listAlertEvents,
   getAlertEvent,
   updateAlertEventStatus,
+  acknowledgeAlertEvent,
+  listAlertEventTimeline,
   createAlertSilence,
+  listSLOs,
+  createSLO,
+  updateSLO,
+  deleteSLO,
+  listSLOStatuses,
+  getRequestCaptureSettings,
+  updateRequestCaptureSettings,
+  listRequestCaptures,
+  getRequestCapture,
+  deleteRequestCapture,
+  listShadowRules,
+  createShadowRule,
+  updateShadowRule,
+  deleteShadowRule,
+  getShadowReport,
+  getStatusPageSettings,
+  updateStatusPageSettings,
+  listStatusIncidents,
+  createStatusIncident,
+  updateStatusIncident,
+  addStatusIncidentUpdate,
+  deleteStatusIncident,
+  setAlertEventPublic,
   getEmailNotificationConfig,
   updateEmailNotificationConfig,
   getAlertRuntimeSettings,
@@ -0,0 +1,59 @@
+/**
+ * Admin Payment API endpoints
+ * Handles top-up order listing, refunds and payment provider settings
+ */
+
+import { apiClient } from '../client'
+import type { BasePaginationResponse, PaymentOrder, PaymentSettings } from '@/types'
+
+export async function listOrders(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    user_id?: number
+    status?: string
+    provider?: string
+    search?: string
+  }
+): Promise<BasePaginationResponse<PaymentOrder>> {
+  const { data } = await apiClient.get<BasePaginationResponse<PaymentOrder>>(
+    '/admin/payments/orders',
+    { params: { page, page_size: pageSize, ...filters } }
+  )
+  return data
+}
+
+/**
+ * Refund an order and deduct the credited balance proportionally
+ * @param amount - Amount in the payment currency; 0 refunds the remainder
+ * @param offline - Only record the refund, without calling the provider refund API
+ */
+export async function refund(
+  orderNo: string,
+  payload: { amount: number; reason: string; offline: boolean }
+): Promise<PaymentOrder> {
+  const { data } = await apiClient.post<PaymentOrder>(
+    `/admin/payments/orders/${encodeURIComponent(orderNo)}/refund`,
+    payload
+  )
+  return data
+}
+
+export async function getSettings(): Promise<PaymentSettings> {
+  const { data } = await apiClient.get<PaymentSettings>('/admin/payments/settings')
+  return data
+}
+
+export async function updateSettings(settings: PaymentSettings): Promise<PaymentSettings> {
+  const { data } = await apiClient.put<PaymentSettings>('/admin/payments/settings', settings)
+  return data
+}
+
+const paymentsAPI = {
+  listOrders,
+  refund,
+  getSettings,
+  updateSettings
+}
+
+export default paymentsAPI
@@ -0,0 +1,53 @@
+/**
+ * Admin Referral API endpoints
+ * Handles referral program settings and review of flagged referrals
+ */
+
+import { apiClient } from '../client'
+import type { BasePaginationResponse, Referral, ReferralSettings } from '@/types'
+
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    status?: string
+    flagged?: boolean
+    referrer_id?: number
+    search?: string
+  }
+): Promise<BasePaginationResponse<Referral>> {
+  const { data } = await apiClient.get<BasePaginationResponse<Referral>>('/admin/referrals', {
+    params: { page, page_size: pageSize, ...filters }
+  })
+  return data
+}
+
+export async function approve(id: number, note?: string): Promise<Referral> {
+  const { data } = await apiClient.post<Referral>(`/admin/referrals/${id}/approve`, { note })
+  return data
+}
+
+export async function block(id: number, note?: string): Promise<Referral> {
+  const { data } = await apiClient.post<Referral>(`/admin/referrals/${id}/block`, { note })
+  return data
+}
+
+export async function getSettings(): Promise<ReferralSettings> {
+  const { data } = await apiClient.get<ReferralSettings>('/admin/referrals/settings')
+  return data
+}
+
+export async function updateSettings(settings: ReferralSettings): Promise<ReferralSettings> {
+  const { data } = await apiClient.put<ReferralSettings>('/admin/referrals/settings', settings)
+  return data
+}
+
+const referralsAPI = {
+  list,
+  approve,
+  block,
+  getSettings,
+  updateSettings
+}
+
+export default referralsAPI
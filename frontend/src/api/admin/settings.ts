@@ -17,6 +17,7 @@ export interface SystemSettings {
   invitation_code_enabled: boolean
   totp_enabled: boolean // TOTP 双因素认证
   totp_encryption_key_configured: boolean // TOTP 加密密钥是否已配置
+  passkey_login_enabled: boolean // 通行密钥无密码登录
   // Default settings
   default_balance: number
   default_concurrency: number
@@ -78,6 +79,7 @@ export interface UpdateSettingsRequest {
   password_reset_enabled?: boolean
   invitation_code_enabled?: boolean
   totp_enabled?: boolean // TOTP 双因素认证
+  passkey_login_enabled?: boolean // 通行密钥无密码登录
   default_balance?: number
   default_concurrency?: number
   site_name?: string
@@ -255,6 +257,74 @@ export async function updateStreamTimeoutSettings(
   return data
 }
 
+/**
+ * Generic OIDC / OAuth2 login provider
+ */
+export interface OIDCProvider {
+  slug: string
+  name: string
+  enabled: boolean
+  discovery_url: string
+  authorize_url: string
+  token_url: string
+  userinfo_url: string
+  client_id: string
+  client_secret?: string
+  client_secret_configured: boolean
+  token_auth_method: 'client_secret_post' | 'client_secret_basic' | 'none'
+  scopes: string
+  use_pkce: boolean
+  redirect_url: string
+  frontend_redirect_url: string
+  subject_claim: string
+  email_claim: string
+  email_verified_claim: string
+  trust_all_emails: boolean
+  username_claim: string
+  allowed_email_domains: string[]
+  trust_email: boolean
+}
+
+/**
+ * List OIDC login providers (client secrets are never returned)
+ */
+export async function listOIDCProviders(): Promise<OIDCProvider[]> {
+  const { data } = await apiClient.get<OIDCProvider[]>('/admin/settings/oidc-providers')
+  return data
+}
+
+/**
+ * Create an OIDC login provider
+ */
+export async function createOIDCProvider(provider: Partial<OIDCProvider>): Promise<OIDCProvider> {
+  const { data } = await apiClient.post<OIDCProvider>('/admin/settings/oidc-providers', provider)
+  return data
+}
+
+/**
+ * Update an OIDC login provider (empty client_secret keeps the current one)
+ */
+export async function updateOIDCProvider(
+  slug: string,
+  provider: Partial<OIDCProvider>
+): Promise<OIDCProvider> {
+  const { data } = await apiClient.put<OIDCProvider>(
+    `/admin/settings/oidc-providers/${encodeURIComponent(slug)}`,
+    provider
+  )
+  return data
+}
+
+/**
+ * Delete an OIDC login provider
+ */
+export async function deleteOIDCProvider(slug: string): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(
+    `/admin/settings/oidc-providers/${encodeURIComponent(slug)}`
+  )
+  return data
+}
+
 export const settingsAPI = {
   getSettings,
   updateSettings,
@@ -264,7 +334,11 @@ export const settingsAPI = {
   regenerateAdminApiKey,
   deleteAdminApiKey,
   getStreamTimeoutSettings,
-  updateStreamTimeoutSettings
+  updateStreamTimeoutSettings,
+  listOIDCProviders,
+  createOIDCProvider,
+  updateOIDCProvider,
+  deleteOIDCProvider
 }
 
 export default settingsAPI
@@ -0,0 +1,82 @@
+/**
+ * Admin Spend Guard API endpoints
+ * Handles spend anomaly auto-suspension records and settings
+ */
+
+import { apiClient } from '../client'
+import type { BasePaginationResponse } from '@/types'
+
+export type SpendSuspensionStatus = 'active' | 'expired' | 'lifted'
+export type SpendGuardScope = 'api_key' | 'user'
+export type SpendGuardRule = 'multiplier' | 'absolute'
+
+export interface SpendSuspension {
+  id: number
+  api_key_id: number
+  user_id: number
+  scope: SpendGuardScope
+  rule: SpendGuardRule
+  window_spend: number
+  baseline_spend: number
+  threshold_spend: number
+  status: SpendSuspensionStatus
+  suspended_until?: string
+  alert_event_id?: number
+  user_notified_at?: string
+  lifted_by?: number
+  lifted_at?: string
+  lift_note?: string
+  created_at: string
+  updated_at: string
+  api_key_name: string
+  api_key_status: string
+  user_email: string
+}
+
+export interface SpendGuardSettings {
+  enabled: boolean
+  baseline_days: number
+  multiplier: number
+  baseline_floor_usd: number
+  absolute_usd: number
+  suspend_minutes: number
+  notify_user: boolean
+}
+
+export async function listSuspensions(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    status?: string
+    user_id?: number
+  }
+): Promise<BasePaginationResponse<SpendSuspension>> {
+  const { data } = await apiClient.get<BasePaginationResponse<SpendSuspension>>('/admin/spend-guard/suspensions', {
+    params: { page, page_size: pageSize, ...filters }
+  })
+  return data
+}
+
+export async function liftSuspension(id: number, note?: string): Promise<SpendSuspension> {
+  const { data } = await apiClient.post<SpendSuspension>(`/admin/spend-guard/suspensions/${id}/lift`, { note })
+  return data
+}
+
+export async function getSettings(): Promise<SpendGuardSettings> {
+  const { data } = await apiClient.get<SpendGuardSettings>('/admin/spend-guard/settings')
+  return data
+}
+
+export async function updateSettings(settings: SpendGuardSettings): Promise<SpendGuardSettings> {
+  const { data } = await apiClient.put<SpendGuardSettings>('/admin/spend-guard/settings', settings)
+  return data
+}
+
+const spendGuardAPI = {
+  listSuspensions,
+  liftSuspension,
+  getSettings,
+  updateSettings
+}
+
+export default spendGuardAPI
@@ -0,0 +1,59 @@
+/**
+ * Admin Subscription Plan API endpoints
+ * Handles plan management and the subscription order log
+ */
+
+import { apiClient } from '../client'
+import type {
+  BasePaginationResponse,
+  SubscriptionOrder,
+  SubscriptionPlan,
+  SubscriptionPlanPayload
+} from '@/types'
+
+export async function list(): Promise<SubscriptionPlan[]> {
+  const { data } = await apiClient.get<SubscriptionPlan[]>('/admin/subscription-plans')
+  return data
+}
+
+export async function create(payload: SubscriptionPlanPayload): Promise<SubscriptionPlan> {
+  const { data } = await apiClient.post<SubscriptionPlan>('/admin/subscription-plans', payload)
+  return data
+}
+
+export async function update(id: number, payload: SubscriptionPlanPayload): Promise<SubscriptionPlan> {
+  const { data } = await apiClient.put<SubscriptionPlan>(`/admin/subscription-plans/${id}`, payload)
+  return data
+}
+
+export async function remove(id: number): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/admin/subscription-plans/${id}`)
+  return data
+}
+
+export async function listOrders(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    user_id?: number
+    group_id?: number
+    kind?: string
+    search?: string
+  }
+): Promise<BasePaginationResponse<SubscriptionOrder>> {
+  const { data } = await apiClient.get<BasePaginationResponse<SubscriptionOrder>>(
+    '/admin/subscription-orders',
+    { params: { page, page_size: pageSize, ...filters } }
+  )
+  return data
+}
+
+const subscriptionPlansAPI = {
+  list,
+  create,
+  update,
+  remove,
+  listOrders
+}
+
+export default subscriptionPlansAPI
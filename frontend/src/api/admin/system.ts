@@ -70,12 +70,43 @@ export async function restartService(): Promise<{ message: string }> {
   return data
 }
 
+export type LogLevel = 'debug' | 'info' | 'warn' | 'error'
+
+export interface LogLevels {
+  default: LogLevel
+  modules: Record<string, LogLevel>
+}
+
+export interface UpdateLogLevelsRequest {
+  default?: LogLevel
+  /** Empty string removes the module override */
+  modules?: Record<string, LogLevel | ''>
+}
+
+/**
+ * Get runtime log levels
+ */
+export async function getLogLevels(): Promise<LogLevels> {
+  const { data } = await apiClient.get<LogLevels>('/admin/system/log-levels')
+  return data
+}
+
+/**
+ * Update runtime log levels (not persisted across restarts)
+ */
+export async function updateLogLevels(req: UpdateLogLevelsRequest): Promise<LogLevels> {
+  const { data } = await apiClient.put<LogLevels>('/admin/system/log-levels', req)
+  return data
+}
+
 export const systemAPI = {
   getVersion,
   checkUpdates,
   performUpdate,
   rollback,
-  restartService
+  restartService,
+  getLogLevels,
+  updateLogLevels
 }
 
 export default systemAPI
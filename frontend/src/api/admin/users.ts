@@ -4,7 +4,7 @@
  */
 
 import { apiClient } from '../client'
-import type { AdminUser, UpdateUserRequest, PaginatedResponse } from '@/types'
+import type { AdminUser, UpdateUserRequest, PaginatedResponse, UserSession, LoginLock } from '@/types'
 
 /**
  * List all users with pagination
@@ -221,6 +221,52 @@ export async function getUserBalanceHistory(
   return data
 }
 
+/**
+ * List active login sessions of a user
+ * @param id - User ID
+ */
+export async function getUserSessions(id: number): Promise<UserSession[]> {
+  const { data } = await apiClient.get<UserSession[]>(`/admin/users/${id}/sessions`)
+  return data
+}
+
+/**
+ * Revoke a single session of a user
+ * @param id - User ID
+ * @param sessionId - Session ID
+ */
+export async function revokeUserSession(id: number, sessionId: string): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/admin/users/${id}/sessions/${sessionId}`)
+  return data
+}
+
+/**
+ * Revoke all sessions of a user
+ * @param id - User ID
+ */
+export async function revokeAllUserSessions(id: number): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/admin/users/${id}/sessions`)
+  return data
+}
+
+/**
+ * List currently locked sign-in accounts and IPs
+ */
+export async function getLoginLocks(): Promise<LoginLock[]> {
+  const { data } = await apiClient.get<LoginLock[]>('/admin/login-locks')
+  return data
+}
+
+/**
+ * Release a sign-in lock
+ * @param scope - 'account' (email) or 'ip'
+ * @param key - Locked email or IP address
+ */
+export async function unlockLogin(scope: LoginLock['scope'], key: string): Promise<{ message: string }> {
+  const { data } = await apiClient.post<{ message: string }>('/admin/login-locks/unlock', { scope, key })
+  return data
+}
+
 export const usersAPI = {
   list,
   getById,
@@ -232,7 +278,12 @@ export const usersAPI = {
   toggleStatus,
   getUserApiKeys,
   getUserUsageStats,
-  getUserBalanceHistory
+  getUserBalanceHistory,
+  getUserSessions,
+  revokeUserSession,
+  revokeAllUserSessions,
+  getLoginLocks,
+  unlockLogin
 }
 
 export default usersAPI
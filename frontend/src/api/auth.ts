@@ -13,7 +13,10 @@ import type {
   SendVerifyCodeResponse,
   PublicSettings,
   TotpLoginResponse,
-  TotpLogin2FARequest
+  TotpLogin2FARequest,
+  WebAuthnRequestOptions,
+  WebAuthnLoginBegin,
+  WebAuthnAssertionResponse
 } from '@/types'
 
 /**
@@ -107,8 +110,8 @@ export async function login(credentials: LoginRequest): Promise<LoginResponse> {
 }
 
 /**
- * Complete login with 2FA code
- * @param request - Temp token and TOTP code
+ * Complete login with a second factor
+ * @param request - Temp token and one of: TOTP code, recovery code or passkey assertion
  * @returns Authentication response with token and user data
  */
 export async function login2FA(request: TotpLogin2FARequest): Promise<AuthResponse> {
@@ -217,6 +220,14 @@ export async function revokeAllSessions(): Promise<{ message: string }> {
   return data
 }
 
+/**
+ * End the current impersonation session (only valid with an impersonation token)
+ */
+export async function exitImpersonation(): Promise<{ message: string }> {
+  const { data } = await apiClient.post<{ message: string }>('/auth/impersonation/exit')
+  return data
+}
+
 /**
  * Check if user is authenticated
  * @returns True if user has valid token
@@ -251,7 +262,10 @@ export async function sendVerifyCode(
  */
 export interface ValidatePromoCodeResponse {
   valid: boolean
+  bonus_type?: 'fixed' | 'percent'
   bonus_amount?: number
+  bonus_percent?: number
+  max_bonus_amount?: number
   error_code?: string
   message?: string
 }
@@ -335,9 +349,59 @@ export async function resetPassword(request: ResetPasswordRequest): Promise<Rese
   return data
 }
 
+/**
+ * Get passkey assertion options for a pending 2FA login
+ * @param tempToken - Temp token from the initial login response
+ */
+export async function beginLogin2FAPasskey(tempToken: string): Promise<WebAuthnRequestOptions> {
+  const { data } = await apiClient.post<{ options: WebAuthnRequestOptions }>('/auth/login/2fa/webauthn/begin', {
+    temp_token: tempToken
+  })
+  return data.options
+}
+
+/**
+ * Start passwordless passkey login
+ */
+export async function beginPasskeyLogin(): Promise<WebAuthnLoginBegin> {
+  const { data } = await apiClient.post<WebAuthnLoginBegin>('/auth/passkey/begin')
+  return data
+}
+
+/**
+ * Complete passwordless passkey login
+ * @param sessionToken - Session token from beginPasskeyLogin
+ * @param credential - Serialized authenticator assertion
+ * @returns Authentication response with token and user data
+ */
+export async function finishPasskeyLogin(
+  sessionToken: string,
+  credential: WebAuthnAssertionResponse
+): Promise<AuthResponse> {
+  const { data } = await apiClient.post<AuthResponse>('/auth/passkey/finish', {
+    session_token: sessionToken,
+    credential
+  })
+
+  // Store token and user data
+  setAuthToken(data.access_token)
+  if (data.refresh_token) {
+    setRefreshToken(data.refresh_token)
+  }
+  if (data.expires_in) {
+    setTokenExpiresAt(data.expires_in)
+  }
+  localStorage.setItem('auth_user', JSON.stringify(data.user))
+
+  return data
+}
+
 export const authAPI = {
   login,
   login2FA,
+  beginLogin2FAPasskey,
+  beginPasskeyLogin,
+  finishPasskeyLogin,
   isTotp2FARequired,
   register,
   getCurrentUser,
@@ -357,7 +421,8 @@ export const authAPI = {
   forgotPassword,
   resetPassword,
   refreshToken,
-  revokeAllSessions
+  revokeAllSessions,
+  exitImpersonation
 }
 
 export default authAPI
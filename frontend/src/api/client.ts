@@ -6,6 +6,7 @@
 import axios, { AxiosInstance, AxiosError, InternalAxiosRequestConfig, AxiosResponse } from 'axios'
 import type { ApiResponse } from '@/types'
 import { getLocale } from '@/i18n'
+import { restoreImpersonationBackup } from '@/utils/impersonation'
 
 // ==================== Axios Instance Configuration ====================
 
@@ -239,6 +240,16 @@ apiClient.interceptors.response.use(
           }
         }
 
+        // Impersonation token ended or expired - return to the admin's own session
+        if (!isAuthEndpoint && restoreImpersonationBackup()) {
+          window.location.href = '/admin/users'
+          return Promise.reject({
+            status,
+            code: 'IMPERSONATION_RESTORED',
+            message: apiData.message || apiData.detail || error.message
+          })
+        }
+
         // No refresh token or is auth endpoint - clear auth and redirect
         const hasToken = !!localStorage.getItem('auth_token')
         const headers = error.config?.headers as Record<string, unknown> | undefined
@@ -267,6 +278,8 @@ apiClient.interceptors.response.use(
       return Promise.reject({
         status,
         code: apiData.code,
+        reason: apiData.reason,
+        metadata: apiData.metadata,
         message: apiData.message || apiData.detail || error.message
       })
     }
@@ -14,9 +14,16 @@ export { keysAPI } from './keys'
 export { usageAPI } from './usage'
 export { userAPI } from './user'
 export { redeemAPI, type RedeemHistoryItem } from './redeem'
+export { referralAPI } from './referral'
+export { promoAPI } from './promo'
+export { subscriptionPlansAPI } from './subscriptionPlans'
+export { paymentsAPI } from './payments'
+export { userDataAPI } from './userData'
 export { userGroupsAPI } from './groups'
 export { totpAPI } from './totp'
+export { webauthnAPI } from './webauthn'
 export { default as announcementsAPI } from './announcements'
+export { default as statusAPI } from './status'
 
 // Admin APIs
 export { adminAPI } from './admin'
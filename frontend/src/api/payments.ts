@@ -0,0 +1,55 @@
+/**
+ * Payment API endpoints
+ * Handles online balance top-up orders
+ */
+
+import { apiClient } from './client'
+import type { BasePaginationResponse, PaymentConfig, PaymentOrder } from '@/types'
+
+/**
+ * Get the enabled payment methods and allowed amount range
+ */
+export async function getConfig(): Promise<PaymentConfig> {
+  const { data } = await apiClient.get<PaymentConfig>('/payments/config')
+  return data
+}
+
+/**
+ * Create a top-up order; redirect the user to the returned pay_url
+ * @param provider - Payment method name
+ * @param amount - Balance amount to add (USD)
+ */
+export async function createOrder(provider: string, amount: number): Promise<PaymentOrder> {
+  const { data } = await apiClient.post<PaymentOrder>('/payments/orders', { provider, amount })
+  return data
+}
+
+/**
+ * Get one of the current user's orders (polled after returning from the provider)
+ */
+export async function getOrder(orderNo: string): Promise<PaymentOrder> {
+  const { data } = await apiClient.get<PaymentOrder>(`/payments/orders/${encodeURIComponent(orderNo)}`)
+  return data
+}
+
+/**
+ * List the current user's top-up orders
+ */
+export async function listOrders(
+  page: number = 1,
+  pageSize: number = 10
+): Promise<BasePaginationResponse<PaymentOrder>> {
+  const { data } = await apiClient.get<BasePaginationResponse<PaymentOrder>>('/payments/orders', {
+    params: { page, page_size: pageSize }
+  })
+  return data
+}
+
+export const paymentsAPI = {
+  getConfig,
+  createOrder,
+  getOrder,
+  listOrders
+}
+
+export default paymentsAPI
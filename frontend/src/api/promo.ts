@@ -0,0 +1,55 @@
+/**
+ * Promo code API endpoints
+ * Handles redeeming promo codes from the user panel
+ */
+
+import { apiClient } from './client'
+import type { BasePaginationResponse, UserPromoCodeUsage } from '@/types'
+
+/**
+ * Pending top-up bonus response
+ */
+export interface PromoPendingResponse {
+  enabled: boolean
+  pending: UserPromoCodeUsage | null
+}
+
+/**
+ * Redeem a promo code for the current user
+ * @param code - Promo code
+ * @returns The created usage (pending for percentage codes)
+ */
+export async function redeem(code: string): Promise<UserPromoCodeUsage> {
+  const { data } = await apiClient.post<UserPromoCodeUsage>('/user/promo-codes/redeem', { code })
+  return data
+}
+
+/**
+ * List the current user's promo code usages
+ */
+export async function list(
+  page: number = 1,
+  pageSize: number = 20
+): Promise<BasePaginationResponse<UserPromoCodeUsage>> {
+  const { data } = await apiClient.get<BasePaginationResponse<UserPromoCodeUsage>>(
+    '/user/promo-codes',
+    { params: { page, page_size: pageSize } }
+  )
+  return data
+}
+
+/**
+ * Get the pending percentage bonus applied on the next balance top-up
+ */
+export async function getPending(): Promise<PromoPendingResponse> {
+  const { data } = await apiClient.get<PromoPendingResponse>('/user/promo-codes/pending')
+  return data
+}
+
+export const promoAPI = {
+  redeem,
+  list,
+  getPending
+}
+
+export default promoAPI
@@ -0,0 +1,50 @@
+/**
+ * Referral API endpoints
+ * Handles the current user's referral link, invited users and reward history
+ */
+
+import { apiClient } from './client'
+import type { BasePaginationResponse, Referral, ReferralOverview, ReferralReward } from '@/types'
+
+/**
+ * Get referral overview (code, program terms and stats)
+ * The referral code is generated on first access when the program is enabled
+ */
+export async function getOverview(): Promise<ReferralOverview> {
+  const { data } = await apiClient.get<ReferralOverview>('/user/referral')
+  return data
+}
+
+/**
+ * List users invited by the current user (emails are masked)
+ */
+export async function listReferrals(
+  page: number = 1,
+  pageSize: number = 20
+): Promise<BasePaginationResponse<Referral>> {
+  const { data } = await apiClient.get<BasePaginationResponse<Referral>>('/user/referral/referrals', {
+    params: { page, page_size: pageSize }
+  })
+  return data
+}
+
+/**
+ * List rewards credited to the current user
+ */
+export async function listRewards(
+  page: number = 1,
+  pageSize: number = 20
+): Promise<BasePaginationResponse<ReferralReward>> {
+  const { data } = await apiClient.get<BasePaginationResponse<ReferralReward>>('/user/referral/rewards', {
+    params: { page, page_size: pageSize }
+  })
+  return data
+}
+
+export const referralAPI = {
+  getOverview,
+  listReferrals,
+  listRewards
+}
+
+export default referralAPI
@@ -0,0 +1,59 @@
+/**
+ * Public status page API (no authentication required)
+ */
+
+import { apiClient } from './client'
+
+export type StatusLevel = 'operational' | 'degraded' | 'partial_outage' | 'major_outage' | 'no_data'
+
+export interface StatusWindow {
+  availability?: number | null
+  latency_p50_ms?: number | null
+}
+
+export interface StatusComponent {
+  platform: string
+  model?: string
+  status: StatusLevel
+  last_hour: StatusWindow
+  last_day: StatusWindow
+  models?: StatusComponent[]
+}
+
+export interface PublicIncidentUpdate {
+  status: string
+  message: string
+  created_at: string
+}
+
+export interface PublicIncident {
+  source: 'alert' | 'manual'
+  id: number
+  title: string
+  status: 'investigating' | 'identified' | 'monitoring' | 'resolved'
+  impact: 'minor' | 'major' | 'critical'
+  platforms: string[]
+  started_at: string
+  resolved_at?: string | null
+  updates?: PublicIncidentUpdate[]
+}
+
+export interface PublicStatus {
+  title: string
+  status: StatusLevel
+  generated_at: string
+  platforms: StatusComponent[]
+  incidents: PublicIncident[]
+  recent_incidents: PublicIncident[]
+}
+
+export async function getStatus(): Promise<PublicStatus> {
+  const { data } = await apiClient.get<PublicStatus>('/status')
+  return data
+}
+
+const statusAPI = {
+  getStatus
+}
+
+export default statusAPI
@@ -0,0 +1,105 @@
+/**
+ * Subscription plan API endpoints
+ * Handles self-service plan purchase, renewal, plan changes and auto-renewal
+ */
+
+import { apiClient } from './client'
+import type {
+  BasePaginationResponse,
+  SubscriptionOrder,
+  SubscriptionPlan,
+  SubscriptionPurchaseResult,
+  SubscriptionQuote,
+  SubscriptionRenewal
+} from '@/types'
+
+/**
+ * List plans available for purchase
+ */
+export async function listPlans(): Promise<SubscriptionPlan[]> {
+  const { data } = await apiClient.get<SubscriptionPlan[]>('/subscriptions/plans')
+  return data
+}
+
+/**
+ * Get the price and resulting expiry for a purchase or plan change
+ * @param planId - Plan ID
+ * @param fromGroupId - Group of the subscription being replaced (plan change only)
+ */
+export async function quote(planId: number, fromGroupId?: number): Promise<SubscriptionQuote> {
+  const { data } = await apiClient.get<SubscriptionQuote>(`/subscriptions/plans/${planId}/quote`, {
+    params: fromGroupId ? { from_group_id: fromGroupId } : undefined
+  })
+  return data
+}
+
+/**
+ * Buy or renew a plan using the account balance
+ */
+export async function purchase(planId: number): Promise<SubscriptionPurchaseResult> {
+  const { data } = await apiClient.post<SubscriptionPurchaseResult>(
+    `/subscriptions/plans/${planId}/purchase`
+  )
+  return data
+}
+
+/**
+ * Switch an active subscription in another group to this plan
+ */
+export async function change(planId: number, fromGroupId: number): Promise<SubscriptionPurchaseResult> {
+  const { data } = await apiClient.post<SubscriptionPurchaseResult>(
+    `/subscriptions/plans/${planId}/change`,
+    { from_group_id: fromGroupId }
+  )
+  return data
+}
+
+/**
+ * List the current user's subscription orders
+ */
+export async function listOrders(
+  page: number = 1,
+  pageSize: number = 20
+): Promise<BasePaginationResponse<SubscriptionOrder>> {
+  const { data } = await apiClient.get<BasePaginationResponse<SubscriptionOrder>>(
+    '/subscriptions/orders',
+    { params: { page, page_size: pageSize } }
+  )
+  return data
+}
+
+/**
+ * List auto-renewal settings per subscription group
+ */
+export async function listAutoRenew(): Promise<SubscriptionRenewal[]> {
+  const { data } = await apiClient.get<SubscriptionRenewal[]>('/subscriptions/auto-renew')
+  return data
+}
+
+/**
+ * Turn auto-renewal on or off for a subscription group
+ * @param planId - Plan to renew with; defaults to the most recently purchased plan
+ */
+export async function setAutoRenew(
+  groupId: number,
+  enabled: boolean,
+  planId?: number
+): Promise<SubscriptionRenewal | null> {
+  const { data } = await apiClient.put<SubscriptionRenewal | null>(
+    `/subscriptions/auto-renew/${groupId}`,
+    { enabled, plan_id: planId }
+  )
+  return data
+}
+
+export const subscriptionPlansAPI = {
+  listPlans,
+  quote,
+  purchase,
+  change,
+  listOrders,
+  listAutoRenew,
+  setAutoRenew
+}
+
+export default subscriptionPlansAPI
@@ -4,7 +4,7 @@
  */
 
 import { apiClient } from './client'
-import type { User, ChangePasswordRequest } from '@/types'
+import type { User, ChangePasswordRequest, UserSession } from '@/types'
 
 /**
  * Get current user profile
@@ -45,10 +45,30 @@ export async function changePassword(
   return data
 }
 
+/**
+ * List active login sessions of the current user
+ * @returns Sessions ordered by last activity, the current one flagged
+ */
+export async function listSessions(): Promise<UserSession[]> {
+  const { data } = await apiClient.get<UserSession[]>('/user/sessions')
+  return data
+}
+
+/**
+ * Sign out a single session
+ * @param id - Session ID
+ */
+export async function revokeSession(id: string): Promise<{ success: boolean }> {
+  const { data } = await apiClient.delete<{ success: boolean }>(`/user/sessions/${id}`)
+  return data
+}
+
 export const userAPI = {
   getProfile,
   updateProfile,
-  changePassword
+  changePassword,
+  listSessions,
+  revokeSession
 }
 
 export default userAPI
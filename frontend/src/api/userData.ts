@@ -0,0 +1,81 @@
+/**
+ * User data API endpoints
+ * Handles self-service personal data export and account deletion
+ */
+
+import { apiClient } from './client'
+import type { AccountDeletionRequest, AccountDeletionStatusResponse, UserDataExport } from '@/types'
+
+/**
+ * List the current user's recent data exports
+ */
+export async function listExports(): Promise<UserDataExport[]> {
+  const { data } = await apiClient.get<UserDataExport[]>('/user/data-export')
+  return data
+}
+
+/**
+ * Request a new data export; the archive is prepared in the background
+ */
+export async function requestExport(): Promise<UserDataExport> {
+  const { data } = await apiClient.post<UserDataExport>('/user/data-export')
+  return data
+}
+
+/**
+ * Download a completed export archive
+ * @param id - Export ID
+ * @returns Zip archive as blob
+ */
+export async function downloadExport(id: number): Promise<Blob> {
+  const response = await apiClient.get(`/user/data-export/${id}/download`, {
+    responseType: 'blob'
+  })
+  return response.data
+}
+
+/**
+ * Get the current open account deletion request (if any) and the grace period
+ */
+export async function getDeletionStatus(): Promise<AccountDeletionStatusResponse> {
+  const { data } = await apiClient.get<AccountDeletionStatusResponse>('/user/account-deletion')
+  return data
+}
+
+/**
+ * Request account deletion; a confirmation link is sent to the account email
+ * @param reason - Optional reason
+ */
+export async function requestDeletion(reason: string = ''): Promise<AccountDeletionRequest> {
+  const { data } = await apiClient.post<AccountDeletionRequest>('/user/account-deletion', { reason })
+  return data
+}
+
+/**
+ * Cancel a deletion request that has not been confirmed yet
+ */
+export async function cancelDeletion(): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>('/user/account-deletion')
+  return data
+}
+
+/**
+ * Confirm account deletion with the emailed token (no authentication required)
+ * @param token - Confirmation token from the email link
+ */
+export async function confirmDeletion(token: string): Promise<{ scheduled_for: string }> {
+  const { data } = await apiClient.post<{ scheduled_for: string }>('/auth/account-deletion/confirm', { token })
+  return data
+}
+
+export const userDataAPI = {
+  listExports,
+  requestExport,
+  downloadExport,
+  getDeletionStatus,
+  requestDeletion,
+  cancelDeletion,
+  confirmDeletion
+}
+
+export default userDataAPI
@@ -0,0 +1,96 @@
+/**
+ * Passkey (WebAuthn) API endpoints
+ * Handles passkey management and two-factor recovery codes
+ */
+
+import { apiClient } from './client'
+import type {
+  WebAuthnCredential,
+  WebAuthnRegistrationBegin,
+  WebAuthnAttestationResponse,
+  IdentityVerificationRequest
+} from '@/types'
+
+/**
+ * List passkeys registered by the current user
+ */
+export async function listCredentials(): Promise<WebAuthnCredential[]> {
+  const { data } = await apiClient.get<WebAuthnCredential[]>('/user/webauthn/credentials')
+  return data
+}
+
+/**
+ * Start passkey registration
+ * @param request - Email code or password depending on verification method
+ * @returns Session token and creation options for navigator.credentials.create
+ */
+export async function beginRegistration(request: IdentityVerificationRequest): Promise<WebAuthnRegistrationBegin> {
+  const { data } = await apiClient.post<WebAuthnRegistrationBegin>('/user/webauthn/register/begin', request)
+  return data
+}
+
+/**
+ * Complete passkey registration
+ * @param sessionToken - Session token from beginRegistration
+ * @param name - Display name for the passkey
+ * @param credential - Serialized authenticator response
+ */
+export async function finishRegistration(
+  sessionToken: string,
+  name: string,
+  credential: WebAuthnAttestationResponse
+): Promise<WebAuthnCredential> {
+  const { data } = await apiClient.post<WebAuthnCredential>('/user/webauthn/register/finish', {
+    session_token: sessionToken,
+    name,
+    credential
+  })
+  return data
+}
+
+/**
+ * Rename a passkey
+ */
+export async function renameCredential(id: number, name: string): Promise<{ success: boolean }> {
+  const { data } = await apiClient.put<{ success: boolean }>(`/user/webauthn/credentials/${id}`, { name })
+  return data
+}
+
+/**
+ * Remove a passkey
+ * @param request - Email code or password depending on verification method
+ */
+export async function deleteCredential(id: number, request: IdentityVerificationRequest): Promise<{ success: boolean }> {
+  const { data } = await apiClient.delete<{ success: boolean }>(`/user/webauthn/credentials/${id}`, { data: request })
+  return data
+}
+
+/**
+ * Get the number of unused recovery codes
+ */
+export async function getRecoveryCodeStatus(): Promise<{ remaining: number }> {
+  const { data } = await apiClient.get<{ remaining: number }>('/user/recovery-codes')
+  return data
+}
+
+/**
+ * Generate a new set of recovery codes (previous codes are invalidated)
+ * @param request - Email code or password depending on verification method
+ * @returns Plaintext codes, shown only once
+ */
+export async function generateRecoveryCodes(request: IdentityVerificationRequest): Promise<{ codes: string[] }> {
+  const { data } = await apiClient.post<{ codes: string[] }>('/user/recovery-codes', request)
+  return data
+}
+
+export const webauthnAPI = {
+  listCredentials,
+  beginRegistration,
+  finishRegistration,
+  renameCredential,
+  deleteCredential,
+  getRecoveryCodeStatus,
+  generateRecoveryCodes
+}
+
+export default webauthnAPI
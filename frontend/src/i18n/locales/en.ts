@@ -270,6 +270,12 @@ export default {
     redeemCodes: 'Redeem Codes',
     ops: 'Ops',
     promoCodes: 'Promo Codes',
+    apiKeyAbuse: 'Key Abuse',
+    spendGuard: 'Spend Guard',
+    referrals: 'Referrals',
+    subscriptionPlans: 'Subscription Plans',
+    payments: 'Payments',
+    referral: 'Invite Friends',
     settings: 'Settings',
     myAccount: 'My Account',
     lightMode: 'Light Mode',
@@ -290,6 +296,8 @@ export default {
     signInToAccount: 'Sign in to your account to continue',
     signIn: 'Sign In',
     signingIn: 'Signing in...',
+    signInWithPasskey: 'Sign in with passkey',
+    passkeyLoginFailed: 'Passkey sign-in failed',
     createAccount: 'Create Account',
     signUpToStart: 'Sign up to start using {siteName}',
     signUp: 'Sign up',
@@ -310,6 +318,10 @@ export default {
     passwordRequired: 'Password is required',
     passwordMinLength: 'Password must be at least 6 characters',
     loginFailed: 'Login failed. Please check your credentials and try again.',
+    loginLocked: 'Too many failed sign-in attempts. Sign-in is locked, please try again in about {minutes} minute(s).',
+    loginThrottled: 'Too many failed attempts. Please wait {seconds} second(s) before trying again.',
+    loginCaptchaRequired: 'Please complete the verification before signing in.',
+    referredByCode: 'You were invited with code {code}',
     registrationFailed: 'Registration failed. Please try again.',
     loginSuccess: 'Login successful! Welcome back.',
     accountCreatedSuccess: 'Account created successfully! Welcome to {siteName}.',
@@ -334,6 +346,9 @@ export default {
     promoCodeDisabled: 'This promo code is disabled',
     promoCodeMaxUsed: 'This promo code has reached its usage limit',
     promoCodeAlreadyUsed: 'You have already used this promo code',
+    promoCodeValidPercent: 'Valid! You will receive an extra {percent}% on your next top-up',
+    promoCodeNotStarted: 'This promo code is not active yet',
+    promoCodeNotForRegister: 'This promo code cannot be used during registration',
     promoCodeValidating: 'Promo code is being validated, please wait',
     promoCodeInvalidCannotRegister: 'Invalid promo code. Please check and try again or clear the promo code field',
     invitationCodeLabel: 'Invitation Code',
@@ -352,6 +367,14 @@ export default {
       callbackMissingToken: 'Missing login token, please try again.',
       backToLogin: 'Back to Login'
     },
+    oidc: {
+      signIn: 'Continue with {name}',
+      callbackTitle: 'Signing you in',
+      callbackProcessing: 'Completing login, please wait...',
+      callbackHint: 'If you are not redirected automatically, go back to the login page and try again.',
+      callbackMissingToken: 'Missing login token, please try again.',
+      backToLogin: 'Back to Login'
+    },
     oauth: {
       code: 'Code',
       state: 'State',
@@ -563,11 +586,13 @@ export default {
     currentExpiration: 'Current expiration',
     expiresAt: 'Expires',
     noExpiration: 'Never',
+    suspendedHint: 'This key has been suspended for suspected sharing or unusual spending. Please contact the administrator to restore it.',
     status: {
       active: 'Active',
       inactive: 'Inactive',
       quota_exhausted: 'Quota Exhausted',
       expired: 'Expired',
+      suspended: 'Suspended',
     },
   },
 
@@ -628,8 +653,98 @@ export default {
     userAgent: 'User-Agent'
   },
 
+  // Admin impersonation banner
+  impersonation: {
+    banner: 'Viewing as {user} (admin: {admin})',
+    expiresAt: 'ends at {time}',
+    exit: 'Exit'
+  },
+
+  // Referral
+
+  referral: {
+    title: 'Invite Friends',
+    description: 'Share your invite link and earn rewards when invited users use the service',
+    disabled: 'The referral program is not enabled.',
+    failedToLoad: 'Failed to load referral data',
+    yourLink: 'Your invite link',
+    yourCode: 'Invite code',
+    copyLink: 'Copy link',
+    linkCopied: 'Invite link copied',
+    termsBonus: 'Earn ${amount} for each invited user once they have spent ${minSpend}.',
+    termsCommission: 'Earn {percent}% of what invited users spend from their balance.',
+    termsCommissionDays: 'Earn {percent}% of what invited users spend from their balance during their first {days} days.',
+    termsCap: 'Rewards from a single invited user are capped at ${amount}.',
+    commissionBase: 'spend ${amount}',
+    noReferrals: 'No invited users yet',
+    noRewards: 'No rewards yet',
+    tabs: {
+      referrals: 'Invited users',
+      rewards: 'Rewards'
+    },
+    stats: {
+      totalReferrals: 'Invited users',
+      totalRewards: 'Total rewards',
+      signupBonusTotal: 'Signup bonuses',
+      commissionTotal: 'Commission'
+    },
+    status: {
+      active: 'Active',
+      held: 'Under review',
+      blocked: 'Not eligible'
+    },
+    rewardKinds: {
+      signup_bonus: 'Signup bonus',
+      commission: 'Commission'
+    }
+  },
+
   // Redeem
   redeem: {
+    promo: {
+      title: 'Promo Code',
+      description: 'Have a promo code? Apply it here for bonus balance.',
+      placeholder: 'Enter promo code',
+      apply: 'Apply',
+      history: 'Recent promo codes',
+      pendingTitle: '{code}: +{percent}% on your next top-up',
+      pendingHint: 'The bonus is added automatically when you next redeem a balance code.',
+      pendingHintCapped: 'The bonus (up to ${max}) is added automatically when you next redeem a balance code.',
+      statusPending: 'Pending +{percent}%',
+      applied: 'Promo code applied: +${amount}',
+      pendingApplied: 'Promo code applied: +{percent}% on your next top-up',
+      errorScope: 'This promo code can only be used during registration',
+      errorNewUsersOnly: 'This promo code is only available to new users',
+      errorGroupRequired: 'This promo code requires an active subscription',
+      errorPending: 'You already have a pending top-up bonus'
+    },
+    payment: {
+      title: 'Online Top-up',
+      description: 'Add balance instantly with an online payment.',
+      amount: 'Amount (USD)',
+      amountHint: 'Between ${min} and ${max}',
+      method: 'Payment method',
+      payPreview: 'You will pay {amount}',
+      payButton: 'Pay now',
+      redirecting: 'Redirecting...',
+      recentOrders: 'Recent top-ups',
+      continuePay: 'Continue',
+      returnPaid: 'Payment received: +${amount} added to your balance',
+      returnPending: 'Waiting for payment confirmation...',
+      returnNotPaid: 'This payment was not completed ({status})',
+      errorUnavailable: 'Online payment is currently unavailable',
+      errorAmount: 'The amount is outside the allowed range',
+      errorTooManyPending: 'You have too many unpaid orders; complete them or wait for them to expire',
+      errorCheckout: 'Failed to create the payment, please try again later',
+      status: {
+        pending: 'Unpaid',
+        paid: 'Paid',
+        partially_refunded: 'Partially refunded',
+        refunded: 'Refunded',
+        expired: 'Expired',
+        failed: 'Failed'
+      }
+    },
     title: 'Redeem Code',
     description: 'Enter your redeem code to add balance or increase concurrency',
     currentBalance: 'Current Balance',
@@ -656,6 +771,9 @@ export default {
     balanceAddedRedeem: 'Balance Added (Redeem)',
     balanceAddedAdmin: 'Balance Added (Admin)',
     balanceDeductedAdmin: 'Balance Deducted (Admin)',
+    balanceAddedPayment: 'Balance Added (Online Payment)',
+    balanceRefundedPayment: 'Balance Deducted (Payment Refund)',
+    onlinePayment: 'Online Payment',
     concurrencyAddedRedeem: 'Concurrency Added (Redeem)',
     concurrencyAddedAdmin: 'Concurrency Added (Admin)',
     concurrencyReducedAdmin: 'Concurrency Reduced (Admin)',
@@ -736,9 +854,107 @@ export default {
       sendCode: 'Send Code',
       codeSent: 'Verification code sent to your email',
       sendCodeFailed: 'Failed to send verification code'
+    },
+    passkeys: {
+      title: 'Passkeys',
+      description: 'Use Touch ID, Windows Hello or a security key as a second factor, or to sign in without a password',
+      count: '{count} passkey(s) registered',
+      notSupported: 'This browser does not support passkeys',
+      add: 'Add Passkey',
+      addTitle: 'Add Passkey',
+      addHint: 'Verify your identity, then follow the browser prompt to create a passkey',
+      addSuccess: 'Passkey added',
+      addFailed: 'Failed to add passkey',
+      cancelled: 'The passkey prompt was cancelled or timed out',
+      name: 'Name',
+      namePlaceholder: 'e.g. MacBook Touch ID',
+      rename: 'Rename',
+      createdAt: 'Added',
+      lastUsedAt: 'Last used',
+      deleteTitle: 'Remove Passkey',
+      deleteHint: 'Remove "{name}"? It can no longer be used to sign in.',
+      deleteSuccess: 'Passkey removed',
+      loginHint: 'Use a passkey registered to your account to continue',
+      loginWithPasskey: 'Use Passkey',
+      useMethod: {
+        passkey: 'Use a passkey',
+        totp: 'Use authenticator app',
+        recovery: 'Use a recovery code'
+      }
+    },
+    recoveryCodes: {
+      title: 'Recovery Codes',
+      remaining: '{count} unused recovery code(s) remaining',
+      none: 'No recovery codes yet. Generate them in case you lose your authenticator or passkey',
+      generate: 'Generate Codes',
+      regenerate: 'Regenerate',
+      generateTitle: 'Generate Recovery Codes',
+      generateHint: 'Any previously generated codes will stop working',
+      saveWarning: 'Save these codes somewhere safe. Each code can be used once and they will not be shown again.',
+      copy: 'Copy Codes',
+      done: 'I have saved them',
+      loginHint: 'Enter one of your recovery codes'
+    },
+    sessions: {
+      title: 'Active Sessions',
+      description: 'Devices currently signed in to your account. Sign out any you do not recognize.',
+      empty: 'No active sessions',
+      current: 'This device',
+      signedInAt: 'signed in',
+      lastActive: 'active',
+      revoke: 'Sign out',
+      signOut: 'Sign out',
+      revokeSuccess: 'Session signed out',
+      revokeAll: 'Sign out everywhere',
+      revokeAllConfirm: 'Sign out all devices, including this one? You will need to log in again.'
+    },
+    dataExport: {
+      title: 'Your Data',
+      description: 'Download a copy of your profile, API key metadata, subscriptions, redeem history and usage records. Archives are available for 7 days.',
+      request: 'Request Export',
+      requested: 'Export requested. It will be ready for download shortly.',
+      empty: 'No exports yet',
+      download: 'Download',
+      expiresAt: 'available until',
+      preparingHint: 'Preparing your archive, refresh the page in a few minutes',
+      failedHint: 'The export failed, please request a new one',
+      status: {
+        pending: 'Queued',
+        processing: 'Preparing',
+        completed: 'Ready',
+        failed: 'Failed',
+        expired: 'Expired'
+      }
+    },
+    accountDeletion: {
+      title: 'Delete Account',
+      description: 'Permanently delete your account. After confirming by email, your account, API keys and sessions are disabled immediately and your personal data is removed after {days} days.',
+      request: 'Delete Account',
+      adminHint: 'Admin accounts cannot be deleted from here.',
+      confirmMessage: 'We will send a confirmation link to your email. Once confirmed, your account is disabled immediately and deleted after {days} days. Consider exporting your data first.',
+      reason: 'Reason (optional)',
+      reasonPlaceholder: 'Let us know why you are leaving',
+      sendConfirmation: 'Send Confirmation Email',
+      emailSent: 'Confirmation email sent. Open the link within 24 hours to confirm.',
+      pendingHint: 'A confirmation link has been sent to your email. Your account stays active until you confirm.',
+      resend: 'Resend Link',
+      cancel: 'Cancel Request',
+      cancelled: 'Account deletion request cancelled'
     }
   },
 
+  // Account deletion confirmation page
+  accountDeletion: {
+    title: 'Confirm Account Deletion',
+    hint: 'You requested to delete your account',
+    warning: 'After confirming, your account, API keys and all sign-in sessions are disabled immediately. Your personal data is permanently removed after the grace period; until then an administrator can still restore the account.',
+    confirm: 'Delete My Account',
+    confirmed: 'Account deletion confirmed',
+    confirmedHint: 'Your account has been disabled and will be permanently deleted on {date}.',
+    invalidLink: 'Invalid or expired link',
+    invalidLinkHint: 'This confirmation link is invalid or has expired. Request account deletion again from your profile page.'
+  },
+
   // Empty States
   empty: {
     noData: 'No data found'
@@ -767,6 +983,46 @@ export default {
     jumpAction: 'Go'
   },
 
+  // Public status page
+  status: {
+    defaultTitle: '{site} Status',
+    unavailable: 'The status page is not available.',
+    updatedAt: 'Updated {time}',
+    startedAt: 'Started {time}',
+    noComponents: 'No data yet.',
+    component: 'Component',
+    availability1h: 'Availability (1h)',
+    availability24h: 'Availability (24h)',
+    latencyP50: 'Median latency',
+    state: 'Status',
+    recentIncidents: 'Recently resolved',
+    overall: {
+      operational: 'All systems operational',
+      degraded: 'Degraded performance',
+      partial_outage: 'Partial outage',
+      major_outage: 'Major outage',
+      no_data: 'No data'
+    },
+    level: {
+      operational: 'Operational',
+      degraded: 'Degraded',
+      partial_outage: 'Partial outage',
+      major_outage: 'Major outage',
+      no_data: 'No data'
+    },
+    impact: {
+      minor: 'Minor',
+      major: 'Major',
+      critical: 'Critical'
+    },
+    incidentStatus: {
+      investigating: 'Investigating',
+      identified: 'Identified',
+      monitoring: 'Monitoring',
+      resolved: 'Resolved'
+    }
+  },
+
   // Errors
   errors: {
     somethingWentWrong: 'Something went wrong',
@@ -962,12 +1218,85 @@ export default {
       },
       // Balance History
       balanceHistory: 'Recharge History',
+      sessions: {
+        title: 'Login Sessions',
+        empty: 'No active sessions',
+        createdAt: 'Signed in',
+        lastUsedAt: 'Last active',
+        expiresAt: 'Expires',
+        revoke: 'Revoke',
+        revokeAll: 'Revoke All',
+        revokeSuccess: 'Session revoked',
+        revokeAllSuccess: 'All sessions revoked'
+      },
+      loginLocks: {
+        title: 'Locked Sign-ins',
+        description: 'Accounts and IPs temporarily locked after repeated failed password attempts. Unlocking also clears the failure counter.',
+        empty: 'No locked accounts or IPs',
+        lockedUntil: 'Locked until',
+        unlock: 'Unlock',
+        unlockSuccess: 'Unlocked',
+        scopes: {
+          account: 'Account',
+          ip: 'IP'
+        }
+      },
+      impersonate: {
+        action: 'Act as User',
+        title: 'Act as User',
+        notice: 'You will see the console exactly as this user does. Changing the password, 2FA, passkeys or sessions and creating API keys are blocked. Every request is recorded with your admin account.',
+        reason: 'Reason',
+        reasonPlaceholder: 'e.g. ticket number or what you need to check',
+        duration: 'Duration (minutes)',
+        durationHint: '5-120 minutes. The session ends automatically and cannot be extended.',
+        start: 'Start',
+        started: 'Now acting as {email}'
+      },
+      impersonations: {
+        title: 'Impersonation Audit',
+        description: '"Act as user" sessions started by admins, with every request made during them.',
+        activeOnly: 'Active only',
+        empty: 'No impersonation sessions',
+        active: 'Active',
+        ended: 'Ended',
+        startedAt: 'Started',
+        endedAt: 'Ended',
+        expiresAt: 'Expires',
+        actionCount: '{count} requests',
+        viewActions: 'Requests',
+        hideActions: 'Hide',
+        moreActions: '{count} earlier requests not shown',
+        end: 'End Now',
+        endSuccess: 'Impersonation session ended'
+      },
+      accountDeletions: {
+        title: 'Account Deletions',
+        description: 'Self-service deletion requests. Scheduled accounts are disabled and anonymized when the grace period ends; restore them before then if needed.',
+        allStatuses: 'All statuses',
+        empty: 'No account deletion requests',
+        requestedAt: 'Requested',
+        confirmedAt: 'Confirmed',
+        scheduledFor: 'Deletes on',
+        restoredAt: 'Restored',
+        completedAt: 'Deleted',
+        keysDisabled: '{count} API keys disabled',
+        restore: 'Restore Account',
+        restoreSuccess: 'Account restored',
+        status: {
+          pending_confirmation: 'Awaiting confirmation',
+          scheduled: 'Scheduled',
+          cancelled: 'Cancelled',
+          restored: 'Restored',
+          completed: 'Deleted'
+        }
+      },
       balanceHistoryTip: 'Click to open recharge history',
       balanceHistoryTitle: 'User Recharge & Concurrency History',
       noBalanceHistory: 'No records found for this user',
       allTypes: 'All Types',
       typeBalance: 'Balance (Redeem)',
       typeAdminBalance: 'Balance (Admin)',
+      typePayment: 'Balance (Online Payment)',
       typeConcurrency: 'Concurrency (Redeem)',
       typeAdminConcurrency: 'Concurrency (Admin)',
       typeSubscription: 'Subscription',
@@ -2291,10 +2620,344 @@ export default {
       deleteConfirm: 'Are you sure you want to delete this announcement? This action cannot be undone.'
     },
 
+    // API Key Abuse Detection
+    apiKeyAbuse: {
+      title: 'API Key Abuse Detection',
+      description: 'Detect shared or abused API keys from IP spread, concurrency and client patterns',
+      allStatus: 'All Status',
+      userIdFilter: 'User ID',
+      scanNow: 'Scan Now',
+      scanning: 'Scanning...',
+      scanResult: 'Scanned {scanned} keys: {flagged} flagged, {actioned} actioned',
+      scanFailed: 'Scan failed',
+      settings: 'Detection Settings',
+      settingsSaved: 'Settings saved',
+      settingsSaveFailed: 'Failed to save settings',
+      failedToLoad: 'Failed to load abuse flags',
+      viewDetail: 'View details',
+      detailTitle: 'Suspicious API Key',
+      keyStatus: 'Key status',
+      owner: 'Owner',
+      ownerNotifiedAt: 'Owner notified at {time}',
+      firstDetected: 'First detected',
+      breakdown: 'Score breakdown',
+      topIps: 'Top IP addresses',
+      topUserAgents: 'Top user agents',
+      rateLimitRpm: 'Rate limit (requests/min)',
+      note: 'Note',
+      reviewedAt: 'Reviewed at {time}',
+      reviewSuccess: 'Action applied',
+      reviewFailed: 'Failed to apply action',
+      auto: 'auto',
+      signalsSummary: '{ips} IPs · {networks} networks · {concurrent} concurrent · {agents} clients',
+      requestsSummary: '{requests} requests · active {hours}/{window}h',
+      columns: {
+        apiKey: 'API Key / Owner',
+        score: 'Score',
+        signals: 'Signals',
+        status: 'Status',
+        lastDetected: 'Last Detected',
+        actions: 'Actions'
+      },
+      status: {
+        open: 'Open',
+        dismissed: 'Dismissed',
+        resolved: 'Resolved'
+      },
+      action: {
+        none: 'No action',
+        rate_limited: 'Rate limited ({rpm}/min)',
+        suspended: 'Suspended'
+      },
+      actions: {
+        dismiss: 'Dismiss (false positive)',
+        restore: 'Lift restrictions',
+        rateLimit: 'Rate limit',
+        suspend: 'Suspend key'
+      },
+      signals: {
+        ips: 'Distinct IPs',
+        networks: 'Networks',
+        concurrency: 'Concurrent IPs (5 min)',
+        userAgents: 'Client user agents',
+        activeHours: 'Active hours'
+      },
+      enabled: 'Enable scheduled detection',
+      enabledHint: 'Manual scans and rate limits on flagged keys work even when disabled',
+      scanIntervalMinutes: 'Scan interval (minutes)',
+      windowHours: 'Window (hours)',
+      minRequests: 'Min requests in window',
+      thresholds: 'Full-score thresholds',
+      thresholdsHint: 'Each signal scores 0 for a single source and reaches its full weight at the threshold (IPs 35, networks 25, concurrency 20, clients 10, round-the-clock activity 10).',
+      flagScore: 'Flag score',
+      autoAction: 'Automatic action',
+      actionScore: 'Action score',
+      dismissSuppressDays: 'Ignore dismissed keys for (days)',
+      notifyOwner: 'Email the key owner on action',
+      autoActions: {
+        none: 'Flag only',
+        rate_limit: 'Rate limit',
+        suspend: 'Suspend'
+      }
+    },
+
+    // Spend Guard
+    subscriptionPlans: {
+      title: 'Subscription Plans',
+      description: 'Sell subscription groups as plans users can buy, renew and change with their balance',
+      tabs: {
+        plans: 'Plans',
+        orders: 'Orders'
+      },
+      create: 'Create Plan',
+      edit: 'Edit Plan',
+      delete: 'Delete Plan',
+      deleteConfirm: 'Delete plan "{name}"? Existing subscriptions are not affected, and auto-renewals using it will fail.',
+      group: 'Subscription Group',
+      groupHint: 'Only groups with subscription billing can be sold as plans',
+      groupRequired: 'Please select a subscription group',
+      name: 'Name',
+      planDescription: 'Description',
+      price: 'Price (USD)',
+      validityDays: 'Validity (days)',
+      validityDaysValue: '{days} days',
+      renewalPolicy: 'Renewal',
+      sortOrder: 'Sort Order',
+      enabled: 'On Sale',
+      enabledHint: 'Disabled plans are hidden from users and are not used for auto-renewal',
+      policy: {
+        none: 'One-time',
+        manual: 'Manual renewal',
+        auto: 'Auto-renewal'
+      },
+      policyHint: {
+        none: 'Can only be bought when the user has no active subscription in this group',
+        manual: 'Users can buy it again to extend an active subscription',
+        auto: 'Users can also opt in to automatic renewal from their balance before expiry'
+      },
+      status: {
+        active: 'On Sale',
+        disabled: 'Disabled'
+      },
+      kind: {
+        purchase: 'Purchase',
+        renew: 'Renewal',
+        auto_renew: 'Auto-renewal',
+        change: 'Plan change'
+      },
+      allGroups: 'All Groups',
+      allKinds: 'All Types',
+      searchPlaceholder: 'Search email or plan...',
+      changedFrom: 'from {group}',
+      creditValue: 'credit ${amount}',
+      columns: {
+        name: 'Plan',
+        group: 'Group',
+        price: 'Price',
+        renewalPolicy: 'Renewal',
+        status: 'Status',
+        sortOrder: 'Sort',
+        actions: 'Actions',
+        user: 'User',
+        plan: 'Plan',
+        kind: 'Type',
+        amount: 'Charged',
+        expiresAt: 'Valid Until',
+        createdAt: 'Time'
+      },
+      created: 'Plan created',
+      updated: 'Plan updated',
+      deleted: 'Plan deleted',
+      failedToLoad: 'Failed to load plans',
+      failedToLoadOrders: 'Failed to load orders',
+      saveFailed: 'Failed to save plan',
+      deleteFailed: 'Failed to delete plan'
+    },
+    payments: {
+      title: 'Payments',
+      description: 'Online balance top-up orders, refunds and payment provider settings',
+      settings: 'Payment Settings',
+      searchPlaceholder: 'Search email, order or transaction no.',
+      allStatuses: 'All Statuses',
+      allProviders: 'All Providers',
+      failedToLoad: 'Failed to load payment orders',
+      failedToLoadSettings: 'Failed to load payment settings',
+      columns: {
+        orderNo: 'Order',
+        user: 'User',
+        provider: 'Provider',
+        amount: 'Amount',
+        status: 'Status',
+        createdAt: 'Created',
+        actions: 'Actions'
+      },
+      provider: {
+        stripe: 'Stripe',
+        generic: 'Generic Gateway'
+      },
+      status: {
+        pending: 'Pending',
+        paid: 'Paid',
+        partially_refunded: 'Partially Refunded',
+        refunded: 'Refunded',
+        expired: 'Expired',
+        failed: 'Failed'
+      },
+      paidAt: 'Paid {time}',
+      refundedValue: 'Refunded {amount} (-${credit} balance)',
+      refund: 'Refund',
+      refundTitle: 'Refund Order',
+      refundRemaining: 'Order {order}: {amount} can still be refunded.',
+      refundAmount: 'Refund amount ({currency})',
+      refundAmountHint: 'Leave 0 to refund the full remaining amount.',
+      refundReason: 'Reason',
+      refundOffline: 'Record offline refund',
+      refundOfflineHint: 'Only record the refund and deduct balance; the money was returned outside this system.',
+      refundDeductHint: 'The credited balance and promo bonus are deducted in proportion to the refunded amount.',
+      refunded: 'Refund completed',
+      refundFailed: 'Refund failed',
+      refundExceedsBalance: "The user's balance is lower than the amount this refund would deduct",
+      refundUnsupported: 'This provider has no refund API configured; record an offline refund instead',
+      enabled: 'Enable online payment',
+      enabledHint: 'Show the top-up card on the Redeem page',
+      publicBaseUrl: 'Public site URL',
+      publicBaseUrlHint: 'Used to build the webhook URL and the page users return to after paying',
+      minAmount: 'Min amount (USD)',
+      maxAmount: 'Max amount (USD)',
+      orderExpireMinutes: 'Order expiry (minutes)',
+      webhookUrl: 'Webhook URL: {url}',
+      secretKey: 'Secret key',
+      webhookSecret: 'Webhook signing secret',
+      secretConfigured: 'Configured (leave blank to keep)',
+      currency: 'Currency',
+      exchangeRate: 'Exchange rate',
+      exchangeRateHint: 'Exchange rate is the amount charged in the payment currency for $1 of balance.',
+      apiBase: 'API base URL',
+      displayName: 'Display name',
+      merchantId: 'Merchant ID',
+      gatewayUrl: 'Checkout URL',
+      refundUrl: 'Refund API URL (optional)',
+      refundUrlHint: 'Without a refund API, refunds can only be recorded as offline refunds',
+      signatureHint: 'Parameters are signed with HMAC-SHA256 over the sorted non-empty k=v pairs joined by &',
+      settingsSaved: 'Payment settings saved',
+      saveFailed: 'Failed to save payment settings'
+    },
+    referrals: {
+      title: 'Referral Program',
+      description: 'Configure referral rewards and review flagged referrals',
+      allStatus: 'All Status',
+      searchPlaceholder: 'Search email or code',
+      flaggedOnly: 'Flagged only',
+      settings: 'Program Settings',
+      settingsSaved: 'Settings saved',
+      settingsSaveFailed: 'Failed to save settings',
+      failedToLoad: 'Failed to load referrals',
+      invitedBy: 'invited by {user}',
+      refereeSpend: 'spend ${amount} (${funded} from top-ups)',
+      bonusPaid: 'bonus paid',
+      approve: 'Approve',
+      block: 'Block',
+      approveTitle: 'Approve Referral',
+      blockTitle: 'Block Referral',
+      approveConfirm: 'Approve the referral of {user}? Rewards accumulated while under review will be paid at the next settlement.',
+      blockConfirm: 'Block the referral of {user}? No further rewards will be paid; rewards already paid are kept.',
+      note: 'Note (optional)',
+      approveSuccess: 'Referral approved',
+      blockSuccess: 'Referral blocked',
+      reviewFailed: 'Failed to update referral',
+      enabled: 'Enable referral program',
+      enabledHint: 'Users get an invite link; rewards are credited to their balance hourly',
+      signupBonusUsd: 'Signup bonus (USD)',
+      signupBonusUsdHint: 'Paid to the referrer per invited user; 0 disables it',
+      signupBonusMinSpendUsd: 'Bonus spend threshold (USD)',
+      signupBonusMinSpendUsdHint: 'The invited user must spend this much from balance before the bonus is paid',
+      commissionPercent: 'Commission (%)',
+      commissionPercentHint: "Share of the invited user's balance spend; 0 disables it",
+      commissionDays: 'Commission period (days)',
+      perRefereeCapUsd: 'Cap per invited user (USD)',
+      perReferrerCapUsd: 'Cap per referrer (USD)',
+      zeroUnlimited: '0 means unlimited',
+      holdFlagged: 'Hold flagged referrals for review',
+      holdFlaggedHint: 'Referrals sharing an IP or email domain with the referrer pay nothing until approved',
+      ignoredEmailDomains: 'Ignored email domains',
+      ignoredEmailDomainsHint: 'Public email providers excluded from the same-domain check, one per line',
+      status: {
+        active: 'Active',
+        held: 'Held',
+        blocked: 'Blocked'
+      },
+      flags: {
+        same_ip: 'Same IP',
+        shared_signup_ip: 'Shared signup IP',
+        same_email_domain: 'Same email domain'
+      },
+      columns: {
+        users: 'Invited User',
+        risk: 'Risk',
+        rewards: 'Rewards',
+        status: 'Status',
+        createdAt: 'Created',
+        actions: 'Actions'
+      }
+    },
+
+    spendGuard: {
+      title: 'Spend Guard',
+      description: 'Temporarily suspend API keys whose spending suddenly far exceeds their own history',
+      allStatus: 'All Status',
+      userIdFilter: 'User ID',
+      settings: 'Guard Settings',
+      settingsSaved: 'Settings saved',
+      settingsSaveFailed: 'Failed to save settings',
+      failedToLoad: 'Failed to load suspensions',
+      spendSummary: '${spend} in 10 min (threshold ${threshold})',
+      baselineSummary: 'historical peak ${baseline}',
+      until: 'Until {time}',
+      untilLifted: 'Until lifted by an administrator',
+      lift: 'Lift',
+      liftTitle: 'Lift Suspension',
+      liftConfirm: 'Restore API key {key} now? The related ops alert will be marked as resolved.',
+      liftSuccess: 'Suspension lifted',
+      liftFailed: 'Failed to lift suspension',
+      note: 'Note',
+      columns: {
+        apiKey: 'API Key / User',
+        trigger: 'Trigger',
+        status: 'Status',
+        createdAt: 'Suspended At',
+        actions: 'Actions'
+      },
+      status: {
+        active: 'Suspended',
+        expired: 'Expired',
+        lifted: 'Lifted'
+      },
+      scopes: {
+        api_key: 'Key spend',
+        user: 'User spend'
+      },
+      rules: {
+        multiplier: 'Multiple of peak',
+        absolute: 'Absolute amount'
+      },
+      enabled: 'Enable spend guard',
+      enabledHint: 'Checks the last 10 minutes of spend for every key and user once per minute',
+      multiplier: 'Multiplier',
+      multiplierHint: 'Trigger when 10-minute spend exceeds this multiple of the historical 10-minute peak; 0 disables',
+      absoluteUsd: 'Absolute limit (USD / 10 min)',
+      absoluteUsdHint: 'Trigger when 10-minute spend reaches this amount; 0 disables',
+      baselineDays: 'Baseline period (days)',
+      baselineFloorUsd: 'Baseline floor (USD)',
+      baselineFloorUsdHint: 'Minimum baseline used for new or low-spend keys',
+      suspendMinutes: 'Suspension duration (minutes)',
+      suspendMinutesHint: '0 means keys stay suspended until an administrator lifts them',
+      notifyUser: 'Email the user when keys are suspended'
+    },
+
     // Promo Codes
     promo: {
       title: 'Promo Code Management',
-      description: 'Create and manage registration promo codes',
+      description: 'Create and manage promo codes for registration and top-up bonuses',
       createCode: 'Create Promo Code',
       editCode: 'Edit Promo Code',
       deleteCode: 'Delete Promo Code',
@@ -2347,7 +3010,33 @@ export default {
       failedToCreate: 'Failed to create promo code',
       failedToUpdate: 'Failed to update promo code',
       failedToDelete: 'Failed to delete promo code',
-      failedToLoadUsages: 'Failed to load usage records'
+      failedToLoadUsages: 'Failed to load usage records',
+      // Bonus type and eligibility rules
+      bonusType: 'Bonus Type',
+      bonusTypeFixed: 'Fixed balance',
+      bonusTypePercent: 'Percentage of next top-up',
+      bonusPercent: 'Bonus Percent (%)',
+      maxBonusAmount: 'Max Bonus ($)',
+      percentHint: 'The bonus is credited when the user next tops up balance with a redeem code.',
+      percentBonusLabel: '+{percent}% next top-up',
+      scope: 'Usable At',
+      scopeRegister: 'Registration',
+      scopePanel: 'User panel',
+      scopeAny: 'Registration & panel',
+      perUserLimit: 'Uses Per User',
+      perUserLimitShort: '{limit} per user',
+      newUsersOnly: 'New users only',
+      newUsersOnlyBadge: 'New users',
+      newUserDays: 'Days after sign-up',
+      newUserDaysHint: 'Users can redeem it from the panel within this many days after registering. 0 = registration only.',
+      requiredGroup: 'Required Subscription',
+      noGroupRequired: 'No requirement',
+      groupPrefix: 'Group #{id}',
+      startsAt: 'Starts At',
+      startsAtShort: 'Starts {time}',
+      statusScheduled: 'Scheduled',
+      usagePending: 'Pending {percent}%',
+      usageTopUp: 'on ${amount} top-up'
     },
 
     // Usage Records
@@ -2402,6 +3091,8 @@ export default {
       errorSearch: 'Error Search',
       errorSearchPlaceholder: 'Search error content...',
       viewDetails: 'View Details',
+      viewCapture: 'Capture',
+      viewCaptureHint: 'View the captured request/response (only when capture rules matched this request)',
       errorDetails: 'Error Details',
       unknownError: 'Unknown Error',
       upstream: 'Upstream',
@@ -2810,11 +3501,19 @@ export default {
         loading: 'Loading...',
         empty: 'No alert events',
         loadFailed: 'Failed to load alert events',
+        ackBadge: 'ACK',
         status: {
           firing: 'FIRING',
           resolved: 'RESOLVED',
           manualResolved: 'MANUAL RESOLVED'
         },
+        timelineAction: {
+          fired: 'Fired',
+          notified: 'Notified',
+          repeatNotified: 'Repeat notification',
+          escalated: 'Escalated',
+          acknowledged: 'Acknowledged'
+        },
         detail: {
           title: 'Alert Detail',
           loading: 'Loading detail...',
@@ -2823,6 +3522,13 @@ export default {
           manualResolve: 'Mark as Resolved',
           manualResolvedSuccess: 'Marked as manually resolved',
           manualResolvedFailed: 'Failed to mark as manually resolved',
+          publishFailed: 'Failed to update status page visibility',
+          publishedSuccess: 'Published on the status page',
+          unpublishedSuccess: 'Removed from the status page',
+          statusPage: 'Status page',
+          publicSummaryPlaceholder: 'Public summary (defaults to the alert title)',
+          publish: 'Publish',
+          unpublish: 'Unpublish',
           silence: 'Ignore Alert',
           silenceSuccess: 'Alert silenced',
           silenceFailed: 'Failed to silence alert',
@@ -2835,7 +3541,22 @@ export default {
           historyTitle: 'History',
           historyHint: 'Recent events with same rule + dimensions',
           historyLoading: 'Loading history...',
-          historyEmpty: 'No history'
+          historyEmpty: 'No history',
+          acknowledge: 'Acknowledge',
+          acknowledgeSuccess: 'Alert acknowledged',
+          acknowledgeFailed: 'Failed to acknowledge alert',
+          acknowledgedAt: 'Acknowledged at {time}',
+          assignee: 'Assignee',
+          assigneePlaceholder: 'Assignee (optional)',
+          ackNotePlaceholder: 'Note (optional)',
+          escalation: 'Escalation',
+          escalationLevel: 'Level {level}',
+          notifications: 'Notifications',
+          lastNotifiedAt: 'last at {time}',
+          timelineTitle: 'Timeline',
+          timelineHint: 'Notifications, escalations, acknowledgement and resolution of this event',
+          timelineLoading: 'Loading timeline...',
+          timelineEmpty: 'No timeline entries'
         },
         table: {
           time: 'Time',
@@ -2871,7 +3592,8 @@ export default {
         metricGroups: {
           system: 'System Metrics',
           group: 'Group-level Metrics (requires group_id)',
-          account: 'Account-level Metrics'
+          account: 'Account-level Metrics',
+          slo: 'SLO Metrics (requires an SLO)'
         },
         metrics: {
           successRate: 'Success Rate (%)',
@@ -2888,7 +3610,17 @@ export default {
           accountRateLimitedCount: 'Rate-limited Accounts',
           accountErrorCount: 'Error Accounts (excluding temporarily unschedulable)',
           accountErrorRatio: 'Error Account Ratio (%)',
-          overloadAccountCount: 'Overloaded Accounts'
+          overloadAccountCount: 'Overloaded Accounts',
+          accountErrorRate: 'Per-Account Error Rate (%)',
+          durationP95: 'P95 Duration (ms)',
+          durationP99: 'P99 Duration (ms)',
+          firstTokenP95: 'P95 First Token (ms)',
+          firstTokenP99: 'P99 First Token (ms)',
+          requestsPerMinute: 'Requests per Minute',
+          tokensPerMinute: 'Tokens per Minute',
+          spendUsd: 'Spend (USD)',
+          sloBurnRate: 'SLO Burn Rate (x)',
+          sloErrorBudgetRemaining: 'SLO Error Budget Remaining (%)'
         },
         metricDescriptions: {
           successRate: 'Percentage of successful requests in the window (0-100).',
@@ -2905,12 +3637,36 @@ export default {
           accountRateLimitedCount: 'Number of rate-limited accounts within the window.',
           accountErrorCount: 'Number of error accounts within the window (excluding temporarily unschedulable).',
           accountErrorRatio: 'Error account ratio within the window (0-100).',
-          overloadAccountCount: 'Number of overloaded accounts within the window.'
+          overloadAccountCount: 'Number of overloaded accounts within the window.',
+          accountErrorRate: 'Highest per-account error rate within the window (0-100). Set filters.account_id to watch a single account.',
+          durationP95: 'P95 request duration of successful requests within the window.',
+          durationP99: 'P99 request duration of successful requests within the window.',
+          firstTokenP95: 'P95 time to first token of successful requests within the window.',
+          firstTokenP99: 'P99 time to first token of successful requests within the window.',
+          requestsPerMinute: 'Average requests (success + error) per minute within the window.',
+          tokensPerMinute: 'Average tokens consumed per minute within the window.',
+          spendUsd: 'Total spend (USD) within the window. Supports filters.user_id.',
+          sloBurnRate: 'Error budget burn rate within the window (1 = budget exhausted exactly at the end of the SLO window). With a short window, both windows must exceed the threshold.',
+          sloErrorBudgetRemaining: 'Remaining error budget over the SLO window (can be negative once exhausted).'
         },
         hints: {
           recommended: 'Recommended: operator {operator}, threshold {threshold}{unit}',
           groupRequired: 'This is a group-level metric; selecting a group (group_id) is required.',
-          groupOptional: 'Optional: limit the rule to a specific group via group_id.'
+          groupOptional: 'Optional: limit the rule to a specific group via group_id.',
+          anomaly: 'Compares the current window with the same UTC hour over the last N days (hourly pre-aggregation). Threshold is in σ for z-score or % for percentage deviation.',
+          sloBurnRate: 'Multi-window alert: the rule fires only when both the rule window and this short window burn faster than the threshold (e.g. 60m + 5m at 14.4x).'
+        },
+        operators: {
+          anomalyAbove: 'Anomaly: above baseline',
+          anomalyBelow: 'Anomaly: below baseline'
+        },
+        anomalyMethods: {
+          zscore: 'Z-score (σ)',
+          percent: 'Percentage deviation (%)'
+        },
+        sloObjectives: {
+          availability: 'Availability',
+          latency: 'First-token latency'
         },
         table: {
           name: 'Name',
@@ -2933,7 +3689,15 @@ export default {
           sustained: 'Sustained (samples)',
           cooldown: 'Cooldown (minutes)',
           enabled: 'Enabled',
-          notifyEmail: 'Send email notifications'
+          notifyEmail: 'Send email notifications',
+          publicStatus: 'Show on public status page',
+          publicStatusHint: 'Events fired by this rule are listed as incidents on the public status page',
+          anomalyMethod: 'Anomaly method',
+          baselineDays: 'Baseline days',
+          slo: 'SLO',
+          sloPlaceholder: 'Select an SLO',
+          sloObjective: 'Objective',
+          shortWindowMinutes: 'Short window (minutes, optional)'
         },
         validation: {
           title: 'Please fix the following issues',
@@ -2945,7 +3709,185 @@ export default {
           thresholdRequired: 'Threshold must be a number',
           windowRange: 'Window must be one of: 1, 5, 60 minutes',
           sustainedRange: 'Sustained must be between 1 and 1440 samples',
-          cooldownRange: 'Cooldown must be between 0 and 1440 minutes'
+          cooldownRange: 'Cooldown must be between 0 and 1440 minutes',
+          baselineDaysRange: 'Baseline days must be between 1 and 30',
+          sloIdRequired: 'An SLO must be selected for SLO metrics'
+        }
+      },
+      slo: {
+        title: 'SLOs & Error Budget',
+        empty: 'No SLOs defined',
+        loadFailed: 'Failed to load SLO status',
+        scopeAll: 'All traffic',
+        window: '{days}d window',
+        availability: 'Availability',
+        latency: 'First token ≤ {ms}ms',
+        target: 'Target',
+        actual: 'Actual',
+        budgetRemaining: 'Budget left',
+        burnRate: 'Burn rate',
+        burnRateWindow: '{minutes}m'
+      },
+      requestCapture: {
+        title: 'Request Captures',
+        configure: 'Capture rules',
+        settingsTitle: 'Request Capture Rules',
+        settingsHint: 'Capture request bodies and reconstructed responses of successful requests for selected API keys / users or a global sample. Captures are redacted, size-capped and deleted after the retention period.',
+        disabled: 'Capture disabled',
+        summary: 'Sampling {rate}% · {targets} targets · kept {hours}h',
+        empty: 'No captures in the last 24 hours',
+        loadFailed: 'Failed to load request captures',
+        saveSuccess: 'Capture rules saved',
+        saveFailed: 'Failed to save capture rules',
+        deleteSuccess: 'Capture deleted',
+        deleteFailed: 'Failed to delete capture',
+        deleteConfirmTitle: 'Delete capture',
+        deleteConfirmMessage: 'Delete this captured request/response?',
+        notCaptured: 'This request was not captured (no capture rule matched, or the capture has expired).',
+        showingLatest: 'Showing latest {shown} of {total}',
+        detailTitle: 'Captured Request',
+        view: 'View',
+        time: 'Time',
+        model: 'Model',
+        reason: 'Matched rule',
+        user: 'User',
+        account: 'Account',
+        path: 'Path',
+        expiresAt: 'Expires at',
+        requestBody: 'Request body',
+        responseBody: 'Response body',
+        truncated: 'truncated',
+        reasons: {
+          api_key: 'API key',
+          user: 'User',
+          sample: 'Sample'
+        },
+        formats: {
+          json: 'JSON',
+          sse_reconstructed: 'reconstructed from stream',
+          sse_raw: 'raw stream',
+          text: 'text'
+        },
+        validation: {
+          targetId: 'Each capture target needs a valid ID'
+        },
+        form: {
+          enabled: 'Enable capture',
+          sampleRate: 'Global sample rate (%)',
+          sampleUntil: 'Sample until (empty = no expiry)',
+          retentionHours: 'Retention (hours)',
+          maxCaptures: 'Max stored captures',
+          maxRequestKB: 'Max request body (KB)',
+          maxResponseKB: 'Max response body (KB)',
+          targets: 'Capture targets',
+          addTarget: 'Add target',
+          noTargets: 'No API key / user targets',
+          expiresAt: 'Expires at (empty = no expiry)',
+          note: 'Note',
+          redactKeys: 'Extra redacted JSON keys',
+          redactKeysHint: 'One per line; matching fields are replaced with [REDACTED]. Credentials are always redacted.',
+          redactPatterns: 'Redaction regex patterns',
+          redactPatternsHint: 'One regular expression per line; matches inside string values are replaced.'
+        }
+      },
+      shadow: {
+        title: 'Traffic Shadowing',
+        description: 'Duplicate sampled successful requests to a candidate account and compare the results with the primary responses. Shadow responses are never returned to clients or billed.',
+        create: 'New rule',
+        createTitle: 'New shadow rule',
+        editTitle: 'Edit shadow rule',
+        formHint: 'The candidate account does not need to belong to the group and may be unschedulable, but it must be active. Shadow requests occupy its concurrency slots and are skipped when it is full.',
+        empty: 'No shadow rules',
+        loadFailed: 'Failed to load shadow rules',
+        saveSuccess: 'Shadow rule saved',
+        saveFailed: 'Failed to save shadow rule',
+        deleteSuccess: 'Shadow rule deleted',
+        deleteFailed: 'Failed to delete shadow rule',
+        deleteConfirmTitle: 'Delete shadow rule',
+        deleteConfirmMessage: 'Delete this shadow rule and stop shadowing? Recorded comparison results are kept until retention cleanup.',
+        reportFailed: 'Failed to load comparison report',
+        name: 'Name',
+        descriptionLabel: 'Description',
+        group: 'Group',
+        groupId: 'Group ID',
+        shadowAccount: 'Shadow account',
+        shadowAccountId: 'Shadow account ID',
+        sampleRate: 'Sample rate',
+        sampleRatePercent: 'Sample rate (%)',
+        expiresAt: 'Expires at',
+        enabled: 'Enabled',
+        status: 'Status',
+        active: 'Active',
+        disabled: 'Disabled',
+        expired: 'Expired',
+        viewReport: 'Report',
+        hideReport: 'Hide report',
+        reportTitle: 'Primary vs shadow',
+        samples: '{count} samples',
+        noSamples: 'No shadow results in this time range',
+        metric: 'Metric',
+        primary: 'Primary',
+        shadow: 'Shadow',
+        durationDelta: 'p50 latency (shadow vs primary)',
+        outputTokensDelta: 'Output tokens (shadow vs primary)',
+        statusCodes: 'Shadow status codes',
+        noStatus: 'no response',
+        recent: 'Recent results',
+        time: 'Time',
+        model: 'Model',
+        error: 'Shadow error',
+        metrics: {
+          successRate: 'Success rate',
+          durationAvg: 'Avg latency',
+          durationP50: 'p50 latency',
+          durationP95: 'p95 latency',
+          firstTokenAvg: 'Avg first token',
+          inputTokens: 'Input tokens',
+          outputTokens: 'Output tokens'
+        },
+        validation: {
+          name: 'Name is required',
+          target: 'Group ID and shadow account ID are required',
+          sampleRate: 'Sample rate must be between 0 and 100%'
+        }
+      },
+      statusPage: {
+        title: 'Public Status Page',
+        description: 'Per-platform/model availability and incidents, visible without login.',
+        disabled: 'disabled',
+        settings: 'Settings',
+        settingsTitle: 'Status Page Settings',
+        enabled: 'Enable public status page',
+        pageTitle: 'Page title',
+        platforms: 'Platforms',
+        platformsHint: 'Comma-separated. Empty = every platform with traffic.',
+        incidentPlatformsHint: 'Comma-separated. Empty = all platforms.',
+        showModels: 'Show per-model breakdown',
+        minModelRequests: 'Min requests (24h) to list a model',
+        cacheSeconds: 'Cache seconds (15-600)',
+        settingsSaved: 'Status page settings saved',
+        loadFailed: 'Failed to load status page data',
+        saveFailed: 'Failed to save',
+        deleteFailed: 'Failed to delete',
+        empty: 'No incidents published yet.',
+        createIncident: 'New Incident',
+        editIncident: 'Edit Incident',
+        incidentTitle: 'Title',
+        impact: 'Impact',
+        status: 'Status',
+        startedAt: 'Started',
+        allPlatforms: 'All',
+        message: 'Message',
+        postUpdate: 'Post Update',
+        post: 'Post',
+        incidentSaved: 'Incident saved',
+        updatePosted: 'Update posted',
+        incidentDeleted: 'Incident deleted',
+        deleteConfirmTitle: 'Delete Incident',
+        deleteConfirmMessage: 'Delete this incident and all of its updates?',
+        validation: {
+          title: 'Title is required',
+          message: 'Message is required'
         }
       },
       runtime: {
@@ -3203,6 +4145,8 @@ export default {
         passwordResetHint: 'Allow users to reset their password via email',
         totp: 'Two-Factor Authentication (2FA)',
         totpHint: 'Allow users to use authenticator apps like Google Authenticator',
+        passkeyLogin: 'Passkey Sign-in',
+        passkeyLoginHint: 'Allow users to sign in with a registered passkey without entering a password',
         totpKeyNotConfigured:
           'Please configure TOTP_ENCRYPTION_KEY in environment variables first. Generate a key with: openssl rand -hex 32'
       },
@@ -3237,6 +4181,59 @@ export default {
         quickSetCopy: 'Generate & Copy (current site)',
         redirectUrlSetAndCopied: 'Redirect URL generated and copied to clipboard'
       },
+      oidc: {
+        title: 'OIDC / OAuth2 Login Providers',
+        description:
+          'Let users sign in with any OIDC or OAuth2 identity provider (Google, Azure AD, Keycloak, GitHub, ...)',
+        addProvider: 'Add Provider',
+        editProvider: 'Edit Provider',
+        deleteProvider: 'Delete Provider',
+        deleteConfirm:
+          'Delete provider "{name}"? Users will no longer be able to sign in with it. Linked identities are kept and reconnect if the same slug is re-created.',
+        empty: 'No providers configured',
+        saved: 'Provider saved',
+        deleted: 'Provider deleted',
+        failedToLoad: 'Failed to load providers',
+        failedToSave: 'Failed to save provider',
+        failedToDelete: 'Failed to delete provider',
+        form: {
+          slug: 'Slug',
+          slugHint: 'Lowercase letters, digits and hyphens; used in the callback URL and cannot be changed',
+          name: 'Display Name',
+          nameHint: 'Shown on the login button',
+          discoveryUrl: 'Discovery URL / Issuer',
+          discoveryUrlHint:
+            'Endpoints are loaded from /.well-known/openid-configuration. Leave empty for plain OAuth2 providers.',
+          manualEndpoints: 'Manual endpoints (optional)',
+          manualEndpointsHint:
+            'Required for providers without discovery (e.g. GitHub); overrides discovered endpoints.',
+          authorizeUrl: 'Authorization URL',
+          tokenUrl: 'Token URL',
+          userinfoUrl: 'UserInfo URL',
+          tokenAuthMethod: 'Token Auth Method',
+          scopes: 'Scopes',
+          redirectUrlHint:
+            'Must match the redirect URL registered at the provider (must be an absolute http(s) URL)',
+          subjectClaim: 'Subject Claim',
+          emailClaim: 'Email Claim',
+          emailVerifiedClaim: 'Email Verified Claim',
+          emailVerifiedClaimHint: 'Defaults to email_verified. Emails without this claim are treated as unverified.',
+          usernameClaim: 'Username Claim',
+          allowedEmailDomains: 'Allowed Email Domains',
+          allowedEmailDomainsHint:
+            'Comma separated. When set, only users with a verified email in these domains can sign in.',
+          usePkce: 'Use PKCE',
+          usePkceHint: 'Recommended; required for public clients (token auth method "none")',
+          trustAllEmails: 'Trust All Emails',
+          trustAllEmailsHint:
+            'Treat every email returned by this provider as verified, ignoring the verified claim. Only for providers that never return unverified emails.',
+          trustEmail: 'Link by Email',
+          trustEmailHint:
+            'Sign verified emails into existing accounts with the same email. Only enable for trusted identity providers.',
+          enabled: 'Enabled',
+          enabledHint: 'Show this provider on the login/register pages'
+        }
+      },
       defaults: {
         title: 'Default User Settings',
         description: 'Default values for new users',
@@ -3565,6 +4562,51 @@ export default {
 
   // User Subscriptions Page
   userSubscriptions: {
+    plans: {
+      title: 'Plans',
+      description: 'Buy, renew or switch subscriptions using your account balance',
+      days: '{days} days',
+      policy: {
+        none: 'One-time',
+        manual: 'Renewable',
+        auto: 'Auto-renewable'
+      },
+      buy: 'Buy',
+      renew: 'Renew',
+      change: 'Switch',
+      notRenewable: 'This plan cannot be renewed while your subscription is active',
+      unavailable: 'This plan is no longer available',
+      changeTargetActive: 'You already have an active subscription in this group, renew it instead',
+      insufficientBalance: 'Insufficient balance, please recharge first',
+      failed: 'Operation failed',
+      confirmTitle: 'Confirm Purchase',
+      changeTitle: 'Switch Plan',
+      changeFrom: 'Replace subscription',
+      changeHint: 'The unused paid time of the replaced subscription is credited up to the new plan price (not refunded to balance), and it ends immediately',
+      plan: 'Plan',
+      credit: 'Credit for unused time',
+      amount: 'Amount due',
+      balance: 'Current balance',
+      newExpiry: 'Valid until',
+      extendHint: 'The validity is added on top of your current expiry date',
+      confirm: 'Confirm',
+      processing: 'Processing...',
+      purchased: 'Plan "{plan}" purchased',
+      changed: 'Switched to plan "{plan}"',
+      autoRenew: 'Auto-renewal',
+      autoRenewHint: 'Renew with {plan} (${price}) from your balance shortly before expiry',
+      autoRenewFailed: 'Last renewal failed: {reason}',
+      autoRenewEnabled: 'Auto-renewal enabled',
+      autoRenewDisabled: 'Auto-renewal disabled',
+      orders: 'Recent Orders',
+      validUntil: 'valid until {date}',
+      kind: {
+        purchase: 'Purchase',
+        renew: 'Renewal',
+        auto_renew: 'Auto-renewal',
+        change: 'Switch'
+      }
+    },
     title: 'My Subscriptions',
     description: 'View your subscription plans and usage',
     noActiveSubscriptions: 'No Active Subscriptions',
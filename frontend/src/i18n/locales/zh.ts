@@ -270,6 +270,12 @@ export default {
     redeemCodes: '兑换码',
     ops: '运维监控',
     promoCodes: '优惠码',
+    apiKeyAbuse: '密钥滥用检测',
+    spendGuard: '消费异常保护',
+    referrals: '邀请返利',
+    subscriptionPlans: '订阅套餐',
+    payments: '在线支付',
+    referral: '邀请好友',
     settings: '系统设置',
     myAccount: '我的账户',
     lightMode: '浅色模式',
@@ -290,6 +296,8 @@ export default {
     signInToAccount: '登录您的账户以继续',
     signIn: '登录',
     signingIn: '登录中...',
+    signInWithPasskey: '使用通行密钥登录',
+    passkeyLoginFailed: '通行密钥登录失败',
     createAccount: '创建账户',
     signUpToStart: '注册以开始使用 {siteName}',
     signUp: '注册',
@@ -310,6 +318,10 @@ export default {
     passwordRequired: '请输入密码',
     passwordMinLength: '密码至少需要 6 个字符',
     loginFailed: '登录失败，请检查您的凭据后重试。',
+    loginLocked: '登录失败次数过多，已临时锁定，请约 {minutes} 分钟后重试。',
+    loginThrottled: '失败次数过多，请等待 {seconds} 秒后再试。',
+    loginCaptchaRequired: '请先完成人机验证后再登录。',
+    referredByCode: '你正在使用邀请码 {code} 注册',
     registrationFailed: '注册失败，请重试。',
     loginSuccess: '登录成功！欢迎回来。',
     accountCreatedSuccess: '账户创建成功！欢迎使用 {siteName}。',
@@ -334,6 +346,9 @@ export default {
     promoCodeDisabled: '此优惠码已被禁用',
     promoCodeMaxUsed: '此优惠码已达到使用上限',
     promoCodeAlreadyUsed: '您已使用过此优惠码',
+    promoCodeValidPercent: '有效！下次充值将额外赠送 {percent}%',
+    promoCodeNotStarted: '此优惠码尚未生效',
+    promoCodeNotForRegister: '此优惠码不能在注册时使用',
     promoCodeValidating: '优惠码正在验证中，请稍候',
     promoCodeInvalidCannotRegister: '优惠码无效，请检查后重试或清空优惠码',
     invitationCodeLabel: '邀请码',
@@ -352,6 +367,14 @@ export default {
       callbackMissingToken: '登录信息缺失，请返回重试。',
       backToLogin: '返回登录'
     },
+    oidc: {
+      signIn: '使用 {name} 登录',
+      callbackTitle: '正在完成登录',
+      callbackProcessing: '正在验证登录信息，请稍候...',
+      callbackHint: '如果页面未自动跳转，请返回登录页重试。',
+      callbackMissingToken: '登录信息缺失，请返回重试。',
+      backToLogin: '返回登录'
+    },
     oauth: {
       code: '授权码',
       state: '状态',
@@ -569,11 +592,13 @@ export default {
     currentExpiration: '当前过期时间',
     expiresAt: '过期时间',
     noExpiration: '永久有效',
+    suspendedHint: '该密钥因疑似共享或消费异常已被停用，请联系管理员恢复。',
     status: {
       active: '活跃',
       inactive: '已停用',
       quota_exhausted: '额度耗尽',
-      expired: '已过期'
+      expired: '已过期',
+      suspended: '已停用'
     }
   },
 
@@ -634,8 +659,98 @@ export default {
     userAgent: 'User-Agent'
   },
 
+  // 管理员模拟登录提示横幅
+  impersonation: {
+    banner: '正在以 {user} 的身份查看（管理员：{admin}）',
+    expiresAt: '{time} 自动结束',
+    exit: '退出'
+  },
+
+  // Referral
+
+  referral: {
+    title: '邀请好友',
+    description: '分享邀请链接，好友使用服务后你将获得奖励',
+    disabled: '邀请返利功能未开启。',
+    failedToLoad: '加载邀请数据失败',
+    yourLink: '你的邀请链接',
+    yourCode: '邀请码',
+    copyLink: '复制链接',
+    linkCopied: '邀请链接已复制',
+    termsBonus: '好友累计消费满 ${minSpend} 后，你将获得 ${amount} 奖励。',
+    termsCommission: '好友使用余额消费时，你将获得消费金额 {percent}% 的返佣。',
+    termsCommissionDays: '好友注册后 {days} 天内使用余额消费时，你将获得消费金额 {percent}% 的返佣。',
+    termsCap: '单个好友带来的奖励上限为 ${amount}。',
+    commissionBase: '消费 ${amount}',
+    noReferrals: '暂无邀请记录',
+    noRewards: '暂无奖励记录',
+    tabs: {
+      referrals: '邀请记录',
+      rewards: '奖励明细'
+    },
+    stats: {
+      totalReferrals: '已邀请',
+      totalRewards: '累计奖励',
+      signupBonusTotal: '注册奖励',
+      commissionTotal: '消费返佣'
+    },
+    status: {
+      active: '有效',
+      held: '审核中',
+      blocked: '无效'
+    },
+    rewardKinds: {
+      signup_bonus: '注册奖励',
+      commission: '消费返佣'
+    }
+  },
+
   // Redeem
   redeem: {
+    promo: {
+      title: '优惠码',
+      description: '有优惠码？在这里使用即可获得赠送余额。',
+      placeholder: '输入优惠码',
+      apply: '使用',
+      history: '最近使用的优惠码',
+      pendingTitle: '{code}：下次充值额外赠送 {percent}%',
+      pendingHint: '下次使用余额兑换码充值时将自动发放。',
+      pendingHintCapped: '下次使用余额兑换码充值时将自动发放（最多 ${max}）。',
+      statusPending: '待发放 +{percent}%',
+      applied: '优惠码使用成功：+${amount}',
+      pendingApplied: '优惠码使用成功：下次充值额外赠送 {percent}%',
+      errorScope: '此优惠码仅限注册时使用',
+      errorNewUsersOnly: '此优惠码仅限新用户使用',
+      errorGroupRequired: '此优惠码需要持有指定订阅',
+      errorPending: '您已有一个待发放的充值赠送'
+    },
+    payment: {
+      title: '在线充值',
+      description: '通过在线支付即时充值余额。',
+      amount: '充值金额（USD）',
+      amountHint: '单笔 ${min} - ${max}',
+      method: '支付方式',
+      payPreview: '需支付 {amount}',
+      payButton: '立即支付',
+      redirecting: '正在跳转...',
+      recentOrders: '最近充值',
+      continuePay: '继续支付',
+      returnPaid: '支付成功：余额已增加 ${amount}',
+      returnPending: '正在等待支付结果确认...',
+      returnNotPaid: '该笔支付未完成（{status}）',
+      errorUnavailable: '在线支付暂不可用',
+      errorAmount: '充值金额超出允许范围',
+      errorTooManyPending: '未支付的订单过多，请先完成支付或等待订单过期',
+      errorCheckout: '创建支付失败，请稍后重试',
+      status: {
+        pending: '待支付',
+        paid: '已支付',
+        partially_refunded: '部分退款',
+        refunded: '已退款',
+        expired: '已过期',
+        failed: '失败'
+      }
+    },
     title: '兑换码',
     description: '输入兑换码以充值余额或增加并发数',
     currentBalance: '当前余额',
@@ -662,6 +777,9 @@ export default {
     balanceAddedRedeem: '余额充值（兑换）',
     balanceAddedAdmin: '余额充值（管理员）',
     balanceDeductedAdmin: '余额扣除（管理员）',
+    balanceAddedPayment: '余额充值（在线支付）',
+    balanceRefundedPayment: '余额扣回（支付退款）',
+    onlinePayment: '在线支付',
     concurrencyAddedRedeem: '并发增加（兑换）',
     concurrencyAddedAdmin: '并发增加（管理员）',
     concurrencyReducedAdmin: '并发减少（管理员）',
@@ -742,9 +860,107 @@ export default {
       sendCode: '发送验证码',
       codeSent: '验证码已发送到您的邮箱',
       sendCodeFailed: '发送验证码失败'
+    },
+    passkeys: {
+      title: '通行密钥',
+      description: '使用 Touch ID、Windows Hello 或安全密钥作为二次验证，或直接免密码登录',
+      count: '已注册 {count} 个通行密钥',
+      notSupported: '当前浏览器不支持通行密钥',
+      add: '添加通行密钥',
+      addTitle: '添加通行密钥',
+      addHint: '验证身份后，按浏览器提示创建通行密钥',
+      addSuccess: '通行密钥已添加',
+      addFailed: '添加通行密钥失败',
+      cancelled: '通行密钥操作已取消或超时',
+      name: '名称',
+      namePlaceholder: '例如：MacBook Touch ID',
+      rename: '重命名',
+      createdAt: '添加于',
+      lastUsedAt: '最近使用',
+      deleteTitle: '移除通行密钥',
+      deleteHint: '确定移除「{name}」？移除后将无法再用它登录。',
+      deleteSuccess: '通行密钥已移除',
+      loginHint: '请使用账号绑定的通行密钥完成验证',
+      loginWithPasskey: '使用通行密钥',
+      useMethod: {
+        passkey: '使用通行密钥',
+        totp: '使用认证器应用',
+        recovery: '使用恢复码'
+      }
+    },
+    recoveryCodes: {
+      title: '恢复码',
+      remaining: '剩余 {count} 个未使用的恢复码',
+      none: '尚未生成恢复码。建议生成以防认证器或通行密钥丢失',
+      generate: '生成恢复码',
+      regenerate: '重新生成',
+      generateTitle: '生成恢复码',
+      generateHint: '之前生成的恢复码将全部失效',
+      saveWarning: '请妥善保存这些恢复码。每个恢复码只能使用一次，关闭后将不再显示。',
+      copy: '复制恢复码',
+      done: '我已保存',
+      loginHint: '请输入一个恢复码'
+    },
+    sessions: {
+      title: '登录设备',
+      description: '当前登录您账号的设备。如有不认识的设备，请立即将其退出。',
+      empty: '暂无活跃会话',
+      current: '当前设备',
+      signedInAt: '登录于',
+      lastActive: '最近活跃',
+      revoke: '退出',
+      signOut: '退出登录',
+      revokeSuccess: '已退出该设备',
+      revokeAll: '退出所有设备',
+      revokeAllConfirm: '确定退出所有设备（包括当前设备）吗？退出后需要重新登录。'
+    },
+    dataExport: {
+      title: '我的数据',
+      description: '下载您的个人资料、API 密钥信息、订阅、兑换记录和使用记录副本。导出文件保留 7 天。',
+      request: '申请导出',
+      requested: '已提交导出申请，稍后即可下载。',
+      empty: '暂无导出记录',
+      download: '下载',
+      expiresAt: '可下载至',
+      preparingHint: '正在生成导出文件，请几分钟后刷新页面',
+      failedHint: '导出失败，请重新申请',
+      status: {
+        pending: '排队中',
+        processing: '生成中',
+        completed: '可下载',
+        failed: '失败',
+        expired: '已过期'
+      }
+    },
+    accountDeletion: {
+      title: '注销账号',
+      description: '永久删除您的账号。通过邮件确认后，账号、API 密钥和登录会话将立即停用，个人数据将在 {days} 天后删除。',
+      request: '注销账号',
+      adminHint: '管理员账号不能在此注销。',
+      confirmMessage: '我们将向您的邮箱发送确认链接。确认后账号立即停用，并在 {days} 天后删除。建议先导出您的数据。',
+      reason: '注销原因（可选）',
+      reasonPlaceholder: '告诉我们您离开的原因',
+      sendConfirmation: '发送确认邮件',
+      emailSent: '确认邮件已发送，请在 24 小时内点击链接完成确认。',
+      pendingHint: '确认链接已发送到您的邮箱。在您确认之前，账号保持正常使用。',
+      resend: '重新发送',
+      cancel: '取消申请',
+      cancelled: '已取消注销申请'
     }
   },
 
+  // Account deletion confirmation page
+  accountDeletion: {
+    title: '确认注销账号',
+    hint: '您申请了注销账号',
+    warning: '确认后，您的账号、API 密钥和所有登录会话将立即停用。个人数据将在宽限期结束后永久删除；在此之前管理员仍可恢复账号。',
+    confirm: '确认注销',
+    confirmed: '已确认注销',
+    confirmedHint: '您的账号已停用，将于 {date} 永久删除。',
+    invalidLink: '链接无效或已过期',
+    invalidLinkHint: '该确认链接无效或已过期，请在个人资料页重新申请注销。'
+  },
+
   // Empty States
   empty: {
     noData: '暂无数据'
@@ -773,6 +989,46 @@ export default {
     jumpAction: '跳转'
   },
 
+  // Public status page
+  status: {
+    defaultTitle: '{site} 服务状态',
+    unavailable: '状态页暂不可用。',
+    updatedAt: '更新于 {time}',
+    startedAt: '开始于 {time}',
+    noComponents: '暂无数据。',
+    component: '组件',
+    availability1h: '可用性（1 小时）',
+    availability24h: '可用性（24 小时）',
+    latencyP50: '中位延迟',
+    state: '状态',
+    recentIncidents: '近期已解决',
+    overall: {
+      operational: '所有服务运行正常',
+      degraded: '性能下降',
+      partial_outage: '部分中断',
+      major_outage: '严重中断',
+      no_data: '暂无数据'
+    },
+    level: {
+      operational: '正常',
+      degraded: '性能下降',
+      partial_outage: '部分中断',
+      major_outage: '严重中断',
+      no_data: '暂无数据'
+    },
+    impact: {
+      minor: '轻微',
+      major: '严重',
+      critical: '紧急'
+    },
+    incidentStatus: {
+      investigating: '调查中',
+      identified: '已定位',
+      monitoring: '观察中',
+      resolved: '已解决'
+    }
+  },
+
   // Errors
   errors: {
     somethingWentWrong: '出错了',
@@ -1023,12 +1279,85 @@ export default {
       },
       // 余额变动记录
       balanceHistory: '充值记录',
+      sessions: {
+        title: '登录会话',
+        empty: '暂无活跃会话',
+        createdAt: '登录于',
+        lastUsedAt: '最近活跃',
+        expiresAt: '过期时间',
+        revoke: '撤销',
+        revokeAll: '全部撤销',
+        revokeSuccess: '会话已撤销',
+        revokeAllSuccess: '已撤销全部会话'
+      },
+      loginLocks: {
+        title: '登录锁定',
+        description: '因多次密码错误而被临时锁定的账号与 IP，解锁后将同时清零失败计数。',
+        empty: '当前没有被锁定的账号或 IP',
+        lockedUntil: '锁定至',
+        unlock: '解锁',
+        unlockSuccess: '已解锁',
+        scopes: {
+          account: '账号',
+          ip: 'IP'
+        }
+      },
+      impersonate: {
+        action: '以用户身份查看',
+        title: '以用户身份查看',
+        notice: '你将看到与该用户完全一致的控制台。修改密码、二步验证、通行密钥、登录会话以及创建 API 密钥均被禁止，期间的每个请求都会以你的管理员账号记录。',
+        reason: '原因',
+        reasonPlaceholder: '例如工单号或需要排查的问题',
+        duration: '时长（分钟）',
+        durationHint: '5-120 分钟，到期自动结束且不可续期。',
+        start: '开始',
+        started: '正在以 {email} 的身份查看'
+      },
+      impersonations: {
+        title: '模拟登录审计',
+        description: '管理员发起的"以用户身份查看"会话，以及期间的全部请求记录。',
+        activeOnly: '仅进行中',
+        empty: '暂无模拟登录记录',
+        active: '进行中',
+        ended: '已结束',
+        startedAt: '开始时间',
+        endedAt: '结束时间',
+        expiresAt: '到期时间',
+        actionCount: '{count} 个请求',
+        viewActions: '请求记录',
+        hideActions: '收起',
+        moreActions: '另有 {count} 条更早的请求未显示',
+        end: '立即结束',
+        endSuccess: '模拟登录已结束'
+      },
+      accountDeletions: {
+        title: '账号注销',
+        description: '用户自助提交的注销申请。已确认的账号处于停用状态，宽限期结束后将被匿名化删除；如需保留请在此之前恢复。',
+        allStatuses: '全部状态',
+        empty: '暂无注销申请',
+        requestedAt: '申请时间',
+        confirmedAt: '确认时间',
+        scheduledFor: '删除时间',
+        restoredAt: '恢复时间',
+        completedAt: '删除于',
+        keysDisabled: '已停用 {count} 个 API 密钥',
+        restore: '恢复账号',
+        restoreSuccess: '账号已恢复',
+        status: {
+          pending_confirmation: '待确认',
+          scheduled: '待删除',
+          cancelled: '已取消',
+          restored: '已恢复',
+          completed: '已删除'
+        }
+      },
       balanceHistoryTip: '点击查看充值记录',
       balanceHistoryTitle: '用户充值和并发变动记录',
       noBalanceHistory: '暂无变动记录',
       allTypes: '全部类型',
       typeBalance: '余额（兑换码）',
       typeAdminBalance: '余额（管理员调整）',
+      typePayment: '余额（在线支付）',
       typeConcurrency: '并发（兑换码）',
       typeAdminConcurrency: '并发（管理员调整）',
       typeSubscription: '订阅',
@@ -2458,10 +2787,344 @@ export default {
       deleteConfirm: '确定要删除该公告吗？此操作无法撤销。'
     },
 
+    // API Key 滥用检测
+    apiKeyAbuse: {
+      title: 'API 密钥滥用检测',
+      description: '基于来源 IP 分布、并发与客户端特征识别被共享或滥用的 API 密钥',
+      allStatus: '全部状态',
+      userIdFilter: '用户 ID',
+      scanNow: '立即扫描',
+      scanning: '扫描中...',
+      scanResult: '已扫描 {scanned} 个密钥：标记 {flagged} 个，处置 {actioned} 个',
+      scanFailed: '扫描失败',
+      settings: '检测设置',
+      settingsSaved: '设置已保存',
+      settingsSaveFailed: '保存设置失败',
+      failedToLoad: '加载滥用标记失败',
+      viewDetail: '查看详情',
+      detailTitle: '可疑 API 密钥',
+      keyStatus: '密钥状态',
+      owner: '所有者',
+      ownerNotifiedAt: '已于 {time} 通知所有者',
+      firstDetected: '首次检测',
+      breakdown: '评分明细',
+      topIps: '主要来源 IP',
+      topUserAgents: '主要 User-Agent',
+      rateLimitRpm: '限流（次/分钟）',
+      note: '备注',
+      reviewedAt: '审核于 {time}',
+      reviewSuccess: '处置成功',
+      reviewFailed: '处置失败',
+      auto: '自动',
+      signalsSummary: '{ips} 个 IP · {networks} 个网段 · 并发 {concurrent} · {agents} 种客户端',
+      requestsSummary: '{requests} 次请求 · 活跃 {hours}/{window} 小时',
+      columns: {
+        apiKey: 'API 密钥 / 所有者',
+        score: '可疑分',
+        signals: '特征',
+        status: '状态',
+        lastDetected: '最近检测',
+        actions: '操作'
+      },
+      status: {
+        open: '待处理',
+        dismissed: '已忽略',
+        resolved: '已解决'
+      },
+      action: {
+        none: '未处置',
+        rate_limited: '已限流（{rpm}/分钟）',
+        suspended: '已停用'
+      },
+      actions: {
+        dismiss: '忽略（误报）',
+        restore: '解除限制',
+        rateLimit: '限流',
+        suspend: '停用密钥'
+      },
+      signals: {
+        ips: '不同 IP 数',
+        networks: '网段数',
+        concurrency: '并发 IP（5 分钟）',
+        userAgents: '客户端 UA 数',
+        activeHours: '活跃小时'
+      },
+      enabled: '启用定时检测',
+      enabledHint: '关闭后仍可手动扫描，已限流的密钥仍会被限流',
+      scanIntervalMinutes: '扫描间隔（分钟）',
+      windowHours: '统计窗口（小时）',
+      minRequests: '窗口内最少请求数',
+      thresholds: '满分阈值',
+      thresholdsHint: '各项特征在单一来源时为 0 分，达到阈值时取满权重（IP 35、网段 25、并发 20、客户端 10、全天候活跃 10）。',
+      flagScore: '标记阈值',
+      autoAction: '自动处置',
+      actionScore: '处置阈值',
+      dismissSuppressDays: '忽略后不再标记（天）',
+      notifyOwner: '处置时邮件通知所有者',
+      autoActions: {
+        none: '仅标记',
+        rate_limit: '限流',
+        suspend: '停用'
+      }
+    },
+
+    // 消费异常自动停用
+    subscriptionPlans: {
+      title: '订阅套餐',
+      description: '将订阅分组包装为套餐，用户可使用余额购买、续费和变更',
+      tabs: {
+        plans: '套餐',
+        orders: '订单'
+      },
+      create: '创建套餐',
+      edit: '编辑套餐',
+      delete: '删除套餐',
+      deleteConfirm: '确定删除套餐「{name}」吗？已有订阅不受影响，使用该套餐的自动续费将失败。',
+      group: '订阅分组',
+      groupHint: '只有订阅计费类型的分组可以作为套餐出售',
+      groupRequired: '请选择订阅分组',
+      name: '名称',
+      planDescription: '描述',
+      price: '价格（USD）',
+      validityDays: '有效期（天）',
+      validityDaysValue: '{days} 天',
+      renewalPolicy: '续费方式',
+      sortOrder: '排序',
+      enabled: '上架',
+      enabledHint: '下架的套餐对用户不可见，也不会用于自动续费',
+      policy: {
+        none: '一次性',
+        manual: '手动续费',
+        auto: '自动续费'
+      },
+      policyHint: {
+        none: '仅在用户没有该分组的有效订阅时可购买',
+        manual: '用户可再次购买以延长有效订阅',
+        auto: '用户还可开启到期前自动使用余额续费'
+      },
+      status: {
+        active: '上架',
+        disabled: '下架'
+      },
+      kind: {
+        purchase: '购买',
+        renew: '续费',
+        auto_renew: '自动续费',
+        change: '套餐变更'
+      },
+      allGroups: '全部分组',
+      allKinds: '全部类型',
+      searchPlaceholder: '搜索邮箱或套餐...',
+      changedFrom: '由 {group} 变更',
+      creditValue: '抵扣 ${amount}',
+      columns: {
+        name: '套餐',
+        group: '分组',
+        price: '价格',
+        renewalPolicy: '续费方式',
+        status: '状态',
+        sortOrder: '排序',
+        actions: '操作',
+        user: '用户',
+        plan: '套餐',
+        kind: '类型',
+        amount: '扣款',
+        expiresAt: '有效期至',
+        createdAt: '时间'
+      },
+      created: '套餐已创建',
+      updated: '套餐已更新',
+      deleted: '套餐已删除',
+      failedToLoad: '加载套餐失败',
+      failedToLoadOrders: '加载订单失败',
+      saveFailed: '保存套餐失败',
+      deleteFailed: '删除套餐失败'
+    },
+    payments: {
+      title: '在线支付',
+      description: '在线充值订单、退款与支付方式配置',
+      settings: '支付配置',
+      searchPlaceholder: '搜索邮箱、订单号或交易号',
+      allStatuses: '全部状态',
+      allProviders: '全部支付方式',
+      failedToLoad: '加载支付订单失败',
+      failedToLoadSettings: '加载支付配置失败',
+      columns: {
+        orderNo: '订单',
+        user: '用户',
+        provider: '支付方式',
+        amount: '金额',
+        status: '状态',
+        createdAt: '创建时间',
+        actions: '操作'
+      },
+      provider: {
+        stripe: 'Stripe',
+        generic: '通用支付网关'
+      },
+      status: {
+        pending: '待支付',
+        paid: '已支付',
+        partially_refunded: '部分退款',
+        refunded: '已退款',
+        expired: '已过期',
+        failed: '失败'
+      },
+      paidAt: '支付于 {time}',
+      refundedValue: '已退 {amount}（扣回余额 ${credit}）',
+      refund: '退款',
+      refundTitle: '订单退款',
+      refundRemaining: '订单 {order} 可退金额：{amount}',
+      refundAmount: '退款金额（{currency}）',
+      refundAmountHint: '填 0 表示退还剩余全部金额',
+      refundReason: '退款原因',
+      refundOffline: '记录线下退款',
+      refundOfflineHint: '仅记录退款并扣回余额，款项已在本系统之外退还',
+      refundDeductHint: '将按退款比例扣回到账余额与优惠码赠送',
+      refunded: '退款完成',
+      refundFailed: '退款失败',
+      refundExceedsBalance: '用户余额不足以扣回本次退款对应的金额',
+      refundUnsupported: '该支付方式未配置退款接口，请使用线下退款',
+      enabled: '启用在线支付',
+      enabledHint: '在兑换页显示在线充值卡片',
+      publicBaseUrl: '站点公开地址',
+      publicBaseUrlHint: '用于生成支付回调地址与支付完成后的返回地址',
+      minAmount: '最小金额（USD）',
+      maxAmount: '最大金额（USD）',
+      orderExpireMinutes: '订单有效期（分钟）',
+      webhookUrl: '回调地址：{url}',
+      secretKey: '密钥',
+      webhookSecret: 'Webhook 签名密钥',
+      secretConfigured: '已配置（留空保持不变）',
+      currency: '币种',
+      exchangeRate: '汇率',
+      exchangeRateHint: '汇率为每 1 美元余额对应的支付币种金额',
+      apiBase: 'API 地址',
+      displayName: '显示名称',
+      merchantId: '商户号',
+      gatewayUrl: '收银台地址',
+      refundUrl: '退款接口地址（可选）',
+      refundUrlHint: '未配置退款接口时只能记录线下退款',
+      signatureHint: '签名：除 sign 外的非空参数按 key 排序，以 k=v 用 & 拼接后计算 HMAC-SHA256',
+      settingsSaved: '支付配置已保存',
+      saveFailed: '保存支付配置失败'
+    },
+    referrals: {
+      title: '邀请返利',
+      description: '配置邀请奖励规则，审核命中风控标记的邀请',
+      allStatus: '全部状态',
+      searchPlaceholder: '搜索邮箱或邀请码',
+      flaggedOnly: '仅看风控标记',
+      settings: '返利设置',
+      settingsSaved: '设置已保存',
+      settingsSaveFailed: '保存设置失败',
+      failedToLoad: '加载邀请记录失败',
+      invitedBy: '邀请人 {user}',
+      refereeSpend: '消费 ${amount}（充值支付 ${funded}）',
+      bonusPaid: '已发注册奖励',
+      approve: '通过',
+      block: '拒绝',
+      approveTitle: '审核通过',
+      blockTitle: '拒绝邀请',
+      approveConfirm: '确认通过 {user} 的邀请关系？审核期间累积的奖励将在下次结算时补发。',
+      blockConfirm: '确认拒绝 {user} 的邀请关系？之后不再发放奖励，已发放的奖励不会追回。',
+      note: '备注（可选）',
+      approveSuccess: '已审核通过',
+      blockSuccess: '已拒绝',
+      reviewFailed: '操作失败',
+      enabled: '启用邀请返利',
+      enabledHint: '用户可获取邀请链接，奖励每小时结算并计入余额',
+      signupBonusUsd: '注册奖励（USD）',
+      signupBonusUsdHint: '每邀请一位用户，邀请人获得的奖励；0 表示不发放',
+      signupBonusMinSpendUsd: '注册奖励消费门槛（USD）',
+      signupBonusMinSpendUsdHint: '被邀请人余额消费达到该金额后才发放注册奖励',
+      commissionPercent: '返佣比例（%）',
+      commissionPercentHint: '被邀请人余额消费的返佣比例；0 表示不返佣',
+      commissionDays: '返佣有效期（天）',
+      perRefereeCapUsd: '单个被邀请人奖励上限（USD）',
+      perReferrerCapUsd: '单个邀请人奖励上限（USD）',
+      zeroUnlimited: '0 表示不限',
+      holdFlagged: '风控标记的邀请需审核',
+      holdFlaggedHint: '与邀请人同 IP 或同邮箱域名的邀请，审核通过前不发放奖励',
+      ignoredEmailDomains: '忽略的邮箱域名',
+      ignoredEmailDomainsHint: '不参与同域名判定的公共邮箱，每行一个',
+      status: {
+        active: '有效',
+        held: '待审核',
+        blocked: '已拒绝'
+      },
+      flags: {
+        same_ip: '同 IP',
+        shared_signup_ip: '注册 IP 重复',
+        same_email_domain: '同邮箱域名'
+      },
+      columns: {
+        users: '被邀请人',
+        risk: '风控',
+        rewards: '奖励',
+        status: '状态',
+        createdAt: '创建时间',
+        actions: '操作'
+      }
+    },
+
+    spendGuard: {
+      title: '消费异常保护',
+      description: '当 API 密钥的消费速率突然远超自身历史水平时自动临时停用',
+      allStatus: '全部状态',
+      userIdFilter: '用户 ID',
+      settings: '保护设置',
+      settingsSaved: '设置已保存',
+      settingsSaveFailed: '保存设置失败',
+      failedToLoad: '加载停用记录失败',
+      spendSummary: '10 分钟消费 ${spend}（阈值 ${threshold}）',
+      baselineSummary: '历史峰值 ${baseline}',
+      until: '至 {time}',
+      untilLifted: '需管理员手动解除',
+      lift: '解除',
+      liftTitle: '解除停用',
+      liftConfirm: '立即恢复 API 密钥 {key}？关联的运维告警将标记为已处理。',
+      liftSuccess: '已解除停用',
+      liftFailed: '解除停用失败',
+      note: '备注',
+      columns: {
+        apiKey: 'API 密钥 / 用户',
+        trigger: '触发原因',
+        status: '状态',
+        createdAt: '停用时间',
+        actions: '操作'
+      },
+      status: {
+        active: '停用中',
+        expired: '已到期',
+        lifted: '已解除'
+      },
+      scopes: {
+        api_key: '密钥消费',
+        user: '用户消费'
+      },
+      rules: {
+        multiplier: '超过峰值倍数',
+        absolute: '超过绝对金额'
+      },
+      enabled: '启用消费异常保护',
+      enabledHint: '每分钟检查一次每个密钥和用户最近 10 分钟的消费',
+      multiplier: '倍数',
+      multiplierHint: '10 分钟消费超过历史 10 分钟峰值的该倍数时触发；0 表示关闭',
+      absoluteUsd: '绝对上限（USD / 10 分钟）',
+      absoluteUsdHint: '10 分钟消费达到该金额时触发；0 表示关闭',
+      baselineDays: '基线回溯（天）',
+      baselineFloorUsd: '基线下限（USD）',
+      baselineFloorUsdHint: '新密钥或低消费密钥使用的最低基线',
+      suspendMinutes: '停用时长（分钟）',
+      suspendMinutesHint: '0 表示需管理员手动解除',
+      notifyUser: '停用时邮件通知用户'
+    },
+
     // Promo Codes
     promo: {
       title: '优惠码管理',
-      description: '创建和管理注册优惠码',
+      description: '创建和管理注册及充值赠送优惠码',
       createCode: '创建优惠码',
       editCode: '编辑优惠码',
       deleteCode: '删除优惠码',
@@ -2514,7 +3177,33 @@ export default {
       failedToCreate: '创建优惠码失败',
       failedToUpdate: '更新优惠码失败',
       failedToDelete: '删除优惠码失败',
-      failedToLoadUsages: '加载使用记录失败'
+      failedToLoadUsages: '加载使用记录失败',
+      // 赠送方式与使用条件
+      bonusType: '赠送方式',
+      bonusTypeFixed: '固定金额',
+      bonusTypePercent: '下次充值按比例赠送',
+      bonusPercent: '赠送比例 (%)',
+      maxBonusAmount: '赠送上限 ($)',
+      percentHint: '用户下次使用余额兑换码充值时自动发放赠送。',
+      percentBonusLabel: '下次充值 +{percent}%',
+      scope: '使用场景',
+      scopeRegister: '注册时',
+      scopePanel: '用户面板',
+      scopeAny: '注册时和用户面板',
+      perUserLimit: '每用户次数',
+      perUserLimitShort: '每人 {limit} 次',
+      newUsersOnly: '仅限新用户',
+      newUsersOnlyBadge: '新用户',
+      newUserDays: '注册后天数',
+      newUserDaysHint: '注册后多少天内可在用户面板使用，0 表示仅注册时可用。',
+      requiredGroup: '需持有订阅',
+      noGroupRequired: '不限',
+      groupPrefix: '分组 #{id}',
+      startsAt: '生效时间',
+      startsAtShort: '{time} 生效',
+      statusScheduled: '未生效',
+      usagePending: '待发放 {percent}%',
+      usageTopUp: '充值 ${amount}'
     },
 
     // Usage Records
@@ -2569,6 +3258,8 @@ export default {
       errorSearch: '错误搜索',
       errorSearchPlaceholder: '搜索错误内容...',
       viewDetails: '查看详情',
+      viewCapture: '抓取',
+      viewCaptureHint: '查看该请求被抓取的请求/响应（仅在命中抓取规则时存在）',
       errorDetails: '错误详情',
       unknownError: '未知错误',
       upstream: '上游',
@@ -2983,11 +3674,19 @@ export default {
         loading: '加载中...',
         empty: '暂无告警事件',
         loadFailed: '加载告警事件失败',
+        ackBadge: '已确认',
         status: {
           firing: '告警中',
           resolved: '已恢复',
           manualResolved: '手动已解决'
         },
+        timelineAction: {
+          fired: '触发',
+          notified: '已通知',
+          repeatNotified: '重复通知',
+          escalated: '已升级',
+          acknowledged: '已确认'
+        },
         detail: {
           title: '告警详情',
           loading: '加载详情中...',
@@ -2996,6 +3695,13 @@ export default {
           manualResolve: '标记为已解决',
           manualResolvedSuccess: '已标记为手动解决',
           manualResolvedFailed: '标记为手动解决失败',
+          publishFailed: '更新状态页可见性失败',
+          publishedSuccess: '已发布到状态页',
+          unpublishedSuccess: '已从状态页移除',
+          statusPage: '状态页',
+          publicSummaryPlaceholder: '公开摘要（为空时使用告警标题）',
+          publish: '发布',
+          unpublish: '取消发布',
           silence: '忽略此告警',
           silenceSuccess: '已静默该告警',
           silenceFailed: '静默失败',
@@ -3008,7 +3714,22 @@ export default {
           historyTitle: '历史记录',
           historyHint: '同一规则 + 相同维度的最近事件',
           historyLoading: '加载历史中...',
-          historyEmpty: '暂无历史记录'
+          historyEmpty: '暂无历史记录',
+          acknowledge: '确认告警',
+          acknowledgeSuccess: '已确认该告警',
+          acknowledgeFailed: '确认告警失败',
+          acknowledgedAt: '已于 {time} 确认',
+          assignee: '处理人',
+          assigneePlaceholder: '处理人（可选）',
+          ackNotePlaceholder: '备注（可选）',
+          escalation: '升级',
+          escalationLevel: '第 {level} 级',
+          notifications: '通知次数',
+          lastNotifiedAt: '最近 {time}',
+          timelineTitle: '事件时间线',
+          timelineHint: '该事件的通知、升级、确认与解决记录',
+          timelineLoading: '加载时间线中...',
+          timelineEmpty: '暂无时间线记录'
         },
         table: {
           time: '时间',
@@ -3044,7 +3765,8 @@ export default {
         metricGroups: {
           system: '系统指标',
           group: '分组级别指标（需 group_id）',
-          account: '账号级别指标'
+          account: '账号级别指标',
+          slo: 'SLO 指标（需选择 SLO）'
         },
         metrics: {
           successRate: '成功率 (%)',
@@ -3061,7 +3783,17 @@ export default {
           accountRateLimitedCount: '限流账号数',
           accountErrorCount: '错误账号数（不含临时不可调度）',
           accountErrorRatio: '错误账号比例 (%)',
-          overloadAccountCount: '过载账号数'
+          overloadAccountCount: '过载账号数',
+          accountErrorRate: '单账号错误率 (%)',
+          durationP95: 'P95 请求耗时 (ms)',
+          durationP99: 'P99 请求耗时 (ms)',
+          firstTokenP95: 'P95 首 Token 耗时 (ms)',
+          firstTokenP99: 'P99 首 Token 耗时 (ms)',
+          requestsPerMinute: '每分钟请求数',
+          tokensPerMinute: '每分钟 Token 数',
+          spendUsd: '消费金额 (USD)',
+          sloBurnRate: 'SLO 预算消耗速率 (x)',
+          sloErrorBudgetRemaining: 'SLO 剩余错误预算 (%)'
         },
         metricDescriptions: {
           successRate: '统计窗口内成功请求占比（0~100）。',
@@ -3078,12 +3810,36 @@ export default {
           accountRateLimitedCount: '统计窗口内被限流的账号数量。',
           accountErrorCount: '统计窗口内产生错误的账号数量（不含临时不可调度）。',
           accountErrorRatio: '统计窗口内错误账号占比（0~100）。',
-          overloadAccountCount: '统计窗口内过载账号数量。'
+          overloadAccountCount: '统计窗口内过载账号数量。',
+          accountErrorRate: '统计窗口内错误率最高的账号的错误率（0~100）；设置 filters.account_id 可只监控单个账号。',
+          durationP95: '统计窗口内成功请求耗时的 P95。',
+          durationP99: '统计窗口内成功请求耗时的 P99。',
+          firstTokenP95: '统计窗口内成功请求首 Token 耗时的 P95。',
+          firstTokenP99: '统计窗口内成功请求首 Token 耗时的 P99。',
+          requestsPerMinute: '统计窗口内平均每分钟请求数（成功 + 失败）。',
+          tokensPerMinute: '统计窗口内平均每分钟消耗的 Token 数。',
+          spendUsd: '统计窗口内的消费总额（USD），支持 filters.user_id。',
+          sloBurnRate: '统计窗口内错误预算的消耗速率（1 表示恰好在 SLO 窗口结束时耗尽预算）；设置短窗口时两个窗口都需超过阈值。',
+          sloErrorBudgetRemaining: 'SLO 窗口内剩余的错误预算百分比（预算耗尽后为负数）。'
         },
         hints: {
           recommended: '推荐：运算符 {operator}，阈值 {threshold}{unit}',
           groupRequired: '该指标为分组级别指标，必须选择分组（group_id）。',
-          groupOptional: '可选：通过 group_id 将规则限定到某个分组。'
+          groupOptional: '可选：通过 group_id 将规则限定到某个分组。',
+          anomaly: '将当前窗口与过去 N 天同一小时（UTC，小时预聚合）的基线比较；z-score 阈值单位为 σ，百分比偏离阈值单位为 %。',
+          sloBurnRate: '多窗口告警：规则窗口和短窗口的消耗速率都超过阈值时才触发（例如 60 分钟 + 5 分钟，14.4 倍）。'
+        },
+        operators: {
+          anomalyAbove: '异常：高于基线',
+          anomalyBelow: '异常：低于基线'
+        },
+        anomalyMethods: {
+          zscore: 'Z-score（σ）',
+          percent: '百分比偏离（%）'
+        },
+        sloObjectives: {
+          availability: '可用性',
+          latency: '首 Token 延迟'
         },
         table: {
           name: '名称',
@@ -3106,7 +3862,15 @@ export default {
           sustained: '连续样本数（每分钟）',
           cooldown: '冷却期（分钟）',
           enabled: '启用',
-          notifyEmail: '发送邮件通知'
+          notifyEmail: '发送邮件通知',
+          publicStatus: '展示在公开状态页',
+          publicStatusHint: '该规则触发的事件会作为故障展示在公开状态页',
+          anomalyMethod: '异常检测方式',
+          baselineDays: '基线天数',
+          slo: 'SLO',
+          sloPlaceholder: '选择 SLO',
+          sloObjective: '目标类型',
+          shortWindowMinutes: '短窗口（分钟，可选）'
         },
         validation: {
           title: '请先修正以下问题',
@@ -3118,7 +3882,185 @@ export default {
           thresholdRequired: '阈值必须为数字',
           windowRange: '统计窗口必须为 1 / 5 / 60 分钟之一',
           sustainedRange: '连续样本数必须在 1 到 1440 之间',
-          cooldownRange: '冷却期必须在 0 到 1440 分钟之间'
+          cooldownRange: '冷却期必须在 0 到 1440 分钟之间',
+          baselineDaysRange: '基线天数必须在 1 到 30 之间',
+          sloIdRequired: 'SLO 指标必须选择一个 SLO'
+        }
+      },
+      slo: {
+        title: 'SLO 与错误预算',
+        empty: '暂无 SLO',
+        loadFailed: '加载 SLO 状态失败',
+        scopeAll: '全部流量',
+        window: '{days} 天窗口',
+        availability: '可用性',
+        latency: '首 Token ≤ {ms}ms',
+        target: '目标',
+        actual: '实际',
+        budgetRemaining: '剩余预算',
+        burnRate: '消耗速率',
+        burnRateWindow: '{minutes} 分钟'
+      },
+      requestCapture: {
+        title: '请求抓取',
+        configure: '抓取规则',
+        settingsTitle: '请求抓取规则',
+        settingsHint: '为指定 API Key / 用户或按全局采样率抓取成功请求的请求体与重建后的响应体。抓取内容会脱敏、限制大小，并在保留期后删除。',
+        disabled: '抓取未启用',
+        summary: '采样 {rate}% · {targets} 个目标 · 保留 {hours} 小时',
+        empty: '最近 24 小时没有抓取记录',
+        loadFailed: '加载请求抓取失败',
+        saveSuccess: '抓取规则已保存',
+        saveFailed: '保存抓取规则失败',
+        deleteSuccess: '抓取记录已删除',
+        deleteFailed: '删除抓取记录失败',
+        deleteConfirmTitle: '删除抓取记录',
+        deleteConfirmMessage: '确定删除这条抓取的请求/响应吗？',
+        notCaptured: '该请求未被抓取（未命中抓取规则，或记录已过期）。',
+        showingLatest: '显示最新 {shown} 条，共 {total} 条',
+        detailTitle: '抓取的请求',
+        view: '查看',
+        time: '时间',
+        model: '模型',
+        reason: '命中规则',
+        user: '用户',
+        account: '账号',
+        path: '路径',
+        expiresAt: '过期时间',
+        requestBody: '请求体',
+        responseBody: '响应体',
+        truncated: '已截断',
+        reasons: {
+          api_key: 'API Key',
+          user: '用户',
+          sample: '采样'
+        },
+        formats: {
+          json: 'JSON',
+          sse_reconstructed: '由流式事件重建',
+          sse_raw: '原始流',
+          text: '文本'
+        },
+        validation: {
+          targetId: '每个抓取目标都需要有效的 ID'
+        },
+        form: {
+          enabled: '启用抓取',
+          sampleRate: '全局采样率（%）',
+          sampleUntil: '采样截止时间（留空表示不过期）',
+          retentionHours: '保留时长（小时）',
+          maxCaptures: '最多保存条数',
+          maxRequestKB: '请求体上限（KB）',
+          maxResponseKB: '响应体上限（KB）',
+          targets: '抓取目标',
+          addTarget: '添加目标',
+          noTargets: '未配置 API Key / 用户目标',
+          expiresAt: '过期时间（留空表示不过期）',
+          note: '备注',
+          redactKeys: '额外脱敏的 JSON 字段',
+          redactKeysHint: '每行一个；匹配字段会被替换为 [REDACTED]。凭证类字段始终脱敏。',
+          redactPatterns: '脱敏正则',
+          redactPatternsHint: '每行一个正则表达式；字符串值中匹配的内容会被替换。'
+        }
+      },
+      shadow: {
+        title: '影子流量',
+        description: '将采样的成功请求异步复制到候选账号，与主请求对比。影子响应不会返回给客户端，也不计费。',
+        create: '新建规则',
+        createTitle: '新建影子规则',
+        editTitle: '编辑影子规则',
+        formHint: '候选账号无需属于该分组，也可以设为不可调度，但必须处于启用状态。影子请求会占用其并发槽位，槽位已满时跳过。',
+        empty: '暂无影子规则',
+        loadFailed: '加载影子规则失败',
+        saveSuccess: '影子规则已保存',
+        saveFailed: '保存影子规则失败',
+        deleteSuccess: '影子规则已删除',
+        deleteFailed: '删除影子规则失败',
+        deleteConfirmTitle: '删除影子规则',
+        deleteConfirmMessage: '确定删除该影子规则并停止复制流量吗？已记录的对比结果将保留至清理周期。',
+        reportFailed: '加载对比报告失败',
+        name: '名称',
+        descriptionLabel: '描述',
+        group: '分组',
+        groupId: '分组 ID',
+        shadowAccount: '影子账号',
+        shadowAccountId: '影子账号 ID',
+        sampleRate: '采样率',
+        sampleRatePercent: '采样率（%）',
+        expiresAt: '过期时间',
+        enabled: '启用',
+        status: '状态',
+        active: '生效中',
+        disabled: '已停用',
+        expired: '已过期',
+        viewReport: '报告',
+        hideReport: '收起报告',
+        reportTitle: '主请求 vs 影子请求',
+        samples: '{count} 个样本',
+        noSamples: '该时间范围内暂无影子结果',
+        metric: '指标',
+        primary: '主请求',
+        shadow: '影子',
+        durationDelta: 'p50 延迟（影子相对主请求）',
+        outputTokensDelta: '输出 token（影子相对主请求）',
+        statusCodes: '影子状态码',
+        noStatus: '无响应',
+        recent: '最近结果',
+        time: '时间',
+        model: '模型',
+        error: '影子错误',
+        metrics: {
+          successRate: '成功率',
+          durationAvg: '平均延迟',
+          durationP50: 'p50 延迟',
+          durationP95: 'p95 延迟',
+          firstTokenAvg: '平均首字延迟',
+          inputTokens: '输入 token',
+          outputTokens: '输出 token'
+        },
+        validation: {
+          name: '请填写名称',
+          target: '请填写分组 ID 和影子账号 ID',
+          sampleRate: '采样率需在 0 到 100% 之间'
+        }
+      },
+      statusPage: {
+        title: '公开状态页',
+        description: '按平台/模型展示可用性与故障，无需登录即可访问。',
+        disabled: '未启用',
+        settings: '设置',
+        settingsTitle: '状态页设置',
+        enabled: '启用公开状态页',
+        pageTitle: '页面标题',
+        platforms: '平台',
+        platformsHint: '逗号分隔；为空 = 所有有流量的平台。',
+        incidentPlatformsHint: '逗号分隔；为空 = 全部平台。',
+        showModels: '显示按模型明细',
+        minModelRequests: '模型展示的最少请求数（24 小时）',
+        cacheSeconds: '缓存秒数（15-600）',
+        settingsSaved: '状态页设置已保存',
+        loadFailed: '加载状态页数据失败',
+        saveFailed: '保存失败',
+        deleteFailed: '删除失败',
+        empty: '暂无已发布的故障公告。',
+        createIncident: '新建故障',
+        editIncident: '编辑故障',
+        incidentTitle: '标题',
+        impact: '影响',
+        status: '状态',
+        startedAt: '开始时间',
+        allPlatforms: '全部',
+        message: '内容',
+        postUpdate: '发布进展',
+        post: '发布',
+        incidentSaved: '故障已保存',
+        updatePosted: '进展已发布',
+        incidentDeleted: '故障已删除',
+        deleteConfirmTitle: '删除故障',
+        deleteConfirmMessage: '确定删除该故障及其全部进展吗？',
+        validation: {
+          title: '请填写标题',
+          message: '请填写内容'
         }
       },
       runtime: {
@@ -3377,6 +4319,8 @@ export default {
         passwordResetHint: '允许用户通过邮箱重置密码',
         totp: '双因素认证 (2FA)',
         totpHint: '允许用户使用 Google Authenticator 等应用进行二次验证',
+        passkeyLogin: '通行密钥登录',
+        passkeyLoginHint: '允许用户使用已注册的通行密钥免密码登录',
         totpKeyNotConfigured:
           '请先在环境变量中配置 TOTP_ENCRYPTION_KEY。使用命令 openssl rand -hex 32 生成密钥。'
       },
@@ -3411,6 +4355,52 @@ export default {
         quickSetCopy: '使用当前站点生成并复制',
         redirectUrlSetAndCopied: '已使用当前站点生成回调地址并复制到剪贴板'
       },
+      oidc: {
+        title: 'OIDC / OAuth2 登录提供方',
+        description: '允许用户使用任意 OIDC 或 OAuth2 身份提供方登录（Google、Azure AD、Keycloak、GitHub 等）',
+        addProvider: '添加提供方',
+        editProvider: '编辑提供方',
+        deleteProvider: '删除提供方',
+        deleteConfirm:
+          '确定删除提供方「{name}」？删除后用户将无法通过它登录。已绑定的身份会保留，重新创建相同标识的提供方后可继续使用。',
+        empty: '暂未配置提供方',
+        saved: '提供方已保存',
+        deleted: '提供方已删除',
+        failedToLoad: '加载提供方失败',
+        failedToSave: '保存提供方失败',
+        failedToDelete: '删除提供方失败',
+        form: {
+          slug: '标识（Slug）',
+          slugHint: '小写字母、数字和连字符，用于回调地址，创建后不可修改',
+          name: '显示名称',
+          nameHint: '显示在登录按钮上',
+          discoveryUrl: 'Discovery 地址 / Issuer',
+          discoveryUrlHint: '自动从 /.well-known/openid-configuration 加载端点；纯 OAuth2 提供方可留空',
+          manualEndpoints: '手动配置端点（可选）',
+          manualEndpointsHint: '不支持 discovery 的提供方（如 GitHub）必填；优先于 discovery 结果',
+          authorizeUrl: '授权地址（Authorization URL）',
+          tokenUrl: '令牌地址（Token URL）',
+          userinfoUrl: '用户信息地址（UserInfo URL）',
+          tokenAuthMethod: 'Token 认证方式',
+          scopes: 'Scopes',
+          redirectUrlHint: '需与提供方后台配置的回调地址一致（必须是 http(s) 完整 URL）',
+          subjectClaim: '用户 ID 声明',
+          emailClaim: '邮箱声明',
+          emailVerifiedClaim: '邮箱已验证声明',
+          emailVerifiedClaimHint: '默认 email_verified，未返回该声明的邮箱视为未验证',
+          usernameClaim: '用户名声明',
+          allowedEmailDomains: '允许的邮箱域名',
+          allowedEmailDomainsHint: '逗号分隔。设置后仅允许这些域名下已验证邮箱的用户登录',
+          usePkce: '启用 PKCE',
+          usePkceHint: '推荐开启；公共客户端（认证方式为 none）必须开启',
+          trustAllEmails: '信任全部邮箱',
+          trustAllEmailsHint: '忽略邮箱已验证声明，提供方返回的邮箱均视为已验证。仅适用于不会返回未验证邮箱的提供方',
+          trustEmail: '按邮箱绑定',
+          trustEmailHint: '已验证邮箱将登录到同邮箱的已有账号。仅应对可信的身份提供方开启',
+          enabled: '启用',
+          enabledHint: '在登录/注册页面显示该提供方'
+        }
+      },
       defaults: {
         title: '用户默认设置',
         description: '新用户的默认值',
@@ -3738,6 +4728,51 @@ export default {
 
   // User Subscriptions Page
   userSubscriptions: {
+    plans: {
+      title: '订阅套餐',
+      description: '使用账户余额购买、续费或变更订阅',
+      days: '{days} 天',
+      policy: {
+        none: '一次性',
+        manual: '可续费',
+        auto: '可自动续费'
+      },
+      buy: '购买',
+      renew: '续费',
+      change: '变更',
+      notRenewable: '该套餐在订阅有效期内不可续费',
+      unavailable: '该套餐已下架',
+      changeTargetActive: '您已拥有该分组的有效订阅，请直接续费',
+      insufficientBalance: '余额不足，请先充值',
+      failed: '操作失败',
+      confirmTitle: '确认购买',
+      changeTitle: '变更套餐',
+      changeFrom: '替换的订阅',
+      changeHint: '被替换订阅中已付费且未使用的部分将折算抵扣（最多抵满新套餐价格，不退回余额），并立即结束',
+      plan: '套餐',
+      credit: '剩余时间抵扣',
+      amount: '应付金额',
+      balance: '当前余额',
+      newExpiry: '有效期至',
+      extendHint: '有效期将在当前到期时间基础上累加',
+      confirm: '确认',
+      processing: '处理中...',
+      purchased: '已购买套餐「{plan}」',
+      changed: '已变更为套餐「{plan}」',
+      autoRenew: '自动续费',
+      autoRenewHint: '到期前自动使用余额按 {plan}（${price}）续费',
+      autoRenewFailed: '上次续费失败：{reason}',
+      autoRenewEnabled: '已开启自动续费',
+      autoRenewDisabled: '已关闭自动续费',
+      orders: '最近订单',
+      validUntil: '有效期至 {date}',
+      kind: {
+        purchase: '购买',
+        renew: '续费',
+        auto_renew: '自动续费',
+        change: '变更'
+      }
+    },
     title: '我的订阅',
     description: '查看您的订阅计划和用量',
     noActiveSubscriptions: '暂无有效订阅',
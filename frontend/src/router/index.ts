@@ -79,6 +79,15 @@ const routes: RouteRecordRaw[] = [
       title: 'LinuxDo OAuth Callback'
     }
   },
+  {
+    path: '/auth/oidc/callback',
+    name: 'OIDCOAuthCallback',
+    component: () => import('@/views/auth/OIDCCallbackView.vue'),
+    meta: {
+      requiresAuth: false,
+      title: 'OIDC Callback'
+    }
+  },
   {
     path: '/forgot-password',
     name: 'ForgotPassword',
@@ -97,6 +106,24 @@ const routes: RouteRecordRaw[] = [
       title: 'Reset Password'
     }
   },
+  {
+    path: '/account-deletion/confirm',
+    name: 'AccountDeletionConfirm',
+    component: () => import('@/views/auth/AccountDeletionConfirmView.vue'),
+    meta: {
+      requiresAuth: false,
+      title: 'Confirm Account Deletion'
+    }
+  },
+  {
+    path: '/status',
+    name: 'Status',
+    component: () => import('@/views/StatusView.vue'),
+    meta: {
+      requiresAuth: false,
+      title: 'Status'
+    }
+  },
 
   // ==================== User Routes ====================
   {
@@ -151,6 +178,18 @@ const routes: RouteRecordRaw[] = [
       descriptionKey: 'redeem.description'
     }
   },
+  {
+    path: '/referral',
+    name: 'Referral',
+    component: () => import('@/views/user/ReferralView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: false,
+      title: 'Invite Friends',
+      titleKey: 'referral.title',
+      descriptionKey: 'referral.description'
+    }
+  },
   {
     path: '/profile',
     name: 'Profile',
@@ -313,6 +352,66 @@ const routes: RouteRecordRaw[] = [
       descriptionKey: 'admin.promo.description'
     }
   },
+  {
+    path: '/admin/api-key-abuse',
+    name: 'AdminAPIKeyAbuse',
+    component: () => import('@/views/admin/APIKeyAbuseView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'API Key Abuse Detection',
+      titleKey: 'admin.apiKeyAbuse.title',
+      descriptionKey: 'admin.apiKeyAbuse.description'
+    }
+  },
+  {
+    path: '/admin/spend-guard',
+    name: 'AdminSpendGuard',
+    component: () => import('@/views/admin/SpendGuardView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'Spend Guard',
+      titleKey: 'admin.spendGuard.title',
+      descriptionKey: 'admin.spendGuard.description'
+    }
+  },
+  {
+    path: '/admin/referrals',
+    name: 'AdminReferrals',
+    component: () => import('@/views/admin/ReferralsView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'Referral Program',
+      titleKey: 'admin.referrals.title',
+      descriptionKey: 'admin.referrals.description'
+    }
+  },
+  {
+    path: '/admin/subscription-plans',
+    name: 'AdminSubscriptionPlans',
+    component: () => import('@/views/admin/SubscriptionPlansView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'Subscription Plans',
+      titleKey: 'admin.subscriptionPlans.title',
+      descriptionKey: 'admin.subscriptionPlans.description'
+    }
+  },
+  {
+    path: '/admin/payments',
+    name: 'AdminPayments',
+    component: () => import('@/views/admin/PaymentsView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'Payments',
+      titleKey: 'admin.payments.title',
+      descriptionKey: 'admin.payments.description'
+    }
+  },
   {
     path: '/admin/settings',
     name: 'AdminSettings',
@@ -446,9 +545,13 @@ router.beforeEach((to, _from, next) => {
     const restrictedPaths = [
       '/admin/groups',
       '/admin/subscriptions',
+      '/admin/subscription-plans',
+      '/admin/payments',
       '/admin/redeem',
+      '/admin/referrals',
       '/subscriptions',
-      '/redeem'
+      '/redeem',
+      '/referral'
     ]
 
     if (restrictedPaths.some((path) => to.path.startsWith(path))) {
@@ -6,7 +6,9 @@
 import { defineStore } from 'pinia'
 import { ref, computed, readonly } from 'vue'
 import { authAPI, isTotp2FARequired, type LoginResponse } from '@/api'
-import type { User, LoginRequest, RegisterRequest, AuthResponse } from '@/types'
+import type { User, LoginRequest, RegisterRequest, AuthResponse, TotpLogin2FARequest } from '@/types'
+import { getPasskeyAssertion } from '@/utils/webauthn'
+import { saveImpersonationBackup, restoreImpersonationBackup } from '@/utils/impersonation'
 
 const AUTH_TOKEN_KEY = 'auth_token'
 const AUTH_USER_KEY = 'auth_user'
@@ -38,6 +40,8 @@ export const useAuthStore = defineStore('auth', () => {
 
   const isSimpleMode = computed(() => runMode.value === 'simple')
 
+  const isImpersonating = computed(() => !!user.value?.impersonation)
+
   // ==================== Actions ====================
 
   /**
@@ -202,15 +206,37 @@ export const useAuthStore = defineStore('auth', () => {
   }
 
   /**
-   * Complete login with 2FA code
+   * Complete login with a second factor
    * @param tempToken - Temporary token from initial login
-   * @param totpCode - 6-digit TOTP code
+   * @param factor - 6-digit TOTP code, or a recovery code / passkey assertion
    * @returns Promise resolving to the authenticated user
    * @throws Error if 2FA verification fails
    */
-  async function login2FA(tempToken: string, totpCode: string): Promise<User> {
+  async function login2FA(
+    tempToken: string,
+    factor: string | Omit<TotpLogin2FARequest, 'temp_token'>
+  ): Promise<User> {
     try {
-      const response = await authAPI.login2FA({ temp_token: tempToken, totp_code: totpCode })
+      const request = typeof factor === 'string' ? { totp_code: factor } : factor
+      const response = await authAPI.login2FA({ temp_token: tempToken, ...request })
+      setAuthFromResponse(response)
+      return user.value!
+    } catch (error) {
+      clearAuth()
+      throw error
+    }
+  }
+
+  /**
+   * Passwordless login with a passkey
+   * @returns Promise resolving to the authenticated user
+   * @throws Error if the passkey ceremony or verification fails
+   */
+  async function loginWithPasskey(): Promise<User> {
+    try {
+      const { session_token, options } = await authAPI.beginPasskeyLogin()
+      const credential = await getPasskeyAssertion(options)
+      const response = await authAPI.finishPasskeyLogin(session_token, credential)
       setAuthFromResponse(response)
       return user.value!
     } catch (error) {
@@ -319,11 +345,81 @@ export const useAuthStore = defineStore('auth', () => {
     }
   }
 
+  /**
+   * Act as another user with an impersonation token issued to an admin
+   * The admin's own session is backed up and restored by exitImpersonation
+   * Impersonation tokens have no refresh token and simply expire
+   * @param impersonationToken - Short-lived token from the admin impersonate endpoint
+   */
+  async function startImpersonation(impersonationToken: string): Promise<User> {
+    saveImpersonationBackup()
+    stopAutoRefresh()
+    stopTokenRefresh()
+
+    token.value = impersonationToken
+    refreshTokenValue.value = null
+    tokenExpiresAt.value = null
+    user.value = null
+    localStorage.setItem(AUTH_TOKEN_KEY, impersonationToken)
+    localStorage.removeItem(REFRESH_TOKEN_KEY)
+    localStorage.removeItem(TOKEN_EXPIRES_AT_KEY)
+
+    try {
+      const userData = await refreshUser()
+      startAutoRefresh()
+      return userData
+    } catch (error) {
+      restoreAdminSession()
+      throw error
+    }
+  }
+
+  /**
+   * End impersonation and switch back to the admin's own session
+   */
+  async function exitImpersonation(): Promise<void> {
+    try {
+      await authAPI.exitImpersonation()
+    } catch {
+      // Session may already have expired - restore locally regardless
+    }
+    restoreAdminSession()
+  }
+
+  /**
+   * Restore the admin session saved before impersonation
+   * Internal helper function
+   */
+  function restoreAdminSession(): void {
+    stopAutoRefresh()
+    stopTokenRefresh()
+    token.value = null
+    refreshTokenValue.value = null
+    tokenExpiresAt.value = null
+    user.value = null
+
+    if (restoreImpersonationBackup()) {
+      checkAuth()
+    } else {
+      clearAuth()
+    }
+  }
+
   /**
    * User logout
    * Clears all authentication state and persisted data
    */
   async function logout(): Promise<void> {
+    // Logging out while impersonating ends the impersonation and logs the admin out too
+    if (isImpersonating.value) {
+      try {
+        await authAPI.exitImpersonation()
+      } catch {
+        // Ignore errors - we still want to log out
+      }
+      restoreImpersonationBackup()
+    }
+
     // Call API logout (revokes refresh token on server)
     await authAPI.logout()
 
@@ -356,7 +452,9 @@ export const useAuthStore = defineStore('auth', () => {
       return userData
     } catch (error) {
       // If refresh fails with 401, clear auth state
-      if ((error as { status?: number }).status === 401) {
+      // (unless the client just switched back to the admin session after impersonation ended)
+      const { status, code } = error as { status?: number; code?: string }
+      if (status === 401 && code !== 'IMPERSONATION_RESTORED') {
         clearAuth()
       }
       throw error
@@ -395,12 +493,16 @@ export const useAuthStore = defineStore('auth', () => {
     isAuthenticated,
     isAdmin,
     isSimpleMode,
+    isImpersonating,
 
     // Actions
     login,
     login2FA,
+    loginWithPasskey,
     register,
     setToken,
+    startImpersonation,
+    exitImpersonation,
     logout,
     checkAuth,
     refreshUser
@@ -34,10 +34,20 @@ export interface User {
   status: 'active' | 'disabled' // Account status
   allowed_groups: number[] | null // Allowed group IDs (null = all non-exclusive groups)
   subscriptions?: UserSubscription[] // User's active subscriptions
+  impersonation?: ImpersonationInfo // Present only when an admin is acting as this user
   created_at: string
   updated_at: string
 }
 
+// 管理员模拟登录信息（/auth/me 返回）
+export interface ImpersonationInfo {
+  session_id: number
+  admin_id: number
+  admin_email: string
+  reason: string
+  expires_at: string
+}
+
 export interface AdminUser extends User {
   // 管理员备注（普通用户接口不返回）
   notes: string
@@ -63,6 +73,7 @@ export interface RegisterRequest {
   turnstile_token?: string
   promo_code?: string
   invitation_code?: string
+  referral_code?: string
 }
 
 export interface SendVerifyCodeRequest {
@@ -81,6 +92,7 @@ export interface PublicSettings {
   promo_code_enabled: boolean
   password_reset_enabled: boolean
   invitation_code_enabled: boolean
+  passkey_login_enabled: boolean
   turnstile_enabled: boolean
   turnstile_site_key: string
   site_name: string
@@ -94,9 +106,16 @@ export interface PublicSettings {
   purchase_subscription_enabled: boolean
   purchase_subscription_url: string
   linuxdo_oauth_enabled: boolean
+  oidc_providers: OIDCPublicProvider[]
+  referral_enabled: boolean
   version: string
 }
 
+export interface OIDCPublicProvider {
+  slug: string
+  name: string
+}
+
 export interface AuthResponse {
   access_token: string
   refresh_token?: string  // New: Refresh Token for token renewal
@@ -397,7 +416,7 @@ export interface ApiKey {
   key: string
   name: string
   group_id: number | null
-  status: 'active' | 'inactive' | 'quota_exhausted' | 'expired'
+  status: 'active' | 'inactive' | 'quota_exhausted' | 'expired' | 'suspended'
   ip_whitelist: string[]
   ip_blacklist: string[]
   quota: number // Quota limit in USD (0 = unlimited)
@@ -1259,6 +1278,10 @@ export interface UserAttributeValuesMap {
 
 // ==================== Promo Code Types ====================
 
+export type PromoBonusType = 'fixed' | 'percent'
+export type PromoScope = 'register' | 'panel' | 'any'
+export type PromoUsageStatus = 'applied' | 'pending'
+
 export interface PromoCode {
   id: number
   code: string
@@ -1270,6 +1293,15 @@ export interface PromoCode {
   notes: string | null
   created_at: string
   updated_at: string
+  bonus_type: PromoBonusType
+  bonus_percent: number
+  max_bonus_amount: number
+  per_user_limit: number
+  scope: PromoScope
+  new_users_only: boolean
+  new_user_days: number
+  required_group_id: number | null
+  starts_at: string | null
 }
 
 export interface PromoCodeUsage {
@@ -1278,6 +1310,14 @@ export interface PromoCodeUsage {
   user_id: number
   bonus_amount: number
   used_at: string
+  bonus_type: PromoBonusType
+  bonus_percent: number
+  max_bonus_amount: number
+  status: PromoUsageStatus
+  source: 'register' | 'panel'
+  base_amount: number
+  redeem_code_id?: number
+  applied_at: string | null
   user?: User
 }
 
@@ -1287,6 +1327,15 @@ export interface CreatePromoCodeRequest {
   max_uses?: number
   expires_at?: number | null
   notes?: string
+  bonus_type?: PromoBonusType
+  bonus_percent?: number
+  max_bonus_amount?: number
+  per_user_limit?: number
+  scope?: PromoScope
+  new_users_only?: boolean
+  new_user_days?: number
+  required_group_id?: number | null
+  starts_at?: number | null
 }
 
 export interface UpdatePromoCodeRequest {
@@ -1296,6 +1345,30 @@ export interface UpdatePromoCodeRequest {
   status?: 'active' | 'disabled'
   expires_at?: number | null
   notes?: string
+  bonus_type?: PromoBonusType
+  bonus_percent?: number
+  max_bonus_amount?: number
+  per_user_limit?: number
+  scope?: PromoScope
+  new_users_only?: boolean
+  new_user_days?: number
+  required_group_id?: number
+  starts_at?: number
+}
+
+/** A promo code usage as seen by the redeeming user */
+export interface UserPromoCodeUsage {
+  id: number
+  code: string
+  bonus_type: PromoBonusType
+  bonus_amount: number
+  bonus_percent: number
+  max_bonus_amount: number
+  status: PromoUsageStatus
+  source: 'register' | 'panel'
+  base_amount: number
+  used_at: string
+  applied_at: string | null
 }
 
 // ==================== TOTP (2FA) Types ====================
@@ -1336,13 +1409,423 @@ export interface TotpVerificationMethod {
   method: 'email' | 'password'
 }
 
+export type TwoFactorMethod = 'totp' | 'webauthn' | 'recovery_code'
+
 export interface TotpLoginResponse {
   requires_2fa: boolean
   temp_token?: string
   user_email_masked?: string
+  methods?: TwoFactorMethod[]
 }
 
 export interface TotpLogin2FARequest {
   temp_token: string
-  totp_code: string
+  totp_code?: string
+  recovery_code?: string
+  webauthn?: WebAuthnAssertionResponse
+}
+
+// ==================== Passkey (WebAuthn) Types ====================
+
+export interface WebAuthnCredentialDescriptor {
+  type: 'public-key'
+  id: string // base64url
+  transports?: string[]
+}
+
+// Server-provided PublicKeyCredentialCreationOptions (binary fields base64url-encoded)
+export interface WebAuthnCreationOptions {
+  challenge: string
+  rp: { id: string; name: string }
+  user: { id: string; name: string; displayName: string }
+  pubKeyCredParams: { type: 'public-key'; alg: number }[]
+  timeout: number
+  attestation: AttestationConveyancePreference
+  excludeCredentials?: WebAuthnCredentialDescriptor[]
+  authenticatorSelection: {
+    residentKey: ResidentKeyRequirement
+    userVerification: UserVerificationRequirement
+  }
+}
+
+// Server-provided PublicKeyCredentialRequestOptions (binary fields base64url-encoded)
+export interface WebAuthnRequestOptions {
+  challenge: string
+  timeout: number
+  rpId: string
+  allowCredentials?: WebAuthnCredentialDescriptor[]
+  userVerification: UserVerificationRequirement
+}
+
+export interface WebAuthnAttestationResponse {
+  credential_id: string
+  client_data_json: string
+  attestation_object: string
+  transports: string[]
+}
+
+export interface WebAuthnAssertionResponse {
+  credential_id: string
+  client_data_json: string
+  authenticator_data: string
+  signature: string
+  user_handle?: string
+}
+
+export interface WebAuthnCredential {
+  id: number
+  name: string
+  transports: string[]
+  last_used_at?: number // Unix timestamp
+  created_at: number // Unix timestamp
+}
+
+export interface WebAuthnRegistrationBegin {
+  session_token: string
+  options: WebAuthnCreationOptions
+}
+
+export interface WebAuthnLoginBegin {
+  session_token: string
+  options: WebAuthnRequestOptions
+}
+
+// ==================== Session Types ====================
+
+export interface UserSession {
+  id: string
+  user_agent: string
+  ip_address: string
+  created_at: string
+  last_used_at: string
+  expires_at: string
+  current: boolean
+}
+
+/** 登录暴力破解防护的锁定记录 */
+export interface LoginLock {
+  scope: 'account' | 'ip'
+  key: string
+  locked_until: string
+  user_id?: number
+}
+
+export interface IdentityVerificationRequest {
+  email_code?: string
+  password?: string
+}
+
+// ==================== Referral Types ====================
+
+export type ReferralStatus = 'active' | 'held' | 'blocked'
+export type ReferralFlag = 'same_ip' | 'shared_signup_ip' | 'same_email_domain'
+export type ReferralRewardKind = 'signup_bonus' | 'commission'
+
+export interface ReferralStats {
+  total_referrals: number
+  active_referrals: number
+  total_rewards: number
+  signup_bonus_total: number
+  commission_total: number
+}
+
+export interface ReferralOverview {
+  enabled: boolean
+  code?: string
+  signup_bonus_usd: number
+  signup_bonus_min_spend_usd: number
+  commission_percent: number
+  commission_days: number
+  per_referee_cap_usd: number
+  stats: ReferralStats
+}
+
+export interface Referral {
+  id: number
+  referrer_id: number
+  referee_id: number
+  code: string
+  status: ReferralStatus
+  flags: ReferralFlag[] | null
+  signup_ip: string
+  email_domain: string
+  signup_bonus_paid: boolean
+  referee_spend: number
+  referee_funded_spend: number
+  reward_total: number
+  settled_until: string
+  reviewed_by?: number
+  reviewed_at?: string
+  review_note?: string
+  created_at: string
+  updated_at: string
+  referrer_email: string
+  referee_email: string
+}
+
+export interface ReferralReward {
+  id: number
+  referral_id: number
+  referrer_id: number
+  referee_id: number
+  kind: ReferralRewardKind
+  amount: number
+  base_amount: number
+  created_at: string
+  referee_email: string
+}
+
+export interface ReferralSettings {
+  enabled: boolean
+  signup_bonus_usd: number
+  signup_bonus_min_spend_usd: number
+  commission_percent: number
+  commission_days: number
+  per_referee_cap_usd: number
+  per_referrer_cap_usd: number
+  hold_flagged: boolean
+  ignored_email_domains: string[]
+}
+
+// ==================== Impersonation Types ====================
+
+export interface ImpersonationSession {
+  id: number
+  admin_user_id: number
+  target_user_id: number
+  reason: string
+  ip_address: string
+  user_agent: string
+  expires_at: string
+  ended_at?: string
+  ended_by?: number
+  created_at: string
+  admin_email: string
+  target_email: string
+  action_count: number
+}
+
+export interface ImpersonationAction {
+  id: number
+  session_id: number
+  admin_user_id: number
+  target_user_id: number
+  method: string
+  path: string
+  status_code: number
+  ip_address: string
+  created_at: string
+}
+
+export interface ImpersonationStartResult {
+  token: string
+  expires_at: string
+  session: ImpersonationSession
+}
+
+// ==================== Data Export & Account Deletion Types ====================
+
+export type UserDataExportStatus = 'pending' | 'processing' | 'completed' | 'failed'
+
+export interface UserDataExport {
+  id: number
+  user_id: number
+  status: UserDataExportStatus
+  file_size: number
+  error_message?: string
+  started_at?: string
+  completed_at?: string
+  expires_at?: string
+  created_at: string
+}
+
+export type AccountDeletionStatus =
+  | 'pending_confirmation'
+  | 'scheduled'
+  | 'cancelled'
+  | 'restored'
+  | 'completed'
+
+export interface AccountDeletionRequest {
+  id: number
+  user_id: number
+  email: string
+  status: AccountDeletionStatus
+  reason: string
+  previous_status?: string
+  revoked_api_key_ids: number[]
+  ip_address: string
+  confirmed_at?: string
+  scheduled_for?: string
+  cancelled_at?: string
+  restored_at?: string
+  restored_by?: number
+  completed_at?: string
+  created_at: string
+  updated_at: string
+}
+
+export interface AccountDeletionStatusResponse {
+  request: AccountDeletionRequest | null
+  grace_period_days: number
+}
+
+// ==================== Subscription Plan Types ====================
+
+export type SubscriptionRenewalPolicy = 'none' | 'manual' | 'auto'
+export type SubscriptionPlanStatus = 'active' | 'disabled'
+export type SubscriptionOrderKind = 'purchase' | 'renew' | 'auto_renew' | 'change'
+
+export interface SubscriptionPlan {
+  id: number
+  group_id: number
+  name: string
+  description: string
+  price: number
+  validity_days: number
+  renewal_policy: SubscriptionRenewalPolicy
+  status: SubscriptionPlanStatus
+  sort_order: number
+  created_at: string
+  updated_at: string
+  group_name: string
+}
+
+export interface SubscriptionOrder {
+  id: number
+  user_id: number
+  plan_id?: number
+  plan_name: string
+  group_id: number
+  subscription_id: number
+  kind: SubscriptionOrderKind
+  from_group_id?: number
+  price: number
+  credit: number
+  amount: number
+  validity_days: number
+  expires_at: string
+  created_at: string
+  user_email?: string
+  group_name: string
+  from_group_name?: string
+}
+
+export interface SubscriptionRenewal {
+  user_id: number
+  group_id: number
+  plan_id?: number
+  auto_renew: boolean
+  last_attempt_at?: string
+  last_error?: string
+  created_at: string
+  updated_at: string
+}
+
+export interface SubscriptionQuote {
+  plan: SubscriptionPlan
+  decision: {
+    kind: SubscriptionOrderKind
+    credit: number
+    amount: number
+    starts_at: string
+    expires_at: string
+    extend: boolean
+  }
+  balance: number
+  sufficient: boolean
+}
+
+export interface SubscriptionPurchaseResult {
+  order: SubscriptionOrder
+  balance: number
+}
+
+export interface SubscriptionPlanPayload {
+  group_id: number
+  name: string
+  description: string
+  price: number
+  validity_days: number
+  renewal_policy: SubscriptionRenewalPolicy
+  status: SubscriptionPlanStatus
+  sort_order: number
+}
+
+// ==================== Payment Types ====================
+
+export type PaymentProviderName = 'stripe' | 'generic'
+export type PaymentOrderStatus =
+  | 'pending'
+  | 'paid'
+  | 'partially_refunded'
+  | 'refunded'
+  | 'expired'
+  | 'failed'
+
+export interface PaymentOrder {
+  id: number
+  order_no: string
+  user_id: number
+  provider: PaymentProviderName
+  provider_order_id: string
+  provider_payment_id: string
+  amount: number
+  pay_amount: number
+  currency: string
+  bonus_amount: number
+  status: PaymentOrderStatus
+  pay_url?: string
+  failure_reason?: string
+  refunded_amount: number
+  refunded_credit: number
+  expires_at: string
+  paid_at?: string
+  refunded_at?: string
+  created_at: string
+  updated_at: string
+  user_email?: string
+}
+
+export interface PaymentProviderInfo {
+  name: PaymentProviderName
+  display_name: string
+  currency: string
+  exchange_rate: number
+}
+
+export interface PaymentConfig {
+  enabled: boolean
+  min_amount: number
+  max_amount: number
+  providers: PaymentProviderInfo[]
+}
+
+export interface PaymentSettings {
+  enabled: boolean
+  public_base_url: string
+  min_amount: number
+  max_amount: number
+  order_expire_minutes: number
+  stripe: {
+    enabled: boolean
+    api_base: string
+    secret_key?: string
+    secret_key_configured: boolean
+    webhook_secret?: string
+    webhook_secret_configured: boolean
+    currency: string
+    exchange_rate: number
+  }
+  generic: {
+    enabled: boolean
+    display_name: string
+    gateway_url: string
+    refund_url: string
+    merchant_id: string
+    secret_key?: string
+    secret_key_configured: boolean
+    currency: string
+    exchange_rate: number
+  }
 }
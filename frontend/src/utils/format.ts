@@ -310,3 +310,34 @@ export function formatRelativeWithDateTime(date: string | Date | null | undefine
 
   return `${relativeTime} · ${dateTime}`
 }
+
+/**
+ * 将 User-Agent 简化为 "浏览器 · 系统" 形式，便于展示登录设备
+ * @param ua User-Agent 字符串
+ * @returns 如 "Chrome · macOS"；无法识别时返回截断后的原始值
+ */
+export function formatUserAgent(ua: string | null | undefined): string {
+  if (!ua) return i18n.global.t('common.unknown')
+
+  const browsers: [RegExp, string][] = [
+    [/Edg\//, 'Edge'],
+    [/OPR\/|Opera/, 'Opera'],
+    [/Firefox\//, 'Firefox'],
+    [/Chrome\//, 'Chrome'],
+    [/Safari\//, 'Safari']
+  ]
+  const systems: [RegExp, string][] = [
+    [/Windows/, 'Windows'],
+    [/iPhone|iPad|iPod/, 'iOS'],
+    [/Android/, 'Android'],
+    [/Mac OS X|Macintosh/, 'macOS'],
+    [/CrOS/, 'ChromeOS'],
+    [/Linux/, 'Linux']
+  ]
+  const browser = browsers.find(([re]) => re.test(ua))?.[1]
+  const system = systems.find(([re]) => re.test(ua))?.[1]
+  if (!browser && !system) {
+    return ua.length > 60 ? `${ua.slice(0, 60)}…` : ua
+  }
+  return [browser, system].filter(Boolean).join(' · ')
+}
@@ -0,0 +1,52 @@
+/**
+ * 管理员模拟登录（以用户身份查看）的本地会话备份
+ * 开始模拟前保存管理员自己的登录状态，退出或模拟 Token 失效时恢复
+ */
+
+const BACKUP_KEY = 'impersonation_backup'
+const AUTH_KEYS = ['auth_token', 'auth_user', 'refresh_token', 'token_expires_at'] as const
+
+type AuthSnapshot = Partial<Record<(typeof AUTH_KEYS)[number], string>>
+
+/**
+ * 保存当前（管理员）登录状态；已存在备份时保留最初的管理员会话
+ */
+export function saveImpersonationBackup(): void {
+  if (hasImpersonationBackup()) return
+  const snapshot: AuthSnapshot = {}
+  for (const key of AUTH_KEYS) {
+    const value = localStorage.getItem(key)
+    if (value !== null) snapshot[key] = value
+  }
+  localStorage.setItem(BACKUP_KEY, JSON.stringify(snapshot))
+}
+
+export function hasImpersonationBackup(): boolean {
+  return localStorage.getItem(BACKUP_KEY) !== null
+}
+
+/**
+ * 用备份覆盖当前登录状态并删除备份
+ * @returns 是否存在可恢复的备份
+ */
+export function restoreImpersonationBackup(): boolean {
+  const raw = localStorage.getItem(BACKUP_KEY)
+  if (raw === null) return false
+  localStorage.removeItem(BACKUP_KEY)
+
+  let snapshot: AuthSnapshot = {}
+  try {
+    snapshot = JSON.parse(raw) as AuthSnapshot
+  } catch {
+    // 备份损坏时按未登录处理
+  }
+  for (const key of AUTH_KEYS) {
+    const value = snapshot[key]
+    if (value !== undefined) {
+      localStorage.setItem(key, value)
+    } else {
+      localStorage.removeItem(key)
+    }
+  }
+  return !!snapshot.auth_token
+}
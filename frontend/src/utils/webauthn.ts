@@ -0,0 +1,114 @@
+/**
+ * 通行密钥（WebAuthn）浏览器端工具
+ * 服务端选项中的二进制字段使用 base64url 编码，这里负责与 ArrayBuffer 互转并调用 navigator.credentials
+ */
+
+import type {
+  WebAuthnCreationOptions,
+  WebAuthnRequestOptions,
+  WebAuthnAttestationResponse,
+  WebAuthnAssertionResponse,
+  WebAuthnCredentialDescriptor
+} from '@/types'
+
+function base64urlToBuffer(value: string): ArrayBuffer {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+  const padded = base64 + '='.repeat((4 - (base64.length % 4)) % 4)
+  const binary = atob(padded)
+  const bytes = new Uint8Array(binary.length)
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i)
+  }
+  return bytes.buffer
+}
+
+function bufferToBase64url(buffer: ArrayBuffer): string {
+  const bytes = new Uint8Array(buffer)
+  let binary = ''
+  for (let i = 0; i < bytes.length; i++) {
+    binary += String.fromCharCode(bytes[i])
+  }
+  return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+}
+
+function toDescriptors(list: WebAuthnCredentialDescriptor[] | undefined): PublicKeyCredentialDescriptor[] {
+  return (list || []).map((c) => ({
+    type: 'public-key',
+    id: base64urlToBuffer(c.id),
+    transports: c.transports as AuthenticatorTransport[] | undefined
+  }))
+}
+
+/**
+ * 当前浏览器是否支持通行密钥
+ */
+export function isWebAuthnSupported(): boolean {
+  return typeof window !== 'undefined' && !!window.PublicKeyCredential && !!navigator.credentials
+}
+
+/**
+ * 用户取消或超时（NotAllowedError）时不需要提示错误
+ */
+export function isWebAuthnCancelled(error: unknown): boolean {
+  return error instanceof DOMException && (error.name === 'NotAllowedError' || error.name === 'AbortError')
+}
+
+/**
+ * 创建通行密钥（注册）
+ */
+export async function createPasskey(options: WebAuthnCreationOptions): Promise<WebAuthnAttestationResponse> {
+  const credential = (await navigator.credentials.create({
+    publicKey: {
+      challenge: base64urlToBuffer(options.challenge),
+      rp: options.rp,
+      user: {
+        id: base64urlToBuffer(options.user.id),
+        name: options.user.name,
+        displayName: options.user.displayName
+      },
+      pubKeyCredParams: options.pubKeyCredParams,
+      timeout: options.timeout,
+      attestation: options.attestation,
+      excludeCredentials: toDescriptors(options.excludeCredentials),
+      authenticatorSelection: options.authenticatorSelection
+    }
+  })) as PublicKeyCredential | null
+  if (!credential) {
+    throw new DOMException('No credential returned', 'NotAllowedError')
+  }
+
+  const response = credential.response as AuthenticatorAttestationResponse
+  return {
+    credential_id: bufferToBase64url(credential.rawId),
+    client_data_json: bufferToBase64url(response.clientDataJSON),
+    attestation_object: bufferToBase64url(response.attestationObject),
+    transports: typeof response.getTransports === 'function' ? response.getTransports() : []
+  }
+}
+
+/**
+ * 使用通行密钥签名（二步验证 / 无密码登录）
+ */
+export async function getPasskeyAssertion(options: WebAuthnRequestOptions): Promise<WebAuthnAssertionResponse> {
+  const credential = (await navigator.credentials.get({
+    publicKey: {
+      challenge: base64urlToBuffer(options.challenge),
+      timeout: options.timeout,
+      rpId: options.rpId,
+      allowCredentials: toDescriptors(options.allowCredentials),
+      userVerification: options.userVerification
+    }
+  })) as PublicKeyCredential | null
+  if (!credential) {
+    throw new DOMException('No credential returned', 'NotAllowedError')
+  }
+
+  const response = credential.response as AuthenticatorAssertionResponse
+  return {
+    credential_id: bufferToBase64url(credential.rawId),
+    client_data_json: bufferToBase64url(response.clientDataJSON),
+    authenticator_data: bufferToBase64url(response.authenticatorData),
+    signature: bufferToBase64url(response.signature),
+    user_handle: response.userHandle ? bufferToBase64url(response.userHandle) : undefined
+  }
+}